@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const usage = `Usage: adminctl [options] <command>
+
+Commands:
+  flags                       list runtime flags
+  set <flag> <value>          change a flag
+  log-level                   show the log levels
+  log-level <level>           set the global log level
+  log-level <component> <level>
+                              set a component's log level, "" removes the override
+  audit                       show who changed what
+
+Options:
+`
+
+type flagInfo struct {
+	Name        string    `json:"name"`
+	Kind        string    `json:"kind"`
+	Description string    `json:"description"`
+	Default     string    `json:"default"`
+	Value       string    `json:"value"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type change struct {
+	Name  string    `json:"name"`
+	Old   string    `json:"old"`
+	New   string    `json:"new"`
+	Actor string    `json:"actor"`
+	Time  time.Time `json:"time"`
+}
+
+// client calls the admin API of an HTTP server
+type client struct {
+	addr  string
+	token string
+	actor string
+}
+
+func main() {
+	addr := flag.String("addr", "http://localhost:8080", "base URL of the HTTP server")
+	token := flag.String("token", os.Getenv("SERVER_ADMIN_TOKEN"), "admin token, $SERVER_ADMIN_TOKEN by default")
+	actor := flag.String("actor", os.Getenv("USER"), "name recorded in the audit log")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	c := client{addr: strings.TrimSuffix(*addr, "/"), token: *token, actor: *actor}
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch cmd, args := args[0], args[1:]; {
+	case cmd == "flags" && len(args) == 0:
+		err = c.listFlags()
+	case cmd == "set" && len(args) == 2:
+		err = c.put("/admin/flags/"+args[0], map[string]string{"value": args[1]})
+	case cmd == "log-level" && len(args) == 0:
+		err = c.logLevels()
+	case cmd == "log-level" && len(args) == 1:
+		err = c.put("/admin/log-levels", map[string]string{"level": args[0]})
+	case cmd == "log-level" && len(args) == 2:
+		err = c.put("/admin/log-levels/"+args[0], map[string]string{"level": args[1]})
+	case cmd == "audit" && len(args) == 0:
+		err = c.audit()
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (c client) listFlags() error {
+	var resp struct {
+		Flags []flagInfo `json:"flags"`
+	}
+	if err := c.do(http.MethodGet, "/admin/flags", nil, &resp); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "FLAG\tKIND\tVALUE\tDEFAULT\tDESCRIPTION")
+	for _, f := range resp.Flags {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", f.Name, f.Kind, f.Value, f.Default, f.Description)
+	}
+	return w.Flush()
+}
+
+func (c client) logLevels() error {
+	var resp struct {
+		Level      string            `json:"level"`
+		Components map[string]string `json:"components"`
+	}
+	if err := c.do(http.MethodGet, "/admin/log-levels", nil, &resp); err != nil {
+		return err
+	}
+
+	fmt.Printf("global: %s\n", resp.Level)
+	components := make([]string, 0, len(resp.Components))
+	for component := range resp.Components {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+	for _, component := range components {
+		fmt.Printf("%s: %s\n", component, resp.Components[component])
+	}
+	return nil
+}
+
+func (c client) audit() error {
+	var resp struct {
+		Changes []change `json:"changes"`
+	}
+	if err := c.do(http.MethodGet, "/admin/audit", nil, &resp); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tACTOR\tSETTING\tOLD\tNEW")
+	for _, ch := range resp.Changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", ch.Time.Format(time.RFC3339), ch.Actor, ch.Name, ch.Old, ch.New)
+	}
+	return w.Flush()
+}
+
+// put sends a change and prints what was recorded
+func (c client) put(path string, body interface{}) error {
+	var ch change
+	if err := c.do(http.MethodPut, path, body, &ch); err != nil {
+		return err
+	}
+	fmt.Printf("%s: %q → %q\n", ch.Name, ch.Old, ch.New)
+	return nil
+}
+
+// do calls the admin API, decoding the JSON response into out
+func (c client) do(method, path string, body, out interface{}) error {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		payload = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.addr+path, payload)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.actor != "" {
+		req.Header.Set("X-Admin-Actor", c.actor)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("%s: %s", apiErr.Error.Code, apiErr.Error.Message)
+		}
+		return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
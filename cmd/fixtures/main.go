@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"go-transport-prac/pkg/sdl/fixtures"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "generate":
+		flags := flag.NewFlagSet("generate", flag.ExitOnError)
+		dir := flags.String("dir", fixtures.DefaultDir, "directory to write the fixtures to")
+		count := flags.Int("count", fixtures.DefaultCount, "number of users in each payload")
+		flags.Parse(os.Args[2:])
+
+		manifest, err := fixtures.NewGenerator(*dir).WithCount(*count).Generate()
+		if err != nil {
+			log.Fatalf("Failed to generate fixtures: %v", err)
+		}
+		fmt.Printf("Generated %d users at %s in %s\n", manifest.Records, manifest.GeneratedAt.Format("2006-01-02T15:04:05Z07:00"), *dir)
+		for _, f := range manifest.Files {
+			fmt.Printf("  %-24s %-8s %-7s %8d bytes  %s\n", f.Path, f.Format, f.Role, f.Size, f.SHA256[:16])
+		}
+
+	case "verify":
+		flags := flag.NewFlagSet("verify", flag.ExitOnError)
+		dir := flags.String("dir", fixtures.DefaultDir, "directory to read the fixtures from")
+		flags.Parse(os.Args[2:])
+
+		report, err := fixtures.Verify(*dir)
+		if err != nil {
+			log.Fatalf("Failed to verify fixtures: %v", err)
+		}
+		for _, c := range report.Checks {
+			status := "ok"
+			if !c.OK() {
+				status = "FAIL: " + c.Error
+			}
+			fmt.Printf("  %-24s %s\n", c.Path, status)
+		}
+		if failures := report.Failures(); len(failures) > 0 {
+			fmt.Printf("%d of %d checks failed\n", len(failures), len(report.Checks))
+			os.Exit(1)
+		}
+		fmt.Printf("All %d checks passed\n", len(report.Checks))
+
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: fixtures generate [-dir fixtures] [-count 12]")
+	fmt.Fprintln(os.Stderr, "       fixtures verify [-dir fixtures]")
+	os.Exit(2)
+}
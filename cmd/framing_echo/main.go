@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/protobuf"
+	"go-transport-prac/pkg/transport/framing"
+)
+
+// Streams Avro and Protobuf users through a framed TCP echo server and decodes the echoes
+func main() {
+	addr := flag.String("addr", "127.0.0.1:0", "address the echo server listens on")
+	prefixName := flag.String("prefix", "varint", "frame length prefix: varint or fixed32")
+	count := flag.Int("count", 5, "number of users sent in each format")
+	flag.Parse()
+
+	prefix, err := framing.ParsePrefix(*prefixName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
+	served := make(chan error, 1)
+	go func() {
+		served <- framing.ServeEcho(ln, prefix)
+	}()
+	fmt.Printf("Echo server listening on %s with %s frames\n", ln.Addr(), prefix)
+
+	if err := run(ln.Addr().String(), prefix, *count); err != nil {
+		log.Fatalf("Client failed: %v", err)
+	}
+
+	ln.Close()
+	if err := <-served; err != nil {
+		log.Fatalf("Echo server failed: %v", err)
+	}
+}
+
+// run sends count Avro users followed by count Protobuf users and decodes each echo
+func run(addr string, prefix framing.Prefix, count int) error {
+	avroManager, err := avro.NewManager("")
+	if err != nil {
+		return fmt.Errorf("failed to create avro manager: %w", err)
+	}
+	protoManager := protobuf.NewManager()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	writer := framing.NewWriter(conn, prefix)
+	reader := framing.NewReader(conn, prefix)
+
+	for _, user := range avroManager.CreateSampleUsers(count) {
+		data, err := avroManager.SerializeUserBinary(user)
+		if err != nil {
+			return err
+		}
+		if err := writer.WriteFrame(data); err != nil {
+			return err
+		}
+		echo, err := reader.ReadFrame()
+		if err != nil {
+			return err
+		}
+		decoded, err := avroManager.DeserializeUserBinary(echo)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("  avro     %4d bytes  user %d %s\n", len(echo), decoded.ID, decoded.Email)
+	}
+
+	for i := 0; i < count; i++ {
+		data, err := protoManager.SerializeUser(protoManager.CreateSampleUser())
+		if err != nil {
+			return err
+		}
+		if err := writer.WriteFrame(data); err != nil {
+			return err
+		}
+		echo, err := reader.ReadFrame()
+		if err != nil {
+			return err
+		}
+		decoded, err := protoManager.DeserializeUser(echo)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("  protobuf %4d bytes  user %d %s\n", len(echo), decoded.GetId(), decoded.GetEmail())
+	}
+
+	return nil
+}
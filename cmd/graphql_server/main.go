@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/wire"
+	"go-transport-prac/pkg/transport/graphql"
+)
+
+func main() {
+	app, err := wire.InitializeApplication()
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
+	}
+
+	server, err := graphql.NewServer(graphql.NewConfig(app.Config.Server), nil, app.Logger)
+	if err != nil {
+		log.Fatalf("Failed to create GraphQL server: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		app.Logger.Fatal("GraphQL server exited", zap.Error(err))
+	case <-ctx.Done():
+		if err := server.Shutdown(context.Background()); err != nil {
+			app.Logger.Error("GraphQL server shutdown failed", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/wire"
+	grpctransport "go-transport-prac/pkg/transport/grpc"
+)
+
+func main() {
+	app, err := wire.InitializeApplication()
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
+	}
+
+	server, err := grpctransport.NewServer(grpctransport.NewConfig(app.Config.Server), grpctransport.NewStore(nil), app.Logger)
+	if err != nil {
+		log.Fatalf("Failed to create gRPC server: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		app.Logger.Fatal("gRPC server exited", zap.Error(err))
+	case <-ctx.Done():
+		if err := server.Shutdown(context.Background()); err != nil {
+			app.Logger.Error("gRPC server shutdown failed", zap.Error(err))
+		}
+	}
+}
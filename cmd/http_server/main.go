@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/flags"
+	"go-transport-prac/internal/health"
+	"go-transport-prac/internal/tracing"
+	"go-transport-prac/internal/wire"
+	"go-transport-prac/pkg/metrics"
+	httptransport "go-transport-prac/pkg/transport/http"
+)
+
+func main() {
+	app, err := wire.InitializeApplication()
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), app.Config.Tracing)
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			app.Logger.Error("Tracing shutdown failed", zap.Error(err))
+		}
+	}()
+
+	server, err := httptransport.NewServer(httptransport.NewConfig(app.Config.Server), app.Logger)
+	if err != nil {
+		log.Fatalf("Failed to create HTTP server: %v", err)
+	}
+	settings := flags.NewSet()
+	server.WithFlags(settings)
+	if app.Config.Server.AdminEnabled {
+		server.WithAdmin(settings, app.Config.Server.AdminToken)
+	}
+	if app.Config.Development.EnableMetrics {
+		server.WithMetrics(metrics.NewCollector(metrics.DefaultConfig(), app.Logger))
+	}
+	server.WithProfiling(app.Config.Development)
+	server.WithHealth(health.NewRegistry(""))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		app.Logger.Fatal("HTTP server exited", zap.Error(err))
+	case <-ctx.Done():
+		if err := server.Shutdown(context.Background()); err != nil {
+			app.Logger.Error("HTTP server shutdown failed", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"go-transport-prac/pkg/sdl/lineage"
+	"go-transport-prac/pkg/sdl/parquet"
+)
+
+func main() {
+	dir := flag.String("dir", "", "pipeline base directory to read the committed lineage from")
+	file := flag.String("file", "", "lineage JSON file to read")
+	column := flag.String("column", "", "show the sources and transforms of an output column")
+	source := flag.String("source", "", "show the output columns derived from an input column")
+	flag.Parse()
+
+	var (
+		graph *lineage.Graph
+		err   error
+	)
+	switch {
+	case *file != "":
+		graph, err = lineage.ReadFile(*file)
+	case *dir != "":
+		graph, err = parquet.NewDataPipeline(*dir).CommittedLineage()
+	default:
+		log.Fatal("one of -dir or -file is required")
+	}
+	if err != nil {
+		log.Fatalf("Failed to load lineage: %v", err)
+	}
+
+	fmt.Printf("Pipeline %s run %s: %s → %s\n", graph.Pipeline, graph.RunID, graph.InputDataset, graph.OutputDataset)
+
+	switch {
+	case *column != "":
+		col, ok := graph.Column(*column)
+		if !ok {
+			log.Fatalf("No lineage recorded for column %q", *column)
+		}
+		printColumn(col)
+	case *source != "":
+		downstream := graph.Downstream(*source)
+		if len(downstream) == 0 {
+			log.Fatalf("No output columns derive from %q", *source)
+		}
+		for _, name := range downstream {
+			fmt.Println(name)
+		}
+	default:
+		for _, col := range graph.Columns {
+			printColumn(col)
+		}
+	}
+}
+
+func printColumn(col lineage.ColumnLineage) {
+	sources := "(generated)"
+	if len(col.Sources) > 0 {
+		sources = strings.Join(col.Sources, ", ")
+	}
+	fmt.Printf("%s ← %s\n", col.Column, sources)
+	for _, t := range col.Transforms {
+		fmt.Printf("    [%s] %s\n", t.Stage, t)
+	}
+}
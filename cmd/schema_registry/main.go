@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/wire"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/transport/schemaregistry"
+)
+
+func main() {
+	app, err := wire.InitializeApplication()
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
+	}
+
+	// Hash-derived IDs survive restarts, so messages framed with an ID
+	// before a restart still resolve once the schema is registered again
+	registry := avro.NewSchemaRegistry().WithIDGenerator(avro.HashIDGenerator{})
+	server := schemaregistry.NewServer(schemaregistry.NewConfig(app.Config.Server), registry, app.Logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		app.Logger.Fatal("Schema registry exited", zap.Error(err))
+	case <-ctx.Done():
+		if err := server.Shutdown(context.Background()); err != nil {
+			app.Logger.Error("Schema registry shutdown failed", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/benchmark"
+	"go-transport-prac/pkg/sdl/faker"
+)
+
+// bench compares serialization of the same users across formats
+func bench(args []string) error {
+	defaults := benchmark.DefaultFormatConfig()
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	formats := fs.String("formats", strings.Join(defaults.Formats, ","), "comma-separated formats to compare")
+	records := fs.Int("records", defaults.Records, "users in the dataset")
+	seed := fs.Int64("seed", 0, "seed of the generated users")
+	iterations := fs.Int("iterations", defaults.Iterations, "timed iterations per format and direction")
+	warmup := fs.Int("warmup", defaults.Warmup, "untimed iterations before measuring")
+	output := fs.String("o", "markdown", "output: markdown, csv or json")
+	cpuProfile := fs.String("cpuprofile", "", "write a CPU profile of the comparison to this file")
+	results := fs.String("results", "", "save the run to this results directory")
+	label := fs.String("label", "", "label of the saved run, e.g. a commit")
+	baseline := fs.String("baseline", "", "compare against this run file, or latest in the results directory")
+	threshold := fs.Float64("threshold", 10, "percentage by which a metric must worsen to fail the comparison")
+	fs.Parse(args)
+
+	if *records <= 0 {
+		return fmt.Errorf("records must be positive")
+	}
+	write := map[string]func(*benchmark.FormatReport) error{
+		"markdown": func(r *benchmark.FormatReport) error { return r.WriteMarkdown(os.Stdout) },
+		"csv":      func(r *benchmark.FormatReport) error { return r.WriteCSV(os.Stdout) },
+		"json":     func(r *benchmark.FormatReport) error { return r.WriteJSON(os.Stdout) },
+	}[*output]
+	if write == nil {
+		return fmt.Errorf("unknown output %q", *output)
+	}
+	if *baseline == "latest" && *results == "" {
+		return fmt.Errorf("-baseline latest requires -results")
+	}
+
+	manager, err := avro.NewManager("")
+	if err != nil {
+		return err
+	}
+	config := benchmark.FormatConfig{
+		Formats:    strings.Split(*formats, ","),
+		Iterations: *iterations,
+		Warmup:     *warmup,
+		CPUProfile: *cpuProfile,
+	}
+	users, err := faker.Records[avro.User](faker.New(*seed), manager.GetUserSchema(), *records)
+	if err != nil {
+		return err
+	}
+	report, err := benchmark.CompareFormats(users, config)
+	if err != nil {
+		return err
+	}
+	if err := write(report); err != nil {
+		return err
+	}
+	return track(benchmark.NewRun(*label, report.Metrics()), *results, *baseline, *threshold/100)
+}
+
+// track compares run against the baseline, before saving it so latest means
+// the previous run, and fails when a metric regressed
+func track(run *benchmark.Run, results, baseline string, threshold float64) error {
+	history := benchmark.NewHistory(results)
+
+	var comparison *benchmark.Comparison
+	if baseline != "" {
+		var old *benchmark.Run
+		var err error
+		if baseline == "latest" {
+			old, err = history.Latest()
+		} else {
+			old, err = benchmark.ReadRun(baseline)
+		}
+		switch {
+		case errors.Is(err, benchmark.ErrNoRuns):
+			fmt.Fprintln(os.Stderr, "no baseline recorded yet")
+		case err != nil:
+			return err
+		default:
+			comparison = benchmark.Compare(old, run, threshold)
+			fmt.Fprintln(os.Stderr)
+			if err := comparison.WriteMarkdown(os.Stderr); err != nil {
+				return err
+			}
+		}
+	}
+
+	if results != "" {
+		path, err := history.Save(run)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "saved run to %s\n", path)
+	}
+	if comparison != nil {
+		return comparison.Err()
+	}
+	return nil
+}
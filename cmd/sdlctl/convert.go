@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/hamba/avro/v2/ocf"
+
+	"go-transport-prac/pkg/sdl/converter"
+	"go-transport-prac/pkg/sdl/parquet"
+)
+
+// convert converts a file, choosing the formats by extension
+func convert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	codec := fs.String("avro-codec", "null", "codec of written Avro files: null, deflate, snappy or zstandard")
+	compression := fs.String("parquet-compression", "", "compression of written Parquet files: none, snappy, gzip or zstd")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: sdlctl convert [options] <src> <dst>")
+		fmt.Fprintln(fs.Output(), "\nFormats by extension: .json, .avro/.ocf, .pb/.binpb/.protobuf, .parquet, .arrows")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("expected a source and a destination")
+	}
+
+	c, err := converter.New()
+	if err != nil {
+		return err
+	}
+	opts := parquet.WriterOptions{Compression: parquet.Codec(*compression)}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	c.WithWriterOptions(opts).WithOCFOptions(ocf.WithCodec(ocf.CodecName(*codec)))
+
+	result, err := c.Convert(fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Converted %d %ss from %s to %s\n", result.Records, result.Model, fs.Arg(0), fs.Arg(1))
+	return nil
+}
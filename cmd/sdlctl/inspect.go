@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/converter"
+	"go-transport-prac/pkg/sdl/parquet"
+)
+
+// fileInfo is what inspect reports for any format
+type fileInfo struct {
+	Path    string              `json:"path"`
+	Format  converter.Format    `json:"format"`
+	Size    int64               `json:"size"`
+	Model   converter.Model     `json:"model,omitempty"`
+	Records int64               `json:"records"`
+	Codec   string              `json:"codec,omitempty"`
+	Schema  json.RawMessage     `json:"schema,omitempty"`
+	Parquet *parquet.FileReport `json:"parquet,omitempty"`
+}
+
+// inspect prints the format, schema and statistics of a file
+func inspect(args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: sdlctl inspect [options] <file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected one file")
+	}
+
+	info, err := inspectFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(info)
+	}
+	return printInfo(info)
+}
+
+func inspectFile(path string) (*fileInfo, error) {
+	format, err := converter.FormatOf(path)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	info := &fileInfo{Path: path, Format: format, Size: stat.Size()}
+
+	switch format {
+	case converter.FormatAvro:
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		ocfInfo, err := avro.InspectOCF(file)
+		if err != nil {
+			return nil, err
+		}
+		info.Records, info.Codec, info.Schema = int64(ocfInfo.Records), ocfInfo.Codec, json.RawMessage(ocfInfo.Schema)
+		if _, err := file.Seek(0, io.SeekStart); err == nil {
+			info.Model, _ = converter.DetectAvroModel(file)
+		}
+
+	case converter.FormatParquet:
+		report, err := parquet.NewFileInspector(parquet.NewSimpleManager(filepath.Dir(path))).Inspect(filepath.Base(path))
+		if err != nil {
+			return nil, err
+		}
+		info.Parquet, info.Records = report, report.NumRows
+		info.Model, _ = converter.DetectParquetModel(path)
+
+	default:
+		c, err := converter.New()
+		if err != nil {
+			return nil, err
+		}
+		users, err := c.ReadUsers(path)
+		if err != nil {
+			return nil, err
+		}
+		info.Model, info.Records = converter.ModelUser, int64(len(users))
+	}
+	return info, nil
+}
+
+func printInfo(info *fileInfo) error {
+	fmt.Printf("File:     %s\n", info.Path)
+	fmt.Printf("Format:   %s\n", info.Format)
+	fmt.Printf("Size:     %d bytes\n", info.Size)
+	if info.Model != "" {
+		fmt.Printf("Model:    %s\n", info.Model)
+	}
+	fmt.Printf("Records:  %d\n", info.Records)
+	if info.Codec != "" {
+		fmt.Printf("Codec:    %s\n", info.Codec)
+	}
+
+	if len(info.Schema) > 0 {
+		var schema bytes.Buffer
+		if err := json.Indent(&schema, info.Schema, "", "  "); err != nil {
+			schema.Write(info.Schema)
+		}
+		fmt.Printf("\nSchema:\n%s\n", schema.String())
+	}
+
+	if report := info.Parquet; report != nil {
+		fmt.Printf("Row groups: %d\n", len(report.RowGroups))
+		if report.CreatedBy != "" {
+			fmt.Printf("Created by: %s\n", report.CreatedBy)
+		}
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "COLUMN\tTYPE\tCOMPRESSION\tVALUES\tNULLS\tSIZE\tRATIO\tMIN\tMAX")
+		for _, c := range report.Columns {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%.2f\t%s\t%s\n", c.Path, c.Type, c.Compression,
+				c.NumValues, c.NullCount, c.CompressedSize, c.CompressionRatio(), c.Min, c.Max)
+		}
+		return w.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+)
+
+// command is one sdlctl subcommand
+type command struct {
+	summary string
+	run     func(args []string) error
+}
+
+var commands = map[string]command{
+	"convert":   {"convert a file between json, avro, protobuf and parquet", convert},
+	"inspect":   {"show the format, schema and statistics of a file", inspect},
+	"validate":  {"validate a file against an Avro or JSON Schema", validate},
+	"bench":     {"compare serialization across formats", bench},
+	"soak":      {"run a scenario for hours, tracking memory, goroutines and errors", soak},
+	"retention": {"delete old Avro and Parquet files by age, size or count", retain},
+	"export":    {"stream an Avro or Parquet file to gzip/zstd-compressed NDJSON", export},
+	"import":    {"write an NDJSON file to an Avro or Parquet file", importNDJSON},
+	"cdc":       {"diff two NDJSON user snapshots into Avro change events and replay them", captureChanges},
+	"encrypt":   {"encrypt plain files, or re-encrypt files, with the configured key", encryptFiles},
+	"pin":       {"check a schema pin upgrade and print the new KAFKA_SCHEMA_PINS", upgradePin},
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "Usage: sdlctl <command> [options]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, `Run "sdlctl <command> -h" for the options of a command.`)
+}
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name, args := os.Args[1], os.Args[2:]
+	cmd, ok := commands[name]
+	if !ok {
+		if name != "-h" && name != "-help" && name != "--help" && name != "help" {
+			fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		}
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(args); err != nil {
+		log.Fatalf("%s: %v", name, err)
+	}
+}
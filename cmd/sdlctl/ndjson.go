@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/hamba/avro/v2/ocf"
+
+	"go-transport-prac/pkg/sdl/converter"
+	"go-transport-prac/pkg/sdl/parquet"
+)
+
+// export streams an Avro or Parquet file to newline-delimited JSON
+func export(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: sdlctl export <src.avro|src.parquet> <dst.ndjson[.gz|.zst]>")
+		fmt.Fprintln(fs.Output(), "\nThe destination is gzip compressed for .gz and zstd compressed for .zst")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("expected a source and a destination")
+	}
+
+	c, err := converter.New()
+	if err != nil {
+		return err
+	}
+	result, err := c.ExportNDJSON(fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d %ss from %s to %s\n", result.Records, result.Model, fs.Arg(0), fs.Arg(1))
+	return nil
+}
+
+// importNDJSON writes the records of a newline-delimited JSON file to an Avro
+// or Parquet file
+func importNDJSON(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	model := fs.String("model", "user", "model of the records: user, product, order or analytics")
+	codec := fs.String("avro-codec", "null", "codec of written Avro files: null, deflate, snappy or zstandard")
+	compression := fs.String("parquet-compression", "", "compression of written Parquet files: none, snappy, gzip or zstd")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: sdlctl import [options] <src.ndjson[.gz|.zst]> <dst.avro|dst.parquet>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("expected a source and a destination")
+	}
+
+	m, err := converter.ParseModel(*model)
+	if err != nil {
+		return err
+	}
+	c, err := converter.New()
+	if err != nil {
+		return err
+	}
+	opts := parquet.WriterOptions{Compression: parquet.Codec(*compression)}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	c.WithWriterOptions(opts).WithOCFOptions(ocf.WithCodec(ocf.CodecName(*codec)))
+
+	result, err := c.ImportNDJSON(fs.Arg(0), fs.Arg(1), m)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d %ss from %s to %s\n", result.Records, result.Model, fs.Arg(0), fs.Arg(1))
+	return nil
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"go-transport-prac/internal/config"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/transport/kafka"
+	"go-transport-prac/pkg/transport/schemaregistry"
+)
+
+// upgradePin moves a subject's schema pin forward, refusing versions readers
+// on the current pin could not decode, and prints the KAFKA_SCHEMA_PINS value
+// to deploy producers and consumers with
+func upgradePin(args []string) error {
+	fs := flag.NewFlagSet("pin", flag.ExitOnError)
+	subject := fs.String("subject", "", "registry subject to upgrade, e.g. users-value")
+	from := fs.Int("from", 0, "version the subject is pinned to; defaults to its KAFKA_SCHEMA_PINS entry")
+	to := fs.Int("to", 0, "version to upgrade to; 0 for the latest")
+	registryURL := fs.String("registry", "", "schema registry to read the subject's versions from, e.g. http://localhost:8085")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: sdlctl pin -subject <subject> [options] <v1.avsc> <v2.avsc>...")
+		fmt.Fprintln(fs.Output(), "       sdlctl pin -subject <subject> -registry <url> [options]")
+		fmt.Fprintln(fs.Output(), "\nThe schema files are the subject's versions, oldest first")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *subject == "" || (fs.NArg() == 0) == (*registryURL == "") {
+		fs.Usage()
+		return fmt.Errorf("expected a subject and either its schema files or a registry")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	pins := make(map[string]int, len(cfg.Kafka.SchemaPins)+1)
+	for s, version := range cfg.Kafka.SchemaPins {
+		pins[s] = version
+	}
+	if *from == 0 {
+		*from = pins[*subject]
+	}
+	if *from == 0 {
+		return fmt.Errorf("subject %s is not pinned: set KAFKA_SCHEMA_PINS or pass -from", *subject)
+	}
+
+	// Every file becomes a version; whether pinned readers can follow is
+	// what UpgradePin decides
+	registry := avro.NewSchemaRegistry()
+	if err := registry.SetCompatibilityLevel(*subject, avro.CompatibilityNone); err != nil {
+		return err
+	}
+	if *registryURL != "" {
+		if err := mirrorVersions(registry, *registryURL, *subject); err != nil {
+			return err
+		}
+	}
+	for _, path := range fs.Args() {
+		schema, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read schema: %w", err)
+		}
+		if _, err := registry.RegisterSchema(*subject, string(schema)); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	manager, err := avro.NewManager("")
+	if err != nil {
+		return err
+	}
+	codec := kafka.NewAvroCodec(manager, registry)
+	if err := codec.PinSchemaVersion(*subject, *from); err != nil {
+		return err
+	}
+	version, err := codec.UpgradePin(*subject, *to)
+	if err != nil {
+		return err
+	}
+
+	if version == *from {
+		fmt.Printf("%s is already pinned to version %d\n", *subject, version)
+	} else {
+		fmt.Printf("Readers on %s version %d can decode version %d\n", *subject, *from, version)
+	}
+	pins[*subject] = version
+	fmt.Printf("KAFKA_SCHEMA_PINS=%s\n", formatPins(pins))
+	return nil
+}
+
+// mirrorVersions registers the versions subject has at the registry at
+// baseURL into registry, keeping their version numbers
+func mirrorVersions(registry *avro.SchemaRegistry, baseURL, subject string) error {
+	ctx := context.Background()
+	client := schemaregistry.NewClient(baseURL)
+	versions, err := client.Versions(ctx, subject)
+	var apiErr *schemaregistry.APIError
+	if errors.As(err, &apiErr) && apiErr.Code == 40401 {
+		return fmt.Errorf("subject %s is not registered at %s", subject, baseURL)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, number := range versions {
+		version, err := client.Version(ctx, subject, number)
+		if err != nil {
+			return err
+		}
+		id, err := registry.RegisterSchema(subject, version.Schema)
+		if err != nil {
+			return fmt.Errorf("version %d: %w", number, err)
+		}
+		metadata, err := registry.GetSchema(id)
+		if err != nil {
+			return err
+		}
+		// Deleted versions leave gaps the local registry would renumber
+		if metadata.Version != version.Version {
+			return fmt.Errorf("subject %s has deleted versions: version %d would be mirrored as %d", subject, version.Version, metadata.Version)
+		}
+	}
+	return nil
+}
+
+// formatPins renders pins in the subject:version list KAFKA_SCHEMA_PINS reads
+func formatPins(pins map[string]int) string {
+	entries := make([]string, 0, len(pins))
+	for subject, version := range pins {
+		entries = append(entries, fmt.Sprintf("%s:%d", subject, version))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/parquet"
+	"go-transport-prac/pkg/sdl/retention"
+)
+
+// retain applies retention policies to the Avro and Parquet files of data
+// directories, once or every -interval
+func retain(args []string) error {
+	fs := flag.NewFlagSet("retention", flag.ExitOnError)
+	maxAge := fs.Duration("max-age", 0, "delete files older than this; 0 disables")
+	maxBytes := fs.Int64("max-bytes", 0, "delete the oldest files until each format of a directory fits; 0 disables")
+	keepLast := fs.Int("keep-last", 0, "keep this many newest files per name prefix; 0 disables")
+	dryRun := fs.Bool("dry-run", false, "report what would be deleted without deleting it")
+	interval := fs.Duration("interval", 0, "apply the policies every interval until interrupted; 0 applies them once")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: sdlctl retention [options] <dir>...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	policy := retention.Policy{MaxAge: *maxAge, MaxTotalSize: *maxBytes, KeepLast: *keepLast}
+	manager := retention.NewManager().WithDryRun(*dryRun)
+	for _, dir := range fs.Args() {
+		avroManager, err := avro.NewManager(dir)
+		if err != nil {
+			return err
+		}
+		parquetManager := parquet.NewSimpleManager(dir)
+		manager.Add(dir+" (avro)", avroManager, policy).Add(dir+" (parquet)", parquetManager, policy)
+	}
+
+	if *interval <= 0 {
+		report, err := manager.Apply(context.Background())
+		report.Print(os.Stdout)
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	manager.Run(ctx, *interval, func(report retention.Report, err error) {
+		fmt.Printf("%s\n", report.RanAt.Format(time.RFC3339))
+		report.Print(os.Stdout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "retention: %v\n", err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/pkg/sdl/benchmark"
+)
+
+// soak runs a soak test, printing a line per snapshot, and fails when the
+// growth exceeds the limits
+func soak(args []string) error {
+	defaults := benchmark.DefaultSoakConfig()
+	fs := flag.NewFlagSet("soak", flag.ExitOnError)
+	scenario := fs.String("scenario", defaults.Scenario, "produce, consume, persist or all")
+	duration := fs.Duration("duration", defaults.Duration, "how long to run; 0 runs until interrupted")
+	interval := fs.Duration("interval", defaults.SnapshotInterval, "time between snapshots")
+	workers := fs.Int("workers", defaults.Workers, "goroutines per scenario")
+	records := fs.Int("records", defaults.RecordsPerCycle, "users per cycle")
+	seed := fs.Int64("seed", defaults.DataSeed, "seed of the generated users")
+	dir := fs.String("dir", "tmp/soak", "directory for the files the scenarios write")
+	output := fs.String("o", "", "write the snapshots as JSON to this file")
+	maxHeap := fs.Int64("max-heap-growth", 64, "fail when the live heap grows by more MiB; 0 disables")
+	maxGoroutines := fs.Int("max-goroutine-growth", 10, "fail when more goroutines are left running; 0 disables")
+	maxErrors := fs.Float64("max-error-rate", 0.01, "fail above this share of failed cycles; 0 disables")
+	fs.Parse(args)
+
+	config := defaults
+	config.Scenario = *scenario
+	config.Duration = *duration
+	config.SnapshotInterval = *interval
+	config.Workers = *workers
+	config.RecordsPerCycle = *records
+	config.DataSeed = *seed
+
+	appLogger, err := logger.NewProduction()
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+	s, err := benchmark.NewSoak(*dir, config)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("%-10s %12s %12s %10s %10s %8s %10s\n", "ELAPSED", "HEAP", "OBJECTS", "GOROUTINES", "CYCLES", "ERRORS", "ERROR RATE")
+	result, err := s.WithLogger(appLogger).OnSnapshot(func(snap benchmark.Snapshot) {
+		fmt.Printf("%-10s %12d %12d %10d %10d %8d %10.4f\n", snap.Elapsed.Round(time.Second), snap.HeapAlloc, snap.HeapObjects,
+			snap.Goroutines, snap.Ops, snap.Errors, snap.ErrorRate())
+		if snap.LastError != "" {
+			fmt.Printf("  last error: %s\n", snap.LastError)
+		}
+	}).Run(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%d cycles in %v, heap %+d bytes, goroutines %+d, error rate %.4f\n",
+		result.Ops(), result.Elapsed.Round(time.Second), result.HeapGrowth(), result.GoroutineGrowth(), result.ErrorRate())
+
+	if *output != "" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode result: %w", err)
+		}
+		if err := os.WriteFile(*output, data, 0644); err != nil {
+			return fmt.Errorf("failed to write result: %w", err)
+		}
+	}
+
+	return result.Check(benchmark.SoakLimits{
+		MaxHeapGrowth:      *maxHeap << 20,
+		MaxGoroutineGrowth: *maxGoroutines,
+		MaxErrorRate:       *maxErrors,
+	})
+}
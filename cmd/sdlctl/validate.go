@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	hamba "github.com/hamba/avro/v2"
+
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/converter"
+	"go-transport-prac/pkg/sdl/jsonschema"
+)
+
+// validate checks a file against an Avro schema (.avsc) or a JSON Schema (.json)
+func validate(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	schemaPath := fs.String("schema", "", "Avro schema (.avsc) or JSON Schema (.json) to validate against")
+	backend := fs.String("backend", string(jsonschema.BackendSanthosh), "JSON Schema validator: santhosh (drafts 4 to 2020-12) or xeipuuv (drafts 4 to 7)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: sdlctl validate -schema <schema> <file>")
+		fmt.Fprintln(fs.Output(), "\nAn Avro schema must be able to read the Avro file; every user of a")
+		fmt.Fprintln(fs.Output(), "json, avro, protobuf or parquet file must match a JSON Schema.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 || *schemaPath == "" {
+		fs.Usage()
+		return fmt.Errorf("expected -schema and one file")
+	}
+
+	schema, err := os.ReadFile(*schemaPath)
+	if err != nil {
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+	if strings.EqualFold(filepath.Ext(*schemaPath), ".avsc") {
+		return validateAvro(string(schema), fs.Arg(0))
+	}
+	return validateJSONSchema(jsonschema.Backend(*backend), string(schema), fs.Arg(0))
+}
+
+// validateAvro checks that readers using schema can resolve the file's writer schema
+func validateAvro(schema, path string) error {
+	reader, err := hamba.Parse(schema)
+	if err != nil {
+		return fmt.Errorf("failed to parse avro schema: %w", err)
+	}
+	if format, err := converter.FormatOf(path); err != nil || format != converter.FormatAvro {
+		return fmt.Errorf("an avro schema validates .avro files, not %s", path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := avro.InspectOCF(file)
+	if err != nil {
+		return err
+	}
+	writer, err := hamba.Parse(info.Schema)
+	if err != nil {
+		return fmt.Errorf("failed to parse the file's schema: %w", err)
+	}
+
+	if incompatibilities := avro.CheckReaderWriterCompatibility(reader, writer); len(incompatibilities) > 0 {
+		for _, inc := range incompatibilities {
+			fmt.Printf("  %s\n", inc)
+		}
+		return fmt.Errorf("%d incompatibilities between the schema and %s", len(incompatibilities), path)
+	}
+	name := string(reader.Type())
+	if named, ok := reader.(hamba.NamedSchema); ok {
+		name = named.FullName()
+	}
+	fmt.Printf("%s: %d records readable with %s\n", path, info.Records, name)
+	return nil
+}
+
+// validateJSONSchema checks the users of the file against a JSON Schema: the
+// whole list when the schema describes an array, otherwise every user
+func validateJSONSchema(backend jsonschema.Backend, schema, path string) error {
+	validator, err := jsonschema.NewValidator(backend, nil)
+	if err != nil {
+		return err
+	}
+	if err := validator.AddSchemaJSON("schema", schema); err != nil {
+		return fmt.Errorf("failed to load json schema: %w", err)
+	}
+	var root struct {
+		Type jsonschema.Types `json:"type"`
+	}
+	json.Unmarshal([]byte(schema), &root)
+
+	c, err := converter.New()
+	if err != nil {
+		return err
+	}
+	users, err := c.ReadUsers(path)
+	if err != nil {
+		return err
+	}
+
+	// Validate the JSON form, which is what the schema describes
+	var documents []interface{}
+	if describesArray(root.Type) {
+		document, err := jsonDocument(users)
+		if err != nil {
+			return err
+		}
+		documents = append(documents, document)
+	} else {
+		for _, u := range users {
+			document, err := jsonDocument(u)
+			if err != nil {
+				return err
+			}
+			documents = append(documents, document)
+		}
+	}
+
+	invalid := 0
+	for i, document := range documents {
+		result, err := validator.ValidateWithDetails("schema", document)
+		if err != nil {
+			return err
+		}
+		if result.Valid {
+			continue
+		}
+		invalid++
+		for _, e := range result.Errors {
+			if len(documents) == 1 {
+				fmt.Printf("  %s: %s\n", e.InstanceLocation, e.Message)
+			} else {
+				fmt.Printf("  user %d (id %d): %s: %s\n", i, users[i].ID, e.InstanceLocation, e.Message)
+			}
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%s does not match the schema", path)
+	}
+	fmt.Printf("%s: all %d users are valid\n", path, len(users))
+	return nil
+}
+
+// describesArray reports whether a schema's type admits arrays
+func describesArray(types jsonschema.Types) bool {
+	for _, t := range types {
+		if t == "array" {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonDocument converts v to the generic form encoding/json decodes to
+func jsonDocument(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode users: %w", err)
+	}
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to decode users: %w", err)
+	}
+	return document, nil
+}
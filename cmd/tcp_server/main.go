@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/wire"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+	grpctransport "go-transport-prac/pkg/transport/grpc"
+	"go-transport-prac/pkg/transport/tcp"
+)
+
+func main() {
+	app, err := wire.InitializeApplication()
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
+	}
+
+	// The gRPC user service already takes and returns protobuf messages, so
+	// its methods serve the same requests over raw TCP
+	users := grpctransport.NewUserService(grpctransport.NewStore(nil))
+
+	server := tcp.NewServer(tcp.NewConfig(app.Config.Server), app.Logger)
+	server.Handle("user.Create", tcp.ProtoHandler(func() *user.CreateUserRequest { return &user.CreateUserRequest{} }, users.CreateUser))
+	server.Handle("user.Get", tcp.ProtoHandler(func() *user.GetUserRequest { return &user.GetUserRequest{} }, users.GetUser))
+	server.Handle("user.Update", tcp.ProtoHandler(func() *user.UpdateUserRequest { return &user.UpdateUserRequest{} }, users.UpdateUser))
+	server.Handle("user.Delete", tcp.ProtoHandler(func() *user.DeleteUserRequest { return &user.DeleteUserRequest{} }, users.DeleteUser))
+	server.Handle("user.List", tcp.ProtoHandler(func() *user.ListUsersRequest { return &user.ListUsersRequest{} }, users.ListUsers))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		app.Logger.Fatal("TCP server exited", zap.Error(err))
+	case <-ctx.Done():
+		if err := server.Shutdown(context.Background()); err != nil {
+			app.Logger.Error("TCP server shutdown failed", zap.Error(err))
+		}
+	}
+}
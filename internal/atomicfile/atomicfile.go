@@ -0,0 +1,113 @@
+// Package atomicfile writes files through a temporary file in the same
+// directory that is renamed over the destination once complete, so readers
+// and crashes see either the old file or the whole new one, never a part
+package atomicfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TempSuffix ends the names of temporary files, so listings can skip files
+// left behind by a crash
+const TempSuffix = ".tmp"
+
+// Perm is the permission of committed files
+const Perm = 0644
+
+// File is a temporary file that replaces its destination on Commit
+type File struct {
+	*os.File
+	path string
+	sync bool
+	done bool
+}
+
+// Create starts writing the file at path. With sync set, Commit flushes the
+// file and the rename to stable storage before returning
+func Create(path string, sync bool) (*File, error) {
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := os.CreateTemp(dir, "."+base+".*"+TempSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	return &File{File: tmp, path: path, sync: sync}, nil
+}
+
+// Commit replaces the destination with everything written so far
+func (f *File) Commit() error {
+	if f.done {
+		return fmt.Errorf("%s is already committed or aborted", f.path)
+	}
+	f.done = true
+	if err := f.commit(); err != nil {
+		f.File.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+func (f *File) commit() error {
+	if f.sync {
+		if err := f.File.Sync(); err != nil {
+			return fmt.Errorf("failed to sync file: %w", err)
+		}
+	}
+	if err := f.File.Chmod(Perm); err != nil {
+		return fmt.Errorf("failed to set file permissions: %w", err)
+	}
+	if err := f.File.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	if err := os.Rename(f.Name(), f.path); err != nil {
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+	if f.sync {
+		// Persist the rename itself; not every platform supports syncing
+		// directories
+		if d, err := os.Open(filepath.Dir(f.path)); err == nil {
+			d.Sync()
+			d.Close()
+		}
+	}
+	return nil
+}
+
+// Abort discards the temporary file and leaves the destination as it was.
+// After Commit it does nothing, so it can be deferred
+func (f *File) Abort() error {
+	if f.done {
+		return nil
+	}
+	f.done = true
+	f.File.Close()
+	if err := os.Remove(f.Name()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove temporary file: %w", err)
+	}
+	return nil
+}
+
+// IsTemp reports whether name is a temporary file of this package
+func IsTemp(name string) bool {
+	base := filepath.Base(name)
+	return strings.HasPrefix(base, ".") && strings.HasSuffix(base, TempSuffix)
+}
+
+// WriteFile writes data to path atomically
+func WriteFile(path string, data []byte, sync bool) error {
+	f, err := Create(path, sync)
+	if err != nil {
+		return err
+	}
+	defer f.Abort()
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return f.Commit()
+}
@@ -0,0 +1,66 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCommitAndAbort(t *testing.T) {
+	testDir := "tmp/test_atomicfile"
+	defer os.RemoveAll(testDir)
+	if err := os.MkdirAll(testDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	path := filepath.Join(testDir, "data.txt")
+
+	if err := WriteFile(path, []byte("old"), true); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	f, err := Create(path, false)
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	f.Write([]byte("half written"))
+	if data, _ := os.ReadFile(path); string(data) != "old" {
+		t.Errorf("Expected the destination to be untouched while writing, got %q", data)
+	}
+	if !IsTemp(f.Name()) {
+		t.Errorf("Expected %s to be recognized as temporary", f.Name())
+	}
+	if err := f.Abort(); err != nil {
+		t.Fatalf("Failed to abort: %v", err)
+	}
+
+	entries, _ := os.ReadDir(testDir)
+	if len(entries) != 1 {
+		t.Errorf("Expected the temporary file to be removed, found %d files", len(entries))
+	}
+	if data, _ := os.ReadFile(path); string(data) != "old" {
+		t.Errorf("Expected an aborted write to keep the old file, got %q", data)
+	}
+
+	f, err = Create(path, false)
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	f.Write([]byte("new"))
+	if err := f.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	if err := f.Abort(); err != nil {
+		t.Errorf("Expected Abort after Commit to do nothing, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "new" {
+		t.Errorf("Expected the committed contents, got %q", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != Perm {
+		t.Errorf("Expected permissions %v, got %v", os.FileMode(Perm), info.Mode().Perm())
+	}
+	if err := f.Commit(); err == nil {
+		t.Error("Expected a second commit to fail")
+	}
+
+	t.Log("✓ Files are replaced whole or not at all")
+}
@@ -0,0 +1,154 @@
+// Package compress compresses whole buffers. Decompression is capped at
+// MaxDecompressedSize, so a small crafted input cannot claim unbounded memory
+package compress
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// MaxDecompressedSize is the largest buffer Decompress inflates
+const MaxDecompressedSize = 64 << 20
+
+// ErrTooLarge reports data that inflates past MaxDecompressedSize
+var ErrTooLarge = fmt.Errorf("decompressed data exceeds the %d byte limit", MaxDecompressedSize)
+
+// Codec compresses and decompresses whole buffers with one algorithm
+type Codec struct {
+	Compress   func(data []byte) ([]byte, error)
+	Decompress func(data []byte) ([]byte, error)
+}
+
+var (
+	// None passes data through unchanged
+	None = Codec{
+		Compress:   func(data []byte) ([]byte, error) { return data, nil },
+		Decompress: func(data []byte) ([]byte, error) { return data, nil },
+	}
+
+	// Gzip compresses with gzip framing, as Kafka producers do
+	Gzip = Codec{
+		Compress: func(data []byte) ([]byte, error) {
+			var buf bytes.Buffer
+			w := gzip.NewWriter(&buf)
+			return finish(&buf, w, data)
+		},
+		Decompress: func(data []byte) ([]byte, error) {
+			r, err := gzip.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			defer r.Close()
+			return readCapped(r)
+		},
+	}
+
+	// Deflate compresses raw deflate streams
+	Deflate = Codec{
+		Compress: func(data []byte) ([]byte, error) {
+			var buf bytes.Buffer
+			w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+			if err != nil {
+				return nil, err
+			}
+			return finish(&buf, w, data)
+		},
+		Decompress: func(data []byte) ([]byte, error) {
+			r := flate.NewReader(bytes.NewReader(data))
+			defer r.Close()
+			return readCapped(r)
+		},
+	}
+
+	// Snappy compresses with the snappy block format
+	Snappy = Codec{
+		Compress: func(data []byte) ([]byte, error) { return snappy.Encode(nil, data), nil },
+		Decompress: func(data []byte) ([]byte, error) {
+			size, err := snappy.DecodedLen(data)
+			if err != nil {
+				return nil, err
+			}
+			if size > MaxDecompressedSize {
+				return nil, ErrTooLarge
+			}
+			return snappy.Decode(nil, data)
+		},
+	}
+
+	// Zstd compresses zstd frames
+	Zstd = Codec{
+		Compress: func(data []byte) ([]byte, error) {
+			encoder, _, err := zstdCodec()
+			if err != nil {
+				return nil, err
+			}
+			return encoder.EncodeAll(data, nil), nil
+		},
+		Decompress: func(data []byte) ([]byte, error) {
+			_, decoder, err := zstdCodec()
+			if err != nil {
+				return nil, err
+			}
+			payload, err := decoder.DecodeAll(data, nil)
+			if errors.Is(err, zstd.ErrDecoderSizeExceeded) {
+				return nil, ErrTooLarge
+			}
+			return payload, err
+		},
+	}
+)
+
+// zstd encoders and decoders are safe for concurrent EncodeAll/DecodeAll
+// calls, so one of each is shared once created
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+// zstdCodec returns the shared zstd encoder and decoder, creating them on first use
+func zstdCodec() (*zstd.Encoder, *zstd.Decoder, error) {
+	zstdOnce.Do(func() {
+		if zstdEncoder, zstdErr = zstd.NewWriter(nil); zstdErr != nil {
+			zstdErr = fmt.Errorf("failed to create zstd encoder: %w", zstdErr)
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(MaxDecompressedSize))
+		if zstdErr != nil {
+			zstdErr = fmt.Errorf("failed to create zstd decoder: %w", zstdErr)
+		}
+	})
+	return zstdEncoder, zstdDecoder, zstdErr
+}
+
+// finish writes data through w and closes it, returning what buf collected
+func finish(buf *bytes.Buffer, w io.WriteCloser, data []byte) ([]byte, error) {
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readCapped reads r to the end, failing once it passes MaxDecompressedSize
+func readCapped(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxDecompressedSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxDecompressedSize {
+		return nil, ErrTooLarge
+	}
+	return data, nil
+}
@@ -0,0 +1,47 @@
+package compress
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+var codecs = map[string]Codec{"none": None, "gzip": Gzip, "deflate": Deflate, "snappy": Snappy, "zstd": Zstd}
+
+func TestRoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte("go-transport-prac "), 1000)
+	for name, codec := range codecs {
+		compressed, err := codec.Compress(data)
+		if err != nil {
+			t.Fatalf("%s: failed to compress: %v", name, err)
+		}
+		decompressed, err := codec.Decompress(compressed)
+		if err != nil {
+			t.Fatalf("%s: failed to decompress: %v", name, err)
+		}
+		if !bytes.Equal(decompressed, data) {
+			t.Errorf("%s: round trip changed the data", name)
+		}
+	}
+
+	t.Log("✓ Every codec round-trips data")
+}
+
+func TestDecompressLimit(t *testing.T) {
+	oversized := make([]byte, MaxDecompressedSize+1)
+	for name, codec := range codecs {
+		if name == "none" {
+			continue
+		}
+		compressed, err := codec.Compress(oversized)
+		if err != nil {
+			t.Fatalf("%s: failed to compress: %v", name, err)
+		}
+		if _, err := codec.Decompress(compressed); !errors.Is(err, ErrTooLarge) {
+			t.Errorf("%s: expected %d bytes to be rejected, got %v", name, len(oversized), err)
+		}
+		t.Logf("%-8s rejected %d bytes compressed to %d", name, len(oversized), len(compressed))
+	}
+
+	t.Log("✓ Data inflating past the size limit is rejected")
+}
@@ -1,105 +1,186 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
+	"gopkg.in/yaml.v3"
 )
 
 // Config represents the application configuration
 type Config struct {
 	// Server configuration
-	Server ServerConfig `envconfig:"SERVER"`
+	Server ServerConfig `envconfig:"SERVER" yaml:"server"`
 	
 	// Database configuration
-	Database DatabaseConfig `envconfig:"DATABASE"`
+	Database DatabaseConfig `envconfig:"DATABASE" yaml:"database"`
 	
 	// Redis configuration
-	Redis RedisConfig `envconfig:"REDIS"`
+	Redis RedisConfig `envconfig:"REDIS" yaml:"redis"`
 	
 	// MinIO configuration
-	MinIO MinIOConfig `envconfig:"MINIO"`
+	MinIO MinIOConfig `envconfig:"MINIO" yaml:"minio"`
+	
+	// Kafka configuration
+	Kafka KafkaConfig `envconfig:"KAFKA" yaml:"kafka"`
+	
+	// NATS configuration
+	NATS NATSConfig `envconfig:"NATS" yaml:"nats"`
 	
 	// Logging configuration
-	Logging LoggingConfig `envconfig:"LOGGING"`
+	Logging LoggingConfig `envconfig:"LOGGING" yaml:"logging"`
+	
+	// Tracing configuration
+	Tracing TracingConfig `envconfig:"TRACING" yaml:"tracing"`
 	
 	// Development configuration
-	Development DevelopmentConfig `envconfig:"DEV"`
+	Development DevelopmentConfig `envconfig:"DEV" yaml:"development"`
+
+	// Encryption-at-rest configuration for SDL files
+	Encryption EncryptionConfig `envconfig:"ENCRYPTION" yaml:"encryption"`
 }
 
 // ServerConfig holds server-related configuration
 type ServerConfig struct {
-	HTTPPort     int           `envconfig:"HTTP_PORT" default:"8080"`
-	GRPCPort     int           `envconfig:"GRPC_PORT" default:"8081"`
-	WSPort       int           `envconfig:"WS_PORT" default:"8082"`
-	GraphQLPort  int           `envconfig:"GRAPHQL_PORT" default:"9090"`
-	ReadTimeout  time.Duration `envconfig:"READ_TIMEOUT" default:"30s"`
-	WriteTimeout time.Duration `envconfig:"WRITE_TIMEOUT" default:"30s"`
-	IdleTimeout  time.Duration `envconfig:"IDLE_TIMEOUT" default:"120s"`
-	Host         string        `envconfig:"HOST" default:"localhost"`
-	TLSEnabled   bool          `envconfig:"TLS_ENABLED" default:"false"`
-	CertFile     string        `envconfig:"CERT_FILE"`
-	KeyFile      string        `envconfig:"KEY_FILE"`
+	HTTPPort     int           `envconfig:"HTTP_PORT" default:"8080" yaml:"http_port"`
+	GRPCPort     int           `envconfig:"GRPC_PORT" default:"8081" yaml:"grpc_port"`
+	WSPort       int           `envconfig:"WS_PORT" default:"8082" yaml:"ws_port"`
+	GraphQLPort  int           `envconfig:"GRAPHQL_PORT" default:"9090" yaml:"graphql_port"`
+	TCPPort      int           `envconfig:"TCP_PORT" default:"8083" yaml:"tcp_port"`
+	// RegistryPort serves the Confluent-compatible schema registry API
+	RegistryPort int           `envconfig:"REGISTRY_PORT" default:"8085" yaml:"registry_port"`
+	ReadTimeout  time.Duration `envconfig:"READ_TIMEOUT" default:"30s" yaml:"read_timeout"`
+	WriteTimeout time.Duration `envconfig:"WRITE_TIMEOUT" default:"30s" yaml:"write_timeout"`
+	IdleTimeout  time.Duration `envconfig:"IDLE_TIMEOUT" default:"120s" yaml:"idle_timeout"`
+	Host         string        `envconfig:"HOST" default:"localhost" yaml:"host"`
+	TLSEnabled   bool          `envconfig:"TLS_ENABLED" default:"false" yaml:"tls_enabled"`
+	CertFile     string        `envconfig:"CERT_FILE" yaml:"cert_file"`
+	KeyFile      string        `envconfig:"KEY_FILE" yaml:"key_file"`
+	// AdminEnabled serves the admin API for runtime settings on the HTTP server
+	AdminEnabled bool   `envconfig:"ADMIN_ENABLED" default:"false" yaml:"admin_enabled"`
+	AdminToken   string `envconfig:"ADMIN_TOKEN" yaml:"admin_token"`
+	// GRPCHealthEnabled and GRPCReflectionEnabled serve the gRPC health and
+	// reflection services next to the application services
+	GRPCHealthEnabled     bool `envconfig:"GRPC_HEALTH_ENABLED" default:"true" yaml:"grpc_health_enabled"`
+	GRPCReflectionEnabled bool `envconfig:"GRPC_REFLECTION_ENABLED" default:"false" yaml:"grpc_reflection_enabled"`
 }
 
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
-	Host         string        `envconfig:"HOST" default:"localhost"`
-	Port         int           `envconfig:"PORT" default:"5432"`
-	Username     string        `envconfig:"USERNAME" default:"transport_user"`
-	Password     string        `envconfig:"PASSWORD" default:"transport_pass"`
-	Name         string        `envconfig:"NAME" default:"transport_db"`
-	SSLMode      string        `envconfig:"SSL_MODE" default:"disable"`
-	MaxOpenConns int           `envconfig:"MAX_OPEN_CONNS" default:"25"`
-	MaxIdleConns int           `envconfig:"MAX_IDLE_CONNS" default:"5"`
-	MaxLifetime  time.Duration `envconfig:"MAX_LIFETIME" default:"300s"`
+	Host         string        `envconfig:"HOST" default:"localhost" yaml:"host"`
+	Port         int           `envconfig:"PORT" default:"5432" yaml:"port"`
+	Username     string        `envconfig:"USERNAME" default:"transport_user" yaml:"username"`
+	Password     string        `envconfig:"PASSWORD" default:"transport_pass" yaml:"password"`
+	Name         string        `envconfig:"NAME" default:"transport_db" yaml:"name"`
+	SSLMode      string        `envconfig:"SSL_MODE" default:"disable" yaml:"ssl_mode"`
+	MaxOpenConns int           `envconfig:"MAX_OPEN_CONNS" default:"25" yaml:"max_open_conns"`
+	MaxIdleConns int           `envconfig:"MAX_IDLE_CONNS" default:"5" yaml:"max_idle_conns"`
+	MaxLifetime  time.Duration `envconfig:"MAX_LIFETIME" default:"300s" yaml:"max_lifetime"`
 }
 
 // RedisConfig holds Redis configuration
 type RedisConfig struct {
-	Host         string        `envconfig:"HOST" default:"localhost"`
-	Port         int           `envconfig:"PORT" default:"6379"`
-	Password     string        `envconfig:"PASSWORD"`
-	Database     int           `envconfig:"DATABASE" default:"0"`
-	MaxRetries   int           `envconfig:"MAX_RETRIES" default:"3"`
-	PoolSize     int           `envconfig:"POOL_SIZE" default:"10"`
-	DialTimeout  time.Duration `envconfig:"DIAL_TIMEOUT" default:"5s"`
-	ReadTimeout  time.Duration `envconfig:"READ_TIMEOUT" default:"3s"`
-	WriteTimeout time.Duration `envconfig:"WRITE_TIMEOUT" default:"3s"`
+	Host         string        `envconfig:"HOST" default:"localhost" yaml:"host"`
+	Port         int           `envconfig:"PORT" default:"6379" yaml:"port"`
+	Password     string        `envconfig:"PASSWORD" yaml:"password"`
+	Database     int           `envconfig:"DATABASE" default:"0" yaml:"database"`
+	MaxRetries   int           `envconfig:"MAX_RETRIES" default:"3" yaml:"max_retries"`
+	PoolSize     int           `envconfig:"POOL_SIZE" default:"10" yaml:"pool_size"`
+	DialTimeout  time.Duration `envconfig:"DIAL_TIMEOUT" default:"5s" yaml:"dial_timeout"`
+	ReadTimeout  time.Duration `envconfig:"READ_TIMEOUT" default:"3s" yaml:"read_timeout"`
+	WriteTimeout time.Duration `envconfig:"WRITE_TIMEOUT" default:"3s" yaml:"write_timeout"`
 }
 
 // MinIOConfig holds MinIO configuration
 type MinIOConfig struct {
-	Endpoint        string `envconfig:"ENDPOINT" default:"localhost:9000"`
-	AccessKeyID     string `envconfig:"ACCESS_KEY_ID" default:"minioadmin"`
-	SecretAccessKey string `envconfig:"SECRET_ACCESS_KEY" default:"minioadmin"`
-	UseSSL          bool   `envconfig:"USE_SSL" default:"false"`
-	BucketName      string `envconfig:"BUCKET_NAME" default:"transport-data"`
-	Region          string `envconfig:"REGION" default:"us-east-1"`
+	Endpoint        string `envconfig:"ENDPOINT" default:"localhost:9000" yaml:"endpoint"`
+	AccessKeyID     string `envconfig:"ACCESS_KEY_ID" default:"minioadmin" yaml:"access_key_id"`
+	SecretAccessKey string `envconfig:"SECRET_ACCESS_KEY" default:"minioadmin" yaml:"secret_access_key"`
+	UseSSL          bool   `envconfig:"USE_SSL" default:"false" yaml:"use_ssl"`
+	BucketName      string `envconfig:"BUCKET_NAME" default:"transport-data" yaml:"bucket_name"`
+	Region          string `envconfig:"REGION" default:"us-east-1" yaml:"region"`
+}
+
+// KafkaConfig holds Kafka configuration
+type KafkaConfig struct {
+	Brokers  []string `envconfig:"BROKERS" default:"localhost:9092" yaml:"brokers"`
+	GroupID  string   `envconfig:"GROUP_ID" default:"go-transport-prac" yaml:"group_id"`
+	ClientID string   `envconfig:"CLIENT_ID" default:"go-transport-prac" yaml:"client_id"`
+	Format   string   `envconfig:"FORMAT" default:"avro" yaml:"format"`
+	// SchemaPins pins subjects to registry versions, e.g. "users-value:2,orders-value:1"
+	SchemaPins map[string]int `envconfig:"SCHEMA_PINS" yaml:"schema_pins"`
+	// SerializersFile is a JSON file of per-subject serializer settings
+	SerializersFile string `envconfig:"SERIALIZERS_FILE" yaml:"serializers_file"`
+	// MaxAttempts handler calls are made before a message is dead-lettered; 0 retries forever
+	MaxAttempts int `envconfig:"MAX_ATTEMPTS" default:"10" yaml:"max_attempts"`
+	// DeadLetterSuffix is appended to a topic to name its dead-letter topic
+	DeadLetterSuffix string `envconfig:"DEAD_LETTER_SUFFIX" default:".dlq" yaml:"dead_letter_suffix"`
+}
+
+// NATSConfig holds NATS configuration
+type NATSConfig struct {
+	URL    string `envconfig:"URL" default:"nats://localhost:4222" yaml:"url"`
+	Name   string `envconfig:"NAME" default:"go-transport-prac" yaml:"name"`
+	Format string `envconfig:"FORMAT" default:"avro" yaml:"format"`
+	// QueueGroup load-balances subscriptions across instances sharing the name; empty fans out to every instance
+	QueueGroup    string        `envconfig:"QUEUE_GROUP" yaml:"queue_group"`
+	MaxReconnects int           `envconfig:"MAX_RECONNECTS" default:"-1" yaml:"max_reconnects"`
+	ReconnectWait time.Duration `envconfig:"RECONNECT_WAIT" default:"2s" yaml:"reconnect_wait"`
 }
 
 // LoggingConfig holds logging configuration
 type LoggingConfig struct {
-	Level       string `envconfig:"LEVEL" default:"info"`
-	Format      string `envconfig:"FORMAT" default:"json"`
-	OutputPaths string `envconfig:"OUTPUT_PATHS" default:"stdout"`
-	Development bool   `envconfig:"DEVELOPMENT" default:"false"`
+	Level       string `envconfig:"LEVEL" default:"info" yaml:"level"`
+	Format      string `envconfig:"FORMAT" default:"json" yaml:"format"`
+	OutputPaths string `envconfig:"OUTPUT_PATHS" default:"stdout" yaml:"output_paths"`
+	Development bool   `envconfig:"DEVELOPMENT" default:"false" yaml:"development"`
+}
+
+// TracingConfig holds OpenTelemetry tracing configuration
+type TracingConfig struct {
+	Enabled bool `envconfig:"ENABLED" default:"false" yaml:"enabled"`
+	// Exporter is "stdout" (pretty-printed spans) or "otlp" (OTLP over gRPC)
+	Exporter    string  `envconfig:"EXPORTER" default:"stdout" yaml:"exporter"`
+	Endpoint    string  `envconfig:"ENDPOINT" default:"localhost:4317" yaml:"endpoint"`
+	Insecure    bool    `envconfig:"INSECURE" default:"true" yaml:"insecure"`
+	ServiceName string  `envconfig:"SERVICE_NAME" default:"go-transport-prac" yaml:"service_name"`
+	SampleRatio float64 `envconfig:"SAMPLE_RATIO" default:"1" yaml:"sample_ratio"`
+}
+
+// EncryptionConfig holds the AES keys SDL files are encrypted with
+type EncryptionConfig struct {
+	// KeyID names the key new files are written with; empty disables encryption
+	KeyID string `envconfig:"KEY_ID" yaml:"key_id"`
+	// Keys maps key IDs to base64 AES keys, e.g. "2024-01:<base64>,2024-06:<base64>"
+	Keys map[string]string `envconfig:"KEYS" yaml:"keys"`
 }
 
 // DevelopmentConfig holds development-specific configuration
 type DevelopmentConfig struct {
-	Enabled         bool `envconfig:"ENABLED" default:"false"`
-	MockServices    bool `envconfig:"MOCK_SERVICES" default:"false"`
-	EnableProfiling bool `envconfig:"ENABLE_PROFILING" default:"false"`
-	EnableMetrics   bool `envconfig:"ENABLE_METRICS" default:"true"`
+	Enabled         bool `envconfig:"ENABLED" default:"false" yaml:"enabled"`
+	MockServices    bool `envconfig:"MOCK_SERVICES" default:"false" yaml:"mock_services"`
+	EnableProfiling bool `envconfig:"ENABLE_PROFILING" default:"false" yaml:"enable_profiling"`
+	EnableMetrics   bool `envconfig:"ENABLE_METRICS" default:"true" yaml:"enable_metrics"`
 }
 
-// Load loads configuration from environment variables
+// ConfigFileEnv names the environment variable pointing Load at a YAML file
+const ConfigFileEnv = "CONFIG_FILE"
+
+// Load loads configuration from environment variables, on top of the YAML
+// file named by CONFIG_FILE when it is set
 func Load() (*Config, error) {
+	if path := os.Getenv(ConfigFileEnv); path != "" {
+		return LoadFile(path)
+	}
+
 	var cfg Config
 	
 	// Process environment variables
@@ -115,6 +196,63 @@ func Load() (*Config, error) {
 	return &cfg, nil
 }
 
+// LoadFile loads configuration from a YAML file. Defaults fill the keys the
+// file leaves out, and environment variables that are set win over the file
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var env Config
+	if err := envconfig.Process("", &env); err != nil {
+		return nil, fmt.Errorf("failed to process environment variables: %w", err)
+	}
+	// Start from the same defaults so the file only replaces what it sets
+	cfg := env
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	overrideFromEnv(reflect.ValueOf(&cfg).Elem(), reflect.ValueOf(&env).Elem(), "")
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+	
+	return &cfg, nil
+}
+
+// overrideFromEnv copies into dst the fields of src whose environment
+// variable is set, naming variables the way envconfig does
+func overrideFromEnv(dst, src reflect.Value, prefix string) {
+	for i := 0; i < dst.NumField(); i++ {
+		field := dst.Type().Field(i)
+		name := field.Tag.Get("envconfig")
+		if name == "" {
+			continue
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "_" + name
+		}
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Time{}) {
+			overrideFromEnv(dst.Field(i), src.Field(i), key)
+			continue
+		}
+		// envconfig falls back to the bare tag when the prefixed key is unset
+		_, set := os.LookupEnv(key)
+		if !set {
+			_, set = os.LookupEnv(name)
+		}
+		if set {
+			dst.Field(i).Set(src.Field(i))
+		}
+	}
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
 	// Validate server ports
@@ -135,12 +273,45 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("database username cannot be empty")
 	}
 	
+	// Validate Kafka configuration
+	if len(c.Kafka.Brokers) == 0 {
+		return fmt.Errorf("at least one Kafka broker must be specified")
+	}
+	
+	validFormats := []string{"avro", "protobuf", "json"}
+	if !contains(validFormats, c.Kafka.Format) {
+		return fmt.Errorf("invalid Kafka format: %s", c.Kafka.Format)
+	}
+	
 	// Validate logging level
 	validLevels := []string{"debug", "info", "warn", "error", "fatal", "panic"}
 	if !contains(validLevels, strings.ToLower(c.Logging.Level)) {
 		return fmt.Errorf("invalid logging level: %s", c.Logging.Level)
 	}
 	
+	// Validate tracing configuration
+	if c.Tracing.Enabled {
+		validExporters := []string{"stdout", "otlp"}
+		if !contains(validExporters, c.Tracing.Exporter) {
+			return fmt.Errorf("invalid tracing exporter: %s", c.Tracing.Exporter)
+		}
+		if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+			return fmt.Errorf("tracing sample ratio must be between 0 and 1: %v", c.Tracing.SampleRatio)
+		}
+	}
+	
+	// Validate encryption configuration
+	if c.Encryption.KeyID != "" {
+		if _, ok := c.Encryption.Keys[c.Encryption.KeyID]; !ok {
+			return fmt.Errorf("encryption key %q is not in the configured keys", c.Encryption.KeyID)
+		}
+	}
+	
+	// Validate admin configuration
+	if c.Server.AdminEnabled && c.Server.AdminToken == "" {
+		return fmt.Errorf("admin API requires an admin token when enabled")
+	}
+	
 	// Validate TLS configuration
 	if c.Server.TLSEnabled {
 		if c.Server.CertFile == "" || c.Server.KeyFile == "" {
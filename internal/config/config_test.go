@@ -0,0 +1,119 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testConfigYAML = `
+server:
+  http_port: 9000
+  read_timeout: 10s
+  host: 0.0.0.0
+kafka:
+  brokers: [kafka-1:9092, kafka-2:9092]
+  format: protobuf
+  schema_pins:
+    users-value: 2
+nats:
+  url: nats://nats:4222
+logging:
+  level: debug
+`
+
+func writeConfig(t *testing.T, dir, body string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	testDir := "tmp/test_config_file"
+	defer os.RemoveAll(testDir)
+	path := writeConfig(t, testDir, testConfigYAML)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Server.HTTPPort != 9000 || cfg.Server.ReadTimeout != 10*time.Second || cfg.Server.Host != "0.0.0.0" {
+		t.Errorf("Unexpected server settings %+v", cfg.Server)
+	}
+	if !reflect.DeepEqual(cfg.Kafka.Brokers, []string{"kafka-1:9092", "kafka-2:9092"}) || cfg.Kafka.SchemaPins["users-value"] != 2 {
+		t.Errorf("Unexpected kafka settings %+v", cfg.Kafka)
+	}
+	if cfg.NATS.URL != "nats://nats:4222" || cfg.Logging.Level != "debug" {
+		t.Errorf("Unexpected nats or logging settings %+v %+v", cfg.NATS, cfg.Logging)
+	}
+	// Keys the file leaves out keep their defaults
+	if cfg.Server.GRPCPort != 8081 || cfg.Server.WriteTimeout != 30*time.Second || cfg.Database.Port != 5432 || cfg.NATS.Name != "go-transport-prac" {
+		t.Errorf("Expected defaults for missing keys, got %+v %+v", cfg.Server, cfg.Database)
+	}
+
+	t.Log("✓ YAML config files are loaded over the defaults")
+}
+
+func TestLoadFileEnvPrecedence(t *testing.T) {
+	testDir := "tmp/test_config_env"
+	defer os.RemoveAll(testDir)
+	path := writeConfig(t, testDir, testConfigYAML)
+
+	t.Setenv("SERVER_HTTP_PORT", "9100")
+	t.Setenv("KAFKA_BROKERS", "env-broker:9092")
+	t.Setenv("DEV_ENABLED", "true")
+	t.Setenv(ConfigFileEnv, path)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Server.HTTPPort != 9100 || !reflect.DeepEqual(cfg.Kafka.Brokers, []string{"env-broker:9092"}) || !cfg.Development.Enabled {
+		t.Errorf("Expected environment variables to win, got %+v %+v", cfg.Server, cfg.Kafka)
+	}
+	if cfg.Server.ReadTimeout != 10*time.Second || cfg.Kafka.Format != "protobuf" {
+		t.Errorf("Expected unset variables to keep the file's values, got %+v", cfg.Server)
+	}
+
+	t.Log("✓ Environment variables override the YAML file")
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	testDir := "tmp/test_config_errors"
+	defer os.RemoveAll(testDir)
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"unknown key", "server:\n  http_prot: 9000\n", "field http_prot not found"},
+		{"bad duration", "server:\n  read_timeout: soon\n", "failed to parse config file"},
+		{"invalid value", "kafka:\n  format: xml\n", "invalid Kafka format: xml"},
+		{"open admin API", "server:\n  admin_enabled: true\n", "admin API requires an admin token"},
+	}
+	for _, tt := range tests {
+		_, err := LoadFile(writeConfig(t, testDir, tt.body))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	if _, err := LoadFile(filepath.Join(testDir, "missing.yaml")); err == nil {
+		t.Error("Expected a missing file to fail")
+	}
+	if cfg, err := LoadFile(writeConfig(t, testDir, "")); err != nil || cfg.Server.HTTPPort != 8080 {
+		t.Errorf("Expected an empty file to give the defaults, got %v", err)
+	}
+
+	t.Log("✓ Bad config files are rejected")
+}
@@ -0,0 +1,72 @@
+// Package ctxio wraps readers and writers so long file reads and writes stop
+// once a context is done. Cancellation is checked before every call on the
+// underlying reader or writer, so a call already in progress still completes
+package ctxio
+
+import (
+	"context"
+	"io"
+)
+
+// NewWriter returns a writer that fails with ctx.Err() once ctx is done. A
+// context that can never be cancelled returns w unchanged
+func NewWriter(ctx context.Context, w io.Writer) io.Writer {
+	if ctx.Done() == nil {
+		return w
+	}
+	return &writer{ctx: ctx, w: w}
+}
+
+// NewReader returns a reader that fails with ctx.Err() once ctx is done. A
+// context that can never be cancelled returns r unchanged
+func NewReader(ctx context.Context, r io.Reader) io.Reader {
+	if ctx.Done() == nil {
+		return r
+	}
+	return &reader{ctx: ctx, r: r}
+}
+
+// NewReaderAt returns a random-access reader that fails with ctx.Err() once
+// ctx is done. A context that can never be cancelled returns r unchanged
+func NewReaderAt(ctx context.Context, r io.ReaderAt) io.ReaderAt {
+	if ctx.Done() == nil {
+		return r
+	}
+	return &readerAt{ctx: ctx, r: r}
+}
+
+type writer struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return w.w.Write(p)
+}
+
+type reader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+type readerAt struct {
+	ctx context.Context
+	r   io.ReaderAt
+}
+
+func (r *readerAt) ReadAt(p []byte, off int64) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.ReadAt(p, off)
+}
@@ -0,0 +1,51 @@
+package ctxio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var buf bytes.Buffer
+	w := NewWriter(ctx, &buf)
+	r := NewReader(ctx, strings.NewReader("hello world"))
+	ra := NewReaderAt(ctx, strings.NewReader("hello world"))
+
+	if _, err := w.Write([]byte("hello")); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	p := make([]byte, 5)
+	if _, err := r.Read(p); err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	if _, err := ra.ReadAt(p, 6); err != nil || string(p) != "world" {
+		t.Fatalf("Failed to read at offset: %q %v", p, err)
+	}
+
+	cancel()
+	if _, err := w.Write([]byte(" world")); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected write to fail with context.Canceled, got %v", err)
+	}
+	if _, err := io.ReadAll(r); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected read to fail with context.Canceled, got %v", err)
+	}
+	if _, err := ra.ReadAt(p, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected ReadAt to fail with context.Canceled, got %v", err)
+	}
+	if buf.String() != "hello" {
+		t.Errorf("Expected only the first write to land, got %q", buf.String())
+	}
+
+	// A context that can never be cancelled adds no wrapper
+	if NewWriter(context.Background(), &buf) != io.Writer(&buf) {
+		t.Error("Expected the background context to return the writer unchanged")
+	}
+
+	t.Log("✓ Reads and writes stop once the context is cancelled")
+}
@@ -32,6 +32,12 @@ const (
 	ErrorTypeRateLimit ErrorType = "rate_limit"
 	// ErrorTypeBadRequest represents bad request errors
 	ErrorTypeBadRequest ErrorType = "bad_request"
+	// ErrorTypeUnsupportedMediaType represents request bodies in an unsupported format
+	ErrorTypeUnsupportedMediaType ErrorType = "unsupported_media_type"
+	// ErrorTypeNotAcceptable represents responses that cannot be produced in any accepted format
+	ErrorTypeNotAcceptable ErrorType = "not_acceptable"
+	// ErrorTypeGone represents resources that existed but are no longer available
+	ErrorTypeGone ErrorType = "gone"
 )
 
 // AppError represents an application error with context
@@ -73,6 +79,12 @@ func (e *AppError) HTTPStatusCode() int {
 		return http.StatusForbidden
 	case ErrorTypeConflict:
 		return http.StatusConflict
+	case ErrorTypeUnsupportedMediaType:
+		return http.StatusUnsupportedMediaType
+	case ErrorTypeNotAcceptable:
+		return http.StatusNotAcceptable
+	case ErrorTypeGone:
+		return http.StatusGone
 	case ErrorTypeTimeout:
 		return http.StatusRequestTimeout
 	case ErrorTypeRateLimit:
@@ -206,6 +218,34 @@ func BadRequestError(code, message string) *AppError {
 	return New(ErrorTypeBadRequest, code, message)
 }
 
+// UnsupportedMediaTypeError creates an unsupported media type error
+func UnsupportedMediaTypeError(code, message string) *AppError {
+	return New(ErrorTypeUnsupportedMediaType, code, message)
+}
+
+// NotAcceptableError creates a not acceptable error
+func NotAcceptableError(code, message string) *AppError {
+	return New(ErrorTypeNotAcceptable, code, message)
+}
+
+// GoneError creates a gone error
+func GoneError(code, message string) *AppError {
+	return New(ErrorTypeGone, code, message)
+}
+
+// DeserializationError creates a bad request error for a decoded field whose
+// value does not have the expected type. path names the field, such as
+// "profile.address.city", and is kept with the types in Fields
+func DeserializationError(path, expected, actual string) *AppError {
+	err := New(ErrorTypeBadRequest, CodeDeserializationError, "Decoded field has an unexpected type")
+	err.Details = fmt.Sprintf("%s: expected %s, got %s", path, expected, actual)
+	return err.WithFields(map[string]interface{}{
+		"path":     path,
+		"expected": expected,
+		"actual":   actual,
+	})
+}
+
 // IsAppError checks if an error is an AppError
 func IsAppError(err error) bool {
 	var appErr *AppError
@@ -288,6 +328,7 @@ const (
 	CodeAlreadyExists       = "ALREADY_EXISTS"
 	CodeConflict            = "CONFLICT"
 	CodeResourceLocked      = "RESOURCE_LOCKED"
+	CodeExpired             = "EXPIRED"
 	
 	// System error codes
 	CodeInternalError       = "INTERNAL_ERROR"
@@ -307,6 +348,8 @@ const (
 	CodeDeserializationError = "DESERIALIZATION_ERROR"
 	CodeEncodingError       = "ENCODING_ERROR"
 	CodeDecodingError       = "DECODING_ERROR"
+	CodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+	CodeNotAcceptable       = "NOT_ACCEPTABLE"
 )
 
 // Predefined common errors
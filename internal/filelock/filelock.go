@@ -0,0 +1,121 @@
+// Package filelock serializes writers of the same file within a process.
+// Locks are advisory: they only order callers that take them, and other
+// processes sharing a directory are not covered
+package filelock
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"go-transport-prac/internal/types"
+)
+
+// Set is a set of read-write locks by key. Entries exist only while held or
+// waited for, so the set does not grow with the number of files touched
+type Set struct {
+	mu    sync.Mutex
+	locks map[any]*entry
+}
+
+type entry struct {
+	sync.RWMutex
+	refs int
+}
+
+// NewSet returns an empty lock set
+func NewSet() *Set {
+	return &Set{locks: make(map[any]*entry)}
+}
+
+// Files is the lock set shared by every manager in the process, so managers
+// created separately for the same directory still exclude each other
+var Files = NewSet()
+
+// Lock takes the exclusive lock of key, which must be comparable, and
+// returns the function releasing it
+func (s *Set) Lock(key any) (unlock func()) {
+	e := s.acquire(key)
+	e.Lock()
+	return func() {
+		e.Unlock()
+		s.release(key, e)
+	}
+}
+
+// RLock takes the shared lock of key, which must be comparable, and returns
+// the function releasing it
+func (s *Set) RLock(key any) (unlock func()) {
+	e := s.acquire(key)
+	e.RLock()
+	return func() {
+		e.RUnlock()
+		s.release(key, e)
+	}
+}
+
+// Len returns the number of keys held or waited for
+func (s *Set) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.locks)
+}
+
+func (s *Set) acquire(key any) *entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.locks[key]
+	if !ok {
+		e = &entry{}
+		s.locks[key] = e
+	}
+	e.refs++
+	return e
+}
+
+func (s *Set) release(key any, e *entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e.refs--; e.refs == 0 {
+		delete(s.locks, key)
+	}
+}
+
+// Identified is implemented by storage backends that can name the location
+// their objects live in, so separate clients of one location share locks
+type Identified interface {
+	StorageID() string
+}
+
+type objectKey struct {
+	storage string
+	name    string
+}
+
+// Key returns the lock key of filename in a manager's storage backend, or in
+// baseDir when storage is nil. Paths are made absolute, so different spellings
+// of a directory share locks
+func Key(storage types.Storage, baseDir, filename string) any {
+	if storage != nil {
+		return objectKey{storageID(storage), filename}
+	}
+	path := filepath.Join(baseDir, filename)
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// storageID names a backend by the location it reports, or else by its
+// address. Backends that are neither identified nor pointers share one key
+// per type, which serializes more than needed but never too little
+func storageID(storage types.Storage) string {
+	if identified, ok := storage.(Identified); ok {
+		return fmt.Sprintf("%T:%s", storage, identified.StorageID())
+	}
+	if v := reflect.ValueOf(storage); v.Kind() == reflect.Pointer {
+		return fmt.Sprintf("%T@%x", storage, v.Pointer())
+	}
+	return fmt.Sprintf("%T", storage)
+}
@@ -0,0 +1,248 @@
+package flags
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/types"
+)
+
+// DefaultAuditSize is the number of changes a set retains
+const DefaultAuditSize = 256
+
+// Kind is the type of a flag's value
+type Kind string
+
+const (
+	KindBool   Kind = "bool"
+	KindInt    Kind = "int"
+	KindString Kind = "string"
+)
+
+// Flag describes a runtime setting and its current value
+type Flag struct {
+	Name        string    `json:"name"`
+	Kind        Kind      `json:"kind"`
+	Description string    `json:"description,omitempty"`
+	Default     string    `json:"default"`
+	Value       string    `json:"value"`
+	UpdatedAt   time.Time `json:"updatedAt,omitempty"`
+}
+
+// Change is one audited update of a setting
+type Change struct {
+	Name  string    `json:"name"`
+	Old   string    `json:"old"`
+	New   string    `json:"new"`
+	Actor string    `json:"actor"`
+	Time  time.Time `json:"time"`
+}
+
+// flag is a defined flag with its value parsed for the hot path
+type flag struct {
+	Flag
+	b bool
+	n int64
+}
+
+// Set holds runtime settings that can change without a restart. Components
+// define the flags they read, with their configured values as defaults, and
+// look them up on every use; Set records who changed what
+type Set struct {
+	clock     types.Clock
+	auditSize int
+
+	mu       sync.RWMutex
+	flags    map[string]*flag
+	watchers map[string][]func(value string)
+	audit    []Change
+}
+
+// NewSet creates an empty set
+func NewSet() *Set {
+	return &Set{
+		clock:     types.SystemClock{},
+		auditSize: DefaultAuditSize,
+		flags:     make(map[string]*flag),
+		watchers:  make(map[string][]func(string)),
+	}
+}
+
+// WithClock sets the clock used to timestamp changes
+func (s *Set) WithClock(clock types.Clock) *Set {
+	s.clock = types.ClockOrSystem(clock)
+	return s
+}
+
+// WithAuditSize sets how many changes are retained
+func (s *Set) WithAuditSize(n int) *Set {
+	if n > 0 {
+		s.auditSize = n
+	}
+	return s
+}
+
+// Define adds a flag. Defining a flag again with the same kind keeps its
+// current value, so components sharing a set can define the same flag
+func (s *Set) Define(name string, kind Kind, value, description string) error {
+	f := &flag{Flag: Flag{Name: name, Kind: kind, Description: description, Default: value, Value: value}}
+	if err := f.parse(value); err != nil {
+		return fmt.Errorf("invalid default for flag %s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.flags[name]; ok {
+		if existing.Kind != kind {
+			return fmt.Errorf("flag %s is already defined as %s", name, existing.Kind)
+		}
+		return nil
+	}
+	s.flags[name] = f
+	return nil
+}
+
+// DefineBool adds a bool flag
+func (s *Set) DefineBool(name string, value bool, description string) error {
+	return s.Define(name, KindBool, strconv.FormatBool(value), description)
+}
+
+// DefineInt adds an int flag
+func (s *Set) DefineInt(name string, value int64, description string) error {
+	return s.Define(name, KindInt, strconv.FormatInt(value, 10), description)
+}
+
+// Set changes a flag on behalf of actor and notifies its watchers. Unknown
+// flags and values of the wrong kind are rejected
+func (s *Set) Set(name, value, actor string) (Change, error) {
+	s.mu.Lock()
+	f, ok := s.flags[name]
+	if !ok {
+		s.mu.Unlock()
+		return Change{}, errors.NotFoundError(errors.CodeNotFound, "flag "+name+" not found")
+	}
+	if err := f.parse(value); err != nil {
+		s.mu.Unlock()
+		return Change{}, errors.ValidationError(errors.CodeInvalidValue, fmt.Sprintf("invalid value for flag %s: %v", name, err))
+	}
+	change := s.record(name, f.Value, value, actor)
+	f.Value = value
+	f.UpdatedAt = change.Time
+	watchers := append([]func(string){}, s.watchers[name]...)
+	s.mu.Unlock()
+
+	for _, fn := range watchers {
+		fn(value)
+	}
+	return change, nil
+}
+
+// Record audits a change made outside the set, such as a log level
+func (s *Set) Record(name, old, new, actor string) Change {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.record(name, old, new, actor)
+}
+
+func (s *Set) record(name, old, new, actor string) Change {
+	change := Change{Name: name, Old: old, New: new, Actor: actor, Time: s.clock.Now()}
+	s.audit = append(s.audit, change)
+	if len(s.audit) > s.auditSize {
+		s.audit = append([]Change(nil), s.audit[len(s.audit)-s.auditSize:]...)
+	}
+	return change
+}
+
+// Watch calls fn with the new value every time the flag is set
+func (s *Set) Watch(name string, fn func(value string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watchers[name] = append(s.watchers[name], fn)
+}
+
+// Lookup returns a flag by name
+func (s *Set) Lookup(name string) (Flag, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	f, ok := s.flags[name]
+	if !ok {
+		return Flag{}, false
+	}
+	return f.Flag, true
+}
+
+// List returns every flag ordered by name
+func (s *Set) List() []Flag {
+	s.mu.RLock()
+	list := make([]Flag, 0, len(s.flags))
+	for _, f := range s.flags {
+		list = append(list, f.Flag)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Audit returns the retained changes, oldest first
+func (s *Set) Audit() []Change {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Change(nil), s.audit...)
+}
+
+// Bool returns a bool flag's value, false when it is not defined
+func (s *Set) Bool(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if f, ok := s.flags[name]; ok {
+		return f.b
+	}
+	return false
+}
+
+// Int returns an int flag's value, 0 when it is not defined
+func (s *Set) Int(name string) int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if f, ok := s.flags[name]; ok {
+		return f.n
+	}
+	return 0
+}
+
+// String returns a flag's value as set, "" when it is not defined
+func (s *Set) String(name string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if f, ok := s.flags[name]; ok {
+		return f.Value
+	}
+	return ""
+}
+
+// parse validates value for the flag's kind and caches the parsed form
+func (f *flag) parse(value string) error {
+	switch f.Kind {
+	case KindBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("expected a bool, got %q", value)
+		}
+		f.b = b
+	case KindInt:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("expected an integer, got %q", value)
+		}
+		f.n = n
+	case KindString:
+	default:
+		return fmt.Errorf("unknown kind %q", f.Kind)
+	}
+	return nil
+}
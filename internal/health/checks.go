@@ -0,0 +1,129 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"go-transport-prac/internal/types"
+)
+
+// Check adapts a function to types.HealthChecker
+type Check struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+var _ types.HealthChecker = (*Check)(nil)
+
+// Func creates a check named name that passes when fn returns nil
+func Func(name string, fn func(ctx context.Context) error) *Check {
+	return &Check{name: name, fn: fn}
+}
+
+// Name returns the name of the health check
+func (c *Check) Name() string {
+	return c.name
+}
+
+// Check performs the health check
+func (c *Check) Check(ctx context.Context) error {
+	return c.fn(ctx)
+}
+
+// Pinger is a connection that can report whether its server is reachable,
+// such as cache.RedisCache or storage.MinIOStorage
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping checks that p's server is reachable, e.g. Ping("redis", redisCache)
+func Ping(name string, p Pinger) *Check {
+	return Func(name, p.Ping)
+}
+
+// SubjectLister lists the subjects of a schema registry, such as
+// avro.SchemaRegistry
+type SubjectLister interface {
+	ListSubjects() []string
+}
+
+// SchemaRegistry checks that registry answers and that every required
+// subject has been registered
+func SchemaRegistry(registry SubjectLister, required ...string) *Check {
+	return Func("schema_registry", func(ctx context.Context) error {
+		registered := make(map[string]bool)
+		for _, subject := range registry.ListSubjects() {
+			registered[subject] = true
+		}
+		for _, subject := range required {
+			if !registered[subject] {
+				return fmt.Errorf("subject %s is not registered", subject)
+			}
+		}
+		return nil
+	})
+}
+
+// HTTP checks that a GET of url answers with a 2xx status, e.g. for a remote
+// schema registry
+func HTTP(name, url string, client *http.Client) *Check {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return Func(name, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to reach %s: %w", url, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("%s answered %s", url, resp.Status)
+		}
+		return nil
+	})
+}
+
+// DiskSpace checks that the filesystem holding dir, such as a Parquet
+// manager's base directory, has at least minFree bytes available. A dir
+// that does not exist yet is measured on its nearest existing parent
+func DiskSpace(dir string, minFree uint64) *Check {
+	return Func("disk_space", func(ctx context.Context) error {
+		path, err := existingParent(dir)
+		if err != nil {
+			return err
+		}
+		free, err := freeBytes(path)
+		if err != nil {
+			return fmt.Errorf("failed to read free space of %s: %w", path, err)
+		}
+		if free < minFree {
+			return fmt.Errorf("%s has %d bytes free, below the minimum of %d", path, free, minFree)
+		}
+		return nil
+	})
+}
+
+// existingParent returns dir or the closest ancestor of it that exists
+func existingParent(dir string) (string, error) {
+	path, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return "", fmt.Errorf("no existing directory above %s", dir)
+		}
+		path = parent
+	}
+}
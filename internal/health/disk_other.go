@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package health
+
+import "errors"
+
+// freeBytes needs statfs, which is only wired up for linux and darwin
+func freeBytes(string) (uint64, error) {
+	return 0, errors.New("disk space checks are not supported on this platform")
+}
@@ -0,0 +1,219 @@
+// Package health aggregates types.HealthChecker implementations into a
+// types.HealthStatus and serves them as liveness and readiness endpoints
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"go-transport-prac/internal/types"
+)
+
+// Aggregated statuses reported in types.HealthStatus.Status
+const (
+	StatusHealthy   = "healthy"
+	StatusUnhealthy = "unhealthy"
+)
+
+// CheckOK is the result recorded for a passing check
+const CheckOK = "ok"
+
+// DefaultTimeout bounds every check that does not finish on its own
+const DefaultTimeout = 2 * time.Second
+
+// Registry runs a set of health checks concurrently
+type Registry struct {
+	mu      sync.RWMutex
+	checks  []types.HealthChecker
+	version string
+	timeout time.Duration
+	clock   types.Clock
+	started time.Time
+}
+
+// NewRegistry creates an empty registry reporting version. An empty version
+// falls back to the main module version from the build info
+func NewRegistry(version string) *Registry {
+	if version == "" {
+		version = buildVersion()
+	}
+	clock := types.SystemClock{}
+	return &Registry{
+		version: version,
+		timeout: DefaultTimeout,
+		clock:   clock,
+		started: clock.Now(),
+	}
+}
+
+// WithTimeout sets how long each check may run before it counts as failed
+func (r *Registry) WithTimeout(timeout time.Duration) *Registry {
+	r.timeout = timeout
+	return r
+}
+
+// WithClock sets the clock timestamps and uptime are measured with
+func (r *Registry) WithClock(clock types.Clock) *Registry {
+	r.clock = clock
+	r.started = clock.Now()
+	return r
+}
+
+// Register adds checks. A check registered under an existing name replaces it
+func (r *Registry) Register(checks ...types.HealthChecker) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, check := range checks {
+		replaced := false
+		for i, existing := range r.checks {
+			if existing.Name() == check.Name() {
+				r.checks[i] = check
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			r.checks = append(r.checks, check)
+		}
+	}
+	return r
+}
+
+// Names returns the names of the registered checks, sorted
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.checks))
+	for _, check := range r.checks {
+		names = append(names, check.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Live reports that the process is up without running any check
+func (r *Registry) Live() types.HealthStatus {
+	return r.status(StatusHealthy, map[string]string{})
+}
+
+// Check runs every registered check concurrently and aggregates the results.
+// The status is unhealthy when any check fails; Checks maps each check name
+// to CheckOK or its error
+func (r *Registry) Check(ctx context.Context) types.HealthStatus {
+	r.mu.RLock()
+	checks := append([]types.HealthChecker(nil), r.checks...)
+	r.mu.RUnlock()
+
+	results := make([]string, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check types.HealthChecker) {
+			defer wg.Done()
+			results[i] = r.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	status := StatusHealthy
+	byName := make(map[string]string, len(checks))
+	for i, check := range checks {
+		byName[check.Name()] = results[i]
+		if results[i] != CheckOK {
+			status = StatusUnhealthy
+		}
+	}
+	return r.status(status, byName)
+}
+
+// run performs one check within the registry timeout
+func (r *Registry) run(ctx context.Context, check types.HealthChecker) string {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Check(ctx)
+	}()
+
+	// A check that ignores its context still cannot hold up the response
+	select {
+	case err := <-done:
+		if err != nil {
+			return err.Error()
+		}
+		return CheckOK
+	case <-ctx.Done():
+		return ctx.Err().Error()
+	}
+}
+
+// status fills in the parts of a HealthStatus shared by every response
+func (r *Registry) status(status string, checks map[string]string) types.HealthStatus {
+	now := r.clock.Now()
+	return types.HealthStatus{
+		Status:    status,
+		Version:   r.version,
+		Timestamp: now,
+		Checks:    checks,
+		Uptime:    now.Sub(r.started),
+		SystemInfo: types.SystemInfo{
+			OS:         runtime.GOOS,
+			Arch:       runtime.GOARCH,
+			NumCPU:     runtime.NumCPU(),
+			GoMaxProcs: runtime.GOMAXPROCS(0),
+		},
+	}
+}
+
+// Mount serves GET /healthz and GET /readyz on mux
+func (r *Registry) Mount(mux *http.ServeMux) {
+	mux.HandleFunc("GET /healthz", r.serveLive)
+	mux.HandleFunc("GET /readyz", r.serveReady)
+}
+
+// Handler returns a handler serving only /healthz and /readyz
+func (r *Registry) Handler() http.Handler {
+	mux := http.NewServeMux()
+	r.Mount(mux)
+	return mux
+}
+
+// serveLive answers 200 while the process can serve requests. Dependencies
+// are not checked so an outage elsewhere does not get every replica restarted
+func (r *Registry) serveLive(w http.ResponseWriter, _ *http.Request) {
+	writeStatus(w, r.Live())
+}
+
+// serveReady answers 200 when every check passes and 503 otherwise
+func (r *Registry) serveReady(w http.ResponseWriter, req *http.Request) {
+	writeStatus(w, r.Check(req.Context()))
+}
+
+func writeStatus(w http.ResponseWriter, status types.HealthStatus) {
+	code := http.StatusOK
+	if status.Status != StatusHealthy {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(status)
+}
+
+// buildVersion returns the main module version, "(devel)" for local builds
+func buildVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "unknown"
+}
@@ -0,0 +1,134 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/internal/types"
+)
+
+type subjects []string
+
+func (s subjects) ListSubjects() []string { return s }
+
+func TestRegistryCheck(t *testing.T) {
+	clock := testutil.NewDefaultFakeClock()
+	registry := NewRegistry("1.2.3").WithClock(clock).WithTimeout(50 * time.Millisecond)
+	registry.Register(
+		Func("ok", func(context.Context) error { return nil }),
+		Func("broken", func(context.Context) error { return errors.New("connection refused") }),
+		// Ignores its context; the timeout still bounds it
+		Func("hung", func(context.Context) error { time.Sleep(time.Second); return nil }),
+		SchemaRegistry(subjects{"users-value"}, "users-value"),
+	)
+	clock.Advance(time.Minute)
+
+	status := registry.Check(context.Background())
+	if status.Status != StatusUnhealthy {
+		t.Errorf("Expected unhealthy status, got %s", status.Status)
+	}
+	if status.Version != "1.2.3" || status.Uptime != time.Minute {
+		t.Errorf("Expected version 1.2.3 and 1m uptime, got %s and %v", status.Version, status.Uptime)
+	}
+	want := map[string]string{
+		"ok":              CheckOK,
+		"broken":          "connection refused",
+		"hung":            context.DeadlineExceeded.Error(),
+		"schema_registry": CheckOK,
+	}
+	for name, result := range want {
+		if status.Checks[name] != result {
+			t.Errorf("Expected check %s to be %q, got %q", name, result, status.Checks[name])
+		}
+	}
+
+	// Re-registering a name replaces the check
+	registry.Register(
+		Func("broken", func(context.Context) error { return nil }),
+		Func("hung", func(context.Context) error { return nil }),
+	)
+	if got := registry.Check(context.Background()); got.Status != StatusHealthy {
+		t.Errorf("Expected healthy status after fixing checks, got %v", got.Checks)
+	}
+	if names := registry.Names(); len(names) != 4 {
+		t.Errorf("Expected 4 checks, got %v", names)
+	}
+
+	t.Log("✓ Checks run concurrently and aggregate into a HealthStatus")
+}
+
+func TestChecks(t *testing.T) {
+	ctx := context.Background()
+
+	if err := SchemaRegistry(subjects{"users-value"}, "users-value", "orders-value").Check(ctx); err == nil {
+		t.Error("Expected a missing subject to fail the registry check")
+	}
+	if err := DiskSpace("tmp/not/created/yet", 1).Check(ctx); err != nil {
+		t.Errorf("Expected disk space check of a missing dir to use its parent, got %v", err)
+	}
+	if err := DiskSpace(".", 1<<62).Check(ctx); err == nil {
+		t.Error("Expected an impossible free space minimum to fail")
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/subjects" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer ts.Close()
+	if err := HTTP("registry", ts.URL+"/subjects", nil).Check(ctx); err != nil {
+		t.Errorf("Expected HTTP check to pass, got %v", err)
+	}
+	if err := HTTP("registry", ts.URL+"/down", nil).Check(ctx); err == nil {
+		t.Error("Expected a 502 to fail the HTTP check")
+	}
+
+	t.Log("✓ Schema registry, disk space and HTTP checks report failures")
+}
+
+func TestHandlers(t *testing.T) {
+	failing := true
+	registry := NewRegistry("").Register(Func("redis", func(context.Context) error {
+		if failing {
+			return errors.New("redis down")
+		}
+		return nil
+	}))
+	handler := registry.Handler()
+
+	get := func(path string) (int, types.HealthStatus) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		var status types.HealthStatus
+		if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+			t.Fatalf("Failed to decode %s: %v", path, err)
+		}
+		return rec.Code, status
+	}
+
+	code, status := get("/healthz")
+	if code != http.StatusOK || len(status.Checks) != 0 {
+		t.Errorf("Expected /healthz to answer 200 without running checks, got %d %v", code, status.Checks)
+	}
+	code, status = get("/readyz")
+	if code != http.StatusServiceUnavailable || !strings.Contains(status.Checks["redis"], "redis down") {
+		t.Errorf("Expected /readyz to answer 503 with the failure, got %d %v", code, status.Checks)
+	}
+
+	failing = false
+	if code, _ := get("/readyz"); code != http.StatusOK {
+		t.Errorf("Expected /readyz to answer 200 once checks pass, got %d", code)
+	}
+	if status.Version == "" || status.SystemInfo.NumCPU == 0 {
+		t.Errorf("Expected version and system info, got %+v", status)
+	}
+
+	t.Log("✓ /healthz reports liveness and /readyz reports the checks")
+}
@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// levels holds the level of a logger and the per-component overrides shared
+// by every logger derived from it
+type levels struct {
+	global zap.AtomicLevel
+
+	mu         sync.RWMutex
+	components map[string]zapcore.Level
+}
+
+func newLevels(level zapcore.Level) *levels {
+	return &levels{global: zap.NewAtomicLevelAt(level), components: make(map[string]zapcore.Level)}
+}
+
+// level returns the effective level of a component
+func (l *levels) level(component string) zapcore.Level {
+	if component != "" {
+		l.mu.RLock()
+		level, ok := l.components[component]
+		l.mu.RUnlock()
+		if ok {
+			return level
+		}
+	}
+	return l.global.Level()
+}
+
+// levelCore filters entries by the level of the component it logs for. The
+// wrapped core accepts every level, so a component can log below the global level
+type levelCore struct {
+	zapcore.Core
+	levels    *levels
+	component string
+}
+
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return level >= c.levels.level(c.component)
+}
+
+// Level reports the effective level to zap.Logger.Level
+func (c *levelCore) Level() zapcore.Level {
+	return c.levels.level(c.component)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), levels: c.levels, component: c.component}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
+
+// ParseLevel parses a level name, rejecting unknown names
+func ParseLevel(level string) (zapcore.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug", "info", "warn", "warning", "error", "fatal", "panic":
+		return parseLevel(level), nil
+	default:
+		return zapcore.InfoLevel, fmt.Errorf("unknown log level %q", level)
+	}
+}
+
+// SetLevel changes the level of the logger and every logger derived from it
+func (l *Logger) SetLevel(level string) error {
+	if l.levels == nil {
+		return fmt.Errorf("logger does not support runtime levels")
+	}
+	parsed, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.levels.global.SetLevel(parsed)
+	return nil
+}
+
+// SetComponentLevel overrides the level of the loggers created with
+// WithComponent(component). An empty level removes the override
+func (l *Logger) SetComponentLevel(component, level string) error {
+	if l.levels == nil {
+		return fmt.Errorf("logger does not support runtime levels")
+	}
+	if component == "" {
+		return fmt.Errorf("component is required")
+	}
+
+	l.levels.mu.Lock()
+	defer l.levels.mu.Unlock()
+	if level == "" {
+		delete(l.levels.components, component)
+		return nil
+	}
+	parsed, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.levels.components[component] = parsed
+	return nil
+}
+
+// GlobalLevel returns the level of components without an override
+func (l *Logger) GlobalLevel() string {
+	if l.levels == nil {
+		return l.Logger.Level().String()
+	}
+	return l.levels.global.Level().String()
+}
+
+// ComponentLevel returns the effective level of a component
+func (l *Logger) ComponentLevel(component string) string {
+	if l.levels == nil {
+		return l.Logger.Level().String()
+	}
+	return l.levels.level(component).String()
+}
+
+// ComponentLevels returns the per-component overrides
+func (l *Logger) ComponentLevels() map[string]string {
+	overrides := make(map[string]string)
+	if l.levels == nil {
+		return overrides
+	}
+	l.levels.mu.RLock()
+	defer l.levels.mu.RUnlock()
+	for component, level := range l.levels.components {
+		overrides[component] = level.String()
+	}
+	return overrides
+}
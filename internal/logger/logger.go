@@ -11,7 +11,8 @@ import (
 // Logger wraps zap logger with additional functionality
 type Logger struct {
 	*zap.Logger
-	sugar *zap.SugaredLogger
+	sugar  *zap.SugaredLogger
+	levels *levels
 }
 
 // Config holds logger configuration
@@ -24,8 +25,11 @@ type Config struct {
 
 // New creates a new logger with the given configuration
 func New(cfg Config) (*Logger, error) {
+	// The encoder core accepts every level; levelCore applies the configured
+	// level and per-component overrides, which can change at runtime
+	levels := newLevels(parseLevel(cfg.Level))
 	zapConfig := zap.Config{
-		Level:       zap.NewAtomicLevelAt(parseLevel(cfg.Level)),
+		Level:       zap.NewAtomicLevelAt(zapcore.DebugLevel),
 		Development: cfg.Development,
 		Sampling: &zap.SamplingConfig{
 			Initial:    100,
@@ -58,7 +62,9 @@ func New(cfg Config) (*Logger, error) {
 	}
 
 	// Build the logger
-	zapLogger, err := zapConfig.Build(zap.AddCallerSkip(1))
+	zapLogger, err := zapConfig.Build(zap.AddCallerSkip(1), zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &levelCore{Core: core, levels: levels}
+	}))
 	if err != nil {
 		return nil, fmt.Errorf("failed to build logger: %w", err)
 	}
@@ -66,6 +72,7 @@ func New(cfg Config) (*Logger, error) {
 	return &Logger{
 		Logger: zapLogger,
 		sugar:  zapLogger.Sugar(),
+		levels: levels,
 	}, nil
 }
 
@@ -99,12 +106,20 @@ func (l *Logger) WithFields(fields ...zap.Field) *Logger {
 	return &Logger{
 		Logger: l.Logger.With(fields...),
 		sugar:  l.Logger.With(fields...).Sugar(),
+		levels: l.levels,
 	}
 }
 
-// WithComponent adds a component field to the logger
+// WithComponent adds a component field to the logger. The logger follows the
+// component's level set with SetComponentLevel
 func (l *Logger) WithComponent(component string) *Logger {
-	return l.WithFields(zap.String("component", component))
+	zapLogger := l.Logger.With(zap.String("component", component)).WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if lc, ok := core.(*levelCore); ok {
+			return &levelCore{Core: lc.Core, levels: lc.levels, component: component}
+		}
+		return core
+	}))
+	return &Logger{Logger: zapLogger, sugar: zapLogger.Sugar(), levels: l.levels}
 }
 
 // WithRequestID adds a request ID field to the logger
@@ -0,0 +1,142 @@
+// Package profiling exposes the runtime profilers of net/http/pprof and
+// runtime/pprof: HTTP endpoints for development servers, heap and goroutine
+// snapshots, and CPU profiles of a single run
+package profiling
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"time"
+
+	"go-transport-prac/internal/config"
+)
+
+// Prefix is the path every pprof endpoint is served under
+const Prefix = "/debug/pprof/"
+
+// Snapshot profiles that can be written with WriteSnapshot
+const (
+	ProfileHeap      = "heap"
+	ProfileGoroutine = "goroutine"
+)
+
+// Mount registers the pprof endpoints on mux when cfg.EnableProfiling is set
+// and reports whether it did
+func Mount(mux *http.ServeMux, cfg config.DevelopmentConfig) bool {
+	if !cfg.EnableProfiling {
+		return false
+	}
+	Register(mux)
+	return true
+}
+
+// Register serves the pprof index and every named profile (heap, goroutine,
+// allocs, block, mutex, threadcreate) under Prefix, plus the CPU profile,
+// execution trace, command line and symbol lookup
+func Register(mux *http.ServeMux) {
+	mux.HandleFunc(Prefix, pprof.Index)
+	mux.HandleFunc(Prefix+"cmdline", pprof.Cmdline)
+	mux.HandleFunc(Prefix+"profile", pprof.Profile)
+	mux.HandleFunc(Prefix+"symbol", pprof.Symbol)
+	mux.HandleFunc(Prefix+"trace", pprof.Trace)
+}
+
+// WriteHeap writes a heap profile to w in the pprof format. A garbage
+// collection runs first so the profile reflects live objects
+func WriteHeap(w io.Writer) error {
+	runtime.GC()
+	if err := runtimepprof.WriteHeapProfile(w); err != nil {
+		return fmt.Errorf("failed to write heap profile: %w", err)
+	}
+	return nil
+}
+
+// WriteGoroutines writes the stacks of every goroutine to w. debug 0 writes
+// the pprof format, 1 a text summary grouped by stack and 2 full panic-style
+// stack traces
+func WriteGoroutines(w io.Writer, debug int) error {
+	if err := runtimepprof.Lookup(ProfileGoroutine).WriteTo(w, debug); err != nil {
+		return fmt.Errorf("failed to write goroutine profile: %w", err)
+	}
+	return nil
+}
+
+// WriteSnapshot writes a heap or goroutine profile in the pprof format to a
+// new timestamped file in dir, e.g. heap-20240102T150405.000.pprof, and
+// returns its path
+func WriteSnapshot(dir, profile string) (string, error) {
+	var write func(io.Writer) error
+	switch profile {
+	case ProfileHeap:
+		write = WriteHeap
+	case ProfileGoroutine:
+		write = func(w io.Writer) error { return WriteGoroutines(w, 0) }
+	default:
+		return "", fmt.Errorf("unknown snapshot profile %q", profile)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	name := fmt.Sprintf("%s-%s.pprof", profile, time.Now().Format("20060102T150405.000"))
+	path := filepath.Join(dir, name)
+	if err := writeFile(path, write); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// CaptureDuring records a CPU profile to w while fn runs. Only one CPU profile
+// can run per process, so fn is not run when another capture is in progress
+func CaptureDuring(w io.Writer, fn func() error) error {
+	if err := runtimepprof.StartCPUProfile(w); err != nil {
+		return fmt.Errorf("failed to start CPU profile: %w", err)
+	}
+	defer runtimepprof.StopCPUProfile()
+	return fn()
+}
+
+// CaptureToFile records a CPU profile of fn to the file at path, creating
+// its directory. An empty path runs fn without profiling
+func CaptureToFile(path string, fn func() error) error {
+	if path == "" {
+		return fn()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create profile directory: %w", err)
+	}
+
+	var runErr error
+	err := writeFile(path, func(w io.Writer) error {
+		return CaptureDuring(w, func() error {
+			runErr = fn()
+			return nil
+		})
+	})
+	if runErr != nil {
+		return runErr
+	}
+	return err
+}
+
+// writeFile creates path and writes it with write, closing it either way
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create profile file: %w", err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close profile file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package profiling
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-transport-prac/internal/config"
+)
+
+func TestMount(t *testing.T) {
+	mux := http.NewServeMux()
+	if Mount(mux, config.DevelopmentConfig{}) {
+		t.Fatal("Expected profiling to stay unmounted when disabled")
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", Prefix, nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 with profiling disabled, got %d", rec.Code)
+	}
+
+	if !Mount(mux, config.DevelopmentConfig{EnableProfiling: true}) {
+		t.Fatal("Expected profiling to be mounted when enabled")
+	}
+	for _, path := range []string{Prefix, Prefix + "heap", Prefix + "goroutine?debug=1", Prefix + "cmdline"} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected 200 from %s, got %d", path, rec.Code)
+		}
+	}
+
+	t.Log("✓ pprof endpoints are mounted only when profiling is enabled")
+}
+
+func TestSnapshots(t *testing.T) {
+	dir := "tmp/test_snapshots"
+	defer os.RemoveAll("tmp")
+
+	for _, profile := range []string{ProfileHeap, ProfileGoroutine} {
+		path, err := WriteSnapshot(dir, profile)
+		if err != nil {
+			t.Fatalf("Failed to write %s snapshot: %v", profile, err)
+		}
+		if !strings.HasPrefix(filepath.Base(path), profile+"-") {
+			t.Errorf("Expected snapshot name to start with %s-, got %s", profile, path)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Failed to stat snapshot: %v", err)
+		}
+		if info.Size() == 0 {
+			t.Errorf("Expected %s snapshot to be non-empty", profile)
+		}
+	}
+	if _, err := WriteSnapshot(dir, "cpu"); err == nil {
+		t.Error("Expected error for an unknown snapshot profile")
+	}
+
+	var buf bytes.Buffer
+	if err := WriteGoroutines(&buf, 1); err != nil {
+		t.Fatalf("Failed to write goroutines: %v", err)
+	}
+	if !strings.Contains(buf.String(), "TestSnapshots") {
+		t.Error("Expected goroutine dump to include the running test")
+	}
+
+	t.Log("✓ Heap and goroutine snapshots are written")
+}
+
+func TestCaptureDuring(t *testing.T) {
+	path := "tmp/test_capture/cpu.pprof"
+	defer os.RemoveAll("tmp")
+
+	ran := false
+	err := CaptureToFile(path, func() error {
+		ran = true
+		// Nested captures cannot start while one is running
+		return CaptureDuring(&bytes.Buffer{}, func() error { return nil })
+	})
+	if err == nil {
+		t.Fatal("Expected a nested capture to fail")
+	}
+	if !ran {
+		t.Fatal("Expected the captured function to run")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat CPU profile: %v", err)
+	}
+	if info.Size() == 0 {
+		t.Error("Expected CPU profile to be non-empty")
+	}
+
+	boom := errors.New("boom")
+	if err := CaptureToFile(path, func() error { return boom }); !errors.Is(err, boom) {
+		t.Errorf("Expected the run error, got %v", err)
+	}
+	if err := CaptureToFile("", func() error { return nil }); err != nil {
+		t.Errorf("Expected an empty path to run without profiling, got %v", err)
+	}
+
+	t.Log("✓ CPU profiles are captured around a run")
+}
@@ -0,0 +1,91 @@
+// Package recordio reads and writes records as self-delimiting documents
+// back to back, for formats whose stream encoders write one document per
+// call, such as MessagePack, CBOR and YAML
+package recordio
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Encoder writes one document per call
+type Encoder interface {
+	Encode(v any) error
+}
+
+// Decoder reads one document per call into v, a pointer. It returns an
+// error matching io.EOF once the stream ends between documents
+type Decoder interface {
+	Decode(v any) error
+}
+
+// EncodeAll writes every value with enc, closing it afterwards if it is an
+// io.Closer
+func EncodeAll[T any](enc Encoder, values []T) error {
+	for i := range values {
+		if err := enc.Encode(&values[i]); err != nil {
+			return fmt.Errorf("failed to encode record %d: %w", i, err)
+		}
+	}
+	if closer, ok := enc.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			return fmt.Errorf("failed to encode: %w", err)
+		}
+	}
+	return nil
+}
+
+// DecodeAll reads records with dec until the stream ends
+func DecodeAll[T any](dec Decoder) ([]T, error) {
+	var values []T
+	for {
+		var v T
+		if err := dec.Decode(&v); err != nil {
+			if errors.Is(err, io.EOF) {
+				return values, nil
+			}
+			return nil, fmt.Errorf("failed to decode record %d: %w", len(values), err)
+		}
+		values = append(values, v)
+	}
+}
+
+// WriteFile writes values to the file at path, creating its directory, with
+// an encoder from newEncoder
+func WriteFile[T any, E Encoder](path string, newEncoder func(io.Writer) E, values []T) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	w := bufio.NewWriter(file)
+	if err := EncodeAll(newEncoder(w), values); err != nil {
+		file.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	return nil
+}
+
+// ReadFile reads the records of the file at path with a decoder from
+// newDecoder
+func ReadFile[T any, D Decoder](path string, newDecoder func(io.Reader) D) ([]T, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+	return DecodeAll[T](newDecoder(bufio.NewReader(file)))
+}
@@ -0,0 +1,72 @@
+package recordio
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type record struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// closingEncoder records whether EncodeAll closed it
+type closingEncoder struct {
+	*json.Encoder
+	closed bool
+}
+
+func (e *closingEncoder) Close() error {
+	e.closed = true
+	return nil
+}
+
+func TestFileRoundTrip(t *testing.T) {
+	testDir := "tmp/test_recordio"
+	defer os.RemoveAll(testDir)
+	path := filepath.Join(testDir, "nested", "records.json")
+
+	records := []record{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c"}}
+	if err := WriteFile(path, json.NewEncoder, records); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	decoded, err := ReadFile[record](path, json.NewDecoder)
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if len(decoded) != len(records) || decoded[2] != records[2] {
+		t.Errorf("Expected %v, got %v", records, decoded)
+	}
+
+	if _, err := ReadFile[record](filepath.Join(testDir, "missing.json"), json.NewDecoder); err == nil {
+		t.Error("Expected a missing file to fail")
+	}
+
+	t.Log("✓ Records round-trip through files")
+}
+
+func TestEncodeAndDecodeAll(t *testing.T) {
+	var buf strings.Builder
+	enc := &closingEncoder{Encoder: json.NewEncoder(&buf)}
+	if err := EncodeAll(enc, []record{{ID: 1}, {ID: 2}}); err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	if !enc.closed {
+		t.Error("Expected a closable encoder to be closed")
+	}
+
+	if decoded, err := DecodeAll[record](json.NewDecoder(strings.NewReader(""))); err != nil || len(decoded) != 0 {
+		t.Errorf("Expected an empty stream to decode to nothing, got %v: %v", decoded, err)
+	}
+
+	stream := buf.String() + `{"id": 3, "na`
+	_, err := DecodeAll[record](json.NewDecoder(strings.NewReader(stream)))
+	if err == nil || !strings.Contains(err.Error(), "record 2") {
+		t.Errorf("Expected a truncated third record to fail, got %v", err)
+	}
+
+	t.Log("✓ Streams hold records back to back")
+}
@@ -0,0 +1,202 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go-transport-prac/internal/types"
+)
+
+// ErrOpen is returned instead of calling a service whose breaker is open
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the state of a Breaker
+type State int
+
+const (
+	// StateClosed lets every call through, counting failures
+	StateClosed State = iota
+	// StateOpen rejects every call until OpenTimeout has passed
+	StateOpen
+	// StateHalfOpen lets trial calls through to test the service
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerConfig tunes a Breaker; zero fields take their defaults
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens
+	// the breaker, 5 by default
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before trying the
+	// service again, 30s by default
+	OpenTimeout time.Duration
+	// HalfOpenRequests is the number of trial calls that must succeed to
+	// close the breaker again, 1 by default
+	HalfOpenRequests int
+	// IsFailure decides which errors count against the service. By default
+	// every error does except permanent ones and a cancelled context
+	IsFailure func(error) bool
+}
+
+// DefaultBreakerConfig opens after 5 failures for 30s
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{FailureThreshold: 5, OpenTimeout: 30 * time.Second, HalfOpenRequests: 1}
+}
+
+// Breaker is a circuit breaker: after FailureThreshold consecutive failures
+// it rejects calls with ErrOpen for OpenTimeout, then lets HalfOpenRequests
+// trial calls through and closes once they all succeed, or opens again on
+// the first failure. It is a Policy and safe for concurrent use
+type Breaker struct {
+	cfg      BreakerConfig
+	clock    types.Clock
+	onChange func(from, to State)
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	openedAt  time.Time
+	trials    int
+	successes int
+}
+
+// NewBreaker returns a closed breaker
+func NewBreaker(cfg BreakerConfig) *Breaker {
+	defaults := DefaultBreakerConfig()
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = defaults.FailureThreshold
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = defaults.OpenTimeout
+	}
+	if cfg.HalfOpenRequests <= 0 {
+		cfg.HalfOpenRequests = defaults.HalfOpenRequests
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = isFailure
+	}
+	return &Breaker{cfg: cfg, clock: types.SystemClock{}}
+}
+
+// WithClock sets the clock OpenTimeout is measured with
+func (b *Breaker) WithClock(clock types.Clock) *Breaker {
+	b.clock = types.ClockOrSystem(clock)
+	return b
+}
+
+// OnStateChange calls fn on every state change, with the breaker locked, so
+// fn must not call the breaker
+func (b *Breaker) OnStateChange(fn func(from, to State)) *Breaker {
+	b.onChange = fn
+	return b
+}
+
+// State returns the current state
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refresh()
+	return b.state
+}
+
+// Execute implements Policy, returning ErrOpen without calling fn while the
+// breaker is open
+func (b *Breaker) Execute(ctx context.Context, fn func(context.Context) error) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+	err := fn(ctx)
+	b.record(err)
+	return err
+}
+
+// allow admits a call or rejects it with ErrOpen
+func (b *Breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refresh()
+
+	switch b.state {
+	case StateOpen:
+		return ErrOpen
+	case StateHalfOpen:
+		if b.trials >= b.cfg.HalfOpenRequests {
+			return ErrOpen
+		}
+		b.trials++
+	}
+	return nil
+}
+
+// record counts the outcome of an admitted call
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	failed := err != nil && b.cfg.IsFailure(err)
+	switch b.state {
+	case StateClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.cfg.FailureThreshold {
+			b.open()
+		}
+	case StateHalfOpen:
+		if failed {
+			b.open()
+			return
+		}
+		b.successes++
+		if b.successes >= b.cfg.HalfOpenRequests {
+			b.setState(StateClosed)
+		}
+	}
+}
+
+// refresh moves an open breaker whose timeout passed to half-open. Must be
+// called with b.mu held
+func (b *Breaker) refresh() {
+	if b.state == StateOpen && b.clock.Now().Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		b.setState(StateHalfOpen)
+	}
+}
+
+// open must be called with b.mu held
+func (b *Breaker) open() {
+	b.openedAt = b.clock.Now()
+	b.setState(StateOpen)
+}
+
+// setState resets the counters of the new state. Must be called with b.mu held
+func (b *Breaker) setState(state State) {
+	from := b.state
+	b.state = state
+	b.failures, b.trials, b.successes = 0, 0, 0
+	if b.onChange != nil && from != state {
+		b.onChange(from, state)
+	}
+}
+
+// isFailure counts every error but permanent ones and cancellations, which
+// say nothing about the service's health
+func isFailure(err error) bool {
+	return !IsPermanent(err) && !errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,81 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrBulkheadFull is returned when a call found no free slot in time
+var ErrBulkheadFull = errors.New("bulkhead is full")
+
+// Bulkhead bounds the calls in flight to a service, so a slow service ties
+// up a fixed number of goroutines and connections instead of all of them. It
+// is a Policy and safe for concurrent use
+type Bulkhead struct {
+	slots   chan struct{}
+	maxWait time.Duration
+}
+
+// NewBulkhead returns a bulkhead admitting maxConcurrent calls at once,
+// at least one. Calls wait for a slot as long as their context allows
+func NewBulkhead(maxConcurrent int) *Bulkhead {
+	return &Bulkhead{slots: make(chan struct{}, max(maxConcurrent, 1))}
+}
+
+// WithMaxWait bounds how long a call waits for a slot; a negative wait
+// rejects calls at once when the bulkhead is full, and 0 waits as long as
+// the call's context allows
+func (b *Bulkhead) WithMaxWait(d time.Duration) *Bulkhead {
+	b.maxWait = d
+	return b
+}
+
+// Acquire takes a slot, returning the function that gives it back
+func (b *Bulkhead) Acquire(ctx context.Context) (func(), error) {
+	release := func() { <-b.slots }
+	select {
+	case b.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+	if b.maxWait < 0 {
+		return nil, ErrBulkheadFull
+	}
+
+	var timeout <-chan time.Time
+	if b.maxWait > 0 {
+		timer := time.NewTimer(b.maxWait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case b.slots <- struct{}{}:
+		return release, nil
+	case <-timeout:
+		return nil, ErrBulkheadFull
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: %w", ErrBulkheadFull, ctx.Err())
+	}
+}
+
+// Execute implements Policy, holding a slot while fn runs
+func (b *Bulkhead) Execute(ctx context.Context, fn func(context.Context) error) error {
+	release, err := b.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return fn(ctx)
+}
+
+// InFlight returns the number of slots taken
+func (b *Bulkhead) InFlight() int {
+	return len(b.slots)
+}
+
+// Capacity returns the number of calls admitted at once
+func (b *Bulkhead) Capacity() int {
+	return cap(b.slots)
+}
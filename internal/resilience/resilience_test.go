@@ -0,0 +1,210 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/testutil"
+)
+
+var errBoom = errors.New("boom")
+
+// fastRetry retries quickly enough for tests
+func fastRetry(attempts int) RetryPolicy {
+	return RetryPolicy{MaxAttempts: attempts, Backoff: Backoff{Initial: time.Millisecond, Max: 4 * time.Millisecond}}
+}
+
+func TestBackoff(t *testing.T) {
+	b := Backoff{Initial: 100 * time.Millisecond, Max: time.Second, Multiplier: 3}
+	for attempt, want := range []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 900 * time.Millisecond, time.Second, time.Second} {
+		if got := b.Delay(attempt); got != want {
+			t.Errorf("Delay(%d): expected %v, got %v", attempt, want, got)
+		}
+	}
+
+	b.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := b.Delay(1); got < 150*time.Millisecond || got > 300*time.Millisecond {
+			t.Fatalf("Expected a jittered delay in [150ms, 300ms], got %v", got)
+		}
+	}
+
+	t.Log("✓ Backoff grows exponentially up to its cap, with jitter")
+}
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+
+	calls := 0
+	var retries []int
+	policy := fastRetry(5)
+	policy.OnRetry = func(attempt int, err error, delay time.Duration) { retries = append(retries, attempt) }
+	got, err := Retry(ctx, policy, func(context.Context) (string, error) {
+		if calls++; calls < 3 {
+			return "", errBoom
+		}
+		return "ok", nil
+	})
+	if err != nil || got != "ok" || calls != 3 || len(retries) != 2 {
+		t.Errorf("Expected success on the third call, got %q, %v after %d calls and retries %v", got, err, calls, retries)
+	}
+
+	calls = 0
+	_, err = Retry(ctx, fastRetry(3), func(context.Context) (int, error) { calls++; return 0, errBoom })
+	if !errors.Is(err, errBoom) || calls != 3 {
+		t.Errorf("Expected the last error after 3 calls, got %v after %d", err, calls)
+	}
+
+	calls = 0
+	rejected := errors.New("rejected")
+	err = fastRetry(3).Execute(ctx, func(context.Context) error { calls++; return Permanent(rejected) })
+	if err != rejected || calls != 1 {
+		t.Errorf("Expected a permanent error returned unmarked after 1 call, got %v after %d", err, calls)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	calls = 0
+	slow := RetryPolicy{MaxAttempts: 10, Backoff: Backoff{Initial: time.Hour}}
+	err = slow.Execute(cancelled, func(context.Context) error {
+		if calls++; calls == 1 {
+			time.AfterFunc(10*time.Millisecond, cancel)
+		}
+		return errBoom
+	})
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, errBoom) || calls != 1 {
+		t.Errorf("Expected the wait to end with the context, got %v after %d calls", err, calls)
+	}
+
+	t.Log("✓ Retries back off, stop on permanent errors and honour the context")
+}
+
+func TestBreaker(t *testing.T) {
+	ctx := context.Background()
+	clock := testutil.NewDefaultFakeClock()
+	var changes []string
+	b := NewBreaker(BreakerConfig{FailureThreshold: 3, OpenTimeout: time.Minute, HalfOpenRequests: 2}).
+		WithClock(clock).
+		OnStateChange(func(from, to State) { changes = append(changes, from.String()+"->"+to.String()) })
+	fail := func(context.Context) error { return errBoom }
+	succeed := func(context.Context) error { return nil }
+
+	// Successes and permanent errors reset the count
+	b.Execute(ctx, fail)
+	b.Execute(ctx, fail)
+	b.Execute(ctx, succeed)
+	b.Execute(ctx, fail)
+	b.Execute(ctx, func(context.Context) error { return Permanent(errBoom) })
+	b.Execute(ctx, fail)
+	if b.State() != StateClosed {
+		t.Fatalf("Expected the breaker to stay closed, got %s", b.State())
+	}
+	b.Execute(ctx, fail)
+	b.Execute(ctx, fail)
+	if b.State() != StateOpen {
+		t.Fatalf("Expected 3 consecutive failures to open the breaker, got %s", b.State())
+	}
+
+	called := false
+	if err := b.Execute(ctx, func(context.Context) error { called = true; return nil }); !errors.Is(err, ErrOpen) || called {
+		t.Errorf("Expected an open breaker to reject calls, got %v (called=%v)", err, called)
+	}
+
+	// A failed trial opens it again for another timeout
+	clock.Advance(time.Minute)
+	if b.State() != StateHalfOpen {
+		t.Fatalf("Expected half-open after the timeout, got %s", b.State())
+	}
+	b.Execute(ctx, fail)
+	if b.State() != StateOpen {
+		t.Fatalf("Expected a failed trial to reopen the breaker, got %s", b.State())
+	}
+
+	clock.Advance(time.Minute)
+	if _, err := Call(ctx, b, func(context.Context) (int, error) { return 1, nil }); err != nil {
+		t.Fatalf("Expected a trial call, got %v", err)
+	}
+	if b.State() != StateHalfOpen {
+		t.Errorf("Expected 2 trials to be needed, got %s", b.State())
+	}
+	b.Execute(ctx, succeed)
+	if b.State() != StateClosed {
+		t.Errorf("Expected successful trials to close the breaker, got %s", b.State())
+	}
+
+	want := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	if len(changes) != len(want) {
+		t.Fatalf("Expected changes %v, got %v", want, changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("Change %d: expected %s, got %s", i, want[i], changes[i])
+		}
+	}
+
+	t.Log("✓ The breaker opens on failures and recovers through trial calls")
+}
+
+func TestBulkhead(t *testing.T) {
+	ctx := context.Background()
+	b := NewBulkhead(2)
+
+	var running, peak atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.Execute(ctx, func(context.Context) error {
+				n := running.Add(1)
+				for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+				}
+				time.Sleep(5 * time.Millisecond)
+				running.Add(-1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	if peak.Load() > 2 || b.InFlight() != 0 {
+		t.Errorf("Expected at most 2 calls at once and all slots back, got peak %d and %d in flight", peak.Load(), b.InFlight())
+	}
+
+	release1, _ := b.Acquire(ctx)
+	release2, _ := b.Acquire(ctx)
+	if _, err := b.WithMaxWait(-1).Acquire(ctx); !errors.Is(err, ErrBulkheadFull) {
+		t.Errorf("Expected a full bulkhead to reject at once, got %v", err)
+	}
+	if _, err := b.WithMaxWait(5 * time.Millisecond).Acquire(ctx); !errors.Is(err, ErrBulkheadFull) {
+		t.Errorf("Expected the wait to time out, got %v", err)
+	}
+	short, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	if _, err := b.WithMaxWait(0).Acquire(short); !errors.Is(err, ErrBulkheadFull) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the wait to end with the context, got %v", err)
+	}
+	release1()
+	release2()
+
+	t.Log("✓ The bulkhead bounds concurrent calls")
+}
+
+func TestCompose(t *testing.T) {
+	ctx := context.Background()
+	breaker := NewBreaker(BreakerConfig{FailureThreshold: 2})
+	policy := Compose(fastRetry(5), breaker, nil, NewBulkhead(1))
+
+	calls := 0
+	_, err := Call(ctx, policy, func(context.Context) (int, error) { calls++; return 0, errBoom })
+	if !errors.Is(err, ErrOpen) || calls != 2 {
+		t.Errorf("Expected retries to stop once the breaker opened, got %v after %d calls", err, calls)
+	}
+	if IsRetryable(err) {
+		t.Error("Expected an open breaker not to be retryable")
+	}
+
+	t.Log("✓ Policies compose, with the breaker cutting retries short")
+}
@@ -0,0 +1,194 @@
+// Package resilience guards calls to remote services: Retry repeats failed
+// calls with exponential backoff and jitter, a Breaker stops calling a
+// service that keeps failing, and a Bulkhead bounds the calls in flight.
+// Each is a Policy, and Compose stacks them around one call.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Policy runs a call under some protection
+type Policy interface {
+	Execute(ctx context.Context, fn func(context.Context) error) error
+}
+
+// Call runs fn under p and returns its result
+func Call[T any](ctx context.Context, p Policy, fn func(context.Context) (T, error)) (T, error) {
+	var result T
+	err := p.Execute(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = fn(ctx)
+		return err
+	})
+	return result, err
+}
+
+// Compose returns a policy running a call under every policy, the first
+// one outermost. Nil policies are skipped
+func Compose(policies ...Policy) Policy {
+	return composed(policies)
+}
+
+type composed []Policy
+
+func (c composed) Execute(ctx context.Context, fn func(context.Context) error) error {
+	for i := len(c) - 1; i >= 0; i-- {
+		if c[i] == nil {
+			continue
+		}
+		p, next := c[i], fn
+		fn = func(ctx context.Context) error { return p.Execute(ctx, next) }
+	}
+	return fn(ctx)
+}
+
+// Backoff computes the delays between attempts: Initial, multiplied by
+// Multiplier after every attempt up to Max, each shortened by a random
+// fraction of at most Jitter so clients retrying together spread out
+type Backoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+	Jitter     float64
+}
+
+// DefaultBackoff starts at 100ms and doubles up to 5s with 20% jitter
+func DefaultBackoff() Backoff {
+	return Backoff{Initial: 100 * time.Millisecond, Max: 5 * time.Second, Multiplier: 2, Jitter: 0.2}
+}
+
+// Delay returns the delay after the given failed attempt, counted from 0
+func (b Backoff) Delay(attempt int) time.Duration {
+	multiplier := b.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	delay := float64(b.Initial)
+	for i := 0; i < attempt && (b.Max <= 0 || delay < float64(b.Max)); i++ {
+		delay *= multiplier
+	}
+	if b.Max > 0 && delay > float64(b.Max) {
+		delay = float64(b.Max)
+	}
+	if jitter := min(max(b.Jitter, 0), 1); jitter > 0 {
+		delay -= delay * jitter * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+// RetryPolicy retries failed calls. It is a Policy
+type RetryPolicy struct {
+	// MaxAttempts is the number of calls including the first; 0 or less
+	// calls once
+	MaxAttempts int
+	Backoff     Backoff
+	// Retryable decides which errors are retried, IsRetryable by default
+	Retryable func(error) bool
+	// OnRetry, when set, is told about every failed attempt before its delay
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+// DefaultRetryPolicy makes 3 attempts with DefaultBackoff
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, Backoff: DefaultBackoff()}
+}
+
+// Retry calls fn until it succeeds, fails with an error that is not
+// retryable, runs out of attempts or ctx is done. The last error is returned
+// with the number of attempts made
+func Retry[T any](ctx context.Context, p RetryPolicy, fn func(context.Context) (T, error)) (T, error) {
+	return Call(ctx, p, fn)
+}
+
+// Execute implements Policy
+func (p RetryPolicy) Execute(ctx context.Context, fn func(context.Context) error) error {
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+	attempts := max(p.MaxAttempts, 1)
+
+	for attempt := 0; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+		if !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+		if attempt+1 >= attempts {
+			if attempts == 1 {
+				return err
+			}
+			return fmt.Errorf("failed after %d attempts: %w", attempts, err)
+		}
+
+		delay := p.Backoff.Delay(attempt)
+		if p.OnRetry != nil {
+			p.OnRetry(attempt+1, err, delay)
+		}
+		if waitErr := sleep(ctx, delay); waitErr != nil {
+			return fmt.Errorf("%w after %d attempts: %w", waitErr, attempt+1, err)
+		}
+	}
+}
+
+// permanentError marks an error no retry can fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying, e.g. a rejected request. Retry
+// returns err itself, unmarked, and a Breaker does not count it as a failure
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// IsRetryable is the default retry decision: every error except permanent
+// ones, an open breaker and a full bulkhead. Retries also stop once the
+// caller's context is done, whatever the error
+func IsRetryable(err error) bool {
+	switch {
+	case err == nil, IsPermanent(err):
+		return false
+	case errors.Is(err, ErrOpen), errors.Is(err, ErrBulkheadFull):
+		return false
+	}
+	return true
+}
+
+// sleep waits for d or until ctx is done, returning ctx's error then
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
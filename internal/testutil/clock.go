@@ -0,0 +1,61 @@
+package testutil
+
+import (
+	"sync"
+	"time"
+
+	"go-transport-prac/internal/types"
+)
+
+// FakeClock is a manually controlled clock for deterministic tests
+type FakeClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+var _ types.Clock = (*FakeClock)(nil)
+
+// DefaultFakeTime is the starting instant used by NewDefaultFakeClock
+var DefaultFakeTime = time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// NewFakeClock creates a clock frozen at start
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// NewDefaultFakeClock creates a clock frozen at DefaultFakeTime
+func NewDefaultFakeClock() *FakeClock {
+	return NewFakeClock(DefaultFakeTime)
+}
+
+// Now returns the current fake time, then advances it by the auto-step if one is set
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+// Advance moves the clock forward by d
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set moves the clock to t
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// SetAutoStep makes every call to Now advance the clock by step afterwards
+func (c *FakeClock) SetAutoStep(step time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.step = step
+}
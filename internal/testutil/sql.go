@@ -0,0 +1,175 @@
+package testutil
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// SQLHandler answers one statement of a FakeSQL database. Queries return
+// their column names and rows; statements executed for their effect return
+// no columns
+type SQLHandler func(args []driver.Value) (columns []string, rows [][]driver.Value, err error)
+
+// FakeSQL is a database/sql driver whose statements are answered by handlers
+// the test registers by statement prefix, so code written against a real
+// database can be exercised without one. Whitespace in statements is
+// collapsed before matching
+type FakeSQL struct {
+	name string
+
+	mu         sync.Mutex
+	prefixes   []string
+	handlers   []SQLHandler
+	statements []string
+}
+
+var fakeSQLDrivers atomic.Int64
+
+// NewFakeSQL registers a new fake driver; open it with DriverName
+func NewFakeSQL() *FakeSQL {
+	f := &FakeSQL{name: fmt.Sprintf("fakesql-%d", fakeSQLDrivers.Add(1))}
+	sql.Register(f.name, f)
+	return f
+}
+
+// DriverName returns the name the driver is registered under
+func (f *FakeSQL) DriverName() string {
+	return f.name
+}
+
+// Handle answers statements starting with prefix; earlier handlers win
+func (f *FakeSQL) Handle(prefix string, handler SQLHandler) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.prefixes = append(f.prefixes, normalizeSQL(prefix))
+	f.handlers = append(f.handlers, handler)
+}
+
+// Statements returns the statements run so far, with BEGIN, COMMIT and
+// ROLLBACK marking transactions
+func (f *FakeSQL) Statements() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.statements...)
+}
+
+func (f *FakeSQL) run(query string, args []driver.Value) ([]string, [][]driver.Value, error) {
+	query = normalizeSQL(query)
+	f.mu.Lock()
+	f.statements = append(f.statements, query)
+	var handler SQLHandler
+	for i, prefix := range f.prefixes {
+		if strings.HasPrefix(query, prefix) {
+			handler = f.handlers[i]
+			break
+		}
+	}
+	f.mu.Unlock()
+
+	if handler == nil {
+		return nil, nil, fmt.Errorf("fakesql: unexpected statement %q", query)
+	}
+	return handler(args)
+}
+
+func (f *FakeSQL) record(statement string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.statements = append(f.statements, statement)
+}
+
+func normalizeSQL(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// Open implements driver.Driver
+func (f *FakeSQL) Open(string) (driver.Conn, error) {
+	return fakeSQLConn{f}, nil
+}
+
+type fakeSQLConn struct {
+	f *FakeSQL
+}
+
+func (c fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeSQLStmt{f: c.f, query: query}, nil
+}
+
+func (c fakeSQLConn) Close() error {
+	return nil
+}
+
+func (c fakeSQLConn) Begin() (driver.Tx, error) {
+	c.f.record("BEGIN")
+	return fakeSQLTx{c.f}, nil
+}
+
+type fakeSQLTx struct {
+	f *FakeSQL
+}
+
+func (tx fakeSQLTx) Commit() error {
+	tx.f.record("COMMIT")
+	return nil
+}
+
+func (tx fakeSQLTx) Rollback() error {
+	tx.f.record("ROLLBACK")
+	return nil
+}
+
+type fakeSQLStmt struct {
+	f     *FakeSQL
+	query string
+}
+
+func (s fakeSQLStmt) Close() error {
+	return nil
+}
+
+func (s fakeSQLStmt) NumInput() int {
+	return -1
+}
+
+func (s fakeSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	_, rows, err := s.f.run(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(len(rows)), nil
+}
+
+func (s fakeSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	columns, rows, err := s.f.run(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeSQLRows{columns: columns, rows: rows}, nil
+}
+
+type fakeSQLRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeSQLRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeSQLRows) Close() error {
+	return nil
+}
+
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,152 @@
+// Package tracing configures OpenTelemetry tracing and provides the span
+// helpers the SDL managers use to trace serialization and file operations
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"go-transport-prac/internal/config"
+)
+
+// InstrumentationName identifies the spans created through this package
+const InstrumentationName = "go-transport-prac"
+
+// Attribute keys recorded on serialization and file spans
+const (
+	// AttrFormat is the serialization format, e.g. "avro", "protobuf", "parquet"
+	AttrFormat = attribute.Key("sdl.format")
+	// AttrEntity is the record type, e.g. "user", "order"
+	AttrEntity = attribute.Key("sdl.entity")
+	// AttrBytes is the encoded size in bytes
+	AttrBytes = attribute.Key("sdl.bytes")
+	// AttrRows is the number of records written or read
+	AttrRows = attribute.Key("sdl.rows")
+	// AttrFile is the file name or storage key
+	AttrFile = attribute.Key("sdl.file")
+)
+
+// Exporters accepted in config.TracingConfig.Exporter
+const (
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Setup installs a global tracer provider and the W3C trace context and
+// baggage propagators as configured. When tracing is disabled the no-op
+// provider stays in place. The returned function flushes and stops the
+// provider and must be called before exiting
+func Setup(ctx context.Context, cfg config.TracingConfig) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := newExporter(ctx, cfg, os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := NewProvider(cfg, exporter)
+	if err != nil {
+		return nil, err
+	}
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// NewProvider creates a batching tracer provider for cfg's service that sends
+// spans to exporter, sampling cfg.SampleRatio of new traces
+func NewProvider(cfg config.TracingConfig, exporter sdktrace.SpanExporter) (*sdktrace.TracerProvider, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	), nil
+}
+
+// newExporter creates the configured span exporter; stdout spans go to w
+func newExporter(ctx context.Context, cfg config.TracingConfig, w io.Writer) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(w), stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		return exporter, nil
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err := otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+		}
+		return exporter, nil
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+}
+
+// Tracer returns the tracer of the global provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(InstrumentationName)
+}
+
+// Start starts a span named name for an operation on entity records in
+// format, e.g. Start(ctx, "avro.serialize", "avro", "user")
+func Start(ctx context.Context, name, format, entity string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, AttrFormat.String(format), AttrEntity.String(entity))
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the row count and any error on span and ends it. A negative
+// row count is not recorded. Cancellation is recorded as an error but not
+// as a failed status, since the caller asked for it
+func End(span trace.Span, rows int, err error) {
+	if rows >= 0 {
+		span.SetAttributes(AttrRows.Int(rows))
+	}
+	if err != nil {
+		span.RecordError(err)
+		if !errors.Is(err, context.Canceled) {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End()
+}
+
+// SetBytes records the encoded size on the span in ctx, if any
+func SetBytes(ctx context.Context, n int64) {
+	trace.SpanFromContext(ctx).SetAttributes(AttrBytes.Int64(n))
+}
+
+// File returns the attribute naming the file or storage key of an operation
+func File(name string) attribute.KeyValue {
+	return AttrFile.String(name)
+}
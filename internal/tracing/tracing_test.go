@@ -0,0 +1,130 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+
+	"go-transport-prac/internal/config"
+)
+
+// record installs a provider exporting spans synchronously to memory
+func record(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		provider.Shutdown(context.Background())
+		otel.SetTracerProvider(previous)
+	})
+	return exporter
+}
+
+func attrs(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	values := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+func TestSpans(t *testing.T) {
+	exporter := record(t)
+
+	ctx, span := Start(context.Background(), "avro.write", "avro", "user", File("users.avro"))
+	SetBytes(ctx, 512)
+	End(span, 3, nil)
+
+	_, span = Start(context.Background(), "avro.read", "avro", "user")
+	End(span, 0, errors.New("corrupt file"))
+
+	_, span = Start(context.Background(), "avro.read", "avro", "user")
+	End(span, -1, context.Canceled)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(spans))
+	}
+
+	got := attrs(spans[0])
+	if got[AttrFormat].AsString() != "avro" || got[AttrEntity].AsString() != "user" || got[AttrFile].AsString() != "users.avro" {
+		t.Errorf("Expected format, entity and file attributes, got %v", spans[0].Attributes)
+	}
+	if got[AttrBytes].AsInt64() != 512 || got[AttrRows].AsInt64() != 3 {
+		t.Errorf("Expected 512 bytes and 3 rows, got %v", spans[0].Attributes)
+	}
+	if spans[1].Status.Code != codes.Error || len(spans[1].Events) != 1 {
+		t.Errorf("Expected a failed span with the error recorded, got %v %v", spans[1].Status, spans[1].Events)
+	}
+	if _, ok := attrs(spans[2])[AttrRows]; ok || spans[2].Status.Code == codes.Error {
+		t.Errorf("Expected a cancelled span without rows or error status, got %v %v", spans[2].Attributes, spans[2].Status)
+	}
+
+	t.Log("✓ Spans record format, entity, size, rows and errors")
+}
+
+func TestPropagation(t *testing.T) {
+	record(t)
+	if _, err := Setup(context.Background(), config.TracingConfig{}); err != nil {
+		t.Fatalf("Failed to set up disabled tracing: %v", err)
+	}
+
+	ctx, span := Tracer().Start(context.Background(), "parent")
+	defer span.End()
+	want := span.SpanContext().TraceID()
+
+	header := http.Header{}
+	InjectHeader(ctx, header)
+	if got := trace.SpanContextFromContext(ExtractHeader(context.Background(), header)).TraceID(); got != want {
+		t.Errorf("Expected trace %s from HTTP headers, got %s", want, got)
+	}
+
+	m := map[string]string{}
+	InjectMap(ctx, m)
+	if got := trace.SpanContextFromContext(ExtractMap(context.Background(), m)).TraceID(); got != want {
+		t.Errorf("Expected trace %s from map, got %s", want, got)
+	}
+
+	outgoing := InjectOutgoing(metadata.AppendToOutgoingContext(ctx, "x-request-id", "42"))
+	md, _ := metadata.FromOutgoingContext(outgoing)
+	if len(md.Get("x-request-id")) != 1 {
+		t.Errorf("Expected existing metadata to be kept, got %v", md)
+	}
+	incoming := metadata.NewIncomingContext(context.Background(), md)
+	if got := trace.SpanContextFromContext(ExtractIncoming(incoming)).TraceID(); got != want {
+		t.Errorf("Expected trace %s from gRPC metadata, got %s", want, got)
+	}
+
+	t.Log("✓ Trace context round-trips through headers, maps and gRPC metadata")
+}
+
+func TestSetup(t *testing.T) {
+	shutdown, err := Setup(context.Background(), config.TracingConfig{})
+	if err != nil || shutdown(context.Background()) != nil {
+		t.Fatalf("Expected disabled tracing to set up and shut down cleanly, got %v", err)
+	}
+	if _, err := Setup(context.Background(), config.TracingConfig{Enabled: true, Exporter: "zipkin"}); err == nil {
+		t.Error("Expected an unknown exporter to fail")
+	}
+
+	provider, err := NewProvider(config.TracingConfig{ServiceName: "test", SampleRatio: 0}, tracetest.NewInMemoryExporter())
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Shutdown(context.Background())
+	if _, span := provider.Tracer("test").Start(context.Background(), "dropped"); span.SpanContext().IsSampled() {
+		t.Error("Expected a zero sample ratio to drop root spans")
+	}
+
+	t.Log("✓ Setup builds the configured provider")
+}
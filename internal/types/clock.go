@@ -0,0 +1,26 @@
+package types
+
+import (
+	"time"
+)
+
+// Clock is the source of the current time for timestamp generation
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock reads the wall clock
+type SystemClock struct{}
+
+// Now returns the current local time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// ClockOrSystem returns c, or SystemClock when c is nil
+func ClockOrSystem(c Clock) Clock {
+	if c == nil {
+		return SystemClock{}
+	}
+	return c
+}
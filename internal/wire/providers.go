@@ -50,11 +50,13 @@ var TestProviderSet = wire.NewSet(
 func ProvideTestConfig() *config.Config {
 	return &config.Config{
 		Server: config.ServerConfig{
-			HTTPPort:    8080,
-			GRPCPort:    8081,
-			WSPort:      8082,
-			GraphQLPort: 9090,
-			Host:        "localhost",
+			HTTPPort:     8080,
+			GRPCPort:     8081,
+			WSPort:       8082,
+			GraphQLPort:  9090,
+			TCPPort:      8083,
+			RegistryPort: 8085,
+			Host:         "localhost",
 		},
 		Database: config.DatabaseConfig{
 			Host:     "localhost",
@@ -68,6 +70,17 @@ func ProvideTestConfig() *config.Config {
 			Host: "localhost",
 			Port: 6379,
 		},
+		Kafka: config.KafkaConfig{
+			Brokers:  []string{"localhost:9092"},
+			GroupID:  "test-group",
+			ClientID: "test-client",
+			Format:   "avro",
+		},
+		NATS: config.NATSConfig{
+			URL:    "nats://localhost:4222",
+			Name:   "test-client",
+			Format: "avro",
+		},
 		Logging: config.LoggingConfig{
 			Level:       "debug",
 			Format:      "console",
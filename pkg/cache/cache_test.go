@@ -0,0 +1,305 @@
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/config"
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/internal/types"
+)
+
+// fakeRedis speaks the subset of RESP2 RedisCache uses; other commands,
+// including the client's connection handshake, get an error reply
+type fakeRedis struct {
+	listener net.Listener
+	mu       sync.Mutex
+	values   map[string]string
+	expires  map[string]time.Time
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	f := &fakeRedis{listener: listener, values: make(map[string]string), expires: make(map[string]time.Time)}
+	go f.serve()
+	t.Cleanup(func() { listener.Close() })
+	return f
+}
+
+func (f *fakeRedis) config() config.RedisConfig {
+	addr := f.listener.Addr().(*net.TCPAddr)
+	return config.RedisConfig{Host: addr.IP.String(), Port: addr.Port, PoolSize: 2, DialTimeout: time.Second, ReadTimeout: time.Second, WriteTimeout: time.Second}
+}
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		io.WriteString(conn, f.exec(args))
+	}
+}
+
+// readCommand reads one array-of-bulk-strings command
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		if line, err = r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func (f *fakeRedis) exec(args []string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for key, at := range f.expires {
+		if !time.Now().Before(at) {
+			delete(f.values, key)
+			delete(f.expires, key)
+		}
+	}
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "GET":
+		value, ok := f.values[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case "SET":
+		f.values[args[1]] = args[2]
+		delete(f.expires, args[1])
+		if len(args) == 5 {
+			n, _ := strconv.Atoi(args[4])
+			unit := time.Second
+			if strings.EqualFold(args[3], "px") {
+				unit = time.Millisecond
+			}
+			f.expires[args[1]] = time.Now().Add(time.Duration(n) * unit)
+		}
+		return "+OK\r\n"
+	case "DEL", "EXISTS":
+		count := 0
+		for _, key := range args[1:] {
+			if _, ok := f.values[key]; ok {
+				count++
+				if strings.EqualFold(args[0], "DEL") {
+					delete(f.values, key)
+					delete(f.expires, key)
+				}
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", count)
+	default:
+		return "-ERR unknown command '" + args[0] + "'\r\n"
+	}
+}
+
+// exerciseCache checks the types.Cache contract shared by every backend
+func exerciseCache(t *testing.T, c types.Cache, expire func(time.Duration)) {
+	t.Helper()
+	ctx := t.Context()
+
+	if _, err := c.Get(ctx, "missing"); !IsMiss(err) {
+		t.Errorf("Expected a miss, got %v", err)
+	}
+
+	if err := c.Set(ctx, "schema:1", []byte(`{"type":"string"}`), 0); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	value, err := c.Get(ctx, "schema:1")
+	if err != nil || string(value) != `{"type":"string"}` {
+		t.Errorf("Expected cached value, got %q (%v)", value, err)
+	}
+	if exists, err := c.Exists(ctx, "schema:1"); err != nil || !exists {
+		t.Errorf("Expected key to exist, got %v (%v)", exists, err)
+	}
+
+	if err := c.Delete(ctx, "schema:1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if exists, err := c.Exists(ctx, "schema:1"); err != nil || exists {
+		t.Errorf("Expected key to be gone, got %v (%v)", exists, err)
+	}
+
+	if err := c.Set(ctx, "short", []byte("x"), 50*time.Millisecond); err != nil {
+		t.Fatalf("Set with TTL failed: %v", err)
+	}
+	if _, err := c.Get(ctx, "short"); err != nil {
+		t.Errorf("Expected key before expiry, got %v", err)
+	}
+	expire(100 * time.Millisecond)
+	if _, err := c.Get(ctx, "short"); !IsMiss(err) {
+		t.Errorf("Expected expired key to miss, got %v", err)
+	}
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestMemoryCache(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := NewMemoryCache().WithClock(clock)
+
+	exerciseCache(t, c, func(d time.Duration) { clock.now = clock.now.Add(d) })
+	t.Log("✓ Memory cache satisfies the cache contract")
+}
+
+func TestFileCache(t *testing.T) {
+	testDir := "tmp/test_file_cache"
+	defer os.RemoveAll(testDir)
+	path := filepath.Join(testDir, "nested", "cache.json")
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	c, err := OpenFileCache(path)
+	if err != nil {
+		t.Fatalf("Failed to open cache: %v", err)
+	}
+	exerciseCache(t, c.WithClock(clock), func(d time.Duration) { clock.now = clock.now.Add(d) })
+
+	ctx := t.Context()
+	c.Set(ctx, "keep", []byte("1"), 0)
+	c.Set(ctx, "drop", []byte("2"), 0)
+	c.Set(ctx, "expiring", []byte("3"), time.Hour)
+	c.Set(ctx, "expired", []byte("4"), time.Minute)
+	clock.now = clock.now.Add(2 * time.Minute)
+	if err := c.Close(); err != nil {
+		t.Fatalf("Failed to save cache: %v", err)
+	}
+
+	reopened, err := OpenFileCache(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen cache: %v", err)
+	}
+	reopened.WithClock(clock)
+	if reopened.Len() != 3 {
+		t.Errorf("Expected 3 persisted entries, got %d", reopened.Len())
+	}
+	if value, err := reopened.Get(ctx, "keep"); err != nil || string(value) != "1" {
+		t.Errorf("Expected persisted value, got %q (%v)", value, err)
+	}
+	// Expiry times survive the reload
+	clock.now = clock.now.Add(time.Hour)
+	if _, err := reopened.Get(ctx, "expiring"); !IsMiss(err) {
+		t.Errorf("Expected the persisted entry to expire, got %v", err)
+	}
+
+	if dropped := reopened.Prune(func(key string, _ []byte) bool { return key != "drop" }); dropped != 1 {
+		t.Errorf("Expected 1 pruned entry, got %d", dropped)
+	}
+	if exists, _ := reopened.Exists(ctx, "drop"); exists {
+		t.Error("Expected the pruned entry to be gone")
+	}
+
+	// A corrupt file is discarded rather than failing the process
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatalf("Failed to corrupt cache file: %v", err)
+	}
+	corrupt, err := OpenFileCache(path)
+	if err != nil || corrupt.Len() != 0 {
+		t.Errorf("Expected an empty cache from a corrupt file, got %d entries (%v)", corrupt.Len(), err)
+	}
+
+	t.Log("✓ File cache persists unexpired entries across reopens")
+}
+
+func TestRedisCache(t *testing.T) {
+	server := newFakeRedis(t)
+	c := NewRedisCache(server.config())
+	defer c.Close()
+
+	if err := c.Ping(t.Context()); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+
+	exerciseCache(t, c, time.Sleep)
+	t.Log("✓ Redis cache satisfies the cache contract")
+}
+
+func TestRedisCacheUnreachable(t *testing.T) {
+	c := NewRedisCache(config.RedisConfig{Host: "127.0.0.1", Port: 1, DialTimeout: 100 * time.Millisecond})
+	defer c.Close()
+
+	_, err := c.Get(t.Context(), "key")
+	if err == nil || IsMiss(err) {
+		t.Errorf("Expected a connection error, got %v", err)
+	}
+	t.Log("✓ Connection failures are not reported as misses")
+}
+
+func TestInstrumentedCache(t *testing.T) {
+	log, err := logger.NewDevelopment()
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	c := Instrument(NewMemoryCache(), "schemas", log)
+	ctx := t.Context()
+
+	c.Set(ctx, "a", []byte("1"), 0)
+	c.Get(ctx, "a")
+	c.Get(ctx, "a")
+	c.Get(ctx, "b")
+
+	stats := c.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Errors != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if ratio := stats.HitRatio(); ratio < 0.66 || ratio > 0.67 {
+		t.Errorf("Expected hit ratio 2/3, got %f", ratio)
+	}
+	c.LogStats()
+
+	t.Log("✓ Instrumented cache counts hits and misses")
+}
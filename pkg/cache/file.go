@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go-transport-prac/internal/types"
+)
+
+var _ types.Cache = (*FileCache)(nil)
+
+// fileCacheVersion is the format version written to cache files; files of
+// another version are ignored on load
+const fileCacheVersion = 1
+
+// FileCache is a MemoryCache persisted to a local file, so short-lived
+// processes and restarted services reuse what earlier runs cached. Entries are
+// loaded by OpenFileCache and written back by Save and Close
+type FileCache struct {
+	*MemoryCache
+	path string
+}
+
+type fileCacheData struct {
+	Version int                       `json:"version"`
+	Entries map[string]fileCacheEntry `json:"entries"`
+}
+
+type fileCacheEntry struct {
+	Value     []byte    `json:"value"`
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+}
+
+// OpenFileCache loads the cache stored at path. A missing, unreadable or
+// outdated file starts an empty cache, since everything in it can be refetched
+func OpenFileCache(path string) (*FileCache, error) {
+	c := &FileCache{MemoryCache: NewMemoryCache(), path: path}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache file: %w", err)
+	}
+
+	var stored fileCacheData
+	if json.Unmarshal(data, &stored) != nil || stored.Version != fileCacheVersion {
+		return c, nil
+	}
+	for key, entry := range stored.Entries {
+		c.entries[key] = memoryEntry{value: entry.Value, expiresAt: entry.ExpiresAt}
+	}
+	return c, nil
+}
+
+// WithClock sets the clock used to expire entries
+func (c *FileCache) WithClock(clock types.Clock) *FileCache {
+	c.MemoryCache.WithClock(clock)
+	return c
+}
+
+// Path returns the file the cache is persisted to
+func (c *FileCache) Path() string {
+	return c.path
+}
+
+// Len returns the number of unexpired entries
+func (c *FileCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for key := range c.entries {
+		if _, ok := c.lookup(key); ok {
+			n++
+		}
+	}
+	return n
+}
+
+// Prune drops every entry keep rejects, such as entries that no longer match
+// their source, and returns how many were dropped
+func (c *FileCache) Prune(keep func(key string, value []byte) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dropped := 0
+	for key, entry := range c.entries {
+		if !keep(key, entry.value) {
+			delete(c.entries, key)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// Save writes the unexpired entries to the cache file, replacing it atomically
+func (c *FileCache) Save() error {
+	c.mu.Lock()
+	stored := fileCacheData{Version: fileCacheVersion, Entries: make(map[string]fileCacheEntry, len(c.entries))}
+	for key := range c.entries {
+		if entry, ok := c.lookup(key); ok {
+			stored.Entries[key] = fileCacheEntry{Value: entry.value, ExpiresAt: entry.expiresAt}
+		}
+	}
+	c.mu.Unlock()
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to replace cache file: %w", err)
+	}
+	return nil
+}
+
+// Close saves the cache. Unlike MemoryCache, the entries are kept
+func (c *FileCache) Close() error {
+	return c.Save()
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/internal/types"
+)
+
+// CodeCacheMiss marks the NotFound error returned for keys that are not cached
+const CodeCacheMiss = "CACHE_MISS"
+
+// IsMiss reports whether err is a cache miss
+func IsMiss(err error) bool {
+	return errors.IsCode(err, CodeCacheMiss)
+}
+
+func miss(key string) error {
+	return errors.NotFoundError(CodeCacheMiss, fmt.Sprintf("key %s not cached", key))
+}
+
+var _ types.Cache = (*InstrumentedCache)(nil)
+
+// Stats counts cache lookups
+type Stats struct {
+	Hits   int64
+	Misses int64
+	Errors int64
+}
+
+// HitRatio returns the fraction of lookups that hit, or 0 before any lookup
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// InstrumentedCache wraps a cache, counting hits and misses and logging each lookup
+type InstrumentedCache struct {
+	types.Cache
+	name   string
+	logger *logger.Logger
+	hits   atomic.Int64
+	misses atomic.Int64
+	errors atomic.Int64
+}
+
+// Instrument wraps c; name identifies the cache in log entries
+func Instrument(c types.Cache, name string, log *logger.Logger) *InstrumentedCache {
+	if log == nil {
+		log = logger.Global()
+	}
+	return &InstrumentedCache{
+		Cache:  c,
+		name:   name,
+		logger: log.WithComponent("cache").WithFields(zap.String("cache", name)),
+	}
+}
+
+// Get looks key up in the wrapped cache and records the outcome
+func (c *InstrumentedCache) Get(ctx context.Context, key string) ([]byte, error) {
+	start := time.Now()
+	value, err := c.Cache.Get(ctx, key)
+
+	duration := zap.Duration("duration", time.Since(start))
+
+	switch {
+	case err == nil:
+		c.hits.Add(1)
+		c.logger.LogCacheOperation("get", key, true, duration, zap.Int64("hits", c.hits.Load()))
+	case IsMiss(err):
+		c.misses.Add(1)
+		c.logger.LogCacheOperation("get", key, false, duration, zap.Int64("misses", c.misses.Load()))
+	default:
+		c.errors.Add(1)
+		c.logger.Warn("Cache lookup failed", zap.String("cache_key", key), zap.Error(err))
+	}
+	return value, err
+}
+
+// Stats returns the lookup counters
+func (c *InstrumentedCache) Stats() Stats {
+	return Stats{Hits: c.hits.Load(), Misses: c.misses.Load(), Errors: c.errors.Load()}
+}
+
+// LogStats writes the lookup counters at info level
+func (c *InstrumentedCache) LogStats() {
+	stats := c.Stats()
+	c.logger.Info("Cache stats",
+		zap.Int64("hits", stats.Hits),
+		zap.Int64("misses", stats.Misses),
+		zap.Int64("errors", stats.Errors),
+		zap.Float64("hit_ratio", stats.HitRatio()))
+}
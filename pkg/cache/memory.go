@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-transport-prac/internal/types"
+)
+
+var _ types.Cache = (*MemoryCache)(nil)
+
+// MemoryCache keeps values in memory, for tests and single-process use
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	clock   types.Clock
+}
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryCache creates an empty in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]memoryEntry), clock: types.SystemClock{}}
+}
+
+// WithClock sets the clock used to expire entries
+func (c *MemoryCache) WithClock(clock types.Clock) *MemoryCache {
+	c.clock = types.ClockOrSystem(clock)
+	return c
+}
+
+// Get returns the value stored under key, or a miss error
+func (c *MemoryCache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.lookup(key)
+	if !ok {
+		return nil, miss(key)
+	}
+	return append([]byte(nil), entry.value...), nil
+}
+
+// Set stores a copy of value under key; a zero expiration keeps it until deleted
+func (c *MemoryCache) Set(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	entry := memoryEntry{value: append([]byte(nil), value...)}
+	if expiration > 0 {
+		entry.expiresAt = c.clock.Now().Add(expiration)
+	}
+
+	c.mu.Lock()
+	c.entries[key] = entry
+	c.mu.Unlock()
+	return nil
+}
+
+// Delete removes key
+func (c *MemoryCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+	return nil
+}
+
+// Exists reports whether an unexpired value is stored under key
+func (c *MemoryCache) Exists(ctx context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.lookup(key)
+	return ok, nil
+}
+
+// Close drops every entry
+func (c *MemoryCache) Close() error {
+	c.mu.Lock()
+	c.entries = make(map[string]memoryEntry)
+	c.mu.Unlock()
+	return nil
+}
+
+// lookup returns the entry for key, evicting it if expired; the caller holds mu
+func (c *MemoryCache) lookup(key string) (memoryEntry, bool) {
+	entry, ok := c.entries[key]
+	if !ok {
+		return memoryEntry{}, false
+	}
+	if !entry.expiresAt.IsZero() && !c.clock.Now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return memoryEntry{}, false
+	}
+	return entry, true
+}
@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"go-transport-prac/internal/config"
+	"go-transport-prac/internal/types"
+)
+
+var _ types.Cache = (*RedisCache)(nil)
+
+// RedisCache stores values in Redis
+type RedisCache struct {
+	client *redis.Client
+}
+
+// NewRedisCache creates a cache connected to the configured Redis server.
+// Connections are opened lazily, so an unreachable server surfaces on first use
+func NewRedisCache(cfg config.RedisConfig) *RedisCache {
+	return &RedisCache{client: redis.NewClient(&redis.Options{
+		Addr:         net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		Password:     cfg.Password,
+		DB:           cfg.Database,
+		MaxRetries:   cfg.MaxRetries,
+		PoolSize:     cfg.PoolSize,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+	})}
+}
+
+// Ping checks that the server is reachable
+func (c *RedisCache) Ping(ctx context.Context) error {
+	if err := c.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to ping redis: %w", err)
+	}
+	return nil
+}
+
+// Get returns the value stored under key, or a miss error
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, miss(key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// Set stores value under key; a zero expiration keeps it until deleted
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	if err := c.client.Set(ctx, key, value, expiration).Err(); err != nil {
+		return fmt.Errorf("failed to set %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes key
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	if err := c.client.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// Exists reports whether key is stored
+func (c *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := c.client.Exists(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check %s: %w", key, err)
+	}
+	return n > 0, nil
+}
+
+// Close closes the connection pool
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
@@ -0,0 +1,231 @@
+package cdc
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/transport/kafka"
+)
+
+// memoryBroker delivers published messages to the topic's handler synchronously
+type memoryBroker struct {
+	mu       sync.Mutex
+	handlers map[string]types.MessageHandler
+	log      []types.Message
+}
+
+func (b *memoryBroker) Publish(ctx context.Context, topic string, message []byte) error {
+	b.mu.Lock()
+	msg := types.Message{Topic: topic, Data: message, Timestamp: time.Now()}
+	b.log = append(b.log, msg)
+	handler := b.handlers[topic]
+	b.mu.Unlock()
+	if handler == nil {
+		return nil
+	}
+	return handler(ctx, msg)
+}
+
+func (b *memoryBroker) Subscribe(ctx context.Context, topic string, handler types.MessageHandler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.handlers == nil {
+		b.handlers = make(map[string]types.MessageHandler)
+	}
+	b.handlers[topic] = handler
+	return nil
+}
+
+func (b *memoryBroker) Unsubscribe(ctx context.Context, topic string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.handlers, topic)
+	return nil
+}
+
+func (b *memoryBroker) Close() error {
+	return nil
+}
+
+// snapshots returns a user table and a later version of it with one user
+// deleted, two updated and two inserted
+func snapshots(t *testing.T) ([]avro.User, []avro.User) {
+	manager, err := avro.NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create avro manager: %v", err)
+	}
+	before := manager.CreateSampleUsers(5)
+	next := manager.CreateSampleUsers(7)
+
+	after := []avro.User{before[0], before[2], before[4], next[5], next[6]}
+	after[1].Status = avro.UserStatusSuspended
+	profile := *after[2].Profile
+	profile.Interests = []string{"cooking"}
+	after[2].Profile = &profile
+	after[2].UpdatedAt = after[2].UpdatedAt.Add(time.Hour)
+	return before, after
+}
+
+// newCodecs returns a producer codec pinned to a change schema version and
+// an unpinned consumer codec sharing one registry
+func newCodecs(t *testing.T, registry *avro.SchemaRegistry, producerVersion int) (*kafka.AvroCodec, *kafka.AvroCodec) {
+	manager, err := avro.NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create avro manager: %v", err)
+	}
+	producer, err := NewCodec(manager, registry)
+	if err != nil {
+		t.Fatalf("Failed to create codec: %v", err)
+	}
+	if err := producer.PinSchemaVersion(Subject("users.cdc"), producerVersion); err != nil {
+		t.Fatalf("Failed to pin schema version: %v", err)
+	}
+	consumer, err := NewCodec(manager, registry)
+	if err != nil {
+		t.Fatalf("Failed to create codec: %v", err)
+	}
+	return producer, consumer
+}
+
+func assertState(t *testing.T, got, want []avro.User) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d users, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if !SameUser(got[i], want[i]) {
+			t.Errorf("User %d: expected %+v, got %+v", want[i].ID, want[i], got[i])
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before, after := snapshots(t)
+	events := Diff(before, after)
+
+	want := []struct {
+		op  Op
+		key int64
+	}{{OpDelete, 2}, {OpUpdate, 3}, {OpDelete, 4}, {OpUpdate, 5}, {OpInsert, 6}, {OpInsert, 7}}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d changes, got %+v", len(want), events)
+	}
+	for i, w := range want {
+		e := events[i]
+		if e.Op != w.op || e.Key != w.key {
+			t.Errorf("Change %d: expected %s %d, got %s %d", i, w.op, w.key, e.Op, e.Key)
+		}
+		if (e.Before == nil) != (e.Op == OpInsert) || (e.After == nil) != (e.Op == OpDelete) {
+			t.Errorf("Change %d: %s has before %v and after %v", i, e.Op, e.Before != nil, e.After != nil)
+		}
+	}
+	if len(Diff(after, after)) != 0 {
+		t.Error("Expected identical snapshots to produce no changes")
+	}
+
+	t.Log("✓ Snapshots diff into ordered inserts, updates and deletes")
+}
+
+func TestMixedVersionStream(t *testing.T) {
+	registry := avro.NewSchemaRegistry()
+	if err := RegisterSchemas(registry, "users.cdc"); err != nil {
+		t.Fatalf("Failed to register schemas: %v", err)
+	}
+	v1Producer, consumerCodec := newCodecs(t, registry, 1)
+	v2Producer, _ := newCodecs(t, registry, 2)
+
+	broker := &memoryBroker{}
+	consumer := NewConsumer(consumerCodec, "users.cdc")
+	if err := broker.Subscribe(context.Background(), "users.cdc", consumer.Handle); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	// A v1 service captures the initial load, then an upgraded v2 service
+	// continues the same stream
+	before, after := snapshots(t)
+	initial := Diff(nil, before)
+	old := NewEmitter(v1Producer, "users.cdc", BrokerPublisher(broker))
+	if err := old.Emit(context.Background(), initial...); err != nil {
+		t.Fatalf("Failed to emit initial load: %v", err)
+	}
+	upgraded := NewEmitter(v2Producer, "users.cdc", BrokerPublisher(broker)).WithSequence(old.Sequence())
+	changes := Diff(before, after)
+	if err := upgraded.Emit(context.Background(), changes...); err != nil {
+		t.Fatalf("Failed to emit changes: %v", err)
+	}
+
+	assertState(t, consumer.State(), after)
+	stats := consumer.Stats()
+	if stats.Inserts != 7 || stats.Updates != 2 || stats.Deletes != 2 || stats.Duplicates != 0 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	v1, _ := registry.GetSchemaVersion(Subject("users.cdc"), 1)
+	v2, _ := registry.GetSchemaVersion(Subject("users.cdc"), 2)
+	if stats.WriterSchemas[v1.ID] != int64(len(initial)) || stats.WriterSchemas[v2.ID] != int64(len(changes)) {
+		t.Errorf("Expected %d v1 and %d v2 events, got %v", len(initial), len(changes), stats.WriterSchemas)
+	}
+
+	// Redelivering the whole stream leaves the state unchanged
+	for _, msg := range broker.log {
+		if err := consumer.Handle(context.Background(), msg); err != nil {
+			t.Fatalf("Failed to handle redelivered change: %v", err)
+		}
+	}
+	assertState(t, consumer.State(), after)
+	if stats := consumer.Stats(); stats.Duplicates != int64(len(broker.log)) {
+		t.Errorf("Expected %d duplicates, got %+v", len(broker.log), stats)
+	}
+
+	if err := consumer.Handle(context.Background(), types.Message{Data: []byte{0xff}}); err == nil {
+		t.Error("Expected an undecodable change to fail")
+	}
+
+	t.Log("✓ A consumer rebuilds the table from a stream mixing v1 and v2 events")
+}
+
+func TestFileStream(t *testing.T) {
+	registry := avro.NewSchemaRegistry()
+	if err := RegisterSchemas(registry, "users.cdc"); err != nil {
+		t.Fatalf("Failed to register schemas: %v", err)
+	}
+	producer, consumerCodec := newCodecs(t, registry, 1)
+
+	before, after := snapshots(t)
+	var buf bytes.Buffer
+	at := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	emitter := NewEmitter(producer, "users.cdc", FilePublisher(&buf)).WithClock(testutil.NewFakeClock(at))
+	if err := emitter.Emit(context.Background(), Diff(nil, before)...); err != nil {
+		t.Fatalf("Failed to emit initial load: %v", err)
+	}
+	if err := emitter.Emit(context.Background(), Diff(before, after)...); err != nil {
+		t.Fatalf("Failed to emit changes: %v", err)
+	}
+
+	consumer := NewConsumer(consumerCodec, "users.cdc")
+	n, err := consumer.Replay(&buf)
+	if err != nil {
+		t.Fatalf("Failed to replay file: %v", err)
+	}
+	if n != int(emitter.Sequence()) {
+		t.Errorf("Expected %d events, read %d", emitter.Sequence(), n)
+	}
+	assertState(t, consumer.State(), after)
+
+	if err := consumer.Apply(ChangeEvent{Op: OpUpdate, Key: 42, Seq: 100}); err == nil {
+		t.Error("Expected an update without an after image to fail")
+	}
+	if err := consumer.Apply(Diff(nil, after[:1])[0]); err == nil {
+		t.Error("Expected an unstamped insert to fail")
+	}
+	if _, err := consumer.Replay(bytes.NewReader([]byte{0, 0, 0, 9, 1})); err == nil {
+		t.Error("Expected a truncated stream to fail")
+	}
+
+	t.Log("✓ Change events replay from a framed file")
+}
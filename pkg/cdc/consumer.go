@@ -0,0 +1,160 @@
+package cdc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/transport/framing"
+	"go-transport-prac/pkg/transport/kafka"
+)
+
+// Stats counts the changes a consumer has applied
+type Stats struct {
+	Inserts int64
+	Updates int64
+	Deletes int64
+	// Duplicates counts redelivered events at or below the key's applied sequence
+	Duplicates int64
+	// WriterSchemas counts events by the registry ID of the schema they were written with
+	WriterSchemas map[int]int64
+}
+
+// Consumer rebuilds the latest state of the user table from change events
+type Consumer struct {
+	codec *kafka.AvroCodec
+	topic string
+
+	mu      sync.Mutex
+	state   map[int64]avro.User
+	applied map[int64]int64 // key -> sequence of the last applied change
+	stats   Stats
+}
+
+// NewConsumer creates a consumer decoding the events of topic with codec
+func NewConsumer(codec *kafka.AvroCodec, topic string) *Consumer {
+	return &Consumer{
+		codec:   codec,
+		topic:   topic,
+		state:   make(map[int64]avro.User),
+		applied: make(map[int64]int64),
+		stats:   Stats{WriterSchemas: make(map[int]int64)},
+	}
+}
+
+// Handle decodes and applies a consumed message. Unlike a sink, a consumer
+// rebuilding state cannot skip a change, so decode failures are returned for
+// the broker to retry or dead-letter
+func (c *Consumer) Handle(ctx context.Context, msg types.Message) error {
+	return c.apply(msg.Data)
+}
+
+// Replay applies every event of a stream written by FilePublisher,
+// returning the number of events read
+func (c *Consumer) Replay(r io.Reader) (int, error) {
+	frames := framing.NewReader(r, framing.PrefixFixed32)
+	var n int
+	for {
+		data, err := frames.ReadFrame()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, fmt.Errorf("failed to read change %d: %w", n+1, err)
+		}
+		if err := c.apply(data); err != nil {
+			return n, err
+		}
+		n++
+	}
+}
+
+// apply decodes one framed event and applies it
+func (c *Consumer) apply(data []byte) error {
+	schemaID, _, err := kafka.DecodeWireFormat(data)
+	if err != nil {
+		return fmt.Errorf("failed to decode change event: %w", err)
+	}
+	var event ChangeEvent
+	if err := c.codec.Decode(c.topic, data, &event); err != nil {
+		return fmt.Errorf("failed to decode change event: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.applyLocked(event); err != nil {
+		return err
+	}
+	c.stats.WriterSchemas[schemaID]++
+	return nil
+}
+
+// Apply applies a decoded event. Events at or below the sequence already
+// applied to their key are counted as duplicates and ignored, so replaying a
+// stream is idempotent. Events must be stamped with a positive sequence, as
+// an Emitter does; the unstamped output of Diff is rejected
+func (c *Consumer) Apply(event ChangeEvent) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.applyLocked(event)
+}
+
+func (c *Consumer) applyLocked(event ChangeEvent) error {
+	if event.Seq <= 0 {
+		return fmt.Errorf("%s of key %d has no sequence number", event.Op, event.Key)
+	}
+	if event.Seq <= c.applied[event.Key] {
+		c.stats.Duplicates++
+		return nil
+	}
+
+	switch event.Op {
+	case OpInsert, OpUpdate:
+		if event.After == nil {
+			return fmt.Errorf("change %d: %s of key %d has no after image", event.Seq, event.Op, event.Key)
+		}
+		c.state[event.Key] = *event.After
+		if event.Op == OpInsert {
+			c.stats.Inserts++
+		} else {
+			c.stats.Updates++
+		}
+	case OpDelete:
+		delete(c.state, event.Key)
+		c.stats.Deletes++
+	default:
+		return fmt.Errorf("change %d: unknown operation %q", event.Seq, event.Op)
+	}
+	c.applied[event.Key] = event.Seq
+	return nil
+}
+
+// State returns the current users ordered by ID
+func (c *Consumer) State() []avro.User {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	users := make([]avro.User, 0, len(c.state))
+	for _, u := range c.state {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users
+}
+
+// Stats returns a snapshot of the consumer's counts
+func (c *Consumer) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.WriterSchemas = make(map[int]int64, len(c.stats.WriterSchemas))
+	for id, n := range c.stats.WriterSchemas {
+		stats.WriterSchemas[id] = n
+	}
+	return stats
+}
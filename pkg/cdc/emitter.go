@@ -0,0 +1,91 @@
+package cdc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/transport/framing"
+	"go-transport-prac/pkg/transport/kafka"
+)
+
+// Publisher delivers one encoded change event
+type Publisher func(ctx context.Context, topic string, data []byte) error
+
+// BrokerPublisher publishes events to a message broker topic
+func BrokerPublisher(broker types.MessageBroker) Publisher {
+	return broker.Publish
+}
+
+// FilePublisher appends events to w as fixed32 length-prefixed frames, the
+// layout Consumer.Replay reads
+func FilePublisher(w io.Writer) Publisher {
+	frames := framing.NewWriter(w, framing.PrefixFixed32)
+	return func(ctx context.Context, topic string, data []byte) error {
+		return frames.WriteFrame(data)
+	}
+}
+
+// Emitter stamps change events with sequence numbers and publishes them
+// encoded with its codec
+type Emitter struct {
+	codec   *kafka.AvroCodec
+	topic   string
+	publish Publisher
+	clock   types.Clock
+
+	mu  sync.Mutex
+	seq int64
+}
+
+// NewEmitter creates an emitter publishing to topic
+func NewEmitter(codec *kafka.AvroCodec, topic string, publish Publisher) *Emitter {
+	return &Emitter{
+		codec:   codec,
+		topic:   topic,
+		publish: publish,
+		clock:   types.SystemClock{},
+	}
+}
+
+// WithClock sets the clock that timestamps events
+func (e *Emitter) WithClock(clock types.Clock) *Emitter {
+	e.clock = clock
+	return e
+}
+
+// WithSequence continues numbering after seq, for resuming a stream
+func (e *Emitter) WithSequence(seq int64) *Emitter {
+	e.seq = seq
+	return e
+}
+
+// Sequence returns the sequence number of the last emitted event
+func (e *Emitter) Sequence() int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.seq
+}
+
+// Emit stamps and publishes events in order, stopping at the first failure.
+// A failed event does not consume a sequence number
+func (e *Emitter) Emit(ctx context.Context, events ...ChangeEvent) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, event := range events {
+		event.Seq = e.seq + 1
+		event.Timestamp = e.clock.Now()
+		data, err := e.codec.Encode(e.topic, event)
+		if err != nil {
+			return fmt.Errorf("failed to encode change %d: %w", event.Seq, err)
+		}
+		if err := e.publish(ctx, e.topic, data); err != nil {
+			return fmt.Errorf("failed to publish change %d: %w", event.Seq, err)
+		}
+		e.seq = event.Seq
+	}
+	return nil
+}
@@ -0,0 +1,432 @@
+// Package connector moves messages from a broker topic into partitioned
+// Parquet files, in the manner of a Kafka Connect sink: records are buffered
+// per partition and flushed to one file per partition on size or time
+// thresholds. Handlers return as soon as a record is buffered, and the sink
+// commits a message's offset only once the file holding it has been written.
+package connector
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/parquet"
+	"go-transport-prac/pkg/transport/kafka"
+)
+
+// Committer is a broker whose subscriptions leave offsets uncommitted until
+// the subscriber commits the messages it has stored, such as the Kafka broker
+type Committer interface {
+	types.MessageBroker
+	SubscribeManual(ctx context.Context, topic string, handler types.MessageHandler) error
+	Commit(ctx context.Context, msgs ...types.Message) error
+}
+
+var _ Committer = (*kafka.Broker)(nil)
+
+// Decoder turns a consumed message into the Parquet row written for it
+type Decoder[T any] func(msg types.Message) (T, error)
+
+// CodecDecoder decodes messages with a broker codec, such as the Avro codec
+// reading schema registry framed payloads, and converts the decoded model to
+// its Parquet row
+func CodecDecoder[In, Out any](codec kafka.Codec, convert func(In) Out) Decoder[Out] {
+	return func(msg types.Message) (Out, error) {
+		var in In
+		if err := codec.Decode(msg.Topic, msg.Data, &in); err != nil {
+			var zero Out
+			return zero, fmt.Errorf("failed to decode message %s: %w", msg.ID, err)
+		}
+		return convert(in), nil
+	}
+}
+
+// Partitioner names the directory a record is written under, such as
+// dt=2024-06-01/hour=10
+type Partitioner[T any] func(record T, msg types.Message) string
+
+// HourlyPartitioner partitions records by the UTC hour their message was produced
+func HourlyPartitioner[T any](_ T, msg types.Message) string {
+	return msg.Timestamp.UTC().Format("dt=2006-01-02/hour=15")
+}
+
+// Config holds the topic and flush thresholds of a connector
+type Config struct {
+	Topic string
+	// MaxRecords flushes a partition once it buffers this many records
+	MaxRecords int
+	// MaxBytes flushes a partition once its buffered message payloads reach this size
+	MaxBytes int
+	// FlushInterval flushes a partition this long after its first buffered record
+	FlushInterval time.Duration
+}
+
+// DefaultConfig returns thresholds suited to hourly partitions of small records
+func DefaultConfig(topic string) Config {
+	return Config{
+		Topic:         topic,
+		MaxRecords:    10000,
+		MaxBytes:      32 << 20,
+		FlushInterval: 30 * time.Second,
+	}
+}
+
+// Stats summarizes a connector's progress
+type Stats struct {
+	// Records counts records written to committed files
+	Records int64
+	Files   int64
+	// Buffered counts records waiting for their partition to flush
+	Buffered int
+	// Committed counts messages whose offsets were committed
+	Committed int64
+	// Skipped counts messages that did not decode and were committed unwritten
+	Skipped int64
+	// FailedFlushes counts flushes whose records were buffered again for a retry
+	FailedFlushes int64
+}
+
+// delivery is a message in the order the broker delivered it; flushed is set
+// once its record is in a committed file, or at once for skipped messages
+type delivery struct {
+	msg     types.Message
+	flushed bool
+}
+
+// batch is the buffered records of one partition and their deliveries
+type batch[T any] struct {
+	partition  string
+	records    []T
+	deliveries []*delivery
+	bytes      int
+	started    time.Time
+}
+
+// ParquetSink consumes a topic into Parquet files of T under a manager, one
+// directory per partition. Files are written whole and renamed into place.
+// Handlers only buffer records, so batches fill from a broker delivering one
+// message at a time; after each flush the sink commits the longest run of
+// delivered messages that are all written, so an offset is never committed
+// past a record still buffered in another partition. A crash between a flush
+// and its commit redelivers messages already written, making delivery
+// at-least-once at message level but never partial at file level
+type ParquetSink[T any] struct {
+	broker    Committer
+	manager   *parquet.SimpleManager
+	decode    Decoder[T]
+	partition Partitioner[T]
+	cfg       Config
+	clock     types.Clock
+	logger    *logger.Logger
+
+	mu         sync.Mutex
+	batches    map[string]*batch[T]
+	deliveries []*delivery
+	closed     bool
+	seq        int64
+	stats      Stats
+
+	// commitMu keeps commits in delivery order
+	commitMu sync.Mutex
+	flushes  sync.WaitGroup
+	stop     chan struct{}
+	stopped  chan struct{}
+}
+
+// NewParquetSink creates a connector writing decoded records under manager,
+// partitioned hourly; zero config thresholds take their DefaultConfig values
+func NewParquetSink[T any](broker Committer, manager *parquet.SimpleManager, decode Decoder[T], cfg Config, log *logger.Logger) *ParquetSink[T] {
+	defaults := DefaultConfig(cfg.Topic)
+	if cfg.MaxRecords <= 0 {
+		cfg.MaxRecords = defaults.MaxRecords
+	}
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = defaults.MaxBytes
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaults.FlushInterval
+	}
+	if log == nil {
+		log = logger.Global()
+	}
+	return &ParquetSink[T]{
+		broker:    broker,
+		manager:   manager,
+		decode:    decode,
+		partition: HourlyPartitioner[T],
+		cfg:       cfg,
+		clock:     types.SystemClock{},
+		logger:    log.WithComponent("parquet_connector"),
+		batches:   make(map[string]*batch[T]),
+	}
+}
+
+// WithPartitioner sets how records are assigned to partitions
+func (s *ParquetSink[T]) WithPartitioner(p Partitioner[T]) *ParquetSink[T] {
+	s.partition = p
+	return s
+}
+
+// WithClock sets the clock that ages buffered partitions and names files
+func (s *ParquetSink[T]) WithClock(clock types.Clock) *ParquetSink[T] {
+	s.clock = clock
+	return s
+}
+
+// Start subscribes to the topic and starts flushing partitions as they age
+func (s *ParquetSink[T]) Start(ctx context.Context) error {
+	s.stop = make(chan struct{})
+	s.stopped = make(chan struct{})
+	if err := s.broker.SubscribeManual(ctx, s.cfg.Topic, s.Handle); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", s.cfg.Topic, err)
+	}
+	go s.run()
+	return nil
+}
+
+// Handle buffers the record of msg and returns; a partition that reaches a
+// size threshold is flushed before it returns. Messages that do not decode are
+// logged, counted and committed in order, so one bad payload cannot stall the
+// topic. Once the connector is closing, Handle waits for ctx to end and returns
+// its error, leaving the message uncommitted for the next consumer
+func (s *ParquetSink[T]) Handle(ctx context.Context, msg types.Message) error {
+	record, decodeErr := s.decode(msg)
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	d := &delivery{msg: msg}
+	s.deliveries = append(s.deliveries, d)
+
+	if decodeErr != nil {
+		d.flushed = true
+		s.stats.Skipped++
+		s.mu.Unlock()
+		s.logger.Error("Skipping undecodable message",
+			zap.String("topic", msg.Topic), zap.String("message_id", msg.ID), zap.Error(decodeErr))
+		s.commit(ctx)
+		return nil
+	}
+
+	partition := s.partition(record, msg)
+	b, ok := s.batches[partition]
+	if !ok {
+		b = &batch[T]{partition: partition, started: s.clock.Now()}
+		s.batches[partition] = b
+	}
+	b.records = append(b.records, record)
+	b.deliveries = append(b.deliveries, d)
+	b.bytes += len(msg.Data)
+	full := len(b.records) >= s.cfg.MaxRecords || b.bytes >= s.cfg.MaxBytes
+	if full {
+		s.detach(b)
+	}
+	s.mu.Unlock()
+
+	if full {
+		s.flush(b)
+	}
+	return nil
+}
+
+// Flush writes every buffered partition and commits what it can, returning
+// the first flush error
+func (s *ParquetSink[T]) Flush() error {
+	s.mu.Lock()
+	pending := make([]*batch[T], 0, len(s.batches))
+	for _, b := range s.batches {
+		s.detach(b)
+		pending = append(pending, b)
+	}
+	s.mu.Unlock()
+
+	var first error
+	for _, b := range pending {
+		if err := s.flush(b); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Stats returns a snapshot of the connector's progress
+func (s *ParquetSink[T]) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.stats
+	for _, b := range s.batches {
+		stats.Buffered += len(b.records)
+	}
+	return stats
+}
+
+// Close stops accepting messages, flushes and commits the buffered
+// partitions, then unsubscribes. Records that fail the final flush are
+// dropped uncommitted, to be redelivered to the next consumer
+func (s *ParquetSink[T]) Close(ctx context.Context) error {
+	if s.stop != nil {
+		close(s.stop)
+		<-s.stopped
+	}
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	flushErr := s.Flush()
+	s.flushes.Wait()
+	commitErr := s.commit(ctx)
+
+	var unsubErr error
+	if s.stop != nil {
+		unsubErr = s.broker.Unsubscribe(ctx, s.cfg.Topic)
+	}
+
+	s.mu.Lock()
+	s.batches = make(map[string]*batch[T])
+	s.deliveries = nil
+	s.mu.Unlock()
+
+	if flushErr != nil {
+		return flushErr
+	}
+	if commitErr != nil {
+		return commitErr
+	}
+	if unsubErr != nil {
+		return fmt.Errorf("failed to unsubscribe from %s: %w", s.cfg.Topic, unsubErr)
+	}
+	return nil
+}
+
+// run flushes partitions once their first record is FlushInterval old
+func (s *ParquetSink[T]) run() {
+	defer close(s.stopped)
+	ticker := time.NewTicker(max(s.cfg.FlushInterval/4, 10*time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.flushExpired()
+		}
+	}
+}
+
+// flushExpired flushes the partitions older than FlushInterval
+func (s *ParquetSink[T]) flushExpired() {
+	now := s.clock.Now()
+	s.mu.Lock()
+	var expired []*batch[T]
+	for _, b := range s.batches {
+		if now.Sub(b.started) >= s.cfg.FlushInterval {
+			s.detach(b)
+			expired = append(expired, b)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, b := range expired {
+		s.flush(b)
+	}
+}
+
+// detach removes b from the buffered partitions, so new records start a new
+// batch while b is written. Callers hold s.mu
+func (s *ParquetSink[T]) detach(b *batch[T]) {
+	if s.batches[b.partition] == b {
+		delete(s.batches, b.partition)
+		s.flushes.Add(1)
+	}
+}
+
+// requeue buffers the records of a failed batch again, ahead of any records
+// its partition received since, to be retried FlushInterval later. Callers
+// hold s.mu
+func (s *ParquetSink[T]) requeue(b *batch[T]) {
+	b.started = s.clock.Now()
+	if current, ok := s.batches[b.partition]; ok {
+		b.records = append(b.records, current.records...)
+		b.deliveries = append(b.deliveries, current.deliveries...)
+		b.bytes += current.bytes
+	}
+	s.batches[b.partition] = b
+}
+
+// flush writes b to a new file in its partition, then commits the messages
+// that are written. A failed batch is buffered again
+func (s *ParquetSink[T]) flush(b *batch[T]) error {
+	defer s.flushes.Done()
+
+	s.mu.Lock()
+	s.seq++
+	name := path.Join(b.partition, fmt.Sprintf("%s-%s-%06d.parquet",
+		s.cfg.Topic, s.clock.Now().UTC().Format("20060102T150405.000"), s.seq))
+	s.mu.Unlock()
+
+	err := parquet.WriteRows(context.Background(), s.manager, name, b.records)
+
+	s.mu.Lock()
+	if err != nil {
+		s.stats.FailedFlushes++
+		if !s.closed {
+			s.requeue(b)
+		}
+	} else {
+		s.stats.Files++
+		s.stats.Records += int64(len(b.records))
+		for _, d := range b.deliveries {
+			d.flushed = true
+		}
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		s.logger.Error("Failed to flush partition",
+			zap.String("partition", b.partition), zap.Int("records", len(b.records)), zap.Error(err))
+		return fmt.Errorf("failed to flush partition %s: %w", b.partition, err)
+	}
+	s.logger.Debug("Flushed partition", zap.String("file", name), zap.Int("records", len(b.records)))
+	return s.commit(context.Background())
+}
+
+// commit commits the longest prefix of delivered messages that are all
+// flushed. A failed commit is logged and left to the next one, which covers
+// the same offsets
+func (s *ParquetSink[T]) commit(ctx context.Context) error {
+	s.commitMu.Lock()
+	defer s.commitMu.Unlock()
+
+	s.mu.Lock()
+	n := 0
+	for n < len(s.deliveries) && s.deliveries[n].flushed {
+		n++
+	}
+	msgs := make([]types.Message, n)
+	for i, d := range s.deliveries[:n] {
+		msgs[i] = d.msg
+	}
+	s.deliveries = s.deliveries[n:]
+	s.mu.Unlock()
+	if n == 0 {
+		return nil
+	}
+
+	if err := s.broker.Commit(ctx, msgs...); err != nil {
+		s.logger.Error("Failed to commit flushed messages", zap.Int("messages", n), zap.Error(err))
+		return fmt.Errorf("failed to commit %d messages: %w", n, err)
+	}
+	s.mu.Lock()
+	s.stats.Committed += int64(n)
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,324 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/converter"
+	"go-transport-prac/pkg/sdl/parquet"
+	"go-transport-prac/pkg/transport/kafka"
+)
+
+// fakeBroker records the subscription and the commits, and delivers messages
+// one at a time like the Kafka and NATS brokers
+type fakeBroker struct {
+	mu           sync.Mutex
+	handler      types.MessageHandler
+	committed    []string
+	unsubscribed []string
+}
+
+func (b *fakeBroker) Publish(ctx context.Context, topic string, message []byte) error {
+	return nil
+}
+
+func (b *fakeBroker) Subscribe(ctx context.Context, topic string, handler types.MessageHandler) error {
+	return fmt.Errorf("the connector must subscribe with manual commits")
+}
+
+func (b *fakeBroker) SubscribeManual(ctx context.Context, topic string, handler types.MessageHandler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handler = handler
+	return nil
+}
+
+func (b *fakeBroker) Commit(ctx context.Context, msgs ...types.Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, msg := range msgs {
+		b.committed = append(b.committed, msg.ID)
+	}
+	return nil
+}
+
+func (b *fakeBroker) Unsubscribe(ctx context.Context, topic string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.unsubscribed = append(b.unsubscribed, topic)
+	return nil
+}
+
+func (b *fakeBroker) Close() error {
+	return nil
+}
+
+// deliver calls the handler for each message in turn and returns their errors
+func (b *fakeBroker) deliver(ctx context.Context, msgs []types.Message) []error {
+	errs := make([]error, len(msgs))
+	for i, msg := range msgs {
+		errs[i] = b.handler(ctx, msg)
+	}
+	return errs
+}
+
+// commits returns the IDs of the committed messages in commit order
+func (b *fakeBroker) commits() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.committed...)
+}
+
+// ids returns the IDs of msgs
+func ids(msgs []types.Message) []string {
+	out := make([]string, len(msgs))
+	for i, msg := range msgs {
+		out[i] = msg.ID
+	}
+	return out
+}
+
+func assertCommitted(t *testing.T, broker *fakeBroker, want []string) {
+	t.Helper()
+	got := broker.commits()
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("Expected commits %v, got %v", want, got)
+	}
+}
+
+// userMessages encodes users as Avro messages produced at the given times
+func userMessages(t *testing.T, codec kafka.Codec, users []avro.User, at ...time.Time) []types.Message {
+	msgs := make([]types.Message, len(users))
+	for i, u := range users {
+		data, err := codec.Encode("users", u)
+		if err != nil {
+			t.Fatalf("Failed to encode user: %v", err)
+		}
+		msgs[i] = types.Message{ID: fmt.Sprintf("users-0-%d", i), Topic: "users", Data: data, Timestamp: at[i%len(at)]}
+	}
+	return msgs
+}
+
+// partitionFiles lists the Parquet files under dir by partition
+func partitionFiles(t *testing.T, dir string) map[string][]string {
+	files := make(map[string][]string)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && filepath.Ext(path) == ".parquet" {
+			rel, _ := filepath.Rel(dir, path)
+			files[filepath.Dir(rel)] = append(files[filepath.Dir(rel)], filepath.Base(rel))
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to list files: %v", err)
+	}
+	return files
+}
+
+func newTestSink(t *testing.T, dir string, cfg Config) (*ParquetSink[parquet.User], *fakeBroker, kafka.Codec) {
+	manager, err := avro.NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create avro manager: %v", err)
+	}
+	codec := kafka.NewAvroCodec(manager, nil)
+	broker := &fakeBroker{}
+	decode := CodecDecoder[avro.User](codec, converter.UserToParquet)
+	s := NewParquetSink(broker, parquet.NewSimpleManager(dir), decode, cfg, nil)
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start connector: %v", err)
+	}
+	return s, broker, codec
+}
+
+func TestParquetSinkFlushesOnSize(t *testing.T) {
+	dir := "tmp/test_connector_size"
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+
+	s, broker, codec := newTestSink(t, dir, Config{Topic: "users", MaxRecords: 5, FlushInterval: time.Hour})
+	manager, _ := avro.NewManager("")
+	users := manager.CreateSampleUsers(10)
+	hour := time.Date(2024, 6, 1, 10, 30, 0, 0, time.UTC)
+	msgs := userMessages(t, codec, users, hour)
+
+	// One message at a time, as a Kafka partition delivers them
+	for i := range msgs {
+		if err := broker.deliver(context.Background(), msgs[i:i+1])[0]; err != nil {
+			t.Fatalf("Message %d was not acknowledged: %v", i, err)
+		}
+		if flushed := (i + 1) / 5 * 5; len(broker.commits()) != flushed {
+			t.Fatalf("After message %d expected %d commits, got %v", i, flushed, broker.commits())
+		}
+	}
+
+	files := partitionFiles(t, dir)
+	partition := filepath.FromSlash("dt=2024-06-01/hour=10")
+	if len(files) != 1 || len(files[partition]) != 2 {
+		t.Fatalf("Expected two files in %s, got %v", partition, files)
+	}
+	var rowIDs []int64
+	for _, name := range files[partition] {
+		rows, err := parquet.NewSimpleManager(filepath.Join(dir, partition)).ReadUsers(name)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if len(rows) != 5 {
+			t.Errorf("Expected 5 users in %s, got %d", name, len(rows))
+		}
+		for _, row := range rows {
+			rowIDs = append(rowIDs, row.ID)
+		}
+	}
+	sort.Slice(rowIDs, func(i, j int) bool { return rowIDs[i] < rowIDs[j] })
+	for i, u := range users {
+		if rowIDs[i] != u.ID {
+			t.Fatalf("Expected every user written once, got %v", rowIDs)
+		}
+	}
+	assertCommitted(t, broker, ids(msgs))
+
+	if stats := s.Stats(); stats.Files != 2 || stats.Records != 10 || stats.Committed != 10 || stats.Buffered != 0 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	if err := s.Close(context.Background()); err != nil {
+		t.Fatalf("Failed to close connector: %v", err)
+	}
+	if len(broker.unsubscribed) != 1 {
+		t.Error("Expected Close to unsubscribe")
+	}
+
+	t.Log("✓ Full partitions flush to one file each from sequential deliveries")
+}
+
+func TestParquetSinkFlushesOnTime(t *testing.T) {
+	dir := "tmp/test_connector_time"
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+
+	s, broker, codec := newTestSink(t, dir, Config{Topic: "users", MaxRecords: 100, FlushInterval: 50 * time.Millisecond})
+	defer s.Close(context.Background())
+	manager, _ := avro.NewManager("")
+	hours := []time.Time{
+		time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC),
+		time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC),
+	}
+	msgs := userMessages(t, codec, manager.CreateSampleUsers(3), hours...)
+
+	for i, err := range broker.deliver(context.Background(), msgs) {
+		if err != nil {
+			t.Fatalf("Message %d was not acknowledged: %v", i, err)
+		}
+	}
+	if len(broker.commits()) != 0 {
+		t.Fatalf("Expected no commits before the interval, got %v", broker.commits())
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(broker.commits()) < len(msgs) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assertCommitted(t, broker, ids(msgs))
+
+	files := partitionFiles(t, dir)
+	if len(files) != 2 || len(files[filepath.FromSlash("dt=2024-06-01/hour=10")]) != 1 || len(files[filepath.FromSlash("dt=2024-06-01/hour=11")]) != 1 {
+		t.Fatalf("Expected one file per hourly partition, got %v", files)
+	}
+
+	t.Log("✓ Partitions flush once their first record reaches the flush interval")
+}
+
+func TestParquetSinkCommitsAfterFlush(t *testing.T) {
+	dir := "tmp/test_connector_commit"
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+
+	s, broker, codec := newTestSink(t, dir, Config{Topic: "users", MaxRecords: 2, FlushInterval: time.Hour})
+	manager, _ := avro.NewManager("")
+	hours := []time.Time{
+		time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC),
+		time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC),
+	}
+	// Messages 0 and 2 fill hour 10, message 1 waits in hour 11
+	msgs := userMessages(t, codec, manager.CreateSampleUsers(4), hours...)
+
+	for i, err := range broker.deliver(context.Background(), msgs[:3]) {
+		if err != nil {
+			t.Fatalf("Message %d was not acknowledged: %v", i, err)
+		}
+	}
+	if stats := s.Stats(); stats.Files != 1 || stats.Buffered != 1 {
+		t.Fatalf("Expected hour 10 flushed and hour 11 buffered, got %+v", stats)
+	}
+	// Committing message 2 would commit message 1 with it, so only message 0 is
+	assertCommitted(t, broker, ids(msgs[:1]))
+
+	// Undecodable messages are skipped and committed in delivery order
+	bad := types.Message{ID: "users-0-99", Topic: "users", Data: []byte{0xff}, Timestamp: hours[0]}
+	if err := broker.deliver(context.Background(), []types.Message{bad})[0]; err != nil {
+		t.Errorf("Expected an undecodable message to be skipped, got %v", err)
+	}
+	assertCommitted(t, broker, ids(msgs[:1]))
+
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Failed to flush: %v", err)
+	}
+	assertCommitted(t, broker, append(ids(msgs[:3]), bad.ID))
+	if stats := s.Stats(); stats.Skipped != 1 || stats.Committed != 4 || stats.Records != 3 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+
+	if err := s.Close(context.Background()); err != nil {
+		t.Fatalf("Failed to close connector: %v", err)
+	}
+	// A closed connector holds messages until the subscription ends, uncommitted
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := broker.deliver(ctx, msgs[3:])[0]; err == nil {
+		t.Error("Expected a closed connector to refuse messages")
+	}
+	assertCommitted(t, broker, append(ids(msgs[:3]), bad.ID))
+
+	t.Log("✓ Messages are committed in delivery order once their files are written")
+}
+
+func TestParquetSinkFlushFailure(t *testing.T) {
+	dir := "tmp/test_connector_failure"
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create test dir: %v", err)
+	}
+	// A file where the partition directory belongs makes every flush fail
+	blocked := filepath.Join(dir, "blocked")
+	if err := os.WriteFile(blocked, nil, 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	s, broker, codec := newTestSink(t, blocked, Config{Topic: "users", MaxRecords: 2, FlushInterval: time.Hour})
+	defer s.Close(context.Background())
+	manager, _ := avro.NewManager("")
+
+	for i, err := range broker.deliver(context.Background(), userMessages(t, codec, manager.CreateSampleUsers(2), time.Now())) {
+		if err != nil {
+			t.Fatalf("Message %d was not acknowledged: %v", i, err)
+		}
+	}
+	if stats := s.Stats(); stats.FailedFlushes != 1 || stats.Files != 0 || stats.Buffered != 2 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	assertCommitted(t, broker, nil)
+	if err := s.Flush(); err == nil {
+		t.Error("Expected the retried flush to fail")
+	}
+	assertCommitted(t, broker, nil)
+
+	t.Log("✓ Failed flushes keep their records buffered and their messages uncommitted")
+}
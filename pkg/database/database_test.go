@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"go-transport-prac/internal/config"
+	"go-transport-prac/internal/testutil"
+)
+
+// newVersionedFake answers the migrator's version table statements; the
+// version is only kept when the transaction recording it runs to the insert.
+// Version table changes fail unless the migration lock is held
+func newVersionedFake() (*testutil.FakeSQL, *int64) {
+	fake := testutil.NewFakeSQL()
+	version := int64(-1)
+	locked := false
+	fake.Handle("SELECT pg_advisory_lock", func([]driver.Value) ([]string, [][]driver.Value, error) {
+		if locked {
+			return nil, nil, fmt.Errorf("migration lock already held")
+		}
+		locked = true
+		return nil, nil, nil
+	})
+	fake.Handle("SELECT pg_advisory_unlock", func([]driver.Value) ([]string, [][]driver.Value, error) {
+		locked = false
+		return nil, nil, nil
+	})
+	fake.Handle("CREATE TABLE IF NOT EXISTS schema_migrations", func([]driver.Value) ([]string, [][]driver.Value, error) {
+		return nil, nil, nil
+	})
+	fake.Handle("SELECT version FROM schema_migrations", func([]driver.Value) ([]string, [][]driver.Value, error) {
+		if version < 0 {
+			return []string{"version"}, nil, nil
+		}
+		return []string{"version"}, [][]driver.Value{{version}}, nil
+	})
+	fake.Handle("DELETE FROM schema_migrations", func([]driver.Value) ([]string, [][]driver.Value, error) {
+		return nil, nil, nil
+	})
+	fake.Handle("INSERT INTO schema_migrations", func(args []driver.Value) ([]string, [][]driver.Value, error) {
+		if !locked {
+			return nil, nil, fmt.Errorf("migration lock not held")
+		}
+		version = args[0].(int64)
+		return nil, nil, nil
+	})
+	fake.Handle("CREATE TABLE widgets", func([]driver.Value) ([]string, [][]driver.Value, error) {
+		return nil, nil, nil
+	})
+	fake.Handle("CREATE INDEX", func([]driver.Value) ([]string, [][]driver.Value, error) {
+		return nil, nil, fmt.Errorf("relation does not exist")
+	})
+	fake.Handle("DROP TABLE widgets", func([]driver.Value) ([]string, [][]driver.Value, error) {
+		return nil, nil, nil
+	})
+	return fake, &version
+}
+
+func TestDSN(t *testing.T) {
+	dsn := DSN(config.DatabaseConfig{Host: "db", Port: 5432, Username: "user", Password: "p@ss word", Name: "transport_db", SSLMode: "disable"})
+	if expected := "postgres://user:p%40ss%20word@db:5432/transport_db?sslmode=disable"; dsn != expected {
+		t.Errorf("Expected %s, got %s", expected, dsn)
+	}
+	t.Log("✓ DSN escapes credentials")
+}
+
+func TestPostgresMigrations(t *testing.T) {
+	fake, version := newVersionedFake()
+	ctx := context.Background()
+
+	widgets := Migration{Version: 1, Name: "create_widgets", Up: "CREATE TABLE widgets (id BIGINT)", Down: "DROP TABLE widgets"}
+	index := Migration{Version: 2, Name: "index_widgets", Up: "CREATE INDEX widgets_id ON widgets (id)"}
+	repo := NewPostgres(config.DatabaseConfig{Name: "test"}, index, widgets).WithDriver(fake.DriverName())
+
+	if err := repo.Migrate(ctx); err == nil {
+		t.Fatal("Expected migrating before Connect to fail")
+	}
+	if err := repo.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer repo.Disconnect(ctx)
+	if err := repo.Ping(ctx); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+
+	// The second migration fails: the first stays applied
+	err := repo.Migrate(ctx)
+	if err == nil || !strings.Contains(err.Error(), "migration 2 index_widgets") {
+		t.Fatalf("Expected migration 2 to fail, got %v", err)
+	}
+	if *version != 1 {
+		t.Errorf("Expected version 1 after the failure, got %d", *version)
+	}
+	statements := fake.Statements()
+	if !slices.Contains(statements, "ROLLBACK") || statements[len(statements)-2] != "ROLLBACK" {
+		t.Errorf("Expected the failed migration to be rolled back, got %v", statements)
+	}
+	if !strings.HasPrefix(statements[len(statements)-1], "SELECT pg_advisory_unlock") {
+		t.Errorf("Expected the migration lock to be released after the failure, got %v", statements)
+	}
+
+	migrator, err := repo.Migrator()
+	if err != nil {
+		t.Fatalf("Migrator failed: %v", err)
+	}
+	if current, err := migrator.Version(ctx); err != nil || current != 1 {
+		t.Errorf("Expected version 1, got %d (%v)", current, err)
+	}
+
+	// Migration 2 has no down statement, so it cannot be reverted
+	if err := migrator.SetVersion(ctx, 2); err != nil {
+		t.Fatalf("SetVersion failed: %v", err)
+	}
+	if err := migrator.Down(ctx); err == nil {
+		t.Error("Expected reverting migration 2 to fail")
+	}
+	if err := migrator.SetVersion(ctx, 1); err != nil {
+		t.Fatalf("SetVersion failed: %v", err)
+	}
+	if err := migrator.Down(ctx); err != nil {
+		t.Fatalf("Down failed: %v", err)
+	}
+	if *version != 0 {
+		t.Errorf("Expected version 0 after reverting, got %d", *version)
+	}
+
+	duplicate := NewSQLMigrator(repo.DB(), widgets, widgets)
+	if err := duplicate.Up(ctx); err == nil {
+		t.Error("Expected duplicate versions to fail")
+	}
+
+	t.Log("✓ Migrations apply in order and stop at the first failure")
+}
@@ -0,0 +1,214 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"slices"
+
+	"go-transport-prac/internal/types"
+)
+
+var _ types.Migrator = (*SQLMigrator)(nil)
+
+// Migration is one numbered schema change with the statements that apply
+// and revert it
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// versionTable holds the single row recording the applied migration version
+const versionTable = "schema_migrations"
+
+// migrationLockKey is the PostgreSQL advisory lock migrators hold while
+// they migrate, so instances starting together apply each migration once
+const migrationLockKey = 0x736368656d61 // "schema"
+
+// querier runs statements on the pool or on the one connection holding the
+// migration lock
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// SQLMigrator applies migrations in version order, each in its own
+// transaction together with the version it reaches. Up, Down and SetVersion
+// hold an advisory lock, so concurrent migrators wait for each other
+type SQLMigrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewSQLMigrator creates a migrator for db; migrations may be given in any order
+func NewSQLMigrator(db *sql.DB, migrations ...Migration) *SQLMigrator {
+	sorted := slices.Clone(migrations)
+	slices.SortFunc(sorted, func(a, b Migration) int { return a.Version - b.Version })
+	return &SQLMigrator{db: db, migrations: sorted}
+}
+
+// Migrations returns the migrations in version order
+func (m *SQLMigrator) Migrations() []Migration {
+	return slices.Clone(m.migrations)
+}
+
+func (m *SQLMigrator) validate() error {
+	for i, migration := range m.migrations {
+		if migration.Version <= 0 {
+			return fmt.Errorf("migration %q has non-positive version %d", migration.Name, migration.Version)
+		}
+		if i > 0 && m.migrations[i-1].Version == migration.Version {
+			return fmt.Errorf("duplicate migration version %d", migration.Version)
+		}
+		if migration.Up == "" {
+			return fmt.Errorf("migration %d has no up statement", migration.Version)
+		}
+	}
+	return nil
+}
+
+// locked runs fn on a connection holding the migration lock. A connection
+// whose lock cannot be released is discarded, which releases it
+func (m *SQLMigrator) locked(ctx context.Context, fn func(q querier) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("failed to take migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey); err != nil {
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}()
+	return fn(conn)
+}
+
+func ensureVersionTable(ctx context.Context, q querier) error {
+	if _, err := q.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+versionTable+" (version INTEGER NOT NULL)"); err != nil {
+		return fmt.Errorf("failed to create %s: %w", versionTable, err)
+	}
+	return nil
+}
+
+// Up applies every migration newer than the current version. A failing
+// migration is rolled back and stops the run at the version before it
+func (m *SQLMigrator) Up(ctx context.Context) error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+	return m.locked(ctx, func(q querier) error {
+		current, err := version(ctx, q)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version <= current {
+				continue
+			}
+			if err := apply(ctx, q, migration.Up, migration.Version); err != nil {
+				return fmt.Errorf("failed to apply migration %d %s: %w", migration.Version, migration.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Down reverts the migration at the current version
+func (m *SQLMigrator) Down(ctx context.Context) error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+	return m.locked(ctx, func(q querier) error {
+		current, err := version(ctx, q)
+		if err != nil {
+			return err
+		}
+		if current == 0 {
+			return nil
+		}
+
+		i := slices.IndexFunc(m.migrations, func(migration Migration) bool { return migration.Version == current })
+		if i < 0 {
+			return fmt.Errorf("no migration for current version %d", current)
+		}
+		migration := m.migrations[i]
+		if migration.Down == "" {
+			return fmt.Errorf("migration %d %s cannot be reverted", migration.Version, migration.Name)
+		}
+
+		previous := 0
+		if i > 0 {
+			previous = m.migrations[i-1].Version
+		}
+		if err := apply(ctx, q, migration.Down, previous); err != nil {
+			return fmt.Errorf("failed to revert migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+		return nil
+	})
+}
+
+// Version returns the applied migration version, 0 before any migration
+func (m *SQLMigrator) Version(ctx context.Context) (int, error) {
+	return version(ctx, m.db)
+}
+
+func version(ctx context.Context, q querier) (int, error) {
+	if err := ensureVersionTable(ctx, q); err != nil {
+		return 0, err
+	}
+	var version int
+	err := q.QueryRowContext(ctx, "SELECT version FROM "+versionTable).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migration version: %w", err)
+	}
+	return version, nil
+}
+
+// SetVersion records version as applied without running any migration,
+// for databases whose schema was created by other means
+func (m *SQLMigrator) SetVersion(ctx context.Context, version int) error {
+	return m.locked(ctx, func(q querier) error {
+		if err := ensureVersionTable(ctx, q); err != nil {
+			return err
+		}
+		return apply(ctx, q, "", version)
+	})
+}
+
+// apply runs statement, if any, and records version in one transaction
+func apply(ctx context.Context, q querier, statement string, version int) error {
+	tx, err := q.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if statement != "" {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM "+versionTable); err != nil {
+		return fmt.Errorf("failed to clear migration version: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO "+versionTable+" (version) VALUES ($1)", version); err != nil {
+		return fmt.Errorf("failed to record migration version: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// Package database connects the pipelines to a PostgreSQL database and keeps
+// its schema up to date with numbered migrations.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+
+	// Registers the pgx driver with database/sql
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"go-transport-prac/internal/config"
+	"go-transport-prac/internal/types"
+)
+
+var _ types.Repository = (*Postgres)(nil)
+
+// PostgresDriver is the database/sql driver Postgres connects with
+const PostgresDriver = "pgx"
+
+// Postgres is a repository backed by a PostgreSQL database
+type Postgres struct {
+	cfg        config.DatabaseConfig
+	driver     string
+	migrations []Migration
+	db         *sql.DB
+}
+
+// NewPostgres creates a repository for the configured database; Migrate
+// applies migrations. Nothing is opened until Connect
+func NewPostgres(cfg config.DatabaseConfig, migrations ...Migration) *Postgres {
+	return &Postgres{cfg: cfg, driver: PostgresDriver, migrations: migrations}
+}
+
+// WithDriver sets the database/sql driver to connect with
+func (p *Postgres) WithDriver(driver string) *Postgres {
+	p.driver = driver
+	return p
+}
+
+// DSN returns the connection URL of the configured database
+func DSN(cfg config.DatabaseConfig) string {
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(cfg.Username, cfg.Password),
+		Host:   net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		Path:   "/" + cfg.Name,
+	}
+	if cfg.SSLMode != "" {
+		u.RawQuery = url.Values{"sslmode": {cfg.SSLMode}}.Encode()
+	}
+	return u.String()
+}
+
+// Connect opens the connection pool and checks the server is reachable
+func (p *Postgres) Connect(ctx context.Context) error {
+	if p.db != nil {
+		return nil
+	}
+	db, err := sql.Open(p.driver, DSN(p.cfg))
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	db.SetMaxOpenConns(p.cfg.MaxOpenConns)
+	db.SetMaxIdleConns(p.cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(p.cfg.MaxLifetime)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return fmt.Errorf("failed to connect to database %s: %w", p.cfg.Name, err)
+	}
+	p.db = db
+	return nil
+}
+
+// Disconnect closes the connection pool
+func (p *Postgres) Disconnect(ctx context.Context) error {
+	if p.db == nil {
+		return nil
+	}
+	err := p.db.Close()
+	p.db = nil
+	if err != nil {
+		return fmt.Errorf("failed to close database: %w", err)
+	}
+	return nil
+}
+
+// Ping checks that the server is reachable
+func (p *Postgres) Ping(ctx context.Context) error {
+	if p.db == nil {
+		return fmt.Errorf("database %s is not connected", p.cfg.Name)
+	}
+	if err := p.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	return nil
+}
+
+// DB returns the connection pool, nil before Connect
+func (p *Postgres) DB() *sql.DB {
+	return p.db
+}
+
+// Migrator returns a migrator over the repository's migrations
+func (p *Postgres) Migrator() (*SQLMigrator, error) {
+	if p.db == nil {
+		return nil, fmt.Errorf("database %s is not connected", p.cfg.Name)
+	}
+	return NewSQLMigrator(p.db, p.migrations...), nil
+}
+
+// Migrate applies the pending migrations
+func (p *Postgres) Migrate(ctx context.Context) error {
+	migrator, err := p.Migrator()
+	if err != nil {
+		return err
+	}
+	return migrator.Up(ctx)
+}
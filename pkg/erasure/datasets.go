@@ -0,0 +1,144 @@
+package erasure
+
+import (
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/parquet"
+)
+
+// erasedName replaces names and other free-text identifiers
+const erasedName = "[erased]"
+
+// AddAvroUsers registers an Avro user file
+func (e *Eraser) AddAvroUsers(filename string) *Eraser {
+	read := func() ([]avro.User, error) { return e.avro.ReadUsersFromFile(filename) }
+	write := func(users []avro.User) error { return e.avro.WriteUsersToFile(filename, users) }
+	user := func(u avro.User) (int64, string) { return u.ID, u.Email }
+
+	return e.add(dataset{
+		name: "users", format: "avro", filename: filename,
+		emails: emailsIn(read, user),
+		erase: func(m *match, mode Mode) (DatasetResult, error) {
+			return eraseRows(read, write, func(u avro.User) bool { return m.user(user(u)) }, anonymizeAvroUser, mode)
+		},
+	})
+}
+
+// AddAvroUserRecords registers an Avro user envelope file
+func (e *Eraser) AddAvroUserRecords(filename string) *Eraser {
+	read := func() ([]avro.UserRecord, error) { return e.avro.ReadUserRecordsFromFile(filename) }
+	write := func(records []avro.UserRecord) error { return e.avro.WriteUserRecordsToFile(filename, records) }
+	user := func(r avro.UserRecord) (int64, string) { return r.User.ID, r.User.Email }
+
+	return e.add(dataset{
+		name: "user_records", format: "avro", filename: filename,
+		emails: emailsIn(read, user),
+		erase: func(m *match, mode Mode) (DatasetResult, error) {
+			return eraseRows(read, write, func(r avro.UserRecord) bool { return m.user(user(r)) },
+				func(r *avro.UserRecord) { anonymizeAvroUser(&r.User) }, mode)
+		},
+	})
+}
+
+// AddAvroOrders registers an Avro order file
+func (e *Eraser) AddAvroOrders(filename string) *Eraser {
+	read := func() ([]avro.Order, error) { return e.avro.ReadOrdersFromFile(filename) }
+	write := func(orders []avro.Order) error { return e.avro.WriteOrdersToFile(filename, orders) }
+
+	return e.add(dataset{
+		name: "orders", format: "avro", filename: filename,
+		erase: func(m *match, mode Mode) (DatasetResult, error) {
+			return eraseRows(read, write, func(o avro.Order) bool { return m.ids[o.UserID] }, anonymizeAvroOrder, mode)
+		},
+	})
+}
+
+// AddParquetUsers registers a Parquet user file
+func (e *Eraser) AddParquetUsers(filename string) *Eraser {
+	read := func() ([]parquet.User, error) { return e.parquet.ReadUsers(filename) }
+	write := func(users []parquet.User) error { return e.parquet.WriteUsers(filename, users) }
+	user := func(u parquet.User) (int64, string) { return u.ID, u.Email }
+
+	return e.add(dataset{
+		name: "users", format: "parquet", filename: filename,
+		emails: emailsIn(read, user),
+		erase: func(m *match, mode Mode) (DatasetResult, error) {
+			return eraseRows(read, write, func(u parquet.User) bool { return m.user(user(u)) }, anonymizeParquetUser, mode)
+		},
+	})
+}
+
+// AddParquetUserRecords registers a Parquet user envelope file
+func (e *Eraser) AddParquetUserRecords(filename string) *Eraser {
+	read := func() ([]parquet.UserRecord, error) { return e.parquet.ReadUserRecords(filename) }
+	write := func(records []parquet.UserRecord) error { return e.parquet.WriteUserRecords(filename, records) }
+	user := func(r parquet.UserRecord) (int64, string) { return r.User.ID, r.User.Email }
+
+	return e.add(dataset{
+		name: "user_records", format: "parquet", filename: filename,
+		emails: emailsIn(read, user),
+		erase: func(m *match, mode Mode) (DatasetResult, error) {
+			return eraseRows(read, write, func(r parquet.UserRecord) bool { return m.user(user(r)) },
+				func(r *parquet.UserRecord) { anonymizeParquetUser(&r.User) }, mode)
+		},
+	})
+}
+
+// AddEventLog registers a Parquet analytics event file
+func (e *Eraser) AddEventLog(filename string) *Eraser {
+	read := func() ([]parquet.Analytics, error) { return e.parquet.ReadAnalytics(filename) }
+	write := func(events []parquet.Analytics) error { return e.parquet.WriteAnalytics(filename, events) }
+
+	return e.add(dataset{
+		name: "events", format: "parquet", filename: filename,
+		erase: func(m *match, mode Mode) (DatasetResult, error) {
+			return eraseRows(read, write, func(a parquet.Analytics) bool { return m.ids[a.UserID] }, anonymizeEvent, mode)
+		},
+	})
+}
+
+func (e *Eraser) add(ds dataset) *Eraser {
+	e.datasets = append(e.datasets, ds)
+	return e
+}
+
+// anonymizeAvroUser keeps the ID, status and timestamps so counts and joins
+// still work, and drops everything that identifies the person
+func anonymizeAvroUser(u *avro.User) {
+	u.Email = ""
+	u.Name = erasedName
+	if u.Profile != nil {
+		u.Profile = &avro.Profile{FirstName: erasedName, LastName: erasedName, Interests: u.Profile.Interests, Metadata: map[string]string{}}
+	}
+}
+
+func anonymizeParquetUser(u *parquet.User) {
+	u.Email = ""
+	u.Name = erasedName
+	if u.Profile != nil {
+		u.Profile = &parquet.Profile{FirstName: erasedName, LastName: erasedName, Interests: u.Profile.Interests, Metadata: map[string]string{}}
+	}
+}
+
+// anonymizeAvroOrder keeps amounts and items but removes delivery and payment details
+func anonymizeAvroOrder(o *avro.Order) {
+	if o.ShippingInfo != nil {
+		country := o.ShippingInfo.Address.Country
+		o.ShippingInfo.Address = avro.ShippingAddress{RecipientName: erasedName, Country: country}
+		o.ShippingInfo.TrackingNumber = nil
+	}
+	if o.PaymentInfo != nil {
+		o.PaymentInfo.TransactionID = nil
+	}
+}
+
+// anonymizeEvent detaches the event from the user, their session and precise location
+func anonymizeEvent(a *parquet.Analytics) {
+	a.UserID = 0
+	a.SessionID = ""
+	if a.DeviceInfo != nil {
+		a.DeviceInfo.UserAgent = ""
+	}
+	if a.Location != nil {
+		a.Location = &parquet.Location{Country: a.Location.Country}
+	}
+}
@@ -0,0 +1,262 @@
+package erasure
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/parquet"
+)
+
+// Mode selects what happens to a subject's records
+type Mode string
+
+const (
+	// ModeDelete drops every record belonging to the subject
+	ModeDelete Mode = "delete"
+	// ModeAnonymize keeps records for aggregates but strips the subject's
+	// identifying fields
+	ModeAnonymize Mode = "anonymize"
+)
+
+// Subject identifies the person whose data is erased; either field may be empty
+type Subject struct {
+	UserID int64  `json:"userId,omitempty"`
+	Email  string `json:"email,omitempty"`
+}
+
+// Options configures one erasure
+type Options struct {
+	Mode Mode
+	// CryptoShred also destroys the subject's encryption key, so any copies
+	// left in backups or unregistered files can no longer be decrypted
+	CryptoShred bool
+}
+
+// KeyShredder destroys per-subject envelope encryption keys
+type KeyShredder interface {
+	ShredKey(ctx context.Context, keyID string) error
+}
+
+// SubjectKeyID returns the key ID a KeyShredder uses for a user's data
+func SubjectKeyID(userID int64) string {
+	return "user:" + strconv.FormatInt(userID, 10)
+}
+
+// DatasetResult reports the erasure of one file
+type DatasetResult struct {
+	Dataset    string `json:"dataset"`
+	Format     string `json:"format"`
+	Filename   string `json:"filename"`
+	Scanned    int    `json:"scanned"`
+	Deleted    int    `json:"deleted"`
+	Anonymized int    `json:"anonymized"`
+	// Rewritten is false when no record matched and the file was left alone
+	Rewritten bool   `json:"rewritten"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report records what an erasure found and changed, for the audit trail
+type Report struct {
+	Subject      Subject         `json:"subject"`
+	Mode         Mode            `json:"mode"`
+	UserIDs      []int64         `json:"userIds"`
+	Datasets     []DatasetResult `json:"datasets"`
+	Deleted      int             `json:"deleted"`
+	Anonymized   int             `json:"anonymized"`
+	ShreddedKeys []string        `json:"shreddedKeys,omitempty"`
+	StartedAt    time.Time       `json:"startedAt"`
+	CompletedAt  time.Time       `json:"completedAt"`
+}
+
+// dataset is a registered file with functions to find and erase a subject in it
+type dataset struct {
+	name     string
+	format   string
+	filename string
+	// emails returns the user IDs registered under an email, for subject resolution
+	emails func(email string) ([]int64, error)
+	erase  func(s *match, mode Mode) (DatasetResult, error)
+}
+
+// Eraser removes or anonymizes a subject's records across registered datasets
+type Eraser struct {
+	avro     *avro.Manager
+	parquet  *parquet.SimpleManager
+	shredder KeyShredder
+	clock    types.Clock
+	datasets []dataset
+}
+
+// NewEraser creates an eraser over files managed by the given managers; either may be nil
+// if no dataset of that format is registered
+func NewEraser(avroManager *avro.Manager, parquetManager *parquet.SimpleManager) *Eraser {
+	return &Eraser{avro: avroManager, parquet: parquetManager, clock: types.SystemClock{}}
+}
+
+// WithClock sets the clock used for report timestamps
+func (e *Eraser) WithClock(clock types.Clock) *Eraser {
+	e.clock = types.ClockOrSystem(clock)
+	return e
+}
+
+// WithKeyShredder sets the key store used when Options.CryptoShred is set
+func (e *Eraser) WithKeyShredder(shredder KeyShredder) *Eraser {
+	e.shredder = shredder
+	return e
+}
+
+// Erase resolves subject to its user IDs, then deletes or anonymizes matching
+// records in every registered dataset. A failing dataset does not stop the
+// others; the report lists each outcome and the error summarizes the failures
+func (e *Eraser) Erase(ctx context.Context, subject Subject, opts Options) (*Report, error) {
+	if subject.UserID == 0 && subject.Email == "" {
+		return nil, errors.BadRequestError(errors.CodeMissingField, "subject needs a user ID or email")
+	}
+	if opts.Mode != ModeDelete && opts.Mode != ModeAnonymize {
+		return nil, errors.BadRequestError(errors.CodeInvalidValue, fmt.Sprintf("unknown erasure mode %q", opts.Mode))
+	}
+	if opts.CryptoShred && e.shredder == nil {
+		return nil, errors.BadRequestError(errors.CodeInvalidInput, "crypto-shredding requested without a key shredder")
+	}
+
+	report := &Report{Subject: subject, Mode: opts.Mode, StartedAt: e.clock.Now()}
+
+	m, err := e.resolve(subject)
+	if err != nil {
+		return nil, err
+	}
+	report.UserIDs = m.userIDs()
+
+	var failed []string
+	for _, ds := range e.datasets {
+		result, err := ds.erase(m, opts.Mode)
+		result.Dataset, result.Format, result.Filename = ds.name, ds.format, ds.filename
+		if err != nil {
+			result.Error = err.Error()
+			failed = append(failed, ds.filename)
+		}
+		report.Datasets = append(report.Datasets, result)
+		report.Deleted += result.Deleted
+		report.Anonymized += result.Anonymized
+	}
+
+	if opts.CryptoShred {
+		for _, id := range report.UserIDs {
+			keyID := SubjectKeyID(id)
+			if err := e.shredder.ShredKey(ctx, keyID); err != nil {
+				failed = append(failed, keyID)
+				continue
+			}
+			report.ShreddedKeys = append(report.ShreddedKeys, keyID)
+		}
+	}
+
+	report.CompletedAt = e.clock.Now()
+	if len(failed) > 0 {
+		return report, errors.InternalError(errors.CodeInternalError,
+			fmt.Sprintf("erasure incomplete for %s", strings.Join(failed, ", ")))
+	}
+	return report, nil
+}
+
+// resolve collects the user IDs belonging to subject, looking emails up in
+// every dataset that stores them
+func (e *Eraser) resolve(subject Subject) (*match, error) {
+	m := &match{ids: make(map[int64]bool), email: strings.ToLower(subject.Email)}
+	if subject.UserID != 0 {
+		m.ids[subject.UserID] = true
+	}
+	if subject.Email == "" {
+		return m, nil
+	}
+
+	for _, ds := range e.datasets {
+		if ds.emails == nil {
+			continue
+		}
+		ids, err := ds.emails(m.email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve subject in %s: %w", ds.filename, err)
+		}
+		for _, id := range ids {
+			m.ids[id] = true
+		}
+	}
+	return m, nil
+}
+
+// match identifies the subject's records
+type match struct {
+	ids   map[int64]bool
+	email string
+}
+
+func (m *match) user(id int64, email string) bool {
+	return m.ids[id] || (m.email != "" && strings.ToLower(email) == m.email)
+}
+
+func (m *match) userIDs() []int64 {
+	ids := make([]int64, 0, len(m.ids))
+	for id := range m.ids {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// eraseRows applies mode to the rows matching the subject and writes the file
+// back only if something changed
+func eraseRows[T any](read func() ([]T, error), write func([]T) error, matches func(T) bool, anonymize func(*T), mode Mode) (DatasetResult, error) {
+	rows, err := read()
+	if err != nil {
+		return DatasetResult{}, err
+	}
+
+	result := DatasetResult{Scanned: len(rows)}
+	kept := rows[:0:0]
+	for _, row := range rows {
+		switch {
+		case !matches(row):
+			kept = append(kept, row)
+		case mode == ModeDelete:
+			result.Deleted++
+		default:
+			anonymize(&row)
+			kept = append(kept, row)
+			result.Anonymized++
+		}
+	}
+
+	if result.Deleted+result.Anonymized == 0 {
+		return result, nil
+	}
+	if err := write(kept); err != nil {
+		return result, err
+	}
+	result.Rewritten = true
+	return result, nil
+}
+
+// emailsIn returns the IDs of users whose email matches
+func emailsIn[T any](read func() ([]T, error), user func(T) (int64, string)) func(string) ([]int64, error) {
+	return func(email string) ([]int64, error) {
+		rows, err := read()
+		if err != nil {
+			return nil, err
+		}
+		var ids []int64
+		for _, row := range rows {
+			if id, e := user(row); strings.ToLower(e) == email {
+				ids = append(ids, id)
+			}
+		}
+		return ids, nil
+	}
+}
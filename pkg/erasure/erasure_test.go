@@ -0,0 +1,204 @@
+package erasure
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/parquet"
+	"go-transport-prac/pkg/storage"
+)
+
+// fixture writes a small set of datasets where user 3 also has a second
+// account, 42, registered under the same email in different case
+func fixture(t *testing.T, dir string) *Eraser {
+	t.Helper()
+	clock := testutil.NewDefaultFakeClock()
+
+	avroManager, err := avro.NewManager(dir)
+	if err != nil {
+		t.Fatalf("Failed to create avro manager: %v", err)
+	}
+	avroManager.WithClock(clock)
+	parquetManager := parquet.NewSimpleManager("").WithStorage(storage.NewMemoryStorage())
+
+	users := avroManager.CreateSampleUsers(5)
+	if err := avroManager.WriteUsersToFile("users.avro", users); err != nil {
+		t.Fatalf("Failed to write avro users: %v", err)
+	}
+
+	var orders []avro.Order
+	for i, userID := range []int64{3, 5, 3} {
+		orders = append(orders, avro.Order{
+			ID: int64(i + 1), UserID: userID, OrderNumber: fmt.Sprintf("ORD-%d", i+1), Status: avro.OrderStatusShipped,
+			ShippingInfo: &avro.ShippingInfo{
+				Address: avro.ShippingAddress{RecipientName: "Recipient", Street: "1 Main St", City: "Springfield", Country: "US"},
+				Method:  "standard",
+			},
+			CreatedAt: clock.Now(), UpdatedAt: clock.Now(),
+		})
+	}
+	if err := avroManager.WriteOrdersToFile("orders.avro", orders); err != nil {
+		t.Fatalf("Failed to write orders: %v", err)
+	}
+
+	parquetUsers := []parquet.User{
+		{ID: 3, Email: "user3@example.com", Name: "User 3", Profile: &parquet.Profile{FirstName: "First3", LastName: "Last3", Phone: "+1-555-1002"}},
+		{ID: 4, Email: "user4@example.com", Name: "User 4"},
+		{ID: 42, Email: "USER3@example.com", Name: "Second Account"},
+	}
+	if err := parquetManager.WriteUsers("users.parquet", parquetUsers); err != nil {
+		t.Fatalf("Failed to write parquet users: %v", err)
+	}
+
+	var events []parquet.Analytics
+	for i, userID := range []int64{3, 4, 42, 3, 5} {
+		events = append(events, parquet.Analytics{
+			ID: int64(i + 1), EventType: "page_view", UserID: userID, SessionID: fmt.Sprintf("session_%d", userID),
+			Timestamp: clock.Now(), Location: &parquet.Location{Country: "US", City: "Springfield", Latitude: 39.8},
+		})
+	}
+	if err := parquetManager.WriteAnalytics("events.parquet", events); err != nil {
+		t.Fatalf("Failed to write events: %v", err)
+	}
+
+	return NewEraser(avroManager, parquetManager).WithClock(clock).
+		AddAvroUsers("users.avro").
+		AddAvroOrders("orders.avro").
+		AddParquetUsers("users.parquet").
+		AddEventLog("events.parquet")
+}
+
+func TestEraseDeletesAcrossDatasets(t *testing.T) {
+	dir := "tmp/test_erase_delete"
+	defer os.RemoveAll(dir)
+	eraser := fixture(t, dir)
+
+	report, err := eraser.Erase(t.Context(), Subject{Email: "User3@Example.com"}, Options{Mode: ModeDelete})
+	if err != nil {
+		t.Fatalf("Erase failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(report.UserIDs, []int64{3, 42}) {
+		t.Errorf("Expected the email to resolve to users 3 and 42, got %v", report.UserIDs)
+	}
+
+	deleted := map[string]int{}
+	for _, ds := range report.Datasets {
+		deleted[ds.Format+"/"+ds.Dataset] = ds.Deleted
+	}
+	expected := map[string]int{"avro/users": 1, "avro/orders": 2, "parquet/users": 2, "parquet/events": 3}
+	if !reflect.DeepEqual(deleted, expected) {
+		t.Errorf("Expected deletions %v, got %v", expected, deleted)
+	}
+	if report.Deleted != 8 || report.Anonymized != 0 {
+		t.Errorf("Unexpected totals: deleted %d anonymized %d", report.Deleted, report.Anonymized)
+	}
+
+	users, _ := eraser.avro.ReadUsersFromFile("users.avro")
+	orders, _ := eraser.avro.ReadOrdersFromFile("orders.avro")
+	events, _ := eraser.parquet.ReadAnalytics("events.parquet")
+	if len(users) != 4 || len(orders) != 1 || orders[0].UserID != 5 || len(events) != 2 {
+		t.Errorf("Subject data survived: %d users, %d orders, %d events", len(users), len(orders), len(events))
+	}
+
+	t.Logf("✓ Erased %d records for %v across %d datasets", report.Deleted, report.UserIDs, len(report.Datasets))
+}
+
+func TestEraseAnonymizes(t *testing.T) {
+	dir := "tmp/test_erase_anonymize"
+	defer os.RemoveAll(dir)
+	eraser := fixture(t, dir)
+
+	report, err := eraser.Erase(t.Context(), Subject{UserID: 3}, Options{Mode: ModeAnonymize})
+	if err != nil {
+		t.Fatalf("Erase failed: %v", err)
+	}
+	// Without an email only user 3 is resolved; account 42 is untouched
+	if report.Anonymized != 6 || report.Deleted != 0 {
+		t.Errorf("Expected 6 anonymized records, got %+v", report)
+	}
+
+	users, _ := eraser.avro.ReadUsersFromFile("users.avro")
+	if u := users[2]; u.ID != 3 || u.Email != "" || u.Name != erasedName || u.Profile.Phone != nil || u.Profile.Address != nil {
+		t.Errorf("User 3 still identifiable: %+v %+v", u, u.Profile)
+	}
+	if users[3].Email != "user4@example.com" {
+		t.Errorf("Other users must be untouched: %+v", users[3])
+	}
+
+	orders, _ := eraser.avro.ReadOrdersFromFile("orders.avro")
+	if a := orders[0].ShippingInfo.Address; a.Street != "" || a.RecipientName != erasedName || a.Country != "US" {
+		t.Errorf("Order address not anonymized: %+v", a)
+	}
+	if orders[1].ShippingInfo.Address.Street == "" {
+		t.Error("Other users' orders must be untouched")
+	}
+
+	events, _ := eraser.parquet.ReadAnalytics("events.parquet")
+	if e := events[0]; e.UserID != 0 || e.SessionID != "" || e.Location.City != "" || e.Location.Country != "US" {
+		t.Errorf("Event not anonymized: %+v", e)
+	}
+	if len(events) != 5 || events[2].UserID != 42 {
+		t.Errorf("Expected events to be kept and user 42 untouched, got %d events", len(events))
+	}
+
+	t.Logf("✓ Anonymized %d records while keeping them for aggregates", report.Anonymized)
+}
+
+// fakeKeyStore records shredded keys and fails for keys listed in failing
+type fakeKeyStore struct {
+	shredded []string
+	failing  map[string]bool
+}
+
+func (k *fakeKeyStore) ShredKey(ctx context.Context, keyID string) error {
+	if k.failing[keyID] {
+		return fmt.Errorf("key service unavailable")
+	}
+	k.shredded = append(k.shredded, keyID)
+	return nil
+}
+
+func TestEraseCryptoShredAndFailures(t *testing.T) {
+	dir := "tmp/test_erase_shred"
+	defer os.RemoveAll(dir)
+	eraser := fixture(t, dir)
+
+	if _, err := eraser.Erase(t.Context(), Subject{UserID: 3}, Options{Mode: ModeDelete, CryptoShred: true}); !errors.IsType(err, errors.ErrorTypeBadRequest) {
+		t.Errorf("Expected crypto-shredding without a key store to be rejected, got %v", err)
+	}
+	if _, err := eraser.Erase(t.Context(), Subject{}, Options{Mode: ModeDelete}); !errors.IsType(err, errors.ErrorTypeBadRequest) {
+		t.Errorf("Expected an empty subject to be rejected, got %v", err)
+	}
+
+	keys := &fakeKeyStore{failing: map[string]bool{"user:42": true}}
+	eraser.WithKeyShredder(keys).AddEventLog("missing.parquet")
+
+	report, err := eraser.Erase(t.Context(), Subject{Email: "user3@example.com"}, Options{Mode: ModeDelete, CryptoShred: true})
+	if err == nil {
+		t.Fatal("Expected the missing dataset and failing key to be reported")
+	}
+	if !reflect.DeepEqual(report.ShreddedKeys, []string{"user:3"}) {
+		t.Errorf("Expected user:3 to be shredded, got %v", report.ShreddedKeys)
+	}
+
+	last := report.Datasets[len(report.Datasets)-1]
+	if last.Filename != "missing.parquet" || last.Error == "" {
+		t.Errorf("Expected the missing file to carry an error, got %+v", last)
+	}
+	// Datasets before the failure were still erased
+	if report.Deleted != 8 {
+		t.Errorf("Expected 8 deletions despite the failure, got %d", report.Deleted)
+	}
+	if report.CompletedAt.Before(report.StartedAt) {
+		t.Errorf("Report timestamps out of order: %v %v", report.StartedAt, report.CompletedAt)
+	}
+
+	t.Logf("✓ Partial erasure reported with %d shredded keys", len(report.ShreddedKeys))
+}
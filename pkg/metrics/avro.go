@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+// AvroManager wraps an Avro manager so its user, product and analytics
+// serialize and deserialize calls are recorded. Other methods pass through
+type AvroManager struct {
+	*avro.Manager
+	in *Instrumenter
+}
+
+// InstrumentAvro wraps manager, recording calls with in
+func InstrumentAvro(manager *avro.Manager, in *Instrumenter) *AvroManager {
+	return &AvroManager{Manager: manager, in: in}
+}
+
+// SerializeUserBinary serializes a user to binary using Avro
+func (m *AvroManager) SerializeUserBinary(user avro.User) ([]byte, error) {
+	return Serialize(m.in, "avro", "user", user, m.Manager.SerializeUserBinary)
+}
+
+// DeserializeUserBinary deserializes a user from binary using Avro
+func (m *AvroManager) DeserializeUserBinary(data []byte) (avro.User, error) {
+	return Deserialize(m.in, "avro", "user", data, m.Manager.DeserializeUserBinary)
+}
+
+// SerializeUserJSON serializes a user to JSON using Avro schema
+func (m *AvroManager) SerializeUserJSON(user avro.User) ([]byte, error) {
+	return Serialize(m.in, "avro_json", "user", user, m.Manager.SerializeUserJSON)
+}
+
+// DeserializeUserJSON deserializes a user from JSON using Avro schema
+func (m *AvroManager) DeserializeUserJSON(data []byte) (avro.User, error) {
+	return Deserialize(m.in, "avro_json", "user", data, m.Manager.DeserializeUserJSON)
+}
+
+// SerializeProductBinary serializes a product to binary using Avro
+func (m *AvroManager) SerializeProductBinary(product avro.Product) ([]byte, error) {
+	return Serialize(m.in, "avro", "product", product, m.Manager.SerializeProductBinary)
+}
+
+// DeserializeProductBinary deserializes a product from binary using Avro
+func (m *AvroManager) DeserializeProductBinary(data []byte) (avro.Product, error) {
+	return Deserialize(m.in, "avro", "product", data, m.Manager.DeserializeProductBinary)
+}
+
+// SerializeProductJSON serializes a product to JSON using Avro schema
+func (m *AvroManager) SerializeProductJSON(product avro.Product) ([]byte, error) {
+	return Serialize(m.in, "avro_json", "product", product, m.Manager.SerializeProductJSON)
+}
+
+// DeserializeProductJSON deserializes a product from JSON using Avro schema
+func (m *AvroManager) DeserializeProductJSON(data []byte) (avro.Product, error) {
+	return Deserialize(m.in, "avro_json", "product", data, m.Manager.DeserializeProductJSON)
+}
+
+// SerializeAnalytics serializes an analytics event to binary using Avro
+func (m *AvroManager) SerializeAnalytics(event avro.Analytics) ([]byte, error) {
+	return Serialize(m.in, "avro", "analytics", event, m.Manager.SerializeAnalytics)
+}
+
+// DeserializeAnalytics deserializes an analytics event from binary using Avro
+func (m *AvroManager) DeserializeAnalytics(data []byte) (avro.Analytics, error) {
+	return Deserialize(m.in, "avro", "analytics", data, m.Manager.DeserializeAnalytics)
+}
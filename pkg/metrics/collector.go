@@ -0,0 +1,217 @@
+package metrics
+
+import (
+	nethttp "net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/internal/types"
+)
+
+// Config holds the Prometheus collector settings
+type Config struct {
+	// Namespace prefixes every metric name
+	Namespace string
+	// LatencyBuckets are the histogram buckets, in seconds, for timers and
+	// histograms whose name does not end in _bytes
+	LatencyBuckets []float64
+	// SizeBuckets are the histogram buckets for histograms named *_bytes
+	SizeBuckets []float64
+	// RuntimeMetrics also exports Go runtime and process metrics
+	RuntimeMetrics bool
+}
+
+// DefaultConfig returns buckets from 100µs to 10s and from 64B to 16MiB
+func DefaultConfig() Config {
+	return Config{
+		Namespace:      "transport",
+		LatencyBuckets: []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		SizeBuckets:    prometheus.ExponentialBuckets(64, 4, 10),
+		RuntimeMetrics: true,
+	}
+}
+
+// kind is the Prometheus type a metric name was first used as
+type kind int
+
+const (
+	kindCounter kind = iota
+	kindGauge
+	kindHistogram
+)
+
+// String returns the Prometheus metric type
+func (k kind) String() string {
+	switch k {
+	case kindCounter:
+		return "counter"
+	case kindGauge:
+		return "gauge"
+	default:
+		return "histogram"
+	}
+}
+
+// vec is a registered metric with the label names fixed at first use
+type vec struct {
+	kind      kind
+	labels    []string
+	counter   *prometheus.CounterVec
+	gauge     *prometheus.GaugeVec
+	histogram *prometheus.HistogramVec
+}
+
+// values maps tags onto the metric's labels: missing tags are empty and
+// tags the metric was not created with are dropped
+func (v *vec) values(tags map[string]string) prometheus.Labels {
+	labels := make(prometheus.Labels, len(v.labels))
+	for _, name := range v.labels {
+		labels[name] = tags[name]
+	}
+	return labels
+}
+
+// Collector is a Prometheus-backed types.MetricsCollector. Metrics are
+// created on first use with the tag names of that call as their labels;
+// a name used again as a different metric type is dropped with a warning
+type Collector struct {
+	cfg      Config
+	registry *prometheus.Registry
+	logger   *logger.Logger
+
+	mu   sync.Mutex
+	vecs map[string]*vec
+}
+
+var _ types.MetricsCollector = (*Collector)(nil)
+
+// NewCollector creates a collector with its own registry
+func NewCollector(cfg Config, log *logger.Logger) *Collector {
+	if log == nil {
+		log = logger.Global()
+	}
+
+	registry := prometheus.NewRegistry()
+	if cfg.RuntimeMetrics {
+		registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	}
+
+	return &Collector{
+		cfg:      cfg,
+		registry: registry,
+		logger:   log.WithComponent("metrics"),
+		vecs:     make(map[string]*vec),
+	}
+}
+
+// Registry returns the registry metrics are registered in
+func (c *Collector) Registry() *prometheus.Registry {
+	return c.registry
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (c *Collector) Handler() nethttp.Handler {
+	return promhttp.HandlerFor(c.registry, promhttp.HandlerOpts{Registry: c.registry})
+}
+
+// Counter adds value, which must not be negative, to a counter
+func (c *Collector) Counter(name string, tags map[string]string, value float64) {
+	if value < 0 {
+		c.logger.Warn("Dropping negative counter increment", zap.String("metric", name), zap.Float64("value", value))
+		return
+	}
+	if v := c.vec(name, kindCounter, tags); v != nil {
+		v.counter.With(v.values(tags)).Add(value)
+	}
+}
+
+// Gauge sets a gauge
+func (c *Collector) Gauge(name string, tags map[string]string, value float64) {
+	if v := c.vec(name, kindGauge, tags); v != nil {
+		v.gauge.With(v.values(tags)).Set(value)
+	}
+}
+
+// Histogram records an observation
+func (c *Collector) Histogram(name string, tags map[string]string, value float64) {
+	if v := c.vec(name, kindHistogram, tags); v != nil {
+		v.histogram.With(v.values(tags)).Observe(value)
+	}
+}
+
+// Timer records duration in seconds on a histogram
+func (c *Collector) Timer(name string, tags map[string]string, duration time.Duration) {
+	c.Histogram(name, tags, duration.Seconds())
+}
+
+// vec returns the metric called name, registering it on first use
+func (c *Collector) vec(name string, k kind, tags map[string]string) *vec {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if v, ok := c.vecs[name]; ok {
+		// A nil entry is a metric that failed to register, already logged
+		if v == nil {
+			return nil
+		}
+		if v.kind != k {
+			c.logger.Warn("Dropping metric recorded as a different type",
+				zap.String("metric", name),
+				zap.Stringer("registered", v.kind),
+				zap.Stringer("recorded", k),
+			)
+			return nil
+		}
+		return v
+	}
+
+	labels := make([]string, 0, len(tags))
+	for key := range tags {
+		labels = append(labels, key)
+	}
+	sort.Strings(labels)
+
+	v := &vec{kind: k, labels: labels}
+	help := strings.ReplaceAll(name, "_", " ")
+	var collector prometheus.Collector
+	switch k {
+	case kindCounter:
+		v.counter = prometheus.NewCounterVec(prometheus.CounterOpts{Namespace: c.cfg.Namespace, Name: name, Help: help}, labels)
+		collector = v.counter
+	case kindGauge:
+		v.gauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{Namespace: c.cfg.Namespace, Name: name, Help: help}, labels)
+		collector = v.gauge
+	case kindHistogram:
+		v.histogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: c.cfg.Namespace,
+			Name:      name,
+			Help:      help,
+			Buckets:   c.buckets(name),
+		}, labels)
+		collector = v.histogram
+	}
+
+	if err := c.registry.Register(collector); err != nil {
+		c.logger.Warn("Failed to register metric", zap.String("metric", name), zap.Error(err))
+		c.vecs[name] = nil
+		return nil
+	}
+	c.vecs[name] = v
+	return v
+}
+
+// buckets returns the histogram buckets for a metric name
+func (c *Collector) buckets(name string) []float64 {
+	if strings.HasSuffix(name, "_bytes") {
+		return c.cfg.SizeBuckets
+	}
+	return c.cfg.LatencyBuckets
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"time"
+
+	"go-transport-prac/internal/types"
+)
+
+// Metric names recorded for every instrumented serialization call, labelled
+// with format (avro, avro_json, protobuf, parquet), operation and record
+const (
+	// SerializationDuration is a latency histogram in seconds
+	SerializationDuration = "serialization_duration_seconds"
+	// SerializationPayloadSize is a histogram of encoded payload sizes
+	SerializationPayloadSize = "serialization_payload_bytes"
+	// SerializationErrors counts failed calls
+	SerializationErrors = "serialization_errors_total"
+)
+
+// Operations an instrumented call is labelled with
+const (
+	OperationSerialize   = "serialize"
+	OperationDeserialize = "deserialize"
+)
+
+// Instrumenter records serialization metrics on a types.MetricsCollector.
+// A nil Instrumenter, or one without a collector, records nothing
+type Instrumenter struct {
+	collector types.MetricsCollector
+}
+
+// NewInstrumenter creates an instrumenter reporting to collector
+func NewInstrumenter(collector types.MetricsCollector) *Instrumenter {
+	return &Instrumenter{collector: collector}
+}
+
+// Observe records one call that started at start and encoded or decoded a
+// payload of size bytes. Failed calls are counted but their size is not recorded
+func (in *Instrumenter) Observe(format, operation, record string, start time.Time, size int, err error) {
+	if in == nil || in.collector == nil {
+		return
+	}
+
+	tags := map[string]string{"format": format, "operation": operation, "record": record}
+	in.collector.Timer(SerializationDuration, tags, time.Since(start))
+	if err != nil {
+		in.collector.Counter(SerializationErrors, tags, 1)
+		return
+	}
+	in.collector.Histogram(SerializationPayloadSize, tags, float64(size))
+}
+
+// Serialize runs fn on v and records it as a serialize call
+func Serialize[T any](in *Instrumenter, format, record string, v T, fn func(T) ([]byte, error)) ([]byte, error) {
+	start := time.Now()
+	data, err := fn(v)
+	in.Observe(format, OperationSerialize, record, start, len(data), err)
+	return data, err
+}
+
+// Deserialize runs fn on data and records it as a deserialize call
+func Deserialize[T any](in *Instrumenter, format, record string, data []byte, fn func([]byte) (T, error)) (T, error) {
+	start := time.Now()
+	v, err := fn(data)
+	in.Observe(format, OperationDeserialize, record, start, len(data), err)
+	return v, err
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/protobuf"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+// scrape returns the collector's metrics in the exposition format
+func scrape(t *testing.T, c *Collector) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	c.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatalf("Failed to read metrics: %v", err)
+	}
+	return string(body)
+}
+
+// expectLines fails for every line missing from the scraped metrics
+func expectLines(t *testing.T, body string, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if !strings.Contains(body, line) {
+			t.Errorf("Expected metrics to contain %q", line)
+		}
+	}
+}
+
+func newTestCollector() *Collector {
+	cfg := DefaultConfig()
+	cfg.RuntimeMetrics = false
+	return NewCollector(cfg, nil)
+}
+
+func TestCollector(t *testing.T) {
+	c := newTestCollector()
+
+	c.Counter("requests_total", map[string]string{"route": "/users"}, 2)
+	c.Counter("requests_total", map[string]string{"route": "/users", "extra": "dropped"}, 1)
+	c.Counter("requests_total", map[string]string{"route": "/users"}, -1)
+	c.Gauge("connections", nil, 7)
+	c.Histogram("payload_bytes", map[string]string{"format": "avro"}, 100)
+	c.Timer("call_duration_seconds", nil, 30*time.Millisecond)
+	// A name reused as another type is dropped rather than panicking
+	c.Gauge("requests_total", nil, 1)
+
+	body := scrape(t, c)
+	expectLines(t, body,
+		`transport_requests_total{route="/users"} 3`,
+		`transport_connections 7`,
+		`transport_payload_bytes_bucket{format="avro",le="256"} 1`,
+		`transport_call_duration_seconds_bucket{le="0.05"} 1`,
+		`transport_call_duration_seconds_count 1`,
+	)
+	if strings.Contains(body, "transport_requests_total 1") {
+		t.Error("Expected the gauge reusing a counter name to be dropped")
+	}
+
+	t.Log("✓ Counters, gauges, histograms and timers are exported")
+}
+
+func TestInstrumentedManagers(t *testing.T) {
+	c := newTestCollector()
+	in := NewInstrumenter(c)
+
+	base, err := avro.NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create avro manager: %v", err)
+	}
+	avroManager := InstrumentAvro(base, in)
+	sample := avroManager.CreateSampleUsers(1)[0]
+	data, err := avroManager.SerializeUserBinary(sample)
+	if err != nil {
+		t.Fatalf("Failed to serialize user: %v", err)
+	}
+	if _, err := avroManager.DeserializeUserBinary(data); err != nil {
+		t.Fatalf("Failed to deserialize user: %v", err)
+	}
+	if _, err := avroManager.DeserializeProductBinary([]byte{0xff}); err == nil {
+		t.Fatal("Expected error deserializing a truncated product")
+	}
+
+	protoManager := InstrumentProtobuf(protobuf.NewManager(), in)
+	payload, err := protoManager.SerializeUser(&user.User{Id: 1, Name: "Ada"})
+	if err != nil {
+		t.Fatalf("Failed to serialize proto user: %v", err)
+	}
+	if err := protoManager.Deserialize(payload, &user.User{}); err != nil {
+		t.Fatalf("Failed to deserialize proto user: %v", err)
+	}
+	if _, err := protoManager.DeserializeUser(nil); err == nil {
+		t.Fatal("Expected error deserializing empty data")
+	}
+
+	body := scrape(t, c)
+	expectLines(t, body,
+		`transport_serialization_duration_seconds_count{format="avro",operation="serialize",record="user"} 1`,
+		`transport_serialization_duration_seconds_count{format="avro",operation="deserialize",record="user"} 1`,
+		`transport_serialization_payload_bytes_count{format="avro",operation="serialize",record="user"} 1`,
+		`transport_serialization_errors_total{format="avro",operation="deserialize",record="product"} 1`,
+		`transport_serialization_payload_bytes_count{format="protobuf",operation="deserialize",record="user"} 1`,
+		`transport_serialization_duration_seconds_count{format="protobuf",operation="deserialize",record="user"} 2`,
+		`transport_serialization_errors_total{format="protobuf",operation="deserialize",record="user"} 1`,
+	)
+
+	// A nil instrumenter records nothing
+	if _, err := InstrumentAvro(base, nil).SerializeUserBinary(sample); err != nil {
+		t.Errorf("Uninstrumented serialize failed: %v", err)
+	}
+
+	t.Log("✓ Serialize and deserialize calls record latency, size and errors")
+}
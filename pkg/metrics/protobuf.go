@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"go-transport-prac/pkg/sdl/protobuf"
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+// ProtobufManager wraps a Protobuf manager so its serialize and deserialize
+// calls are recorded. Other methods pass through
+type ProtobufManager struct {
+	*protobuf.Manager
+	in *Instrumenter
+}
+
+// InstrumentProtobuf wraps manager, recording calls with in
+func InstrumentProtobuf(manager *protobuf.Manager, in *Instrumenter) *ProtobufManager {
+	return &ProtobufManager{Manager: manager, in: in}
+}
+
+// SerializeUser serializes a User message to bytes
+func (m *ProtobufManager) SerializeUser(u *user.User) ([]byte, error) {
+	return Serialize(m.in, "protobuf", "user", u, m.Manager.SerializeUser)
+}
+
+// DeserializeUser deserializes bytes to a User message
+func (m *ProtobufManager) DeserializeUser(data []byte) (*user.User, error) {
+	return Deserialize(m.in, "protobuf", "user", data, m.Manager.DeserializeUser)
+}
+
+// SerializeProduct serializes a Product message to bytes
+func (m *ProtobufManager) SerializeProduct(p *product.Product) ([]byte, error) {
+	return Serialize(m.in, "protobuf", "product", p, m.Manager.SerializeProduct)
+}
+
+// DeserializeProduct deserializes bytes to a Product message
+func (m *ProtobufManager) DeserializeProduct(data []byte) (*product.Product, error) {
+	return Deserialize(m.in, "protobuf", "product", data, m.Manager.DeserializeProduct)
+}
+
+// SerializeOrder serializes an Order message to bytes
+func (m *ProtobufManager) SerializeOrder(o *order.Order) ([]byte, error) {
+	return Serialize(m.in, "protobuf", "order", o, m.Manager.SerializeOrder)
+}
+
+// DeserializeOrder deserializes bytes to an Order message
+func (m *ProtobufManager) DeserializeOrder(data []byte) (*order.Order, error) {
+	return Deserialize(m.in, "protobuf", "order", data, m.Manager.DeserializeOrder)
+}
+
+// Serialize serializes any message, recorded under its message name
+func (m *ProtobufManager) Serialize(msg proto.Message) ([]byte, error) {
+	return Serialize(m.in, "protobuf", messageName(msg), msg, m.Manager.Serialize)
+}
+
+// Deserialize deserializes data into msg, recorded under its message name
+func (m *ProtobufManager) Deserialize(data []byte, msg proto.Message) error {
+	start := time.Now()
+	err := m.Manager.Deserialize(data, msg)
+	m.in.Observe("protobuf", OperationDeserialize, messageName(msg), start, len(data), err)
+	return err
+}
+
+// messageName returns the lower-cased short name of msg's type, matching the
+// record labels of the typed methods
+func messageName(msg proto.Message) string {
+	if msg == nil {
+		return "unknown"
+	}
+	return strings.ToLower(string(msg.ProtoReflect().Descriptor().Name()))
+}
@@ -0,0 +1,156 @@
+package model
+
+import (
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+// ToAvro converts the user to the Avro model
+func (u User) ToAvro() avro.User {
+	out := avro.User{
+		ID:        u.ID,
+		Email:     u.Email,
+		Name:      u.Name,
+		Status:    avro.UserStatus(u.Status),
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+	if p := u.Profile; p != nil {
+		out.Profile = &avro.Profile{
+			FirstName: p.FirstName,
+			LastName:  p.LastName,
+			Phone:     optionalString(p.Phone),
+			Interests: p.Interests,
+			Metadata:  p.Metadata,
+		}
+		if a := p.Address; a != nil {
+			out.Profile.Address = &avro.Address{
+				Street:     a.Street,
+				City:       a.City,
+				State:      a.State,
+				PostalCode: a.PostalCode,
+				Country:    a.Country,
+			}
+		}
+	}
+	return out
+}
+
+// UserFromAvro converts an Avro user to the canonical model
+func UserFromAvro(u avro.User) User {
+	out := User{
+		ID:        u.ID,
+		Email:     u.Email,
+		Name:      u.Name,
+		Status:    UserStatus(u.Status),
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+	if p := u.Profile; p != nil {
+		out.Profile = &Profile{
+			FirstName: p.FirstName,
+			LastName:  p.LastName,
+			Phone:     stringValue(p.Phone),
+			Interests: p.Interests,
+			Metadata:  p.Metadata,
+		}
+		if a := p.Address; a != nil {
+			out.Profile.Address = &Address{
+				Street:     a.Street,
+				City:       a.City,
+				State:      a.State,
+				PostalCode: a.PostalCode,
+				Country:    a.Country,
+			}
+		}
+	}
+	return out
+}
+
+// ToAvro converts the product to the Avro model. The exact decimal amount is
+// left unset, so readers fall back to AmountCents
+func (p Product) ToAvro() avro.Product {
+	return avro.Product{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		SKU:         p.SKU,
+		Price:       p.Price.ToAvro(),
+		Inventory: avro.Inventory{
+			Quantity:       p.Inventory.Quantity,
+			Reserved:       p.Inventory.Reserved,
+			Available:      p.Inventory.Available,
+			TrackInventory: p.Inventory.TrackInventory,
+			ReorderLevel:   p.Inventory.ReorderLevel,
+			MaxStock:       p.Inventory.MaxStock,
+		},
+		Categories:     p.Categories,
+		Tags:           p.Tags,
+		Status:         avro.ProductStatus(p.Status),
+		Specifications: p.Specifications,
+		ReleaseDate:    p.ReleaseDate,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}
+}
+
+// ProductFromAvro converts an Avro product to the canonical model
+func ProductFromAvro(p avro.Product) Product {
+	return Product{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		SKU:         p.SKU,
+		Price:       PriceFromAvro(p.Price),
+		Inventory: Inventory{
+			Quantity:       p.Inventory.Quantity,
+			Reserved:       p.Inventory.Reserved,
+			Available:      p.Inventory.Available,
+			TrackInventory: p.Inventory.TrackInventory,
+			ReorderLevel:   p.Inventory.ReorderLevel,
+			MaxStock:       p.Inventory.MaxStock,
+		},
+		Categories:     p.Categories,
+		Tags:           p.Tags,
+		Status:         ProductStatus(p.Status),
+		Specifications: p.Specifications,
+		ReleaseDate:    p.ReleaseDate,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}
+}
+
+// ToAvro converts the price to the Avro model
+func (p Price) ToAvro() avro.Price {
+	out := avro.Price{Currency: p.Currency, AmountCents: p.AmountCents}
+	if p.DiscountPercentage != 0 {
+		discount := p.DiscountPercentage
+		out.DiscountPercentage = &discount
+	}
+	return out
+}
+
+// PriceFromAvro converts an Avro price to the canonical model. The exact
+// decimal amount is dropped in favour of AmountCents
+func PriceFromAvro(p avro.Price) Price {
+	out := Price{Currency: p.Currency, AmountCents: p.AmountCents}
+	if p.DiscountPercentage != nil {
+		out.DiscountPercentage = *p.DiscountPercentage
+	}
+	return out
+}
+
+// optionalString maps the empty string, which the canonical model uses for
+// an absent value, to nil
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
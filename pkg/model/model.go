@@ -0,0 +1,99 @@
+// Package model holds the canonical domain structs shared by the transports
+// and pipelines. Each serialization format keeps its own generated or tagged
+// structs; the mappers in this package (ToAvro/UserFromAvro, ToProto/
+// UserFromProto) and in model/parquetmodel convert between them and the
+// canonical model, so a field added to the domain is mapped in one place.
+//
+// Optional values are zero values when absent, the way protobuf and Parquet
+// store them. The Avro mappers turn them into nulls and back.
+package model
+
+import (
+	"time"
+)
+
+// UserStatus is the lifecycle state of a user
+type UserStatus string
+
+const (
+	UserStatusActive    UserStatus = "ACTIVE"
+	UserStatusInactive  UserStatus = "INACTIVE"
+	UserStatusSuspended UserStatus = "SUSPENDED"
+	UserStatusDeleted   UserStatus = "DELETED"
+)
+
+// ProductStatus is the sales state of a product
+type ProductStatus string
+
+const (
+	ProductStatusActive       ProductStatus = "ACTIVE"
+	ProductStatusInactive     ProductStatus = "INACTIVE"
+	ProductStatusOutOfStock   ProductStatus = "OUT_OF_STOCK"
+	ProductStatusDiscontinued ProductStatus = "DISCONTINUED"
+)
+
+// User is a registered user
+type User struct {
+	ID        int64      `json:"id"`
+	Email     string     `json:"email" pii:"email"`
+	Name      string     `json:"name" pii:"name"`
+	Status    UserStatus `json:"status"`
+	Profile   *Profile   `json:"profile,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+// Profile holds the optional personal details of a user
+type Profile struct {
+	FirstName string            `json:"firstName" pii:"name"`
+	LastName  string            `json:"lastName" pii:"name"`
+	Phone     string            `json:"phone,omitempty" pii:"phone"`
+	Address   *Address          `json:"address,omitempty"`
+	Interests []string          `json:"interests,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// Address is a postal address
+type Address struct {
+	Street     string `json:"street" pii:"address"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	PostalCode string `json:"postalCode" pii:"postal_code"`
+	Country    string `json:"country"`
+}
+
+// Product is an item in the catalog
+type Product struct {
+	ID             int64             `json:"id"`
+	Name           string            `json:"name"`
+	Description    string            `json:"description"`
+	SKU            string            `json:"sku"`
+	Price          Price             `json:"price"`
+	Inventory      Inventory         `json:"inventory"`
+	Categories     []string          `json:"categories,omitempty"`
+	Tags           []string          `json:"tags,omitempty"`
+	Status         ProductStatus     `json:"status"`
+	Specifications map[string]string `json:"specifications,omitempty"`
+	// ReleaseDate is only carried by Avro
+	ReleaseDate *time.Time `json:"releaseDate,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// Price is an amount in the smallest unit of its currency
+type Price struct {
+	Currency    string `json:"currency"`
+	AmountCents int64  `json:"amountCents"`
+	// DiscountPercentage is zero when the price is not discounted
+	DiscountPercentage float32 `json:"discountPercentage,omitempty"`
+}
+
+// Inventory tracks the stock of a product
+type Inventory struct {
+	Quantity       int32 `json:"quantity"`
+	Reserved       int32 `json:"reserved"`
+	Available      int32 `json:"available"`
+	TrackInventory bool  `json:"trackInventory"`
+	ReorderLevel   int32 `json:"reorderLevel"`
+	MaxStock       int32 `json:"maxStock"`
+}
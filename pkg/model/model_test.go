@@ -0,0 +1,111 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+func sampleUsers(t *testing.T) []avro.User {
+	t.Helper()
+	manager, err := avro.NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create avro manager: %v", err)
+	}
+	return manager.WithClock(testutil.NewDefaultFakeClock()).CreateSampleUsers(3)
+}
+
+func sampleProduct() Product {
+	created := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	return Product{
+		ID:          9,
+		Name:        "Sensor",
+		Description: "Temperature sensor",
+		SKU:         "SKU-000009",
+		Price:       Price{Currency: "USD", AmountCents: 1999, DiscountPercentage: 0.1},
+		Inventory:   Inventory{Quantity: 10, Reserved: 2, Available: 8, TrackInventory: true, ReorderLevel: 3, MaxStock: 50},
+		Categories:  []string{"iot"},
+		Tags:        []string{"sensor", "temperature"},
+		Status:      ProductStatusActive,
+		Specifications: map[string]string{
+			"range": "-40..125C",
+		},
+		CreatedAt: created,
+		UpdatedAt: created.Add(time.Hour),
+	}
+}
+
+func TestAvroMapping(t *testing.T) {
+	for _, u := range sampleUsers(t) {
+		if back := UserFromAvro(u).ToAvro(); !reflect.DeepEqual(back, u) {
+			t.Errorf("User changed across the model:\n got %+v\nwant %+v", back, u)
+		}
+	}
+
+	// Absent optional values are zero in the model and null in Avro
+	phone := ""
+	u := UserFromAvro(avro.User{ID: 1, Profile: &avro.Profile{FirstName: "Ada", Phone: &phone}})
+	if u.Profile.Phone != "" || u.ToAvro().Profile.Phone != nil {
+		t.Errorf("Expected an empty phone to become null, got %+v", u.ToAvro().Profile)
+	}
+
+	product := sampleProduct()
+	release := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	product.ReleaseDate = &release
+	if back := ProductFromAvro(product.ToAvro()); !reflect.DeepEqual(back, product) {
+		t.Errorf("Product changed across Avro:\n got %+v\nwant %+v", back, product)
+	}
+	if product.ToAvro().Price.DiscountPercentage == nil {
+		t.Error("Expected the discount to be set")
+	}
+	product.Price.DiscountPercentage = 0
+	if product.ToAvro().Price.DiscountPercentage != nil {
+		t.Error("Expected no discount to become null")
+	}
+
+	// The exact decimal is dropped, AmountCents is kept
+	price := PriceFromAvro(avro.Price{Currency: "EUR", AmountCents: 1234, Amount: avro.NewDecimal(123456, 4)})
+	if price != (Price{Currency: "EUR", AmountCents: 1234}) {
+		t.Errorf("Unexpected price %+v", price)
+	}
+
+	t.Log("✓ Users and products map to and from Avro")
+}
+
+func TestProtoMapping(t *testing.T) {
+	for _, avroUser := range sampleUsers(t) {
+		u := UserFromAvro(avroUser)
+		if back := UserFromProto(u.ToProto()); !reflect.DeepEqual(back, u) {
+			t.Errorf("User changed across protobuf:\n got %+v\nwant %+v", back, u)
+		}
+	}
+
+	msg := User{ID: 1, Status: UserStatusSuspended}.ToProto()
+	if msg.GetStatus().String() != "USER_STATUS_SUSPENDED" || msg.GetCreatedAt() != nil {
+		t.Errorf("Expected a suspended status and no timestamp, got %v and %v", msg.GetStatus(), msg.GetCreatedAt())
+	}
+	if msg := (User{Status: "BANNED"}).ToProto(); msg.GetStatus().String() != "USER_STATUS_UNSPECIFIED" {
+		t.Errorf("Expected an unknown status to be unspecified, got %v", msg.GetStatus())
+	}
+	if back := UserFromProto(User{ID: 1}.ToProto()); back.Status != "" || !back.CreatedAt.IsZero() {
+		t.Errorf("Expected an empty status and zero time, got %+v", back)
+	}
+
+	product := sampleProduct()
+	msg2 := product.ToProto()
+	if msg2.GetStatus().String() != "PRODUCT_STATUS_ACTIVE" || msg2.GetSpecifications().GetAttributes()["range"] != "-40..125C" {
+		t.Errorf("Unexpected product message %v", msg2)
+	}
+	if back := ProductFromProto(msg2); !reflect.DeepEqual(back, product) {
+		t.Errorf("Product changed across protobuf:\n got %+v\nwant %+v", back, product)
+	}
+	product.Specifications = nil
+	if product.ToProto().Specifications != nil {
+		t.Error("Expected no specifications message without attributes")
+	}
+
+	t.Log("✓ Users and products map to and from protobuf")
+}
@@ -0,0 +1,139 @@
+// Package parquetmodel maps the canonical model to and from the Parquet rows.
+// It lives apart from package model so that servers using the model do not
+// link the Parquet library.
+package parquetmodel
+
+import (
+	"go-transport-prac/pkg/model"
+	"go-transport-prac/pkg/sdl/parquet"
+)
+
+// UserToParquet converts a user to its Parquet row
+func UserToParquet(u model.User) parquet.User {
+	row := parquet.User{
+		ID:        u.ID,
+		Email:     u.Email,
+		Name:      u.Name,
+		Status:    string(u.Status),
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+	if p := u.Profile; p != nil {
+		row.Profile = &parquet.Profile{
+			FirstName: p.FirstName,
+			LastName:  p.LastName,
+			Phone:     p.Phone,
+			Interests: p.Interests,
+			Metadata:  p.Metadata,
+		}
+		if a := p.Address; a != nil {
+			row.Profile.Address = &parquet.Address{
+				Street:     a.Street,
+				City:       a.City,
+				State:      a.State,
+				PostalCode: a.PostalCode,
+				Country:    a.Country,
+			}
+		}
+	}
+	return row
+}
+
+// UserFromParquet converts a Parquet row to the canonical model
+func UserFromParquet(row parquet.User) model.User {
+	u := model.User{
+		ID:        row.ID,
+		Email:     row.Email,
+		Name:      row.Name,
+		Status:    model.UserStatus(row.Status),
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+	if p := row.Profile; p != nil {
+		u.Profile = &model.Profile{
+			FirstName: p.FirstName,
+			LastName:  p.LastName,
+			Phone:     p.Phone,
+			Interests: p.Interests,
+			Metadata:  p.Metadata,
+		}
+		if a := p.Address; a != nil {
+			u.Profile.Address = &model.Address{
+				Street:     a.Street,
+				City:       a.City,
+				State:      a.State,
+				PostalCode: a.PostalCode,
+				Country:    a.Country,
+			}
+		}
+	}
+	return u
+}
+
+// ProductToParquet converts a product to its Parquet row. The release date has
+// no column and is dropped
+func ProductToParquet(p model.Product) parquet.Product {
+	return parquet.Product{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		SKU:         p.SKU,
+		Price:       PriceToParquet(p.Price),
+		Inventory: &parquet.Inventory{
+			Quantity:       p.Inventory.Quantity,
+			Reserved:       p.Inventory.Reserved,
+			Available:      p.Inventory.Available,
+			TrackInventory: p.Inventory.TrackInventory,
+			ReorderLevel:   p.Inventory.ReorderLevel,
+			MaxStock:       p.Inventory.MaxStock,
+		},
+		Categories:     p.Categories,
+		Tags:           p.Tags,
+		Status:         string(p.Status),
+		Specifications: p.Specifications,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}
+}
+
+// ProductFromParquet converts a Parquet row to the canonical model
+func ProductFromParquet(row parquet.Product) model.Product {
+	p := model.Product{
+		ID:             row.ID,
+		Name:           row.Name,
+		Description:    row.Description,
+		SKU:            row.SKU,
+		Price:          PriceFromParquet(row.Price),
+		Categories:     row.Categories,
+		Tags:           row.Tags,
+		Status:         model.ProductStatus(row.Status),
+		Specifications: row.Specifications,
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+	}
+	if i := row.Inventory; i != nil {
+		p.Inventory = model.Inventory{
+			Quantity:       i.Quantity,
+			Reserved:       i.Reserved,
+			Available:      i.Available,
+			TrackInventory: i.TrackInventory,
+			ReorderLevel:   i.ReorderLevel,
+			MaxStock:       i.MaxStock,
+		}
+	}
+	return p
+}
+
+// PriceToParquet converts a price to its Parquet group
+func PriceToParquet(p model.Price) *parquet.Price {
+	return &parquet.Price{Currency: p.Currency, AmountCents: p.AmountCents, DiscountPercentage: p.DiscountPercentage}
+}
+
+// PriceFromParquet converts a Parquet price group, which may be null, to the
+// canonical model
+func PriceFromParquet(p *parquet.Price) model.Price {
+	if p == nil {
+		return model.Price{}
+	}
+	return model.Price{Currency: p.Currency, AmountCents: p.AmountCents, DiscountPercentage: p.DiscountPercentage}
+}
@@ -0,0 +1,163 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+// ToProto converts the user to its protobuf message. A status without a
+// protobuf value becomes USER_STATUS_UNSPECIFIED
+func (u User) ToProto() *user.User {
+	msg := &user.User{
+		Id:        uint64(u.ID),
+		Email:     u.Email,
+		Name:      u.Name,
+		Status:    user.UserStatus(user.UserStatus_value["USER_STATUS_"+string(u.Status)]),
+		CreatedAt: timeToProto(u.CreatedAt),
+		UpdatedAt: timeToProto(u.UpdatedAt),
+	}
+	if p := u.Profile; p != nil {
+		msg.Profile = &user.Profile{
+			FirstName: p.FirstName,
+			LastName:  p.LastName,
+			Phone:     p.Phone,
+			Interests: p.Interests,
+			Metadata:  p.Metadata,
+		}
+		if a := p.Address; a != nil {
+			msg.Profile.Address = &user.Address{
+				Street:     a.Street,
+				City:       a.City,
+				State:      a.State,
+				PostalCode: a.PostalCode,
+				Country:    a.Country,
+			}
+		}
+	}
+	return msg
+}
+
+// UserFromProto converts a protobuf user message to the canonical model
+func UserFromProto(msg *user.User) User {
+	u := User{
+		ID:        int64(msg.GetId()),
+		Email:     msg.GetEmail(),
+		Name:      msg.GetName(),
+		Status:    UserStatus(enumFromProto("USER_STATUS_", msg.GetStatus().String())),
+		CreatedAt: timeFromProto(msg.GetCreatedAt()),
+		UpdatedAt: timeFromProto(msg.GetUpdatedAt()),
+	}
+	if p := msg.GetProfile(); p != nil {
+		u.Profile = &Profile{
+			FirstName: p.GetFirstName(),
+			LastName:  p.GetLastName(),
+			Phone:     p.GetPhone(),
+			Interests: p.GetInterests(),
+			Metadata:  p.GetMetadata(),
+		}
+		if a := p.GetAddress(); a != nil {
+			u.Profile.Address = &Address{
+				Street:     a.GetStreet(),
+				City:       a.GetCity(),
+				State:      a.GetState(),
+				PostalCode: a.GetPostalCode(),
+				Country:    a.GetCountry(),
+			}
+		}
+	}
+	return u
+}
+
+// ToProto converts the product to its protobuf message. The release date has
+// no protobuf field and is dropped
+func (p Product) ToProto() *product.Product {
+	msg := &product.Product{
+		Id:          uint64(p.ID),
+		Name:        p.Name,
+		Description: p.Description,
+		Sku:         p.SKU,
+		Price:       p.Price.ToProto(),
+		Inventory: &product.Inventory{
+			Quantity:       p.Inventory.Quantity,
+			Reserved:       p.Inventory.Reserved,
+			Available:      p.Inventory.Available,
+			TrackInventory: p.Inventory.TrackInventory,
+			ReorderLevel:   p.Inventory.ReorderLevel,
+			MaxStock:       p.Inventory.MaxStock,
+		},
+		Categories: p.Categories,
+		Tags:       p.Tags,
+		Status:     product.ProductStatus(product.ProductStatus_value["PRODUCT_STATUS_"+string(p.Status)]),
+		CreatedAt:  timeToProto(p.CreatedAt),
+		UpdatedAt:  timeToProto(p.UpdatedAt),
+	}
+	if len(p.Specifications) > 0 {
+		msg.Specifications = &product.Specifications{Attributes: p.Specifications}
+	}
+	return msg
+}
+
+// ProductFromProto converts a protobuf product message to the canonical model
+func ProductFromProto(msg *product.Product) Product {
+	inv := msg.GetInventory()
+	return Product{
+		ID:          int64(msg.GetId()),
+		Name:        msg.GetName(),
+		Description: msg.GetDescription(),
+		SKU:         msg.GetSku(),
+		Price:       PriceFromProto(msg.GetPrice()),
+		Inventory: Inventory{
+			Quantity:       inv.GetQuantity(),
+			Reserved:       inv.GetReserved(),
+			Available:      inv.GetAvailable(),
+			TrackInventory: inv.GetTrackInventory(),
+			ReorderLevel:   inv.GetReorderLevel(),
+			MaxStock:       inv.GetMaxStock(),
+		},
+		Categories:     msg.GetCategories(),
+		Tags:           msg.GetTags(),
+		Status:         ProductStatus(enumFromProto("PRODUCT_STATUS_", msg.GetStatus().String())),
+		Specifications: msg.GetSpecifications().GetAttributes(),
+		CreatedAt:      timeFromProto(msg.GetCreatedAt()),
+		UpdatedAt:      timeFromProto(msg.GetUpdatedAt()),
+	}
+}
+
+// ToProto converts the price to its protobuf message
+func (p Price) ToProto() *product.Price {
+	return &product.Price{Currency: p.Currency, AmountCents: p.AmountCents, DiscountPercentage: p.DiscountPercentage}
+}
+
+// PriceFromProto converts a protobuf price message to the canonical model
+func PriceFromProto(msg *product.Price) Price {
+	return Price{Currency: msg.GetCurrency(), AmountCents: msg.GetAmountCents(), DiscountPercentage: msg.GetDiscountPercentage()}
+}
+
+// enumFromProto maps a protobuf enum name like USER_STATUS_ACTIVE back onto
+// its symbol, and the unspecified value onto the empty status
+func enumFromProto(prefix, name string) string {
+	if strings.HasSuffix(name, "_UNSPECIFIED") {
+		return ""
+	}
+	return strings.TrimPrefix(name, prefix)
+}
+
+// timeToProto leaves the zero time unset
+func timeToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func timeFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
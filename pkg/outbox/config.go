@@ -0,0 +1,36 @@
+package outbox
+
+import "time"
+
+// Config holds outbox delivery and storage settings
+type Config struct {
+	// TopicPrefix is prepended to an event's type to form its broker topic
+	TopicPrefix string
+	// RetryBackoff is the delay after the first failed delivery; it doubles
+	// on each retry up to MaxRetryBackoff
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	// MaxAttempts is how many times delivery of an event is tried before it
+	// is parked in the dead letter file; a negative value retries forever
+	MaxAttempts int
+	// Sync fsyncs the log after every append and ack. Without it a power
+	// loss can drop emitted events or redeliver delivered ones
+	Sync bool
+	// CompactSize is the log size at which a fully delivered log is emptied
+	CompactSize int64
+	// MaxRecordSize is the largest serialized event accepted
+	MaxRecordSize int
+}
+
+// DefaultConfig returns durable settings with a short retry backoff
+func DefaultConfig() Config {
+	return Config{
+		TopicPrefix:     "events.",
+		RetryBackoff:    100 * time.Millisecond,
+		MaxRetryBackoff: 10 * time.Second,
+		MaxAttempts:     10,
+		Sync:            true,
+		CompactSize:     4 << 20,
+		MaxRecordSize:   1 << 20,
+	}
+}
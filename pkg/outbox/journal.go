@@ -0,0 +1,264 @@
+package outbox
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/transport/framing"
+)
+
+const (
+	// logFile holds the records, each framed with a 4-byte length prefix
+	logFile = "outbox.log"
+	// ackFile holds the log offset up to which records were delivered
+	ackFile = "outbox.ack"
+	// deadFile holds the records whose delivery was given up, framed like the log
+	deadFile = "outbox.dead"
+	// framePrefixSize is the length of a PrefixFixed32 frame header
+	framePrefixSize = 4
+)
+
+// journal is the append-only record log plus the offset of the first
+// undelivered record. It is not safe for concurrent use
+type journal struct {
+	dir    string
+	file   *os.File
+	writer *framing.Writer
+	sync   bool
+	// size is the end of the last complete frame
+	size int64
+	// acked is the offset up to which records were delivered
+	acked int64
+	// maxRecordSize bounds frames read back from the log
+	maxRecordSize int
+}
+
+// openJournal opens or creates the journal in dir and returns it with the
+// number of undelivered records. A frame torn by a crash mid-append is
+// truncated; a log that cannot be read to its end is an error
+func openJournal(dir string, sync bool, maxRecordSize int) (*journal, int, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, 0, fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+
+	file, err := os.OpenFile(filepath.Join(dir, logFile), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open outbox log: %w", err)
+	}
+	j := &journal{
+		dir:           dir,
+		file:          file,
+		writer:        framing.NewWriter(file, framing.PrefixFixed32),
+		sync:          sync,
+		maxRecordSize: maxRecordSize,
+	}
+
+	acked, err := readAck(filepath.Join(dir, ackFile))
+	if err != nil {
+		file.Close()
+		return nil, 0, err
+	}
+
+	pending, err := j.recover(acked)
+	if err != nil {
+		file.Close()
+		return nil, 0, err
+	}
+	return j, pending, nil
+}
+
+// recover scans the log for complete frames, truncates a torn frame after the
+// last one and aligns the ack offset to a frame boundary, redelivering rather
+// than skipping a record when the two disagree. Any other unreadable frame
+// fails, leaving the log untouched, since the records after it would be lost
+func (j *journal) recover(acked int64) (int, error) {
+	info, err := j.file.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat outbox log: %w", err)
+	}
+
+	reader := framing.NewReader(io.NewSectionReader(j.file, 0, info.Size()), framing.PrefixFixed32).
+		WithMaxFrameSize(j.maxRecordSize)
+	var end int64
+	pending := 0
+	for {
+		frame, err := reader.ReadFrame()
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if errors.Is(err, framing.ErrFrameTooLarge) {
+			return 0, fmt.Errorf("outbox record at offset %d is larger than MaxRecordSize %d: %w", end, j.maxRecordSize, err)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read outbox log at offset %d: %w", end, err)
+		}
+		end += framePrefixSize + int64(len(frame))
+		if end <= acked {
+			j.acked = end
+		} else {
+			pending++
+		}
+	}
+
+	if end < info.Size() {
+		if err := j.file.Truncate(end); err != nil {
+			return 0, fmt.Errorf("failed to truncate torn outbox record: %w", err)
+		}
+	}
+	j.size = end
+	return pending, nil
+}
+
+// append writes payload as one frame, syncing it to disk when configured.
+// A failed append is rolled back so the log never ends in a partial frame
+func (j *journal) append(payload []byte) error {
+	if err := j.writer.WriteFrame(payload); err != nil {
+		j.file.Truncate(j.size)
+		return fmt.Errorf("failed to append outbox record: %w", err)
+	}
+	if j.sync {
+		if err := j.file.Sync(); err != nil {
+			j.file.Truncate(j.size)
+			return fmt.Errorf("failed to sync outbox log: %w", err)
+		}
+	}
+	j.size += framePrefixSize + int64(len(payload))
+	return nil
+}
+
+// park appends payload to the dead letter file, syncing it when configured
+func (j *journal) park(payload []byte) error {
+	file, err := os.OpenFile(filepath.Join(j.dir, deadFile), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open outbox dead letters: %w", err)
+	}
+	if err := framing.NewWriter(file, framing.PrefixFixed32).WriteFrame(payload); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to park outbox record: %w", err)
+	}
+	if j.sync {
+		if err := file.Sync(); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to sync outbox dead letters: %w", err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close outbox dead letters: %w", err)
+	}
+	return nil
+}
+
+// reader returns a frame reader over the records between from and the end of the log
+func (j *journal) reader(from int64) *framing.Reader {
+	return framing.NewReader(io.NewSectionReader(j.file, from, j.size-from), framing.PrefixFixed32).
+		WithMaxFrameSize(j.maxRecordSize)
+}
+
+// ack records that everything before offset was delivered
+func (j *journal) ack(offset int64) error {
+	if err := writeAck(filepath.Join(j.dir, ackFile), offset, j.sync); err != nil {
+		return err
+	}
+	j.acked = offset
+	return nil
+}
+
+// reset empties a fully delivered log. The ack offset is cleared first, so a
+// crash in between redelivers the old records instead of skipping new ones
+func (j *journal) reset() error {
+	if err := j.ack(0); err != nil {
+		return err
+	}
+	if err := j.file.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate outbox log: %w", err)
+	}
+	j.size = 0
+	return nil
+}
+
+// close closes the log file
+func (j *journal) close() error {
+	if err := j.file.Close(); err != nil {
+		return fmt.Errorf("failed to close outbox log: %w", err)
+	}
+	return nil
+}
+
+// readAck reads the ack offset, which is zero before the first delivery
+func readAck(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read outbox ack: %w", err)
+	}
+	offset, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid outbox ack offset %q", data)
+	}
+	return offset, nil
+}
+
+// writeAck replaces the ack file through a rename, so a crash leaves either
+// the old or the new offset
+func writeAck(path string, offset int64, sync bool) error {
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create outbox ack: %w", err)
+	}
+	if _, err := file.WriteString(strconv.FormatInt(offset, 10) + "\n"); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write outbox ack: %w", err)
+	}
+	if sync {
+		if err := file.Sync(); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to sync outbox ack: %w", err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close outbox ack: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace outbox ack: %w", err)
+	}
+	return nil
+}
+
+// ReadParked returns the events in dir's dead letter file, oldest first. An
+// outbox parks an event once its delivery fails MaxAttempts times
+func ReadParked(dir string) ([]types.Event, error) {
+	data, err := os.ReadFile(filepath.Join(dir, deadFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox dead letters: %w", err)
+	}
+
+	reader := framing.NewReader(bytes.NewReader(data), framing.PrefixFixed32).WithMaxFrameSize(len(data))
+	var events []types.Event
+	for {
+		frame, err := reader.ReadFrame()
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return events, fmt.Errorf("failed to read parked event %d: %w", len(events)+1, err)
+		}
+		event, err := DecodeEvent(frame)
+		if err != nil {
+			return events, fmt.Errorf("failed to decode parked event %d: %w", len(events)+1, err)
+		}
+		events = append(events, event)
+	}
+}
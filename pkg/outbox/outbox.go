@@ -0,0 +1,479 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/transport/framing"
+)
+
+// AllEvents subscribes a handler to every event type
+const AllEvents = "*"
+
+// ErrClosed is returned by operations on a closed outbox
+var ErrClosed = errors.New("outbox is closed")
+
+// subscriber is a registered local handler
+type subscriber struct {
+	handler types.EventHandler
+	stop    func() bool
+}
+
+// Outbox is a durable types.EventEmitter. Emit appends the event to a log on
+// disk and returns; a background dispatcher delivers logged events in order
+// to the broker and to local subscribers, retrying with backoff until all of
+// them accept it or it runs out of attempts and is parked. Progress is
+// persisted, so events emitted before a crash or restart are delivered once
+// the outbox is reopened. Delivery is at least once: an event whose delivery
+// was interrupted is delivered again.
+type Outbox struct {
+	cfg    Config
+	broker types.MessageBroker
+	logger *logger.Logger
+	clock  types.Clock
+
+	mu       sync.Mutex
+	journal  *journal
+	handlers map[string][]*subscriber
+	pending  int
+	closed   bool
+	// delivered is closed and replaced whenever an event is acknowledged
+	delivered chan struct{}
+
+	wake   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var _ types.EventEmitter = (*Outbox)(nil)
+
+// New opens the outbox stored in dir and starts delivering its pending events.
+// Events are published to broker on cfg.TopicPrefix plus the event type; a nil
+// broker delivers to local subscribers only. Zero durations, sizes and
+// attempts in cfg take their DefaultConfig values
+func New(dir string, broker types.MessageBroker, cfg Config, log *logger.Logger) (*Outbox, error) {
+	defaults := DefaultConfig()
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = defaults.RetryBackoff
+	}
+	if cfg.MaxRetryBackoff < cfg.RetryBackoff {
+		cfg.MaxRetryBackoff = max(defaults.MaxRetryBackoff, cfg.RetryBackoff)
+	}
+	if cfg.MaxAttempts == 0 {
+		cfg.MaxAttempts = defaults.MaxAttempts
+	}
+	if cfg.CompactSize <= 0 {
+		cfg.CompactSize = defaults.CompactSize
+	}
+	if cfg.MaxRecordSize <= 0 {
+		cfg.MaxRecordSize = defaults.MaxRecordSize
+	}
+	if log == nil {
+		log = logger.Global()
+	}
+
+	j, pending, err := openJournal(dir, cfg.Sync, cfg.MaxRecordSize)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	o := &Outbox{
+		cfg:       cfg,
+		broker:    broker,
+		logger:    log.WithComponent("outbox"),
+		clock:     types.SystemClock{},
+		journal:   j,
+		handlers:  make(map[string][]*subscriber),
+		pending:   pending,
+		delivered: make(chan struct{}),
+		wake:      make(chan struct{}, 1),
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	o.logger.Info("Outbox opened", zap.String("dir", dir), zap.Int("pending", pending))
+
+	go o.dispatch()
+	return o, nil
+}
+
+// WithClock sets the clock used to stamp events emitted without a timestamp
+func (o *Outbox) WithClock(clock types.Clock) *Outbox {
+	o.clock = types.ClockOrSystem(clock)
+	return o
+}
+
+// Emit durably appends event to the outbox; it returns once the event is on
+// disk, before it is delivered. A missing ID or timestamp is filled in
+func (o *Outbox) Emit(ctx context.Context, event types.Event) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if event.Type == "" {
+		return fmt.Errorf("event type is required")
+	}
+	if event.ID == "" {
+		id, err := newEventID()
+		if err != nil {
+			return fmt.Errorf("failed to generate event ID: %w", err)
+		}
+		event.ID = id
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = o.clock.Now()
+	}
+
+	payload, err := EncodeEvent(event)
+	if err != nil {
+		return err
+	}
+	if len(payload) > o.cfg.MaxRecordSize {
+		return fmt.Errorf("event %s is %d bytes, more than the %d allowed", event.ID, len(payload), o.cfg.MaxRecordSize)
+	}
+
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return ErrClosed
+	}
+	err = o.journal.append(payload)
+	if err == nil {
+		o.pending++
+	}
+	o.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Subscribe calls handler for every delivered event of eventType, or of every
+// type for AllEvents. An event is acknowledged only once all handlers
+// subscribed when it is delivered succeed. The subscription ends when ctx is
+// done or Unsubscribe is called
+func (o *Outbox) Subscribe(ctx context.Context, eventType string, handler types.EventHandler) error {
+	if handler == nil {
+		return fmt.Errorf("event handler is required")
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return ErrClosed
+	}
+
+	sub := &subscriber{handler: handler}
+	sub.stop = context.AfterFunc(ctx, func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		o.remove(eventType, sub)
+	})
+	o.handlers[eventType] = append(o.handlers[eventType], sub)
+	return nil
+}
+
+// Unsubscribe removes the first handler for eventType with the same function
+// as handler. Closures created by the same function literal compare equal
+func (o *Outbox) Unsubscribe(ctx context.Context, eventType string, handler types.EventHandler) error {
+	target := reflect.ValueOf(handler).Pointer()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, sub := range o.handlers[eventType] {
+		if reflect.ValueOf(sub.handler).Pointer() == target {
+			sub.stop()
+			o.remove(eventType, sub)
+			return nil
+		}
+	}
+	return fmt.Errorf("no handler subscribed to event type %s", eventType)
+}
+
+// Flush waits until every event emitted so far has been delivered
+func (o *Outbox) Flush(ctx context.Context) error {
+	for {
+		o.mu.Lock()
+		if o.pending == 0 {
+			o.mu.Unlock()
+			return nil
+		}
+		if o.closed {
+			o.mu.Unlock()
+			return ErrClosed
+		}
+		delivered := o.delivered
+		o.mu.Unlock()
+
+		select {
+		case <-delivered:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Pending returns the number of emitted events not yet delivered
+func (o *Outbox) Pending() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.pending
+}
+
+// Close stops the dispatcher and closes the log. Undelivered events stay on
+// disk for the next New; the broker is left open
+func (o *Outbox) Close() error {
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return nil
+	}
+	o.closed = true
+	for _, subs := range o.handlers {
+		for _, sub := range subs {
+			sub.stop()
+		}
+	}
+	o.handlers = make(map[string][]*subscriber)
+	o.mu.Unlock()
+
+	o.cancel()
+	<-o.done
+
+	o.mu.Lock()
+	close(o.delivered)
+	o.mu.Unlock()
+	return o.journal.close()
+}
+
+// remove drops sub from eventType's handlers; the caller holds mu
+func (o *Outbox) remove(eventType string, sub *subscriber) {
+	subs := o.handlers[eventType]
+	for i, other := range subs {
+		if other == sub {
+			o.handlers[eventType] = append(subs[:i:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(o.handlers[eventType]) == 0 {
+		delete(o.handlers, eventType)
+	}
+}
+
+// dispatch delivers logged events in order until the outbox is closed
+func (o *Outbox) dispatch() {
+	defer close(o.done)
+
+	for {
+		o.mu.Lock()
+		offset := o.journal.acked
+		reader := o.journal.reader(offset)
+		o.mu.Unlock()
+
+		err := o.drain(reader, offset)
+		if o.ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			// Unacknowledged events are read again from the last ack
+			o.logger.Error("Failed to dispatch outbox", zap.Error(err))
+			if !sleepContext(o.ctx, o.cfg.MaxRetryBackoff) {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-o.wake:
+		case <-o.ctx.Done():
+			return
+		}
+	}
+}
+
+// drain delivers the records in reader, which starts at offset, acknowledging
+// each one, and compacts the log once it has all been delivered
+func (o *Outbox) drain(reader *framing.Reader, offset int64) error {
+	for {
+		frame, err := reader.ReadFrame()
+		if err == io.EOF {
+			return o.compact()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read outbox record at offset %d: %w", offset, err)
+		}
+
+		event, err := DecodeEvent(frame)
+		if err != nil {
+			// A record that cannot be decoded can never be delivered
+			o.logger.Error("Skipping unreadable outbox record", zap.Int64("offset", offset), zap.Error(err))
+		} else if err := o.deliver(event, frame); err != nil {
+			return err
+		}
+
+		offset += framePrefixSize + int64(len(frame))
+		if err := o.ack(offset); err != nil {
+			return err
+		}
+	}
+}
+
+// deliver hands event to the broker and the local subscribers, retrying with
+// backoff until all of them accept it. After MaxAttempts failures the event is
+// parked instead; targets that accepted it keep it. It returns an error if the
+// outbox closed first or the event could not be parked
+func (o *Outbox) deliver(event types.Event, payload []byte) error {
+	d := &delivery{
+		topic:     o.cfg.TopicPrefix + event.Type,
+		published: o.broker == nil,
+		handlers:  o.subscribers(event.Type),
+	}
+	d.handled = make([]bool, len(d.handlers))
+
+	backoff := o.cfg.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := d.try(o.ctx, o.broker, event, payload)
+		if err == nil {
+			return nil
+		}
+
+		if o.cfg.MaxAttempts > 0 && attempt >= o.cfg.MaxAttempts {
+			o.logger.Error("Event delivery failed, parking it",
+				zap.String("event_id", event.ID),
+				zap.String("event_type", event.Type),
+				zap.Int("attempts", attempt),
+				zap.Error(err),
+			)
+			return o.park(payload)
+		}
+
+		o.logger.Warn("Event delivery failed, retrying",
+			zap.String("event_id", event.ID),
+			zap.String("event_type", event.Type),
+			zap.Int("attempt", attempt),
+			zap.Error(err),
+		)
+
+		if !sleepContext(o.ctx, backoff) {
+			return o.ctx.Err()
+		}
+		backoff *= 2
+		if backoff > o.cfg.MaxRetryBackoff {
+			backoff = o.cfg.MaxRetryBackoff
+		}
+	}
+}
+
+// park appends an event that ran out of attempts to the dead letter file
+func (o *Outbox) park(payload []byte) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.journal.park(payload)
+}
+
+// subscribers returns the handlers an event of eventType is delivered to
+func (o *Outbox) subscribers(eventType string) []types.EventHandler {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var handlers []types.EventHandler
+	for _, sub := range o.handlers[eventType] {
+		handlers = append(handlers, sub.handler)
+	}
+	if eventType != AllEvents {
+		for _, sub := range o.handlers[AllEvents] {
+			handlers = append(handlers, sub.handler)
+		}
+	}
+	return handlers
+}
+
+// ack persists delivery up to offset and wakes Flush callers
+func (o *Outbox) ack(offset int64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err := o.journal.ack(offset); err != nil {
+		return err
+	}
+	o.pending--
+	close(o.delivered)
+	o.delivered = make(chan struct{})
+	return nil
+}
+
+// compact empties the log once everything in it was delivered and it has
+// grown past CompactSize
+func (o *Outbox) compact() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.journal.acked != o.journal.size || o.journal.size < o.cfg.CompactSize {
+		return nil
+	}
+	size := o.journal.size
+	if err := o.journal.reset(); err != nil {
+		return err
+	}
+	o.logger.Debug("Compacted outbox log", zap.Int64("bytes", size))
+	return nil
+}
+
+// delivery tracks which targets accepted one event, so retries skip them
+type delivery struct {
+	topic     string
+	published bool
+	handlers  []types.EventHandler
+	handled   []bool
+}
+
+// try delivers the event to every target that has not accepted it yet
+func (d *delivery) try(ctx context.Context, broker types.MessageBroker, event types.Event, payload []byte) error {
+	if !d.published {
+		if err := broker.Publish(ctx, d.topic, payload); err != nil {
+			return fmt.Errorf("failed to publish to topic %s: %w", d.topic, err)
+		}
+		d.published = true
+	}
+
+	var errs []error
+	for i, handler := range d.handlers {
+		if d.handled[i] {
+			continue
+		}
+		if err := handler(ctx, event); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		d.handled[i] = true
+	}
+	return errors.Join(errs...)
+}
+
+// sleepContext sleeps for d, returning false if ctx is done first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
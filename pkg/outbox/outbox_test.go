@@ -0,0 +1,411 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/transport/framing"
+)
+
+// fakeBroker records published messages, failing the first `failures` publishes
+type fakeBroker struct {
+	mu        sync.Mutex
+	failures  int
+	published []types.Message
+}
+
+func (b *fakeBroker) Publish(ctx context.Context, topic string, message []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures > 0 {
+		b.failures--
+		return errors.New("broker unavailable")
+	}
+	b.published = append(b.published, types.Message{Topic: topic, Data: message})
+	return nil
+}
+
+func (b *fakeBroker) Subscribe(ctx context.Context, topic string, handler types.MessageHandler) error {
+	return nil
+}
+
+func (b *fakeBroker) Unsubscribe(ctx context.Context, topic string) error {
+	return nil
+}
+
+func (b *fakeBroker) Close() error {
+	return nil
+}
+
+// events decodes the published messages
+func (b *fakeBroker) events(t *testing.T) []types.Event {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	events := make([]types.Event, 0, len(b.published))
+	for _, msg := range b.published {
+		event, err := DecodeEvent(msg.Data)
+		if err != nil {
+			t.Fatalf("Failed to decode published event: %v", err)
+		}
+		if msg.Topic != "events."+event.Type {
+			t.Errorf("Expected topic events.%s, got %s", event.Type, msg.Topic)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func testConfig() Config {
+	cfg := DefaultConfig()
+	cfg.RetryBackoff = time.Millisecond
+	cfg.MaxRetryBackoff = 5 * time.Millisecond
+	cfg.Sync = false
+	return cfg
+}
+
+func openTestOutbox(t *testing.T, dir string, broker types.MessageBroker, cfg Config) *Outbox {
+	t.Helper()
+	log, err := logger.NewDevelopment()
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	o, err := New(dir, broker, cfg, log)
+	if err != nil {
+		t.Fatalf("Failed to open outbox: %v", err)
+	}
+	return o
+}
+
+func flush(t *testing.T, o *Outbox) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := o.Flush(ctx); err != nil {
+		t.Fatalf("Failed to flush outbox: %v", err)
+	}
+}
+
+type order struct {
+	ID    int     `json:"id"`
+	Total float64 `json:"total"`
+}
+
+func TestEmitDeliversWithRetry(t *testing.T) {
+	dir := "tmp/test_deliver"
+	defer os.RemoveAll(dir)
+
+	broker := &fakeBroker{failures: 3}
+	o := openTestOutbox(t, dir, broker, testConfig())
+	defer o.Close()
+
+	ctx := context.Background()
+	for i := 1; i <= 5; i++ {
+		err := o.Emit(ctx, types.Event{
+			Type:     "order.created",
+			Source:   "orders",
+			Data:     order{ID: i, Total: float64(i) * 9.5},
+			Metadata: map[string]any{"attempt": i, "region": "eu"},
+		})
+		if err != nil {
+			t.Fatalf("Failed to emit event: %v", err)
+		}
+	}
+	flush(t, o)
+
+	events := broker.events(t)
+	if len(events) != 5 {
+		t.Fatalf("Expected 5 published events, got %d", len(events))
+	}
+	for i, event := range events {
+		var got order
+		if err := UnmarshalData(event, &got); err != nil {
+			t.Fatalf("Failed to unmarshal event data: %v", err)
+		}
+		if got.ID != i+1 || event.ID == "" || event.Source != "orders" || event.Timestamp.IsZero() {
+			t.Errorf("Event %d out of order or incomplete: %+v %+v", i, event, got)
+		}
+		if event.Metadata["region"] != "eu" || event.Metadata["attempt"] != float64(i+1) {
+			t.Errorf("Unexpected metadata: %v", event.Metadata)
+		}
+	}
+	if o.Pending() != 0 {
+		t.Errorf("Expected no pending events, got %d", o.Pending())
+	}
+
+	if err := o.Emit(ctx, types.Event{}); err == nil {
+		t.Error("Expected error emitting an event without a type")
+	}
+
+	// Events without a timestamp are stamped from the outbox's clock
+	o.WithClock(testutil.NewDefaultFakeClock())
+	if err := o.Emit(ctx, types.Event{Type: "order.shipped"}); err != nil {
+		t.Fatalf("Failed to emit event: %v", err)
+	}
+	flush(t, o)
+	if events := broker.events(t); len(events) != 6 || !events[5].Timestamp.Equal(testutil.DefaultFakeTime) {
+		t.Errorf("Expected the event stamped at %s, got %+v", testutil.DefaultFakeTime, events)
+	}
+
+	t.Log("✓ Events are delivered in order after transient broker failures")
+}
+
+func TestRedeliveryAfterRestart(t *testing.T) {
+	dir := "tmp/test_restart"
+	defer os.RemoveAll(dir)
+
+	// The broker is down for the whole first run
+	down := &fakeBroker{failures: 1 << 30}
+	cfg := testConfig()
+	cfg.MaxAttempts = -1
+	o := openTestOutbox(t, dir, down, cfg)
+	for i := 0; i < 3; i++ {
+		if err := o.Emit(context.Background(), types.Event{Type: "user.updated", Data: i}); err != nil {
+			t.Fatalf("Failed to emit event: %v", err)
+		}
+	}
+	if err := o.Close(); err != nil {
+		t.Fatalf("Failed to close outbox: %v", err)
+	}
+	if err := o.Emit(context.Background(), types.Event{Type: "user.updated"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+
+	// A crash mid-append leaves a torn frame at the end of the log
+	log, err := os.OpenFile(filepath.Join(dir, logFile), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open log: %v", err)
+	}
+	log.Write([]byte{0, 0, 1, 0, 'x'})
+	log.Close()
+
+	up := &fakeBroker{}
+	o = openTestOutbox(t, dir, up, testConfig())
+	defer o.Close()
+	if err := o.Emit(context.Background(), types.Event{Type: "user.updated", Data: 3}); err != nil {
+		t.Fatalf("Failed to emit after restart: %v", err)
+	}
+	flush(t, o)
+
+	events := up.events(t)
+	if len(events) != 4 {
+		t.Fatalf("Expected 4 events after restart, got %d", len(events))
+	}
+	for i, event := range events {
+		var n int
+		if err := UnmarshalData(event, &n); err != nil || n != i {
+			t.Errorf("Expected event data %d, got %d (%v)", i, n, err)
+		}
+	}
+
+	t.Log("✓ Undelivered events survive a restart and a torn append")
+}
+
+func TestExhaustedEventsAreParked(t *testing.T) {
+	dir := "tmp/test_park"
+	defer os.RemoveAll(dir)
+
+	cfg := testConfig()
+	cfg.MaxAttempts = 3
+	broker := &fakeBroker{failures: 3}
+	o := openTestOutbox(t, dir, broker, cfg)
+	defer o.Close()
+
+	for i := 0; i < 2; i++ {
+		if err := o.Emit(context.Background(), types.Event{ID: fmt.Sprintf("evt-%d", i), Type: "order.created", Data: i}); err != nil {
+			t.Fatalf("Failed to emit event: %v", err)
+		}
+	}
+	flush(t, o)
+
+	// The first event fails all its attempts and is parked; the second gets through
+	parked, err := ReadParked(dir)
+	if err != nil {
+		t.Fatalf("Failed to read parked events: %v", err)
+	}
+	if len(parked) != 1 || parked[0].ID != "evt-0" {
+		t.Fatalf("Expected evt-0 to be parked, got %+v", parked)
+	}
+	if events := broker.events(t); len(events) != 1 || events[0].ID != "evt-1" {
+		t.Errorf("Expected only evt-1 to be published, got %+v", events)
+	}
+	if empty, err := ReadParked(filepath.Join(dir, "unused")); err != nil || empty != nil {
+		t.Errorf("Expected no parked events in an unused directory, got %v (%v)", empty, err)
+	}
+
+	t.Log("✓ Events that run out of attempts are parked and delivery moves on")
+}
+
+func TestRecoverRejectsUnreadableLog(t *testing.T) {
+	dir := "tmp/test_recover_oversized"
+	defer os.RemoveAll(dir)
+
+	down := &fakeBroker{failures: 1 << 30}
+	cfg := testConfig()
+	cfg.MaxAttempts = -1
+	o := openTestOutbox(t, dir, down, cfg)
+	for i := 0; i < 3; i++ {
+		if err := o.Emit(context.Background(), types.Event{Type: "user.updated", Data: i}); err != nil {
+			t.Fatalf("Failed to emit event: %v", err)
+		}
+	}
+	if err := o.Close(); err != nil {
+		t.Fatalf("Failed to close outbox: %v", err)
+	}
+	before, err := os.Stat(filepath.Join(dir, logFile))
+	if err != nil {
+		t.Fatalf("Failed to stat log: %v", err)
+	}
+
+	// Records larger than MaxRecordSize fail the open instead of truncating the log
+	small := cfg
+	small.MaxRecordSize = 16
+	if _, err := New(dir, down, small, nil); !errors.Is(err, framing.ErrFrameTooLarge) {
+		t.Fatalf("Expected an oversized record to fail the open, got %v", err)
+	}
+	after, err := os.Stat(filepath.Join(dir, logFile))
+	if err != nil || after.Size() != before.Size() {
+		t.Fatalf("Expected the log to be left at %d bytes, got %v (%v)", before.Size(), after, err)
+	}
+
+	up := &fakeBroker{}
+	o = openTestOutbox(t, dir, up, testConfig())
+	defer o.Close()
+	flush(t, o)
+	if n := len(up.events(t)); n != 3 {
+		t.Errorf("Expected all 3 events once the limit allows them, got %d", n)
+	}
+
+	t.Log("✓ An unreadable record fails recovery and keeps the log")
+}
+
+func TestAckSurvivesRestart(t *testing.T) {
+	dir := "tmp/test_ack"
+	defer os.RemoveAll(dir)
+
+	broker := &fakeBroker{}
+	o := openTestOutbox(t, dir, broker, testConfig())
+	if err := o.Emit(context.Background(), types.Event{Type: "product.created"}); err != nil {
+		t.Fatalf("Failed to emit event: %v", err)
+	}
+	flush(t, o)
+	o.Close()
+
+	o = openTestOutbox(t, dir, broker, testConfig())
+	if err := o.Emit(context.Background(), types.Event{Type: "product.deleted"}); err != nil {
+		t.Fatalf("Failed to emit event: %v", err)
+	}
+	flush(t, o)
+	o.Close()
+
+	events := broker.events(t)
+	if len(events) != 2 || events[0].Type != "product.created" || events[1].Type != "product.deleted" {
+		t.Errorf("Expected each event delivered once, got %d events", len(events))
+	}
+
+	t.Log("✓ Delivered events are not redelivered after a restart")
+}
+
+func TestLocalSubscribers(t *testing.T) {
+	dir := "tmp/test_subscribers"
+	defer os.RemoveAll(dir)
+
+	o := openTestOutbox(t, dir, nil, testConfig())
+	defer o.Close()
+
+	var mu sync.Mutex
+	var got []string
+	failOnce := true
+	record := func(ctx context.Context, event types.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, event.Type)
+		return nil
+	}
+	flaky := func(ctx context.Context, event types.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if failOnce {
+			failOnce = false
+			return errors.New("temporary failure")
+		}
+		got = append(got, "flaky:"+event.Type)
+		return nil
+	}
+
+	ctx := context.Background()
+	o.Subscribe(ctx, "user.created", record)
+	o.Subscribe(ctx, AllEvents, flaky)
+
+	o.Emit(ctx, types.Event{Type: "user.created"})
+	flush(t, o)
+
+	if err := o.Unsubscribe(ctx, "user.created", record); err != nil {
+		t.Fatalf("Failed to unsubscribe: %v", err)
+	}
+	if err := o.Unsubscribe(ctx, "user.created", record); err == nil {
+		t.Error("Expected error unsubscribing twice")
+	}
+	o.Emit(ctx, types.Event{Type: "user.created"})
+	flush(t, o)
+
+	mu.Lock()
+	defer mu.Unlock()
+	// The failing handler is retried without redelivering to the one that succeeded
+	want := []string{"user.created", "flaky:user.created", "flaky:user.created"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	t.Log("✓ Local subscribers receive events and failed handlers are retried alone")
+}
+
+func TestCompaction(t *testing.T) {
+	dir := "tmp/test_compaction"
+	defer os.RemoveAll(dir)
+
+	cfg := testConfig()
+	cfg.CompactSize = 256
+	broker := &fakeBroker{}
+	o := openTestOutbox(t, dir, broker, cfg)
+	defer o.Close()
+
+	for i := 0; i < 20; i++ {
+		o.Emit(context.Background(), types.Event{Type: "analytics.tracked", Data: i})
+	}
+	flush(t, o)
+
+	// The dispatcher compacts after its last ack, so give it a moment
+	deadline := time.Now().Add(time.Second)
+	for {
+		info, err := os.Stat(filepath.Join(dir, logFile))
+		if err != nil {
+			t.Fatalf("Failed to stat log: %v", err)
+		}
+		if info.Size() == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the delivered log to be compacted, size %d", info.Size())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	o.Emit(context.Background(), types.Event{Type: "analytics.tracked", Data: 20})
+	flush(t, o)
+	if n := len(broker.events(t)); n != 21 {
+		t.Errorf("Expected 21 events, got %d", n)
+	}
+
+	t.Log("✓ A fully delivered log is compacted")
+}
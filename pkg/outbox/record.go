@@ -0,0 +1,124 @@
+package outbox
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hamba/avro/v2"
+
+	"go-transport-prac/internal/types"
+)
+
+// recordSchema is the Avro schema of an outbox record. Event data and metadata
+// values are free-form, so they are stored as JSON
+const recordSchema = `{
+  "type": "record",
+  "name": "OutboxEvent",
+  "namespace": "com.example.avro",
+  "fields": [
+    {"name": "id", "type": "string"},
+    {"name": "type", "type": "string"},
+    {"name": "source", "type": "string"},
+    {"name": "timestamp", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+    {"name": "data", "type": "bytes", "doc": "JSON-encoded event data"},
+    {"name": "metadata", "type": {"type": "map", "values": "string"}, "doc": "JSON-encoded metadata values"}
+  ]
+}`
+
+var schema = avro.MustParse(recordSchema)
+
+// record is the Avro form of a types.Event
+type record struct {
+	ID        string            `avro:"id"`
+	Type      string            `avro:"type"`
+	Source    string            `avro:"source"`
+	Timestamp time.Time         `avro:"timestamp"`
+	Data      []byte            `avro:"data"`
+	Metadata  map[string]string `avro:"metadata"`
+}
+
+// Schema returns the Avro schema of the records the outbox stores and publishes
+func Schema() avro.Schema {
+	return schema
+}
+
+// EncodeEvent serializes an event as an outbox record, the payload published to the broker
+func EncodeEvent(event types.Event) ([]byte, error) {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event data: %w", err)
+	}
+
+	metadata := make(map[string]string, len(event.Metadata))
+	for key, value := range event.Metadata {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal event metadata %s: %w", key, err)
+		}
+		metadata[key] = string(encoded)
+	}
+
+	payload, err := avro.Marshal(schema, record{
+		ID:        event.ID,
+		Type:      event.Type,
+		Source:    event.Source,
+		Timestamp: event.Timestamp,
+		Data:      data,
+		Metadata:  metadata,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize event %s: %w", event.ID, err)
+	}
+	return payload, nil
+}
+
+// DecodeEvent deserializes an outbox record. Data is returned as a
+// json.RawMessage for UnmarshalData; metadata values are decoded as JSON
+func DecodeEvent(payload []byte) (types.Event, error) {
+	var r record
+	if err := avro.Unmarshal(schema, payload, &r); err != nil {
+		return types.Event{}, fmt.Errorf("failed to deserialize event: %w", err)
+	}
+
+	metadata := make(map[string]any, len(r.Metadata))
+	for key, encoded := range r.Metadata {
+		var value any
+		if err := json.Unmarshal([]byte(encoded), &value); err != nil {
+			return types.Event{}, fmt.Errorf("failed to unmarshal event metadata %s: %w", key, err)
+		}
+		metadata[key] = value
+	}
+
+	return types.Event{
+		ID:        r.ID,
+		Type:      r.Type,
+		Source:    r.Source,
+		Data:      json.RawMessage(r.Data),
+		Timestamp: r.Timestamp,
+		Metadata:  metadata,
+	}, nil
+}
+
+// UnmarshalData decodes the data of an event delivered by the outbox into v
+func UnmarshalData(event types.Event, v any) error {
+	raw, ok := event.Data.(json.RawMessage)
+	if !ok {
+		return fmt.Errorf("event %s data is %T, not an outbox record", event.ID, event.Data)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("failed to unmarshal event %s data: %w", event.ID, err)
+	}
+	return nil
+}
+
+// newEventID returns a random event ID
+func newEventID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
@@ -0,0 +1,408 @@
+// Package redaction masks, hashes or tokenizes personal data in records before
+// they are serialized, so exports in any format can leave the trusted zone.
+// Fields are marked with a `pii` struct tag naming their category, such as
+// pii:"email" or pii:"phone", and a Redactor maps categories to strategies.
+// A tag may pin a strategy for its field: pii:"email,hash".
+package redaction
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Strategy is how a category of personal data is redacted
+type Strategy string
+
+const (
+	// StrategyMask hides most of a value but keeps its shape, e.g. j***@example.com
+	StrategyMask Strategy = "mask"
+	// StrategyHash replaces a value with a keyed hash, so equal values still
+	// join and count alike but cannot be read back
+	StrategyHash Strategy = "hash"
+	// StrategyTokenize replaces a value with a token the TokenStore can
+	// exchange back for it
+	StrategyTokenize Strategy = "tokenize"
+	// StrategyDrop clears a value
+	StrategyDrop Strategy = "drop"
+	// StrategyKeep leaves a value as it is
+	StrategyKeep Strategy = "keep"
+)
+
+// TagName is the struct tag fields are marked with
+const TagName = "pii"
+
+// hashSize is the number of hash bytes kept, hex encoded
+const hashSize = 16
+
+// Redactor applies strategies to the fields tagged with TagName. It is safe
+// for concurrent use once configured
+type Redactor struct {
+	defaultStrategy Strategy
+	rules           map[string]Strategy
+	secret          []byte
+	tokens          TokenStore
+}
+
+// New returns a redactor masking every category
+func New() *Redactor {
+	return &Redactor{defaultStrategy: StrategyMask, rules: make(map[string]Strategy)}
+}
+
+// WithDefault sets the strategy for categories without a rule
+func (r *Redactor) WithDefault(strategy Strategy) *Redactor {
+	r.defaultStrategy = strategy
+	return r
+}
+
+// WithRule sets the strategy for one category
+func (r *Redactor) WithRule(category string, strategy Strategy) *Redactor {
+	r.rules[strings.ToLower(category)] = strategy
+	return r
+}
+
+// WithSecret sets the key of StrategyHash. Without a key, hashes of values
+// with few possibilities, such as phone numbers, could be reversed by
+// hashing every candidate, so hashing fails until one is set
+func (r *Redactor) WithSecret(secret []byte) *Redactor {
+	r.secret = append([]byte(nil), secret...)
+	return r
+}
+
+// WithTokenStore sets the store StrategyTokenize exchanges values with
+func (r *Redactor) WithTokenStore(store TokenStore) *Redactor {
+	r.tokens = store
+	return r
+}
+
+// Redact returns a copy of v with every tagged field redacted. v itself,
+// including what its pointers, slices and maps refer to, is left unchanged
+func Redact[T any](ctx context.Context, r *Redactor, v T) (T, error) {
+	out, err := r.walk(ctx, reflect.ValueOf(&v).Elem(), nil, r.redact)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return out.Interface().(T), nil
+}
+
+// RedactAll returns redacted copies of values
+func RedactAll[T any](ctx context.Context, r *Redactor, values []T) ([]T, error) {
+	out := make([]T, len(values))
+	for i, v := range values {
+		redacted, err := Redact(ctx, r, v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to redact record %d: %w", i, err)
+		}
+		out[i] = redacted
+	}
+	return out, nil
+}
+
+// Restore returns a copy of v with every tokenized field exchanged back for
+// its original value through the token store
+func Restore[T any](ctx context.Context, r *Redactor, v T) (T, error) {
+	out, err := r.walk(ctx, reflect.ValueOf(&v).Elem(), nil, r.restore)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return out.Interface().(T), nil
+}
+
+// rule is the category of a tagged field and the strategy applied to it
+type rule struct {
+	category string
+	strategy Strategy
+	// inherited rules come from a tagged struct, map or slice enclosing the
+	// value rather than from the value's own field
+	inherited bool
+}
+
+// ruleFor parses the tag of a field
+func (r *Redactor) ruleFor(tag string) (*rule, error) {
+	category, strategy, _ := strings.Cut(tag, ",")
+	category = strings.ToLower(strings.TrimSpace(category))
+	if category == "" {
+		return nil, fmt.Errorf("pii tag %q has no category", tag)
+	}
+
+	s := Strategy(strings.TrimSpace(strategy))
+	if s == "" {
+		s = r.defaultStrategy
+		if ruled, ok := r.rules[category]; ok {
+			s = ruled
+		}
+	}
+	switch s {
+	case StrategyMask, StrategyHash, StrategyTokenize, StrategyDrop, StrategyKeep:
+	default:
+		return nil, fmt.Errorf("unknown redaction strategy %q for %s", s, category)
+	}
+	return &rule{category: category, strategy: s}, nil
+}
+
+// transform rewrites one string under a rule
+type transform func(ctx context.Context, rl *rule, s string) (string, error)
+
+// walk returns a copy of v, sharing no pointers, slices or maps with it,
+// with fn applied to every string under a rule
+func (r *Redactor) walk(ctx context.Context, v reflect.Value, rl *rule, fn transform) (reflect.Value, error) {
+	out := reflect.New(v.Type()).Elem()
+	if rl != nil && rl.strategy == StrategyKeep {
+		out.Set(v)
+		return out, nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if rl == nil {
+			out.Set(v)
+			return out, nil
+		}
+		s, err := fn(ctx, rl, v.String())
+		if err != nil {
+			return out, err
+		}
+		out.SetString(s)
+
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return out, nil
+		}
+		if v.Kind() == reflect.Interface {
+			elem, err := r.walk(ctx, v.Elem(), rl, fn)
+			if err != nil {
+				return out, err
+			}
+			out.Set(elem)
+			return out, nil
+		}
+		if dropped(rl, v.Elem()) {
+			return out, nil
+		}
+		elem, err := r.walk(ctx, v.Elem(), rl, fn)
+		if err != nil {
+			return out, err
+		}
+		ptr := reflect.New(v.Type().Elem())
+		ptr.Elem().Set(elem)
+		out.Set(ptr)
+
+	case reflect.Struct:
+		out.Set(v)
+		if dropped(rl, v) {
+			out.SetZero()
+			return out, nil
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldRule := inherit(rl)
+			if tag, ok := field.Tag.Lookup(TagName); ok {
+				parsed, err := r.ruleFor(tag)
+				if err != nil {
+					return out, fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+				}
+				fieldRule = parsed
+			}
+			value, err := r.walk(ctx, v.Field(i), fieldRule, fn)
+			if err != nil {
+				return out, fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+			}
+			out.Field(i).Set(value)
+		}
+
+	case reflect.Slice:
+		if v.IsNil() || dropped(rl, v) {
+			return out, nil
+		}
+		slice := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			elem, err := r.walk(ctx, v.Index(i), inherit(rl), fn)
+			if err != nil {
+				return out, err
+			}
+			slice.Index(i).Set(elem)
+		}
+		out.Set(slice)
+
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			elem, err := r.walk(ctx, v.Index(i), inherit(rl), fn)
+			if err != nil {
+				return out, err
+			}
+			out.Index(i).Set(elem)
+		}
+
+	case reflect.Map:
+		if v.IsNil() || dropped(rl, v) {
+			return out, nil
+		}
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			elem, err := r.walk(ctx, iter.Value(), inherit(rl), fn)
+			if err != nil {
+				return out, err
+			}
+			m.SetMapIndex(iter.Key(), elem)
+		}
+		out.Set(m)
+
+	default:
+		// Numbers, booleans and times under their own tag cannot be masked
+		// or hashed in place, so anything but keep clears them
+		if rl == nil || rl.inherited {
+			out.Set(v)
+		}
+	}
+	return out, nil
+}
+
+// inherit passes a rule down to the values inside a tagged field
+func inherit(rl *rule) *rule {
+	if rl == nil || rl.inherited {
+		return rl
+	}
+	inherited := *rl
+	inherited.inherited = true
+	return &inherited
+}
+
+// dropped reports whether a value is cleared as a whole: a field tagged with
+// StrategyDrop, or a tagged field holding no strings, such as a time
+func dropped(rl *rule, v reflect.Value) bool {
+	if rl == nil || rl.inherited {
+		return false
+	}
+	return rl.strategy == StrategyDrop || !holdsStrings(v.Type())
+}
+
+// holdsStrings reports whether values of t contain strings a strategy can rewrite
+func holdsStrings(t reflect.Type) bool {
+	return holdsStringsSeen(t, make(map[reflect.Type]bool))
+}
+
+var holdsStringsCache sync.Map
+
+func holdsStringsSeen(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if cached, ok := holdsStringsCache.Load(t); ok {
+		return cached.(bool)
+	}
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	var holds bool
+	switch t.Kind() {
+	case reflect.String, reflect.Interface:
+		holds = true
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		holds = holdsStringsSeen(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField() && !holds; i++ {
+			holds = t.Field(i).IsExported() && holdsStringsSeen(t.Field(i).Type, seen)
+		}
+	}
+	holdsStringsCache.Store(t, holds)
+	return holds
+}
+
+// redact applies the strategy of rl to s
+func (r *Redactor) redact(ctx context.Context, rl *rule, s string) (string, error) {
+	if s == "" {
+		return s, nil
+	}
+	switch rl.strategy {
+	case StrategyMask:
+		return Mask(rl.category, s), nil
+	case StrategyHash:
+		if len(r.secret) == 0 {
+			return "", fmt.Errorf("hashing %s needs a secret, see WithSecret", rl.category)
+		}
+		mac := hmac.New(sha256.New, r.secret)
+		mac.Write([]byte(rl.category + ":" + s))
+		return hex.EncodeToString(mac.Sum(nil)[:hashSize]), nil
+	case StrategyTokenize:
+		if r.tokens == nil {
+			return "", fmt.Errorf("tokenizing %s needs a token store, see WithTokenStore", rl.category)
+		}
+		token, err := r.tokens.Tokenize(ctx, rl.category, s)
+		if err != nil {
+			return "", fmt.Errorf("failed to tokenize %s: %w", rl.category, err)
+		}
+		return token, nil
+	case StrategyDrop:
+		return "", nil
+	}
+	return s, nil
+}
+
+// restore exchanges the tokens of tokenized fields back for their values
+func (r *Redactor) restore(ctx context.Context, rl *rule, s string) (string, error) {
+	if rl.strategy != StrategyTokenize || s == "" {
+		return s, nil
+	}
+	if r.tokens == nil {
+		return "", fmt.Errorf("restoring %s needs a token store, see WithTokenStore", rl.category)
+	}
+	value, err := r.tokens.Detokenize(ctx, s)
+	if err != nil {
+		return "", fmt.Errorf("failed to restore %s: %w", rl.category, err)
+	}
+	return value, nil
+}
+
+// Mask hides most of s. Emails keep the first letter and the domain, phone
+// numbers their last four digits and formatting, and anything else its
+// first letter; the masked part does not reveal its length
+func Mask(category, s string) string {
+	switch category {
+	case "email":
+		if local, domain, ok := strings.Cut(s, "@"); ok && local != "" {
+			return firstRune(local) + "***@" + domain
+		}
+	case "phone":
+		return maskDigits(s, 4)
+	}
+	return firstRune(s) + "***"
+}
+
+func firstRune(s string) string {
+	_, size := utf8.DecodeRuneInString(s)
+	return s[:size]
+}
+
+// maskDigits replaces every digit but the last keep with *
+func maskDigits(s string, keep int) string {
+	digits := 0
+	for _, c := range s {
+		if unicode.IsDigit(c) {
+			digits++
+		}
+	}
+	var b strings.Builder
+	for _, c := range s {
+		if unicode.IsDigit(c) {
+			digits--
+			if digits >= keep {
+				c = '*'
+			}
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
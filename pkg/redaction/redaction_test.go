@@ -0,0 +1,221 @@
+package redaction
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/yaml"
+)
+
+func testUser() avro.User {
+	phone := "+1 (555) 123-4567"
+	return avro.User{
+		ID:     1,
+		Email:  "jane.doe@example.com",
+		Name:   "Jane Doe",
+		Status: avro.UserStatusActive,
+		Profile: &avro.Profile{
+			FirstName: "Jane",
+			LastName:  "Doe",
+			Phone:     &phone,
+			Address: &avro.Address{
+				Street:     "1 Main St",
+				City:       "Springfield",
+				PostalCode: "12345",
+			},
+			Interests: []string{"go"},
+		},
+		CreatedAt: time.Unix(1700000000, 0).UTC(),
+	}
+}
+
+func TestMask(t *testing.T) {
+	ctx := context.Background()
+	user := testUser()
+
+	got, err := Redact(ctx, New(), user)
+	if err != nil {
+		t.Fatalf("Failed to redact: %v", err)
+	}
+
+	expected := map[string][2]string{
+		"email":  {got.Email, "j***@example.com"},
+		"name":   {got.Name, "J***"},
+		"phone":  {*got.Profile.Phone, "+* (***) ***-4567"},
+		"street": {got.Profile.Address.Street, "1***"},
+		"city":   {got.Profile.Address.City, "Springfield"},
+	}
+	for field, pair := range expected {
+		if pair[0] != pair[1] {
+			t.Errorf("%s: expected %q, got %q", field, pair[1], pair[0])
+		}
+	}
+	if got.ID != user.ID || got.Status != user.Status || !got.CreatedAt.Equal(user.CreatedAt) {
+		t.Errorf("Expected untagged fields to be kept, got %+v", got)
+	}
+
+	// The original and everything it points to is untouched
+	if user.Email != "jane.doe@example.com" || *user.Profile.Phone != "+1 (555) 123-4567" || user.Profile.Address.Street != "1 Main St" {
+		t.Errorf("Expected the original to be unchanged, got %+v", user)
+	}
+	if got.Profile == user.Profile || got.Profile.Phone == user.Profile.Phone {
+		t.Error("Expected the copy not to share pointers with the original")
+	}
+
+	t.Log("✓ Masking hides personal data and leaves the original as it was")
+}
+
+func TestHash(t *testing.T) {
+	ctx := context.Background()
+	r := New().WithDefault(StrategyHash).WithSecret([]byte("pepper"))
+
+	first, err := Redact(ctx, r, testUser())
+	if err != nil {
+		t.Fatalf("Failed to redact: %v", err)
+	}
+	second, err := Redact(ctx, r, testUser())
+	if err != nil {
+		t.Fatalf("Failed to redact: %v", err)
+	}
+	if first.Email != second.Email || len(first.Email) != 2*hashSize || strings.Contains(first.Email, "jane") {
+		t.Errorf("Expected equal values to hash alike, got %q and %q", first.Email, second.Email)
+	}
+	if first.Profile.FirstName == first.Profile.LastName {
+		t.Error("Expected different values to hash differently")
+	}
+
+	other, _ := Redact(ctx, New().WithDefault(StrategyHash).WithSecret([]byte("salt")), testUser())
+	if other.Email == first.Email {
+		t.Error("Expected a different secret to give a different hash")
+	}
+	if _, err := Redact(ctx, New().WithDefault(StrategyHash), testUser()); err == nil {
+		t.Error("Expected hashing without a secret to fail")
+	}
+
+	t.Log("✓ Hashing is keyed and deterministic")
+}
+
+func TestTokenizeAndRestore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTokenStore()
+	r := New().WithRule("email", StrategyTokenize).WithRule("phone", StrategyTokenize).WithTokenStore(store)
+
+	users := []avro.User{testUser(), testUser()}
+	got, err := RedactAll(ctx, r, users)
+	if err != nil {
+		t.Fatalf("Failed to redact: %v", err)
+	}
+	if !strings.HasPrefix(got[0].Email, tokenPrefix) || got[0].Email != got[1].Email {
+		t.Errorf("Expected the same token for the same email, got %q and %q", got[0].Email, got[1].Email)
+	}
+	if got[0].Name != "J***" {
+		t.Errorf("Expected names to fall back to masking, got %q", got[0].Name)
+	}
+	if store.Len() != 2 {
+		t.Errorf("Expected 2 tokens, got %d", store.Len())
+	}
+
+	restored, err := Restore(ctx, r, got[0])
+	if err != nil {
+		t.Fatalf("Failed to restore: %v", err)
+	}
+	if restored.Email != users[0].Email || *restored.Profile.Phone != *users[0].Profile.Phone {
+		t.Errorf("Expected tokenized fields to be restored, got %q and %q", restored.Email, *restored.Profile.Phone)
+	}
+	if restored.Name != "J***" {
+		t.Errorf("Expected masked fields to stay masked, got %q", restored.Name)
+	}
+
+	if _, err := store.Detokenize(ctx, "tok_unknown"); err == nil {
+		t.Error("Expected an unknown token to fail")
+	}
+	if _, err := Redact(ctx, New().WithDefault(StrategyTokenize), testUser()); err == nil {
+		t.Error("Expected tokenizing without a store to fail")
+	}
+
+	t.Log("✓ Tokens are stable and exchange back for their values")
+}
+
+type tagged struct {
+	Email    string            `pii:"email,keep"`
+	Notes    []string          `pii:"free_text"`
+	Contacts map[string]string `pii:"phone,drop"`
+	Location *float64          `pii:"location"`
+	SeenAt   time.Time         `pii:"activity"`
+	Count    int
+	Nested   any
+	private  string
+}
+
+func TestTagsAndRules(t *testing.T) {
+	ctx := context.Background()
+	lat := 51.5
+	v := tagged{
+		Email:    "a@b.c",
+		Notes:    []string{"call Bob", "Alice"},
+		Contacts: map[string]string{"home": "555-0100"},
+		Location: &lat,
+		SeenAt:   time.Now(),
+		Count:    3,
+		Nested:   &avro.User{Email: "x@y.z"},
+		private:  "kept",
+	}
+
+	got, err := Redact(ctx, New().WithRule("email", StrategyDrop), v)
+	if err != nil {
+		t.Fatalf("Failed to redact: %v", err)
+	}
+	if got.Email != "a@b.c" {
+		t.Errorf("Expected the tag strategy to win over rules, got %q", got.Email)
+	}
+	if len(got.Notes) != 2 || got.Notes[0] != "c***" || got.Notes[1] != "A***" {
+		t.Errorf("Expected every element of a tagged slice to be masked, got %v", got.Notes)
+	}
+	if got.Contacts != nil || got.Location != nil || !got.SeenAt.IsZero() {
+		t.Errorf("Expected dropped and non-string fields to be cleared, got %+v", got)
+	}
+	if got.Count != 3 || got.private != "kept" {
+		t.Errorf("Expected untagged fields to be kept, got %+v", got)
+	}
+	if nested := got.Nested.(*avro.User); nested.Email != "" {
+		t.Errorf("Expected records behind interfaces to follow rules, got %q", nested.Email)
+	}
+
+	type badTag struct {
+		Email string `pii:"email,scramble"`
+	}
+	if _, err := Redact(ctx, New(), badTag{Email: "a@b.c"}); err == nil || !strings.Contains(err.Error(), "badTag.Email") {
+		t.Errorf("Expected an unknown strategy to fail naming the field, got %v", err)
+	}
+
+	t.Log("✓ Tags, rules and defaults apply in order")
+}
+
+func TestSerializer(t *testing.T) {
+	s := NewSerializer(yaml.NewManager("tmp/test_redaction"), New())
+	user := testUser()
+
+	data, err := s.Serialize(&user)
+	if err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+	if strings.Contains(string(data), "jane.doe") || !strings.Contains(string(data), "j***@example.com") {
+		t.Errorf("Expected redacted output, got:\n%s", data)
+	}
+	if user.Email != "jane.doe@example.com" {
+		t.Error("Expected serializing not to change the record")
+	}
+
+	var back avro.User
+	if err := s.Deserialize(data, &back); err != nil {
+		t.Fatalf("Failed to deserialize: %v", err)
+	}
+	if back.Email != "j***@example.com" || s.FileExtension() != yaml.FileExtension {
+		t.Errorf("Unexpected round trip %q with extension %q", back.Email, s.FileExtension())
+	}
+
+	t.Log("✓ Any serializer writes redacted records")
+}
@@ -0,0 +1,47 @@
+package redaction
+
+import (
+	"context"
+	"fmt"
+
+	"go-transport-prac/internal/types"
+)
+
+// Serializer redacts data before handing it to another serializer, so any
+// format can write PII-safe output
+type Serializer struct {
+	inner    types.Serializer
+	redactor *Redactor
+}
+
+// NewSerializer returns a serializer redacting with r before serializing with inner
+func NewSerializer(inner types.Serializer, r *Redactor) *Serializer {
+	return &Serializer{inner: inner, redactor: r}
+}
+
+// Serialize redacts data and serializes the copy
+func (s *Serializer) Serialize(data any) ([]byte, error) {
+	redacted, err := Redact(context.Background(), s.redactor, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to redact data: %w", err)
+	}
+	return s.inner.Serialize(redacted)
+}
+
+// Deserialize deserializes data as it is; redacted values cannot be recovered
+// here, see Restore for tokenized fields
+func (s *Serializer) Deserialize(data []byte, target any) error {
+	return s.inner.Deserialize(data, target)
+}
+
+// ContentType returns the content type of the inner serializer
+func (s *Serializer) ContentType() string {
+	return s.inner.ContentType()
+}
+
+// FileExtension returns the file extension of the inner serializer
+func (s *Serializer) FileExtension() string {
+	return s.inner.FileExtension()
+}
+
+var _ types.Serializer = (*Serializer)(nil)
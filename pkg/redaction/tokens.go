@@ -0,0 +1,88 @@
+package redaction
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+)
+
+// TokenStore exchanges values for tokens and back. Implementations backed by
+// a vault or database let tokenized exports be re-identified by whoever holds
+// access to the store, and by no one else
+type TokenStore interface {
+	// Tokenize returns the token of value, the same token every time the
+	// same value of a category is tokenized
+	Tokenize(ctx context.Context, category, value string) (string, error)
+	// Detokenize returns the value a token was issued for
+	Detokenize(ctx context.Context, token string) (string, error)
+}
+
+// tokenPrefix marks tokens issued by MemoryTokenStore
+const tokenPrefix = "tok_"
+
+// MemoryTokenStore is a TokenStore holding its tokens in memory. Tokens are
+// random, so they reveal nothing of their values
+type MemoryTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[string]string
+	values map[string]string
+}
+
+// NewMemoryTokenStore returns an empty store
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{
+		tokens: make(map[string]string),
+		values: make(map[string]string),
+	}
+}
+
+// Tokenize implements TokenStore
+func (s *MemoryTokenStore) Tokenize(_ context.Context, category, value string) (string, error) {
+	key := category + "\x00" + value
+
+	s.mu.RLock()
+	token, ok := s.tokens[key]
+	s.mu.RUnlock()
+	if ok {
+		return token, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if token, ok := s.tokens[key]; ok {
+		return token, nil
+	}
+	for {
+		buf := make([]byte, 12)
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("failed to generate token: %w", err)
+		}
+		token = tokenPrefix + hex.EncodeToString(buf)
+		if _, taken := s.values[token]; !taken {
+			break
+		}
+	}
+	s.tokens[key] = token
+	s.values[token] = value
+	return token, nil
+}
+
+// Detokenize implements TokenStore
+func (s *MemoryTokenStore) Detokenize(_ context.Context, token string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[token]
+	if !ok {
+		return "", fmt.Errorf("unknown token %q", token)
+	}
+	return value, nil
+}
+
+// Len returns the number of tokens issued
+func (s *MemoryTokenStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.values)
+}
@@ -0,0 +1,134 @@
+package arrow
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/memory"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/parquet"
+)
+
+func sampleUsers(n int) []parquet.User {
+	now := testutil.DefaultFakeTime
+	users := make([]parquet.User, n)
+	for i := range users {
+		users[i] = parquet.User{
+			ID:        int64(i + 1),
+			Email:     fmt.Sprintf("user%d@example.com", i+1),
+			Name:      fmt.Sprintf("User %d", i+1),
+			Status:    "active",
+			CreatedAt: now.Add(time.Duration(i) * time.Nanosecond),
+			UpdatedAt: now.Add(time.Duration(i) * time.Hour),
+		}
+		switch i % 3 {
+		case 0:
+			users[i].Profile = &parquet.Profile{
+				FirstName: "First",
+				LastName:  "Last",
+				Phone:     "+1-555-0100",
+				Address:   &parquet.Address{Street: "1 Main St", City: "Taipei", State: "TP", PostalCode: "100", Country: "TW"},
+				Interests: []string{"go", "arrow"},
+				Metadata:  map[string]string{"tier": "gold", "source": "test"},
+			}
+		case 1:
+			// An empty profile keeps its empty, non-nil collections
+			users[i].Profile = &parquet.Profile{Interests: []string{}, Metadata: map[string]string{}}
+		}
+	}
+	return users
+}
+
+func TestUsersRecordRoundTrip(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	users := sampleUsers(7)
+	rec := Users.NewRecord(mem, users)
+	if rec.NumRows() != 7 || rec.NumCols() != 7 {
+		t.Fatalf("Expected 7 rows and 7 columns, got %d and %d", rec.NumRows(), rec.NumCols())
+	}
+
+	back, err := Users.FromRecord(rec)
+	rec.Release()
+	if err != nil {
+		t.Fatalf("Failed to read record: %v", err)
+	}
+	if !reflect.DeepEqual(back, users) {
+		t.Errorf("Users changed across the record:\n got %+v\nwant %+v", back, users)
+	}
+
+	// A record of another model is rejected
+	products := Products.NewRecord(mem, nil)
+	defer products.Release()
+	if _, err := Users.FromRecord(products); err == nil {
+		t.Error("Expected a product record to be rejected")
+	}
+
+	t.Log("✓ Users round trip through Arrow records")
+}
+
+func TestArrowIPCRoundTrip(t *testing.T) {
+	price := &parquet.Price{Currency: "USD", AmountCents: 1999, DiscountPercentage: 12.5}
+	products := []parquet.Product{
+		{ID: 1, Name: "Widget", SKU: "W-1", Price: price, Inventory: &parquet.Inventory{Quantity: 10, Available: 8, TrackInventory: true},
+			Categories: []string{"tools"}, Status: "active", Specifications: map[string]string{"color": "red"},
+			CreatedAt: testutil.DefaultFakeTime, UpdatedAt: testutil.DefaultFakeTime},
+		{ID: 2, Name: "Gadget", Status: "draft"},
+	}
+	expires := testutil.DefaultFakeTime.Add(24 * time.Hour)
+	events := []parquet.Analytics{
+		{ID: 1, EventType: "page_view", UserID: 7, SessionID: "s1", Timestamp: testutil.DefaultFakeTime,
+			Properties: map[string]string{"page": "/"}, Metrics: map[string]float64{"duration": 1.5},
+			DeviceInfo: &parquet.DeviceInfo{UserAgent: "test", Platform: "linux", Mobile: true},
+			Location:   &parquet.Location{Country: "TW", Latitude: 25.03, Longitude: 121.56}, ExpiresAt: &expires},
+		{ID: 2, EventType: "click", SessionID: "s2", Timestamp: testutil.DefaultFakeTime.Add(time.Millisecond)},
+	}
+
+	var buf bytes.Buffer
+	users := sampleUsers(10)
+	if err := WriteArrowIPC(&buf, Users, users, 3); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	backUsers, err := ReadArrowIPC(bytes.NewReader(buf.Bytes()), Users)
+	if err != nil {
+		t.Fatalf("Failed to read users: %v", err)
+	}
+	if !reflect.DeepEqual(backUsers, users) {
+		t.Error("Users changed across the IPC stream")
+	}
+	if _, err := ReadArrowIPC(bytes.NewReader(buf.Bytes()), Products); err == nil {
+		t.Error("Expected reading users as products to fail")
+	}
+
+	buf.Reset()
+	if err := WriteArrowIPC(&buf, Products, products, 0); err != nil {
+		t.Fatalf("Failed to write products: %v", err)
+	}
+	if back, err := ReadArrowIPC(&buf, Products); err != nil || !reflect.DeepEqual(back, products) {
+		t.Errorf("Products changed across the IPC stream: %+v (%v)", back, err)
+	}
+
+	buf.Reset()
+	if err := WriteArrowIPC(&buf, Analytics, events, 1); err != nil {
+		t.Fatalf("Failed to write events: %v", err)
+	}
+	if back, err := ReadArrowIPC(&buf, Analytics); err != nil || !reflect.DeepEqual(back, events) {
+		t.Errorf("Events changed across the IPC stream: %+v (%v)", back, err)
+	}
+
+	// An empty stream still carries its schema
+	buf.Reset()
+	if err := WriteArrowIPC[parquet.User](&buf, Users, nil, 0); err != nil {
+		t.Fatalf("Failed to write an empty stream: %v", err)
+	}
+	if back, err := ReadArrowIPC(&buf, Users); err != nil || len(back) != 0 {
+		t.Errorf("Expected no users from an empty stream, got %d (%v)", len(back), err)
+	}
+
+	t.Log("✓ Users, products and analytics events round trip through Arrow IPC streams")
+}
@@ -0,0 +1,201 @@
+// Package arrow converts the shared Parquet models to Apache Arrow record
+// batches and back, so they can be handed to Arrow-based compute without
+// copying, and reads and writes them in the Arrow IPC stream format.
+package arrow
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// Codec converts one model to and from Arrow records of a fixed schema
+type Codec[T any] struct {
+	schema *arrow.Schema
+	append func(b *array.RecordBuilder, v *T)
+	read   func(columns []arrow.Array, row int) T
+}
+
+// Schema returns the Arrow schema of the model's records
+func (c Codec[T]) Schema() *arrow.Schema {
+	return c.schema
+}
+
+// NewRecord builds one record batch holding values; the caller releases it
+func (c Codec[T]) NewRecord(mem memory.Allocator, values []T) arrow.Record {
+	b := array.NewRecordBuilder(mem, c.schema)
+	defer b.Release()
+
+	b.Reserve(len(values))
+	for i := range values {
+		c.append(b, &values[i])
+	}
+	return b.NewRecord()
+}
+
+// FromRecord reads the values of rec, which must have the codec's schema.
+// Strings are copied, so the values outlive the record
+func (c Codec[T]) FromRecord(rec arrow.Record) ([]T, error) {
+	if !rec.Schema().Equal(c.schema) {
+		return nil, fmt.Errorf("record schema does not match: got %s, want %s", rec.Schema(), c.schema)
+	}
+	columns := rec.Columns()
+	values := make([]T, rec.NumRows())
+	for i := range values {
+		values[i] = c.read(columns, i)
+	}
+	return values, nil
+}
+
+// Timestamps are stored in nanoseconds so Go times round trip exactly; the
+// zero time is stored as null
+var timestampType = &arrow.TimestampType{Unit: arrow.Nanosecond, TimeZone: "UTC"}
+
+// Nil slices and maps are stored as null lists and maps, so they stay
+// distinct from empty ones
+var (
+	stringList = arrow.ListOf(arrow.BinaryTypes.String)
+	stringMap  = arrow.MapOf(arrow.BinaryTypes.String, arrow.BinaryTypes.String)
+	floatMap   = arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Float64)
+)
+
+func field(name string, typ arrow.DataType) arrow.Field {
+	return arrow.Field{Name: name, Type: typ}
+}
+
+func nullable(name string, typ arrow.DataType) arrow.Field {
+	return arrow.Field{Name: name, Type: typ, Nullable: true}
+}
+
+func appendString(b array.Builder, s string) {
+	b.(*array.StringBuilder).Append(s)
+}
+
+func appendTime(b array.Builder, t time.Time) {
+	tb := b.(*array.TimestampBuilder)
+	if t.IsZero() {
+		tb.AppendNull()
+		return
+	}
+	tb.Append(arrow.Timestamp(t.UnixNano()))
+}
+
+func appendTimePtr(b array.Builder, t *time.Time) {
+	if t == nil {
+		b.AppendNull()
+		return
+	}
+	appendTime(b, *t)
+}
+
+func appendStrings(b array.Builder, values []string) {
+	lb := b.(*array.ListBuilder)
+	if values == nil {
+		lb.AppendNull()
+		return
+	}
+	lb.Append(true)
+	vb := lb.ValueBuilder().(*array.StringBuilder)
+	for _, v := range values {
+		vb.Append(v)
+	}
+}
+
+// appendStringMap appends m with its keys sorted, so equal maps encode equally
+func appendStringMap(b array.Builder, m map[string]string) {
+	mb := b.(*array.MapBuilder)
+	if m == nil {
+		mb.AppendNull()
+		return
+	}
+	mb.Append(true)
+	kb := mb.KeyBuilder().(*array.StringBuilder)
+	ib := mb.ItemBuilder().(*array.StringBuilder)
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		kb.Append(k)
+		ib.Append(m[k])
+	}
+}
+
+func appendFloatMap(b array.Builder, m map[string]float64) {
+	mb := b.(*array.MapBuilder)
+	if m == nil {
+		mb.AppendNull()
+		return
+	}
+	mb.Append(true)
+	kb := mb.KeyBuilder().(*array.StringBuilder)
+	ib := mb.ItemBuilder().(*array.Float64Builder)
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		kb.Append(k)
+		ib.Append(m[k])
+	}
+}
+
+// stringAt copies the string, as Arrow strings share the record's buffers
+func stringAt(a arrow.Array, i int) string {
+	return strings.Clone(a.(*array.String).Value(i))
+}
+
+func timeAt(a arrow.Array, i int) time.Time {
+	if a.IsNull(i) {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(a.(*array.Timestamp).Value(i))).UTC()
+}
+
+func timePtrAt(a arrow.Array, i int) *time.Time {
+	if a.IsNull(i) {
+		return nil
+	}
+	t := timeAt(a, i)
+	return &t
+}
+
+func stringsAt(a arrow.Array, i int) []string {
+	list := a.(*array.List)
+	if list.IsNull(i) {
+		return nil
+	}
+	start, end := list.ValueOffsets(i)
+	values := list.ListValues()
+	out := make([]string, 0, end-start)
+	for j := start; j < end; j++ {
+		out = append(out, stringAt(values, int(j)))
+	}
+	return out
+}
+
+func stringMapAt(a arrow.Array, i int) map[string]string {
+	m := a.(*array.Map)
+	if m.IsNull(i) {
+		return nil
+	}
+	start, end := m.ValueOffsets(i)
+	keys, items := m.Keys(), m.Items()
+	out := make(map[string]string, end-start)
+	for j := int(start); j < int(end); j++ {
+		out[stringAt(keys, j)] = stringAt(items, j)
+	}
+	return out
+}
+
+func floatMapAt(a arrow.Array, i int) map[string]float64 {
+	m := a.(*array.Map)
+	if m.IsNull(i) {
+		return nil
+	}
+	start, end := m.ValueOffsets(i)
+	keys, items := m.Keys(), m.Items().(*array.Float64)
+	out := make(map[string]float64, end-start)
+	for j := int(start); j < int(end); j++ {
+		out[stringAt(keys, j)] = items.Value(j)
+	}
+	return out
+}
@@ -0,0 +1,62 @@
+package arrow
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// WriteArrowIPC writes values to w in the Arrow IPC stream format, in record
+// batches of at most batchSize rows; a batchSize of zero writes one batch
+func WriteArrowIPC[T any](w io.Writer, codec Codec[T], values []T, batchSize int) error {
+	mem := memory.NewGoAllocator()
+	writer := ipc.NewWriter(w, ipc.WithSchema(codec.schema), ipc.WithAllocator(mem))
+
+	if batchSize <= 0 {
+		batchSize = max(len(values), 1)
+	}
+	for start := 0; start < len(values); start += batchSize {
+		rec := codec.NewRecord(mem, values[start:min(start+batchSize, len(values))])
+		err := writer.Write(rec)
+		rec.Release()
+		if err != nil {
+			writer.Close()
+			return fmt.Errorf("failed to write record batch at row %d: %w", start, err)
+		}
+	}
+
+	// Close writes the schema of an empty stream and the end-of-stream marker
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close IPC stream: %w", err)
+	}
+	return nil
+}
+
+// ReadArrowIPC reads every record batch of an Arrow IPC stream written with
+// the codec's schema
+func ReadArrowIPC[T any](r io.Reader, codec Codec[T]) ([]T, error) {
+	reader, err := ipc.NewReader(r, ipc.WithAllocator(memory.NewGoAllocator()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open IPC stream: %w", err)
+	}
+	defer reader.Release()
+
+	if !reader.Schema().Equal(codec.schema) {
+		return nil, fmt.Errorf("stream schema does not match: got %s, want %s", reader.Schema(), codec.schema)
+	}
+
+	var values []T
+	for reader.Next() {
+		batch, err := codec.FromRecord(reader.Record())
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, batch...)
+	}
+	if err := reader.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read IPC stream after %d rows: %w", len(values), err)
+	}
+	return values, nil
+}
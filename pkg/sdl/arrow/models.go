@@ -0,0 +1,315 @@
+package arrow
+
+import (
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+
+	"go-transport-prac/pkg/sdl/parquet"
+)
+
+var addressType = arrow.StructOf(
+	field("street", arrow.BinaryTypes.String),
+	field("city", arrow.BinaryTypes.String),
+	field("state", arrow.BinaryTypes.String),
+	field("postal_code", arrow.BinaryTypes.String),
+	field("country", arrow.BinaryTypes.String),
+)
+
+var profileType = arrow.StructOf(
+	field("first_name", arrow.BinaryTypes.String),
+	field("last_name", arrow.BinaryTypes.String),
+	field("phone", arrow.BinaryTypes.String),
+	nullable("address", addressType),
+	nullable("interests", stringList),
+	nullable("metadata", stringMap),
+)
+
+// Users converts parquet.User records, with the columns of the Parquet schema
+var Users = Codec[parquet.User]{
+	schema: arrow.NewSchema([]arrow.Field{
+		field("id", arrow.PrimitiveTypes.Int64),
+		field("email", arrow.BinaryTypes.String),
+		field("name", arrow.BinaryTypes.String),
+		field("status", arrow.BinaryTypes.String),
+		nullable("profile", profileType),
+		nullable("created_at", timestampType),
+		nullable("updated_at", timestampType),
+	}, nil),
+	append: appendUser,
+	read:   readUser,
+}
+
+func appendUser(b *array.RecordBuilder, u *parquet.User) {
+	b.Field(0).(*array.Int64Builder).Append(u.ID)
+	appendString(b.Field(1), u.Email)
+	appendString(b.Field(2), u.Name)
+	appendString(b.Field(3), u.Status)
+
+	profile := b.Field(4).(*array.StructBuilder)
+	if p := u.Profile; p == nil {
+		profile.AppendNull()
+	} else {
+		profile.Append(true)
+		appendString(profile.FieldBuilder(0), p.FirstName)
+		appendString(profile.FieldBuilder(1), p.LastName)
+		appendString(profile.FieldBuilder(2), p.Phone)
+
+		address := profile.FieldBuilder(3).(*array.StructBuilder)
+		if a := p.Address; a == nil {
+			address.AppendNull()
+		} else {
+			address.Append(true)
+			appendString(address.FieldBuilder(0), a.Street)
+			appendString(address.FieldBuilder(1), a.City)
+			appendString(address.FieldBuilder(2), a.State)
+			appendString(address.FieldBuilder(3), a.PostalCode)
+			appendString(address.FieldBuilder(4), a.Country)
+		}
+		appendStrings(profile.FieldBuilder(4), p.Interests)
+		appendStringMap(profile.FieldBuilder(5), p.Metadata)
+	}
+
+	appendTime(b.Field(5), u.CreatedAt)
+	appendTime(b.Field(6), u.UpdatedAt)
+}
+
+func readUser(columns []arrow.Array, i int) parquet.User {
+	u := parquet.User{
+		ID:        columns[0].(*array.Int64).Value(i),
+		Email:     stringAt(columns[1], i),
+		Name:      stringAt(columns[2], i),
+		Status:    stringAt(columns[3], i),
+		CreatedAt: timeAt(columns[5], i),
+		UpdatedAt: timeAt(columns[6], i),
+	}
+
+	if profile := columns[4].(*array.Struct); profile.IsValid(i) {
+		p := &parquet.Profile{
+			FirstName: stringAt(profile.Field(0), i),
+			LastName:  stringAt(profile.Field(1), i),
+			Phone:     stringAt(profile.Field(2), i),
+			Interests: stringsAt(profile.Field(4), i),
+			Metadata:  stringMapAt(profile.Field(5), i),
+		}
+		if address := profile.Field(3).(*array.Struct); address.IsValid(i) {
+			p.Address = &parquet.Address{
+				Street:     stringAt(address.Field(0), i),
+				City:       stringAt(address.Field(1), i),
+				State:      stringAt(address.Field(2), i),
+				PostalCode: stringAt(address.Field(3), i),
+				Country:    stringAt(address.Field(4), i),
+			}
+		}
+		u.Profile = p
+	}
+	return u
+}
+
+var priceType = arrow.StructOf(
+	field("currency", arrow.BinaryTypes.String),
+	field("amount_cents", arrow.PrimitiveTypes.Int64),
+	field("discount_percentage", arrow.PrimitiveTypes.Float32),
+)
+
+var inventoryType = arrow.StructOf(
+	field("quantity", arrow.PrimitiveTypes.Int32),
+	field("reserved", arrow.PrimitiveTypes.Int32),
+	field("available", arrow.PrimitiveTypes.Int32),
+	field("track_inventory", arrow.FixedWidthTypes.Boolean),
+	field("reorder_level", arrow.PrimitiveTypes.Int32),
+	field("max_stock", arrow.PrimitiveTypes.Int32),
+)
+
+// Products converts parquet.Product records
+var Products = Codec[parquet.Product]{
+	schema: arrow.NewSchema([]arrow.Field{
+		field("id", arrow.PrimitiveTypes.Int64),
+		field("name", arrow.BinaryTypes.String),
+		field("description", arrow.BinaryTypes.String),
+		field("sku", arrow.BinaryTypes.String),
+		nullable("price", priceType),
+		nullable("inventory", inventoryType),
+		nullable("categories", stringList),
+		nullable("tags", stringList),
+		field("status", arrow.BinaryTypes.String),
+		nullable("specifications", stringMap),
+		nullable("created_at", timestampType),
+		nullable("updated_at", timestampType),
+	}, nil),
+	append: appendProduct,
+	read:   readProduct,
+}
+
+func appendProduct(b *array.RecordBuilder, p *parquet.Product) {
+	b.Field(0).(*array.Int64Builder).Append(p.ID)
+	appendString(b.Field(1), p.Name)
+	appendString(b.Field(2), p.Description)
+	appendString(b.Field(3), p.SKU)
+
+	price := b.Field(4).(*array.StructBuilder)
+	if pr := p.Price; pr == nil {
+		price.AppendNull()
+	} else {
+		price.Append(true)
+		appendString(price.FieldBuilder(0), pr.Currency)
+		price.FieldBuilder(1).(*array.Int64Builder).Append(pr.AmountCents)
+		price.FieldBuilder(2).(*array.Float32Builder).Append(pr.DiscountPercentage)
+	}
+
+	inventory := b.Field(5).(*array.StructBuilder)
+	if inv := p.Inventory; inv == nil {
+		inventory.AppendNull()
+	} else {
+		inventory.Append(true)
+		inventory.FieldBuilder(0).(*array.Int32Builder).Append(inv.Quantity)
+		inventory.FieldBuilder(1).(*array.Int32Builder).Append(inv.Reserved)
+		inventory.FieldBuilder(2).(*array.Int32Builder).Append(inv.Available)
+		inventory.FieldBuilder(3).(*array.BooleanBuilder).Append(inv.TrackInventory)
+		inventory.FieldBuilder(4).(*array.Int32Builder).Append(inv.ReorderLevel)
+		inventory.FieldBuilder(5).(*array.Int32Builder).Append(inv.MaxStock)
+	}
+
+	appendStrings(b.Field(6), p.Categories)
+	appendStrings(b.Field(7), p.Tags)
+	appendString(b.Field(8), p.Status)
+	appendStringMap(b.Field(9), p.Specifications)
+	appendTime(b.Field(10), p.CreatedAt)
+	appendTime(b.Field(11), p.UpdatedAt)
+}
+
+func readProduct(columns []arrow.Array, i int) parquet.Product {
+	p := parquet.Product{
+		ID:             columns[0].(*array.Int64).Value(i),
+		Name:           stringAt(columns[1], i),
+		Description:    stringAt(columns[2], i),
+		SKU:            stringAt(columns[3], i),
+		Categories:     stringsAt(columns[6], i),
+		Tags:           stringsAt(columns[7], i),
+		Status:         stringAt(columns[8], i),
+		Specifications: stringMapAt(columns[9], i),
+		CreatedAt:      timeAt(columns[10], i),
+		UpdatedAt:      timeAt(columns[11], i),
+	}
+
+	if price := columns[4].(*array.Struct); price.IsValid(i) {
+		p.Price = &parquet.Price{
+			Currency:           stringAt(price.Field(0), i),
+			AmountCents:        price.Field(1).(*array.Int64).Value(i),
+			DiscountPercentage: price.Field(2).(*array.Float32).Value(i),
+		}
+	}
+	if inventory := columns[5].(*array.Struct); inventory.IsValid(i) {
+		p.Inventory = &parquet.Inventory{
+			Quantity:       inventory.Field(0).(*array.Int32).Value(i),
+			Reserved:       inventory.Field(1).(*array.Int32).Value(i),
+			Available:      inventory.Field(2).(*array.Int32).Value(i),
+			TrackInventory: inventory.Field(3).(*array.Boolean).Value(i),
+			ReorderLevel:   inventory.Field(4).(*array.Int32).Value(i),
+			MaxStock:       inventory.Field(5).(*array.Int32).Value(i),
+		}
+	}
+	return p
+}
+
+var deviceInfoType = arrow.StructOf(
+	field("user_agent", arrow.BinaryTypes.String),
+	field("platform", arrow.BinaryTypes.String),
+	field("browser", arrow.BinaryTypes.String),
+	field("version", arrow.BinaryTypes.String),
+	field("mobile", arrow.FixedWidthTypes.Boolean),
+)
+
+var locationType = arrow.StructOf(
+	field("country", arrow.BinaryTypes.String),
+	field("region", arrow.BinaryTypes.String),
+	field("city", arrow.BinaryTypes.String),
+	field("latitude", arrow.PrimitiveTypes.Float64),
+	field("longitude", arrow.PrimitiveTypes.Float64),
+)
+
+// Analytics converts parquet.Analytics events
+var Analytics = Codec[parquet.Analytics]{
+	schema: arrow.NewSchema([]arrow.Field{
+		field("id", arrow.PrimitiveTypes.Int64),
+		field("event_type", arrow.BinaryTypes.String),
+		field("user_id", arrow.PrimitiveTypes.Int64),
+		field("session_id", arrow.BinaryTypes.String),
+		nullable("timestamp", timestampType),
+		nullable("properties", stringMap),
+		nullable("metrics", floatMap),
+		nullable("device_info", deviceInfoType),
+		nullable("location", locationType),
+		nullable("expires_at", timestampType),
+	}, nil),
+	append: appendAnalytics,
+	read:   readAnalytics,
+}
+
+func appendAnalytics(b *array.RecordBuilder, e *parquet.Analytics) {
+	b.Field(0).(*array.Int64Builder).Append(e.ID)
+	appendString(b.Field(1), e.EventType)
+	b.Field(2).(*array.Int64Builder).Append(e.UserID)
+	appendString(b.Field(3), e.SessionID)
+	appendTime(b.Field(4), e.Timestamp)
+	appendStringMap(b.Field(5), e.Properties)
+	appendFloatMap(b.Field(6), e.Metrics)
+
+	device := b.Field(7).(*array.StructBuilder)
+	if d := e.DeviceInfo; d == nil {
+		device.AppendNull()
+	} else {
+		device.Append(true)
+		appendString(device.FieldBuilder(0), d.UserAgent)
+		appendString(device.FieldBuilder(1), d.Platform)
+		appendString(device.FieldBuilder(2), d.Browser)
+		appendString(device.FieldBuilder(3), d.Version)
+		device.FieldBuilder(4).(*array.BooleanBuilder).Append(d.Mobile)
+	}
+
+	location := b.Field(8).(*array.StructBuilder)
+	if l := e.Location; l == nil {
+		location.AppendNull()
+	} else {
+		location.Append(true)
+		appendString(location.FieldBuilder(0), l.Country)
+		appendString(location.FieldBuilder(1), l.Region)
+		appendString(location.FieldBuilder(2), l.City)
+		location.FieldBuilder(3).(*array.Float64Builder).Append(l.Latitude)
+		location.FieldBuilder(4).(*array.Float64Builder).Append(l.Longitude)
+	}
+
+	appendTimePtr(b.Field(9), e.ExpiresAt)
+}
+
+func readAnalytics(columns []arrow.Array, i int) parquet.Analytics {
+	e := parquet.Analytics{
+		ID:         columns[0].(*array.Int64).Value(i),
+		EventType:  stringAt(columns[1], i),
+		UserID:     columns[2].(*array.Int64).Value(i),
+		SessionID:  stringAt(columns[3], i),
+		Timestamp:  timeAt(columns[4], i),
+		Properties: stringMapAt(columns[5], i),
+		Metrics:    floatMapAt(columns[6], i),
+		ExpiresAt:  timePtrAt(columns[9], i),
+	}
+
+	if device := columns[7].(*array.Struct); device.IsValid(i) {
+		e.DeviceInfo = &parquet.DeviceInfo{
+			UserAgent: stringAt(device.Field(0), i),
+			Platform:  stringAt(device.Field(1), i),
+			Browser:   stringAt(device.Field(2), i),
+			Version:   stringAt(device.Field(3), i),
+			Mobile:    device.Field(4).(*array.Boolean).Value(i),
+		}
+	}
+	if location := columns[8].(*array.Struct); location.IsValid(i) {
+		e.Location = &parquet.Location{
+			Country:   stringAt(location.Field(0), i),
+			Region:    stringAt(location.Field(1), i),
+			City:      stringAt(location.Field(2), i),
+			Latitude:  location.Field(3).(*array.Float64).Value(i),
+			Longitude: location.Field(4).(*array.Float64).Value(i),
+		}
+	}
+	return e
+}
@@ -0,0 +1,88 @@
+package avro
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/hamba/avro/v2"
+
+	"go-transport-prac/internal/tracing"
+)
+
+// SerializeAnalytics serializes an analytics event to binary Avro
+func (m *Manager) SerializeAnalytics(event Analytics) ([]byte, error) {
+	data, err := m.SerializeStruct(m.analyticsSchema, event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize analytics event %d: %w", event.ID, err)
+	}
+	return data, nil
+}
+
+// DeserializeAnalytics deserializes an analytics event from binary Avro
+func (m *Manager) DeserializeAnalytics(data []byte) (Analytics, error) {
+	var event Analytics
+	if err := m.DeserializeStruct(m.analyticsSchema, data, &event); err != nil {
+		return Analytics{}, fmt.Errorf("failed to deserialize analytics event: %w", err)
+	}
+	return event, nil
+}
+
+// WriteAnalyticsToFile writes analytics events to a binary Avro file
+func (m *Manager) WriteAnalyticsToFile(filename string, events []Analytics) error {
+	return m.WriteAnalyticsToFileContext(context.Background(), filename, events)
+}
+
+// WriteAnalyticsToFileContext writes analytics events to a binary Avro file,
+// stopping with ctx.Err() once ctx is done
+func (m *Manager) WriteAnalyticsToFileContext(ctx context.Context, filename string, events []Analytics) (err error) {
+	ctx, span := startSpan(ctx, "avro.write", "analytics", filename)
+	defer func() { tracing.End(span, len(events), err) }()
+
+	return m.writeFile(ctx, filename, m.catalogEntry(formatAvro, "analytics", m.analyticsSchema, len(events)), func(w io.Writer) error {
+		encoder := avro.NewEncoderForSchema(m.analyticsSchema, w)
+
+		for _, event := range events {
+			if err := encoder.Encode(event); err != nil {
+				return fmt.Errorf("failed to encode analytics event %d: %w", event.ID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// ReadAnalyticsFromFile reads analytics events from a binary Avro file
+func (m *Manager) ReadAnalyticsFromFile(filename string) ([]Analytics, error) {
+	return m.ReadAnalyticsFromFileContext(context.Background(), filename)
+}
+
+// ReadAnalyticsFromFileContext reads analytics events from a binary Avro file,
+// stopping with ctx.Err() once ctx is done
+func (m *Manager) ReadAnalyticsFromFileContext(ctx context.Context, filename string) (result []Analytics, err error) {
+	ctx, span := startSpan(ctx, "avro.read", "analytics", filename)
+	defer func() { tracing.End(span, len(result), err) }()
+
+	file, err := m.openFile(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	decoder := decodeAPI.NewDecoder(m.analyticsSchema, file)
+
+	var events []Analytics
+	for {
+		// Decode into a fresh value so optional fields never leak from the previous event
+		var event Analytics
+		if err := decoder.Decode(&event); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode analytics event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return finishRead(ctx, events, nil)
+}
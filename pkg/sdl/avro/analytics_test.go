@@ -0,0 +1,122 @@
+package avro
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// sampleAnalytics returns events alternating between anonymous and signed-in users
+func sampleAnalytics(count int) []Analytics {
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	city := "Taipei"
+	latitude, longitude := 25.033, 121.565
+
+	events := make([]Analytics, count)
+	for i := range events {
+		events[i] = Analytics{
+			ID:         int64(i + 1),
+			EventType:  []string{"page_view", "click", "purchase"}[i%3],
+			SessionID:  fmt.Sprintf("session_%d", i%4),
+			Timestamp:  base.Add(time.Duration(i) * time.Minute),
+			Properties: map[string]string{"page": fmt.Sprintf("/page/%d", i)},
+			Metrics:    map[string]float64{"duration": float64(i) * 1.5},
+		}
+		if i%2 == 1 {
+			userID := int64(100 + i)
+			events[i].UserID = &userID
+			events[i].DeviceInfo = &DeviceInfo{UserAgent: "Mozilla/5.0", Platform: "web", Browser: "chrome", Version: "120", Mobile: false}
+			events[i].Location = &Location{Country: "TW", City: &city, Latitude: &latitude, Longitude: &longitude}
+		}
+	}
+	return events
+}
+
+func TestAnalyticsSerialization(t *testing.T) {
+	manager, err := NewManager("tmp/test_analytics")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer os.RemoveAll("tmp/test_analytics")
+
+	for _, event := range sampleAnalytics(4) {
+		data, err := manager.SerializeAnalytics(event)
+		if err != nil {
+			t.Fatalf("Failed to serialize analytics event: %v", err)
+		}
+		decoded, err := manager.DeserializeAnalytics(data)
+		if err != nil {
+			t.Fatalf("Failed to deserialize analytics event: %v", err)
+		}
+		decoded.Timestamp = decoded.Timestamp.UTC()
+		if !reflect.DeepEqual(decoded, event) {
+			t.Errorf("Analytics event mismatch:\nexpected %+v\ngot      %+v", event, decoded)
+		}
+	}
+
+	// The map path reads what the struct-tag fast path wrote
+	event := sampleAnalytics(2)[1]
+	data, _ := manager.SerializeAnalytics(event)
+	mapped, _ := NewManager("tmp/test_analytics")
+	mapped.WithNativeStructs(false)
+	decoded, err := mapped.DeserializeAnalytics(data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize analytics event via map: %v", err)
+	}
+	if decoded.UserID == nil || *decoded.UserID != *event.UserID || *decoded.Location.City != "Taipei" {
+		t.Errorf("Map path mismatch: %+v", decoded)
+	}
+
+	t.Log("✓ Analytics events round-trip through Avro")
+}
+
+func TestAnalyticsFiles(t *testing.T) {
+	manager, err := NewManager("tmp/test_analytics_files")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer os.RemoveAll("tmp/test_analytics_files")
+
+	events := sampleAnalytics(50)
+	utc := func(events []Analytics) []Analytics {
+		for i := range events {
+			events[i].Timestamp = events[i].Timestamp.UTC()
+		}
+		return events
+	}
+
+	if err := manager.WriteAnalyticsToFile("analytics.avro", events); err != nil {
+		t.Fatalf("Failed to write analytics file: %v", err)
+	}
+	read, err := manager.ReadAnalyticsFromFile("analytics.avro")
+	if err != nil {
+		t.Fatalf("Failed to read analytics file: %v", err)
+	}
+	if !reflect.DeepEqual(utc(read), events) {
+		t.Errorf("Analytics file changed %d events", len(events))
+	}
+
+	if err := manager.WriteAnalyticsToOCFFile("analytics_ocf.avro", events); err != nil {
+		t.Fatalf("Failed to write analytics OCF file: %v", err)
+	}
+	read, err = manager.ReadAnalyticsFromOCFFile("analytics_ocf.avro")
+	if err != nil {
+		t.Fatalf("Failed to read analytics OCF file: %v", err)
+	}
+	if !reflect.DeepEqual(utc(read), events) {
+		t.Errorf("Analytics OCF file changed %d events", len(events))
+	}
+
+	var buf bytes.Buffer
+	if err := manager.WriteAnalyticsOCF(&buf, events[:1]); err != nil {
+		t.Fatalf("Failed to write analytics OCF: %v", err)
+	}
+	if name, err := OCFSchemaName(&buf); err != nil || name != "com.example.avro.Analytics" {
+		t.Errorf("Expected an Analytics OCF schema, got %q (%v)", name, err)
+	}
+
+	t.Log("✓ Analytics events are written to and read from Avro files")
+}
@@ -0,0 +1,139 @@
+package avro
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/hamba/avro/v2"
+)
+
+// batchBuffers holds the buffers batches are encoded into
+var batchBuffers = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// SerializeUsersBatch serializes each user to binary Avro, as
+// SerializeUserBinary would, reusing pooled buffers and encoders across the
+// batch. The returned slices share one backing array
+func (m *Manager) SerializeUsersBatch(users []User) ([][]byte, error) {
+	return encodeBatch(m, m.userSchema, users, m.userToAvroMap, "user")
+}
+
+// DeserializeUsersBatch deserializes binary Avro users, as
+// DeserializeUserBinary would, reusing a pooled decoder across the batch
+func (m *Manager) DeserializeUsersBatch(data [][]byte) ([]User, error) {
+	return decodeBatch(m, m.userSchema, data, m.avroMapToUser, "user")
+}
+
+// SerializeProductsBatch serializes each product to binary Avro, as
+// SerializeProductBinary would, reusing pooled buffers and encoders across
+// the batch. The returned slices share one backing array
+func (m *Manager) SerializeProductsBatch(products []Product) ([][]byte, error) {
+	return encodeBatch(m, m.productSchema, products, m.productToAvroMap, "product")
+}
+
+// DeserializeProductsBatch deserializes binary Avro products, as
+// DeserializeProductBinary would, reusing a pooled decoder across the batch
+func (m *Manager) DeserializeProductsBatch(data [][]byte) ([]Product, error) {
+	return decodeBatch(m, m.productSchema, data, m.avroMapToProduct, "product")
+}
+
+// SerializeOrdersBatch serializes each order to binary Avro from its struct
+// tags, reusing pooled buffers and encoders across the batch. The returned
+// slices share one backing array
+func (m *Manager) SerializeOrdersBatch(orders []Order) ([][]byte, error) {
+	return encodeBatch[Order](m, m.orderSchema, orders, nil, "order")
+}
+
+// DeserializeOrdersBatch deserializes binary Avro orders into their structs,
+// reusing a pooled decoder across the batch
+func (m *Manager) DeserializeOrdersBatch(data [][]byte) ([]Order, error) {
+	return decodeBatch[Order](m, m.orderSchema, data, nil, "order")
+}
+
+// encodeBatch encodes every item into one pooled buffer, then copies the
+// buffer out once and slices it per item. Items go through the struct-tag
+// fast path unless it is disabled or fails, falling back to toMap when set
+func encodeBatch[T any](m *Manager, schema avro.Schema, items []T, toMap func(T) map[string]interface{}, kind string) ([][]byte, error) {
+	codec := m.codec(schema)
+	buf := batchBuffers.Get().(*bytes.Buffer)
+	writer := codec.getWriter()
+	defer func() {
+		codec.putWriter(writer)
+		if buf.Cap() <= maxPooledBuffer {
+			buf.Reset()
+			batchBuffers.Put(buf)
+		}
+	}()
+
+	buf.Reset()
+	result := make([][]byte, len(items))
+	for i := range items {
+		writer.Reset(nil)
+		writer.Error = nil
+		if !m.mapOnly || toMap == nil {
+			// A pointer spares boxing every item into an interface
+			writer.WriteVal(schema, &items[i])
+		}
+		if (m.mapOnly || writer.Error != nil) && toMap != nil {
+			writer.Reset(nil)
+			writer.Error = nil
+			writer.WriteVal(schema, toMap(items[i]))
+		}
+		if writer.Error != nil {
+			return nil, fmt.Errorf("failed to encode %s %d: %w", kind, i, writer.Error)
+		}
+		buf.Write(writer.Buffer())
+		// Only the length is kept: it is where the record ends in buf
+		result[i] = buf.Bytes()
+	}
+
+	data := bytes.Clone(buf.Bytes())
+	start := 0
+	for i := range result {
+		end := len(result[i])
+		result[i] = data[start:end:end]
+		start = end
+	}
+	return result, nil
+}
+
+// decodeBatch decodes every record with one pooled reader. Records go
+// through the struct-tag fast path unless it is disabled, unsafe for schema
+// or fails, falling back to fromMap when set
+func decodeBatch[T any](m *Manager, schema avro.Schema, data [][]byte, fromMap func(interface{}) (T, error), kind string) ([]T, error) {
+	codec := m.codec(schema)
+	reader := codec.getReader(nil)
+	defer codec.putReader(reader)
+
+	native := (!m.mapOnly && !nativeUnsafe(schema)) || fromMap == nil
+	result := make([]T, len(data))
+	for i, record := range data {
+		if native {
+			reader.Reset(record)
+			reader.Error = nil
+			reader.ReadVal(schema, &result[i])
+			if reader.Error == nil {
+				continue
+			}
+			if fromMap == nil {
+				return nil, fmt.Errorf("failed to decode %s %d: %w", kind, i, reader.Error)
+			}
+			var zero T
+			result[i] = zero
+		}
+
+		reader.Reset(record)
+		reader.Error = nil
+		var generic interface{}
+		reader.ReadVal(schema, &generic)
+		if reader.Error != nil {
+			return nil, fmt.Errorf("failed to decode %s %d: %w", kind, i, reader.Error)
+		}
+		item, err := fromMap(generic)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s %d: %w", kind, i, err)
+		}
+		result[i] = item
+	}
+	return result, nil
+}
@@ -0,0 +1,190 @@
+package avro
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBatchSerialization(t *testing.T) {
+	native, err := NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	mapped, _ := NewManager("")
+	mapped.WithNativeStructs(false)
+
+	for _, manager := range []*Manager{native, mapped} {
+		users := manager.CreateSampleUsers(20)
+		data, err := manager.SerializeUsersBatch(users)
+		if err != nil {
+			t.Fatalf("Failed to serialize users: %v", err)
+		}
+		back, err := manager.DeserializeUsersBatch(data)
+		if err != nil || len(back) != len(users) {
+			t.Fatalf("Failed to deserialize users: %v", err)
+		}
+		// Maps encode in random key order, so compare decoded values
+		for i, user := range users {
+			encoded, _ := manager.SerializeUserBinary(user)
+			single, _ := manager.DeserializeUserBinary(encoded)
+			if !reflect.DeepEqual(back[i], single) {
+				t.Errorf("User %d: expected %+v, got %+v", i, single, back[i])
+			}
+		}
+
+		products := manager.CreateSampleProducts(20)
+		productData, err := manager.SerializeProductsBatch(products)
+		if err != nil {
+			t.Fatalf("Failed to serialize products: %v", err)
+		}
+		productsBack, err := manager.DeserializeProductsBatch(productData)
+		if err != nil || len(productsBack) != len(products) || productsBack[19].ID != products[19].ID {
+			t.Fatalf("Failed to round-trip products: %v", err)
+		}
+	}
+
+	orders := unionOrders()
+	orderData, err := native.SerializeOrdersBatch(orders)
+	if err != nil {
+		t.Fatalf("Failed to serialize orders: %v", err)
+	}
+	ordersBack, err := native.DeserializeOrdersBatch(orderData)
+	if err != nil {
+		t.Fatalf("Failed to deserialize orders: %v", err)
+	}
+	for i := range orders {
+		if !reflect.DeepEqual(orders[i], ordersBack[i]) {
+			t.Errorf("Order %d: expected %+v, got %+v", i, orders[i], ordersBack[i])
+		}
+	}
+
+	// Appending to one record must not overwrite the next
+	data, _ := native.SerializeUsersBatch(native.CreateSampleUsers(2))
+	second := string(data[1])
+	_ = append(data[0], 0xff)
+	if string(data[1]) != second {
+		t.Error("Expected batch records not to share spare capacity")
+	}
+
+	if _, err := native.DeserializeOrdersBatch([][]byte{orderData[0], {0xff}}); err == nil {
+		t.Error("Expected a corrupt record to fail")
+	}
+	if empty, err := native.SerializeUsersBatch(nil); err != nil || len(empty) != 0 {
+		t.Errorf("Expected an empty batch, got %v, %v", empty, err)
+	}
+
+	t.Log("✓ Batch APIs match the per-item encodings")
+}
+
+// The "items" variants are the per-item calls the batch APIs replace
+
+func BenchmarkUsersBatch(b *testing.B) {
+	manager, err := NewManager("")
+	if err != nil {
+		b.Fatal(err)
+	}
+	users := manager.CreateSampleUsers(100)
+
+	b.Run("items", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, user := range users {
+				if _, err := manager.SerializeUserBinary(user); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := manager.SerializeUsersBatch(users); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkUsersBatchDecode(b *testing.B) {
+	manager, err := NewManager("")
+	if err != nil {
+		b.Fatal(err)
+	}
+	data, err := manager.SerializeUsersBatch(manager.CreateSampleUsers(100))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("items", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, record := range data {
+				if _, err := manager.DeserializeUserBinary(record); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := manager.DeserializeUsersBatch(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkProductsBatch(b *testing.B) {
+	manager, err := NewManager("")
+	if err != nil {
+		b.Fatal(err)
+	}
+	products := manager.CreateSampleProducts(100)
+
+	b.Run("items", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, product := range products {
+				if _, err := manager.SerializeProductBinary(product); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := manager.SerializeProductsBatch(products); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkOrdersBatch(b *testing.B) {
+	manager, err := NewManager("")
+	if err != nil {
+		b.Fatal(err)
+	}
+	orders := unionOrders()
+
+	b.Run("items", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, order := range orders {
+				if _, err := manager.SerializeStruct(manager.GetOrderSchema(), order); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := manager.SerializeOrdersBatch(orders); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -4,280 +4,326 @@ import (
 	"encoding/json"
 	"fmt"
 	"runtime"
+	"sort"
+	"sync"
 	"time"
 )
 
-// BenchmarkResults contains performance comparison results
+// BenchmarkConfig controls a PerformanceBenchmark run
+type BenchmarkConfig struct {
+	// Records is the number of users and of products in the dataset
+	Records int `json:"records"`
+	// Warmup passes over the dataset run before each format and are not measured
+	Warmup int `json:"warmup"`
+	// Parallelism is the number of goroutines splitting the dataset in each phase
+	Parallelism int `json:"parallelism"`
+}
+
+// DefaultBenchmarkConfig returns 1000 records, one warm-up pass and one goroutine
+func DefaultBenchmarkConfig() BenchmarkConfig {
+	return BenchmarkConfig{Records: 1000, Warmup: 1, Parallelism: 1}
+}
+
+// Latencies summarizes the per-item latencies of one phase
+type Latencies struct {
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P95  time.Duration `json:"p95"`
+	P99  time.Duration `json:"p99"`
+	Max  time.Duration `json:"max"`
+}
+
+// BenchmarkResults contains performance comparison results. The phase times
+// are the wall time to serialize, then deserialize, the whole dataset
 type BenchmarkResults struct {
-	Format               string        `json:"format"`
-	SerializationTime    time.Duration `json:"serializationTime"`
-	DeserializationTime  time.Duration `json:"deserializationTime"`
-	SerializedSize       int           `json:"serializedSize"`
-	MemoryUsage          int64         `json:"memoryUsage"`
-	ItemsPerSecond       float64       `json:"itemsPerSecond"`
+	Format              string        `json:"format"`
+	Records             int           `json:"records"`
+	SerializationTime   time.Duration `json:"serializationTime"`
+	DeserializationTime time.Duration `json:"deserializationTime"`
+	SerializeLatency    Latencies     `json:"serializeLatency"`
+	DeserializeLatency  Latencies     `json:"deserializeLatency"`
+	SerializedSize      int           `json:"serializedSize"`
+	MemoryUsage         int64         `json:"memoryUsage"`
+	// ItemsPerSecond counts serializations and deserializations over both phases
+	ItemsPerSecond float64 `json:"itemsPerSecond"`
 }
 
 // PerformanceBenchmark runs performance tests comparing different serialization formats
 type PerformanceBenchmark struct {
-	manager *Manager
-	users   []User
+	manager  *Manager
+	config   BenchmarkConfig
+	users    []User
 	products []Product
 }
 
-// NewPerformanceBenchmark creates a new performance benchmark
-func NewPerformanceBenchmark() (*PerformanceBenchmark, error) {
+// NewPerformanceBenchmark creates a performance benchmark over a generated
+// dataset; zero config fields take their defaults
+func NewPerformanceBenchmark(config BenchmarkConfig) (*PerformanceBenchmark, error) {
+	defaults := DefaultBenchmarkConfig()
+	if config.Records <= 0 {
+		config.Records = defaults.Records
+	}
+	if config.Warmup < 0 {
+		config.Warmup = 0
+	}
+	if config.Parallelism <= 0 {
+		config.Parallelism = defaults.Parallelism
+	}
+
 	manager, err := NewManager("tmp/benchmark")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create manager: %w", err)
 	}
 
-	pb := &PerformanceBenchmark{
-		manager: manager,
-	}
-
-	// Generate test data
-	pb.users = manager.CreateSampleUsers(1000)
-	pb.products = manager.CreateSampleProducts(1000)
+	return &PerformanceBenchmark{
+		manager:  manager,
+		config:   config,
+		users:    manager.CreateSampleUsers(config.Records),
+		products: manager.CreateSampleProducts(config.Records),
+	}, nil
+}
 
-	return pb, nil
+// Config returns the configuration the benchmark runs with
+func (pb *PerformanceBenchmark) Config() BenchmarkConfig {
+	return pb.config
 }
 
 // RunBenchmarks executes all performance benchmarks
 func (pb *PerformanceBenchmark) RunBenchmarks() error {
 	fmt.Println("=== Performance Benchmarks ===")
-	fmt.Printf("Testing with %d users and %d products\n", len(pb.users), len(pb.products))
+	fmt.Printf("Testing with %d users and %d products, %d warm-up pass(es), %d goroutine(s)\n",
+		len(pb.users), len(pb.products), pb.config.Warmup, pb.config.Parallelism)
 
-	// Run user benchmarks
 	fmt.Println("--- User Serialization Benchmarks ---")
-	
-	avroJSONResults, err := pb.benchmarkAvroJSON("user")
+	userResults, err := pb.Results("user")
 	if err != nil {
-		return fmt.Errorf("Avro JSON benchmark failed: %w", err)
+		return err
 	}
+	pb.displayResults("User", userResults)
 
-	avroBinaryResults, err := pb.benchmarkAvroBinary("user")
-	if err != nil {
-		return fmt.Errorf("Avro binary benchmark failed: %w", err)
-	}
-
-	stdJSONResults, err := pb.benchmarkStandardJSON("user")
+	fmt.Println("--- Product Serialization Benchmarks ---")
+	productResults, err := pb.Results("product")
 	if err != nil {
-		return fmt.Errorf("Standard JSON benchmark failed: %w", err)
+		return err
 	}
+	pb.displayResults("Product", productResults)
 
-	// Display results
-	pb.displayResults("User", []BenchmarkResults{avroJSONResults, avroBinaryResults, stdJSONResults})
+	// The summary compares the uncompressed formats
+	pb.showSummary(userResults[:3])
 
-	// Run product benchmarks
-	fmt.Println("--- Product Serialization Benchmarks ---")
-	
-	avroJSONProductResults, err := pb.benchmarkAvroJSON("product")
-	if err != nil {
-		return fmt.Errorf("Avro JSON product benchmark failed: %w", err)
-	}
+	return nil
+}
 
-	avroBinaryProductResults, err := pb.benchmarkAvroBinary("product")
+// Results benchmarks every format for "user" or "product" records: Avro
+// JSON, Avro binary, standard JSON, then compressed Avro with each codec
+func (pb *PerformanceBenchmark) Results(dataType string) ([]BenchmarkResults, error) {
+	cases, err := pb.cases(dataType)
 	if err != nil {
-		return fmt.Errorf("Avro binary product benchmark failed: %w", err)
+		return nil, err
 	}
 
-	stdJSONProductResults, err := pb.benchmarkStandardJSON("product")
-	if err != nil {
-		return fmt.Errorf("Standard JSON product benchmark failed: %w", err)
+	results := make([]BenchmarkResults, 0, len(cases))
+	for _, c := range cases {
+		result, err := pb.run(c)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s benchmark failed: %w", c.format, dataType, err)
+		}
+		results = append(results, result)
 	}
-
-	// Display results
-	pb.displayResults("Product", []BenchmarkResults{avroJSONProductResults, avroBinaryProductResults, stdJSONProductResults})
-
-	// Show summary
-	pb.showSummary([]BenchmarkResults{avroJSONResults, avroBinaryResults, stdJSONResults})
-
-	return nil
+	return results, nil
 }
 
-// benchmarkAvroJSON benchmarks Avro JSON serialization
-func (pb *PerformanceBenchmark) benchmarkAvroJSON(dataType string) (BenchmarkResults, error) {
-	var memBefore, memAfter runtime.MemStats
-	runtime.GC()
-	runtime.ReadMemStats(&memBefore)
-
-	startTime := time.Now()
-
-	var totalSize int
-	var iterations int
+// benchmarkCase serializes item i of the dataset and deserializes payloads
+type benchmarkCase struct {
+	format      string
+	serialize   func(i int) ([]byte, error)
+	deserialize func(data []byte) error
+}
 
-	if dataType == "user" {
-		for _, user := range pb.users {
-			data, err := pb.manager.SerializeUserJSON(user)
-			if err != nil {
-				return BenchmarkResults{}, err
+// cases returns the formats benchmarked for dataType
+func (pb *PerformanceBenchmark) cases(dataType string) ([]benchmarkCase, error) {
+	m := pb.manager
+	var cases []benchmarkCase
+	switch dataType {
+	case "user":
+		users := pb.users
+		cases = []benchmarkCase{
+			{"Avro JSON",
+				func(i int) ([]byte, error) { return m.SerializeUserJSON(users[i]) },
+				func(data []byte) error { _, err := m.DeserializeUserJSON(data); return err }},
+			{"Avro Binary",
+				func(i int) ([]byte, error) { return m.SerializeUserBinary(users[i]) },
+				func(data []byte) error { _, err := m.DeserializeUserBinary(data); return err }},
+			{"Standard JSON",
+				func(i int) ([]byte, error) { return json.Marshal(users[i]) },
+				func(data []byte) error { var user User; return json.Unmarshal(data, &user) }},
+		}
+		for _, codec := range Codecs() {
+			if codec == CodecNone {
+				continue
 			}
-			totalSize += len(data)
-			iterations++
-
-			// Test deserialization 
-			_, err = pb.manager.DeserializeUserJSON(data)
-			if err != nil {
-				return BenchmarkResults{}, err
+			cases = append(cases, benchmarkCase{"Avro+" + string(codec),
+				func(i int) ([]byte, error) { return m.SerializeUserBinaryCompressed(users[i], codec) },
+				func(data []byte) error { _, err := m.DeserializeUserBinaryCompressed(data); return err }})
+		}
+	case "product":
+		products := pb.products
+		cases = []benchmarkCase{
+			{"Avro JSON",
+				func(i int) ([]byte, error) { return m.SerializeProductJSON(products[i]) },
+				func(data []byte) error { _, err := m.DeserializeProductJSON(data); return err }},
+			{"Avro Binary",
+				func(i int) ([]byte, error) { return m.SerializeProductBinary(products[i]) },
+				func(data []byte) error { _, err := m.DeserializeProductBinary(data); return err }},
+			{"Standard JSON",
+				func(i int) ([]byte, error) { return json.Marshal(products[i]) },
+				func(data []byte) error { var product Product; return json.Unmarshal(data, &product) }},
+		}
+		for _, codec := range Codecs() {
+			if codec == CodecNone {
+				continue
 			}
+			cases = append(cases, benchmarkCase{"Avro+" + string(codec),
+				func(i int) ([]byte, error) { return m.SerializeProductBinaryCompressed(products[i], codec) },
+				func(data []byte) error { _, err := m.DeserializeProductBinaryCompressed(data); return err }})
 		}
-	} else {
-		for _, product := range pb.products {
-			data, err := pb.manager.SerializeProductJSON(product)
+	default:
+		return nil, fmt.Errorf("unknown data type %q", dataType)
+	}
+	return cases, nil
+}
+
+// run warms c up, then times its serialization phase over the whole dataset
+// and its deserialization phase over the payloads that phase produced
+func (pb *PerformanceBenchmark) run(c benchmarkCase) (BenchmarkResults, error) {
+	records := pb.config.Records
+	for w := 0; w < pb.config.Warmup; w++ {
+		for i := 0; i < records; i++ {
+			data, err := c.serialize(i)
 			if err != nil {
 				return BenchmarkResults{}, err
 			}
-			totalSize += len(data)
-			iterations++
-
-			// Test deserialization
-			_, err = pb.manager.DeserializeProductJSON(data)
-			if err != nil {
+			if err := c.deserialize(data); err != nil {
 				return BenchmarkResults{}, err
 			}
 		}
 	}
 
-	elapsed := time.Since(startTime)
-	runtime.ReadMemStats(&memAfter)
-
-	return BenchmarkResults{
-		Format:              "Avro JSON",
-		SerializationTime:   elapsed / 2, // Approximate since we do both ser/deser
-		DeserializationTime: elapsed / 2,
-		SerializedSize:      totalSize / iterations,
-		MemoryUsage:         int64(memAfter.TotalAlloc - memBefore.TotalAlloc),
-		ItemsPerSecond:      float64(iterations*2) / elapsed.Seconds(), // ser + deser
-	}, nil
-}
-
-// benchmarkAvroBinary benchmarks Avro binary serialization
-func (pb *PerformanceBenchmark) benchmarkAvroBinary(dataType string) (BenchmarkResults, error) {
 	var memBefore, memAfter runtime.MemStats
 	runtime.GC()
 	runtime.ReadMemStats(&memBefore)
 
-	startTime := time.Now()
-
-	var totalSize int
-	var iterations int
-
-	if dataType == "user" {
-		for _, user := range pb.users {
-			data, err := pb.manager.SerializeUserBinary(user)
-			if err != nil {
-				return BenchmarkResults{}, err
-			}
-			totalSize += len(data)
-			iterations++
-
-			// Test deserialization
-			_, err = pb.manager.DeserializeUserBinary(data)
-			if err != nil {
-				return BenchmarkResults{}, err
-			}
-		}
-	} else {
-		for _, product := range pb.products {
-			data, err := pb.manager.SerializeProductBinary(product)
-			if err != nil {
-				return BenchmarkResults{}, err
-			}
-			totalSize += len(data)
-			iterations++
-
-			// Test deserialization
-			_, err = pb.manager.DeserializeProductBinary(data)
-			if err != nil {
-				return BenchmarkResults{}, err
-			}
-		}
+	payloads := make([][]byte, records)
+	serLatencies, serTime, err := pb.phase(func(i int) (err error) {
+		payloads[i], err = c.serialize(i)
+		return err
+	})
+	if err != nil {
+		return BenchmarkResults{}, fmt.Errorf("failed to serialize: %w", err)
+	}
+	deserLatencies, deserTime, err := pb.phase(func(i int) error {
+		return c.deserialize(payloads[i])
+	})
+	if err != nil {
+		return BenchmarkResults{}, fmt.Errorf("failed to deserialize: %w", err)
 	}
 
-	elapsed := time.Since(startTime)
 	runtime.ReadMemStats(&memAfter)
 
+	var totalSize int
+	for _, payload := range payloads {
+		totalSize += len(payload)
+	}
+
 	return BenchmarkResults{
-		Format:              "Avro Binary",
-		SerializationTime:   elapsed / 2,
-		DeserializationTime: elapsed / 2,
-		SerializedSize:      totalSize / iterations,
+		Format:              c.format,
+		Records:             records,
+		SerializationTime:   serTime,
+		DeserializationTime: deserTime,
+		SerializeLatency:    summarize(serLatencies),
+		DeserializeLatency:  summarize(deserLatencies),
+		SerializedSize:      totalSize / records,
 		MemoryUsage:         int64(memAfter.TotalAlloc - memBefore.TotalAlloc),
-		ItemsPerSecond:      float64(iterations*2) / elapsed.Seconds(),
+		ItemsPerSecond:      float64(records*2) / (serTime + deserTime).Seconds(),
 	}, nil
 }
 
-// benchmarkStandardJSON benchmarks standard Go JSON serialization
-func (pb *PerformanceBenchmark) benchmarkStandardJSON(dataType string) (BenchmarkResults, error) {
-	var memBefore, memAfter runtime.MemStats
-	runtime.GC()
-	runtime.ReadMemStats(&memBefore)
-
-	startTime := time.Now()
-
-	var totalSize int
-	var iterations int
-
-	if dataType == "user" {
-		for _, user := range pb.users {
-			data, err := json.Marshal(user)
-			if err != nil {
-				return BenchmarkResults{}, err
+// phase calls op for every record, split across Parallelism goroutines,
+// returning each call's latency and the wall time of the whole phase
+func (pb *PerformanceBenchmark) phase(op func(i int) error) ([]time.Duration, time.Duration, error) {
+	records, workers := pb.config.Records, min(pb.config.Parallelism, pb.config.Records)
+	latencies := make([]time.Duration, records)
+	errs := make([]error, workers)
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			// Workers take every workers-th record, so each index has one writer
+			for i := w; i < records; i += workers {
+				opStart := time.Now()
+				if err := op(i); err != nil {
+					errs[w] = err
+					return
+				}
+				latencies[i] = time.Since(opStart)
 			}
-			totalSize += len(data)
-			iterations++
+		}(w)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
 
-			// Test deserialization
-			var deserializedUser User
-			err = json.Unmarshal(data, &deserializedUser)
-			if err != nil {
-				return BenchmarkResults{}, err
-			}
+	for _, err := range errs {
+		if err != nil {
+			return nil, 0, err
 		}
-	} else {
-		for _, product := range pb.products {
-			data, err := json.Marshal(product)
-			if err != nil {
-				return BenchmarkResults{}, err
-			}
-			totalSize += len(data)
-			iterations++
+	}
+	return latencies, elapsed, nil
+}
 
-			// Test deserialization
-			var deserializedProduct Product
-			err = json.Unmarshal(data, &deserializedProduct)
-			if err != nil {
-				return BenchmarkResults{}, err
-			}
-		}
+// summarize computes nearest-rank percentiles, sorting latencies in place
+func summarize(latencies []time.Duration) Latencies {
+	if len(latencies) == 0 {
+		return Latencies{}
 	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
 
-	elapsed := time.Since(startTime)
-	runtime.ReadMemStats(&memAfter)
+	var total time.Duration
+	for _, l := range latencies {
+		total += l
+	}
+	return Latencies{
+		Mean: total / time.Duration(len(latencies)),
+		P50:  percentile(latencies, 50),
+		P95:  percentile(latencies, 95),
+		P99:  percentile(latencies, 99),
+		Max:  latencies[len(latencies)-1],
+	}
+}
 
-	return BenchmarkResults{
-		Format:              "Standard JSON",
-		SerializationTime:   elapsed / 2,
-		DeserializationTime: elapsed / 2,
-		SerializedSize:      totalSize / iterations,
-		MemoryUsage:         int64(memAfter.TotalAlloc - memBefore.TotalAlloc),
-		ItemsPerSecond:      float64(iterations*2) / elapsed.Seconds(),
-	}, nil
+// percentile returns the nearest-rank p-th percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p/100*float64(len(sorted))+0.999999) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
 }
 
 // displayResults displays benchmark results in a formatted table
 func (pb *PerformanceBenchmark) displayResults(dataType string, results []BenchmarkResults) {
 	fmt.Printf("\n%s Serialization Performance:\n", dataType)
-	fmt.Printf("%-15s %-12s %-15s %-12s %-15s %-12s\n", 
-		"Format", "Ser Time", "Deser Time", "Size (B)", "Memory (KB)", "Items/sec")
-	fmt.Printf("%-15s %-12s %-15s %-12s %-15s %-12s\n", 
-		"------", "--------", "----------", "--------", "----------", "---------")
+	fmt.Printf("%-15s %-10s %-11s %-26s %-26s %-10s %-12s %-10s\n",
+		"Format", "Ser Time", "Deser Time", "Ser p50/p95/p99", "Deser p50/p95/p99", "Size (B)", "Memory (KB)", "Items/sec")
+	fmt.Printf("%-15s %-10s %-11s %-26s %-26s %-10s %-12s %-10s\n",
+		"------", "--------", "----------", "---------------", "-----------------", "--------", "-----------", "---------")
 
 	for _, result := range results {
-		fmt.Printf("%-15s %-12s %-15s %-12d %-15.1f %-12.0f\n",
+		fmt.Printf("%-15s %-10s %-11s %-26s %-26s %-10d %-12.1f %-10.0f\n",
 			result.Format,
 			formatDuration(result.SerializationTime),
 			formatDuration(result.DeserializationTime),
+			formatLatencies(result.SerializeLatency),
+			formatLatencies(result.DeserializeLatency),
 			result.SerializedSize,
 			float64(result.MemoryUsage)/1024,
 			result.ItemsPerSecond)
@@ -290,7 +336,7 @@ func (pb *PerformanceBenchmark) displayResults(dataType string, results []Benchm
 		for _, result := range results {
 			if result.SerializedSize != baseSize {
 				savings := float64(baseSize-result.SerializedSize) / float64(baseSize) * 100
-				fmt.Printf("  %s vs %s: %.1f%% size difference\n", 
+				fmt.Printf("  %s vs %s: %.1f%% size difference\n",
 					results[0].Format, result.Format, savings)
 			}
 		}
@@ -300,7 +346,7 @@ func (pb *PerformanceBenchmark) displayResults(dataType string, results []Benchm
 // showSummary displays an overall performance summary
 func (pb *PerformanceBenchmark) showSummary(results []BenchmarkResults) {
 	fmt.Println("\n=== Performance Summary ===")
-	
+
 	// Find fastest serializer
 	fastest := results[0]
 	for _, result := range results[1:] {
@@ -310,6 +356,16 @@ func (pb *PerformanceBenchmark) showSummary(results []BenchmarkResults) {
 	}
 	fmt.Printf("✓ Fastest overall: %s (%.0f items/sec)\n", fastest.Format, fastest.ItemsPerSecond)
 
+	// Find the lowest tail latency
+	steadiest := results[0]
+	for _, result := range results[1:] {
+		if result.SerializeLatency.P99 < steadiest.SerializeLatency.P99 {
+			steadiest = result
+		}
+	}
+	fmt.Printf("✓ Lowest p99 serialization latency: %s (%s)\n",
+		steadiest.Format, formatDuration(steadiest.SerializeLatency.P99))
+
 	// Find most memory efficient
 	mostEfficient := results[0]
 	for _, result := range results[1:] {
@@ -317,7 +373,7 @@ func (pb *PerformanceBenchmark) showSummary(results []BenchmarkResults) {
 			mostEfficient = result
 		}
 	}
-	fmt.Printf("✓ Most memory efficient: %s (%d KB)\n", 
+	fmt.Printf("✓ Most memory efficient: %s (%d KB)\n",
 		mostEfficient.Format, mostEfficient.MemoryUsage/1024)
 
 	// Find smallest serialized size
@@ -327,7 +383,7 @@ func (pb *PerformanceBenchmark) showSummary(results []BenchmarkResults) {
 			smallest = result
 		}
 	}
-	fmt.Printf("✓ Smallest serialized size: %s (%d bytes)\n", 
+	fmt.Printf("✓ Smallest serialized size: %s (%d bytes)\n",
 		smallest.Format, smallest.SerializedSize)
 
 	fmt.Println("\nKey Findings:")
@@ -337,6 +393,11 @@ func (pb *PerformanceBenchmark) showSummary(results []BenchmarkResults) {
 	fmt.Println("• Performance varies based on data structure complexity")
 }
 
+// formatLatencies formats the p50, p95 and p99 of l
+func formatLatencies(l Latencies) string {
+	return formatDuration(l.P50) + "/" + formatDuration(l.P95) + "/" + formatDuration(l.P99)
+}
+
 // formatDuration formats duration for display
 func formatDuration(d time.Duration) string {
 	if d < time.Microsecond {
@@ -350,12 +411,13 @@ func formatDuration(d time.Duration) string {
 	}
 }
 
-// RunPerformanceComparison runs the complete performance comparison
+// RunPerformanceComparison runs the complete performance comparison with
+// DefaultBenchmarkConfig
 func RunPerformanceComparison() error {
-	benchmark, err := NewPerformanceBenchmark()
+	benchmark, err := NewPerformanceBenchmark(DefaultBenchmarkConfig())
 	if err != nil {
 		return fmt.Errorf("failed to create benchmark: %w", err)
 	}
 
 	return benchmark.RunBenchmarks()
-}
\ No newline at end of file
+}
@@ -0,0 +1,61 @@
+package avro
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPerformanceBenchmark(t *testing.T) {
+	pb, err := NewPerformanceBenchmark(BenchmarkConfig{Records: 40, Parallelism: 4})
+	if err != nil {
+		t.Fatalf("Failed to create benchmark: %v", err)
+	}
+	if cfg := pb.Config(); cfg.Records != 40 || cfg.Parallelism != 4 || cfg.Warmup != 0 {
+		t.Errorf("Expected the configured dataset and parallelism, got %+v", cfg)
+	}
+
+	for _, dataType := range []string{"user", "product"} {
+		results, err := pb.Results(dataType)
+		if err != nil {
+			t.Fatalf("Failed to benchmark %s: %v", dataType, err)
+		}
+		if len(results) != 3+len(Codecs())-1 || results[1].Format != "Avro Binary" {
+			t.Fatalf("Expected every format, got %d results", len(results))
+		}
+		for _, r := range results {
+			if r.Records != 40 || r.SerializationTime <= 0 || r.DeserializationTime <= 0 || r.SerializedSize <= 0 {
+				t.Errorf("%s %s: expected both phases timed, got %+v", dataType, r.Format, r)
+			}
+			for _, l := range []Latencies{r.SerializeLatency, r.DeserializeLatency} {
+				if l.P50 <= 0 || l.P50 > l.P95 || l.P95 > l.P99 || l.P99 > l.Max {
+					t.Errorf("%s %s: expected ordered percentiles, got %+v", dataType, r.Format, l)
+				}
+			}
+		}
+	}
+
+	if _, err := pb.Results("order"); err == nil {
+		t.Error("Expected an unknown data type to fail")
+	}
+
+	t.Log("✓ Serialization and deserialization are timed separately with percentiles")
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[99-i] = time.Duration(i+1) * time.Millisecond
+	}
+	l := summarize(latencies)
+	if l.P50 != 50*time.Millisecond || l.P95 != 95*time.Millisecond || l.P99 != 99*time.Millisecond || l.Max != 100*time.Millisecond {
+		t.Errorf("Expected nearest-rank percentiles, got %+v", l)
+	}
+	if l.Mean != 50500*time.Microsecond {
+		t.Errorf("Expected a mean of 50.5ms, got %v", l.Mean)
+	}
+	if one := summarize([]time.Duration{time.Second}); one.P50 != time.Second || one.P99 != time.Second {
+		t.Errorf("Expected a single latency at every percentile, got %+v", one)
+	}
+
+	t.Log("✓ Percentiles use the nearest rank")
+}
@@ -0,0 +1,110 @@
+package avro
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hamba/avro/v2"
+)
+
+// maxPooledBuffer is the largest buffer returned to a pool, so one huge
+// record or batch does not pin its memory for the life of the process
+const maxPooledBuffer = 1 << 20
+
+// schemaCodec pools the Avro writers and readers of one schema. hamba/avro
+// already caches the compiled encoders per type; the pools spare each call a
+// new Encoder, Decoder and buffer
+type schemaCodec struct {
+	schema  avro.Schema
+	writers sync.Pool
+	readers sync.Pool
+}
+
+func newSchemaCodec(schema avro.Schema) *schemaCodec {
+	c := &schemaCodec{schema: schema}
+	c.writers.New = func() any { return avro.NewWriter(nil, 512, avro.WithWriterConfig(decodeAPI)) }
+	c.readers.New = func() any { return avro.NewReader(nil, 0, avro.WithReaderConfig(decodeAPI)) }
+	return c
+}
+
+// codec returns the pooled codec of schema, creating it on first use. Safe
+// for concurrent use
+func (m *Manager) codec(schema avro.Schema) *schemaCodec {
+	if c, ok := m.codecs.Load(schema); ok {
+		return c.(*schemaCodec)
+	}
+	c, _ := m.codecs.LoadOrStore(schema, newSchemaCodec(schema))
+	return c.(*schemaCodec)
+}
+
+// Encode encodes v to binary Avro under schema with a pooled encoder cached
+// for the schema. v may be a tagged struct, a pointer to one, or the generic
+// value hamba/avro encodes. Safe for concurrent use
+func (m *Manager) Encode(schema avro.Schema, v interface{}) ([]byte, error) {
+	data, err := m.codec(schema).encode(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode: %w", err)
+	}
+	return data, nil
+}
+
+// Decode decodes binary Avro under schema into the value v points to, with
+// a pooled decoder cached for the schema. Safe for concurrent use
+func (m *Manager) Decode(schema avro.Schema, data []byte, v interface{}) error {
+	if err := m.codec(schema).decode(data, v); err != nil {
+		return fmt.Errorf("failed to decode: %w", err)
+	}
+	return nil
+}
+
+// encode returns a copy of v's encoding, so the writer can go back to the pool
+func (c *schemaCodec) encode(v interface{}) ([]byte, error) {
+	writer := c.getWriter()
+	defer c.putWriter(writer)
+
+	writer.WriteVal(c.schema, v)
+	if writer.Error != nil {
+		return nil, writer.Error
+	}
+	data := make([]byte, writer.Buffered())
+	copy(data, writer.Buffer())
+	return data, nil
+}
+
+func (c *schemaCodec) decode(data []byte, v interface{}) error {
+	reader := c.getReader(data)
+	defer c.putReader(reader)
+
+	reader.ReadVal(c.schema, v)
+	return reader.Error
+}
+
+func (c *schemaCodec) getWriter() *avro.Writer {
+	writer := c.writers.Get().(*avro.Writer)
+	writer.Reset(nil)
+	writer.Error = nil
+	return writer
+}
+
+func (c *schemaCodec) putWriter(writer *avro.Writer) {
+	if cap(writer.Buffer()) > maxPooledBuffer {
+		return
+	}
+	writer.Reset(nil)
+	writer.Error = nil
+	c.writers.Put(writer)
+}
+
+func (c *schemaCodec) getReader(data []byte) *avro.Reader {
+	reader := c.readers.Get().(*avro.Reader)
+	reader.Reset(data)
+	reader.Error = nil
+	return reader
+}
+
+// putReader drops the reader's reference to the decoded data
+func (c *schemaCodec) putReader(reader *avro.Reader) {
+	reader.Reset(nil)
+	reader.Error = nil
+	c.readers.Put(reader)
+}
@@ -0,0 +1,283 @@
+package avro
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hamba/avro/v2"
+)
+
+// IncompatibilityType classifies why a reader schema cannot resolve data written with a writer schema
+type IncompatibilityType string
+
+const (
+	IncompatibilityTypeMismatch         IncompatibilityType = "TYPE_MISMATCH"
+	IncompatibilityNameMismatch         IncompatibilityType = "NAME_MISMATCH"
+	IncompatibilityFixedSizeMismatch    IncompatibilityType = "FIXED_SIZE_MISMATCH"
+	IncompatibilityMissingEnumSymbols   IncompatibilityType = "MISSING_ENUM_SYMBOLS"
+	IncompatibilityMissingUnionBranch   IncompatibilityType = "MISSING_UNION_BRANCH"
+	IncompatibilityReaderFieldNoDefault IncompatibilityType = "READER_FIELD_MISSING_DEFAULT_VALUE"
+)
+
+// Incompatibility describes a single schema resolution failure
+type Incompatibility struct {
+	Type       IncompatibilityType `json:"type"`
+	Path       string              `json:"path"`
+	Message    string              `json:"message"`
+	ReaderType string              `json:"readerType"`
+	WriterType string              `json:"writerType"`
+	// Direction is "backward" when the new schema failed to read old data,
+	// "forward" when an old schema failed to read new data
+	Direction string `json:"direction,omitempty"`
+	// Version is the registered version the new schema was checked against
+	Version int `json:"version,omitempty"`
+}
+
+// String returns a human-readable description of the incompatibility
+func (i Incompatibility) String() string {
+	var prefix string
+	if i.Direction != "" {
+		prefix = fmt.Sprintf("[%s v%d] ", i.Direction, i.Version)
+	}
+	return fmt.Sprintf("%s%s at %s: %s", prefix, i.Type, i.Path, i.Message)
+}
+
+// CompatibilityReport contains the outcome of checking a schema against a subject
+type CompatibilityReport struct {
+	Subject           string             `json:"subject"`
+	Level             CompatibilityLevel `json:"level"`
+	Compatible        bool               `json:"compatible"`
+	CheckedVersions   []int              `json:"checkedVersions"`
+	Incompatibilities []Incompatibility  `json:"incompatibilities,omitempty"`
+}
+
+// AddIncompatibilities records incompatibilities found against a registered version,
+// tagging each with the direction ("backward" or "forward") and version
+func (r *CompatibilityReport) AddIncompatibilities(direction string, version int, incompatibilities []Incompatibility) {
+	for _, inc := range incompatibilities {
+		inc.Direction = direction
+		inc.Version = version
+		r.Incompatibilities = append(r.Incompatibilities, inc)
+	}
+}
+
+// CompatibilityError is returned by RegisterSchema when the new schema violates the subject's compatibility level
+type CompatibilityError struct {
+	Report *CompatibilityReport
+}
+
+// Error implements the error interface
+func (e *CompatibilityError) Error() string {
+	messages := make([]string, len(e.Report.Incompatibilities))
+	for i, inc := range e.Report.Incompatibilities {
+		messages[i] = inc.String()
+	}
+	return fmt.Sprintf("schema is not %s compatible with subject %s: %s",
+		e.Report.Level, e.Report.Subject, strings.Join(messages, "; "))
+}
+
+// CheckReaderWriterCompatibility applies the Avro schema resolution rules and reports
+// every reason the reader schema cannot decode data written with the writer schema.
+// An empty result means the schemas are compatible in that direction.
+func CheckReaderWriterCompatibility(reader, writer avro.Schema) []Incompatibility {
+	checker := &compatibilityChecker{seen: make(map[string]bool)}
+	checker.check(reader, writer, "$")
+	return checker.incompatibilities
+}
+
+// compatibilityChecker walks a reader/writer schema pair collecting incompatibilities
+type compatibilityChecker struct {
+	seen              map[string]bool
+	incompatibilities []Incompatibility
+}
+
+func (c *compatibilityChecker) add(typ IncompatibilityType, path string, reader, writer avro.Schema, format string, args ...interface{}) {
+	c.incompatibilities = append(c.incompatibilities, Incompatibility{
+		Type:       typ,
+		Path:       path,
+		Message:    fmt.Sprintf(format, args...),
+		ReaderType: schemaTypeName(reader),
+		WriterType: schemaTypeName(writer),
+	})
+}
+
+// matches reports whether reader can read writer without recording anything
+func (c *compatibilityChecker) matches(reader, writer avro.Schema) bool {
+	probe := &compatibilityChecker{seen: make(map[string]bool)}
+	for k, v := range c.seen {
+		probe.seen[k] = v
+	}
+	probe.check(reader, writer, "")
+	return len(probe.incompatibilities) == 0
+}
+
+func (c *compatibilityChecker) check(reader, writer avro.Schema, path string) {
+	reader = derefSchema(reader)
+	writer = derefSchema(writer)
+
+	// Every branch the writer may have produced must be readable
+	if writerUnion, ok := writer.(*avro.UnionSchema); ok {
+		for _, branch := range writerUnion.Types() {
+			branch = derefSchema(branch)
+			if _, readerIsUnion := reader.(*avro.UnionSchema); readerIsUnion || reader.Type() == branch.Type() {
+				c.check(reader, branch, path)
+				continue
+			}
+			if !c.matches(reader, branch) {
+				c.add(IncompatibilityMissingUnionBranch, path, reader, branch,
+					"reader cannot read writer union branch %s", schemaTypeName(branch))
+			}
+		}
+		return
+	}
+
+	// The writer's type must match at least one reader branch
+	if readerUnion, ok := reader.(*avro.UnionSchema); ok {
+		for _, branch := range readerUnion.Types() {
+			if c.matches(branch, writer) {
+				c.check(branch, writer, path)
+				return
+			}
+		}
+		c.add(IncompatibilityMissingUnionBranch, path, reader, writer,
+			"reader union has no branch for writer type %s", schemaTypeName(writer))
+		return
+	}
+
+	if reader.Type() != writer.Type() {
+		if !isPromotable(writer.Type(), reader.Type()) {
+			c.add(IncompatibilityTypeMismatch, path, reader, writer,
+				"writer type %s cannot be promoted to reader type %s", writer.Type(), reader.Type())
+		}
+		return
+	}
+
+	switch r := reader.(type) {
+	case *avro.ArraySchema:
+		c.check(r.Items(), writer.(*avro.ArraySchema).Items(), path+"[]")
+	case *avro.MapSchema:
+		c.check(r.Values(), writer.(*avro.MapSchema).Values(), path+"{}")
+	case *avro.FixedSchema:
+		w := writer.(*avro.FixedSchema)
+		if !c.checkName(r, w, path) {
+			return
+		}
+		if r.Size() != w.Size() {
+			c.add(IncompatibilityFixedSizeMismatch, path, reader, writer,
+				"expected fixed size %d, writer has %d", r.Size(), w.Size())
+		}
+	case *avro.EnumSchema:
+		w := writer.(*avro.EnumSchema)
+		if !c.checkName(r, w, path) {
+			return
+		}
+		var missing []string
+		for _, symbol := range w.Symbols() {
+			if !containsString(r.Symbols(), symbol) {
+				missing = append(missing, symbol)
+			}
+		}
+		if len(missing) > 0 && !r.HasDefault() {
+			c.add(IncompatibilityMissingEnumSymbols, path, reader, writer,
+				"reader enum %s is missing symbols %v and has no default", r.FullName(), missing)
+		}
+	case *avro.RecordSchema:
+		w := writer.(*avro.RecordSchema)
+		if !c.checkName(r, w, path) {
+			return
+		}
+
+		// Recursive records are only walked once per reader/writer pair
+		key := r.FullName() + "<-" + w.FullName()
+		if c.seen[key] {
+			return
+		}
+		c.seen[key] = true
+
+		for _, readerField := range r.Fields() {
+			fieldPath := path + "." + readerField.Name()
+			writerField := findWriterField(w, readerField)
+			if writerField == nil {
+				if !readerField.HasDefault() {
+					c.add(IncompatibilityReaderFieldNoDefault, fieldPath, readerField.Type(), nil,
+						"reader field %s is missing from writer schema and has no default", readerField.Name())
+				}
+				continue
+			}
+			c.check(readerField.Type(), writerField.Type(), fieldPath)
+		}
+	}
+}
+
+// checkName verifies named types match by name or reader alias
+func (c *compatibilityChecker) checkName(reader, writer avro.NamedSchema, path string) bool {
+	if reader.Name() == writer.Name() || containsString(reader.Aliases(), writer.FullName()) {
+		return true
+	}
+	c.add(IncompatibilityNameMismatch, path, reader, writer,
+		"reader name %s does not match writer name %s", reader.FullName(), writer.FullName())
+	return false
+}
+
+// findWriterField locates the writer field for a reader field by name or by reader alias
+func findWriterField(writer *avro.RecordSchema, readerField *avro.Field) *avro.Field {
+	for _, f := range writer.Fields() {
+		if f.Name() == readerField.Name() {
+			return f
+		}
+	}
+	for _, f := range writer.Fields() {
+		if containsString(readerField.Aliases(), f.Name()) {
+			return f
+		}
+	}
+	return nil
+}
+
+// derefSchema resolves named references to their definitions
+func derefSchema(schema avro.Schema) avro.Schema {
+	if ref, ok := schema.(*avro.RefSchema); ok {
+		return ref.Schema()
+	}
+	return schema
+}
+
+// isPromotable reports whether the Avro spec allows reading writerType as readerType
+func isPromotable(writerType, readerType avro.Type) bool {
+	switch writerType {
+	case avro.Int:
+		return readerType == avro.Long || readerType == avro.Float || readerType == avro.Double
+	case avro.Long:
+		return readerType == avro.Float || readerType == avro.Double
+	case avro.Float:
+		return readerType == avro.Double
+	case avro.String:
+		return readerType == avro.Bytes
+	case avro.Bytes:
+		return readerType == avro.String
+	default:
+		return false
+	}
+}
+
+// schemaTypeName returns a short description of a schema for reports
+func schemaTypeName(schema avro.Schema) string {
+	if schema == nil {
+		return ""
+	}
+	schema = derefSchema(schema)
+	if named, ok := schema.(avro.NamedSchema); ok {
+		return fmt.Sprintf("%s<%s>", schema.Type(), named.FullName())
+	}
+	return string(schema.Type())
+}
+
+// containsString checks if a slice contains a string
+func containsString(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,236 @@
+package avro
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+)
+
+const compatBaseSchema = `{
+	"type": "record",
+	"name": "Item",
+	"namespace": "com.example.test",
+	"fields": [
+		{"name": "id", "type": "int"},
+		{"name": "name", "type": "string"}
+	]
+}`
+
+func mustParseSchema(t *testing.T, schemaJSON string) avro.Schema {
+	t.Helper()
+	schema, err := avro.Parse(schemaJSON)
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	return schema
+}
+
+func TestCheckReaderWriterCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		reader   string
+		writer   string
+		wantType IncompatibilityType
+	}{
+		{
+			name:   "identical schemas",
+			reader: compatBaseSchema,
+			writer: compatBaseSchema,
+		},
+		{
+			name: "reader adds field with default",
+			reader: `{"type": "record", "name": "Item", "namespace": "com.example.test", "fields": [
+				{"name": "id", "type": "int"},
+				{"name": "name", "type": "string"},
+				{"name": "tag", "type": "string", "default": "none"}]}`,
+			writer: compatBaseSchema,
+		},
+		{
+			name: "reader adds field without default",
+			reader: `{"type": "record", "name": "Item", "namespace": "com.example.test", "fields": [
+				{"name": "id", "type": "int"},
+				{"name": "name", "type": "string"},
+				{"name": "tag", "type": "string"}]}`,
+			writer:   compatBaseSchema,
+			wantType: IncompatibilityReaderFieldNoDefault,
+		},
+		{
+			name: "reader drops field",
+			reader: `{"type": "record", "name": "Item", "namespace": "com.example.test", "fields": [
+				{"name": "id", "type": "int"}]}`,
+			writer: compatBaseSchema,
+		},
+		{
+			name: "int promoted to long",
+			reader: `{"type": "record", "name": "Item", "namespace": "com.example.test", "fields": [
+				{"name": "id", "type": "long"},
+				{"name": "name", "type": "string"}]}`,
+			writer: compatBaseSchema,
+		},
+		{
+			name:   "long cannot narrow to int",
+			reader: compatBaseSchema,
+			writer: `{"type": "record", "name": "Item", "namespace": "com.example.test", "fields": [
+				{"name": "id", "type": "long"},
+				{"name": "name", "type": "string"}]}`,
+			wantType: IncompatibilityTypeMismatch,
+		},
+		{
+			name: "reader widens field to union",
+			reader: `{"type": "record", "name": "Item", "namespace": "com.example.test", "fields": [
+				{"name": "id", "type": "int"},
+				{"name": "name", "type": ["null", "string"]}]}`,
+			writer: compatBaseSchema,
+		},
+		{
+			name:   "writer union branch unreadable",
+			reader: compatBaseSchema,
+			writer: `{"type": "record", "name": "Item", "namespace": "com.example.test", "fields": [
+				{"name": "id", "type": "int"},
+				{"name": "name", "type": ["null", "string"]}]}`,
+			wantType: IncompatibilityMissingUnionBranch,
+		},
+		{
+			name: "field renamed with alias",
+			reader: `{"type": "record", "name": "Item", "namespace": "com.example.test", "fields": [
+				{"name": "id", "type": "int"},
+				{"name": "title", "type": "string", "aliases": ["name"]}]}`,
+			writer: compatBaseSchema,
+		},
+		{
+			name: "record renamed without alias",
+			reader: `{"type": "record", "name": "Article", "namespace": "com.example.test", "fields": [
+				{"name": "id", "type": "int"},
+				{"name": "name", "type": "string"}]}`,
+			writer:   compatBaseSchema,
+			wantType: IncompatibilityNameMismatch,
+		},
+		{
+			name:   "reader enum adds symbol",
+			reader: `{"type": "enum", "name": "Status", "symbols": ["ACTIVE", "INACTIVE", "ARCHIVED"]}`,
+			writer: `{"type": "enum", "name": "Status", "symbols": ["ACTIVE", "INACTIVE"]}`,
+		},
+		{
+			name:     "reader enum missing symbol",
+			reader:   `{"type": "enum", "name": "Status", "symbols": ["ACTIVE", "INACTIVE"]}`,
+			writer:   `{"type": "enum", "name": "Status", "symbols": ["ACTIVE", "INACTIVE", "ARCHIVED"]}`,
+			wantType: IncompatibilityMissingEnumSymbols,
+		},
+		{
+			name:   "reader enum missing symbol with default",
+			reader: `{"type": "enum", "name": "Status", "symbols": ["ACTIVE", "INACTIVE"], "default": "INACTIVE"}`,
+			writer: `{"type": "enum", "name": "Status", "symbols": ["ACTIVE", "INACTIVE", "ARCHIVED"]}`,
+		},
+		{
+			name:     "fixed size change",
+			reader:   `{"type": "fixed", "name": "Hash", "size": 16}`,
+			writer:   `{"type": "fixed", "name": "Hash", "size": 32}`,
+			wantType: IncompatibilityFixedSizeMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckReaderWriterCompatibility(mustParseSchema(t, tt.reader), mustParseSchema(t, tt.writer))
+
+			if tt.wantType == "" {
+				if len(got) != 0 {
+					t.Errorf("Expected compatible, got %v", got)
+				}
+				return
+			}
+
+			if len(got) == 0 {
+				t.Fatalf("Expected %s, got compatible", tt.wantType)
+			}
+			if got[0].Type != tt.wantType {
+				t.Errorf("Expected %s, got %s", tt.wantType, got[0])
+			}
+		})
+	}
+
+	t.Log("✓ Reader/writer compatibility rules verified")
+}
+
+func TestRegistryRejectsIncompatibleSchema(t *testing.T) {
+	registry := NewSchemaRegistry()
+
+	if _, err := registry.RegisterSchema("item", compatBaseSchema); err != nil {
+		t.Fatalf("Failed to register base schema: %v", err)
+	}
+
+	// Adding a required field breaks backward compatibility
+	breaking := `{"type": "record", "name": "Item", "namespace": "com.example.test", "fields": [
+		{"name": "id", "type": "int"},
+		{"name": "name", "type": "string"},
+		{"name": "price", "type": "double"}]}`
+
+	_, err := registry.RegisterSchema("item", breaking)
+	if err == nil {
+		t.Fatal("Expected registration to fail")
+	}
+
+	var compatErr *CompatibilityError
+	if !errors.As(err, &compatErr) {
+		t.Fatalf("Expected CompatibilityError, got %T: %v", err, err)
+	}
+	if len(compatErr.Report.Incompatibilities) != 1 {
+		t.Fatalf("Expected 1 incompatibility, got %d", len(compatErr.Report.Incompatibilities))
+	}
+
+	inc := compatErr.Report.Incompatibilities[0]
+	if inc.Direction != "backward" || inc.Version != 1 || inc.Path != "$.price" {
+		t.Errorf("Unexpected incompatibility: %s", inc)
+	}
+
+	t.Log("✓ Registry rejected incompatible schema with report")
+}
+
+func TestRegistryTransitiveCompatibility(t *testing.T) {
+	v1 := compatBaseSchema
+	// v2 drops name, v3 re-adds it as a long: compatible with v2 only
+	v2 := `{"type": "record", "name": "Item", "namespace": "com.example.test", "fields": [
+		{"name": "id", "type": "int"}]}`
+	v3 := `{"type": "record", "name": "Item", "namespace": "com.example.test", "fields": [
+		{"name": "id", "type": "int"},
+		{"name": "name", "type": "long", "default": 0}]}`
+
+	tests := []struct {
+		level          CompatibilityLevel
+		wantCompatible bool
+		wantChecked    int
+	}{
+		{CompatibilityBackward, true, 1},
+		{CompatibilityBackwardTransitive, false, 2},
+		{CompatibilityFullTransitive, false, 2},
+		{CompatibilityNone, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.level), func(t *testing.T) {
+			registry := NewSchemaRegistry()
+			registry.SetCompatibilityLevel("item", CompatibilityNone)
+			for _, schema := range []string{v1, v2} {
+				if _, err := registry.RegisterSchema("item", schema); err != nil {
+					t.Fatalf("Failed to register schema: %v", err)
+				}
+			}
+			registry.SetCompatibilityLevel("item", tt.level)
+
+			report, err := registry.CheckCompatibilityReport("item", v3)
+			if err != nil {
+				t.Fatalf("Failed to check compatibility: %v", err)
+			}
+
+			if report.Compatible != tt.wantCompatible {
+				t.Errorf("Expected compatible=%v, got %v: %v", tt.wantCompatible, report.Compatible, report.Incompatibilities)
+			}
+			if len(report.CheckedVersions) != tt.wantChecked {
+				t.Errorf("Expected %d checked versions, got %v", tt.wantChecked, report.CheckedVersions)
+			}
+		})
+	}
+
+	t.Log("✓ Transitive compatibility levels verified")
+}
@@ -0,0 +1,126 @@
+package avro
+
+import (
+	"fmt"
+
+	"go-transport-prac/internal/compress"
+)
+
+// Codec names how a binary Avro payload is compressed. Compressed payloads
+// start with a two byte header: compressedMagic followed by the codec id, so
+// readers decompress without being told the codec
+type Codec string
+
+const (
+	CodecNone    Codec = "none"
+	CodecDeflate Codec = "deflate"
+	CodecSnappy  Codec = "snappy"
+	CodecZstd    Codec = "zstd"
+)
+
+// compressedMagic marks a payload written by CompressPayload
+const compressedMagic byte = 0xAC
+
+// compressedHeaderSize is the length of the magic and codec id prefix
+const compressedHeaderSize = 2
+
+// MaxDecompressedSize is the largest payload DecompressPayload inflates, so a
+// small crafted payload cannot claim unbounded memory
+const MaxDecompressedSize = compress.MaxDecompressedSize
+
+// payloadCodec pairs a Codec's header id with its compression
+type payloadCodec struct {
+	id byte
+	compress.Codec
+}
+
+var payloadCodecs = map[Codec]payloadCodec{
+	CodecNone:    {id: 0, Codec: compress.None},
+	CodecDeflate: {id: 1, Codec: compress.Deflate},
+	CodecSnappy:  {id: 2, Codec: compress.Snappy},
+	CodecZstd:    {id: 3, Codec: compress.Zstd},
+}
+
+// Codecs returns the supported payload codecs, uncompressed first
+func Codecs() []Codec {
+	return []Codec{CodecNone, CodecDeflate, CodecSnappy, CodecZstd}
+}
+
+// CompressPayload compresses data with codec and prefixes the codec header
+func CompressPayload(data []byte, codec Codec) ([]byte, error) {
+	if codec == "" {
+		codec = CodecNone
+	}
+	c, ok := payloadCodecs[codec]
+	if !ok {
+		return nil, fmt.Errorf("unsupported codec %q", codec)
+	}
+
+	compressed, err := c.Compress(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress payload with %s: %w", codec, err)
+	}
+
+	out := make([]byte, 0, compressedHeaderSize+len(compressed))
+	out = append(out, compressedMagic, c.id)
+	return append(out, compressed...), nil
+}
+
+// DecompressPayload reads the codec header and returns the decompressed
+// payload together with the codec it was written with
+func DecompressPayload(data []byte) ([]byte, Codec, error) {
+	if len(data) < compressedHeaderSize || data[0] != compressedMagic {
+		return nil, "", fmt.Errorf("payload has no compression header")
+	}
+
+	for codec, c := range payloadCodecs {
+		if c.id != data[1] {
+			continue
+		}
+		payload, err := c.Decompress(data[compressedHeaderSize:])
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to decompress %s payload: %w", codec, err)
+		}
+		return payload, codec, nil
+	}
+
+	return nil, "", fmt.Errorf("unknown codec id %d", data[1])
+}
+
+// SerializeUserBinaryCompressed serializes a user to binary Avro and compresses it with codec
+func (m *Manager) SerializeUserBinaryCompressed(user User, codec Codec) ([]byte, error) {
+	data, err := m.SerializeUserBinary(user)
+	if err != nil {
+		return nil, err
+	}
+	return CompressPayload(data, codec)
+}
+
+// DeserializeUserBinaryCompressed decompresses a payload written by
+// SerializeUserBinaryCompressed and deserializes the user
+func (m *Manager) DeserializeUserBinaryCompressed(data []byte) (User, error) {
+	payload, _, err := DecompressPayload(data)
+	if err != nil {
+		return User{}, err
+	}
+	return m.DeserializeUserBinary(payload)
+}
+
+// SerializeProductBinaryCompressed serializes a product to binary Avro and compresses it with codec
+func (m *Manager) SerializeProductBinaryCompressed(product Product, codec Codec) ([]byte, error) {
+	data, err := m.SerializeProductBinary(product)
+	if err != nil {
+		return nil, err
+	}
+	return CompressPayload(data, codec)
+}
+
+// DeserializeProductBinaryCompressed decompresses a payload written by
+// SerializeProductBinaryCompressed and deserializes the product
+func (m *Manager) DeserializeProductBinaryCompressed(data []byte) (Product, error) {
+	payload, _, err := DecompressPayload(data)
+	if err != nil {
+		return Product{}, err
+	}
+	return m.DeserializeProductBinary(payload)
+}
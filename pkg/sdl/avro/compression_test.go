@@ -0,0 +1,115 @@
+package avro
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompressedUserRoundTrip(t *testing.T) {
+	manager, err := NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	user := manager.CreateSampleUsers(1)[0]
+	plain, err := manager.SerializeUserBinary(user)
+	if err != nil {
+		t.Fatalf("Failed to serialize user: %v", err)
+	}
+
+	for _, codec := range Codecs() {
+		data, err := manager.SerializeUserBinaryCompressed(user, codec)
+		if err != nil {
+			t.Fatalf("%s: failed to serialize user: %v", codec, err)
+		}
+		if data[0] != compressedMagic {
+			t.Errorf("%s: expected compression header, got %x", codec, data[:2])
+		}
+
+		// Map fields encode in random order, so compare bytes against plain compressed directly
+		compressed, err := CompressPayload(plain, codec)
+		if err != nil {
+			t.Fatalf("%s: failed to compress: %v", codec, err)
+		}
+		payload, read, err := DecompressPayload(compressed)
+		if err != nil {
+			t.Fatalf("%s: failed to decompress: %v", codec, err)
+		}
+		if read != codec || !reflect.DeepEqual(payload, plain) {
+			t.Errorf("%s: header recorded %s, payload changed: %v", codec, read, !reflect.DeepEqual(payload, plain))
+		}
+
+		decoded, err := manager.DeserializeUserBinaryCompressed(data)
+		if err != nil {
+			t.Fatalf("%s: failed to deserialize user: %v", codec, err)
+		}
+		if decoded.ID != user.ID || decoded.Email != user.Email || decoded.Name != user.Name {
+			t.Errorf("%s: user mismatch: %+v", codec, decoded)
+		}
+		t.Logf("%-8s %d bytes (plain %d)", codec, len(data), len(plain))
+	}
+
+	// Products use the same header
+	product := manager.CreateSampleProducts(1)[0]
+	data, err := manager.SerializeProductBinaryCompressed(product, CodecZstd)
+	if err != nil {
+		t.Fatalf("Failed to serialize product: %v", err)
+	}
+	decodedProduct, err := manager.DeserializeProductBinaryCompressed(data)
+	if err != nil || decodedProduct.ID != product.ID {
+		t.Errorf("Product mismatch: %+v (%v)", decodedProduct, err)
+	}
+
+	if _, err := manager.SerializeUserBinaryCompressed(user, Codec("lz4")); err == nil {
+		t.Error("Expected error for unsupported codec")
+	}
+	for _, bad := range [][]byte{nil, plain, {compressedMagic, 99}} {
+		if _, err := manager.DeserializeUserBinaryCompressed(bad); err == nil {
+			t.Errorf("Expected error decompressing %x", bad)
+		}
+	}
+
+	t.Log("✓ Compressed user and product payloads round-trip with every codec")
+}
+
+func BenchmarkUserCompression(b *testing.B) {
+	manager, err := NewManager("")
+	if err != nil {
+		b.Fatal(err)
+	}
+	user := manager.CreateSampleUsers(1)[0]
+
+	for _, codec := range Codecs() {
+		b.Run(string(codec), func(b *testing.B) {
+			b.ReportAllocs()
+			var size int
+			for i := 0; i < b.N; i++ {
+				data, err := manager.SerializeUserBinaryCompressed(user, codec)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := manager.DeserializeUserBinaryCompressed(data); err != nil {
+					b.Fatal(err)
+				}
+				size = len(data)
+			}
+			b.ReportMetric(float64(size), "bytes/op-payload")
+		})
+	}
+}
+
+func TestDecompressPayloadLimit(t *testing.T) {
+	oversized := make([]byte, MaxDecompressedSize+1)
+	for _, codec := range []Codec{CodecDeflate, CodecSnappy, CodecZstd} {
+		data, err := CompressPayload(oversized, codec)
+		if err != nil {
+			t.Fatalf("%s: failed to compress: %v", codec, err)
+		}
+		if _, _, err := DecompressPayload(data); err == nil || !strings.Contains(err.Error(), "exceeds") {
+			t.Errorf("%s: expected a %d byte payload to be rejected, got %v", codec, len(oversized), err)
+		}
+		t.Logf("%-8s rejected %d bytes compressed to %d", codec, len(oversized), len(data))
+	}
+
+	t.Log("✓ Payloads inflating past the size limit are rejected")
+}
@@ -0,0 +1,97 @@
+package avro
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/catalog"
+	"go-transport-prac/pkg/storage"
+)
+
+func TestConcurrentWriters(t *testing.T) {
+	testDir := "tmp/test_concurrent_avro"
+	defer os.RemoveAll(testDir)
+
+	backends := map[string]types.Storage{"local": nil, "storage": storage.NewMemoryStorage()}
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			files := catalog.New()
+			// Separately created managers of the same directory share locks
+			managers := make([]*Manager, 2)
+			for i := range managers {
+				m, err := NewManager(testDir)
+				if err != nil {
+					t.Fatalf("Failed to create manager: %v", err)
+				}
+				if backend != nil {
+					m.WithStorage(backend)
+				}
+				managers[i] = m.WithChecksums(true).WithCatalog(files)
+			}
+			if err := managers[0].WriteUsersToFile("users.avro", managers[0].CreateSampleUsers(1)); err != nil {
+				t.Fatalf("Failed to write users: %v", err)
+			}
+
+			const writers, readers, rounds = 8, 4, 20
+			var wg sync.WaitGroup
+			errs := make(chan error, (writers+readers)*rounds)
+			for w := 0; w < writers; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					m := managers[w%len(managers)]
+					for r := 0; r < rounds; r++ {
+						if err := m.WriteUsersToFile("users.avro", m.CreateSampleUsers(50*(w+1))); err != nil {
+							errs <- fmt.Errorf("writer %d: %w", w, err)
+						}
+					}
+				}(w)
+			}
+			for r := 0; r < readers; r++ {
+				wg.Add(1)
+				go func(r int) {
+					defer wg.Done()
+					m := managers[r%len(managers)]
+					for i := 0; i < rounds; i++ {
+						users, err := m.ReadUsersFromFile("users.avro")
+						if err != nil {
+							errs <- fmt.Errorf("reader %d: %w", r, err)
+						} else if len(users) < 1 || len(users) > 50*writers {
+							errs <- fmt.Errorf("reader %d: read %d users", r, len(users))
+						}
+						if err := m.VerifyFile("users.avro"); err != nil {
+							errs <- fmt.Errorf("reader %d: %w", r, err)
+						}
+					}
+				}(r)
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				t.Error(err)
+			}
+
+			// The file, its checksum and its catalog entry come from the same write
+			users, err := managers[1].ReadUsersFromFile("users.avro")
+			if err != nil {
+				t.Fatalf("Failed to read users: %v", err)
+			}
+			if err := managers[1].VerifyFile("users.avro"); err != nil {
+				t.Errorf("Expected the last write's checksum: %v", err)
+			}
+			entry, _ := files.Get("users.avro")
+			info, err := managers[1].StatFile("users.avro")
+			if err != nil {
+				t.Fatalf("Failed to stat file: %v", err)
+			}
+			if entry.Records != int64(len(users)) || (backend == nil && entry.Bytes != info.Size()) {
+				t.Errorf("Expected the catalog to describe the last write, got %+v for %d users", entry, len(users))
+			}
+		})
+	}
+
+	t.Log("✓ Concurrent writers leave a consistent file, checksum and catalog entry")
+}
@@ -0,0 +1,93 @@
+package avro
+
+import (
+	"context"
+
+	"go-transport-prac/internal/tracing"
+)
+
+// SerializeUserBinaryContext serializes a user to binary using Avro, failing
+// with ctx.Err() when ctx is already done
+func (m *Manager) SerializeUserBinaryContext(ctx context.Context, user User) ([]byte, error) {
+	return withContext(ctx, "avro.serialize", "user", user, m.SerializeUserBinary)
+}
+
+// DeserializeUserBinaryContext deserializes a user from binary using Avro,
+// failing with ctx.Err() when ctx is already done
+func (m *Manager) DeserializeUserBinaryContext(ctx context.Context, data []byte) (User, error) {
+	return withContext(ctx, "avro.deserialize", "user", data, m.DeserializeUserBinary)
+}
+
+// SerializeUserJSONContext serializes a user to JSON using Avro schema,
+// failing with ctx.Err() when ctx is already done
+func (m *Manager) SerializeUserJSONContext(ctx context.Context, user User) ([]byte, error) {
+	return withContext(ctx, "avro.serialize", "user", user, m.SerializeUserJSON)
+}
+
+// DeserializeUserJSONContext deserializes a user from JSON using Avro schema,
+// failing with ctx.Err() when ctx is already done
+func (m *Manager) DeserializeUserJSONContext(ctx context.Context, data []byte) (User, error) {
+	return withContext(ctx, "avro.deserialize", "user", data, m.DeserializeUserJSON)
+}
+
+// SerializeProductBinaryContext serializes a product to binary using Avro,
+// failing with ctx.Err() when ctx is already done
+func (m *Manager) SerializeProductBinaryContext(ctx context.Context, product Product) ([]byte, error) {
+	return withContext(ctx, "avro.serialize", "product", product, m.SerializeProductBinary)
+}
+
+// DeserializeProductBinaryContext deserializes a product from binary using
+// Avro, failing with ctx.Err() when ctx is already done
+func (m *Manager) DeserializeProductBinaryContext(ctx context.Context, data []byte) (Product, error) {
+	return withContext(ctx, "avro.deserialize", "product", data, m.DeserializeProductBinary)
+}
+
+// SerializeProductJSONContext serializes a product to JSON using Avro
+// schema, failing with ctx.Err() when ctx is already done
+func (m *Manager) SerializeProductJSONContext(ctx context.Context, product Product) ([]byte, error) {
+	return withContext(ctx, "avro.serialize", "product", product, m.SerializeProductJSON)
+}
+
+// DeserializeProductJSONContext deserializes a product from JSON using Avro
+// schema, failing with ctx.Err() when ctx is already done
+func (m *Manager) DeserializeProductJSONContext(ctx context.Context, data []byte) (Product, error) {
+	return withContext(ctx, "avro.deserialize", "product", data, m.DeserializeProductJSON)
+}
+
+// SerializeAnalyticsContext serializes an analytics event to binary using
+// Avro, failing with ctx.Err() when ctx is already done
+func (m *Manager) SerializeAnalyticsContext(ctx context.Context, event Analytics) ([]byte, error) {
+	return withContext(ctx, "avro.serialize", "analytics", event, m.SerializeAnalytics)
+}
+
+// DeserializeAnalyticsContext deserializes an analytics event from binary
+// using Avro, failing with ctx.Err() when ctx is already done
+func (m *Manager) DeserializeAnalyticsContext(ctx context.Context, data []byte) (Analytics, error) {
+	return withContext(ctx, "avro.deserialize", "analytics", data, m.DeserializeAnalytics)
+}
+
+// withContext runs fn on in within a span unless ctx is already done. Single
+// records encode in microseconds, so they are not interrupted once started
+func withContext[In, Out any](ctx context.Context, name, entity string, in In, fn func(In) (Out, error)) (out Out, err error) {
+	ctx, span := tracing.Start(ctx, name, "avro", entity)
+	defer func() { tracing.End(span, 1, err) }()
+
+	if err := ctx.Err(); err != nil {
+		var zero Out
+		return zero, err
+	}
+	out, err = fn(in)
+	tracing.SetBytes(ctx, encodedSize(in, out))
+	return out, err
+}
+
+// encodedSize returns the length of whichever of in and out is the encoded form
+func encodedSize(in, out any) int64 {
+	if data, ok := out.([]byte); ok {
+		return int64(len(data))
+	}
+	if data, ok := in.([]byte); ok {
+		return int64(len(data))
+	}
+	return 0
+}
@@ -0,0 +1,84 @@
+package avro
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/storage"
+)
+
+func TestContextVariants(t *testing.T) {
+	testDir := "tmp/test_context"
+	manager, err := NewManager(testDir)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer os.RemoveAll(testDir)
+	manager.WithClock(testutil.NewDefaultFakeClock())
+
+	users := manager.CreateSampleUsers(50)
+	ctx := context.Background()
+	if err := manager.WriteUsersToFileContext(ctx, "users.avro", users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	read, err := manager.ReadUsersFromFileContext(ctx, "users.avro")
+	if err != nil {
+		t.Fatalf("Failed to read users: %v", err)
+	}
+	if len(read) != len(users) {
+		t.Fatalf("Expected %d users, got %d", len(users), len(read))
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	if err := manager.WriteUsersToOCFFileContext(cancelled, "cancelled.avro", users); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled write to fail with context.Canceled, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(testDir, "cancelled.avro")); !os.IsNotExist(err) {
+		t.Errorf("Expected the cancelled write to leave no file, got %v", err)
+	}
+	if _, err := manager.ReadUsersFromFileContext(cancelled, "users.avro"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled read to fail with context.Canceled, got %v", err)
+	}
+	if _, err := manager.ReadOrdersWhereContext(cancelled, "users.avro", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled order read to fail with context.Canceled, got %v", err)
+	}
+	if _, err := manager.SerializeUserBinaryContext(cancelled, users[0]); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled serialize to fail with context.Canceled, got %v", err)
+	}
+
+	data, err := manager.SerializeUserBinaryContext(ctx, users[0])
+	if err != nil {
+		t.Fatalf("Failed to serialize user: %v", err)
+	}
+	if user, err := manager.DeserializeUserBinaryContext(ctx, data); err != nil || user.ID != users[0].ID {
+		t.Errorf("Expected user %d back, got %d (%v)", users[0].ID, user.ID, err)
+	}
+
+	t.Log("✓ Context variants round-trip and stop once the context is cancelled")
+}
+
+func TestContextStorage(t *testing.T) {
+	manager, err := NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	backend := storage.NewMemoryStorage()
+	manager.WithStorage(backend)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := manager.WriteUsersToFileContext(cancelled, "users.avro", manager.CreateSampleUsers(5)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled upload to fail with context.Canceled, got %v", err)
+	}
+	if exists, _ := backend.Exists(context.Background(), "users.avro"); exists {
+		t.Error("Expected nothing to be uploaded")
+	}
+
+	t.Log("✓ Cancelled writes are not uploaded to storage")
+}
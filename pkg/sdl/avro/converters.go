@@ -2,7 +2,11 @@ package avro
 
 import (
 	"fmt"
+	"math/big"
+	"strings"
 	"time"
+
+	"go-transport-prac/internal/errors"
 )
 
 // userToAvroMap converts a User struct to an Avro-compatible map
@@ -54,72 +58,43 @@ func (m *Manager) userToAvroMap(user User) map[string]interface{} {
 	return data
 }
 
-// avroMapToUser converts an Avro map to a User struct
-func (m *Manager) avroMapToUser(data map[string]interface{}) (User, error) {
+// avroMapToUser converts a decoded Avro record to a User struct. A field of
+// the wrong type fails with an errors.DeserializationError naming its path
+func (m *Manager) avroMapToUser(record interface{}) (User, error) {
+	r := newFieldReader(record)
 	user := User{
-		ID:    toInt64(data["id"]),
-		Email: data["email"].(string),
-		Name:  data["name"].(string),
-		Status: UserStatus(data["status"].(string)),
-	}
-
-	// Handle timestamps
-	if createdAtMs := data["createdAt"]; createdAtMs != nil {
-		user.CreatedAt = time.UnixMilli(toInt64(createdAtMs))
-	}
-	if updatedAtMs := data["updatedAt"]; updatedAtMs != nil {
-		user.UpdatedAt = time.UnixMilli(toInt64(updatedAtMs))
+		ID:        r.long("id"),
+		Email:     r.str("email"),
+		Name:      r.str("name"),
+		Status:    UserStatus(r.str("status")),
+		CreatedAt: r.timestamp("createdAt"),
+		UpdatedAt: r.timestamp("updatedAt"),
 	}
 
-	// Handle profile (optional)
-	if profileData := data["profile"]; profileData != nil {
-		if profileMap, ok := profileData.(map[string]interface{}); ok {
-			if profileValue, exists := profileMap["com.example.avro.Profile"]; exists {
-				if profileValueMap, ok := profileValue.(map[string]interface{}); ok {
-					profile := &Profile{
-						FirstName: profileValueMap["firstName"].(string),
-						LastName:  profileValueMap["lastName"].(string),
-						Interests: stringSliceFromInterface(profileValueMap["interests"]),
-						Metadata:  stringMapFromInterface(profileValueMap["metadata"]),
-					}
-
-					// Handle optional phone
-					if phoneData := profileValueMap["phone"]; phoneData != nil {
-						// Handle different possible formats for union types
-						if phoneMap, ok := phoneData.(map[string]interface{}); ok {
-							if phoneValue, exists := phoneMap["string"]; exists {
-								phoneStr := phoneValue.(string)
-								profile.Phone = &phoneStr
-							}
-						} else if phoneStr, ok := phoneData.(string); ok {
-							// Sometimes unions are returned as direct values
-							profile.Phone = &phoneStr
-						}
-					}
-
-					// Handle optional address
-					if addressData := profileValueMap["address"]; addressData != nil {
-						if addressMap, ok := addressData.(map[string]interface{}); ok {
-							if addressValue, exists := addressMap["com.example.avro.Address"]; exists {
-								if addressValueMap, ok := addressValue.(map[string]interface{}); ok {
-									profile.Address = &Address{
-										Street:     addressValueMap["street"].(string),
-										City:       addressValueMap["city"].(string),
-										State:      addressValueMap["state"].(string),
-										PostalCode: addressValueMap["postalCode"].(string),
-										Country:    addressValueMap["country"].(string),
-									}
-								}
-							}
-						}
-					}
-
-					user.Profile = profile
-				}
+	// Optional unions come back wrapped as {"branch": value}
+	if p := r.optionalRecord("profile", "com.example.avro.Profile"); p != nil {
+		profile := &Profile{
+			FirstName: p.str("firstName"),
+			LastName:  p.str("lastName"),
+			Phone:     p.optionalString("phone"),
+			Interests: p.stringList("interests"),
+			Metadata:  p.stringMap("metadata"),
+		}
+		if a := p.optionalRecord("address", "com.example.avro.Address"); a != nil {
+			profile.Address = &Address{
+				Street:     a.str("street"),
+				City:       a.str("city"),
+				State:      a.str("state"),
+				PostalCode: a.str("postalCode"),
+				Country:    a.str("country"),
 			}
 		}
+		user.Profile = profile
 	}
 
+	if err := r.Err(); err != nil {
+		return User{}, err
+	}
 	return user, nil
 }
 
@@ -130,6 +105,11 @@ func (m *Manager) productToAvroMap(product Product) map[string]interface{} {
 		"currency":    product.Price.Currency,
 		"amountCents": product.Price.AmountCents,
 	}
+	if product.Price.Amount != nil {
+		priceData["amount"] = map[string]interface{}{"bytes.decimal": product.Price.Amount.Rat()}
+	} else {
+		priceData["amount"] = nil
+	}
 	if product.Price.DiscountPercentage != nil {
 		priceData["discountPercentage"] = map[string]interface{}{"float": *product.Price.DiscountPercentage}
 	} else {
@@ -146,6 +126,11 @@ func (m *Manager) productToAvroMap(product Product) map[string]interface{} {
 		"maxStock":       product.Inventory.MaxStock,
 	}
 
+	var releaseDate interface{}
+	if product.ReleaseDate != nil {
+		releaseDate = map[string]interface{}{"int.date": *product.ReleaseDate}
+	}
+
 	return map[string]interface{}{
 		"id":            product.ID,
 		"name":          product.Name,
@@ -157,137 +142,314 @@ func (m *Manager) productToAvroMap(product Product) map[string]interface{} {
 		"tags":          product.Tags,
 		"status":        string(product.Status),
 		"specifications": product.Specifications,
+		"releaseDate":   releaseDate,
 		"createdAt":     product.CreatedAt.UnixMilli(),
 		"updatedAt":     product.UpdatedAt.UnixMilli(),
 	}
 }
 
-// avroMapToProduct converts an Avro map to a Product struct
-func (m *Manager) avroMapToProduct(data map[string]interface{}) (Product, error) {
+// avroMapToProduct converts a decoded Avro record to a Product struct. A
+// field of the wrong type fails with an errors.DeserializationError naming
+// its path
+func (m *Manager) avroMapToProduct(record interface{}) (Product, error) {
+	r := newFieldReader(record)
 	product := Product{
-		ID:          toInt64(data["id"]),
-		Name:        data["name"].(string),
-		Description: data["description"].(string),
-		SKU:         data["sku"].(string),
-		Categories:  stringSliceFromInterface(data["categories"]),
-		Tags:        stringSliceFromInterface(data["tags"]),
-		Status:      ProductStatus(data["status"].(string)),
-		Specifications: stringMapFromInterface(data["specifications"]),
-	}
-
-	// Handle timestamps  
-	if createdAtMs := data["createdAt"]; createdAtMs != nil {
-		product.CreatedAt = time.UnixMilli(toInt64(createdAtMs))
-	}
-	if updatedAtMs := data["updatedAt"]; updatedAtMs != nil {
-		product.UpdatedAt = time.UnixMilli(toInt64(updatedAtMs))
+		ID:             r.long("id"),
+		Name:           r.str("name"),
+		Description:    r.str("description"),
+		SKU:            r.str("sku"),
+		Categories:     r.stringList("categories"),
+		Tags:           r.stringList("tags"),
+		Status:         ProductStatus(r.str("status")),
+		Specifications: r.stringMap("specifications"),
+		ReleaseDate:    r.optionalDate("releaseDate"),
+		CreatedAt:      r.timestamp("createdAt"),
+		UpdatedAt:      r.timestamp("updatedAt"),
 	}
 
-	// Handle price
-	if priceData, ok := data["price"].(map[string]interface{}); ok {
+	if p := r.record("price"); p != nil {
 		product.Price = Price{
-			Currency:    priceData["currency"].(string),
-			AmountCents: toInt64(priceData["amountCents"]),
-		}
-
-		// Handle optional discount
-		if discountData := priceData["discountPercentage"]; discountData != nil {
-			if discountMap, ok := discountData.(map[string]interface{}); ok {
-				if discountValue, exists := discountMap["float"]; exists {
-					discount := float32(discountValue.(float64))
-					product.Price.DiscountPercentage = &discount
-				}
-			} else if discountValue, ok := discountData.(float64); ok {
-				// Sometimes unions are returned as direct values
-				discount := float32(discountValue)
-				product.Price.DiscountPercentage = &discount
-			} else if discountValue, ok := discountData.(float32); ok {
-				product.Price.DiscountPercentage = &discountValue
-			}
+			Currency:           p.str("currency"),
+			AmountCents:        p.long("amountCents"),
+			Amount:             p.optionalDecimal("amount"),
+			DiscountPercentage: p.optionalFloat("discountPercentage"),
 		}
 	}
 
-	// Handle inventory
-	if inventoryData, ok := data["inventory"].(map[string]interface{}); ok {
+	if inv := r.record("inventory"); inv != nil {
 		product.Inventory = Inventory{
-			Quantity:       toInt32(inventoryData["quantity"]),
-			Reserved:       toInt32(inventoryData["reserved"]),
-			Available:      toInt32(inventoryData["available"]),
-			TrackInventory: inventoryData["trackInventory"].(bool),
-			ReorderLevel:   toInt32(inventoryData["reorderLevel"]),
-			MaxStock:       toInt32(inventoryData["maxStock"]),
+			Quantity:       inv.int("quantity"),
+			Reserved:       inv.int("reserved"),
+			Available:      inv.int("available"),
+			TrackInventory: inv.boolean("trackInventory"),
+			ReorderLevel:   inv.int("reorderLevel"),
+			MaxStock:       inv.int("maxStock"),
 		}
 	}
 
+	if err := r.Err(); err != nil {
+		return Product{}, err
+	}
 	return product, nil
 }
 
 // Helper functions
 
-// toInt64 safely converts various numeric types to int64
-func toInt64(v interface{}) int64 {
-	switch val := v.(type) {
-	case int:
-		return int64(val)
-	case int32:
-		return int64(val)
-	case int64:
-		return val
-	case float64:
-		return int64(val)
-	default:
-		return 0
+// fieldReader reads the typed fields of a generically decoded record. The
+// first field of an unexpected type becomes the error of the whole read, so
+// a converter reads every field and checks Err once
+type fieldReader struct {
+	path string
+	data map[string]interface{}
+	// err is shared with the readers of nested records
+	err *error
+}
+
+// newFieldReader returns a reader of record, failing at once if it is not a
+// record
+func newFieldReader(record interface{}) *fieldReader {
+	r := &fieldReader{err: new(error)}
+	data, ok := record.(map[string]interface{})
+	if !ok {
+		r.fail("", "record", record)
 	}
+	r.data = data
+	return r
 }
 
-// toInt32 safely converts various numeric types to int32
-func toInt32(v interface{}) int32 {
-	switch val := v.(type) {
-	case int:
-		return int32(val)
-	case int32:
-		return val
-	case int64:
-		return int32(val)
-	case float64:
-		return int32(val)
+// Err returns the first mismatch of the reader or any reader nested in it
+func (r *fieldReader) Err() error {
+	return *r.err
+}
+
+// fieldPath joins key, which may be an index such as "[0]", to the reader's path
+func (r *fieldReader) fieldPath(key string) string {
+	switch {
+	case r.path == "":
+		return key
+	case key == "" || strings.HasPrefix(key, "["):
+		return r.path + key
 	default:
-		return 0
+		return r.path + "." + key
+	}
+}
+
+func (r *fieldReader) fail(key, expected string, value interface{}) {
+	if *r.err == nil {
+		*r.err = errors.DeserializationError(r.fieldPath(key), expected, typeName(value))
+	}
+}
+
+// nested returns a reader of a nested record sharing r's error
+func (r *fieldReader) nested(key string, data map[string]interface{}) *fieldReader {
+	return &fieldReader{path: r.fieldPath(key), data: data, err: r.err}
+}
+
+func (r *fieldReader) str(key string) string {
+	s, ok := r.data[key].(string)
+	if !ok {
+		r.fail(key, "string", r.data[key])
 	}
+	return s
 }
 
-// stringSliceFromInterface converts an interface{} to []string
-func stringSliceFromInterface(data interface{}) []string {
-	if data == nil {
+func (r *fieldReader) boolean(key string) bool {
+	b, ok := r.data[key].(bool)
+	if !ok {
+		r.fail(key, "boolean", r.data[key])
+	}
+	return b
+}
+
+func (r *fieldReader) long(key string) int64 {
+	n, ok := toInt64(r.data[key])
+	if !ok {
+		r.fail(key, "long", r.data[key])
+	}
+	return n
+}
+
+func (r *fieldReader) int(key string) int32 {
+	n, ok := toInt64(r.data[key])
+	if !ok {
+		r.fail(key, "int", r.data[key])
+	}
+	return int32(n)
+}
+
+func (r *fieldReader) timestamp(key string) time.Time {
+	t, ok := toTime(r.data[key])
+	if !ok {
+		r.fail(key, "timestamp-millis", r.data[key])
+	}
+	return t
+}
+
+// stringList reads an array of strings; null reads as empty
+func (r *fieldReader) stringList(key string) []string {
+	switch items := r.data[key].(type) {
+	case nil:
+		return []string{}
+	case []interface{}:
+		result := make([]string, len(items))
+		for i, item := range items {
+			s, ok := item.(string)
+			if !ok {
+				r.fail(fmt.Sprintf("%s[%d]", key, i), "string", item)
+			}
+			result[i] = s
+		}
+		return result
+	default:
+		r.fail(key, "array", items)
 		return []string{}
 	}
+}
 
-	if slice, ok := data.([]interface{}); ok {
-		result := make([]string, len(slice))
-		for i, item := range slice {
-			result[i] = item.(string)
+// stringMap reads a map of strings; null reads as empty
+func (r *fieldReader) stringMap(key string) map[string]string {
+	switch values := r.data[key].(type) {
+	case nil:
+		return map[string]string{}
+	case map[string]interface{}:
+		result := make(map[string]string, len(values))
+		for k, v := range values {
+			s, ok := v.(string)
+			if !ok {
+				r.fail(fmt.Sprintf("%s[%s]", key, k), "string", v)
+			}
+			result[k] = s
 		}
 		return result
+	default:
+		r.fail(key, "map", values)
+		return map[string]string{}
+	}
+}
+
+// record returns a reader of a nested record, or nil if the field is not one
+func (r *fieldReader) record(key string) *fieldReader {
+	data, ok := r.data[key].(map[string]interface{})
+	if !ok {
+		r.fail(key, "record", r.data[key])
+		return nil
 	}
+	return r.nested(key, data)
+}
 
-	return []string{}
+// optionalRecord returns a reader of a nullable record union, or nil if the
+// field is null or holds another branch
+func (r *fieldReader) optionalRecord(key, branch string) *fieldReader {
+	value := unionValue(r.data[key], branch)
+	if value == nil {
+		return nil
+	}
+	data, ok := value.(map[string]interface{})
+	if !ok {
+		r.fail(key, "record or null", value)
+		return nil
+	}
+	return r.nested(key, data)
 }
 
-// stringMapFromInterface converts an interface{} to map[string]string
-func stringMapFromInterface(data interface{}) map[string]string {
-	if data == nil {
-		return map[string]string{}
+func (r *fieldReader) optionalString(key string) *string {
+	switch value := unionValue(r.data[key], "string").(type) {
+	case nil:
+		return nil
+	case string:
+		return &value
+	default:
+		r.fail(key, "string or null", value)
+		return nil
 	}
+}
 
-	if m, ok := data.(map[string]interface{}); ok {
-		result := make(map[string]string)
-		for k, v := range m {
-			result[k] = v.(string)
-		}
-		return result
+func (r *fieldReader) optionalFloat(key string) *float32 {
+	switch value := unionValue(r.data[key], "float").(type) {
+	case nil:
+		return nil
+	case float32:
+		return &value
+	case float64:
+		f := float32(value)
+		return &f
+	default:
+		r.fail(key, "float or null", value)
+		return nil
 	}
+}
 
-	return map[string]string{}
+func (r *fieldReader) optionalDecimal(key string) *Decimal {
+	switch value := unionValue(r.data[key], "bytes.decimal").(type) {
+	case nil:
+		return nil
+	case *big.Rat:
+		return (*Decimal)(value)
+	default:
+		r.fail(key, "decimal or null", value)
+		return nil
+	}
+}
+
+// optionalDate reads a nullable date union as a UTC day
+func (r *fieldReader) optionalDate(key string) *time.Time {
+	value := unionValue(r.data[key], "int.date")
+	if value == nil {
+		return nil
+	}
+	date, ok := toTime(value)
+	if !ok {
+		r.fail(key, "date or null", value)
+		return nil
+	}
+	date = date.UTC()
+	return &date
+}
+
+// typeName names the Go type of a decoded value, or null
+func typeName(v interface{}) string {
+	if v == nil {
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// toInt64 converts the numeric types hamba/avro decodes to int64
+func toInt64(v interface{}) (int64, bool) {
+	switch val := v.(type) {
+	case int:
+		return int64(val), true
+	case int32:
+		return int64(val), true
+	case int64:
+		return val, true
+	case float64:
+		return int64(val), true
+	default:
+		return 0, false
+	}
+}
+
+// toTime converts a decoded timestamp-millis value, which hamba/avro returns as
+// time.Time, or a raw epoch-millis number into a time.Time
+func toTime(v interface{}) (time.Time, bool) {
+	if t, ok := v.(time.Time); ok {
+		return t, true
+	}
+	ms, ok := toInt64(v)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(ms), true
+}
+
+// unionValue returns the value of a decoded union, which hamba/avro may return
+// either wrapped as {"branch": value} or bare
+func unionValue(data interface{}, branch string) interface{} {
+	if wrapped, ok := data.(map[string]interface{}); ok {
+		return wrapped[branch]
+	}
+	return data
 }
 
 // CompareData compares two interface{} values for testing
@@ -0,0 +1,147 @@
+package avro
+
+import (
+	"testing"
+
+	"github.com/hamba/avro/v2"
+
+	"go-transport-prac/internal/errors"
+)
+
+// decodedRecord decodes data into the generic form the map converters read
+func decodedRecord(t *testing.T, schema avro.Schema, data []byte) map[string]interface{} {
+	t.Helper()
+	var result interface{}
+	if err := decodeAPI.Unmarshal(schema, data, &result); err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	return result.(map[string]interface{})
+}
+
+// child returns the nested record at key, unwrapping a union branch
+func child(record map[string]interface{}, key, branch string) map[string]interface{} {
+	value := record[key].(map[string]interface{})
+	if branch != "" {
+		value = value[branch].(map[string]interface{})
+	}
+	return value
+}
+
+// assertDeserializationError checks err names path with the expected and
+// actual types
+func assertDeserializationError(t *testing.T, err error, path, expected, actual string) {
+	t.Helper()
+	appErr, ok := errors.AsAppError(err)
+	if !ok || appErr.Code != errors.CodeDeserializationError || appErr.Type != errors.ErrorTypeBadRequest {
+		t.Fatalf("Expected a deserialization error for %s, got %v", path, err)
+	}
+	if appErr.Fields["path"] != path || appErr.Fields["expected"] != expected || appErr.Fields["actual"] != actual {
+		t.Errorf("Expected %s: %s/%s, got %v", path, expected, actual, appErr.Fields)
+	}
+}
+
+func TestAvroMapToUserMismatchedFields(t *testing.T) {
+	m, err := NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	user := m.CreateSampleUsers(1)[0]
+
+	tests := []struct {
+		name     string
+		mutate   func(record map[string]interface{})
+		path     string
+		expected string
+		actual   string
+	}{
+		{"email", func(r map[string]interface{}) { r["email"] = 42 }, "email", "string", "int"},
+		{"missing id", func(r map[string]interface{}) { delete(r, "id") }, "id", "long", "null"},
+		{"createdAt", func(r map[string]interface{}) { r["createdAt"] = "yesterday" }, "createdAt", "timestamp-millis", "string"},
+		{"profile", func(r map[string]interface{}) {
+			r["profile"] = map[string]interface{}{"com.example.avro.Profile": "x"}
+		}, "profile", "record or null", "string"},
+		{"address city", func(r map[string]interface{}) {
+			profile := child(r, "profile", "com.example.avro.Profile")
+			child(profile, "address", "com.example.avro.Address")["city"] = true
+		}, "profile.address.city", "string", "bool"},
+		{"interest", func(r map[string]interface{}) {
+			child(r, "profile", "com.example.avro.Profile")["interests"] = []interface{}{"music", 7}
+		}, "profile.interests[1]", "string", "int"},
+		{"phone", func(r map[string]interface{}) {
+			child(r, "profile", "com.example.avro.Profile")["phone"] = 5551234
+		}, "profile.phone", "string or null", "int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := m.SerializeUserBinary(user)
+			if err != nil {
+				t.Fatalf("Failed to serialize: %v", err)
+			}
+			record := decodedRecord(t, m.userSchema, data)
+			if _, err := m.avroMapToUser(record); err != nil {
+				t.Fatalf("Failed to convert the unmodified record: %v", err)
+			}
+
+			tt.mutate(record)
+			got, err := m.avroMapToUser(record)
+			assertDeserializationError(t, err, tt.path, tt.expected, tt.actual)
+			if got.ID != 0 || got.Profile != nil {
+				t.Errorf("Expected a zero user on error, got %+v", got)
+			}
+		})
+	}
+
+	_, err = m.avroMapToUser([]interface{}{"not", "a", "record"})
+	assertDeserializationError(t, err, "", "record", "[]interface {}")
+
+	t.Log("✓ Mismatched user fields fail with their path and types")
+}
+
+func TestAvroMapToProductMismatchedFields(t *testing.T) {
+	m, err := NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	product := m.CreateSampleProducts(1)[0]
+
+	tests := []struct {
+		name     string
+		mutate   func(record map[string]interface{})
+		path     string
+		expected string
+		actual   string
+	}{
+		{"missing price", func(r map[string]interface{}) { delete(r, "price") }, "price", "record", "null"},
+		{"currency", func(r map[string]interface{}) { child(r, "price", "")["currency"] = 1.5 }, "price.currency", "string", "float64"},
+		{"discount", func(r map[string]interface{}) {
+			child(r, "price", "")["discountPercentage"] = "10%"
+		}, "price.discountPercentage", "float or null", "string"},
+		{"trackInventory", func(r map[string]interface{}) {
+			child(r, "inventory", "")["trackInventory"] = "yes"
+		}, "inventory.trackInventory", "boolean", "string"},
+		{"specification", func(r map[string]interface{}) {
+			r["specifications"] = map[string]interface{}{"weight": 3}
+		}, "specifications[weight]", "string", "int"},
+		{"tags", func(r map[string]interface{}) { r["tags"] = "sale" }, "tags", "array", "string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := m.SerializeProductBinary(product)
+			if err != nil {
+				t.Fatalf("Failed to serialize: %v", err)
+			}
+			record := decodedRecord(t, m.productSchema, data)
+			if _, err := m.avroMapToProduct(record); err != nil {
+				t.Fatalf("Failed to convert the unmodified record: %v", err)
+			}
+
+			tt.mutate(record)
+			_, err = m.avroMapToProduct(record)
+			assertDeserializationError(t, err, tt.path, tt.expected, tt.actual)
+		})
+	}
+
+	t.Log("✓ Mismatched product fields fail with their path and types")
+}
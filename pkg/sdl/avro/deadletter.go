@@ -0,0 +1,71 @@
+package avro
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go-transport-prac/pkg/sdl/deadletter"
+)
+
+// WithDecodeMode sets how ReadUsersFromFile handles records that fail to
+// decode. In deadletter.Tolerant mode bad records are appended to letters,
+// which may be nil, and the good users are returned with a *deadletter.Error
+// summarizing the failures
+func (m *Manager) WithDecodeMode(mode deadletter.Mode, letters *deadletter.File) *Manager {
+	m.decodeMode = mode
+	m.deadLetters = letters
+	return m
+}
+
+// readUsersTolerant decodes users one record at a time. A record that decodes
+// but cannot be converted to a User is dead-lettered on its own; binary Avro
+// has no record framing to resynchronize on, so a record that fails to decode
+// is dead-lettered together with the rest of the file
+func (m *Manager) readUsersTolerant(ctx context.Context, filename string) ([]User, error) {
+	file, err := m.openFile(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	batch := deadletter.NewBatch(m.deadLetters, filename)
+	var users []User
+	truncErr, err := scanRecords(filename, m.userSchema, data, func(index int, record interface{}, offset, end int64) error {
+		user, err := m.recordToUser(record)
+		if err != nil {
+			return batch.Fail(offset, index, 1, data[offset:end], err)
+		}
+		users = append(users, user)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if truncErr != nil {
+		if err := batch.Fail(truncErr.Offset, truncErr.RecordsRecovered, 1, data[truncErr.Offset:], fmt.Errorf("failed to decode user: %w", truncErr.Err)); err != nil {
+			return nil, err
+		}
+	}
+	return users, batch.Err(len(users))
+}
+
+// recordToUser converts a generically decoded record to a User
+func (m *Manager) recordToUser(record interface{}) (User, error) {
+	fields, ok := record.(map[string]interface{})
+	if !ok {
+		return User{}, fmt.Errorf("unexpected user record %T", record)
+	}
+	user, err := m.avroMapToUser(fields)
+	if err != nil {
+		return User{}, fmt.Errorf("failed to convert avro map to user: %w", err)
+	}
+	return user, nil
+}
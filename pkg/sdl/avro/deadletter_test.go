@@ -0,0 +1,75 @@
+package avro
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-transport-prac/pkg/sdl/deadletter"
+)
+
+func TestTolerantDecodeModeDeadLetters(t *testing.T) {
+	dir := "tmp/test_deadletter"
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	users := manager.CreateSampleUsers(5)
+	if err := manager.WriteUsersToFile("users.avro", users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+
+	// Cut the last record short, as a crashed writer would
+	filePath := filepath.Join(dir, "users.avro")
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if err := os.WriteFile(filePath, data[:len(data)-10], 0644); err != nil {
+		t.Fatalf("Failed to truncate file: %v", err)
+	}
+
+	if _, err := manager.ReadUsersFromFile("users.avro"); err == nil {
+		t.Fatal("Expected the strict read to fail")
+	}
+
+	letters := deadletter.NewFile(filepath.Join(dir, "users.deadletter.jsonl"))
+	manager.WithDecodeMode(deadletter.Tolerant, letters)
+
+	read, err := manager.ReadUsersFromFile("users.avro")
+	summary, ok := deadletter.Partial(err)
+	if !ok {
+		t.Fatalf("Expected a dead-letter summary, got %v", err)
+	}
+	if len(read) != 4 || read[3].ID != users[3].ID || summary.Good != 4 || summary.Failed != 1 {
+		t.Fatalf("Expected 4 good users and 1 failure, got %d and %+v", len(read), summary)
+	}
+
+	entries, err := deadletter.ReadFile(letters.Path())
+	if err != nil {
+		t.Fatalf("Failed to read dead letters: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 dead letter, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Source != "users.avro" || entry.Record != 4 || entry.Error == "" {
+		t.Errorf("Unexpected dead letter: %+v", entry)
+	}
+	if !bytes.Equal(entry.Data, data[entry.Offset:len(data)-10]) {
+		t.Errorf("Expected the dead letter to hold the bytes from offset %d", entry.Offset)
+	}
+
+	// An intact file reads without a summary in tolerant mode too
+	if err := manager.WriteUsersToFile("users.avro", users); err != nil {
+		t.Fatalf("Failed to rewrite users: %v", err)
+	}
+	if read, err := manager.ReadUsersFromFile("users.avro"); err != nil || len(read) != 5 {
+		t.Errorf("Expected 5 users without error, got %d (%v)", len(read), err)
+	}
+
+	t.Log("✓ Tolerant reads return good users and dead-letter the rest")
+}
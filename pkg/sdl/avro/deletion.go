@@ -0,0 +1,179 @@
+package avro
+
+import (
+	"fmt"
+
+	"go-transport-prac/internal/errors"
+)
+
+// Mode controls whether a subject accepts changes
+type Mode string
+
+const (
+	// ModeReadWrite allows registering and deleting schemas (the default)
+	ModeReadWrite Mode = "READWRITE"
+	// ModeReadOnly rejects registering and deleting schemas; lookups still work
+	ModeReadOnly Mode = "READONLY"
+)
+
+// SetMode sets the mode of a subject
+func (sr *SchemaRegistry) SetMode(subject string, mode Mode) error {
+	if mode != ModeReadWrite && mode != ModeReadOnly {
+		return errors.ValidationError(errors.CodeInvalidValue, fmt.Sprintf("invalid mode %q", mode))
+	}
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	sr.modes[subject] = mode
+	return nil
+}
+
+// GetMode gets the mode of a subject
+func (sr *SchemaRegistry) GetMode(subject string) Mode {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	if mode, exists := sr.modes[subject]; exists {
+		return mode
+	}
+	return ModeReadWrite
+}
+
+// DeleteSubject deletes every version of a subject and returns the deleted
+// versions. Like Confluent, a soft delete hides the subject from lookups by
+// subject while its schemas stay resolvable by ID, and a permanent delete
+// removes them for good but only after the subject was soft-deleted
+func (sr *SchemaRegistry) DeleteSubject(subject string, permanent bool) ([]int, error) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if err := sr.checkWritable(subject); err != nil {
+		return nil, err
+	}
+
+	schemaIDs := sr.subjectSchemas[subject]
+	if len(schemaIDs) == 0 {
+		return nil, subjectNotFound(subject)
+	}
+
+	live := sr.liveIDs(subject)
+	if !permanent {
+		if len(live) == 0 {
+			return nil, errors.NotFoundError(CodeSubjectSoftDeleted,
+				fmt.Sprintf("subject %s was soft deleted; delete it permanently to remove it", subject))
+		}
+		versions := make([]int, len(live))
+		for i, id := range live {
+			versions[i] = sr.softDelete(id)
+		}
+		return versions, nil
+	}
+
+	if len(live) > 0 {
+		return nil, errors.ConflictError(CodeSubjectNotSoftDeleted,
+			fmt.Sprintf("subject %s must be soft deleted before it is permanently deleted", subject))
+	}
+	versions := make([]int, len(schemaIDs))
+	for i, id := range schemaIDs {
+		versions[i] = sr.schemas[id].Version
+		sr.purge(id)
+	}
+	delete(sr.subjectSchemas, subject)
+	return versions, nil
+}
+
+// DeleteSchemaVersion deletes one version of a subject with the same soft
+// and permanent semantics as DeleteSubject. Deleting the latest version makes
+// the previous one the latest again
+func (sr *SchemaRegistry) DeleteSchemaVersion(subject string, version int, permanent bool) (int, error) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if err := sr.checkWritable(subject); err != nil {
+		return 0, err
+	}
+
+	index := -1
+	schemaIDs := sr.subjectSchemas[subject]
+	for i, id := range schemaIDs {
+		if sr.schemas[id].Version == version {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		if len(schemaIDs) == 0 {
+			return 0, subjectNotFound(subject)
+		}
+		return 0, versionNotFound(subject, version)
+	}
+
+	id := schemaIDs[index]
+	deleted := sr.schemas[id].Deleted
+	if !permanent {
+		if deleted {
+			return 0, errors.NotFoundError(CodeVersionSoftDeleted,
+				fmt.Sprintf("schema version %d of subject %s was soft deleted; delete it permanently to remove it", version, subject))
+		}
+		return sr.softDelete(id), nil
+	}
+
+	if !deleted {
+		return 0, errors.ConflictError(CodeVersionNotSoftDeleted,
+			fmt.Sprintf("schema version %d of subject %s must be soft deleted before it is permanently deleted", version, subject))
+	}
+	sr.purge(id)
+	sr.subjectSchemas[subject] = append(schemaIDs[:index:index], schemaIDs[index+1:]...)
+	if len(sr.subjectSchemas[subject]) == 0 {
+		delete(sr.subjectSchemas, subject)
+	}
+	return version, nil
+}
+
+// checkWritable rejects changes to a read-only subject
+// Note: This method assumes the caller already holds the lock
+func (sr *SchemaRegistry) checkWritable(subject string) error {
+	if sr.modes[subject] == ModeReadOnly {
+		return errors.ForbiddenError(errors.CodeResourceLocked,
+			fmt.Sprintf("subject %s is in read-only mode", subject))
+	}
+	return nil
+}
+
+// liveIDs returns the IDs of a subject's versions that are not soft-deleted
+// Note: This method assumes the caller already holds the lock
+func (sr *SchemaRegistry) liveIDs(subject string) []int {
+	var live []int
+	for _, id := range sr.subjectSchemas[subject] {
+		if !sr.schemas[id].Deleted {
+			live = append(live, id)
+		}
+	}
+	return live
+}
+
+// softDelete marks a schema deleted and returns its version
+func (sr *SchemaRegistry) softDelete(id int) int {
+	metadata := sr.schemas[id]
+	metadata.Deleted = true
+	sr.schemas[id] = metadata
+	sr.invalidateCached(sr.latestCacheKey(metadata.Subject))
+	sr.invalidateCached(sr.idCacheKey(id))
+	return metadata.Version
+}
+
+// purge removes a schema, leaving its ID unused
+func (sr *SchemaRegistry) purge(id int) {
+	sr.invalidateCached(sr.latestCacheKey(sr.schemas[id].Subject))
+	sr.invalidateCached(sr.idCacheKey(id))
+	delete(sr.schemas, id)
+}
+
+func subjectNotFound(subject string) error {
+	return errors.NotFoundError(CodeSubjectNotFound, fmt.Sprintf("subject %s not found", subject))
+}
+
+func versionNotFound(subject string, version int) error {
+	return errors.NotFoundError(CodeVersionNotFound, fmt.Sprintf("schema version %d not found for subject %s", version, subject))
+}
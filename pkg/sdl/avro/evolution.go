@@ -66,6 +66,30 @@ func NewEvolutionManager(baseDir string) (*EvolutionManager, error) {
 	return manager, nil
 }
 
+// UserSchemaJSON returns the schema text of user schema version 1, 2 or 3.
+// Unlike the parsed schema's canonical form it keeps field defaults, which
+// readers need to resolve data written with an older version
+func UserSchemaJSON(version int) (string, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch version {
+	case 1:
+		data, err = schemaFiles.ReadFile("schemas/user.avsc")
+	case 2:
+		data, err = evolutionSchemaFiles.ReadFile("schemas/user_v2.avsc")
+	case 3:
+		data, err = evolutionSchemaFiles.ReadFile("schemas/user_v3.avsc")
+	default:
+		return "", fmt.Errorf("unknown user schema version %d", version)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read user v%d schema: %w", version, err)
+	}
+	return string(data), nil
+}
+
 // DemonstrateSchemaEvolution shows various schema evolution scenarios
 func (em *EvolutionManager) DemonstrateSchemaEvolution() error {
 	fmt.Println("=== Schema Evolution Demonstration ===")
@@ -80,6 +104,11 @@ func (em *EvolutionManager) DemonstrateSchemaEvolution() error {
 		return fmt.Errorf("JSON evolution test failed: %w", err)
 	}
 
+	// Decode old binary data with the newer schema
+	if err := em.testSchemaResolution(); err != nil {
+		return fmt.Errorf("schema resolution test failed: %w", err)
+	}
+
 	// Show evolution best practices
 	em.showEvolutionBestPractices()
 
@@ -91,23 +120,37 @@ func (em *EvolutionManager) DemonstrateSchemaEvolution() error {
 func (em *EvolutionManager) analyzeSchemaCompatibility() error {
 	fmt.Println("--- Schema Compatibility Analysis ---")
 
-	fmt.Println("✓ v1 -> v2 Compatibility:")
-	fmt.Println("  • Added optional fields with defaults (forward compatible)")
-	fmt.Println("  • No removed fields (backward compatible)")
-	fmt.Println("  • No type changes (fully compatible)")
-
-	fmt.Println("✓ v2 -> v3 Compatibility:")
-	fmt.Println("  • Added optional nested fields (forward compatible)")
-	fmt.Println("  • Extended enum with new values (forward compatible)")
-	fmt.Println("  • Added fields with defaults (backward compatible)")
+	pairs := []struct {
+		label    string
+		old, new avro.Schema
+	}{
+		{"v1 -> v2", em.userV1, em.userV2},
+		{"v2 -> v3", em.userV2, em.userV3},
+		{"v1 -> v3", em.userV1, em.userV3},
+	}
 
-	fmt.Println("✓ v1 -> v3 Compatibility:")
-	fmt.Println("  • Transitive compatibility maintained")
-	fmt.Println("  • All new fields are optional with defaults")
+	for _, pair := range pairs {
+		fmt.Printf("✓ %s Compatibility:\n", pair.label)
+		printCompatibilityResult("backward", CheckReaderWriterCompatibility(pair.new, pair.old))
+		printCompatibilityResult("forward", CheckReaderWriterCompatibility(pair.old, pair.new))
+	}
 
 	return nil
 }
 
+// printCompatibilityResult prints the outcome of a single compatibility direction
+func printCompatibilityResult(direction string, incompatibilities []Incompatibility) {
+	if len(incompatibilities) == 0 {
+		fmt.Printf("  • %s compatible\n", direction)
+		return
+	}
+
+	fmt.Printf("  • NOT %s compatible:\n", direction)
+	for _, inc := range incompatibilities {
+		fmt.Printf("    - %s\n", inc)
+	}
+}
+
 // testJSONEvolution demonstrates evolution with JSON (simpler than binary)
 func (em *EvolutionManager) testJSONEvolution() error {
 	fmt.Println("--- JSON Schema Evolution Test ---")
@@ -175,6 +218,34 @@ func (em *EvolutionManager) testJSONEvolution() error {
 	return nil
 }
 
+// testSchemaResolution decodes v1 binary data with the v3 reader schema
+func (em *EvolutionManager) testSchemaResolution() error {
+	fmt.Println("--- Reader/Writer Schema Resolution ---")
+
+	v1Data := map[string]interface{}{
+		"id":        int64(3),
+		"email":     "resolution@example.com",
+		"name":      "Resolution Test",
+		"status":    "ACTIVE",
+		"profile":   nil,
+		"createdAt": time.Now(),
+		"updatedAt": time.Now(),
+	}
+
+	data, err := avro.Marshal(em.userV1, v1Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal v1 data: %w", err)
+	}
+
+	record, err := decodeWithSchemas(em.userV1, em.userV3, data)
+	if err != nil {
+		return fmt.Errorf("failed to decode v1 data with v3 schema: %w", err)
+	}
+
+	fmt.Printf("✓ v1 binary (%d bytes) read with v3 schema: %d fields\n", len(data), len(record))
+	return nil
+}
+
 // showEvolutionBestPractices displays schema evolution best practices
 func (em *EvolutionManager) showEvolutionBestPractices() {
 	fmt.Println("--- Schema Evolution Best Practices ---")
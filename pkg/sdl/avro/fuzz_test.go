@@ -0,0 +1,110 @@
+package avro
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+
+	"go-transport-prac/internal/testutil"
+)
+
+// fuzzManagers returns a manager on the struct fast path and one on the map
+// converters, so both decoders see every input
+func fuzzManagers(f *testing.F) []*Manager {
+	f.Helper()
+	native, err := NewManager("")
+	if err != nil {
+		f.Fatalf("Failed to create manager: %v", err)
+	}
+	maps, err := NewManager("")
+	if err != nil {
+		f.Fatalf("Failed to create manager: %v", err)
+	}
+	clock := testutil.NewDefaultFakeClock()
+	return []*Manager{native.WithClock(clock), maps.WithClock(clock).WithNativeStructs(false)}
+}
+
+// addSeeds adds each encoding, a truncated copy and a copy with a flipped
+// byte to the corpus
+func addSeeds(f *testing.F, encoded [][]byte) {
+	f.Add([]byte{})
+	for _, data := range encoded {
+		f.Add(data)
+		f.Add(data[:len(data)/2])
+		flipped := append([]byte(nil), data...)
+		flipped[len(flipped)/3] ^= 0xff
+		f.Add(flipped)
+	}
+}
+
+func FuzzDeserializeUserBinary(f *testing.F) {
+	managers := fuzzManagers(f)
+	var seeds [][]byte
+	for _, u := range managers[0].CreateSampleUsers(3) {
+		data, err := managers[0].SerializeUserBinary(u)
+		if err != nil {
+			f.Fatalf("Failed to serialize seed: %v", err)
+		}
+		seeds = append(seeds, data)
+	}
+	addSeeds(f, seeds)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, m := range managers {
+			user, err := m.DeserializeUserBinary(data)
+			if err != nil {
+				continue
+			}
+			// Whatever decodes must encode again
+			if _, err := m.SerializeUserBinary(user); err != nil {
+				t.Errorf("Decoded user does not encode: %v", err)
+			}
+		}
+	})
+}
+
+func FuzzDeserializeProduct(f *testing.F) {
+	managers := fuzzManagers(f)
+	var seeds [][]byte
+	for _, p := range managers[0].CreateSampleProducts(3) {
+		data, err := managers[0].SerializeProductBinary(p)
+		if err != nil {
+			f.Fatalf("Failed to serialize seed: %v", err)
+		}
+		seeds = append(seeds, data)
+	}
+	addSeeds(f, seeds)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, m := range managers {
+			product, err := m.DeserializeProductBinary(data)
+			if err != nil {
+				continue
+			}
+			if _, err := m.SerializeProductBinary(product); err != nil {
+				t.Errorf("Decoded product does not encode: %v", err)
+			}
+		}
+	})
+}
+
+func TestDecodeRejectsOversizedArray(t *testing.T) {
+	schema := avro.MustParse(`{"type": "array", "items": "string"}`)
+	// A single block claiming 2^31 items
+	data := binary.AppendVarint(nil, 1<<31)
+
+	var values []string
+	if err := decodeAPI.Unmarshal(schema, data, &values); err == nil {
+		t.Fatal("Expected an oversized array to fail")
+	}
+
+	m, err := NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if _, err := m.avroMapToUser(map[string]interface{}{"email": 42}); err == nil {
+		t.Fatal("Expected a mistyped field to fail")
+	}
+	t.Log("✓ Malformed input fails without exhausting memory or panicking")
+}
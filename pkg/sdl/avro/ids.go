@@ -0,0 +1,73 @@
+package avro
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// IDGenerator assigns the ID of a schema newly registered under subject.
+// fingerprint is the hex SHA-256 fingerprint of the schema's canonical form
+type IDGenerator interface {
+	NextID(subject, fingerprint string) (int, error)
+}
+
+// MonotonicIDGenerator hands out consecutive IDs. IDs depend on registration
+// order, so they are only stable within one registry process
+type MonotonicIDGenerator struct {
+	mu   sync.Mutex
+	next int
+}
+
+// NewMonotonicIDGenerator creates a generator whose first ID is start; a
+// start below 1 starts at 1
+func NewMonotonicIDGenerator(start int) *MonotonicIDGenerator {
+	return &MonotonicIDGenerator{next: max(start, 1)}
+}
+
+// NextID returns the next ID in sequence
+func (g *MonotonicIDGenerator) NextID(string, string) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.next > math.MaxInt32 {
+		return 0, fmt.Errorf("schema IDs exhausted")
+	}
+	id := g.next
+	g.next++
+	return id, nil
+}
+
+// Peek returns the ID the next registration will get
+func (g *MonotonicIDGenerator) Peek() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.next
+}
+
+// HashIDGenerator derives IDs from the subject and schema fingerprint, so
+// every registry instance and restart assigns the same schema the same ID.
+// IDs are positive 31-bit values, as Confluent clients expect; two schemas
+// hashing to the same ID make the second registration fail
+type HashIDGenerator struct{}
+
+// NextID returns the ID derived from subject and fingerprint
+func (HashIDGenerator) NextID(subject, fingerprint string) (int, error) {
+	sum := sha256.Sum256([]byte(subject + "\x00" + fingerprint))
+	id := int(binary.BigEndian.Uint32(sum[:4]) & math.MaxInt32)
+	if id == 0 {
+		id = 1
+	}
+	return id, nil
+}
+
+// IDGeneratorFunc adapts a function to IDGenerator, e.g. to take IDs from
+// an external allocator such as a database sequence shared by every registry
+type IDGeneratorFunc func(subject, fingerprint string) (int, error)
+
+// NextID calls f
+func (f IDGeneratorFunc) NextID(subject, fingerprint string) (int, error) {
+	return f(subject, fingerprint)
+}
@@ -0,0 +1,173 @@
+package avro
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/hamba/avro/v2"
+)
+
+// Avro logical types used by the schemas:
+//
+//	decimal           bytes, read and written as *big.Rat (Decimal)
+//	uuid              string
+//	date              int days since the epoch, time.Time at midnight UTC
+//	time-millis       int milliseconds after midnight, time.Duration
+//	timestamp-millis  long, time.Time
+//	timestamp-micros  long, time.Time
+
+var (
+	ratType      = reflect.TypeOf(big.Rat{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// Decimal is an exact decimal number for the Avro decimal logical type. It
+// converts to *big.Rat, which hamba/avro reads and writes, and marshals to JSON
+// as a decimal string such as "19.99"
+type Decimal big.Rat
+
+// NewDecimal returns unscaled * 10^-scale, e.g. NewDecimal(1999, 2) is 19.99
+func NewDecimal(unscaled int64, scale int) *Decimal {
+	denom := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)
+	return (*Decimal)(new(big.Rat).SetFrac(big.NewInt(unscaled), denom))
+}
+
+// DecimalFromCents returns an amount in cents as a decimal
+func DecimalFromCents(cents int64) *Decimal {
+	return NewDecimal(cents, 2)
+}
+
+// ParseDecimal parses a decimal string such as "19.99", "-3" or "1.5e2"
+func ParseDecimal(s string) (*Decimal, error) {
+	if strings.Contains(s, "/") {
+		return nil, fmt.Errorf("invalid decimal %q", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal %q", s)
+	}
+	return (*Decimal)(r), nil
+}
+
+// Rat returns d as a *big.Rat sharing its value
+func (d *Decimal) Rat() *big.Rat {
+	return (*big.Rat)(d)
+}
+
+// Cents returns d in hundredths, rounded half away from zero
+func (d *Decimal) Cents() int64 {
+	r := new(big.Rat).Mul(d.Rat(), big.NewRat(100, 1))
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if new(big.Int).Lsh(new(big.Int).Abs(m), 1).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(int64(m.Sign())))
+	}
+	return q.Int64()
+}
+
+// Cmp compares d and other, returning -1, 0 or +1
+func (d *Decimal) Cmp(other *Decimal) int {
+	return d.Rat().Cmp(other.Rat())
+}
+
+// String formats d with as many decimal places as it needs, up to 18
+func (d *Decimal) String() string {
+	if d == nil {
+		return "<nil>"
+	}
+	return d.Rat().FloatString(decimalPlaces(d.Rat()))
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d *Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	d.Rat().Set(parsed.Rat())
+	return nil
+}
+
+// decimalPlaces returns how many decimal places represent r exactly, or 18
+// for fractions such as 1/3 that never terminate
+func decimalPlaces(r *big.Rat) int {
+	const maxPlaces = 18
+	denom := new(big.Int).Set(r.Denom())
+	ten := big.NewInt(10)
+	for places := 0; places < maxPlaces; places++ {
+		if denom.Cmp(big.NewInt(1)) == 0 {
+			return places
+		}
+		// The denominator divides 10^n only if it has no factors but 2 and 5
+		g := new(big.Int).GCD(nil, nil, denom, ten)
+		if g.Cmp(big.NewInt(1)) == 0 {
+			return maxPlaces
+		}
+		denom.Quo(denom, g)
+	}
+	return maxPlaces
+}
+
+// validUUID reports whether s is a UUID in its canonical 8-4-4-4-12 hex form
+func validUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case i == 8 || i == 13 || i == 18 || i == 23:
+			if c != '-' {
+				return false
+			}
+		case !strings.ContainsRune("0123456789abcdefABCDEF", c):
+			return false
+		}
+	}
+	return true
+}
+
+// hasUnionDecimal reports whether schema holds a decimal inside a union.
+// hamba/avro decodes such a value into the pointer's target as if it were the
+// pointer itself, so schemas with one must not take the struct-tag fast path
+func hasUnionDecimal(schema avro.Schema) bool {
+	return walkUnionDecimal(schema, false, map[string]bool{})
+}
+
+func walkUnionDecimal(schema avro.Schema, inUnion bool, seen map[string]bool) bool {
+	schema = derefSchema(schema)
+	if named, ok := schema.(avro.NamedSchema); ok {
+		if seen[named.FullName()] {
+			return false
+		}
+		seen[named.FullName()] = true
+	}
+
+	switch s := schema.(type) {
+	case *avro.RecordSchema:
+		for _, field := range s.Fields() {
+			if walkUnionDecimal(field.Type(), false, seen) {
+				return true
+			}
+		}
+	case *avro.ArraySchema:
+		return walkUnionDecimal(s.Items(), false, seen)
+	case *avro.MapSchema:
+		return walkUnionDecimal(s.Values(), false, seen)
+	case *avro.UnionSchema:
+		for _, branch := range s.Types() {
+			if walkUnionDecimal(branch, true, seen) {
+				return true
+			}
+		}
+	default:
+		return inUnion && logicalType(schema) == avro.Decimal
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package avro
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDecimal(t *testing.T) {
+	d, err := ParseDecimal("19.99")
+	if err != nil {
+		t.Fatalf("Failed to parse decimal: %v", err)
+	}
+	if d.String() != "19.99" || d.Cents() != 1999 {
+		t.Errorf("Expected 19.99 / 1999 cents, got %s / %d", d, d.Cents())
+	}
+	if d.Cmp(NewDecimal(1999, 2)) != 0 || d.Cmp(DecimalFromCents(1999)) != 0 {
+		t.Errorf("Expected %s to equal 1999 cents", d)
+	}
+
+	// Rounds half away from zero
+	for input, cents := range map[string]int64{"0.125": 13, "-0.125": -13, "0.124": 12, "3": 300} {
+		parsed, err := ParseDecimal(input)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", input, err)
+		}
+		if parsed.Cents() != cents {
+			t.Errorf("Expected %s to be %d cents, got %d", input, cents, parsed.Cents())
+		}
+	}
+
+	for _, input := range []string{"", "abc", "1/3"} {
+		if _, err := ParseDecimal(input); err == nil {
+			t.Errorf("Expected error parsing %q", input)
+		}
+	}
+
+	price := Price{Currency: "USD", AmountCents: 500, Amount: NewDecimal(49999, 4)}
+	data, err := json.Marshal(price)
+	if err != nil {
+		t.Fatalf("Failed to marshal price: %v", err)
+	}
+	var decoded Price
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal price %s: %v", data, err)
+	}
+	if decoded.Amount == nil || decoded.Amount.String() != "4.9999" {
+		t.Errorf("Expected amount 4.9999 from %s, got %v", data, decoded.Amount)
+	}
+	if (Price{AmountCents: 250}).Decimal().String() != "2.5" {
+		t.Errorf("Expected Decimal() to fall back to AmountCents")
+	}
+
+	t.Log("✓ Decimal parses, rounds and marshals exactly")
+}
+
+func TestLogicalTypesProductRoundTrip(t *testing.T) {
+	manager, err := NewManager("tmp/test_logical_product")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer os.RemoveAll("tmp/test_logical_product")
+
+	release := time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)
+	product := manager.CreateSampleProducts(1)[0]
+	product.Price.Amount = NewDecimal(1999, 2)
+	product.ReleaseDate = &release
+
+	serializers := map[string]func(Product) ([]byte, error){
+		"binary": manager.SerializeProductBinary,
+		"json":   manager.SerializeProductJSON,
+	}
+	deserializers := map[string]func([]byte) (Product, error){
+		"binary": manager.DeserializeProductBinary,
+		"json":   manager.DeserializeProductJSON,
+	}
+	for name, serialize := range serializers {
+		data, err := serialize(product)
+		if err != nil {
+			t.Fatalf("Failed to serialize %s product: %v", name, err)
+		}
+		decoded, err := deserializers[name](data)
+		if err != nil {
+			t.Fatalf("Failed to deserialize %s product: %v", name, err)
+		}
+		if decoded.Price.Amount == nil || decoded.Price.Amount.Cmp(product.Price.Amount) != 0 {
+			t.Errorf("%s: amount mismatch: expected %s, got %s", name, product.Price.Amount, decoded.Price.Amount)
+		}
+		if decoded.ReleaseDate == nil || !decoded.ReleaseDate.Equal(release) {
+			t.Errorf("%s: release date mismatch: expected %v, got %v", name, release, decoded.ReleaseDate)
+		}
+	}
+
+	// The generic mapper handles the same logical types
+	var mapped Product
+	data, err := manager.SerializeStruct(manager.GetProductSchema(), product)
+	if err != nil {
+		t.Fatalf("Failed to serialize product struct: %v", err)
+	}
+	if err := manager.DeserializeStruct(manager.GetProductSchema(), data, &mapped); err != nil {
+		t.Fatalf("Failed to deserialize product struct: %v", err)
+	}
+	if mapped.Price.Amount == nil || mapped.Price.Amount.String() != "19.99" || !mapped.ReleaseDate.Equal(release) {
+		t.Errorf("Mapper mismatch: amount %s, release %v", mapped.Price.Amount, mapped.ReleaseDate)
+	}
+
+	// Optional logical fields stay nil
+	product.Price.Amount, product.ReleaseDate = nil, nil
+	data, err = manager.SerializeProductBinary(product)
+	if err != nil {
+		t.Fatalf("Failed to serialize product: %v", err)
+	}
+	decoded, err := manager.DeserializeProductBinary(data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize product: %v", err)
+	}
+	if decoded.Price.Amount != nil || decoded.ReleaseDate != nil {
+		t.Errorf("Expected nil amount and release date, got %s / %v", decoded.Price.Amount, decoded.ReleaseDate)
+	}
+
+	t.Log("✓ Decimal and date round-trip on products")
+}
+
+func TestLogicalTypesOrderRoundTrip(t *testing.T) {
+	native, err := NewManager("tmp/test_logical_order")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer os.RemoveAll("tmp/test_logical_order")
+	mapped, _ := NewManager("tmp/test_logical_order")
+	mapped.WithNativeStructs(false)
+
+	id := "6f1c2a8e-3b4d-4e5f-8a9b-0c1d2e3f4a5b"
+	deliveryTime := 14*time.Hour + 30*time.Minute
+	authorizedAt := time.Date(2024, time.March, 15, 10, 4, 5, 123456000, time.UTC)
+	transaction := "txn-1"
+
+	order := benchmarkOrder()
+	order.UUID = &id
+	order.ShippingInfo.DeliveryTime = &deliveryTime
+	order.PaymentInfo = &PaymentInfo{
+		Method:        "credit_card",
+		Status:        PaymentStatusAuthorized,
+		TransactionID: &transaction,
+		Amount:        order.Summary.Total,
+		AuthorizedAt:  &authorizedAt,
+	}
+
+	for name, manager := range map[string]*Manager{"native": native, "mapper": mapped} {
+		data, err := manager.SerializeStruct(manager.GetOrderSchema(), order)
+		if err != nil {
+			t.Fatalf("%s: failed to serialize order: %v", name, err)
+		}
+		var decoded Order
+		if err := manager.DeserializeStruct(manager.GetOrderSchema(), data, &decoded); err != nil {
+			t.Fatalf("%s: failed to deserialize order: %v", name, err)
+		}
+
+		if decoded.UUID == nil || *decoded.UUID != id {
+			t.Errorf("%s: uuid mismatch: %v", name, decoded.UUID)
+		}
+		if decoded.ShippingInfo == nil || decoded.ShippingInfo.DeliveryTime == nil || *decoded.ShippingInfo.DeliveryTime != deliveryTime {
+			t.Errorf("%s: delivery time mismatch: %+v", name, decoded.ShippingInfo)
+		}
+		// timestamp-micros keeps the microseconds timestamp-millis would drop
+		if decoded.PaymentInfo == nil || decoded.PaymentInfo.AuthorizedAt == nil || !decoded.PaymentInfo.AuthorizedAt.Equal(authorizedAt) {
+			t.Errorf("%s: authorizedAt mismatch: %+v", name, decoded.PaymentInfo)
+		}
+	}
+
+	// The mapper rejects ids that are not UUIDs
+	bad := "not-a-uuid"
+	order.UUID = &bad
+	if _, err := mapped.SerializeStruct(mapped.GetOrderSchema(), order); err == nil {
+		t.Error("Expected error for invalid uuid")
+	}
+
+	t.Log("✓ uuid, time-millis and timestamp-micros round-trip on orders")
+}
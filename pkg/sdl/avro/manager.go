@@ -2,14 +2,28 @@ package avro
 
 import (
 	"bytes"
+	"context"
 	"embed"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/hamba/avro/v2"
+
+	"go-transport-prac/internal/atomicfile"
+	"go-transport-prac/internal/ctxio"
+	"go-transport-prac/internal/filelock"
+	"go-transport-prac/internal/tracing"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/catalog"
+	"go-transport-prac/pkg/sdl/checksum"
+	"go-transport-prac/pkg/sdl/deadletter"
+	"go-transport-prac/pkg/sdl/encryption"
 )
 
 // Embed schema files
@@ -17,11 +31,39 @@ import (
 var schemaFiles embed.FS
 
 // Manager handles Avro serialization and deserialization operations
+//
+// Once configured, a Manager is safe for concurrent use; the With methods are
+// not. Writes, deletes and repairs of a file are serialized across every
+// manager in the process, and files are replaced atomically, so readers see
+// the old or the new contents without waiting. Other processes writing the
+// same directory are not covered by the locks
 type Manager struct {
 	baseDir     string
 	userSchema  avro.Schema
 	productSchema avro.Schema
 	orderSchema avro.Schema
+	analyticsSchema avro.Schema
+	userEnvelopeSchema avro.Schema
+	clock       types.Clock
+	// mapOnly disables the struct-tag fast path
+	mapOnly     bool
+	// storage, when set, replaces baseDir for file reads and writes
+	storage     types.Storage
+	// decodeMode and deadLetters control how ReadUsersFromFile handles bad records
+	decodeMode  deadletter.Mode
+	deadLetters *deadletter.File
+	// keys, when set, encrypts every file the manager writes
+	keys encryption.KeyProvider
+	// plaintextReads lets a manager with keys read files that are not encrypted
+	plaintextReads bool
+	// checksums records a SHA-256 sidecar for every file the manager writes
+	checksums bool
+	// catalog, when set, records every file the manager writes
+	catalog *catalog.Catalog
+	// sync flushes every written file to stable storage before returning
+	sync bool
+	// codecs caches the pooled writers and readers of each schema
+	codecs sync.Map
 }
 
 // NewManager creates a new Avro manager
@@ -32,6 +74,7 @@ func NewManager(baseDir string) (*Manager, error) {
 
 	manager := &Manager{
 		baseDir: baseDir,
+		clock:   types.SystemClock{},
 	}
 
 	// Load schemas
@@ -42,6 +85,75 @@ func NewManager(baseDir string) (*Manager, error) {
 	return manager, nil
 }
 
+// WithClock sets the clock used for generated CreatedAt/UpdatedAt timestamps
+func (m *Manager) WithClock(clock types.Clock) *Manager {
+	m.clock = types.ClockOrSystem(clock)
+	return m
+}
+
+// WithStorage sends file reads and writes to storage instead of baseDir.
+// Filenames are used as object keys
+func (m *Manager) WithStorage(storage types.Storage) *Manager {
+	m.storage = storage
+	return m
+}
+
+// WithEncryption encrypts every file the manager writes with the current key
+// of keys and decrypts the files it reads. Files that are not encrypted then
+// fail to read, see WithPlaintextReads
+func (m *Manager) WithEncryption(keys encryption.KeyProvider) *Manager {
+	m.keys = keys
+	return m
+}
+
+// WithPlaintextReads lets a manager with encryption read files written before
+// it was turned on, while they are migrated. Leave it off otherwise, so a
+// plain file put in place of an encrypted one is not read as data
+func (m *Manager) WithPlaintextReads(on bool) *Manager {
+	m.plaintextReads = on
+	return m
+}
+
+// WithChecksums records the SHA-256 checksum of every file the manager writes
+// in a sidecar named after it, see VerifyFile
+func (m *Manager) WithChecksums(on bool) *Manager {
+	m.checksums = on
+	return m
+}
+
+// WithCatalog records every file the manager writes in c, with its entity,
+// record count, schema fingerprint, size and checksum. DeleteFile drops the
+// file's entry
+func (m *Manager) WithCatalog(c *catalog.Catalog) *Manager {
+	m.catalog = c
+	return m
+}
+
+// WithSync flushes every file the manager writes, and its rename into place,
+// to stable storage before the write returns. Files are always replaced
+// atomically; without sync a power loss can still lose a recent write
+func (m *Manager) WithSync(on bool) *Manager {
+	m.sync = on
+	return m
+}
+
+// Formats of the files the manager writes, as recorded in its catalog
+const (
+	formatAvro = "avro"
+	formatOCF  = "avro-ocf"
+)
+
+// catalogEntry describes a file about to be written for the catalog
+func (m *Manager) catalogEntry(format, entity string, schema avro.Schema, records int) catalog.Entry {
+	fingerprint := schema.Fingerprint()
+	return catalog.Entry{
+		Entity:        entity,
+		Format:        format,
+		SchemaVersion: hex.EncodeToString(fingerprint[:]),
+		Records:       int64(records),
+	}
+}
+
 // loadSchemas loads all Avro schemas from embedded files
 func (m *Manager) loadSchemas() error {
 	// Load user schema
@@ -77,6 +189,28 @@ func (m *Manager) loadSchemas() error {
 		return fmt.Errorf("failed to parse order schema: %w", err)
 	}
 
+	// Load analytics schema
+	analyticsSchemaBytes, err := schemaFiles.ReadFile("schemas/analytics.avsc")
+	if err != nil {
+		return fmt.Errorf("failed to read analytics schema: %w", err)
+	}
+
+	m.analyticsSchema, err = avro.Parse(string(analyticsSchemaBytes))
+	if err != nil {
+		return fmt.Errorf("failed to parse analytics schema: %w", err)
+	}
+
+	// Load record headers and build the user envelope schema
+	headersSchemaBytes, err := schemaFiles.ReadFile("schemas/record_headers.avsc")
+	if err != nil {
+		return fmt.Errorf("failed to read record headers schema: %w", err)
+	}
+
+	m.userEnvelopeSchema, err = newEnvelopeSchema("UserEnvelope", string(headersSchemaBytes), m.userSchema)
+	if err != nil {
+		return fmt.Errorf("failed to build user envelope schema: %w", err)
+	}
+
 	return nil
 }
 
@@ -87,154 +221,295 @@ func (m *Manager) ensureDir() error {
 
 // SerializeUserJSON serializes a user to JSON using Avro schema
 func (m *Manager) SerializeUserJSON(user User) ([]byte, error) {
+	if data, ok := m.marshalNative(m.userSchema, user); ok {
+		return data, nil
+	}
+
 	// Convert to Avro-compatible map
 	data := m.userToAvroMap(user)
-	return avro.Marshal(m.userSchema, data)
+	return m.codec(m.userSchema).encode(data)
 }
 
 // DeserializeUserJSON deserializes a user from JSON using Avro schema
 func (m *Manager) DeserializeUserJSON(data []byte) (User, error) {
+	var native User
+	if m.unmarshalNative(m.userSchema, data, &native) {
+		return native, nil
+	}
+
 	var result interface{}
-	err := avro.Unmarshal(m.userSchema, data, &result)
+	err := m.codec(m.userSchema).decode(data, &result)
 	if err != nil {
 		return User{}, fmt.Errorf("failed to unmarshal user: %w", err)
 	}
 
-	return m.avroMapToUser(result.(map[string]interface{}))
+	return m.avroMapToUser(result)
 }
 
 // SerializeUserBinary serializes a user to binary using Avro
 func (m *Manager) SerializeUserBinary(user User) ([]byte, error) {
-	data := m.userToAvroMap(user)
-	
-	var buf bytes.Buffer
-	encoder := avro.NewEncoderForSchema(m.userSchema, &buf)
+	if data, ok := m.marshalNative(m.userSchema, user); ok {
+		return data, nil
+	}
 
-	err := encoder.Encode(data)
+	encoded, err := m.codec(m.userSchema).encode(m.userToAvroMap(user))
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode user: %w", err)
 	}
 
-	return buf.Bytes(), nil
+	return encoded, nil
 }
 
 // DeserializeUserBinary deserializes a user from binary using Avro
 func (m *Manager) DeserializeUserBinary(data []byte) (User, error) {
-	reader := bytes.NewReader(data)
-	decoder := avro.NewDecoderForSchema(m.userSchema, reader)
+	var native User
+	if m.unmarshalNative(m.userSchema, data, &native) {
+		return native, nil
+	}
 
 	var result interface{}
-	err := decoder.Decode(&result)
+	err := m.codec(m.userSchema).decode(data, &result)
 	if err != nil {
 		return User{}, fmt.Errorf("failed to decode user: %w", err)
 	}
 
-	return m.avroMapToUser(result.(map[string]interface{}))
+	return m.avroMapToUser(result)
 }
 
 // SerializeProductJSON serializes a product to JSON using Avro schema
 func (m *Manager) SerializeProductJSON(product Product) ([]byte, error) {
+	if data, ok := m.marshalNative(m.productSchema, product); ok {
+		return data, nil
+	}
+
 	data := m.productToAvroMap(product)
-	return avro.Marshal(m.productSchema, data)
+	return m.codec(m.productSchema).encode(data)
 }
 
 // DeserializeProductJSON deserializes a product from JSON using Avro schema
 func (m *Manager) DeserializeProductJSON(data []byte) (Product, error) {
+	var native Product
+	if m.unmarshalNative(m.productSchema, data, &native) {
+		return native, nil
+	}
+
 	var result interface{}
-	err := avro.Unmarshal(m.productSchema, data, &result)
+	err := m.codec(m.productSchema).decode(data, &result)
 	if err != nil {
 		return Product{}, fmt.Errorf("failed to unmarshal product: %w", err)
 	}
 
-	return m.avroMapToProduct(result.(map[string]interface{}))
+	return m.avroMapToProduct(result)
 }
 
 // SerializeProductBinary serializes a product to binary using Avro
 func (m *Manager) SerializeProductBinary(product Product) ([]byte, error) {
-	data := m.productToAvroMap(product)
-	
-	var buf bytes.Buffer
-	encoder := avro.NewEncoderForSchema(m.productSchema, &buf)
+	if data, ok := m.marshalNative(m.productSchema, product); ok {
+		return data, nil
+	}
 
-	err := encoder.Encode(data)
+	encoded, err := m.codec(m.productSchema).encode(m.productToAvroMap(product))
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode product: %w", err)
 	}
 
-	return buf.Bytes(), nil
+	return encoded, nil
 }
 
 // DeserializeProductBinary deserializes a product from binary using Avro
 func (m *Manager) DeserializeProductBinary(data []byte) (Product, error) {
-	reader := bytes.NewReader(data)
-	decoder := avro.NewDecoderForSchema(m.productSchema, reader)
+	var native Product
+	if m.unmarshalNative(m.productSchema, data, &native) {
+		return native, nil
+	}
 
 	var result interface{}
-	err := decoder.Decode(&result)
+	err := m.codec(m.productSchema).decode(data, &result)
 	if err != nil {
 		return Product{}, fmt.Errorf("failed to decode product: %w", err)
 	}
 
-	return m.avroMapToProduct(result.(map[string]interface{}))
+	return m.avroMapToProduct(result)
 }
 
 // WriteUsersToFile writes users to a binary Avro file
 func (m *Manager) WriteUsersToFile(filename string, users []User) error {
-	if err := m.ensureDir(); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
+	return m.WriteUsersToFileContext(context.Background(), filename, users)
+}
 
-	filePath := filepath.Join(m.baseDir, filename)
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer file.Close()
+// WriteUsersToFileContext writes users to a binary Avro file, stopping with
+// ctx.Err() once ctx is done. A cancelled write leaves no file behind
+func (m *Manager) WriteUsersToFileContext(ctx context.Context, filename string, users []User) (err error) {
+	ctx, span := startSpan(ctx, "avro.write", "user", filename)
+	defer func() { tracing.End(span, len(users), err) }()
 
-	encoder := avro.NewEncoderForSchema(m.userSchema, file)
+	return m.writeFile(ctx, filename, m.catalogEntry(formatAvro, "user", m.userSchema, len(users)), func(w io.Writer) error {
+		writer := m.NewUserStreamWriter(w)
 
-	for _, user := range users {
-		data := m.userToAvroMap(user)
-		err := encoder.Encode(data)
-		if err != nil {
-			return fmt.Errorf("failed to encode user %d: %w", user.ID, err)
+		for _, user := range users {
+			if err := writer.Write(user); err != nil {
+				return err
+			}
 		}
-	}
 
-	return nil
+		return nil
+	})
 }
 
-// ReadUsersFromFile reads users from a binary Avro file
+// ReadUsersFromFile reads users from a binary Avro file. See WithDecodeMode
+// for reading past bad records
 func (m *Manager) ReadUsersFromFile(filename string) ([]User, error) {
-	filePath := filepath.Join(m.baseDir, filename)
-	file, err := os.Open(filePath)
+	return m.ReadUsersFromFileContext(context.Background(), filename)
+}
+
+// ReadUsersFromFileContext reads users from a binary Avro file, stopping with
+// ctx.Err() once ctx is done
+func (m *Manager) ReadUsersFromFileContext(ctx context.Context, filename string) (result []User, err error) {
+	ctx, span := startSpan(ctx, "avro.read", "user", filename)
+	defer func() { tracing.End(span, len(result), err) }()
+
+	if m.decodeMode == deadletter.Tolerant {
+		return m.readUsersTolerant(ctx, filename)
+	}
+
+	file, err := m.openFile(ctx, filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, err
 	}
 	defer file.Close()
 
-	decoder := avro.NewDecoderForSchema(m.userSchema, file)
+	reader := m.NewUserStreamReader(file)
 
 	var users []User
-	for {
-		var result interface{}
-		err := decoder.Decode(&result)
-		if err != nil {
-			if err == io.EOF {
-				break // End of file
+	for reader.Next() {
+		users = append(users, reader.User())
+	}
+	return finishRead(ctx, users, reader.Err())
+}
+
+// finishRead returns ctx.Err() instead of records when ctx was cancelled
+// during a read, since a decoder takes a read cut short for the end of file
+func finishRead[T any](ctx context.Context, records []T, err error) ([]T, error) {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// writeFile runs write against a file in baseDir, or a buffer uploaded to the
+// storage backend once write returns. Writes fail once ctx is done, and a
+// local file cut short by cancellation is removed
+func (m *Manager) writeFile(ctx context.Context, filename string, entry catalog.Entry, write func(io.Writer) error) error {
+	// The file, its sidecar and its catalog entry change together
+	defer filelock.Files.Lock(filelock.Key(m.storage, m.baseDir, filename))()
+
+	counter := &byteCounter{}
+	defer func() { tracing.SetBytes(ctx, counter.n) }()
+
+	if m.keys != nil {
+		plain := write
+		write = func(w io.Writer) error { return encryption.Encrypt(ctx, w, m.keys, plain) }
+	}
+	// The checksum covers the stored bytes, so it also catches damage to
+	// encrypted files without needing their key
+	var hasher *checksum.Writer
+	if m.checksums || m.catalog != nil {
+		inner := write
+		write = func(w io.Writer) error {
+			hasher = checksum.NewWriter(w)
+			return inner(hasher)
+		}
+	}
+
+	if m.storage != nil {
+		var buf bytes.Buffer
+		counter.w = ctxio.NewWriter(ctx, &buf)
+		if err := write(counter); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
 			}
-			return nil, fmt.Errorf("failed to decode user: %w", err)
+			return err
 		}
+		if err := m.storage.Put(ctx, filename, &buf); err != nil {
+			return fmt.Errorf("failed to store file: %w", err)
+		}
+		if m.checksums {
+			if err := checksum.PutSidecar(ctx, m.storage, filename, hasher.Sum()); err != nil {
+				return err
+			}
+		}
+		return m.catalogFile(ctx, filename, entry, hasher)
+	}
 
-		user, err := m.avroMapToUser(result.(map[string]interface{}))
-		if err != nil {
-			return nil, fmt.Errorf("failed to convert avro map to user: %w", err)
+	if err := m.ensureDir(); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// Write to a temporary file renamed over filename once complete, so a
+	// failed or interrupted write never leaves a truncated file behind
+	filePath := filepath.Join(m.baseDir, filename)
+	file, err := atomicfile.Create(filePath, m.sync)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Abort()
+
+	counter.w = ctxio.NewWriter(ctx, file)
+	if err := write(counter); err != nil {
+		// The encoder error is only a symptom of the cancellation
+		if ctx.Err() != nil {
+			return ctx.Err()
 		}
+		return err
+	}
+	if err := file.Commit(); err != nil {
+		return err
+	}
+	if m.checksums {
+		if err := checksum.WriteSidecar(filePath, hasher.Sum()); err != nil {
+			return err
+		}
+	}
+	return m.catalogFile(ctx, filename, entry, hasher)
+}
 
-		users = append(users, user)
+// catalogFile records a written file in the catalog, if the manager has one
+func (m *Manager) catalogFile(ctx context.Context, filename string, entry catalog.Entry, hasher *checksum.Writer) error {
+	if m.catalog == nil {
+		return nil
+	}
+	entry.File = filename
+	entry.Bytes = hasher.Size()
+	entry.SHA256 = hasher.Sum()
+	entry.CreatedAt = m.clock.Now()
+	if err := m.catalog.Record(ctx, entry); err != nil {
+		return fmt.Errorf("failed to catalog file: %w", err)
 	}
+	return nil
+}
 
-	return users, nil
+// openFile opens a file in baseDir or the storage backend. Reads from it fail
+// once ctx is done, and closing it records the bytes read on the span in ctx
+func (m *Manager) openFile(ctx context.Context, filename string) (io.ReadCloser, error) {
+	var file io.ReadCloser
+	var err error
+	if m.storage != nil {
+		file, err = m.storage.Get(ctx, filename)
+	} else {
+		file, err = os.Open(filepath.Join(m.baseDir, filename))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	plain, err := encryption.Open(ctx, ctxio.NewReader(ctx, file), m.keys, m.plaintextReads)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return countedFile{&byteCounter{r: plain}, ctx, file}, nil
 }
 
 // GetUserSchema returns the user schema
@@ -252,10 +527,15 @@ func (m *Manager) GetOrderSchema() avro.Schema {
 	return m.orderSchema
 }
 
+// GetAnalyticsSchema returns the analytics event schema
+func (m *Manager) GetAnalyticsSchema() avro.Schema {
+	return m.analyticsSchema
+}
+
 // CreateSampleUsers creates sample user data for testing
 func (m *Manager) CreateSampleUsers(count int) []User {
 	users := make([]User, count)
-	now := time.Now()
+	now := m.clock.Now()
 
 	for i := 0; i < count; i++ {
 		phone := fmt.Sprintf("+1-555-%04d", i+1000)
@@ -293,7 +573,7 @@ func (m *Manager) CreateSampleUsers(count int) []User {
 // CreateSampleProducts creates sample product data for testing
 func (m *Manager) CreateSampleProducts(count int) []Product {
 	products := make([]Product, count)
-	now := time.Now()
+	now := m.clock.Now()
 
 	categories := [][]string{
 		{"Electronics", "Computers"},
@@ -346,8 +626,23 @@ func (m *Manager) CreateSampleProducts(count int) []Product {
 	return products
 }
 
-// ListFiles lists all Avro files in the base directory
+// ListFiles lists all Avro files in the base directory or storage backend
 func (m *Manager) ListFiles() ([]string, error) {
+	if m.storage != nil {
+		keys, err := m.storage.List(context.Background(), "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files: %w", err)
+		}
+
+		var files []string
+		for _, key := range keys {
+			if filepath.Ext(key) == ".avro" {
+				files = append(files, key)
+			}
+		}
+		return files, nil
+	}
+
 	if err := m.ensureDir(); err != nil {
 		return nil, fmt.Errorf("failed to create directory: %w", err)
 	}
@@ -367,8 +662,78 @@ func (m *Manager) ListFiles() ([]string, error) {
 	return files, nil
 }
 
-// DeleteFile deletes an Avro file
+// DeleteFile deletes an Avro file, its checksum sidecar and its catalog
+// entry, if any
 func (m *Manager) DeleteFile(filename string) error {
+	defer filelock.Files.Lock(filelock.Key(m.storage, m.baseDir, filename))()
+
+	if m.storage != nil {
+		ctx := context.Background()
+		if err := m.storage.Delete(ctx, filename); err != nil {
+			return err
+		}
+		if err := checksum.RemoveObjectSidecar(ctx, m.storage, filename); err != nil {
+			return err
+		}
+		return m.uncatalogFile(ctx, filename)
+	}
 	filePath := filepath.Join(m.baseDir, filename)
-	return os.Remove(filePath)
+	if err := os.Remove(filePath); err != nil {
+		return err
+	}
+	if err := checksum.RemoveSidecar(filePath); err != nil {
+		return err
+	}
+	return m.uncatalogFile(context.Background(), filename)
+}
+
+// uncatalogFile drops a deleted file from the catalog, if the manager has one
+func (m *Manager) uncatalogFile(ctx context.Context, filename string) error {
+	if m.catalog == nil {
+		return nil
+	}
+	return m.catalog.Remove(ctx, filename)
+}
+
+// StatFile returns the size and modification time of an Avro file. Objects in
+// a storage backend report the creation time recorded in the catalog, or a
+// zero time when the manager has no entry for them
+func (m *Manager) StatFile(filename string) (fs.FileInfo, error) {
+	if m.storage == nil {
+		return os.Stat(filepath.Join(m.baseDir, filename))
+	}
+	if m.catalog != nil {
+		if e, ok := m.catalog.Get(filename); ok {
+			return catalog.FileInfo(e), nil
+		}
+	}
+	obj, err := m.storage.Get(context.Background(), filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer obj.Close()
+	size, err := io.Copy(io.Discard, obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return catalog.FileInfo(catalog.Entry{File: filename, Bytes: size}), nil
+}
+
+// VerifyFile checks a file against the checksum recorded when it was
+// written with WithChecksums. It returns a *checksum.MismatchError for a
+// corrupted file and an error wrapping checksum.ErrNoChecksum when no
+// checksum was recorded
+func (m *Manager) VerifyFile(filename string) error {
+	return m.VerifyFileContext(context.Background(), filename)
+}
+
+// VerifyFileContext is VerifyFile, stopping once ctx is done
+func (m *Manager) VerifyFileContext(ctx context.Context, filename string) error {
+	// Hold off writers, so the file is checked against its own sidecar
+	defer filelock.Files.RLock(filelock.Key(m.storage, m.baseDir, filename))()
+
+	if m.storage != nil {
+		return checksum.VerifyObject(ctx, m.storage, filename)
+	}
+	return checksum.VerifyFile(ctx, filepath.Join(m.baseDir, filename))
 }
\ No newline at end of file
@@ -1,9 +1,22 @@
 package avro
 
 import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
+
+	"go-transport-prac/internal/atomicfile"
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/catalog"
+	"go-transport-prac/pkg/sdl/checksum"
+	"go-transport-prac/pkg/sdl/encryption"
+	"go-transport-prac/pkg/storage"
 )
 
 func TestAvroManagerCreation(t *testing.T) {
@@ -349,6 +362,297 @@ func TestFileOperations(t *testing.T) {
 	t.Logf("✓ File operations successful: wrote and read %d users", len(users))
 }
 
+func TestFileOperationsWithStorage(t *testing.T) {
+	backend := storage.NewMemoryStorage()
+	manager, err := NewManager("tmp/test_storage_ops")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	manager.WithStorage(backend)
+
+	users := manager.CreateSampleUsers(3)
+	if err := manager.WriteUsersToFile("users.avro", users); err != nil {
+		t.Fatalf("Failed to write users to storage: %v", err)
+	}
+
+	// Nothing may reach the local directory
+	if _, err := os.Stat("tmp/test_storage_ops"); !os.IsNotExist(err) {
+		t.Errorf("Expected no local directory, stat returned %v", err)
+		os.RemoveAll("tmp/test_storage_ops")
+	}
+
+	readUsers, err := manager.ReadUsersFromFile("users.avro")
+	if err != nil {
+		t.Fatalf("Failed to read users from storage: %v", err)
+	}
+	if len(readUsers) != len(users) || readUsers[2].Email != users[2].Email {
+		t.Errorf("User mismatch: wrote %d, read %d", len(users), len(readUsers))
+	}
+
+	files, err := manager.ListFiles()
+	if err != nil || !reflect.DeepEqual(files, []string{"users.avro"}) {
+		t.Errorf("Expected [users.avro], got %v (%v)", files, err)
+	}
+
+	if err := manager.DeleteFile("users.avro"); err != nil {
+		t.Fatalf("Failed to delete file: %v", err)
+	}
+	if _, err := manager.ReadUsersFromFile("users.avro"); err == nil {
+		t.Error("Expected reading a deleted file to fail")
+	}
+
+	t.Log("✓ File operations target the storage backend")
+}
+
+func TestFileOperationsWithEncryption(t *testing.T) {
+	testDir := "tmp/test_encrypted_ops"
+	defer os.RemoveAll(testDir)
+	keys, err := encryption.NewStaticKeys("k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	if err != nil {
+		t.Fatalf("Failed to create keys: %v", err)
+	}
+	plainManager, err := NewManager(testDir)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	manager, _ := NewManager(testDir)
+	manager.WithEncryption(keys)
+
+	users := manager.CreateSampleUsers(3)
+	if err := manager.WriteUsersToFile("users.avro", users); err != nil {
+		t.Fatalf("Failed to write encrypted users: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(testDir, "users.avro"))
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if !encryption.IsEncrypted(data) || bytes.Contains(data, []byte(users[0].Email)) {
+		t.Fatal("Expected the file to be encrypted")
+	}
+
+	readUsers, err := manager.ReadUsersFromFile("users.avro")
+	if err != nil {
+		t.Fatalf("Failed to read encrypted users: %v", err)
+	}
+	if len(readUsers) != len(users) || readUsers[1].Email != users[1].Email {
+		t.Errorf("User mismatch: wrote %d, read %d", len(users), len(readUsers))
+	}
+	if _, err := plainManager.ReadUsersFromFile("users.avro"); err == nil {
+		t.Error("Expected an encrypted file to fail without keys")
+	}
+
+	// Files written before encryption was turned on only read while
+	// plaintext reads are allowed for migrating them
+	if err := plainManager.WriteUsersToFile("plain.avro", users); err != nil {
+		t.Fatalf("Failed to write plain users: %v", err)
+	}
+	if _, err := manager.ReadUsersFromFile("plain.avro"); !errors.Is(err, encryption.ErrNotEncrypted) {
+		t.Errorf("Expected a plain file to be rejected with encryption on, got %v", err)
+	}
+	manager.WithPlaintextReads(true)
+	if readUsers, err := manager.ReadUsersFromFile("plain.avro"); err != nil || len(readUsers) != len(users) {
+		t.Errorf("Expected plain files to read with plaintext reads allowed, got %d users (%v)", len(readUsers), err)
+	}
+	manager.WithPlaintextReads(false)
+
+	// Containers and storage backends go through the same layer
+	manager.WithStorage(storage.NewMemoryStorage())
+	if err := manager.WriteUsersToOCFFile("users.ocf", users); err != nil {
+		t.Fatalf("Failed to write encrypted container: %v", err)
+	}
+	if readUsers, err := manager.ReadUsersFromOCFFile("users.ocf"); err != nil || len(readUsers) != len(users) {
+		t.Errorf("Expected the encrypted container to read back, got %d users (%v)", len(readUsers), err)
+	}
+
+	t.Log("✓ Files are encrypted at rest and decrypted transparently")
+}
+
+func TestFileOperationsWithChecksums(t *testing.T) {
+	testDir := "tmp/test_checksum_ops"
+	defer os.RemoveAll(testDir)
+	manager, err := NewManager(testDir)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	manager.WithChecksums(true)
+
+	users := manager.CreateSampleUsers(3)
+	if err := manager.WriteUsersToFile("users.avro", users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	if err := manager.VerifyFile("users.avro"); err != nil {
+		t.Fatalf("Expected a fresh file to verify: %v", err)
+	}
+
+	// Flip one byte in the middle of the file
+	path := filepath.Join(testDir, "users.avro")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	data[len(data)/2] ^= 0xff
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to corrupt file: %v", err)
+	}
+	var mismatch *checksum.MismatchError
+	if err := manager.VerifyFile("users.avro"); !errors.As(err, &mismatch) {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+
+	manager.WithChecksums(false)
+	if err := manager.WriteUsersToFile("plain.avro", users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	if err := manager.VerifyFile("plain.avro"); !errors.Is(err, checksum.ErrNoChecksum) {
+		t.Errorf("Expected ErrNoChecksum for a file written without checksums, got %v", err)
+	}
+
+	if err := manager.DeleteFile("users.avro"); err != nil {
+		t.Fatalf("Failed to delete file: %v", err)
+	}
+	if _, err := os.Stat(checksum.SidecarName(path)); !os.IsNotExist(err) {
+		t.Error("Expected the sidecar to be deleted with its file")
+	}
+
+	// Storage backends keep the sidecar as an object next to the file
+	store := storage.NewMemoryStorage()
+	manager.WithStorage(store).WithChecksums(true)
+	if err := manager.WriteUsersToOCFFile("users.ocf", users); err != nil {
+		t.Fatalf("Failed to write container: %v", err)
+	}
+	if err := manager.VerifyFile("users.ocf"); err != nil {
+		t.Errorf("Expected the stored container to verify: %v", err)
+	}
+	if err := manager.DeleteFile("users.ocf"); err != nil {
+		t.Fatalf("Failed to delete object: %v", err)
+	}
+	if keys, _ := store.List(t.Context(), ""); len(keys) != 0 {
+		t.Errorf("Expected no objects left, got %v", keys)
+	}
+
+	t.Log("✓ Checksums are recorded on write and catch corrupted files")
+}
+
+func TestFileOperationsWithCatalog(t *testing.T) {
+	testDir := "tmp/test_catalog_ops"
+	defer os.RemoveAll(testDir)
+	manager, err := NewManager(testDir)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	files := catalog.New()
+	manager.WithClock(testutil.NewDefaultFakeClock()).WithCatalog(files)
+
+	users := manager.CreateSampleUsers(4)
+	if err := manager.WriteUsersToFile("users.avro", users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	ctx := catalog.WithTags(t.Context(), map[string]string{"source": "import"})
+	if err := manager.WriteProductsToOCFFileContext(ctx, "products.ocf", manager.CreateSampleProducts(2)); err != nil {
+		t.Fatalf("Failed to write products: %v", err)
+	}
+
+	entry, ok := files.Get("users.avro")
+	if !ok {
+		t.Fatal("Expected the users file to be catalogued")
+	}
+	info, _ := os.Stat(filepath.Join(testDir, "users.avro"))
+	if entry.Entity != "user" || entry.Format != "avro" || entry.Records != 4 || entry.Bytes != info.Size() {
+		t.Errorf("Unexpected entry %+v for a %d byte file", entry, info.Size())
+	}
+	if err := checksum.Verify(mustOpen(t, filepath.Join(testDir, "users.avro")), checksum.Format(entry.SHA256, "users.avro"), "users.avro"); err != nil {
+		t.Errorf("Expected the catalogued checksum to match the file: %v", err)
+	}
+	fingerprint := manager.GetUserSchema().Fingerprint()
+	if entry.SchemaVersion != hex.EncodeToString(fingerprint[:]) {
+		t.Errorf("Expected the user schema fingerprint, got %q", entry.SchemaVersion)
+	}
+
+	products := files.ListByEntity("product")
+	if len(products) != 1 || products[0].Format != "avro-ocf" || products[0].Tags["source"] != "import" {
+		t.Errorf("Expected a tagged container entry, got %+v", products)
+	}
+
+	if err := manager.DeleteFile("users.avro"); err != nil {
+		t.Fatalf("Failed to delete file: %v", err)
+	}
+	if _, ok := files.Get("users.avro"); ok {
+		t.Error("Expected the entry to be dropped with its file")
+	}
+
+	t.Log("✓ Written files are catalogued with their metadata")
+}
+
+func TestFileOperationsAtomic(t *testing.T) {
+	testDir := "tmp/test_atomic_ops"
+	defer os.RemoveAll(testDir)
+	manager, err := NewManager(testDir)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	manager.WithChecksums(true).WithSync(true)
+
+	users := manager.CreateSampleUsers(3)
+	if err := manager.WriteUsersToFile("users.avro", users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	original, _ := os.ReadFile(filepath.Join(testDir, "users.avro"))
+
+	// Fail halfway through replacing the file
+	failure := errors.New("disk full")
+	err = manager.writeFile(t.Context(), "users.avro", catalog.Entry{}, func(w io.Writer) error {
+		w.Write(original[:len(original)/2])
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Expected the write error, got %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(testDir, "users.avro")); !bytes.Equal(data, original) {
+		t.Error("Expected a failed write to leave the previous file intact")
+	}
+	if err := manager.VerifyFile("users.avro"); err != nil {
+		t.Errorf("Expected the previous file to still match its checksum: %v", err)
+	}
+	// A failed write into a new file leaves nothing behind
+	manager.writeFile(t.Context(), "new.avro", catalog.Entry{}, func(w io.Writer) error {
+		w.Write(original[:10])
+		return failure
+	})
+	entries, _ := os.ReadDir(testDir)
+	if len(entries) != 2 {
+		t.Errorf("Expected only users.avro and its checksum, found %d files", len(entries))
+	}
+
+	// A crash leaves the temporary file, which is not listed as data
+	crashed, err := atomicfile.Create(filepath.Join(testDir, "crashed.avro"), false)
+	if err != nil {
+		t.Fatalf("Failed to create temporary file: %v", err)
+	}
+	crashed.Write(original[:10])
+	crashed.Close()
+	files, err := manager.ListFiles()
+	if err != nil || len(files) != 1 || files[0] != "users.avro" {
+		t.Errorf("Expected only users.avro to be listed, got %v (%v)", files, err)
+	}
+
+	readBack, err := manager.ReadUsersFromFile("users.avro")
+	if err != nil || len(readBack) != len(users) {
+		t.Errorf("Expected %d users after failed writes, got %d (%v)", len(users), len(readBack), err)
+	}
+
+	t.Log("✓ Failed writes never leave truncated files")
+}
+
+func mustOpen(t *testing.T, path string) *os.File {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	t.Cleanup(func() { file.Close() })
+	return file
+}
+
 func TestSampleDataGeneration(t *testing.T) {
 	manager, err := NewManager("tmp/test_samples")
 	if err != nil {
@@ -407,4 +711,47 @@ func TestSampleDataGeneration(t *testing.T) {
 	}
 
 	t.Log("✓ Sample data generation successful")
-}
\ No newline at end of file
+}
+
+func TestSampleDataWithFakeClock(t *testing.T) {
+	clock := testutil.NewDefaultFakeClock()
+	manager, err := NewManager("tmp/test_avro_clock")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	manager.WithClock(clock)
+
+	users := manager.CreateSampleUsers(3)
+	if !users[0].UpdatedAt.Equal(testutil.DefaultFakeTime) {
+		t.Errorf("Expected UpdatedAt %v, got %v", testutil.DefaultFakeTime, users[0].UpdatedAt)
+	}
+	if !users[2].CreatedAt.Equal(testutil.DefaultFakeTime.Add(-2 * time.Hour)) {
+		t.Errorf("Unexpected CreatedAt %v", users[2].CreatedAt)
+	}
+
+	// Identical clocks must produce identical records
+	clock.Set(testutil.DefaultFakeTime)
+	if again := manager.CreateSampleUsers(3); !reflect.DeepEqual(users, again) {
+		t.Error("Expected deterministic sample users")
+	}
+
+	products := manager.CreateSampleProducts(1)
+	if !products[0].UpdatedAt.Equal(testutil.DefaultFakeTime) {
+		t.Errorf("Expected product UpdatedAt %v, got %v", testutil.DefaultFakeTime, products[0].UpdatedAt)
+	}
+
+	registry := NewSchemaRegistry().WithClock(clock)
+	id, err := registry.RegisterSchema("users-value", manager.GetUserSchema().String())
+	if err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+	metadata, err := registry.GetSchema(id)
+	if err != nil {
+		t.Fatalf("Failed to get schema: %v", err)
+	}
+	if !metadata.CreatedAt.Equal(testutil.DefaultFakeTime) {
+		t.Errorf("Expected registry CreatedAt %v, got %v", testutil.DefaultFakeTime, metadata.CreatedAt)
+	}
+
+	t.Log("✓ Sample data timestamps come from the injected clock")
+}
@@ -0,0 +1,490 @@
+package avro
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hamba/avro/v2"
+)
+
+// fieldIndexCache caches Avro field name -> struct field index lookups per struct type
+var fieldIndexCache sync.Map
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// StructToAvro converts a struct (or pointer to struct) into the generic value expected
+// by schema. Struct fields are matched by their `avro` tag, then `json` tag, then name.
+func StructToAvro(schema avro.Schema, v interface{}) (interface{}, error) {
+	return encodeValue(schema, reflect.ValueOf(v), "$")
+}
+
+// AvroToStruct populates the value pointed to by v from a decoded generic Avro value
+func AvroToStruct(schema avro.Schema, data interface{}, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("target must be a non-nil pointer, got %T", v)
+	}
+	return decodeValue(schema, data, rv.Elem(), "$")
+}
+
+// SerializeStruct serializes any tagged struct to binary Avro without a bespoke converter
+func (m *Manager) SerializeStruct(schema avro.Schema, v interface{}) ([]byte, error) {
+	if avroTagged(v) {
+		if encoded, ok := m.marshalNative(schema, v); ok {
+			return encoded, nil
+		}
+	}
+
+	data, err := StructToAvro(schema, v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map struct: %w", err)
+	}
+
+	encoded, err := m.codec(schema).encode(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode struct: %w", err)
+	}
+
+	return encoded, nil
+}
+
+// DeserializeStruct deserializes binary Avro into any tagged struct without a bespoke converter
+func (m *Manager) DeserializeStruct(schema avro.Schema, data []byte, v interface{}) error {
+	if avroTagged(v) && m.unmarshalNative(schema, data, v) {
+		return nil
+	}
+
+	var result interface{}
+	if err := m.codec(schema).decode(data, &result); err != nil {
+		return fmt.Errorf("failed to decode struct: %w", err)
+	}
+
+	if err := AvroToStruct(schema, result, v); err != nil {
+		return fmt.Errorf("failed to map struct: %w", err)
+	}
+
+	return nil
+}
+
+// encodeValue walks schema and rv together producing a value hamba/avro can encode
+func encodeValue(schema avro.Schema, rv reflect.Value, path string) (interface{}, error) {
+	schema = derefSchema(schema)
+
+	for rv.IsValid() && (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) {
+		if rv.IsNil() {
+			rv = reflect.Value{}
+			break
+		}
+		rv = rv.Elem()
+	}
+
+	if union, ok := schema.(*avro.UnionSchema); ok {
+		return encodeUnion(union, rv, path)
+	}
+
+	if !rv.IsValid() {
+		if schema.Type() == avro.Null {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: nil value for non-nullable %s", path, schema.Type())
+	}
+
+	switch s := schema.(type) {
+	case *avro.RecordSchema:
+		if rv.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("%s: cannot map %s to record %s", path, rv.Type(), s.FullName())
+		}
+		indexes := structFieldIndexes(rv.Type())
+		record := make(map[string]interface{}, len(s.Fields()))
+		for _, field := range s.Fields() {
+			index, found := indexes[field.Name()]
+			if !found {
+				if !field.HasDefault() {
+					return nil, fmt.Errorf("%s.%s: no struct field and no schema default", path, field.Name())
+				}
+				record[field.Name()] = field.Default()
+				continue
+			}
+			value, err := encodeValue(field.Type(), rv.FieldByIndex(index), path+"."+field.Name())
+			if err != nil {
+				return nil, err
+			}
+			record[field.Name()] = value
+		}
+		return record, nil
+
+	case *avro.EnumSchema:
+		if rv.Kind() != reflect.String {
+			return nil, fmt.Errorf("%s: cannot map %s to enum %s", path, rv.Type(), s.FullName())
+		}
+		if !containsString(s.Symbols(), rv.String()) {
+			return nil, fmt.Errorf("%s: %q is not a symbol of enum %s", path, rv.String(), s.FullName())
+		}
+		return rv.String(), nil
+
+	case *avro.ArraySchema:
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return nil, fmt.Errorf("%s: cannot map %s to array", path, rv.Type())
+		}
+		items := make([]interface{}, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			item, err := encodeValue(s.Items(), rv.Index(i), fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+
+	case *avro.MapSchema:
+		if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("%s: cannot map %s to map", path, rv.Type())
+		}
+		values := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			value, err := encodeValue(s.Values(), iter.Value(), path+"."+key)
+			if err != nil {
+				return nil, err
+			}
+			values[key] = value
+		}
+		return values, nil
+
+	case *avro.FixedSchema:
+		if rv.Kind() != reflect.Array || rv.Type().Elem().Kind() != reflect.Uint8 || rv.Len() != s.Size() {
+			return nil, fmt.Errorf("%s: cannot map %s to fixed(%d)", path, rv.Type(), s.Size())
+		}
+		return rv.Interface(), nil
+	}
+
+	return encodePrimitive(schema, rv, path)
+}
+
+// encodeUnion picks the first union branch the value maps to and wraps it with the branch name
+func encodeUnion(union *avro.UnionSchema, rv reflect.Value, path string) (interface{}, error) {
+	if !rv.IsValid() {
+		if union.Nullable() {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: nil value for non-nullable union", path)
+	}
+
+	for _, branch := range union.Types() {
+		if branch.Type() == avro.Null {
+			continue
+		}
+		value, err := encodeValue(branch, rv, path)
+		if err == nil {
+			return map[string]interface{}{unionBranchName(branch): value}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: %s matches no union branch", path, rv.Type())
+}
+
+// encodePrimitive converts Go scalars to the exact types hamba/avro expects
+func encodePrimitive(schema avro.Schema, rv reflect.Value, path string) (interface{}, error) {
+	switch schema.Type() {
+	case avro.Boolean:
+		if rv.Kind() == reflect.Bool {
+			return rv.Bool(), nil
+		}
+	case avro.Int:
+		switch logicalType(schema) {
+		case avro.Date:
+			if rv.Type() == timeType {
+				return rv.Interface().(time.Time), nil
+			}
+		case avro.TimeMillis:
+			if rv.Type() == durationType {
+				return time.Duration(rv.Int()), nil
+			}
+		}
+		if isIntKind(rv.Kind()) {
+			if rv.Int() < math.MinInt32 || rv.Int() > math.MaxInt32 {
+				return nil, fmt.Errorf("%s: %d overflows int", path, rv.Int())
+			}
+			return int(rv.Int()), nil
+		}
+		if isUintKind(rv.Kind()) {
+			if rv.Uint() > math.MaxInt32 {
+				return nil, fmt.Errorf("%s: %d overflows int", path, rv.Uint())
+			}
+			return int(rv.Uint()), nil
+		}
+	case avro.Long:
+		if rv.Type() == timeType {
+			return rv.Interface().(time.Time), nil
+		}
+		if rv.Type() == durationType && logicalType(schema) == avro.TimeMicros {
+			return time.Duration(rv.Int()), nil
+		}
+		if isIntKind(rv.Kind()) {
+			return rv.Int(), nil
+		}
+		if isUintKind(rv.Kind()) {
+			if rv.Uint() > math.MaxInt64 {
+				return nil, fmt.Errorf("%s: %d overflows long", path, rv.Uint())
+			}
+			return int64(rv.Uint()), nil
+		}
+	case avro.Float:
+		if isFloatKind(rv.Kind()) {
+			return float32(rv.Float()), nil
+		}
+	case avro.Double:
+		if isFloatKind(rv.Kind()) {
+			return rv.Float(), nil
+		}
+	case avro.String:
+		if rv.Kind() == reflect.String {
+			if logicalType(schema) == avro.UUID && !validUUID(rv.String()) {
+				return nil, fmt.Errorf("%s: %q is not a valid uuid", path, rv.String())
+			}
+			return rv.String(), nil
+		}
+	case avro.Bytes:
+		if logicalType(schema) == avro.Decimal && rv.Type().ConvertibleTo(ratType) {
+			rat := rv.Convert(ratType).Interface().(big.Rat)
+			return &rat, nil
+		}
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Bytes(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: cannot map %s to %s", path, rv.Type(), schema.Type())
+}
+
+// decodeValue writes the generic Avro value data into rv following schema
+func decodeValue(schema avro.Schema, data interface{}, rv reflect.Value, path string) error {
+	schema = derefSchema(schema)
+
+	if rv.Kind() == reflect.Ptr {
+		if data == nil {
+			rv.Set(reflect.Zero(rv.Type()))
+			return nil
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return decodeValue(schema, data, rv.Elem(), path)
+	}
+
+	if union, ok := schema.(*avro.UnionSchema); ok {
+		return decodeUnion(union, data, rv, path)
+	}
+
+	if data == nil {
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+
+	switch s := schema.(type) {
+	case *avro.RecordSchema:
+		record, ok := data.(map[string]interface{})
+		if !ok || rv.Kind() != reflect.Struct {
+			return fmt.Errorf("%s: cannot map %T to %s", path, data, rv.Type())
+		}
+		indexes := structFieldIndexes(rv.Type())
+		for _, field := range s.Fields() {
+			index, found := indexes[field.Name()]
+			if !found {
+				continue // Fields without a struct counterpart are dropped
+			}
+			if err := decodeValue(field.Type(), record[field.Name()], rv.FieldByIndex(index), path+"."+field.Name()); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case *avro.ArraySchema:
+		items, ok := data.([]interface{})
+		if !ok || rv.Kind() != reflect.Slice {
+			return fmt.Errorf("%s: cannot map %T to %s", path, data, rv.Type())
+		}
+		slice := reflect.MakeSlice(rv.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(s.Items(), item, slice.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+		return nil
+
+	case *avro.MapSchema:
+		values, ok := data.(map[string]interface{})
+		if !ok || rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("%s: cannot map %T to %s", path, data, rv.Type())
+		}
+		result := reflect.MakeMapWithSize(rv.Type(), len(values))
+		for key, value := range values {
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			if err := decodeValue(s.Values(), value, elem, path+"."+key); err != nil {
+				return err
+			}
+			result.SetMapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()), elem)
+		}
+		rv.Set(result)
+		return nil
+	}
+
+	return decodePrimitive(data, rv, path)
+}
+
+// decodeUnion unwraps a union value, accepting both {"branch": value} and bare values
+func decodeUnion(union *avro.UnionSchema, data interface{}, rv reflect.Value, path string) error {
+	if data == nil {
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+
+	if wrapped, ok := data.(map[string]interface{}); ok && len(wrapped) == 1 {
+		for _, branch := range union.Types() {
+			if value, found := wrapped[unionBranchName(branch)]; found {
+				return decodeValue(branch, value, rv, path)
+			}
+		}
+	}
+
+	var lastErr error
+	for _, branch := range union.Types() {
+		if branch.Type() == avro.Null {
+			continue
+		}
+		if lastErr = decodeValue(branch, data, rv, path); lastErr == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s: %T matches no union branch: %w", path, data, lastErr)
+}
+
+// decodePrimitive assigns a decoded scalar to rv, converting between compatible kinds
+func decodePrimitive(data interface{}, rv reflect.Value, path string) error {
+	if rv.Type() == timeType {
+		switch t := data.(type) {
+		case time.Time:
+			rv.Set(reflect.ValueOf(t))
+			return nil
+		case int64:
+			rv.Set(reflect.ValueOf(time.UnixMilli(t)))
+			return nil
+		}
+		return fmt.Errorf("%s: cannot map %T to time.Time", path, data)
+	}
+
+	if rv.Type().ConvertibleTo(ratType) {
+		rat, ok := data.(*big.Rat)
+		if !ok || rat == nil {
+			return fmt.Errorf("%s: cannot map %T to %s", path, data, rv.Type())
+		}
+		rv.Set(reflect.ValueOf(rat).Elem().Convert(rv.Type()))
+		return nil
+	}
+
+	dv := reflect.ValueOf(data)
+	switch {
+	case isIntKind(rv.Kind()) && isIntKind(dv.Kind()):
+		if rv.OverflowInt(dv.Int()) {
+			return fmt.Errorf("%s: %d overflows %s", path, dv.Int(), rv.Type())
+		}
+		rv.SetInt(dv.Int())
+	case isUintKind(rv.Kind()) && isIntKind(dv.Kind()):
+		if dv.Int() < 0 || rv.OverflowUint(uint64(dv.Int())) {
+			return fmt.Errorf("%s: %d overflows %s", path, dv.Int(), rv.Type())
+		}
+		rv.SetUint(uint64(dv.Int()))
+	case isFloatKind(rv.Kind()) && (isFloatKind(dv.Kind()) || isIntKind(dv.Kind())):
+		rv.SetFloat(dv.Convert(reflect.TypeOf(float64(0))).Float())
+	case rv.Kind() == reflect.String && dv.Kind() == reflect.String:
+		rv.SetString(dv.String())
+	case rv.Kind() == reflect.Bool && dv.Kind() == reflect.Bool:
+		rv.SetBool(dv.Bool())
+	case dv.Type().AssignableTo(rv.Type()):
+		rv.Set(dv)
+	case dv.Type().ConvertibleTo(rv.Type()) && dv.Kind() == rv.Kind():
+		rv.Set(dv.Convert(rv.Type()))
+	default:
+		return fmt.Errorf("%s: cannot map %T to %s", path, data, rv.Type())
+	}
+	return nil
+}
+
+// structFieldIndexes returns the Avro name -> field index mapping for a struct type
+func structFieldIndexes(t reflect.Type) map[string][]int {
+	if cached, ok := fieldIndexCache.Load(t); ok {
+		return cached.(map[string][]int)
+	}
+
+	indexes := make(map[string][]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := tagName(field.Tag.Get("avro"))
+		if name == "" {
+			name = tagName(field.Tag.Get("json"))
+		}
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name[:1]) + field.Name[1:]
+		}
+		indexes[name] = field.Index
+	}
+
+	fieldIndexCache.Store(t, indexes)
+	return indexes
+}
+
+// tagName extracts the name portion of a struct tag value
+func tagName(tag string) string {
+	if idx := strings.Index(tag, ","); idx >= 0 {
+		return tag[:idx]
+	}
+	return tag
+}
+
+// unionBranchName returns the key hamba/avro uses for a union branch
+func unionBranchName(schema avro.Schema) string {
+	schema = derefSchema(schema)
+	if named, ok := schema.(avro.NamedSchema); ok {
+		return named.FullName()
+	}
+	name := string(schema.Type())
+	if logical, ok := schema.(avro.LogicalTypeSchema); ok && logical.Logical() != nil {
+		name += "." + string(logical.Logical().Type())
+	}
+	return name
+}
+
+// logicalType returns the logical type annotating schema, or "" for none
+func logicalType(schema avro.Schema) avro.LogicalType {
+	if logical, ok := schema.(avro.LogicalTypeSchema); ok && logical.Logical() != nil {
+		return logical.Logical().Type()
+	}
+	return ""
+}
+
+func isIntKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Int64
+}
+
+func isUintKind(kind reflect.Kind) bool {
+	return kind >= reflect.Uint && kind <= reflect.Uint64
+}
+
+func isFloatKind(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
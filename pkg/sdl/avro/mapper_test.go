@@ -0,0 +1,216 @@
+package avro
+
+import (
+	"math"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hamba/avro/v2"
+)
+
+func TestStructMapperUserMatchesConverters(t *testing.T) {
+	manager, err := NewManager("tmp/test_mapper_user")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer os.RemoveAll("tmp/test_mapper_user")
+	// Exercise the mapper rather than the struct-tag fast path
+	manager.WithNativeStructs(false)
+
+	user := manager.CreateSampleUsers(1)[0]
+	user.CreatedAt = user.CreatedAt.Truncate(time.Millisecond)
+	user.UpdatedAt = user.UpdatedAt.Truncate(time.Millisecond)
+
+	data, err := manager.SerializeStruct(manager.GetUserSchema(), user)
+	if err != nil {
+		t.Fatalf("Failed to serialize user: %v", err)
+	}
+
+	// The hand-written converters must read what the generic mapper wrote
+	viaConverter, err := manager.DeserializeUserBinary(data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize with converter: %v", err)
+	}
+	if viaConverter.Email != user.Email || viaConverter.Profile == nil || *viaConverter.Profile.Phone != *user.Profile.Phone {
+		t.Errorf("Converter read mismatch: %+v", viaConverter)
+	}
+
+	var decoded User
+	if err := manager.DeserializeStruct(manager.GetUserSchema(), data, &decoded); err != nil {
+		t.Fatalf("Failed to deserialize user: %v", err)
+	}
+	if !decoded.CreatedAt.Equal(user.CreatedAt) {
+		t.Errorf("CreatedAt mismatch: expected %v, got %v", user.CreatedAt, decoded.CreatedAt)
+	}
+	decoded.CreatedAt, decoded.UpdatedAt = user.CreatedAt, user.UpdatedAt
+	if !reflect.DeepEqual(decoded, user) {
+		t.Errorf("User mismatch:\nexpected %+v\ngot      %+v", user, decoded)
+	}
+
+	t.Log("✓ Generic mapper round-trips users compatibly with converters")
+}
+
+func TestStructMapperOrder(t *testing.T) {
+	manager, err := NewManager("tmp/test_mapper_order")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer os.RemoveAll("tmp/test_mapper_order")
+	// Exercise the mapper rather than the struct-tag fast path
+	manager.WithNativeStructs(false)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	tracking := "1Z999"
+	price := Price{Currency: "USD", AmountCents: 1999}
+	order := Order{
+		ID:          1,
+		UserID:      42,
+		OrderNumber: "ORD-1",
+		Status:      OrderStatusShipped,
+		Items: []OrderItem{{
+			ProductID: 7, ProductName: "Widget", ProductSKU: "W-7", Quantity: 2,
+			UnitPrice: price, TotalPrice: Price{Currency: "USD", AmountCents: 3998},
+			ProductVariant: map[string]string{"color": "blue"},
+		}},
+		Summary: OrderSummary{Subtotal: price, Tax: price, ShippingCost: price, Discount: price, Total: price, TotalItems: 2},
+		ShippingInfo: &ShippingInfo{
+			Address:        ShippingAddress{RecipientName: "A", Street: "1 St", City: "C", State: "S", PostalCode: "1", Country: "US"},
+			Method:         "ground",
+			TrackingNumber: &tracking,
+			Cost:           price,
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+		ShippedAt: &now,
+	}
+
+	data, err := manager.SerializeStruct(manager.GetOrderSchema(), order)
+	if err != nil {
+		t.Fatalf("Failed to serialize order: %v", err)
+	}
+
+	var decoded Order
+	if err := manager.DeserializeStruct(manager.GetOrderSchema(), data, &decoded); err != nil {
+		t.Fatalf("Failed to deserialize order: %v", err)
+	}
+
+	if decoded.ShippingInfo == nil || *decoded.ShippingInfo.TrackingNumber != tracking {
+		t.Errorf("Shipping info mismatch: %+v", decoded.ShippingInfo)
+	}
+	if decoded.ShippedAt == nil || !decoded.ShippedAt.Equal(now) {
+		t.Errorf("ShippedAt mismatch: %v", decoded.ShippedAt)
+	}
+	if decoded.PaymentInfo != nil || decoded.DeliveredAt != nil {
+		t.Errorf("Expected nil optional fields, got %+v / %v", decoded.PaymentInfo, decoded.DeliveredAt)
+	}
+	if len(decoded.Items) != 1 || decoded.Items[0].ProductVariant["color"] != "blue" {
+		t.Errorf("Items mismatch: %+v", decoded.Items)
+	}
+
+	// Invalid enum values are reported instead of panicking
+	order.Status = "LOST"
+	if _, err := manager.SerializeStruct(manager.GetOrderSchema(), order); err == nil {
+		t.Error("Expected error for unknown enum symbol")
+	}
+
+	t.Log("✓ Generic mapper handles nested unions, records and logical types")
+}
+
+func TestStructMapperUnsigned(t *testing.T) {
+	schema := avro.MustParse(`{"type": "record", "name": "Counters", "fields": [
+		{"name": "hits", "type": "int"},
+		{"name": "bytes", "type": "long"}
+	]}`)
+	type counters struct {
+		Hits  uint16 `avro:"hits"`
+		Bytes uint64 `avro:"bytes"`
+	}
+
+	data, err := StructToAvro(schema, counters{Hits: 65535, Bytes: 1 << 40})
+	if err != nil {
+		t.Fatalf("Failed to map unsigned fields: %v", err)
+	}
+	var decoded counters
+	if err := AvroToStruct(schema, data, &decoded); err != nil {
+		t.Fatalf("Failed to map back unsigned fields: %v", err)
+	}
+	if decoded.Hits != 65535 || decoded.Bytes != 1<<40 {
+		t.Errorf("Unsigned fields mismatch: %+v", decoded)
+	}
+
+	if _, err := StructToAvro(schema, counters{Bytes: math.MaxUint64}); err == nil {
+		t.Error("Expected a uint64 beyond the long range to fail")
+	}
+	var small struct {
+		Hits uint8 `avro:"hits"`
+	}
+	if err := AvroToStruct(schema, map[string]interface{}{"hits": 300}, &small); err == nil {
+		t.Error("Expected an int beyond uint8 to fail")
+	}
+	if err := AvroToStruct(schema, map[string]interface{}{"hits": -1}, &decoded); err == nil {
+		t.Error("Expected a negative int to fail for an unsigned field")
+	}
+
+	t.Log("✓ Generic mapper maps unsigned integers with range checks")
+}
+
+func TestStructMapperIntegerOverflow(t *testing.T) {
+	schema := avro.MustParse(`{"type": "record", "name": "Counter", "fields": [
+		{"name": "hits", "type": "int"}
+	]}`)
+	type counter struct {
+		Hits int64 `avro:"hits"`
+	}
+
+	encodeCases := []struct {
+		name  string
+		value int64
+		ok    bool
+	}{
+		{"max int", math.MaxInt32, true},
+		{"min int", math.MinInt32, true},
+		{"above int", math.MaxInt32 + 1, false},
+		{"below int", math.MinInt32 - 1, false},
+	}
+	for _, tc := range encodeCases {
+		_, err := StructToAvro(schema, counter{Hits: tc.value})
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: encoding %d gave %v", tc.name, tc.value, err)
+		}
+	}
+
+	var int8Target struct {
+		Hits int8 `avro:"hits"`
+	}
+	var int16Target struct {
+		Hits int16 `avro:"hits"`
+	}
+	var int32Target struct {
+		Hits int32 `avro:"hits"`
+	}
+	decodeCases := []struct {
+		name   string
+		value  int64
+		target interface{}
+		ok     bool
+	}{
+		{"int8 max", math.MaxInt8, &int8Target, true},
+		{"int8 above", math.MaxInt8 + 1, &int8Target, false},
+		{"int8 below", math.MinInt8 - 1, &int8Target, false},
+		{"int16 above", math.MaxInt16 + 1, &int16Target, false},
+		{"int16 below", math.MinInt16 - 1, &int16Target, false},
+		{"int32 min", math.MinInt32, &int32Target, true},
+		{"int32 above", math.MaxInt32 + 1, &int32Target, false},
+		{"int32 below", math.MinInt32 - 1, &int32Target, false},
+	}
+	for _, tc := range decodeCases {
+		err := AvroToStruct(schema, map[string]interface{}{"hits": tc.value}, tc.target)
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: decoding %d gave %v", tc.name, tc.value, err)
+		}
+	}
+
+	t.Log("✓ Generic mapper rejects signed integers out of range")
+}
@@ -14,16 +14,36 @@ const (
 	UserStatusDeleted   UserStatus = "DELETED"
 )
 
+// EnumValues returns every user status
+func (UserStatus) EnumValues() []string {
+	return []string{
+		string(UserStatusActive),
+		string(UserStatusInactive),
+		string(UserStatusSuspended),
+		string(UserStatusDeleted),
+	}
+}
+
 // ProductStatus represents the product status enum
 type ProductStatus string
 
 const (
-	ProductStatusActive        ProductStatus = "ACTIVE"
-	ProductStatusInactive      ProductStatus = "INACTIVE"
-	ProductStatusOutOfStock    ProductStatus = "OUT_OF_STOCK"
-	ProductStatusDiscontinued  ProductStatus = "DISCONTINUED"
+	ProductStatusActive       ProductStatus = "ACTIVE"
+	ProductStatusInactive     ProductStatus = "INACTIVE"
+	ProductStatusOutOfStock   ProductStatus = "OUT_OF_STOCK"
+	ProductStatusDiscontinued ProductStatus = "DISCONTINUED"
 )
 
+// EnumValues returns every product status
+func (ProductStatus) EnumValues() []string {
+	return []string{
+		string(ProductStatusActive),
+		string(ProductStatusInactive),
+		string(ProductStatusOutOfStock),
+		string(ProductStatusDiscontinued),
+	}
+}
+
 // OrderStatus represents the order status enum
 type OrderStatus string
 
@@ -37,6 +57,19 @@ const (
 	OrderStatusRefunded   OrderStatus = "REFUNDED"
 )
 
+// EnumValues returns every order status
+func (OrderStatus) EnumValues() []string {
+	return []string{
+		string(OrderStatusPending),
+		string(OrderStatusConfirmed),
+		string(OrderStatusProcessing),
+		string(OrderStatusShipped),
+		string(OrderStatusDelivered),
+		string(OrderStatusCancelled),
+		string(OrderStatusRefunded),
+	}
+}
+
 // PaymentStatus represents the payment status enum
 type PaymentStatus string
 
@@ -48,162 +81,193 @@ const (
 	PaymentStatusRefunded   PaymentStatus = "REFUNDED"
 )
 
+// EnumValues returns every payment status
+func (PaymentStatus) EnumValues() []string {
+	return []string{
+		string(PaymentStatusPending),
+		string(PaymentStatusAuthorized),
+		string(PaymentStatusCaptured),
+		string(PaymentStatusFailed),
+		string(PaymentStatusRefunded),
+	}
+}
+
 // User represents a user entity
 type User struct {
-	ID        int64      `json:"id"`
-	Email     string     `json:"email"`
-	Name      string     `json:"name"`
-	Status    UserStatus `json:"status"`
-	Profile   *Profile   `json:"profile"`
-	CreatedAt time.Time  `json:"createdAt"`
-	UpdatedAt time.Time  `json:"updatedAt"`
+	ID        int64      `json:"id" yaml:"id" avro:"id" jsonschema:"minimum=1"`
+	Email     string     `json:"email" yaml:"email" avro:"email" jsonschema:"format=email" pii:"email"`
+	Name      string     `json:"name" yaml:"name" avro:"name" jsonschema:"minLength=1" pii:"name"`
+	Status    UserStatus `json:"status" yaml:"status" avro:"status"`
+	Profile   *Profile   `json:"profile" yaml:"profile" avro:"profile"`
+	CreatedAt time.Time  `json:"createdAt" yaml:"createdAt" avro:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt" yaml:"updatedAt" avro:"updatedAt"`
 }
 
 // Profile contains user profile information
 type Profile struct {
-	FirstName string            `json:"firstName"`
-	LastName  string            `json:"lastName"`
-	Phone     *string           `json:"phone"`
-	Address   *Address          `json:"address"`
-	Interests []string          `json:"interests"`
-	Metadata  map[string]string `json:"metadata"`
+	FirstName string            `json:"firstName" yaml:"firstName" avro:"firstName" pii:"name"`
+	LastName  string            `json:"lastName" yaml:"lastName" avro:"lastName" pii:"name"`
+	Phone     *string           `json:"phone" yaml:"phone" avro:"phone" pii:"phone"`
+	Address   *Address          `json:"address" yaml:"address" avro:"address"`
+	Interests []string          `json:"interests" yaml:"interests" avro:"interests"`
+	Metadata  map[string]string `json:"metadata" yaml:"metadata" avro:"metadata"`
 }
 
 // Address represents a physical address
 type Address struct {
-	Street     string `json:"street"`
-	City       string `json:"city"`
-	State      string `json:"state"`
-	PostalCode string `json:"postalCode"`
-	Country    string `json:"country"`
+	Street     string `json:"street" yaml:"street" avro:"street" pii:"address"`
+	City       string `json:"city" yaml:"city" avro:"city"`
+	State      string `json:"state" yaml:"state" avro:"state"`
+	PostalCode string `json:"postalCode" yaml:"postalCode" avro:"postalCode" pii:"postal_code"`
+	Country    string `json:"country" yaml:"country" avro:"country"`
 }
 
 // Product represents a product entity
 type Product struct {
-	ID            int64                 `json:"id"`
-	Name          string                `json:"name"`
-	Description   string                `json:"description"`
-	SKU           string                `json:"sku"`
-	Price         Price                 `json:"price"`
-	Inventory     Inventory             `json:"inventory"`
-	Categories    []string              `json:"categories"`
-	Tags          []string              `json:"tags"`
-	Status        ProductStatus         `json:"status"`
-	Specifications map[string]string    `json:"specifications"`
-	CreatedAt     time.Time             `json:"createdAt"`
-	UpdatedAt     time.Time             `json:"updatedAt"`
+	ID             int64             `json:"id" yaml:"id" avro:"id"`
+	Name           string            `json:"name" yaml:"name" avro:"name"`
+	Description    string            `json:"description" yaml:"description" avro:"description"`
+	SKU            string            `json:"sku" yaml:"sku" avro:"sku"`
+	Price          Price             `json:"price" yaml:"price" avro:"price"`
+	Inventory      Inventory         `json:"inventory" yaml:"inventory" avro:"inventory"`
+	Categories     []string          `json:"categories" yaml:"categories" avro:"categories"`
+	Tags           []string          `json:"tags" yaml:"tags" avro:"tags"`
+	Status         ProductStatus     `json:"status" yaml:"status" avro:"status"`
+	Specifications map[string]string `json:"specifications" yaml:"specifications" avro:"specifications"`
+	// ReleaseDate is an Avro date; only the day is kept
+	ReleaseDate *time.Time `json:"releaseDate,omitempty" yaml:"releaseDate,omitempty" avro:"releaseDate"`
+	CreatedAt   time.Time  `json:"createdAt" yaml:"createdAt" avro:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt" yaml:"updatedAt" avro:"updatedAt"`
 }
 
 // Price contains pricing information
 type Price struct {
-	Currency           string   `json:"currency"`
-	AmountCents        int64    `json:"amountCents"`
-	DiscountPercentage *float32 `json:"discountPercentage"`
+	Currency    string `json:"currency" yaml:"currency" avro:"currency"`
+	AmountCents int64  `json:"amountCents" yaml:"amountCents" avro:"amountCents"`
+	// Amount is the exact amount as an Avro decimal(18,2), for amounts that
+	// should not be rounded to cents by the reader
+	Amount             *Decimal `json:"amount,omitempty" yaml:"amount,omitempty" avro:"amount"`
+	DiscountPercentage *float32 `json:"discountPercentage" yaml:"discountPercentage" avro:"discountPercentage"`
+}
+
+// Decimal returns the exact amount, or AmountCents as a decimal when Amount is unset
+func (p Price) Decimal() *Decimal {
+	if p.Amount != nil {
+		return p.Amount
+	}
+	return DecimalFromCents(p.AmountCents)
 }
 
 // Inventory tracks product availability
 type Inventory struct {
-	Quantity       int32 `json:"quantity"`
-	Reserved       int32 `json:"reserved"`
-	Available      int32 `json:"available"`
-	TrackInventory bool  `json:"trackInventory"`
-	ReorderLevel   int32 `json:"reorderLevel"`
-	MaxStock       int32 `json:"maxStock"`
+	Quantity       int32 `json:"quantity" yaml:"quantity" avro:"quantity"`
+	Reserved       int32 `json:"reserved" yaml:"reserved" avro:"reserved"`
+	Available      int32 `json:"available" yaml:"available" avro:"available"`
+	TrackInventory bool  `json:"trackInventory" yaml:"trackInventory" avro:"trackInventory"`
+	ReorderLevel   int32 `json:"reorderLevel" yaml:"reorderLevel" avro:"reorderLevel"`
+	MaxStock       int32 `json:"maxStock" yaml:"maxStock" avro:"maxStock"`
 }
 
 // Order represents an order entity
 type Order struct {
-	ID           int64         `json:"id"`
-	UserID       int64         `json:"userId"`
-	OrderNumber  string        `json:"orderNumber"`
-	Status       OrderStatus   `json:"status"`
-	Items        []OrderItem   `json:"items"`
-	Summary      OrderSummary  `json:"summary"`
-	ShippingInfo *ShippingInfo `json:"shippingInfo"`
-	PaymentInfo  *PaymentInfo  `json:"paymentInfo"`
-	CreatedAt    time.Time     `json:"createdAt"`
-	UpdatedAt    time.Time     `json:"updatedAt"`
-	ShippedAt    *time.Time    `json:"shippedAt"`
-	DeliveredAt  *time.Time    `json:"deliveredAt"`
+	ID int64 `json:"id" yaml:"id" avro:"id"`
+	// UUID is a globally unique order ID, an Avro uuid
+	UUID         *string       `json:"uuid,omitempty" yaml:"uuid,omitempty" avro:"uuid" jsonschema:"format=uuid"`
+	UserID       int64         `json:"userId" yaml:"userId" avro:"userId"`
+	OrderNumber  string        `json:"orderNumber" yaml:"orderNumber" avro:"orderNumber"`
+	Status       OrderStatus   `json:"status" yaml:"status" avro:"status"`
+	Items        []OrderItem   `json:"items" yaml:"items" avro:"items"`
+	Summary      OrderSummary  `json:"summary" yaml:"summary" avro:"summary"`
+	ShippingInfo *ShippingInfo `json:"shippingInfo" yaml:"shippingInfo" avro:"shippingInfo"`
+	PaymentInfo  *PaymentInfo  `json:"paymentInfo" yaml:"paymentInfo" avro:"paymentInfo"`
+	CreatedAt    time.Time     `json:"createdAt" yaml:"createdAt" avro:"createdAt"`
+	UpdatedAt    time.Time     `json:"updatedAt" yaml:"updatedAt" avro:"updatedAt"`
+	ShippedAt    *time.Time    `json:"shippedAt" yaml:"shippedAt" avro:"shippedAt"`
+	DeliveredAt  *time.Time    `json:"deliveredAt" yaml:"deliveredAt" avro:"deliveredAt"`
 }
 
 // OrderItem represents an item in an order
 type OrderItem struct {
-	ProductID      int64             `json:"productId"`
-	ProductName    string            `json:"productName"`
-	ProductSKU     string            `json:"productSku"`
-	Quantity       int32             `json:"quantity"`
-	UnitPrice      Price             `json:"unitPrice"`
-	TotalPrice     Price             `json:"totalPrice"`
-	ProductVariant map[string]string `json:"productVariant"`
+	ProductID      int64             `json:"productId" yaml:"productId" avro:"productId"`
+	ProductName    string            `json:"productName" yaml:"productName" avro:"productName"`
+	ProductSKU     string            `json:"productSku" yaml:"productSku" avro:"productSku"`
+	Quantity       int32             `json:"quantity" yaml:"quantity" avro:"quantity"`
+	UnitPrice      Price             `json:"unitPrice" yaml:"unitPrice" avro:"unitPrice"`
+	TotalPrice     Price             `json:"totalPrice" yaml:"totalPrice" avro:"totalPrice"`
+	ProductVariant map[string]string `json:"productVariant" yaml:"productVariant" avro:"productVariant"`
 }
 
 // OrderSummary contains order totals
 type OrderSummary struct {
-	Subtotal     Price `json:"subtotal"`
-	Tax          Price `json:"tax"`
-	ShippingCost Price `json:"shippingCost"`
-	Discount     Price `json:"discount"`
-	Total        Price `json:"total"`
-	TotalItems   int32 `json:"totalItems"`
+	Subtotal     Price `json:"subtotal" yaml:"subtotal" avro:"subtotal"`
+	Tax          Price `json:"tax" yaml:"tax" avro:"tax"`
+	ShippingCost Price `json:"shippingCost" yaml:"shippingCost" avro:"shippingCost"`
+	Discount     Price `json:"discount" yaml:"discount" avro:"discount"`
+	Total        Price `json:"total" yaml:"total" avro:"total"`
+	TotalItems   int32 `json:"totalItems" yaml:"totalItems" avro:"totalItems"`
 }
 
 // ShippingInfo contains shipping details
 type ShippingInfo struct {
-	Address           ShippingAddress `json:"address"`
-	Method            string          `json:"method"`
-	TrackingNumber    *string         `json:"trackingNumber"`
-	Carrier           *string         `json:"carrier"`
-	Cost              Price           `json:"cost"`
-	EstimatedDelivery *time.Time      `json:"estimatedDelivery"`
+	Address           ShippingAddress `json:"address" yaml:"address" avro:"address"`
+	Method            string          `json:"method" yaml:"method" avro:"method"`
+	TrackingNumber    *string         `json:"trackingNumber" yaml:"trackingNumber" avro:"trackingNumber"`
+	Carrier           *string         `json:"carrier" yaml:"carrier" avro:"carrier"`
+	Cost              Price           `json:"cost" yaml:"cost" avro:"cost"`
+	EstimatedDelivery *time.Time      `json:"estimatedDelivery" yaml:"estimatedDelivery" avro:"estimatedDelivery"`
+	// DeliveryTime is the preferred time of day for delivery, an Avro time-millis
+	DeliveryTime *time.Duration `json:"deliveryTime,omitempty" yaml:"deliveryTime,omitempty" avro:"deliveryTime"`
 }
 
 // ShippingAddress represents a shipping address
 type ShippingAddress struct {
-	RecipientName string `json:"recipientName"`
-	Street        string `json:"street"`
-	City          string `json:"city"`
-	State         string `json:"state"`
-	PostalCode    string `json:"postalCode"`
-	Country       string `json:"country"`
+	RecipientName string `json:"recipientName" yaml:"recipientName" avro:"recipientName" pii:"name"`
+	Street        string `json:"street" yaml:"street" avro:"street" pii:"address"`
+	City          string `json:"city" yaml:"city" avro:"city"`
+	State         string `json:"state" yaml:"state" avro:"state"`
+	PostalCode    string `json:"postalCode" yaml:"postalCode" avro:"postalCode" pii:"postal_code"`
+	Country       string `json:"country" yaml:"country" avro:"country"`
 }
 
 // PaymentInfo contains payment details
 type PaymentInfo struct {
-	Method        string         `json:"method"`
-	Status        PaymentStatus  `json:"status"`
-	TransactionID *string        `json:"transactionId"`
-	Amount        Price          `json:"amount"`
-	ProcessedAt   *time.Time     `json:"processedAt"`
+	Method        string        `json:"method" yaml:"method" avro:"method"`
+	Status        PaymentStatus `json:"status" yaml:"status" avro:"status"`
+	TransactionID *string       `json:"transactionId" yaml:"transactionId" avro:"transactionId" pii:"transaction"`
+	Amount        Price         `json:"amount" yaml:"amount" avro:"amount"`
+	ProcessedAt   *time.Time    `json:"processedAt" yaml:"processedAt" avro:"processedAt"`
+	// AuthorizedAt is when the processor authorized the payment, an Avro
+	// timestamp-micros to keep the processor's precision
+	AuthorizedAt *time.Time `json:"authorizedAt,omitempty" yaml:"authorizedAt,omitempty" avro:"authorizedAt"`
 }
 
 // Analytics represents analytics data
 type Analytics struct {
-	ID        int64             `json:"id"`
-	EventType string            `json:"eventType"`
-	UserID    *int64            `json:"userId"`
-	SessionID string            `json:"sessionId"`
-	Timestamp time.Time         `json:"timestamp"`
-	Properties map[string]string `json:"properties"`
-	Metrics   map[string]float64 `json:"metrics"`
-	DeviceInfo *DeviceInfo       `json:"deviceInfo"`
-	Location  *Location         `json:"location"`
+	ID         int64              `json:"id" yaml:"id" avro:"id"`
+	EventType  string             `json:"eventType" yaml:"eventType" avro:"eventType"`
+	UserID     *int64             `json:"userId" yaml:"userId" avro:"userId"`
+	SessionID  string             `json:"sessionId" yaml:"sessionId" avro:"sessionId"`
+	Timestamp  time.Time          `json:"timestamp" yaml:"timestamp" avro:"timestamp"`
+	Properties map[string]string  `json:"properties" yaml:"properties" avro:"properties"`
+	Metrics    map[string]float64 `json:"metrics" yaml:"metrics" avro:"metrics"`
+	DeviceInfo *DeviceInfo        `json:"deviceInfo" yaml:"deviceInfo" avro:"deviceInfo"`
+	Location   *Location          `json:"location" yaml:"location" avro:"location"`
 }
 
 // DeviceInfo contains device information
 type DeviceInfo struct {
-	UserAgent string `json:"userAgent"`
-	Platform  string `json:"platform"`
-	Browser   string `json:"browser"`
-	Version   string `json:"version"`
-	Mobile    bool   `json:"mobile"`
+	UserAgent string `json:"userAgent" yaml:"userAgent" avro:"userAgent"`
+	Platform  string `json:"platform" yaml:"platform" avro:"platform"`
+	Browser   string `json:"browser" yaml:"browser" avro:"browser"`
+	Version   string `json:"version" yaml:"version" avro:"version"`
+	Mobile    bool   `json:"mobile" yaml:"mobile" avro:"mobile"`
 }
 
 // Location contains geographical information
 type Location struct {
-	Country   string   `json:"country"`
-	Region    *string  `json:"region"`
-	City      *string  `json:"city"`
-	Latitude  *float64 `json:"latitude"`
-	Longitude *float64 `json:"longitude"`
-}
\ No newline at end of file
+	Country   string   `json:"country" yaml:"country" avro:"country"`
+	Region    *string  `json:"region" yaml:"region" avro:"region"`
+	City      *string  `json:"city" yaml:"city" avro:"city"`
+	Latitude  *float64 `json:"latitude" yaml:"latitude" avro:"latitude" pii:"location"`
+	Longitude *float64 `json:"longitude" yaml:"longitude" avro:"longitude" pii:"location"`
+}
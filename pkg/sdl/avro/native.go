@@ -0,0 +1,89 @@
+package avro
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/hamba/avro/v2"
+)
+
+// avroTaggedCache caches whether a struct type has an avro tag on every exported field
+var avroTaggedCache sync.Map
+
+// unionDecimalCache caches hasUnionDecimal per schema
+var unionDecimalCache sync.Map
+
+// maxDecodeItems caps the element count of a decoded array, so a corrupt
+// length prefix fails the decode instead of allocating gigabytes
+const maxDecodeItems = 1 << 20
+
+// decodeAPI is the hamba/avro configuration every decode in this package uses
+var decodeAPI = avro.Config{MaxSliceAllocSize: maxDecodeItems}.Freeze()
+
+// WithNativeStructs enables or disables the struct-tag fast path. It is enabled by
+// default; when disabled every value goes through the map converters.
+func (m *Manager) WithNativeStructs(enabled bool) *Manager {
+	m.mapOnly = !enabled
+	return m
+}
+
+// marshalNative encodes v straight from its avro struct tags. It reports false when
+// the fast path is disabled or cannot encode v, so the caller falls back to the map path.
+func (m *Manager) marshalNative(schema avro.Schema, v interface{}) ([]byte, bool) {
+	if m.mapOnly {
+		return nil, false
+	}
+
+	data, err := m.codec(schema).encode(v)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// unmarshalNative decodes data straight into the tagged struct v. It reports false
+// when the fast path is disabled or cannot decode data, so the caller falls back to the map path.
+func (m *Manager) unmarshalNative(schema avro.Schema, data []byte, v interface{}) bool {
+	if m.mapOnly || nativeUnsafe(schema) {
+		return false
+	}
+	return m.codec(schema).decode(data, v) == nil
+}
+
+// nativeUnsafe reports whether hamba/avro would silently mis-decode schema into structs
+func nativeUnsafe(schema avro.Schema) bool {
+	if unsafe, ok := unionDecimalCache.Load(schema); ok {
+		return unsafe.(bool)
+	}
+	unsafe := hasUnionDecimal(schema)
+	unionDecimalCache.Store(schema, unsafe)
+	return unsafe
+}
+
+// avroTagged reports whether v is a struct, or pointer to one, whose exported fields
+// all carry avro tags. hamba/avro silently skips fields it cannot match by tag, so
+// only fully tagged structs may take the fast path.
+func avroTagged(v interface{}) bool {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return false
+	}
+
+	if tagged, ok := avroTaggedCache.Load(t); ok {
+		return tagged.(bool)
+	}
+
+	tagged := true
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.IsExported() && field.Tag.Get("avro") == "" {
+			tagged = false
+			break
+		}
+	}
+	avroTaggedCache.Store(t, tagged)
+	return tagged
+}
@@ -0,0 +1,139 @@
+package avro
+
+import (
+	"testing"
+)
+
+// benchmarkPath names a manager configured for one serialization path
+type benchmarkPath struct {
+	name    string
+	manager *Manager
+}
+
+// benchmarkManagers returns a manager using the struct-tag fast path and one using the map path
+func benchmarkManagers(b *testing.B) (native, mapped *Manager) {
+	b.Helper()
+
+	native, err := NewManager("")
+	if err != nil {
+		b.Fatal(err)
+	}
+	mapped, err = NewManager("")
+	if err != nil {
+		b.Fatal(err)
+	}
+	return native, mapped.WithNativeStructs(false)
+}
+
+func BenchmarkUserSerialization(b *testing.B) {
+	native, mapped := benchmarkManagers(b)
+	user := native.CreateSampleUsers(1)[0]
+
+	for _, path := range []benchmarkPath{{"native", native}, {"map", mapped}} {
+		manager := path.manager
+		b.Run(path.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := manager.SerializeUserBinary(user); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkUserDeserialization(b *testing.B) {
+	native, mapped := benchmarkManagers(b)
+	data, err := native.SerializeUserBinary(native.CreateSampleUsers(1)[0])
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, path := range []benchmarkPath{{"native", native}, {"map", mapped}} {
+		manager := path.manager
+		b.Run(path.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := manager.DeserializeUserBinary(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkProductSerialization(b *testing.B) {
+	native, mapped := benchmarkManagers(b)
+	product := native.CreateSampleProducts(1)[0]
+
+	for _, path := range []benchmarkPath{{"native", native}, {"map", mapped}} {
+		manager := path.manager
+		b.Run(path.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := manager.SerializeProductBinary(product); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkProductDeserialization(b *testing.B) {
+	native, mapped := benchmarkManagers(b)
+	data, err := native.SerializeProductBinary(native.CreateSampleProducts(1)[0])
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, path := range []benchmarkPath{{"native", native}, {"map", mapped}} {
+		manager := path.manager
+		b.Run(path.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := manager.DeserializeProductBinary(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// The "map" variant of the order benchmarks measures the reflective struct mapper
+func BenchmarkOrderSerializeStruct(b *testing.B) {
+	native, mapped := benchmarkManagers(b)
+	order := benchmarkOrder()
+
+	for _, path := range []benchmarkPath{{"native", native}, {"map", mapped}} {
+		manager := path.manager
+		b.Run(path.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := manager.SerializeStruct(manager.GetOrderSchema(), order); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkOrderDeserializeStruct(b *testing.B) {
+	native, mapped := benchmarkManagers(b)
+	data, err := native.SerializeStruct(native.GetOrderSchema(), benchmarkOrder())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, path := range []benchmarkPath{{"native", native}, {"map", mapped}} {
+		manager := path.manager
+		b.Run(path.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var order Order
+				if err := manager.DeserializeStruct(manager.GetOrderSchema(), data, &order); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package avro
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hamba/avro/v2"
+)
+
+// benchmarkOrder returns an order exercising nested records, unions and maps
+func benchmarkOrder() Order {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	tracking := "1Z999"
+	price := Price{Currency: "USD", AmountCents: 1999}
+	return Order{
+		ID:          1,
+		UserID:      42,
+		OrderNumber: "ORD-1",
+		Status:      OrderStatusShipped,
+		Items: []OrderItem{{
+			ProductID: 7, ProductName: "Widget", ProductSKU: "W-7", Quantity: 2,
+			UnitPrice: price, TotalPrice: Price{Currency: "USD", AmountCents: 3998},
+			ProductVariant: map[string]string{"color": "blue"},
+		}},
+		Summary: OrderSummary{Subtotal: price, Tax: price, ShippingCost: price, Discount: price, Total: price, TotalItems: 2},
+		ShippingInfo: &ShippingInfo{
+			Address:        ShippingAddress{RecipientName: "A", Street: "1 St", City: "C", State: "S", PostalCode: "1", Country: "US"},
+			Method:         "ground",
+			TrackingNumber: &tracking,
+			Cost:           price,
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+		ShippedAt: &now,
+	}
+}
+
+func TestNativeStructsMatchMapPath(t *testing.T) {
+	native, err := NewManager("tmp/test_native")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer os.RemoveAll("tmp/test_native")
+	mapped, _ := NewManager("tmp/test_native")
+	mapped.WithNativeStructs(false)
+
+	for _, user := range native.CreateSampleUsers(3) {
+		user.CreatedAt = user.CreatedAt.Truncate(time.Millisecond)
+		user.UpdatedAt = user.UpdatedAt.Truncate(time.Millisecond)
+
+		// Both paths must read what the other wrote
+		fast, err := native.SerializeUserBinary(user)
+		if err != nil {
+			t.Fatalf("Failed to serialize user natively: %v", err)
+		}
+		slow, err := mapped.SerializeUserBinary(user)
+		if err != nil {
+			t.Fatalf("Failed to serialize user via map: %v", err)
+		}
+
+		fromSlow, err := native.DeserializeUserBinary(slow)
+		if err != nil {
+			t.Fatalf("Failed to deserialize user natively: %v", err)
+		}
+		fromFast, err := mapped.DeserializeUserBinary(fast)
+		if err != nil {
+			t.Fatalf("Failed to deserialize user via map: %v", err)
+		}
+		if !reflect.DeepEqual(fromSlow, fromFast) || !fromFast.CreatedAt.Equal(user.CreatedAt) {
+			t.Errorf("User paths disagree:\nnative: %+v\nmap:    %+v", fromSlow, fromFast)
+		}
+	}
+
+	for _, product := range native.CreateSampleProducts(3) {
+		product.CreatedAt = product.CreatedAt.Truncate(time.Millisecond)
+		product.UpdatedAt = product.UpdatedAt.Truncate(time.Millisecond)
+
+		fast, err := native.SerializeProductBinary(product)
+		if err != nil {
+			t.Fatalf("Failed to serialize product natively: %v", err)
+		}
+		fromFast, err := mapped.DeserializeProductBinary(fast)
+		if err != nil {
+			t.Fatalf("Failed to deserialize product via map: %v", err)
+		}
+		fromNative, err := native.DeserializeProductBinary(fast)
+		if err != nil {
+			t.Fatalf("Failed to deserialize product natively: %v", err)
+		}
+		if !reflect.DeepEqual(fromNative, fromFast) {
+			t.Errorf("Product paths disagree:\nnative: %+v\nmap:    %+v", fromNative, fromFast)
+		}
+	}
+
+	t.Log("✓ Struct-tag fast path and map converters are wire compatible")
+}
+
+func TestNativeStructsCoverOrders(t *testing.T) {
+	manager, err := NewManager("tmp/test_native_order")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer os.RemoveAll("tmp/test_native_order")
+
+	// The fast path must handle orders itself rather than silently falling back
+	order := benchmarkOrder()
+	direct, err := avro.Marshal(manager.GetOrderSchema(), order)
+	if err != nil {
+		t.Fatalf("Order is not encodable from its struct tags: %v", err)
+	}
+
+	var decoded Order
+	if err := manager.DeserializeStruct(manager.GetOrderSchema(), direct, &decoded); err != nil {
+		t.Fatalf("Failed to deserialize order: %v", err)
+	}
+	if decoded.ShippingInfo == nil || *decoded.ShippingInfo.TrackingNumber != "1Z999" || !decoded.ShippedAt.Equal(*order.ShippedAt) {
+		t.Errorf("Order mismatch: %+v", decoded)
+	}
+
+	if !avroTagged(order) || !avroTagged(&decoded) {
+		t.Error("Expected model structs to be fully avro tagged")
+	}
+	if avroTagged(struct{ Name string }{}) {
+		t.Error("Expected untagged struct to use the map path")
+	}
+
+	t.Log("✓ Orders take the struct-tag fast path")
+}
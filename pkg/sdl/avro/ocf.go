@@ -0,0 +1,451 @@
+package avro
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/ocf"
+
+	"go-transport-prac/internal/tracing"
+)
+
+// WriteUsersOCF writes users as an Avro Object Container File, which embeds
+// the writer schema so any Avro implementation can read it without this
+// package's schemas. opts configure the codec, block size or sync marker
+func (m *Manager) WriteUsersOCF(w io.Writer, users []User, opts ...ocf.EncoderFunc) error {
+	encoder, err := ocf.NewEncoderWithSchema(m.userSchema, w, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create ocf encoder: %w", err)
+	}
+
+	for _, user := range users {
+		if err := encoder.Encode(m.userToAvroMap(user)); err != nil {
+			return fmt.Errorf("failed to encode user %d: %w", user.ID, err)
+		}
+	}
+
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to flush ocf encoder: %w", err)
+	}
+	return nil
+}
+
+// ReadUsersOCF reads users from an Avro Object Container File using the
+// schema embedded in its header
+func (m *Manager) ReadUsersOCF(r io.Reader) ([]User, error) {
+	reader, err := m.NewUserOCFReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return reader.All()
+}
+
+// WriteUsersToOCFFile writes users to an Avro Object Container File
+func (m *Manager) WriteUsersToOCFFile(filename string, users []User, opts ...ocf.EncoderFunc) error {
+	return m.WriteUsersToOCFFileContext(context.Background(), filename, users, opts...)
+}
+
+// WriteUsersToOCFFileContext writes users to an Avro Object Container File,
+// stopping with ctx.Err() once ctx is done
+func (m *Manager) WriteUsersToOCFFileContext(ctx context.Context, filename string, users []User, opts ...ocf.EncoderFunc) (err error) {
+	ctx, span := startSpan(ctx, "avro.ocf.write", "user", filename)
+	defer func() { tracing.End(span, len(users), err) }()
+
+	return m.writeFile(ctx, filename, m.catalogEntry(formatOCF, "user", m.userSchema, len(users)), func(w io.Writer) error {
+		return m.WriteUsersOCF(w, users, opts...)
+	})
+}
+
+// ReadUsersFromOCFFile reads users from an Avro Object Container File
+func (m *Manager) ReadUsersFromOCFFile(filename string) ([]User, error) {
+	return m.ReadUsersFromOCFFileContext(context.Background(), filename)
+}
+
+// ReadUsersFromOCFFileContext reads users from an Avro Object Container
+// File, stopping with ctx.Err() once ctx is done
+func (m *Manager) ReadUsersFromOCFFileContext(ctx context.Context, filename string) (result []User, err error) {
+	ctx, span := startSpan(ctx, "avro.ocf.read", "user", filename)
+	defer func() { tracing.End(span, len(result), err) }()
+
+	file, err := m.openFile(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	users, err := m.ReadUsersOCF(file)
+	return finishRead(ctx, users, err)
+}
+
+// OCFSchemaName returns the full name of the record schema embedded in an
+// Object Container File header, e.g. "com.example.avro.User"
+func OCFSchemaName(r io.Reader) (string, error) {
+	decoder, err := ocf.NewDecoder(r, ocf.WithDecoderConfig(decodeAPI))
+	if err != nil {
+		return "", fmt.Errorf("failed to create ocf decoder: %w", err)
+	}
+	schema, err := avro.Parse(string(decoder.Metadata()["avro.schema"]))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse ocf schema: %w", err)
+	}
+	named, ok := schema.(avro.NamedSchema)
+	if !ok {
+		return "", fmt.Errorf("ocf schema is a %s, not a record", schema.Type())
+	}
+	return named.FullName(), nil
+}
+
+// OCFInfo describes an Object Container File
+type OCFInfo struct {
+	Schema  string `json:"schema"`
+	Codec   string `json:"codec"`
+	Records int    `json:"records"`
+}
+
+// InspectOCF reads an Object Container File header and counts its records
+// without mapping them to a model
+func InspectOCF(r io.Reader) (OCFInfo, error) {
+	decoder, err := ocf.NewDecoder(r, ocf.WithDecoderConfig(decodeAPI))
+	if err != nil {
+		return OCFInfo{}, fmt.Errorf("failed to create ocf decoder: %w", err)
+	}
+
+	metadata := decoder.Metadata()
+	info := OCFInfo{Schema: string(metadata["avro.schema"]), Codec: string(metadata["avro.codec"])}
+	if info.Codec == "" {
+		info.Codec = string(ocf.Null)
+	}
+	for decoder.HasNext() {
+		var record interface{}
+		if err := decoder.Decode(&record); err != nil {
+			return info, fmt.Errorf("failed to decode record %d: %w", info.Records, err)
+		}
+		info.Records++
+	}
+	if err := decoder.Error(); err != nil {
+		return info, fmt.Errorf("failed to read ocf: %w", err)
+	}
+	return info, nil
+}
+
+// WriteProductsOCF writes products as an Avro Object Container File
+func (m *Manager) WriteProductsOCF(w io.Writer, products []Product, opts ...ocf.EncoderFunc) error {
+	encoder, err := ocf.NewEncoderWithSchema(m.productSchema, w, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create ocf encoder: %w", err)
+	}
+
+	for _, product := range products {
+		if err := encoder.Encode(m.productToAvroMap(product)); err != nil {
+			return fmt.Errorf("failed to encode product %d: %w", product.ID, err)
+		}
+	}
+
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to flush ocf encoder: %w", err)
+	}
+	return nil
+}
+
+// ReadProductsOCF reads products from an Avro Object Container File using the
+// schema embedded in its header
+func (m *Manager) ReadProductsOCF(r io.Reader) ([]Product, error) {
+	reader, err := m.NewProductOCFReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return reader.All()
+}
+
+// WriteOrdersOCF writes orders as an Avro Object Container File. Orders are
+// always encoded from their avro struct tags
+func (m *Manager) WriteOrdersOCF(w io.Writer, orders []Order, opts ...ocf.EncoderFunc) error {
+	encoder, err := ocf.NewEncoderWithSchema(m.orderSchema, w, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create ocf encoder: %w", err)
+	}
+
+	for _, order := range orders {
+		if err := encoder.Encode(order); err != nil {
+			return fmt.Errorf("failed to encode order %d: %w", order.ID, err)
+		}
+	}
+
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to flush ocf encoder: %w", err)
+	}
+	return nil
+}
+
+// ReadOrdersOCF reads orders from an Avro Object Container File
+func (m *Manager) ReadOrdersOCF(r io.Reader) ([]Order, error) {
+	reader, err := m.NewOrderOCFReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return reader.All()
+}
+
+// WriteProductsToOCFFile writes products to an Avro Object Container File
+func (m *Manager) WriteProductsToOCFFile(filename string, products []Product, opts ...ocf.EncoderFunc) error {
+	return m.WriteProductsToOCFFileContext(context.Background(), filename, products, opts...)
+}
+
+// WriteProductsToOCFFileContext writes products to an Avro Object Container File,
+// stopping with ctx.Err() once ctx is done
+func (m *Manager) WriteProductsToOCFFileContext(ctx context.Context, filename string, products []Product, opts ...ocf.EncoderFunc) (err error) {
+	ctx, span := startSpan(ctx, "avro.ocf.write", "product", filename)
+	defer func() { tracing.End(span, len(products), err) }()
+
+	return m.writeFile(ctx, filename, m.catalogEntry(formatOCF, "product", m.productSchema, len(products)), func(w io.Writer) error {
+		return m.WriteProductsOCF(w, products, opts...)
+	})
+}
+
+// ReadProductsFromOCFFile reads products from an Avro Object Container File
+func (m *Manager) ReadProductsFromOCFFile(filename string) ([]Product, error) {
+	return m.ReadProductsFromOCFFileContext(context.Background(), filename)
+}
+
+// ReadProductsFromOCFFileContext reads products from an Avro Object Container
+// File, stopping with ctx.Err() once ctx is done
+func (m *Manager) ReadProductsFromOCFFileContext(ctx context.Context, filename string) (result []Product, err error) {
+	ctx, span := startSpan(ctx, "avro.ocf.read", "product", filename)
+	defer func() { tracing.End(span, len(result), err) }()
+
+	file, err := m.openFile(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	products, err := m.ReadProductsOCF(file)
+	return finishRead(ctx, products, err)
+}
+
+// WriteOrdersToOCFFile writes orders to an Avro Object Container File
+func (m *Manager) WriteOrdersToOCFFile(filename string, orders []Order, opts ...ocf.EncoderFunc) error {
+	return m.WriteOrdersToOCFFileContext(context.Background(), filename, orders, opts...)
+}
+
+// WriteOrdersToOCFFileContext writes orders to an Avro Object Container File,
+// stopping with ctx.Err() once ctx is done
+func (m *Manager) WriteOrdersToOCFFileContext(ctx context.Context, filename string, orders []Order, opts ...ocf.EncoderFunc) (err error) {
+	ctx, span := startSpan(ctx, "avro.ocf.write", "order", filename)
+	defer func() { tracing.End(span, len(orders), err) }()
+
+	return m.writeFile(ctx, filename, m.catalogEntry(formatOCF, "order", m.orderSchema, len(orders)), func(w io.Writer) error {
+		return m.WriteOrdersOCF(w, orders, opts...)
+	})
+}
+
+// ReadOrdersFromOCFFile reads orders from an Avro Object Container File
+func (m *Manager) ReadOrdersFromOCFFile(filename string) ([]Order, error) {
+	return m.ReadOrdersFromOCFFileContext(context.Background(), filename)
+}
+
+// ReadOrdersFromOCFFileContext reads orders from an Avro Object Container
+// File, stopping with ctx.Err() once ctx is done
+func (m *Manager) ReadOrdersFromOCFFileContext(ctx context.Context, filename string) (result []Order, err error) {
+	ctx, span := startSpan(ctx, "avro.ocf.read", "order", filename)
+	defer func() { tracing.End(span, len(result), err) }()
+
+	file, err := m.openFile(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	orders, err := m.ReadOrdersOCF(file)
+	return finishRead(ctx, orders, err)
+}
+
+// WriteAnalyticsOCF writes analytics events as an Avro Object Container File.
+// Events are always encoded from their avro struct tags
+func (m *Manager) WriteAnalyticsOCF(w io.Writer, events []Analytics, opts ...ocf.EncoderFunc) error {
+	encoder, err := ocf.NewEncoderWithSchema(m.analyticsSchema, w, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create ocf encoder: %w", err)
+	}
+
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("failed to encode analytics event %d: %w", event.ID, err)
+		}
+	}
+
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to flush ocf encoder: %w", err)
+	}
+	return nil
+}
+
+// ReadAnalyticsOCF reads analytics events from an Avro Object Container File
+func (m *Manager) ReadAnalyticsOCF(r io.Reader) ([]Analytics, error) {
+	reader, err := m.NewAnalyticsOCFReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return reader.All()
+}
+
+// WriteAnalyticsToOCFFile writes analytics events to an Avro Object Container File
+func (m *Manager) WriteAnalyticsToOCFFile(filename string, events []Analytics, opts ...ocf.EncoderFunc) error {
+	return m.WriteAnalyticsToOCFFileContext(context.Background(), filename, events, opts...)
+}
+
+// WriteAnalyticsToOCFFileContext writes analytics events to an Avro Object Container File,
+// stopping with ctx.Err() once ctx is done
+func (m *Manager) WriteAnalyticsToOCFFileContext(ctx context.Context, filename string, events []Analytics, opts ...ocf.EncoderFunc) (err error) {
+	ctx, span := startSpan(ctx, "avro.ocf.write", "analytics", filename)
+	defer func() { tracing.End(span, len(events), err) }()
+
+	return m.writeFile(ctx, filename, m.catalogEntry(formatOCF, "analytics", m.analyticsSchema, len(events)), func(w io.Writer) error {
+		return m.WriteAnalyticsOCF(w, events, opts...)
+	})
+}
+
+// ReadAnalyticsFromOCFFile reads analytics events from an Avro Object Container File
+func (m *Manager) ReadAnalyticsFromOCFFile(filename string) ([]Analytics, error) {
+	return m.ReadAnalyticsFromOCFFileContext(context.Background(), filename)
+}
+
+// ReadAnalyticsFromOCFFileContext reads analytics events from an Avro Object Container
+// File, stopping with ctx.Err() once ctx is done
+func (m *Manager) ReadAnalyticsFromOCFFileContext(ctx context.Context, filename string) (result []Analytics, err error) {
+	ctx, span := startSpan(ctx, "avro.ocf.read", "analytics", filename)
+	defer func() { tracing.End(span, len(result), err) }()
+
+	file, err := m.openFile(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	events, err := m.ReadAnalyticsOCF(file)
+	return finishRead(ctx, events, err)
+}
+
+// OCFReader decodes the records of an Object Container File one at a time,
+// so memory stays bounded however large the file is
+type OCFReader[T any] struct {
+	decoder *ocf.Decoder
+	decode  func(*ocf.Decoder) (T, error)
+	value   T
+	err     error
+}
+
+func newOCFReader[T any](r io.Reader, decode func(*ocf.Decoder) (T, error)) (*OCFReader[T], error) {
+	decoder, err := ocf.NewDecoder(r, ocf.WithDecoderConfig(decodeAPI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ocf decoder: %w", err)
+	}
+	return &OCFReader[T]{decoder: decoder, decode: decode}, nil
+}
+
+// NewUserOCFReader streams the users of an Object Container File
+func (m *Manager) NewUserOCFReader(r io.Reader) (*OCFReader[User], error) {
+	return newOCFReader(r, func(decoder *ocf.Decoder) (User, error) {
+		record, err := decodeOCFRecord(decoder, "user")
+		if err != nil {
+			return User{}, err
+		}
+		user, err := m.avroMapToUser(record)
+		if err != nil {
+			return User{}, fmt.Errorf("failed to convert avro map to user: %w", err)
+		}
+		return user, nil
+	})
+}
+
+// NewProductOCFReader streams the products of an Object Container File
+func (m *Manager) NewProductOCFReader(r io.Reader) (*OCFReader[Product], error) {
+	return newOCFReader(r, func(decoder *ocf.Decoder) (Product, error) {
+		record, err := decodeOCFRecord(decoder, "product")
+		if err != nil {
+			return Product{}, err
+		}
+		product, err := m.avroMapToProduct(record)
+		if err != nil {
+			return Product{}, fmt.Errorf("failed to convert avro map to product: %w", err)
+		}
+		return product, nil
+	})
+}
+
+// NewOrderOCFReader streams the orders of an Object Container File
+func (m *Manager) NewOrderOCFReader(r io.Reader) (*OCFReader[Order], error) {
+	return newOCFReader(r, func(decoder *ocf.Decoder) (Order, error) {
+		// Decode into a fresh value so optional fields never leak from the previous order
+		var order Order
+		if err := decoder.Decode(&order); err != nil {
+			return Order{}, fmt.Errorf("failed to decode order: %w", err)
+		}
+		return order, nil
+	})
+}
+
+// NewAnalyticsOCFReader streams the analytics events of an Object Container File
+func (m *Manager) NewAnalyticsOCFReader(r io.Reader) (*OCFReader[Analytics], error) {
+	return newOCFReader(r, func(decoder *ocf.Decoder) (Analytics, error) {
+		var event Analytics
+		if err := decoder.Decode(&event); err != nil {
+			return Analytics{}, fmt.Errorf("failed to decode analytics event: %w", err)
+		}
+		return event, nil
+	})
+}
+
+// decodeOCFRecord decodes the next record of decoder as a generic map
+func decodeOCFRecord(decoder *ocf.Decoder, name string) (map[string]interface{}, error) {
+	var result interface{}
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+	record, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to decode %s: expected a record, got %T", name, result)
+	}
+	return record, nil
+}
+
+// Next decodes the next record and reports whether there is one
+func (r *OCFReader[T]) Next() bool {
+	var zero T
+	r.value = zero
+	if r.err != nil {
+		return false
+	}
+	if !r.decoder.HasNext() {
+		if err := r.decoder.Error(); err != nil {
+			r.err = fmt.Errorf("failed to read ocf file: %w", err)
+		}
+		return false
+	}
+	r.value, r.err = r.decode(r.decoder)
+	return r.err == nil
+}
+
+// Value returns the record Next decoded
+func (r *OCFReader[T]) Value() T {
+	return r.value
+}
+
+// Err returns the error that ended the iteration, or nil at the end of the file
+func (r *OCFReader[T]) Err() error {
+	return r.err
+}
+
+// All decodes every remaining record
+func (r *OCFReader[T]) All() ([]T, error) {
+	var records []T
+	for r.Next() {
+		records = append(records, r.Value())
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return records, nil
+}
@@ -0,0 +1,138 @@
+package avro
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/hamba/avro/v2/ocf"
+
+	"go-transport-prac/internal/testutil"
+)
+
+func TestUserOCFRoundTrip(t *testing.T) {
+	manager, err := NewManager("tmp/test_ocf")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer os.RemoveAll("tmp/test_ocf")
+	manager.WithClock(testutil.NewDefaultFakeClock())
+
+	users := manager.CreateSampleUsers(25)
+
+	var buf bytes.Buffer
+	sync := [16]byte{'g', 'o', '-', 't', 'r', 'a', 'n', 's', 'p', 'o', 'r', 't'}
+	if err := manager.WriteUsersOCF(&buf, users, ocf.WithSyncBlock(sync), ocf.WithCodec(ocf.Deflate)); err != nil {
+		t.Fatalf("Failed to write OCF: %v", err)
+	}
+
+	decoder, err := ocf.NewDecoder(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Failed to open OCF: %v", err)
+	}
+	if codec := string(decoder.Metadata()["avro.codec"]); codec != string(ocf.Deflate) {
+		t.Errorf("Expected deflate codec in header, got %q", codec)
+	}
+
+	decoded, err := manager.ReadUsersOCF(&buf)
+	if err != nil {
+		t.Fatalf("Failed to read OCF: %v", err)
+	}
+	if len(decoded) != len(users) {
+		t.Fatalf("Expected %d users, got %d", len(users), len(decoded))
+	}
+	for i := range users {
+		if decoded[i].Email != users[i].Email || !decoded[i].CreatedAt.Equal(users[i].CreatedAt) {
+			t.Errorf("User %d mismatch: %+v", i, decoded[i])
+		}
+	}
+
+	if err := manager.WriteUsersToOCFFile("users.avro", users); err != nil {
+		t.Fatalf("Failed to write OCF file: %v", err)
+	}
+	fromFile, err := manager.ReadUsersFromOCFFile("users.avro")
+	if err != nil {
+		t.Fatalf("Failed to read OCF file: %v", err)
+	}
+	if len(fromFile) != len(users) {
+		t.Errorf("Expected %d users from file, got %d", len(users), len(fromFile))
+	}
+
+	if _, err := manager.ReadUsersOCF(bytes.NewReader([]byte("not an ocf file"))); err == nil {
+		t.Error("Expected error reading data without an OCF header")
+	}
+
+	t.Log("✓ OCF round-trip successful")
+}
+
+func TestInspectOCF(t *testing.T) {
+	manager, err := NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := manager.WriteProductsOCF(&buf, manager.CreateSampleProducts(7), ocf.WithCodec(ocf.Snappy)); err != nil {
+		t.Fatalf("Failed to write OCF: %v", err)
+	}
+	info, err := InspectOCF(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Failed to inspect OCF: %v", err)
+	}
+	if info.Records != 7 || info.Codec != string(ocf.Snappy) {
+		t.Errorf("Expected 7 snappy records, got %+v", info)
+	}
+	if name, err := OCFSchemaName(bytes.NewReader(buf.Bytes())); err != nil || name != "com.example.avro.Product" {
+		t.Errorf("Expected the product schema, got %q (%v)", name, err)
+	}
+
+	if _, err := InspectOCF(bytes.NewReader([]byte("not an ocf file"))); err == nil {
+		t.Error("Expected a file without an OCF header to be rejected")
+	}
+
+	t.Log("✓ OCF headers and record counts are inspected")
+}
+
+func TestOCFReader(t *testing.T) {
+	manager, err := NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	products := manager.CreateSampleProducts(12)
+	var buf bytes.Buffer
+	if err := manager.WriteProductsOCF(&buf, products, ocf.WithBlockLength(5)); err != nil {
+		t.Fatalf("Failed to write OCF: %v", err)
+	}
+	data := buf.Bytes()
+
+	reader, err := manager.NewProductOCFReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Failed to open OCF: %v", err)
+	}
+	var n int
+	for reader.Next() {
+		if reader.Value().SKU != products[n].SKU {
+			t.Errorf("Product %d mismatch: %+v", n, reader.Value())
+		}
+		n++
+	}
+	if reader.Err() != nil || n != len(products) {
+		t.Fatalf("Expected %d products, got %d (%v)", len(products), n, reader.Err())
+	}
+
+	// A truncated file ends the iteration with an error
+	reader, err = manager.NewProductOCFReader(bytes.NewReader(data[:len(data)-20]))
+	if err != nil {
+		t.Fatalf("Failed to open OCF: %v", err)
+	}
+	if _, err := reader.All(); err == nil {
+		t.Error("Expected a truncated file to fail")
+	}
+
+	if _, err := manager.NewUserOCFReader(bytes.NewReader([]byte("not avro"))); err == nil {
+		t.Error("Expected a file without an OCF header to fail")
+	}
+
+	t.Log("✓ Object Container Files stream one record at a time")
+}
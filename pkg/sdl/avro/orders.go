@@ -0,0 +1,141 @@
+package avro
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/hamba/avro/v2"
+
+	"go-transport-prac/internal/tracing"
+)
+
+// OrderStreamWriter encodes orders one at a time to an underlying writer.
+// Orders are always encoded from their avro struct tags.
+type OrderStreamWriter struct {
+	encoder *avro.Encoder
+	count   int
+}
+
+// NewOrderStreamWriter creates a stream writer that encodes orders as binary Avro records to w
+func (m *Manager) NewOrderStreamWriter(w io.Writer) *OrderStreamWriter {
+	return &OrderStreamWriter{encoder: avro.NewEncoderForSchema(m.orderSchema, w)}
+}
+
+// Write encodes a single order
+func (sw *OrderStreamWriter) Write(order Order) error {
+	if err := sw.encoder.Encode(order); err != nil {
+		return fmt.Errorf("failed to encode order %d: %w", order.ID, err)
+	}
+	sw.count++
+	return nil
+}
+
+// Count returns the number of orders written so far
+func (sw *OrderStreamWriter) Count() int {
+	return sw.count
+}
+
+// OrderStreamReader decodes orders one at a time from an underlying reader
+type OrderStreamReader struct {
+	decoder *avro.Decoder
+	current Order
+	err     error
+}
+
+// NewOrderStreamReader creates a stream reader over binary Avro order records in r
+func (m *Manager) NewOrderStreamReader(r io.Reader) *OrderStreamReader {
+	return &OrderStreamReader{decoder: decodeAPI.NewDecoder(m.orderSchema, r)}
+}
+
+// Next advances to the next order, returning false at end of stream or on error
+func (sr *OrderStreamReader) Next() bool {
+	if sr.err != nil {
+		return false
+	}
+
+	// Decode into a fresh value so optional fields never leak from the previous order
+	var order Order
+	if err := sr.decoder.Decode(&order); err != nil {
+		if err != io.EOF {
+			sr.err = fmt.Errorf("failed to decode order: %w", err)
+		}
+		return false
+	}
+
+	sr.current = order
+	return true
+}
+
+// Order returns the order decoded by the last call to Next
+func (sr *OrderStreamReader) Order() Order {
+	return sr.current
+}
+
+// Err returns the first error encountered, or nil at a clean end of stream
+func (sr *OrderStreamReader) Err() error {
+	return sr.err
+}
+
+// WriteOrdersToFile writes orders to a binary Avro file
+func (m *Manager) WriteOrdersToFile(filename string, orders []Order) error {
+	return m.WriteOrdersToFileContext(context.Background(), filename, orders)
+}
+
+// WriteOrdersToFileContext writes orders to a binary Avro file, stopping with
+// ctx.Err() once ctx is done
+func (m *Manager) WriteOrdersToFileContext(ctx context.Context, filename string, orders []Order) (err error) {
+	ctx, span := startSpan(ctx, "avro.write", "order", filename)
+	defer func() { tracing.End(span, len(orders), err) }()
+
+	return m.writeFile(ctx, filename, m.catalogEntry(formatAvro, "order", m.orderSchema, len(orders)), func(w io.Writer) error {
+		writer := m.NewOrderStreamWriter(w)
+
+		for _, order := range orders {
+			if err := writer.Write(order); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ReadOrdersFromFile reads orders from a binary Avro file
+func (m *Manager) ReadOrdersFromFile(filename string) ([]Order, error) {
+	return m.ReadOrdersWhereContext(context.Background(), filename, nil)
+}
+
+// ReadOrdersFromFileContext reads orders from a binary Avro file, stopping
+// with ctx.Err() once ctx is done
+func (m *Manager) ReadOrdersFromFileContext(ctx context.Context, filename string) ([]Order, error) {
+	return m.ReadOrdersWhereContext(ctx, filename, nil)
+}
+
+// ReadOrdersWhere streams orders from a binary Avro file and returns those
+// matching match; a nil match returns every order
+func (m *Manager) ReadOrdersWhere(filename string, match func(Order) bool) ([]Order, error) {
+	return m.ReadOrdersWhereContext(context.Background(), filename, match)
+}
+
+// ReadOrdersWhereContext is ReadOrdersWhere, stopping with ctx.Err() once ctx is done
+func (m *Manager) ReadOrdersWhereContext(ctx context.Context, filename string, match func(Order) bool) (result []Order, err error) {
+	ctx, span := startSpan(ctx, "avro.read", "order", filename)
+	defer func() { tracing.End(span, len(result), err) }()
+
+	file, err := m.openFile(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := m.NewOrderStreamReader(file)
+
+	var orders []Order
+	for reader.Next() {
+		if match == nil || match(reader.Order()) {
+			orders = append(orders, reader.Order())
+		}
+	}
+	return finishRead(ctx, orders, reader.Err())
+}
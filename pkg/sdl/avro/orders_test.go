@@ -0,0 +1,188 @@
+package avro
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-transport-prac/pkg/storage"
+)
+
+// unionOrders returns orders covering every null/non-null combination of the
+// optional fields in order.avsc
+func unionOrders() []Order {
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	str := func(s string) *string { return &s }
+	at := func(d time.Duration) *time.Time { t := base.Add(d); return &t }
+	price := func(cents int64) Price { return Price{Currency: "USD", AmountCents: cents} }
+	address := ShippingAddress{RecipientName: "Ada", Street: "1 Main St", City: "Springfield", State: "IL", PostalCode: "62701", Country: "US"}
+
+	order := func(id int64, status OrderStatus) Order {
+		return Order{
+			ID:          id,
+			UserID:      100 + id,
+			OrderNumber: "ORD-" + string(rune('A'+id)),
+			Status:      status,
+			Items: []OrderItem{{
+				ProductID: 7, ProductName: "Widget", ProductSKU: "W-7", Quantity: 2,
+				UnitPrice: price(500), TotalPrice: price(1000),
+				ProductVariant: map[string]string{"color": "red"},
+			}},
+			Summary:   OrderSummary{Subtotal: price(1000), Tax: price(80), ShippingCost: price(0), Discount: price(0), Total: price(1080), TotalItems: 2},
+			CreatedAt: base,
+			UpdatedAt: base.Add(time.Minute),
+		}
+	}
+
+	// No shipping or payment at all
+	pending := order(1, OrderStatusPending)
+
+	// Shipping without tracking, carrier or estimate; payment without transaction
+	confirmed := order(2, OrderStatusConfirmed)
+	confirmed.ShippingInfo = &ShippingInfo{Address: address, Method: "standard", Cost: price(0)}
+	confirmed.PaymentInfo = &PaymentInfo{Method: "paypal", Status: PaymentStatusAuthorized, Amount: price(1080)}
+
+	// Carrier known before tracking is assigned
+	processing := order(3, OrderStatusProcessing)
+	processing.ShippingInfo = &ShippingInfo{Address: address, Method: "express", Carrier: str("UPS"), Cost: price(1500), EstimatedDelivery: at(72 * time.Hour)}
+	processing.PaymentInfo = &PaymentInfo{Method: "credit_card", Status: PaymentStatusCaptured, TransactionID: str("txn-3"), Amount: price(2580), ProcessedAt: at(time.Hour)}
+
+	// Tracking without carrier, shipped but not delivered
+	shipped := order(4, OrderStatusShipped)
+	shipped.ShippingInfo = &ShippingInfo{Address: address, Method: "standard", TrackingNumber: str("1Z999"), Cost: price(0)}
+	shipped.ShippedAt = at(24 * time.Hour)
+
+	// Every optional field set
+	delivered := order(5, OrderStatusDelivered)
+	delivered.ShippingInfo = &ShippingInfo{Address: address, Method: "overnight", TrackingNumber: str("1Z555"), Carrier: str("FedEx"), Cost: price(2500), EstimatedDelivery: at(48 * time.Hour)}
+	delivered.PaymentInfo = &PaymentInfo{Method: "apple_pay", Status: PaymentStatusCaptured, TransactionID: str("txn-5"), Amount: price(3580), ProcessedAt: at(time.Minute)}
+	delivered.ShippedAt = at(24 * time.Hour)
+	delivered.DeliveredAt = at(40 * time.Hour)
+
+	// Empty strings are values, not nulls
+	cancelled := order(6, OrderStatusCancelled)
+	cancelled.ShippingInfo = &ShippingInfo{Address: address, Method: "standard", TrackingNumber: str(""), Carrier: str(""), Cost: price(0)}
+	cancelled.PaymentInfo = &PaymentInfo{Method: "credit_card", Status: PaymentStatusRefunded, TransactionID: str(""), Amount: price(1080)}
+
+	// Delivered timestamp without shipped timestamp
+	refunded := order(7, OrderStatusRefunded)
+	refunded.DeliveredAt = at(30 * time.Hour)
+
+	return []Order{pending, confirmed, processing, shipped, delivered, cancelled, refunded}
+}
+
+func TestOrderFileRoundTrip(t *testing.T) {
+	manager, err := NewManager("tmp/test_order_files")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer os.RemoveAll("tmp/test_order_files")
+
+	orders := unionOrders()
+	if err := manager.WriteOrdersToFile("orders.avro", orders); err != nil {
+		t.Fatalf("Failed to write orders: %v", err)
+	}
+
+	readOrders, err := manager.ReadOrdersFromFile("orders.avro")
+	if err != nil {
+		t.Fatalf("Failed to read orders: %v", err)
+	}
+	if len(readOrders) != len(orders) {
+		t.Fatalf("Expected %d orders, got %d", len(orders), len(readOrders))
+	}
+
+	for i := range orders {
+		if !reflect.DeepEqual(readOrders[i], orders[i]) {
+			t.Errorf("Order %d (%s) mismatch:\nwrote: %+v\nread:  %+v", orders[i].ID, orders[i].Status, orders[i], readOrders[i])
+		}
+	}
+
+	t.Logf("✓ %d orders round-trip with every nested union combination", len(orders))
+}
+
+func TestOrderUnionsStayDistinct(t *testing.T) {
+	manager, err := NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	var buf bytes.Buffer
+	writer := manager.NewOrderStreamWriter(&buf)
+	for _, order := range unionOrders() {
+		if err := writer.Write(order); err != nil {
+			t.Fatalf("Failed to write order: %v", err)
+		}
+	}
+
+	reader := manager.NewOrderStreamReader(&buf)
+	byID := make(map[int64]Order)
+	for reader.Next() {
+		byID[reader.Order().ID] = reader.Order()
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatalf("Stream reader failed: %v", err)
+	}
+
+	// A null union after a non-null one must not inherit the earlier value
+	if byID[1].ShippingInfo != nil || byID[1].PaymentInfo != nil || byID[1].ShippedAt != nil {
+		t.Errorf("Expected order 1 to have no optional fields: %+v", byID[1])
+	}
+	if s := byID[3].ShippingInfo; s == nil || s.TrackingNumber != nil || s.Carrier == nil || *s.Carrier != "UPS" {
+		t.Errorf("Expected carrier without tracking on order 3: %+v", s)
+	}
+	if s := byID[4].ShippingInfo; s == nil || s.Carrier != nil || s.TrackingNumber == nil || *s.TrackingNumber != "1Z999" {
+		t.Errorf("Expected tracking without carrier on order 4: %+v", s)
+	}
+	if o := byID[4]; o.ShippedAt == nil || o.DeliveredAt != nil {
+		t.Errorf("Expected shipped but undelivered order 4: shipped %v delivered %v", o.ShippedAt, o.DeliveredAt)
+	}
+	if s := byID[6].ShippingInfo; s == nil || s.TrackingNumber == nil || *s.TrackingNumber != "" || s.Carrier == nil {
+		t.Errorf("Expected empty strings to survive on order 6: %+v", s)
+	}
+	if o := byID[7]; o.ShippedAt != nil || o.DeliveredAt == nil {
+		t.Errorf("Expected delivered-only timestamps on order 7: shipped %v delivered %v", o.ShippedAt, o.DeliveredAt)
+	}
+	if p := byID[2].PaymentInfo; p == nil || p.TransactionID != nil || p.ProcessedAt != nil {
+		t.Errorf("Expected payment without transaction on order 2: %+v", p)
+	}
+
+	t.Log("✓ Null and non-null union branches stay distinct across a stream")
+}
+
+func TestReadOrdersWhere(t *testing.T) {
+	manager, err := NewManager("tmp/test_order_query")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	manager.WithStorage(storage.NewMemoryStorage())
+
+	if err := manager.WriteOrdersToFile("orders.avro", unionOrders()); err != nil {
+		t.Fatalf("Failed to write orders: %v", err)
+	}
+
+	tracked, err := manager.ReadOrdersWhere("orders.avro", func(o Order) bool {
+		return o.ShippingInfo != nil && o.ShippingInfo.TrackingNumber != nil && *o.ShippingInfo.TrackingNumber != ""
+	})
+	if err != nil {
+		t.Fatalf("Failed to query orders: %v", err)
+	}
+	if len(tracked) != 2 || tracked[0].ID != 4 || tracked[1].ID != 5 {
+		t.Errorf("Expected tracked orders 4 and 5, got %d orders", len(tracked))
+	}
+
+	undelivered, err := manager.ReadOrdersWhere("orders.avro", func(o Order) bool { return o.DeliveredAt == nil })
+	if err != nil {
+		t.Fatalf("Failed to query orders: %v", err)
+	}
+	if len(undelivered) != 5 {
+		t.Errorf("Expected 5 undelivered orders, got %d", len(undelivered))
+	}
+
+	if _, err := manager.ReadOrdersFromFile("missing.avro"); err == nil {
+		t.Error("Expected reading a missing file to fail")
+	}
+
+	t.Log("✓ Order queries filter while streaming from storage")
+}
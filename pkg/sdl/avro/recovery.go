@@ -0,0 +1,157 @@
+package avro
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/hamba/avro/v2"
+
+	"go-transport-prac/internal/filelock"
+	"go-transport-prac/pkg/sdl/encryption"
+)
+
+// TruncationError reports a file whose trailing bytes could not be decoded,
+// typically because a writer crashed in the middle of a record
+type TruncationError struct {
+	Filename         string
+	Offset           int64 // byte offset where the last complete record ends
+	FileSize         int64
+	RecordsRecovered int
+	Err              error
+}
+
+// Error implements the error interface
+func (e *TruncationError) Error() string {
+	return fmt.Sprintf("file %s truncated at offset %d of %d (%d records recovered): %v",
+		e.Filename, e.Offset, e.FileSize, e.RecordsRecovered, e.Err)
+}
+
+// Unwrap returns the underlying decode error
+func (e *TruncationError) Unwrap() error {
+	return e.Err
+}
+
+// errEncryptedRecovery rejects recovering encrypted files: a cut short
+// encrypted file fails authentication, so no prefix of it can be trusted
+var errEncryptedRecovery = fmt.Errorf("cannot recover records from an encrypted file")
+
+// ReadUsersFromFileTolerant reads users from a binary Avro file, returning every
+// record decoded before the first corrupt or partial record. If the file does not
+// end on a record boundary the error is a *TruncationError and users is still populated.
+func (m *Manager) ReadUsersFromFileTolerant(filename string) ([]User, error) {
+	data, err := m.readStored(context.Background(), filename)
+	if err != nil {
+		return nil, err
+	}
+	if encryption.IsEncrypted(data) {
+		return nil, errEncryptedRecovery
+	}
+
+	var users []User
+	truncErr, err := scanRecords(filename, m.userSchema, data, func(_ int, record interface{}, _, _ int64) error {
+		user, err := m.recordToUser(record)
+		if err != nil {
+			return err
+		}
+		users = append(users, user)
+		return nil
+	})
+	if err != nil {
+		return users, err
+	}
+	if truncErr != nil {
+		return users, truncErr
+	}
+	return users, nil
+}
+
+// RepairFile truncates a file written with schema to its last complete record.
+// It returns the TruncationError describing what was removed, or nil if the file was intact.
+func (m *Manager) RepairFile(filename string, schema avro.Schema) (*TruncationError, error) {
+	defer filelock.Files.Lock(filelock.Key(m.storage, m.baseDir, filename))()
+
+	ctx := context.Background()
+	data, err := m.readStored(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	if encryption.IsEncrypted(data) {
+		return nil, errEncryptedRecovery
+	}
+
+	truncErr, _ := scanRecords(filename, schema, data, func(int, interface{}, int64, int64) error { return nil })
+	if truncErr == nil {
+		return nil, nil
+	}
+
+	if m.storage != nil {
+		if err := m.storage.Put(ctx, filename, bytes.NewReader(data[:truncErr.Offset])); err != nil {
+			return nil, fmt.Errorf("failed to store repaired file: %w", err)
+		}
+	} else if err := os.Truncate(filepath.Join(m.baseDir, filename), truncErr.Offset); err != nil {
+		return nil, fmt.Errorf("failed to truncate file: %w", err)
+	}
+
+	return truncErr, nil
+}
+
+// readStored returns the bytes of a file in baseDir or the storage backend
+// as stored, without decrypting them
+func (m *Manager) readStored(ctx context.Context, filename string) ([]byte, error) {
+	if m.storage == nil {
+		data, err := os.ReadFile(filepath.Join(m.baseDir, filename))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open file: %w", err)
+		}
+		return data, nil
+	}
+
+	obj, err := m.storage.Get(ctx, filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer obj.Close()
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return data, nil
+}
+
+// scanRecords decodes consecutive records from data, calling fn with the
+// index of each record and the byte range it was decoded from. It stops at the first record
+// that fails to decode, described by the returned *TruncationError, or at the
+// first error fn returns
+func scanRecords(filename string, schema avro.Schema, data []byte, fn func(index int, record interface{}, offset, end int64) error) (*TruncationError, error) {
+	src := bytes.NewReader(data)
+	// A one byte buffer keeps the reader from consuming past the current record,
+	// so the source position is always the exact end of the last decoded record
+	reader := avro.NewReader(src, 1)
+
+	var offset int64
+	for decoded := 0; offset < int64(len(data)); decoded++ {
+		var record interface{}
+		reader.ReadVal(schema, &record)
+		if reader.Error != nil {
+			return &TruncationError{
+				Filename:         filename,
+				Offset:           offset,
+				FileSize:         int64(len(data)),
+				RecordsRecovered: decoded,
+				Err:              reader.Error,
+			}, nil
+		}
+
+		end := int64(len(data)) - int64(src.Len())
+		if err := fn(decoded, record, offset, end); err != nil {
+			return nil, err
+		}
+		offset = end
+	}
+
+	return nil, nil
+}
@@ -1,11 +1,18 @@
 package avro
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/hamba/avro/v2"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/types"
 )
 
 // SchemaRegistry simulates a schema registry for managing Avro schemas
@@ -13,10 +20,27 @@ type SchemaRegistry struct {
 	mu              sync.RWMutex
 	schemas         map[int]SchemaMetadata
 	subjectSchemas  map[string][]int
-	nextSchemaID    int
+	ids             IDGenerator
 	compatibilityLevels map[string]CompatibilityLevel
+	modes           map[string]Mode
+	clock           types.Clock
+	cache           types.Cache
+	cacheNamespace  string
+	cacheTTL        time.Duration
 }
 
+// Codes of the internal/errors app errors returned by registry lookups and
+// deletions, for telling the not-found cases apart with errors.IsCode
+const (
+	CodeSubjectNotFound       = "SUBJECT_NOT_FOUND"
+	CodeVersionNotFound       = "SCHEMA_VERSION_NOT_FOUND"
+	CodeSchemaNotFound        = "SCHEMA_NOT_FOUND"
+	CodeSubjectSoftDeleted    = "SUBJECT_SOFT_DELETED"
+	CodeSubjectNotSoftDeleted = "SUBJECT_NOT_SOFT_DELETED"
+	CodeVersionSoftDeleted    = "SCHEMA_VERSION_SOFT_DELETED"
+	CodeVersionNotSoftDeleted = "SCHEMA_VERSION_NOT_SOFT_DELETED"
+)
+
 // SchemaMetadata contains metadata about a registered schema
 type SchemaMetadata struct {
 	ID          int                 `json:"id"`
@@ -27,6 +51,9 @@ type SchemaMetadata struct {
 	CreatedAt   time.Time           `json:"createdAt"`
 	Fingerprint string              `json:"fingerprint"`
 	References  []SchemaReference   `json:"references,omitempty"`
+	// Deleted marks a soft-deleted version, hidden from subject lookups but
+	// still resolvable by ID and restored by registering the schema again
+	Deleted     bool                `json:"deleted,omitempty"`
 }
 
 // SchemaReference represents a reference to another schema
@@ -44,51 +71,130 @@ const (
 	CompatibilityFull     CompatibilityLevel = "FULL"
 	CompatibilityForward  CompatibilityLevel = "FORWARD"
 	CompatibilityBackward CompatibilityLevel = "BACKWARD"
+
+	// Transitive levels check against every registered version instead of only the latest
+	CompatibilityFullTransitive     CompatibilityLevel = "FULL_TRANSITIVE"
+	CompatibilityForwardTransitive  CompatibilityLevel = "FORWARD_TRANSITIVE"
+	CompatibilityBackwardTransitive CompatibilityLevel = "BACKWARD_TRANSITIVE"
 )
 
+// IsTransitive reports whether the level checks all previous versions
+func (l CompatibilityLevel) IsTransitive() bool {
+	return l == CompatibilityFullTransitive || l == CompatibilityForwardTransitive || l == CompatibilityBackwardTransitive
+}
+
+// ChecksBackward reports whether the level requires the new schema to read old data
+func (l CompatibilityLevel) ChecksBackward() bool {
+	return l == CompatibilityBackward || l == CompatibilityBackwardTransitive || l == CompatibilityFull || l == CompatibilityFullTransitive
+}
+
+// ChecksForward reports whether the level requires old schemas to read new data
+func (l CompatibilityLevel) ChecksForward() bool {
+	return l == CompatibilityForward || l == CompatibilityForwardTransitive || l == CompatibilityFull || l == CompatibilityFullTransitive
+}
+
 // NewSchemaRegistry creates a new schema registry
 func NewSchemaRegistry() *SchemaRegistry {
 	return &SchemaRegistry{
 		schemas:             make(map[int]SchemaMetadata),
 		subjectSchemas:     make(map[string][]int),
-		nextSchemaID:       1,
+		ids:                NewMonotonicIDGenerator(1),
 		compatibilityLevels: make(map[string]CompatibilityLevel),
+		modes:               make(map[string]Mode),
+		clock:               types.SystemClock{},
 	}
 }
 
+// WithClock sets the clock used for schema CreatedAt timestamps
+func (sr *SchemaRegistry) WithClock(clock types.Clock) *SchemaRegistry {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	sr.clock = types.ClockOrSystem(clock)
+	return sr
+}
+
+// WithIDGenerator sets how IDs of newly registered schemas are assigned; the
+// default is a MonotonicIDGenerator starting at 1
+func (sr *SchemaRegistry) WithIDGenerator(ids IDGenerator) *SchemaRegistry {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	sr.ids = ids
+	return sr
+}
+
+// WithCache shares the schemas this registry registers through c for ttl, so
+// replicas using the same cache and namespace resolve IDs and subjects they
+// never registered. The registry's own schemas always win over cached
+// entries; registries that assign IDs independently must use different
+// namespaces. A zero ttl keeps entries until they are invalidated
+func (sr *SchemaRegistry) WithCache(c types.Cache, namespace string, ttl time.Duration) *SchemaRegistry {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	sr.cache = c
+	sr.cacheNamespace = namespace
+	sr.cacheTTL = ttl
+	return sr
+}
+
 // RegisterSchema registers a new schema or returns existing schema ID
 func (sr *SchemaRegistry) RegisterSchema(subject string, schemaJSON string) (int, error) {
 	sr.mu.Lock()
 	defer sr.mu.Unlock()
 
+	if err := sr.checkWritable(subject); err != nil {
+		return 0, err
+	}
+
 	// Parse the schema to validate it
 	schema, err := avro.Parse(schemaJSON)
 	if err != nil {
 		return 0, fmt.Errorf("invalid schema: %w", err)
 	}
 
-	// Generate fingerprint (simplified - in real implementation would use actual fingerprinting)
-	fingerprint := fmt.Sprintf("fp_%s_%d", subject, len(schemaJSON))
+	// Fingerprint the canonical form so formatting differences don't create new versions
+	fingerprint := schemaFingerprint(schema)
 
 	// Check if schema already exists for this subject
 	if schemaIDs, exists := sr.subjectSchemas[subject]; exists {
 		for _, id := range schemaIDs {
-			if sr.schemas[id].Fingerprint == fingerprint {
-				return id, nil // Schema already registered
+			metadata := sr.schemas[id]
+			if metadata.Fingerprint != fingerprint {
+				continue
+			}
+			// Registering a soft-deleted schema again restores its version
+			if metadata.Deleted {
+				metadata.Deleted = false
+				sr.schemas[id] = metadata
+				sr.store(sr.idCacheKey(id), metadata)
+				sr.invalidateCached(sr.latestCacheKey(subject))
 			}
+			return id, nil // Schema already registered
 		}
 	}
 
 	// Check compatibility with existing schemas
-	if err := sr.checkCompatibility(subject, schema); err != nil {
-		return 0, fmt.Errorf("schema compatibility check failed: %w", err)
+	if report := sr.checkCompatibility(subject, schema); !report.Compatible {
+		return 0, fmt.Errorf("schema compatibility check failed: %w", &CompatibilityError{Report: report})
 	}
 
 	// Register new schema
-	schemaID := sr.nextSchemaID
-	sr.nextSchemaID++
+	schemaID, err := sr.ids.NextID(subject, fingerprint)
+	if err != nil {
+		return 0, fmt.Errorf("failed to assign schema ID: %w", err)
+	}
+	if schemaID < 1 {
+		return 0, fmt.Errorf("failed to assign schema ID: invalid ID %d", schemaID)
+	}
+	if taken, exists := sr.schemas[schemaID]; exists {
+		return 0, errors.ConflictError(errors.CodeConflict, fmt.Sprintf("schema ID %d is already used by subject %s version %d",
+			schemaID, taken.Subject, taken.Version))
+	}
 
-	version := len(sr.subjectSchemas[subject]) + 1
+	// Versions keep counting past deleted ones, like Confluent
+	version := 1
+	if schemaIDs := sr.subjectSchemas[subject]; len(schemaIDs) > 0 {
+		version = sr.schemas[schemaIDs[len(schemaIDs)-1]].Version + 1
+	}
 
 	metadata := SchemaMetadata{
 		ID:          schemaID,
@@ -96,41 +202,165 @@ func (sr *SchemaRegistry) RegisterSchema(subject string, schemaJSON string) (int
 		Subject:     subject,
 		Schema:      schema,
 		SchemaJSON:  schemaJSON,
-		CreatedAt:   time.Now(),
+		CreatedAt:   sr.clock.Now(),
 		Fingerprint: fingerprint,
 	}
 
 	sr.schemas[schemaID] = metadata
 	sr.subjectSchemas[subject] = append(sr.subjectSchemas[subject], schemaID)
+	sr.store(sr.idCacheKey(schemaID), metadata)
+	sr.store(sr.latestCacheKey(subject), metadata)
 
 	return schemaID, nil
 }
 
-// GetSchema retrieves a schema by ID
+// GetSchema retrieves a schema by ID. Soft-deleted schemas are still returned
+// so data written with them stays readable. IDs this registry does not hold
+// are looked up in the cache shared with its replicas
 func (sr *SchemaRegistry) GetSchema(schemaID int) (SchemaMetadata, error) {
 	sr.mu.RLock()
 	defer sr.mu.RUnlock()
 
-	metadata, exists := sr.schemas[schemaID]
-	if !exists {
-		return SchemaMetadata{}, fmt.Errorf("schema with ID %d not found", schemaID)
+	if metadata, exists := sr.schemas[schemaID]; exists {
+		return metadata, nil
 	}
-
-	return metadata, nil
+	if metadata, ok := sr.cached(sr.idCacheKey(schemaID)); ok && metadata.ID == schemaID {
+		return metadata, nil
+	}
+	return SchemaMetadata{}, errors.NotFoundError(CodeSchemaNotFound, fmt.Sprintf("schema with ID %d not found", schemaID))
 }
 
-// GetLatestSchema retrieves the latest schema for a subject
+// GetLatestSchema retrieves the latest schema for a subject. Subjects this
+// registry has never seen are looked up in the cache shared with its replicas
 func (sr *SchemaRegistry) GetLatestSchema(subject string) (SchemaMetadata, error) {
 	sr.mu.RLock()
 	defer sr.mu.RUnlock()
 
-	schemaIDs, exists := sr.subjectSchemas[subject]
-	if !exists || len(schemaIDs) == 0 {
-		return SchemaMetadata{}, fmt.Errorf("no schemas found for subject %s", subject)
+	if _, known := sr.subjectSchemas[subject]; known {
+		if schemaIDs := sr.liveIDs(subject); len(schemaIDs) > 0 {
+			return sr.schemas[schemaIDs[len(schemaIDs)-1]], nil
+		}
+	} else if metadata, ok := sr.cached(sr.latestCacheKey(subject)); ok && metadata.Subject == subject {
+		return metadata, nil
+	}
+	return SchemaMetadata{}, errors.NotFoundError(CodeSubjectNotFound, fmt.Sprintf("no schemas found for subject %s", subject))
+}
+
+// idCachePrefix starts the cache keys of schemas by ID, avro:schema:<namespace>:id:<id>
+func (sr *SchemaRegistry) idCachePrefix() string {
+	return "avro:schema:" + sr.cacheNamespace + ":id:"
+}
+
+// latestCachePrefix starts the cache keys of subjects' latest schemas,
+// avro:schema:<namespace>:latest:<subject>
+func (sr *SchemaRegistry) latestCachePrefix() string {
+	return "avro:schema:" + sr.cacheNamespace + ":latest:"
+}
+
+// idCacheKey is the cache key for a schema looked up by ID
+func (sr *SchemaRegistry) idCacheKey(schemaID int) string {
+	return sr.idCachePrefix() + strconv.Itoa(schemaID)
+}
+
+// latestCacheKey is the cache key for a subject's latest schema
+func (sr *SchemaRegistry) latestCacheKey(subject string) string {
+	return sr.latestCachePrefix() + subject
+}
+
+// schemaFingerprint is the hex SHA-256 fingerprint of a schema's canonical form
+func schemaFingerprint(schema avro.Schema) string {
+	return fmt.Sprintf("%x", schema.Fingerprint())
+}
+
+// cached returns the metadata stored under key, re-parsing its schema.
+// Cache failures are treated as misses so lookups fall back to the registry
+func (sr *SchemaRegistry) cached(key string) (SchemaMetadata, bool) {
+	if sr.cache == nil {
+		return SchemaMetadata{}, false
+	}
+
+	data, err := sr.cache.Get(context.Background(), key)
+	if err != nil {
+		return SchemaMetadata{}, false
+	}
+	metadata, ok := decodeCached(data)
+	return metadata, ok
+}
+
+// decodeCached parses cached metadata, rejecting entries whose schema no
+// longer matches the fingerprint it was cached with
+func decodeCached(data []byte) (SchemaMetadata, bool) {
+	var metadata SchemaMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return SchemaMetadata{}, false
+	}
+	schema, err := avro.Parse(metadata.SchemaJSON)
+	if err != nil || schemaFingerprint(schema) != metadata.Fingerprint {
+		return SchemaMetadata{}, false
+	}
+	metadata.Schema = schema
+	return metadata, true
+}
+
+// ValidCacheEntry reports whether a cached registry entry can still be used,
+// for pruning a persisted cache on load:
+//
+//	c, _ := cache.OpenFileCache(".cache/schemas.json")
+//	registry.WithCache(c, "orders", time.Hour)
+//	c.Prune(registry.ValidCacheEntry)
+//
+// An entry is kept only when this registry holds the same schema under its ID
+// or as the subject's latest version, so register the schemas before pruning.
+// Entries it cannot verify are dropped. Keys that are not entries of this
+// registry's namespace are kept
+func (sr *SchemaRegistry) ValidCacheEntry(key string, value []byte) bool {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	var id, subject string
+	switch idPrefix, latestPrefix := sr.idCachePrefix(), sr.latestCachePrefix(); {
+	case strings.HasPrefix(key, idPrefix):
+		id = strings.TrimPrefix(key, idPrefix)
+	case strings.HasPrefix(key, latestPrefix):
+		subject = strings.TrimPrefix(key, latestPrefix)
+	default:
+		return true
+	}
+
+	metadata, ok := decodeCached(value)
+	if !ok {
+		return false
+	}
+
+	if id != "" {
+		if strconv.Itoa(metadata.ID) != id {
+			return false
+		}
+		current, exists := sr.schemas[metadata.ID]
+		return exists && current.Fingerprint == metadata.Fingerprint
+	}
+	if metadata.Subject != subject {
+		return false
+	}
+	schemaIDs := sr.liveIDs(subject)
+	return len(schemaIDs) > 0 && sr.schemas[schemaIDs[len(schemaIDs)-1]].Fingerprint == metadata.Fingerprint
+}
+
+// store caches metadata under key, ignoring cache failures
+func (sr *SchemaRegistry) store(key string, metadata SchemaMetadata) {
+	if sr.cache == nil {
+		return
+	}
+	if data, err := json.Marshal(metadata); err == nil {
+		sr.cache.Set(context.Background(), key, data, sr.cacheTTL)
 	}
+}
 
-	latestID := schemaIDs[len(schemaIDs)-1]
-	return sr.schemas[latestID], nil
+// invalidateCached drops key from the cache
+func (sr *SchemaRegistry) invalidateCached(key string) {
+	if sr.cache != nil {
+		sr.cache.Delete(context.Background(), key)
+	}
 }
 
 // GetSchemaVersion retrieves a specific version of a schema for a subject
@@ -138,35 +368,65 @@ func (sr *SchemaRegistry) GetSchemaVersion(subject string, version int) (SchemaM
 	sr.mu.RLock()
 	defer sr.mu.RUnlock()
 
-	schemaIDs, exists := sr.subjectSchemas[subject]
-	if !exists || version < 1 || version > len(schemaIDs) {
-		return SchemaMetadata{}, fmt.Errorf("schema version %d not found for subject %s", version, subject)
+	schemaIDs := sr.liveIDs(subject)
+	if len(schemaIDs) == 0 {
+		return SchemaMetadata{}, subjectNotFound(subject)
 	}
+	for _, id := range schemaIDs {
+		if sr.schemas[id].Version == version {
+			return sr.schemas[id], nil
+		}
+	}
+	return SchemaMetadata{}, versionNotFound(subject, version)
+}
 
-	schemaID := schemaIDs[version-1]
-	return sr.schemas[schemaID], nil
+// LookupSchema returns the version of a subject registered with schemaJSON,
+// compared by canonical form
+func (sr *SchemaRegistry) LookupSchema(subject string, schemaJSON string) (SchemaMetadata, error) {
+	schema, err := avro.Parse(schemaJSON)
+	if err != nil {
+		return SchemaMetadata{}, fmt.Errorf("invalid schema: %w", err)
+	}
+	fingerprint := schemaFingerprint(schema)
+
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	schemaIDs := sr.liveIDs(subject)
+	if len(schemaIDs) == 0 {
+		return SchemaMetadata{}, subjectNotFound(subject)
+	}
+	for _, id := range schemaIDs {
+		if sr.schemas[id].Fingerprint == fingerprint {
+			return sr.schemas[id], nil
+		}
+	}
+	return SchemaMetadata{}, errors.NotFoundError(CodeSchemaNotFound, fmt.Sprintf("schema not registered under subject %s", subject))
 }
 
-// ListSubjects returns all registered subjects
+// ListSubjects returns all registered subjects with at least one version
+// that is not soft-deleted
 func (sr *SchemaRegistry) ListSubjects() []string {
 	sr.mu.RLock()
 	defer sr.mu.RUnlock()
 
 	subjects := make([]string, 0, len(sr.subjectSchemas))
 	for subject := range sr.subjectSchemas {
-		subjects = append(subjects, subject)
+		if len(sr.liveIDs(subject)) > 0 {
+			subjects = append(subjects, subject)
+		}
 	}
 	return subjects
 }
 
-// ListSchemaVersions returns all versions for a subject
+// ListSchemaVersions returns all versions for a subject that are not soft-deleted
 func (sr *SchemaRegistry) ListSchemaVersions(subject string) ([]int, error) {
 	sr.mu.RLock()
 	defer sr.mu.RUnlock()
 
-	schemaIDs, exists := sr.subjectSchemas[subject]
-	if !exists {
-		return nil, fmt.Errorf("subject %s not found", subject)
+	schemaIDs := sr.liveIDs(subject)
+	if len(schemaIDs) == 0 {
+		return nil, subjectNotFound(subject)
 	}
 
 	versions := make([]int, len(schemaIDs))
@@ -198,97 +458,113 @@ func (sr *SchemaRegistry) GetCompatibilityLevel(subject string) CompatibilityLev
 
 // CheckCompatibility checks if a new schema is compatible with existing schemas
 func (sr *SchemaRegistry) CheckCompatibility(subject string, schemaJSON string) (bool, error) {
+	report, err := sr.CheckCompatibilityReport(subject, schemaJSON)
+	if err != nil {
+		return false, err
+	}
+
+	return report.Compatible, nil
+}
+
+// CheckCompatibilityReport checks a new schema against the subject and returns the full report
+func (sr *SchemaRegistry) CheckCompatibilityReport(subject string, schemaJSON string) (*CompatibilityReport, error) {
 	sr.mu.RLock()
 	defer sr.mu.RUnlock()
 
 	schema, err := avro.Parse(schemaJSON)
 	if err != nil {
-		return false, fmt.Errorf("invalid schema: %w", err)
+		return nil, fmt.Errorf("invalid schema: %w", err)
 	}
 
-	return sr.checkCompatibility(subject, schema) == nil, nil
+	return sr.checkCompatibility(subject, schema), nil
+}
+
+// CheckCompatibilityWithVersion checks a new schema against one registered
+// version in the directions of the subject's compatibility level
+func (sr *SchemaRegistry) CheckCompatibilityWithVersion(subject string, version int, schemaJSON string) (*CompatibilityReport, error) {
+	schema, err := avro.Parse(schemaJSON)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	existing, err := sr.GetSchemaVersion(subject, version)
+	if err != nil {
+		return nil, err
+	}
+	level := sr.GetCompatibilityLevel(subject)
+
+	report := &CompatibilityReport{
+		Subject:         subject,
+		Level:           level,
+		CheckedVersions: []int{existing.Version},
+	}
+	if level.ChecksBackward() {
+		report.AddIncompatibilities("backward", existing.Version, sr.checkBackwardCompatibility(existing.Schema, schema))
+	}
+	if level.ChecksForward() {
+		report.AddIncompatibilities("forward", existing.Version, sr.checkForwardCompatibility(existing.Schema, schema))
+	}
+	report.Compatible = len(report.Incompatibilities) == 0
+	return report, nil
 }
 
 // checkCompatibility performs the actual compatibility check
 // Note: This method assumes the caller already holds the appropriate lock
-func (sr *SchemaRegistry) checkCompatibility(subject string, newSchema avro.Schema) error {
+func (sr *SchemaRegistry) checkCompatibility(subject string, newSchema avro.Schema) *CompatibilityReport {
 	// Get compatibility level without additional locking since caller holds lock
 	compatibilityLevel := CompatibilityBackward // Default
 	if level, exists := sr.compatibilityLevels[subject]; exists {
 		compatibilityLevel = level
 	}
-	
+
+	report := &CompatibilityReport{
+		Subject:    subject,
+		Level:      compatibilityLevel,
+		Compatible: true,
+	}
+
 	// If no compatibility checking required
 	if compatibilityLevel == CompatibilityNone {
-		return nil
+		return report
 	}
 
-	schemaIDs, exists := sr.subjectSchemas[subject]
-	if !exists || len(schemaIDs) == 0 {
-		return nil // No existing schemas to check against
+	// Soft-deleted versions are not checked against
+	schemaIDs := sr.liveIDs(subject)
+	if len(schemaIDs) == 0 {
+		return report // No existing schemas to check against
 	}
 
-	// Get the latest schema for compatibility checking
-	latestID := schemaIDs[len(schemaIDs)-1]
-	latestSchema := sr.schemas[latestID].Schema
-
-	switch compatibilityLevel {
-	case CompatibilityForward:
-		return sr.checkForwardCompatibility(latestSchema, newSchema)
-	case CompatibilityBackward:
-		return sr.checkBackwardCompatibility(latestSchema, newSchema)
-	case CompatibilityFull:
-		if err := sr.checkForwardCompatibility(latestSchema, newSchema); err != nil {
-			return err
-		}
-		return sr.checkBackwardCompatibility(latestSchema, newSchema)
-	default:
-		return nil
+	// Non-transitive levels only check against the latest schema
+	if !compatibilityLevel.IsTransitive() {
+		schemaIDs = schemaIDs[len(schemaIDs)-1:]
 	}
-}
 
-// checkForwardCompatibility checks if new schema can read data written with old schema
-func (sr *SchemaRegistry) checkForwardCompatibility(oldSchema, newSchema avro.Schema) error {
-	// Simplified compatibility check - in practice this would be more comprehensive
-	if oldSchema.Type() != newSchema.Type() {
-		return fmt.Errorf("schema types don't match: %s vs %s", oldSchema.Type(), newSchema.Type())
-	}
-	
-	// Check if schemas are identical (simplified check)
-	if oldSchema.String() == newSchema.String() {
-		return nil
+	for _, id := range schemaIDs {
+		existing := sr.schemas[id]
+		report.CheckedVersions = append(report.CheckedVersions, existing.Version)
+
+		if compatibilityLevel.ChecksBackward() {
+			report.AddIncompatibilities("backward", existing.Version,
+				sr.checkBackwardCompatibility(existing.Schema, newSchema))
+		}
+		if compatibilityLevel.ChecksForward() {
+			report.AddIncompatibilities("forward", existing.Version,
+				sr.checkForwardCompatibility(existing.Schema, newSchema))
+		}
 	}
 
-	// This is a simplified check. Real implementation would:
-	// - Check field additions/removals
-	// - Verify default values
-	// - Check type promotions
-	// - Validate enum symbol additions
-	
-	fmt.Printf("⚠ Forward compatibility check passed (simplified)\n")
-	return nil
+	report.Compatible = len(report.Incompatibilities) == 0
+	return report
 }
 
-// checkBackwardCompatibility checks if old schema can read data written with new schema
-func (sr *SchemaRegistry) checkBackwardCompatibility(oldSchema, newSchema avro.Schema) error {
-	// Simplified compatibility check
-	if oldSchema.Type() != newSchema.Type() {
-		return fmt.Errorf("schema types don't match: %s vs %s", oldSchema.Type(), newSchema.Type())
-	}
-
-	// Check if schemas are identical (simplified check)
-	if oldSchema.String() == newSchema.String() {
-		return nil
-	}
+// checkForwardCompatibility checks if old schema can read data written with new schema
+func (sr *SchemaRegistry) checkForwardCompatibility(oldSchema, newSchema avro.Schema) []Incompatibility {
+	return CheckReaderWriterCompatibility(oldSchema, newSchema)
+}
 
-	// This is a simplified check. Real implementation would:
-	// - Ensure no required fields were added
-	// - Check that removed fields had defaults
-	// - Verify enum symbol compatibility
-	// - Check type compatibility
-	
-	fmt.Printf("⚠ Backward compatibility check passed (simplified)\n")
-	return nil
+// checkBackwardCompatibility checks if new schema can read data written with old schema
+func (sr *SchemaRegistry) checkBackwardCompatibility(oldSchema, newSchema avro.Schema) []Incompatibility {
+	return CheckReaderWriterCompatibility(newSchema, oldSchema)
 }
 
 // GetStats returns registry statistics
@@ -299,9 +575,11 @@ func (sr *SchemaRegistry) GetStats() map[string]interface{} {
 	stats := map[string]interface{}{
 		"total_schemas":     len(sr.schemas),
 		"total_subjects":    len(sr.subjectSchemas),
-		"next_schema_id":    sr.nextSchemaID,
 		"subjects":          sr.ListSubjects(),
 	}
+	if monotonic, ok := sr.ids.(*MonotonicIDGenerator); ok {
+		stats["next_schema_id"] = monotonic.Peek()
+	}
 
 	subjectStats := make(map[string]int)
 	for subject, schemaIDs := range sr.subjectSchemas {
@@ -0,0 +1,327 @@
+package avro
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/pkg/cache"
+)
+
+func TestRegistryCachesLookups(t *testing.T) {
+	shared := cache.Instrument(cache.NewMemoryCache(), "avro-schemas", nil)
+	registry := NewSchemaRegistry().WithCache(shared, "items", time.Minute)
+
+	id, err := registry.RegisterSchema("item", compatBaseSchema)
+	if err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+
+	// The registry resolves its own schemas without going through the cache
+	for i := 0; i < 3; i++ {
+		metadata, err := registry.GetSchema(id)
+		if err != nil {
+			t.Fatalf("Failed to get schema: %v", err)
+		}
+		if metadata.Schema == nil || metadata.Subject != "item" {
+			t.Fatalf("Unexpected metadata: %+v", metadata)
+		}
+	}
+	if stats := shared.Stats(); stats.Hits != 0 || stats.Misses != 0 {
+		t.Errorf("Expected no cache lookups, got %+v", stats)
+	}
+
+	// A registry sharing the cache and namespace sees schemas it never registered, with the schema re-parsed
+	replica := NewSchemaRegistry().WithCache(shared, "items", time.Minute)
+	metadata, err := replica.GetSchema(id)
+	if err != nil {
+		t.Fatalf("Expected cached schema on replica: %v", err)
+	}
+	latest, err := registry.GetLatestSchema("item")
+	if err != nil || latest.Version != 1 {
+		t.Fatalf("Expected version 1, got %+v (%v)", latest, err)
+	}
+	if metadata.Schema == nil || metadata.Version != 1 || metadata.Fingerprint != latest.Fingerprint {
+		t.Errorf("Unexpected cached metadata: %+v", metadata)
+	}
+	if cached, err := replica.GetLatestSchema("item"); err != nil || cached.Version != 1 {
+		t.Fatalf("Expected cached version 1 on replica, got %+v (%v)", cached, err)
+	}
+
+	// Registering a new version replaces the cached latest schema
+	evolved := `{
+		"type": "record",
+		"name": "Item",
+		"namespace": "com.example.test",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": "string"},
+			{"name": "color", "type": "string", "default": "red"}
+		]
+	}`
+	if _, err := registry.RegisterSchema("item", evolved); err != nil {
+		t.Fatalf("Failed to register evolved schema: %v", err)
+	}
+	if current, err := replica.GetLatestSchema("item"); err != nil || current.Version != 2 {
+		t.Fatalf("Expected version 2 on replica after registering, got %+v (%v)", current, err)
+	}
+
+	// A registry in another namespace assigns the same ID without either seeing the other's schema
+	other := NewSchemaRegistry().WithCache(shared, "orders", time.Minute)
+	if _, err := other.GetSchema(id); !errors.IsType(err, errors.ErrorTypeNotFound) {
+		t.Errorf("Expected schema %d to be unknown in another namespace, got %v", id, err)
+	}
+	otherID, err := other.RegisterSchema("item", `{"type":"record","name":"Other","fields":[{"name":"x","type":"long"}]}`)
+	if err != nil || otherID != id {
+		t.Fatalf("Expected other schema under ID %d, got %d (%v)", id, otherID, err)
+	}
+	if metadata, err := registry.GetSchema(id); err != nil || metadata.Fingerprint != latest.Fingerprint {
+		t.Errorf("Expected the registry's own schema %d, got %+v (%v)", id, metadata, err)
+	}
+	if metadata, err := replica.GetSchema(id); err != nil || metadata.Fingerprint != latest.Fingerprint {
+		t.Errorf("Expected the replica to keep its namespace's schema %d, got %+v (%v)", id, metadata, err)
+	}
+
+	t.Log("✓ Registries resolve their own schemas first and share the rest through a namespaced cache")
+}
+
+func TestRegistryPersistentCache(t *testing.T) {
+	testDir := "tmp/test_registry_cache"
+	defer os.RemoveAll(testDir)
+	path := filepath.Join(testDir, "schemas.json")
+
+	// A first process registers and looks up a schema, then exits
+	first, err := cache.OpenFileCache(path)
+	if err != nil {
+		t.Fatalf("Failed to open cache: %v", err)
+	}
+	registry := NewSchemaRegistry().WithCache(first, "items", 0)
+	id, err := registry.RegisterSchema("item", compatBaseSchema)
+	if err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+	if err := first.Close(); err != nil {
+		t.Fatalf("Failed to save cache: %v", err)
+	}
+
+	// A later process registers the same schema and keeps the persisted entries
+	second, err := cache.OpenFileCache(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen cache: %v", err)
+	}
+	restarted := NewSchemaRegistry().WithCache(second, "items", 0)
+	if _, err := restarted.RegisterSchema("item", compatBaseSchema); err != nil {
+		t.Fatalf("Failed to register schema again: %v", err)
+	}
+	if dropped := second.Prune(restarted.ValidCacheEntry); dropped != 0 {
+		t.Errorf("Expected no stale entries, dropped %d", dropped)
+	}
+
+	// Replicas resolve the schema from disk without registering it, but cannot vouch for it
+	replica := NewSchemaRegistry().WithCache(second, "items", 0)
+	metadata, err := replica.GetSchema(id)
+	if err != nil || metadata.Schema == nil || metadata.Subject != "item" {
+		t.Fatalf("Expected schema %d from the persisted cache, got %+v (%v)", id, metadata, err)
+	}
+	key := "avro:schema:items:id:" + strconv.Itoa(id)
+	persisted, err := second.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Failed to read persisted entry: %v", err)
+	}
+	if replica.ValidCacheEntry(key, persisted) {
+		t.Error("Expected an entry the registry does not hold to be rejected")
+	}
+	if replica.ValidCacheEntry("avro:schema:items:latest:item", persisted) {
+		t.Error("Expected a subject the registry does not hold to be rejected")
+	}
+
+	// A registry that assigned the same ID to another schema makes the entries stale
+	other := NewSchemaRegistry()
+	if _, err := other.RegisterSchema("item", `{"type":"record","name":"Other","fields":[{"name":"x","type":"long"}]}`); err != nil {
+		t.Fatalf("Failed to register other schema: %v", err)
+	}
+	if dropped := second.Prune(other.ValidCacheEntry); dropped != 0 {
+		t.Errorf("Expected entries of another namespace to be kept, dropped %d", dropped)
+	}
+	other.WithCache(second, "items", 0)
+	if dropped := second.Prune(other.ValidCacheEntry); dropped != 2 {
+		t.Errorf("Expected both entries to be stale, dropped %d", dropped)
+	}
+
+	// Entries edited on disk no longer match their fingerprint
+	tampered := []byte(`{"id":1,"subject":"item","schema":"\"string\"","fingerprint":"00"}`)
+	if restarted.ValidCacheEntry("avro:schema:items:id:1", tampered) {
+		t.Error("Expected a fingerprint mismatch to be rejected")
+	}
+	if !restarted.ValidCacheEntry("jsonschema:user", []byte("{}")) {
+		t.Error("Expected keys of other caches to be kept")
+	}
+
+	t.Log("✓ Persisted schema cache is reused across processes and pruned when stale")
+}
+
+func TestRegistryDeletion(t *testing.T) {
+	shared := cache.NewMemoryCache()
+	registry := NewSchemaRegistry().WithCache(shared, "items", time.Minute)
+	evolved := `{"type":"record","name":"Item","namespace":"com.example.test","fields":[
+		{"name":"id","type":"int"},
+		{"name":"name","type":"string"},
+		{"name":"color","type":"string","default":"red"}]}`
+
+	v1, err := registry.RegisterSchema("item", compatBaseSchema)
+	if err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+	v2, err := registry.RegisterSchema("item", evolved)
+	if err != nil {
+		t.Fatalf("Failed to register evolved schema: %v", err)
+	}
+	if _, err := registry.GetLatestSchema("item"); err != nil {
+		t.Fatalf("Failed to get latest schema: %v", err)
+	}
+
+	// Permanent deletion requires a soft delete first
+	if _, err := registry.DeleteSchemaVersion("item", 2, true); !errors.IsType(err, errors.ErrorTypeConflict) {
+		t.Errorf("Expected a conflict deleting a live version permanently, got %v", err)
+	}
+	if version, err := registry.DeleteSchemaVersion("item", 2, false); err != nil || version != 2 {
+		t.Fatalf("Expected version 2 to be soft deleted, got %d (%v)", version, err)
+	}
+	latest, err := registry.GetLatestSchema("item")
+	if err != nil || latest.Version != 1 {
+		t.Errorf("Expected version 1 to be the latest again, got %+v (%v)", latest, err)
+	}
+	if _, err := registry.GetSchemaVersion("item", 2); err == nil {
+		t.Error("Expected a soft-deleted version to be hidden")
+	}
+	if metadata, err := registry.GetSchema(v2); err != nil || !metadata.Deleted {
+		t.Errorf("Expected a soft-deleted schema to resolve by ID, got %+v (%v)", metadata, err)
+	}
+	v2Key := "avro:schema:items:id:" + strconv.Itoa(v2)
+	if _, err := shared.Get(context.Background(), v2Key); !cache.IsMiss(err) {
+		t.Errorf("Expected the soft-deleted schema to leave the cache, got %v", err)
+	}
+
+	// Registering the schema again restores it under the same ID
+	if id, err := registry.RegisterSchema("item", evolved); err != nil || id != v2 {
+		t.Fatalf("Expected schema %d to be restored, got %d (%v)", v2, id, err)
+	}
+	if versions, _ := registry.ListSchemaVersions("item"); len(versions) != 2 {
+		t.Errorf("Expected both versions after restoring, got %v", versions)
+	}
+	if _, err := shared.Get(context.Background(), v2Key); err != nil {
+		t.Errorf("Expected the restored schema to be cached again: %v", err)
+	}
+
+	versions, err := registry.DeleteSubject("item", false)
+	if err != nil || len(versions) != 2 {
+		t.Fatalf("Expected both versions to be soft deleted, got %v (%v)", versions, err)
+	}
+	if len(registry.ListSubjects()) != 0 {
+		t.Errorf("Expected a soft-deleted subject to be hidden, got %v", registry.ListSubjects())
+	}
+	if _, err := registry.DeleteSubject("item", false); !errors.IsType(err, errors.ErrorTypeNotFound) {
+		t.Errorf("Expected soft deleting twice to fail, got %v", err)
+	}
+	if _, err := registry.DeleteSubject("item", true); err != nil {
+		t.Fatalf("Failed to delete subject permanently: %v", err)
+	}
+	if _, err := registry.GetSchema(v1); err == nil {
+		t.Error("Expected a permanently deleted schema to be gone")
+	}
+
+	// Versions start over for a permanently deleted subject, while IDs are not reused
+	id, err := registry.RegisterSchema("item", compatBaseSchema)
+	if err != nil {
+		t.Fatalf("Failed to register schema again: %v", err)
+	}
+	if metadata, _ := registry.GetSchema(id); id == v1 || metadata.Version != 1 {
+		t.Errorf("Expected a new ID at version 1, got ID %d version %d", id, metadata.Version)
+	}
+
+	t.Log("✓ Subjects and versions are soft deleted, restored and permanently deleted")
+}
+
+func TestRegistryReadOnlyMode(t *testing.T) {
+	registry := NewSchemaRegistry()
+	if _, err := registry.RegisterSchema("item", compatBaseSchema); err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+	if err := registry.SetMode("item", "IMPORT"); err == nil {
+		t.Error("Expected an unknown mode to be rejected")
+	}
+	if err := registry.SetMode("item", ModeReadOnly); err != nil {
+		t.Fatalf("Failed to set mode: %v", err)
+	}
+
+	evolved := `{"type":"record","name":"Item","namespace":"com.example.test","fields":[
+		{"name":"id","type":"int"},
+		{"name":"name","type":"string"},
+		{"name":"color","type":"string","default":"red"}]}`
+	if _, err := registry.RegisterSchema("item", evolved); !errors.IsType(err, errors.ErrorTypeForbidden) {
+		t.Errorf("Expected registering in read-only mode to be forbidden, got %v", err)
+	}
+	if _, err := registry.DeleteSubject("item", false); !errors.IsType(err, errors.ErrorTypeForbidden) {
+		t.Errorf("Expected deleting in read-only mode to be forbidden, got %v", err)
+	}
+	if _, err := registry.GetLatestSchema("item"); err != nil {
+		t.Errorf("Expected lookups to work in read-only mode, got %v", err)
+	}
+	if _, err := registry.RegisterSchema("other", evolved); err != nil {
+		t.Errorf("Expected other subjects to stay writable, got %v", err)
+	}
+
+	registry.SetMode("item", ModeReadWrite)
+	if _, err := registry.RegisterSchema("item", evolved); err != nil || registry.GetMode("item") != ModeReadWrite {
+		t.Errorf("Expected registering to work again, got %v", err)
+	}
+
+	t.Log("✓ Read-only subjects reject changes but serve lookups")
+}
+
+func TestRegistryIDGenerators(t *testing.T) {
+	other := `{"type":"record","name":"Other","fields":[{"name":"x","type":"long"}]}`
+
+	// Hash-derived IDs do not depend on registration order
+	first := NewSchemaRegistry().WithIDGenerator(HashIDGenerator{})
+	second := NewSchemaRegistry().WithIDGenerator(HashIDGenerator{})
+	itemID, err := first.RegisterSchema("item", compatBaseSchema)
+	if err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+	otherID, _ := first.RegisterSchema("other", other)
+	if id, _ := second.RegisterSchema("other", other); id != otherID {
+		t.Errorf("Expected ID %d on the second registry, got %d", otherID, id)
+	}
+	if id, _ := second.RegisterSchema("item", compatBaseSchema); id != itemID {
+		t.Errorf("Expected ID %d on the second registry, got %d", itemID, id)
+	}
+	if id, _ := second.RegisterSchema("item-copy", compatBaseSchema); id == itemID {
+		t.Error("Expected the same schema under another subject to get another ID")
+	}
+
+	monotonic := NewSchemaRegistry().WithIDGenerator(NewMonotonicIDGenerator(100))
+	if id, _ := monotonic.RegisterSchema("item", compatBaseSchema); id != 100 {
+		t.Errorf("Expected the first monotonic ID to be 100, got %d", id)
+	}
+	if next := monotonic.GetStats()["next_schema_id"]; next != 101 {
+		t.Errorf("Expected next ID 101 in stats, got %v", next)
+	}
+
+	// An external allocator that hands out a used ID is rejected
+	external := NewSchemaRegistry().WithIDGenerator(IDGeneratorFunc(func(subject, fingerprint string) (int, error) {
+		return 7, nil
+	}))
+	if id, err := external.RegisterSchema("item", compatBaseSchema); err != nil || id != 7 {
+		t.Fatalf("Expected the external ID 7, got %d (%v)", id, err)
+	}
+	if _, err := external.RegisterSchema("other", other); !errors.IsType(err, errors.ErrorTypeConflict) {
+		t.Errorf("Expected a conflict for a reused ID, got %v", err)
+	}
+
+	t.Log("✓ Monotonic, hash-derived and external ID strategies assign schema IDs")
+}
@@ -0,0 +1,54 @@
+package avro
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hamba/avro/v2"
+)
+
+// DeserializeWithSchemas decodes binary data written with writerSchema into the shape of readerSchema,
+// applying Avro schema resolution: reader fields missing from the writer get their defaults and
+// writer fields unknown to the reader are skipped
+func (m *Manager) DeserializeWithSchemas(writerSchema, readerSchema avro.Schema, data []byte) (map[string]interface{}, error) {
+	return decodeWithSchemas(writerSchema, readerSchema, data)
+}
+
+// DeserializeUserWithWriterSchema decodes user data written with another version of the user schema
+// into the current User struct
+func (m *Manager) DeserializeUserWithWriterSchema(writerSchema avro.Schema, data []byte) (User, error) {
+	result, err := decodeWithSchemas(writerSchema, m.userSchema, data)
+	if err != nil {
+		return User{}, err
+	}
+
+	return m.avroMapToUser(result)
+}
+
+// decodeWithSchemas resolves the writer schema against the reader schema and decodes data
+func decodeWithSchemas(writerSchema, readerSchema avro.Schema, data []byte) (map[string]interface{}, error) {
+	if incompatibilities := CheckReaderWriterCompatibility(readerSchema, writerSchema); len(incompatibilities) > 0 {
+		messages := make([]string, len(incompatibilities))
+		for i, inc := range incompatibilities {
+			messages[i] = inc.String()
+		}
+		return nil, fmt.Errorf("reader schema cannot resolve writer schema: %s", strings.Join(messages, "; "))
+	}
+
+	resolved, err := avro.NewSchemaCompatibility().Resolve(readerSchema, writerSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve schemas: %w", err)
+	}
+
+	var result interface{}
+	if err := decodeAPI.Unmarshal(resolved, data, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode with resolved schema: %w", err)
+	}
+
+	record, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected record, got %T", result)
+	}
+
+	return record, nil
+}
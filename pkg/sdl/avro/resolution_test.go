@@ -0,0 +1,71 @@
+package avro
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hamba/avro/v2"
+)
+
+func TestDeserializeWithSchemas(t *testing.T) {
+	manager, err := NewManager("tmp/test_resolution")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer os.RemoveAll("tmp/test_resolution")
+
+	evolution, err := NewEvolutionManager("tmp/test_resolution")
+	if err != nil {
+		t.Fatalf("Failed to create evolution manager: %v", err)
+	}
+	userV2 := evolution.userV2
+
+	user := manager.CreateSampleUsers(1)[0]
+	v1Data, err := manager.SerializeUserBinary(user)
+	if err != nil {
+		t.Fatalf("Failed to serialize user: %v", err)
+	}
+
+	// Old data read with the newer schema picks up defaults
+	record, err := manager.DeserializeWithSchemas(manager.GetUserSchema(), userV2, v1Data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize v1 data with v2 schema: %v", err)
+	}
+	if record["lastLoginAt"] != nil {
+		t.Errorf("Expected lastLoginAt default nil, got %v", record["lastLoginAt"])
+	}
+	profile := record["profile"].(map[string]interface{})["com.example.avro.Profile"].(map[string]interface{})
+	if profile["preferredLanguage"] != "en" {
+		t.Errorf("Expected preferredLanguage default 'en', got %v", profile["preferredLanguage"])
+	}
+
+	// Newer data read into the current struct skips unknown fields
+	v2Record := manager.userToAvroMap(user)
+	v2Profile := v2Record["profile"].(map[string]interface{})["com.example.avro.Profile"].(map[string]interface{})
+	v2Profile["dateOfBirth"] = nil
+	v2Profile["preferredLanguage"] = "fr"
+	v2Record["lastLoginAt"] = map[string]interface{}{"long.timestamp-millis": time.Now()}
+
+	v2Data, err := avro.Marshal(userV2, v2Record)
+	if err != nil {
+		t.Fatalf("Failed to serialize v2 data: %v", err)
+	}
+
+	decoded, err := manager.DeserializeUserWithWriterSchema(userV2, v2Data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize v2 data into User: %v", err)
+	}
+	if decoded.ID != user.ID || decoded.Email != user.Email {
+		t.Errorf("User mismatch: expected %d/%s, got %d/%s", user.ID, user.Email, decoded.ID, decoded.Email)
+	}
+
+	// Incompatible schemas are rejected up front
+	incompatible := avro.MustParse(`{"type": "record", "name": "User", "namespace": "com.example.avro", "fields": [
+		{"name": "id", "type": "long"}, {"name": "required", "type": "string"}]}`)
+	if _, err := manager.DeserializeWithSchemas(manager.GetUserSchema(), incompatible, v1Data); err == nil {
+		t.Error("Expected error for incompatible reader schema")
+	}
+
+	t.Log("✓ Reader/writer schema resolution works in both directions")
+}
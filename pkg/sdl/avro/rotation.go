@@ -0,0 +1,42 @@
+package avro
+
+import (
+	"io"
+
+	"go-transport-prac/pkg/sdl/rotation"
+)
+
+// userSegment adapts UserStreamWriter to a rotation segment
+type userSegment struct {
+	writer *UserStreamWriter
+}
+
+func (s *userSegment) Write(users []User) error {
+	for _, user := range users {
+		if err := s.writer.Write(user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *userSegment) Close() error {
+	return nil
+}
+
+// NewUserRotatingWriter creates a writer that rolls binary Avro user files by time window and/or size,
+// producing files such as users-2024-06-01T10.avro in the manager's base directory
+func (m *Manager) NewUserRotatingWriter(cfg rotation.Config) (*rotation.Writer[User], error) {
+	if cfg.Dir == "" {
+		cfg.Dir = m.baseDir
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = "users"
+	}
+	if cfg.Extension == "" {
+		cfg.Extension = ".avro"
+	}
+	return rotation.NewWriter(cfg, func(w io.Writer) (rotation.SegmentWriter[User], error) {
+		return &userSegment{writer: m.NewUserStreamWriter(w)}, nil
+	})
+}
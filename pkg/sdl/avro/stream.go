@@ -0,0 +1,136 @@
+package avro
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/hamba/avro/v2"
+)
+
+// UserStreamWriter encodes users one at a time to an underlying writer
+type UserStreamWriter struct {
+	manager *Manager
+	encoder *avro.Encoder
+	count   int
+}
+
+// NewUserStreamWriter creates a stream writer that encodes users as binary Avro records to w
+func (m *Manager) NewUserStreamWriter(w io.Writer) *UserStreamWriter {
+	return &UserStreamWriter{
+		manager: m,
+		encoder: avro.NewEncoderForSchema(m.userSchema, w),
+	}
+}
+
+// Write encodes a single user
+func (sw *UserStreamWriter) Write(user User) error {
+	if err := sw.encoder.Encode(sw.manager.userToAvroMap(user)); err != nil {
+		return fmt.Errorf("failed to encode user %d: %w", user.ID, err)
+	}
+	sw.count++
+	return nil
+}
+
+// Count returns the number of users written so far
+func (sw *UserStreamWriter) Count() int {
+	return sw.count
+}
+
+// UserStreamReader decodes users one at a time from an underlying reader
+type UserStreamReader struct {
+	manager *Manager
+	decoder *avro.Decoder
+	current User
+	err     error
+}
+
+// NewUserStreamReader creates a stream reader over binary Avro user records in r
+func (m *Manager) NewUserStreamReader(r io.Reader) *UserStreamReader {
+	return &UserStreamReader{
+		manager: m,
+		decoder: decodeAPI.NewDecoder(m.userSchema, r),
+	}
+}
+
+// Next advances to the next user, returning false at end of stream or on error
+func (sr *UserStreamReader) Next() bool {
+	if sr.err != nil {
+		return false
+	}
+
+	var result interface{}
+	if err := sr.decoder.Decode(&result); err != nil {
+		if err != io.EOF {
+			sr.err = fmt.Errorf("failed to decode user: %w", err)
+		}
+		return false
+	}
+
+	user, err := sr.manager.avroMapToUser(result)
+	if err != nil {
+		sr.err = fmt.Errorf("failed to convert avro map to user: %w", err)
+		return false
+	}
+
+	sr.current = user
+	return true
+}
+
+// User returns the user decoded by the last call to Next
+func (sr *UserStreamReader) User() User {
+	return sr.current
+}
+
+// Err returns the first error encountered, or nil at a clean end of stream
+func (sr *UserStreamReader) Err() error {
+	return sr.err
+}
+
+// EncodeChan encodes users received on the channel to w until it is closed or ctx is done.
+// Any number of producers may send on users; it returns the number of users written.
+func (m *Manager) EncodeChan(ctx context.Context, w io.Writer, users <-chan User) (int, error) {
+	writer := m.NewUserStreamWriter(w)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return writer.Count(), ctx.Err()
+		case user, ok := <-users:
+			if !ok {
+				return writer.Count(), nil
+			}
+			if err := writer.Write(user); err != nil {
+				return writer.Count(), err
+			}
+		}
+	}
+}
+
+// DecodeChan decodes users from r in a background goroutine and sends them on the returned channel.
+// Both channels are closed when the stream ends; at most one error is sent.
+func (m *Manager) DecodeChan(ctx context.Context, r io.Reader) (<-chan User, <-chan error) {
+	users := make(chan User)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(users)
+		defer close(errs)
+
+		reader := m.NewUserStreamReader(r)
+		for reader.Next() {
+			select {
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			case users <- reader.User():
+			}
+		}
+
+		if err := reader.Err(); err != nil {
+			errs <- err
+		}
+	}()
+
+	return users, errs
+}
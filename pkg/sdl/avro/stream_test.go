@@ -0,0 +1,94 @@
+package avro
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestUserStreamRoundTrip(t *testing.T) {
+	manager, err := NewManager("tmp/test_stream")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	users := manager.CreateSampleUsers(50)
+
+	var buf bytes.Buffer
+	writer := manager.NewUserStreamWriter(&buf)
+	for _, user := range users {
+		if err := writer.Write(user); err != nil {
+			t.Fatalf("Failed to write user: %v", err)
+		}
+	}
+	if writer.Count() != len(users) {
+		t.Errorf("Expected count %d, got %d", len(users), writer.Count())
+	}
+
+	reader := manager.NewUserStreamReader(&buf)
+	var count int
+	for reader.Next() {
+		if reader.User().ID != users[count].ID {
+			t.Errorf("User %d mismatch: expected ID %d, got %d", count, users[count].ID, reader.User().ID)
+		}
+		count++
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatalf("Stream reader failed: %v", err)
+	}
+	if count != len(users) {
+		t.Errorf("Expected %d users, got %d", len(users), count)
+	}
+
+	t.Log("✓ Stream writer/reader round-trip successful")
+}
+
+func TestUserStreamChannels(t *testing.T) {
+	manager, err := NewManager("tmp/test_stream_chan")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	users := manager.CreateSampleUsers(40)
+	input := make(chan User)
+
+	// Several concurrent producers feed one encoder
+	var wg sync.WaitGroup
+	for p := 0; p < 4; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := p; i < len(users); i += 4 {
+				input <- users[i]
+			}
+		}(p)
+	}
+	go func() {
+		wg.Wait()
+		close(input)
+	}()
+
+	var buf bytes.Buffer
+	written, err := manager.EncodeChan(context.Background(), &buf, input)
+	if err != nil {
+		t.Fatalf("EncodeChan failed: %v", err)
+	}
+	if written != len(users) {
+		t.Fatalf("Expected %d users written, got %d", len(users), written)
+	}
+
+	decoded, errs := manager.DecodeChan(context.Background(), &buf)
+	seen := make(map[int64]bool)
+	for user := range decoded {
+		seen[user.ID] = true
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("DecodeChan failed: %v", err)
+	}
+	if len(seen) != len(users) {
+		t.Errorf("Expected %d distinct users, got %d", len(users), len(seen))
+	}
+
+	t.Log("✓ Channel pipeline round-trip successful")
+}
@@ -0,0 +1,47 @@
+package avro
+
+import (
+	"context"
+	"io"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"go-transport-prac/internal/tracing"
+)
+
+// startSpan starts a span for a whole-file write or read of entity records
+func startSpan(ctx context.Context, name, entity, filename string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, name, "avro", entity, tracing.File(filename))
+}
+
+// byteCounter counts the bytes written to or read from a file
+type byteCounter struct {
+	w io.Writer
+	r io.Reader
+	n int64
+}
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *byteCounter) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// countedFile records the bytes read from a file on the span in ctx once it
+// is closed
+type countedFile struct {
+	*byteCounter
+	ctx    context.Context
+	closer io.Closer
+}
+
+func (f countedFile) Close() error {
+	tracing.SetBytes(f.ctx, f.n)
+	return f.closer.Close()
+}
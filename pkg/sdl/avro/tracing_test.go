@@ -0,0 +1,72 @@
+package avro
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"go-transport-prac/internal/tracing"
+)
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	testDir := "tmp/test_tracing"
+	manager, err := NewManager(testDir)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	defer os.RemoveAll(testDir)
+
+	ctx := context.Background()
+	users := manager.CreateSampleUsers(20)
+	if err := manager.WriteUsersToFileContext(ctx, "users.avro", users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	if _, err := manager.ReadUsersFromFileContext(ctx, "users.avro"); err != nil {
+		t.Fatalf("Failed to read users: %v", err)
+	}
+	data, err := manager.SerializeUserBinaryContext(ctx, users[0])
+	if err != nil {
+		t.Fatalf("Failed to serialize user: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(spans))
+	}
+	stat, err := os.Stat(testDir + "/users.avro")
+	if err != nil {
+		t.Fatalf("Failed to stat file: %v", err)
+	}
+	want := []struct {
+		name  string
+		bytes int64
+		rows  int64
+	}{
+		{"avro.write", stat.Size(), 20},
+		{"avro.read", stat.Size(), 20},
+		{"avro.serialize", int64(len(data)), 1},
+	}
+	for i, w := range want {
+		values := map[string]int64{}
+		for _, kv := range spans[i].Attributes {
+			if kv.Key == tracing.AttrBytes || kv.Key == tracing.AttrRows {
+				values[string(kv.Key)] = kv.Value.AsInt64()
+			}
+		}
+		if spans[i].Name != w.name || values[string(tracing.AttrBytes)] != w.bytes || values[string(tracing.AttrRows)] != w.rows {
+			t.Errorf("Expected %s with %d bytes and %d rows, got %s %v", w.name, w.bytes, w.rows, spans[i].Name, spans[i].Attributes)
+		}
+	}
+
+	t.Log("✓ File and serialization calls are traced with size and row count")
+}
@@ -0,0 +1,457 @@
+package benchmark
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"time"
+
+	parquetgo "github.com/segmentio/parquet-go"
+	"google.golang.org/protobuf/encoding/protodelim"
+
+	"go-transport-prac/internal/profiling"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/cbor"
+	"go-transport-prac/pkg/sdl/converter"
+	"go-transport-prac/pkg/sdl/flatbuffers"
+	fbmodels "go-transport-prac/pkg/sdl/flatbuffers/gen/models"
+	"go-transport-prac/pkg/sdl/msgpack"
+	"go-transport-prac/pkg/sdl/parquet"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+// Formats compared by CompareFormats
+const (
+	FormatAvro     = "avro"
+	FormatProtobuf = "protobuf"
+	FormatParquet  = "parquet"
+	FormatJSON     = "json"
+	FormatMsgpack  = "msgpack"
+	FormatCBOR     = "cbor"
+	// FormatFlatBuffers unpacks every buffer into the model
+	FormatFlatBuffers = "flatbuffers"
+	// FormatFlatBuffersView reads one field per buffer in place, without unpacking
+	FormatFlatBuffersView = "flatbuffers-view"
+)
+
+// AllFormats lists every format in the order results are reported
+var AllFormats = []string{
+	FormatAvro, FormatProtobuf, FormatParquet, FormatJSON, FormatMsgpack, FormatCBOR,
+	FormatFlatBuffers, FormatFlatBuffersView,
+}
+
+// FormatConfig controls a cross-format comparison
+type FormatConfig struct {
+	// Formats to compare; empty compares AllFormats
+	Formats []string `json:"formats"`
+	// Records is the number of users in the dataset
+	Records int `json:"records"`
+	// Iterations is the number of timed serializations and deserializations per format
+	Iterations int `json:"iterations"`
+	// Warmup iterations run first and are not measured
+	Warmup int `json:"warmup"`
+	// CPUProfile, when set, is the file a CPU profile of the comparison is written to
+	CPUProfile string `json:"cpuProfile,omitempty"`
+}
+
+// DefaultFormatConfig returns a 1000-user dataset timed over 50 iterations
+func DefaultFormatConfig() FormatConfig {
+	return FormatConfig{
+		Formats:    AllFormats,
+		Records:    1000,
+		Iterations: 50,
+		Warmup:     5,
+	}
+}
+
+// FormatResult is the cost of one format for the whole dataset. Latencies are
+// per dataset; allocations are averaged over the iterations
+type FormatResult struct {
+	Format                string       `json:"format"`
+	Records               int          `json:"records"`
+	PayloadBytes          int          `json:"payloadBytes"`
+	Serialize             LatencyStats `json:"serialize"`
+	Deserialize           LatencyStats `json:"deserialize"`
+	SerializeAllocs       uint64       `json:"serializeAllocs"`
+	SerializeAllocBytes   uint64       `json:"serializeAllocBytes"`
+	DeserializeAllocs     uint64       `json:"deserializeAllocs"`
+	DeserializeAllocBytes uint64       `json:"deserializeAllocBytes"`
+}
+
+// BytesPerRecord returns the average encoded size of a user
+func (r FormatResult) BytesPerRecord() float64 {
+	if r.Records == 0 {
+		return 0
+	}
+	return float64(r.PayloadBytes) / float64(r.Records)
+}
+
+// FormatReport is the outcome of CompareFormats
+type FormatReport struct {
+	Config      FormatConfig   `json:"config"`
+	Environment Environment    `json:"environment"`
+	Results     []FormatResult `json:"results"`
+}
+
+// Result returns the result of the named format
+func (r *FormatReport) Result(format string) (FormatResult, bool) {
+	for _, res := range r.Results {
+		if res.Format == format {
+			return res, true
+		}
+	}
+	return FormatResult{}, false
+}
+
+// formatCodec encodes the whole dataset to one payload and decodes it,
+// returning the number of users decoded. Conversions from the Avro model to
+// a format's own types happen before timing starts
+type formatCodec struct {
+	encode func() ([]byte, error)
+	decode func([]byte) (int, error)
+}
+
+// CompareFormats runs the same users through every configured format, timing
+// each serialization and deserialization of the full dataset
+func CompareFormats(users []avro.User, config FormatConfig) (*FormatReport, error) {
+	if len(users) == 0 {
+		return nil, fmt.Errorf("at least one user is required")
+	}
+	if config.Iterations <= 0 {
+		return nil, fmt.Errorf("iterations must be positive")
+	}
+	if len(config.Formats) == 0 {
+		config.Formats = AllFormats
+	}
+	config.Records = len(users)
+
+	report := &FormatReport{Config: config, Environment: CurrentEnvironment()}
+	err := profiling.CaptureToFile(config.CPUProfile, func() error {
+		for _, format := range config.Formats {
+			codec, err := newFormatCodec(format, users)
+			if err != nil {
+				return err
+			}
+			result, err := measureFormat(format, codec, len(users), config)
+			if err != nil {
+				return fmt.Errorf("%s: %w", format, err)
+			}
+			report.Results = append(report.Results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// measureFormat times every iteration and counts allocations over each phase
+func measureFormat(format string, codec formatCodec, records int, config FormatConfig) (FormatResult, error) {
+	payload, err := codec.encode()
+	if err != nil {
+		return FormatResult{}, fmt.Errorf("failed to serialize: %w", err)
+	}
+	if n, err := codec.decode(payload); err != nil || n != records {
+		return FormatResult{}, fmt.Errorf("round trip decoded %d of %d users: %v", n, records, err)
+	}
+	for i := 0; i < config.Warmup; i++ {
+		codec.encode()
+		codec.decode(payload)
+	}
+
+	result := FormatResult{Format: format, Records: records, PayloadBytes: len(payload)}
+
+	var errs int
+	latencies := make([]time.Duration, 0, config.Iterations)
+	allocs, allocBytes := allocated(func() {
+		for i := 0; i < config.Iterations; i++ {
+			start := time.Now()
+			_, err := codec.encode()
+			if err != nil {
+				errs++
+				continue
+			}
+			latencies = append(latencies, time.Since(start))
+		}
+	})
+	result.Serialize = latencyStats("serialize", latencies, errs)
+	result.SerializeAllocs = allocs / uint64(config.Iterations)
+	result.SerializeAllocBytes = allocBytes / uint64(config.Iterations)
+
+	errs = 0
+	latencies = latencies[:0]
+	allocs, allocBytes = allocated(func() {
+		for i := 0; i < config.Iterations; i++ {
+			start := time.Now()
+			if _, err := codec.decode(payload); err != nil {
+				errs++
+				continue
+			}
+			latencies = append(latencies, time.Since(start))
+		}
+	})
+	result.Deserialize = latencyStats("deserialize", latencies, errs)
+	result.DeserializeAllocs = allocs / uint64(config.Iterations)
+	result.DeserializeAllocBytes = allocBytes / uint64(config.Iterations)
+
+	return result, nil
+}
+
+// allocated returns the heap allocations fn made, in objects and bytes
+func allocated(fn func()) (uint64, uint64) {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	fn()
+	runtime.ReadMemStats(&after)
+	return after.Mallocs - before.Mallocs, after.TotalAlloc - before.TotalAlloc
+}
+
+func newFormatCodec(format string, users []avro.User) (formatCodec, error) {
+	switch format {
+	case FormatAvro:
+		return avroCodec(users)
+	case FormatProtobuf:
+		return protobufCodec(users)
+	case FormatParquet:
+		return parquetCodec(users), nil
+	case FormatJSON:
+		return jsonCodec(users), nil
+	case FormatMsgpack:
+		return msgpackCodec(users), nil
+	case FormatCBOR:
+		return cborCodec(users), nil
+	case FormatFlatBuffers:
+		return flatbuffersCodec(users, false), nil
+	case FormatFlatBuffersView:
+		return flatbuffersCodec(users, true), nil
+	}
+	return formatCodec{}, fmt.Errorf("unknown format %q", format)
+}
+
+// avroCodec writes binary Avro records back to back, as the stream writer does
+func avroCodec(users []avro.User) (formatCodec, error) {
+	manager, err := avro.NewManager("")
+	if err != nil {
+		return formatCodec{}, fmt.Errorf("failed to create avro manager: %w", err)
+	}
+	return formatCodec{
+		encode: func() ([]byte, error) {
+			var buf bytes.Buffer
+			writer := manager.NewUserStreamWriter(&buf)
+			for _, u := range users {
+				if err := writer.Write(u); err != nil {
+					return nil, err
+				}
+			}
+			return buf.Bytes(), nil
+		},
+		decode: func(data []byte) (int, error) {
+			reader := manager.NewUserStreamReader(bytes.NewReader(data))
+			n := 0
+			for reader.Next() {
+				n++
+			}
+			return n, reader.Err()
+		},
+	}, nil
+}
+
+// protobufCodec writes length-delimited user messages
+func protobufCodec(users []avro.User) (formatCodec, error) {
+	msgs := make([]*user.User, len(users))
+	for i, u := range users {
+		msg, err := converter.UserToProto(u)
+		if err != nil {
+			return formatCodec{}, err
+		}
+		msgs[i] = msg
+	}
+	return formatCodec{
+		encode: func() ([]byte, error) {
+			var buf bytes.Buffer
+			for _, msg := range msgs {
+				if _, err := protodelim.MarshalTo(&buf, msg); err != nil {
+					return nil, fmt.Errorf("failed to marshal user %d: %w", msg.GetId(), err)
+				}
+			}
+			return buf.Bytes(), nil
+		},
+		decode: func(data []byte) (int, error) {
+			reader := bufio.NewReader(bytes.NewReader(data))
+			n := 0
+			for {
+				if err := protodelim.UnmarshalFrom(reader, &user.User{}); err != nil {
+					if err == io.EOF {
+						return n, nil
+					}
+					return n, fmt.Errorf("failed to decode user %d: %w", n, err)
+				}
+				n++
+			}
+		},
+	}, nil
+}
+
+// parquetCodec writes an in-memory Parquet file
+func parquetCodec(users []avro.User) formatCodec {
+	rows := toParquetUsers(users)
+	return formatCodec{
+		encode: func() ([]byte, error) {
+			var buf bytes.Buffer
+			if err := parquetgo.Write(&buf, rows); err != nil {
+				return nil, err
+			}
+			return buf.Bytes(), nil
+		},
+		decode: func(data []byte) (int, error) {
+			decoded, err := parquetgo.Read[parquet.User](bytes.NewReader(data), int64(len(data)))
+			return len(decoded), err
+		},
+	}
+}
+
+// jsonCodec encodes the users as one JSON array
+func jsonCodec(users []avro.User) formatCodec {
+	return formatCodec{
+		encode: func() ([]byte, error) {
+			return json.Marshal(users)
+		},
+		decode: func(data []byte) (int, error) {
+			var decoded []avro.User
+			err := json.Unmarshal(data, &decoded)
+			return len(decoded), err
+		},
+	}
+}
+
+// msgpackCodec writes MessagePack user documents back to back
+func msgpackCodec(users []avro.User) formatCodec {
+	manager := msgpack.NewManager("")
+	return streamCodec(users, manager.EncodeUsers, manager.DecodeUsers)
+}
+
+// cborCodec writes canonical CBOR user data items back to back
+func cborCodec(users []avro.User) formatCodec {
+	manager := cbor.NewManager("").WithCanonical(true)
+	return streamCodec(users, manager.EncodeUsers, manager.DecodeUsers)
+}
+
+// flatbuffersCodec writes size-prefixed user buffers back to back. With view
+// set, decoding reads each email in place instead of unpacking the users
+func flatbuffersCodec(users []avro.User, view bool) formatCodec {
+	manager := flatbuffers.NewManager("")
+	codec := streamCodec(users, manager.EncodeUsers, manager.DecodeUsers)
+	if !view {
+		return codec
+	}
+	codec.decode = func(data []byte) (int, error) {
+		n := 0
+		err := manager.EachUser(data, func(u *fbmodels.User) error {
+			if len(u.Email()) == 0 {
+				return fmt.Errorf("user %d has no email", u.Id())
+			}
+			n++
+			return nil
+		})
+		return n, err
+	}
+	return codec
+}
+
+// streamCodec round-trips users through a manager's stream encoding
+func streamCodec(users []avro.User, encode func(io.Writer, []avro.User) error, decode func(io.Reader) ([]avro.User, error)) formatCodec {
+	return formatCodec{
+		encode: func() ([]byte, error) {
+			var buf bytes.Buffer
+			if err := encode(&buf, users); err != nil {
+				return nil, err
+			}
+			return buf.Bytes(), nil
+		},
+		decode: func(data []byte) (int, error) {
+			decoded, err := decode(bytes.NewReader(data))
+			return len(decoded), err
+		},
+	}
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *FormatReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// formatColumns are the columns of the CSV and markdown reports
+var formatColumns = []string{
+	"format", "records", "payload_bytes", "bytes_per_record",
+	"ser_p50_us", "ser_p95_us", "ser_p99_us", "ser_allocs", "ser_alloc_bytes",
+	"deser_p50_us", "deser_p95_us", "deser_p99_us", "deser_allocs", "deser_alloc_bytes",
+}
+
+// rows renders every result in the order of formatColumns
+func (r *FormatReport) rows() [][]string {
+	micros := func(d time.Duration) string {
+		return strconv.FormatFloat(float64(d)/float64(time.Microsecond), 'f', 1, 64)
+	}
+	rows := make([][]string, 0, len(r.Results))
+	for _, res := range r.Results {
+		rows = append(rows, []string{
+			res.Format,
+			strconv.Itoa(res.Records),
+			strconv.Itoa(res.PayloadBytes),
+			strconv.FormatFloat(res.BytesPerRecord(), 'f', 1, 64),
+			micros(res.Serialize.P50), micros(res.Serialize.P95), micros(res.Serialize.P99),
+			strconv.FormatUint(res.SerializeAllocs, 10), strconv.FormatUint(res.SerializeAllocBytes, 10),
+			micros(res.Deserialize.P50), micros(res.Deserialize.P95), micros(res.Deserialize.P99),
+			strconv.FormatUint(res.DeserializeAllocs, 10), strconv.FormatUint(res.DeserializeAllocBytes, 10),
+		})
+	}
+	return rows
+}
+
+// WriteCSV writes a header row and one row per format; latencies are in microseconds
+func (r *FormatReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(formatColumns); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(r.rows()); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+// WriteMarkdown writes the report as a markdown table; latencies are in microseconds
+func (r *FormatReport) WriteMarkdown(w io.Writer) error {
+	var buf bytes.Buffer
+	writeRow := func(cells []string) {
+		buf.WriteString("|")
+		for _, cell := range cells {
+			buf.WriteString(" " + cell + " |")
+		}
+		buf.WriteString("\n")
+	}
+
+	writeRow(formatColumns)
+	separator := make([]string, len(formatColumns))
+	for i := range separator {
+		separator[i] = "---"
+		if i > 0 {
+			separator[i] = "---:"
+		}
+	}
+	writeRow(separator)
+	for _, row := range r.rows() {
+		writeRow(row)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
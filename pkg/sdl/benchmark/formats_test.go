@@ -0,0 +1,140 @@
+package benchmark
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+func sampleUsers(t testing.TB, n int) []avro.User {
+	manager, err := avro.NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create avro manager: %v", err)
+	}
+	return manager.CreateSampleUsers(n)
+}
+
+func TestCompareFormats(t *testing.T) {
+	report, err := CompareFormats(sampleUsers(t, 50), FormatConfig{Iterations: 5, Warmup: 1})
+	if err != nil {
+		t.Fatalf("Failed to compare formats: %v", err)
+	}
+
+	if len(report.Results) != len(AllFormats) {
+		t.Fatalf("Expected %d results, got %d", len(AllFormats), len(report.Results))
+	}
+	for i, res := range report.Results {
+		if res.Format != AllFormats[i] {
+			t.Errorf("Expected %s at %d, got %s", AllFormats[i], i, res.Format)
+		}
+		if res.Records != 50 || res.PayloadBytes == 0 {
+			t.Errorf("%s: expected 50 records in a non-empty payload, got %+v", res.Format, res)
+		}
+		if res.Serialize.Count != 5 || res.Deserialize.Count != 5 || res.Serialize.Errors+res.Deserialize.Errors != 0 {
+			t.Errorf("%s: expected 5 successful iterations per phase, got %+v / %+v", res.Format, res.Serialize, res.Deserialize)
+		}
+		if res.Serialize.P50 <= 0 || res.Serialize.P50 > res.Serialize.P99 || res.DeserializeAllocs == 0 {
+			t.Errorf("%s: implausible measurements %+v", res.Format, res)
+		}
+	}
+
+	// The binary row formats are smaller than JSON for the same users
+	jsonResult, _ := report.Result(FormatJSON)
+	for _, format := range []string{FormatAvro, FormatProtobuf, FormatMsgpack, FormatCBOR} {
+		if res, _ := report.Result(format); res.PayloadBytes >= jsonResult.PayloadBytes {
+			t.Errorf("Expected %s (%d bytes) to be smaller than JSON (%d bytes)", format, res.PayloadBytes, jsonResult.PayloadBytes)
+		}
+	}
+
+	t.Log("✓ Every format round-trips the dataset and is measured")
+}
+
+func TestCompareFormatsSubset(t *testing.T) {
+	report, err := CompareFormats(sampleUsers(t, 5), FormatConfig{Formats: []string{FormatParquet}, Iterations: 1})
+	if err != nil {
+		t.Fatalf("Failed to compare formats: %v", err)
+	}
+	if len(report.Results) != 1 || report.Results[0].Format != FormatParquet {
+		t.Fatalf("Expected only parquet, got %+v", report.Results)
+	}
+
+	if _, err := CompareFormats(sampleUsers(t, 5), FormatConfig{Formats: []string{"xml"}, Iterations: 1}); err == nil {
+		t.Error("Expected an unknown format to be rejected")
+	}
+
+	t.Log("✓ Formats can be selected")
+}
+
+func TestFormatReportOutputs(t *testing.T) {
+	report, err := CompareFormats(sampleUsers(t, 10), FormatConfig{Iterations: 2})
+	if err != nil {
+		t.Fatalf("Failed to compare formats: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("Failed to write JSON: %v", err)
+	}
+	var decoded FormatReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded.Results) != len(AllFormats) {
+		t.Errorf("Expected the JSON report to decode with every format (%v)", err)
+	}
+
+	buf.Reset()
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(records) != len(AllFormats)+1 || records[0][0] != "format" || records[1][0] != FormatAvro {
+		t.Errorf("Unexpected CSV report: %v (%v)", records, err)
+	}
+
+	buf.Reset()
+	if err := report.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("Failed to write markdown: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(AllFormats)+2 || !strings.HasPrefix(lines[1], "| --- | ---: |") || !strings.HasPrefix(lines[5], "| json |") {
+		t.Errorf("Unexpected markdown report:\n%s", buf.String())
+	}
+
+	t.Log("✓ Reports render as JSON, CSV and markdown")
+}
+
+// BenchmarkFormats times one serialization and deserialization of 1000 users
+// per format and reports the payload size
+func BenchmarkFormats(b *testing.B) {
+	users := sampleUsers(b, 1000)
+	for _, format := range AllFormats {
+		codec, err := newFormatCodec(format, users)
+		if err != nil {
+			b.Fatal(err)
+		}
+		payload, err := codec.encode()
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(format+"/serialize", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := codec.encode(); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(payload))/float64(len(users)), "bytes/record")
+		})
+		b.Run(format+"/deserialize", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := codec.decode(payload); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
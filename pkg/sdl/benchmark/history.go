@@ -0,0 +1,356 @@
+package benchmark
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"go-transport-prac/internal/atomicfile"
+)
+
+// Environment describes the machine and toolchain a run was measured on
+type Environment struct {
+	GOOS      string `json:"goos"`
+	GOARCH    string `json:"goarch"`
+	CPU       string `json:"cpu,omitempty"`
+	NumCPU    int    `json:"numCPU"`
+	GoVersion string `json:"goVersion"`
+	Hostname  string `json:"hostname,omitempty"`
+}
+
+// CurrentEnvironment describes the running process. CPU is the model name
+// where the platform reports one
+func CurrentEnvironment() Environment {
+	hostname, _ := os.Hostname()
+	return Environment{
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		CPU:       cpuModel(),
+		NumCPU:    runtime.NumCPU(),
+		GoVersion: runtime.Version(),
+		Hostname:  hostname,
+	}
+}
+
+// Comparable reports whether runs on e and other can be compared fairly:
+// same platform, CPU model and Go version
+func (e Environment) Comparable(other Environment) bool {
+	return e.GOOS == other.GOOS && e.GOARCH == other.GOARCH && e.CPU == other.CPU &&
+		e.NumCPU == other.NumCPU && e.GoVersion == other.GoVersion
+}
+
+// cpuModel reads the CPU model name on Linux
+func cpuModel() string {
+	file, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && strings.TrimSpace(key) == "model name" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// Metric is one measured value of a run
+type Metric struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+	// HigherIsBetter marks throughputs; every other metric regresses when it grows
+	HigherIsBetter bool `json:"higherIsBetter,omitempty"`
+}
+
+// Run is one benchmark run in machine-readable form
+type Run struct {
+	Time        time.Time   `json:"time"`
+	Label       string      `json:"label,omitempty"`
+	Environment Environment `json:"environment"`
+	Metrics     []Metric    `json:"metrics"`
+}
+
+// NewRun stamps metrics with the current time and environment. Label names
+// the run, e.g. a commit or branch
+func NewRun(label string, metrics []Metric) *Run {
+	return &Run{Time: time.Now().UTC(), Label: label, Environment: CurrentEnvironment(), Metrics: metrics}
+}
+
+// Metric returns the named metric
+func (r *Run) Metric(name string) (Metric, bool) {
+	for _, m := range r.Metrics {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return Metric{}, false
+}
+
+// WriteJSON writes the run as indented JSON
+func (r *Run) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// ReadRun reads a run written by WriteJSON
+func ReadRun(path string) (*Run, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run: %w", err)
+	}
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to decode run %s: %w", path, err)
+	}
+	return &run, nil
+}
+
+// Metrics flattens the report to <format>/<measure> metrics: p50, p95 and
+// p99 latencies in nanoseconds, allocations per iteration and bytes per record
+func (r *FormatReport) Metrics() []Metric {
+	var metrics []Metric
+	add := func(format, name string, value float64, unit string) {
+		metrics = append(metrics, Metric{Name: format + "/" + name, Value: value, Unit: unit})
+	}
+	for _, res := range r.Results {
+		for _, phase := range []struct {
+			name        string
+			stats       LatencyStats
+			allocs, mem uint64
+		}{
+			{"serialize", res.Serialize, res.SerializeAllocs, res.SerializeAllocBytes},
+			{"deserialize", res.Deserialize, res.DeserializeAllocs, res.DeserializeAllocBytes},
+		} {
+			add(res.Format, phase.name+"-p50", float64(phase.stats.P50), "ns")
+			add(res.Format, phase.name+"-p95", float64(phase.stats.P95), "ns")
+			add(res.Format, phase.name+"-p99", float64(phase.stats.P99), "ns")
+			add(res.Format, phase.name+"-allocs", float64(phase.allocs), "allocs")
+			add(res.Format, phase.name+"-alloc-bytes", float64(phase.mem), "B")
+		}
+		add(res.Format, "bytes-per-record", res.BytesPerRecord(), "B")
+	}
+	return metrics
+}
+
+// Metrics flattens the result to <op>/<measure> latency metrics in
+// nanoseconds, plus the overall throughput and mutex wait
+func (r *MixedResult) Metrics() []Metric {
+	var metrics []Metric
+	for _, s := range r.Ops {
+		metrics = append(metrics,
+			Metric{Name: s.Op + "/p50", Value: float64(s.P50), Unit: "ns"},
+			Metric{Name: s.Op + "/p95", Value: float64(s.P95), Unit: "ns"},
+			Metric{Name: s.Op + "/p99", Value: float64(s.P99), Unit: "ns"},
+		)
+	}
+	return append(metrics,
+		Metric{Name: "throughput", Value: r.Throughput(), Unit: "ops/s", HigherIsBetter: true},
+		Metric{Name: "mutex-wait", Value: float64(r.MutexWait), Unit: "ns"},
+	)
+}
+
+// History stores runs as JSON files in a results directory, one file per
+// run named after its time and label, so runs sort by name
+type History struct {
+	dir string
+}
+
+// NewHistory returns the history kept in dir, created on the first Save
+func NewHistory(dir string) *History {
+	return &History{dir: dir}
+}
+
+// Save writes run to the results directory, returning its path
+func (h *History) Save(run *Run) (string, error) {
+	if err := os.MkdirAll(h.dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create results directory: %w", err)
+	}
+
+	name := run.Time.UTC().Format("20060102T150405.000000000Z")
+	if label := safeLabel(run.Label); label != "" {
+		name += "-" + label
+	}
+	path := filepath.Join(h.dir, name+".json")
+
+	file, err := atomicfile.Create(path, false)
+	if err != nil {
+		return "", err
+	}
+	if err := run.WriteJSON(file); err != nil {
+		file.Abort()
+		return "", fmt.Errorf("failed to write run: %w", err)
+	}
+	if err := file.Commit(); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Runs returns every stored run, oldest first
+func (h *History) Runs() ([]*Run, error) {
+	paths, err := filepath.Glob(filepath.Join(h.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	runs := make([]*Run, 0, len(paths))
+	for _, path := range paths {
+		run, err := ReadRun(path)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Time.Before(runs[j].Time) })
+	return runs, nil
+}
+
+// ErrNoRuns is returned by Latest when nothing has been saved yet
+var ErrNoRuns = errors.New("no benchmark runs recorded")
+
+// Latest returns the most recent stored run
+func (h *History) Latest() (*Run, error) {
+	runs, err := h.Runs()
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, ErrNoRuns
+	}
+	return runs[len(runs)-1], nil
+}
+
+// safeLabel keeps letters, digits, dots, dashes and underscores of a label
+func safeLabel(label string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, label)
+}
+
+// Change is the difference in one metric between two runs
+type Change struct {
+	Name string  `json:"name"`
+	Unit string  `json:"unit"`
+	Old  float64 `json:"old"`
+	New  float64 `json:"new"`
+	// Delta is the relative change, (New-Old)/Old; positive is worse unless
+	// the metric is higher-is-better. It is 0 when FromZero is set
+	Delta float64 `json:"delta"`
+	// FromZero marks a metric that left a baseline of 0, where no relative
+	// change exists; it is a regression unless the metric is higher-is-better
+	FromZero   bool `json:"fromZero,omitempty"`
+	Regression bool `json:"regression"`
+}
+
+// percent renders Delta as a signed percentage, or "from 0" for FromZero
+func (c Change) percent() string {
+	if c.FromZero {
+		return "from 0"
+	}
+	return fmt.Sprintf("%+.1f%%", c.Delta*100)
+}
+
+// Comparison is the outcome of Compare
+type Comparison struct {
+	Threshold float64  `json:"threshold"`
+	Changes   []Change `json:"changes"`
+	// Missing lists metrics of the baseline absent from the current run
+	Missing []string `json:"missing,omitempty"`
+	// EnvironmentChanged warns that the runs were measured on different
+	// machines or toolchains, so differences may not be the code's
+	EnvironmentChanged bool `json:"environmentChanged"`
+}
+
+// Compare compares every metric the baseline shares with the current run,
+// flagging a regression where the current value is worse by more than
+// threshold, a fraction: 0.1 flags metrics more than 10% worse
+func Compare(baseline, current *Run, threshold float64) *Comparison {
+	c := &Comparison{Threshold: threshold, EnvironmentChanged: !baseline.Environment.Comparable(current.Environment)}
+	for _, before := range baseline.Metrics {
+		after, ok := current.Metric(before.Name)
+		if !ok {
+			c.Missing = append(c.Missing, before.Name)
+			continue
+		}
+
+		change := Change{Name: before.Name, Unit: before.Unit, Old: before.Value, New: after.Value}
+		switch {
+		case before.Value != 0:
+			change.Delta = (after.Value - before.Value) / math.Abs(before.Value)
+			worse := change.Delta
+			if before.HigherIsBetter {
+				worse = -worse
+			}
+			change.Regression = worse > threshold
+		case after.Value != 0:
+			change.FromZero = true
+			change.Regression = !before.HigherIsBetter
+		}
+		c.Changes = append(c.Changes, change)
+	}
+	return c
+}
+
+// Regressions returns the changes flagged as regressions
+func (c *Comparison) Regressions() []Change {
+	var regressions []Change
+	for _, change := range c.Changes {
+		if change.Regression {
+			regressions = append(regressions, change)
+		}
+	}
+	return regressions
+}
+
+// Err returns an error naming every regression, or nil, so CI can fail on it
+func (c *Comparison) Err() error {
+	regressions := c.Regressions()
+	if len(regressions) == 0 {
+		return nil
+	}
+	names := make([]string, len(regressions))
+	for i, r := range regressions {
+		names[i] = fmt.Sprintf("%s %s", r.Name, r.percent())
+	}
+	return fmt.Errorf("%d metric(s) regressed by more than %.1f%%: %s",
+		len(regressions), c.Threshold*100, strings.Join(names, ", "))
+}
+
+// WriteMarkdown writes every change as a markdown table, regressions marked
+func (c *Comparison) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	if c.EnvironmentChanged {
+		b.WriteString("> The runs were measured in different environments.\n\n")
+	}
+	b.WriteString("| metric | old | new | change | |\n| --- | ---: | ---: | ---: | --- |\n")
+	for _, change := range c.Changes {
+		flag := ""
+		if change.Regression {
+			flag = "regression"
+		}
+		fmt.Fprintf(&b, "| %s | %.1f %s | %.1f %s | %s | %s |\n",
+			change.Name, change.Old, change.Unit, change.New, change.Unit, change.percent(), flag)
+	}
+	for _, name := range c.Missing {
+		fmt.Fprintf(&b, "| %s | | missing | | |\n", name)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
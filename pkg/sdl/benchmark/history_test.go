@@ -0,0 +1,146 @@
+package benchmark
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	dir := "tmp/test_history"
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+
+	history := NewHistory(dir)
+	if _, err := history.Latest(); !errors.Is(err, ErrNoRuns) {
+		t.Fatalf("Expected ErrNoRuns from an empty history, got %v", err)
+	}
+
+	first := NewRun("main", []Metric{{Name: "avro/serialize-p50", Value: 100, Unit: "ns"}})
+	second := NewRun("feature/x", []Metric{{Name: "avro/serialize-p50", Value: 90, Unit: "ns"}})
+	second.Time = first.Time.Add(time.Second)
+	if _, err := history.Save(first); err != nil {
+		t.Fatalf("Failed to save run: %v", err)
+	}
+	path, err := history.Save(second)
+	if err != nil {
+		t.Fatalf("Failed to save run: %v", err)
+	}
+	if filepath.Dir(path) != dir || !strings.HasSuffix(path, "-feature_x.json") {
+		t.Errorf("Expected the label to be sanitized into the file name, got %s", path)
+	}
+
+	runs, err := history.Runs()
+	if err != nil {
+		t.Fatalf("Failed to list runs: %v", err)
+	}
+	if len(runs) != 2 || runs[0].Label != "main" || runs[1].Label != "feature/x" {
+		t.Fatalf("Expected both runs oldest first, got %+v", runs)
+	}
+	latest, err := history.Latest()
+	if err != nil || latest.Label != "feature/x" {
+		t.Fatalf("Expected the latest run, got %+v (%v)", latest, err)
+	}
+	if latest.Environment.GOOS == "" || latest.Environment.GoVersion == "" || latest.Environment.NumCPU == 0 {
+		t.Errorf("Expected the environment to be recorded, got %+v", latest.Environment)
+	}
+
+	t.Log("✓ Runs are stored and listed oldest first")
+}
+
+func TestCompare(t *testing.T) {
+	env := CurrentEnvironment()
+	baseline := &Run{Environment: env, Metrics: []Metric{
+		{Name: "avro/serialize-p50", Value: 100, Unit: "ns"},
+		{Name: "avro/deserialize-p50", Value: 100, Unit: "ns"},
+		{Name: "throughput", Value: 1000, Unit: "ops/s", HigherIsBetter: true},
+		{Name: "json/serialize-p50", Value: 100, Unit: "ns"},
+	}}
+	current := &Run{Environment: env, Metrics: []Metric{
+		{Name: "avro/serialize-p50", Value: 125, Unit: "ns"},
+		{Name: "avro/deserialize-p50", Value: 105, Unit: "ns"},
+		{Name: "throughput", Value: 800, Unit: "ops/s", HigherIsBetter: true},
+	}}
+
+	c := Compare(baseline, current, 0.1)
+	if c.EnvironmentChanged {
+		t.Error("Expected runs in the same environment to be comparable")
+	}
+	if len(c.Changes) != 3 || len(c.Missing) != 1 || c.Missing[0] != "json/serialize-p50" {
+		t.Fatalf("Expected 3 changes and 1 missing metric, got %+v", c)
+	}
+	regressions := c.Regressions()
+	if len(regressions) != 2 || regressions[0].Name != "avro/serialize-p50" || regressions[1].Name != "throughput" {
+		t.Fatalf("Expected the latency and throughput regressions, got %+v", regressions)
+	}
+	if regressions[0].Delta != 0.25 {
+		t.Errorf("Expected a +25%% delta, got %v", regressions[0].Delta)
+	}
+	if err := c.Err(); err == nil || !strings.Contains(err.Error(), "avro/serialize-p50 +25.0%") {
+		t.Errorf("Expected an error naming the regressions, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := c.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("Failed to write markdown: %v", err)
+	}
+	if !strings.Contains(buf.String(), "| throughput | 1000.0 ops/s | 800.0 ops/s | -20.0% | regression |") {
+		t.Errorf("Expected the throughput row, got:\n%s", buf.String())
+	}
+
+	// Improvements and changes below the threshold pass
+	if err := Compare(baseline, current, 0.3).Err(); err != nil {
+		t.Errorf("Expected no regression above 30%%, got %v", err)
+	}
+
+	// A metric leaving a zero baseline has no relative change
+	zero := &Run{Environment: env, Metrics: []Metric{
+		{Name: "avro/serialize-allocs", Value: 0, Unit: "allocs"},
+		{Name: "errors-handled", Value: 0, Unit: "ops", HigherIsBetter: true},
+	}}
+	grown := &Run{Environment: env, Metrics: []Metric{
+		{Name: "avro/serialize-allocs", Value: 2, Unit: "allocs"},
+		{Name: "errors-handled", Value: 5, Unit: "ops", HigherIsBetter: true},
+	}}
+	fromZero := Compare(zero, grown, 0.1)
+	if _, err := json.Marshal(fromZero); err != nil {
+		t.Fatalf("Failed to marshal a comparison from zero: %v", err)
+	}
+	if regressions := fromZero.Regressions(); len(regressions) != 1 || !regressions[0].FromZero || regressions[0].Name != "avro/serialize-allocs" {
+		t.Errorf("Expected only the allocations to regress from zero, got %+v", fromZero.Changes)
+	}
+	if err := fromZero.Err(); err == nil || !strings.Contains(err.Error(), "avro/serialize-allocs from 0") {
+		t.Errorf("Expected an error naming the metric that left zero, got %v", err)
+	}
+
+	current.Environment.GoVersion = "go0.0"
+	if !Compare(baseline, current, 0.1).EnvironmentChanged {
+		t.Error("Expected a different Go version to be flagged")
+	}
+
+	t.Log("✓ Compare flags regressions beyond the threshold")
+}
+
+func TestFormatReportMetrics(t *testing.T) {
+	report, err := CompareFormats(sampleUsers(t, 5), FormatConfig{Formats: []string{FormatAvro}, Iterations: 2})
+	if err != nil {
+		t.Fatalf("Failed to compare formats: %v", err)
+	}
+	if report.Environment.GOARCH == "" {
+		t.Error("Expected the report to record its environment")
+	}
+
+	run := NewRun("", report.Metrics())
+	for _, name := range []string{"avro/serialize-p99", "avro/deserialize-allocs", "avro/bytes-per-record"} {
+		if m, ok := run.Metric(name); !ok || m.Value <= 0 {
+			t.Errorf("Expected a positive %s, got %+v", name, m)
+		}
+	}
+
+	t.Log("✓ Format reports flatten to named metrics")
+}
@@ -0,0 +1,397 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime/metrics"
+	"sort"
+	"sync"
+	"time"
+
+	"go-transport-prac/internal/profiling"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/converter"
+	"go-transport-prac/pkg/sdl/faker"
+	"go-transport-prac/pkg/sdl/parquet"
+)
+
+// Operation names reported by the mixed workload
+const (
+	OpParquetWrite   = "parquet_write"
+	OpAvroWrite      = "avro_write"
+	OpParquetRead    = "parquet_read"
+	OpAvroRead       = "avro_read"
+	OpRegistryLookup = "registry_lookup"
+)
+
+// registrySubject is the subject the user schema is registered under
+const registrySubject = "users-value"
+
+// mutexWaitMetric is the cumulative time goroutines spent blocked on sync.Mutex/RWMutex
+const mutexWaitMetric = "/sync/mutex/wait/total:seconds"
+
+// MixedConfig controls the shape of a mixed read/write workload
+type MixedConfig struct {
+	// Writers alternate between writing new Parquet and Avro files
+	Writers int `json:"writers"`
+	// Readers alternate between scanning seeded Parquet and Avro files
+	Readers int `json:"readers"`
+	// Lookups alternate between registry lookups by ID and by subject
+	Lookups int `json:"lookups"`
+	// RecordsPerFile is the number of users in every written or seeded file
+	RecordsPerFile int `json:"recordsPerFile"`
+	// SeedFiles is the number of files per format readers scan
+	SeedFiles int `json:"seedFiles"`
+	// DataSeed seeds the generator of the users written, so runs with the
+	// same seed handle the same data
+	DataSeed int64 `json:"dataSeed"`
+	// Iterations is the number of operations per worker. Ignored when Duration is set
+	Iterations int `json:"iterations"`
+	// Duration runs every worker until it elapses
+	Duration time.Duration `json:"duration"`
+	// CPUProfile, when set, is the file a CPU profile of the run is written to
+	CPUProfile string `json:"cpuProfile,omitempty"`
+}
+
+// DefaultMixedConfig returns a read-heavy workload with a few writers
+func DefaultMixedConfig() MixedConfig {
+	return MixedConfig{
+		Writers:        2,
+		Readers:        4,
+		Lookups:        4,
+		RecordsPerFile: 100,
+		SeedFiles:      4,
+		Iterations:     50,
+	}
+}
+
+// LatencyStats summarizes the latencies of one operation
+type LatencyStats struct {
+	Op     string        `json:"op"`
+	Count  int           `json:"count"`
+	Errors int           `json:"errors"`
+	Mean   time.Duration `json:"mean"`
+	P50    time.Duration `json:"p50"`
+	P95    time.Duration `json:"p95"`
+	P99    time.Duration `json:"p99"`
+	Max    time.Duration `json:"max"`
+}
+
+// MixedResult is the outcome of a mixed workload run
+type MixedResult struct {
+	Config      MixedConfig    `json:"config"`
+	Environment Environment    `json:"environment"`
+	Elapsed     time.Duration  `json:"elapsed"`
+	Ops         []LatencyStats `json:"ops"`
+	// MutexWait is the time goroutines spent blocked on mutexes during the run
+	MutexWait time.Duration `json:"mutexWait"`
+}
+
+// Op returns the stats for the named operation
+func (r *MixedResult) Op(name string) (LatencyStats, bool) {
+	for _, s := range r.Ops {
+		if s.Op == name {
+			return s, true
+		}
+	}
+	return LatencyStats{}, false
+}
+
+// Throughput returns completed operations per second across all workers
+func (r *MixedResult) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	total := 0
+	for _, s := range r.Ops {
+		total += s.Count
+	}
+	return float64(total) / r.Elapsed.Seconds()
+}
+
+// MixedWorkload runs concurrent file writers, file readers and registry
+// lookups against shared Avro and Parquet managers and one schema registry
+type MixedWorkload struct {
+	config       MixedConfig
+	avro         *avro.Manager
+	parquet      *parquet.SimpleManager
+	registry     *avro.SchemaRegistry
+	schemaID     int
+	avroUsers    []avro.User
+	parquetUsers []parquet.User
+	seeded       []string
+}
+
+// NewMixedWorkload creates a workload rooted at baseDir and seeds the files readers scan
+func NewMixedWorkload(baseDir string, config MixedConfig) (*MixedWorkload, error) {
+	if config.Writers < 0 || config.Readers < 0 || config.Lookups < 0 {
+		return nil, fmt.Errorf("worker counts cannot be negative")
+	}
+	if config.Writers+config.Readers+config.Lookups == 0 {
+		return nil, fmt.Errorf("at least one worker is required")
+	}
+	if config.Iterations <= 0 && config.Duration <= 0 {
+		return nil, fmt.Errorf("iterations or duration must be positive")
+	}
+	if config.RecordsPerFile <= 0 || config.SeedFiles <= 0 {
+		return nil, fmt.Errorf("records per file and seed files must be positive")
+	}
+
+	avroManager, err := avro.NewManager(filepath.Join(baseDir, "avro"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avro manager: %w", err)
+	}
+
+	registry := avro.NewSchemaRegistry()
+	schemaID, err := registry.RegisterSchema(registrySubject, avroManager.GetUserSchema().String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to register user schema: %w", err)
+	}
+
+	w := &MixedWorkload{
+		config:   config,
+		avro:     avroManager,
+		parquet:  parquet.NewSimpleManager(filepath.Join(baseDir, "parquet")),
+		registry: registry,
+		schemaID: schemaID,
+	}
+	w.avroUsers, err = faker.Records[avro.User](faker.New(config.DataSeed), avroManager.GetUserSchema(), config.RecordsPerFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate users: %w", err)
+	}
+	w.parquetUsers = toParquetUsers(w.avroUsers)
+
+	for i := 0; i < config.SeedFiles; i++ {
+		name := fmt.Sprintf("seed_%03d", i)
+		if err := w.parquet.WriteUsers(name+".parquet", w.parquetUsers); err != nil {
+			return nil, fmt.Errorf("failed to seed parquet file: %w", err)
+		}
+		if err := w.avro.WriteUsersToFile(name+".avro", w.avroUsers); err != nil {
+			return nil, fmt.Errorf("failed to seed avro file: %w", err)
+		}
+		w.seeded = append(w.seeded, name)
+	}
+
+	return w, nil
+}
+
+// sample is one timed operation
+type sample struct {
+	op      string
+	latency time.Duration
+	err     error
+}
+
+// worker performs the n-th operation of its kind
+type worker func(n int) sample
+
+// Run starts every worker and returns the latency summary once they finish.
+// Cancelling ctx stops the workers early; the partial result is returned
+// with the context error
+func (w *MixedWorkload) Run(ctx context.Context) (*MixedResult, error) {
+	if w.config.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.config.Duration)
+		defer cancel()
+	}
+
+	var workers []worker
+	for i := 0; i < w.config.Writers; i++ {
+		workers = append(workers, w.writer(i))
+	}
+	for i := 0; i < w.config.Readers; i++ {
+		workers = append(workers, w.reader(i))
+	}
+	for i := 0; i < w.config.Lookups; i++ {
+		workers = append(workers, w.lookup(i))
+	}
+
+	// Each worker records into its own slice so measuring adds no contention
+	samples := make([][]sample, len(workers))
+	mutexBefore := mutexWait()
+	start := time.Now()
+
+	err := profiling.CaptureToFile(w.config.CPUProfile, func() error {
+		var wg sync.WaitGroup
+		for i, work := range workers {
+			wg.Add(1)
+			go func(i int, work worker) {
+				defer wg.Done()
+				for n := 0; w.config.Duration > 0 || n < w.config.Iterations; n++ {
+					if ctx.Err() != nil {
+						return
+					}
+					samples[i] = append(samples[i], work(n))
+				}
+			}(i, work)
+		}
+		wg.Wait()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MixedResult{
+		Config:      w.config,
+		Environment: CurrentEnvironment(),
+		Elapsed:     time.Since(start),
+		MutexWait:   mutexWait() - mutexBefore,
+	}
+	result.Ops = summarize(samples)
+	if w.config.Duration <= 0 && ctx.Err() != nil {
+		return result, ctx.Err()
+	}
+	return result, nil
+}
+
+// writer alternates between new Parquet and Avro files named after the worker
+func (w *MixedWorkload) writer(id int) worker {
+	return func(n int) sample {
+		name := fmt.Sprintf("writer_%02d_%06d", id, n)
+		if n%2 == 0 {
+			return w.timed(OpParquetWrite, func() error {
+				return w.parquet.WriteUsers(name+".parquet", w.parquetUsers)
+			})
+		}
+		return w.timed(OpAvroWrite, func() error {
+			return w.avro.WriteUsersToFile(name+".avro", w.avroUsers)
+		})
+	}
+}
+
+// reader scans the seeded files, starting at a different file per worker
+func (w *MixedWorkload) reader(id int) worker {
+	return func(n int) sample {
+		name := w.seeded[(id+n/2)%len(w.seeded)]
+		if n%2 == 0 {
+			return w.timed(OpParquetRead, func() error {
+				users, err := w.parquet.ReadUsers(name + ".parquet")
+				return checkCount(users, err, w.config.RecordsPerFile)
+			})
+		}
+		return w.timed(OpAvroRead, func() error {
+			users, err := w.avro.ReadUsersFromFile(name + ".avro")
+			return checkCount(users, err, w.config.RecordsPerFile)
+		})
+	}
+}
+
+// lookup alternates between registry lookups by schema ID and by subject
+func (w *MixedWorkload) lookup(int) worker {
+	return func(n int) sample {
+		return w.timed(OpRegistryLookup, func() error {
+			if n%2 == 0 {
+				_, err := w.registry.GetSchema(w.schemaID)
+				return err
+			}
+			_, err := w.registry.GetLatestSchema(registrySubject)
+			return err
+		})
+	}
+}
+
+func (w *MixedWorkload) timed(op string, fn func() error) sample {
+	start := time.Now()
+	err := fn()
+	return sample{op: op, latency: time.Since(start), err: err}
+}
+
+// checkCount fails reads that return a partial file
+func checkCount[T any](records []T, err error, want int) error {
+	if err != nil {
+		return err
+	}
+	if len(records) != want {
+		return fmt.Errorf("read %d records, want %d", len(records), want)
+	}
+	return nil
+}
+
+// summarize groups samples by operation and computes their latency percentiles
+func summarize(samples [][]sample) []LatencyStats {
+	latencies := make(map[string][]time.Duration)
+	errors := make(map[string]int)
+	for _, worker := range samples {
+		for _, s := range worker {
+			if s.err != nil {
+				errors[s.op]++
+				continue
+			}
+			latencies[s.op] = append(latencies[s.op], s.latency)
+		}
+	}
+
+	ops := make([]string, 0, len(latencies)+len(errors))
+	for op := range latencies {
+		ops = append(ops, op)
+	}
+	for op := range errors {
+		if _, ok := latencies[op]; !ok {
+			ops = append(ops, op)
+		}
+	}
+	sort.Strings(ops)
+
+	stats := make([]LatencyStats, 0, len(ops))
+	for _, op := range ops {
+		stats = append(stats, latencyStats(op, latencies[op], errors[op]))
+	}
+	return stats
+}
+
+// latencyStats computes nearest-rank percentiles over successful operations
+func latencyStats(op string, latencies []time.Duration, errors int) LatencyStats {
+	stats := LatencyStats{Op: op, Count: len(latencies), Errors: errors}
+	if len(latencies) == 0 {
+		return stats
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	var total time.Duration
+	for _, l := range latencies {
+		total += l
+	}
+	stats.Mean = total / time.Duration(len(latencies))
+	stats.P50 = Percentile(latencies, 50)
+	stats.P95 = Percentile(latencies, 95)
+	stats.P99 = Percentile(latencies, 99)
+	stats.Max = latencies[len(latencies)-1]
+	return stats
+}
+
+// Percentile returns the nearest-rank p-th percentile of sorted latencies
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p/100*float64(len(sorted))+0.999999) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// mutexWait reads the cumulative mutex wait time from the runtime
+func mutexWait() time.Duration {
+	sample := []metrics.Sample{{Name: mutexWaitMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindFloat64 {
+		return 0
+	}
+	return time.Duration(sample[0].Value.Float64() * float64(time.Second))
+}
+
+// toParquetUsers converts the Avro sample users so both formats hold the same data
+func toParquetUsers(users []avro.User) []parquet.User {
+	out := make([]parquet.User, len(users))
+	for i, u := range users {
+		out[i] = converter.UserToParquet(u)
+	}
+	return out
+}
@@ -0,0 +1,168 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMixedWorkload(t *testing.T) {
+	dir := "tmp/test_mixed_workload"
+	defer os.RemoveAll(dir)
+
+	config := MixedConfig{
+		Writers:        2,
+		Readers:        2,
+		Lookups:        2,
+		RecordsPerFile: 20,
+		SeedFiles:      2,
+		Iterations:     6,
+	}
+	workload, err := NewMixedWorkload(dir, config)
+	if err != nil {
+		t.Fatalf("Failed to create workload: %v", err)
+	}
+
+	result, err := workload.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Workload failed: %v", err)
+	}
+
+	// Every worker alternates between two operations of its kind
+	expected := map[string]int{
+		OpParquetWrite:   6,
+		OpAvroWrite:      6,
+		OpParquetRead:    6,
+		OpAvroRead:       6,
+		OpRegistryLookup: 12,
+	}
+	for op, count := range expected {
+		stats, ok := result.Op(op)
+		if !ok {
+			t.Errorf("Missing stats for %s", op)
+			continue
+		}
+		if stats.Count != count || stats.Errors != 0 {
+			t.Errorf("%s: expected %d successful ops, got %d (%d errors)", op, count, stats.Count, stats.Errors)
+		}
+		if stats.P50 > stats.P95 || stats.P95 > stats.P99 || stats.P99 > stats.Max || stats.P50 <= 0 {
+			t.Errorf("%s: percentiles out of order: %+v", op, stats)
+		}
+	}
+	if result.Throughput() <= 0 {
+		t.Error("Expected positive throughput")
+	}
+
+	t.Logf("✓ Mixed workload completed in %v at %.0f ops/s (mutex wait %v)", result.Elapsed, result.Throughput(), result.MutexWait)
+}
+
+func TestMixedWorkload_Cancelled(t *testing.T) {
+	dir := "tmp/test_mixed_cancelled"
+	defer os.RemoveAll(dir)
+
+	config := DefaultMixedConfig()
+	config.RecordsPerFile = 5
+	config.SeedFiles = 1
+	workload, err := NewMixedWorkload(dir, config)
+	if err != nil {
+		t.Fatalf("Failed to create workload: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := workload.Run(ctx); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	t.Log("✓ Cancelled runs report the context error")
+}
+
+func TestNewMixedWorkload_InvalidConfig(t *testing.T) {
+	tests := map[string]func(*MixedConfig){
+		"no workers":       func(c *MixedConfig) { c.Writers, c.Readers, c.Lookups = 0, 0, 0 },
+		"negative workers": func(c *MixedConfig) { c.Readers = -1 },
+		"no iterations":    func(c *MixedConfig) { c.Iterations, c.Duration = 0, 0 },
+		"no seed files":    func(c *MixedConfig) { c.SeedFiles = 0 },
+	}
+
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := DefaultMixedConfig()
+			mutate(&config)
+			if _, err := NewMixedWorkload("tmp/test_mixed_invalid", config); err == nil {
+				t.Error("Expected a configuration error")
+			}
+		})
+	}
+	os.RemoveAll("tmp/test_mixed_invalid")
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	cases := map[float64]time.Duration{
+		0:   time.Millisecond,
+		50:  50 * time.Millisecond,
+		95:  95 * time.Millisecond,
+		99:  99 * time.Millisecond,
+		100: 100 * time.Millisecond,
+	}
+	for p, want := range cases {
+		if got := Percentile(sorted, p); got != want {
+			t.Errorf("p%v: got %v, want %v", p, got, want)
+		}
+	}
+	if Percentile(nil, 99) != 0 {
+		t.Error("Expected zero for no samples")
+	}
+}
+
+// BenchmarkMixedWorkload runs read-heavy, balanced and write-heavy mixes and
+// reports per-operation tail latencies alongside the usual ns/op
+func BenchmarkMixedWorkload(b *testing.B) {
+	mixes := []struct {
+		name                      string
+		writers, readers, lookups int
+	}{
+		{"read_heavy", 1, 6, 4},
+		{"balanced", 4, 4, 4},
+		{"write_heavy", 6, 2, 2},
+	}
+
+	for _, mix := range mixes {
+		b.Run(mix.name, func(b *testing.B) {
+			dir := fmt.Sprintf("tmp/bench_mixed_%s", mix.name)
+			defer os.RemoveAll(dir)
+
+			config := DefaultMixedConfig()
+			config.Writers, config.Readers, config.Lookups = mix.writers, mix.readers, mix.lookups
+			config.Iterations = b.N
+
+			workload, err := NewMixedWorkload(dir, config)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ResetTimer()
+			result, err := workload.Run(context.Background())
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.StopTimer()
+
+			for _, stats := range result.Ops {
+				if stats.Errors > 0 {
+					b.Fatalf("%s: %d operations failed", stats.Op, stats.Errors)
+				}
+				b.ReportMetric(float64(stats.P99.Microseconds()), stats.Op+"-p99-µs")
+			}
+			b.ReportMetric(result.Throughput(), "ops/s")
+			b.ReportMetric(float64(result.MutexWait.Microseconds()), "mutex-wait-µs")
+		})
+	}
+}
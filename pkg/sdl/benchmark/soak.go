@@ -0,0 +1,430 @@
+package benchmark
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/pkg/cache"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/faker"
+	"go-transport-prac/pkg/sdl/parquet"
+	"go-transport-prac/pkg/sink"
+	"go-transport-prac/pkg/transport/eventlog"
+)
+
+// Scenarios a soak test can run
+const (
+	// ScenarioProduce streams users through an AsyncWriter into Avro files
+	ScenarioProduce = "produce"
+	// ScenarioConsume publishes events to an event log that consumers follow
+	// by cursor, and streams seeded Parquet files through a UserReader
+	ScenarioConsume = "consume"
+	// ScenarioPersist writes and rereads Parquet files and caches every user
+	ScenarioPersist = "persist"
+	// ScenarioAll runs one worker group per scenario
+	ScenarioAll = "all"
+)
+
+// soakTopic is the event log topic of the consume scenario
+const soakTopic = "users"
+
+// SoakConfig controls a long-running soak test
+type SoakConfig struct {
+	// Scenario is one of the Scenario constants
+	Scenario string `json:"scenario"`
+	// Workers is the number of goroutines per scenario
+	Workers int `json:"workers"`
+	// RecordsPerCycle is the number of users every cycle of a worker handles
+	RecordsPerCycle int `json:"recordsPerCycle"`
+	// DataSeed seeds the generator of the users every cycle handles
+	DataSeed int64 `json:"dataSeed"`
+	// Duration is how long the test runs; zero runs until the context is cancelled
+	Duration time.Duration `json:"duration"`
+	// SnapshotInterval is the time between resource snapshots
+	SnapshotInterval time.Duration `json:"snapshotInterval"`
+	// CacheKeys bounds the keys the persist scenario writes, so a cache that
+	// keeps growing beyond them is a leak rather than the workload
+	CacheKeys int `json:"cacheKeys"`
+}
+
+// DefaultSoakConfig returns an hour of every scenario with a snapshot per minute
+func DefaultSoakConfig() SoakConfig {
+	return SoakConfig{
+		Scenario:         ScenarioAll,
+		Workers:          2,
+		RecordsPerCycle:  200,
+		Duration:         time.Hour,
+		SnapshotInterval: time.Minute,
+		CacheKeys:        10000,
+	}
+}
+
+// Snapshot is the state of the process at one point of a soak test. Ops and
+// Errors count the cycles since the previous snapshot
+type Snapshot struct {
+	Elapsed     time.Duration `json:"elapsed"`
+	HeapAlloc   uint64        `json:"heapAlloc"`
+	HeapObjects uint64        `json:"heapObjects"`
+	Goroutines  int           `json:"goroutines"`
+	Ops         int64         `json:"ops"`
+	Errors      int64         `json:"errors"`
+	// LastError is the most recent error since the previous snapshot
+	LastError string `json:"lastError,omitempty"`
+}
+
+// ErrorRate returns the share of failed cycles since the previous snapshot
+func (s Snapshot) ErrorRate() float64 {
+	if s.Ops == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Ops)
+}
+
+// SoakLimits are the growth a soak test tolerates between its first and last
+// snapshot. Zero fields are not checked
+type SoakLimits struct {
+	MaxHeapGrowth      int64   `json:"maxHeapGrowth"`
+	MaxGoroutineGrowth int     `json:"maxGoroutineGrowth"`
+	MaxErrorRate       float64 `json:"maxErrorRate"`
+}
+
+// SoakResult is the outcome of a soak test
+type SoakResult struct {
+	Config    SoakConfig    `json:"config"`
+	Elapsed   time.Duration `json:"elapsed"`
+	Snapshots []Snapshot    `json:"snapshots"`
+}
+
+// Ops returns the cycles completed over the whole run
+func (r *SoakResult) Ops() int64 {
+	var total int64
+	for _, s := range r.Snapshots {
+		total += s.Ops
+	}
+	return total
+}
+
+// ErrorRate returns the share of failed cycles over the whole run
+func (r *SoakResult) ErrorRate() float64 {
+	var ops, errs int64
+	for _, s := range r.Snapshots {
+		ops += s.Ops
+		errs += s.Errors
+	}
+	if ops == 0 {
+		return 0
+	}
+	return float64(errs) / float64(ops)
+}
+
+// HeapGrowth returns the change in live heap between the first and last snapshot
+func (r *SoakResult) HeapGrowth() int64 {
+	if len(r.Snapshots) < 2 {
+		return 0
+	}
+	return int64(r.Snapshots[len(r.Snapshots)-1].HeapAlloc) - int64(r.Snapshots[0].HeapAlloc)
+}
+
+// GoroutineGrowth returns the change in goroutines between the first and last snapshot
+func (r *SoakResult) GoroutineGrowth() int {
+	if len(r.Snapshots) < 2 {
+		return 0
+	}
+	return r.Snapshots[len(r.Snapshots)-1].Goroutines - r.Snapshots[0].Goroutines
+}
+
+// Check returns an error describing every limit the run exceeded
+func (r *SoakResult) Check(limits SoakLimits) error {
+	var violations []string
+	if limits.MaxHeapGrowth > 0 && r.HeapGrowth() > limits.MaxHeapGrowth {
+		violations = append(violations, fmt.Sprintf("heap grew by %d bytes (limit %d)", r.HeapGrowth(), limits.MaxHeapGrowth))
+	}
+	if limits.MaxGoroutineGrowth > 0 && r.GoroutineGrowth() > limits.MaxGoroutineGrowth {
+		violations = append(violations, fmt.Sprintf("goroutines grew by %d (limit %d)", r.GoroutineGrowth(), limits.MaxGoroutineGrowth))
+	}
+	if limits.MaxErrorRate > 0 && r.ErrorRate() > limits.MaxErrorRate {
+		violations = append(violations, fmt.Sprintf("error rate %.4f (limit %.4f)", r.ErrorRate(), limits.MaxErrorRate))
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("soak test failed: %v", violations)
+	}
+	return nil
+}
+
+// Soak runs a scenario continuously, snapshotting heap, goroutines and
+// error rates, to catch leaks in the streaming writers, caches and consumers
+// that short benchmarks miss. The first snapshot is taken before the workers
+// start and the last after they stopped, so goroutines still running at the
+// end leaked. The heap grows until the bounded cache and event log fill, so
+// heap limits should allow for them
+type Soak struct {
+	config     SoakConfig
+	dir        string
+	avro       *avro.Manager
+	parquet    *parquet.SimpleManager
+	cache      *cache.MemoryCache
+	events     *eventlog.Log
+	log        *logger.Logger
+	avroUsers  []avro.User
+	users      []parquet.User
+	onSnapshot func(Snapshot)
+
+	ops     atomic.Int64
+	errors  atomic.Int64
+	lastErr atomic.Value
+}
+
+// NewSoak creates a soak test writing its files under baseDir
+func NewSoak(baseDir string, config SoakConfig) (*Soak, error) {
+	switch config.Scenario {
+	case ScenarioProduce, ScenarioConsume, ScenarioPersist, ScenarioAll:
+	default:
+		return nil, fmt.Errorf("unknown soak scenario %q", config.Scenario)
+	}
+	if config.Workers <= 0 || config.RecordsPerCycle <= 0 {
+		return nil, fmt.Errorf("workers and records per cycle must be positive")
+	}
+	if config.SnapshotInterval <= 0 {
+		return nil, fmt.Errorf("snapshot interval must be positive")
+	}
+	if config.CacheKeys <= 0 {
+		config.CacheKeys = DefaultSoakConfig().CacheKeys
+	}
+
+	avroManager, err := avro.NewManager(filepath.Join(baseDir, "avro"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avro manager: %w", err)
+	}
+	for _, dir := range []string{"avro", "parquet"} {
+		if err := os.MkdirAll(filepath.Join(baseDir, dir), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create %s directory: %w", dir, err)
+		}
+	}
+
+	s := &Soak{
+		config:  config,
+		dir:     baseDir,
+		avro:    avroManager,
+		parquet: parquet.NewSimpleManager(filepath.Join(baseDir, "parquet")),
+		cache:   cache.NewMemoryCache(),
+		events:  eventlog.NewLog(eventlog.DefaultCapacity),
+		log:     logger.Global(),
+	}
+	s.avroUsers, err = faker.Records[avro.User](faker.New(config.DataSeed), avroManager.GetUserSchema(), config.RecordsPerCycle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate users: %w", err)
+	}
+	s.users = toParquetUsers(s.avroUsers)
+
+	if config.Scenario == ScenarioConsume || config.Scenario == ScenarioAll {
+		if err := s.parquet.WriteUsers("seed.parquet", s.users); err != nil {
+			return nil, fmt.Errorf("failed to seed parquet file: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// WithLogger sets the logger of the async writers
+func (s *Soak) WithLogger(log *logger.Logger) *Soak {
+	s.log = log
+	return s
+}
+
+// OnSnapshot sets a function called with every snapshot as it is taken
+func (s *Soak) OnSnapshot(fn func(Snapshot)) *Soak {
+	s.onSnapshot = fn
+	return s
+}
+
+// Run runs the soak test until its duration elapses or ctx is cancelled and
+// returns the snapshots, ending with one taken after the workers stopped
+func (s *Soak) Run(ctx context.Context) (*SoakResult, error) {
+	if s.config.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.config.Duration)
+		defer cancel()
+	}
+
+	var cycles []func(ctx context.Context, worker, n int) error
+	scenario := s.config.Scenario
+	if scenario == ScenarioProduce || scenario == ScenarioAll {
+		cycles = append(cycles, s.produce)
+	}
+	if scenario == ScenarioConsume || scenario == ScenarioAll {
+		cycles = append(cycles, s.consume)
+	}
+	if scenario == ScenarioPersist || scenario == ScenarioAll {
+		cycles = append(cycles, s.persist)
+	}
+
+	start := time.Now()
+	result := &SoakResult{Config: s.config}
+	result.Snapshots = append(result.Snapshots, s.snapshot(start))
+
+	var wg sync.WaitGroup
+	for _, cycle := range cycles {
+		for worker := 0; worker < s.config.Workers; worker++ {
+			wg.Add(1)
+			go func(cycle func(context.Context, int, int) error, worker int) {
+				defer wg.Done()
+				for n := 0; ctx.Err() == nil; n++ {
+					s.record(ctx, cycle(ctx, worker, n))
+				}
+			}(cycle, worker)
+		}
+	}
+
+	ticker := time.NewTicker(s.config.SnapshotInterval)
+	defer ticker.Stop()
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+			result.Snapshots = append(result.Snapshots, s.snapshot(start))
+		}
+	}
+	wg.Wait()
+
+	result.Snapshots = append(result.Snapshots, s.snapshot(start))
+	result.Elapsed = time.Since(start)
+	return result, nil
+}
+
+// Close removes the files the soak test wrote
+func (s *Soak) Close() error {
+	s.cache.Close()
+	return os.RemoveAll(s.dir)
+}
+
+// record counts a finished cycle. Cycles cut short by the end of the run are not errors
+func (s *Soak) record(ctx context.Context, err error) {
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+	s.ops.Add(1)
+	if err != nil {
+		s.errors.Add(1)
+		s.lastErr.Store(err.Error())
+	}
+}
+
+// snapshot collects garbage so HeapAlloc is the live heap, then reads the
+// counters accumulated since the previous snapshot
+func (s *Soak) snapshot(start time.Time) Snapshot {
+	runtime.GC()
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	snap := Snapshot{
+		Elapsed:     time.Since(start),
+		HeapAlloc:   mem.HeapAlloc,
+		HeapObjects: mem.HeapObjects,
+		Goroutines:  runtime.NumGoroutine(),
+		Ops:         s.ops.Swap(0),
+		Errors:      s.errors.Swap(0),
+	}
+	if snap.Errors > 0 {
+		snap.LastError, _ = s.lastErr.Load().(string)
+	}
+	if s.onSnapshot != nil {
+		s.onSnapshot(snap)
+	}
+	return snap
+}
+
+// produce streams a cycle of users through a new AsyncWriter into an Avro
+// file, closes both and removes the file
+func (s *Soak) produce(ctx context.Context, worker, n int) error {
+	name := filepath.Join(s.dir, "avro", fmt.Sprintf("produce_%02d.avro", worker))
+	file, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(name)
+	defer file.Close()
+
+	buffered := bufio.NewWriter(file)
+	encoder := s.avro.NewUserStreamWriter(buffered)
+	writer := sink.NewAsyncWriter[avro.User](sink.NewStreamSink(buffered, encoder.Write), sink.DefaultConfig(), s.log)
+	// Close drains the queue even when ctx is done, so nothing writes after the file closed
+	for _, user := range s.avroUsers {
+		if err := writer.Submit(ctx, user); err != nil {
+			writer.Close(context.Background())
+			return err
+		}
+	}
+	if err := writer.Close(context.Background()); err != nil {
+		return err
+	}
+	if encoder.Count() != len(s.avroUsers) {
+		return fmt.Errorf("wrote %d users, want %d", encoder.Count(), len(s.avroUsers))
+	}
+	return file.Close()
+}
+
+// consume publishes a cycle of users to the event log and follows them from
+// the head cursor, then streams the seeded Parquet file
+func (s *Soak) consume(ctx context.Context, worker, n int) error {
+	cursor := s.events.Head()
+	for i := range s.avroUsers {
+		s.events.Append(soakTopic, &s.avroUsers[i])
+	}
+
+	// Other workers publish too, so read until this worker's events were all seen
+	for seen := 0; seen < len(s.avroUsers); {
+		page, err := s.events.Wait(ctx, cursor, []string{soakTopic}, len(s.avroUsers))
+		if err != nil {
+			return fmt.Errorf("failed to read events: %w", err)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		seen += len(page.Events)
+		cursor = page.Cursor
+	}
+
+	read := 0
+	err := s.parquet.ReadUsersBatched("seed.parquet", 64, func(users []parquet.User) error {
+		read += len(users)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if read != len(s.users) {
+		return fmt.Errorf("read %d users, want %d", read, len(s.users))
+	}
+	return nil
+}
+
+// persist writes a cycle of users to Parquet, rereads them and caches each
+// one under a key from a bounded set
+func (s *Soak) persist(ctx context.Context, worker, n int) error {
+	name := fmt.Sprintf("persist_%02d.parquet", worker)
+	if err := s.parquet.WriteUsers(name, s.users); err != nil {
+		return err
+	}
+	users, err := s.parquet.ReadUsers(name)
+	if err := checkCount(users, err, len(s.users)); err != nil {
+		return err
+	}
+
+	for i, user := range users {
+		key := fmt.Sprintf("user:%d", (n*len(users)+i)%s.config.CacheKeys)
+		if err := s.cache.Set(ctx, key, []byte(user.Email), time.Minute); err != nil {
+			return err
+		}
+		if _, err := s.cache.Get(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
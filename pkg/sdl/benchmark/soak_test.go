@@ -0,0 +1,59 @@
+package benchmark
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSoak(t *testing.T) {
+	config := SoakConfig{
+		Scenario:         ScenarioAll,
+		Workers:          2,
+		RecordsPerCycle:  20,
+		Duration:         600 * time.Millisecond,
+		SnapshotInterval: 100 * time.Millisecond,
+		CacheKeys:        100,
+	}
+	soak, err := NewSoak("tmp/test_soak", config)
+	if err != nil {
+		t.Fatalf("Failed to create soak test: %v", err)
+	}
+	defer soak.Close()
+
+	var seen int
+	result, err := soak.OnSnapshot(func(Snapshot) { seen++ }).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Soak test failed: %v", err)
+	}
+
+	if len(result.Snapshots) < 3 || seen != len(result.Snapshots) {
+		t.Fatalf("Expected a baseline, periodic and final snapshots, got %d (%d reported)", len(result.Snapshots), seen)
+	}
+	if result.Ops() == 0 {
+		t.Fatal("Expected completed cycles")
+	}
+	for _, snap := range result.Snapshots {
+		if snap.Errors > 0 {
+			t.Errorf("Unexpected errors at %v: %s", snap.Elapsed, snap.LastError)
+		}
+	}
+
+	// Every writer, consumer and cache goroutine has exited once Run returns
+	if err := result.Check(SoakLimits{MaxGoroutineGrowth: 1, MaxErrorRate: 0.0001}); err != nil {
+		t.Errorf("Expected no leaks: %v", err)
+	}
+
+	t.Logf("✓ Soak test ran %d cycles over %v (heap %+d bytes, goroutines %+d)",
+		result.Ops(), result.Elapsed, result.HeapGrowth(), result.GoroutineGrowth())
+}
+
+func TestSoakRejectsUnknownScenario(t *testing.T) {
+	config := DefaultSoakConfig()
+	config.Scenario = "replay"
+	if _, err := NewSoak("tmp/test_soak_invalid", config); err == nil {
+		t.Fatal("Expected an unknown scenario to be rejected")
+	}
+
+	t.Log("✓ Unknown scenarios are rejected")
+}
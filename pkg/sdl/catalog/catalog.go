@@ -0,0 +1,331 @@
+// Package catalog tracks the files written to a managed data directory: what
+// each holds, how many records, which schema, its size and checksum, so
+// pipeline stages can find files by entity or age instead of by name.
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go-transport-prac/internal/types"
+)
+
+// Entry describes one written file
+type Entry struct {
+	// File is the name the file was written under, relative to its manager
+	File   string `json:"file"`
+	Entity string `json:"entity"`
+	Format string `json:"format"`
+	// SchemaVersion is the fingerprint of the schema the file was written
+	// with, so files written before and after a schema change tell apart
+	SchemaVersion string            `json:"schemaVersion"`
+	Records       int64             `json:"records"`
+	Bytes         int64             `json:"bytes"`
+	CreatedAt     time.Time         `json:"createdAt"`
+	SHA256        string            `json:"sha256"`
+	Tags          map[string]string `json:"tags,omitempty"`
+}
+
+// Store persists catalog entries
+type Store interface {
+	Load(ctx context.Context) ([]Entry, error)
+	Save(ctx context.Context, entries []Entry) error
+}
+
+// Query selects catalog entries. Zero fields match everything
+type Query struct {
+	Entity string
+	// From and To bound CreatedAt, From inclusive and To exclusive
+	From time.Time
+	To   time.Time
+	// Tags must all be set on an entry, with the same values
+	Tags map[string]string
+}
+
+func (q Query) matches(e Entry) bool {
+	if q.Entity != "" && e.Entity != q.Entity {
+		return false
+	}
+	if !q.From.IsZero() && e.CreatedAt.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !e.CreatedAt.Before(q.To) {
+		return false
+	}
+	for k, v := range q.Tags {
+		if e.Tags[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Catalog holds the entries of one data directory. It is safe for concurrent
+// use, and every change is saved to its store before it returns
+type Catalog struct {
+	mu      sync.RWMutex
+	entries map[string]Entry
+	store   Store
+	clock   types.Clock
+}
+
+// New returns an empty catalog kept in memory only
+func New() *Catalog {
+	return &Catalog{entries: make(map[string]Entry), clock: types.SystemClock{}}
+}
+
+// Open returns the catalog saved in store, empty if nothing was saved yet
+func Open(ctx context.Context, store Store) (*Catalog, error) {
+	c := New()
+	c.store = store
+	entries, err := store.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load catalog: %w", err)
+	}
+	for _, e := range entries {
+		c.entries[e.File] = e
+	}
+	return c, nil
+}
+
+// WithClock sets the clock used for the CreatedAt of recorded entries
+func (c *Catalog) WithClock(clock types.Clock) *Catalog {
+	c.clock = types.ClockOrSystem(clock)
+	return c
+}
+
+// Record adds an entry, replacing any entry for the same file. A zero
+// CreatedAt is set from the catalog's clock, and tags attached to ctx with
+// WithTags are added to the entry's own
+func (c *Catalog) Record(ctx context.Context, e Entry) error {
+	if e.File == "" {
+		return fmt.Errorf("catalog entry has no file name")
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = c.clock.Now()
+	}
+	if tags := TagsFrom(ctx); len(tags) > 0 || len(e.Tags) > 0 {
+		merged := maps.Clone(tags)
+		if merged == nil {
+			merged = make(map[string]string, len(e.Tags))
+		}
+		maps.Copy(merged, e.Tags)
+		e.Tags = merged
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	previous, existed := c.entries[e.File]
+	c.entries[e.File] = e
+	if err := c.save(ctx); err != nil {
+		if existed {
+			c.entries[e.File] = previous
+		} else {
+			delete(c.entries, e.File)
+		}
+		return err
+	}
+	return nil
+}
+
+// Tag sets a tag on the entry of file
+func (c *Catalog) Tag(ctx context.Context, file, key, value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[file]
+	if !ok {
+		return fmt.Errorf("file %s is not in the catalog", file)
+	}
+	previous := e
+	e.Tags = maps.Clone(e.Tags)
+	if e.Tags == nil {
+		e.Tags = make(map[string]string)
+	}
+	e.Tags[key] = value
+	c.entries[file] = e
+	if err := c.save(ctx); err != nil {
+		c.entries[file] = previous
+		return err
+	}
+	return nil
+}
+
+// Remove drops the entry of file, if any
+func (c *Catalog) Remove(ctx context.Context, file string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[file]
+	if !ok {
+		return nil
+	}
+	delete(c.entries, file)
+	if err := c.save(ctx); err != nil {
+		c.entries[file] = e
+		return err
+	}
+	return nil
+}
+
+// Get returns the entry of file
+func (c *Catalog) Get(file string) (Entry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, ok := c.entries[file]
+	return e, ok
+}
+
+// List returns the entries matching q, oldest first
+func (c *Catalog) List(q Query) []Entry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var out []Entry
+	for _, e := range c.entries {
+		if q.matches(e) {
+			out = append(out, e)
+		}
+	}
+	sortEntries(out)
+	return out
+}
+
+// ListByEntity returns the entries of files holding entity, oldest first
+func (c *Catalog) ListByEntity(entity string) []Entry {
+	return c.List(Query{Entity: entity})
+}
+
+// ListByDateRange returns the entries created in [from, to), oldest first
+func (c *Catalog) ListByDateRange(from, to time.Time) []Entry {
+	return c.List(Query{From: from, To: to})
+}
+
+// Files returns the file names of entries
+func Files(entries []Entry) []string {
+	files := make([]string, len(entries))
+	for i, e := range entries {
+		files[i] = e.File
+	}
+	return files
+}
+
+// Totals returns the records and bytes of entries
+func Totals(entries []Entry) (records, bytes int64) {
+	for _, e := range entries {
+		records += e.Records
+		bytes += e.Bytes
+	}
+	return records, bytes
+}
+
+// FileInfo returns e as the fs.FileInfo of a regular file, for files whose
+// backend has no metadata of its own
+func FileInfo(e Entry) fs.FileInfo {
+	return entryInfo{e}
+}
+
+type entryInfo struct {
+	e Entry
+}
+
+func (i entryInfo) Name() string       { return path.Base(i.e.File) }
+func (i entryInfo) Size() int64        { return i.e.Bytes }
+func (i entryInfo) Mode() fs.FileMode  { return 0644 }
+func (i entryInfo) ModTime() time.Time { return i.e.CreatedAt }
+func (i entryInfo) IsDir() bool        { return false }
+func (i entryInfo) Sys() any           { return i.e }
+
+func (c *Catalog) save(ctx context.Context) error {
+	if c.store == nil {
+		return nil
+	}
+	entries := slices.Collect(maps.Values(c.entries))
+	sortEntries(entries)
+	if err := c.store.Save(ctx, entries); err != nil {
+		return fmt.Errorf("failed to save catalog: %w", err)
+	}
+	return nil
+}
+
+func sortEntries(entries []Entry) {
+	slices.SortFunc(entries, func(a, b Entry) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.File, b.File)
+	})
+}
+
+// JSONStore keeps a catalog in a JSON file
+type JSONStore struct {
+	path string
+}
+
+// NewJSONStore returns a store keeping the catalog at path
+func NewJSONStore(path string) *JSONStore {
+	return &JSONStore{path: path}
+}
+
+// Load implements Store
+func (s *JSONStore) Load(context.Context) ([]Entry, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse catalog: %w", err)
+	}
+	return entries, nil
+}
+
+// Save implements Store. The file is replaced in one rename, so a crash
+// leaves either the old or the new catalog
+func (s *JSONStore) Save(_ context.Context, entries []Entry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal catalog: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write catalog: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace catalog: %w", err)
+	}
+	return nil
+}
+
+type tagsKey struct{}
+
+// WithTags returns a context whose writes are catalogued with tags, added to
+// any tags already attached to ctx
+func WithTags(ctx context.Context, tags map[string]string) context.Context {
+	merged := maps.Clone(TagsFrom(ctx))
+	if merged == nil {
+		merged = make(map[string]string, len(tags))
+	}
+	maps.Copy(merged, tags)
+	return context.WithValue(ctx, tagsKey{}, merged)
+}
+
+// TagsFrom returns the tags attached to ctx with WithTags
+func TagsFrom(ctx context.Context) map[string]string {
+	tags, _ := ctx.Value(tagsKey{}).(map[string]string)
+	return tags
+}
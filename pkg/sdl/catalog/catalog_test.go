@@ -0,0 +1,106 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/testutil"
+)
+
+func TestRecordAndQuery(t *testing.T) {
+	testDir := "tmp/test_catalog"
+	defer os.RemoveAll(testDir)
+	ctx := context.Background()
+	clock := testutil.NewDefaultFakeClock()
+	start := clock.Now()
+
+	c, err := Open(ctx, NewJSONStore(filepath.Join(testDir, "catalog.json")))
+	if err != nil {
+		t.Fatalf("Failed to open catalog: %v", err)
+	}
+	c.WithClock(clock)
+
+	record := func(ctx context.Context, e Entry) {
+		t.Helper()
+		if err := c.Record(ctx, e); err != nil {
+			t.Fatalf("Failed to record %s: %v", e.File, err)
+		}
+		clock.Advance(time.Hour)
+	}
+	record(ctx, Entry{File: "users_1.parquet", Entity: "user", Records: 10, Bytes: 100})
+	record(tagged(ctx, "stage", "raw"), Entry{File: "orders.parquet", Entity: "order", Records: 5, Bytes: 50})
+	record(tagged(ctx, "stage", "raw"), Entry{File: "users_2.parquet", Entity: "user", Records: 20, Bytes: 200, Tags: map[string]string{"stage": "compacted"}})
+
+	users := c.ListByEntity("user")
+	if len(users) != 2 || users[0].File != "users_1.parquet" || users[1].File != "users_2.parquet" {
+		t.Fatalf("Expected both user files oldest first, got %v", Files(users))
+	}
+	if users[0].CreatedAt != start {
+		t.Errorf("Expected CreatedAt from the clock, got %v", users[0].CreatedAt)
+	}
+	if records, bytes := Totals(users); records != 30 || bytes != 300 {
+		t.Errorf("Expected 30 records in 300 bytes, got %d in %d", records, bytes)
+	}
+	if got := c.ListByDateRange(start.Add(time.Hour), start.Add(2*time.Hour)); len(got) != 1 || got[0].File != "orders.parquet" {
+		t.Errorf("Expected only the second file in the second hour, got %v", Files(got))
+	}
+	// An entry's own tags win over those of the context
+	if got := c.List(Query{Tags: map[string]string{"stage": "compacted"}}); len(got) != 1 || got[0].File != "users_2.parquet" {
+		t.Errorf("Expected the compacted file, got %v", Files(got))
+	}
+
+	if err := c.Tag(ctx, "users_1.parquet", "owner", "etl"); err != nil {
+		t.Fatalf("Failed to tag: %v", err)
+	}
+	if err := c.Tag(ctx, "missing.parquet", "owner", "etl"); err == nil {
+		t.Error("Expected tagging an unknown file to fail")
+	}
+	if err := c.Remove(ctx, "orders.parquet"); err != nil {
+		t.Fatalf("Failed to remove: %v", err)
+	}
+
+	// A reopened catalog sees every change
+	reopened, err := Open(ctx, NewJSONStore(filepath.Join(testDir, "catalog.json")))
+	if err != nil {
+		t.Fatalf("Failed to reopen catalog: %v", err)
+	}
+	if e, ok := reopened.Get("users_1.parquet"); !ok || e.Tags["owner"] != "etl" || e.Records != 10 {
+		t.Errorf("Expected the tagged entry to persist, got %+v", e)
+	}
+	if _, ok := reopened.Get("orders.parquet"); ok {
+		t.Error("Expected the removed entry to stay removed")
+	}
+
+	t.Log("✓ Entries persist and are found by entity, date and tag")
+}
+
+func tagged(ctx context.Context, key, value string) context.Context {
+	return WithTags(ctx, map[string]string{key: value})
+}
+
+type failingStore struct{}
+
+func (failingStore) Load(context.Context) ([]Entry, error) { return nil, nil }
+
+func (failingStore) Save(context.Context, []Entry) error { return errors.New("disk full") }
+
+func TestFailedSavesAreRolledBack(t *testing.T) {
+	ctx := context.Background()
+	c, _ := Open(ctx, failingStore{})
+
+	if err := c.Record(ctx, Entry{File: "users.parquet"}); err == nil {
+		t.Fatal("Expected the save to fail")
+	}
+	if _, ok := c.Get("users.parquet"); ok {
+		t.Error("Expected an entry that was not saved to be dropped")
+	}
+	if err := c.Record(ctx, Entry{}); err == nil {
+		t.Error("Expected an entry without a file name to fail")
+	}
+
+	t.Log("✓ The catalog only holds what its store saved")
+}
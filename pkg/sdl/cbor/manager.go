@@ -0,0 +1,253 @@
+// Package cbor serializes the shared Avro models as CBOR (RFC 8949), a compact
+// schemaless binary format common on constrained devices. Field names come
+// from the models' json tags, times are tagged date/time strings and the
+// canonical option produces byte-for-byte deterministic output.
+package cbor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"go-transport-prac/internal/recordio"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+// ContentType is the media type of CBOR payloads
+const ContentType = "application/cbor"
+
+// FileExtension is the extension of CBOR files
+const FileExtension = ".cbor"
+
+// Manager handles CBOR serialization and deserialization
+type Manager struct {
+	baseDir   string
+	canonical bool
+	enc       cbor.EncMode
+	dec       cbor.DecMode
+}
+
+var _ types.Serializer = (*Manager)(nil)
+
+// NewManager creates a new CBOR manager writing files under baseDir
+func NewManager(baseDir string) *Manager {
+	if baseDir == "" {
+		baseDir = "data/cbor"
+	}
+	m := &Manager{baseDir: baseDir}
+	m.buildModes()
+	return m
+}
+
+// WithCanonical switches to Core Deterministic Encoding (RFC 8949 §4.2):
+// map keys and struct fields are sorted, integers and floats take their
+// shortest form and indefinite lengths are not used, so equal values always
+// encode to equal bytes. Decoding then rejects duplicate map keys
+func (m *Manager) WithCanonical(on bool) *Manager {
+	m.canonical = on
+	m.buildModes()
+	return m
+}
+
+// Canonical reports whether the manager encodes deterministically
+func (m *Manager) Canonical() bool {
+	return m.canonical
+}
+
+// buildModes derives the encoding and decoding modes from the options. The
+// options are constants, so building the modes cannot fail
+func (m *Manager) buildModes() {
+	encOpts := cbor.EncOptions{}
+	decOpts := cbor.DecOptions{DupMapKey: cbor.DupMapKeyQuiet}
+	if m.canonical {
+		encOpts = cbor.CoreDetEncOptions()
+		decOpts.DupMapKey = cbor.DupMapKeyEnforcedAPF
+	}
+	// Times are tag 0 RFC 3339 strings, which keep nanoseconds and the zone
+	encOpts.Time = cbor.TimeRFC3339Nano
+	encOpts.TimeTag = cbor.EncTagRequired
+	encOpts.TextMarshaler = cbor.TextMarshalerTextString
+	decOpts.TextUnmarshaler = cbor.TextUnmarshalerTextString
+	// Generic maps decode with string keys, as they do from JSON
+	decOpts.DefaultMapType = reflect.TypeOf(map[string]any(nil))
+
+	var err error
+	if m.enc, err = encOpts.EncMode(); err != nil {
+		panic(fmt.Sprintf("invalid cbor encoding options: %v", err))
+	}
+	if m.dec, err = decOpts.DecMode(); err != nil {
+		panic(fmt.Sprintf("invalid cbor decoding options: %v", err))
+	}
+}
+
+// ContentType returns the media type of CBOR payloads
+func (m *Manager) ContentType() string {
+	return ContentType
+}
+
+// FileExtension returns the extension of CBOR files
+func (m *Manager) FileExtension() string {
+	return FileExtension
+}
+
+// Serialize encodes any value as one CBOR data item
+func (m *Manager) Serialize(data any) ([]byte, error) {
+	encoded, err := m.enc.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode: %w", err)
+	}
+	return encoded, nil
+}
+
+// Deserialize decodes one CBOR data item into target, a pointer. Trailing
+// bytes after the item are rejected
+func (m *Manager) Deserialize(data []byte, target any) error {
+	if len(data) == 0 {
+		return fmt.Errorf("data cannot be empty")
+	}
+	if err := m.dec.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("failed to decode: %w", err)
+	}
+	return nil
+}
+
+// SerializeUser serializes a user to CBOR
+func (m *Manager) SerializeUser(user avro.User) ([]byte, error) {
+	return m.Serialize(user)
+}
+
+// DeserializeUser deserializes a user from CBOR
+func (m *Manager) DeserializeUser(data []byte) (avro.User, error) {
+	var user avro.User
+	if err := m.Deserialize(data, &user); err != nil {
+		return avro.User{}, fmt.Errorf("failed to deserialize user: %w", err)
+	}
+	return user, nil
+}
+
+// SerializeProduct serializes a product to CBOR
+func (m *Manager) SerializeProduct(product avro.Product) ([]byte, error) {
+	return m.Serialize(product)
+}
+
+// DeserializeProduct deserializes a product from CBOR
+func (m *Manager) DeserializeProduct(data []byte) (avro.Product, error) {
+	var product avro.Product
+	if err := m.Deserialize(data, &product); err != nil {
+		return avro.Product{}, fmt.Errorf("failed to deserialize product: %w", err)
+	}
+	return product, nil
+}
+
+// SerializeOrder serializes an order to CBOR
+func (m *Manager) SerializeOrder(order avro.Order) ([]byte, error) {
+	return m.Serialize(order)
+}
+
+// DeserializeOrder deserializes an order from CBOR
+func (m *Manager) DeserializeOrder(data []byte) (avro.Order, error) {
+	var order avro.Order
+	if err := m.Deserialize(data, &order); err != nil {
+		return avro.Order{}, fmt.Errorf("failed to deserialize order: %w", err)
+	}
+	return order, nil
+}
+
+// StreamEncoder writes CBOR data items to a stream back to back
+type StreamEncoder struct {
+	enc   *cbor.Encoder
+	count int
+}
+
+// NewStreamEncoder creates an encoder writing to w
+func (m *Manager) NewStreamEncoder(w io.Writer) *StreamEncoder {
+	return &StreamEncoder{enc: m.enc.NewEncoder(w)}
+}
+
+// Encode writes one value as the next data item
+func (e *StreamEncoder) Encode(v any) error {
+	if err := e.enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode item %d: %w", e.count, err)
+	}
+	e.count++
+	return nil
+}
+
+// Count returns the number of items written
+func (e *StreamEncoder) Count() int {
+	return e.count
+}
+
+// StreamDecoder reads CBOR data items from a stream one at a time
+type StreamDecoder struct {
+	dec   *cbor.Decoder
+	count int
+}
+
+// NewStreamDecoder creates a decoder reading from r
+func (m *Manager) NewStreamDecoder(r io.Reader) *StreamDecoder {
+	return &StreamDecoder{dec: m.dec.NewDecoder(r)}
+}
+
+// Decode reads the next data item into target, a pointer. It returns io.EOF
+// once the stream ends between items; a stream ending inside an item is an error
+func (d *StreamDecoder) Decode(target any) error {
+	if err := d.dec.Decode(target); err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.EOF
+		}
+		return fmt.Errorf("failed to decode item %d: %w", d.count, err)
+	}
+	d.count++
+	return nil
+}
+
+// Count returns the number of items read
+func (d *StreamDecoder) Count() int {
+	return d.count
+}
+
+// EncodeUsers writes users to w as CBOR data items back to back
+func (m *Manager) EncodeUsers(w io.Writer, users []avro.User) error {
+	return recordio.EncodeAll(m.enc.NewEncoder(w), users)
+}
+
+// DecodeUsers reads CBOR user data items from r until it ends
+func (m *Manager) DecodeUsers(r io.Reader) ([]avro.User, error) {
+	return recordio.DecodeAll[avro.User](m.dec.NewDecoder(r))
+}
+
+// WriteUsersToFile writes users to a CBOR file under the base directory
+func (m *Manager) WriteUsersToFile(filename string, users []avro.User) error {
+	return recordio.WriteFile(filepath.Join(m.baseDir, filename), m.enc.NewEncoder, users)
+}
+
+// ReadUsersFromFile reads users from a CBOR file under the base directory
+func (m *Manager) ReadUsersFromFile(filename string) ([]avro.User, error) {
+	return recordio.ReadFile[avro.User](filepath.Join(m.baseDir, filename), m.dec.NewDecoder)
+}
+
+// WriteProductsToFile writes products to a CBOR file under the base directory
+func (m *Manager) WriteProductsToFile(filename string, products []avro.Product) error {
+	return recordio.WriteFile(filepath.Join(m.baseDir, filename), m.enc.NewEncoder, products)
+}
+
+// ReadProductsFromFile reads products from a CBOR file under the base directory
+func (m *Manager) ReadProductsFromFile(filename string) ([]avro.Product, error) {
+	return recordio.ReadFile[avro.Product](filepath.Join(m.baseDir, filename), m.dec.NewDecoder)
+}
+
+// WriteOrdersToFile writes orders to a CBOR file under the base directory
+func (m *Manager) WriteOrdersToFile(filename string, orders []avro.Order) error {
+	return recordio.WriteFile(filepath.Join(m.baseDir, filename), m.enc.NewEncoder, orders)
+}
+
+// ReadOrdersFromFile reads orders from a CBOR file under the base directory
+func (m *Manager) ReadOrdersFromFile(filename string) ([]avro.Order, error) {
+	return recordio.ReadFile[avro.Order](filepath.Join(m.baseDir, filename), m.dec.NewDecoder)
+}
@@ -0,0 +1,270 @@
+package cbor
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+func samples(t *testing.T) *avro.Manager {
+	t.Helper()
+	manager, err := avro.NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create avro manager: %v", err)
+	}
+	return manager.WithClock(testutil.NewDefaultFakeClock())
+}
+
+func sampleUsers(t *testing.T, count int) []avro.User {
+	return samples(t).CreateSampleUsers(count)
+}
+
+func sampleOrder(id int64) avro.Order {
+	created := time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.UTC)
+	shipped := created.Add(2 * time.Hour)
+	tracking := "TRK00000001"
+	usd := func(cents int64) avro.Price { return avro.Price{Currency: "USD", AmountCents: cents} }
+	return avro.Order{
+		ID:          id,
+		UserID:      7,
+		OrderNumber: "ORD-000001",
+		Status:      avro.OrderStatusShipped,
+		Items: []avro.OrderItem{
+			{ProductID: 1, ProductName: "Sensor", ProductSKU: "SKU-000001", Quantity: 2, UnitPrice: usd(1500), TotalPrice: usd(3000)},
+		},
+		Summary: avro.OrderSummary{
+			Subtotal: usd(3000), Tax: usd(300), ShippingCost: usd(500), Discount: usd(0), Total: usd(3800), TotalItems: 1,
+		},
+		ShippingInfo: &avro.ShippingInfo{
+			Address:        avro.ShippingAddress{RecipientName: "User 7", Street: "1 Main St", City: "Test City", State: "TS", PostalCode: "10000", Country: "USA"},
+			Method:         "standard",
+			TrackingNumber: &tracking,
+			Cost:           usd(500),
+		},
+		CreatedAt: created,
+		UpdatedAt: shipped,
+		ShippedAt: &shipped,
+	}
+}
+
+func TestCBORRoundTrip(t *testing.T) {
+	manager := NewManager("")
+
+	user := sampleUsers(t, 1)[0]
+	data, err := manager.SerializeUser(user)
+	if err != nil {
+		t.Fatalf("Failed to serialize user: %v", err)
+	}
+	back, err := manager.DeserializeUser(data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize user: %v", err)
+	}
+	if !reflect.DeepEqual(back, user) {
+		t.Errorf("User changed across CBOR:\n got %+v\nwant %+v", back, user)
+	}
+
+	// Decimals travel as text and keep their exact value
+	product := samples(t).CreateSampleProducts(1)[0]
+	release := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	product.ReleaseDate = &release
+	product.Price.Amount = avro.NewDecimal(123456, 2)
+	data, err = manager.SerializeProduct(product)
+	if err != nil {
+		t.Fatalf("Failed to serialize product: %v", err)
+	}
+	backProduct, err := manager.DeserializeProduct(data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize product: %v", err)
+	}
+	if backProduct.Price.Amount == nil || backProduct.Price.Amount.Cmp(product.Price.Amount) != 0 {
+		t.Errorf("Expected amount %s, got %s", product.Price.Amount, backProduct.Price.Amount)
+	}
+	backProduct.Price.Amount, product.Price.Amount = nil, nil
+	if !reflect.DeepEqual(backProduct, product) {
+		t.Errorf("Product changed across CBOR:\n got %+v\nwant %+v", backProduct, product)
+	}
+
+	// Nanoseconds survive in the tagged times
+	order := sampleOrder(1)
+	data, err = manager.SerializeOrder(order)
+	if err != nil {
+		t.Fatalf("Failed to serialize order: %v", err)
+	}
+	backOrder, err := manager.DeserializeOrder(data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize order: %v", err)
+	}
+	if !reflect.DeepEqual(backOrder, order) {
+		t.Errorf("Order changed across CBOR:\n got %+v\nwant %+v", backOrder, order)
+	}
+
+	if _, err := manager.DeserializeUser(nil); err == nil {
+		t.Error("Expected empty data to be rejected")
+	}
+	if _, err := manager.DeserializeUser([]byte{0xff}); err == nil {
+		t.Error("Expected an invalid data item to be rejected")
+	}
+	if _, err := manager.DeserializeOrder(append(data, 0x01)); err == nil {
+		t.Error("Expected trailing bytes to be rejected")
+	}
+
+	t.Log("✓ Users, products and orders round trip through CBOR")
+}
+
+func TestCBORCanonical(t *testing.T) {
+	canonical := NewManager("").WithCanonical(true)
+	if !canonical.Canonical() || NewManager("").Canonical() {
+		t.Fatal("Expected only the canonical manager to report canonical encoding")
+	}
+
+	// Keys sort by length, then bytewise: "a", "b", "aa"
+	data, err := canonical.Serialize(map[string]int{"aa": 1, "b": 2, "a": 3})
+	if err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+	if got := hex.EncodeToString(data); got != "a361610361620262616101" {
+		t.Errorf("Unexpected canonical map encoding %s", got)
+	}
+
+	// Equal users encode to equal bytes however often they are encoded
+	user := sampleUsers(t, 1)[0]
+	first, _ := canonical.SerializeUser(user)
+	for i := 0; i < 10; i++ {
+		again, err := canonical.SerializeUser(user)
+		if err != nil || !bytes.Equal(again, first) {
+			t.Fatalf("Canonical encoding %d differs: %v", i, err)
+		}
+	}
+
+	// Duplicate keys are rejected only by the canonical manager
+	duplicate := []byte{0xa2, 0x61, 'a', 0x01, 0x61, 'a', 0x02}
+	var generic map[string]any
+	if err := canonical.Deserialize(duplicate, &generic); err == nil {
+		t.Error("Expected duplicate keys to be rejected")
+	}
+	if err := NewManager("").Deserialize(duplicate, &generic); err != nil {
+		t.Errorf("Expected duplicate keys to be accepted by default: %v", err)
+	}
+
+	t.Log("✓ Canonical CBOR is deterministic")
+}
+
+func TestCBORTagsAndKeys(t *testing.T) {
+	manager := NewManager("")
+
+	// Times are tag 0 RFC 3339 strings
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	data, err := manager.Serialize(at)
+	if err != nil {
+		t.Fatalf("Failed to serialize time: %v", err)
+	}
+	if want := append([]byte{0xc0, 0x74}, "2024-01-02T03:04:05Z"...); !bytes.Equal(data, want) {
+		t.Errorf("Expected tagged time %x, got %x", want, data)
+	}
+	// Tag 1 epoch times from other encoders decode too
+	var decoded time.Time
+	if err := manager.Deserialize([]byte{0xc1, 0x1a, 0x65, 0x93, 0x7d, 0x25}, &decoded); err != nil || !decoded.Equal(at) {
+		t.Errorf("Expected epoch time %v, got %v (%v)", at, decoded, err)
+	}
+
+	// Field names are the json ones and generic maps have string keys
+	user := sampleUsers(t, 1)[0]
+	data, err = manager.SerializeUser(user)
+	if err != nil {
+		t.Fatalf("Failed to serialize user: %v", err)
+	}
+	var generic map[string]any
+	if err := manager.Deserialize(data, &generic); err != nil {
+		t.Fatalf("Failed to decode into a map: %v", err)
+	}
+	if generic["email"] != user.Email || generic["createdAt"] == nil {
+		t.Errorf("Expected json field names, got %v", generic)
+	}
+	if _, ok := generic["profile"].(map[string]any); !ok {
+		t.Errorf("Expected a nested map with string keys, got %T", generic["profile"])
+	}
+
+	jsonData, _ := json.Marshal(user)
+	if len(data) >= len(jsonData) {
+		t.Errorf("Expected CBOR (%d bytes) to be smaller than JSON (%d bytes)", len(data), len(jsonData))
+	}
+	if manager.ContentType() != "application/cbor" || manager.FileExtension() != ".cbor" {
+		t.Errorf("Unexpected content type %s or extension %s", manager.ContentType(), manager.FileExtension())
+	}
+
+	t.Log("✓ CBOR uses tagged times and json field names")
+}
+
+func TestCBORStreams(t *testing.T) {
+	testDir := "tmp/test_cbor_streams"
+	manager := NewManager(testDir).WithCanonical(true)
+	defer os.RemoveAll(testDir)
+
+	// Items of different types share one stream
+	var buf bytes.Buffer
+	enc := manager.NewStreamEncoder(&buf)
+	readings := []map[string]float64{{"temperature": 21.5}, {"temperature": 22}, {"humidity": 0.4}}
+	for _, reading := range readings {
+		if err := enc.Encode(reading); err != nil {
+			t.Fatalf("Failed to encode reading: %v", err)
+		}
+	}
+	if err := enc.Encode(sampleOrder(2)); err != nil {
+		t.Fatalf("Failed to encode order: %v", err)
+	}
+	if enc.Count() != 4 {
+		t.Errorf("Expected 4 items written, got %d", enc.Count())
+	}
+
+	dec := manager.NewStreamDecoder(bytes.NewReader(buf.Bytes()))
+	for i, want := range readings {
+		var reading map[string]float64
+		if err := dec.Decode(&reading); err != nil || !reflect.DeepEqual(reading, want) {
+			t.Fatalf("Reading %d: expected %v, got %v (%v)", i, want, reading, err)
+		}
+	}
+	var order avro.Order
+	if err := dec.Decode(&order); err != nil || order.ID != 2 {
+		t.Fatalf("Expected order 2, got %d (%v)", order.ID, err)
+	}
+	if err := dec.Decode(&order); err != io.EOF || dec.Count() != 4 {
+		t.Errorf("Expected io.EOF after 4 items, got %v after %d", err, dec.Count())
+	}
+
+	// A truncated stream fails instead of dropping the last user
+	users := sampleUsers(t, 20)
+	buf.Reset()
+	if err := manager.EncodeUsers(&buf, users[:2]); err != nil {
+		t.Fatalf("Failed to encode users: %v", err)
+	}
+	if _, err := manager.DecodeUsers(bytes.NewReader(buf.Bytes()[:buf.Len()-3])); err == nil {
+		t.Error("Expected a truncated stream to fail")
+	}
+
+	if err := manager.WriteUsersToFile("users.cbor", users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	if back, err := manager.ReadUsersFromFile("users.cbor"); err != nil || !reflect.DeepEqual(back, users) {
+		t.Errorf("Users changed across the file (%v)", err)
+	}
+	orders := []avro.Order{sampleOrder(1), sampleOrder(2)}
+	if err := manager.WriteOrdersToFile("orders.cbor", orders); err != nil {
+		t.Fatalf("Failed to write orders: %v", err)
+	}
+	if back, err := manager.ReadOrdersFromFile("orders.cbor"); err != nil || !reflect.DeepEqual(back, orders) {
+		t.Errorf("Orders changed across the file (%v)", err)
+	}
+	if _, err := manager.ReadProductsFromFile("missing.cbor"); err == nil {
+		t.Error("Expected a missing file to fail")
+	}
+
+	t.Log("✓ CBOR streams and files hold back-to-back items")
+}
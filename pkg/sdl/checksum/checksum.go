@@ -0,0 +1,205 @@
+// Package checksum records SHA-256 checksums of written files in sidecar
+// files and verifies files against them, so corruption is caught when a file
+// is checked rather than when a reader trips over it. Sidecars use the
+// sha256sum format, so `sha256sum -c users.avro.sha256` checks them too.
+package checksum
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"go-transport-prac/internal/atomicfile"
+	"go-transport-prac/internal/ctxio"
+	"go-transport-prac/internal/types"
+)
+
+// Extension is appended to a file name to name its sidecar
+const Extension = ".sha256"
+
+// ErrNoChecksum is returned when verifying a file that has no sidecar
+var ErrNoChecksum = errors.New("no checksum recorded")
+
+// MismatchError is returned when a file does not match its recorded checksum
+type MismatchError struct {
+	File     string
+	Expected string
+	Actual   string
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("checksum mismatch for %s: expected %s, got %s", e.File, e.Expected, e.Actual)
+}
+
+// SidecarName returns the name of the sidecar of filename
+func SidecarName(filename string) string {
+	return filename + Extension
+}
+
+// Writer hashes everything written through it
+type Writer struct {
+	w    io.Writer
+	hash hash.Hash
+	n    int64
+}
+
+// NewWriter returns a writer hashing what it passes on to w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w, hash: sha256.New()}
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.hash.Write(p[:n])
+	w.n += int64(n)
+	return n, err
+}
+
+// Sum returns the hex encoded checksum of the bytes written so far
+func (w *Writer) Sum() string {
+	return hex.EncodeToString(w.hash.Sum(nil))
+}
+
+// Size returns the number of bytes written so far
+func (w *Writer) Size() int64 {
+	return w.n
+}
+
+// Compute returns the hex encoded checksum of r and its size
+func Compute(r io.Reader) (string, int64, error) {
+	w := NewWriter(io.Discard)
+	if _, err := io.Copy(w, r); err != nil {
+		return "", 0, fmt.Errorf("failed to hash file: %w", err)
+	}
+	return w.Sum(), w.Size(), nil
+}
+
+// Format returns the sidecar contents recording sum for filename
+func Format(sum, filename string) []byte {
+	return []byte(sum + "  " + path.Base(filepath.ToSlash(filename)) + "\n")
+}
+
+// Parse returns the checksum recorded in sidecar contents
+func Parse(data []byte) (string, error) {
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty checksum file")
+	}
+	sum := strings.ToLower(fields[0])
+	if decoded, err := hex.DecodeString(sum); err != nil || len(decoded) != sha256.Size {
+		return "", fmt.Errorf("invalid checksum %q", fields[0])
+	}
+	return sum, nil
+}
+
+// Verify checks r against the sidecar contents recorded for name
+func Verify(r io.Reader, sidecar []byte, name string) error {
+	expected, err := Parse(sidecar)
+	if err != nil {
+		return fmt.Errorf("failed to read checksum of %s: %w", name, err)
+	}
+	actual, _, err := Compute(r)
+	if err != nil {
+		return err
+	}
+	if actual != expected {
+		return &MismatchError{File: name, Expected: expected, Actual: actual}
+	}
+	return nil
+}
+
+// WriteSidecar records sum next to the local file at filePath, replacing any
+// previous sidecar atomically
+func WriteSidecar(filePath, sum string) error {
+	if err := atomicfile.WriteFile(SidecarName(filePath), Format(sum, filePath), false); err != nil {
+		return fmt.Errorf("failed to write checksum: %w", err)
+	}
+	return nil
+}
+
+// VerifyFile checks the local file at filePath against its sidecar, stopping
+// once ctx is done
+func VerifyFile(ctx context.Context, filePath string) error {
+	sidecar, err := os.ReadFile(SidecarName(filePath))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%s: %w", filePath, ErrNoChecksum)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read checksum: %w", err)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+	return Verify(ctxio.NewReader(ctx, file), sidecar, filePath)
+}
+
+// RemoveSidecar removes the sidecar of the local file at filePath, if any
+func RemoveSidecar(filePath string) error {
+	if err := os.Remove(SidecarName(filePath)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove checksum: %w", err)
+	}
+	return nil
+}
+
+// PutSidecar records sum for the object key in storage
+func PutSidecar(ctx context.Context, storage types.Storage, key, sum string) error {
+	if err := storage.Put(ctx, SidecarName(key), bytes.NewReader(Format(sum, key))); err != nil {
+		return fmt.Errorf("failed to store checksum: %w", err)
+	}
+	return nil
+}
+
+// VerifyObject checks the object key in storage against its sidecar
+func VerifyObject(ctx context.Context, storage types.Storage, key string) error {
+	exists, err := storage.Exists(ctx, SidecarName(key))
+	if err != nil {
+		return fmt.Errorf("failed to look up checksum: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("%s: %w", key, ErrNoChecksum)
+	}
+	sidecar, err := readObject(ctx, storage, SidecarName(key))
+	if err != nil {
+		return fmt.Errorf("failed to read checksum: %w", err)
+	}
+
+	obj, err := storage.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer obj.Close()
+	return Verify(ctxio.NewReader(ctx, obj), sidecar, key)
+}
+
+// RemoveObjectSidecar removes the sidecar of the object key, if any
+func RemoveObjectSidecar(ctx context.Context, storage types.Storage, key string) error {
+	exists, err := storage.Exists(ctx, SidecarName(key))
+	if err != nil || !exists {
+		return err
+	}
+	if err := storage.Delete(ctx, SidecarName(key)); err != nil {
+		return fmt.Errorf("failed to remove checksum: %w", err)
+	}
+	return nil
+}
+
+func readObject(ctx context.Context, storage types.Storage, key string) ([]byte, error) {
+	obj, err := storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+	return io.ReadAll(obj)
+}
@@ -0,0 +1,116 @@
+package checksum
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-transport-prac/pkg/storage"
+)
+
+func TestSidecarRoundTrip(t *testing.T) {
+	testDir := "tmp/test_checksum"
+	defer os.RemoveAll(testDir)
+	if err := os.MkdirAll(testDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	ctx := context.Background()
+
+	path := filepath.Join(testDir, "users.avro")
+	var file bytes.Buffer
+	w := NewWriter(&file)
+	w.Write([]byte("Obj\x01 some records"))
+	if err := os.WriteFile(path, file.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := WriteSidecar(path, w.Sum()); err != nil {
+		t.Fatalf("Failed to write sidecar: %v", err)
+	}
+
+	sidecar, _ := os.ReadFile(SidecarName(path))
+	if !strings.HasSuffix(string(sidecar), "  users.avro\n") || w.Size() != int64(file.Len()) {
+		t.Errorf("Unexpected sidecar %q", sidecar)
+	}
+	if err := VerifyFile(ctx, path); err != nil {
+		t.Fatalf("Expected the file to verify: %v", err)
+	}
+
+	// sha256sum reads the same format, where it is installed
+	if _, err := exec.LookPath("sha256sum"); err == nil {
+		cmd := exec.Command("sha256sum", "-c", "users.avro.sha256")
+		cmd.Dir = testDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("sha256sum rejected the sidecar: %v\n%s", err, out)
+		}
+	}
+
+	os.WriteFile(path, []byte("Obj\x01 some recordz"), 0644)
+	var mismatch *MismatchError
+	if err := VerifyFile(ctx, path); !errors.As(err, &mismatch) || mismatch.Expected != w.Sum() {
+		t.Errorf("Expected a mismatch, got %v", err)
+	}
+
+	if err := RemoveSidecar(path); err != nil {
+		t.Fatalf("Failed to remove sidecar: %v", err)
+	}
+	if err := VerifyFile(ctx, path); !errors.Is(err, ErrNoChecksum) {
+		t.Errorf("Expected ErrNoChecksum, got %v", err)
+	}
+	if err := RemoveSidecar(path); err != nil {
+		t.Errorf("Expected removing a missing sidecar to succeed, got %v", err)
+	}
+
+	t.Log("✓ Sidecars record checksums that catch modified files")
+}
+
+func TestObjects(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	data := []byte("PAR1 columns PAR1")
+	sum, _, _ := Compute(bytes.NewReader(data))
+
+	store.Put(ctx, "users.parquet", bytes.NewReader(data))
+	if err := VerifyObject(ctx, store, "users.parquet"); !errors.Is(err, ErrNoChecksum) {
+		t.Errorf("Expected ErrNoChecksum, got %v", err)
+	}
+	if err := PutSidecar(ctx, store, "users.parquet", sum); err != nil {
+		t.Fatalf("Failed to store sidecar: %v", err)
+	}
+	if err := VerifyObject(ctx, store, "users.parquet"); err != nil {
+		t.Errorf("Expected the object to verify: %v", err)
+	}
+
+	store.Put(ctx, "users.parquet", bytes.NewReader(data[:len(data)-1]))
+	var mismatch *MismatchError
+	if err := VerifyObject(ctx, store, "users.parquet"); !errors.As(err, &mismatch) {
+		t.Errorf("Expected a truncated object to mismatch, got %v", err)
+	}
+
+	if err := RemoveObjectSidecar(ctx, store, "users.parquet"); err != nil {
+		t.Fatalf("Failed to remove sidecar: %v", err)
+	}
+	if exists, _ := store.Exists(ctx, SidecarName("users.parquet")); exists {
+		t.Error("Expected the sidecar to be removed")
+	}
+
+	t.Log("✓ Objects in storage verify against sidecar objects")
+}
+
+func TestParse(t *testing.T) {
+	valid := strings.Repeat("ab", 32)
+	if sum, err := Parse([]byte(strings.ToUpper(valid) + "  file\n")); err != nil || sum != valid {
+		t.Errorf("Expected %s, got %q (%v)", valid, sum, err)
+	}
+	for _, bad := range []string{"", "\n", "xyz  file", strings.Repeat("ab", 16) + "  file"} {
+		if _, err := Parse([]byte(bad)); err == nil {
+			t.Errorf("Expected %q to fail", bad)
+		}
+	}
+
+	t.Log("✓ Malformed sidecars are rejected")
+}
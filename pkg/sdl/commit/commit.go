@@ -0,0 +1,432 @@
+// Package commit publishes a set of related files as one unit. Files are written
+// to a staging directory, moved into a versioned commit directory and only become
+// visible once the dataset manifest is atomically replaced to point at them.
+package commit
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-transport-prac/internal/atomicfile"
+	"go-transport-prac/internal/filelock"
+	"go-transport-prac/internal/types"
+)
+
+const (
+	// stagingDir holds transactions that have not been committed yet
+	stagingDir = "_staging"
+	// manifestSuffix is appended to the dataset name to form the manifest file name
+	manifestSuffix = ".manifest.json"
+	// StaleStagingAge is how long a staging directory of another process must
+	// sit untouched before Recover treats its writer as crashed
+	StaleStagingAge = 24 * time.Hour
+)
+
+var (
+	// owner marks the staging directories of this process
+	owner = randomHex(4)
+	// open holds the staging directories of this process's unfinished transactions
+	open sync.Map
+)
+
+// FileEntry describes one committed file
+type FileEntry struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest is the committed state of a dataset. Readers resolve files through it
+// and never look at commit directories directly.
+type Manifest struct {
+	Dataset     string      `json:"dataset"`
+	Version     int         `json:"version"`
+	CommitDir   string      `json:"commitDir"`
+	CommittedAt time.Time   `json:"committedAt"`
+	Files       []FileEntry `json:"files"`
+
+	dir string
+}
+
+// Path returns the absolute path of a committed file
+func (m *Manifest) Path(name string) (string, bool) {
+	for _, f := range m.Files {
+		if f.Name == name {
+			return filepath.Join(m.dir, m.CommitDir, name), true
+		}
+	}
+	return "", false
+}
+
+// Verify checks that every committed file still has its recorded size and checksum
+func (m *Manifest) Verify() error {
+	for _, f := range m.Files {
+		size, sum, err := checksum(filepath.Join(m.dir, m.CommitDir, f.Name))
+		if err != nil {
+			return fmt.Errorf("failed to checksum %s: %w", f.Name, err)
+		}
+		if size != f.Size || sum != f.SHA256 {
+			return fmt.Errorf("file %s does not match manifest", f.Name)
+		}
+	}
+	return nil
+}
+
+// ReadManifest loads the current manifest of dataset in dir.
+// It returns an error satisfying os.IsNotExist when nothing was committed yet.
+func ReadManifest(dir, dataset string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, dataset+manifestSuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	m.dir = dir
+	return &m, nil
+}
+
+// Committer creates transactions for one dataset in a directory
+type Committer struct {
+	dir     string
+	dataset string
+	clock   types.Clock
+}
+
+// NewCommitter creates a committer for dataset in dir
+func NewCommitter(dir, dataset string) *Committer {
+	return &Committer{
+		dir:     dir,
+		dataset: dataset,
+		clock:   types.SystemClock{},
+	}
+}
+
+// WithClock sets the clock used for CommittedAt timestamps
+func (c *Committer) WithClock(clock types.Clock) *Committer {
+	c.clock = types.ClockOrSystem(clock)
+	return c
+}
+
+// Begin starts a transaction with an empty staging directory
+func (c *Committer) Begin() (*Transaction, error) {
+	staging := filepath.Join(c.dir, stagingDir, c.dataset+"-"+owner+"-"+randomHex(8))
+	if err := os.MkdirAll(staging, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	open.Store(staging, true)
+	return &Transaction{committer: c, dir: staging}, nil
+}
+
+// Recover removes what transactions that crashed before their manifest swap
+// left behind: commit directories newer than the manifest, and staging
+// directories. Staging directories of this process's open transactions are
+// kept, and those of other processes are only removed once StaleStagingAge
+// passes without a write, so concurrent writers sharing dir are not disturbed.
+// Commits of this process are never caught between their move and manifest
+// swap, but a commit directory another process is about to publish looks
+// like an orphan, so Recover should run before other processes commit
+func (c *Committer) Recover() error {
+	unlock := filelock.Files.Lock(c.lockKey())
+	defer unlock()
+
+	staged, err := filepath.Glob(filepath.Join(c.dir, stagingDir, c.dataset+"-*"))
+	if err != nil {
+		return fmt.Errorf("failed to list staging directories: %w", err)
+	}
+	for _, dir := range staged {
+		if !c.abandoned(dir) {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to remove staging directory: %w", err)
+		}
+	}
+
+	current, err := c.currentManifest()
+	if err != nil {
+		return err
+	}
+	if err := c.removeVersions(func(version int) bool {
+		return current == nil || version > current.Version
+	}); err != nil {
+		return err
+	}
+	return c.prune(current)
+}
+
+// abandoned reports whether no transaction is writing to the staging
+// directory dir any more. Modification times come from the file system's
+// clock, so they are compared against the wall clock
+func (c *Committer) abandoned(dir string) bool {
+	if _, ok := open.Load(dir); ok {
+		return false
+	}
+	// <dataset>-<owner>-<id>; more dashes belong to a dataset named like ours
+	id := strings.TrimPrefix(filepath.Base(dir), c.dataset+"-")
+	if strings.Count(id, "-") > 1 {
+		return false
+	}
+	if strings.HasPrefix(id, owner+"-") {
+		return true
+	}
+	info, err := os.Stat(dir)
+	return err == nil && time.Since(info.ModTime()) > StaleStagingAge
+}
+
+// currentManifest returns the committed manifest, or nil if there is none
+func (c *Committer) currentManifest() (*Manifest, error) {
+	m, err := ReadManifest(c.dir, c.dataset)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return m, nil
+}
+
+// prune removes commit directories older than the version before current.
+// The previous commit is kept so readers that loaded the old manifest can
+// finish and the dataset can be rolled back. Newer versions are left alone:
+// another committer may have moved its files there and not yet swapped the
+// manifest
+func (c *Committer) prune(current *Manifest) error {
+	if current == nil {
+		return nil
+	}
+	return c.removeVersions(func(version int) bool {
+		return version < current.Version-1
+	})
+}
+
+// removeVersions removes the commit directories of the versions remove
+// selects
+func (c *Committer) removeVersions(remove func(version int) bool) error {
+	commits, err := os.ReadDir(filepath.Join(c.dir, c.dataset))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list commit directories: %w", err)
+	}
+
+	for _, entry := range commits {
+		version, ok := parseVersion(entry.Name())
+		if !entry.IsDir() || !ok || !remove(version) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(c.dir, c.dataset, entry.Name())); err != nil {
+			return fmt.Errorf("failed to remove commit directory: %w", err)
+		}
+	}
+	return nil
+}
+
+// commitDir returns the directory of a version, relative to the committer's
+func (c *Committer) commitDir(version int) string {
+	return filepath.Join(c.dataset, fmt.Sprintf("v%06d", version))
+}
+
+// parseVersion returns the version of a commit directory name
+func parseVersion(name string) (int, bool) {
+	digits, ok := strings.CutPrefix(name, "v")
+	if !ok {
+		return 0, false
+	}
+	version, err := strconv.Atoi(digits)
+	return version, err == nil
+}
+
+// lockKey serializes this process's commits of the dataset, from reading
+// the manifest to pruning after the swap
+func (c *Committer) lockKey() any {
+	return filelock.Key(nil, c.dir, c.dataset+manifestSuffix)
+}
+
+// Transaction stages files until they are committed or aborted
+type Transaction struct {
+	committer *Committer
+	dir       string
+	done      bool
+}
+
+// Dir returns the staging directory files should be written into
+func (t *Transaction) Dir() string {
+	return t.dir
+}
+
+// Path returns the staging path for a file name
+func (t *Transaction) Path(name string) string {
+	return filepath.Join(t.dir, name)
+}
+
+// WriteFile stages a file with the given contents
+func (t *Transaction) WriteFile(name string, data []byte) error {
+	if err := os.WriteFile(t.Path(name), data, 0644); err != nil {
+		return fmt.Errorf("failed to stage %s: %w", name, err)
+	}
+	return nil
+}
+
+// Abort discards all staged files
+func (t *Transaction) Abort() error {
+	if t.done {
+		return nil
+	}
+	t.done = true
+	open.Delete(t.dir)
+	return os.RemoveAll(t.dir)
+}
+
+// Commit publishes every staged file as the next version of the dataset.
+// Until the manifest rename succeeds readers keep seeing the previous version.
+func (t *Transaction) Commit() (*Manifest, error) {
+	if t.done {
+		return nil, fmt.Errorf("transaction already finished")
+	}
+	c := t.committer
+	unlock := filelock.Files.Lock(c.lockKey())
+	defer unlock()
+
+	previous, err := c.currentManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := stagedFiles(t.dir)
+	if err != nil {
+		t.Abort()
+		return nil, err
+	}
+
+	version := 1
+	if previous != nil {
+		version = previous.Version + 1
+	}
+	commitDir := c.commitDir(version)
+
+	if err := os.MkdirAll(filepath.Join(c.dir, c.dataset), 0755); err != nil {
+		t.Abort()
+		return nil, fmt.Errorf("failed to create commit directory: %w", err)
+	}
+	// Renaming onto an existing version fails, so concurrent committers cannot both win
+	if err := os.Rename(t.dir, filepath.Join(c.dir, commitDir)); err != nil {
+		t.Abort()
+		return nil, fmt.Errorf("failed to move staged files: %w", err)
+	}
+	t.done = true
+	open.Delete(t.dir)
+
+	manifest := &Manifest{
+		Dataset:     c.dataset,
+		Version:     version,
+		CommitDir:   commitDir,
+		CommittedAt: c.clock.Now(),
+		Files:       files,
+		dir:         c.dir,
+	}
+	if err := writeManifest(c.dir, c.dataset, manifest); err != nil {
+		os.RemoveAll(filepath.Join(c.dir, commitDir))
+		return nil, err
+	}
+
+	if err := c.prune(manifest); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// stagedFiles syncs and checksums every regular file in dir
+func stagedFiles(dir string) ([]FileEntry, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staged files: %w", err)
+	}
+
+	var files []FileEntry
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if err := syncFile(path); err != nil {
+			return nil, err
+		}
+		size, sum, err := checksum(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to checksum %s: %w", entry.Name(), err)
+		}
+		files = append(files, FileEntry{Name: entry.Name(), Size: size, SHA256: sum})
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files staged")
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, nil
+}
+
+// writeManifest atomically replaces the dataset manifest
+func writeManifest(dir, dataset string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	if err := atomicfile.WriteFile(filepath.Join(dir, dataset+manifestSuffix), data, true); err != nil {
+		return fmt.Errorf("failed to swap manifest: %w", err)
+	}
+	return nil
+}
+
+// syncFile flushes a staged file to stable storage
+func syncFile(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", filepath.Base(path), err)
+	}
+	defer f.Close()
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// randomHex returns n random bytes in hex; crypto/rand.Read cannot fail
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// checksum returns the size and hex SHA-256 of a file
+func checksum(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return n, hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,251 @@
+package commit
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/testutil"
+)
+
+func TestCommitPublishesAllFiles(t *testing.T) {
+	dir := "tmp/test_commit"
+	defer os.RemoveAll(dir)
+
+	committer := NewCommitter(dir, "users").WithClock(testutil.NewDefaultFakeClock())
+
+	txn, err := committer.Begin()
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	if err := txn.WriteFile("data.bin", []byte("payload")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := txn.WriteFile("report.json", []byte(`{"ok":true}`)); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	// Nothing is visible before the commit
+	if _, err := ReadManifest(dir, "users"); !os.IsNotExist(err) {
+		t.Fatalf("Expected no manifest before commit, got %v", err)
+	}
+
+	manifest, err := txn.Commit()
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if manifest.Version != 1 || len(manifest.Files) != 2 || !manifest.CommittedAt.Equal(testutil.DefaultFakeTime) {
+		t.Fatalf("Unexpected manifest: %+v", manifest)
+	}
+	if _, err := os.Stat(txn.Dir()); !os.IsNotExist(err) {
+		t.Errorf("Expected staging directory to be gone, got %v", err)
+	}
+
+	read, err := ReadManifest(dir, "users")
+	if err != nil {
+		t.Fatalf("ReadManifest failed: %v", err)
+	}
+	if err := read.Verify(); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	path, ok := read.Path("data.bin")
+	if !ok {
+		t.Fatal("Expected data.bin in manifest")
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "payload" {
+		t.Fatalf("Unexpected committed data %q: %v", data, err)
+	}
+
+	t.Log("✓ Staged files published together")
+}
+
+func TestUncommittedTransactionIsInvisible(t *testing.T) {
+	dir := "tmp/test_commit_crash"
+	defer os.RemoveAll(dir)
+
+	committer := NewCommitter(dir, "users")
+
+	first, err := committer.Begin()
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	first.WriteFile("data.bin", []byte("v1"))
+	if _, err := first.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	// A second load is still writing: one file staged, not yet committed
+	inFlight, err := committer.Begin()
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	inFlight.WriteFile("data.bin", []byte("v2-partial"))
+
+	manifest, err := ReadManifest(dir, "users")
+	if err != nil {
+		t.Fatalf("ReadManifest failed: %v", err)
+	}
+	path, _ := manifest.Path("data.bin")
+	if data, _ := os.ReadFile(path); string(data) != "v1" {
+		t.Fatalf("Expected readers to still see v1, got %q", data)
+	}
+
+	// Staging directories of other processes: one crashed a long time ago,
+	// one is writing right now. A leftover of this process is not open
+	staging := func(name string, age time.Duration) string {
+		path := filepath.Join(dir, stagingDir, name)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		modified := time.Now().Add(-age)
+		os.Chtimes(path, modified, modified)
+		return path
+	}
+	crashed := staging("users-deadbeef-0123456789abcdef", 2*StaleStagingAge)
+	concurrent := staging("users-cafef00d-0123456789abcdef", time.Minute)
+	leftover := staging("users-"+owner+"-0123456789abcdef", time.Minute)
+	otherDataset := staging("users-archive-deadbeef-0123456789abcdef", 2*StaleStagingAge)
+
+	// A crash between the directory move and the manifest swap leaves an orphan version
+	orphan := filepath.Join(dir, "users", "v000002")
+	if err := os.MkdirAll(orphan, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(orphan, "data.bin"), []byte("orphan"), 0644)
+
+	if err := committer.Recover(); err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+	for _, removed := range []string{crashed, leftover, orphan} {
+		if _, err := os.Stat(removed); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed, got %v", removed, err)
+		}
+	}
+	for _, kept := range []string{inFlight.Dir(), concurrent, otherDataset} {
+		if _, err := os.Stat(kept); err != nil {
+			t.Errorf("Expected %s to be kept: %v", kept, err)
+		}
+	}
+	inFlight.Abort()
+
+	next, err := committer.Begin()
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	next.WriteFile("data.bin", []byte("v2"))
+	manifest, err = next.Commit()
+	if err != nil {
+		t.Fatalf("Commit after recovery failed: %v", err)
+	}
+	if manifest.Version != 2 {
+		t.Errorf("Expected version 2, got %d", manifest.Version)
+	}
+
+	// Recovering keeps the previous commit as committing does
+	if err := committer.Recover(); err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "users", "v000001")); err != nil {
+		t.Errorf("Expected the previous commit to be kept: %v", err)
+	}
+
+	t.Log("✓ Partial transactions never reach readers")
+}
+
+func TestCommitPrunesOldVersions(t *testing.T) {
+	dir := "tmp/test_commit_prune"
+	defer os.RemoveAll(dir)
+
+	committer := NewCommitter(dir, "users")
+	for i := 0; i < 3; i++ {
+		txn, err := committer.Begin()
+		if err != nil {
+			t.Fatalf("Begin failed: %v", err)
+		}
+		txn.WriteFile("data.bin", []byte{byte(i)})
+		if _, err := txn.Commit(); err != nil {
+			t.Fatalf("Commit %d failed: %v", i, err)
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "users"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Name() != "v000002" || entries[1].Name() != "v000003" {
+		t.Errorf("Expected only the current and previous versions, got %v", entries)
+	}
+
+	empty, err := committer.Begin()
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	if _, err := empty.Commit(); err == nil {
+		t.Error("Expected empty transaction to fail")
+	}
+
+	t.Log("✓ Superseded versions pruned")
+}
+
+func TestConcurrentCommitters(t *testing.T) {
+	dir := "tmp/test_commit_concurrent"
+	defer os.RemoveAll(dir)
+
+	// Committers of one process take turns, so every commit gets a version
+	const writers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			txn, err := NewCommitter(dir, "users").Begin()
+			if err != nil {
+				errs <- err
+				return
+			}
+			txn.WriteFile("data.bin", []byte{byte(i)})
+			if _, err := txn.Commit(); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("Concurrent commit failed: %v", err)
+	}
+
+	manifest, err := ReadManifest(dir, "users")
+	if err != nil {
+		t.Fatalf("ReadManifest failed: %v", err)
+	}
+	if manifest.Version != writers {
+		t.Errorf("Expected version %d, got %d", writers, manifest.Version)
+	}
+	if err := manifest.Verify(); err != nil {
+		t.Errorf("Expected the current commit to be intact: %v", err)
+	}
+
+	// Another process moved its files to the next version and has not
+	// swapped the manifest yet: pruning for the current version keeps them
+	committer := NewCommitter(dir, "users")
+	next := filepath.Join(dir, committer.commitDir(manifest.Version+1))
+	if err := os.MkdirAll(next, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := committer.prune(manifest); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, "users"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[2].Name() != filepath.Base(next) {
+		t.Errorf("Expected the previous, current and in-flight versions, got %v", entries)
+	}
+
+	t.Log("✓ Concurrent committers never lose each other's versions")
+}
@@ -0,0 +1,317 @@
+// Package conformance round-trips canonical model fixtures through every SDL
+// format and compares the result field by field. Differences a format is
+// known to introduce (nulls read back as empty values, truncated timestamps,
+// dropped time zones, fields the format has no place for) are reported as
+// lossiness; anything else is a mismatch and fails the run.
+package conformance
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"go-transport-prac/pkg/model"
+)
+
+// Kind classifies a difference between a fixture and its round trip
+type Kind string
+
+const (
+	// KindNilToEmpty is a nil pointer, slice or map read back as an empty one
+	KindNilToEmpty Kind = "nil-to-empty"
+	// KindEmptyToNil is an empty value read back as nil
+	KindEmptyToNil Kind = "empty-to-nil"
+	// KindTruncated is a timestamp cut to the format's precision
+	KindTruncated Kind = "truncated"
+	// KindLocation is the same instant read back in another time zone
+	KindLocation Kind = "location"
+	// KindDropped is a field the format does not carry, read back as zero
+	KindDropped Kind = "dropped"
+	// KindMismatch is any other difference; it fails the run
+	KindMismatch Kind = "mismatch"
+)
+
+// Format round-trips canonical values through one serialization format
+type Format struct {
+	Name string
+	// Precision is the unit the format may truncate timestamps to; zero
+	// means timestamps must come back exactly
+	Precision time.Duration
+	// Unsupported lists the field paths, such as "releaseDate", the format
+	// has no place for
+	Unsupported []string
+	User        func(model.User) (model.User, error)
+	Product     func(model.Product) (model.Product, error)
+}
+
+func (f Format) unsupported(path string) bool {
+	for _, p := range f.Unsupported {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+// Fixture is a named canonical value; exactly one of User and Product is set
+type Fixture struct {
+	Name    string
+	User    *model.User
+	Product *model.Product
+}
+
+// Difference is one field that changed across a round trip
+type Difference struct {
+	// Path names the field by its JSON keys, e.g. "profile.address.city"
+	Path string `json:"path"`
+	Kind Kind   `json:"kind"`
+	Want string `json:"want"`
+	Got  string `json:"got"`
+}
+
+// Result is the outcome of one fixture through one format
+type Result struct {
+	Format      string       `json:"format"`
+	Fixture     string       `json:"fixture"`
+	Error       string       `json:"error,omitempty"`
+	Differences []Difference `json:"differences,omitempty"`
+}
+
+// Failed reports whether the round trip errored or produced a mismatch
+func (r Result) Failed() bool {
+	if r.Error != "" {
+		return true
+	}
+	for _, d := range r.Differences {
+		if d.Kind == KindMismatch {
+			return true
+		}
+	}
+	return false
+}
+
+// Report holds the results of Run in format, then fixture order
+type Report struct {
+	Results []Result `json:"results"`
+}
+
+// Run round-trips every fixture through every format
+func Run(formats []Format, fixtures []Fixture) *Report {
+	report := &Report{}
+	for _, f := range formats {
+		for _, fx := range fixtures {
+			report.Results = append(report.Results, roundTrip(f, fx))
+		}
+	}
+	return report
+}
+
+func roundTrip(f Format, fx Fixture) Result {
+	result := Result{Format: f.Name, Fixture: fx.Name}
+	var want, got any
+	var err error
+	switch {
+	case fx.User != nil && f.User != nil:
+		want = *fx.User
+		got, err = f.User(*fx.User)
+	case fx.Product != nil && f.Product != nil:
+		want = *fx.Product
+		got, err = f.Product(*fx.Product)
+	default:
+		result.Error = "format does not support the fixture"
+		return result
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Differences = compare(f, "", reflect.ValueOf(want), reflect.ValueOf(got), nil)
+	return result
+}
+
+// Failures returns the results that errored or produced a mismatch
+func (r *Report) Failures() []Result {
+	var failed []Result
+	for _, res := range r.Results {
+		if res.Failed() {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Lossiness returns the distinct lossy differences of a format as
+// "path: kind", sorted
+func (r *Report) Lossiness(format string) []string {
+	seen := make(map[string]bool)
+	for _, res := range r.Results {
+		if res.Format != format {
+			continue
+		}
+		for _, d := range res.Differences {
+			if d.Kind != KindMismatch {
+				seen[d.Path+": "+string(d.Kind)] = true
+			}
+		}
+	}
+	losses := make([]string, 0, len(seen))
+	for loss := range seen {
+		losses = append(losses, loss)
+	}
+	sort.Strings(losses)
+	return losses
+}
+
+// WriteMarkdown writes every difference and error as a Markdown table
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("| Format | Fixture | Field | Kind | Want | Got |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+	for _, res := range r.Results {
+		if res.Error != "" {
+			fmt.Fprintf(&b, "| %s | %s | | error | | %s |\n", res.Format, res.Fixture, cell(res.Error))
+		}
+		for _, d := range res.Differences {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n", res.Format, res.Fixture, d.Path, d.Kind, cell(d.Want), cell(d.Got))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func cell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// compare walks want and got together, appending a difference for every
+// field that changed
+func compare(f Format, path string, want, got reflect.Value, diffs []Difference) []Difference {
+	add := func(kind Kind) []Difference {
+		return append(diffs, Difference{Path: path, Kind: kind, Want: show(want), Got: show(got)})
+	}
+	// A value the format read back as zero is dropped if the format cannot
+	// carry it, and a mismatch otherwise
+	lost := func() []Difference {
+		if got.IsZero() && f.unsupported(path) {
+			return add(KindDropped)
+		}
+		return add(KindMismatch)
+	}
+
+	if want.Type() == timeType {
+		wt, gt := want.Interface().(time.Time), got.Interface().(time.Time)
+		// Zone names are not compared; formats that keep the offset read it
+		// back as an unnamed zone
+		_, wantOffset := wt.Zone()
+		_, gotOffset := gt.Zone()
+		if wantOffset != gotOffset && !gt.IsZero() {
+			diffs = add(KindLocation)
+		}
+		switch {
+		case wt.Equal(gt):
+			return diffs
+		case f.Precision > 0 && wt.Truncate(f.Precision).Equal(gt):
+			return add(KindTruncated)
+		default:
+			return lost()
+		}
+	}
+
+	switch want.Kind() {
+	case reflect.Pointer:
+		switch {
+		case want.IsNil() && got.IsNil():
+			return diffs
+		case want.IsNil():
+			if got.Elem().IsZero() {
+				return add(KindNilToEmpty)
+			}
+			return add(KindMismatch)
+		case got.IsNil():
+			if want.Elem().IsZero() {
+				return add(KindEmptyToNil)
+			}
+			return lost()
+		}
+		return compare(f, path, want.Elem(), got.Elem(), diffs)
+
+	case reflect.Struct:
+		for i := 0; i < want.NumField(); i++ {
+			diffs = compare(f, join(path, fieldName(want.Type().Field(i))), want.Field(i), got.Field(i), diffs)
+		}
+		return diffs
+
+	case reflect.Slice, reflect.Map:
+		switch {
+		case want.IsNil() && got.IsNil():
+			return diffs
+		case want.IsNil() && got.Len() == 0:
+			return add(KindNilToEmpty)
+		case got.IsNil() && want.Len() == 0:
+			return add(KindEmptyToNil)
+		case want.Len() != got.Len():
+			return lost()
+		}
+		if want.Kind() == reflect.Slice {
+			for i := 0; i < want.Len(); i++ {
+				diffs = compare(f, fmt.Sprintf("%s[%d]", path, i), want.Index(i), got.Index(i), diffs)
+			}
+			return diffs
+		}
+		keys := want.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, key := range keys {
+			value := got.MapIndex(key)
+			if !value.IsValid() {
+				diffs = append(diffs, Difference{Path: fmt.Sprintf("%s[%v]", path, key), Kind: KindMismatch, Want: show(want.MapIndex(key)), Got: "missing"})
+				continue
+			}
+			diffs = compare(f, fmt.Sprintf("%s[%v]", path, key), want.MapIndex(key), value, diffs)
+		}
+		return diffs
+	}
+
+	if !reflect.DeepEqual(want.Interface(), got.Interface()) {
+		return lost()
+	}
+	return diffs
+}
+
+// fieldName returns the JSON key of a field
+func fieldName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func show(v reflect.Value) string {
+	if !v.IsValid() {
+		return "missing"
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return "nil"
+		}
+	}
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
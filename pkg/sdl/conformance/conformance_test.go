@@ -0,0 +1,134 @@
+package conformance
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-transport-prac/pkg/model"
+)
+
+func TestConformance(t *testing.T) {
+	formats, err := Formats()
+	if err != nil {
+		t.Fatalf("Failed to create formats: %v", err)
+	}
+	report := Run(formats, Fixtures())
+	if len(report.Results) != len(formats)*len(Fixtures()) {
+		t.Fatalf("Expected %d results, got %d", len(formats)*len(Fixtures()), len(report.Results))
+	}
+
+	for _, res := range report.Failures() {
+		t.Errorf("%s/%s failed: %s %+v", res.Format, res.Fixture, res.Error, res.Differences)
+	}
+
+	// Known lossiness, which the run must report
+	expected := map[string][]string{
+		"avro":     {"createdAt: truncated", "createdAt: location"},
+		"protobuf": {"releaseDate: dropped", "createdAt: location", "profile.interests: empty-to-nil"},
+		"parquet":  {"releaseDate: dropped"},
+	}
+	for format, losses := range expected {
+		got := report.Lossiness(format)
+		for _, loss := range losses {
+			if !contains(got, loss) {
+				t.Errorf("Expected %s to report %q, got %v", format, loss, got)
+			}
+		}
+	}
+	// JSON keeps the offset and nanoseconds, so it loses nothing
+	if losses := report.Lossiness("json"); len(losses) != 0 {
+		t.Errorf("Expected JSON to be lossless, got %v", losses)
+	}
+
+	for _, f := range formats {
+		t.Logf("%s: %v", f.Name, report.Lossiness(f.Name))
+	}
+	t.Log("✓ Fixtures survive every format up to its known lossiness")
+}
+
+func TestCompare(t *testing.T) {
+	fixtures := Fixtures()
+	fixture, product, minimal := fixtures[0], fixtures[3], fixtures[4]
+
+	// A format that loses data in every way compare distinguishes
+	lossy := Format{
+		Name:        "lossy",
+		Precision:   time.Millisecond,
+		Unsupported: []string{"releaseDate"},
+		User: func(u model.User) (model.User, error) {
+			u.CreatedAt = u.CreatedAt.UTC().Truncate(time.Millisecond)
+			u.UpdatedAt = u.UpdatedAt.Add(-time.Second)
+			profile := *u.Profile
+			profile.Interests = nil
+			profile.Metadata = map[string]string{"source": "import", "tier": "silver"}
+			profile.Address = nil
+			u.Profile = &profile
+			return u, nil
+		},
+		Product: func(p model.Product) (model.Product, error) {
+			p.ReleaseDate = nil
+			if p.Tags == nil {
+				p.Tags = []string{}
+			}
+			return p, nil
+		},
+	}
+	report := Run([]Format{lossy}, []Fixture{fixture, product, minimal})
+
+	want := []Difference{
+		{Path: "profile.address", Kind: KindMismatch},
+		{Path: "profile.interests", Kind: KindMismatch},
+		{Path: "profile.metadata[tier]", Kind: KindMismatch, Want: "gold", Got: "silver"},
+		{Path: "createdAt", Kind: KindLocation},
+		{Path: "createdAt", Kind: KindTruncated},
+		{Path: "updatedAt", Kind: KindMismatch},
+	}
+	got := report.Results[0].Differences
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d differences, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i].Path != want[i].Path || got[i].Kind != want[i].Kind || (want[i].Want != "" && (got[i].Want != want[i].Want || got[i].Got != want[i].Got)) {
+			t.Errorf("Difference %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+	if !report.Results[0].Failed() {
+		t.Error("Expected mismatches to fail the user")
+	}
+
+	// Dropping an unsupported field and returning empty slices is only lossy
+	if report.Results[1].Failed() || report.Results[2].Failed() {
+		t.Errorf("Expected the products to pass, got %+v %+v", report.Results[1].Differences, report.Results[2].Differences)
+	}
+	wantLosses := []string{"createdAt: location", "createdAt: truncated", "releaseDate: dropped", "tags: nil-to-empty"}
+	if losses := report.Lossiness("lossy"); !reflect.DeepEqual(losses, wantLosses) {
+		t.Errorf("Expected lossiness %v, got %v", wantLosses, losses)
+	}
+
+	broken := Format{Name: "broken", User: func(model.User) (model.User, error) { return model.User{}, errors.New("boom | bang") }}
+	report = Run([]Format{broken}, []Fixture{fixture, product})
+	if report.Results[0].Error != "boom | bang" || report.Results[1].Error == "" || len(report.Failures()) != 2 {
+		t.Errorf("Expected both fixtures to fail, got %+v", report.Results)
+	}
+	var md strings.Builder
+	if err := report.WriteMarkdown(&md); err != nil {
+		t.Fatalf("Failed to write markdown: %v", err)
+	}
+	if !strings.Contains(md.String(), `| broken | user-full | | error | | boom \| bang |`) {
+		t.Errorf("Unexpected markdown:\n%s", md.String())
+	}
+
+	t.Log("✓ Differences are classified by kind")
+}
+
+func contains(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
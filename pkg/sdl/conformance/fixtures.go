@@ -0,0 +1,89 @@
+package conformance
+
+import (
+	"time"
+
+	"go-transport-prac/pkg/model"
+)
+
+// Fixtures returns canonical values chosen to expose lossiness: timestamps
+// with nanoseconds in a non-UTC zone, nil against empty collections, absent
+// optional groups and non-ASCII text
+func Fixtures() []Fixture {
+	zone := time.FixedZone("IST", 5*3600+30*60)
+	created := time.Date(2024, 3, 1, 12, 30, 15, 123456789, zone)
+	updated := time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC)
+	release := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	full := model.User{
+		ID:     1,
+		Email:  "ada@example.com",
+		Name:   "Ada Lovelace",
+		Status: model.UserStatusActive,
+		Profile: &model.Profile{
+			FirstName: "Ada",
+			LastName:  "Lovelace",
+			Phone:     "+44-20-7946-0000",
+			Address:   &model.Address{Street: "12 St James's Sq", City: "London", State: "LDN", PostalCode: "SW1Y 4JH", Country: "UK"},
+			Interests: []string{"mathematics", "engines"},
+			Metadata:  map[string]string{"source": "import", "tier": "gold"},
+		},
+		CreatedAt: created,
+		UpdatedAt: updated,
+	}
+	minimal := model.User{
+		ID:        2,
+		Email:     "grace@example.com",
+		Name:      "Grace",
+		Status:    model.UserStatusInactive,
+		CreatedAt: updated,
+		UpdatedAt: updated,
+	}
+	empty := model.User{
+		ID:     3,
+		Email:  "zoë@example.com",
+		Name:   "Zoë 李",
+		Status: model.UserStatusSuspended,
+		Profile: &model.Profile{
+			FirstName: "Zoë",
+			LastName:  "李",
+			Interests: []string{},
+			Metadata:  map[string]string{},
+		},
+		CreatedAt: updated,
+		UpdatedAt: updated,
+	}
+
+	fullProduct := model.Product{
+		ID:             10,
+		Name:           "Sensor <v2> & co",
+		Description:    "Temperature sensor, -40..125°C",
+		SKU:            "SKU-000010",
+		Price:          model.Price{Currency: "USD", AmountCents: 1999, DiscountPercentage: 0.25},
+		Inventory:      model.Inventory{Quantity: 100, Reserved: 5, Available: 95, TrackInventory: true, ReorderLevel: 10, MaxStock: 500},
+		Categories:     []string{"iot", "sensors"},
+		Tags:           []string{"temperature"},
+		Status:         model.ProductStatusActive,
+		Specifications: map[string]string{"range": "-40..125C", "accuracy": "0.5C"},
+		ReleaseDate:    &release,
+		CreatedAt:      created,
+		UpdatedAt:      updated,
+	}
+	minimalProduct := model.Product{
+		ID:        11,
+		Name:      "Gateway",
+		SKU:       "SKU-000011",
+		Price:     model.Price{Currency: "EUR", AmountCents: 0},
+		Status:    model.ProductStatusDiscontinued,
+		CreatedAt: updated,
+		UpdatedAt: updated,
+	}
+
+	return []Fixture{
+		{Name: "user-full", User: &full},
+		{Name: "user-minimal", User: &minimal},
+		{Name: "user-empty-collections", User: &empty},
+		{Name: "product-full", Product: &fullProduct},
+		{Name: "product-minimal", Product: &minimalProduct},
+	}
+}
@@ -0,0 +1,174 @@
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	parquetgo "github.com/segmentio/parquet-go"
+	"google.golang.org/protobuf/proto"
+
+	"go-transport-prac/pkg/model"
+	"go-transport-prac/pkg/model/parquetmodel"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/cbor"
+	"go-transport-prac/pkg/sdl/flatbuffers"
+	"go-transport-prac/pkg/sdl/msgpack"
+	"go-transport-prac/pkg/sdl/parquet"
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+	"go-transport-prac/pkg/sdl/xml"
+	"go-transport-prac/pkg/sdl/yaml"
+)
+
+// avroModelCodec is implemented by the managers that serialize the Avro models
+type avroModelCodec interface {
+	SerializeUser(avro.User) ([]byte, error)
+	DeserializeUser([]byte) (avro.User, error)
+	SerializeProduct(avro.Product) ([]byte, error)
+	DeserializeProduct([]byte) (avro.Product, error)
+}
+
+// Formats returns every SDL format with its known precision and unsupported
+// fields
+func Formats() ([]Format, error) {
+	avroManager, err := avro.NewManager("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avro manager: %w", err)
+	}
+	return []Format{
+		// The Avro schemas use timestamp-millis
+		viaAvro("avro", avroBinary{avroManager}, time.Millisecond),
+		Protobuf(),
+		Parquet(),
+		viaAvro("json", jsonCodec{}, 0),
+		viaAvro("msgpack", msgpack.NewManager(""), 0),
+		viaAvro("cbor", cbor.NewManager("").WithCanonical(true), 0),
+		viaAvro("flatbuffers", flatbuffers.NewManager(""), 0),
+		viaAvro("xml", xml.NewManager("").WithSchema(xml.ModelsSchema()), 0),
+		viaAvro("yaml", yaml.NewManager(""), 0),
+	}, nil
+}
+
+// viaAvro round-trips through a codec of the Avro models
+func viaAvro(name string, codec avroModelCodec, precision time.Duration) Format {
+	return Format{
+		Name:      name,
+		Precision: precision,
+		User: func(u model.User) (model.User, error) {
+			data, err := codec.SerializeUser(u.ToAvro())
+			if err != nil {
+				return model.User{}, err
+			}
+			back, err := codec.DeserializeUser(data)
+			return model.UserFromAvro(back), err
+		},
+		Product: func(p model.Product) (model.Product, error) {
+			data, err := codec.SerializeProduct(p.ToAvro())
+			if err != nil {
+				return model.Product{}, err
+			}
+			back, err := codec.DeserializeProduct(data)
+			return model.ProductFromAvro(back), err
+		},
+	}
+}
+
+// Protobuf round-trips through the generated messages
+func Protobuf() Format {
+	return Format{
+		Name:        "protobuf",
+		Unsupported: []string{"releaseDate"},
+		User: func(u model.User) (model.User, error) {
+			data, err := proto.Marshal(u.ToProto())
+			if err != nil {
+				return model.User{}, fmt.Errorf("failed to marshal user: %w", err)
+			}
+			var msg user.User
+			if err := proto.Unmarshal(data, &msg); err != nil {
+				return model.User{}, fmt.Errorf("failed to unmarshal user: %w", err)
+			}
+			return model.UserFromProto(&msg), nil
+		},
+		Product: func(p model.Product) (model.Product, error) {
+			data, err := proto.Marshal(p.ToProto())
+			if err != nil {
+				return model.Product{}, fmt.Errorf("failed to marshal product: %w", err)
+			}
+			var msg product.Product
+			if err := proto.Unmarshal(data, &msg); err != nil {
+				return model.Product{}, fmt.Errorf("failed to unmarshal product: %w", err)
+			}
+			return model.ProductFromProto(&msg), nil
+		},
+	}
+}
+
+// Parquet round-trips through a one-row in-memory Parquet file
+func Parquet() Format {
+	return Format{
+		Name:        "parquet",
+		Precision:   time.Microsecond,
+		Unsupported: []string{"releaseDate"},
+		User: func(u model.User) (model.User, error) {
+			rows, err := parquetRoundTrip([]parquet.User{parquetmodel.UserToParquet(u)})
+			if err != nil {
+				return model.User{}, err
+			}
+			return parquetmodel.UserFromParquet(rows[0]), nil
+		},
+		Product: func(p model.Product) (model.Product, error) {
+			rows, err := parquetRoundTrip([]parquet.Product{parquetmodel.ProductToParquet(p)})
+			if err != nil {
+				return model.Product{}, err
+			}
+			return parquetmodel.ProductFromParquet(rows[0]), nil
+		},
+	}
+}
+
+func parquetRoundTrip[T any](rows []T) ([]T, error) {
+	var buf bytes.Buffer
+	if err := parquetgo.Write(&buf, rows); err != nil {
+		return nil, fmt.Errorf("failed to write parquet: %w", err)
+	}
+	back, err := parquetgo.Read[T](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parquet: %w", err)
+	}
+	if len(back) != len(rows) {
+		return nil, fmt.Errorf("read %d rows, wrote %d", len(back), len(rows))
+	}
+	return back, nil
+}
+
+// avroBinary adapts the Avro manager's binary encoding
+type avroBinary struct{ m *avro.Manager }
+
+func (a avroBinary) SerializeUser(u avro.User) ([]byte, error) { return a.m.SerializeUserBinary(u) }
+func (a avroBinary) DeserializeUser(data []byte) (avro.User, error) {
+	return a.m.DeserializeUserBinary(data)
+}
+func (a avroBinary) SerializeProduct(p avro.Product) ([]byte, error) {
+	return a.m.SerializeProductBinary(p)
+}
+func (a avroBinary) DeserializeProduct(data []byte) (avro.Product, error) {
+	return a.m.DeserializeProductBinary(data)
+}
+
+// jsonCodec is encoding/json over the Avro models' json tags
+type jsonCodec struct{}
+
+func (jsonCodec) SerializeUser(u avro.User) ([]byte, error) { return json.Marshal(u) }
+func (jsonCodec) DeserializeUser(data []byte) (avro.User, error) {
+	var u avro.User
+	err := json.Unmarshal(data, &u)
+	return u, err
+}
+func (jsonCodec) SerializeProduct(p avro.Product) ([]byte, error) { return json.Marshal(p) }
+func (jsonCodec) DeserializeProduct(data []byte) (avro.Product, error) {
+	var p avro.Product
+	err := json.Unmarshal(data, &p)
+	return p, err
+}
@@ -0,0 +1,235 @@
+package converter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hamba/avro/v2/ocf"
+	parquetgo "github.com/segmentio/parquet-go"
+
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/parquet"
+)
+
+// Model is one of the shared models a file can hold
+type Model string
+
+const (
+	ModelUser      Model = "user"
+	ModelProduct   Model = "product"
+	ModelOrder     Model = "order"
+	ModelAnalytics Model = "analytics event"
+)
+
+// avroModels maps the record names of the Avro schemas to their model
+var avroModels = map[string]Model{
+	"User":      ModelUser,
+	"Product":   ModelProduct,
+	"Order":     ModelOrder,
+	"Analytics": ModelAnalytics,
+}
+
+// parquetModels maps a column only one model's Parquet schema has to the model
+var parquetModels = []struct {
+	column string
+	model  Model
+}{
+	{"email", ModelUser},
+	{"sku", ModelProduct},
+	{"order_number", ModelOrder},
+	{"event_type", ModelAnalytics},
+}
+
+// Result describes a finished conversion
+type Result struct {
+	Model   Model
+	Records int
+}
+
+// Converter converts files between Avro Object Container Files and Parquet,
+// detecting which model a file holds from its schema. Fields without a
+// counterpart are mapped as Parquet stores them: empty optional strings and
+// zero discounts become Avro nulls
+type Converter struct {
+	avroManager   *avro.Manager
+	writerOptions parquet.WriterOptions
+	ocfOptions    []ocf.EncoderFunc
+}
+
+// New creates a converter writing Parquet with the default writer options and
+// uncompressed Avro
+func New() (*Converter, error) {
+	avroManager, err := avro.NewManager("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avro manager: %w", err)
+	}
+	return &Converter{avroManager: avroManager}, nil
+}
+
+// WithWriterOptions sets the options of the Parquet files written
+func (c *Converter) WithWriterOptions(opts parquet.WriterOptions) *Converter {
+	c.writerOptions = opts
+	return c
+}
+
+// WithOCFOptions sets the codec, block size or sync marker of the Avro files written
+func (c *Converter) WithOCFOptions(opts ...ocf.EncoderFunc) *Converter {
+	c.ocfOptions = opts
+	return c
+}
+
+// AvroToParquet reads the Avro Object Container File src and writes its
+// records to the Parquet file dst
+func (c *Converter) AvroToParquet(src, dst string) (Result, error) {
+	file, err := os.Open(src)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to open avro file: %w", err)
+	}
+	defer file.Close()
+
+	model, err := DetectAvroModel(file)
+	if err != nil {
+		return Result{}, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return Result{}, fmt.Errorf("failed to rewind avro file: %w", err)
+	}
+
+	manager := parquet.NewSimpleManager(filepath.Dir(dst))
+	name := filepath.Base(dst)
+	var n int
+	switch model {
+	case ModelUser:
+		var users []avro.User
+		if users, err = c.avroManager.ReadUsersOCF(file); err == nil {
+			n = len(users)
+			err = manager.WriteUsersWithOptions(name, mapAll(users, UserToParquet), c.writerOptions)
+		}
+	case ModelProduct:
+		var products []avro.Product
+		if products, err = c.avroManager.ReadProductsOCF(file); err == nil {
+			n = len(products)
+			err = manager.WriteProductsWithOptions(name, mapAll(products, ProductToParquet), c.writerOptions)
+		}
+	case ModelOrder:
+		var orders []avro.Order
+		if orders, err = c.avroManager.ReadOrdersOCF(file); err == nil {
+			n = len(orders)
+			err = manager.WriteOrdersWithOptions(name, mapAll(orders, OrderToParquet), c.writerOptions)
+		}
+	case ModelAnalytics:
+		var events []avro.Analytics
+		if events, err = c.avroManager.ReadAnalyticsOCF(file); err == nil {
+			n = len(events)
+			err = manager.WriteAnalyticsWithOptions(name, mapAll(events, AnalyticsToParquet), c.writerOptions)
+		}
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to convert %ss to parquet: %w", model, err)
+	}
+	return Result{Model: model, Records: n}, nil
+}
+
+// ParquetToAvro reads the Parquet file src and writes its rows to the Avro
+// Object Container File dst
+func (c *Converter) ParquetToAvro(src, dst string) (Result, error) {
+	model, err := DetectParquetModel(src)
+	if err != nil {
+		return Result{}, err
+	}
+
+	manager := parquet.NewSimpleManager(filepath.Dir(src))
+	name := filepath.Base(src)
+	out, err := os.Create(dst)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create avro file: %w", err)
+	}
+	defer out.Close()
+
+	var n int
+	switch model {
+	case ModelUser:
+		var rows []parquet.User
+		if rows, err = manager.ReadUsers(name); err == nil {
+			n = len(rows)
+			err = c.avroManager.WriteUsersOCF(out, mapAll(rows, UserFromParquet), c.ocfOptions...)
+		}
+	case ModelProduct:
+		var rows []parquet.Product
+		if rows, err = manager.ReadProducts(name); err == nil {
+			n = len(rows)
+			err = c.avroManager.WriteProductsOCF(out, mapAll(rows, ProductFromParquet), c.ocfOptions...)
+		}
+	case ModelOrder:
+		var rows []parquet.Order
+		if rows, err = manager.ReadOrders(name); err == nil {
+			n = len(rows)
+			err = c.avroManager.WriteOrdersOCF(out, mapAll(rows, OrderFromParquet), c.ocfOptions...)
+		}
+	case ModelAnalytics:
+		var rows []parquet.Analytics
+		if rows, err = manager.ReadAnalytics(name); err == nil {
+			n = len(rows)
+			err = c.avroManager.WriteAnalyticsOCF(out, mapAll(rows, AnalyticsFromParquet), c.ocfOptions...)
+		}
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to convert %ss to avro: %w", model, err)
+	}
+	if err := out.Close(); err != nil {
+		return Result{}, fmt.Errorf("failed to close avro file: %w", err)
+	}
+	return Result{Model: model, Records: n}, nil
+}
+
+// DetectAvroModel reads an Object Container File header and returns the
+// model of its record schema
+func DetectAvroModel(r io.Reader) (Model, error) {
+	fullName, err := avro.OCFSchemaName(r)
+	if err != nil {
+		return "", err
+	}
+	name := fullName[strings.LastIndex(fullName, ".")+1:]
+	model, ok := avroModels[name]
+	if !ok {
+		return "", fmt.Errorf("avro schema %s is not a user, product, order or analytics event", fullName)
+	}
+	return model, nil
+}
+
+// DetectParquetModel opens a Parquet file and returns the model its columns belong to
+func DetectParquetModel(path string) (Model, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open parquet file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to stat parquet file: %w", err)
+	}
+	pf, err := parquetgo.OpenFile(file, info.Size())
+	if err != nil {
+		return "", fmt.Errorf("failed to open parquet file: %w", err)
+	}
+
+	for _, candidate := range parquetModels {
+		if _, ok := pf.Schema().Lookup(candidate.column); ok {
+			return candidate.model, nil
+		}
+	}
+	return "", fmt.Errorf("parquet file %s does not hold users, products, orders or analytics events", path)
+}
+
+// mapAll converts every element of in
+func mapAll[In, Out any](in []In, convert func(In) Out) []Out {
+	out := make([]Out, len(in))
+	for i, v := range in {
+		out[i] = convert(v)
+	}
+	return out
+}
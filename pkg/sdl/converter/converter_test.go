@@ -0,0 +1,254 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/parquet"
+)
+
+func sampleOrders() []avro.Order {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	shipped := created.Add(48 * time.Hour)
+	tracking := "1Z999"
+	discount := float32(10)
+	price := func(cents int64) avro.Price { return avro.Price{Currency: "USD", AmountCents: cents} }
+
+	return []avro.Order{
+		{
+			ID: 1, UserID: 7, OrderNumber: "ORD-1", Status: avro.OrderStatusShipped,
+			Items: []avro.OrderItem{
+				{ProductID: 3, ProductName: "Keyboard", ProductSKU: "KB-1", Quantity: 2, UnitPrice: price(4500), TotalPrice: price(9000),
+					ProductVariant: map[string]string{"layout": "ISO"}},
+			},
+			Summary: avro.OrderSummary{Subtotal: price(9000), Tax: price(720), ShippingCost: price(500),
+				Discount: avro.Price{Currency: "USD", AmountCents: 900, DiscountPercentage: &discount}, Total: price(9320), TotalItems: 2},
+			ShippingInfo: &avro.ShippingInfo{
+				Address:        avro.ShippingAddress{RecipientName: "Ada", Street: "1 Main St", City: "Springfield", State: "IL", PostalCode: "62701", Country: "USA"},
+				Method:         "express",
+				TrackingNumber: &tracking,
+				Cost:           price(500),
+			},
+			PaymentInfo: &avro.PaymentInfo{Method: "card", Status: avro.PaymentStatusCaptured, Amount: price(9320), ProcessedAt: &created},
+			CreatedAt:   created,
+			UpdatedAt:   shipped,
+			ShippedAt:   &shipped,
+		},
+		{
+			ID: 2, UserID: 8, OrderNumber: "ORD-2", Status: avro.OrderStatusPending,
+			Summary:   avro.OrderSummary{Subtotal: price(0), Tax: price(0), ShippingCost: price(0), Discount: price(0), Total: price(0)},
+			CreatedAt: created,
+			UpdatedAt: created,
+		},
+	}
+}
+
+// roundTrip converts the OCF file at src to Parquet and back, returning both paths
+func roundTrip(t *testing.T, c *Converter, dir, name string, model Model, count int) (string, string) {
+	t.Helper()
+
+	src := filepath.Join(dir, name+".avro")
+	pq := filepath.Join(dir, name+".parquet")
+	back := filepath.Join(dir, name+"_back.avro")
+
+	result, err := c.AvroToParquet(src, pq)
+	if err != nil {
+		t.Fatalf("Failed to convert %s to parquet: %v", name, err)
+	}
+	if result.Model != model || result.Records != count {
+		t.Fatalf("Expected %d %ss, got %+v", count, model, result)
+	}
+	if detected, err := DetectParquetModel(pq); err != nil || detected != model {
+		t.Fatalf("Expected the parquet file to hold %ss, got %s (%v)", model, detected, err)
+	}
+
+	result, err = c.ParquetToAvro(pq, back)
+	if err != nil {
+		t.Fatalf("Failed to convert %s back to avro: %v", name, err)
+	}
+	if result.Model != model || result.Records != count {
+		t.Fatalf("Expected %d %ss back, got %+v", count, model, result)
+	}
+	return pq, back
+}
+
+func TestAvroParquetRoundTrip(t *testing.T) {
+	testDir := "tmp/test_converter"
+	defer os.RemoveAll(testDir)
+
+	avroManager, err := avro.NewManager(testDir)
+	if err != nil {
+		t.Fatalf("Failed to create avro manager: %v", err)
+	}
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create converter: %v", err)
+	}
+
+	// Written values are read back first, so both sides carry Avro's millisecond times
+	if err := avroManager.WriteUsersToOCFFile("users.avro", avroManager.CreateSampleUsers(25)); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	users, _ := avroManager.ReadUsersFromOCFFile("users.avro")
+	pq, _ := roundTrip(t, c, testDir, "users", ModelUser, len(users))
+	back, err := avroManager.ReadUsersFromOCFFile("users_back.avro")
+	if err != nil || !reflect.DeepEqual(utcUsers(back), utcUsers(users)) {
+		t.Errorf("Users changed in the round trip (%v)", err)
+	}
+	rows, err := parquet.NewSimpleManager(testDir).ReadUsers(filepath.Base(pq))
+	if err != nil || len(rows) != len(users) || rows[3].Email != users[3].Email {
+		t.Errorf("Expected the parquet file to hold the users (%v)", err)
+	}
+
+	if err := avroManager.WriteProductsToOCFFile("products.avro", avroManager.CreateSampleProducts(10)); err != nil {
+		t.Fatalf("Failed to write products: %v", err)
+	}
+	products, _ := avroManager.ReadProductsFromOCFFile("products.avro")
+	roundTrip(t, c, testDir, "products", ModelProduct, len(products))
+	backProducts, err := avroManager.ReadProductsFromOCFFile("products_back.avro")
+	if err != nil || !reflect.DeepEqual(utcProducts(backProducts), utcProducts(products)) {
+		t.Errorf("Products changed in the round trip (%v)", err)
+	}
+
+	if err := avroManager.WriteOrdersToOCFFile("orders.avro", sampleOrders()); err != nil {
+		t.Fatalf("Failed to write orders: %v", err)
+	}
+	orders, _ := avroManager.ReadOrdersFromOCFFile("orders.avro")
+	roundTrip(t, c, testDir, "orders", ModelOrder, len(orders))
+	backOrders, err := avroManager.ReadOrdersFromOCFFile("orders_back.avro")
+	if err != nil || !reflect.DeepEqual(utcOrders(backOrders), utcOrders(orders)) {
+		t.Errorf("Orders changed in the round trip (%v)\nbefore: %+v\nafter:  %+v", err, orders, backOrders)
+	}
+
+	t.Log("✓ Users, products and orders convert between Avro and Parquet without loss")
+}
+
+func TestAnalyticsRoundTrip(t *testing.T) {
+	testDir := "tmp/test_converter_analytics"
+	defer os.RemoveAll(testDir)
+
+	// Events as the parquet analytics workflow writes them; zero values are what Parquet stores as null
+	timestamp := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	events := []parquet.Analytics{
+		{
+			ID: 1, EventType: "page_view", UserID: 42, SessionID: "session_1", Timestamp: timestamp,
+			Properties: map[string]string{"page": "/home"},
+			Metrics:    map[string]float64{"duration": 30},
+			DeviceInfo: &parquet.DeviceInfo{Platform: "web", Browser: "chrome"},
+			Location:   &parquet.Location{Country: "TW", City: "Taipei", Latitude: 25.033, Longitude: 121.565},
+		},
+		{
+			ID: 2, EventType: "click", SessionID: "session_2", Timestamp: timestamp.Add(time.Minute),
+			Properties: map[string]string{"page": "/cart"}, Metrics: map[string]float64{"duration": 5},
+		},
+	}
+	if err := parquet.NewSimpleManager(testDir).WriteAnalytics("events.parquet", events); err != nil {
+		t.Fatalf("Failed to write analytics: %v", err)
+	}
+
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create converter: %v", err)
+	}
+	src := filepath.Join(testDir, "events.parquet")
+	avroFile := filepath.Join(testDir, "events.avro")
+	result, err := c.ParquetToAvro(src, avroFile)
+	if err != nil || result.Model != ModelAnalytics || result.Records != len(events) {
+		t.Fatalf("Failed to convert analytics to avro: %+v (%v)", result, err)
+	}
+
+	avroManager, _ := avro.NewManager(testDir)
+	converted, err := avroManager.ReadAnalyticsFromOCFFile("events.avro")
+	if err != nil || len(converted) != len(events) {
+		t.Fatalf("Failed to read converted analytics: %v", err)
+	}
+	if converted[0].UserID == nil || *converted[0].UserID != 42 || *converted[0].Location.City != "Taipei" {
+		t.Errorf("Unexpected converted event: %+v", converted[0])
+	}
+	if converted[1].UserID != nil || converted[1].DeviceInfo != nil {
+		t.Errorf("Expected an anonymous event without device info, got %+v", converted[1])
+	}
+
+	back := filepath.Join(testDir, "events_back.parquet")
+	if _, err := c.AvroToParquet(avroFile, back); err != nil {
+		t.Fatalf("Failed to convert analytics back to parquet: %v", err)
+	}
+	rows, err := parquet.NewSimpleManager(testDir).ReadAnalytics("events_back.parquet")
+	if err != nil {
+		t.Fatalf("Failed to read analytics back: %v", err)
+	}
+	for i := range rows {
+		rows[i].Timestamp = rows[i].Timestamp.UTC()
+	}
+	if !reflect.DeepEqual(rows, events) {
+		t.Errorf("Analytics changed in the round trip:\nbefore: %+v\nafter:  %+v", events, rows)
+	}
+
+	t.Log("✓ Parquet analytics events convert to Avro and back")
+}
+
+func TestDetectModelErrors(t *testing.T) {
+	testDir := "tmp/test_converter_errors"
+	defer os.RemoveAll(testDir)
+
+	avroManager, _ := avro.NewManager(testDir)
+	records := []avro.UserRecord{{User: avroManager.CreateSampleUsers(1)[0]}}
+	if err := avroManager.WriteUserRecordsToFile("envelope.avro", records); err != nil {
+		t.Fatalf("Failed to write envelope file: %v", err)
+	}
+
+	c, _ := New()
+	if _, err := c.AvroToParquet(filepath.Join(testDir, "envelope.avro"), filepath.Join(testDir, "out.parquet")); err == nil {
+		t.Error("Expected a file without an OCF header to be rejected")
+	}
+
+	envelopes := []parquet.UserRecord{{User: parquet.User{ID: 1, Email: "a@example.com"}}}
+	if err := parquet.NewSimpleManager(testDir).WriteUserRecords("envelopes.parquet", envelopes); err != nil {
+		t.Fatalf("Failed to write user records: %v", err)
+	}
+	if _, err := c.ParquetToAvro(filepath.Join(testDir, "envelopes.parquet"), filepath.Join(testDir, "out.avro")); err == nil {
+		t.Error("Expected a parquet file of another model to be rejected")
+	}
+
+	t.Log("✓ Files of other models are rejected")
+}
+
+func utcUsers(users []avro.User) []avro.User {
+	for i := range users {
+		users[i].CreatedAt, users[i].UpdatedAt = users[i].CreatedAt.UTC(), users[i].UpdatedAt.UTC()
+	}
+	return users
+}
+
+func utcProducts(products []avro.Product) []avro.Product {
+	for i := range products {
+		products[i].CreatedAt, products[i].UpdatedAt = products[i].CreatedAt.UTC(), products[i].UpdatedAt.UTC()
+	}
+	return products
+}
+
+func utcOrders(orders []avro.Order) []avro.Order {
+	utc := func(t *time.Time) {
+		if t != nil {
+			*t = t.UTC()
+		}
+	}
+	for i := range orders {
+		o := &orders[i]
+		utc(&o.CreatedAt)
+		utc(&o.UpdatedAt)
+		utc(o.ShippedAt)
+		utc(o.DeliveredAt)
+		if o.PaymentInfo != nil {
+			utc(o.PaymentInfo.ProcessedAt)
+		}
+		if o.ShippingInfo != nil {
+			utc(o.ShippingInfo.EstimatedDelivery)
+		}
+	}
+	return orders
+}
@@ -0,0 +1,215 @@
+package converter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+
+	"go-transport-prac/pkg/sdl/arrow"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/parquet"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+// Format is a file format Convert reads and writes
+type Format string
+
+const (
+	// FormatJSON is a JSON array of users
+	FormatJSON Format = "json"
+	// FormatAvro is an Avro Object Container File
+	FormatAvro Format = "avro"
+	// FormatProtobuf is a stream of length-delimited user messages
+	FormatProtobuf Format = "protobuf"
+	// FormatParquet is a Parquet file
+	FormatParquet Format = "parquet"
+	// FormatArrow is an Arrow IPC stream of user record batches
+	FormatArrow Format = "arrow"
+)
+
+// formatExtensions maps file extensions to their format
+var formatExtensions = map[string]Format{
+	".json":     FormatJSON,
+	".avro":     FormatAvro,
+	".ocf":      FormatAvro,
+	".pb":       FormatProtobuf,
+	".binpb":    FormatProtobuf,
+	".protobuf": FormatProtobuf,
+	".parquet":  FormatParquet,
+	".arrows":   FormatArrow,
+}
+
+// FormatOf returns the format of a file from its extension
+func FormatOf(path string) (Format, error) {
+	format, ok := formatExtensions[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return "", fmt.Errorf("cannot tell the format of %s from its extension", path)
+	}
+	return format, nil
+}
+
+// Convert converts src to dst, choosing both formats by file extension.
+// Avro and Parquet files hold users, products, orders or analytics events; JSON, protobuf
+// and Arrow files hold users, so conversions involving them are limited to users
+func (c *Converter) Convert(src, dst string) (Result, error) {
+	from, err := FormatOf(src)
+	if err != nil {
+		return Result{}, err
+	}
+	to, err := FormatOf(dst)
+	if err != nil {
+		return Result{}, err
+	}
+
+	switch {
+	case from == to:
+		return Result{}, fmt.Errorf("%s and %s are both %s", src, dst, from)
+	case from == FormatAvro && to == FormatParquet:
+		return c.AvroToParquet(src, dst)
+	case from == FormatParquet && to == FormatAvro:
+		return c.ParquetToAvro(src, dst)
+	}
+
+	users, err := c.ReadUsers(src)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := c.WriteUsers(dst, users); err != nil {
+		return Result{}, err
+	}
+	return Result{Model: ModelUser, Records: len(users)}, nil
+}
+
+// ReadUsers reads the users of a file in any Format
+func (c *Converter) ReadUsers(path string) ([]avro.User, error) {
+	format, err := FormatOf(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == FormatParquet {
+		if model, err := DetectParquetModel(path); err != nil || model != ModelUser {
+			return nil, userFileError(path, model, err)
+		}
+		rows, err := parquet.NewSimpleManager(filepath.Dir(path)).ReadUsers(filepath.Base(path))
+		if err != nil {
+			return nil, err
+		}
+		return mapAll(rows, UserFromParquet), nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	switch format {
+	case FormatAvro:
+		if model, err := DetectAvroModel(file); err != nil || model != ModelUser {
+			return nil, userFileError(path, model, err)
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("failed to rewind avro file: %w", err)
+		}
+		return c.avroManager.ReadUsersOCF(file)
+	case FormatProtobuf:
+		return readProtoUsers(file)
+	case FormatArrow:
+		rows, err := arrow.ReadArrowIPC(file, arrow.Users)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return mapAll(rows, UserFromParquet), nil
+	default:
+		var users []avro.User
+		if err := json.NewDecoder(file).Decode(&users); err != nil {
+			return nil, fmt.Errorf("failed to decode users: %w", err)
+		}
+		return users, nil
+	}
+}
+
+// WriteUsers writes users to a file in any Format
+func (c *Converter) WriteUsers(path string, users []avro.User) error {
+	format, err := FormatOf(path)
+	if err != nil {
+		return err
+	}
+
+	if format == FormatParquet {
+		return parquet.NewSimpleManager(filepath.Dir(path)).
+			WriteUsersWithOptions(filepath.Base(path), mapAll(users, UserToParquet), c.writerOptions)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	buffered := bufio.NewWriter(file)
+	switch format {
+	case FormatAvro:
+		err = c.avroManager.WriteUsersOCF(buffered, users, c.ocfOptions...)
+	case FormatProtobuf:
+		err = writeProtoUsers(buffered, users)
+	case FormatArrow:
+		err = arrow.WriteArrowIPC(buffered, arrow.Users, mapAll(users, UserToParquet), 0)
+	default:
+		encoder := json.NewEncoder(buffered)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(users)
+	}
+	if err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return file.Close()
+}
+
+// userFileError explains why a file cannot be read as users
+func userFileError(path string, model Model, err error) error {
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%s holds %ss; only users convert to and from json, protobuf and arrow", path, model)
+}
+
+// readProtoUsers decodes length-delimited user messages
+func readProtoUsers(r io.Reader) ([]avro.User, error) {
+	reader := bufio.NewReader(r)
+	var users []avro.User
+	for {
+		msg := &user.User{}
+		if err := protodelim.UnmarshalFrom(reader, msg); err != nil {
+			if err == io.EOF {
+				return users, nil
+			}
+			return nil, fmt.Errorf("failed to decode user %d: %w", len(users), err)
+		}
+		users = append(users, UserFromProto(msg))
+	}
+}
+
+// writeProtoUsers encodes users as length-delimited messages
+func writeProtoUsers(w io.Writer, users []avro.User) error {
+	for _, u := range users {
+		msg, err := UserToProto(u)
+		if err != nil {
+			return err
+		}
+		if _, err := protodelim.MarshalTo(w, msg); err != nil {
+			return fmt.Errorf("failed to marshal user %d: %w", u.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+func TestFormatOf(t *testing.T) {
+	cases := map[string]Format{
+		"users.json":        FormatJSON,
+		"data/users.AVRO":   FormatAvro,
+		"users.ocf":         FormatAvro,
+		"users.binpb":       FormatProtobuf,
+		"users.pb":          FormatProtobuf,
+		"out/users.parquet": FormatParquet,
+		"users.arrows":      FormatArrow,
+	}
+	for path, want := range cases {
+		if got, err := FormatOf(path); err != nil || got != want {
+			t.Errorf("%s: expected %s, got %s (%v)", path, want, got, err)
+		}
+	}
+	if _, err := FormatOf("users.csv"); err == nil {
+		t.Error("Expected an unknown extension to be rejected")
+	}
+
+	t.Log("✓ Formats are chosen by extension")
+}
+
+func TestConvertAcrossFormats(t *testing.T) {
+	testDir := "tmp/test_convert_formats"
+	defer os.RemoveAll(testDir)
+	if err := os.MkdirAll(testDir, 0755); err != nil {
+		t.Fatalf("Failed to create test dir: %v", err)
+	}
+
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create converter: %v", err)
+	}
+	manager, _ := avro.NewManager("")
+	path := func(name string) string { return filepath.Join(testDir, name) }
+
+	// Starting from Avro gives every format the same millisecond times
+	if err := c.WriteUsers(path("users.avro"), manager.CreateSampleUsers(12)); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	original, err := c.ReadUsers(path("users.avro"))
+	if err != nil {
+		t.Fatalf("Failed to read users: %v", err)
+	}
+	chain := []string{"users.avro", "users.json", "users.pb", "users.parquet", "users.arrows", "back.avro"}
+	for i := 1; i < len(chain); i++ {
+		result, err := c.Convert(path(chain[i-1]), path(chain[i]))
+		if err != nil {
+			t.Fatalf("Failed to convert %s to %s: %v", chain[i-1], chain[i], err)
+		}
+		if result.Model != ModelUser || result.Records != 12 {
+			t.Errorf("%s: expected 12 users, got %+v", chain[i], result)
+		}
+	}
+
+	back, err := c.ReadUsers(path("back.avro"))
+	if err != nil {
+		t.Fatalf("Failed to read converted users: %v", err)
+	}
+	if !reflect.DeepEqual(utcUsers(back), utcUsers(original)) {
+		t.Error("Users changed across the formats")
+	}
+
+	t.Log("✓ Users convert across avro, json, protobuf, parquet and arrow")
+}
+
+func TestConvertRejectsNonUserModels(t *testing.T) {
+	testDir := "tmp/test_convert_models"
+	defer os.RemoveAll(testDir)
+
+	manager, _ := avro.NewManager(testDir)
+	if err := manager.WriteProductsToOCFFile("products.avro", manager.CreateSampleProducts(3)); err != nil {
+		t.Fatalf("Failed to write products: %v", err)
+	}
+
+	c, _ := New()
+	if _, err := c.Convert(filepath.Join(testDir, "products.avro"), filepath.Join(testDir, "products.json")); err == nil {
+		t.Error("Expected products to be rejected for json output")
+	}
+	if result, err := c.Convert(filepath.Join(testDir, "products.avro"), filepath.Join(testDir, "products.parquet")); err != nil || result.Model != ModelProduct {
+		t.Errorf("Expected products to convert to parquet, got %+v (%v)", result, err)
+	}
+	if _, err := c.Convert(filepath.Join(testDir, "products.avro"), filepath.Join(testDir, "copy.avro")); err == nil {
+		t.Error("Expected a conversion to the same format to be rejected")
+	}
+
+	t.Log("✓ Only users convert to json and protobuf")
+}
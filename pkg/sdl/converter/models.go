@@ -0,0 +1,272 @@
+package converter
+
+import (
+	"time"
+
+	"go-transport-prac/pkg/model"
+	"go-transport-prac/pkg/model/parquetmodel"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/parquet"
+)
+
+// UserToParquet converts an Avro user to its Parquet row
+func UserToParquet(u avro.User) parquet.User {
+	return parquetmodel.UserToParquet(model.UserFromAvro(u))
+}
+
+// UserFromParquet converts a Parquet row back to an Avro user
+func UserFromParquet(row parquet.User) avro.User {
+	return parquetmodel.UserFromParquet(row).ToAvro()
+}
+
+// ProductToParquet converts an Avro product to its Parquet row
+func ProductToParquet(p avro.Product) parquet.Product {
+	return parquetmodel.ProductToParquet(model.ProductFromAvro(p))
+}
+
+// ProductFromParquet converts a Parquet row back to an Avro product
+func ProductFromParquet(row parquet.Product) avro.Product {
+	return parquetmodel.ProductFromParquet(row).ToAvro()
+}
+
+// OrderToParquet converts an Avro order to its Parquet row
+func OrderToParquet(o avro.Order) parquet.Order {
+	row := parquet.Order{
+		ID:          o.ID,
+		UserID:      o.UserID,
+		OrderNumber: o.OrderNumber,
+		Status:      string(o.Status),
+		Summary: &parquet.OrderSummary{
+			Subtotal:     priceToParquet(o.Summary.Subtotal),
+			Tax:          priceToParquet(o.Summary.Tax),
+			ShippingCost: priceToParquet(o.Summary.ShippingCost),
+			Discount:     priceToParquet(o.Summary.Discount),
+			Total:        priceToParquet(o.Summary.Total),
+			TotalItems:   o.Summary.TotalItems,
+		},
+		CreatedAt:   o.CreatedAt,
+		UpdatedAt:   o.UpdatedAt,
+		ShippedAt:   o.ShippedAt,
+		DeliveredAt: o.DeliveredAt,
+	}
+	for _, item := range o.Items {
+		row.Items = append(row.Items, parquet.OrderItem{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			ProductSKU:  item.ProductSKU,
+			Quantity:    item.Quantity,
+			UnitPrice:   priceToParquet(item.UnitPrice),
+			TotalPrice:  priceToParquet(item.TotalPrice),
+			Variant:     item.ProductVariant,
+		})
+	}
+	if s := o.ShippingInfo; s != nil {
+		row.ShippingInfo = &parquet.ShippingInfo{
+			Address: &parquet.ShippingAddress{
+				RecipientName: s.Address.RecipientName,
+				Street:        s.Address.Street,
+				City:          s.Address.City,
+				State:         s.Address.State,
+				PostalCode:    s.Address.PostalCode,
+				Country:       s.Address.Country,
+			},
+			Method:            s.Method,
+			TrackingNumber:    stringValue(s.TrackingNumber),
+			Carrier:           stringValue(s.Carrier),
+			Cost:              priceToParquet(s.Cost),
+			EstimatedDelivery: s.EstimatedDelivery,
+		}
+	}
+	if p := o.PaymentInfo; p != nil {
+		row.PaymentInfo = &parquet.PaymentInfo{
+			Method:        p.Method,
+			Status:        string(p.Status),
+			TransactionID: stringValue(p.TransactionID),
+			Amount:        priceToParquet(p.Amount),
+			ProcessedAt:   p.ProcessedAt,
+		}
+	}
+	return row
+}
+
+// OrderFromParquet converts a Parquet row back to an Avro order
+func OrderFromParquet(row parquet.Order) avro.Order {
+	o := avro.Order{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		OrderNumber: row.OrderNumber,
+		Status:      avro.OrderStatus(row.Status),
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+		ShippedAt:   optionalTime(row.ShippedAt),
+		DeliveredAt: optionalTime(row.DeliveredAt),
+	}
+	for _, item := range row.Items {
+		o.Items = append(o.Items, avro.OrderItem{
+			ProductID:      item.ProductID,
+			ProductName:    item.ProductName,
+			ProductSKU:     item.ProductSKU,
+			Quantity:       item.Quantity,
+			UnitPrice:      priceFromParquet(item.UnitPrice),
+			TotalPrice:     priceFromParquet(item.TotalPrice),
+			ProductVariant: item.Variant,
+		})
+	}
+	if s := row.Summary; s != nil {
+		o.Summary = avro.OrderSummary{
+			Subtotal:     priceFromParquet(s.Subtotal),
+			Tax:          priceFromParquet(s.Tax),
+			ShippingCost: priceFromParquet(s.ShippingCost),
+			Discount:     priceFromParquet(s.Discount),
+			Total:        priceFromParquet(s.Total),
+			TotalItems:   s.TotalItems,
+		}
+	}
+	if s := row.ShippingInfo; s != nil {
+		o.ShippingInfo = &avro.ShippingInfo{
+			Method:            s.Method,
+			TrackingNumber:    optionalString(s.TrackingNumber),
+			Carrier:           optionalString(s.Carrier),
+			Cost:              priceFromParquet(s.Cost),
+			EstimatedDelivery: optionalTime(s.EstimatedDelivery),
+		}
+		if a := s.Address; a != nil {
+			o.ShippingInfo.Address = avro.ShippingAddress{
+				RecipientName: a.RecipientName,
+				Street:        a.Street,
+				City:          a.City,
+				State:         a.State,
+				PostalCode:    a.PostalCode,
+				Country:       a.Country,
+			}
+		}
+	}
+	if p := row.PaymentInfo; p != nil {
+		o.PaymentInfo = &avro.PaymentInfo{
+			Method:        p.Method,
+			Status:        avro.PaymentStatus(p.Status),
+			TransactionID: optionalString(p.TransactionID),
+			Amount:        priceFromParquet(p.Amount),
+			ProcessedAt:   optionalTime(p.ProcessedAt),
+		}
+	}
+	return o
+}
+
+// AnalyticsToParquet converts an Avro analytics event to its Parquet row
+func AnalyticsToParquet(a avro.Analytics) parquet.Analytics {
+	row := parquet.Analytics{
+		ID:         a.ID,
+		EventType:  a.EventType,
+		SessionID:  a.SessionID,
+		Timestamp:  a.Timestamp,
+		Properties: a.Properties,
+		Metrics:    a.Metrics,
+	}
+	if a.UserID != nil {
+		row.UserID = *a.UserID
+	}
+	if d := a.DeviceInfo; d != nil {
+		row.DeviceInfo = &parquet.DeviceInfo{
+			UserAgent: d.UserAgent,
+			Platform:  d.Platform,
+			Browser:   d.Browser,
+			Version:   d.Version,
+			Mobile:    d.Mobile,
+		}
+	}
+	if l := a.Location; l != nil {
+		row.Location = &parquet.Location{
+			Country:   l.Country,
+			Region:    stringValue(l.Region),
+			City:      stringValue(l.City),
+			Latitude:  floatValue(l.Latitude),
+			Longitude: floatValue(l.Longitude),
+		}
+	}
+	return row
+}
+
+// AnalyticsFromParquet converts a Parquet row back to an Avro analytics event.
+// Retention metadata has no Avro counterpart and is dropped
+func AnalyticsFromParquet(row parquet.Analytics) avro.Analytics {
+	a := avro.Analytics{
+		ID:         row.ID,
+		EventType:  row.EventType,
+		SessionID:  row.SessionID,
+		Timestamp:  row.Timestamp,
+		Properties: row.Properties,
+		Metrics:    row.Metrics,
+	}
+	if row.UserID != 0 {
+		userID := row.UserID
+		a.UserID = &userID
+	}
+	if d := row.DeviceInfo; d != nil {
+		a.DeviceInfo = &avro.DeviceInfo{
+			UserAgent: d.UserAgent,
+			Platform:  d.Platform,
+			Browser:   d.Browser,
+			Version:   d.Version,
+			Mobile:    d.Mobile,
+		}
+	}
+	if l := row.Location; l != nil {
+		a.Location = &avro.Location{
+			Country:   l.Country,
+			Region:    optionalString(l.Region),
+			City:      optionalString(l.City),
+			Latitude:  optionalFloat(l.Latitude),
+			Longitude: optionalFloat(l.Longitude),
+		}
+	}
+	return a
+}
+
+func priceToParquet(p avro.Price) *parquet.Price {
+	return parquetmodel.PriceToParquet(model.PriceFromAvro(p))
+}
+
+// priceFromParquet maps a zero discount, which Parquet stores as null, to nil
+func priceFromParquet(p *parquet.Price) avro.Price {
+	return parquetmodel.PriceFromParquet(p).ToAvro()
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// optionalString maps the empty string, which Parquet stores as null and
+// protobuf as its default, to nil
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func floatValue(f *float64) float64 {
+	if f == nil {
+		return 0
+	}
+	return *f
+}
+
+// optionalFloat maps zero, which Parquet stores as null, to nil
+func optionalFloat(f float64) *float64 {
+	if f == 0 {
+		return nil
+	}
+	return &f
+}
+
+// optionalTime drops timestamps Parquet read back as the zero time
+func optionalTime(t *time.Time) *time.Time {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,219 @@
+package converter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/hamba/avro/v2/ocf"
+
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/ndjson"
+	"go-transport-prac/pkg/sdl/parquet"
+)
+
+// rowIterator is the iteration shared by the Avro and Parquet streaming readers
+type rowIterator[T any] interface {
+	Next() bool
+	Value() T
+	Err() error
+}
+
+// ParseModel parses the name of a model; "analytics" names analytics events
+func ParseModel(name string) (Model, error) {
+	switch Model(name) {
+	case ModelUser, ModelProduct, ModelOrder, ModelAnalytics:
+		return Model(name), nil
+	case "analytics":
+		return ModelAnalytics, nil
+	}
+	return "", fmt.Errorf("unknown model %q: expected user, product, order or analytics", name)
+}
+
+// ExportNDJSON streams the records of the Avro or Parquet file src to dst as
+// newline-delimited JSON, compressed as dst's extension implies: .gz for
+// gzip, .zst for zstd. Every record is written as its Avro model marshals to
+// JSON, so both source formats yield the same lines
+func (c *Converter) ExportNDJSON(src, dst string) (Result, error) {
+	from, err := FormatOf(src)
+	if err != nil {
+		return Result{}, err
+	}
+	switch from {
+	case FormatAvro:
+		return c.exportAvroNDJSON(src, dst)
+	case FormatParquet:
+		return c.exportParquetNDJSON(src, dst)
+	}
+	return Result{}, fmt.Errorf("cannot export %s: only avro and parquet files export to ndjson", src)
+}
+
+func (c *Converter) exportAvroNDJSON(src, dst string) (Result, error) {
+	file, err := os.Open(src)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to open avro file: %w", err)
+	}
+	defer file.Close()
+
+	model, err := DetectAvroModel(file)
+	if err != nil {
+		return Result{}, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return Result{}, fmt.Errorf("failed to rewind avro file: %w", err)
+	}
+
+	var n int
+	switch model {
+	case ModelUser:
+		var reader *avro.OCFReader[avro.User]
+		if reader, err = c.avroManager.NewUserOCFReader(file); err == nil {
+			n, err = exportRows(dst, reader, identity[avro.User])
+		}
+	case ModelProduct:
+		var reader *avro.OCFReader[avro.Product]
+		if reader, err = c.avroManager.NewProductOCFReader(file); err == nil {
+			n, err = exportRows(dst, reader, identity[avro.Product])
+		}
+	case ModelOrder:
+		var reader *avro.OCFReader[avro.Order]
+		if reader, err = c.avroManager.NewOrderOCFReader(file); err == nil {
+			n, err = exportRows(dst, reader, identity[avro.Order])
+		}
+	case ModelAnalytics:
+		var reader *avro.OCFReader[avro.Analytics]
+		if reader, err = c.avroManager.NewAnalyticsOCFReader(file); err == nil {
+			n, err = exportRows(dst, reader, identity[avro.Analytics])
+		}
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to export %ss to ndjson: %w", model, err)
+	}
+	return Result{Model: model, Records: n}, nil
+}
+
+func (c *Converter) exportParquetNDJSON(src, dst string) (Result, error) {
+	model, err := DetectParquetModel(src)
+	if err != nil {
+		return Result{}, err
+	}
+
+	manager := parquet.NewSimpleManager(filepath.Dir(src))
+	name := filepath.Base(src)
+	var n int
+	switch model {
+	case ModelUser:
+		n, err = exportParquetRows(manager, name, dst, UserFromParquet)
+	case ModelProduct:
+		n, err = exportParquetRows(manager, name, dst, ProductFromParquet)
+	case ModelOrder:
+		n, err = exportParquetRows(manager, name, dst, OrderFromParquet)
+	case ModelAnalytics:
+		n, err = exportParquetRows(manager, name, dst, AnalyticsFromParquet)
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to export %ss to ndjson: %w", model, err)
+	}
+	return Result{Model: model, Records: n}, nil
+}
+
+// exportParquetRows streams the rows of a Parquet file in batches, converting
+// each to its Avro model
+func exportParquetRows[Row, Out any](manager *parquet.SimpleManager, name, dst string, convert func(Row) Out) (int, error) {
+	reader, err := parquet.OpenRowReader[Row](manager, name, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	return exportRows(dst, reader, convert)
+}
+
+// exportRows writes every row of rows, converted, to the NDJSON file dst
+func exportRows[T, Out any](dst string, rows rowIterator[T], convert func(T) Out) (int, error) {
+	var n int
+	err := ndjson.CreateFile(dst, func(w *ndjson.Writer) error {
+		for rows.Next() {
+			if err := w.Write(convert(rows.Value())); err != nil {
+				return err
+			}
+		}
+		n = w.Count()
+		return rows.Err()
+	})
+	return n, err
+}
+
+// ImportNDJSON reads the NDJSON file src, plain or compressed, as records of
+// model and writes them to the Avro or Parquet file dst. The records are held
+// in memory, as the Avro and Parquet writers take whole datasets
+func (c *Converter) ImportNDJSON(src, dst string, model Model) (Result, error) {
+	to, err := FormatOf(dst)
+	if err != nil {
+		return Result{}, err
+	}
+	if to != FormatAvro && to != FormatParquet {
+		return Result{}, fmt.Errorf("cannot import into %s: ndjson imports into avro and parquet files", dst)
+	}
+
+	var n int
+	switch model {
+	case ModelUser:
+		n, err = importRows(c, src, dst, to, c.avroManager.WriteUsersOCF,
+			func(m *parquet.SimpleManager, name string, users []avro.User) error {
+				return m.WriteUsersWithOptions(name, mapAll(users, UserToParquet), c.writerOptions)
+			})
+	case ModelProduct:
+		n, err = importRows(c, src, dst, to, c.avroManager.WriteProductsOCF,
+			func(m *parquet.SimpleManager, name string, products []avro.Product) error {
+				return m.WriteProductsWithOptions(name, mapAll(products, ProductToParquet), c.writerOptions)
+			})
+	case ModelOrder:
+		n, err = importRows(c, src, dst, to, c.avroManager.WriteOrdersOCF,
+			func(m *parquet.SimpleManager, name string, orders []avro.Order) error {
+				return m.WriteOrdersWithOptions(name, mapAll(orders, OrderToParquet), c.writerOptions)
+			})
+	case ModelAnalytics:
+		n, err = importRows(c, src, dst, to, c.avroManager.WriteAnalyticsOCF,
+			func(m *parquet.SimpleManager, name string, events []avro.Analytics) error {
+				return m.WriteAnalyticsWithOptions(name, mapAll(events, AnalyticsToParquet), c.writerOptions)
+			})
+	default:
+		return Result{}, fmt.Errorf("unknown model %q", model)
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to import %ss from ndjson: %w", model, err)
+	}
+	return Result{Model: model, Records: n}, nil
+}
+
+// importRows reads the records of src and writes them with the writer of to
+func importRows[T any](c *Converter, src, dst string, to Format,
+	writeAvro func(io.Writer, []T, ...ocf.EncoderFunc) error,
+	writeParquet func(*parquet.SimpleManager, string, []T) error) (int, error) {
+	records, err := ndjson.ImportFile[T](src)
+	if err != nil {
+		return 0, err
+	}
+	if to == FormatParquet {
+		return len(records), writeParquet(parquet.NewSimpleManager(filepath.Dir(dst)), filepath.Base(dst), records)
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create avro file: %w", err)
+	}
+	defer out.Close()
+	if err := writeAvro(out, records, c.ocfOptions...); err != nil {
+		return 0, err
+	}
+	if err := out.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close avro file: %w", err)
+	}
+	return len(records), nil
+}
+
+// identity returns v unchanged
+func identity[T any](v T) T {
+	return v
+}
@@ -0,0 +1,99 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/ndjson"
+)
+
+func TestNDJSONExportImport(t *testing.T) {
+	testDir := "tmp/test_ndjson"
+	defer os.RemoveAll(testDir)
+	if err := os.MkdirAll(testDir, 0755); err != nil {
+		t.Fatalf("Failed to create test dir: %v", err)
+	}
+
+	c, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create converter: %v", err)
+	}
+	manager, _ := avro.NewManager("")
+	path := func(name string) string { return filepath.Join(testDir, name) }
+
+	products := manager.CreateSampleProducts(15)
+	out, err := os.Create(path("products.avro"))
+	if err != nil {
+		t.Fatalf("Failed to create avro file: %v", err)
+	}
+	if err := manager.WriteProductsOCF(out, products); err != nil {
+		t.Fatalf("Failed to write products: %v", err)
+	}
+	out.Close()
+	if _, err := c.Convert(path("products.avro"), path("products.parquet")); err != nil {
+		t.Fatalf("Failed to convert products to parquet: %v", err)
+	}
+
+	// Avro and Parquet sources export the same lines
+	for _, pair := range [][2]string{
+		{"products.avro", "from_avro.ndjson.gz"},
+		{"products.parquet", "from_parquet.ndjson.zst"},
+	} {
+		result, err := c.ExportNDJSON(path(pair[0]), path(pair[1]))
+		if err != nil {
+			t.Fatalf("Failed to export %s: %v", pair[0], err)
+		}
+		if result.Model != ModelProduct || result.Records != len(products) {
+			t.Errorf("%s: expected %d products, got %+v", pair[0], len(products), result)
+		}
+	}
+	fromAvro, err := ndjson.ImportFile[avro.Product](path("from_avro.ndjson.gz"))
+	if err != nil {
+		t.Fatalf("Failed to read exported products: %v", err)
+	}
+	fromParquet, err := ndjson.ImportFile[avro.Product](path("from_parquet.ndjson.zst"))
+	if err != nil {
+		t.Fatalf("Failed to read exported products: %v", err)
+	}
+	if !reflect.DeepEqual(utcProducts(fromAvro), utcProducts(fromParquet)) {
+		t.Error("Expected Avro and Parquet exports to match")
+	}
+
+	// Import back into both formats
+	for _, name := range []string{"back.avro", "back.parquet"} {
+		result, err := c.ImportNDJSON(path("from_avro.ndjson.gz"), path(name), ModelProduct)
+		if err != nil {
+			t.Fatalf("Failed to import into %s: %v", name, err)
+		}
+		if result.Records != len(products) {
+			t.Errorf("%s: expected %d products, got %+v", name, len(products), result)
+		}
+	}
+	in, err := os.Open(path("back.avro"))
+	if err != nil {
+		t.Fatalf("Failed to open imported products: %v", err)
+	}
+	defer in.Close()
+	back, err := manager.ReadProductsOCF(in)
+	if err != nil {
+		t.Fatalf("Failed to read imported products: %v", err)
+	}
+	if !reflect.DeepEqual(utcProducts(back), utcProducts(fromAvro)) {
+		t.Error("Products changed across the ndjson round trip")
+	}
+	if result, err := c.ExportNDJSON(path("back.parquet"), path("again.ndjson")); err != nil || result.Records != len(products) {
+		t.Errorf("Expected the imported parquet file to export, got %+v, %v", result, err)
+	}
+
+	if _, err := c.ImportNDJSON(path("from_avro.ndjson.gz"), path("back.json"), ModelProduct); err == nil {
+		t.Error("Expected importing into json to fail")
+	}
+	if _, err := ParseModel("invoice"); err == nil {
+		t.Error("Expected an unknown model to fail")
+	}
+
+	t.Log("✓ Avro and Parquet files export to compressed ndjson and import back")
+}
@@ -0,0 +1,26 @@
+package converter
+
+import (
+	"fmt"
+
+	"go-transport-prac/pkg/model"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+// protoStatusPrefix turns an Avro status symbol into a protobuf enum name
+const protoStatusPrefix = "USER_STATUS_"
+
+// UserToProto converts an Avro user to its protobuf message, rejecting
+// statuses the protobuf enum lacks
+func UserToProto(u avro.User) (*user.User, error) {
+	if _, ok := user.UserStatus_value[protoStatusPrefix+string(u.Status)]; !ok {
+		return nil, fmt.Errorf("user %d: status %q has no protobuf value", u.ID, u.Status)
+	}
+	return model.UserFromAvro(u).ToProto(), nil
+}
+
+// UserFromProto converts a protobuf user back to an Avro user
+func UserFromProto(msg *user.User) avro.User {
+	return model.UserFromProto(msg).ToAvro()
+}
@@ -0,0 +1,238 @@
+// Package deadletter collects records that fail to decode, so a tolerant
+// reader can return the good records of a file instead of failing it whole.
+// Every failure is appended to a dead-letter file as one JSON line holding
+// the source, position, error and original bytes of the record.
+package deadletter
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go-transport-prac/internal/types"
+)
+
+// Mode selects how readers handle records that fail to decode
+type Mode int
+
+const (
+	// Strict fails the whole read on the first bad record
+	Strict Mode = iota
+	// Tolerant skips bad records, sends them to the dead-letter file and
+	// returns the good records with an *Error summarizing the failures
+	Tolerant
+)
+
+// String returns the flag value of the mode
+func (m Mode) String() string {
+	if m == Tolerant {
+		return "tolerant"
+	}
+	return "strict"
+}
+
+// ParseMode parses a strict or tolerant flag value
+func ParseMode(s string) (Mode, error) {
+	switch s {
+	case "strict", "":
+		return Strict, nil
+	case "tolerant":
+		return Tolerant, nil
+	}
+	return Strict, fmt.Errorf("unknown decode mode %q, want strict or tolerant", s)
+}
+
+// Entry is one dead-lettered record
+type Entry struct {
+	Source string `json:"source"`
+	// Offset is the byte offset of Data in the source
+	Offset int64 `json:"offset"`
+	// Record is the index of the first record Data holds
+	Record int `json:"record"`
+	// Records counts the records lost with Data, such as a whole row group,
+	// and is 1 when the count is unknown
+	Records int    `json:"records"`
+	Error   string `json:"error"`
+	// Data holds the original bytes, base64 encoded in the file
+	Data     []byte    `json:"data"`
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// File appends entries to a JSON Lines dead-letter file. It is safe for
+// concurrent use
+type File struct {
+	mu    sync.Mutex
+	path  string
+	clock types.Clock
+	count int
+}
+
+// NewFile creates a dead-letter file at path; it is created on the first entry
+func NewFile(path string) *File {
+	return &File{path: path, clock: types.SystemClock{}}
+}
+
+// WithClock sets the clock entries are stamped with
+func (f *File) WithClock(clock types.Clock) *File {
+	f.clock = types.ClockOrSystem(clock)
+	return f
+}
+
+// Path returns the dead-letter file path
+func (f *File) Path() string {
+	return f.path
+}
+
+// Count returns the number of entries added through f
+func (f *File) Count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.count
+}
+
+// Add appends entry, stamping it with the current time if FailedAt is unset
+func (f *File) Add(entry Entry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if entry.FailedAt.IsZero() {
+		entry.FailedAt = f.clock.Now()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return fmt.Errorf("failed to create dead-letter directory: %w", err)
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open dead-letter file: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write dead letter: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close dead-letter file: %w", err)
+	}
+	f.count++
+	return nil
+}
+
+// ReadFile reads every entry of a dead-letter file
+func ReadFile(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dead-letter file: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1<<30)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return entries, fmt.Errorf("failed to parse dead letter %d: %w", len(entries)+1, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, fmt.Errorf("failed to read dead-letter file: %w", err)
+	}
+	return entries, nil
+}
+
+// Error summarizes a tolerant read that skipped bad records. The reader
+// returns it together with the good records
+type Error struct {
+	Source string
+	// Good counts the records returned
+	Good int
+	// Failed counts the records lost, in Entries dead-letter entries
+	Failed  int
+	Entries int
+	// DeadLetter is the file the entries went to, empty when none was set
+	DeadLetter string
+	// First is the first decode failure
+	First error
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%d records in %s failed to decode, %d read", e.Failed, e.Source, e.Good)
+	if e.DeadLetter != "" {
+		msg += fmt.Sprintf(", %d sent to %s", e.Entries, e.DeadLetter)
+	}
+	return fmt.Sprintf("%s: %v", msg, e.First)
+}
+
+// Unwrap returns the first decode failure
+func (e *Error) Unwrap() error {
+	return e.First
+}
+
+// Partial reports whether err is only the summary of a tolerant read, in
+// which case the records read alongside it are usable
+func Partial(err error) (*Error, bool) {
+	var summary *Error
+	if errors.As(err, &summary) {
+		return summary, true
+	}
+	return nil, false
+}
+
+// Batch tracks the failures of one tolerant read of source
+type Batch struct {
+	letters *File
+	summary Error
+}
+
+// NewBatch starts tracking a read of source; letters may be nil to only count failures
+func NewBatch(letters *File, source string) *Batch {
+	batch := &Batch{letters: letters, summary: Error{Source: source}}
+	if letters != nil {
+		batch.summary.DeadLetter = letters.Path()
+	}
+	return batch
+}
+
+// Fail records a failure covering records records from the one at index
+// record, whose original bytes start at offset. It only fails when the
+// dead-letter file cannot be written
+func (b *Batch) Fail(offset int64, record, records int, data []byte, cause error) error {
+	if b.summary.First == nil {
+		b.summary.First = cause
+	}
+	b.summary.Failed += records
+	b.summary.Entries++
+	if b.letters == nil {
+		return nil
+	}
+	return b.letters.Add(Entry{
+		Source:  b.summary.Source,
+		Offset:  offset,
+		Record:  record,
+		Records: records,
+		Error:   cause.Error(),
+		Data:    data,
+	})
+}
+
+// Err returns nil when nothing failed, or the *Error summarizing the read
+// that returned good records
+func (b *Batch) Err(good int) error {
+	if b.summary.Entries == 0 {
+		return nil
+	}
+	summary := b.summary
+	summary.Good = good
+	return &summary
+}
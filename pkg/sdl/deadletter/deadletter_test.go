@@ -0,0 +1,89 @@
+package deadletter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-transport-prac/internal/testutil"
+)
+
+func TestBatchDeadLettersFailures(t *testing.T) {
+	dir := "tmp/test_deadletter"
+	defer os.RemoveAll(dir)
+
+	letters := NewFile(filepath.Join(dir, "users.deadletter.jsonl")).WithClock(testutil.NewDefaultFakeClock())
+	batch := NewBatch(letters, "users.avro")
+	if err := batch.Err(3); err != nil {
+		t.Fatalf("Expected no error before any failure, got %v", err)
+	}
+
+	cause := errors.New("bad record")
+	if err := batch.Fail(120, 3, 1, []byte{0xff, 0x01}, cause); err != nil {
+		t.Fatalf("Fail failed: %v", err)
+	}
+	if err := batch.Fail(400, 7, 5, []byte("rest"), fmt.Errorf("truncated")); err != nil {
+		t.Fatalf("Fail failed: %v", err)
+	}
+
+	err := batch.Err(6)
+	summary, ok := Partial(fmt.Errorf("read failed: %w", err))
+	if !ok {
+		t.Fatalf("Expected a partial read error, got %v", err)
+	}
+	if summary.Good != 6 || summary.Failed != 6 || summary.Entries != 2 || !errors.Is(err, cause) {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+	if !strings.Contains(err.Error(), "6 records in users.avro failed to decode") || !strings.Contains(err.Error(), letters.Path()) {
+		t.Errorf("Unexpected message: %v", err)
+	}
+
+	entries, err := ReadFile(letters.Path())
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if len(entries) != 2 || letters.Count() != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	first := entries[0]
+	if first.Source != "users.avro" || first.Offset != 120 || first.Record != 3 || first.Error != "bad record" ||
+		!bytes.Equal(first.Data, []byte{0xff, 0x01}) || !first.FailedAt.Equal(testutil.DefaultFakeTime) {
+		t.Errorf("Unexpected entry: %+v", first)
+	}
+
+	t.Log("✓ Failures are dead-lettered with their bytes and summarized")
+}
+
+func TestBatchWithoutFile(t *testing.T) {
+	batch := NewBatch(nil, "users.parquet")
+	if err := batch.Fail(0, 0, 10, nil, errors.New("corrupt page")); err != nil {
+		t.Fatalf("Fail failed: %v", err)
+	}
+	summary, ok := Partial(batch.Err(0))
+	if !ok || summary.Failed != 10 || summary.DeadLetter != "" || strings.Contains(summary.Error(), "sent to") {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+	if _, ok := Partial(errors.New("other")); ok {
+		t.Error("Expected other errors not to be partial")
+	}
+
+	t.Log("✓ Failures are counted without a dead-letter file")
+}
+
+func TestParseMode(t *testing.T) {
+	for _, mode := range []Mode{Strict, Tolerant} {
+		parsed, err := ParseMode(mode.String())
+		if err != nil || parsed != mode {
+			t.Errorf("Expected %s to round trip, got %v (%v)", mode, parsed, err)
+		}
+	}
+	if _, err := ParseMode("lenient"); err == nil {
+		t.Error("Expected an unknown mode to fail")
+	}
+
+	t.Log("✓ Decode modes parse from flags")
+}
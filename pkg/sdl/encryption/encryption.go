@@ -0,0 +1,453 @@
+// Package encryption encrypts SDL files at rest with AES-GCM, so Avro and
+// Parquet files can be kept on storage that is not trusted with the data.
+//
+// An encrypted file is a header followed by the plaintext sealed in chunks:
+//
+//	magic "SDLENC" | version | chunk size (uint32) | key ID length | key ID | nonce prefix (7 bytes)
+//	chunk 0 | chunk 1 | ... | final chunk
+//
+// Every chunk holds up to chunk size bytes of plaintext plus a 16 byte tag.
+// Its nonce is the file's random prefix, the chunk index and a flag marking
+// the final chunk, and the header is authenticated with every chunk, so
+// reordered, truncated or extended files and edited headers fail to decrypt.
+// Chunks let files be written as a stream and read at random offsets,
+// which Parquet readers need for the footer.
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Magic starts every encrypted file
+const Magic = "SDLENC"
+
+const (
+	version = 1
+	// DefaultChunkSize is the plaintext size of every chunk but the last
+	DefaultChunkSize = 64 * 1024
+	prefixSize       = 7
+	tagSize          = 16
+	maxKeyIDLen      = 255
+	// maxChunkSize bounds the chunk size a header may declare
+	maxChunkSize = 16 * 1024 * 1024
+)
+
+// ErrNotEncrypted is returned when a file does not start with Magic
+var ErrNotEncrypted = fmt.Errorf("file is not encrypted")
+
+// Header is the metadata stored in front of an encrypted file
+type Header struct {
+	KeyID     string
+	Nonce     [prefixSize]byte
+	ChunkSize int
+}
+
+// IsEncrypted reports whether prefix, the first bytes of a file, starts with Magic
+func IsEncrypted(prefix []byte) bool {
+	return bytes.HasPrefix(prefix, []byte(Magic))
+}
+
+// marshal encodes h as it is written in front of the file
+func (h Header) marshal() []byte {
+	b := make([]byte, 0, len(Magic)+6+len(h.KeyID)+prefixSize)
+	b = append(b, Magic...)
+	b = append(b, version)
+	b = binary.BigEndian.AppendUint32(b, uint32(h.ChunkSize))
+	b = append(b, byte(len(h.KeyID)))
+	b = append(b, h.KeyID...)
+	return append(b, h.Nonce[:]...)
+}
+
+// ReadHeader reads the header in front of an encrypted file and returns it
+// with its encoding, which authenticates every chunk
+func ReadHeader(r io.Reader) (Header, []byte, error) {
+	fixed := make([]byte, len(Magic)+6)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return Header{}, nil, ErrNotEncrypted
+		}
+		return Header{}, nil, fmt.Errorf("failed to read encryption header: %w", err)
+	}
+	if !IsEncrypted(fixed) {
+		return Header{}, nil, ErrNotEncrypted
+	}
+	if v := fixed[len(Magic)]; v != version {
+		return Header{}, nil, fmt.Errorf("unsupported encryption version %d", v)
+	}
+
+	h := Header{ChunkSize: int(binary.BigEndian.Uint32(fixed[len(Magic)+1:]))}
+	if h.ChunkSize <= 0 || h.ChunkSize > maxChunkSize {
+		return Header{}, nil, fmt.Errorf("invalid encryption chunk size %d", h.ChunkSize)
+	}
+	rest := make([]byte, int(fixed[len(fixed)-1])+prefixSize)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return Header{}, nil, fmt.Errorf("failed to read encryption header: %w", err)
+	}
+	h.KeyID = string(rest[:len(rest)-prefixSize])
+	copy(h.Nonce[:], rest[len(rest)-prefixSize:])
+	return h, append(fixed, rest...), nil
+}
+
+// chunkNonce returns the nonce of chunk index
+func chunkNonce(prefix [prefixSize]byte, index uint32, final bool) []byte {
+	nonce := make([]byte, 0, 12)
+	nonce = append(nonce, prefix[:]...)
+	nonce = binary.BigEndian.AppendUint32(nonce, index)
+	if final {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// Writer encrypts everything written to it. Close must be called to write
+// the final chunk; a file without it fails to decrypt
+type Writer struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	header Header
+	aad    []byte
+	buf    []byte
+	index  uint32
+	closed bool
+}
+
+// NewWriter writes the header for the current key of keys to w and returns
+// a writer encrypting to w
+func NewWriter(ctx context.Context, w io.Writer, keys KeyProvider) (*Writer, error) {
+	keyID, key, err := keys.EncryptionKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get encryption key: %w", err)
+	}
+	if len(keyID) > maxKeyIDLen {
+		return nil, fmt.Errorf("key ID longer than %d bytes", maxKeyIDLen)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	h := Header{KeyID: keyID, ChunkSize: DefaultChunkSize}
+	if _, err := rand.Read(h.Nonce[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	aad := h.marshal()
+	if _, err := w.Write(aad); err != nil {
+		return nil, fmt.Errorf("failed to write encryption header: %w", err)
+	}
+	return &Writer{w: w, aead: aead, header: h, aad: aad, buf: make([]byte, 0, h.ChunkSize)}, nil
+}
+
+// Header returns the header written in front of the file
+func (w *Writer) Header() Header {
+	return w.header
+}
+
+// Write implements io.Writer. A full chunk is only sealed once more data
+// arrives, since the last chunk is sealed differently
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, fmt.Errorf("write to closed encryption writer")
+	}
+	n := len(p)
+	for len(p) > 0 {
+		if len(w.buf) == cap(w.buf) {
+			if err := w.seal(false); err != nil {
+				return n - len(p), err
+			}
+		}
+		c := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+c]
+		p = p[c:]
+	}
+	return n, nil
+}
+
+// Close seals the final chunk. It does not close the underlying writer
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.seal(true)
+}
+
+func (w *Writer) seal(final bool) error {
+	if w.index == ^uint32(0) {
+		return fmt.Errorf("file too large to encrypt")
+	}
+	sealed := w.aead.Seal(nil, chunkNonce(w.header.Nonce, w.index, final), w.buf, w.aad)
+	if _, err := w.w.Write(sealed); err != nil {
+		return fmt.Errorf("failed to write encrypted chunk: %w", err)
+	}
+	w.index++
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// openAEAD reads the key named by h from keys
+func openAEAD(ctx context.Context, h Header, keys KeyProvider) (cipher.AEAD, error) {
+	if keys == nil {
+		return nil, fmt.Errorf("file is encrypted with key %q but no key provider is configured", h.KeyID)
+	}
+	key, err := keys.DecryptionKey(ctx, h.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decryption key %q: %w", h.KeyID, err)
+	}
+	return newAEAD(key)
+}
+
+// Reader decrypts an encrypted file as a stream
+type Reader struct {
+	r      *bufio.Reader
+	aead   cipher.AEAD
+	header Header
+	aad    []byte
+	chunk  []byte
+	plain  []byte
+	index  uint32
+	done   bool
+	err    error
+}
+
+// NewReader reads the header from r and returns a reader of the plaintext,
+// decrypted with the key the header names
+func NewReader(ctx context.Context, r io.Reader, keys KeyProvider) (*Reader, error) {
+	h, aad, err := ReadHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := openAEAD(ctx, h, keys)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{
+		r:      bufio.NewReaderSize(r, h.ChunkSize+tagSize+1),
+		aead:   aead,
+		header: h,
+		aad:    aad,
+		chunk:  make([]byte, h.ChunkSize+tagSize),
+	}, nil
+}
+
+// Header returns the header of the file
+func (r *Reader) Header() Header {
+	return r.header
+}
+
+// Read implements io.Reader. It fails if the file was modified or cut short
+func (r *Reader) Read(p []byte) (int, error) {
+	for len(r.plain) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		r.err = r.next()
+	}
+	n := copy(p, r.plain)
+	r.plain = r.plain[n:]
+	return n, nil
+}
+
+// next decrypts the next chunk, which is final when nothing follows it
+func (r *Reader) next() error {
+	n, err := io.ReadFull(r.r, r.chunk)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return fmt.Errorf("failed to read encrypted chunk: %w", err)
+	}
+	final := err != nil
+	if !final {
+		if _, err := r.r.Peek(1); err == io.EOF {
+			final = true
+		}
+	}
+	if n < tagSize {
+		return fmt.Errorf("encrypted file is truncated")
+	}
+
+	plain, err := r.aead.Open(r.chunk[:0], chunkNonce(r.header.Nonce, r.index, final), r.chunk[:n], r.aad)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt chunk %d: file was modified, truncated or encrypted with another key", r.index)
+	}
+	r.index++
+	r.plain = plain
+	r.done = final
+	return nil
+}
+
+// ReaderAt decrypts an encrypted file at random offsets
+type ReaderAt struct {
+	r      io.ReaderAt
+	aead   cipher.AEAD
+	header Header
+	aad    []byte
+	offset int64
+	chunks int64
+	size   int64
+
+	mu     sync.Mutex
+	cached int64
+	sealed []byte
+	plain  []byte
+}
+
+// NewReaderAt reads the header of the size byte encrypted file r and returns
+// a reader of its plaintext
+func NewReaderAt(ctx context.Context, r io.ReaderAt, size int64, keys KeyProvider) (*ReaderAt, error) {
+	h, aad, err := ReadHeader(io.NewSectionReader(r, 0, size))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := openAEAD(ctx, h, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	body := size - int64(len(aad))
+	sealed := int64(h.ChunkSize + tagSize)
+	chunks := (body + sealed - 1) / sealed
+	if chunks == 0 || body-(chunks-1)*sealed < tagSize {
+		return nil, fmt.Errorf("encrypted file is truncated")
+	}
+	return &ReaderAt{
+		r:      r,
+		aead:   aead,
+		header: h,
+		aad:    aad,
+		offset: int64(len(aad)),
+		chunks: chunks,
+		size:   body - chunks*tagSize,
+	}, nil
+}
+
+// Size returns the plaintext size
+func (r *ReaderAt) Size() int64 {
+	return r.size
+}
+
+// Header returns the header of the file
+func (r *ReaderAt) Header() Header {
+	return r.header
+}
+
+// ReadAt implements io.ReaderAt, decrypting every chunk p overlaps. It is
+// safe for concurrent use
+func (r *ReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset")
+	}
+	chunkSize := int64(r.header.ChunkSize)
+	n := 0
+	for n < len(p) && off < r.size {
+		index := off / chunkSize
+		c, err := r.readChunk(index, p[n:], off-index*chunkSize)
+		n += c
+		off += int64(c)
+		if err != nil {
+			return n, err
+		}
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// readChunk copies chunk index from offset within it into p. The last chunk
+// decrypted is kept, since readers tend to read a chunk in small pieces
+func (r *ReaderAt) readChunk(index int64, p []byte, offset int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cached != index || r.plain == nil {
+		chunkSize := int64(r.header.ChunkSize)
+		start := r.offset + index*(chunkSize+tagSize)
+		length := min(chunkSize+tagSize, r.offset+r.size+r.chunks*tagSize-start)
+		if r.sealed == nil {
+			r.sealed = make([]byte, chunkSize+tagSize)
+		}
+		if _, err := r.r.ReadAt(r.sealed[:length], start); err != nil && err != io.EOF {
+			return 0, fmt.Errorf("failed to read encrypted chunk: %w", err)
+		}
+		plain, err := r.aead.Open(r.plain[:0], chunkNonce(r.header.Nonce, uint32(index), index == r.chunks-1), r.sealed[:length], r.aad)
+		if err != nil {
+			r.plain = nil
+			return 0, fmt.Errorf("failed to decrypt chunk %d: file was modified or encrypted with another key", index)
+		}
+		r.plain, r.cached = plain, index
+	}
+	return copy(p, r.plain[offset:]), nil
+}
+
+// Encrypt runs write against a writer encrypting to w with keys, then seals
+// the final chunk
+func Encrypt(ctx context.Context, w io.Writer, keys KeyProvider, write func(io.Writer) error) error {
+	enc, err := NewWriter(ctx, w, keys)
+	if err != nil {
+		return err
+	}
+	if err := write(enc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// Open returns the plaintext of r, decrypted with keys. Once keys are
+// configured a file that is not encrypted fails with ErrNotEncrypted, so a
+// plain file planted on untrusted storage is not read as data, unless
+// allowPlaintext is set to read files written before encryption was turned on
+// while migrating them. With nil keys plain files are returned as they are and
+// encrypted files fail to open
+func Open(ctx context.Context, r io.Reader, keys KeyProvider, allowPlaintext bool) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	if prefix, _ := br.Peek(len(Magic)); !IsEncrypted(prefix) {
+		if err := checkPlaintext(keys, allowPlaintext); err != nil {
+			return nil, err
+		}
+		return br, nil
+	}
+	return NewReader(ctx, br, keys)
+}
+
+// OpenAt returns the plaintext of the size byte file r and its size,
+// decrypted with keys. Plain files are handled as by Open
+func OpenAt(ctx context.Context, r io.ReaderAt, size int64, keys KeyProvider, allowPlaintext bool) (io.ReaderAt, int64, error) {
+	prefix := make([]byte, len(Magic))
+	if n, _ := r.ReadAt(prefix, 0); !IsEncrypted(prefix[:n]) {
+		if err := checkPlaintext(keys, allowPlaintext); err != nil {
+			return nil, 0, err
+		}
+		return r, size, nil
+	}
+	dec, err := NewReaderAt(ctx, r, size, keys)
+	if err != nil {
+		return nil, 0, err
+	}
+	return dec, dec.Size(), nil
+}
+
+// checkPlaintext reports whether a plain file may be read with keys
+func checkPlaintext(keys KeyProvider, allowPlaintext bool) error {
+	if keys != nil && !allowPlaintext {
+		return fmt.Errorf("%w: encryption is configured and plaintext reads are not allowed", ErrNotEncrypted)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"go-transport-prac/internal/config"
+)
+
+func testKeys(t *testing.T, ids ...string) *StaticKeys {
+	t.Helper()
+	keys := make(map[string][]byte)
+	for _, id := range ids {
+		key := make([]byte, 32)
+		rand.Read(key)
+		keys[id] = key
+	}
+	s, err := NewStaticKeys(ids[0], keys)
+	if err != nil {
+		t.Fatalf("Failed to create keys: %v", err)
+	}
+	return s
+}
+
+func encrypt(t *testing.T, keys KeyProvider, plain []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	err := Encrypt(context.Background(), &buf, keys, func(w io.Writer) error {
+		// Uneven writes cross chunk boundaries
+		for len(plain) > 0 {
+			n := min(len(plain), 1000)
+			if _, err := w.Write(plain[:n]); err != nil {
+				return err
+			}
+			plain = plain[n:]
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func decrypt(keys KeyProvider, data []byte) ([]byte, error) {
+	r, err := Open(context.Background(), bytes.NewReader(data), keys, false)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestRoundTrip(t *testing.T) {
+	keys := testKeys(t, "k1")
+	ctx := context.Background()
+
+	for _, size := range []int{0, 1, DefaultChunkSize - 1, DefaultChunkSize, DefaultChunkSize + 1, 3*DefaultChunkSize + 17} {
+		plain := make([]byte, size)
+		rand.Read(plain)
+		data := encrypt(t, keys, plain)
+
+		if !IsEncrypted(data) || size >= 64 && bytes.Contains(data, plain[:64]) {
+			t.Fatalf("size %d: expected an encrypted file", size)
+		}
+
+		got, err := decrypt(keys, data)
+		if err != nil {
+			t.Fatalf("size %d: failed to decrypt stream: %v", size, err)
+		}
+		if !bytes.Equal(got, plain) {
+			t.Fatalf("size %d: stream decryption differs", size)
+		}
+
+		r, n, err := OpenAt(ctx, bytes.NewReader(data), int64(len(data)), keys, false)
+		if err != nil {
+			t.Fatalf("size %d: failed to open at: %v", size, err)
+		}
+		if n != int64(size) {
+			t.Fatalf("size %d: expected plaintext size %d, got %d", size, size, n)
+		}
+		got = make([]byte, size)
+		if _, err := r.ReadAt(got, 0); err != nil {
+			t.Fatalf("size %d: failed to read at 0: %v", size, err)
+		}
+		if !bytes.Equal(got, plain) {
+			t.Fatalf("size %d: random access decryption differs", size)
+		}
+		// A read across a chunk boundary near the end, as Parquet reads footers
+		if size > 10 {
+			off := int64(size) - 10
+			tail := make([]byte, 10)
+			if _, err := r.ReadAt(tail, off); err != nil || !bytes.Equal(tail, plain[off:]) {
+				t.Fatalf("size %d: failed to read the tail: %v", size, err)
+			}
+			if _, err := r.ReadAt(make([]byte, 20), off); err != io.EOF {
+				t.Fatalf("size %d: expected io.EOF past the end, got %v", size, err)
+			}
+		}
+	}
+
+	t.Log("✓ Streams and random reads decrypt what was encrypted")
+}
+
+func TestTamperingIsDetected(t *testing.T) {
+	keys := testKeys(t, "k1", "k2")
+	plain := bytes.Repeat([]byte("secret user data "), 10000)
+	data := encrypt(t, keys, plain)
+	sealed := DefaultChunkSize + tagSize
+	headerLen := len(data) - len(plain) - (len(plain)/DefaultChunkSize+1)*tagSize
+
+	tests := map[string][]byte{
+		"flipped byte":         flip(data, len(data)/2),
+		"flipped header":       flip(data, len(Magic)+3),
+		"dropped final chunk":  data[:headerLen+sealed*2],
+		"truncated chunk":      data[:len(data)-5],
+		"appended data":        append(append([]byte(nil), data...), data[headerLen:headerLen+sealed]...),
+		"reordered chunks":     swapChunks(data, headerLen, sealed),
+		"header only":          data[:headerLen],
+		"renamed key in place": renameKey(data),
+	}
+	for name, tampered := range tests {
+		if _, err := decrypt(keys, tampered); err == nil {
+			t.Errorf("%s: expected decryption to fail", name)
+		}
+		if r, _, err := OpenAt(context.Background(), bytes.NewReader(tampered), int64(len(tampered)), keys, false); err == nil {
+			if _, err := io.ReadAll(io.NewSectionReader(r, 0, int64(len(plain)))); err == nil {
+				t.Errorf("%s: expected random access decryption to fail", name)
+			}
+		}
+	}
+
+	t.Log("✓ Modified, truncated and reordered files fail to decrypt")
+}
+
+func flip(data []byte, i int) []byte {
+	out := append([]byte(nil), data...)
+	out[i] ^= 0x01
+	return out
+}
+
+func swapChunks(data []byte, headerLen, sealed int) []byte {
+	out := append([]byte(nil), data...)
+	first := out[headerLen : headerLen+sealed]
+	second := out[headerLen+sealed : headerLen+2*sealed]
+	tmp := append([]byte(nil), first...)
+	copy(first, second)
+	copy(second, tmp)
+	return out
+}
+
+func renameKey(data []byte) []byte {
+	// "k1" becomes "k2", a key the provider also holds
+	return bytes.Replace(data, []byte("k1"), []byte("k2"), 1)
+}
+
+func TestKeyRotation(t *testing.T) {
+	keys := testKeys(t, "2024-01", "2024-06")
+	old := encrypt(t, keys, []byte("written before rotation"))
+
+	if err := keys.Rotate("2024-06"); err != nil {
+		t.Fatalf("Failed to rotate: %v", err)
+	}
+	current := encrypt(t, keys, []byte("written after rotation"))
+
+	h, _, err := ReadHeader(bytes.NewReader(current))
+	if err != nil || h.KeyID != "2024-06" {
+		t.Fatalf("Expected the rotated key ID in the header, got %q (%v)", h.KeyID, err)
+	}
+	for _, data := range [][]byte{old, current} {
+		if _, err := decrypt(keys, data); err != nil {
+			t.Errorf("Failed to decrypt after rotation: %v", err)
+		}
+	}
+
+	if _, err := decrypt(testKeys(t, "2024-01"), old); err == nil {
+		t.Error("Expected a different key with the same ID to fail")
+	}
+	if _, err := decrypt(testKeys(t, "other"), old); err == nil || !strings.Contains(err.Error(), "2024-01") {
+		t.Errorf("Expected an unknown key error naming the key, got %v", err)
+	}
+	if _, err := decrypt(nil, old); err == nil {
+		t.Error("Expected an encrypted file to fail without a key provider")
+	}
+	if err := keys.Rotate("missing"); err == nil {
+		t.Error("Expected rotating to an unknown key to fail")
+	}
+
+	t.Log("✓ Files stay readable after the write key is rotated")
+}
+
+func TestPlainFiles(t *testing.T) {
+	plain := []byte("Obj\x01 an Avro file that was never encrypted")
+	got, err := decrypt(nil, plain)
+	if err != nil || !bytes.Equal(got, plain) {
+		t.Fatalf("Expected plain data to pass through without keys, got %q (%v)", got, err)
+	}
+	if _, _, err := ReadHeader(bytes.NewReader(plain)); err != ErrNotEncrypted {
+		t.Errorf("Expected ErrNotEncrypted, got %v", err)
+	}
+
+	// Once keys are configured, plain files are only read when allowed
+	keys := testKeys(t, "k1")
+	if _, err := decrypt(keys, plain); !errors.Is(err, ErrNotEncrypted) {
+		t.Errorf("Expected a plain file to be rejected with keys, got %v", err)
+	}
+	if _, _, err := OpenAt(context.Background(), bytes.NewReader(plain), int64(len(plain)), keys, false); !errors.Is(err, ErrNotEncrypted) {
+		t.Errorf("Expected a plain file to be rejected at random access with keys, got %v", err)
+	}
+	r, err := Open(context.Background(), bytes.NewReader(plain), keys, true)
+	if err != nil {
+		t.Fatalf("Expected plaintext reads to be allowed: %v", err)
+	}
+	if got, _ := io.ReadAll(r); !bytes.Equal(got, plain) {
+		t.Errorf("Expected the plain file back, got %q", got)
+	}
+	if _, n, err := OpenAt(context.Background(), bytes.NewReader(plain), int64(len(plain)), keys, true); err != nil || n != int64(len(plain)) {
+		t.Errorf("Expected plaintext random access to be allowed, got size %d (%v)", n, err)
+	}
+
+	t.Log("✓ Plain files read without keys, and with keys only when allowed")
+}
+
+func TestKeysFromConfig(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 16))
+	keys, err := KeysFromConfig(config.EncryptionConfig{KeyID: "a", Keys: map[string]string{"a": key, "b": key}})
+	if err != nil {
+		t.Fatalf("Failed to load keys: %v", err)
+	}
+	if id, _, _ := keys.EncryptionKey(context.Background()); id != "a" || len(keys.KeyIDs()) != 2 {
+		t.Errorf("Unexpected keys %v with current %q", keys.KeyIDs(), id)
+	}
+
+	bad := []config.EncryptionConfig{
+		{KeyID: "a", Keys: map[string]string{"a": "not base64!"}},
+		{KeyID: "a", Keys: map[string]string{"a": base64.StdEncoding.EncodeToString([]byte("short"))}},
+		{KeyID: "missing", Keys: map[string]string{"a": key}},
+	}
+	for _, cfg := range bad {
+		if _, err := KeysFromConfig(cfg); err == nil {
+			t.Errorf("Expected %+v to fail", cfg)
+		}
+	}
+
+	t.Log("✓ Keys load from configuration")
+}
@@ -0,0 +1,114 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go-transport-prac/internal/config"
+)
+
+// KeyProvider supplies AES keys by ID, the way a KMS does. Files record the
+// ID of the key they were written with, so keys can be rotated while older
+// files stay readable
+type KeyProvider interface {
+	// EncryptionKey returns the ID and key new files are written with
+	EncryptionKey(ctx context.Context) (string, []byte, error)
+	// DecryptionKey returns the key with the given ID
+	DecryptionKey(ctx context.Context, keyID string) ([]byte, error)
+}
+
+// StaticKeys is a KeyProvider holding its keys in memory
+type StaticKeys struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string][]byte
+}
+
+// NewStaticKeys returns a provider writing with the key current and reading
+// with any of keys. Keys must be 16, 24 or 32 bytes, for AES-128, AES-192 or
+// AES-256
+func NewStaticKeys(current string, keys map[string][]byte) (*StaticKeys, error) {
+	s := &StaticKeys{keys: make(map[string][]byte, len(keys))}
+	for id, key := range keys {
+		if err := s.Add(id, key); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.Rotate(current); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// KeysFromConfig returns the keys of cfg, which are base64 encoded
+func KeysFromConfig(cfg config.EncryptionConfig) (*StaticKeys, error) {
+	keys := make(map[string][]byte, len(cfg.Keys))
+	for id, encoded := range cfg.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode key %q: %w", id, err)
+		}
+		keys[id] = key
+	}
+	return NewStaticKeys(cfg.KeyID, keys)
+}
+
+// Add adds a key readers can decrypt with
+func (s *StaticKeys) Add(keyID string, key []byte) error {
+	if keyID == "" || len(keyID) > maxKeyIDLen {
+		return fmt.Errorf("key ID must be 1 to %d bytes", maxKeyIDLen)
+	}
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return fmt.Errorf("key %q must be 16, 24 or 32 bytes, got %d", keyID, len(key))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[keyID] = append([]byte(nil), key...)
+	return nil
+}
+
+// Rotate writes new files with the key keyID, which must have been added
+func (s *StaticKeys) Rotate(keyID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.keys[keyID]; !ok {
+		return fmt.Errorf("unknown encryption key %q", keyID)
+	}
+	s.current = keyID
+	return nil
+}
+
+// KeyIDs returns the IDs of every key, sorted
+func (s *StaticKeys) KeyIDs() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids := make([]string, 0, len(s.keys))
+	for id := range s.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// EncryptionKey implements KeyProvider
+func (s *StaticKeys) EncryptionKey(context.Context) (string, []byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current, s.keys[s.current], nil
+}
+
+// DecryptionKey implements KeyProvider
+func (s *StaticKeys) DecryptionKey(_ context.Context, keyID string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown encryption key %q", keyID)
+	}
+	return key, nil
+}
@@ -0,0 +1,118 @@
+package faker
+
+// location is a city with the formats of its postal codes and phone numbers.
+// In patterns, each # is replaced by a random digit
+type location struct {
+	City, State, Country string
+	Postal, Phone        string
+}
+
+var locations = []location{
+	{"San Francisco", "CA", "USA", "941##", "+1-415-###-####"},
+	{"Seattle", "WA", "USA", "981##", "+1-206-###-####"},
+	{"Austin", "TX", "USA", "787##", "+1-512-###-####"},
+	{"New York", "NY", "USA", "100##", "+1-212-###-####"},
+	{"Chicago", "IL", "USA", "606##", "+1-312-###-####"},
+	{"Boston", "MA", "USA", "021##", "+1-617-###-####"},
+	{"Denver", "CO", "USA", "802##", "+1-303-###-####"},
+	{"Toronto", "ON", "Canada", "M5V 1A#", "+1-416-###-####"},
+	{"London", "LDN", "UK", "EC1A #BB", "+44-20-####-####"},
+	{"Manchester", "MAN", "UK", "M1 #AE", "+44-161-###-####"},
+	{"Berlin", "BE", "Germany", "101##", "+49-30-#######"},
+	{"Munich", "BY", "Germany", "803##", "+49-89-#######"},
+	{"Paris", "IDF", "France", "750##", "+33-1-##-##-##-##"},
+	{"Amsterdam", "NH", "Netherlands", "10## AB", "+31-20-###-####"},
+	{"Taipei", "TPE", "Taiwan", "1##", "+886-2-####-####"},
+	{"Tokyo", "13", "Japan", "1##-####", "+81-3-####-####"},
+	{"Sydney", "NSW", "Australia", "20##", "+61-2-####-####"},
+	{"Singapore", "SG", "Singapore", "0#####", "+65-####-####"},
+}
+
+var firstNames = []string{
+	"Ada", "Alan", "Grace", "Linus", "Margaret", "Dennis", "Barbara", "Ken",
+	"Frances", "Edsger", "Radia", "Tim", "Hedy", "John", "Katherine", "Donald",
+	"Sophie", "Guido", "Anita", "Bjarne", "Mei", "Hiro", "Lena", "Mateo",
+	"Priya", "Omar", "Chloe", "Noah", "Yuki", "Elena", "Kai", "Zara",
+}
+
+var lastNames = []string{
+	"Lovelace", "Turing", "Hopper", "Torvalds", "Hamilton", "Ritchie", "Liskov",
+	"Thompson", "Allen", "Dijkstra", "Perlman", "Berners-Lee", "Lamarr", "Backus",
+	"Johnson", "Knuth", "Wilson", "van Rossum", "Borg", "Stroustrup", "Chen",
+	"Tanaka", "Schmidt", "Garcia", "Patel", "Haddad", "Martin", "Nguyen",
+	"Sato", "Rossi", "Lin", "Okafor",
+}
+
+var streetNames = []string{
+	"Main St", "Oak Ave", "Maple Dr", "Market St", "Mission St", "Elm St",
+	"Park Ave", "High St", "Station Rd", "Church Ln", "Lake View Rd",
+	"Cedar Ct", "Sunset Blvd", "River Rd", "Hill St", "King St",
+}
+
+var emailDomains = []string{"example.com", "example.org", "example.net", "mail.test"}
+
+var interests = []string{
+	"technology", "sports", "music", "travel", "cooking", "photography",
+	"reading", "gaming", "hiking", "art", "movies", "fitness", "gardening",
+	"cycling", "chess", "astronomy",
+}
+
+// productLine groups product nouns with the categories they are listed under
+type productLine struct {
+	Categories []string
+	Nouns      []string
+}
+
+var productLines = []productLine{
+	{[]string{"Electronics", "Audio"}, []string{"Headphones", "Speaker", "Earbuds", "Soundbar"}},
+	{[]string{"Electronics", "Computers"}, []string{"Keyboard", "Monitor", "Mouse", "Laptop Stand", "Webcam"}},
+	{[]string{"Home", "Kitchen"}, []string{"Kettle", "Blender", "Coffee Grinder", "Toaster"}},
+	{[]string{"Sports", "Outdoors"}, []string{"Backpack", "Tent", "Water Bottle", "Trail Shoes"}},
+	{[]string{"Clothing", "Accessories"}, []string{"Jacket", "Scarf", "Wallet", "Sunglasses"}},
+	{[]string{"Books", "Nonfiction"}, []string{"Field Guide", "Cookbook", "Atlas", "Handbook"}},
+}
+
+var productAdjectives = []string{
+	"Wireless", "Compact", "Ergonomic", "Portable", "Premium", "Classic",
+	"Ultralight", "Smart", "Rugged", "Eco", "Pro", "Mini",
+}
+
+var productTags = []string{
+	"new", "bestseller", "sale", "limited", "eco-friendly", "gift",
+	"clearance", "bundle", "refurbished", "exclusive",
+}
+
+var colors = []string{"black", "white", "silver", "red", "blue", "green", "graphite"}
+
+var sizes = []string{"small", "medium", "large", "xl"}
+
+var materials = []string{"aluminium", "steel", "cotton", "leather", "bamboo", "recycled plastic"}
+
+// currencies are weighted towards USD by repetition
+var currencies = []string{"USD", "USD", "USD", "USD", "EUR", "EUR", "GBP", "JPY", "TWD", "CAD"}
+
+var carriers = []string{"UPS", "FedEx", "DHL", "USPS", "Royal Mail"}
+
+var shippingMethods = []string{"standard", "express", "overnight", "pickup"}
+
+var paymentMethods = []string{"credit_card", "debit_card", "paypal", "bank_transfer", "apple_pay"}
+
+// metadataValues are the values generated for each key of a metadata map
+var metadataValues = map[string][]string{
+	"source":   {"web", "ios", "android", "import", "referral"},
+	"tier":     {"free", "silver", "gold", "platinum"},
+	"locale":   {"en-US", "en-GB", "de-DE", "fr-FR", "zh-TW", "ja-JP"},
+	"campaign": {"spring-sale", "newsletter", "launch", "organic"},
+	"plan":     {"monthly", "annual", "trial"},
+}
+
+var metadataKeys = []string{"source", "tier", "locale", "campaign", "plan"}
+
+var specificationKeys = []string{"color", "weight", "size", "material", "warranty"}
+
+var words = []string{
+	"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing",
+	"elit", "sed", "do", "eiusmod", "tempor", "incididunt", "labore",
+	"dolore", "magna", "aliqua", "enim", "minim", "veniam", "quis",
+	"nostrud", "exercitation", "ullamco", "laboris", "nisi", "aliquip",
+}
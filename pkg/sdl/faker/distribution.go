@@ -0,0 +1,73 @@
+package faker
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Distribution draws numbers for a field
+type Distribution interface {
+	Sample(r *rand.Rand) float64
+}
+
+// Uniform draws evenly from [Min, Max)
+type Uniform struct {
+	Min, Max float64
+}
+
+// Sample implements Distribution
+func (u Uniform) Sample(r *rand.Rand) float64 {
+	return u.Min + r.Float64()*(u.Max-u.Min)
+}
+
+// Normal draws from a normal distribution, clamped to [Min, Max] when Max is
+// above Min
+type Normal struct {
+	Mean, StdDev float64
+	Min, Max     float64
+}
+
+// Sample implements Distribution
+func (n Normal) Sample(r *rand.Rand) float64 {
+	return clamp(n.Mean+r.NormFloat64()*n.StdDev, n.Min, n.Max)
+}
+
+// Exponential draws from an exponential distribution with the given mean,
+// suited to waiting times and counts with a long tail
+type Exponential struct {
+	Mean float64
+}
+
+// Sample implements Distribution
+func (e Exponential) Sample(r *rand.Rand) float64 {
+	return r.ExpFloat64() * e.Mean
+}
+
+// LogNormal draws a value whose logarithm is normal around log(Median),
+// suited to prices and sizes. Sigma widens the spread
+type LogNormal struct {
+	Median, Sigma float64
+	Min, Max      float64
+}
+
+// Sample implements Distribution
+func (l LogNormal) Sample(r *rand.Rand) float64 {
+	return clamp(l.Median*math.Exp(r.NormFloat64()*l.Sigma), l.Min, l.Max)
+}
+
+// Fixed always returns Value
+type Fixed struct {
+	Value float64
+}
+
+// Sample implements Distribution
+func (f Fixed) Sample(*rand.Rand) float64 {
+	return f.Value
+}
+
+func clamp(v, min, max float64) float64 {
+	if max <= min {
+		return v
+	}
+	return math.Max(min, math.Min(max, v))
+}
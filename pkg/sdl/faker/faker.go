@@ -0,0 +1,497 @@
+// Package faker generates realistic randomized records for benchmarks, load
+// tests and fixtures. Values are chosen from field names (an "email" field
+// gets an email address, "amountCents" a price), from the symbols of Avro
+// enums, or from a `fake` struct tag, and every record is built around one
+// persona so its name, email, city and phone number agree. A generator is
+// deterministic: the same seed yields the same records.
+package faker
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Epoch is the default end of the window generated timestamps fall in
+var Epoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Generator produces randomized values. It is not safe for concurrent use;
+// give each goroutine its own generator with its own seed
+type Generator struct {
+	rng      *rand.Rand
+	nullRate float64
+	minItems int
+	maxItems int
+	start    time.Time
+	end      time.Time
+	// fields holds distributions by lower-cased field name
+	fields map[string]Distribution
+	// ids numbers the records of each owner type from 1
+	ids     map[string]int64
+	records int64
+	persona *persona
+}
+
+// New returns a generator seeded with seed. Optional values are left null one
+// time in ten, lists and maps hold 1 to 4 items, and timestamps fall in the
+// year before Epoch
+func New(seed int64) *Generator {
+	return &Generator{
+		rng:      rand.New(rand.NewSource(seed)),
+		nullRate: 0.1,
+		minItems: 1,
+		maxItems: 4,
+		start:    Epoch.AddDate(-1, 0, 0),
+		end:      Epoch,
+		fields:   make(map[string]Distribution),
+		ids:      make(map[string]int64),
+	}
+}
+
+// WithNullRate sets the probability, from 0 to 1, that an optional value is
+// left null
+func (g *Generator) WithNullRate(rate float64) *Generator {
+	g.nullRate = clamp(rate, 0, 1)
+	return g
+}
+
+// WithItems sets the bounds of the number of items in generated lists and maps
+func (g *Generator) WithItems(min, max int) *Generator {
+	g.minItems, g.maxItems = max0(min), max0(max)
+	if g.maxItems < g.minItems {
+		g.maxItems = g.minItems
+	}
+	return g
+}
+
+// WithTimeRange sets the window generated timestamps fall in
+func (g *Generator) WithTimeRange(start, end time.Time) *Generator {
+	if end.Before(start) {
+		start, end = end, start
+	}
+	g.start, g.end = start, end
+	return g
+}
+
+// WithDistribution draws the numbers of every field named field, compared
+// case-insensitively, from d
+func (g *Generator) WithDistribution(field string, d Distribution) *Generator {
+	g.fields[normalize(field)] = d
+	return g
+}
+
+// Rand returns the generator's source of randomness, for values the
+// generator has no rule for
+func (g *Generator) Rand() *rand.Rand {
+	return g.rng
+}
+
+func max0(n int) int {
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// normalize lower-cases a field name and drops separators, so "postal_code"
+// and "postalCode" match
+func normalize(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(name))
+}
+
+// persona holds the values a record's fields must agree on
+type persona struct {
+	seq         int64
+	first, last string
+	location    location
+	domain      string
+	line        productLine
+	adjective   string
+	noun        string
+	created     time.Time
+	cents       int64
+	inventory   [5]int64
+	inventoryOK bool
+}
+
+// begin starts a new top-level record
+func (g *Generator) begin() {
+	g.records++
+	line := productLines[g.rng.Intn(len(productLines))]
+	g.persona = &persona{
+		seq:       g.records,
+		first:     pick(g.rng, firstNames),
+		last:      pick(g.rng, lastNames),
+		location:  pick(g.rng, locations),
+		domain:    pick(g.rng, emailDomains),
+		line:      line,
+		adjective: pick(g.rng, productAdjectives),
+		noun:      pick(g.rng, line.Nouns),
+		created:   g.timeBetween(g.start, g.end),
+	}
+}
+
+func pick[T any](r *rand.Rand, values []T) T {
+	return values[r.Intn(len(values))]
+}
+
+// pickDistinct returns n different values, or all of them if there are fewer
+func pickDistinct(r *rand.Rand, values []string, n int) []string {
+	if n > len(values) {
+		n = len(values)
+	}
+	picked := make([]string, n)
+	for i, j := range r.Perm(len(values))[:n] {
+		picked[i] = values[j]
+	}
+	return picked
+}
+
+// digits replaces every # in pattern with a random digit
+func (g *Generator) digits(pattern string) string {
+	b := []byte(pattern)
+	for i, c := range b {
+		if c == '#' {
+			b[i] = byte('0' + g.rng.Intn(10))
+		}
+	}
+	return string(b)
+}
+
+func (g *Generator) items() int {
+	return g.minItems + g.rng.Intn(g.maxItems-g.minItems+1)
+}
+
+func (g *Generator) null() bool {
+	return g.rng.Float64() < g.nullRate
+}
+
+func (g *Generator) timeBetween(start, end time.Time) time.Time {
+	span := end.Sub(start)
+	if span <= 0 {
+		return start
+	}
+	return start.Add(time.Duration(g.rng.Int63n(int64(span)))).Truncate(time.Millisecond)
+}
+
+// kind names the rule a string field is generated by
+type kind string
+
+const (
+	kindEmail          kind = "email"
+	kindFirstName      kind = "firstname"
+	kindLastName       kind = "lastname"
+	kindName           kind = "name"
+	kindProductName    kind = "productname"
+	kindPhone          kind = "phone"
+	kindStreet         kind = "street"
+	kindCity           kind = "city"
+	kindState          kind = "state"
+	kindPostalCode     kind = "postalcode"
+	kindCountry        kind = "country"
+	kindCurrency       kind = "currency"
+	kindSKU            kind = "sku"
+	kindDescription    kind = "description"
+	kindUUID           kind = "uuid"
+	kindURL            kind = "url"
+	kindInterest       kind = "interest"
+	kindTag            kind = "tag"
+	kindCategory       kind = "category"
+	kindColor          kind = "color"
+	kindCarrier        kind = "carrier"
+	kindShippingMethod kind = "shippingmethod"
+	kindPaymentMethod  kind = "paymentmethod"
+	kindOrderNumber    kind = "ordernumber"
+	kindTracking       kind = "trackingnumber"
+	kindTransaction    kind = "transactionid"
+	kindWord           kind = "word"
+)
+
+// kindOf guesses the rule for field name of a record of type owner
+func kindOf(owner, name string) kind {
+	n, o := normalize(name), normalize(owner)
+	productish := strings.Contains(o, "product") || strings.Contains(o, "item")
+	switch {
+	case strings.HasSuffix(n, "email"):
+		return kindEmail
+	case n == "firstname" || n == "givenname":
+		return kindFirstName
+	case n == "lastname" || n == "surname" || n == "familyname":
+		return kindLastName
+	case n == "productname" || (n == "name" || n == "title") && productish:
+		return kindProductName
+	case n == "name" || n == "fullname" || n == "displayname" || n == "customername":
+		return kindName
+	case strings.Contains(n, "phone"):
+		return kindPhone
+	case n == "street" || n == "address1" || n == "addressline1":
+		return kindStreet
+	case n == "city" || n == "town":
+		return kindCity
+	case n == "state" || n == "region" || n == "province":
+		return kindState
+	case n == "postalcode" || n == "postcode" || n == "zip" || n == "zipcode":
+		return kindPostalCode
+	case n == "country":
+		return kindCountry
+	case n == "currency":
+		return kindCurrency
+	case strings.HasSuffix(n, "sku"):
+		return kindSKU
+	case n == "description" || n == "summary" || n == "bio":
+		return kindDescription
+	case strings.HasSuffix(n, "uuid") || n == "guid":
+		return kindUUID
+	case strings.HasSuffix(n, "url") || n == "website":
+		return kindURL
+	case strings.HasPrefix(n, "interest") || n == "hobbies":
+		return kindInterest
+	case strings.HasPrefix(n, "tag") || n == "labels":
+		return kindTag
+	case strings.HasPrefix(n, "categor"):
+		return kindCategory
+	case n == "color" || n == "colour":
+		return kindColor
+	case n == "carrier":
+		return kindCarrier
+	case n == "method" && strings.Contains(o, "payment"):
+		return kindPaymentMethod
+	case n == "method":
+		return kindShippingMethod
+	case n == "ordernumber":
+		return kindOrderNumber
+	case n == "trackingnumber":
+		return kindTracking
+	case n == "transactionid":
+		return kindTransaction
+	}
+	return kindWord
+}
+
+// text generates a string of kind k from the current persona
+func (g *Generator) text(k kind) string {
+	p := g.persona
+	switch k {
+	case kindEmail:
+		local := strings.ReplaceAll(strings.ToLower(p.first+"."+p.last), " ", "")
+		return fmt.Sprintf("%s%d@%s", local, p.seq, p.domain)
+	case kindFirstName:
+		return p.first
+	case kindLastName:
+		return p.last
+	case kindName:
+		return p.first + " " + p.last
+	case kindProductName:
+		return p.adjective + " " + p.noun
+	case kindPhone:
+		return g.digits(p.location.Phone)
+	case kindStreet:
+		return fmt.Sprintf("%d %s", 1+g.rng.Intn(2000), pick(g.rng, streetNames))
+	case kindCity:
+		return p.location.City
+	case kindState:
+		return p.location.State
+	case kindPostalCode:
+		return g.digits(p.location.Postal)
+	case kindCountry:
+		return p.location.Country
+	case kindCurrency:
+		return pick(g.rng, currencies)
+	case kindSKU:
+		prefix := strings.ToUpper(p.line.Categories[0][:3])
+		return fmt.Sprintf("%s-%06d", prefix, p.seq)
+	case kindDescription:
+		return fmt.Sprintf("%s %s for %s, %s", p.adjective, strings.ToLower(p.noun),
+			pick(g.rng, interests), g.sentence(4))
+	case kindUUID:
+		return g.uuid()
+	case kindURL:
+		return fmt.Sprintf("https://%s/%s/%d", p.domain, strings.ToLower(strings.ReplaceAll(p.noun, " ", "-")), p.seq)
+	case kindInterest:
+		return pick(g.rng, interests)
+	case kindTag:
+		return pick(g.rng, productTags)
+	case kindCategory:
+		return pick(g.rng, p.line.Categories)
+	case kindColor:
+		return pick(g.rng, colors)
+	case kindCarrier:
+		return pick(g.rng, carriers)
+	case kindShippingMethod:
+		return pick(g.rng, shippingMethods)
+	case kindPaymentMethod:
+		return pick(g.rng, paymentMethods)
+	case kindOrderNumber:
+		return fmt.Sprintf("ORD-%d-%06d", p.created.Year(), p.seq)
+	case kindTracking:
+		return g.digits("1Z###############")
+	case kindTransaction:
+		return fmt.Sprintf("txn_%016x", g.rng.Uint64())
+	}
+	return g.sentence(1 + g.rng.Intn(2))
+}
+
+// texts generates the items of a list field; lists of vocabulary such as
+// interests or tags hold no duplicates
+func (g *Generator) texts(k kind, n int) []string {
+	switch k {
+	case kindInterest:
+		return pickDistinct(g.rng, interests, n)
+	case kindTag:
+		return pickDistinct(g.rng, productTags, n)
+	case kindCategory:
+		return pickDistinct(g.rng, g.persona.line.Categories, n)
+	}
+	values := make([]string, n)
+	for i := range values {
+		values[i] = g.text(k)
+	}
+	return values
+}
+
+// entries generates a string map; metadata and specifications get keys and
+// values that belong together
+func (g *Generator) entries(name string, n int) map[string]string {
+	values := make(map[string]string, n)
+	switch normalize(name) {
+	case "metadata", "attributes", "labels":
+		for _, key := range pickDistinct(g.rng, metadataKeys, n) {
+			values[key] = pick(g.rng, metadataValues[key])
+		}
+	case "specifications", "specs", "properties":
+		for _, key := range pickDistinct(g.rng, specificationKeys, n) {
+			values[key] = g.specification(key)
+		}
+	default:
+		for i := 0; i < n; i++ {
+			values[g.sentence(1)] = g.sentence(1)
+		}
+	}
+	return values
+}
+
+func (g *Generator) specification(key string) string {
+	switch key {
+	case "color":
+		return pick(g.rng, colors)
+	case "weight":
+		return fmt.Sprintf("%.1fkg", LogNormal{Median: 1.2, Sigma: 0.8, Min: 0.1, Max: 40}.Sample(g.rng))
+	case "size":
+		return pick(g.rng, sizes)
+	case "material":
+		return pick(g.rng, materials)
+	default:
+		return fmt.Sprintf("%d years", 1+g.rng.Intn(3))
+	}
+}
+
+func (g *Generator) sentence(n int) string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = pick(g.rng, words)
+	}
+	return strings.Join(parts, " ")
+}
+
+// uuid returns a random version 4 UUID drawn from the generator's seed
+func (g *Generator) uuid() string {
+	var b [16]byte
+	g.rng.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// Inventory fields, kept consistent within a record
+const (
+	invQuantity = iota
+	invReserved
+	invAvailable
+	invReorderLevel
+	invMaxStock
+)
+
+var inventoryFields = map[string]int{
+	"quantity":     invQuantity,
+	"reserved":     invReserved,
+	"available":    invAvailable,
+	"reorderlevel": invReorderLevel,
+	"maxstock":     invMaxStock,
+}
+
+func isInventoryField(name string) bool {
+	_, ok := inventoryFields[name]
+	return ok
+}
+
+func (g *Generator) inventory(field int) int64 {
+	p := g.persona
+	if !p.inventoryOK {
+		quantity := int64(Exponential{Mean: 200}.Sample(g.rng))
+		reserved := int64(0)
+		if quantity > 0 {
+			reserved = g.rng.Int63n(quantity/5 + 1)
+		}
+		reorder := quantity/10 + 5
+		p.inventory = [5]int64{quantity, reserved, quantity - reserved, reorder, quantity + reorder*10}
+		p.inventoryOK = true
+	}
+	return p.inventory[field]
+}
+
+// number draws a number for field name of a record of type owner. Whole
+// numbers named "id" count the records of their owner from 1
+func (g *Generator) number(owner, name string) float64 {
+	n, o := normalize(name), normalize(owner)
+	if d, ok := g.fields[n]; ok {
+		return d.Sample(g.rng)
+	}
+	switch {
+	case n == "id":
+		g.ids[o]++
+		return float64(g.ids[o])
+	case strings.HasSuffix(n, "id"):
+		return float64(1 + g.rng.Intn(10000))
+	case strings.Contains(n, "cents") || n == "amount" || n == "price" || strings.HasSuffix(n, "total"):
+		g.persona.cents = int64(math.Round(LogNormal{Median: 2500, Sigma: 0.9, Min: 99, Max: 500000}.Sample(g.rng)))
+		return float64(g.persona.cents)
+	case n == "quantity" && !strings.Contains(o, "inventory"):
+		// Items ordered, mostly one or two
+		return float64(1 + int(Exponential{Mean: 1}.Sample(g.rng)))
+	case isInventoryField(n):
+		return float64(g.inventory(inventoryFields[n]))
+	case strings.Contains(n, "discount") || strings.Contains(n, "percent"):
+		return float64(1+g.rng.Intn(10)) * 0.05
+	case n == "age":
+		return math.Round(Normal{Mean: 38, StdDev: 12, Min: 18, Max: 90}.Sample(g.rng))
+	case n == "rating" || n == "score":
+		return math.Round(Uniform{Min: 1, Max: 5}.Sample(g.rng)*10) / 10
+	case strings.HasSuffix(n, "count") || strings.HasPrefix(n, "total"):
+		return math.Round(Exponential{Mean: 20}.Sample(g.rng))
+	}
+	return math.Round(Uniform{Min: 0, Max: 1000}.Sample(g.rng))
+}
+
+// timestamp generates a time for field name: a record is updated after it
+// is created, and dates are whole UTC days
+func (g *Generator) timestamp(name string) time.Time {
+	p := g.persona
+	n := normalize(name)
+	var t time.Time
+	switch {
+	case n == "createdat" || n == "created":
+		t = p.created
+	case strings.Contains(n, "birth"):
+		age := Normal{Mean: 38, StdDev: 12, Min: 18, Max: 90}.Sample(g.rng)
+		t = g.end.Add(-time.Duration(age * 365.25 * 24 * float64(time.Hour)))
+	default:
+		// Updates, shipments and other events follow creation
+		t = g.timeBetween(p.created, g.end)
+	}
+	if strings.HasSuffix(n, "date") || strings.HasPrefix(n, "date") || strings.Contains(n, "birth") {
+		t = t.UTC().Truncate(24 * time.Hour)
+	}
+	return t
+}
@@ -0,0 +1,203 @@
+package faker
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+func newManager(t *testing.T) *avro.Manager {
+	t.Helper()
+	m, err := avro.NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	return m
+}
+
+func TestRecordsAreDeterministic(t *testing.T) {
+	m := newManager(t)
+
+	first, err := Records[avro.User](New(42), m.GetUserSchema(), 20)
+	if err != nil {
+		t.Fatalf("Failed to generate users: %v", err)
+	}
+	second, err := Records[avro.User](New(42), m.GetUserSchema(), 20)
+	if err != nil {
+		t.Fatalf("Failed to generate users: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Error("Expected the same seed to generate the same users")
+	}
+
+	other, err := Records[avro.User](New(43), m.GetUserSchema(), 20)
+	if err != nil {
+		t.Fatalf("Failed to generate users: %v", err)
+	}
+	if reflect.DeepEqual(first, other) {
+		t.Error("Expected different seeds to generate different users")
+	}
+
+	t.Log("✓ Generated records are deterministic per seed")
+}
+
+func TestRecordsFollowUserSchema(t *testing.T) {
+	m := newManager(t)
+	users, err := Records[avro.User](New(1), m.GetUserSchema(), 200)
+	if err != nil {
+		t.Fatalf("Failed to generate users: %v", err)
+	}
+
+	names := make(map[string]bool)
+	for i, user := range users {
+		if user.ID != int64(i+1) {
+			t.Errorf("Expected user %d to have ID %d, got %d", i, i+1, user.ID)
+		}
+		switch user.Status {
+		case avro.UserStatusActive, avro.UserStatusInactive, avro.UserStatusSuspended, avro.UserStatusDeleted:
+		default:
+			t.Errorf("Unexpected status %q", user.Status)
+		}
+		if user.UpdatedAt.Before(user.CreatedAt) {
+			t.Errorf("User %d updated at %v before being created at %v", user.ID, user.UpdatedAt, user.CreatedAt)
+		}
+		if user.Profile != nil {
+			first := strings.ToLower(user.Profile.FirstName)
+			if !strings.HasPrefix(user.Email, first+".") {
+				t.Errorf("Expected email %q to start with first name %q", user.Email, first)
+			}
+			if user.Name != user.Profile.FirstName+" "+user.Profile.LastName {
+				t.Errorf("Expected name %q to match the profile", user.Name)
+			}
+		}
+		names[user.Name] = true
+
+		if _, err := m.SerializeUserBinary(user); err != nil {
+			t.Fatalf("Failed to serialize generated user: %v", err)
+		}
+	}
+	if len(names) < 50 {
+		t.Errorf("Expected varied names, got %d distinct in %d users", len(names), len(users))
+	}
+
+	t.Logf("✓ %d generated users follow the schema with %d distinct names", len(users), len(names))
+}
+
+func TestRecordsFollowProductSchema(t *testing.T) {
+	m := newManager(t)
+	products, err := Records[avro.Product](New(7), m.GetProductSchema(), 100)
+	if err != nil {
+		t.Fatalf("Failed to generate products: %v", err)
+	}
+
+	for _, product := range products {
+		if product.Price.AmountCents <= 0 {
+			t.Errorf("Expected a positive price, got %d", product.Price.AmountCents)
+		}
+		if product.Price.Amount != nil && product.Price.Amount.Cmp(avro.DecimalFromCents(product.Price.AmountCents)) != 0 {
+			t.Errorf("Expected amount %v to match %d cents", product.Price.Amount, product.Price.AmountCents)
+		}
+		inv := product.Inventory
+		if inv.Available != inv.Quantity-inv.Reserved {
+			t.Errorf("Expected available %d = quantity %d - reserved %d", inv.Available, inv.Quantity, inv.Reserved)
+		}
+
+		data, err := m.SerializeProductBinary(product)
+		if err != nil {
+			t.Fatalf("Failed to serialize generated product: %v", err)
+		}
+		if _, err := m.DeserializeProductBinary(data); err != nil {
+			t.Fatalf("Failed to deserialize generated product: %v", err)
+		}
+	}
+
+	t.Logf("✓ %d generated products follow the schema", len(products))
+}
+
+type loadTestEvent struct {
+	ID       int64             `json:"id"`
+	Kind     string            `fake:"oneof=view|click|purchase"`
+	Contact  string            `json:"contact_email" fake:"email"`
+	City     string            `json:"city"`
+	Latency  float64           `json:"latencyMs"`
+	Tags     []string          `json:"tags"`
+	Metadata map[string]string `json:"metadata"`
+	Internal string            `fake:"-"`
+	Retries  *int
+	ignored  string
+}
+
+func TestMakeUsesTagsAndDistributions(t *testing.T) {
+	g := New(3).WithDistribution("latencyMs", Uniform{Min: 5, Max: 50}).WithNullRate(0)
+	events, err := Make[loadTestEvent](g, 100)
+	if err != nil {
+		t.Fatalf("Failed to make events: %v", err)
+	}
+
+	for i, e := range events {
+		if e.ID != int64(i+1) {
+			t.Errorf("Expected ID %d, got %d", i+1, e.ID)
+		}
+		if e.Kind != "view" && e.Kind != "click" && e.Kind != "purchase" {
+			t.Errorf("Expected a oneof kind, got %q", e.Kind)
+		}
+		if !strings.Contains(e.Contact, "@") {
+			t.Errorf("Expected an email, got %q", e.Contact)
+		}
+		if e.City == "" || e.Internal != "" || e.ignored != "" {
+			t.Errorf("Unexpected fields %+v", e)
+		}
+		if e.Latency < 5 || e.Latency >= 50 {
+			t.Errorf("Expected latency in [5, 50), got %v", e.Latency)
+		}
+		if len(e.Tags) == 0 || len(e.Metadata) == 0 || e.Retries == nil {
+			t.Errorf("Expected lists, maps and pointers to be set, got %+v", e)
+		}
+	}
+
+	type badTag struct {
+		Name string `fake:"nickname"`
+	}
+	if _, err := Make[badTag](New(1), 1); err == nil {
+		t.Error("Expected an unknown fake tag to fail")
+	}
+
+	t.Log("✓ Make honours fake tags and configured distributions")
+}
+
+func TestDistributions(t *testing.T) {
+	g := New(9)
+	tests := []struct {
+		name string
+		d    Distribution
+		mean float64
+	}{
+		{"uniform", Uniform{Min: 10, Max: 20}, 15},
+		{"normal", Normal{Mean: 100, StdDev: 5}, 100},
+		{"exponential", Exponential{Mean: 4}, 4},
+		{"fixed", Fixed{Value: 3}, 3},
+	}
+
+	for _, tt := range tests {
+		sum := 0.0
+		const n = 20000
+		for i := 0; i < n; i++ {
+			sum += tt.d.Sample(g.Rand())
+		}
+		if mean := sum / n; math.Abs(mean-tt.mean) > tt.mean*0.05 {
+			t.Errorf("%s: expected a mean near %v, got %v", tt.name, tt.mean, mean)
+		}
+	}
+
+	clamped := LogNormal{Median: 10, Sigma: 3, Min: 1, Max: 100}
+	for i := 0; i < 1000; i++ {
+		if v := clamped.Sample(g.Rand()); v < 1 || v > 100 {
+			t.Fatalf("Expected a clamped sample in [1, 100], got %v", v)
+		}
+	}
+
+	t.Log("✓ Distributions sample around their means")
+}
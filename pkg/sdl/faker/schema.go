@@ -0,0 +1,207 @@
+package faker
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"time"
+
+	hamba "github.com/hamba/avro/v2"
+
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+// Value generates one record for schema in the generic form hamba/avro
+// encodes: records are maps, unions are nil or a map from the branch name to
+// the value, and logical types use time.Time, time.Duration and *big.Rat
+func (g *Generator) Value(schema hamba.Schema) (any, error) {
+	g.begin()
+	return g.value(schema, "", "")
+}
+
+// Records generates n records for schema and maps them into T through the
+// SDL struct mapper, so every record is known to encode against schema
+func Records[T any](g *Generator, schema hamba.Schema, n int) ([]T, error) {
+	records := make([]T, n)
+	for i := range records {
+		value, err := g.Value(schema)
+		if err != nil {
+			return nil, err
+		}
+		data, err := hamba.Marshal(schema, value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode generated record: %w", err)
+		}
+		var decoded any
+		if err := hamba.Unmarshal(schema, data, &decoded); err != nil {
+			return nil, fmt.Errorf("failed to decode generated record: %w", err)
+		}
+		if err := avro.AvroToStruct(schema, decoded, &records[i]); err != nil {
+			return nil, fmt.Errorf("failed to map generated record: %w", err)
+		}
+	}
+	return records, nil
+}
+
+func (g *Generator) value(schema hamba.Schema, owner, name string) (any, error) {
+	if ref, ok := schema.(*hamba.RefSchema); ok {
+		schema = ref.Schema()
+	}
+
+	switch s := schema.(type) {
+	case *hamba.RecordSchema:
+		record := make(map[string]any, len(s.Fields()))
+		for _, field := range s.Fields() {
+			v, err := g.value(field.Type(), s.Name(), field.Name())
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", s.Name(), field.Name(), err)
+			}
+			record[field.Name()] = v
+		}
+		return record, nil
+
+	case *hamba.EnumSchema:
+		return pick(g.rng, s.Symbols()), nil
+
+	case *hamba.UnionSchema:
+		return g.union(s, owner, name)
+
+	case *hamba.ArraySchema:
+		n := g.items()
+		if s.Items().Type() == hamba.String {
+			values := g.texts(kindOf(owner, name), n)
+			items := make([]any, len(values))
+			for i, v := range values {
+				items[i] = v
+			}
+			return items, nil
+		}
+		items := make([]any, n)
+		for i := range items {
+			v, err := g.value(s.Items(), owner, name)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = v
+		}
+		return items, nil
+
+	case *hamba.MapSchema:
+		if s.Values().Type() == hamba.String {
+			entries := make(map[string]any)
+			for k, v := range g.entries(name, g.items()) {
+				entries[k] = v
+			}
+			return entries, nil
+		}
+		entries := make(map[string]any)
+		for i, n := 0, g.items(); i < n; i++ {
+			v, err := g.value(s.Values(), owner, name)
+			if err != nil {
+				return nil, err
+			}
+			entries[g.sentence(1)] = v
+		}
+		return entries, nil
+
+	case *hamba.FixedSchema:
+		if s.Logical() != nil && s.Logical().Type() == hamba.Decimal {
+			return g.decimal(owner, name), nil
+		}
+		b := reflect.New(reflect.ArrayOf(s.Size(), reflect.TypeOf(byte(0)))).Elem()
+		for i := 0; i < s.Size(); i++ {
+			b.Index(i).SetUint(uint64(g.rng.Intn(256)))
+		}
+		return b.Interface(), nil
+
+	case *hamba.PrimitiveSchema:
+		return g.primitive(s, owner, name)
+	}
+
+	return nil, fmt.Errorf("unsupported schema type %s", schema.Type())
+}
+
+// union leaves a nullable union null at the generator's null rate and
+// otherwise picks one of its other branches
+func (g *Generator) union(s *hamba.UnionSchema, owner, name string) (any, error) {
+	var branches []hamba.Schema
+	for _, branch := range s.Types() {
+		if branch.Type() != hamba.Null {
+			branches = append(branches, branch)
+		}
+	}
+	if len(branches) == 0 || (s.Nullable() && g.null()) {
+		return nil, nil
+	}
+
+	branch := pick(g.rng, branches)
+	if ref, ok := branch.(*hamba.RefSchema); ok {
+		branch = ref.Schema()
+	}
+	v, err := g.value(branch, owner, name)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{branchName(branch): v}, nil
+}
+
+// branchName names a union branch the way hamba/avro does in generic values
+func branchName(schema hamba.Schema) string {
+	if named, ok := schema.(hamba.NamedSchema); ok {
+		return named.FullName()
+	}
+	name := string(schema.Type())
+	if logical, ok := schema.(hamba.LogicalTypeSchema); ok && logical.Logical() != nil {
+		name += "." + string(logical.Logical().Type())
+	}
+	return name
+}
+
+func (g *Generator) primitive(s *hamba.PrimitiveSchema, owner, name string) (any, error) {
+	if s.Logical() != nil {
+		switch s.Logical().Type() {
+		case hamba.TimestampMillis, hamba.TimestampMicros, hamba.LocalTimestampMillis, hamba.LocalTimestampMicros:
+			return g.timestamp(name), nil
+		case hamba.Date:
+			return g.timestamp(name).UTC().Truncate(24 * time.Hour), nil
+		case hamba.TimeMillis, hamba.TimeMicros:
+			return time.Duration(g.rng.Int63n(int64(24 * time.Hour))).Truncate(time.Millisecond), nil
+		case hamba.UUID:
+			return g.uuid(), nil
+		case hamba.Decimal:
+			return g.decimal(owner, name), nil
+		}
+	}
+
+	switch s.Type() {
+	case hamba.Null:
+		return nil, nil
+	case hamba.Boolean:
+		return g.rng.Intn(2) == 1, nil
+	case hamba.Int:
+		return int(g.number(owner, name)), nil
+	case hamba.Long:
+		return int64(g.number(owner, name)), nil
+	case hamba.Float:
+		return float32(g.number(owner, name)), nil
+	case hamba.Double:
+		return g.number(owner, name), nil
+	case hamba.String:
+		return g.text(kindOf(owner, name)), nil
+	case hamba.Bytes:
+		b := make([]byte, 16)
+		g.rng.Read(b)
+		return b, nil
+	}
+	return nil, fmt.Errorf("unsupported primitive type %s", s.Type())
+}
+
+// decimal returns an exact amount; it matches the record's cents field when
+// one was generated first, so amount and amountCents agree
+func (g *Generator) decimal(owner, name string) *big.Rat {
+	cents := g.persona.cents
+	if cents == 0 {
+		cents = int64(g.number(owner, "cents"))
+	}
+	return big.NewRat(cents, 100)
+}
@@ -0,0 +1,215 @@
+package faker
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+var knownKinds = map[kind]bool{
+	kindEmail: true, kindFirstName: true, kindLastName: true, kindName: true,
+	kindProductName: true, kindPhone: true, kindStreet: true, kindCity: true,
+	kindState: true, kindPostalCode: true, kindCountry: true, kindCurrency: true,
+	kindSKU: true, kindDescription: true, kindUUID: true, kindURL: true,
+	kindInterest: true, kindTag: true, kindCategory: true, kindColor: true,
+	kindCarrier: true, kindShippingMethod: true, kindPaymentMethod: true,
+	kindOrderNumber: true, kindTracking: true, kindTransaction: true, kindWord: true,
+}
+
+// fakeTag is a parsed `fake` struct tag: "-" skips the field, "oneof=a|b|c"
+// picks one of the values and any other value names the kind of text, such
+// as "email" or "city"
+type fakeTag struct {
+	skip  bool
+	oneOf []string
+	kind  kind
+}
+
+func parseFakeTag(tag string) (fakeTag, error) {
+	switch {
+	case tag == "":
+		return fakeTag{}, nil
+	case tag == "-":
+		return fakeTag{skip: true}, nil
+	case strings.HasPrefix(tag, "oneof="):
+		return fakeTag{oneOf: strings.Split(strings.TrimPrefix(tag, "oneof="), "|")}, nil
+	}
+	k := kind(normalize(tag))
+	if !knownKinds[k] {
+		return fakeTag{}, fmt.Errorf("unknown fake tag %q", tag)
+	}
+	return fakeTag{kind: k}, nil
+}
+
+// Fill sets every exported field of the struct v points to. Fields are
+// matched to values by their `fake` tag, or else by the name in their
+// `avro` or `json` tag or their Go name. Pointers are left nil at the
+// generator's null rate, and pointers to types with no exported fields,
+// such as *big.Rat, are always left nil
+func (g *Generator) Fill(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("fill target must be a non-nil pointer, got %T", v)
+	}
+	g.begin()
+	return g.fill(rv.Elem(), "", "", fakeTag{})
+}
+
+// Make returns n values of T filled by g
+func Make[T any](g *Generator, n int) ([]T, error) {
+	values := make([]T, n)
+	for i := range values {
+		if err := g.Fill(&values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+func (g *Generator) fill(rv reflect.Value, owner, name string, tag fakeTag) error {
+	if len(tag.oneOf) > 0 && rv.Kind() == reflect.String {
+		rv.SetString(pick(g.rng, tag.oneOf))
+		return nil
+	}
+	if rv.Type() == timeType {
+		rv.Set(reflect.ValueOf(g.timestamp(name)))
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if g.null() || !fillable(rv.Type().Elem()) {
+			return nil
+		}
+		elem := reflect.New(rv.Type().Elem())
+		if err := g.fill(elem.Elem(), owner, name, tag); err != nil {
+			return err
+		}
+		rv.Set(elem)
+
+	case reflect.Struct:
+		return g.fillStruct(rv)
+
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, 16)
+			g.rng.Read(b)
+			rv.SetBytes(b)
+			return nil
+		}
+		n := g.items()
+		if rv.Type().Elem().Kind() == reflect.String && len(tag.oneOf) == 0 {
+			// Vocabulary lists may hold fewer than n distinct values
+			values := g.texts(textKind(owner, name, tag), n)
+			slice := reflect.MakeSlice(rv.Type(), len(values), len(values))
+			for i, s := range values {
+				slice.Index(i).SetString(s)
+			}
+			rv.Set(slice)
+			return nil
+		}
+		slice := reflect.MakeSlice(rv.Type(), n, n)
+		for i := 0; i < n; i++ {
+			if err := g.fill(slice.Index(i), owner, name, tag); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+
+	case reflect.Map:
+		return g.fillMap(rv, owner, name, tag)
+
+	case reflect.String:
+		rv.SetString(g.text(textKind(owner, name, tag)))
+	case reflect.Bool:
+		rv.SetBool(g.rng.Intn(2) == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		rv.SetInt(int64(g.number(owner, name)))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		rv.SetUint(uint64(max(0, g.number(owner, name))))
+	case reflect.Float32, reflect.Float64:
+		rv.SetFloat(g.number(owner, name))
+	}
+	return nil
+}
+
+func (g *Generator) fillStruct(rv reflect.Value) error {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag, err := parseFakeTag(field.Tag.Get("fake"))
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+		if tag.skip {
+			continue
+		}
+		if err := g.fill(rv.Field(i), t.Name(), fieldName(field), tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *Generator) fillMap(rv reflect.Value, owner, name string, tag fakeTag) error {
+	t := rv.Type()
+	m := reflect.MakeMap(t)
+	if t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.String {
+		for k, v := range g.entries(name, g.items()) {
+			m.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), reflect.ValueOf(v).Convert(t.Elem()))
+		}
+		rv.Set(m)
+		return nil
+	}
+	if t.Key().Kind() != reflect.String {
+		// Keys other than strings are left to the caller
+		return nil
+	}
+	for i, n := 0, g.items(); i < n; i++ {
+		value := reflect.New(t.Elem()).Elem()
+		if err := g.fill(value, owner, name, tag); err != nil {
+			return err
+		}
+		m.SetMapIndex(reflect.ValueOf(g.sentence(1)).Convert(t.Key()), value)
+	}
+	rv.Set(m)
+	return nil
+}
+
+// textKind returns the tagged kind of a string field, or the kind its name
+// suggests
+func textKind(owner, name string, tag fakeTag) kind {
+	if tag.kind != "" {
+		return tag.kind
+	}
+	return kindOf(owner, name)
+}
+
+// fillable reports whether Fill can set anything in a value of type t
+func fillable(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == timeType {
+		return true
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldName returns the name a field is serialized under
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"avro", "json"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(key), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
@@ -0,0 +1,29 @@
+package fixtures
+
+import (
+	"time"
+
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+// normalize puts a user in the form every format round-trips to: UTC times at
+// millisecond precision (the Avro timestamp-millis resolution) and nil rather
+// than empty collections
+func normalize(u avro.User) avro.User {
+	u.CreatedAt = u.CreatedAt.UTC().Truncate(time.Millisecond)
+	u.UpdatedAt = u.UpdatedAt.UTC().Truncate(time.Millisecond)
+	if p := u.Profile; p != nil {
+		profile := *p
+		if profile.Phone != nil && *profile.Phone == "" {
+			profile.Phone = nil
+		}
+		if len(profile.Interests) == 0 {
+			profile.Interests = nil
+		}
+		if len(profile.Metadata) == 0 {
+			profile.Metadata = nil
+		}
+		u.Profile = &profile
+	}
+	return u
+}
@@ -0,0 +1,348 @@
+package fixtures
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	parquetgo "github.com/segmentio/parquet-go"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/converter"
+	"go-transport-prac/pkg/sdl/jsonschema"
+	"go-transport-prac/pkg/sdl/parquet"
+	"go-transport-prac/pkg/sdl/protobuf"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+const (
+	// DefaultDir is where fixtures are generated when no directory is given
+	DefaultDir = "fixtures"
+	// DefaultCount is the number of users in a fixture set
+	DefaultCount = 12
+	// ManifestFile lists every fixture file with its size and checksum
+	ManifestFile = "manifest.json"
+	// ManifestVersion changes whenever the fixture layout changes
+	ManifestVersion = 1
+)
+
+// Epoch is the default generation time, so fixtures are stable across runs
+var Epoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Format is the serialization format of a fixture file
+type Format string
+
+const (
+	FormatAvro     Format = "avro"
+	FormatProtobuf Format = "protobuf"
+	FormatParquet  Format = "parquet"
+	FormatJSON     Format = "json"
+)
+
+// Role tells payloads from the schemas that describe them
+type Role string
+
+const (
+	RolePayload Role = "payload"
+	RoleSchema  Role = "schema"
+)
+
+// Fixture file paths, relative to the fixture directory
+const (
+	AvroUsers          = "avro/users.avro"
+	AvroSchema         = "avro/user.avsc"
+	ProtobufUsers      = "protobuf/users.binpb"
+	ProtobufSource     = "protobuf/user.proto"
+	ProtobufDescriptor = "protobuf/user.desc"
+	ParquetUsers       = "parquet/users.parquet"
+	ParquetSchema      = "parquet/schema.txt"
+	JSONUsers          = "json/users.json"
+	JSONSchema         = "json/user.schema.json"
+)
+
+// File describes one generated file
+type File struct {
+	Path     string `json:"path"`
+	Format   Format `json:"format"`
+	Role     Role   `json:"role"`
+	Encoding string `json:"encoding"`
+	Records  int    `json:"records,omitempty"`
+	Size     int64  `json:"size"`
+	SHA256   string `json:"sha256"`
+}
+
+// Manifest is written next to the fixtures. Consumers in other languages
+// read it to find the payloads, and Verify uses it to rebuild the expected
+// records and detect modified files
+type Manifest struct {
+	Version     int       `json:"version"`
+	Entity      string    `json:"entity"`
+	Records     int       `json:"records"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	Files       []File    `json:"files"`
+}
+
+// File returns the manifest entry for a path
+func (m *Manifest) File(path string) (File, bool) {
+	for _, f := range m.Files {
+		if f.Path == path {
+			return f, true
+		}
+	}
+	return File{}, false
+}
+
+// fixedClock always returns the same instant
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+// Generator writes the canonical user dataset in every supported format
+type Generator struct {
+	dir   string
+	count int
+	clock types.Clock
+}
+
+// NewGenerator creates a generator writing to dir with DefaultCount users generated at Epoch
+func NewGenerator(dir string) *Generator {
+	if dir == "" {
+		dir = DefaultDir
+	}
+	return &Generator{
+		dir:   dir,
+		count: DefaultCount,
+		clock: fixedClock(Epoch),
+	}
+}
+
+// WithCount sets the number of users generated
+func (g *Generator) WithCount(count int) *Generator {
+	g.count = count
+	return g
+}
+
+// WithClock sets the clock the generation time is read from. The default is
+// fixed at Epoch; the time is recorded in the manifest so Verify can rebuild
+// the same records
+func (g *Generator) WithClock(clock types.Clock) *Generator {
+	g.clock = types.ClockOrSystem(clock)
+	return g
+}
+
+// Users returns the canonical dataset generated at now. Statuses cycle through
+// every enum symbol, and some users have no phone or address, so consumers
+// exercise nulls and enums rather than only the happy path
+func Users(count int, now time.Time) ([]avro.User, error) {
+	// The manager only builds records here; it never touches its directory
+	manager, err := avro.NewManager("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avro manager: %w", err)
+	}
+	users := manager.WithClock(fixedClock(now)).CreateSampleUsers(count)
+
+	statuses := []avro.UserStatus{avro.UserStatusActive, avro.UserStatusInactive, avro.UserStatusSuspended, avro.UserStatusDeleted}
+	for i := range users {
+		users[i].Status = statuses[i%len(statuses)]
+		if i%5 == 1 {
+			users[i].Name = fmt.Sprintf("Zoë Ünicode %d 測試", i+1)
+		}
+		if i%3 == 2 {
+			users[i].Profile.Phone = nil
+		}
+		if i%4 == 3 {
+			users[i].Profile.Address = nil
+		}
+		users[i] = normalize(users[i])
+	}
+	return users, nil
+}
+
+// Generate writes every fixture and the manifest, replacing existing files
+func (g *Generator) Generate() (*Manifest, error) {
+	if g.count <= 0 {
+		return nil, fmt.Errorf("fixture count must be positive, got %d", g.count)
+	}
+
+	now := g.clock.Now().UTC().Truncate(time.Millisecond)
+	users, err := Users(g.count, now)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sub := range []string{"avro", "protobuf", "parquet", "json"} {
+		if err := os.MkdirAll(filepath.Join(g.dir, sub), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create fixture directory: %w", err)
+		}
+	}
+
+	manifest := &Manifest{
+		Version:     ManifestVersion,
+		Entity:      "user",
+		Records:     len(users),
+		GeneratedAt: now,
+	}
+
+	steps := []struct {
+		file  File
+		write func(path string) error
+	}{
+		{File{Path: AvroUsers, Format: FormatAvro, Role: RolePayload, Encoding: "Avro Object Container File, null codec", Records: len(users)}, g.writeAvro(users)},
+		{File{Path: AvroSchema, Format: FormatAvro, Role: RoleSchema, Encoding: "Avro schema JSON"}, g.writeAvroSchema},
+		{File{Path: ProtobufUsers, Format: FormatProtobuf, Role: RolePayload, Encoding: "user.User messages, each prefixed by its varint length", Records: len(users)}, g.writeProtobuf(users)},
+		{File{Path: ProtobufSource, Format: FormatProtobuf, Role: RoleSchema, Encoding: "proto3 source"}, g.writeProtoSource},
+		{File{Path: ProtobufDescriptor, Format: FormatProtobuf, Role: RoleSchema, Encoding: "google.protobuf.FileDescriptorSet, binary"}, g.writeDescriptorSet},
+		{File{Path: ParquetUsers, Format: FormatParquet, Role: RolePayload, Encoding: "Parquet file, one row group", Records: len(users)}, g.writeParquet(users)},
+		{File{Path: ParquetSchema, Format: FormatParquet, Role: RoleSchema, Encoding: "Parquet message type"}, g.writeParquetSchema},
+		{File{Path: JSONUsers, Format: FormatJSON, Role: RolePayload, Encoding: "JSON array, RFC 3339 timestamps", Records: len(users)}, g.writeJSON(users)},
+		{File{Path: JSONSchema, Format: FormatJSON, Role: RoleSchema, Encoding: "JSON Schema draft 2020-12"}, g.writeJSONSchema},
+	}
+
+	for _, step := range steps {
+		path := filepath.Join(g.dir, filepath.FromSlash(step.file.Path))
+		if err := step.write(path); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", step.file.Path, err)
+		}
+		size, sum, err := checksum(path)
+		if err != nil {
+			return nil, err
+		}
+		step.file.Size, step.file.SHA256 = size, sum
+		manifest.Files = append(manifest.Files, step.file)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(g.dir, ManifestFile), append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+func (g *Generator) writeAvro(users []avro.User) func(string) error {
+	return func(path string) error {
+		manager, err := avro.NewManager(filepath.Dir(path))
+		if err != nil {
+			return fmt.Errorf("failed to create avro manager: %w", err)
+		}
+		return manager.WriteUsersToOCFFile(filepath.Base(path), users)
+	}
+}
+
+func (g *Generator) writeAvroSchema(path string) error {
+	manager, err := avro.NewManager(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("failed to create avro manager: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(manager.GetUserSchema().String()), "", "  "); err != nil {
+		return fmt.Errorf("failed to format avro schema: %w", err)
+	}
+	buf.WriteByte('\n')
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+func (g *Generator) writeProtobuf(users []avro.User) func(string) error {
+	return func(path string) error {
+		var buf bytes.Buffer
+		for _, u := range users {
+			msg, err := converter.UserToProto(u)
+			if err != nil {
+				return err
+			}
+			if _, err := protodelim.MarshalTo(&buf, msg); err != nil {
+				return fmt.Errorf("failed to marshal user %d: %w", u.ID, err)
+			}
+		}
+		return os.WriteFile(path, buf.Bytes(), 0644)
+	}
+}
+
+func (g *Generator) writeProtoSource(path string) error {
+	source, err := protobuf.ProtoSource("user.proto")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, source, 0644)
+}
+
+func (g *Generator) writeDescriptorSet(path string) error {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(protobuf.FileDescriptorSet(&user.User{}))
+	if err != nil {
+		return fmt.Errorf("failed to marshal descriptor set: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (g *Generator) writeParquet(users []avro.User) func(string) error {
+	return func(path string) error {
+		rows := make([]parquet.User, len(users))
+		for i, u := range users {
+			rows[i] = converter.UserToParquet(u)
+		}
+		return parquet.NewSimpleManager(filepath.Dir(path)).WriteUsers(filepath.Base(path), rows)
+	}
+}
+
+func (g *Generator) writeParquetSchema(path string) error {
+	return os.WriteFile(path, []byte(parquetSchema()+"\n"), 0644)
+}
+
+func (g *Generator) writeJSON(users []avro.User) func(string) error {
+	return func(path string) error {
+		data, err := json.MarshalIndent(users, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal users: %w", err)
+		}
+		return os.WriteFile(path, append(data, '\n'), 0644)
+	}
+}
+
+func (g *Generator) writeJSONSchema(path string) error {
+	data, err := jsonSchema()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// jsonSchema generates the JSON Schema of users.json from the user model
+func jsonSchema() ([]byte, error) {
+	return jsonschema.NewSchemaGenerator().
+		WithID("https://go-transport-prac/fixtures/user.schema.json").
+		WithTitle("Users").
+		WithDescription("Fixture users as serialized by encoding/json from avro.User").
+		GenerateJSON([]avro.User{})
+}
+
+// parquetSchema renders the Parquet schema of the user rows
+func parquetSchema() string {
+	return parquetgo.SchemaOf(new(parquet.User)).String()
+}
+
+// checksum returns the size and hex SHA-256 of a file
+func checksum(path string) (int64, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to hash file: %w", err)
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,173 @@
+package fixtures
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+func TestGenerateAndVerify(t *testing.T) {
+	dir := "tmp/test_fixtures"
+	defer os.RemoveAll("tmp")
+
+	manifest, err := NewGenerator(dir).Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate fixtures: %v", err)
+	}
+	if manifest.Records != DefaultCount || !manifest.GeneratedAt.Equal(Epoch) {
+		t.Errorf("Expected %d records at %v, got %d at %v", DefaultCount, Epoch, manifest.Records, manifest.GeneratedAt)
+	}
+	if len(manifest.Files) != 9 {
+		t.Errorf("Expected 9 fixture files, got %d", len(manifest.Files))
+	}
+	for _, f := range manifest.Files {
+		if f.Size == 0 || len(f.SHA256) != 64 {
+			t.Errorf("File %s has size %d and checksum %q", f.Path, f.Size, f.SHA256)
+		}
+		if f.Role == RolePayload && f.Records != DefaultCount {
+			t.Errorf("Payload %s records %d, expected %d", f.Path, f.Records, DefaultCount)
+		}
+	}
+
+	report, err := Verify(dir)
+	if err != nil {
+		t.Fatalf("Failed to verify fixtures: %v", err)
+	}
+	for _, c := range report.Checks {
+		if !c.OK() {
+			t.Errorf("Check %s failed: %s", c.Path, c.Error)
+		}
+	}
+	if len(report.Checks) != 9 {
+		t.Errorf("Expected 9 checks, got %d", len(report.Checks))
+	}
+
+	t.Log("✓ Fixtures generated and verified in every format")
+}
+
+func TestVerifyDetectsChanges(t *testing.T) {
+	dir := "tmp/test_fixtures_tampered"
+	defer os.RemoveAll("tmp")
+
+	if _, err := NewGenerator(dir).WithCount(5).Generate(); err != nil {
+		t.Fatalf("Failed to generate fixtures: %v", err)
+	}
+
+	// Flip a byte in the middle of the protobuf payload
+	path := filepath.Join(dir, filepath.FromSlash(ProtobufUsers))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read payload: %v", err)
+	}
+	data[len(data)/2] ^= 0xff
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write payload: %v", err)
+	}
+	if err := os.Remove(filepath.Join(dir, filepath.FromSlash(JSONUsers))); err != nil {
+		t.Fatalf("Failed to remove payload: %v", err)
+	}
+
+	report, err := Verify(dir)
+	if err != nil {
+		t.Fatalf("Failed to verify fixtures: %v", err)
+	}
+	failed := make(map[string]string)
+	for _, c := range report.Failures() {
+		failed[c.Path] = c.Error
+	}
+	if len(failed) != 2 {
+		t.Errorf("Expected 2 failed checks, got %v", failed)
+	}
+	if !strings.Contains(failed[ProtobufUsers], "checksum mismatch") {
+		t.Errorf("Expected checksum mismatch for %s, got %q", ProtobufUsers, failed[ProtobufUsers])
+	}
+	if _, ok := failed[JSONUsers]; !ok {
+		t.Errorf("Expected a failure for the missing %s", JSONUsers)
+	}
+	if report.OK() {
+		t.Error("Expected report to fail")
+	}
+
+	if _, err := Verify("tmp/test_fixtures_missing"); err == nil {
+		t.Error("Expected error for a directory without a manifest")
+	}
+
+	t.Log("✓ Verification detects modified and missing files")
+}
+
+func TestCompareUsersReportsDifferences(t *testing.T) {
+	expected, err := Users(4, Epoch)
+	if err != nil {
+		t.Fatalf("Failed to build users: %v", err)
+	}
+
+	// A record that decoded to a different value must not pass
+	decoded, _ := Users(4, Epoch)
+	decoded[2].Email = "someone.else@example.com"
+	if err := compareUsers(expected, decoded); err == nil || !strings.Contains(err.Error(), "id 3") {
+		t.Errorf("Expected difference at id 3, got %v", err)
+	}
+
+	// Sub-millisecond precision and time zones are not differences
+	decoded, _ = Users(4, Epoch)
+	decoded[0].CreatedAt = decoded[0].CreatedAt.In(time.FixedZone("UTC+8", 8*3600)).Add(300 * time.Microsecond)
+	if err := compareUsers(expected, decoded); err != nil {
+		t.Errorf("Expected normalized users to match: %v", err)
+	}
+
+	if err := compareUsers(expected, expected[:3]); err == nil {
+		t.Error("Expected error for a missing record")
+	}
+
+	t.Log("✓ User comparison ignores encoding-only differences")
+}
+
+func TestUsersCoverEdgeCases(t *testing.T) {
+	users, err := Users(DefaultCount, Epoch)
+	if err != nil {
+		t.Fatalf("Failed to build users: %v", err)
+	}
+
+	statuses := make(map[avro.UserStatus]bool)
+	var noPhone, noAddress, unicode int
+	for _, u := range users {
+		statuses[u.Status] = true
+		if u.Profile.Phone == nil {
+			noPhone++
+		}
+		if u.Profile.Address == nil {
+			noAddress++
+		}
+		if strings.ContainsRune(u.Name, '測') {
+			unicode++
+		}
+	}
+	if len(statuses) != 4 {
+		t.Errorf("Expected every status, got %v", statuses)
+	}
+	if noPhone == 0 || noAddress == 0 || unicode == 0 {
+		t.Errorf("Expected users without phone (%d), without address (%d) and with non-ASCII names (%d)", noPhone, noAddress, unicode)
+	}
+
+	// A generator clock changes the timestamps but not the record shape
+	clock := testutil.NewDefaultFakeClock()
+	manifest, err := NewGenerator("tmp/test_fixtures_clock").WithClock(clock).WithCount(3).Generate()
+	defer os.RemoveAll("tmp")
+	if err != nil {
+		t.Fatalf("Failed to generate fixtures: %v", err)
+	}
+	if !manifest.GeneratedAt.Equal(testutil.DefaultFakeTime) {
+		t.Errorf("Expected generation time %v, got %v", testutil.DefaultFakeTime, manifest.GeneratedAt)
+	}
+	report, err := Verify("tmp/test_fixtures_clock")
+	if err != nil || !report.OK() {
+		t.Errorf("Expected fixtures generated at a custom time to verify: %v %+v", err, report.Failures())
+	}
+
+	t.Log("✓ Canonical users cover enums, nulls and non-ASCII text")
+}
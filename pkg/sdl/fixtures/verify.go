@@ -0,0 +1,301 @@
+package fixtures
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	hamba "github.com/hamba/avro/v2"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/converter"
+	"go-transport-prac/pkg/sdl/jsonschema"
+	"go-transport-prac/pkg/sdl/parquet"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+// Check is the outcome of verifying one fixture file
+type Check struct {
+	Path    string `json:"path"`
+	Format  Format `json:"format"`
+	Role    Role   `json:"role"`
+	Records int    `json:"records,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// OK reports whether the file passed every check
+func (c Check) OK() bool {
+	return c.Error == ""
+}
+
+// Report lists the checks run against a fixture directory
+type Report struct {
+	Dir      string    `json:"dir"`
+	Manifest *Manifest `json:"manifest"`
+	Checks   []Check   `json:"checks"`
+}
+
+// OK reports whether every check passed
+func (r *Report) OK() bool {
+	return len(r.Failures()) == 0
+}
+
+// Failures returns the checks that failed
+func (r *Report) Failures() []Check {
+	var failed []Check
+	for _, c := range r.Checks {
+		if !c.OK() {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// verifier re-reads one fixture file, returning the records it decoded
+type verifier func(dir, path string) (int, error)
+
+// Verify re-reads every fixture in dir. Each file must match the size and
+// checksum in the manifest, each schema must parse, and each payload must
+// decode to the records the manifest says were generated. Problems with
+// individual files are reported as failed checks; an error is returned only
+// when the manifest itself cannot be read
+func Verify(dir string) (*Report, error) {
+	if dir == "" {
+		dir = DefaultDir
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if manifest.Version != ManifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %d, expected %d", manifest.Version, ManifestVersion)
+	}
+
+	expected, err := Users(manifest.Records, manifest.GeneratedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	verifiers := map[string]verifier{
+		AvroUsers: func(dir, path string) (int, error) {
+			manager, err := avro.NewManager(dir)
+			if err != nil {
+				return 0, fmt.Errorf("failed to create avro manager: %w", err)
+			}
+			users, err := manager.ReadUsersFromOCFFile(path)
+			if err != nil {
+				return 0, err
+			}
+			return len(users), compareUsers(expected, users)
+		},
+		AvroSchema: func(dir, path string) (int, error) {
+			_, err := hamba.ParseFiles(filepath.Join(dir, path))
+			return 0, err
+		},
+		ProtobufUsers: func(dir, path string) (int, error) {
+			users, err := readProtobufUsers(filepath.Join(dir, path))
+			if err != nil {
+				return 0, err
+			}
+			return len(users), compareUsers(expected, users)
+		},
+		ProtobufSource: func(dir, path string) (int, error) {
+			source, err := os.ReadFile(filepath.Join(dir, path))
+			if err != nil {
+				return 0, err
+			}
+			if !strings.Contains(string(source), "message User {") {
+				return 0, fmt.Errorf("proto source does not declare message User")
+			}
+			return 0, nil
+		},
+		ProtobufDescriptor: func(dir, path string) (int, error) {
+			return 0, verifyDescriptorSet(filepath.Join(dir, path))
+		},
+		ParquetUsers: func(dir, path string) (int, error) {
+			rows, err := parquet.NewSimpleManager(dir).ReadUsers(path)
+			if err != nil {
+				return 0, err
+			}
+			users := make([]avro.User, len(rows))
+			for i, row := range rows {
+				users[i] = converter.UserFromParquet(row)
+			}
+			return len(users), compareUsers(expected, users)
+		},
+		ParquetSchema: func(dir, path string) (int, error) {
+			text, err := os.ReadFile(filepath.Join(dir, path))
+			if err != nil {
+				return 0, err
+			}
+			if strings.TrimSpace(string(text)) != parquetSchema() {
+				return 0, fmt.Errorf("parquet schema differs from the user model")
+			}
+			return 0, nil
+		},
+		JSONUsers: func(dir, path string) (int, error) {
+			users, err := readJSONUsers(filepath.Join(dir, path), filepath.Join(dir, filepath.FromSlash(JSONSchema)))
+			if err != nil {
+				return 0, err
+			}
+			return len(users), compareUsers(expected, users)
+		},
+		JSONSchema: func(dir, path string) (int, error) {
+			source, err := os.ReadFile(filepath.Join(dir, path))
+			if err != nil {
+				return 0, err
+			}
+			expected, err := jsonSchema()
+			if err != nil {
+				return 0, err
+			}
+			if strings.TrimSpace(string(source)) != string(expected) {
+				return 0, fmt.Errorf("json schema differs from the user model")
+			}
+			return 0, jsonschema.NewXeipuuvValidator(nil).AddSchemaJSON("user", string(source))
+		},
+	}
+
+	report := &Report{Dir: dir, Manifest: &manifest}
+	for _, path := range []string{AvroUsers, AvroSchema, ProtobufUsers, ProtobufSource, ProtobufDescriptor, ParquetUsers, ParquetSchema, JSONUsers, JSONSchema} {
+		file, ok := manifest.File(path)
+		if !ok {
+			report.Checks = append(report.Checks, Check{Path: path, Error: "missing from manifest"})
+			continue
+		}
+		report.Checks = append(report.Checks, verifyFile(dir, file, verifiers[path]))
+	}
+	return report, nil
+}
+
+// verifyFile checks a file's size and checksum, then decodes it
+func verifyFile(dir string, file File, verify verifier) Check {
+	check := Check{Path: file.Path, Format: file.Format, Role: file.Role}
+
+	size, sum, err := checksum(filepath.Join(dir, filepath.FromSlash(file.Path)))
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	if size != file.Size || sum != file.SHA256 {
+		check.Error = fmt.Sprintf("checksum mismatch: manifest has %d bytes sha256 %s, file has %d bytes sha256 %s",
+			file.Size, file.SHA256, size, sum)
+		return check
+	}
+
+	records, err := verify(dir, filepath.FromSlash(file.Path))
+	check.Records = records
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+	if records != file.Records {
+		check.Error = fmt.Sprintf("expected %d records, decoded %d", file.Records, records)
+	}
+	return check
+}
+
+// readProtobufUsers decodes length-delimited user messages
+func readProtobufUsers(path string) ([]avro.User, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var users []avro.User
+	for {
+		msg := &user.User{}
+		if err := protodelim.UnmarshalFrom(reader, msg); err != nil {
+			if err == io.EOF {
+				return users, nil
+			}
+			return nil, fmt.Errorf("failed to decode user %d: %w", len(users), err)
+		}
+		users = append(users, converter.UserFromProto(msg))
+	}
+}
+
+// verifyDescriptorSet checks that the descriptor set resolves on its own and describes user.User
+func verifyDescriptorSet(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read descriptor set: %w", err)
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return fmt.Errorf("failed to decode descriptor set: %w", err)
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return fmt.Errorf("failed to resolve descriptor set: %w", err)
+	}
+	if _, err := files.FindDescriptorByName("user.User"); err != nil {
+		return fmt.Errorf("descriptor set does not describe user.User: %w", err)
+	}
+	return nil
+}
+
+// readJSONUsers validates the JSON payload against its schema, then decodes it
+func readJSONUsers(path, schemaPath string) ([]avro.User, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	schema, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read json schema: %w", err)
+	}
+
+	validator := jsonschema.NewXeipuuvValidator(nil)
+	if err := validator.AddSchemaJSON("users", string(schema)); err != nil {
+		return nil, err
+	}
+	if err := validator.ValidateJSON("users", string(data)); err != nil {
+		return nil, err
+	}
+
+	var users []avro.User
+	if err := json.Unmarshal(data, &users); err != nil {
+		return nil, fmt.Errorf("failed to decode users: %w", err)
+	}
+	return users, nil
+}
+
+// compareUsers reports the first decoded user that differs from the canonical dataset
+func compareUsers(expected, actual []avro.User) error {
+	if len(actual) != len(expected) {
+		return fmt.Errorf("expected %d users, decoded %d", len(expected), len(actual))
+	}
+	for i := range expected {
+		want, got := normalize(expected[i]), normalize(actual[i])
+		if !reflect.DeepEqual(want, got) {
+			return fmt.Errorf("user %d (id %d) differs from the canonical record: got %s", i, want.ID, describe(got))
+		}
+	}
+	return nil
+}
+
+// describe renders a user, including its profile, for error messages
+func describe(u avro.User) string {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return fmt.Sprintf("%+v", u)
+	}
+	return string(data)
+}
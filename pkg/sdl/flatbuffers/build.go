@@ -0,0 +1,341 @@
+package flatbuffers
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/flatbuffers/gen/models"
+)
+
+var (
+	userStatuses = map[avro.UserStatus]models.UserStatus{
+		avro.UserStatusActive:    models.UserStatusActive,
+		avro.UserStatusInactive:  models.UserStatusInactive,
+		avro.UserStatusSuspended: models.UserStatusSuspended,
+		avro.UserStatusDeleted:   models.UserStatusDeleted,
+	}
+	productStatuses = map[avro.ProductStatus]models.ProductStatus{
+		avro.ProductStatusActive:       models.ProductStatusActive,
+		avro.ProductStatusInactive:     models.ProductStatusInactive,
+		avro.ProductStatusOutOfStock:   models.ProductStatusOutOfStock,
+		avro.ProductStatusDiscontinued: models.ProductStatusDiscontinued,
+	}
+	orderStatuses = map[avro.OrderStatus]models.OrderStatus{
+		avro.OrderStatusPending:    models.OrderStatusPending,
+		avro.OrderStatusConfirmed:  models.OrderStatusConfirmed,
+		avro.OrderStatusProcessing: models.OrderStatusProcessing,
+		avro.OrderStatusShipped:    models.OrderStatusShipped,
+		avro.OrderStatusDelivered:  models.OrderStatusDelivered,
+		avro.OrderStatusCancelled:  models.OrderStatusCancelled,
+		avro.OrderStatusRefunded:   models.OrderStatusRefunded,
+	}
+	paymentStatuses = map[avro.PaymentStatus]models.PaymentStatus{
+		avro.PaymentStatusPending:    models.PaymentStatusPending,
+		avro.PaymentStatusAuthorized: models.PaymentStatusAuthorized,
+		avro.PaymentStatusCaptured:   models.PaymentStatusCaptured,
+		avro.PaymentStatusFailed:     models.PaymentStatusFailed,
+		avro.PaymentStatusRefunded:   models.PaymentStatusRefunded,
+	}
+)
+
+// enumValue maps a model status to its schema enum; the empty status is Unspecified
+func enumValue[S ~string, E ~byte](values map[S]E, status S) (E, error) {
+	if status == "" {
+		return 0, nil
+	}
+	v, ok := values[status]
+	if !ok {
+		return 0, fmt.Errorf("unknown status %q", status)
+	}
+	return v, nil
+}
+
+// nanos returns t as Unix nanoseconds, 0 for the zero time
+func nanos(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// createString returns 0, an absent field, for the empty string
+func createString(b *flatbuffers.Builder, s string) flatbuffers.UOffsetT {
+	if s == "" {
+		return 0
+	}
+	return b.CreateString(s)
+}
+
+// createOptionalString keeps empty strings, so only nil is absent
+func createOptionalString(b *flatbuffers.Builder, s *string) flatbuffers.UOffsetT {
+	if s == nil {
+		return 0
+	}
+	return b.CreateString(*s)
+}
+
+func createOffsets(b *flatbuffers.Builder, offsets []flatbuffers.UOffsetT) flatbuffers.UOffsetT {
+	b.StartVector(flatbuffers.SizeUOffsetT, len(offsets), flatbuffers.SizeUOffsetT)
+	for i := len(offsets) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(offsets[i])
+	}
+	return b.EndVector(len(offsets))
+}
+
+func createStrings(b *flatbuffers.Builder, values []string) flatbuffers.UOffsetT {
+	if len(values) == 0 {
+		return 0
+	}
+	offsets := make([]flatbuffers.UOffsetT, len(values))
+	for i, v := range values {
+		offsets[i] = b.CreateString(v)
+	}
+	return createOffsets(b, offsets)
+}
+
+// createKeyValues writes a map as KeyValue tables sorted by key, so equal maps
+// produce equal buffers
+func createKeyValues(b *flatbuffers.Builder, values map[string]string) flatbuffers.UOffsetT {
+	if len(values) == 0 {
+		return 0
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	offsets := make([]flatbuffers.UOffsetT, len(keys))
+	for i, k := range keys {
+		key, value := b.CreateString(k), b.CreateString(values[k])
+		models.KeyValueStart(b)
+		models.KeyValueAddKey(b, key)
+		models.KeyValueAddValue(b, value)
+		offsets[i] = models.KeyValueEnd(b)
+	}
+	return createOffsets(b, offsets)
+}
+
+func buildPrice(b *flatbuffers.Builder, p avro.Price) flatbuffers.UOffsetT {
+	currency := createString(b, p.Currency)
+	var amount flatbuffers.UOffsetT
+	if p.Amount != nil {
+		amount = b.CreateString(p.Amount.String())
+	}
+	models.PriceStart(b)
+	models.PriceAddCurrency(b, currency)
+	models.PriceAddAmountCents(b, p.AmountCents)
+	models.PriceAddAmount(b, amount)
+	if p.DiscountPercentage != nil {
+		models.PriceAddDiscountPercentage(b, *p.DiscountPercentage)
+	}
+	return models.PriceEnd(b)
+}
+
+func buildUser(b *flatbuffers.Builder, u avro.User) (flatbuffers.UOffsetT, error) {
+	status, err := enumValue(userStatuses, u.Status)
+	if err != nil {
+		return 0, err
+	}
+	email, name := createString(b, u.Email), createString(b, u.Name)
+	var profile flatbuffers.UOffsetT
+	if u.Profile != nil {
+		profile = buildProfile(b, u.Profile)
+	}
+	models.UserStart(b)
+	models.UserAddId(b, u.ID)
+	models.UserAddEmail(b, email)
+	models.UserAddName(b, name)
+	models.UserAddStatus(b, status)
+	models.UserAddProfile(b, profile)
+	models.UserAddCreatedAt(b, nanos(u.CreatedAt))
+	models.UserAddUpdatedAt(b, nanos(u.UpdatedAt))
+	return models.UserEnd(b), nil
+}
+
+func buildProfile(b *flatbuffers.Builder, p *avro.Profile) flatbuffers.UOffsetT {
+	firstName, lastName := createString(b, p.FirstName), createString(b, p.LastName)
+	phone := createOptionalString(b, p.Phone)
+	var address flatbuffers.UOffsetT
+	if a := p.Address; a != nil {
+		street, city, state := createString(b, a.Street), createString(b, a.City), createString(b, a.State)
+		postalCode, country := createString(b, a.PostalCode), createString(b, a.Country)
+		models.AddressStart(b)
+		models.AddressAddStreet(b, street)
+		models.AddressAddCity(b, city)
+		models.AddressAddState(b, state)
+		models.AddressAddPostalCode(b, postalCode)
+		models.AddressAddCountry(b, country)
+		address = models.AddressEnd(b)
+	}
+	interests, metadata := createStrings(b, p.Interests), createKeyValues(b, p.Metadata)
+	models.ProfileStart(b)
+	models.ProfileAddFirstName(b, firstName)
+	models.ProfileAddLastName(b, lastName)
+	models.ProfileAddPhone(b, phone)
+	models.ProfileAddAddress(b, address)
+	models.ProfileAddInterests(b, interests)
+	models.ProfileAddMetadata(b, metadata)
+	return models.ProfileEnd(b)
+}
+
+func buildProduct(b *flatbuffers.Builder, p avro.Product) (flatbuffers.UOffsetT, error) {
+	status, err := enumValue(productStatuses, p.Status)
+	if err != nil {
+		return 0, err
+	}
+	name, description, sku := createString(b, p.Name), createString(b, p.Description), createString(b, p.SKU)
+	price := buildPrice(b, p.Price)
+	categories, tags := createStrings(b, p.Categories), createStrings(b, p.Tags)
+	specifications := createKeyValues(b, p.Specifications)
+
+	models.ProductStart(b)
+	models.ProductAddId(b, p.ID)
+	models.ProductAddName(b, name)
+	models.ProductAddDescription(b, description)
+	models.ProductAddSku(b, sku)
+	models.ProductAddPrice(b, price)
+	inv := p.Inventory
+	models.ProductAddInventory(b, models.CreateInventory(b,
+		inv.Quantity, inv.Reserved, inv.Available, inv.ReorderLevel, inv.MaxStock, inv.TrackInventory))
+	models.ProductAddCategories(b, categories)
+	models.ProductAddTags(b, tags)
+	models.ProductAddStatus(b, status)
+	models.ProductAddSpecifications(b, specifications)
+	if p.ReleaseDate != nil {
+		models.ProductAddReleaseDate(b, nanos(*p.ReleaseDate))
+	}
+	models.ProductAddCreatedAt(b, nanos(p.CreatedAt))
+	models.ProductAddUpdatedAt(b, nanos(p.UpdatedAt))
+	return models.ProductEnd(b), nil
+}
+
+func buildOrder(b *flatbuffers.Builder, o avro.Order) (flatbuffers.UOffsetT, error) {
+	status, err := enumValue(orderStatuses, o.Status)
+	if err != nil {
+		return 0, err
+	}
+	uuid, orderNumber := createOptionalString(b, o.UUID), createString(b, o.OrderNumber)
+
+	var items flatbuffers.UOffsetT
+	if len(o.Items) > 0 {
+		offsets := make([]flatbuffers.UOffsetT, len(o.Items))
+		for i, item := range o.Items {
+			offsets[i] = buildOrderItem(b, item)
+		}
+		items = createOffsets(b, offsets)
+	}
+	summary := buildOrderSummary(b, o.Summary)
+	var shipping, payment flatbuffers.UOffsetT
+	if o.ShippingInfo != nil {
+		shipping = buildShippingInfo(b, o.ShippingInfo)
+	}
+	if o.PaymentInfo != nil {
+		if payment, err = buildPaymentInfo(b, o.PaymentInfo); err != nil {
+			return 0, err
+		}
+	}
+
+	models.OrderStart(b)
+	models.OrderAddId(b, o.ID)
+	models.OrderAddUuid(b, uuid)
+	models.OrderAddUserId(b, o.UserID)
+	models.OrderAddOrderNumber(b, orderNumber)
+	models.OrderAddStatus(b, status)
+	models.OrderAddItems(b, items)
+	models.OrderAddSummary(b, summary)
+	models.OrderAddShippingInfo(b, shipping)
+	models.OrderAddPaymentInfo(b, payment)
+	models.OrderAddCreatedAt(b, nanos(o.CreatedAt))
+	models.OrderAddUpdatedAt(b, nanos(o.UpdatedAt))
+	if o.ShippedAt != nil {
+		models.OrderAddShippedAt(b, nanos(*o.ShippedAt))
+	}
+	if o.DeliveredAt != nil {
+		models.OrderAddDeliveredAt(b, nanos(*o.DeliveredAt))
+	}
+	return models.OrderEnd(b), nil
+}
+
+func buildOrderItem(b *flatbuffers.Builder, item avro.OrderItem) flatbuffers.UOffsetT {
+	name, sku := createString(b, item.ProductName), createString(b, item.ProductSKU)
+	unitPrice, totalPrice := buildPrice(b, item.UnitPrice), buildPrice(b, item.TotalPrice)
+	variant := createKeyValues(b, item.ProductVariant)
+	models.OrderItemStart(b)
+	models.OrderItemAddProductId(b, item.ProductID)
+	models.OrderItemAddProductName(b, name)
+	models.OrderItemAddProductSku(b, sku)
+	models.OrderItemAddQuantity(b, item.Quantity)
+	models.OrderItemAddUnitPrice(b, unitPrice)
+	models.OrderItemAddTotalPrice(b, totalPrice)
+	models.OrderItemAddProductVariant(b, variant)
+	return models.OrderItemEnd(b)
+}
+
+func buildOrderSummary(b *flatbuffers.Builder, s avro.OrderSummary) flatbuffers.UOffsetT {
+	subtotal, tax := buildPrice(b, s.Subtotal), buildPrice(b, s.Tax)
+	shipping, discount, total := buildPrice(b, s.ShippingCost), buildPrice(b, s.Discount), buildPrice(b, s.Total)
+	models.OrderSummaryStart(b)
+	models.OrderSummaryAddSubtotal(b, subtotal)
+	models.OrderSummaryAddTax(b, tax)
+	models.OrderSummaryAddShippingCost(b, shipping)
+	models.OrderSummaryAddDiscount(b, discount)
+	models.OrderSummaryAddTotal(b, total)
+	models.OrderSummaryAddTotalItems(b, s.TotalItems)
+	return models.OrderSummaryEnd(b)
+}
+
+func buildShippingInfo(b *flatbuffers.Builder, s *avro.ShippingInfo) flatbuffers.UOffsetT {
+	a := s.Address
+	recipient, street, city := createString(b, a.RecipientName), createString(b, a.Street), createString(b, a.City)
+	state, postalCode, country := createString(b, a.State), createString(b, a.PostalCode), createString(b, a.Country)
+	models.ShippingAddressStart(b)
+	models.ShippingAddressAddRecipientName(b, recipient)
+	models.ShippingAddressAddStreet(b, street)
+	models.ShippingAddressAddCity(b, city)
+	models.ShippingAddressAddState(b, state)
+	models.ShippingAddressAddPostalCode(b, postalCode)
+	models.ShippingAddressAddCountry(b, country)
+	address := models.ShippingAddressEnd(b)
+
+	method := createString(b, s.Method)
+	tracking, carrier := createOptionalString(b, s.TrackingNumber), createOptionalString(b, s.Carrier)
+	cost := buildPrice(b, s.Cost)
+	models.ShippingInfoStart(b)
+	models.ShippingInfoAddAddress(b, address)
+	models.ShippingInfoAddMethod(b, method)
+	models.ShippingInfoAddTrackingNumber(b, tracking)
+	models.ShippingInfoAddCarrier(b, carrier)
+	models.ShippingInfoAddCost(b, cost)
+	if s.EstimatedDelivery != nil {
+		models.ShippingInfoAddEstimatedDelivery(b, nanos(*s.EstimatedDelivery))
+	}
+	if s.DeliveryTime != nil {
+		models.ShippingInfoAddDeliveryTime(b, int64(*s.DeliveryTime))
+	}
+	return models.ShippingInfoEnd(b)
+}
+
+func buildPaymentInfo(b *flatbuffers.Builder, p *avro.PaymentInfo) (flatbuffers.UOffsetT, error) {
+	status, err := enumValue(paymentStatuses, p.Status)
+	if err != nil {
+		return 0, err
+	}
+	method, transaction := createString(b, p.Method), createOptionalString(b, p.TransactionID)
+	amount := buildPrice(b, p.Amount)
+	models.PaymentInfoStart(b)
+	models.PaymentInfoAddMethod(b, method)
+	models.PaymentInfoAddStatus(b, status)
+	models.PaymentInfoAddTransactionId(b, transaction)
+	models.PaymentInfoAddAmount(b, amount)
+	if p.ProcessedAt != nil {
+		models.PaymentInfoAddProcessedAt(b, nanos(*p.ProcessedAt))
+	}
+	if p.AuthorizedAt != nil {
+		models.PaymentInfoAddAuthorizedAt(b, nanos(*p.AuthorizedAt))
+	}
+	return models.PaymentInfoEnd(b), nil
+}
@@ -0,0 +1,397 @@
+// Package flatbuffers serializes the shared Avro models as FlatBuffers. The
+// schemas in schemas/ are compiled to accessors in gen/models; a buffer is
+// read in place through them, so a reader can look at a few fields of a large
+// record without parsing or allocating the rest.
+package flatbuffers
+
+//go:generate flatc --go -o gen schemas/common.fbs schemas/user.fbs schemas/product.fbs schemas/order.fbs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/flatbuffers/gen/models"
+)
+
+// ContentType is the media type of FlatBuffers payloads
+const ContentType = "application/x-flatbuffers"
+
+// FileExtension is the extension of FlatBuffers files
+const FileExtension = ".fb"
+
+// MaxBufferSize bounds the size prefix accepted when reading a stream
+const MaxBufferSize = 64 << 20
+
+// Manager handles FlatBuffers serialization and deserialization
+type Manager struct {
+	baseDir  string
+	builders sync.Pool
+}
+
+var _ types.Serializer = (*Manager)(nil)
+
+// NewManager creates a new FlatBuffers manager writing files under baseDir
+func NewManager(baseDir string) *Manager {
+	if baseDir == "" {
+		baseDir = "data/flatbuffers"
+	}
+	return &Manager{
+		baseDir:  baseDir,
+		builders: sync.Pool{New: func() any { return flatbuffers.NewBuilder(1024) }},
+	}
+}
+
+// ContentType returns the media type of FlatBuffers payloads
+func (m *Manager) ContentType() string {
+	return ContentType
+}
+
+// FileExtension returns the extension of FlatBuffers files
+func (m *Manager) FileExtension() string {
+	return FileExtension
+}
+
+// finish builds one record with a pooled builder and returns a copy of the
+// finished buffer, size-prefixed when prefixed is set
+func (m *Manager) finish(identifier string, prefixed bool, build func(*flatbuffers.Builder) (flatbuffers.UOffsetT, error)) ([]byte, error) {
+	b := m.builders.Get().(*flatbuffers.Builder)
+	defer m.builders.Put(b)
+	b.Reset()
+
+	root, err := build(b)
+	if err != nil {
+		return nil, err
+	}
+	if prefixed {
+		b.FinishSizePrefixedWithFileIdentifier(root, []byte(identifier))
+	} else {
+		b.FinishWithFileIdentifier(root, []byte(identifier))
+	}
+	return append([]byte(nil), b.FinishedBytes()...), nil
+}
+
+// SerializeUser builds a user buffer
+func (m *Manager) SerializeUser(user avro.User) ([]byte, error) {
+	data, err := m.finish(models.UserIdentifier, false, func(b *flatbuffers.Builder) (flatbuffers.UOffsetT, error) {
+		return buildUser(b, user)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize user: %w", err)
+	}
+	return data, nil
+}
+
+// SerializeProduct builds a product buffer
+func (m *Manager) SerializeProduct(product avro.Product) ([]byte, error) {
+	data, err := m.finish(models.ProductIdentifier, false, func(b *flatbuffers.Builder) (flatbuffers.UOffsetT, error) {
+		return buildProduct(b, product)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize product: %w", err)
+	}
+	return data, nil
+}
+
+// SerializeOrder builds an order buffer
+func (m *Manager) SerializeOrder(order avro.Order) ([]byte, error) {
+	data, err := m.finish(models.OrderIdentifier, false, func(b *flatbuffers.Builder) (flatbuffers.UOffsetT, error) {
+		return buildOrder(b, order)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize order: %w", err)
+	}
+	return data, nil
+}
+
+// checkRoot rejects buffers too short to hold a root offset and file
+// identifier, buffers of another type and root offsets past the end
+func checkRoot(data []byte, identifier string) error {
+	if len(data) < flatbuffers.SizeUOffsetT+len(identifier) {
+		return fmt.Errorf("buffer of %d bytes is too short", len(data))
+	}
+	if !flatbuffers.BufferHasIdentifier(data, identifier) {
+		return fmt.Errorf("expected file identifier %q, got %q", identifier, flatbuffers.GetBufferIdentifier(data))
+	}
+	if root := flatbuffers.GetUOffsetT(data); int(root) >= len(data) {
+		return fmt.Errorf("root offset %d is out of range", root)
+	}
+	return nil
+}
+
+// ViewUser returns accessors reading the user in data in place, without
+// copying or decoding it. The view shares data, which must not change while
+// it is in use. Only the identifier and root offset are checked, so views of
+// untrusted data may panic on access; DeserializeUser does not
+func (m *Manager) ViewUser(data []byte) (*models.User, error) {
+	if err := checkRoot(data, models.UserIdentifier); err != nil {
+		return nil, err
+	}
+	return models.GetRootAsUser(data, 0), nil
+}
+
+// ViewProduct returns accessors reading the product in data in place
+func (m *Manager) ViewProduct(data []byte) (*models.Product, error) {
+	if err := checkRoot(data, models.ProductIdentifier); err != nil {
+		return nil, err
+	}
+	return models.GetRootAsProduct(data, 0), nil
+}
+
+// ViewOrder returns accessors reading the order in data in place
+func (m *Manager) ViewOrder(data []byte) (*models.Order, error) {
+	if err := checkRoot(data, models.OrderIdentifier); err != nil {
+		return nil, err
+	}
+	return models.GetRootAsOrder(data, 0), nil
+}
+
+// unpack runs fn, turning the panics of out-of-range reads in a malformed
+// buffer into errors
+func unpack[T any](fn func() (T, error)) (v T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero T
+			v, err = zero, fmt.Errorf("malformed buffer: %v", r)
+		}
+	}()
+	return fn()
+}
+
+// DeserializeUser copies a user out of a buffer. Empty lists and maps come
+// back nil, as FlatBuffers does not tell them apart from absent ones
+func (m *Manager) DeserializeUser(data []byte) (avro.User, error) {
+	view, err := m.ViewUser(data)
+	if err != nil {
+		return avro.User{}, fmt.Errorf("failed to deserialize user: %w", err)
+	}
+	user, err := unpack(func() (avro.User, error) { return unpackUser(view) })
+	if err != nil {
+		return avro.User{}, fmt.Errorf("failed to deserialize user: %w", err)
+	}
+	return user, nil
+}
+
+// DeserializeProduct copies a product out of a buffer
+func (m *Manager) DeserializeProduct(data []byte) (avro.Product, error) {
+	view, err := m.ViewProduct(data)
+	if err != nil {
+		return avro.Product{}, fmt.Errorf("failed to deserialize product: %w", err)
+	}
+	product, err := unpack(func() (avro.Product, error) { return unpackProduct(view) })
+	if err != nil {
+		return avro.Product{}, fmt.Errorf("failed to deserialize product: %w", err)
+	}
+	return product, nil
+}
+
+// DeserializeOrder copies an order out of a buffer
+func (m *Manager) DeserializeOrder(data []byte) (avro.Order, error) {
+	view, err := m.ViewOrder(data)
+	if err != nil {
+		return avro.Order{}, fmt.Errorf("failed to deserialize order: %w", err)
+	}
+	order, err := unpack(func() (avro.Order, error) { return unpackOrder(view) })
+	if err != nil {
+		return avro.Order{}, fmt.Errorf("failed to deserialize order: %w", err)
+	}
+	return order, nil
+}
+
+// Serialize builds a buffer for a User, Product or Order, or a pointer to one
+func (m *Manager) Serialize(data any) ([]byte, error) {
+	switch v := data.(type) {
+	case avro.User:
+		return m.SerializeUser(v)
+	case *avro.User:
+		return m.SerializeUser(*v)
+	case avro.Product:
+		return m.SerializeProduct(v)
+	case *avro.Product:
+		return m.SerializeProduct(*v)
+	case avro.Order:
+		return m.SerializeOrder(v)
+	case *avro.Order:
+		return m.SerializeOrder(*v)
+	}
+	return nil, fmt.Errorf("unsupported type %T", data)
+}
+
+// Deserialize copies a buffer into a *User, *Product or *Order
+func (m *Manager) Deserialize(data []byte, target any) error {
+	var err error
+	switch v := target.(type) {
+	case *avro.User:
+		*v, err = m.DeserializeUser(data)
+	case *avro.Product:
+		*v, err = m.DeserializeProduct(data)
+	case *avro.Order:
+		*v, err = m.DeserializeOrder(data)
+	default:
+		return fmt.Errorf("unsupported target type %T", target)
+	}
+	return err
+}
+
+// EncodeUsers writes users to w as size-prefixed buffers back to back
+func (m *Manager) EncodeUsers(w io.Writer, users []avro.User) error {
+	return encodeAll(m, w, models.UserIdentifier, users, buildUser)
+}
+
+// DecodeUsers reads size-prefixed user buffers from r until it ends
+func (m *Manager) DecodeUsers(r io.Reader) ([]avro.User, error) {
+	return decodeAll(r, m.DeserializeUser)
+}
+
+// EachUser calls fn with a view of every size-prefixed user buffer in data,
+// without copying. Views are only valid during the call
+func (m *Manager) EachUser(data []byte, fn func(*models.User) error) error {
+	var view models.User
+	return eachBuffer(data, func(buf []byte) error {
+		if err := checkRoot(buf, models.UserIdentifier); err != nil {
+			return err
+		}
+		view.Init(buf, flatbuffers.GetUOffsetT(buf))
+		return fn(&view)
+	})
+}
+
+// WriteUsersToFile writes users to a file of size-prefixed buffers under the base directory
+func (m *Manager) WriteUsersToFile(filename string, users []avro.User) error {
+	return writeFile(m, filename, func(w io.Writer) error { return m.EncodeUsers(w, users) })
+}
+
+// ReadUsersFromFile reads users from a file of size-prefixed buffers under the base directory
+func (m *Manager) ReadUsersFromFile(filename string) ([]avro.User, error) {
+	return readFile(m, filename, m.DeserializeUser)
+}
+
+// WriteProductsToFile writes products to a file of size-prefixed buffers under the base directory
+func (m *Manager) WriteProductsToFile(filename string, products []avro.Product) error {
+	return writeFile(m, filename, func(w io.Writer) error {
+		return encodeAll(m, w, models.ProductIdentifier, products, buildProduct)
+	})
+}
+
+// ReadProductsFromFile reads products from a file of size-prefixed buffers under the base directory
+func (m *Manager) ReadProductsFromFile(filename string) ([]avro.Product, error) {
+	return readFile(m, filename, m.DeserializeProduct)
+}
+
+// WriteOrdersToFile writes orders to a file of size-prefixed buffers under the base directory
+func (m *Manager) WriteOrdersToFile(filename string, orders []avro.Order) error {
+	return writeFile(m, filename, func(w io.Writer) error {
+		return encodeAll(m, w, models.OrderIdentifier, orders, buildOrder)
+	})
+}
+
+// ReadOrdersFromFile reads orders from a file of size-prefixed buffers under the base directory
+func (m *Manager) ReadOrdersFromFile(filename string) ([]avro.Order, error) {
+	return readFile(m, filename, m.DeserializeOrder)
+}
+
+func encodeAll[T any](m *Manager, w io.Writer, identifier string, values []T, build func(*flatbuffers.Builder, T) (flatbuffers.UOffsetT, error)) error {
+	for i := range values {
+		data, err := m.finish(identifier, true, func(b *flatbuffers.Builder) (flatbuffers.UOffsetT, error) {
+			return build(b, values[i])
+		})
+		if err != nil {
+			return fmt.Errorf("failed to build record %d: %w", i, err)
+		}
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("failed to write record %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// decodeAll reads size-prefixed buffers from r, reusing one read buffer as
+// every record is copied out of it
+func decodeAll[T any](r io.Reader, deserialize func([]byte) (T, error)) ([]T, error) {
+	var values []T
+	var prefix [flatbuffers.SizeUint32]byte
+	var buf []byte
+	for {
+		if _, err := io.ReadFull(r, prefix[:]); err != nil {
+			if err == io.EOF {
+				return values, nil
+			}
+			return nil, fmt.Errorf("failed to read size of record %d: %w", len(values), err)
+		}
+		size := binary.LittleEndian.Uint32(prefix[:])
+		if size > MaxBufferSize {
+			return nil, fmt.Errorf("record %d of %d bytes exceeds the maximum of %d", len(values), size, MaxBufferSize)
+		}
+		if cap(buf) < int(size) {
+			buf = make([]byte, size)
+		}
+		buf = buf[:size]
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, fmt.Errorf("failed to read record %d: %w", len(values), err)
+		}
+		v, err := deserialize(buf)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", len(values), err)
+		}
+		values = append(values, v)
+	}
+}
+
+// eachBuffer splits data into its size-prefixed buffers
+func eachBuffer(data []byte, fn func([]byte) error) error {
+	for n := 0; len(data) > 0; n++ {
+		if len(data) < flatbuffers.SizeUint32 {
+			return fmt.Errorf("record %d: truncated size prefix", n)
+		}
+		size := binary.LittleEndian.Uint32(data)
+		data = data[flatbuffers.SizeUint32:]
+		if uint64(size) > uint64(len(data)) {
+			return fmt.Errorf("record %d: %w", n, io.ErrUnexpectedEOF)
+		}
+		if err := fn(data[:size]); err != nil {
+			return fmt.Errorf("record %d: %w", n, err)
+		}
+		data = data[size:]
+	}
+	return nil
+}
+
+func writeFile(m *Manager, filename string, encode func(io.Writer) error) error {
+	if err := os.MkdirAll(m.baseDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	file, err := os.Create(filepath.Join(m.baseDir, filename))
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	w := bufio.NewWriter(file)
+	if err := encode(w); err != nil {
+		file.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	return nil
+}
+
+func readFile[T any](m *Manager, filename string, deserialize func([]byte) (T, error)) ([]T, error) {
+	file, err := os.Open(filepath.Join(m.baseDir, filename))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+	return decodeAll(bufio.NewReader(file), deserialize)
+}
@@ -0,0 +1,264 @@
+package flatbuffers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/flatbuffers/gen/models"
+)
+
+func samples(t *testing.T) *avro.Manager {
+	t.Helper()
+	manager, err := avro.NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create avro manager: %v", err)
+	}
+	return manager.WithClock(testutil.NewDefaultFakeClock())
+}
+
+func sampleOrder(id int64) avro.Order {
+	created := time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.UTC)
+	shipped, estimated := created.Add(2*time.Hour), created.Add(72*time.Hour)
+	deliveryTime := 14 * time.Hour
+	uuid, tracking, carrier, transaction := "0b6f9c1e-3f4a-4c55-9a1e-6d2f0c1b2a3d", "TRK00000001", "", "txn_1"
+	usd := func(cents int64) avro.Price { return avro.Price{Currency: "USD", AmountCents: cents} }
+	return avro.Order{
+		ID:          id,
+		UUID:        &uuid,
+		UserID:      7,
+		OrderNumber: "ORD-000001",
+		Status:      avro.OrderStatusShipped,
+		Items: []avro.OrderItem{
+			{ProductID: 1, ProductName: "Sensor", ProductSKU: "SKU-000001", Quantity: 2, UnitPrice: usd(1500), TotalPrice: usd(3000),
+				ProductVariant: map[string]string{"color": "red", "size": "m"}},
+			{ProductID: 2, ProductName: "Gateway", ProductSKU: "SKU-000002", Quantity: 1, UnitPrice: usd(9900), TotalPrice: usd(9900)},
+		},
+		Summary: avro.OrderSummary{
+			Subtotal: usd(12900), Tax: usd(1290), ShippingCost: usd(500), Discount: usd(0), Total: usd(14690), TotalItems: 2,
+		},
+		ShippingInfo: &avro.ShippingInfo{
+			Address:           avro.ShippingAddress{RecipientName: "User 7", Street: "1 Main St", City: "Test City", State: "TS", PostalCode: "10000", Country: "USA"},
+			Method:            "standard",
+			TrackingNumber:    &tracking,
+			Carrier:           &carrier,
+			Cost:              usd(500),
+			EstimatedDelivery: &estimated,
+			DeliveryTime:      &deliveryTime,
+		},
+		PaymentInfo: &avro.PaymentInfo{
+			Method:        "credit_card",
+			Status:        avro.PaymentStatusCaptured,
+			TransactionID: &transaction,
+			Amount:        avro.Price{Currency: "USD", AmountCents: 14690, Amount: avro.NewDecimal(14690, 2)},
+			ProcessedAt:   &shipped,
+		},
+		CreatedAt: created,
+		UpdatedAt: shipped,
+		ShippedAt: &shipped,
+	}
+}
+
+func TestFlatBuffersRoundTrip(t *testing.T) {
+	manager := NewManager("")
+
+	for _, user := range samples(t).CreateSampleUsers(3) {
+		data, err := manager.SerializeUser(user)
+		if err != nil {
+			t.Fatalf("Failed to serialize user: %v", err)
+		}
+		back, err := manager.DeserializeUser(data)
+		if err != nil {
+			t.Fatalf("Failed to deserialize user: %v", err)
+		}
+		if !reflect.DeepEqual(back, user) {
+			t.Errorf("User changed across FlatBuffers:\n got %+v\nwant %+v", back, user)
+		}
+	}
+
+	product := samples(t).CreateSampleProducts(2)[1]
+	release := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	discount := float32(0.15)
+	product.ReleaseDate = &release
+	product.Price.DiscountPercentage = &discount
+	product.Price.Amount = avro.NewDecimal(123456, 2)
+	data, err := manager.SerializeProduct(product)
+	if err != nil {
+		t.Fatalf("Failed to serialize product: %v", err)
+	}
+	backProduct, err := manager.DeserializeProduct(data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize product: %v", err)
+	}
+	if backProduct.Price.Amount == nil || backProduct.Price.Amount.Cmp(product.Price.Amount) != 0 {
+		t.Errorf("Expected amount %s, got %s", product.Price.Amount, backProduct.Price.Amount)
+	}
+	backProduct.Price.Amount, product.Price.Amount = nil, nil
+	if !reflect.DeepEqual(backProduct, product) {
+		t.Errorf("Product changed across FlatBuffers:\n got %+v\nwant %+v", backProduct, product)
+	}
+
+	// Through the Serializer interface; the empty carrier stays distinct from nil
+	order := sampleOrder(1)
+	data, err = manager.Serialize(&order)
+	if err != nil {
+		t.Fatalf("Failed to serialize order: %v", err)
+	}
+	var backOrder avro.Order
+	if err := manager.Deserialize(data, &backOrder); err != nil {
+		t.Fatalf("Failed to deserialize order: %v", err)
+	}
+	if backOrder.PaymentInfo.Amount.Amount.Cmp(order.PaymentInfo.Amount.Amount) != 0 {
+		t.Errorf("Expected payment amount %s, got %s", order.PaymentInfo.Amount.Amount, backOrder.PaymentInfo.Amount.Amount)
+	}
+	backOrder.PaymentInfo.Amount.Amount, order.PaymentInfo.Amount.Amount = nil, nil
+	if !reflect.DeepEqual(backOrder, order) {
+		t.Errorf("Order changed across FlatBuffers:\n got %+v\nwant %+v", backOrder, order)
+	}
+
+	if _, err := manager.Serialize("not a model"); err == nil {
+		t.Error("Expected an unsupported type to be rejected")
+	}
+	if _, err := manager.SerializeUser(avro.User{ID: 1, Status: "UNKNOWN"}); err == nil {
+		t.Error("Expected an unknown status to be rejected")
+	}
+	if manager.ContentType() != "application/x-flatbuffers" || manager.FileExtension() != ".fb" {
+		t.Errorf("Unexpected content type %s or extension %s", manager.ContentType(), manager.FileExtension())
+	}
+
+	t.Log("✓ Users, products and orders round trip through FlatBuffers")
+}
+
+func TestFlatBuffersViews(t *testing.T) {
+	manager := NewManager("")
+	user := samples(t).CreateSampleUsers(1)[0]
+	data, err := manager.SerializeUser(user)
+	if err != nil {
+		t.Fatalf("Failed to serialize user: %v", err)
+	}
+
+	view, err := manager.ViewUser(data)
+	if err != nil {
+		t.Fatalf("Failed to view user: %v", err)
+	}
+	if view.Id() != user.ID || string(view.Email()) != user.Email || view.Status() != models.UserStatusActive {
+		t.Errorf("Unexpected view id %d, email %s, status %s", view.Id(), view.Email(), view.Status())
+	}
+	if profile := view.Profile(nil); profile == nil || string(profile.Address(nil).City()) != user.Profile.Address.City || profile.InterestsLength() != len(user.Profile.Interests) {
+		t.Error("Expected the profile to be readable through the view")
+	}
+
+	// Reading fields allocates nothing, and writes go straight to the buffer
+	if allocs := testing.AllocsPerRun(100, func() { _, _ = view.Id(), view.Email() }); allocs != 0 {
+		t.Errorf("Expected field reads not to allocate, got %.0f allocations", allocs)
+	}
+	if !view.MutateId(99) {
+		t.Fatal("Expected the id to be mutable in place")
+	}
+	if back, err := manager.DeserializeUser(data); err != nil || back.ID != 99 {
+		t.Errorf("Expected the mutated id 99 in the buffer, got %d (%v)", back.ID, err)
+	}
+
+	order, err := manager.SerializeOrder(sampleOrder(5))
+	if err != nil {
+		t.Fatalf("Failed to serialize order: %v", err)
+	}
+	orderView, err := manager.ViewOrder(order)
+	if err != nil {
+		t.Fatalf("Failed to view order: %v", err)
+	}
+	var item models.OrderItem
+	if orderView.ItemsLength() != 2 || !orderView.Items(&item, 1) || string(item.ProductName()) != "Gateway" {
+		t.Error("Expected the second order item to be readable through the view")
+	}
+
+	// Buffers are checked for their file identifier
+	if _, err := manager.ViewProduct(data); err == nil {
+		t.Error("Expected a user buffer to be rejected as a product")
+	}
+	if _, err := manager.DeserializeOrder(data[:6]); err == nil {
+		t.Error("Expected a short buffer to be rejected")
+	}
+	// A corrupt table fails instead of panicking
+	corrupt := append([]byte(nil), data...)
+	root := binary.LittleEndian.Uint32(corrupt)
+	binary.LittleEndian.PutUint32(corrupt[root:], 0x7fff0000)
+	if _, err := manager.DeserializeUser(corrupt); err == nil {
+		t.Error("Expected a corrupt buffer to be rejected")
+	}
+
+	t.Log("✓ FlatBuffers views read fields in place")
+}
+
+func TestFlatBuffersStreams(t *testing.T) {
+	testDir := "tmp/test_flatbuffers_streams"
+	manager := NewManager(testDir)
+	defer os.RemoveAll(testDir)
+
+	users := samples(t).CreateSampleUsers(20)
+	var buf bytes.Buffer
+	if err := manager.EncodeUsers(&buf, users); err != nil {
+		t.Fatalf("Failed to encode users: %v", err)
+	}
+	back, err := manager.DecodeUsers(bytes.NewReader(buf.Bytes()))
+	if err != nil || !reflect.DeepEqual(back, users) {
+		t.Fatalf("Users changed across the stream (%v)", err)
+	}
+
+	// Views over the whole stream without copying
+	var ids []int64
+	err = manager.EachUser(buf.Bytes(), func(u *models.User) error {
+		ids = append(ids, u.Id())
+		return nil
+	})
+	if err != nil || len(ids) != len(users) || ids[19] != users[19].ID {
+		t.Errorf("Expected %d user views, got %v (%v)", len(users), ids, err)
+	}
+
+	truncated := buf.Bytes()[:buf.Len()-3]
+	if _, err := manager.DecodeUsers(bytes.NewReader(truncated)); err == nil {
+		t.Error("Expected a truncated stream to fail")
+	}
+	if err := manager.EachUser(truncated, func(*models.User) error { return nil }); err == nil {
+		t.Error("Expected a truncated stream to fail when viewed")
+	}
+
+	// FlatBuffers trades size for access: alignment and vtables cost bytes
+	jsonData, _ := json.Marshal(users)
+	t.Logf("FlatBuffers stream %d bytes, JSON %d bytes", buf.Len(), len(jsonData))
+
+	if err := manager.WriteUsersToFile("users.fb", users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	if back, err := manager.ReadUsersFromFile("users.fb"); err != nil || !reflect.DeepEqual(back, users) {
+		t.Errorf("Users changed across the file (%v)", err)
+	}
+	products := samples(t).CreateSampleProducts(10)
+	if err := manager.WriteProductsToFile("products.fb", products); err != nil {
+		t.Fatalf("Failed to write products: %v", err)
+	}
+	if back, err := manager.ReadProductsFromFile("products.fb"); err != nil || !reflect.DeepEqual(back, products) {
+		t.Errorf("Products changed across the file (%v)", err)
+	}
+	orders := []avro.Order{sampleOrder(1), sampleOrder(2)}
+	if err := manager.WriteOrdersToFile("orders.fb", orders); err != nil {
+		t.Fatalf("Failed to write orders: %v", err)
+	}
+	if back, err := manager.ReadOrdersFromFile("orders.fb"); err != nil || len(back) != 2 || back[1].ID != 2 {
+		t.Errorf("Orders changed across the file (%v)", err)
+	}
+	if _, err := manager.ReadUsersFromFile("products.fb"); err == nil {
+		t.Error("Expected a products file to be rejected as users")
+	}
+	if _, err := manager.ReadUsersFromFile("missing.fb"); err == nil {
+		t.Error("Expected a missing file to fail")
+	}
+
+	t.Log("✓ FlatBuffers streams and files hold size-prefixed buffers")
+}
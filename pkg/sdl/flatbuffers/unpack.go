@@ -0,0 +1,295 @@
+package flatbuffers
+
+import (
+	"fmt"
+	"time"
+
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/flatbuffers/gen/models"
+)
+
+// statusName maps a schema enum back to the model status; Unspecified is empty
+func statusName[S ~string, E comparable](values map[S]E, v E) (S, error) {
+	var zero E
+	if v == zero {
+		return "", nil
+	}
+	for status, e := range values {
+		if e == v {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("unknown status %v", v)
+}
+
+// fromNanos returns Unix nanoseconds as a UTC time, the zero time for 0
+func fromNanos(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n).UTC()
+}
+
+func optionalTime(n *int64) *time.Time {
+	if n == nil {
+		return nil
+	}
+	t := fromNanos(*n)
+	return &t
+}
+
+func optionalString(b []byte) *string {
+	if b == nil {
+		return nil
+	}
+	s := string(b)
+	return &s
+}
+
+func unpackStrings(n int, at func(int) []byte) []string {
+	if n == 0 {
+		return nil
+	}
+	values := make([]string, n)
+	for i := range values {
+		values[i] = string(at(i))
+	}
+	return values
+}
+
+func unpackKeyValues(n int, at func(*models.KeyValue, int) bool) map[string]string {
+	if n == 0 {
+		return nil
+	}
+	values := make(map[string]string, n)
+	var kv models.KeyValue
+	for i := 0; i < n; i++ {
+		at(&kv, i)
+		values[string(kv.Key())] = string(kv.Value())
+	}
+	return values
+}
+
+func unpackPrice(p *models.Price) (avro.Price, error) {
+	if p == nil {
+		return avro.Price{}, nil
+	}
+	price := avro.Price{
+		Currency:           string(p.Currency()),
+		AmountCents:        p.AmountCents(),
+		DiscountPercentage: p.DiscountPercentage(),
+	}
+	if amount := p.Amount(); amount != nil {
+		d, err := avro.ParseDecimal(string(amount))
+		if err != nil {
+			return avro.Price{}, err
+		}
+		price.Amount = d
+	}
+	return price, nil
+}
+
+func unpackUser(u *models.User) (avro.User, error) {
+	status, err := statusName(userStatuses, u.Status())
+	if err != nil {
+		return avro.User{}, err
+	}
+	user := avro.User{
+		ID:        u.Id(),
+		Email:     string(u.Email()),
+		Name:      string(u.Name()),
+		Status:    status,
+		CreatedAt: fromNanos(u.CreatedAt()),
+		UpdatedAt: fromNanos(u.UpdatedAt()),
+	}
+	if p := u.Profile(nil); p != nil {
+		user.Profile = &avro.Profile{
+			FirstName: string(p.FirstName()),
+			LastName:  string(p.LastName()),
+			Phone:     optionalString(p.Phone()),
+			Interests: unpackStrings(p.InterestsLength(), p.Interests),
+			Metadata:  unpackKeyValues(p.MetadataLength(), p.Metadata),
+		}
+		if a := p.Address(nil); a != nil {
+			user.Profile.Address = &avro.Address{
+				Street:     string(a.Street()),
+				City:       string(a.City()),
+				State:      string(a.State()),
+				PostalCode: string(a.PostalCode()),
+				Country:    string(a.Country()),
+			}
+		}
+	}
+	return user, nil
+}
+
+func unpackProduct(p *models.Product) (avro.Product, error) {
+	status, err := statusName(productStatuses, p.Status())
+	if err != nil {
+		return avro.Product{}, err
+	}
+	price, err := unpackPrice(p.Price(nil))
+	if err != nil {
+		return avro.Product{}, err
+	}
+	product := avro.Product{
+		ID:             p.Id(),
+		Name:           string(p.Name()),
+		Description:    string(p.Description()),
+		SKU:            string(p.Sku()),
+		Price:          price,
+		Categories:     unpackStrings(p.CategoriesLength(), p.Categories),
+		Tags:           unpackStrings(p.TagsLength(), p.Tags),
+		Status:         status,
+		Specifications: unpackKeyValues(p.SpecificationsLength(), p.Specifications),
+		ReleaseDate:    optionalTime(p.ReleaseDate()),
+		CreatedAt:      fromNanos(p.CreatedAt()),
+		UpdatedAt:      fromNanos(p.UpdatedAt()),
+	}
+	if inv := p.Inventory(nil); inv != nil {
+		product.Inventory = avro.Inventory{
+			Quantity:       inv.Quantity(),
+			Reserved:       inv.Reserved(),
+			Available:      inv.Available(),
+			TrackInventory: inv.TrackInventory(),
+			ReorderLevel:   inv.ReorderLevel(),
+			MaxStock:       inv.MaxStock(),
+		}
+	}
+	return product, nil
+}
+
+func unpackOrder(o *models.Order) (avro.Order, error) {
+	status, err := statusName(orderStatuses, o.Status())
+	if err != nil {
+		return avro.Order{}, err
+	}
+	order := avro.Order{
+		ID:          o.Id(),
+		UUID:        optionalString(o.Uuid()),
+		UserID:      o.UserId(),
+		OrderNumber: string(o.OrderNumber()),
+		Status:      status,
+		CreatedAt:   fromNanos(o.CreatedAt()),
+		UpdatedAt:   fromNanos(o.UpdatedAt()),
+		ShippedAt:   optionalTime(o.ShippedAt()),
+		DeliveredAt: optionalTime(o.DeliveredAt()),
+	}
+
+	if n := o.ItemsLength(); n > 0 {
+		order.Items = make([]avro.OrderItem, n)
+		var item models.OrderItem
+		for i := range order.Items {
+			o.Items(&item, i)
+			if order.Items[i], err = unpackOrderItem(&item); err != nil {
+				return avro.Order{}, fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+	}
+	if s := o.Summary(nil); s != nil {
+		if order.Summary, err = unpackOrderSummary(s); err != nil {
+			return avro.Order{}, err
+		}
+	}
+	if s := o.ShippingInfo(nil); s != nil {
+		if order.ShippingInfo, err = unpackShippingInfo(s); err != nil {
+			return avro.Order{}, err
+		}
+	}
+	if p := o.PaymentInfo(nil); p != nil {
+		if order.PaymentInfo, err = unpackPaymentInfo(p); err != nil {
+			return avro.Order{}, err
+		}
+	}
+	return order, nil
+}
+
+func unpackOrderItem(item *models.OrderItem) (avro.OrderItem, error) {
+	unitPrice, err := unpackPrice(item.UnitPrice(nil))
+	if err != nil {
+		return avro.OrderItem{}, err
+	}
+	totalPrice, err := unpackPrice(item.TotalPrice(nil))
+	if err != nil {
+		return avro.OrderItem{}, err
+	}
+	return avro.OrderItem{
+		ProductID:      item.ProductId(),
+		ProductName:    string(item.ProductName()),
+		ProductSKU:     string(item.ProductSku()),
+		Quantity:       item.Quantity(),
+		UnitPrice:      unitPrice,
+		TotalPrice:     totalPrice,
+		ProductVariant: unpackKeyValues(item.ProductVariantLength(), item.ProductVariant),
+	}, nil
+}
+
+func unpackOrderSummary(s *models.OrderSummary) (avro.OrderSummary, error) {
+	summary := avro.OrderSummary{TotalItems: s.TotalItems()}
+	prices := []struct {
+		dst *avro.Price
+		src *models.Price
+	}{
+		{&summary.Subtotal, s.Subtotal(nil)},
+		{&summary.Tax, s.Tax(nil)},
+		{&summary.ShippingCost, s.ShippingCost(nil)},
+		{&summary.Discount, s.Discount(nil)},
+		{&summary.Total, s.Total(nil)},
+	}
+	for _, p := range prices {
+		price, err := unpackPrice(p.src)
+		if err != nil {
+			return avro.OrderSummary{}, err
+		}
+		*p.dst = price
+	}
+	return summary, nil
+}
+
+func unpackShippingInfo(s *models.ShippingInfo) (*avro.ShippingInfo, error) {
+	cost, err := unpackPrice(s.Cost(nil))
+	if err != nil {
+		return nil, err
+	}
+	info := &avro.ShippingInfo{
+		Method:            string(s.Method()),
+		TrackingNumber:    optionalString(s.TrackingNumber()),
+		Carrier:           optionalString(s.Carrier()),
+		Cost:              cost,
+		EstimatedDelivery: optionalTime(s.EstimatedDelivery()),
+	}
+	if d := s.DeliveryTime(); d != nil {
+		deliveryTime := time.Duration(*d)
+		info.DeliveryTime = &deliveryTime
+	}
+	if a := s.Address(nil); a != nil {
+		info.Address = avro.ShippingAddress{
+			RecipientName: string(a.RecipientName()),
+			Street:        string(a.Street()),
+			City:          string(a.City()),
+			State:         string(a.State()),
+			PostalCode:    string(a.PostalCode()),
+			Country:       string(a.Country()),
+		}
+	}
+	return info, nil
+}
+
+func unpackPaymentInfo(p *models.PaymentInfo) (*avro.PaymentInfo, error) {
+	status, err := statusName(paymentStatuses, p.Status())
+	if err != nil {
+		return nil, err
+	}
+	amount, err := unpackPrice(p.Amount(nil))
+	if err != nil {
+		return nil, err
+	}
+	return &avro.PaymentInfo{
+		Method:        string(p.Method()),
+		Status:        status,
+		TransactionID: optionalString(p.TransactionId()),
+		Amount:        amount,
+		ProcessedAt:   optionalTime(p.ProcessedAt()),
+		AuthorizedAt:  optionalTime(p.AuthorizedAt()),
+	}, nil
+}
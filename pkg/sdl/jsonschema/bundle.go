@@ -0,0 +1,287 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	santhosh "github.com/santhosh-tekuri/jsonschema/v6"
+
+	"go-transport-prac/internal/errors"
+)
+
+// Bundle returns a schema as a standalone document: every schema it
+// references from other resources is copied under its $defs and each $ref is
+// rewritten to a local pointer. The bundle validates like the original
+// without needing the referenced files or $ids
+func (v *SanthoshValidator) Bundle(schemaID string) ([]byte, error) {
+	urls, exists := v.urls[schemaID]
+	if !exists {
+		return nil, errors.NotFoundError(errors.CodeNotFound,
+			fmt.Sprintf("schema not found: %s", schemaID))
+	}
+
+	b := &bundler{
+		sources: v.sources,
+		aliases: make(map[string]string),
+		names:   make(map[string]string),
+		used:    make(map[string]bool),
+	}
+	for _, resourceURLs := range v.urls {
+		for _, alias := range resourceURLs {
+			b.aliases[alias] = resourceURLs[0]
+		}
+	}
+
+	root, err := b.bundle(urls[0], v.sources[urls[0]])
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.ErrorTypeValidation,
+			errors.CodeValidationFailed,
+			"failed to bundle schema %s", schemaID)
+	}
+
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal bundle: %w", err)
+	}
+	return data, nil
+}
+
+// bundler inlines the resources a schema references
+type bundler struct {
+	sources map[string]interface{}
+	// aliases maps every URL of a resource to its location
+	aliases map[string]string
+	// names maps the location of each inlined resource to its $defs name
+	names   map[string]string
+	used    map[string]bool
+	rootURL string
+	defsKey string
+	defs    map[string]interface{}
+	pending []string
+}
+
+// bundle rewrites the root document and inlines what it references
+func (b *bundler) bundle(location string, doc interface{}) (interface{}, error) {
+	b.rootURL = location
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		// Boolean schemas cannot reference anything
+		return doc, nil
+	}
+
+	// Drafts before 2019-09 keep definitions under "definitions"
+	b.defsKey = "$defs"
+	if draft, _ := obj["$schema"].(string); strings.Contains(draft, "draft-0") {
+		b.defsKey = "definitions"
+	}
+	b.defs = make(map[string]interface{})
+	if existing, ok := obj[b.defsKey].(map[string]interface{}); ok {
+		for name := range existing {
+			b.used[name] = true
+		}
+	}
+
+	root, err := b.rewriteResource(location, obj, true)
+	if err != nil {
+		return nil, err
+	}
+
+	for len(b.pending) > 0 {
+		next := b.pending[0]
+		b.pending = b.pending[1:]
+		doc, err := b.load(next)
+		if err != nil {
+			return nil, err
+		}
+		inlined := doc
+		if obj, ok := doc.(map[string]interface{}); ok {
+			if inlined, err = b.rewriteResource(next, obj, false); err != nil {
+				return nil, err
+			}
+		}
+		b.defs[b.names[next]] = inlined
+	}
+
+	if len(b.defs) > 0 {
+		rootObj := root.(map[string]interface{})
+		defs := make(map[string]interface{})
+		if existing, ok := rootObj[b.defsKey].(map[string]interface{}); ok {
+			for name, def := range existing {
+				defs[name] = def
+			}
+		}
+		for name, def := range b.defs {
+			defs[name] = def
+		}
+		rootObj[b.defsKey] = defs
+	}
+	return root, nil
+}
+
+// load returns the document at a resource location, reading files that were
+// referenced but never added
+func (b *bundler) load(location string) (interface{}, error) {
+	if doc, ok := b.sources[location]; ok {
+		return doc, nil
+	}
+	if strings.HasPrefix(location, "file:") {
+		return santhosh.FileLoader{}.Load(location)
+	}
+	return nil, fmt.Errorf("unresolved reference to %s", location)
+}
+
+// rewriteResource copies a resource, with its refs rewritten relative to the
+// bundle root. Inlined resources lose their $id and $schema, which would
+// otherwise change how the rewritten refs resolve
+func (b *bundler) rewriteResource(location string, obj map[string]interface{}, isRoot bool) (interface{}, error) {
+	base := location
+	if schemaID, ok := obj["$id"].(string); ok {
+		resolved, err := resolveURL(location, schemaID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid $id %q: %w", schemaID, err)
+		}
+		base = resolved
+	}
+
+	copied, err := b.rewrite(obj, base, location, true)
+	if err != nil {
+		return nil, err
+	}
+	if !isRoot {
+		delete(copied, "$id")
+		delete(copied, "$schema")
+	}
+	return copied, nil
+}
+
+// rewrite copies a schema object, rewriting $refs resolved against base.
+// location identifies the resource being copied
+func (b *bundler) rewrite(obj map[string]interface{}, base, location string, resourceRoot bool) (map[string]interface{}, error) {
+	if _, ok := obj["$id"].(string); ok && !resourceRoot {
+		return nil, fmt.Errorf("nested $id in %s is not supported", location)
+	}
+
+	copied := make(map[string]interface{}, len(obj))
+	for _, key := range sortedKeys(obj) {
+		value := obj[key]
+		switch key {
+		case "$ref":
+			if ref, ok := value.(string); ok {
+				rewritten, err := b.rewriteRef(ref, base, location)
+				if err != nil {
+					return nil, err
+				}
+				copied[key] = rewritten
+				continue
+			}
+		case "enum", "const", "default", "examples":
+			// Instance values, not schemas
+			copied[key] = value
+			continue
+		case "properties", "patternProperties", "$defs", "definitions", "dependentSchemas", "dependencies":
+			// Schemas by name, where names are not keywords
+			if schemas, ok := value.(map[string]interface{}); ok {
+				rewritten := make(map[string]interface{}, len(schemas))
+				for _, name := range sortedKeys(schemas) {
+					var err error
+					if rewritten[name], err = b.rewriteValue(schemas[name], base, location); err != nil {
+						return nil, err
+					}
+				}
+				copied[key] = rewritten
+				continue
+			}
+		}
+
+		rewritten, err := b.rewriteValue(value, base, location)
+		if err != nil {
+			return nil, err
+		}
+		copied[key] = rewritten
+	}
+	return copied, nil
+}
+
+// rewriteValue copies the schemas nested anywhere in value
+func (b *bundler) rewriteValue(value interface{}, base, location string) (interface{}, error) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return b.rewrite(value, base, location, false)
+	case []interface{}:
+		copied := make([]interface{}, len(value))
+		for i, item := range value {
+			rewritten, err := b.rewriteValue(item, base, location)
+			if err != nil {
+				return nil, err
+			}
+			copied[i] = rewritten
+		}
+		return copied, nil
+	}
+	return value, nil
+}
+
+// rewriteRef turns a reference into a pointer within the bundle
+func (b *bundler) rewriteRef(ref, base, location string) (string, error) {
+	resolved, err := resolveURL(base, ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid $ref %q in %s: %w", ref, location, err)
+	}
+	u, err := url.Parse(resolved)
+	if err != nil {
+		return "", fmt.Errorf("invalid $ref %q in %s: %w", ref, location, err)
+	}
+	fragment := u.EscapedFragment()
+	u.Fragment, u.RawFragment = "", ""
+
+	target := u.String()
+	if target == base {
+		target = location
+	}
+	if alias, ok := b.aliases[target]; ok {
+		target = alias
+	}
+	if target == b.rootURL {
+		return "#" + fragment, nil
+	}
+	if fragment != "" && !strings.HasPrefix(fragment, "/") {
+		return "", fmt.Errorf("$ref %q in %s uses an anchor, which cannot be bundled", ref, location)
+	}
+	return "#/" + b.defsKey + "/" + escapePointer(b.nameOf(target)) + fragment, nil
+}
+
+// nameOf returns the $defs name of an inlined resource, queueing it the first
+// time it is referenced
+func (b *bundler) nameOf(location string) string {
+	if name, ok := b.names[location]; ok {
+		return name
+	}
+
+	stem := path.Base(location)
+	if u, err := url.Parse(location); err == nil && u.Path != "" {
+		stem = path.Base(u.Path)
+	}
+	stem = strings.TrimSuffix(stem, path.Ext(stem))
+	if stem == "" || stem == "." || stem == "/" {
+		stem = "schema"
+	}
+
+	name := stem
+	for i := 2; b.used[name]; i++ {
+		name = fmt.Sprintf("%s_%d", stem, i)
+	}
+	b.used[name] = true
+	b.names[location] = name
+	b.pending = append(b.pending, location)
+	return name
+}
+
+// escapePointer escapes a JSON Pointer token for use in a URI fragment
+func escapePointer(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	token = strings.ReplaceAll(token, "/", "~1")
+	return (&url.URL{Fragment: token}).EscapedFragment()
+}
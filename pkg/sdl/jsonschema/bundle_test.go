@@ -0,0 +1,248 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-transport-prac/internal/testutil"
+)
+
+// writeSchemaDir writes an order schema that references an address by path
+// and money by $id, each with local refs of their own
+func writeSchemaDir(t *testing.T, dir string) {
+	t.Helper()
+	files := map[string]string{
+		"common/address.json": `{
+			"type": "object",
+			"properties": {
+				"city": {"type": "string"},
+				"postcode": {"$ref": "#/$defs/postcode"}
+			},
+			"required": ["city"],
+			"$defs": {"postcode": {"type": "string", "pattern": "^[0-9]{3,5}$"}}
+		}`,
+		"common/money.json": `{
+			"$id": "https://example.com/schemas/money.json",
+			"type": "object",
+			"properties": {
+				"amount": {"type": "integer", "minimum": 0},
+				"currency": {"type": "string", "minLength": 3, "maxLength": 3}
+			},
+			"required": ["amount", "currency"]
+		}`,
+		"customer.json": `{
+			"$id": "https://example.com/schemas/customer.json",
+			"type": "object",
+			"properties": {"credit": {"$ref": "money.json"}}
+		}`,
+		"order.json": `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"properties": {
+				"shipping": {"$ref": "common/address.json"},
+				"city": {"$ref": "common/address.json#/properties/city"},
+				"total": {"$ref": "https://example.com/schemas/money.json"},
+				"lines": {"type": "array", "items": {"$ref": "#/$defs/line"}}
+			},
+			"required": ["shipping", "total"],
+			"$defs": {
+				"line": {
+					"type": "object",
+					"properties": {
+						"quantity": {"type": "integer", "minimum": 1},
+						"price": {"$ref": "common/money.json"}
+					}
+				}
+			}
+		}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+const (
+	validOrder   = `{"shipping": {"city": "Taipei", "postcode": "100"}, "city": "Taipei", "total": {"amount": 300, "currency": "TWD"}, "lines": [{"quantity": 3, "price": {"amount": 100, "currency": "TWD"}}]}`
+	invalidOrder = `{"shipping": {"city": "Taipei", "postcode": "1A"}, "total": {"amount": 300, "currency": "TWD"}, "lines": [{"quantity": 0, "price": {"amount": -1, "currency": "TWD"}}]}`
+)
+
+func TestSanthoshValidator_SchemaDir(t *testing.T) {
+	dir := "tmp/test_schema_dir"
+	defer os.RemoveAll(dir)
+	writeSchemaDir(t, dir)
+
+	helper := testutil.NewTestHelper(t)
+	validator := NewSanthoshValidator(helper.Logger())
+
+	ids, err := validator.AddSchemaDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"common/address.json", "common/money.json", "customer.json", "order.json"}, ids)
+
+	assert.NoError(t, validator.ValidateJSON("order.json", validOrder))
+	result, err := validator.ValidateWithDetails("order.json", json.RawMessage(invalidOrder))
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	var locations []string
+	for _, e := range result.Errors {
+		locations = append(locations, e.InstanceLocation)
+	}
+	assert.Subset(t, locations, []string{"/shipping/postcode", "/lines/0/quantity", "/lines/0/price/amount"})
+
+	assert.NoError(t, validator.ValidateJSON("customer.json", `{"credit": {"amount": 5, "currency": "USD"}}`))
+	helper.AssertError(validator.ValidateJSON("customer.json", `{"credit": {"amount": 5}}`))
+
+	// Schemas added later reference files by $id
+	require.NoError(t, validator.AddSchemaJSON("refund", `{"properties": {"amount": {"$ref": "https://example.com/schemas/money.json"}}}`))
+	helper.AssertError(validator.ValidateJSON("refund", `{"amount": {"amount": "5"}}`))
+
+	// A directory is added only if every schema compiles
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"$ref": "missing.json"}`), 0644))
+	other := NewSanthoshValidator(helper.Logger())
+	_, err = other.AddSchemaDir(dir)
+	helper.AssertError(err)
+	assert.Empty(t, other.ListSchemas())
+
+	_, err = other.AddSchemaDir(filepath.Join(dir, "missing"))
+	helper.AssertError(err)
+
+	t.Log("✓ Schema directories resolve relative and $id references")
+}
+
+func TestSanthoshValidator_SchemaFile(t *testing.T) {
+	dir := "tmp/test_schema_file"
+	defer os.RemoveAll(dir)
+	writeSchemaDir(t, dir)
+
+	helper := testutil.NewTestHelper(t)
+	validator := NewSanthoshValidator(helper.Logger())
+
+	// Money is only reachable by $id once added; the address file is read on demand
+	helper.AssertError(validator.AddSchemaFile("order", filepath.Join(dir, "order.json")))
+	require.NoError(t, validator.AddSchemaFile("money", filepath.Join(dir, "common", "money.json")))
+	require.NoError(t, validator.AddSchemaFile("order", filepath.Join(dir, "order.json")))
+
+	assert.NoError(t, validator.ValidateJSON("order", validOrder))
+	helper.AssertError(validator.ValidateJSON("order", invalidOrder))
+
+	helper.AssertError(validator.AddSchemaFile("missing", filepath.Join(dir, "missing.json")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.json"), []byte(`{"type": `), 0644))
+	helper.AssertError(validator.AddSchemaFile("invalid", filepath.Join(dir, "invalid.json")))
+
+	assert.True(t, validator.RemoveSchema("money"))
+	helper.AssertError(validator.AddSchemaJSON("price", `{"$ref": "https://example.com/schemas/money.json"}`))
+
+	t.Log("✓ Schema files resolve references against their location")
+}
+
+func TestSanthoshValidator_Bundle(t *testing.T) {
+	dir := "tmp/test_schema_bundle"
+	defer os.RemoveAll(dir)
+	writeSchemaDir(t, dir)
+
+	helper := testutil.NewTestHelper(t)
+	validator := NewSanthoshValidator(helper.Logger())
+	_, err := validator.AddSchemaDir(dir)
+	require.NoError(t, err)
+
+	bundle, err := validator.Bundle("order.json")
+	require.NoError(t, err)
+
+	// Every reference is local, and referenced resources are inlined once
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(bundle, &doc))
+	defs := doc["$defs"].(map[string]interface{})
+	assert.ElementsMatch(t, []string{"line", "address", "money"}, keysOf(defs))
+	assert.NotContains(t, defs["money"], "$id")
+	assert.Equal(t, "#/$defs/address/properties/city", doc["properties"].(map[string]interface{})["city"].(map[string]interface{})["$ref"])
+	for _, line := range strings.Split(string(bundle), "\n") {
+		if strings.Contains(line, `"$ref"`) {
+			assert.Contains(t, line, `"#/$defs/`)
+		}
+	}
+
+	// The bundle validates the same way without the files, on either backend
+	require.NoError(t, os.RemoveAll(dir))
+	for _, backend := range []Backend{BackendSanthosh, BackendXeipuuv} {
+		standalone, err := NewValidator(backend, helper.Logger())
+		require.NoError(t, err)
+		require.NoError(t, standalone.AddSchemaJSON("order", string(bundle)), backend)
+		assert.NoError(t, standalone.ValidateJSON("order", validOrder), backend)
+		helper.AssertError(standalone.ValidateJSON("order", invalidOrder))
+	}
+
+	// Bundling is deterministic
+	again, err := validator.Bundle("order.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(bundle), string(again))
+
+	_, err = validator.Bundle("missing")
+	helper.AssertError(err, "schema not found")
+
+	t.Log("✓ Bundles are standalone schema documents")
+}
+
+func TestSanthoshValidator_BundleRecursive(t *testing.T) {
+	dir := "tmp/test_schema_recursive"
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+
+	// Resources that reference each other, and a $defs name the inlined one would take
+	files := map[string]string{
+		"node.json": `{
+			"type": "object",
+			"properties": {
+				"value": {"type": "integer"},
+				"children": {"type": "array", "items": {"$ref": "node.json"}},
+				"meta": {"$ref": "meta.json"}
+			}
+		}`,
+		"meta.json": `{"type": "object", "properties": {"parent": {"$ref": "node.json"}}}`,
+		"tree.json": `{
+			"$defs": {"node": {"type": "null"}},
+			"properties": {"root": {"$ref": "node.json"}}
+		}`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	helper := testutil.NewTestHelper(t)
+	validator := NewSanthoshValidator(helper.Logger())
+	_, err := validator.AddSchemaDir(dir)
+	require.NoError(t, err)
+
+	bundle, err := validator.Bundle("tree.json")
+	require.NoError(t, err)
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(bundle, &doc))
+	assert.ElementsMatch(t, []string{"node", "node_2", "meta"}, keysOf(doc["$defs"].(map[string]interface{})))
+
+	standalone := NewSanthoshValidator(helper.Logger())
+	require.NoError(t, standalone.AddSchemaJSON("tree", string(bundle)))
+	assert.NoError(t, standalone.ValidateJSON("tree", `{"root": {"value": 1, "children": [{"value": 2, "meta": {"parent": {"value": 1}}}]}}`))
+	helper.AssertError(standalone.ValidateJSON("tree", `{"root": {"children": [{"meta": {"parent": {"value": "1"}}}]}}`))
+
+	// Anchors into other resources cannot be rewritten as pointers
+	require.NoError(t, validator.AddSchemaJSON("anchored", `{"$id": "https://example.com/anchored", "$defs": {"a": {"$anchor": "a", "type": "string"}}}`))
+	require.NoError(t, validator.AddSchemaJSON("anchor-user", `{"$ref": "https://example.com/anchored#a"}`))
+	_, err = validator.Bundle("anchor-user")
+	helper.AssertError(err, "anchor")
+
+	t.Log("✓ Recursive references are bundled once")
+}
+
+func keysOf(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,543 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+// The registry shares its compatibility vocabulary with the Avro registry, so
+// reports and errors from both formats can be handled alike
+type (
+	CompatibilityLevel  = avro.CompatibilityLevel
+	CompatibilityReport = avro.CompatibilityReport
+	CompatibilityError  = avro.CompatibilityError
+	Incompatibility     = avro.Incompatibility
+	IncompatibilityType = avro.IncompatibilityType
+)
+
+const (
+	CompatibilityNone               = avro.CompatibilityNone
+	CompatibilityFull               = avro.CompatibilityFull
+	CompatibilityForward            = avro.CompatibilityForward
+	CompatibilityBackward           = avro.CompatibilityBackward
+	CompatibilityFullTransitive     = avro.CompatibilityFullTransitive
+	CompatibilityForwardTransitive  = avro.CompatibilityForwardTransitive
+	CompatibilityBackwardTransitive = avro.CompatibilityBackwardTransitive
+)
+
+// Reasons a reader schema rejects documents a writer schema accepts
+const (
+	IncompatibilityTypeNarrowed                 IncompatibilityType = "TYPE_NARROWED"
+	IncompatibilityEnumNarrowed                 IncompatibilityType = "ENUM_NARROWED"
+	IncompatibilityRangeNarrowed                IncompatibilityType = "RANGE_NARROWED"
+	IncompatibilityPatternChanged               IncompatibilityType = "PATTERN_CHANGED"
+	IncompatibilityFormatChanged                IncompatibilityType = "FORMAT_CHANGED"
+	IncompatibilityRequiredPropertyAdded        IncompatibilityType = "REQUIRED_PROPERTY_ADDED"
+	IncompatibilityPropertyRemoved              IncompatibilityType = "PROPERTY_REMOVED_FROM_CLOSED_CONTENT_MODEL"
+	IncompatibilityPropertyAddedToOpenModel     IncompatibilityType = "PROPERTY_ADDED_TO_OPEN_CONTENT_MODEL"
+	IncompatibilityAdditionalPropertiesNarrowed IncompatibilityType = "ADDITIONAL_PROPERTIES_NARROWED"
+	IncompatibilityCombinedBranchMissing        IncompatibilityType = "COMBINED_TYPE_BRANCH_MISSING"
+	IncompatibilityReferenceChanged             IncompatibilityType = "REFERENCE_CHANGED"
+)
+
+// CheckReaderWriterCompatibility reports every way the reader schema may reject
+// a document the writer schema accepts. Both are decoded JSON Schema
+// documents; local $refs are followed. An empty result means every document
+// valid under the writer is also valid under the reader.
+//
+// Backward compatibility checks the new schema as reader against an old one as
+// writer, so adding a required property is backward-incompatible while adding
+// an optional one to a closed object is not; forward compatibility swaps them
+func CheckReaderWriterCompatibility(reader, writer interface{}) []Incompatibility {
+	checker := &compatibilityChecker{
+		readerRoot: reader,
+		writerRoot: writer,
+		seen:       make(map[string]bool),
+	}
+	checker.check(reader, writer, "$")
+	return checker.incompatibilities
+}
+
+// compatibilityChecker walks a reader/writer schema pair collecting incompatibilities
+type compatibilityChecker struct {
+	readerRoot        interface{}
+	writerRoot        interface{}
+	seen              map[string]bool
+	incompatibilities []Incompatibility
+}
+
+func (c *compatibilityChecker) add(typ IncompatibilityType, path string, reader, writer interface{}, format string, args ...interface{}) {
+	c.incompatibilities = append(c.incompatibilities, Incompatibility{
+		Type:       typ,
+		Path:       path,
+		Message:    fmt.Sprintf(format, args...),
+		ReaderType: describe(reader),
+		WriterType: describe(writer),
+	})
+}
+
+// fork returns a checker for trying one branch of a combined schema
+func (c *compatibilityChecker) fork() *compatibilityChecker {
+	seen := make(map[string]bool, len(c.seen))
+	for k, v := range c.seen {
+		seen[k] = v
+	}
+	return &compatibilityChecker{readerRoot: c.readerRoot, writerRoot: c.writerRoot, seen: seen}
+}
+
+func (c *compatibilityChecker) check(reader, writer interface{}, path string) {
+	reader, readerRef := resolveRef(c.readerRoot, reader)
+	writer, writerRef := resolveRef(c.writerRoot, writer)
+	if isRef(reader) || isRef(writer) {
+		// Remote references can only be compared by name
+		if readerRef != writerRef {
+			c.add(IncompatibilityReferenceChanged, path, reader, writer,
+				"reader references %s, writer %s", valueOrNone(readerRef), valueOrNone(writerRef))
+		}
+		return
+	}
+	if readerRef != "" || writerRef != "" {
+		// Recursive schemas are compared once per pair of definitions
+		key := fmt.Sprintf("%x|%x", identity(reader), identity(writer))
+		if c.seen[key] {
+			return
+		}
+		c.seen[key] = true
+	}
+
+	if writer == false || acceptsAll(reader) {
+		return
+	}
+	if reader == false {
+		c.add(IncompatibilityTypeNarrowed, path, reader, writer, "reader accepts no documents")
+		return
+	}
+	r, _ := reader.(map[string]interface{})
+	w, _ := writer.(map[string]interface{})
+	if w == nil {
+		w = map[string]interface{}{}
+	}
+
+	// Every branch the writer allows must be readable, and the reader must
+	// accept the writer through at least one of its branches
+	if branches := combined(w); branches != nil {
+		for _, branch := range branches {
+			c.check(r, mergeBranch(w, branch), path)
+		}
+		return
+	}
+	if branches := combined(r); branches != nil {
+		for _, branch := range branches {
+			attempt := c.fork()
+			attempt.check(mergeBranch(r, branch), w, path)
+			if len(attempt.incompatibilities) == 0 {
+				return
+			}
+		}
+		c.add(IncompatibilityCombinedBranchMissing, path, r, w, "no branch of the reader accepts the writer")
+		return
+	}
+	if all, ok := r["allOf"].([]interface{}); ok {
+		for _, member := range all {
+			c.check(member, w, path)
+		}
+	}
+
+	c.checkTypes(r, w, path)
+	c.checkEnum(r, w, path)
+	c.checkBounds(r, w, path)
+	for _, keyword := range []string{"pattern", "format"} {
+		if rv, ok := r[keyword]; ok && rv != w[keyword] {
+			typ := IncompatibilityPatternChanged
+			if keyword == "format" {
+				typ = IncompatibilityFormatChanged
+			}
+			c.add(typ, path, r, w, "reader requires %s %v, writer %v", keyword, rv, valueOrNone(w[keyword]))
+		}
+	}
+	if writerAdmits(w, "object") {
+		c.checkObject(r, w, path)
+	}
+	if items, ok := r["items"]; ok && writerAdmits(w, "array") {
+		writerItems, ok := w["items"]
+		if !ok {
+			writerItems = true
+		}
+		c.check(items, writerItems, path+"[]")
+	}
+}
+
+// checkTypes requires every writer type to be a reader type; integers are numbers
+func (c *compatibilityChecker) checkTypes(r, w map[string]interface{}, path string) {
+	readerTypes := typeSet(r)
+	if readerTypes == nil {
+		return
+	}
+	writerTypes := typeSet(w)
+	if writerTypes == nil {
+		c.add(IncompatibilityTypeNarrowed, path, r, w, "writer allows any type, reader only %s", describe(r))
+		return
+	}
+	for _, t := range sortedKeys(writerTypes) {
+		if !readerTypes[t] && !(t == "integer" && readerTypes["number"]) {
+			c.add(IncompatibilityTypeNarrowed, path, r, w, "reader does not accept %s", t)
+		}
+	}
+}
+
+// checkEnum requires every writer value to be a reader value
+func (c *compatibilityChecker) checkEnum(r, w map[string]interface{}, path string) {
+	readerValues := enumValues(r)
+	if readerValues == nil {
+		return
+	}
+	writerValues := enumValues(w)
+	if writerValues == nil {
+		c.add(IncompatibilityEnumNarrowed, path, r, w, "reader restricts values to %s", jsonString(readerValues))
+		return
+	}
+	var missing []interface{}
+	for _, v := range writerValues {
+		if !containsValue(readerValues, v) {
+			missing = append(missing, v)
+		}
+	}
+	if len(missing) > 0 {
+		c.add(IncompatibilityEnumNarrowed, path, r, w, "reader does not accept %s", jsonString(missing))
+	}
+}
+
+// Lower and upper bound keywords; the reader may not tighten any of them
+var (
+	lowerBounds = []string{"minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties"}
+	upperBounds = []string{"maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties"}
+)
+
+func (c *compatibilityChecker) checkBounds(r, w map[string]interface{}, path string) {
+	for _, keyword := range lowerBounds {
+		rv, ok := number(r[keyword])
+		if !ok {
+			continue
+		}
+		if wv, ok := number(w[keyword]); !ok || wv < rv {
+			c.add(IncompatibilityRangeNarrowed, path, r, w, "reader requires %s %v, writer %v", keyword, rv, valueOrNone(w[keyword]))
+		}
+	}
+	for _, keyword := range upperBounds {
+		rv, ok := number(r[keyword])
+		if !ok {
+			continue
+		}
+		if wv, ok := number(w[keyword]); !ok || wv > rv {
+			c.add(IncompatibilityRangeNarrowed, path, r, w, "reader requires %s %v, writer %v", keyword, rv, valueOrNone(w[keyword]))
+		}
+	}
+}
+
+// checkObject compares required, declared and additional properties
+func (c *compatibilityChecker) checkObject(r, w map[string]interface{}, path string) {
+	writerRequired := stringSet(w["required"])
+	for _, name := range stringList(r["required"]) {
+		if !writerRequired[name] {
+			c.add(IncompatibilityRequiredPropertyAdded, path+"."+name, r, w, "reader requires %s, which the writer may omit", name)
+		}
+	}
+
+	readerProps, _ := r["properties"].(map[string]interface{})
+	writerProps, _ := w["properties"].(map[string]interface{})
+	readerExtra := additionalProperties(r)
+	writerExtra := additionalProperties(w)
+
+	for _, name := range sortedKeys(writerProps) {
+		if readerProp, ok := readerProps[name]; ok {
+			c.check(readerProp, writerProps[name], path+"."+name)
+			continue
+		}
+		if readerExtra == false {
+			c.add(IncompatibilityPropertyRemoved, path+"."+name, r, w, "reader does not allow %s", name)
+			continue
+		}
+		c.check(readerExtra, writerProps[name], path+"."+name)
+	}
+
+	for _, name := range sortedKeys(readerProps) {
+		if _, ok := writerProps[name]; ok || writerExtra == false {
+			continue
+		}
+		if writerExtra == true {
+			if !acceptsAll(readerProps[name]) {
+				c.add(IncompatibilityPropertyAddedToOpenModel, path+"."+name, r, w,
+					"reader constrains %s, which the writer allows as any additional property", name)
+			}
+			continue
+		}
+		c.check(readerProps[name], writerExtra, path+"."+name)
+	}
+
+	switch {
+	case readerExtra == true || writerExtra == false:
+	case readerExtra == false:
+		c.add(IncompatibilityAdditionalPropertiesNarrowed, path, r, w, "reader rejects properties the writer allows")
+	default:
+		c.check(readerExtra, writerExtra, path+".*")
+	}
+}
+
+// resolveRef follows local $refs, returning the target and the reference
+// followed. Remote references are left in place
+func resolveRef(root, schema interface{}) (interface{}, string) {
+	var followed string
+	for depth := 0; depth < 32; depth++ {
+		obj, ok := schema.(map[string]interface{})
+		if !ok {
+			return schema, followed
+		}
+		ref, ok := obj["$ref"].(string)
+		if !ok {
+			return schema, followed
+		}
+		target, ok := pointer(root, ref)
+		if !ok {
+			return schema, ref
+		}
+		schema, followed = target, ref
+	}
+	return schema, followed
+}
+
+// isRef reports whether a schema is an unresolved $ref
+func isRef(schema interface{}) bool {
+	obj, ok := schema.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, ok = obj["$ref"]
+	return ok
+}
+
+// identity distinguishes decoded schema objects
+func identity(schema interface{}) uintptr {
+	if obj, ok := schema.(map[string]interface{}); ok {
+		return reflect.ValueOf(obj).Pointer()
+	}
+	return 0
+}
+
+// pointer evaluates a "#/..." JSON pointer within root
+func pointer(root interface{}, ref string) (interface{}, bool) {
+	if ref == "#" {
+		return root, true
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, false
+	}
+	current := root
+	for _, token := range strings.Split(ref[2:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = obj[token]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// annotations do not constrain documents
+var annotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "$defs": true, "definitions": true,
+	"title": true, "description": true, "default": true, "examples": true,
+	"readOnly": true, "writeOnly": true, "deprecated": true,
+}
+
+// acceptsAll reports whether a schema accepts every document
+func acceptsAll(schema interface{}) bool {
+	if schema == true {
+		return true
+	}
+	obj, ok := schema.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for keyword := range obj {
+		if !annotations[keyword] {
+			return false
+		}
+	}
+	return true
+}
+
+// combined returns the branches of an anyOf or oneOf
+func combined(schema map[string]interface{}) []interface{} {
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		if branches, ok := schema[keyword].([]interface{}); ok {
+			return branches
+		}
+	}
+	return nil
+}
+
+// mergeBranch combines a branch with the keywords beside its anyOf or oneOf
+func mergeBranch(base map[string]interface{}, branch interface{}) interface{} {
+	obj, ok := branch.(map[string]interface{})
+	if !ok {
+		return branch
+	}
+	merged := make(map[string]interface{}, len(base)+len(obj))
+	for k, v := range base {
+		if k != "anyOf" && k != "oneOf" {
+			merged[k] = v
+		}
+	}
+	if _, ok := obj["$ref"]; ok && len(merged) > 0 && !acceptsAll(merged) {
+		// Keywords beside a $ref apply alongside it; keep the reference intact
+		return map[string]interface{}{"allOf": []interface{}{merged, obj}}
+	}
+	for k, v := range obj {
+		merged[k] = v
+	}
+	return merged
+}
+
+// additionalProperties returns true, false or the schema of undeclared properties
+func additionalProperties(schema map[string]interface{}) interface{} {
+	extra, ok := schema["additionalProperties"]
+	if !ok || acceptsAll(extra) {
+		return true
+	}
+	return extra
+}
+
+// typeSet returns the types a schema allows, or nil for any type
+func typeSet(schema map[string]interface{}) map[string]bool {
+	switch t := schema["type"].(type) {
+	case string:
+		return map[string]bool{t: true}
+	case []interface{}:
+		set := make(map[string]bool, len(t))
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				set[s] = true
+			}
+		}
+		return set
+	}
+	return nil
+}
+
+// writerAdmits reports whether a writer may produce values of type t
+func writerAdmits(schema map[string]interface{}, t string) bool {
+	types := typeSet(schema)
+	return types == nil || types[t]
+}
+
+// enumValues returns the values an enum or const allows, or nil
+func enumValues(schema map[string]interface{}) []interface{} {
+	if values, ok := schema["enum"].([]interface{}); ok {
+		return values
+	}
+	if value, ok := schema["const"]; ok {
+		return []interface{}{value}
+	}
+	return nil
+}
+
+func containsValue(values []interface{}, v interface{}) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(normalizeNumber(candidate), normalizeNumber(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeNumber makes json.Number and float64 values comparable
+func normalizeNumber(v interface{}) interface{} {
+	if n, ok := number(v); ok {
+		return n
+	}
+	return v
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func stringList(v interface{}) []string {
+	list, _ := v.([]interface{})
+	strs := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
+func stringSet(v interface{}) map[string]bool {
+	set := make(map[string]bool)
+	for _, s := range stringList(v) {
+		set[s] = true
+	}
+	return set
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func valueOrNone(v interface{}) interface{} {
+	if v == nil || v == "" {
+		return "none"
+	}
+	return v
+}
+
+func jsonString(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// describe summarizes a schema for incompatibility reports
+func describe(schema interface{}) string {
+	switch s := schema.(type) {
+	case bool:
+		if s {
+			return "any"
+		}
+		return "none"
+	case map[string]interface{}:
+		if ref, ok := s["$ref"].(string); ok {
+			return ref
+		}
+		if values := enumValues(s); values != nil {
+			return "enum"
+		}
+		if types := typeSet(s); types != nil {
+			return strings.Join(sortedKeys(types), "|")
+		}
+		if combined(s) != nil {
+			return "union"
+		}
+	}
+	return "any"
+}
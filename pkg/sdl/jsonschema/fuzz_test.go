@@ -0,0 +1,45 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+func FuzzValidateJSON(f *testing.F) {
+	log, err := logger.NewDevelopment()
+	if err != nil {
+		f.Fatalf("Failed to create logger: %v", err)
+	}
+	validator := NewSanthoshValidator(log)
+	schema, err := NewSchemaGenerator().GenerateJSON([]avro.User{})
+	if err != nil {
+		f.Fatalf("Failed to generate schema: %v", err)
+	}
+	if err := validator.AddSchemaJSON("users", string(schema)); err != nil {
+		f.Fatalf("Failed to add schema: %v", err)
+	}
+
+	manager, err := avro.NewManager("")
+	if err != nil {
+		f.Fatalf("Failed to create manager: %v", err)
+	}
+	for _, count := range []int{0, 1, 3} {
+		data, err := json.Marshal(manager.CreateSampleUsers(count))
+		if err != nil {
+			f.Fatalf("Failed to marshal seed: %v", err)
+		}
+		f.Add(string(data))
+		f.Add(string(data[:len(data)/2]))
+	}
+	f.Add(`[{"id": "1", "email": 42}]`)
+	f.Add(`null`)
+
+	f.Fuzz(func(t *testing.T, input string) {
+		if err := validator.ValidateJSON("users", input); err == nil && !json.Valid([]byte(input)) {
+			t.Errorf("Accepted invalid JSON %q", input)
+		}
+	})
+}
@@ -0,0 +1,481 @@
+package jsonschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Draft202012 is the meta-schema URI of generated schemas
+const Draft202012 = "https://json-schema.org/draft/2020-12/schema"
+
+// Enum is implemented by types whose values are limited to a fixed set, such
+// as the status types of the SDL models
+type Enum interface {
+	EnumValues() []string
+}
+
+// Schema is a JSON Schema document or subschema
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 Types              `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Types is the "type" keyword; a single type is written as a string
+type Types []string
+
+// MarshalJSON writes one type as a string and several as an array
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON reads the type keyword in either form
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	enumType          = reflect.TypeOf((*Enum)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// SchemaGenerator builds JSON Schemas from Go types the way encoding/json
+// serializes them. Named structs and enums become $defs; fields are required
+// unless they are pointers or tagged omitempty, and pointers, slices and maps
+// may be null. A `jsonschema` struct tag adds constraints, e.g.
+// `jsonschema:"format=email,minLength=1"`
+type SchemaGenerator struct {
+	id          string
+	title       string
+	description string
+	additional  bool
+
+	defs  map[string]*Schema
+	names map[reflect.Type]string
+}
+
+// NewSchemaGenerator creates a generator that rejects unknown properties
+func NewSchemaGenerator() *SchemaGenerator {
+	return &SchemaGenerator{}
+}
+
+// WithID sets the $id of generated schemas
+func (g *SchemaGenerator) WithID(id string) *SchemaGenerator {
+	g.id = id
+	return g
+}
+
+// WithTitle sets the title of generated schemas; it defaults to the type name
+func (g *SchemaGenerator) WithTitle(title string) *SchemaGenerator {
+	g.title = title
+	return g
+}
+
+// WithDescription sets the description of generated schemas
+func (g *SchemaGenerator) WithDescription(description string) *SchemaGenerator {
+	g.description = description
+	return g
+}
+
+// WithAdditionalProperties allows objects to carry properties their struct
+// does not declare
+func (g *SchemaGenerator) WithAdditionalProperties(allow bool) *SchemaGenerator {
+	g.additional = allow
+	return g
+}
+
+// Generate returns the schema of v's type. A struct is described at the root
+// with the types it references under $defs
+func (g *SchemaGenerator) Generate(v interface{}) (*Schema, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, fmt.Errorf("cannot generate a schema for nil")
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	g.defs = make(map[string]*Schema)
+	g.names = make(map[reflect.Type]string)
+
+	var root *Schema
+	var err error
+	if t.Kind() == reflect.Struct && t != timeType {
+		root, err = g.structSchema(t)
+	} else {
+		root, err = g.schemaOf(t)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	root.Schema = Draft202012
+	root.ID = g.id
+	root.Title = g.title
+	if root.Title == "" {
+		root.Title = t.Name()
+	}
+	root.Description = g.description
+	if len(g.defs) > 0 {
+		root.Defs = g.defs
+	}
+	return root, nil
+}
+
+// GenerateJSON returns the schema of v's type as indented JSON
+func (g *SchemaGenerator) GenerateJSON(v interface{}) ([]byte, error) {
+	schema, err := g.Generate(v)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	return data, nil
+}
+
+// schemaOf describes a type in a property, item or map value position
+func (g *SchemaGenerator) schemaOf(t reflect.Type) (*Schema, error) {
+	switch {
+	case t.Kind() != reflect.Ptr && (t.Implements(enumType) || reflect.PointerTo(t).Implements(enumType)):
+		return g.ref(t, func() (*Schema, error) { return enumSchema(t), nil })
+	case t == timeType:
+		return &Schema{Type: Types{"string"}, Format: "date-time"}, nil
+	case t.Kind() != reflect.Ptr && (t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType)):
+		// Custom JSON may take any shape
+		return &Schema{}, nil
+	case t.Kind() != reflect.Ptr && (t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)):
+		return &Schema{Type: Types{"string"}}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: Types{"integer"}}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: Types{"integer"}, Minimum: float(0)}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}, nil
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Ptr:
+		elem, err := g.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return nullable(elem), nil
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return nullable(&Schema{Type: Types{"string"}, ContentEncoding: "base64"}), nil
+		}
+		items, err := g.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		array := &Schema{Type: Types{"array"}, Items: items}
+		if t.Kind() == reflect.Slice {
+			return nullable(array), nil
+		}
+		return array, nil
+	case reflect.Map:
+		if key := t.Key().Kind(); key != reflect.String && !(key >= reflect.Int && key <= reflect.Uint64) &&
+			!t.Key().Implements(textMarshalerType) {
+			return nil, fmt.Errorf("cannot generate a schema for %s: unsupported map key", t)
+		}
+		values, err := g.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return nullable(&Schema{Type: Types{"object"}, AdditionalProperties: values}), nil
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return g.ref(t, func() (*Schema, error) { return g.structSchema(t) })
+	}
+	return nil, fmt.Errorf("cannot generate a schema for %s", t)
+}
+
+// ref returns a $ref to the named type's definition, building it on first use.
+// The name is reserved before building so recursive types terminate
+func (g *SchemaGenerator) ref(t reflect.Type, build func() (*Schema, error)) (*Schema, error) {
+	if name, ok := g.names[t]; ok {
+		return &Schema{Ref: defRef(name)}, nil
+	}
+
+	name := t.Name()
+	if _, taken := g.defs[name]; taken {
+		// Same-named types from different packages are qualified, e.g. parquet.Price
+		name = t.String()
+	}
+	g.names[t] = name
+	g.defs[name] = nil
+
+	def, err := build()
+	if err != nil {
+		return nil, err
+	}
+	g.defs[name] = def
+	return &Schema{Ref: defRef(name)}, nil
+}
+
+// defRef is the JSON pointer to a definition
+func defRef(name string) string {
+	return "#/$defs/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
+
+// enumSchema lists the values of an Enum type
+func enumSchema(t reflect.Type) *Schema {
+	var values []string
+	if t.Implements(enumType) {
+		values = reflect.Zero(t).Interface().(Enum).EnumValues()
+	} else {
+		values = reflect.New(t).Interface().(Enum).EnumValues()
+	}
+	schema := &Schema{Enum: values}
+	if t.Kind() == reflect.String {
+		schema.Type = Types{"string"}
+	}
+	return schema
+}
+
+// field is a struct field as encoding/json sees it
+type field struct {
+	name     string
+	depth    int
+	schema   *Schema
+	required bool
+}
+
+// structSchema describes a struct's exported fields, flattening embedded
+// structs as encoding/json does
+func (g *SchemaGenerator) structSchema(t reflect.Type) (*Schema, error) {
+	var fields []field
+	if err := g.collectFields(t, 0, &fields); err != nil {
+		return nil, err
+	}
+
+	schema := &Schema{Type: Types{"object"}, Properties: make(map[string]*Schema, len(fields))}
+	for _, f := range fields {
+		schema.Properties[f.name] = f.schema
+		if f.required {
+			schema.Required = append(schema.Required, f.name)
+		}
+	}
+	if !g.additional {
+		schema.AdditionalProperties = false
+	}
+	return schema, nil
+}
+
+// collectFields appends the fields of t, keeping the shallowest of any
+// fields that share a name
+func (g *SchemaGenerator) collectFields(t reflect.Type, depth int, fields *[]field) error {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		ft := sf.Type
+		if sf.Anonymous && name == "" {
+			embedded := ft
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := g.collectFields(embedded, depth+1, fields); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		schema, err := g.schemaOf(ft)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), sf.Name, err)
+		}
+		if hasOption(opts, "string") {
+			schema = quoted(ft, schema)
+		}
+		if tag, ok := sf.Tag.Lookup("jsonschema"); ok {
+			if schema, err = constrain(schema, tag); err != nil {
+				return fmt.Errorf("%s.%s: %w", t.Name(), sf.Name, err)
+			}
+		}
+
+		f := field{
+			name:     name,
+			depth:    depth,
+			schema:   schema,
+			required: ft.Kind() != reflect.Ptr && !hasOption(opts, "omitempty"),
+		}
+		if !replaceField(fields, f) {
+			*fields = append(*fields, f)
+		}
+	}
+	return nil
+}
+
+// replaceField overwrites a deeper field of the same name, reporting whether
+// a field of that name was already present
+func replaceField(fields *[]field, f field) bool {
+	for i, existing := range *fields {
+		if existing.name == f.name {
+			if f.depth < existing.depth {
+				(*fields)[i] = f
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// hasOption reports whether a json tag's options include opt
+func hasOption(opts, opt string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// quoted applies the ",string" option, which encodes scalars as JSON strings
+func quoted(t reflect.Type, schema *Schema) *Schema {
+	nullable := t.Kind() == reflect.Ptr
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String:
+		if nullable {
+			return &Schema{Type: Types{"string", "null"}}
+		}
+		return &Schema{Type: Types{"string"}}
+	}
+	return schema
+}
+
+// constrain applies a `jsonschema` struct tag such as "format=email,minimum=1".
+// Constraints on a nullable field apply to its non-null value
+func constrain(schema *Schema, tag string) (*Schema, error) {
+	target := schema
+	if len(schema.AnyOf) == 2 {
+		// nullable() wraps references and enums; copy so shared $defs stay untouched
+		inner := *schema.AnyOf[0]
+		target = &inner
+		schema = &Schema{AnyOf: []*Schema{target, schema.AnyOf[1]}}
+	}
+
+	for _, item := range strings.Split(tag, ",") {
+		key, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid jsonschema tag %q: expected key=value", item)
+		}
+		var err error
+		switch key {
+		case "format":
+			target.Format = value
+		case "pattern":
+			target.Pattern = value
+		case "description":
+			target.Description = value
+		case "minimum":
+			target.Minimum, err = parseFloat(value)
+		case "maximum":
+			target.Maximum, err = parseFloat(value)
+		case "minLength":
+			target.MinLength, err = parseInt(value)
+		case "maxLength":
+			target.MaxLength, err = parseInt(value)
+		default:
+			return nil, fmt.Errorf("unknown jsonschema tag key %q", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid jsonschema %s %q: %w", key, value, err)
+		}
+	}
+	return schema, nil
+}
+
+// nullable also accepts null, which encoding/json writes for nil pointers,
+// slices and maps
+func nullable(schema *Schema) *Schema {
+	switch {
+	case schema.Ref != "" || schema.Enum != nil:
+		return &Schema{AnyOf: []*Schema{schema, {Type: Types{"null"}}}}
+	case len(schema.Type) == 0:
+		// The empty schema already accepts null
+		return schema
+	}
+	copied := *schema
+	copied.Type = append(append(Types{}, schema.Type...), "null")
+	return &copied
+}
+
+func float(v float64) *float64 {
+	return &v
+}
+
+func parseFloat(s string) (*float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func parseInt(s string) (*int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
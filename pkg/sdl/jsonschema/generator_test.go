@@ -0,0 +1,171 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+func TestSchemaGenerator_User(t *testing.T) {
+	helper := testutil.NewTestHelper(t)
+
+	schema, err := NewSchemaGenerator().Generate(avro.User{})
+	require.NoError(t, err)
+
+	assert.Equal(t, Draft202012, schema.Schema)
+	assert.Equal(t, "User", schema.Title)
+	assert.Equal(t, Types{"object"}, schema.Type)
+	assert.Equal(t, false, schema.AdditionalProperties)
+
+	// Pointers are optional and nullable; everything else is required
+	assert.Equal(t, []string{"id", "email", "name", "status", "createdAt", "updatedAt"}, schema.Required)
+	assert.Equal(t, "#/$defs/Profile", schema.Properties["profile"].AnyOf[0].Ref)
+	assert.Equal(t, Types{"null"}, schema.Properties["profile"].AnyOf[1].Type)
+	assert.Equal(t, Types{"string", "null"}, schema.Defs["Profile"].Properties["phone"].Type)
+
+	// Enums and jsonschema tags
+	assert.Equal(t, "#/$defs/UserStatus", schema.Properties["status"].Ref)
+	assert.Equal(t, avro.UserStatus("").EnumValues(), schema.Defs["UserStatus"].Enum)
+	assert.Equal(t, "email", schema.Properties["email"].Format)
+	assert.Equal(t, 1.0, *schema.Properties["id"].Minimum)
+	assert.Equal(t, "date-time", schema.Properties["createdAt"].Format)
+
+	// Sample users validate; a bad enum value and an unknown property do not
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	validator := NewXeipuuvValidator(helper.Logger())
+	require.NoError(t, validator.AddSchemaJSON("user", string(data)))
+
+	manager, err := avro.NewManager("")
+	require.NoError(t, err)
+	for _, u := range manager.CreateSampleUsers(5) {
+		encoded, _ := json.Marshal(u)
+		assert.NoError(t, validator.ValidateJSON("user", string(encoded)))
+	}
+	helper.AssertError(validator.ValidateJSON("user",
+		`{"id": 1, "email": "a@example.com", "name": "A", "status": "BANNED", "createdAt": "2024-01-01T00:00:00Z", "updatedAt": "2024-01-01T00:00:00Z"}`))
+	helper.AssertError(validator.ValidateJSON("user",
+		`{"id": 1, "email": "a@example.com", "name": "A", "status": "ACTIVE", "createdAt": "2024-01-01T00:00:00Z", "updatedAt": "2024-01-01T00:00:00Z", "role": "admin"}`))
+
+	t.Log("✓ User schema generated and enforced")
+}
+
+func TestSchemaGenerator_ProductAndOrder(t *testing.T) {
+	helper := testutil.NewTestHelper(t)
+
+	product, err := NewSchemaGenerator().GenerateJSON(avro.Product{})
+	require.NoError(t, err)
+	validator := NewXeipuuvValidator(helper.Logger())
+	require.NoError(t, validator.AddSchemaJSON("product", string(product)))
+
+	manager, err := avro.NewManager("")
+	require.NoError(t, err)
+	for _, p := range manager.CreateSampleProducts(5) {
+		assert.NoError(t, validator.ValidateData("product", p))
+	}
+
+	order, err := NewSchemaGenerator().Generate(avro.Order{})
+	require.NoError(t, err)
+	for _, name := range []string{"OrderStatus", "PaymentStatus", "OrderItem", "OrderSummary", "Price", "ShippingInfo", "ShippingAddress", "PaymentInfo"} {
+		assert.Contains(t, order.Defs, name)
+	}
+	assert.Equal(t, "#/$defs/OrderItem", order.Properties["items"].Items.Ref)
+	assert.Equal(t, Types{"string", "null"}, order.Properties["shippedAt"].Type)
+	assert.NotContains(t, order.Required, "shippedAt")
+
+	t.Log("✓ Product and order schemas generated")
+}
+
+type node struct {
+	Name     string  `json:"name"`
+	Children []*node `json:"children,omitempty"`
+}
+
+type audit struct {
+	CreatedBy string `json:"createdBy"`
+	Revision  int    `json:"revision"`
+}
+
+type document struct {
+	audit
+	Revision int64           `json:"revision,string"`
+	Title    string          `json:"title" jsonschema:"minLength=1,maxLength=80,pattern=^[A-Z]"`
+	Body     []byte          `json:"body"`
+	Size     uint32          `json:"size"`
+	Scores   [3]float64      `json:"scores"`
+	Extra    interface{}     `json:"extra,omitempty"`
+	Labels   map[int]string  `json:"labels"`
+	Tree     node            `json:"tree"`
+	Raw      json.RawMessage `json:"raw"`
+	Expires  *time.Time      `json:"expires"`
+	Internal string          `json:"-"`
+	Untagged bool
+	secret   string
+}
+
+func TestSchemaGenerator_FollowsEncodingJSON(t *testing.T) {
+	schema, err := NewSchemaGenerator().WithAdditionalProperties(true).Generate(&document{})
+	require.NoError(t, err)
+
+	props := schema.Properties
+	assert.Nil(t, schema.AdditionalProperties)
+	assert.Len(t, props, 12)
+	assert.Contains(t, props, "createdBy", "embedded fields are flattened")
+	assert.Equal(t, Types{"string"}, props["revision"].Type, "the outer ,string field wins")
+	assert.Contains(t, props, "Untagged")
+	assert.NotContains(t, props, "Internal")
+	assert.NotContains(t, props, "secret")
+
+	assert.Equal(t, "base64", props["body"].ContentEncoding)
+	assert.Equal(t, 0.0, *props["size"].Minimum)
+	assert.Equal(t, Types{"array"}, props["scores"].Type)
+	assert.Equal(t, &Schema{}, props["extra"])
+	assert.Equal(t, &Schema{}, props["raw"])
+	assert.Equal(t, Types{"object", "null"}, props["labels"].Type)
+	assert.Equal(t, 1, *props["title"].MinLength)
+	assert.Equal(t, 80, *props["title"].MaxLength)
+	assert.Equal(t, "^[A-Z]", props["title"].Pattern)
+	assert.NotContains(t, schema.Required, "extra")
+	assert.NotContains(t, schema.Required, "expires")
+
+	// Recursive types reference their own definition
+	assert.Equal(t, "#/$defs/node", props["tree"].Ref)
+	assert.Equal(t, "#/$defs/node", schema.Defs["node"].Properties["children"].Items.AnyOf[0].Ref)
+
+	// The generated document round-trips through encoding/json
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	var decoded Schema
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, Types{"string", "null"}, decoded.Properties["expires"].Type)
+
+	t.Log("✓ Generated schemas follow encoding/json")
+}
+
+func TestSchemaGenerator_Errors(t *testing.T) {
+	_, err := NewSchemaGenerator().Generate(nil)
+	assert.Error(t, err)
+
+	_, err = NewSchemaGenerator().Generate(struct {
+		Updates chan int `json:"updates"`
+	}{})
+	assert.Error(t, err)
+
+	_, err = NewSchemaGenerator().Generate(struct {
+		Name string `json:"name" jsonschema:"minLength=short"`
+	}{})
+	assert.Error(t, err)
+
+	_, err = NewSchemaGenerator().Generate(struct {
+		Name string `json:"name" jsonschema:"color=blue"`
+	}{})
+	assert.Error(t, err)
+
+	t.Log("✓ Unsupported types and invalid tags are rejected")
+}
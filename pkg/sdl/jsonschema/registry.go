@@ -0,0 +1,302 @@
+package jsonschema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	santhosh "github.com/santhosh-tekuri/jsonschema/v6"
+
+	"go-transport-prac/internal/types"
+)
+
+// SchemaRegistry manages versioned JSON Schemas by subject, with the same
+// compatibility levels and semantics as the Avro SchemaRegistry
+type SchemaRegistry struct {
+	mu                  sync.RWMutex
+	schemas             map[int]SchemaMetadata
+	subjectSchemas      map[string][]int
+	nextSchemaID        int
+	compatibilityLevels map[string]CompatibilityLevel
+	clock               types.Clock
+}
+
+// SchemaMetadata contains metadata about a registered schema
+type SchemaMetadata struct {
+	ID      int    `json:"id"`
+	Version int    `json:"version"`
+	Subject string `json:"subject"`
+	// Schema is the decoded schema document
+	Schema      interface{} `json:"-"`
+	SchemaJSON  string      `json:"schema"`
+	CreatedAt   time.Time   `json:"createdAt"`
+	Fingerprint string      `json:"fingerprint"`
+}
+
+// NewSchemaRegistry creates a new schema registry
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		schemas:             make(map[int]SchemaMetadata),
+		subjectSchemas:      make(map[string][]int),
+		nextSchemaID:        1,
+		compatibilityLevels: make(map[string]CompatibilityLevel),
+		clock:               types.SystemClock{},
+	}
+}
+
+// WithClock sets the clock used for schema CreatedAt timestamps
+func (sr *SchemaRegistry) WithClock(clock types.Clock) *SchemaRegistry {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	sr.clock = types.ClockOrSystem(clock)
+	return sr
+}
+
+// RegisterSchema registers a new schema or returns existing schema ID
+func (sr *SchemaRegistry) RegisterSchema(subject string, schemaJSON string) (int, error) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	schema, err := parseSchema(schemaJSON)
+	if err != nil {
+		return 0, err
+	}
+
+	// Fingerprint the canonical form so formatting differences don't create new versions
+	fingerprint := schemaFingerprint(schema)
+
+	// Check if schema already exists for this subject
+	if schemaIDs, exists := sr.subjectSchemas[subject]; exists {
+		for _, id := range schemaIDs {
+			if sr.schemas[id].Fingerprint == fingerprint {
+				return id, nil // Schema already registered
+			}
+		}
+	}
+
+	// Check compatibility with existing schemas
+	if report := sr.checkCompatibility(subject, schema); !report.Compatible {
+		return 0, fmt.Errorf("schema compatibility check failed: %w", &CompatibilityError{Report: report})
+	}
+
+	schemaID := sr.nextSchemaID
+	sr.nextSchemaID++
+
+	metadata := SchemaMetadata{
+		ID:          schemaID,
+		Version:     len(sr.subjectSchemas[subject]) + 1,
+		Subject:     subject,
+		Schema:      schema,
+		SchemaJSON:  schemaJSON,
+		CreatedAt:   sr.clock.Now(),
+		Fingerprint: fingerprint,
+	}
+
+	sr.schemas[schemaID] = metadata
+	sr.subjectSchemas[subject] = append(sr.subjectSchemas[subject], schemaID)
+
+	return schemaID, nil
+}
+
+// parseSchema decodes a schema and checks it against its meta-schema
+func parseSchema(schemaJSON string) (interface{}, error) {
+	schema, err := santhosh.UnmarshalJSON(strings.NewReader(schemaJSON))
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	compiler := santhosh.NewCompiler()
+	compiler.DefaultDraft(santhosh.Draft2020)
+	const url = "mem:///registry/schema.json"
+	if err := compiler.AddResource(url, schema); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	if _, err := compiler.Compile(url); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return schema, nil
+}
+
+// schemaFingerprint hashes the schema with its keys sorted and whitespace removed
+func schemaFingerprint(schema interface{}) string {
+	canonical, _ := json.Marshal(schema)
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// GetSchema retrieves a schema by ID
+func (sr *SchemaRegistry) GetSchema(schemaID int) (SchemaMetadata, error) {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	metadata, exists := sr.schemas[schemaID]
+	if !exists {
+		return SchemaMetadata{}, fmt.Errorf("schema with ID %d not found", schemaID)
+	}
+	return metadata, nil
+}
+
+// GetLatestSchema retrieves the latest schema for a subject
+func (sr *SchemaRegistry) GetLatestSchema(subject string) (SchemaMetadata, error) {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	schemaIDs, exists := sr.subjectSchemas[subject]
+	if !exists || len(schemaIDs) == 0 {
+		return SchemaMetadata{}, fmt.Errorf("no schemas found for subject %s", subject)
+	}
+	return sr.schemas[schemaIDs[len(schemaIDs)-1]], nil
+}
+
+// GetSchemaVersion retrieves a specific version of a schema for a subject
+func (sr *SchemaRegistry) GetSchemaVersion(subject string, version int) (SchemaMetadata, error) {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	schemaIDs, exists := sr.subjectSchemas[subject]
+	if !exists || version < 1 || version > len(schemaIDs) {
+		return SchemaMetadata{}, fmt.Errorf("schema version %d not found for subject %s", version, subject)
+	}
+	return sr.schemas[schemaIDs[version-1]], nil
+}
+
+// ListSubjects returns all registered subjects
+func (sr *SchemaRegistry) ListSubjects() []string {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	subjects := make([]string, 0, len(sr.subjectSchemas))
+	for subject := range sr.subjectSchemas {
+		subjects = append(subjects, subject)
+	}
+	return subjects
+}
+
+// ListSchemaVersions returns all versions for a subject
+func (sr *SchemaRegistry) ListSchemaVersions(subject string) ([]int, error) {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	schemaIDs, exists := sr.subjectSchemas[subject]
+	if !exists {
+		return nil, fmt.Errorf("subject %s not found", subject)
+	}
+
+	versions := make([]int, len(schemaIDs))
+	for i, id := range schemaIDs {
+		versions[i] = sr.schemas[id].Version
+	}
+	return versions, nil
+}
+
+// SetCompatibilityLevel sets the compatibility level for a subject
+func (sr *SchemaRegistry) SetCompatibilityLevel(subject string, level CompatibilityLevel) error {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	sr.compatibilityLevels[subject] = level
+	return nil
+}
+
+// GetCompatibilityLevel gets the compatibility level for a subject
+func (sr *SchemaRegistry) GetCompatibilityLevel(subject string) CompatibilityLevel {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	if level, exists := sr.compatibilityLevels[subject]; exists {
+		return level
+	}
+	return CompatibilityBackward // Default compatibility level
+}
+
+// CheckCompatibility checks if a new schema is compatible with existing schemas
+func (sr *SchemaRegistry) CheckCompatibility(subject string, schemaJSON string) (bool, error) {
+	report, err := sr.CheckCompatibilityReport(subject, schemaJSON)
+	if err != nil {
+		return false, err
+	}
+	return report.Compatible, nil
+}
+
+// CheckCompatibilityReport checks a new schema against the subject and returns the full report
+func (sr *SchemaRegistry) CheckCompatibilityReport(subject string, schemaJSON string) (*CompatibilityReport, error) {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	schema, err := parseSchema(schemaJSON)
+	if err != nil {
+		return nil, err
+	}
+	return sr.checkCompatibility(subject, schema), nil
+}
+
+// checkCompatibility checks newSchema against the subject's registered versions.
+// The caller holds the lock
+func (sr *SchemaRegistry) checkCompatibility(subject string, newSchema interface{}) *CompatibilityReport {
+	compatibilityLevel := CompatibilityBackward
+	if level, exists := sr.compatibilityLevels[subject]; exists {
+		compatibilityLevel = level
+	}
+
+	report := &CompatibilityReport{
+		Subject:    subject,
+		Level:      compatibilityLevel,
+		Compatible: true,
+	}
+	if compatibilityLevel == CompatibilityNone {
+		return report
+	}
+
+	schemaIDs := sr.subjectSchemas[subject]
+	if len(schemaIDs) == 0 {
+		return report
+	}
+	// Non-transitive levels only check against the latest schema
+	if !compatibilityLevel.IsTransitive() {
+		schemaIDs = schemaIDs[len(schemaIDs)-1:]
+	}
+
+	for _, id := range schemaIDs {
+		existing := sr.schemas[id]
+		report.CheckedVersions = append(report.CheckedVersions, existing.Version)
+
+		// Backward: documents valid under the old schema stay valid under the new one
+		if compatibilityLevel.ChecksBackward() {
+			report.AddIncompatibilities("backward", existing.Version,
+				CheckReaderWriterCompatibility(newSchema, existing.Schema))
+		}
+		// Forward: documents valid under the new schema are valid under the old one
+		if compatibilityLevel.ChecksForward() {
+			report.AddIncompatibilities("forward", existing.Version,
+				CheckReaderWriterCompatibility(existing.Schema, newSchema))
+		}
+	}
+
+	report.Compatible = len(report.Incompatibilities) == 0
+	return report
+}
+
+// GetStats returns registry statistics
+func (sr *SchemaRegistry) GetStats() map[string]interface{} {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	subjectStats := make(map[string]int, len(sr.subjectSchemas))
+	subjects := make([]string, 0, len(sr.subjectSchemas))
+	for subject, schemaIDs := range sr.subjectSchemas {
+		subjectStats[subject] = len(schemaIDs)
+		subjects = append(subjects, subject)
+	}
+
+	return map[string]interface{}{
+		"total_schemas":       len(sr.schemas),
+		"total_subjects":      len(sr.subjectSchemas),
+		"next_schema_id":      sr.nextSchemaID,
+		"subjects":            subjects,
+		"schemas_per_subject": subjectStats,
+	}
+}
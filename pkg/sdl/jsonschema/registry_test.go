@@ -0,0 +1,315 @@
+package jsonschema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+const itemV1 = `{
+	"type": "object",
+	"properties": {
+		"id": {"type": "integer"},
+		"name": {"type": "string"}
+	},
+	"required": ["id"],
+	"additionalProperties": false
+}`
+
+// decode parses a schema the way the registry does
+func decode(t *testing.T, schemaJSON string) interface{} {
+	t.Helper()
+	schema, err := parseSchema(schemaJSON)
+	require.NoError(t, err)
+	return schema
+}
+
+func TestCheckReaderWriterCompatibility(t *testing.T) {
+	tests := []struct {
+		name   string
+		reader string
+		writer string
+		want   []IncompatibilityType
+		path   string
+	}{
+		{
+			name:   "identical",
+			reader: itemV1,
+			writer: itemV1,
+		},
+		{
+			name:   "added optional property to closed object",
+			reader: `{"type": "object", "properties": {"id": {"type": "integer"}, "name": {"type": "string"}, "sku": {"type": "string"}}, "required": ["id"], "additionalProperties": false}`,
+			writer: itemV1,
+		},
+		{
+			name:   "added required property",
+			reader: `{"type": "object", "properties": {"id": {"type": "integer"}, "name": {"type": "string"}}, "required": ["id", "name"], "additionalProperties": false}`,
+			writer: itemV1,
+			want:   []IncompatibilityType{IncompatibilityRequiredPropertyAdded},
+			path:   "$.name",
+		},
+		{
+			name:   "removed property from closed object",
+			reader: `{"type": "object", "properties": {"id": {"type": "integer"}}, "required": ["id"], "additionalProperties": false}`,
+			writer: itemV1,
+			want:   []IncompatibilityType{IncompatibilityPropertyRemoved},
+			path:   "$.name",
+		},
+		{
+			name:   "added property to open object",
+			reader: `{"type": "object", "properties": {"id": {"type": "integer"}, "sku": {"type": "string"}}}`,
+			writer: `{"type": "object", "properties": {"id": {"type": "integer"}}}`,
+			want:   []IncompatibilityType{IncompatibilityPropertyAddedToOpenModel},
+			path:   "$.sku",
+		},
+		{
+			name:   "closed an open object",
+			reader: `{"type": "object", "properties": {"id": {"type": "integer"}}, "additionalProperties": false}`,
+			writer: `{"type": "object", "properties": {"id": {"type": "integer"}}}`,
+			want:   []IncompatibilityType{IncompatibilityAdditionalPropertiesNarrowed},
+			path:   "$",
+		},
+		{
+			name:   "integer widened to number",
+			reader: `{"type": "number"}`,
+			writer: `{"type": "integer"}`,
+		},
+		{
+			name:   "number narrowed to integer",
+			reader: `{"type": "integer"}`,
+			writer: `{"type": "number"}`,
+			want:   []IncompatibilityType{IncompatibilityTypeNarrowed},
+			path:   "$",
+		},
+		{
+			name:   "enum value removed",
+			reader: `{"enum": ["ACTIVE", "INACTIVE"]}`,
+			writer: `{"enum": ["ACTIVE", "INACTIVE", "DELETED"]}`,
+			want:   []IncompatibilityType{IncompatibilityEnumNarrowed},
+			path:   "$",
+		},
+		{
+			name:   "range and length tightened",
+			reader: `{"type": "object", "properties": {"qty": {"type": "integer", "minimum": 1}, "code": {"type": "string", "maxLength": 3}}}`,
+			writer: `{"type": "object", "properties": {"qty": {"type": "integer", "minimum": 0}, "code": {"type": "string"}}}`,
+			want:   []IncompatibilityType{IncompatibilityRangeNarrowed, IncompatibilityRangeNarrowed},
+			path:   "$.qty",
+		},
+		{
+			name:   "format added",
+			reader: `{"type": "string", "format": "email"}`,
+			writer: `{"type": "string"}`,
+			want:   []IncompatibilityType{IncompatibilityFormatChanged},
+			path:   "$",
+		},
+		{
+			name:   "made nullable",
+			reader: `{"type": ["string", "null"]}`,
+			writer: `{"type": "string"}`,
+		},
+		{
+			name:   "dropped a union branch",
+			reader: `{"$defs": {"a": {"type": "object", "properties": {"x": {"type": "string"}}}}, "$ref": "#/$defs/a"}`,
+			writer: `{"$defs": {"a": {"type": "object", "properties": {"x": {"type": "string"}}}}, "anyOf": [{"$ref": "#/$defs/a"}, {"type": "null"}]}`,
+			want:   []IncompatibilityType{IncompatibilityTypeNarrowed},
+			path:   "$",
+		},
+		{
+			name:   "array items narrowed",
+			reader: `{"type": "array", "items": {"type": "integer"}}`,
+			writer: `{"type": "array", "items": {"type": "number"}}`,
+			want:   []IncompatibilityType{IncompatibilityTypeNarrowed},
+			path:   "$[]",
+		},
+		{
+			name:   "recursive definitions",
+			reader: `{"$defs": {"node": {"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}}}}, "$ref": "#/$defs/node"}`,
+			writer: `{"$defs": {"node": {"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}}}}, "$ref": "#/$defs/node"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incompatibilities := CheckReaderWriterCompatibility(decode(t, tt.reader), decode(t, tt.writer))
+
+			var got []IncompatibilityType
+			for _, inc := range incompatibilities {
+				got = append(got, inc.Type)
+			}
+			assert.Equal(t, tt.want, got, "%v", incompatibilities)
+			if len(incompatibilities) > 0 {
+				assert.Equal(t, tt.path, incompatibilities[len(incompatibilities)-1].Path)
+			}
+		})
+	}
+
+	t.Log("✓ Reader/writer compatibility rules verified")
+}
+
+func TestSchemaRegistry_RegisterAndVersion(t *testing.T) {
+	clock := testutil.NewDefaultFakeClock()
+	registry := NewSchemaRegistry().WithClock(clock)
+
+	id, err := registry.RegisterSchema("item", itemV1)
+	require.NoError(t, err)
+
+	// Formatting differences do not create new versions
+	again, err := registry.RegisterSchema("item", strings.Join(strings.Fields(itemV1), ""))
+	require.NoError(t, err)
+	assert.Equal(t, id, again)
+
+	clock.Advance(time.Minute)
+	v2 := `{"type": "object", "properties": {"id": {"type": "integer"}, "name": {"type": "string"}, "sku": {"type": "string"}}, "required": ["id"], "additionalProperties": false}`
+	id2, err := registry.RegisterSchema("item", v2)
+	require.NoError(t, err)
+
+	latest, err := registry.GetLatestSchema("item")
+	require.NoError(t, err)
+	assert.Equal(t, id2, latest.ID)
+	assert.Equal(t, 2, latest.Version)
+	assert.Len(t, latest.Fingerprint, 64)
+	assert.True(t, latest.CreatedAt.After(clock.Now().Add(-time.Second)))
+
+	first, err := registry.GetSchemaVersion("item", 1)
+	require.NoError(t, err)
+	assert.Equal(t, id, first.ID)
+	byID, err := registry.GetSchema(id2)
+	require.NoError(t, err)
+	assert.Equal(t, v2, byID.SchemaJSON)
+
+	versions, err := registry.ListSchemaVersions("item")
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, versions)
+	assert.Equal(t, []string{"item"}, registry.ListSubjects())
+	assert.Equal(t, 2, registry.GetStats()["total_schemas"])
+
+	_, err = registry.GetSchemaVersion("item", 3)
+	assert.Error(t, err)
+	_, err = registry.GetLatestSchema("order")
+	assert.Error(t, err)
+
+	// Documents must be JSON and schemas must satisfy the meta-schema
+	_, err = registry.RegisterSchema("bad", `{"type": 5}`)
+	assert.Error(t, err)
+	_, err = registry.RegisterSchema("bad", `{"type": "object"`)
+	assert.Error(t, err)
+
+	t.Log("✓ Schemas are registered, versioned and fingerprinted")
+}
+
+func TestSchemaRegistry_RejectsIncompatibleSchema(t *testing.T) {
+	registry := NewSchemaRegistry()
+	_, err := registry.RegisterSchema("item", itemV1)
+	require.NoError(t, err)
+
+	breaking := `{"type": "object", "properties": {"id": {"type": "integer"}, "name": {"type": "string"}, "price": {"type": "number"}}, "required": ["id", "price"], "additionalProperties": false}`
+	_, err = registry.RegisterSchema("item", breaking)
+	require.Error(t, err)
+
+	// The same error type as the Avro registry
+	var compatErr *avro.CompatibilityError
+	require.True(t, errors.As(err, &compatErr), "got %T", err)
+	require.Len(t, compatErr.Report.Incompatibilities, 1)
+	inc := compatErr.Report.Incompatibilities[0]
+	assert.Equal(t, IncompatibilityRequiredPropertyAdded, inc.Type)
+	assert.Equal(t, "backward", inc.Direction)
+	assert.Equal(t, 1, inc.Version)
+	assert.Equal(t, "$.price", inc.Path)
+
+	// Optional properties of a closed object are backward but not forward compatible
+	optional := `{"type": "object", "properties": {"id": {"type": "integer"}, "name": {"type": "string"}, "price": {"type": "number"}}, "required": ["id"], "additionalProperties": false}`
+	for level, want := range map[CompatibilityLevel]bool{
+		CompatibilityBackward: true,
+		CompatibilityForward:  false,
+		CompatibilityFull:     false,
+		CompatibilityNone:     true,
+	} {
+		require.NoError(t, registry.SetCompatibilityLevel("item", level))
+		compatible, err := registry.CheckCompatibility("item", optional)
+		require.NoError(t, err)
+		assert.Equal(t, want, compatible, string(level))
+	}
+
+	t.Log("✓ Registry rejected incompatible schema with report")
+}
+
+func TestSchemaRegistry_TransitiveCompatibility(t *testing.T) {
+	// v2 drops the closed model, v3 narrows name to a number: compatible with v2 only
+	v2 := `{"type": "object", "properties": {"id": {"type": "integer"}}, "required": ["id"]}`
+	v3 := `{"type": "object", "properties": {"id": {"type": "integer"}, "name": {"type": ["number", "string"]}}, "required": ["id"]}`
+
+	tests := []struct {
+		level          CompatibilityLevel
+		wantCompatible bool
+		wantChecked    int
+	}{
+		{CompatibilityBackward, false, 1},
+		{CompatibilityForward, true, 1},
+		{CompatibilityForwardTransitive, false, 2},
+		{CompatibilityNone, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.level), func(t *testing.T) {
+			registry := NewSchemaRegistry()
+			registry.SetCompatibilityLevel("item", CompatibilityNone)
+			for _, schema := range []string{itemV1, v2} {
+				_, err := registry.RegisterSchema("item", schema)
+				require.NoError(t, err)
+			}
+			registry.SetCompatibilityLevel("item", tt.level)
+
+			report, err := registry.CheckCompatibilityReport("item", v3)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCompatible, report.Compatible, "%v", report.Incompatibilities)
+			assert.Len(t, report.CheckedVersions, tt.wantChecked)
+			assert.Equal(t, tt.level, report.Level)
+		})
+	}
+
+	t.Log("✓ Transitive levels check every version")
+}
+
+// userV2 adds an optional nickname to avro.User
+type userV2 struct {
+	avro.User
+	Nickname *string `json:"nickname"`
+}
+
+func TestSchemaRegistry_GeneratedSchemas(t *testing.T) {
+	registry := NewSchemaRegistry()
+	registry.SetCompatibilityLevel("user", CompatibilityBackwardTransitive)
+
+	v1, err := NewSchemaGenerator().GenerateJSON(avro.User{})
+	require.NoError(t, err)
+	v2, err := NewSchemaGenerator().WithTitle("User").GenerateJSON(userV2{})
+	require.NoError(t, err)
+
+	_, err = registry.RegisterSchema("user", string(v1))
+	require.NoError(t, err)
+	_, err = registry.RegisterSchema("user", string(v2))
+	require.NoError(t, err, "adding an optional field is backward compatible")
+
+	// Dropping the field again is forward compatible only: v2 documents with a
+	// nickname fail the closed v1 model
+	report, err := registry.CheckCompatibilityReport("user", string(v1))
+	require.NoError(t, err)
+	assert.False(t, report.Compatible)
+	assert.Equal(t, IncompatibilityPropertyRemoved, report.Incompatibilities[0].Type)
+	assert.Equal(t, "$.nickname", report.Incompatibilities[0].Path)
+
+	registry.SetCompatibilityLevel("user", CompatibilityForwardTransitive)
+	report, err = registry.CheckCompatibilityReport("user", string(v1))
+	require.NoError(t, err)
+	assert.True(t, report.Compatible, "%v", report.Incompatibilities)
+
+	t.Log("✓ Generated schemas evolve under the registry")
+}
@@ -0,0 +1,177 @@
+package jsonschema
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+func TestSanthoshValidator_Draft202012(t *testing.T) {
+	helper := testutil.NewTestHelper(t)
+	validator := NewSanthoshValidator(helper.Logger())
+
+	// prefixItems, dependentRequired and unevaluatedProperties are 2020-12 only
+	err := validator.AddSchemaJSON("point", `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"coords": {"type": "array", "prefixItems": [{"type": "number"}, {"type": "number"}], "items": false},
+			"label": {"type": "string"},
+			"color": {"type": "string"}
+		},
+		"dependentRequired": {"color": ["label"]},
+		"unevaluatedProperties": false
+	}`)
+	require.NoError(t, err)
+
+	assert.NoError(t, validator.ValidateJSON("point", `{"coords": [1, 2], "label": "a", "color": "red"}`))
+	helper.AssertError(validator.ValidateJSON("point", `{"coords": [1, 2, 3]}`))
+	helper.AssertError(validator.ValidateJSON("point", `{"coords": [1, "2"]}`))
+	helper.AssertError(validator.ValidateJSON("point", `{"color": "red"}`))
+	helper.AssertError(validator.ValidateJSON("point", `{"extra": true}`))
+	helper.AssertError(validator.ValidateJSON("point", `{"coords": [1, 2]`))
+	helper.AssertError(validator.ValidateJSON("missing", `{}`), "schema not found")
+
+	t.Log("✓ Draft 2020-12 keywords are enforced")
+}
+
+func TestSanthoshValidator_GeneratedSchemas(t *testing.T) {
+	helper := testutil.NewTestHelper(t)
+	validator := NewSanthoshValidator(helper.Logger())
+
+	schema, err := NewSchemaGenerator().GenerateJSON([]avro.User{})
+	require.NoError(t, err)
+	require.NoError(t, validator.AddSchemaJSON("users", string(schema)))
+
+	manager, err := avro.NewManager("")
+	require.NoError(t, err)
+	users := manager.CreateSampleUsers(5)
+	assert.NoError(t, validator.ValidateData("users", users))
+
+	// Formats are asserted, not just annotations
+	users[2].Email = "not-an-email"
+	result, err := validator.ValidateWithDetails("users", users)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "/2/email", result.Errors[0].InstanceLocation)
+	assert.Contains(t, result.Errors[0].KeywordLocation, "/format")
+
+	t.Log("✓ Generated schemas validate sample users")
+}
+
+func TestSanthoshValidator_References(t *testing.T) {
+	helper := testutil.NewTestHelper(t)
+	validator := NewSanthoshValidator(helper.Logger())
+
+	// By ID, relative to the validator's base URL
+	require.NoError(t, validator.AddSchemaJSON("address", `{
+		"type": "object",
+		"properties": {"city": {"type": "string"}},
+		"required": ["city"]
+	}`))
+	require.NoError(t, validator.AddSchemaJSON("person", `{
+		"type": "object",
+		"properties": {"home": {"$ref": "address"}}
+	}`))
+	assert.NoError(t, validator.ValidateJSON("person", `{"home": {"city": "Taipei"}}`))
+	helper.AssertError(validator.ValidateJSON("person", `{"home": {}}`))
+
+	// By $id
+	require.NoError(t, validator.AddSchemaJSON("money", `{
+		"$id": "https://example.com/schemas/money.json",
+		"type": "object",
+		"properties": {"amount": {"type": "integer"}, "currency": {"type": "string", "minLength": 3, "maxLength": 3}},
+		"required": ["amount", "currency"]
+	}`))
+	require.NoError(t, validator.AddSchemaJSON("invoice", `{
+		"$id": "https://example.com/schemas/invoice.json",
+		"type": "object",
+		"properties": {"total": {"$ref": "money.json"}}
+	}`))
+	assert.NoError(t, validator.ValidateJSON("invoice", `{"total": {"amount": 100, "currency": "TWD"}}`))
+	helper.AssertError(validator.ValidateJSON("invoice", `{"total": {"amount": 100, "currency": "NT"}}`))
+
+	// Unresolvable references fail at compile time
+	helper.AssertError(validator.AddSchemaJSON("broken", `{"$ref": "nowhere"}`))
+
+	// Replacing a schema keeps its ID
+	require.NoError(t, validator.AddSchemaJSON("address", `{"type": "string"}`))
+	assert.NoError(t, validator.ValidateJSON("address", `"Taipei"`))
+	assert.ElementsMatch(t, []string{"address", "person", "money", "invoice"}, validator.ListSchemas())
+
+	assert.True(t, validator.RemoveSchema("address"))
+	assert.False(t, validator.RemoveSchema("address"))
+
+	t.Log("✓ Schemas reference each other by ID and $id")
+}
+
+func TestSanthoshValidator_CustomFormat(t *testing.T) {
+	helper := testutil.NewTestHelper(t)
+	validator := NewSanthoshValidator(helper.Logger()).
+		WithFormat("sku", func(v interface{}) error {
+			s, ok := v.(string)
+			if !ok {
+				return nil
+			}
+			if !strings.HasPrefix(s, "SKU-") {
+				return fmt.Errorf("%q does not start with SKU-", s)
+			}
+			return nil
+		})
+
+	require.NoError(t, validator.AddSchemaJSON("sku", `{"type": "string", "format": "sku"}`))
+	assert.NoError(t, validator.ValidateJSON("sku", `"SKU-001"`))
+	helper.AssertError(validator.ValidateJSON("sku", `"001"`))
+
+	t.Log("✓ Custom formats are asserted")
+}
+
+func TestNewValidator(t *testing.T) {
+	helper := testutil.NewTestHelper(t)
+	schema := `{
+		"type": "object",
+		"properties": {"name": {"type": "string", "minLength": 1}},
+		"required": ["name"]
+	}`
+
+	for _, backend := range []Backend{BackendXeipuuv, BackendSanthosh} {
+		t.Run(string(backend), func(t *testing.T) {
+			validator, err := NewValidator(backend, helper.Logger())
+			require.NoError(t, err)
+			require.NoError(t, validator.AddSchemaJSON("named", schema))
+
+			// Both backends report errors the same way through the middleware
+			middleware := NewSimpleHTTPMiddleware(validator, helper.Logger())
+			handler := middleware.ValidateRequest("named")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+			}))
+
+			for body, status := range map[string]int{`{"name": "a"}`: http.StatusCreated, `{"name": ""}`: http.StatusBadRequest} {
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				assert.Equal(t, status, rec.Code, body)
+			}
+
+			result, err := validator.ValidateWithDetails("named", map[string]interface{}{})
+			require.NoError(t, err)
+			assert.False(t, result.Valid)
+			assert.NotEmpty(t, result.Errors)
+		})
+	}
+
+	_, err := NewValidator("ajv", helper.Logger())
+	helper.AssertError(err)
+
+	t.Log("✓ Both backends are interchangeable")
+}
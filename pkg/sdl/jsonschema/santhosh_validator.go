@@ -0,0 +1,401 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	santhosh "github.com/santhosh-tekuri/jsonschema/v6"
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/logger"
+)
+
+// schemaBaseURL is the base URL of schemas added without an $id, so they can
+// reference each other as e.g. {"$ref": "address"}
+const schemaBaseURL = "mem:///schemas/"
+
+// SanthoshValidator provides JSON Schema validation using
+// santhosh-tekuri/jsonschema, which supports drafts 4 through 2020-12. Schemas
+// may $ref any schema added before them, by $id or by ID, and local files
+type SanthoshValidator struct {
+	schemas map[string]*santhosh.Schema
+	// sources holds every added schema by resource URL for $ref resolution
+	sources map[string]interface{}
+	// urls holds the resource URLs of each schema ID
+	urls    map[string][]string
+	formats map[string]*santhosh.Format
+	logger  *logger.Logger
+}
+
+// NewSanthoshValidator creates a new validator using santhosh-tekuri/jsonschema.
+// Formats such as email and date-time are asserted, as XeipuuvValidator does
+func NewSanthoshValidator(log *logger.Logger) *SanthoshValidator {
+	if log == nil {
+		log = logger.Global()
+	}
+	return &SanthoshValidator{
+		schemas: make(map[string]*santhosh.Schema),
+		sources: make(map[string]interface{}),
+		urls:    make(map[string][]string),
+		formats: make(map[string]*santhosh.Format),
+		logger:  log,
+	}
+}
+
+// WithFormat registers a custom format, replacing any built-in one of the
+// same name, for schemas added afterwards
+func (v *SanthoshValidator) WithFormat(name string, validate func(value interface{}) error) *SanthoshValidator {
+	v.formats[name] = &santhosh.Format{Name: name, Validate: validate}
+	return v
+}
+
+// AddSchemaJSON adds a schema from JSON string
+func (v *SanthoshValidator) AddSchemaJSON(id string, schemaJSON string) error {
+	doc, err := santhosh.UnmarshalJSON(strings.NewReader(schemaJSON))
+	if err != nil {
+		return errors.Wrap(err, errors.ErrorTypeValidation,
+			errors.CodeValidationFailed,
+			"failed to parse schema")
+	}
+
+	url := schemaBaseURL + id
+	if schemaID := rootID(doc); schemaID != "" {
+		url = schemaID
+	}
+	return v.addResources([]resource{{id: id, urls: []string{url}, doc: doc}})
+}
+
+// AddSchemaFile adds a schema from a file. Relative $refs resolve against the
+// file's location, or its $id, and other schemas may reference it by either
+func (v *SanthoshValidator) AddSchemaFile(id string, path string) error {
+	r, err := loadFileResource(id, path)
+	if err != nil {
+		return err
+	}
+	return v.addResources([]resource{r})
+}
+
+// AddSchemaDir adds every .json file under dir, so the schemas may reference
+// each other in any order. Each schema's ID is its slash-separated path
+// relative to dir, e.g. "common/address.json"; the IDs are returned sorted
+func (v *SanthoshValidator) AddSchemaDir(dir string) ([]string, error) {
+	var resources []resource
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".json") {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		r, err := loadFileResource(filepath.ToSlash(rel), path)
+		if err != nil {
+			return err
+		}
+		resources = append(resources, r)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.ErrorTypeInternal,
+			errors.CodeInternalError,
+			"failed to read schema directory %s", dir)
+	}
+
+	if err := v.addResources(resources); err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(resources))
+	for i, r := range resources {
+		ids[i] = r.id
+	}
+	return ids, nil
+}
+
+// resource is a schema document and the URLs it can be referenced by; the
+// first URL is its location
+type resource struct {
+	id   string
+	urls []string
+	doc  interface{}
+}
+
+// loadFileResource reads a schema file, addressed by its file URL and its $id
+func loadFileResource(id, path string) (resource, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return resource{}, errors.Wrapf(err, errors.ErrorTypeInternal,
+			errors.CodeInternalError,
+			"failed to resolve schema file %s", path)
+	}
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return resource{}, errors.Wrapf(err, errors.ErrorTypeInternal,
+			errors.CodeInternalError,
+			"failed to read schema file %s", path)
+	}
+	doc, err := santhosh.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return resource{}, errors.Wrapf(err, errors.ErrorTypeValidation,
+			errors.CodeValidationFailed,
+			"failed to parse schema file %s", path)
+	}
+
+	location := fileURL(abs)
+	r := resource{id: id, urls: []string{location}, doc: doc}
+	if schemaID := rootID(doc); schemaID != "" {
+		if resolved, err := resolveURL(location, schemaID); err == nil && resolved != location {
+			r.urls = append(r.urls, resolved)
+		}
+	}
+	return r, nil
+}
+
+// addResources compiles the resources together with the schemas added
+// before them, and adds them only if all of them compile
+func (v *SanthoshValidator) addResources(resources []resource) error {
+	replaced := make(map[string]bool)
+	for _, r := range resources {
+		for _, url := range v.urls[r.id] {
+			replaced[url] = true
+		}
+		for _, url := range r.urls {
+			replaced[url] = true
+		}
+	}
+
+	// A fresh compiler lets a schema be replaced under the same URL
+	compiler := santhosh.NewCompiler()
+	compiler.DefaultDraft(santhosh.Draft2020)
+	compiler.AssertFormat()
+	for _, format := range v.formats {
+		compiler.RegisterFormat(format)
+	}
+	for resourceURL, source := range v.sources {
+		if replaced[resourceURL] {
+			continue
+		}
+		if err := compiler.AddResource(resourceURL, source); err != nil {
+			return errors.Wrap(err, errors.ErrorTypeValidation,
+				errors.CodeValidationFailed,
+				"failed to load schema resources")
+		}
+	}
+	for _, r := range resources {
+		for _, url := range r.urls {
+			if err := compiler.AddResource(url, r.doc); err != nil {
+				return errors.Wrap(err, errors.ErrorTypeValidation,
+					errors.CodeValidationFailed,
+					"failed to add schema")
+			}
+		}
+	}
+
+	compiled := make([]*santhosh.Schema, len(resources))
+	for i, r := range resources {
+		schema, err := compiler.Compile(r.urls[0])
+		if err != nil {
+			v.logger.Warn("Failed to compile schema", zap.String("schema_id", r.id), zap.Error(err))
+			return errors.Wrap(err, errors.ErrorTypeValidation,
+				errors.CodeValidationFailed,
+				"failed to compile schema")
+		}
+		compiled[i] = schema
+	}
+
+	for i, r := range resources {
+		for _, url := range v.urls[r.id] {
+			delete(v.sources, url)
+		}
+		for _, url := range r.urls {
+			v.sources[url] = r.doc
+		}
+		v.schemas[r.id] = compiled[i]
+		v.urls[r.id] = r.urls
+	}
+	return nil
+}
+
+// rootID returns the $id of a schema document, if any
+func rootID(doc interface{}) string {
+	if obj, ok := doc.(map[string]interface{}); ok {
+		if schemaID, ok := obj["$id"].(string); ok {
+			return schemaID
+		}
+	}
+	return ""
+}
+
+// fileURL converts an absolute path to a file URL
+func fileURL(abs string) string {
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}
+	if !strings.HasPrefix(u.Path, "/") {
+		u.Path = "/" + u.Path
+	}
+	return u.String()
+}
+
+// resolveURL resolves ref against base
+func resolveURL(base, ref string) (string, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	return b.ResolveReference(r).String(), nil
+}
+
+// ValidateJSON validates a JSON string against a schema
+func (v *SanthoshValidator) ValidateJSON(schemaID string, jsonData string) error {
+	instance, err := santhosh.UnmarshalJSON(strings.NewReader(jsonData))
+	if err != nil {
+		return errors.ValidationError(errors.CodeInvalidInput,
+			fmt.Sprintf("validation error: %v", err))
+	}
+	return v.validate(schemaID, instance)
+}
+
+// ValidateData validates Go data against a schema
+func (v *SanthoshValidator) ValidateData(schemaID string, data interface{}) error {
+	instance, err := toInstance(data)
+	if err != nil {
+		return errors.ValidationError(errors.CodeInvalidInput,
+			fmt.Sprintf("validation error: %v", err))
+	}
+	return v.validate(schemaID, instance)
+}
+
+// validate validates a decoded JSON value
+func (v *SanthoshValidator) validate(schemaID string, instance interface{}) error {
+	schema, exists := v.schemas[schemaID]
+	if !exists {
+		return errors.ValidationError(errors.CodeValidationFailed,
+			fmt.Sprintf("schema not found: %s", schemaID))
+	}
+
+	err := schema.Validate(instance)
+	if err == nil {
+		return nil
+	}
+	details := validationErrors(err)
+	if details == nil {
+		return errors.ValidationError(errors.CodeInvalidInput,
+			fmt.Sprintf("validation error: %v", err))
+	}
+
+	errorMessages := make([]string, len(details))
+	for i, detail := range details {
+		errorMessages[i] = fmt.Sprintf("%s: %s", detail.InstanceLocation, detail.Message)
+	}
+	return errors.ValidationError(errors.CodeValidationFailed,
+		fmt.Sprintf("validation failed: %v", errorMessages))
+}
+
+// ValidateWithDetails returns detailed validation results
+func (v *SanthoshValidator) ValidateWithDetails(schemaID string, data interface{}) (*ValidationResult, error) {
+	schema, exists := v.schemas[schemaID]
+	if !exists {
+		return &ValidationResult{
+			Valid: false,
+			Errors: []ValidationError{
+				{
+					Message: fmt.Sprintf("schema not found: %s", schemaID),
+				},
+			},
+		}, nil
+	}
+
+	instance, err := toInstance(data)
+	if err != nil {
+		return nil, errors.ValidationError(errors.CodeInvalidInput,
+			fmt.Sprintf("validation error: %v", err))
+	}
+
+	validationResult := &ValidationResult{
+		Valid:  true,
+		Schema: schemaID,
+		Data:   data,
+	}
+	if err := schema.Validate(instance); err != nil {
+		details := validationErrors(err)
+		if details == nil {
+			return nil, errors.ValidationError(errors.CodeInvalidInput,
+				fmt.Sprintf("validation error: %v", err))
+		}
+		validationResult.Valid = false
+		validationResult.Errors = details
+	}
+
+	return validationResult, nil
+}
+
+// ListSchemas returns all registered schema IDs
+func (v *SanthoshValidator) ListSchemas() []string {
+	ids := make([]string, 0, len(v.schemas))
+	for id := range v.schemas {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// GetSchema returns a compiled schema by ID
+func (v *SanthoshValidator) GetSchema(schemaID string) (*santhosh.Schema, bool) {
+	schema, exists := v.schemas[schemaID]
+	return schema, exists
+}
+
+// RemoveSchema removes a schema from the validator; schemas that already
+// reference it keep their compiled copy
+func (v *SanthoshValidator) RemoveSchema(schemaID string) bool {
+	_, exists := v.schemas[schemaID]
+	delete(v.schemas, schemaID)
+	for _, url := range v.urls[schemaID] {
+		delete(v.sources, url)
+	}
+	delete(v.urls, schemaID)
+	return exists
+}
+
+// toInstance converts Go data to the generic JSON values the validator reads
+func toInstance(data interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal data: %w", err)
+	}
+	return santhosh.UnmarshalJSON(bytes.NewReader(encoded))
+}
+
+// validationErrors flattens a validation failure into its leaf errors, or
+// returns nil if err is not a validation failure
+func validationErrors(err error) []ValidationError {
+	var validationErr *santhosh.ValidationError
+	if !stderrors.As(err, &validationErr) {
+		return nil
+	}
+
+	var details []ValidationError
+	for _, unit := range validationErr.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		details = append(details, ValidationError{
+			InstanceLocation: unit.InstanceLocation,
+			KeywordLocation:  unit.KeywordLocation,
+			Message:          unit.Error.String(),
+		})
+	}
+	if len(details) == 0 {
+		details = append(details, ValidationError{Message: validationErr.Error()})
+	}
+	return details
+}
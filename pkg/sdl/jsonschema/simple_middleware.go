@@ -16,12 +16,12 @@ import (
 
 // SimpleHTTPMiddleware provides simple HTTP middleware for JSON Schema validation
 type SimpleHTTPMiddleware struct {
-	validator *XeipuuvValidator
+	validator Validator
 	logger    *logger.Logger
 }
 
 // NewSimpleHTTPMiddleware creates a new simple HTTP middleware
-func NewSimpleHTTPMiddleware(validator *XeipuuvValidator, logger *logger.Logger) *SimpleHTTPMiddleware {
+func NewSimpleHTTPMiddleware(validator Validator, logger *logger.Logger) *SimpleHTTPMiddleware {
 	return &SimpleHTTPMiddleware{
 		validator: validator,
 		logger:    logger,
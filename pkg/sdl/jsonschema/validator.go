@@ -0,0 +1,43 @@
+package jsonschema
+
+import (
+	"fmt"
+
+	"go-transport-prac/internal/logger"
+)
+
+// Validator validates JSON documents against schemas registered by ID
+type Validator interface {
+	AddSchemaJSON(id string, schemaJSON string) error
+	ValidateJSON(schemaID string, jsonData string) error
+	ValidateData(schemaID string, data interface{}) error
+	ValidateWithDetails(schemaID string, data interface{}) (*ValidationResult, error)
+	ListSchemas() []string
+	RemoveSchema(schemaID string) bool
+}
+
+// Backend names a Validator implementation
+type Backend string
+
+const (
+	// BackendXeipuuv validates drafts 4, 6 and 7 with xeipuuv/gojsonschema
+	BackendXeipuuv Backend = "xeipuuv"
+	// BackendSanthosh validates drafts 4 through 2020-12 with santhosh-tekuri/jsonschema
+	BackendSanthosh Backend = "santhosh"
+)
+
+var (
+	_ Validator = (*XeipuuvValidator)(nil)
+	_ Validator = (*SanthoshValidator)(nil)
+)
+
+// NewValidator creates a validator with the given backend
+func NewValidator(backend Backend, log *logger.Logger) (Validator, error) {
+	switch backend {
+	case BackendXeipuuv:
+		return NewXeipuuvValidator(log), nil
+	case BackendSanthosh:
+		return NewSanthoshValidator(log), nil
+	}
+	return nil, fmt.Errorf("unknown JSON Schema backend %q", backend)
+}
@@ -1,12 +1,15 @@
 package jsonschema
 
 import (
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/cache"
 )
 
 func TestXeipuuvValidator_AddSchemaJSON(t *testing.T) {
@@ -327,6 +330,50 @@ func TestXeipuuvValidator_RemoveSchema(t *testing.T) {
 	assert.False(t, removed)
 }
 
+func TestXeipuuvValidator_WithCache(t *testing.T) {
+	helper := testutil.NewTestHelper(t)
+	shared := cache.Instrument(cache.NewMemoryCache(), "jsonschema", helper.Logger())
+
+	writer := NewXeipuuvValidator(helper.Logger()).WithCache(shared, time.Minute)
+	reader := NewXeipuuvValidator(helper.Logger()).WithCache(shared, time.Minute)
+
+	err := writer.AddSchemaJSON("user", `{"type": "object", "required": ["name"]}`)
+	require.NoError(t, err)
+
+	// The second validator compiles the schema from the shared cache once
+	assert.NoError(t, reader.ValidateJSON("user", `{"name": "Ada"}`))
+	assert.Error(t, reader.ValidateJSON("user", `{}`))
+	assert.Equal(t, int64(1), shared.Stats().Hits)
+	assert.Contains(t, reader.ListSchemas(), "user")
+
+	// Removing a schema drops it from the cache for everyone
+	writer.RemoveSchema("user")
+	other := NewXeipuuvValidator(helper.Logger()).WithCache(shared, time.Minute)
+	assert.Error(t, other.ValidateJSON("user", `{"name": "Ada"}`))
+	assert.Equal(t, int64(1), shared.Stats().Misses)
+}
+
+func TestXeipuuvValidator_ConcurrentCacheLookups(t *testing.T) {
+	helper := testutil.NewTestHelper(t)
+	shared := cache.NewMemoryCache()
+	writer := NewXeipuuvValidator(helper.Logger()).WithCache(shared, time.Minute)
+	require.NoError(t, writer.AddSchemaJSON("user", `{"type": "object", "required": ["name"]}`))
+
+	// Every goroutine misses locally and compiles the schema from the cache
+	reader := NewXeipuuvValidator(helper.Logger()).WithCache(shared, time.Minute)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, reader.ValidateJSON("user", `{"name": "Ada"}`))
+			reader.ListSchemas()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, []string{"user"}, reader.ListSchemas())
+}
+
 // Benchmark tests
 func BenchmarkXeipuuvValidator_ValidateJSON(b *testing.B) {
 	validator := NewXeipuuvValidator(nil)
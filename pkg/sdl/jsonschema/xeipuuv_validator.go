@@ -1,28 +1,52 @@
 package jsonschema
 
 import (
+	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/xeipuuv/gojsonschema"
+	"go.uber.org/zap"
 
 	"go-transport-prac/internal/errors"
 	"go-transport-prac/internal/logger"
+	"go-transport-prac/internal/types"
 )
 
 // XeipuuvValidator provides JSON Schema validation using xeipuuv/gojsonschema
 type XeipuuvValidator struct {
+	logger   *logger.Logger
+	cache    types.Cache
+	cacheTTL time.Duration
+
+	// mu guards schemas and expires, which lookups fill from the cache
+	mu      sync.Mutex
 	schemas map[string]*gojsonschema.Schema
-	logger  *logger.Logger
+	// expires holds when schemas compiled from the cache must be reloaded
+	expires map[string]time.Time
 }
 
 // NewXeipuuvValidator creates a new validator using xeipuuv/gojsonschema
-func NewXeipuuvValidator(logger *logger.Logger) *XeipuuvValidator {
+func NewXeipuuvValidator(log *logger.Logger) *XeipuuvValidator {
+	if log == nil {
+		log = logger.Global()
+	}
 	return &XeipuuvValidator{
 		schemas: make(map[string]*gojsonschema.Schema),
-		logger:  logger,
+		logger:  log,
+		expires: make(map[string]time.Time),
 	}
 }
 
+// WithCache shares schema sources through c for ttl, so validators backed by
+// the same cache compile schemas added by any of them
+func (v *XeipuuvValidator) WithCache(c types.Cache, ttl time.Duration) *XeipuuvValidator {
+	v.cache = c
+	v.cacheTTL = ttl
+	return v
+}
+
 // AddSchemaJSON adds a schema from JSON string
 func (v *XeipuuvValidator) AddSchemaJSON(id string, schemaJSON string) error {
 	schemaLoader := gojsonschema.NewStringLoader(schemaJSON)
@@ -33,13 +57,65 @@ func (v *XeipuuvValidator) AddSchemaJSON(id string, schemaJSON string) error {
 			"failed to compile schema")
 	}
 
+	v.mu.Lock()
 	v.schemas[id] = schema
+	delete(v.expires, id)
+	v.mu.Unlock()
+
+	if v.cache != nil {
+		if err := v.cache.Set(context.Background(), schemaCacheKey(id), []byte(schemaJSON), v.cacheTTL); err != nil {
+			v.logger.Warn("Failed to cache schema", zap.String("schema_id", id), zap.Error(err))
+		}
+	}
 	return nil
 }
 
+// schemaCacheKey is the cache key for a schema's source
+func schemaCacheKey(id string) string {
+	return "jsonschema:" + id
+}
+
+// lookup returns the compiled schema for id, compiling it from the cache when
+// it was added elsewhere or its cached copy has expired
+func (v *XeipuuvValidator) lookup(id string) (*gojsonschema.Schema, bool) {
+	v.mu.Lock()
+	schema, exists := v.schemas[id]
+	if exists {
+		expiresAt, fromCache := v.expires[id]
+		if !fromCache || time.Now().Before(expiresAt) {
+			v.mu.Unlock()
+			return schema, true
+		}
+		delete(v.schemas, id)
+		delete(v.expires, id)
+	}
+	v.mu.Unlock()
+	if v.cache == nil {
+		return nil, false
+	}
+
+	source, err := v.cache.Get(context.Background(), schemaCacheKey(id))
+	if err != nil {
+		return nil, false
+	}
+	schema, err = gojsonschema.NewSchema(gojsonschema.NewBytesLoader(source))
+	if err != nil {
+		v.logger.Warn("Failed to compile cached schema", zap.String("schema_id", id), zap.Error(err))
+		return nil, false
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.schemas[id] = schema
+	if v.cacheTTL > 0 {
+		v.expires[id] = time.Now().Add(v.cacheTTL)
+	}
+	return schema, true
+}
+
 // ValidateJSON validates a JSON string against a schema
 func (v *XeipuuvValidator) ValidateJSON(schemaID string, jsonData string) error {
-	schema, exists := v.schemas[schemaID]
+	schema, exists := v.lookup(schemaID)
 	if !exists {
 		return errors.ValidationError(errors.CodeValidationFailed,
 			fmt.Sprintf("schema not found: %s", schemaID))
@@ -66,7 +142,7 @@ func (v *XeipuuvValidator) ValidateJSON(schemaID string, jsonData string) error
 
 // ValidateData validates Go data against a schema
 func (v *XeipuuvValidator) ValidateData(schemaID string, data interface{}) error {
-	schema, exists := v.schemas[schemaID]
+	schema, exists := v.lookup(schemaID)
 	if !exists {
 		return errors.ValidationError(errors.CodeValidationFailed,
 			fmt.Sprintf("schema not found: %s", schemaID))
@@ -93,7 +169,7 @@ func (v *XeipuuvValidator) ValidateData(schemaID string, data interface{}) error
 
 // ValidateWithDetails returns detailed validation results
 func (v *XeipuuvValidator) ValidateWithDetails(schemaID string, data interface{}) (*ValidationResult, error) {
-	schema, exists := v.schemas[schemaID]
+	schema, exists := v.lookup(schemaID)
 	if !exists {
 		return &ValidationResult{
 			Valid: false,
@@ -134,6 +210,8 @@ func (v *XeipuuvValidator) ValidateWithDetails(schemaID string, data interface{}
 
 // ListSchemas returns all registered schema IDs
 func (v *XeipuuvValidator) ListSchemas() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
 	ids := make([]string, 0, len(v.schemas))
 	for id := range v.schemas {
 		ids = append(ids, id)
@@ -143,17 +221,22 @@ func (v *XeipuuvValidator) ListSchemas() []string {
 
 // GetSchema returns a compiled schema by ID
 func (v *XeipuuvValidator) GetSchema(schemaID string) (*gojsonschema.Schema, bool) {
-	schema, exists := v.schemas[schemaID]
+	schema, exists := v.lookup(schemaID)
 	return schema, exists
 }
 
 // RemoveSchema removes a schema from the validator
 func (v *XeipuuvValidator) RemoveSchema(schemaID string) bool {
-	if _, exists := v.schemas[schemaID]; exists {
-		delete(v.schemas, schemaID)
-		return true
+	v.mu.Lock()
+	_, exists := v.schemas[schemaID]
+	delete(v.schemas, schemaID)
+	delete(v.expires, schemaID)
+	v.mu.Unlock()
+
+	if v.cache != nil {
+		v.cache.Delete(context.Background(), schemaCacheKey(schemaID))
 	}
-	return false
+	return exists
 }
 
 // ValidationResult represents validation results
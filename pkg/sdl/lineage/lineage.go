@@ -0,0 +1,172 @@
+package lineage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"go-transport-prac/internal/types"
+)
+
+// Transform is one step applied to produce a column
+type Transform struct {
+	Stage  string   `json:"stage"`
+	Name   string   `json:"name"`
+	Output string   `json:"output"`
+	Inputs []string `json:"inputs"`
+}
+
+// String renders the step as "output ← name(inputs)"
+func (t Transform) String() string {
+	return fmt.Sprintf("%s ← %s(%s)", t.Output, t.Name, strings.Join(t.Inputs, ", "))
+}
+
+// ColumnLineage traces an output column back to the input columns it derives from
+type ColumnLineage struct {
+	Column  string   `json:"column"`
+	Sources []string `json:"sources"`
+	// Transforms lists every step that contributed, in the order they ran
+	Transforms []Transform `json:"transforms"`
+}
+
+// Graph is the column lineage of one pipeline run
+type Graph struct {
+	Pipeline      string          `json:"pipeline"`
+	RunID         string          `json:"runId"`
+	InputDataset  string          `json:"inputDataset"`
+	OutputDataset string          `json:"outputDataset"`
+	RecordedAt    time.Time       `json:"recordedAt"`
+	Columns       []ColumnLineage `json:"columns"`
+}
+
+// Column returns the lineage of an output column
+func (g *Graph) Column(name string) (ColumnLineage, bool) {
+	for _, c := range g.Columns {
+		if c.Column == name {
+			return c, true
+		}
+	}
+	return ColumnLineage{}, false
+}
+
+// Downstream returns the output columns derived from an input column
+func (g *Graph) Downstream(source string) []string {
+	var columns []string
+	for _, c := range g.Columns {
+		if slices.Contains(c.Sources, source) {
+			columns = append(columns, c.Column)
+		}
+	}
+	return columns
+}
+
+// WriteFile writes the graph as indented JSON
+func (g *Graph) WriteFile(path string) error {
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal lineage: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write lineage: %w", err)
+	}
+	return nil
+}
+
+// ReadFile reads a graph written by WriteFile
+func ReadFile(path string) (*Graph, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lineage: %w", err)
+	}
+	var g Graph
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("failed to parse lineage: %w", err)
+	}
+	return &g, nil
+}
+
+// Recorder collects the transforms of a run and composes them into a Graph.
+// Columns are named by their path in the working schema, e.g. profile.first_name;
+// a column read before any stage wrote it is an input column
+type Recorder struct {
+	graph   Graph
+	clock   types.Clock
+	columns map[string]*ColumnLineage
+}
+
+// NewRecorder creates a recorder for one run of pipeline
+func NewRecorder(pipeline, runID, inputDataset, outputDataset string) *Recorder {
+	return &Recorder{
+		graph: Graph{
+			Pipeline:      pipeline,
+			RunID:         runID,
+			InputDataset:  inputDataset,
+			OutputDataset: outputDataset,
+		},
+		clock:   types.SystemClock{},
+		columns: make(map[string]*ColumnLineage),
+	}
+}
+
+// WithClock sets the clock used for the graph's RecordedAt
+func (r *Recorder) WithClock(clock types.Clock) *Recorder {
+	r.clock = types.ClockOrSystem(clock)
+	return r
+}
+
+// Record notes that stage wrote output by applying transform to inputs. The
+// output inherits the sources and earlier transforms of every input, so
+// recording status ← normalize(status) after status ← copy(raw_status) traces
+// back to raw_status
+func (r *Recorder) Record(stage, transform, output string, inputs ...string) {
+	step := Transform{Stage: stage, Name: transform, Output: output, Inputs: inputs}
+	next := &ColumnLineage{Column: output}
+
+	for _, input := range inputs {
+		upstream, ok := r.columns[input]
+		if !ok {
+			next.Sources = appendUnique(next.Sources, input)
+			continue
+		}
+		for _, source := range upstream.Sources {
+			next.Sources = appendUnique(next.Sources, source)
+		}
+		for _, t := range upstream.Transforms {
+			if !slices.ContainsFunc(next.Transforms, func(seen Transform) bool { return equalTransforms(seen, t) }) {
+				next.Transforms = append(next.Transforms, t)
+			}
+		}
+	}
+
+	next.Transforms = append(next.Transforms, step)
+	r.columns[output] = next
+}
+
+// Graph returns the lineage recorded so far, with columns sorted by name
+func (r *Recorder) Graph() *Graph {
+	g := r.graph
+	g.RecordedAt = r.clock.Now()
+	g.Columns = make([]ColumnLineage, 0, len(r.columns))
+	for _, c := range r.columns {
+		column := *c
+		sort.Strings(column.Sources)
+		g.Columns = append(g.Columns, column)
+	}
+	sort.Slice(g.Columns, func(i, j int) bool { return g.Columns[i].Column < g.Columns[j].Column })
+	return &g
+}
+
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
+
+func equalTransforms(a, b Transform) bool {
+	return a.Stage == b.Stage && a.Name == b.Name && a.Output == b.Output && slices.Equal(a.Inputs, b.Inputs)
+}
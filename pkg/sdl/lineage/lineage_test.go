@@ -0,0 +1,87 @@
+package lineage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go-transport-prac/internal/testutil"
+)
+
+func TestRecorderComposesStages(t *testing.T) {
+	clock := testutil.NewDefaultFakeClock()
+	r := NewRecorder("users_etl", "run-1", "raw_users", "users").WithClock(clock)
+
+	r.Record("extract", "default_name", "name", "full_name", "id")
+	r.Record("extract", "copy", "status", "raw_status")
+	r.Record("transform", "normalize_status", "status", "status")
+	r.Record("transform", "split", "profile.first_name", "name")
+	r.Record("transform", "quality_score", "score", "status", "profile.first_name")
+	r.Record("transform", "now", "updated_at")
+
+	g := r.Graph()
+	if !g.RecordedAt.Equal(clock.Now()) || g.RunID != "run-1" {
+		t.Errorf("Unexpected graph metadata: %+v", g)
+	}
+
+	firstName, ok := g.Column("profile.first_name")
+	if !ok {
+		t.Fatal("Expected lineage for profile.first_name")
+	}
+	if !reflect.DeepEqual(firstName.Sources, []string{"full_name", "id"}) {
+		t.Errorf("Expected first name to trace to full_name and id, got %v", firstName.Sources)
+	}
+	if len(firstName.Transforms) != 2 || firstName.Transforms[1].String() != "profile.first_name ← split(name)" {
+		t.Errorf("Unexpected transforms: %v", firstName.Transforms)
+	}
+
+	// In-place transforms keep the original source
+	status, _ := g.Column("status")
+	if !reflect.DeepEqual(status.Sources, []string{"raw_status"}) || len(status.Transforms) != 2 {
+		t.Errorf("Expected status to trace to raw_status through two steps, got %+v", status)
+	}
+
+	// Shared upstream steps are listed once
+	score, _ := g.Column("score")
+	if len(score.Transforms) != 5 || !reflect.DeepEqual(score.Sources, []string{"full_name", "id", "raw_status"}) {
+		t.Errorf("Unexpected score lineage: %+v", score)
+	}
+
+	// Generated columns have no sources
+	if updated, _ := g.Column("updated_at"); len(updated.Sources) != 0 {
+		t.Errorf("Expected no sources for updated_at, got %v", updated.Sources)
+	}
+
+	if downstream := g.Downstream("full_name"); !reflect.DeepEqual(downstream, []string{"name", "profile.first_name", "score"}) {
+		t.Errorf("Unexpected downstream columns: %v", downstream)
+	}
+
+	t.Log("✓ Lineage composes across stages back to input columns")
+}
+
+func TestGraphFileRoundTrip(t *testing.T) {
+	dir := "tmp/test_lineage_file"
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	r := NewRecorder("p", "run", "in", "out").WithClock(testutil.NewDefaultFakeClock())
+	r.Record("transform", "upper", "name", "name")
+	g := r.Graph()
+
+	path := filepath.Join(dir, "lineage.json")
+	if err := g.WriteFile(path); err != nil {
+		t.Fatalf("Failed to write graph: %v", err)
+	}
+	read, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read graph: %v", err)
+	}
+	if !reflect.DeepEqual(read, g) {
+		t.Errorf("Graph changed in round trip:\nwrote %+v\nread  %+v", g, read)
+	}
+
+	t.Log("✓ Lineage graph round-trips through JSON")
+}
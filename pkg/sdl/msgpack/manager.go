@@ -0,0 +1,230 @@
+// Package msgpack serializes the shared Avro models as MessagePack, a
+// schemaless binary format. Field names come from the models' json tags, so
+// a MessagePack document has the same keys as the models' JSON encoding.
+package msgpack
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"go-transport-prac/internal/recordio"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+// ContentType is the media type of MessagePack payloads
+const ContentType = "application/msgpack"
+
+// FileExtension is the extension of MessagePack files
+const FileExtension = ".msgpack"
+
+// Manager handles MessagePack serialization and deserialization
+type Manager struct {
+	baseDir string
+	clock   types.Clock
+	// arrayStructs encodes structs as arrays of field values
+	arrayStructs bool
+}
+
+var _ types.Serializer = (*Manager)(nil)
+
+// NewManager creates a new MessagePack manager writing files under baseDir
+func NewManager(baseDir string) *Manager {
+	if baseDir == "" {
+		baseDir = "data/msgpack"
+	}
+	return &Manager{baseDir: baseDir, clock: types.SystemClock{}}
+}
+
+// WithClock sets the clock used for sample CreatedAt/UpdatedAt timestamps
+func (m *Manager) WithClock(clock types.Clock) *Manager {
+	m.clock = types.ClockOrSystem(clock)
+	return m
+}
+
+// WithArrayEncodedStructs encodes structs as arrays of field values in field
+// order instead of maps keyed by name. Payloads shrink, but readers must use
+// the same struct definitions and this option too
+func (m *Manager) WithArrayEncodedStructs(on bool) *Manager {
+	m.arrayStructs = on
+	return m
+}
+
+// ContentType returns the media type of MessagePack payloads
+func (m *Manager) ContentType() string {
+	return ContentType
+}
+
+// FileExtension returns the extension of MessagePack files
+func (m *Manager) FileExtension() string {
+	return FileExtension
+}
+
+func (m *Manager) newEncoder(w io.Writer) *msgpack.Encoder {
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	enc.UseArrayEncodedStructs(m.arrayStructs)
+	return enc
+}
+
+func (m *Manager) newDecoder(r io.Reader) utcDecoder {
+	dec := msgpack.NewDecoder(r)
+	dec.SetCustomStructTag("json")
+	return utcDecoder{dec}
+}
+
+// utcDecoder converts the times it decodes to UTC
+type utcDecoder struct {
+	*msgpack.Decoder
+}
+
+func (d utcDecoder) Decode(v any) error {
+	if err := d.Decoder.Decode(v); err != nil {
+		return err
+	}
+	toUTC(reflect.ValueOf(v))
+	return nil
+}
+
+// Serialize encodes any value as one MessagePack document
+func (m *Manager) Serialize(data any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := m.newEncoder(&buf).Encode(data); err != nil {
+		return nil, fmt.Errorf("failed to encode: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Deserialize decodes one MessagePack document into target, a pointer.
+// Times come back in UTC
+func (m *Manager) Deserialize(data []byte, target any) error {
+	if len(data) == 0 {
+		return fmt.Errorf("data cannot be empty")
+	}
+	if err := m.newDecoder(bytes.NewReader(data)).Decode(target); err != nil {
+		return fmt.Errorf("failed to decode: %w", err)
+	}
+	return nil
+}
+
+// SerializeUser serializes a user to MessagePack
+func (m *Manager) SerializeUser(user avro.User) ([]byte, error) {
+	return m.Serialize(user)
+}
+
+// DeserializeUser deserializes a user from MessagePack
+func (m *Manager) DeserializeUser(data []byte) (avro.User, error) {
+	var user avro.User
+	if err := m.Deserialize(data, &user); err != nil {
+		return avro.User{}, fmt.Errorf("failed to deserialize user: %w", err)
+	}
+	return user, nil
+}
+
+// SerializeProduct serializes a product to MessagePack
+func (m *Manager) SerializeProduct(product avro.Product) ([]byte, error) {
+	return m.Serialize(product)
+}
+
+// DeserializeProduct deserializes a product from MessagePack
+func (m *Manager) DeserializeProduct(data []byte) (avro.Product, error) {
+	var product avro.Product
+	if err := m.Deserialize(data, &product); err != nil {
+		return avro.Product{}, fmt.Errorf("failed to deserialize product: %w", err)
+	}
+	return product, nil
+}
+
+// SerializeOrder serializes an order to MessagePack
+func (m *Manager) SerializeOrder(order avro.Order) ([]byte, error) {
+	return m.Serialize(order)
+}
+
+// DeserializeOrder deserializes an order from MessagePack
+func (m *Manager) DeserializeOrder(data []byte) (avro.Order, error) {
+	var order avro.Order
+	if err := m.Deserialize(data, &order); err != nil {
+		return avro.Order{}, fmt.Errorf("failed to deserialize order: %w", err)
+	}
+	return order, nil
+}
+
+// EncodeUsers writes users to w as MessagePack documents back to back
+func (m *Manager) EncodeUsers(w io.Writer, users []avro.User) error {
+	return recordio.EncodeAll(m.newEncoder(w), users)
+}
+
+// DecodeUsers reads MessagePack user documents from r until it ends
+func (m *Manager) DecodeUsers(r io.Reader) ([]avro.User, error) {
+	return recordio.DecodeAll[avro.User](m.newDecoder(r))
+}
+
+// WriteUsersToFile writes users to a MessagePack file under the base directory
+func (m *Manager) WriteUsersToFile(filename string, users []avro.User) error {
+	return recordio.WriteFile(filepath.Join(m.baseDir, filename), m.newEncoder, users)
+}
+
+// ReadUsersFromFile reads users from a MessagePack file under the base directory
+func (m *Manager) ReadUsersFromFile(filename string) ([]avro.User, error) {
+	return recordio.ReadFile[avro.User](filepath.Join(m.baseDir, filename), m.newDecoder)
+}
+
+// WriteProductsToFile writes products to a MessagePack file under the base directory
+func (m *Manager) WriteProductsToFile(filename string, products []avro.Product) error {
+	return recordio.WriteFile(filepath.Join(m.baseDir, filename), m.newEncoder, products)
+}
+
+// ReadProductsFromFile reads products from a MessagePack file under the base directory
+func (m *Manager) ReadProductsFromFile(filename string) ([]avro.Product, error) {
+	return recordio.ReadFile[avro.Product](filepath.Join(m.baseDir, filename), m.newDecoder)
+}
+
+// WriteOrdersToFile writes orders to a MessagePack file under the base directory
+func (m *Manager) WriteOrdersToFile(filename string, orders []avro.Order) error {
+	return recordio.WriteFile(filepath.Join(m.baseDir, filename), m.newEncoder, orders)
+}
+
+// ReadOrdersFromFile reads orders from a MessagePack file under the base directory
+func (m *Manager) ReadOrdersFromFile(filename string) ([]avro.Order, error) {
+	return recordio.ReadFile[avro.Order](filepath.Join(m.baseDir, filename), m.newDecoder)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// toUTC converts every time reachable from v through pointers, structs and
+// slices to UTC, as MessagePack timestamps decode in the local time zone
+func toUTC(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			toUTC(v.Elem())
+		}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			if v.CanSet() {
+				v.Set(reflect.ValueOf(v.Interface().(time.Time).UTC()))
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				toUTC(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		switch v.Type().Elem().Kind() {
+		case reflect.Pointer, reflect.Struct, reflect.Slice, reflect.Array:
+		default:
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			toUTC(v.Index(i))
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package msgpack
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+func TestMessagePackRoundTrip(t *testing.T) {
+	manager := NewManager("").WithClock(testutil.NewDefaultFakeClock())
+
+	user := manager.CreateSampleUsers(1)[0]
+	data, err := manager.SerializeUser(user)
+	if err != nil {
+		t.Fatalf("Failed to serialize user: %v", err)
+	}
+	back, err := manager.DeserializeUser(data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize user: %v", err)
+	}
+	if !reflect.DeepEqual(back, user) {
+		t.Errorf("User changed across MessagePack:\n got %+v\nwant %+v", back, user)
+	}
+
+	// A product with an exact decimal amount and a release date
+	product := manager.CreateSampleProducts(2)[1]
+	release := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	product.ReleaseDate = &release
+	product.Price.Amount = avro.NewDecimal(123456, 2)
+	data, err = manager.SerializeProduct(product)
+	if err != nil {
+		t.Fatalf("Failed to serialize product: %v", err)
+	}
+	backProduct, err := manager.DeserializeProduct(data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize product: %v", err)
+	}
+	if backProduct.Price.Amount.Cmp(product.Price.Amount) != 0 {
+		t.Errorf("Expected amount %s, got %s", product.Price.Amount, backProduct.Price.Amount)
+	}
+	backProduct.Price.Amount, product.Price.Amount = nil, nil
+	if !reflect.DeepEqual(backProduct, product) {
+		t.Errorf("Product changed across MessagePack:\n got %+v\nwant %+v", backProduct, product)
+	}
+
+	for _, order := range manager.CreateSampleOrders(2) {
+		data, err := manager.SerializeOrder(order)
+		if err != nil {
+			t.Fatalf("Failed to serialize order: %v", err)
+		}
+		back, err := manager.DeserializeOrder(data)
+		if err != nil {
+			t.Fatalf("Failed to deserialize order: %v", err)
+		}
+		if !reflect.DeepEqual(back, order) {
+			t.Errorf("Order changed across MessagePack:\n got %+v\nwant %+v", back, order)
+		}
+	}
+
+	if _, err := manager.DeserializeUser(nil); err == nil {
+		t.Error("Expected empty data to be rejected")
+	}
+	if _, err := manager.DeserializeUser([]byte{0xc1}); err == nil {
+		t.Error("Expected an invalid document to be rejected")
+	}
+
+	t.Log("✓ Users, products and orders round trip through MessagePack")
+}
+
+func TestMessagePackKeys(t *testing.T) {
+	manager := NewManager("").WithClock(testutil.NewDefaultFakeClock())
+	user := manager.CreateSampleUsers(1)[0]
+
+	// The generic Serializer methods use the json field names
+	var serializer interface {
+		Serialize(any) ([]byte, error)
+		Deserialize([]byte, any) error
+	} = manager
+	data, err := serializer.Serialize(user)
+	if err != nil {
+		t.Fatalf("Failed to serialize: %v", err)
+	}
+	var generic map[string]any
+	if err := serializer.Deserialize(data, &generic); err != nil {
+		t.Fatalf("Failed to decode into a map: %v", err)
+	}
+	if generic["email"] != user.Email || generic["createdAt"] == nil {
+		t.Errorf("Expected json field names, got %v", generic)
+	}
+	if manager.ContentType() != "application/msgpack" || manager.FileExtension() != ".msgpack" {
+		t.Errorf("Unexpected content type %s or extension %s", manager.ContentType(), manager.FileExtension())
+	}
+
+	// MessagePack is smaller than JSON, and smaller still without field names
+	jsonData, _ := json.Marshal(user)
+	arrays, err := NewManager("").WithArrayEncodedStructs(true).SerializeUser(user)
+	if err != nil {
+		t.Fatalf("Failed to serialize as arrays: %v", err)
+	}
+	if len(data) >= len(jsonData) || len(arrays) >= len(data) {
+		t.Errorf("Expected %d (arrays) < %d (maps) < %d (json) bytes", len(arrays), len(data), len(jsonData))
+	}
+	back, err := NewManager("").WithArrayEncodedStructs(true).DeserializeUser(arrays)
+	if err != nil || !reflect.DeepEqual(back, user) {
+		t.Errorf("User changed across array-encoded MessagePack: %v", err)
+	}
+
+	t.Log("✓ MessagePack documents use json field names")
+}
+
+func TestMessagePackFiles(t *testing.T) {
+	testDir := "tmp/test_msgpack_files"
+	manager := NewManager(testDir).WithClock(testutil.NewDefaultFakeClock())
+	defer os.RemoveAll(testDir)
+
+	users := manager.CreateSampleUsers(25)
+	if err := manager.WriteUsersToFile("users.msgpack", users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	back, err := manager.ReadUsersFromFile("users.msgpack")
+	if err != nil || !reflect.DeepEqual(back, users) {
+		t.Fatalf("Users changed across the file (%v)", err)
+	}
+
+	products := manager.CreateSampleProducts(10)
+	if err := manager.WriteProductsToFile("products.msgpack", products); err != nil {
+		t.Fatalf("Failed to write products: %v", err)
+	}
+	if back, err := manager.ReadProductsFromFile("products.msgpack"); err != nil || !reflect.DeepEqual(back, products) {
+		t.Errorf("Products changed across the file (%v)", err)
+	}
+
+	orders := manager.CreateSampleOrders(10)
+	if err := manager.WriteOrdersToFile("orders.msgpack", orders); err != nil {
+		t.Fatalf("Failed to write orders: %v", err)
+	}
+	if back, err := manager.ReadOrdersFromFile("orders.msgpack"); err != nil || !reflect.DeepEqual(back, orders) {
+		t.Errorf("Orders changed across the file (%v)", err)
+	}
+
+	// A truncated stream fails instead of dropping the last user
+	var buf bytes.Buffer
+	if err := manager.EncodeUsers(&buf, users[:2]); err != nil {
+		t.Fatalf("Failed to encode users: %v", err)
+	}
+	if _, err := manager.DecodeUsers(bytes.NewReader(buf.Bytes()[:buf.Len()-3])); err == nil {
+		t.Error("Expected a truncated stream to fail")
+	}
+	if _, err := manager.ReadUsersFromFile("missing.msgpack"); err == nil {
+		t.Error("Expected a missing file to fail")
+	}
+
+	t.Log("✓ MessagePack files hold users, products and orders")
+}
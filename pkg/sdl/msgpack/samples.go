@@ -0,0 +1,150 @@
+package msgpack
+
+import (
+	"fmt"
+	"time"
+
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+// CreateSampleUsers creates sample user data for testing
+func (m *Manager) CreateSampleUsers(count int) []avro.User {
+	users := make([]avro.User, count)
+	now := m.clock.Now().UTC()
+
+	for i := range users {
+		phone := fmt.Sprintf("+1-555-%04d", i+1000)
+		users[i] = avro.User{
+			ID:     int64(i + 1),
+			Email:  fmt.Sprintf("user%d@example.com", i+1),
+			Name:   fmt.Sprintf("User %d", i+1),
+			Status: avro.UserStatusActive,
+			Profile: &avro.Profile{
+				FirstName: fmt.Sprintf("First%d", i+1),
+				LastName:  fmt.Sprintf("Last%d", i+1),
+				Phone:     &phone,
+				Address: &avro.Address{
+					Street:     fmt.Sprintf("%d Main St", (i+1)*100),
+					City:       "Test City",
+					State:      "TS",
+					PostalCode: fmt.Sprintf("%05d", i+10000),
+					Country:    "USA",
+				},
+				Interests: []string{"technology", "sports", "music"},
+				Metadata:  map[string]string{"source": "sample_data", "batch_id": fmt.Sprintf("batch_%d", i/100)},
+			},
+			CreatedAt: now.Add(-time.Duration(i) * time.Hour),
+			UpdatedAt: now,
+		}
+	}
+	return users
+}
+
+// CreateSampleProducts creates sample product data for testing
+func (m *Manager) CreateSampleProducts(count int) []avro.Product {
+	products := make([]avro.Product, count)
+	now := m.clock.Now().UTC()
+
+	for i := range products {
+		var discount *float32
+		if d := float32(i%20) / 100; d > 0 {
+			discount = &d
+		}
+		products[i] = avro.Product{
+			ID:          int64(i + 1),
+			Name:        fmt.Sprintf("Product %d", i+1),
+			Description: fmt.Sprintf("Description for product %d", i+1),
+			SKU:         fmt.Sprintf("SKU-%06d", i+1),
+			Price: avro.Price{
+				Currency:           "USD",
+				AmountCents:        int64((i%100 + 1) * 100),
+				DiscountPercentage: discount,
+			},
+			Inventory: avro.Inventory{
+				Quantity:       int32(i%1000 + 100),
+				Reserved:       int32(i % 50),
+				Available:      int32(i%1000 + 100 - i%50),
+				TrackInventory: true,
+				ReorderLevel:   int32(i%20 + 10),
+				MaxStock:       int32(i%1000 + 1000),
+			},
+			Categories:     []string{"Electronics", "Computers"},
+			Tags:           []string{"sample", fmt.Sprintf("tag%d", i%10)},
+			Status:         avro.ProductStatusActive,
+			Specifications: map[string]string{"color": []string{"red", "blue", "green"}[i%3]},
+			CreatedAt:      now.Add(-time.Duration(i) * 24 * time.Hour),
+			UpdatedAt:      now,
+		}
+	}
+	return products
+}
+
+// CreateSampleOrders creates sample orders of one to three items; every
+// other order has shipped
+func (m *Manager) CreateSampleOrders(count int) []avro.Order {
+	orders := make([]avro.Order, count)
+	now := m.clock.Now().UTC()
+	usd := func(cents int64) avro.Price { return avro.Price{Currency: "USD", AmountCents: cents} }
+
+	for i := range orders {
+		items := make([]avro.OrderItem, i%3+1)
+		var subtotal int64
+		for j := range items {
+			unit := int64((j + 1) * 1500)
+			items[j] = avro.OrderItem{
+				ProductID:   int64(j + 1),
+				ProductName: fmt.Sprintf("Product %d", j+1),
+				ProductSKU:  fmt.Sprintf("SKU-%06d", j+1),
+				Quantity:    int32(j + 1),
+				UnitPrice:   usd(unit),
+				TotalPrice:  usd(unit * int64(j+1)),
+			}
+			subtotal += unit * int64(j+1)
+		}
+		tax := subtotal / 10
+
+		created := now.Add(-time.Duration(i) * time.Hour)
+		orders[i] = avro.Order{
+			ID:          int64(i + 1),
+			UserID:      int64(i%10 + 1),
+			OrderNumber: fmt.Sprintf("ORD-%06d", i+1),
+			Status:      avro.OrderStatusConfirmed,
+			Items:       items,
+			Summary: avro.OrderSummary{
+				Subtotal:     usd(subtotal),
+				Tax:          usd(tax),
+				ShippingCost: usd(500),
+				Discount:     usd(0),
+				Total:        usd(subtotal + tax + 500),
+				TotalItems:   int32(len(items)),
+			},
+			PaymentInfo: &avro.PaymentInfo{
+				Method: "credit_card",
+				Status: avro.PaymentStatusCaptured,
+				Amount: usd(subtotal + tax + 500),
+			},
+			CreatedAt: created,
+			UpdatedAt: now,
+		}
+		if i%2 == 0 {
+			tracking := fmt.Sprintf("TRK%08d", i+1)
+			shipped := created.Add(2 * time.Hour)
+			orders[i].Status = avro.OrderStatusShipped
+			orders[i].ShippedAt = &shipped
+			orders[i].ShippingInfo = &avro.ShippingInfo{
+				Address: avro.ShippingAddress{
+					RecipientName: fmt.Sprintf("User %d", i%10+1),
+					Street:        "1 Main St",
+					City:          "Test City",
+					State:         "TS",
+					PostalCode:    "10000",
+					Country:       "USA",
+				},
+				Method:         "standard",
+				TrackingNumber: &tracking,
+				Cost:           usd(500),
+			}
+		}
+	}
+	return orders
+}
@@ -0,0 +1,296 @@
+// Package ndjson streams values as newline-delimited JSON, one document per
+// line, optionally compressed with gzip or zstd, for tools such as jq or
+// BigQuery loads that read NDJSON. Readers detect the compression from the
+// stream itself, so compressed and plain files import the same way.
+package ndjson
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+
+	"go-transport-prac/internal/atomicfile"
+)
+
+// Compression names how a stream is compressed
+type Compression string
+
+const (
+	None Compression = "none"
+	Gzip Compression = "gzip"
+	Zstd Compression = "zstd"
+)
+
+// MaxLineSize is the longest line a Reader accepts
+const MaxLineSize = 64 << 20
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// ParseCompression parses a compression name; an empty name is None
+func ParseCompression(name string) (Compression, error) {
+	switch c := Compression(strings.ToLower(name)); c {
+	case "", None:
+		return None, nil
+	case Gzip, Zstd:
+		return c, nil
+	}
+	return "", fmt.Errorf("unknown compression %q: expected none, gzip or zstd", name)
+}
+
+// CompressionOf returns the compression a file name implies: .gz is gzip,
+// .zst zstd and anything else none
+func CompressionOf(path string) Compression {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gz":
+		return Gzip
+	case ".zst":
+		return Zstd
+	}
+	return None
+}
+
+// Writer encodes values to w one line each
+type Writer struct {
+	buffered   *bufio.Writer
+	compressor io.WriteCloser
+	encoder    *json.Encoder
+	count      int
+}
+
+// NewWriter returns a writer compressing its lines with c. Close flushes the
+// writer without closing w
+func NewWriter(w io.Writer, c Compression) (*Writer, error) {
+	writer := &Writer{}
+	switch c {
+	case "", None:
+	case Gzip:
+		writer.compressor = gzip.NewWriter(w)
+	case Zstd:
+		encoder, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd writer: %w", err)
+		}
+		writer.compressor = encoder
+	default:
+		return nil, fmt.Errorf("unknown compression %q", c)
+	}
+	if writer.compressor != nil {
+		w = writer.compressor
+	}
+	writer.buffered = bufio.NewWriter(w)
+	writer.encoder = json.NewEncoder(writer.buffered)
+	writer.encoder.SetEscapeHTML(false)
+	return writer, nil
+}
+
+// Write encodes v as one line
+func (w *Writer) Write(v interface{}) error {
+	if err := w.encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode line %d: %w", w.count+1, err)
+	}
+	w.count++
+	return nil
+}
+
+// Count returns the number of lines written
+func (w *Writer) Count() int {
+	return w.count
+}
+
+// Close flushes buffered lines and ends the compressed stream
+func (w *Writer) Close() error {
+	if err := w.buffered.Flush(); err != nil {
+		return fmt.Errorf("failed to flush ndjson: %w", err)
+	}
+	if w.compressor != nil {
+		if err := w.compressor.Close(); err != nil {
+			return fmt.Errorf("failed to close compressor: %w", err)
+		}
+	}
+	return nil
+}
+
+// Reader decodes the lines of a stream into T one at a time. Blank lines are
+// skipped.
+//
+//	reader, err := ndjson.NewReader[avro.User](file)
+//	if err != nil {
+//		return err
+//	}
+//	defer reader.Close()
+//	for reader.Next() {
+//		user := reader.Value()
+//	}
+//	return reader.Err()
+type Reader[T any] struct {
+	scanner      *bufio.Scanner
+	decompressor io.Closer
+	line         int
+	value        T
+	err          error
+}
+
+// NewReader returns a reader of r, decompressing gzip and zstd streams
+func NewReader[T any](r io.Reader) (*Reader[T], error) {
+	buffered := bufio.NewReader(r)
+	header, err := buffered.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read ndjson: %w", err)
+	}
+
+	reader := &Reader[T]{}
+	var source io.Reader = buffered
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		source, reader.decompressor = gz, gz
+	case bytes.HasPrefix(header, zstdMagic):
+		decoder, err := zstd.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd reader: %w", err)
+		}
+		source, reader.decompressor = decoder, decoder.IOReadCloser()
+	}
+
+	reader.scanner = bufio.NewScanner(source)
+	reader.scanner.Buffer(make([]byte, 0, 64<<10), MaxLineSize)
+	return reader, nil
+}
+
+// Next decodes the next line and reports whether there is one
+func (r *Reader[T]) Next() bool {
+	var zero T
+	r.value = zero
+	if r.err != nil {
+		return false
+	}
+	for r.scanner.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(line, &r.value); err != nil {
+			r.err = fmt.Errorf("failed to decode line %d: %w", r.line, err)
+			return false
+		}
+		return true
+	}
+	if err := r.scanner.Err(); err != nil {
+		r.err = fmt.Errorf("failed to read line %d: %w", r.line+1, err)
+	}
+	return false
+}
+
+// Value returns the value Next decoded
+func (r *Reader[T]) Value() T {
+	return r.value
+}
+
+// Line returns the line number of the current value, counting from 1
+func (r *Reader[T]) Line() int {
+	return r.line
+}
+
+// Err returns the error that ended the iteration, or nil at the end of the stream
+func (r *Reader[T]) Err() error {
+	return r.err
+}
+
+// Close releases the decompressor without closing the underlying reader
+func (r *Reader[T]) Close() error {
+	if r.decompressor != nil {
+		return r.decompressor.Close()
+	}
+	return nil
+}
+
+// Export writes items to w as NDJSON compressed with c, returning the number
+// of lines written
+func Export[T any](w io.Writer, items []T, c Compression) (int, error) {
+	writer, err := NewWriter(w, c)
+	if err != nil {
+		return 0, err
+	}
+	for i := range items {
+		if err := writer.Write(&items[i]); err != nil {
+			return writer.Count(), err
+		}
+	}
+	return writer.Count(), writer.Close()
+}
+
+// Import reads every line of r, plain or compressed, into a T
+func Import[T any](r io.Reader) ([]T, error) {
+	reader, err := NewReader[T](r)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var items []T
+	for reader.Next() {
+		items = append(items, reader.Value())
+	}
+	return items, reader.Err()
+}
+
+// ExportFile writes items to path, compressed as its extension implies. The
+// file appears only once it is complete
+func ExportFile[T any](path string, items []T) (int, error) {
+	var n int
+	err := CreateFile(path, func(w *Writer) error {
+		for i := range items {
+			if err := w.Write(&items[i]); err != nil {
+				return err
+			}
+		}
+		n = w.Count()
+		return nil
+	})
+	return n, err
+}
+
+// ImportFile reads every line of the file at path into a T
+func ImportFile[T any](path string) ([]T, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+	return Import[T](file)
+}
+
+// CreateFile calls fn with a writer to path, compressed as its extension
+// implies, and keeps the file only if fn and closing the writer succeed
+func CreateFile(path string, fn func(*Writer) error) error {
+	file, err := atomicfile.Create(path, false)
+	if err != nil {
+		return err
+	}
+	writer, err := NewWriter(file, CompressionOf(path))
+	if err == nil {
+		if err = fn(writer); err == nil {
+			err = writer.Close()
+		}
+	}
+	if err != nil {
+		file.Abort()
+		return err
+	}
+	return file.Commit()
+}
@@ -0,0 +1,129 @@
+package ndjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+func sampleUsers(t *testing.T, n int) []avro.User {
+	manager, err := avro.NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create avro manager: %v", err)
+	}
+	return manager.CreateSampleUsers(n)
+}
+
+func TestRoundTrip(t *testing.T) {
+	users := sampleUsers(t, 25)
+
+	for _, c := range []Compression{None, Gzip, Zstd} {
+		var buf bytes.Buffer
+		n, err := Export(&buf, users, c)
+		if err != nil || n != len(users) {
+			t.Fatalf("%s: failed to export %d users: %d, %v", c, len(users), n, err)
+		}
+		if c == None {
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			if len(lines) != len(users) {
+				t.Fatalf("Expected one line per user, got %d", len(lines))
+			}
+			var first map[string]interface{}
+			if err := json.Unmarshal([]byte(lines[0]), &first); err != nil || first["email"] != users[0].Email {
+				t.Errorf("Expected each line to be a JSON user, got %s (%v)", lines[0], err)
+			}
+		}
+
+		back, err := Import[avro.User](&buf)
+		if err != nil {
+			t.Fatalf("%s: failed to import: %v", c, err)
+		}
+		if len(back) != len(users) {
+			t.Fatalf("%s: expected %d users, got %d", c, len(users), len(back))
+		}
+		for i := range users {
+			if back[i].ID != users[i].ID || !reflect.DeepEqual(back[i].Profile, users[i].Profile) || !back[i].CreatedAt.Equal(users[i].CreatedAt) {
+				t.Errorf("%s: user %d mismatch: %+v", c, i, back[i])
+			}
+		}
+	}
+
+	t.Log("✓ Users round-trip through plain, gzip and zstd NDJSON")
+}
+
+func TestReaderErrors(t *testing.T) {
+	input := "{\"id\":1}\n\n  \n{\"id\":2}\n{\"id\":\n"
+	reader, err := NewReader[avro.User](strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Failed to create reader: %v", err)
+	}
+	var ids []int64
+	for reader.Next() {
+		ids = append(ids, reader.Value().ID)
+	}
+	if !reflect.DeepEqual(ids, []int64{1, 2}) {
+		t.Errorf("Expected blank lines to be skipped, got %v", ids)
+	}
+	if err := reader.Err(); err == nil || !strings.Contains(err.Error(), "line 5") {
+		t.Errorf("Expected the malformed line to be reported, got %v", err)
+	}
+
+	if items, err := Import[avro.User](strings.NewReader("")); err != nil || len(items) != 0 {
+		t.Errorf("Expected an empty stream to import nothing, got %v, %v", items, err)
+	}
+	if _, err := ParseCompression("lz4"); err == nil {
+		t.Error("Expected an unknown compression to fail")
+	}
+	if _, err := NewWriter(&bytes.Buffer{}, "brotli"); err == nil {
+		t.Error("Expected a writer with an unknown compression to fail")
+	}
+
+	t.Log("✓ Malformed lines report their line number")
+}
+
+func TestFiles(t *testing.T) {
+	dir := "tmp/test_files"
+	os.RemoveAll(dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	users := sampleUsers(t, 10)
+	for _, name := range []string{"users.ndjson", "users.ndjson.gz", "users.ndjson.zst"} {
+		path := filepath.Join(dir, name)
+		if _, err := ExportFile(path, users); err != nil {
+			t.Fatalf("Failed to export %s: %v", name, err)
+		}
+		back, err := ImportFile[avro.User](path)
+		if err != nil || len(back) != len(users) {
+			t.Fatalf("Failed to import %s: %d users, %v", name, len(back), err)
+		}
+	}
+
+	plain, _ := os.Stat(filepath.Join(dir, "users.ndjson"))
+	for _, name := range []string{"users.ndjson.gz", "users.ndjson.zst"} {
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		if int64(len(data)) >= plain.Size() || bytes.HasPrefix(data, []byte("{")) {
+			t.Errorf("Expected %s to be compressed, got %d bytes for %d plain", name, len(data), plain.Size())
+		}
+	}
+
+	// A failed export leaves no file behind
+	failed := filepath.Join(dir, "failed.ndjson")
+	err := CreateFile(failed, func(w *Writer) error { return w.Write(func() {}) })
+	if err == nil {
+		t.Fatal("Expected an unencodable value to fail")
+	}
+	if _, err := os.Stat(failed); !os.IsNotExist(err) {
+		t.Errorf("Expected no file after a failed export, got %v", err)
+	}
+
+	t.Log("✓ Files are compressed by extension and written atomically")
+}
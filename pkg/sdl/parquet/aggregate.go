@@ -0,0 +1,377 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+)
+
+// Aggregator computes grouped aggregates over Parquet files, decoding only the
+// columns it groups, filters or measures, e.g.
+//
+//	Agg().GroupBy("status").Count().Run(manager, "users.parquet")
+type Aggregator struct {
+	filter   Filter
+	groupBy  []string
+	bucket   string
+	width    time.Duration
+	count    bool
+	measures []measure
+}
+
+type measureKind int
+
+const (
+	measureSum measureKind = iota
+	measureMin
+	measureMax
+)
+
+type measure struct {
+	kind   measureKind
+	column string
+}
+
+// Agg starts an aggregation
+func Agg() *Aggregator {
+	return &Aggregator{}
+}
+
+// Where restricts the aggregation to rows matching filter; row groups the
+// filter rules out are skipped as in ScanUsers
+func (a *Aggregator) Where(filter Filter) *Aggregator {
+	a.filter = filter
+	return a
+}
+
+// GroupBy groups rows by the values of non-repeated leaf columns
+func (a *Aggregator) GroupBy(columns ...string) *Aggregator {
+	a.groupBy = append(a.groupBy, columns...)
+	return a
+}
+
+// Bucket groups rows by the start of the width-long time bucket their
+// timestamp column falls into, in addition to any GroupBy columns
+func (a *Aggregator) Bucket(column string, width time.Duration) *Aggregator {
+	a.bucket, a.width = column, width
+	return a
+}
+
+// Count counts the rows of each group
+func (a *Aggregator) Count() *Aggregator {
+	a.count = true
+	return a
+}
+
+// Sum adds up the non-null values of a numeric column
+func (a *Aggregator) Sum(column string) *Aggregator {
+	a.measures = append(a.measures, measure{kind: measureSum, column: column})
+	return a
+}
+
+// Min keeps the smallest non-null value of a numeric column
+func (a *Aggregator) Min(column string) *Aggregator {
+	a.measures = append(a.measures, measure{kind: measureMin, column: column})
+	return a
+}
+
+// Max keeps the largest non-null value of a numeric column
+func (a *Aggregator) Max(column string) *Aggregator {
+	a.measures = append(a.measures, measure{kind: measureMax, column: column})
+	return a
+}
+
+// AggregateRow holds the aggregates of one group
+type AggregateRow struct {
+	// Group holds the GroupBy values in order; nulls are empty strings and
+	// timestamps are formatted as RFC 3339
+	Group []string
+	// Bucket is the start of the time bucket, zero without Bucket or for
+	// rows whose timestamp is null
+	Bucket time.Time
+	Count  int64
+	// Sum, Min and Max are keyed by column; Min and Max leave out columns
+	// that are null throughout the group
+	Sum map[string]float64
+	Min map[string]float64
+	Max map[string]float64
+}
+
+// AggregateResult holds the groups ordered by bucket, then group values
+type AggregateResult struct {
+	Rows  []AggregateRow
+	Stats ScanStats
+}
+
+// Group returns the row with the given group values and bucket, if any
+func (r *AggregateResult) Group(bucket time.Time, values ...string) (AggregateRow, bool) {
+	for _, row := range r.Rows {
+		if row.Bucket.Equal(bucket) && slices.Equal(row.Group, values) {
+			return row, true
+		}
+	}
+	return AggregateRow{}, false
+}
+
+// Counts returns the row counts keyed by the first group value, a shorthand
+// for single-column groupings without buckets
+func (r *AggregateResult) Counts() map[string]int64 {
+	counts := make(map[string]int64, len(r.Rows))
+	for _, row := range r.Rows {
+		key := ""
+		if len(row.Group) > 0 {
+			key = row.Group[0]
+		}
+		counts[key] += row.Count
+	}
+	return counts
+}
+
+// Run aggregates the files together, reading them through the manager
+func (a *Aggregator) Run(m *SimpleManager, files ...string) (*AggregateResult, error) {
+	return a.RunContext(context.Background(), m, files...)
+}
+
+// RunContext aggregates the files together, reading them through the
+// manager, stopping with ctx.Err() once ctx is done
+func (a *Aggregator) RunContext(ctx context.Context, m *SimpleManager, files ...string) (*AggregateResult, error) {
+	if !a.count && len(a.measures) == 0 {
+		return nil, fmt.Errorf("aggregation has no Count, Sum, Min or Max")
+	}
+	if a.bucket != "" && a.width <= 0 {
+		return nil, fmt.Errorf("bucket width must be positive, got %s", a.width)
+	}
+
+	groups := make(map[string]*AggregateRow)
+	result := &AggregateResult{}
+	for _, filename := range files {
+		if err := a.aggregateFile(ctx, m, filename, groups, &result.Stats); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("failed to aggregate %s: %w", filename, err)
+		}
+	}
+
+	result.Rows = make([]AggregateRow, 0, len(groups))
+	for _, row := range groups {
+		result.Rows = append(result.Rows, *row)
+	}
+	slices.SortFunc(result.Rows, func(x, y AggregateRow) int {
+		if c := x.Bucket.Compare(y.Bucket); c != 0 {
+			return c
+		}
+		return slices.Compare(x.Group, y.Group)
+	})
+	return result, nil
+}
+
+// aggregateFile folds the rows of one file into groups, a row group at a time
+func (a *Aggregator) aggregateFile(ctx context.Context, m *SimpleManager, filename string, groups map[string]*AggregateRow, stats *ScanStats) error {
+	file, size, err := m.openFile(ctx, filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	pf, err := parquet.OpenFile(file, size)
+	if err != nil {
+		return fmt.Errorf("failed to open parquet file: %w", err)
+	}
+
+	predicates := make([]resolvedPredicate, len(a.filter))
+	for i, p := range a.filter {
+		if predicates[i], err = resolvePredicate(pf, p); err != nil {
+			return err
+		}
+	}
+	groupLeaves := make([]*parquet.Column, len(a.groupBy))
+	for i, path := range a.groupBy {
+		if groupLeaves[i], err = leafColumn(pf, path); err != nil {
+			return err
+		}
+	}
+	var bucketLeaf *parquet.Column
+	if a.bucket != "" {
+		if bucketLeaf, err = leafColumn(pf, a.bucket); err != nil {
+			return err
+		}
+		if lt := bucketLeaf.Type().LogicalType(); lt == nil || lt.Timestamp == nil {
+			return fmt.Errorf("bucket column %s is not a timestamp column", a.bucket)
+		}
+	}
+	measureLeaves := make([]*parquet.Column, len(a.measures))
+	for i, ms := range a.measures {
+		if measureLeaves[i], err = leafColumn(pf, ms.column); err != nil {
+			return err
+		}
+		switch measureLeaves[i].Type().Kind() {
+		case parquet.Int32, parquet.Int64, parquet.Float, parquet.Double:
+		default:
+			return fmt.Errorf("column %s is not numeric", ms.column)
+		}
+	}
+
+	metadata := pf.Metadata()
+	indexes := pf.ColumnIndexes()
+	stats.RowGroups += len(pf.RowGroups())
+
+	for i, rg := range pf.RowGroups() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !mayMatch(predicates, metadata.RowGroups[i], i, indexes) {
+			stats.RowGroupsSkipped++
+			continue
+		}
+		stats.RowsScanned += rg.NumRows()
+
+		// Each column is decoded once per row group, however often it is used
+		decoded := make(map[int][]parquet.Value)
+		values := func(leaf *parquet.Column) ([]parquet.Value, error) {
+			if v, ok := decoded[leaf.Index()]; ok {
+				return v, nil
+			}
+			path := strings.Join(leaf.Path(), ".")
+			v, err := chunkValues(rg.ColumnChunks()[leaf.Index()], path)
+			if err != nil {
+				return nil, err
+			}
+			decoded[leaf.Index()] = v
+			return v, nil
+		}
+
+		rows := SelectAll(int(rg.NumRows()))
+		for _, p := range predicates {
+			v, err := values(p.leaf)
+			if err != nil {
+				return err
+			}
+			rows = p.filter(v, rows)
+			if len(rows) == 0 {
+				break
+			}
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		stats.RowsMatched += len(rows)
+
+		groupValues := make([][]parquet.Value, len(groupLeaves))
+		for j, leaf := range groupLeaves {
+			if groupValues[j], err = values(leaf); err != nil {
+				return err
+			}
+		}
+		var bucketValues []parquet.Value
+		if bucketLeaf != nil {
+			if bucketValues, err = values(bucketLeaf); err != nil {
+				return err
+			}
+		}
+		measureValues := make([][]parquet.Value, len(measureLeaves))
+		for j, leaf := range measureLeaves {
+			if measureValues[j], err = values(leaf); err != nil {
+				return err
+			}
+		}
+
+		var key strings.Builder
+		for _, r := range rows {
+			group := make([]string, len(groupLeaves))
+			key.Reset()
+			for j, leaf := range groupLeaves {
+				group[j] = groupValue(leaf, groupValues[j][r])
+				// Nulls key apart from empty strings, though both read back as ""
+				if groupValues[j][r].IsNull() {
+					key.WriteByte(0)
+				} else {
+					key.WriteByte(1)
+				}
+				key.WriteString(strconv.Quote(group[j]))
+			}
+			var bucket time.Time
+			if bucketValues != nil && !bucketValues[r].IsNull() {
+				bucket = timestampTime(bucketLeaf, bucketValues[r].Int64()).Truncate(a.width)
+				key.WriteString(strconv.FormatInt(bucket.UnixNano(), 10))
+			}
+
+			row, ok := groups[key.String()]
+			if !ok {
+				row = &AggregateRow{Group: group, Bucket: bucket}
+				groups[key.String()] = row
+			}
+			if a.count {
+				row.Count++
+			}
+			for j, ms := range a.measures {
+				v := measureValues[j][r]
+				if v.IsNull() {
+					continue
+				}
+				row.add(ms, numericValue(v))
+			}
+		}
+	}
+	return nil
+}
+
+// add folds one non-null value into the row's measure
+func (row *AggregateRow) add(ms measure, v float64) {
+	switch ms.kind {
+	case measureSum:
+		if row.Sum == nil {
+			row.Sum = make(map[string]float64)
+		}
+		row.Sum[ms.column] += v
+	case measureMin:
+		if row.Min == nil {
+			row.Min = make(map[string]float64)
+		}
+		if current, ok := row.Min[ms.column]; !ok || v < current {
+			row.Min[ms.column] = v
+		}
+	case measureMax:
+		if row.Max == nil {
+			row.Max = make(map[string]float64)
+		}
+		if current, ok := row.Max[ms.column]; !ok || v > current {
+			row.Max[ms.column] = v
+		}
+	}
+}
+
+// numericValue converts an integer or floating point value to a float64
+func numericValue(v parquet.Value) float64 {
+	switch v.Kind() {
+	case parquet.Float, parquet.Double:
+		return v.Double()
+	default:
+		return float64(v.Int64())
+	}
+}
+
+// groupValue formats a group-by value as a string
+func groupValue(leaf *parquet.Column, v parquet.Value) string {
+	if v.IsNull() {
+		return ""
+	}
+	switch v.Kind() {
+	case parquet.Boolean:
+		return strconv.FormatBool(v.Boolean())
+	case parquet.Int32, parquet.Int64:
+		if lt := leaf.Type().LogicalType(); lt != nil && lt.Timestamp != nil {
+			return timestampTime(leaf, v.Int64()).Format(time.RFC3339Nano)
+		}
+		return strconv.FormatInt(v.Int64(), 10)
+	case parquet.Float, parquet.Double:
+		return strconv.FormatFloat(v.Double(), 'g', -1, 64)
+	default:
+		return string(v.ByteArray())
+	}
+}
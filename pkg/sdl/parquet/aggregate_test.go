@@ -0,0 +1,164 @@
+package parquet
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAggregatorGroupBy(t *testing.T) {
+	testDir := "tmp/test_aggregate_group"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+	users := writePushdownUsers(t, manager, "users.parquet")
+
+	result, err := Agg().GroupBy("status").Count().Run(manager, "users.parquet")
+	if err != nil {
+		t.Fatalf("Aggregation failed: %v", err)
+	}
+	want := make(map[string]int64)
+	for _, u := range users {
+		want[u.Status]++
+	}
+	counts := result.Counts()
+	if len(counts) != len(want) {
+		t.Fatalf("Expected %d statuses, got %v", len(want), counts)
+	}
+	for status, count := range want {
+		if counts[status] != count {
+			t.Errorf("Status %s: expected %d users, got %d", status, count, counts[status])
+		}
+	}
+	if result.Stats.RowsMatched != len(users) || result.Stats.RowGroupsSkipped != 0 {
+		t.Errorf("Expected every row aggregated, got %+v", result.Stats)
+	}
+
+	// Filters skip row groups, and measures fold per group
+	type idStats struct {
+		count         int64
+		sum, min, max float64
+	}
+	byStatus := make(map[string]*idStats)
+	for _, u := range users {
+		if u.Profile.Address == nil || u.Profile.Address.Country != "USA" {
+			continue
+		}
+		s, ok := byStatus[u.Status]
+		if !ok {
+			s = &idStats{min: float64(u.ID), max: float64(u.ID)}
+			byStatus[u.Status] = s
+		}
+		s.count++
+		s.sum += float64(u.ID)
+		s.min = min(s.min, float64(u.ID))
+		s.max = max(s.max, float64(u.ID))
+	}
+
+	result, err = Agg().
+		Where(Where("profile.address.country", OpEqual, "USA")).
+		GroupBy("profile.address.country", "status").
+		Count().Sum("id").Min("id").Max("id").
+		Run(manager, "users.parquet")
+	if err != nil {
+		t.Fatalf("Filtered aggregation failed: %v", err)
+	}
+	if len(result.Rows) != len(byStatus) {
+		t.Fatalf("Expected %d groups, got %d", len(byStatus), len(result.Rows))
+	}
+	for status, s := range byStatus {
+		row, ok := result.Group(time.Time{}, "USA", status)
+		if !ok {
+			t.Fatalf("Missing group USA/%s", status)
+		}
+		if row.Count != s.count || row.Sum["id"] != s.sum || row.Min["id"] != s.min || row.Max["id"] != s.max {
+			t.Errorf("Group USA/%s: expected %+v, got %+v", status, *s, row)
+		}
+	}
+	if result.Stats.RowGroupsSkipped < 5 {
+		t.Errorf("Expected the country filter to skip row groups, got %+v", result.Stats)
+	}
+
+	t.Log("✓ Aggregator groups, filters and measures columns")
+}
+
+func TestAggregatorBuckets(t *testing.T) {
+	testDir := "tmp/test_aggregate_buckets"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+	users := writePushdownUsers(t, manager, "users.parquet")
+
+	// Groups span files
+	if err := manager.WriteUsers("more.parquet", users[:100]); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	users = append(users, users[:100]...)
+
+	result, err := Agg().Bucket("created_at", 24*time.Hour).Count().Run(manager, "users.parquet", "more.parquet")
+	if err != nil {
+		t.Fatalf("Bucketed aggregation failed: %v", err)
+	}
+	want := make(map[time.Time]int64)
+	for _, u := range users {
+		want[u.CreatedAt.UTC().Truncate(24*time.Hour)]++
+	}
+	if len(result.Rows) != len(want) {
+		t.Fatalf("Expected %d daily buckets, got %d", len(want), len(result.Rows))
+	}
+	for i, row := range result.Rows {
+		if i > 0 && !row.Bucket.After(result.Rows[i-1].Bucket) {
+			t.Fatalf("Buckets out of order at %d: %s after %s", i, row.Bucket, result.Rows[i-1].Bucket)
+		}
+		if row.Count != want[row.Bucket] {
+			t.Errorf("Bucket %s: expected %d users, got %d", row.Bucket, want[row.Bucket], row.Count)
+		}
+	}
+	if first := result.Rows[0].Bucket; !first.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the first bucket on 2024-01-01, got %s", first)
+	}
+
+	// Buckets combine with group-by columns
+	result, err = Agg().GroupBy("status").Bucket("created_at", 24*time.Hour).Count().Run(manager, "users.parquet")
+	if err != nil {
+		t.Fatalf("Grouped bucket aggregation failed: %v", err)
+	}
+	var total int64
+	for _, row := range result.Rows {
+		if len(row.Group) != 1 || row.Bucket.IsZero() {
+			t.Fatalf("Expected a status and bucket per row, got %+v", row)
+		}
+		total += row.Count
+	}
+	if total != 1000 {
+		t.Errorf("Expected 1000 users across groups, got %d", total)
+	}
+
+	t.Log("✓ Aggregator buckets timestamps across files")
+}
+
+func TestAggregatorErrors(t *testing.T) {
+	testDir := "tmp/test_aggregate_errors"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+	writePushdownUsers(t, manager, "users.parquet")
+
+	cases := map[string]*Aggregator{
+		"no measures":       Agg().GroupBy("status"),
+		"non-numeric sum":   Agg().Sum("status"),
+		"unknown column":    Agg().GroupBy("missing").Count(),
+		"non-timestamp":     Agg().Bucket("id", time.Hour).Count(),
+		"zero bucket width": Agg().Bucket("created_at", 0).Count(),
+		"repeated group-by": Agg().GroupBy("profile.interests").Count(),
+		"missing file":      nil,
+	}
+	for name, agg := range cases {
+		files := []string{"users.parquet"}
+		if agg == nil {
+			agg, files = Agg().Count(), []string{"missing.parquet"}
+		}
+		if _, err := agg.Run(manager, files...); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	t.Log("✓ Aggregator rejects invalid aggregations")
+}
@@ -0,0 +1,127 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ConversionEvents are the event types AnalyzeAnalytics reports conversion rates for
+var ConversionEvents = []string{"purchase", "signup"}
+
+// AnalyticsReport summarizes analytics event files
+type AnalyticsReport struct {
+	Events int
+	// Sessions and Users count distinct non-empty session IDs and non-null user IDs
+	Sessions   int
+	Users      int
+	EventTypes map[string]int64
+	// ConversionRates holds, for each of ConversionEvents, the share of
+	// sessions with at least one event of that type
+	ConversionRates map[string]float64
+	// Hourly holds one entry per hour with events, in order
+	Hourly []HourlyAnalytics
+}
+
+// HourlyAnalytics aggregates the events of one hour
+type HourlyAnalytics struct {
+	Hour      time.Time
+	Events    int64
+	Users     int
+	Purchases int64
+}
+
+// AnalyzeAnalytics computes an AnalyticsReport over analytics event files,
+// decoding only the event_type, session_id, user_id and timestamp columns
+func (m *SimpleManager) AnalyzeAnalytics(files ...string) (*AnalyticsReport, error) {
+	return m.AnalyzeAnalyticsContext(context.Background(), files...)
+}
+
+// AnalyzeAnalyticsContext computes an AnalyticsReport over analytics event
+// files, stopping with ctx.Err() once ctx is done
+func (m *SimpleManager) AnalyzeAnalyticsContext(ctx context.Context, files ...string) (*AnalyticsReport, error) {
+	byType, err := Agg().GroupBy("event_type").Count().RunContext(ctx, m, files...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count event types: %w", err)
+	}
+	report := &AnalyticsReport{
+		Events:          byType.Stats.RowsMatched,
+		EventTypes:      byType.Counts(),
+		ConversionRates: make(map[string]float64, len(ConversionEvents)),
+	}
+
+	// One row per session and event type it had
+	sessions, err := Agg().GroupBy("session_id", "event_type").Count().RunContext(ctx, m, files...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count sessions: %w", err)
+	}
+	seen := make(map[string]bool)
+	converted := make(map[string]int)
+	for _, row := range sessions.Rows {
+		session, eventType := row.Group[0], row.Group[1]
+		if session == "" {
+			continue
+		}
+		seen[session] = true
+		converted[eventType]++
+	}
+	report.Sessions = len(seen)
+	for _, eventType := range ConversionEvents {
+		if report.Sessions > 0 {
+			report.ConversionRates[eventType] = float64(converted[eventType]) / float64(report.Sessions)
+		}
+	}
+
+	users, err := Agg().GroupBy("user_id").Count().RunContext(ctx, m, files...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+	for _, row := range users.Rows {
+		if row.Group[0] != "" {
+			report.Users++
+		}
+	}
+
+	if report.Hourly, err = m.hourlyAnalytics(ctx, files); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// hourlyAnalytics buckets the events by hour of their timestamp
+func (m *SimpleManager) hourlyAnalytics(ctx context.Context, files []string) ([]HourlyAnalytics, error) {
+	byType, err := Agg().GroupBy("event_type").Bucket("timestamp", time.Hour).Count().RunContext(ctx, m, files...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bucket events: %w", err)
+	}
+	byUser, err := Agg().GroupBy("user_id").Bucket("timestamp", time.Hour).Count().RunContext(ctx, m, files...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bucket users: %w", err)
+	}
+
+	// Both results are ordered by bucket, so hours are appended in order
+	var hourly []HourlyAnalytics
+	index := make(map[time.Time]int)
+	hour := func(t time.Time) *HourlyAnalytics {
+		i, ok := index[t]
+		if !ok {
+			i = len(hourly)
+			index[t] = i
+			hourly = append(hourly, HourlyAnalytics{Hour: t})
+		}
+		return &hourly[i]
+	}
+	for _, row := range byType.Rows {
+		h := hour(row.Bucket)
+		h.Events += row.Count
+		if row.Group[0] == "purchase" {
+			h.Purchases += row.Count
+		}
+	}
+	for _, row := range byUser.Rows {
+		if row.Group[0] != "" {
+			hour(row.Bucket).Users++
+		}
+	}
+	return hourly, nil
+}
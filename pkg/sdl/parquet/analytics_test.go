@@ -0,0 +1,75 @@
+package parquet
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeAnalytics(t *testing.T) {
+	testDir := "tmp/test_analytics_report"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	event := func(id int64, eventType string, userID int64, session string, offset time.Duration) Analytics {
+		return Analytics{ID: id, EventType: eventType, UserID: userID, SessionID: session, Timestamp: start.Add(offset)}
+	}
+	// Four sessions: s1 and s2 purchase, s3 signs up, s4 only browses
+	first := []Analytics{
+		event(1, "page_view", 1, "s1", 0),
+		event(2, "purchase", 1, "s1", 10*time.Minute),
+		event(3, "purchase", 1, "s1", 20*time.Minute),
+		event(4, "page_view", 2, "s2", 30*time.Minute),
+	}
+	second := []Analytics{
+		event(5, "purchase", 2, "s2", 70*time.Minute),
+		event(6, "signup", 3, "s3", 80*time.Minute),
+		event(7, "page_view", 0, "s4", 130*time.Minute),
+		event(8, "page_view", 0, "", 140*time.Minute),
+	}
+	if err := manager.WriteAnalytics("events_1.parquet", first); err != nil {
+		t.Fatalf("Failed to write events: %v", err)
+	}
+	if err := manager.WriteAnalytics("events_2.parquet", second); err != nil {
+		t.Fatalf("Failed to write events: %v", err)
+	}
+
+	report, err := manager.AnalyzeAnalytics("events_1.parquet", "events_2.parquet")
+	if err != nil {
+		t.Fatalf("Analysis failed: %v", err)
+	}
+	if report.Events != 8 || report.Sessions != 4 || report.Users != 3 {
+		t.Errorf("Expected 8 events, 4 sessions and 3 users, got %d, %d and %d", report.Events, report.Sessions, report.Users)
+	}
+	if report.EventTypes["page_view"] != 4 || report.EventTypes["purchase"] != 3 || report.EventTypes["signup"] != 1 {
+		t.Errorf("Unexpected event type counts: %v", report.EventTypes)
+	}
+	if report.ConversionRates["purchase"] != 0.5 || report.ConversionRates["signup"] != 0.25 {
+		t.Errorf("Expected conversion rates of 0.5 and 0.25, got %v", report.ConversionRates)
+	}
+
+	want := []HourlyAnalytics{
+		{Hour: start, Events: 4, Users: 2, Purchases: 2},
+		{Hour: start.Add(time.Hour), Events: 2, Users: 2, Purchases: 1},
+		{Hour: start.Add(2 * time.Hour), Events: 2, Users: 0, Purchases: 0},
+	}
+	if len(report.Hourly) != len(want) {
+		t.Fatalf("Expected %d hours, got %+v", len(want), report.Hourly)
+	}
+	for i, h := range report.Hourly {
+		if !h.Hour.Equal(want[i].Hour) || h.Events != want[i].Events || h.Users != want[i].Users || h.Purchases != want[i].Purchases {
+			t.Errorf("Hour %d: expected %+v, got %+v", i, want[i], h)
+		}
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := manager.AnalyzeAnalyticsContext(cancelled, "events_1.parquet"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled analysis to fail with context.Canceled, got %v", err)
+	}
+
+	t.Log("✓ Analytics report counts events, conversions and hours")
+}
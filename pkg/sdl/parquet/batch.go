@@ -0,0 +1,137 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BatchWorkerStats reports the batches one worker processed
+type BatchWorkerStats struct {
+	Worker  int
+	Batches int
+	Records int
+	// Busy is the time the worker spent processing batches
+	Busy time.Duration
+}
+
+// BatchStats summarizes the batch stage of a batch processing run
+type BatchStats struct {
+	Batches int
+	Records int
+	// Files are the written batch files, in batch order
+	Files    []string
+	Workers  []BatchWorkerStats
+	Duration time.Duration
+}
+
+// WithBatchWorkers sets how many batches RunBatchProcessing generates,
+// transforms and writes at once. Each worker holds one batch in memory at a
+// time; one worker, the default, processes the batches in order. Custom
+// transforms must be safe for concurrent use with more than one worker
+func (dp *DataPipeline) WithBatchWorkers(workers int) *DataPipeline {
+	dp.batchWorkers = max(workers, 1)
+	return dp
+}
+
+// processBatches runs numBatches batches of batchSize users through the
+// worker pool. The first failing batch cancels the others and its error is
+// returned; the batch files already written are then removed
+func (dp *DataPipeline) processBatches(ctx context.Context, numBatches, batchSize int) (BatchStats, error) {
+	workers := min(max(dp.batchWorkers, 1), numBatches)
+	stats := BatchStats{
+		Files:   make([]string, numBatches),
+		Workers: make([]BatchWorkerStats, workers),
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		failOnce sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		failOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	// An unbuffered channel keeps at most one batch per worker in flight
+	jobs := make(chan int)
+	start := time.Now()
+	for w := range stats.Workers {
+		worker := &stats.Workers[w]
+		worker.Worker = w
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				began := time.Now()
+				filename, records, err := dp.processBatch(ctx, batch, batchSize)
+				worker.Busy += time.Since(began)
+				if err != nil {
+					fail(err)
+					continue
+				}
+				stats.Files[batch] = filename
+				worker.Batches++
+				worker.Records += records
+				fmt.Printf("  ✓ Processed batch %d: %d records (worker %d)\n", batch, records, worker.Worker)
+			}
+		}()
+	}
+
+send:
+	for batch := 0; batch < numBatches && ctx.Err() == nil; batch++ {
+		select {
+		case jobs <- batch:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	stats.Duration = time.Since(start)
+
+	for _, worker := range stats.Workers {
+		stats.Batches += worker.Batches
+		stats.Records += worker.Records
+	}
+
+	err := firstErr
+	if err == nil {
+		// The caller's context was cancelled before every batch was sent
+		err = ctx.Err()
+	}
+	if err != nil {
+		for _, filename := range stats.Files {
+			if filename != "" {
+				dp.manager.DeleteFile(filename)
+			}
+		}
+		stats.Files = nil
+		return stats, err
+	}
+	return stats, nil
+}
+
+// processBatch generates, transforms and writes one batch
+func (dp *DataPipeline) processBatch(ctx context.Context, batch, batchSize int) (string, int, error) {
+	users, _, err := dp.transforms.Apply(dp.generateBatchData(batch, batchSize))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to transform batch %d: %w", batch, err)
+	}
+
+	filename := fmt.Sprintf("batch_%03d.parquet", batch)
+	if err := dp.manager.WriteUsersContext(ctx, filename, users); err != nil {
+		return "", 0, fmt.Errorf("failed to write batch %d: %w", batch, err)
+	}
+	return filename, len(users), nil
+}
@@ -0,0 +1,87 @@
+package parquet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go-transport-prac/internal/testutil"
+)
+
+func TestBatchProcessing_WorkerPool(t *testing.T) {
+	testDir := "tmp/test_batch_workers"
+	pipeline := NewDataPipeline(testDir).WithClock(testutil.NewDefaultFakeClock()).WithBatchWorkers(3)
+	defer pipeline.CleanupWorkflow()
+
+	stats, err := pipeline.RunBatchProcessingContext(context.Background())
+	if err != nil {
+		t.Fatalf("Batch processing failed: %v", err)
+	}
+	if stats.Batches != 5 || stats.Records != 5000 || len(stats.Workers) != 3 {
+		t.Fatalf("Expected 5 batches of 5000 records over 3 workers, got %+v", stats)
+	}
+	for i, filename := range stats.Files {
+		if expected := fmt.Sprintf("batch_%03d.parquet", i); filename != expected {
+			t.Errorf("Expected %s at position %d, got %s", expected, i, filename)
+		}
+	}
+	batches := 0
+	for _, worker := range stats.Workers {
+		batches += worker.Batches
+	}
+	if batches != 5 {
+		t.Errorf("Expected the workers to process 5 batches, got %d", batches)
+	}
+
+	// Every batch was transformed before it was written
+	users, err := pipeline.manager.ReadUsers("users_compacted_000.parquet")
+	if err != nil {
+		t.Fatalf("Failed to read compacted users: %v", err)
+	}
+	if len(users) != 5000 || users[0].ID != 1 || users[4999].ID != 5000 {
+		t.Fatalf("Expected users 1 to 5000, got %d", len(users))
+	}
+	if users[0].Profile.Metadata["quality_score"] == "" || users[0].Profile.Metadata["status_normalized"] != "true" {
+		t.Errorf("Expected transformed users, got %+v", users[0].Profile)
+	}
+
+	t.Log("✓ Batches are processed by a worker pool in batch order")
+}
+
+func TestBatchProcessing_FirstErrorStops(t *testing.T) {
+	testDir := "tmp/test_batch_workers_error"
+	pipeline := NewDataPipeline(testDir).WithBatchWorkers(2)
+	defer pipeline.CleanupWorkflow()
+
+	// Batch 2 starts at user 2001
+	pipeline.Transforms().Register(NewTransformFunc("reject_batch_2", func(users []User) ([]User, error) {
+		if users[0].ID == 2001 {
+			return nil, fmt.Errorf("bad batch")
+		}
+		return users, nil
+	}), 50)
+
+	_, err := pipeline.RunBatchProcessingContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "failed to transform batch 2") {
+		t.Fatalf("Expected batch 2 to fail the run, got %v", err)
+	}
+
+	files, err := pipeline.manager.ListFiles()
+	if err != nil {
+		t.Fatalf("Failed to list files: %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("Expected no batch files after a failed run, got %v", files)
+	}
+
+	// A cancelled context stops the run too
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := pipeline.RunBatchProcessingContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	t.Log("✓ The first failing batch stops the worker pool")
+}
@@ -0,0 +1,78 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go-transport-prac/pkg/sdl/catalog"
+)
+
+// catalogRow is a catalog entry as stored in Parquet
+type catalogRow struct {
+	File          string            `parquet:"file"`
+	Entity        string            `parquet:"entity"`
+	Format        string            `parquet:"format"`
+	SchemaVersion string            `parquet:"schema_version"`
+	Records       int64             `parquet:"records"`
+	Bytes         int64             `parquet:"bytes"`
+	CreatedAt     time.Time         `parquet:"created_at"`
+	SHA256        string            `parquet:"sha256"`
+	Tags          map[string]string `parquet:"tags"`
+}
+
+// CatalogStore keeps a catalog in a Parquet file, so it can be queried with
+// the same tools as the data it describes
+type CatalogStore struct {
+	manager  *SimpleManager
+	filename string
+}
+
+// NewCatalogStore returns a store keeping the catalog in filename through m.
+// The catalog file itself is never catalogued, but is listed by ListFiles
+// when kept in the directory it describes
+func NewCatalogStore(m *SimpleManager, filename string) *CatalogStore {
+	return &CatalogStore{manager: m, filename: filename}
+}
+
+// uncatalogued returns the store's manager without its catalog, which is
+// being saved or loaded through it
+func (s *CatalogStore) uncatalogued() *SimpleManager {
+	m := *s.manager
+	m.catalog = nil
+	return &m
+}
+
+// Load implements catalog.Store
+func (s *CatalogStore) Load(ctx context.Context) ([]catalog.Entry, error) {
+	m := s.uncatalogued()
+	if m.storage != nil {
+		exists, err := m.storage.Exists(ctx, s.filename)
+		if err != nil || !exists {
+			return nil, err
+		}
+	} else if _, err := os.Stat(filepath.Join(m.baseDir, s.filename)); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	rows, err := readRows[catalogRow](ctx, m, s.filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+	entries := make([]catalog.Entry, len(rows))
+	for i, r := range rows {
+		entries[i] = catalog.Entry(r)
+	}
+	return entries, nil
+}
+
+// Save implements catalog.Store
+func (s *CatalogStore) Save(ctx context.Context, entries []catalog.Entry) error {
+	rows := make([]catalogRow, len(entries))
+	for i, e := range entries {
+		rows[i] = catalogRow(e)
+	}
+	return writeRows(ctx, s.uncatalogued(), s.filename, rows)
+}
@@ -0,0 +1,97 @@
+package parquet
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/catalog"
+)
+
+func TestManagerCatalog(t *testing.T) {
+	testDir := "tmp/test_parquet_catalog"
+	defer os.RemoveAll(testDir)
+	ctx := context.Background()
+
+	store := NewCatalogStore(NewSimpleManager(filepath.Join(testDir, "meta")), "catalog.parquet")
+	files, err := catalog.Open(ctx, store)
+	if err != nil {
+		t.Fatalf("Failed to open catalog: %v", err)
+	}
+	manager := NewSimpleManager(testDir).WithCatalog(files)
+
+	if err := manager.WriteUsers("users.parquet", createSampleUsers(50)); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	if err := manager.WriteUsers("more_users.parquet", createSampleUsers(7)); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+
+	entry, ok := files.Get("users.parquet")
+	info, _ := os.Stat(filepath.Join(testDir, "users.parquet"))
+	if !ok || entry.Entity != "user" || entry.Format != "parquet" || entry.Records != 50 || entry.Bytes != info.Size() {
+		t.Errorf("Unexpected entry %+v for a %d byte file", entry, info.Size())
+	}
+	if entry.SchemaVersion != schemaVersion(parquet.SchemaOf(new(User))) || len(entry.SHA256) != 64 {
+		t.Errorf("Expected the schema fingerprint and checksum, got %+v", entry)
+	}
+
+	// The Parquet store reloads what was recorded
+	reopened, err := catalog.Open(ctx, store)
+	if err != nil {
+		t.Fatalf("Failed to reopen catalog: %v", err)
+	}
+	if users := reopened.ListByEntity("user"); len(users) != 2 || users[1].Records != 7 && users[0].Records != 7 {
+		t.Errorf("Expected both user entries to persist, got %+v", users)
+	}
+	if _, ok := reopened.Get("catalog.parquet"); ok {
+		t.Error("Expected the catalog not to catalogue itself")
+	}
+
+	if err := manager.DeleteFile("users.parquet"); err != nil {
+		t.Fatalf("Failed to delete file: %v", err)
+	}
+	if _, ok := files.Get("users.parquet"); ok {
+		t.Error("Expected the entry to be dropped with its file")
+	}
+
+	t.Log("✓ Parquet files are catalogued and the catalog persists as Parquet")
+}
+
+func TestPipelineCleanupBefore(t *testing.T) {
+	testDir := "tmp/test_pipeline_cleanup"
+	clock := testutil.NewDefaultFakeClock()
+	pipeline := NewDataPipeline(testDir).WithClock(clock)
+	defer pipeline.CleanupWorkflow()
+
+	if err := pipeline.manager.WriteUsers("old.parquet", createSampleUsers(5)); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	clock.Advance(48 * time.Hour)
+	if err := pipeline.manager.WriteUsers("new.parquet", createSampleUsers(5)); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+
+	removed, err := pipeline.CleanupBefore(clock.Now().Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("Cleanup failed: %v", err)
+	}
+	if len(removed) != 1 || removed[0].File != "old.parquet" {
+		t.Errorf("Expected only old.parquet to be removed, got %v", catalog.Files(removed))
+	}
+	if files, _ := pipeline.manager.ListFiles(); len(files) != 1 || files[0] != "new.parquet" {
+		t.Errorf("Expected new.parquet to be kept, got %v", files)
+	}
+
+	// The catalog is kept in the data directory and outlives the pipeline
+	if _, ok := NewDataPipeline(testDir).Catalog().Get("new.parquet"); !ok {
+		t.Error("Expected a new pipeline to load the persisted catalog")
+	}
+
+	t.Log("✓ Cleanup finds old files through the catalog")
+}
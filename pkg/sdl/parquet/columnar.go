@@ -0,0 +1,402 @@
+package parquet
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/segmentio/parquet-go"
+)
+
+// CompareOp is a comparison used by column filters
+type CompareOp int
+
+const (
+	OpEqual CompareOp = iota
+	OpNotEqual
+	OpLess
+	OpLessOrEqual
+	OpGreater
+	OpGreaterOrEqual
+)
+
+// Selection lists the row indexes that survived filtering so far
+type Selection []int
+
+// SelectAll returns a selection covering rows 0..n-1
+func SelectAll(n int) Selection {
+	sel := make(Selection, n)
+	for i := range sel {
+		sel[i] = i
+	}
+	return sel
+}
+
+// Column is a single decoded Parquet column held as a typed slice
+type Column interface {
+	Name() string
+	Len() int
+}
+
+// Int64Column holds INT32/INT64 column values
+type Int64Column struct {
+	name   string
+	Values []int64
+	Nulls  []bool
+}
+
+// Float64Column holds FLOAT/DOUBLE column values
+type Float64Column struct {
+	name   string
+	Values []float64
+	Nulls  []bool
+}
+
+// StringColumn holds BYTE_ARRAY column values
+type StringColumn struct {
+	name   string
+	Values []string
+	Nulls  []bool
+}
+
+// BoolColumn holds BOOLEAN column values
+type BoolColumn struct {
+	name   string
+	Values []bool
+	Nulls  []bool
+}
+
+func (c *Int64Column) Name() string   { return c.name }
+func (c *Int64Column) Len() int       { return len(c.Values) }
+func (c *Float64Column) Name() string { return c.name }
+func (c *Float64Column) Len() int     { return len(c.Values) }
+func (c *StringColumn) Name() string  { return c.name }
+func (c *StringColumn) Len() int      { return len(c.Values) }
+func (c *BoolColumn) Name() string    { return c.name }
+func (c *BoolColumn) Len() int        { return len(c.Values) }
+
+// ColumnBatch is a set of equally sized decoded columns from one file
+type ColumnBatch struct {
+	NumRows int
+	columns map[string]Column
+}
+
+// Column returns a column by its dotted path
+func (b *ColumnBatch) Column(path string) (Column, bool) {
+	col, ok := b.columns[path]
+	return col, ok
+}
+
+// Int64 returns an integer column by path, or nil if missing or of another type
+func (b *ColumnBatch) Int64(path string) *Int64Column {
+	col, _ := b.columns[path].(*Int64Column)
+	return col
+}
+
+// Float64 returns a floating point column by path, or nil if missing or of another type
+func (b *ColumnBatch) Float64(path string) *Float64Column {
+	col, _ := b.columns[path].(*Float64Column)
+	return col
+}
+
+// String returns a string column by path, or nil if missing or of another type
+func (b *ColumnBatch) String(path string) *StringColumn {
+	col, _ := b.columns[path].(*StringColumn)
+	return col
+}
+
+// Bool returns a boolean column by path, or nil if missing or of another type
+func (b *ColumnBatch) Bool(path string) *BoolColumn {
+	col, _ := b.columns[path].(*BoolColumn)
+	return col
+}
+
+// ReadColumns decodes only the requested non-repeated leaf columns, addressed by dotted
+// path (e.g. "status", "profile.address.country"), without materializing row structs
+func (m *SimpleManager) ReadColumns(filename string, paths ...string) (*ColumnBatch, error) {
+	filePath := filepath.Join(m.baseDir, filename)
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	pf, err := parquet.OpenFile(file, stat.Size())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+
+	batch := &ColumnBatch{
+		NumRows: int(pf.NumRows()),
+		columns: make(map[string]Column, len(paths)),
+	}
+
+	for _, path := range paths {
+		col, err := readColumn(pf, path)
+		if err != nil {
+			return nil, err
+		}
+		batch.columns[path] = col
+	}
+
+	return batch, nil
+}
+
+// readColumn decodes every page of a leaf column into a typed column
+func readColumn(pf *parquet.File, path string) (Column, error) {
+	leaf, err := leafColumn(pf, path)
+	if err != nil {
+		return nil, err
+	}
+
+	numRows := int(pf.NumRows())
+	var appendValue func(v parquet.Value)
+	var col Column
+
+	switch leaf.Type().Kind() {
+	case parquet.Int32, parquet.Int64:
+		c := &Int64Column{name: path, Values: make([]int64, 0, numRows), Nulls: make([]bool, 0, numRows)}
+		appendValue = func(v parquet.Value) { c.Values = append(c.Values, v.Int64()); c.Nulls = append(c.Nulls, v.IsNull()) }
+		col = c
+	case parquet.Float, parquet.Double:
+		c := &Float64Column{name: path, Values: make([]float64, 0, numRows), Nulls: make([]bool, 0, numRows)}
+		appendValue = func(v parquet.Value) { c.Values = append(c.Values, v.Double()); c.Nulls = append(c.Nulls, v.IsNull()) }
+		col = c
+	case parquet.ByteArray, parquet.FixedLenByteArray:
+		c := &StringColumn{name: path, Values: make([]string, 0, numRows), Nulls: make([]bool, 0, numRows)}
+		appendValue = func(v parquet.Value) {
+			c.Values = append(c.Values, string(v.ByteArray()))
+			c.Nulls = append(c.Nulls, v.IsNull())
+		}
+		col = c
+	case parquet.Boolean:
+		c := &BoolColumn{name: path, Values: make([]bool, 0, numRows), Nulls: make([]bool, 0, numRows)}
+		appendValue = func(v parquet.Value) { c.Values = append(c.Values, v.Boolean()); c.Nulls = append(c.Nulls, v.IsNull()) }
+		col = c
+	default:
+		return nil, fmt.Errorf("column %s has unsupported type %s", path, leaf.Type())
+	}
+
+	pages := leaf.Pages()
+	defer pages.Close()
+
+	if err := readValues(pages, path, appendValue); err != nil {
+		return nil, err
+	}
+
+	if col.Len() != numRows {
+		return nil, fmt.Errorf("column %s decoded %d values for %d rows", path, col.Len(), numRows)
+	}
+
+	return col, nil
+}
+
+// Filter narrows sel to rows whose non-null value compares true against v.
+// The selection is filtered in place and the narrowed slice returned.
+func (c *Int64Column) Filter(op CompareOp, v int64, sel Selection) Selection {
+	out := sel[:0]
+	values, nulls := c.Values, c.Nulls
+	switch op {
+	case OpEqual:
+		for _, i := range sel {
+			if !nulls[i] && values[i] == v {
+				out = append(out, i)
+			}
+		}
+	case OpNotEqual:
+		for _, i := range sel {
+			if !nulls[i] && values[i] != v {
+				out = append(out, i)
+			}
+		}
+	case OpLess:
+		for _, i := range sel {
+			if !nulls[i] && values[i] < v {
+				out = append(out, i)
+			}
+		}
+	case OpLessOrEqual:
+		for _, i := range sel {
+			if !nulls[i] && values[i] <= v {
+				out = append(out, i)
+			}
+		}
+	case OpGreater:
+		for _, i := range sel {
+			if !nulls[i] && values[i] > v {
+				out = append(out, i)
+			}
+		}
+	case OpGreaterOrEqual:
+		for _, i := range sel {
+			if !nulls[i] && values[i] >= v {
+				out = append(out, i)
+			}
+		}
+	}
+	return out
+}
+
+// Filter narrows sel to rows whose non-null value compares true against v
+func (c *Float64Column) Filter(op CompareOp, v float64, sel Selection) Selection {
+	out := sel[:0]
+	values, nulls := c.Values, c.Nulls
+	switch op {
+	case OpEqual:
+		for _, i := range sel {
+			if !nulls[i] && values[i] == v {
+				out = append(out, i)
+			}
+		}
+	case OpNotEqual:
+		for _, i := range sel {
+			if !nulls[i] && values[i] != v {
+				out = append(out, i)
+			}
+		}
+	case OpLess:
+		for _, i := range sel {
+			if !nulls[i] && values[i] < v {
+				out = append(out, i)
+			}
+		}
+	case OpLessOrEqual:
+		for _, i := range sel {
+			if !nulls[i] && values[i] <= v {
+				out = append(out, i)
+			}
+		}
+	case OpGreater:
+		for _, i := range sel {
+			if !nulls[i] && values[i] > v {
+				out = append(out, i)
+			}
+		}
+	case OpGreaterOrEqual:
+		for _, i := range sel {
+			if !nulls[i] && values[i] >= v {
+				out = append(out, i)
+			}
+		}
+	}
+	return out
+}
+
+// FilterEquals narrows sel to rows whose non-null value equals v
+func (c *StringColumn) FilterEquals(v string, sel Selection) Selection {
+	out := sel[:0]
+	values, nulls := c.Values, c.Nulls
+	for _, i := range sel {
+		if !nulls[i] && values[i] == v {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// FilterIn narrows sel to rows whose non-null value is one of values
+func (c *StringColumn) FilterIn(values []string, sel Selection) Selection {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+
+	out := sel[:0]
+	for _, i := range sel {
+		if c.Nulls[i] {
+			continue
+		}
+		if _, ok := set[c.Values[i]]; ok {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// FilterEquals narrows sel to rows whose non-null value equals v
+func (c *BoolColumn) FilterEquals(v bool, sel Selection) Selection {
+	out := sel[:0]
+	values, nulls := c.Values, c.Nulls
+	for _, i := range sel {
+		if !nulls[i] && values[i] == v {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// FilterNotNull narrows sel to rows where the column has a value
+func FilterNotNull(nulls []bool, sel Selection) Selection {
+	out := sel[:0]
+	for _, i := range sel {
+		if !nulls[i] {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// Take returns the selected values
+func (c *Int64Column) Take(sel Selection) []int64 {
+	out := make([]int64, len(sel))
+	for j, i := range sel {
+		out[j] = c.Values[i]
+	}
+	return out
+}
+
+// Take returns the selected values
+func (c *Float64Column) Take(sel Selection) []float64 {
+	out := make([]float64, len(sel))
+	for j, i := range sel {
+		out[j] = c.Values[i]
+	}
+	return out
+}
+
+// Take returns the selected values
+func (c *StringColumn) Take(sel Selection) []string {
+	out := make([]string, len(sel))
+	for j, i := range sel {
+		out[j] = c.Values[i]
+	}
+	return out
+}
+
+// Sum adds the selected non-null values
+func (c *Int64Column) Sum(sel Selection) int64 {
+	var sum int64
+	for _, i := range sel {
+		if !c.Nulls[i] {
+			sum += c.Values[i]
+		}
+	}
+	return sum
+}
+
+// Sum adds the selected non-null values
+func (c *Float64Column) Sum(sel Selection) float64 {
+	var sum float64
+	for _, i := range sel {
+		if !c.Nulls[i] {
+			sum += c.Values[i]
+		}
+	}
+	return sum
+}
+
+// CountBy counts selected non-null rows per distinct value
+func (c *StringColumn) CountBy(sel Selection) map[string]int {
+	counts := make(map[string]int)
+	for _, i := range sel {
+		if !c.Nulls[i] {
+			counts[c.Values[i]]++
+		}
+	}
+	return counts
+}
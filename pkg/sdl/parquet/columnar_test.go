@@ -0,0 +1,284 @@
+package parquet
+
+import (
+	"os"
+	"testing"
+)
+
+var columnarStatuses = []string{"active", "inactive", "suspended", "deleted"}
+var columnarCountries = []string{"USA", "Canada", "UK", "Germany", "Japan"}
+
+// createVariedUsers creates sample users with a spread of statuses and countries
+func createVariedUsers(count int) []User {
+	users := createSampleUsers(count)
+	for i := range users {
+		users[i].Status = columnarStatuses[i%len(columnarStatuses)]
+		address := *users[i].Profile.Address
+		address.Country = columnarCountries[i%len(columnarCountries)]
+		profile := *users[i].Profile
+		profile.Address = &address
+		if i%10 == 0 {
+			profile.Address = nil
+		}
+		users[i].Profile = &profile
+	}
+	return users
+}
+
+func TestReadColumns(t *testing.T) {
+	testDir := "tmp/test_columnar"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+
+	users := createVariedUsers(100)
+	filename := "columnar_users.parquet"
+	if err := manager.WriteUsers(filename, users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+
+	batch, err := manager.ReadColumns(filename, "id", "status", "profile.address.country")
+	if err != nil {
+		t.Fatalf("Failed to read columns: %v", err)
+	}
+
+	if batch.NumRows != len(users) {
+		t.Fatalf("Expected %d rows, got %d", len(users), batch.NumRows)
+	}
+
+	ids := batch.Int64("id")
+	statuses := batch.String("status")
+	countries := batch.String("profile.address.country")
+	if ids == nil || statuses == nil || countries == nil {
+		t.Fatal("Expected typed id, status and country columns")
+	}
+
+	for i, user := range users {
+		if ids.Values[i] != user.ID {
+			t.Errorf("Row %d: expected id %d, got %d", i, user.ID, ids.Values[i])
+		}
+		if statuses.Values[i] != user.Status {
+			t.Errorf("Row %d: expected status %s, got %s", i, user.Status, statuses.Values[i])
+		}
+		if user.Profile.Address == nil {
+			if !countries.Nulls[i] {
+				t.Errorf("Row %d: expected null country", i)
+			}
+		} else if countries.Nulls[i] || countries.Values[i] != user.Profile.Address.Country {
+			t.Errorf("Row %d: expected country %s, got %q", i, user.Profile.Address.Country, countries.Values[i])
+		}
+	}
+
+	t.Log("✓ Columns read without materializing users")
+}
+
+func TestReadColumnsErrors(t *testing.T) {
+	testDir := "tmp/test_columnar_errors"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+
+	filename := "columnar_users.parquet"
+	if err := manager.WriteUsers(filename, createVariedUsers(10)); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+
+	if _, err := manager.ReadColumns(filename, "missing"); err == nil {
+		t.Error("Expected error for missing column")
+	}
+	if _, err := manager.ReadColumns(filename, "profile"); err == nil {
+		t.Error("Expected error for group column")
+	}
+	if _, err := manager.ReadColumns(filename, "profile.interests"); err == nil {
+		t.Error("Expected error for repeated column")
+	}
+
+	t.Log("✓ Unsupported columns rejected")
+}
+
+func TestColumnFilters(t *testing.T) {
+	ids := &Int64Column{name: "id", Values: []int64{1, 2, 3, 4, 5}, Nulls: []bool{false, false, true, false, false}}
+	statuses := &StringColumn{
+		name:   "status",
+		Values: []string{"active", "inactive", "active", "active", "deleted"},
+		Nulls:  make([]bool, 5),
+	}
+
+	sel := ids.Filter(OpGreater, 1, SelectAll(5))
+	sel = statuses.FilterEquals("active", sel)
+	if len(sel) != 1 || sel[0] != 3 {
+		t.Fatalf("Expected selection [3], got %v", sel)
+	}
+
+	if sum := ids.Sum(SelectAll(5)); sum != 12 {
+		t.Errorf("Expected sum 12 skipping nulls, got %d", sum)
+	}
+
+	in := statuses.FilterIn([]string{"inactive", "deleted"}, SelectAll(5))
+	if got := statuses.Take(in); len(got) != 2 || got[0] != "inactive" || got[1] != "deleted" {
+		t.Errorf("Unexpected FilterIn result: %v", got)
+	}
+
+	counts := statuses.CountBy(SelectAll(5))
+	if counts["active"] != 3 || counts["inactive"] != 1 || counts["deleted"] != 1 {
+		t.Errorf("Unexpected counts: %v", counts)
+	}
+
+	prices := &Float64Column{name: "price", Values: []float64{9.99, 19.99, 29.99}, Nulls: make([]bool, 3)}
+	if cheap := prices.Filter(OpLessOrEqual, 19.99, SelectAll(3)); len(cheap) != 2 {
+		t.Errorf("Expected 2 prices <= 19.99, got %d", len(cheap))
+	}
+
+	t.Log("✓ Column filters and aggregates work")
+}
+
+// rowWiseActiveCountries filters users by status and id, counting countries from structs
+func rowWiseActiveCountries(users []User) map[string]int {
+	counts := make(map[string]int)
+	for _, user := range users {
+		if user.Status != "active" || user.ID <= 1000 {
+			continue
+		}
+		if user.Profile != nil && user.Profile.Address != nil {
+			counts[user.Profile.Address.Country]++
+		}
+	}
+	return counts
+}
+
+// columnarActiveCountries runs the same query over decoded columns
+func columnarActiveCountries(batch *ColumnBatch, sel Selection) map[string]int {
+	sel = batch.String("status").FilterEquals("active", sel)
+	sel = batch.Int64("id").Filter(OpGreater, 1000, sel)
+	return batch.String("profile.address.country").CountBy(sel)
+}
+
+func TestColumnarMatchesRowWise(t *testing.T) {
+	testDir := "tmp/test_columnar_query"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+
+	users := createVariedUsers(10000)
+	filename := "query_users.parquet"
+	if err := manager.WriteUsers(filename, users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+
+	batch, err := manager.ReadColumns(filename, "id", "status", "profile.address.country")
+	if err != nil {
+		t.Fatalf("Failed to read columns: %v", err)
+	}
+
+	expected := rowWiseActiveCountries(users)
+	actual := columnarActiveCountries(batch, SelectAll(batch.NumRows))
+	if len(expected) != len(actual) {
+		t.Fatalf("Expected %v, got %v", expected, actual)
+	}
+	for country, count := range expected {
+		if actual[country] != count {
+			t.Errorf("Country %s: expected %d, got %d", country, count, actual[country])
+		}
+	}
+
+	t.Log("✓ Columnar query matches row-wise query")
+}
+
+// In-memory filtering benchmarks on 10k rows already decoded from the same file
+func BenchmarkRowWiseFilter(b *testing.B) {
+	testDir := "tmp/bench_rowwise_filter"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+
+	filename := "filter.parquet"
+	if err := manager.WriteUsers(filename, createVariedUsers(10000)); err != nil {
+		b.Fatal(err)
+	}
+	users, err := manager.ReadUsers(filename)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		rowWiseActiveCountries(users)
+	}
+
+	b.ReportMetric(10000, "records")
+}
+
+func BenchmarkColumnarFilter(b *testing.B) {
+	testDir := "tmp/bench_columnar_filter"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+
+	filename := "filter.parquet"
+	if err := manager.WriteUsers(filename, createVariedUsers(10000)); err != nil {
+		b.Fatal(err)
+	}
+	batch, err := manager.ReadColumns(filename, "id", "status", "profile.address.country")
+	if err != nil {
+		b.Fatal(err)
+	}
+	sel := make(Selection, batch.NumRows)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		for j := range sel {
+			sel[j] = j
+		}
+		columnarActiveCountries(batch, sel)
+	}
+
+	b.ReportMetric(10000, "records")
+}
+
+// End-to-end benchmarks including the file read
+func BenchmarkRowWiseReadAndFilter(b *testing.B) {
+	testDir := "tmp/bench_rowwise_read"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+
+	filename := "filter.parquet"
+	if err := manager.WriteUsers(filename, createVariedUsers(10000)); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		users, err := manager.ReadUsers(filename)
+		if err != nil {
+			b.Fatal(err)
+		}
+		rowWiseActiveCountries(users)
+	}
+
+	b.ReportMetric(10000, "records")
+}
+
+func BenchmarkColumnarReadAndFilter(b *testing.B) {
+	testDir := "tmp/bench_columnar_read"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+
+	filename := "filter.parquet"
+	if err := manager.WriteUsers(filename, createVariedUsers(10000)); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		batch, err := manager.ReadColumns(filename, "id", "status", "profile.address.country")
+		if err != nil {
+			b.Fatal(err)
+		}
+		columnarActiveCountries(batch, SelectAll(batch.NumRows))
+	}
+
+	b.ReportMetric(10000, "records")
+}
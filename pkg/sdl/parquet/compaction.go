@@ -0,0 +1,194 @@
+package parquet
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"go-transport-prac/pkg/sdl/deadletter"
+)
+
+// DefaultTargetFileSize is the size compacted files aim for unless configured otherwise
+const DefaultTargetFileSize = 128 << 20
+
+// Compactor merges small Parquet files of T into fewer, larger files. Rows
+// are deduplicated by key, the row from the latest input winning, and written
+// in ascending key order so sorted inputs stay sorted across the outputs.
+// Parquet files cannot be appended to, so compaction is how batches written
+// over time become one dataset
+type Compactor[T any, K cmp.Ordered] struct {
+	manager      *SimpleManager
+	key          func(T) K
+	targetSize   int64
+	targetRows   int
+	opts         WriterOptions
+	deleteInputs bool
+}
+
+// CompactionStats summarizes one compaction
+type CompactionStats struct {
+	Inputs      []string
+	Outputs     []string
+	RowsRead    int
+	RowsWritten int
+	// Duplicates counts rows replaced by a later row with the same key
+	Duplicates int
+	BytesRead  int64
+	// DeadLettered counts the rows a tolerant manager could not decode; the
+	// inputs holding them are kept
+	DeadLettered int
+}
+
+// NewCompactor creates a compactor deduplicating rows by key, aiming for
+// DefaultTargetFileSize outputs written with the manager's writer options
+func NewCompactor[T any, K cmp.Ordered](m *SimpleManager, key func(T) K) *Compactor[T, K] {
+	return &Compactor[T, K]{
+		manager:    m,
+		key:        key,
+		targetSize: DefaultTargetFileSize,
+		opts:       m.writerOptions,
+	}
+}
+
+// NewUserCompactor creates a compactor of user files deduplicated and sorted by ID
+func (m *SimpleManager) NewUserCompactor() *Compactor[User, int64] {
+	opts := m.writerOptions
+	if len(opts.SortingColumns) == 0 {
+		opts.SortingColumns = []SortingColumn{{Path: "id"}}
+	}
+	return NewCompactor(m, func(u User) int64 { return u.ID }).WithWriterOptions(opts)
+}
+
+// WithTargetFileSize sets the size in bytes outputs aim for, estimated from
+// the bytes per row of the inputs; zero disables the limit
+func (c *Compactor[T, K]) WithTargetFileSize(size int64) *Compactor[T, K] {
+	c.targetSize = size
+	return c
+}
+
+// WithTargetRows caps the number of rows per output; zero disables the cap.
+// When both limits are set the smaller file wins
+func (c *Compactor[T, K]) WithTargetRows(rows int) *Compactor[T, K] {
+	c.targetRows = rows
+	return c
+}
+
+// WithWriterOptions sets the options outputs are written with. Sorting
+// columns should follow the key so each file records the order it is in
+func (c *Compactor[T, K]) WithWriterOptions(opts WriterOptions) *Compactor[T, K] {
+	c.opts = opts
+	return c
+}
+
+// WithDeleteInputs removes the inputs once every output has been written
+func (c *Compactor[T, K]) WithDeleteInputs(enabled bool) *Compactor[T, K] {
+	c.deleteInputs = enabled
+	return c
+}
+
+// Compact merges inputs into files named prefix_000.parquet, prefix_001.parquet, ...
+func (c *Compactor[T, K]) Compact(inputs []string, prefix string) (CompactionStats, error) {
+	return c.CompactContext(context.Background(), inputs, prefix)
+}
+
+// CompactContext is Compact, stopping between files once ctx is done. Inputs
+// are only deleted after every output has been written
+func (c *Compactor[T, K]) CompactContext(ctx context.Context, inputs []string, prefix string) (CompactionStats, error) {
+	stats := CompactionStats{Inputs: inputs}
+	if len(inputs) == 0 {
+		return stats, fmt.Errorf("no files to compact")
+	}
+	if prefix == "" {
+		return stats, fmt.Errorf("output prefix cannot be empty")
+	}
+	if c.targetSize < 0 || c.targetRows < 0 {
+		return stats, fmt.Errorf("target size and rows cannot be negative")
+	}
+	if err := c.opts.Validate(); err != nil {
+		return stats, fmt.Errorf("invalid writer options: %w", err)
+	}
+
+	var rows []T
+	var damaged []string
+	index := make(map[K]int)
+	for _, filename := range inputs {
+		size, err := c.fileSize(ctx, filename)
+		if err != nil {
+			return stats, fmt.Errorf("failed to open %s: %w", filename, err)
+		}
+		batch, err := readRows[T](ctx, c.manager, filename)
+		if summary, ok := deadletter.Partial(err); ok {
+			stats.DeadLettered += summary.Failed
+			damaged = append(damaged, filename)
+		} else if err != nil {
+			return stats, fmt.Errorf("failed to read %s: %w", filename, err)
+		}
+		stats.BytesRead += size
+		stats.RowsRead += len(batch)
+
+		for _, row := range batch {
+			key := c.key(row)
+			if i, ok := index[key]; ok {
+				rows[i] = row
+				stats.Duplicates++
+				continue
+			}
+			index[key] = len(rows)
+			rows = append(rows, row)
+		}
+	}
+
+	slices.SortStableFunc(rows, func(a, b T) int {
+		return cmp.Compare(c.key(a), c.key(b))
+	})
+
+	perFile := c.rowsPerFile(stats.BytesRead, stats.RowsRead, len(rows))
+	for start := 0; start < len(rows); start += perFile {
+		end := min(start+perFile, len(rows))
+		filename := fmt.Sprintf("%s_%03d.parquet", prefix, len(stats.Outputs))
+		if err := writeRowsWith(ctx, c.manager, filename, rows[start:end], c.opts); err != nil {
+			return stats, fmt.Errorf("failed to write %s: %w", filename, err)
+		}
+		stats.Outputs = append(stats.Outputs, filename)
+		stats.RowsWritten += end - start
+	}
+
+	if c.deleteInputs {
+		for _, filename := range inputs {
+			// An input overwritten by an output of the same name is kept, as
+			// is one whose bad rows were skipped
+			if slices.Contains(stats.Outputs, filename) || slices.Contains(damaged, filename) {
+				continue
+			}
+			if err := c.manager.DeleteFile(filename); err != nil {
+				return stats, fmt.Errorf("failed to delete %s: %w", filename, err)
+			}
+		}
+	}
+	return stats, nil
+}
+
+// rowsPerFile splits rows by the target row count and the target size,
+// assuming outputs take about as many bytes per row as the inputs did
+func (c *Compactor[T, K]) rowsPerFile(bytesRead int64, rowsRead, rows int) int {
+	perFile := rows
+	if c.targetRows > 0 {
+		perFile = min(perFile, c.targetRows)
+	}
+	if c.targetSize > 0 && bytesRead > 0 && rowsRead > 0 {
+		bytesPerRow := max(bytesRead/int64(rowsRead), 1)
+		perFile = min(perFile, int(max(c.targetSize/bytesPerRow, 1)))
+	}
+	return max(perFile, 1)
+}
+
+// fileSize returns the size in bytes of a file
+func (c *Compactor[T, K]) fileSize(ctx context.Context, filename string) (int64, error) {
+	file, size, err := c.manager.openFile(ctx, filename)
+	if err != nil {
+		return 0, err
+	}
+	file.Close()
+	return size, nil
+}
@@ -0,0 +1,134 @@
+package parquet
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestCompactor_MergesAndDeduplicates(t *testing.T) {
+	testDir := "tmp/test_compaction"
+	defer os.RemoveAll(testDir)
+
+	manager := NewSimpleManager(testDir)
+	users := createSampleUsers(200)
+	if err := manager.WriteUsers("batch_000.parquet", users[:100]); err != nil {
+		t.Fatalf("Failed to write first batch: %v", err)
+	}
+	if err := manager.WriteUsers("batch_001.parquet", users[100:]); err != nil {
+		t.Fatalf("Failed to write second batch: %v", err)
+	}
+
+	// A later batch updates users 50-59, written out of order
+	var updates []User
+	for i := 59; i >= 50; i-- {
+		user := users[i-1]
+		user.Name = "Updated User"
+		updates = append(updates, user)
+	}
+	if err := manager.WriteUsers("batch_002.parquet", updates); err != nil {
+		t.Fatalf("Failed to write updates: %v", err)
+	}
+
+	inputs := []string{"batch_000.parquet", "batch_001.parquet", "batch_002.parquet"}
+	stats, err := manager.NewUserCompactor().WithTargetRows(80).WithDeleteInputs(true).Compact(inputs, "users")
+	if err != nil {
+		t.Fatalf("Failed to compact: %v", err)
+	}
+	if stats.RowsRead != 210 || stats.RowsWritten != 200 || stats.Duplicates != 10 || stats.BytesRead == 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	expected := []string{"users_000.parquet", "users_001.parquet", "users_002.parquet"}
+	if !reflect.DeepEqual(stats.Outputs, expected) {
+		t.Fatalf("Expected outputs %v, got %v", expected, stats.Outputs)
+	}
+
+	files, err := manager.ListFiles()
+	if err != nil {
+		t.Fatalf("Failed to list files: %v", err)
+	}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected the inputs to be deleted, got %v", files)
+	}
+
+	// Outputs hold every user once, in ID order across files, with the latest version
+	var nextID int64 = 1
+	for i, filename := range stats.Outputs {
+		compacted, err := manager.ReadUsers(filename)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", filename, err)
+		}
+		if want := []int{80, 80, 40}[i]; len(compacted) != want {
+			t.Errorf("Expected %d rows in %s, got %d", want, filename, len(compacted))
+		}
+		for _, user := range compacted {
+			if user.ID != nextID {
+				t.Fatalf("Expected user %d in %s, got %d", nextID, filename, user.ID)
+			}
+			updated := user.ID >= 50 && user.ID <= 59
+			if updated != (user.Name == "Updated User") {
+				t.Errorf("User %d has name %q", user.ID, user.Name)
+			}
+			nextID++
+		}
+
+		info, err := manager.GetBasicFileInfo(filename)
+		if err != nil {
+			t.Fatalf("Failed to get file info: %v", err)
+		}
+		if !reflect.DeepEqual(info.SortingColumns, []SortingColumn{{Path: "id"}}) {
+			t.Errorf("Expected %s to record the id sort order, got %+v", filename, info.SortingColumns)
+		}
+	}
+
+	t.Log("✓ Batches compacted, deduplicated and sorted by ID")
+}
+
+func TestCompactor_TargetFileSize(t *testing.T) {
+	testDir := "tmp/test_compaction_size"
+	defer os.RemoveAll(testDir)
+
+	manager := NewSimpleManager(testDir)
+	users := createSampleUsers(1000)
+	var inputs []string
+	for i := 0; i < 10; i++ {
+		filename := fmt.Sprintf("small_%d.parquet", i)
+		if err := manager.WriteUsers(filename, users[i*100:(i+1)*100]); err != nil {
+			t.Fatalf("Failed to write %s: %v", filename, err)
+		}
+		inputs = append(inputs, filename)
+	}
+
+	// Aiming for about a quarter of the input bytes gives four files
+	compactor := manager.NewUserCompactor()
+	stats, err := compactor.Compact(inputs, "probe")
+	if err != nil {
+		t.Fatalf("Failed to compact: %v", err)
+	}
+	if len(stats.Outputs) != 1 {
+		t.Fatalf("Expected one output with the default target size, got %d", len(stats.Outputs))
+	}
+
+	stats, err = compactor.WithTargetFileSize(stats.BytesRead/4).Compact(inputs, "sized")
+	if err != nil {
+		t.Fatalf("Failed to compact: %v", err)
+	}
+	if len(stats.Outputs) < 4 || len(stats.Outputs) > 5 || stats.RowsWritten != 1000 {
+		t.Errorf("Expected 1000 rows in 4-5 files, got %d in %d", stats.RowsWritten, len(stats.Outputs))
+	}
+
+	// Inputs are kept unless deletion is requested
+	if _, err := manager.GetBasicFileInfo(inputs[0]); err != nil {
+		t.Errorf("Expected inputs to be kept: %v", err)
+	}
+
+	if _, err := compactor.Compact(nil, "empty"); err == nil {
+		t.Error("Expected an error compacting no files")
+	}
+	if _, err := compactor.Compact(inputs, ""); err == nil {
+		t.Error("Expected an error without an output prefix")
+	}
+
+	t.Log("✓ Compacted files split by target size")
+}
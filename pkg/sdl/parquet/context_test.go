@@ -0,0 +1,73 @@
+package parquet
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-transport-prac/pkg/storage"
+)
+
+func TestContextVariants(t *testing.T) {
+	testDir := "tmp/test_context"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+
+	ctx := context.Background()
+	users := createVariedUsers(3000)
+	if err := manager.WriteUsersContext(ctx, "users.parquet", users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	read, err := manager.ReadUsersContext(ctx, "users.parquet")
+	if err != nil {
+		t.Fatalf("Failed to read users: %v", err)
+	}
+	if len(read) != len(users) {
+		t.Fatalf("Expected %d users, got %d", len(users), len(read))
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	if err := manager.WriteUsersContext(cancelled, "cancelled.parquet", users); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled write to fail with context.Canceled, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(testDir, "cancelled.parquet")); !os.IsNotExist(err) {
+		t.Errorf("Expected the cancelled write to leave no file, got %v", err)
+	}
+	if _, err := manager.ReadUsersContext(cancelled, "users.parquet"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled read to fail with context.Canceled, got %v", err)
+	}
+
+	// Batched reads stop between batches once the context is cancelled
+	batchCtx, stop := context.WithCancel(ctx)
+	batches := 0
+	err = manager.ReadUsersBatchedContext(batchCtx, "users.parquet", 500, func([]User) error {
+		batches++
+		stop()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || batches != 1 {
+		t.Errorf("Expected one batch and context.Canceled, got %d batches and %v", batches, err)
+	}
+
+	t.Log("✓ Context variants round-trip and stop once the context is cancelled")
+}
+
+func TestContextStorage(t *testing.T) {
+	backend := storage.NewMemoryStorage()
+	manager := NewSimpleManager("").WithStorage(backend)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := manager.WriteAnalyticsContext(cancelled, "events.parquet", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled upload to fail with context.Canceled, got %v", err)
+	}
+	if exists, _ := backend.Exists(context.Background(), "events.parquet"); exists {
+		t.Error("Expected nothing to be uploaded")
+	}
+
+	t.Log("✓ Cancelled writes are not uploaded to storage")
+}
@@ -0,0 +1,170 @@
+package parquet
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go-transport-prac/pkg/database"
+)
+
+// usersTable is the table UserTable loads into
+const usersTable = "users"
+
+// UserTableMigrations create the users table UserTable upserts into. The
+// profile is kept as JSON since it is only read back whole
+func UserTableMigrations() []database.Migration {
+	return []database.Migration{
+		{
+			Version: 1,
+			Name:    "create_users",
+			Up: `CREATE TABLE ` + usersTable + ` (
+				id BIGINT PRIMARY KEY,
+				email TEXT NOT NULL,
+				name TEXT NOT NULL,
+				status TEXT NOT NULL,
+				profile JSONB,
+				created_at TIMESTAMPTZ NOT NULL,
+				updated_at TIMESTAMPTZ NOT NULL
+			)`,
+			Down: `DROP TABLE ` + usersTable,
+		},
+		{
+			Version: 2,
+			Name:    "index_users_updated_at",
+			Up:      `CREATE INDEX users_updated_at_idx ON ` + usersTable + ` (updated_at, id)`,
+			Down:    `DROP INDEX users_updated_at_idx`,
+		},
+	}
+}
+
+// UserTable loads users into and extracts them from a PostgreSQL table
+// created by UserTableMigrations
+type UserTable struct {
+	db *sql.DB
+}
+
+// NewUserTable creates a users table over db, such as the pool of a connected
+// database.Postgres
+func NewUserTable(db *sql.DB) *UserTable {
+	return &UserTable{db: db}
+}
+
+const upsertUserSQL = `INSERT INTO ` + usersTable + ` (id, email, name, status, profile, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (id) DO UPDATE SET
+		email = EXCLUDED.email,
+		name = EXCLUDED.name,
+		status = EXCLUDED.status,
+		profile = EXCLUDED.profile,
+		created_at = EXCLUDED.created_at,
+		updated_at = EXCLUDED.updated_at`
+
+// UpsertUsers inserts users, replacing the rows with the same IDs, in one
+// transaction
+func (t *UserTable) UpsertUsers(ctx context.Context, users []User) error {
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, upsertUserSQL)
+	if err != nil {
+		return fmt.Errorf("failed to prepare upsert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, user := range users {
+		var profile []byte
+		if user.Profile != nil {
+			if profile, err = json.Marshal(user.Profile); err != nil {
+				return fmt.Errorf("failed to marshal profile of user %d: %w", user.ID, err)
+			}
+		}
+		if _, err := stmt.ExecContext(ctx, user.ID, user.Email, user.Name, user.Status, profile,
+			user.CreatedAt.UTC(), user.UpdatedAt.UTC()); err != nil {
+			return fmt.Errorf("failed to upsert user %d: %w", user.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit users: %w", err)
+	}
+	return nil
+}
+
+// ReadUsers reads every user in ID order
+func (t *UserTable) ReadUsers(ctx context.Context) ([]User, error) {
+	rows, err := t.db.QueryContext(ctx, `SELECT id, email, name, status, profile, created_at, updated_at
+		FROM `+usersTable+` ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		var (
+			user    User
+			profile []byte
+		)
+		if err := rows.Scan(&user.ID, &user.Email, &user.Name, &user.Status, &profile, &user.CreatedAt, &user.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		if profile != nil {
+			if err := json.Unmarshal(profile, &user.Profile); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal profile of user %d: %w", user.ID, err)
+			}
+		}
+		user.CreatedAt = user.CreatedAt.In(time.UTC)
+		user.UpdatedAt = user.UpdatedAt.In(time.UTC)
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read users: %w", err)
+	}
+	return users, nil
+}
+
+// Extract reads every user, so the table can be the source of an ETL run
+func (t *UserTable) Extract() ([]User, error) {
+	return t.ReadUsers(context.Background())
+}
+
+// WithUserTable makes the load step also upsert the transformed users into
+// table, before the Parquet output and its checkpoint are committed
+func (dp *DataPipeline) WithUserTable(table *UserTable) *DataPipeline {
+	dp.table = table
+	return dp
+}
+
+// verifyTable reads the users table back and checks it holds every committed
+// user as committed, to the microsecond precision of PostgreSQL timestamps
+func (dp *DataPipeline) verifyTable(committed []User) error {
+	if dp.table == nil {
+		return nil
+	}
+	loaded, err := dp.table.ReadUsers(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to read back database users: %w", err)
+	}
+
+	byID := make(map[int64]User, len(loaded))
+	for _, user := range loaded {
+		byID[user.ID] = user
+	}
+	for _, user := range committed {
+		row, ok := byID[user.ID]
+		if !ok {
+			return fmt.Errorf("user %d is missing from the database", user.ID)
+		}
+		if row.Email != user.Email || row.Status != user.Status || !row.UpdatedAt.Equal(user.UpdatedAt.Truncate(time.Microsecond)) {
+			return fmt.Errorf("user %d differs between the database and the Parquet output", user.ID)
+		}
+	}
+	fmt.Printf("  - Verified %d records in the database\n", len(committed))
+	return nil
+}
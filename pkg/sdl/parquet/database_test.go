@@ -0,0 +1,162 @@
+package parquet
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+
+	"go-transport-prac/internal/config"
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/database"
+)
+
+// fakeUsersTable keeps the rows of the users table by ID
+type fakeUsersTable struct {
+	mu   sync.Mutex
+	rows map[int64][]driver.Value
+}
+
+func newFakeUsersDB(t *testing.T) (*database.Postgres, *fakeUsersTable) {
+	t.Helper()
+	fake := testutil.NewFakeSQL()
+	table := &fakeUsersTable{rows: make(map[int64][]driver.Value)}
+	version := int64(0)
+
+	ok := func([]driver.Value) ([]string, [][]driver.Value, error) { return nil, nil, nil }
+	fake.Handle("SELECT pg_advisory_lock", ok)
+	fake.Handle("SELECT pg_advisory_unlock", ok)
+	fake.Handle("CREATE TABLE IF NOT EXISTS schema_migrations", ok)
+	fake.Handle("SELECT version FROM schema_migrations", func([]driver.Value) ([]string, [][]driver.Value, error) {
+		return []string{"version"}, [][]driver.Value{{version}}, nil
+	})
+	fake.Handle("DELETE FROM schema_migrations", ok)
+	fake.Handle("INSERT INTO schema_migrations", func(args []driver.Value) ([]string, [][]driver.Value, error) {
+		version = args[0].(int64)
+		return nil, nil, nil
+	})
+	fake.Handle("CREATE TABLE users", ok)
+	fake.Handle("CREATE INDEX users_updated_at_idx", ok)
+	fake.Handle("INSERT INTO users", func(args []driver.Value) ([]string, [][]driver.Value, error) {
+		table.mu.Lock()
+		defer table.mu.Unlock()
+		table.rows[args[0].(int64)] = slices.Clone(args)
+		return nil, nil, nil
+	})
+	fake.Handle("SELECT id, email, name, status, profile, created_at, updated_at FROM users ORDER BY id", func([]driver.Value) ([]string, [][]driver.Value, error) {
+		table.mu.Lock()
+		defer table.mu.Unlock()
+		var rows [][]driver.Value
+		for _, id := range slices.Sorted(maps.Keys(table.rows)) {
+			rows = append(rows, table.rows[id])
+		}
+		return []string{"id", "email", "name", "status", "profile", "created_at", "updated_at"}, rows, nil
+	})
+
+	repo := database.NewPostgres(config.DatabaseConfig{Name: "test"}, UserTableMigrations()...).WithDriver(fake.DriverName())
+	if err := repo.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { repo.Disconnect(context.Background()) })
+	if err := repo.Migrate(context.Background()); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if version != 2 {
+		t.Fatalf("Expected both users migrations to be applied, got version %d", version)
+	}
+	return repo, table
+}
+
+func TestUserTable_UpsertAndRead(t *testing.T) {
+	repo, _ := newFakeUsersDB(t)
+	table := NewUserTable(repo.DB())
+	ctx := context.Background()
+
+	users := createSampleUsers(3)
+	if err := table.UpsertUsers(ctx, users); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	// Upserting again replaces the row instead of adding one
+	users[1].Email = "changed@example.com"
+	if err := table.UpsertUsers(ctx, users[1:2]); err != nil {
+		t.Fatalf("Second upsert failed: %v", err)
+	}
+
+	loaded, err := table.Extract()
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(loaded) != 3 || loaded[1].Email != "changed@example.com" {
+		t.Fatalf("Expected 3 users with user 2 updated, got %+v", loaded)
+	}
+	if loaded[0].Profile == nil || loaded[0].Profile.FirstName != users[0].Profile.FirstName {
+		t.Errorf("Expected the profile to round trip, got %+v", loaded[0].Profile)
+	}
+	if !loaded[0].CreatedAt.Equal(users[0].CreatedAt) {
+		t.Errorf("Expected created_at %v, got %v", users[0].CreatedAt, loaded[0].CreatedAt)
+	}
+
+	t.Log("✓ Users are upserted into and read back from the table")
+}
+
+func TestETLWorkflow_DatabaseLoad(t *testing.T) {
+	testDir := "tmp/test_etl_database"
+	repo, rows := newFakeUsersDB(t)
+	table := NewUserTable(repo.DB())
+
+	pipeline := NewDataPipeline(testDir).WithClock(testutil.NewDefaultFakeClock()).WithUserTable(table)
+	defer pipeline.CleanupWorkflow()
+
+	if err := pipeline.RunETLWorkflow(); err != nil {
+		t.Fatalf("ETL workflow failed: %v", err)
+	}
+
+	dataPath, report, err := pipeline.committedOutput()
+	if err != nil {
+		t.Fatalf("Failed to resolve committed output: %v", err)
+	}
+	if len(rows.rows) != report.Records {
+		t.Errorf("Expected %d rows in the table, got %d (%s)", report.Records, len(rows.rows), dataPath)
+	}
+
+	// The table can feed the next run
+	extracted, err := NewDataPipeline(testDir).WithExtractor(table).extract()
+	if err != nil || len(extracted) != report.Records {
+		t.Errorf("Expected to extract %d users from the table, got %d: %v", report.Records, len(extracted), err)
+	}
+
+	// A table that rejects writes fails the load
+	broken := NewUserTable(sqlClosedDB(t))
+	if err := NewDataPipeline(testDir).WithUserTable(broken).RunETLWorkflow(); err == nil {
+		t.Error("Expected a failing table to fail the workflow")
+	}
+
+	// and leaves the checkpoint where it was, so the next run loads the users again
+	incrementalDir := testDir + "_incremental"
+	incremental := NewDataPipeline(incrementalDir).WithUserTable(broken).WithSource(func() ([]User, error) {
+		return createSampleUsers(3), nil
+	})
+	defer incremental.CleanupWorkflow()
+	if _, err := incremental.RunIncrementalETL(); err == nil {
+		t.Error("Expected a failing table to fail the incremental run")
+	}
+	if checkpoint, err := incremental.resumeCheckpoint(); err != nil || !checkpoint.IsZero() {
+		t.Errorf("Expected no checkpoint after a failed database load, got %+v: %v", checkpoint, err)
+	}
+
+	t.Log("✓ The ETL workflow loads users into the database and verifies them")
+}
+
+func sqlClosedDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open(testutil.NewFakeSQL().DriverName(), "")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	db.Close()
+	return db
+}
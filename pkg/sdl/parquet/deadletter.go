@@ -0,0 +1,103 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/format"
+
+	"go-transport-prac/pkg/sdl/deadletter"
+)
+
+// WithDecodeMode sets how whole-file reads handle rows that fail to decode.
+// In deadletter.Tolerant mode a file is read one row group at a time: the raw
+// bytes of a row group that fails are appended to letters, which may be nil,
+// and the rows of the other row groups are returned with a *deadletter.Error
+// summarizing the failures. Streaming readers always read strictly
+func (m *SimpleManager) WithDecodeMode(mode deadletter.Mode, letters *deadletter.File) *SimpleManager {
+	m.decodeMode = mode
+	m.deadLetters = letters
+	return m
+}
+
+// readRowsTolerant reads every row group of file it can. A file whose footer
+// cannot be read has no row groups to salvage and fails as a whole
+func readRowsTolerant[T any](ctx context.Context, m *SimpleManager, filename string, file readerAtCloser, size int64) ([]T, error) {
+	pf, err := parquet.OpenFile(file, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+
+	batch := deadletter.NewBatch(m.deadLetters, filename)
+	metadata := pf.Metadata()
+	var rows []T
+	first := 0
+	for i, rg := range pf.RowGroups() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		numRows := int(rg.NumRows())
+
+		groupRows, err := readRowGroup[T](rg)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			offset, length := rowGroupSpan(metadata.RowGroups[i])
+			data := make([]byte, length)
+			if n, readErr := file.ReadAt(data, offset); readErr != nil && readErr != io.EOF {
+				data = data[:n]
+			}
+			cause := fmt.Errorf("failed to read row group %d: %w", i, err)
+			if err := batch.Fail(offset, first, numRows, data, cause); err != nil {
+				return nil, err
+			}
+		} else {
+			rows = append(rows, groupRows...)
+		}
+		first += numRows
+	}
+	return rows, batch.Err(len(rows))
+}
+
+// readRowGroup reads every row of rg. Corrupt pages can make the decoders
+// panic, which is reported as an error of the row group
+func readRowGroup[T any](rg parquet.RowGroup) (rows []T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			rows, err = nil, fmt.Errorf("corrupt row group: %v", r)
+		}
+	}()
+
+	reader := parquet.NewGenericRowGroupReader[T](rg)
+	defer reader.Close()
+
+	rows = make([]T, rg.NumRows())
+	n, err := reader.Read(rows)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if n < len(rows) {
+		return nil, fmt.Errorf("row group ended after %d of %d rows", n, len(rows))
+	}
+	return rows, nil
+}
+
+// rowGroupSpan returns the byte range of the column chunks of a row group
+func rowGroupSpan(rg format.RowGroup) (offset, length int64) {
+	var end int64
+	for i, chunk := range rg.Columns {
+		meta := chunk.MetaData
+		start := meta.DataPageOffset
+		if meta.DictionaryPageOffset > 0 && meta.DictionaryPageOffset < start {
+			start = meta.DictionaryPageOffset
+		}
+		if i == 0 || start < offset {
+			offset = start
+		}
+		end = max(end, start+meta.TotalCompressedSize)
+	}
+	return offset, end - offset
+}
@@ -0,0 +1,112 @@
+package parquet
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+
+	"go-transport-prac/pkg/sdl/deadletter"
+)
+
+// corruptRowGroup overwrites the column chunks of one row group with garbage
+func corruptRowGroup(t *testing.T, path string, index int) (int64, int64) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	pf, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	offset, length := rowGroupSpan(pf.Metadata().RowGroups[index])
+	copy(data[offset:offset+length], bytes.Repeat([]byte{0xff}, int(length)))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+	return offset, length
+}
+
+func TestTolerantReadDeadLettersRowGroups(t *testing.T) {
+	testDir := "tmp/test_deadletter"
+	defer os.RemoveAll(testDir)
+
+	manager := NewSimpleManager(testDir)
+	users := createSampleUsers(30)
+	if err := manager.WriteUsersWithOptions("users.parquet", users, WriterOptions{RowGroupSize: 10}); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	offset, length := corruptRowGroup(t, filepath.Join(testDir, "users.parquet"), 1)
+
+	if _, err := manager.ReadUsers("users.parquet"); err == nil {
+		t.Fatal("Expected the strict read to fail")
+	}
+
+	letters := deadletter.NewFile(filepath.Join(testDir, "dead", "users.jsonl"))
+	manager.WithDecodeMode(deadletter.Tolerant, letters)
+
+	read, err := manager.ReadUsers("users.parquet")
+	summary, ok := deadletter.Partial(err)
+	if !ok {
+		t.Fatalf("Expected a dead-letter summary, got %v", err)
+	}
+	if len(read) != 20 || read[9].ID != 10 || read[10].ID != 21 || summary.Failed != 10 || summary.Entries != 1 {
+		t.Fatalf("Expected the first and last row groups, got %d users and %+v", len(read), summary)
+	}
+
+	entries, err := deadletter.ReadFile(letters.Path())
+	if err != nil {
+		t.Fatalf("Failed to read dead letters: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 dead letter, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Record != 10 || entry.Records != 10 || entry.Offset != offset || int64(len(entry.Data)) != length {
+		t.Errorf("Unexpected dead letter: offset %d, record %d, %d records, %d bytes", entry.Offset, entry.Record, entry.Records, len(entry.Data))
+	}
+
+	t.Log("✓ Tolerant reads skip corrupt row groups into the dead-letter file")
+}
+
+func TestCompactor_TolerantKeepsDamagedInputs(t *testing.T) {
+	testDir := "tmp/test_deadletter_compaction"
+	defer os.RemoveAll(testDir)
+
+	manager := NewSimpleManager(testDir)
+	users := createSampleUsers(40)
+	if err := manager.WriteUsers("batch_000.parquet", users[:20]); err != nil {
+		t.Fatalf("Failed to write first batch: %v", err)
+	}
+	if err := manager.WriteUsersWithOptions("batch_001.parquet", users[20:], WriterOptions{RowGroupSize: 10}); err != nil {
+		t.Fatalf("Failed to write second batch: %v", err)
+	}
+	corruptRowGroup(t, filepath.Join(testDir, "batch_001.parquet"), 0)
+
+	inputs := []string{"batch_000.parquet", "batch_001.parquet"}
+	if _, err := manager.NewUserCompactor().Compact(inputs, "users"); err == nil {
+		t.Fatal("Expected strict compaction to fail")
+	}
+
+	manager.WithDecodeMode(deadletter.Tolerant, deadletter.NewFile(filepath.Join(testDir, "dead.jsonl")))
+	stats, err := manager.NewUserCompactor().WithDeleteInputs(true).Compact(inputs, "users")
+	if err != nil {
+		t.Fatalf("Failed to compact: %v", err)
+	}
+	if stats.RowsWritten != 30 || stats.DeadLettered != 10 {
+		t.Errorf("Expected 30 rows written and 10 dead-lettered, got %+v", stats)
+	}
+
+	files, err := manager.ListFiles()
+	if err != nil {
+		t.Fatalf("Failed to list files: %v", err)
+	}
+	if slices.Contains(files, "batch_000.parquet") || !slices.Contains(files, "batch_001.parquet") {
+		t.Errorf("Expected only the damaged input to be kept, got %v", files)
+	}
+
+	t.Log("✓ Compaction goes on past corrupt rows and keeps their input")
+}
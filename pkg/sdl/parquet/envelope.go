@@ -0,0 +1,74 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-transport-prac/internal/types"
+)
+
+// RecordHeaders carries per-record metadata stored alongside the payload in envelope files
+type RecordHeaders struct {
+	IngestTime    time.Time         `parquet:"ingest_time"`
+	Source        string            `parquet:"source"`
+	SchemaVersion int32             `parquet:"schema_version"`
+	Tenant        string            `parquet:"tenant"`
+	Extra         map[string]string `parquet:"extra"`
+	// ExpiresAt marks when the record falls out of retention; nil keeps it indefinitely
+	ExpiresAt *time.Time `parquet:"expires_at,optional"`
+}
+
+// NewRecordHeaders creates headers stamped with clock's current time as the
+// ingest time; a nil clock reads the wall clock
+func NewRecordHeaders(clock types.Clock, source, tenant string, schemaVersion int32) RecordHeaders {
+	return RecordHeaders{
+		IngestTime:    types.ClockOrSystem(clock).Now(),
+		Source:        source,
+		SchemaVersion: schemaVersion,
+		Tenant:        tenant,
+		Extra:         map[string]string{},
+	}
+}
+
+// WithTTL sets the headers to expire ttl after the ingest time
+func (h RecordHeaders) WithTTL(ttl time.Duration) RecordHeaders {
+	expiresAt := h.IngestTime.Add(ttl)
+	h.ExpiresAt = &expiresAt
+	return h
+}
+
+// UserRecord is a user wrapped in a record envelope
+type UserRecord struct {
+	Headers RecordHeaders `parquet:"headers"`
+	User    User          `parquet:"payload"`
+}
+
+// WriteUserRecords writes users together with their record headers to a Parquet file
+func (m *SimpleManager) WriteUserRecords(filename string, records []UserRecord) error {
+	return m.WriteUserRecordsContext(context.Background(), filename, records)
+}
+
+// WriteUserRecordsContext writes users together with their record headers to
+// a Parquet file, stopping with ctx.Err() once ctx is done
+func (m *SimpleManager) WriteUserRecordsContext(ctx context.Context, filename string, records []UserRecord) error {
+	if err := writeRows(ctx, m, filename, records); err != nil {
+		return fmt.Errorf("failed to write user records: %w", err)
+	}
+	return nil
+}
+
+// ReadUserRecords reads users and their record headers from a Parquet envelope file
+func (m *SimpleManager) ReadUserRecords(filename string) ([]UserRecord, error) {
+	return m.ReadUserRecordsContext(context.Background(), filename)
+}
+
+// ReadUserRecordsContext reads users and their record headers from a Parquet
+// envelope file, stopping with ctx.Err() once ctx is done
+func (m *SimpleManager) ReadUserRecordsContext(ctx context.Context, filename string) ([]UserRecord, error) {
+	records, err := readRows[UserRecord](ctx, m, filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user records: %w", err)
+	}
+	return records, nil
+}
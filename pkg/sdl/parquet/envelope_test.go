@@ -0,0 +1,67 @@
+package parquet
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/testutil"
+)
+
+func TestUserRecordEnvelope(t *testing.T) {
+	testDir := "tmp/test_envelope_parquet"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+
+	clock := testutil.NewDefaultFakeClock()
+	headers := NewRecordHeaders(clock, "signup-service", "tenant-a", 2)
+	headers.Extra["region"] = "eu-west-1"
+
+	records := []UserRecord{
+		{
+			Headers: headers,
+			User: User{
+				ID:        1,
+				Email:     "envelope@example.com",
+				Name:      "Envelope User",
+				Status:    "active",
+				Profile:   &Profile{FirstName: "Envelope", LastName: "User", Interests: []string{"headers"}, Metadata: map[string]string{}},
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			},
+		},
+		{
+			Headers: NewRecordHeaders(clock, "import-job", "tenant-b", 1),
+			User:    User{ID: 2, Email: "second@example.com", Name: "Second", Status: "inactive", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		},
+	}
+
+	if err := manager.WriteUserRecords("records.parquet", records); err != nil {
+		t.Fatalf("Failed to write user records: %v", err)
+	}
+
+	readRecords, err := manager.ReadUserRecords("records.parquet")
+	if err != nil {
+		t.Fatalf("Failed to read user records: %v", err)
+	}
+
+	if len(readRecords) != len(records) {
+		t.Fatalf("Expected %d records, got %d", len(records), len(readRecords))
+	}
+
+	first := readRecords[0]
+	if first.Headers.Source != "signup-service" || first.Headers.Tenant != "tenant-a" || first.Headers.SchemaVersion != 2 {
+		t.Errorf("Headers mismatch: %+v", first.Headers)
+	}
+	if first.Headers.Extra["region"] != "eu-west-1" {
+		t.Errorf("Extra header mismatch: %v", first.Headers.Extra)
+	}
+	if !first.Headers.IngestTime.Equal(testutil.DefaultFakeTime) {
+		t.Errorf("Ingest time mismatch: expected %v, got %v", testutil.DefaultFakeTime, first.Headers.IngestTime)
+	}
+	if first.User.Email != "envelope@example.com" || readRecords[1].Headers.Tenant != "tenant-b" {
+		t.Errorf("Payload mismatch: %+v", readRecords)
+	}
+
+	t.Log("✓ Parquet record envelope round-trip successful")
+}
@@ -0,0 +1,427 @@
+package parquet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/deadletter"
+)
+
+// Extractor reads the raw users an ETL run starts from
+type Extractor interface {
+	Extract() ([]User, error)
+}
+
+// WithExtractor sets the extractor the ETL reads users from
+func (dp *DataPipeline) WithExtractor(extractor Extractor) *DataPipeline {
+	return dp.WithSource(extractor.Extract)
+}
+
+// FieldMapping maps source columns, or dotted keys of nested JSON objects, to
+// user fields such as email, profile.address.city or profile.metadata.tier.
+// The active field takes a boolean and sets the status to active or inactive
+type FieldMapping map[string]string
+
+// DefaultFieldMapping maps columns named like the user fields, including the
+// id,name,email,active layout of the test CSV data
+func DefaultFieldMapping() FieldMapping {
+	return FieldMapping{
+		"id":          "id",
+		"email":       "email",
+		"name":        "name",
+		"status":      "status",
+		"active":      "active",
+		"first_name":  "profile.first_name",
+		"last_name":   "profile.last_name",
+		"phone":       "profile.phone",
+		"street":      "profile.address.street",
+		"city":        "profile.address.city",
+		"state":       "profile.address.state",
+		"postal_code": "profile.address.postal_code",
+		"country":     "profile.address.country",
+		"interests":   "profile.interests",
+		"created_at":  "created_at",
+		"updated_at":  "updated_at",
+	}
+}
+
+// fieldSetter parses a source value into one user field
+type fieldSetter func(u *User, value string) error
+
+// fieldSetters set the fields a mapping may target
+var fieldSetters = map[string]fieldSetter{
+	"id": func(u *User, value string) error {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid id %q", value)
+		}
+		u.ID = id
+		return nil
+	},
+	"email":  func(u *User, value string) error { u.Email = value; return nil },
+	"name":   func(u *User, value string) error { u.Name = value; return nil },
+	"status": func(u *User, value string) error { u.Status = value; return nil },
+	"active": func(u *User, value string) error {
+		active, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid active flag %q", value)
+		}
+		u.Status = "inactive"
+		if active {
+			u.Status = "active"
+		}
+		return nil
+	},
+	"created_at":         timeSetter(func(u *User) *time.Time { return &u.CreatedAt }),
+	"updated_at":         timeSetter(func(u *User) *time.Time { return &u.UpdatedAt }),
+	"profile.first_name": func(u *User, value string) error { profileOf(u).FirstName = value; return nil },
+	"profile.last_name":  func(u *User, value string) error { profileOf(u).LastName = value; return nil },
+	"profile.phone":      func(u *User, value string) error { profileOf(u).Phone = value; return nil },
+	"profile.interests": func(u *User, value string) error {
+		profileOf(u).Interests = strings.Split(value, ";")
+		return nil
+	},
+	"profile.address.street":      func(u *User, value string) error { addressOf(u).Street = value; return nil },
+	"profile.address.city":        func(u *User, value string) error { addressOf(u).City = value; return nil },
+	"profile.address.state":       func(u *User, value string) error { addressOf(u).State = value; return nil },
+	"profile.address.postal_code": func(u *User, value string) error { addressOf(u).PostalCode = value; return nil },
+	"profile.address.country":     func(u *User, value string) error { addressOf(u).Country = value; return nil },
+}
+
+// timeSetter parses RFC 3339 timestamps or Unix seconds
+func timeSetter(field func(u *User) *time.Time) fieldSetter {
+	return func(u *User, value string) error {
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+			*field(u) = time.Unix(seconds, 0).UTC()
+			return nil
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("invalid timestamp %q", value)
+		}
+		*field(u) = t
+		return nil
+	}
+}
+
+// setterFor resolves the setter of a field path; profile.metadata.<key>
+// sets one metadata entry
+func setterFor(path string) (fieldSetter, error) {
+	if setter, ok := fieldSetters[path]; ok {
+		return setter, nil
+	}
+	if key, ok := strings.CutPrefix(path, "profile.metadata."); ok && key != "" {
+		return func(u *User, value string) error {
+			metadataOf(u)[key] = value
+			return nil
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown field %q", path)
+}
+
+// fileFormat is the layout of an extracted file
+type fileFormat int
+
+const (
+	formatCSV fileFormat = iota
+	formatJSONLines
+)
+
+// FileExtractor reads users from a CSV file with a header row or a JSON
+// Lines file of one object per line, mapping columns to user fields
+type FileExtractor struct {
+	path    string
+	format  fileFormat
+	mapping FieldMapping
+	comma   rune
+	mode    deadletter.Mode
+	letters *deadletter.File
+	clock   types.Clock
+}
+
+// NewCSVExtractor creates an extractor reading a comma separated file whose
+// first row names the columns
+func NewCSVExtractor(path string) *FileExtractor {
+	return newFileExtractor(path, formatCSV)
+}
+
+// NewJSONLinesExtractor creates an extractor reading one JSON object per line
+func NewJSONLinesExtractor(path string) *FileExtractor {
+	return newFileExtractor(path, formatJSONLines)
+}
+
+func newFileExtractor(path string, format fileFormat) *FileExtractor {
+	return &FileExtractor{
+		path:    path,
+		format:  format,
+		mapping: DefaultFieldMapping(),
+		comma:   ',',
+		clock:   types.SystemClock{},
+	}
+}
+
+// WithMapping replaces the default field mapping. Mapped columns missing
+// from a row are left unset; columns without a mapping are ignored
+func (e *FileExtractor) WithMapping(mapping FieldMapping) *FileExtractor {
+	e.mapping = mapping
+	return e
+}
+
+// WithComma sets the CSV field delimiter
+func (e *FileExtractor) WithComma(comma rune) *FileExtractor {
+	e.comma = comma
+	return e
+}
+
+// WithDecodeMode sets how malformed rows are handled. In deadletter.Tolerant
+// mode they are appended to letters, which may be nil, and the good users are
+// returned with a *deadletter.Error; the pipeline goes on with them
+func (e *FileExtractor) WithDecodeMode(mode deadletter.Mode, letters *deadletter.File) *FileExtractor {
+	e.mode = mode
+	e.letters = letters
+	return e
+}
+
+// WithClock sets the clock that stamps rows without an updated_at
+func (e *FileExtractor) WithClock(clock types.Clock) *FileExtractor {
+	e.clock = types.ClockOrSystem(clock)
+	return e
+}
+
+// rawRow is one row of the source before it is mapped
+type rawRow struct {
+	line   int
+	offset int64
+	data   []byte
+	values map[string]string
+	err    error
+}
+
+// Extract reads every row of the file. A malformed row fails the extraction
+// in strict mode, the default
+func (e *FileExtractor) Extract() ([]User, error) {
+	setters := make(map[string]fieldSetter, len(e.mapping))
+	for column, field := range e.mapping {
+		setter, err := setterFor(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mapping for column %s: %w", column, err)
+		}
+		setters[column] = setter
+	}
+
+	data, err := os.ReadFile(e.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", e.path, err)
+	}
+
+	var rows []rawRow
+	switch e.format {
+	case formatCSV:
+		rows, err = e.csvRows(data)
+	default:
+		rows, err = jsonLinesRows(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	batch := deadletter.NewBatch(e.letters, e.path)
+	now := e.clock.Now()
+	source := filepath.Base(e.path)
+	var users []User
+	for i, row := range rows {
+		err := row.err
+		var user User
+		if err == nil {
+			user, err = mapRow(row.values, setters)
+		}
+		if err != nil {
+			err = fmt.Errorf("malformed row at line %d: %w", row.line, err)
+			if e.mode != deadletter.Tolerant {
+				return nil, err
+			}
+			if err := batch.Fail(row.offset, i, 1, row.data, err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if user.UpdatedAt.IsZero() {
+			user.UpdatedAt = now
+		}
+		if user.CreatedAt.IsZero() {
+			user.CreatedAt = user.UpdatedAt
+		}
+		metadata := metadataOf(&user)
+		metadata["source"] = source
+		metadata["extracted"] = now.Format(time.RFC3339)
+		users = append(users, user)
+	}
+	return users, batch.Err(len(users))
+}
+
+// mapRow sets the mapped fields of a user from the values of a row, in column
+// order so errors are reported consistently
+func mapRow(values map[string]string, setters map[string]fieldSetter) (User, error) {
+	var user User
+	columns := make([]string, 0, len(values))
+	for column := range values {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	for _, column := range columns {
+		setter, ok := setters[column]
+		value := strings.TrimSpace(values[column])
+		if !ok || value == "" {
+			continue
+		}
+		if err := setter(&user, value); err != nil {
+			return user, fmt.Errorf("column %s: %w", column, err)
+		}
+	}
+	if user.ID <= 0 {
+		return user, fmt.Errorf("missing id")
+	}
+	return user, nil
+}
+
+// csvRows splits data into rows keyed by the header. A row with the wrong
+// number of fields or broken quoting is returned with its error
+func (e *FileExtractor) csvRows(data []byte) ([]rawRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = e.comma
+	reader.ReuseRecord = false
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	var rows []rawRow
+	for {
+		offset := reader.InputOffset()
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		end := reader.InputOffset()
+		line, _ := reader.FieldPos(0)
+		row := rawRow{line: line, offset: offset, data: bytes.TrimRight(data[offset:end], "\r\n")}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			row.line = parseErr.StartLine
+			row.err = parseErr.Err
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		} else {
+			row.values = make(map[string]string, len(header))
+			for i, column := range header {
+				row.values[column] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// jsonLinesRows parses each non-blank line as a JSON object, flattening
+// nested objects into dotted keys
+func jsonLinesRows(data []byte) ([]rawRow, error) {
+	var rows []rawRow
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	scanner.Split(scanRawLines)
+
+	var offset int64
+	for line := 1; scanner.Scan(); line++ {
+		start := offset
+		offset += int64(len(scanner.Bytes()))
+		raw := bytes.TrimSuffix(bytes.TrimSuffix(scanner.Bytes(), []byte("\n")), []byte("\r"))
+		if len(bytes.TrimSpace(raw)) == 0 {
+			continue
+		}
+
+		row := rawRow{line: line, offset: start, data: bytes.Clone(raw)}
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); err != nil {
+			row.err = fmt.Errorf("invalid JSON: %w", err)
+		} else {
+			row.values = make(map[string]string)
+			row.err = flattenJSON("", object, row.values)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read JSON lines: %w", err)
+	}
+	return rows, nil
+}
+
+// scanRawLines splits like bufio.ScanLines but keeps each line's terminator,
+// so offsets count the bytes actually consumed
+func scanRawLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i+1], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// flattenJSON converts the values of object to strings under dotted keys;
+// arrays of strings are joined with semicolons
+func flattenJSON(prefix string, object map[string]interface{}, values map[string]string) error {
+	for key, value := range object {
+		key = prefix + key
+		switch v := value.(type) {
+		case nil:
+		case string:
+			values[key] = v
+		case json.Number:
+			values[key] = v.String()
+		case bool:
+			values[key] = strconv.FormatBool(v)
+		case map[string]interface{}:
+			if err := flattenJSON(key+".", v, values); err != nil {
+				return err
+			}
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return fmt.Errorf("key %s: only arrays of strings are supported", key)
+				}
+				items[i] = s
+			}
+			values[key] = strings.Join(items, ";")
+		}
+	}
+	return nil
+}
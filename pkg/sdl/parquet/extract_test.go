@@ -0,0 +1,181 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/deadletter"
+)
+
+func writeExtractFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create test dir: %v", err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestExtract_CSV(t *testing.T) {
+	testDir := "tmp/test_extract_csv"
+	defer os.RemoveAll(testDir)
+
+	clock := testutil.NewDefaultFakeClock()
+	path := writeExtractFile(t, testDir, "users.csv", testutil.MockData.CSVData)
+
+	users, err := NewCSVExtractor(path).WithClock(clock).Extract()
+	if err != nil {
+		t.Fatalf("Extraction failed: %v", err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("Expected 2 users, got %d", len(users))
+	}
+	if users[0].ID != 1 || users[0].Name != "Test User" || users[0].Email != "test@example.com" || users[0].Status != "active" {
+		t.Errorf("Unexpected first user: %+v", users[0])
+	}
+	if users[1].Status != "inactive" {
+		t.Errorf("Expected active=false to map to inactive, got %q", users[1].Status)
+	}
+	if !users[0].UpdatedAt.Equal(testutil.DefaultFakeTime) || !users[0].CreatedAt.Equal(testutil.DefaultFakeTime) {
+		t.Errorf("Expected rows stamped with the clock, got %v and %v", users[0].CreatedAt, users[0].UpdatedAt)
+	}
+	if users[0].Profile == nil || users[0].Profile.Metadata["source"] != "users.csv" {
+		t.Errorf("Expected source metadata, got %+v", users[0].Profile)
+	}
+
+	// A custom mapping and delimiter
+	path = writeExtractFile(t, testDir, "custom.csv", "user_id;mail;town;tier\n7;a@example.com;Taipei;gold\n")
+	users, err = NewCSVExtractor(path).WithComma(';').WithMapping(FieldMapping{
+		"user_id": "id",
+		"mail":    "email",
+		"town":    "profile.address.city",
+		"tier":    "profile.metadata.tier",
+	}).Extract()
+	if err != nil {
+		t.Fatalf("Custom extraction failed: %v", err)
+	}
+	if len(users) != 1 || users[0].ID != 7 || users[0].Email != "a@example.com" {
+		t.Fatalf("Unexpected custom users: %+v", users)
+	}
+	if users[0].Profile.Address == nil || users[0].Profile.Address.City != "Taipei" || users[0].Profile.Metadata["tier"] != "gold" {
+		t.Errorf("Expected mapped profile fields, got %+v", users[0].Profile)
+	}
+
+	if _, err := NewCSVExtractor(path).WithMapping(FieldMapping{"user_id": "nickname"}).Extract(); err == nil {
+		t.Error("Expected an unknown mapped field to fail")
+	}
+
+	t.Log("✓ CSV files are extracted through the field mapping")
+}
+
+func TestExtract_JSONLines(t *testing.T) {
+	testDir := "tmp/test_extract_jsonl"
+	defer os.RemoveAll(testDir)
+
+	var line bytes.Buffer
+	if err := json.Compact(&line, []byte(testutil.MockData.JSONData)); err != nil {
+		t.Fatalf("Failed to compact JSON: %v", err)
+	}
+	content := line.String() + "\n\n" + `{"id": 2, "email": "b@example.com", "active": false}` + "\n"
+	path := writeExtractFile(t, testDir, "users.jsonl", content)
+
+	mapping := DefaultFieldMapping()
+	mapping["metadata.created_at"] = "created_at"
+	mapping["metadata.tags"] = "profile.interests"
+	users, err := NewJSONLinesExtractor(path).WithMapping(mapping).Extract()
+	if err != nil {
+		t.Fatalf("Extraction failed: %v", err)
+	}
+	if len(users) != 2 || users[0].ID != 1 || users[1].ID != 2 {
+		t.Fatalf("Expected users 1 and 2, got %+v", users)
+	}
+	if !users[0].CreatedAt.Equal(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the nested created_at, got %v", users[0].CreatedAt)
+	}
+	if interests := users[0].Profile.Interests; len(interests) != 2 || interests[0] != "test" || interests[1] != "user" {
+		t.Errorf("Expected the nested tags as interests, got %v", interests)
+	}
+	if users[0].Status != "active" || users[1].Status != "inactive" {
+		t.Errorf("Unexpected statuses %q and %q", users[0].Status, users[1].Status)
+	}
+
+	t.Log("✓ JSON Lines files are extracted with nested keys")
+}
+
+func TestExtract_MalformedRows(t *testing.T) {
+	testDir := "tmp/test_extract_malformed"
+	defer os.RemoveAll(testDir)
+
+	csvPath := writeExtractFile(t, testDir, "users.csv", testutil.MockData.CSVData+
+		"\nthree,Bad Id,bad@example.com,true"+
+		"\n4,Too,Many,Fields,here"+
+		"\n5,Missing Flag,missing@example.com,maybe"+
+		"\n6,Last User,last@example.com,true\n")
+	jsonPath := writeExtractFile(t, testDir, "users.jsonl",
+		`{"id": 1, "email": "a@example.com"}`+"\n{not json\n"+`{"email": "no-id@example.com"}`+"\n")
+
+	// Strict mode fails on the first malformed row
+	if _, err := NewCSVExtractor(csvPath).Extract(); err == nil || !strings.Contains(err.Error(), "line 4") {
+		t.Fatalf("Expected strict extraction to fail at line 4, got %v", err)
+	}
+
+	letters := deadletter.NewFile(filepath.Join(testDir, "dead", "users.jsonl"))
+	users, err := NewCSVExtractor(csvPath).WithDecodeMode(deadletter.Tolerant, letters).Extract()
+	summary, ok := deadletter.Partial(err)
+	if !ok {
+		t.Fatalf("Expected a partial extraction, got %v", err)
+	}
+	if len(users) != 3 || users[2].ID != 6 || summary.Failed != 3 || summary.Good != 3 {
+		t.Fatalf("Expected 3 good and 3 failed rows, got %d users and %+v", len(users), summary)
+	}
+
+	entries, err := deadletter.ReadFile(letters.Path())
+	if err != nil {
+		t.Fatalf("Failed to read dead letters: %v", err)
+	}
+	if len(entries) != 3 || string(entries[0].Data) != "three,Bad Id,bad@example.com,true" || entries[1].Record != 3 {
+		t.Errorf("Unexpected dead letters: %+v", entries)
+	}
+
+	users, err = NewJSONLinesExtractor(jsonPath).WithDecodeMode(deadletter.Tolerant, letters).Extract()
+	if summary, ok := deadletter.Partial(err); !ok || summary.Failed != 2 || len(users) != 1 {
+		t.Fatalf("Expected 1 good and 2 failed JSON lines, got %d users and %v", len(users), err)
+	}
+	if letters.Count() != 5 {
+		t.Errorf("Expected 5 dead letters, got %d", letters.Count())
+	}
+
+	// Offsets count the CR of CRLF line endings
+	good := `{"id": 1, "email": "a@example.com"}`
+	crlfPath := writeExtractFile(t, testDir, "crlf.jsonl", good+"\r\n"+good+"\r\n{not json\r\n")
+	crlfLetters := deadletter.NewFile(filepath.Join(testDir, "dead", "crlf.jsonl"))
+	if _, err := NewJSONLinesExtractor(crlfPath).WithDecodeMode(deadletter.Tolerant, crlfLetters).Extract(); err == nil {
+		t.Fatal("Expected a partial extraction of CRLF lines")
+	}
+	entries, err = deadletter.ReadFile(crlfLetters.Path())
+	if err != nil {
+		t.Fatalf("Failed to read dead letters: %v", err)
+	}
+	content, _ := os.ReadFile(crlfPath)
+	if want := int64(bytes.Index(content, []byte("{not"))); len(entries) != 1 || entries[0].Offset != want || string(entries[0].Data) != "{not json" {
+		t.Errorf("Expected a dead letter of {not json at offset %d, got %+v", want, entries)
+	}
+
+	// The pipeline goes on with the good rows of a tolerant extractor
+	pipeline := NewDataPipeline(testDir).WithExtractor(NewCSVExtractor(csvPath).WithDecodeMode(deadletter.Tolerant, nil))
+	users, err = pipeline.extract()
+	if err != nil || len(users) != 3 {
+		t.Fatalf("Expected the pipeline to extract 3 users, got %d: %v", len(users), err)
+	}
+
+	t.Log("✓ Malformed rows fail strict extraction and are dead-lettered in tolerant mode")
+}
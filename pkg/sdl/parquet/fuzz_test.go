@@ -0,0 +1,52 @@
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"go-transport-prac/pkg/storage"
+)
+
+func FuzzParquetRead(f *testing.F) {
+	backend := storage.NewMemoryStorage()
+	manager := NewSimpleManager("").WithStorage(backend)
+	ctx := context.Background()
+
+	for _, count := range []int{1, 3} {
+		if err := manager.WriteUsers("seed.parquet", createSampleUsers(count)); err != nil {
+			f.Fatalf("Failed to write seed: %v", err)
+		}
+		r, err := backend.Get(ctx, "seed.parquet")
+		if err != nil {
+			f.Fatalf("Failed to get seed: %v", err)
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			f.Fatalf("Failed to read seed: %v", err)
+		}
+		f.Add(data)
+		f.Add(data[:len(data)/2])
+		// Corrupt the pages but keep the footer
+		flipped := append([]byte(nil), data...)
+		flipped[len(flipped)/3] ^= 0xff
+		f.Add(flipped)
+	}
+	f.Add([]byte("PAR1PAR1"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := backend.Put(ctx, "fuzz.parquet", bytes.NewReader(data)); err != nil {
+			t.Fatalf("Failed to put input: %v", err)
+		}
+		users, err := manager.ReadUsers("fuzz.parquet")
+		if err != nil {
+			return
+		}
+		// Whatever decodes must encode again
+		if err := manager.WriteUsers("roundtrip.parquet", users); err != nil {
+			t.Errorf("Decoded users do not encode: %v", err)
+		}
+	})
+}
@@ -0,0 +1,222 @@
+package parquet
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/atomicfile"
+	"go-transport-prac/internal/testutil"
+)
+
+// sourceUser is a complete user updated at updatedAt, so it passes verification
+func sourceUser(id int64, name string, updatedAt time.Time) User {
+	return User{
+		ID:     id,
+		Email:  fmt.Sprintf("user%d@example.com", id),
+		Name:   name,
+		Status: "active",
+		Profile: &Profile{
+			Phone:   "+1-555-0100",
+			Address: &Address{City: "Boston", Country: "USA"},
+		},
+		CreatedAt: updatedAt,
+		UpdatedAt: updatedAt,
+	}
+}
+
+// sameCheckpoint compares checkpoints that may have been through JSON
+func sameCheckpoint(a, b Checkpoint) bool {
+	return a.LastUpdatedAt.Equal(b.LastUpdatedAt) && a.LastID == b.LastID &&
+		a.RunID == b.RunID && a.Records == b.Records && a.SavedAt.Equal(b.SavedAt)
+}
+
+// failingStore fails the next save, as if the process died after committing
+type failingStore struct {
+	CheckpointStore
+	failNext bool
+}
+
+func (s *failingStore) Save(checkpoint Checkpoint) error {
+	if s.failNext {
+		s.failNext = false
+		return fmt.Errorf("disk full")
+	}
+	return s.CheckpointStore.Save(checkpoint)
+}
+
+func TestIncrementalETL(t *testing.T) {
+	testDir := "tmp/test_incremental_etl"
+	clock := testutil.NewDefaultFakeClock()
+	t0 := clock.Now()
+
+	records := map[int64]string{1: "Alice Smith", 2: "Bob Johnson", 3: "Carol White"}
+	updated := map[int64]time.Time{1: t0, 2: t0, 3: t0.Add(time.Minute)}
+	pipeline := NewDataPipeline(testDir).WithClock(clock).WithSource(func() ([]User, error) {
+		var users []User
+		for id := int64(1); id <= int64(len(records)); id++ {
+			users = append(users, sourceUser(id, records[id], updated[id]))
+		}
+		return users, nil
+	})
+	defer pipeline.CleanupWorkflow()
+
+	stats, err := pipeline.RunIncrementalETL()
+	if err != nil {
+		t.Fatalf("First run failed: %v", err)
+	}
+	if stats.Loaded != 3 || stats.Total != 3 || !stats.Resumed.IsZero() {
+		t.Errorf("Unexpected first run: %+v", stats)
+	}
+	if stats.Checkpoint.LastID != 3 || !stats.Checkpoint.LastUpdatedAt.Equal(t0.Add(time.Minute)) || stats.Checkpoint.Records != 3 {
+		t.Errorf("Unexpected checkpoint: %+v", stats.Checkpoint)
+	}
+
+	// Nothing new: nothing is transformed or committed
+	clock.Advance(time.Hour)
+	stats, err = pipeline.RunIncrementalETL()
+	if err != nil {
+		t.Fatalf("Second run failed: %v", err)
+	}
+	if stats.Extracted != 3 || stats.Loaded != 0 || stats.Checkpoint.RunID != "20240101_120000" {
+		t.Errorf("Expected nothing to load, got %+v", stats)
+	}
+
+	// A new user and an update to Bob are picked up; Bob is replaced, not duplicated
+	records[4] = "Dave Brown"
+	updated[4] = t0.Add(2 * time.Minute)
+	records[2] = "Robert Johnson"
+	updated[2] = t0.Add(3 * time.Minute)
+	clock.Advance(time.Hour)
+	stats, err = pipeline.RunIncrementalETL()
+	if err != nil {
+		t.Fatalf("Third run failed: %v", err)
+	}
+	if stats.Loaded != 2 || stats.Total != 4 || stats.Checkpoint.LastID != 2 || stats.Checkpoint.Records != 5 {
+		t.Errorf("Unexpected third run: %+v", stats)
+	}
+
+	dataPath, report, err := pipeline.committedOutput()
+	if err != nil {
+		t.Fatalf("Failed to resolve committed output: %v", err)
+	}
+	users, err := NewSimpleManager(filepath.Dir(dataPath)).ReadUsers(filepath.Base(dataPath))
+	if err != nil {
+		t.Fatalf("Failed to read committed users: %v", err)
+	}
+	if report.Records != 4 || len(users) != 4 || users[1].ID != 2 || users[1].Name != "Robert Johnson" {
+		t.Errorf("Expected 4 users with Bob updated, got %d: %+v", len(users), users)
+	}
+
+	saved, err := NewFileCheckpointStore(filepath.Join(pipeline.processedDir, "users_etl.checkpoint.json")).Load()
+	if err != nil {
+		t.Fatalf("Failed to load checkpoint: %v", err)
+	}
+	if !sameCheckpoint(saved, stats.Checkpoint) {
+		t.Errorf("Expected saved checkpoint %+v, got %+v", stats.Checkpoint, saved)
+	}
+
+	t.Log("✓ Incremental runs load only records newer than the checkpoint")
+}
+
+func TestIncrementalETL_ResumeAfterFailure(t *testing.T) {
+	testDir := "tmp/test_incremental_resume"
+	clock := testutil.NewDefaultFakeClock()
+	t0 := clock.Now()
+
+	count := 2
+	failExtract := true
+	store := &failingStore{CheckpointStore: NewFileCheckpointStore(filepath.Join(testDir, "processed", "users_etl.checkpoint.json"))}
+	pipeline := NewDataPipeline(testDir).WithClock(clock).WithCheckpointStore(store).WithSource(func() ([]User, error) {
+		if failExtract {
+			return nil, fmt.Errorf("source unavailable")
+		}
+		var users []User
+		for id := 1; id <= count; id++ {
+			users = append(users, sourceUser(int64(id), fmt.Sprintf("User Number%d", id), t0.Add(time.Duration(id)*time.Minute)))
+		}
+		return users, nil
+	})
+	defer pipeline.CleanupWorkflow()
+
+	// A failed extraction leaves the checkpoint alone
+	if _, err := pipeline.RunIncrementalETL(); err == nil {
+		t.Fatal("Expected the extraction to fail")
+	}
+	if checkpoint, err := store.Load(); err != nil || !checkpoint.IsZero() {
+		t.Fatalf("Expected no checkpoint after a failed run, got %+v (%v)", checkpoint, err)
+	}
+
+	// The retry loads everything, but dies before saving its checkpoint
+	failExtract = false
+	store.failNext = true
+	if _, err := pipeline.RunIncrementalETL(); err == nil {
+		t.Fatal("Expected the checkpoint save to fail")
+	}
+	if checkpoint, _ := store.Load(); !checkpoint.IsZero() {
+		t.Fatalf("Expected the stored checkpoint to lag behind, got %+v", checkpoint)
+	}
+
+	// The next run resumes from the checkpoint committed with the output
+	count = 3
+	clock.Advance(time.Hour)
+	stats, err := pipeline.RunIncrementalETL()
+	if err != nil {
+		t.Fatalf("Resumed run failed: %v", err)
+	}
+	if stats.Resumed.LastID != 2 || stats.Loaded != 1 || stats.Total != 3 || stats.Checkpoint.Records != 3 {
+		t.Errorf("Expected to resume after user 2 and load user 3, got %+v", stats)
+	}
+	if checkpoint, _ := store.Load(); !sameCheckpoint(checkpoint, stats.Checkpoint) {
+		t.Errorf("Expected the store to be repaired, got %+v", checkpoint)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(testDir, "processed"))
+	if err != nil {
+		t.Fatalf("Failed to list processed files: %v", err)
+	}
+	for _, entry := range entries {
+		if atomicfile.IsTemp(entry.Name()) {
+			t.Errorf("Expected no temporary checkpoint file, got %s", entry.Name())
+		}
+	}
+
+	t.Log("✓ Incremental ETL resumes without reloading committed records")
+}
+
+func TestFileCheckpointStoreConcurrentSaves(t *testing.T) {
+	testDir := "tmp/test_checkpoint_store"
+	defer os.RemoveAll(testDir)
+	store := NewFileCheckpointStore(filepath.Join(testDir, "users_etl.checkpoint.json"))
+
+	// Every saver writes its own temporary file, so one of them wins whole
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := store.Save(Checkpoint{LastID: int64(i + 1), Records: i + 1}); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("Concurrent save failed: %v", err)
+	}
+
+	checkpoint, err := store.Load()
+	if err != nil {
+		t.Fatalf("Failed to load checkpoint: %v", err)
+	}
+	if checkpoint.LastID < 1 || int64(checkpoint.Records) != checkpoint.LastID {
+		t.Errorf("Expected one saver's whole checkpoint, got %+v", checkpoint)
+	}
+
+	t.Log("✓ Concurrent checkpoint saves never mix")
+}
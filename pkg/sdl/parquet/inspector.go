@@ -0,0 +1,357 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/format"
+)
+
+// ColumnChunkInfo describes one column chunk within a row group
+type ColumnChunkInfo struct {
+	Path             string   `json:"path"`
+	Type             string   `json:"type"`
+	Compression      string   `json:"compression"`
+	Encodings        []string `json:"encodings"`
+	NumValues        int64    `json:"numValues"`
+	NullCount        int64    `json:"nullCount"`
+	Min              string   `json:"min,omitempty"`
+	Max              string   `json:"max,omitempty"`
+	HasMinMax        bool     `json:"hasMinMax"`
+	Dictionary       bool     `json:"dictionary"`
+	CompressedSize   int64    `json:"compressedSize"`
+	UncompressedSize int64    `json:"uncompressedSize"`
+}
+
+// RowGroupInfo describes one row group of a file
+type RowGroupInfo struct {
+	Index         int               `json:"index"`
+	NumRows       int64             `json:"numRows"`
+	TotalByteSize int64             `json:"totalByteSize"`
+	Columns       []ColumnChunkInfo `json:"columns"`
+}
+
+// ColumnSummary aggregates a column's chunks across every row group of a file
+type ColumnSummary struct {
+	Path             string   `json:"path"`
+	Type             string   `json:"type"`
+	Compression      string   `json:"compression"`
+	Encodings        []string `json:"encodings"`
+	NumValues        int64    `json:"numValues"`
+	NullCount        int64    `json:"nullCount"`
+	Min              string   `json:"min,omitempty"`
+	Max              string   `json:"max,omitempty"`
+	HasMinMax        bool     `json:"hasMinMax"`
+	Dictionary       bool     `json:"dictionary"`
+	CompressedSize   int64    `json:"compressedSize"`
+	UncompressedSize int64    `json:"uncompressedSize"`
+}
+
+// CompressionRatio returns uncompressed over compressed size
+func (c ColumnSummary) CompressionRatio() float64 {
+	if c.CompressedSize == 0 {
+		return 0
+	}
+	return float64(c.UncompressedSize) / float64(c.CompressedSize)
+}
+
+// FileReport is the footer metadata of a Parquet file, per row group and per column
+type FileReport struct {
+	Filename  string          `json:"filename"`
+	FileSize  int64           `json:"fileSize"`
+	NumRows   int64           `json:"numRows"`
+	CreatedBy string          `json:"createdBy,omitempty"`
+	RowGroups []RowGroupInfo  `json:"rowGroups"`
+	Columns   []ColumnSummary `json:"columns"`
+}
+
+// Column returns the summary of the column at a dotted path such as profile.address.city
+func (r *FileReport) Column(path string) (ColumnSummary, bool) {
+	for _, c := range r.Columns {
+		if c.Path == path {
+			return c, true
+		}
+	}
+	return ColumnSummary{}, false
+}
+
+// Summary totals the reports of one or more files
+type Summary struct {
+	Files             int      `json:"files"`
+	RowGroups         int      `json:"rowGroups"`
+	Rows              int64    `json:"rows"`
+	FileBytes         int64    `json:"fileBytes"`
+	CompressedBytes   int64    `json:"compressedBytes"`
+	UncompressedBytes int64    `json:"uncompressedBytes"`
+	NullValues        int64    `json:"nullValues"`
+	DictionaryColumns []string `json:"dictionaryColumns"`
+}
+
+// CompressionRatio returns uncompressed over compressed column data size
+func (s Summary) CompressionRatio() float64 {
+	if s.CompressedBytes == 0 {
+		return 0
+	}
+	return float64(s.UncompressedBytes) / float64(s.CompressedBytes)
+}
+
+// Summarize totals file reports. DictionaryColumns lists columns that are
+// dictionary encoded in at least one file
+func Summarize(reports ...*FileReport) Summary {
+	var s Summary
+	dictionary := make(map[string]bool)
+
+	for _, r := range reports {
+		s.Files++
+		s.RowGroups += len(r.RowGroups)
+		s.Rows += r.NumRows
+		s.FileBytes += r.FileSize
+		for _, c := range r.Columns {
+			s.CompressedBytes += c.CompressedSize
+			s.UncompressedBytes += c.UncompressedSize
+			s.NullValues += c.NullCount
+			if c.Dictionary {
+				dictionary[c.Path] = true
+			}
+		}
+	}
+
+	s.DictionaryColumns = make([]string, 0, len(dictionary))
+	for path := range dictionary {
+		s.DictionaryColumns = append(s.DictionaryColumns, path)
+	}
+	sort.Strings(s.DictionaryColumns)
+	return s
+}
+
+// FileInspector reads Parquet footers without decoding any rows
+type FileInspector struct {
+	manager *SimpleManager
+}
+
+// NewFileInspector creates an inspector for files managed by manager
+func NewFileInspector(manager *SimpleManager) *FileInspector {
+	return &FileInspector{manager: manager}
+}
+
+// Inspect reports row groups, column chunks and column statistics of a file
+func (fi *FileInspector) Inspect(filename string) (*FileReport, error) {
+	file, size, err := fi.manager.openFile(context.Background(), filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	pf, err := parquet.OpenFile(file, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+
+	metadata := pf.Metadata()
+	indexes := pf.ColumnIndexes()
+	report := &FileReport{
+		Filename:  filename,
+		FileSize:  size,
+		NumRows:   pf.NumRows(),
+		CreatedBy: metadata.CreatedBy,
+		RowGroups: make([]RowGroupInfo, 0, len(metadata.RowGroups)),
+	}
+
+	columns := make(map[string]*columnAccumulator)
+	var order []string
+
+	for i, rg := range metadata.RowGroups {
+		info := RowGroupInfo{
+			Index:         i,
+			NumRows:       rg.NumRows,
+			TotalByteSize: rg.TotalByteSize,
+			Columns:       make([]ColumnChunkInfo, 0, len(rg.Columns)),
+		}
+
+		for j, chunk := range rg.Columns {
+			meta := chunk.MetaData
+			path := strings.Join(meta.PathInSchema, ".")
+			leaf, ok := pf.Schema().Lookup(meta.PathInSchema...)
+			if !ok {
+				return nil, fmt.Errorf("row group %d has column %s missing from the schema", i, path)
+			}
+
+			chunkInfo := ColumnChunkInfo{
+				Path:             path,
+				Type:             meta.Type.String(),
+				Compression:      meta.Codec.String(),
+				Encodings:        encodingNames(meta.Encoding),
+				NumValues:        meta.NumValues,
+				NullCount:        meta.Statistics.NullCount,
+				Dictionary:       usesDictionary(meta),
+				CompressedSize:   meta.TotalCompressedSize,
+				UncompressedSize: meta.TotalUncompressedSize,
+			}
+
+			var index *format.ColumnIndex
+			if k := i*len(rg.Columns) + j; k < len(indexes) {
+				index = &indexes[k]
+			}
+			min, max, ok := minMax(leaf.Node, index, meta.Statistics)
+			if ok {
+				chunkInfo.HasMinMax = true
+				chunkInfo.Min = formatValue(leaf.Node, min)
+				chunkInfo.Max = formatValue(leaf.Node, max)
+			}
+			info.Columns = append(info.Columns, chunkInfo)
+
+			acc, exists := columns[path]
+			if !exists {
+				acc = &columnAccumulator{node: leaf.Node}
+				columns[path] = acc
+				order = append(order, path)
+			}
+			acc.add(chunkInfo, min, max, ok)
+		}
+
+		report.RowGroups = append(report.RowGroups, info)
+	}
+
+	for _, path := range order {
+		report.Columns = append(report.Columns, columns[path].summary())
+	}
+	return report, nil
+}
+
+// InspectAll inspects several files and totals them
+func (fi *FileInspector) InspectAll(filenames ...string) ([]*FileReport, Summary, error) {
+	reports := make([]*FileReport, 0, len(filenames))
+	for _, filename := range filenames {
+		report, err := fi.Inspect(filename)
+		if err != nil {
+			return nil, Summary{}, fmt.Errorf("failed to inspect %s: %w", filename, err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, Summarize(reports...), nil
+}
+
+// columnAccumulator merges a column's chunks across row groups
+type columnAccumulator struct {
+	node     parquet.Node
+	totals   ColumnSummary
+	min, max parquet.Value
+	hasStats bool
+	seen     map[string]bool
+}
+
+func (a *columnAccumulator) add(chunk ColumnChunkInfo, min, max parquet.Value, hasMinMax bool) {
+	s := &a.totals
+	if s.Path == "" {
+		s.Path, s.Type, s.Compression = chunk.Path, chunk.Type, chunk.Compression
+		a.seen = make(map[string]bool)
+	}
+	for _, e := range chunk.Encodings {
+		if !a.seen[e] {
+			a.seen[e] = true
+			s.Encodings = append(s.Encodings, e)
+		}
+	}
+	s.NumValues += chunk.NumValues
+	s.NullCount += chunk.NullCount
+	s.Dictionary = s.Dictionary || chunk.Dictionary
+	s.CompressedSize += chunk.CompressedSize
+	s.UncompressedSize += chunk.UncompressedSize
+
+	if !hasMinMax {
+		return
+	}
+	compare := a.node.Type().Compare
+	if !a.hasStats || compare(min, a.min) < 0 {
+		a.min = min
+	}
+	if !a.hasStats || compare(max, a.max) > 0 {
+		a.max = max
+	}
+	a.hasStats = true
+}
+
+func (a *columnAccumulator) summary() ColumnSummary {
+	s := a.totals
+	if a.hasStats {
+		s.HasMinMax = true
+		s.Min = formatValue(a.node, a.min)
+		s.Max = formatValue(a.node, a.max)
+	}
+	return s
+}
+
+// minMax decodes a chunk's min/max. The page index is preferred: it is built
+// from page contents, while chunk statistics for byte arrays can be stale in
+// files with several row groups written by this version of parquet-go. It also
+// flags every page of optional nested columns as null, so only pages without
+// bounds are treated as null
+func minMax(node parquet.Node, index *format.ColumnIndex, stats format.Statistics) (min, max parquet.Value, ok bool) {
+	if node == nil {
+		return parquet.Value{}, parquet.Value{}, false
+	}
+	typ := node.Type()
+	kind := typ.Kind()
+
+	if index != nil && len(index.MinValues) > 0 {
+		for page := range index.MinValues {
+			if page < len(index.NullPages) && index.NullPages[page] && len(index.MinValues[page]) == 0 {
+				continue
+			}
+			pageMin, pageMax := kind.Value(index.MinValues[page]), kind.Value(index.MaxValues[page])
+			if !ok || typ.Compare(pageMin, min) < 0 {
+				min = pageMin
+			}
+			if !ok || typ.Compare(pageMax, max) > 0 {
+				max = pageMax
+			}
+			ok = true
+		}
+		if ok {
+			return min.Clone(), max.Clone(), true
+		}
+	}
+
+	minBytes, maxBytes := stats.MinValue, stats.MaxValue
+	if minBytes == nil || maxBytes == nil {
+		minBytes, maxBytes = stats.Min, stats.Max
+	}
+	if minBytes == nil || maxBytes == nil {
+		return parquet.Value{}, parquet.Value{}, false
+	}
+	return kind.Value(minBytes).Clone(), kind.Value(maxBytes).Clone(), true
+}
+
+// formatValue renders a statistic, showing timestamps as RFC 3339 times
+func formatValue(node parquet.Node, v parquet.Value) string {
+	if lt := node.Type().LogicalType(); lt != nil && lt.Timestamp != nil {
+		return timestampTime(node, v.Int64()).Format(time.RFC3339Nano)
+	}
+	return v.String()
+}
+
+// encodingNames lists chunk encodings by name
+func encodingNames(encodings []format.Encoding) []string {
+	names := make([]string, len(encodings))
+	for i, e := range encodings {
+		names[i] = e.String()
+	}
+	return names
+}
+
+// usesDictionary reports whether a chunk has a dictionary page or dictionary-encoded data
+func usesDictionary(meta format.ColumnMetaData) bool {
+	if meta.DictionaryPageOffset != 0 {
+		return true
+	}
+	for _, e := range meta.Encoding {
+		if e == format.RLEDictionary || e == format.PlainDictionary {
+			return true
+		}
+	}
+	return false
+}
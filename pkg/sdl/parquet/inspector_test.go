@@ -0,0 +1,122 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+
+	"go-transport-prac/pkg/sdl/catalog"
+)
+
+// inspectedRow exercises dictionary encoding, optional columns and timestamps
+type inspectedRow struct {
+	ID       int64     `parquet:"id"`
+	Country  string    `parquet:"country,dict"`
+	Nickname *string   `parquet:"nickname,optional"`
+	SeenAt   time.Time `parquet:"seen_at,timestamp(millisecond)"`
+}
+
+func TestFileInspector(t *testing.T) {
+	dir := "tmp/test_inspector"
+	defer os.RemoveAll(dir)
+	manager := NewSimpleManager(dir)
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := make([]inspectedRow, 250)
+	for i := range rows {
+		rows[i] = inspectedRow{
+			ID:      int64(i + 1),
+			Country: []string{"USA", "Canada", "UK"}[i%3],
+			SeenAt:  base.Add(time.Duration(i) * time.Minute),
+		}
+		if i%5 != 0 {
+			nickname := fmt.Sprintf("nick%03d", i)
+			rows[i].Nickname = &nickname
+		}
+	}
+
+	err := manager.writeFile(context.Background(), "inspected.parquet", catalog.Entry{}, func(w io.Writer) error {
+		writer := parquet.NewGenericWriter[inspectedRow](w,
+			parquet.Compression(&parquet.Snappy),
+			parquet.MaxRowsPerRowGroup(100),
+		)
+		// Flush after every 100 rows to produce three row groups
+		for start := 0; start < len(rows); start += 100 {
+			end := start + 100
+			if end > len(rows) {
+				end = len(rows)
+			}
+			if _, err := writer.Write(rows[start:end]); err != nil {
+				return err
+			}
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+		}
+		return writer.Close()
+	})
+	if err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	report, err := NewFileInspector(manager).Inspect("inspected.parquet")
+	if err != nil {
+		t.Fatalf("Failed to inspect file: %v", err)
+	}
+
+	if report.NumRows != 250 || len(report.RowGroups) != 3 {
+		t.Fatalf("Expected 250 rows in 3 row groups, got %d rows in %d", report.NumRows, len(report.RowGroups))
+	}
+	if report.RowGroups[2].NumRows != 50 {
+		t.Errorf("Expected 50 rows in the last row group, got %d", report.RowGroups[2].NumRows)
+	}
+
+	// Per-row-group statistics cover only that group
+	first := report.RowGroups[1].Columns[0]
+	if first.Path != "id" || first.Min != "101" || first.Max != "200" {
+		t.Errorf("Unexpected row group id stats: %+v", first)
+	}
+
+	id, _ := report.Column("id")
+	if id.Min != "1" || id.Max != "250" || id.Compression != "SNAPPY" || id.Type != "INT64" {
+		t.Errorf("Unexpected id summary: %+v", id)
+	}
+
+	country, _ := report.Column("country")
+	if !country.Dictionary || country.Min != "Canada" || country.Max != "USA" {
+		t.Errorf("Expected a dictionary encoded country column, got %+v", country)
+	}
+
+	nickname, _ := report.Column("nickname")
+	if nickname.NullCount != 50 || nickname.Min != "nick001" || nickname.Max != "nick249" {
+		t.Errorf("Unexpected nickname summary: %+v", nickname)
+	}
+
+	seen, _ := report.Column("seen_at")
+	if seen.Min != "2024-01-01T00:00:00Z" || seen.Max != "2024-01-01T04:09:00Z" {
+		t.Errorf("Expected timestamp statistics, got min %s max %s", seen.Min, seen.Max)
+	}
+
+	summary := Summarize(report)
+	if summary.Rows != 250 || summary.RowGroups != 3 || summary.NullValues != 50 {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+	if len(summary.DictionaryColumns) != 1 || summary.DictionaryColumns[0] != "country" {
+		t.Errorf("Expected country as the only dictionary column, got %v", summary.DictionaryColumns)
+	}
+	if summary.CompressionRatio() <= 0 {
+		t.Errorf("Expected a compression ratio, got %.2f", summary.CompressionRatio())
+	}
+
+	if _, err := NewFileInspector(manager).Inspect("missing.parquet"); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+
+	t.Logf("✓ Inspected %d row groups, %d columns (compression ratio %.2f)",
+		len(report.RowGroups), len(report.Columns), summary.CompressionRatio())
+}
@@ -0,0 +1,43 @@
+package parquet
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+
+	"go-transport-prac/pkg/metrics"
+)
+
+// WithMetrics records the latency, file size and errors of every whole-file
+// write and read through the manager, labelled with format "parquet"
+func (m *SimpleManager) WithMetrics(in *metrics.Instrumenter) *SimpleManager {
+	m.metrics = in
+	return m
+}
+
+// observe records one write or read of a file of T when metrics are enabled
+func observe[T any](m *SimpleManager, operation string, start time.Time, size int64, err error) {
+	if m.metrics == nil {
+		return
+	}
+	m.metrics.Observe("parquet", operation, recordName[T](), start, int(size), err)
+}
+
+// recordName labels the metrics of a Parquet model, e.g. "user" for User
+func recordName[T any]() string {
+	return strings.ToLower(parquet.SchemaOf(new(T)).Name())
+}
+
+// byteCounter counts the bytes of a file as they are written
+type byteCounter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,84 @@
+package parquet
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"go-transport-prac/pkg/metrics"
+)
+
+// recordingCollector keeps every observation it is given
+type recordingCollector struct {
+	mu       sync.Mutex
+	counters map[string]float64
+	observed map[string][]float64
+}
+
+func newRecordingCollector() *recordingCollector {
+	return &recordingCollector{counters: make(map[string]float64), observed: make(map[string][]float64)}
+}
+
+func observationKey(name string, tags map[string]string) string {
+	return name + "/" + tags["operation"] + "/" + tags["record"]
+}
+
+func (c *recordingCollector) Counter(name string, tags map[string]string, value float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counters[observationKey(name, tags)] += value
+}
+
+func (c *recordingCollector) Gauge(name string, tags map[string]string, value float64) {}
+
+func (c *recordingCollector) Histogram(name string, tags map[string]string, value float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.observed[observationKey(name, tags)] = append(c.observed[observationKey(name, tags)], value)
+}
+
+func (c *recordingCollector) Timer(name string, tags map[string]string, duration time.Duration) {
+	c.Histogram(name, tags, duration.Seconds())
+}
+
+func TestManagerMetrics(t *testing.T) {
+	testDir := "tmp/test_metrics"
+	defer os.RemoveAll(testDir)
+
+	collector := newRecordingCollector()
+	manager := NewSimpleManager(testDir).WithMetrics(metrics.NewInstrumenter(collector))
+
+	users := createSampleUsers(10)
+	if err := manager.WriteUsers("users.parquet", users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	if _, err := manager.ReadUsers("users.parquet"); err != nil {
+		t.Fatalf("Failed to read users: %v", err)
+	}
+	if _, err := manager.ReadOrders("missing.parquet"); err == nil {
+		t.Fatal("Expected error reading a missing file")
+	}
+
+	info, err := os.Stat(testDir + "/users.parquet")
+	if err != nil {
+		t.Fatalf("Failed to stat file: %v", err)
+	}
+
+	written := collector.observed[metrics.SerializationPayloadSize+"/serialize/user"]
+	read := collector.observed[metrics.SerializationPayloadSize+"/deserialize/user"]
+	if len(written) != 1 || written[0] != float64(info.Size()) {
+		t.Errorf("Expected one write of %d bytes, got %v", info.Size(), written)
+	}
+	if len(read) != 1 || read[0] != float64(info.Size()) {
+		t.Errorf("Expected one read of %d bytes, got %v", info.Size(), read)
+	}
+	if len(collector.observed[metrics.SerializationDuration+"/serialize/user"]) != 1 {
+		t.Error("Expected the write latency to be recorded")
+	}
+	if collector.counters[metrics.SerializationErrors+"/deserialize/order"] != 1 {
+		t.Errorf("Expected one failed order read, got %v", collector.counters)
+	}
+
+	t.Log("✓ Parquet writes and reads record latency, file size and errors")
+}
@@ -7,8 +7,8 @@ import (
 // User represents a user entity for Parquet storage
 type User struct {
 	ID        int64     `parquet:"id"`
-	Email     string    `parquet:"email"`
-	Name      string    `parquet:"name"`
+	Email     string    `parquet:"email" pii:"email"`
+	Name      string    `parquet:"name" pii:"name"`
 	Status    string    `parquet:"status"`
 	Profile   *Profile  `parquet:"profile"`
 	CreatedAt time.Time `parquet:"created_at"`
@@ -17,9 +17,9 @@ type User struct {
 
 // Profile contains user profile information
 type Profile struct {
-	FirstName string            `parquet:"first_name"`
-	LastName  string            `parquet:"last_name"`
-	Phone     string            `parquet:"phone,optional"`
+	FirstName string            `parquet:"first_name" pii:"name"`
+	LastName  string            `parquet:"last_name" pii:"name"`
+	Phone     string            `parquet:"phone,optional" pii:"phone"`
 	Address   *Address          `parquet:"address,optional"`
 	Interests []string          `parquet:"interests"`
 	Metadata  map[string]string `parquet:"metadata"`
@@ -27,10 +27,10 @@ type Profile struct {
 
 // Address represents a physical address
 type Address struct {
-	Street     string `parquet:"street"`
+	Street     string `parquet:"street" pii:"address"`
 	City       string `parquet:"city"`
 	State      string `parquet:"state"`
-	PostalCode string `parquet:"postal_code"`
+	PostalCode string `parquet:"postal_code" pii:"postal_code"`
 	Country    string `parquet:"country"`
 }
 
@@ -69,74 +69,109 @@ type Inventory struct {
 
 // Order represents an order entity for Parquet storage  
 type Order struct {
-	ID          int64        `parquet:"id,int64"`
-	UserID      int64        `parquet:"user_id,int64"`
-	OrderNumber string       `parquet:"order_number,utf8"`
-	Status      string       `parquet:"status,utf8"`
-	Items       []*OrderItem `parquet:"items,list"`
-	Summary     *OrderSummary `parquet:"summary,group"`
+	ID          int64        `parquet:"id"`
+	UserID      int64        `parquet:"user_id"`
+	OrderNumber string       `parquet:"order_number"`
+	Status      string       `parquet:"status"`
+	Items       []OrderItem  `parquet:"items,list"`
+	Summary     *OrderSummary `parquet:"summary"`
+	ShippingInfo *ShippingInfo `parquet:"shipping_info,optional"`
+	PaymentInfo  *PaymentInfo  `parquet:"payment_info,optional"`
 	CreatedAt   time.Time    `parquet:"created_at,timestamp(millisecond)"`
 	UpdatedAt   time.Time    `parquet:"updated_at,timestamp(millisecond)"`
+	ShippedAt   *time.Time   `parquet:"shipped_at,optional"`
+	DeliveredAt *time.Time   `parquet:"delivered_at,optional"`
 }
 
 // OrderItem represents an item in an order
 type OrderItem struct {
-	ProductID   int64             `parquet:"product_id,int64"`
-	ProductName string            `parquet:"product_name,utf8"`
-	ProductSKU  string            `parquet:"product_sku,utf8"`
-	Quantity    int32             `parquet:"quantity,int32"`
-	UnitPrice   *Price            `parquet:"unit_price,group"`
-	TotalPrice  *Price            `parquet:"total_price,group"`
-	Variant     map[string]string `parquet:"variant,map"`
+	ProductID   int64             `parquet:"product_id"`
+	ProductName string            `parquet:"product_name"`
+	ProductSKU  string            `parquet:"product_sku"`
+	Quantity    int32             `parquet:"quantity"`
+	UnitPrice   *Price            `parquet:"unit_price"`
+	TotalPrice  *Price            `parquet:"total_price"`
+	Variant     map[string]string `parquet:"variant"`
 }
 
 // OrderSummary contains order totals
 type OrderSummary struct {
-	Subtotal     *Price `parquet:"subtotal,group"`
-	Tax          *Price `parquet:"tax,group"`
-	ShippingCost *Price `parquet:"shipping_cost,group"`
-	Discount     *Price `parquet:"discount,group"`
-	Total        *Price `parquet:"total,group"`
-	TotalItems   int32  `parquet:"total_items,int32"`
+	Subtotal     *Price `parquet:"subtotal"`
+	Tax          *Price `parquet:"tax"`
+	ShippingCost *Price `parquet:"shipping_cost"`
+	Discount     *Price `parquet:"discount"`
+	Total        *Price `parquet:"total"`
+	TotalItems   int32  `parquet:"total_items"`
+}
+
+// ShippingInfo contains shipping details
+type ShippingInfo struct {
+	Address           *ShippingAddress `parquet:"address"`
+	Method            string           `parquet:"method"`
+	TrackingNumber    string           `parquet:"tracking_number,optional"`
+	Carrier           string           `parquet:"carrier,optional"`
+	Cost              *Price           `parquet:"cost"`
+	EstimatedDelivery *time.Time       `parquet:"estimated_delivery,optional"`
+}
+
+// ShippingAddress represents a shipping address
+type ShippingAddress struct {
+	RecipientName string `parquet:"recipient_name" pii:"name"`
+	Street        string `parquet:"street" pii:"address"`
+	City          string `parquet:"city"`
+	State         string `parquet:"state"`
+	PostalCode    string `parquet:"postal_code" pii:"postal_code"`
+	Country       string `parquet:"country"`
+}
+
+// PaymentInfo contains payment details
+type PaymentInfo struct {
+	Method        string     `parquet:"method"`
+	Status        string     `parquet:"status"`
+	TransactionID string     `parquet:"transaction_id,optional" pii:"transaction"`
+	Amount        *Price     `parquet:"amount"`
+	ProcessedAt   *time.Time `parquet:"processed_at,optional"`
 }
 
 // Analytics represents analytics data for demonstration
 type Analytics struct {
-	ID            int64             `parquet:"id,int64"`
-	EventType     string            `parquet:"event_type,utf8"`
-	UserID        int64             `parquet:"user_id,int64,optional"`
-	SessionID     string            `parquet:"session_id,utf8"`
+	ID            int64             `parquet:"id"`
+	EventType     string            `parquet:"event_type"`
+	UserID        int64             `parquet:"user_id,optional"`
+	SessionID     string            `parquet:"session_id"`
 	Timestamp     time.Time         `parquet:"timestamp,timestamp(millisecond)"`
-	Properties    map[string]string `parquet:"properties,map"`
-	Metrics       map[string]float64 `parquet:"metrics,map"`
-	DeviceInfo    *DeviceInfo       `parquet:"device_info,group,optional"`
-	Location      *Location         `parquet:"location,group,optional"`
+	Properties    map[string]string `parquet:"properties"`
+	Metrics       map[string]float64 `parquet:"metrics"`
+	DeviceInfo    *DeviceInfo       `parquet:"device_info,optional"`
+	Location      *Location         `parquet:"location,optional"`
+	// ExpiresAt marks when the event falls out of retention; nil keeps it indefinitely
+	ExpiresAt     *time.Time        `parquet:"expires_at,optional"`
 }
 
 // DeviceInfo contains device information
 type DeviceInfo struct {
-	UserAgent string `parquet:"user_agent,utf8"`
-	Platform  string `parquet:"platform,utf8"`
-	Browser   string `parquet:"browser,utf8,optional"`
-	Version   string `parquet:"version,utf8,optional"`
-	Mobile    bool   `parquet:"mobile,boolean"`
+	UserAgent string `parquet:"user_agent"`
+	Platform  string `parquet:"platform"`
+	Browser   string `parquet:"browser,optional"`
+	Version   string `parquet:"version,optional"`
+	Mobile    bool   `parquet:"mobile"`
 }
 
 // Location contains geographical information
 type Location struct {
-	Country   string  `parquet:"country,utf8"`
-	Region    string  `parquet:"region,utf8,optional"`
-	City      string  `parquet:"city,utf8,optional"`
-	Latitude  float64 `parquet:"latitude,double,optional"`
-	Longitude float64 `parquet:"longitude,double,optional"`
+	Country   string  `parquet:"country"`
+	Region    string  `parquet:"region,optional"`
+	City      string  `parquet:"city,optional"`
+	Latitude  float64 `parquet:"latitude,optional" pii:"location"`
+	Longitude float64 `parquet:"longitude,optional" pii:"location"`
 }
 
 // TimeSeriesData represents time series data for analytics
 type TimeSeriesData struct {
 	Timestamp time.Time `parquet:"timestamp,timestamp(millisecond)"`
-	MetricName string   `parquet:"metric_name,utf8"`
-	Value     float64   `parquet:"value,double"`
-	Tags      map[string]string `parquet:"tags,map"`
-	UserID    int64     `parquet:"user_id,int64,optional"`
-	SessionID string    `parquet:"session_id,utf8,optional"`
+	MetricName string   `parquet:"metric_name"`
+	Value     float64   `parquet:"value"`
+	Tags      map[string]string `parquet:"tags"`
+	UserID    int64     `parquet:"user_id,optional"`
+	SessionID string    `parquet:"session_id,optional"`
 }
\ No newline at end of file
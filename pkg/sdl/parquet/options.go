@@ -0,0 +1,202 @@
+package parquet
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/compress"
+
+	"go-transport-prac/internal/tracing"
+	"go-transport-prac/pkg/metrics"
+	"go-transport-prac/pkg/sdl/catalog"
+)
+
+// Codec names a column compression codec
+type Codec string
+
+const (
+	CodecNone   Codec = "none"
+	CodecSnappy Codec = "snappy"
+	CodecGzip   Codec = "gzip"
+	CodecZstd   Codec = "zstd"
+)
+
+// codecs maps codec names to parquet-go codecs
+var codecs = map[Codec]compress.Codec{
+	CodecNone:   &parquet.Uncompressed,
+	CodecSnappy: &parquet.Snappy,
+	CodecGzip:   &parquet.Gzip,
+	CodecZstd:   &parquet.Zstd,
+}
+
+// SortingColumn declares a column rows are sorted by, as a dotted path such as profile.address.city
+type SortingColumn struct {
+	Path       string `json:"path"`
+	Descending bool   `json:"descending,omitempty"`
+	NullsFirst bool   `json:"nullsFirst,omitempty"`
+}
+
+// WriterOptions configures how Parquet files are written. Zero values keep
+// the parquet-go defaults: no compression, 256KiB pages, data page v2 and
+// row groups bounded only by the number of rows written at once
+type WriterOptions struct {
+	Compression Codec `json:"compression,omitempty"`
+	// RowGroupSize is the maximum number of rows per row group
+	RowGroupSize int64 `json:"rowGroupSize,omitempty"`
+	// PageSize is the size in bytes of the buffer a column page is built in
+	PageSize int `json:"pageSize,omitempty"`
+	// DataPageVersion is 1 or 2. Version 1 is only for readers that predate
+	// v2 pages; the pinned parquet-go cannot read v1 pages of optional
+	// columns back when built with the purego tag
+	DataPageVersion int `json:"dataPageVersion,omitempty"`
+	// SortingColumns sorts rows before they are written, in order of
+	// precedence, and records the order in the row group metadata
+	SortingColumns []SortingColumn `json:"sortingColumns,omitempty"`
+}
+
+// Validate checks the options without applying them to a schema
+func (o WriterOptions) Validate() error {
+	if o.Compression != "" {
+		if _, ok := codecs[o.Compression]; !ok {
+			return fmt.Errorf("unsupported compression codec %q", o.Compression)
+		}
+	}
+	if o.RowGroupSize < 0 {
+		return fmt.Errorf("row group size cannot be negative")
+	}
+	if o.PageSize < 0 {
+		return fmt.Errorf("page size cannot be negative")
+	}
+	if o.DataPageVersion != 0 && o.DataPageVersion != 1 && o.DataPageVersion != 2 {
+		return fmt.Errorf("unsupported data page version %d", o.DataPageVersion)
+	}
+	for _, c := range o.SortingColumns {
+		if c.Path == "" {
+			return fmt.Errorf("sorting column path cannot be empty")
+		}
+	}
+	return nil
+}
+
+// sortingColumns converts the declared sort order to parquet-go sorting columns
+func (o WriterOptions) sortingColumns() []parquet.SortingColumn {
+	columns := make([]parquet.SortingColumn, len(o.SortingColumns))
+	for i, c := range o.SortingColumns {
+		path := strings.Split(c.Path, ".")
+		column := parquet.Ascending(path...)
+		if c.Descending {
+			column = parquet.Descending(path...)
+		}
+		if c.NullsFirst {
+			column = parquet.NullsFirst(column)
+		}
+		columns[i] = column
+	}
+	return columns
+}
+
+// writerOptions converts the options to parquet-go writer options
+func (o WriterOptions) writerOptions() ([]parquet.WriterOption, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+
+	var options []parquet.WriterOption
+	if o.Compression != "" {
+		options = append(options, parquet.Compression(codecs[o.Compression]))
+	}
+	if o.RowGroupSize > 0 {
+		options = append(options, parquet.MaxRowsPerRowGroup(o.RowGroupSize))
+	}
+	if o.PageSize > 0 {
+		options = append(options, parquet.PageBufferSize(o.PageSize))
+	}
+	if o.DataPageVersion != 0 {
+		options = append(options, parquet.DataPageVersion(o.DataPageVersion))
+	}
+	if len(o.SortingColumns) > 0 {
+		options = append(options, parquet.SortingWriterConfig(parquet.SortingColumns(o.sortingColumns()...)))
+	}
+	return options, nil
+}
+
+// schemaVersion fingerprints a Parquet schema for the catalog
+func schemaVersion(schema *parquet.Schema) string {
+	sum := sha256.Sum256([]byte(schema.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// WriteRows writes rows of any Parquet model to filename with the manager's
+// writer options. filename may include subdirectories, which are created
+func WriteRows[T any](ctx context.Context, m *SimpleManager, filename string, rows []T) error {
+	if err := writeRowsWith(ctx, m, filename, rows, m.writerOptions); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filename, err)
+	}
+	return nil
+}
+
+// writeRowsWith writes rows of any Parquet model to filename using opts
+func writeRowsWith[T any](ctx context.Context, m *SimpleManager, filename string, rows []T, opts WriterOptions) (err error) {
+	ctx, span := startSpan[T](ctx, "parquet.write", filename)
+	defer func() { tracing.End(span, len(rows), err) }()
+
+	options, err := opts.writerOptions()
+	if err != nil {
+		return fmt.Errorf("invalid writer options: %w", err)
+	}
+
+	schema := parquet.SchemaOf(new(T))
+	for _, c := range opts.SortingColumns {
+		if _, ok := schema.Lookup(strings.Split(c.Path, ".")...); !ok {
+			return fmt.Errorf("invalid writer options: sorting column %q is not a leaf column of %s", c.Path, schema.Name())
+		}
+	}
+
+	start := time.Now()
+	counter := &byteCounter{}
+	entry := catalog.Entry{
+		Entity:        recordName[T](),
+		Format:        "parquet",
+		SchemaVersion: schemaVersion(schema),
+		Records:       int64(len(rows)),
+	}
+	err = m.writeFile(ctx, filename, entry, func(w io.Writer) error {
+		counter.w = w
+		writer := parquet.NewGenericWriter[T](counter, options...)
+
+		if len(opts.SortingColumns) == 0 {
+			// Rows go in batches so cancellation is noticed between them
+			for start := 0; start < len(rows); start += DefaultBatchSize {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				end := min(start+DefaultBatchSize, len(rows))
+				if _, err := writer.Write(rows[start:end]); err != nil {
+					return fmt.Errorf("failed to write rows: %w", err)
+				}
+			}
+			return writer.Close()
+		}
+
+		// Rows are sorted in a buffer, then copied into row groups in order
+		buffer := parquet.NewRowBuffer[T](parquet.SortingRowGroupConfig(parquet.SortingColumns(opts.sortingColumns()...)))
+		if _, err := buffer.Write(rows); err != nil {
+			return fmt.Errorf("failed to buffer rows: %w", err)
+		}
+		sort.Sort(buffer)
+		if _, err := writer.WriteRowGroup(buffer); err != nil {
+			return fmt.Errorf("failed to write rows: %w", err)
+		}
+		return writer.Close()
+	})
+	observe[T](m, metrics.OperationSerialize, start, counter.n, err)
+	tracing.SetBytes(ctx, counter.n)
+	return err
+}
@@ -0,0 +1,161 @@
+package parquet
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestWriterOptions_PerManager(t *testing.T) {
+	testDir := "tmp/test_writer_options"
+	defer os.RemoveAll(testDir)
+
+	opts := WriterOptions{
+		Compression:    CodecZstd,
+		RowGroupSize:   100,
+		PageSize:       4096,
+		SortingColumns: []SortingColumn{{Path: "status"}, {Path: "id", Descending: true}},
+	}
+	manager := NewSimpleManager(testDir).WithWriterOptions(opts)
+
+	users := createVariedUsers(250)
+	if err := manager.WriteUsers("users.parquet", users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+
+	info, err := manager.GetBasicFileInfo("users.parquet")
+	if err != nil {
+		t.Fatalf("Failed to get file info: %v", err)
+	}
+	if info.Compression != CodecZstd {
+		t.Errorf("Expected zstd compression, got %s", info.Compression)
+	}
+	if info.NumRowGroups != 3 || info.NumRows != 250 {
+		t.Errorf("Expected 250 rows in 3 row groups, got %d in %d", info.NumRows, info.NumRowGroups)
+	}
+	if info.DataPageVersion != 2 {
+		t.Errorf("Expected data page v2 by default, got v%d", info.DataPageVersion)
+	}
+	if !reflect.DeepEqual(info.SortingColumns, opts.SortingColumns) {
+		t.Errorf("Expected sorting columns %v, got %v", opts.SortingColumns, info.SortingColumns)
+	}
+
+	// Rows come back in the declared order
+	read, err := manager.ReadUsers("users.parquet")
+	if err != nil {
+		t.Fatalf("Failed to read users: %v", err)
+	}
+	if len(read) != len(users) {
+		t.Fatalf("Expected %d users, got %d", len(users), len(read))
+	}
+	for i := 1; i < len(read); i++ {
+		prev, cur := read[i-1], read[i]
+		if prev.Status > cur.Status || (prev.Status == cur.Status && prev.ID < cur.ID) {
+			t.Fatalf("Rows %d and %d out of order: %s/%d then %s/%d", i-1, i, prev.Status, prev.ID, cur.Status, cur.ID)
+		}
+	}
+
+	t.Logf("✓ Wrote %d row groups with %s, %d data pages", info.NumRowGroups, info.Compression, info.DataPages)
+}
+
+func TestWriterOptions_PerCall(t *testing.T) {
+	testDir := "tmp/test_writer_options_call"
+	defer os.RemoveAll(testDir)
+	manager := NewSimpleManager(testDir).WithWriterOptions(WriterOptions{Compression: CodecSnappy})
+
+	products := make([]Product, 50)
+	for i := range products {
+		products[i] = Product{ID: int64(i + 1), Name: fmt.Sprintf("Product %d", i), SKU: fmt.Sprintf("SKU-%03d", i)}
+	}
+
+	for _, codec := range []Codec{CodecNone, CodecSnappy, CodecGzip, CodecZstd} {
+		filename := fmt.Sprintf("products_%s.parquet", codec)
+		if err := manager.WriteProductsWithOptions(filename, products, WriterOptions{Compression: codec}); err != nil {
+			t.Fatalf("Failed to write %s file: %v", codec, err)
+		}
+
+		info, err := manager.GetBasicFileInfo(filename)
+		if err != nil {
+			t.Fatalf("Failed to get file info: %v", err)
+		}
+		if info.Compression != codec {
+			t.Errorf("Expected %s compression, got %s", codec, info.Compression)
+		}
+
+		read, err := manager.ReadProducts(filename)
+		if err != nil || len(read) != len(products) || read[49].SKU != "SKU-049" {
+			t.Errorf("%s: failed to read products back: %v", codec, err)
+		}
+	}
+
+	// The manager's options still apply to calls without overrides
+	if err := manager.WriteProducts("default.parquet", products); err != nil {
+		t.Fatalf("Failed to write products: %v", err)
+	}
+	if info, _ := manager.GetBasicFileInfo("default.parquet"); info.Compression != CodecSnappy {
+		t.Errorf("Expected the manager's snappy compression, got %s", info.Compression)
+	}
+
+	t.Log("✓ Per-call options override the manager's options")
+}
+
+func TestWriterOptions_PageSettings(t *testing.T) {
+	testDir := "tmp/test_writer_options_pages"
+	defer os.RemoveAll(testDir)
+	manager := NewSimpleManager(testDir)
+	users := createSampleUsers(500)
+
+	if err := manager.WriteUsers("default.parquet", users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	if err := manager.WriteUsersWithOptions("small_pages.parquet", users, WriterOptions{PageSize: 1024}); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	if err := manager.WriteUsersWithOptions("v1.parquet", users, WriterOptions{DataPageVersion: 1}); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+
+	defaults, _ := manager.GetBasicFileInfo("default.parquet")
+	small, _ := manager.GetBasicFileInfo("small_pages.parquet")
+	v1, _ := manager.GetBasicFileInfo("v1.parquet")
+
+	if defaults.Compression != CodecNone || defaults.DataPageVersion != 2 || defaults.NumRowGroups != 1 {
+		t.Errorf("Unexpected defaults: %s, v%d, %d row groups", defaults.Compression, defaults.DataPageVersion, defaults.NumRowGroups)
+	}
+	if small.DataPages <= defaults.DataPages {
+		t.Errorf("Expected smaller pages to produce more pages: %d vs %d", small.DataPages, defaults.DataPages)
+	}
+	if v1.DataPageVersion != 1 {
+		t.Errorf("Expected data page v1, got v%d", v1.DataPageVersion)
+	}
+
+	t.Logf("✓ %d data pages with 1KiB pages, %d with defaults", small.DataPages, defaults.DataPages)
+}
+
+func TestWriterOptions_Invalid(t *testing.T) {
+	testDir := "tmp/test_writer_options_invalid"
+	defer os.RemoveAll(testDir)
+	manager := NewSimpleManager(testDir)
+	users := createSampleUsers(3)
+
+	tests := map[string]WriterOptions{
+		"unknown codec":       {Compression: "lz4"},
+		"negative row groups": {RowGroupSize: -1},
+		"page version":        {DataPageVersion: 3},
+		"empty sort path":     {SortingColumns: []SortingColumn{{}}},
+		"unknown sort column": {SortingColumns: []SortingColumn{{Path: "profile.nickname"}}},
+		"sort by group":       {SortingColumns: []SortingColumn{{Path: "profile"}}},
+	}
+
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := manager.WriteUsersWithOptions("invalid.parquet", users, opts); err == nil {
+				t.Error("Expected an error")
+			}
+			if _, err := os.Stat(testDir + "/invalid.parquet"); !os.IsNotExist(err) {
+				t.Error("Expected no file to be created")
+			}
+		})
+	}
+}
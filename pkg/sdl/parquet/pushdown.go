@@ -0,0 +1,480 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/format"
+)
+
+// Predicate compares a non-repeated leaf column, addressed by dotted path,
+// against a value. Null values never match
+type Predicate struct {
+	Column string
+	Op     CompareOp
+	// Value is an int, int32, int64, float32, float64, string, []byte, bool
+	// or, for timestamp columns, a time.Time
+	Value interface{}
+}
+
+// Filter is a conjunction of predicates: a row matches when every predicate does
+type Filter []Predicate
+
+// Where starts a filter, e.g. Where("profile.address.country", OpEqual, "USA")
+func Where(column string, op CompareOp, value interface{}) Filter {
+	return Filter{{Column: column, Op: op, Value: value}}
+}
+
+// And returns the filter with another predicate added
+func (f Filter) And(column string, op CompareOp, value interface{}) Filter {
+	return append(f[:len(f):len(f)], Predicate{Column: column, Op: op, Value: value})
+}
+
+// ScanStats reports how much of a file a filtered read had to decode
+type ScanStats struct {
+	RowGroups        int
+	RowGroupsSkipped int
+	// RowsScanned counts the rows of row groups that could not be skipped
+	RowsScanned int64
+	RowsMatched int
+}
+
+// ReadUsersWhere reads the users matching filter. Row groups whose column
+// statistics rule the filter out are skipped, and only the filter columns
+// are decoded for the rest; full rows are decoded for matches only
+func (m *SimpleManager) ReadUsersWhere(filename string, filter Filter) ([]User, error) {
+	users, _, err := m.ScanUsers(filename, filter)
+	return users, err
+}
+
+// ReadUsersColumns reads only the given columns into otherwise empty users.
+// Columns are the non-repeated leaves of User, such as "id", "status" or
+// "profile.address.country"; profile and address are set when one of their
+// columns is non-null
+func (m *SimpleManager) ReadUsersColumns(filename string, columns ...string) ([]User, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("at least one column is required")
+	}
+	users, _, err := m.ScanUsers(filename, nil, columns...)
+	return users, err
+}
+
+// ScanUsers combines ReadUsersWhere and ReadUsersColumns: it returns the users
+// matching filter with only columns set, or whole users when no columns are
+// given, and reports how many row groups the statistics let it skip
+func (m *SimpleManager) ScanUsers(filename string, filter Filter, columns ...string) ([]User, ScanStats, error) {
+	file, size, err := m.openFile(context.Background(), filename)
+	if err != nil {
+		return nil, ScanStats{}, err
+	}
+	defer file.Close()
+
+	pf, err := parquet.OpenFile(file, size)
+	if err != nil {
+		return nil, ScanStats{}, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+
+	predicates := make([]resolvedPredicate, len(filter))
+	for i, p := range filter {
+		if predicates[i], err = resolvePredicate(pf, p); err != nil {
+			return nil, ScanStats{}, err
+		}
+	}
+	projection := make([]projectedColumn, len(columns))
+	for i, path := range columns {
+		if projection[i], err = resolveProjection(pf, path); err != nil {
+			return nil, ScanStats{}, err
+		}
+	}
+
+	metadata := pf.Metadata()
+	indexes := pf.ColumnIndexes()
+	stats := ScanStats{RowGroups: len(pf.RowGroups())}
+	var users []User
+
+	for i, rg := range pf.RowGroups() {
+		if !mayMatch(predicates, metadata.RowGroups[i], i, indexes) {
+			stats.RowGroupsSkipped++
+			continue
+		}
+		stats.RowsScanned += rg.NumRows()
+
+		rows := SelectAll(int(rg.NumRows()))
+		for _, p := range predicates {
+			values, err := chunkValues(rg.ColumnChunks()[p.leaf.Index()], p.path)
+			if err != nil {
+				return nil, ScanStats{}, err
+			}
+			rows = p.filter(values, rows)
+			if len(rows) == 0 {
+				break
+			}
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		var matched []User
+		if len(projection) == 0 {
+			matched, err = readRowGroupUsers(rg, rows)
+		} else {
+			matched, err = projectUsers(rg, projection, rows)
+		}
+		if err != nil {
+			return nil, ScanStats{}, err
+		}
+		users = append(users, matched...)
+	}
+
+	stats.RowsMatched = len(users)
+	return users, stats, nil
+}
+
+// resolvedPredicate is a predicate bound to its column and converted value
+type resolvedPredicate struct {
+	path  string
+	op    CompareOp
+	leaf  *parquet.Column
+	value parquet.Value
+}
+
+func resolvePredicate(pf *parquet.File, p Predicate) (resolvedPredicate, error) {
+	leaf, err := leafColumn(pf, p.Column)
+	if err != nil {
+		return resolvedPredicate{}, err
+	}
+	if p.Op < OpEqual || p.Op > OpGreaterOrEqual {
+		return resolvedPredicate{}, fmt.Errorf("unsupported comparison %d on column %s", p.Op, p.Column)
+	}
+	value, err := columnValue(leaf, p.Value)
+	if err != nil {
+		return resolvedPredicate{}, fmt.Errorf("invalid value for column %s: %w", p.Column, err)
+	}
+	return resolvedPredicate{path: p.Column, op: p.Op, leaf: leaf, value: value}, nil
+}
+
+// filter narrows rows to those whose value matches
+func (p resolvedPredicate) filter(values []parquet.Value, rows Selection) Selection {
+	compare := p.leaf.Type().Compare
+	out := rows[:0]
+	for _, r := range rows {
+		if v := values[r]; !v.IsNull() && compareMatches(p.op, compare(v, p.value)) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// mayMatch reports whether a row group can hold matching rows, judging by the
+// min/max of each predicate column. Chunks without statistics always may
+func mayMatch(predicates []resolvedPredicate, rg format.RowGroup, index int, indexes []format.ColumnIndex) bool {
+	for _, p := range predicates {
+		j := p.leaf.Index()
+		var columnIndex *format.ColumnIndex
+		if k := index*len(rg.Columns) + j; k < len(indexes) {
+			columnIndex = &indexes[k]
+		}
+		min, max, ok := minMax(p.leaf, columnIndex, rg.Columns[j].MetaData.Statistics)
+		if !ok {
+			continue
+		}
+
+		compare := p.leaf.Type().Compare
+		lo, hi := compare(min, p.value), compare(max, p.value)
+		var possible bool
+		switch p.op {
+		case OpEqual:
+			possible = lo <= 0 && hi >= 0
+		case OpNotEqual:
+			possible = lo != 0 || hi != 0
+		case OpLess:
+			possible = lo < 0
+		case OpLessOrEqual:
+			possible = lo <= 0
+		case OpGreater:
+			possible = hi > 0
+		case OpGreaterOrEqual:
+			possible = hi >= 0
+		}
+		if !possible {
+			return false
+		}
+	}
+	return true
+}
+
+// compareMatches reports whether a comparison result satisfies op
+func compareMatches(op CompareOp, cmp int) bool {
+	switch op {
+	case OpEqual:
+		return cmp == 0
+	case OpNotEqual:
+		return cmp != 0
+	case OpLess:
+		return cmp < 0
+	case OpLessOrEqual:
+		return cmp <= 0
+	case OpGreater:
+		return cmp > 0
+	case OpGreaterOrEqual:
+		return cmp >= 0
+	}
+	return false
+}
+
+// columnValue converts a Go value to a value of the column's physical type
+func columnValue(leaf *parquet.Column, v interface{}) (parquet.Value, error) {
+	if t, ok := v.(time.Time); ok {
+		n, ok := timestampUnits(leaf, t)
+		if !ok {
+			return parquet.Value{}, fmt.Errorf("column is not a timestamp")
+		}
+		v = n
+	}
+
+	switch kind := leaf.Type().Kind(); kind {
+	case parquet.Int32, parquet.Int64:
+		var n int64
+		switch x := v.(type) {
+		case int:
+			n = int64(x)
+		case int32:
+			n = int64(x)
+		case int64:
+			n = x
+		default:
+			return parquet.Value{}, fmt.Errorf("expected an integer, got %T", v)
+		}
+		if kind == parquet.Int32 {
+			return parquet.Int32Value(int32(n)), nil
+		}
+		return parquet.Int64Value(n), nil
+	case parquet.Float, parquet.Double:
+		var f float64
+		switch x := v.(type) {
+		case float32:
+			f = float64(x)
+		case float64:
+			f = x
+		case int:
+			f = float64(x)
+		default:
+			return parquet.Value{}, fmt.Errorf("expected a number, got %T", v)
+		}
+		if kind == parquet.Float {
+			return parquet.FloatValue(float32(f)), nil
+		}
+		return parquet.DoubleValue(f), nil
+	case parquet.ByteArray:
+		switch x := v.(type) {
+		case string:
+			return parquet.ByteArrayValue([]byte(x)), nil
+		case []byte:
+			return parquet.ByteArrayValue(x), nil
+		}
+		return parquet.Value{}, fmt.Errorf("expected a string, got %T", v)
+	case parquet.Boolean:
+		if b, ok := v.(bool); ok {
+			return parquet.BooleanValue(b), nil
+		}
+		return parquet.Value{}, fmt.Errorf("expected a bool, got %T", v)
+	default:
+		return parquet.Value{}, fmt.Errorf("unsupported column type %s", leaf.Type())
+	}
+}
+
+// timestampUnits converts t to the unit of a timestamp column
+func timestampUnits(node parquet.Node, t time.Time) (int64, bool) {
+	lt := node.Type().LogicalType()
+	if lt == nil || lt.Timestamp == nil {
+		return 0, false
+	}
+	switch {
+	case lt.Timestamp.Unit.Millis != nil:
+		return t.UnixMilli(), true
+	case lt.Timestamp.Unit.Micros != nil:
+		return t.UnixMicro(), true
+	default:
+		return t.UnixNano(), true
+	}
+}
+
+// timestampTime converts a timestamp column value to a time
+func timestampTime(node parquet.Node, n int64) time.Time {
+	lt := node.Type().LogicalType()
+	switch {
+	case lt.Timestamp.Unit.Millis != nil:
+		return time.UnixMilli(n).UTC()
+	case lt.Timestamp.Unit.Micros != nil:
+		return time.UnixMicro(n).UTC()
+	default:
+		return time.Unix(0, n).UTC()
+	}
+}
+
+// projectedColumn is a User column bound to the setter that fills it in
+type projectedColumn struct {
+	path string
+	leaf *parquet.Column
+	set  func(u *User, v parquet.Value)
+}
+
+// userSetters assign a non-null column value to the matching User field
+var userSetters = map[string]func(u *User, v parquet.Value){
+	"id":                          func(u *User, v parquet.Value) { u.ID = v.Int64() },
+	"email":                       func(u *User, v parquet.Value) { u.Email = string(v.ByteArray()) },
+	"name":                        func(u *User, v parquet.Value) { u.Name = string(v.ByteArray()) },
+	"status":                      func(u *User, v parquet.Value) { u.Status = string(v.ByteArray()) },
+	"profile.first_name":          func(u *User, v parquet.Value) { profileOf(u).FirstName = string(v.ByteArray()) },
+	"profile.last_name":           func(u *User, v parquet.Value) { profileOf(u).LastName = string(v.ByteArray()) },
+	"profile.phone":               func(u *User, v parquet.Value) { profileOf(u).Phone = string(v.ByteArray()) },
+	"profile.address.street":      func(u *User, v parquet.Value) { addressOf(u).Street = string(v.ByteArray()) },
+	"profile.address.city":        func(u *User, v parquet.Value) { addressOf(u).City = string(v.ByteArray()) },
+	"profile.address.state":       func(u *User, v parquet.Value) { addressOf(u).State = string(v.ByteArray()) },
+	"profile.address.postal_code": func(u *User, v parquet.Value) { addressOf(u).PostalCode = string(v.ByteArray()) },
+	"profile.address.country":     func(u *User, v parquet.Value) { addressOf(u).Country = string(v.ByteArray()) },
+}
+
+func resolveProjection(pf *parquet.File, path string) (projectedColumn, error) {
+	leaf, err := leafColumn(pf, path)
+	if err != nil {
+		return projectedColumn{}, err
+	}
+
+	set, ok := userSetters[path]
+	switch {
+	case ok:
+	case path == "created_at" || path == "updated_at":
+		set = func(u *User, v parquet.Value) {
+			t := timestampTime(leaf, v.Int64())
+			if path == "created_at" {
+				u.CreatedAt = t
+			} else {
+				u.UpdatedAt = t
+			}
+		}
+	default:
+		return projectedColumn{}, fmt.Errorf("column %s cannot be projected onto User", path)
+	}
+	return projectedColumn{path: path, leaf: leaf, set: set}, nil
+}
+
+func profileOf(u *User) *Profile {
+	if u.Profile == nil {
+		u.Profile = &Profile{}
+	}
+	return u.Profile
+}
+
+func addressOf(u *User) *Address {
+	p := profileOf(u)
+	if p.Address == nil {
+		p.Address = &Address{}
+	}
+	return p.Address
+}
+
+// projectUsers builds users holding only the projected columns of the selected rows
+func projectUsers(rg parquet.RowGroup, projection []projectedColumn, rows Selection) ([]User, error) {
+	users := make([]User, len(rows))
+	for _, col := range projection {
+		values, err := chunkValues(rg.ColumnChunks()[col.leaf.Index()], col.path)
+		if err != nil {
+			return nil, err
+		}
+		for i, r := range rows {
+			if v := values[r]; !v.IsNull() {
+				col.set(&users[i], v)
+			}
+		}
+	}
+	return users, nil
+}
+
+// readRowGroupUsers decodes whole users for the selected rows, seeking over
+// each run of consecutive rows
+func readRowGroupUsers(rg parquet.RowGroup, rows Selection) ([]User, error) {
+	reader := parquet.NewGenericRowGroupReader[User](rg)
+	defer reader.Close()
+
+	users := make([]User, len(rows))
+	for start := 0; start < len(rows); {
+		end := start + 1
+		for end < len(rows) && rows[end] == rows[end-1]+1 {
+			end++
+		}
+		if err := reader.SeekToRow(int64(rows[start])); err != nil {
+			return nil, fmt.Errorf("failed to seek to row %d: %w", rows[start], err)
+		}
+		for read := start; read < end; {
+			n, err := reader.Read(users[read:end])
+			read += n
+			if err == io.EOF && read < end {
+				return nil, fmt.Errorf("row group ended before row %d", rows[read])
+			}
+			if err != nil && err != io.EOF {
+				return nil, fmt.Errorf("failed to read users: %w", err)
+			}
+		}
+		start = end
+	}
+	return users, nil
+}
+
+// leafColumn resolves a dotted path to a non-repeated leaf column
+func leafColumn(pf *parquet.File, path string) (*parquet.Column, error) {
+	leaf := pf.Root()
+	for _, name := range strings.Split(path, ".") {
+		if leaf = leaf.Column(name); leaf == nil {
+			return nil, fmt.Errorf("column %s not found", path)
+		}
+	}
+	if !leaf.Leaf() || leaf.MaxRepetitionLevel() > 0 {
+		return nil, fmt.Errorf("column %s is not a non-repeated leaf column", path)
+	}
+	return leaf, nil
+}
+
+// chunkValues decodes a column chunk of a non-repeated leaf, one value per row
+func chunkValues(chunk parquet.ColumnChunk, path string) ([]parquet.Value, error) {
+	values := make([]parquet.Value, 0, chunk.NumValues())
+	pages := chunk.Pages()
+	defer pages.Close()
+
+	err := readValues(pages, path, func(v parquet.Value) { values = append(values, v.Clone()) })
+	return values, err
+}
+
+// readValues calls fn with every value of every page
+func readValues(pages parquet.Pages, path string, fn func(parquet.Value)) error {
+	buf := make([]parquet.Value, 1024)
+	for {
+		page, err := pages.ReadPage()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read page of column %s: %w", path, err)
+		}
+
+		values := page.Values()
+		for {
+			n, err := values.ReadValues(buf)
+			for _, v := range buf[:n] {
+				fn(v)
+			}
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				parquet.Release(page)
+				return fmt.Errorf("failed to read values of column %s: %w", path, err)
+			}
+		}
+		parquet.Release(page)
+	}
+}
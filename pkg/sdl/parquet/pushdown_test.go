@@ -0,0 +1,165 @@
+package parquet
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// writePushdownUsers writes varied users sorted by country in row groups of 100,
+// so each row group covers a narrow country range
+func writePushdownUsers(t *testing.T, manager *SimpleManager, filename string) []User {
+	t.Helper()
+
+	users := createVariedUsers(1000)
+	for i := range users {
+		users[i].CreatedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(i) * time.Hour)
+	}
+	opts := WriterOptions{
+		RowGroupSize:   100,
+		SortingColumns: []SortingColumn{{Path: "profile.address.country"}},
+	}
+	if err := manager.WriteUsersWithOptions(filename, users, opts); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+
+	// Read back in file order, which the sorting changed
+	written, err := manager.ReadUsers(filename)
+	if err != nil {
+		t.Fatalf("Failed to read users: %v", err)
+	}
+	return written
+}
+
+func TestReadUsersWhere(t *testing.T) {
+	testDir := "tmp/test_pushdown_where"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+	users := writePushdownUsers(t, manager, "users.parquet")
+
+	var want []User
+	for _, u := range users {
+		if u.Profile.Address != nil && u.Profile.Address.Country == "USA" {
+			want = append(want, u)
+		}
+	}
+
+	got, stats, err := manager.ScanUsers("users.parquet", Where("profile.address.country", OpEqual, "USA"))
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(got) != len(want) || stats.RowsMatched != len(want) {
+		t.Fatalf("Expected %d USA users, got %d (%+v)", len(want), len(got), stats)
+	}
+	for i := range got {
+		if got[i].ID != want[i].ID || got[i].Email != want[i].Email || got[i].Profile.Address.Country != "USA" {
+			t.Fatalf("User %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+	if stats.RowGroups != 10 || stats.RowGroupsSkipped < 5 || stats.RowsScanned >= int64(len(users)) {
+		t.Errorf("Expected the country statistics to skip most row groups, got %+v", stats)
+	}
+
+	// Predicates combine, and timestamps compare as times
+	cutoff := time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)
+	filter := Where("profile.address.country", OpEqual, "Japan").And("created_at", OpLess, cutoff)
+	got, err = manager.ReadUsersWhere("users.parquet", filter)
+	if err != nil {
+		t.Fatalf("Combined filter failed: %v", err)
+	}
+	count := 0
+	for _, u := range users {
+		if u.Profile.Address != nil && u.Profile.Address.Country == "Japan" && u.CreatedAt.Before(cutoff) {
+			count++
+		}
+	}
+	if len(got) != count || count == 0 {
+		t.Errorf("Expected %d Japanese users created before %s, got %d", count, cutoff, len(got))
+	}
+
+	// A value outside every row group's range skips the whole file
+	_, stats, err = manager.ScanUsers("users.parquet", Where("id", OpGreater, 5000))
+	if err != nil || stats.RowGroupsSkipped != stats.RowGroups || stats.RowsMatched != 0 {
+		t.Errorf("Expected every row group skipped, got %+v (%v)", stats, err)
+	}
+
+	t.Logf("✓ Filtered %d USA users, skipping row groups by statistics", len(want))
+}
+
+func TestReadUsersColumns(t *testing.T) {
+	testDir := "tmp/test_pushdown_columns"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+	users := writePushdownUsers(t, manager, "users.parquet")
+
+	got, err := manager.ReadUsersColumns("users.parquet", "id", "status", "profile.address.country", "created_at")
+	if err != nil {
+		t.Fatalf("Projection failed: %v", err)
+	}
+	if len(got) != len(users) {
+		t.Fatalf("Expected %d users, got %d", len(users), len(got))
+	}
+	for i, u := range got {
+		want := users[i]
+		if u.ID != want.ID || u.Status != want.Status || !u.CreatedAt.Equal(want.CreatedAt) {
+			t.Fatalf("User %d: expected %d/%s/%s, got %d/%s/%s", i, want.ID, want.Status, want.CreatedAt, u.ID, u.Status, u.CreatedAt)
+		}
+		if u.Email != "" || u.Name != "" {
+			t.Fatalf("User %d: expected unprojected columns to stay empty, got %+v", i, u)
+		}
+		if want.Profile.Address == nil {
+			if u.Profile != nil {
+				t.Fatalf("User %d: expected no profile without an address, got %+v", i, u.Profile)
+			}
+			continue
+		}
+		if u.Profile.Address.Country != want.Profile.Address.Country || u.Profile.Address.City != "" {
+			t.Fatalf("User %d: unexpected address %+v", i, u.Profile.Address)
+		}
+	}
+
+	// Filters and projections combine
+	got, _, err = manager.ScanUsers("users.parquet", Where("status", OpEqual, "deleted"), "id")
+	if err != nil || len(got) != len(users)/4 {
+		t.Fatalf("Expected %d deleted users, got %d (%v)", len(users)/4, len(got), err)
+	}
+
+	t.Log("✓ Projected reads decode only the requested columns")
+}
+
+func TestPushdownErrors(t *testing.T) {
+	testDir := "tmp/test_pushdown_errors"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+	writePushdownUsers(t, manager, "users.parquet")
+
+	cases := map[string]func() error{
+		"unknown column": func() error {
+			_, err := manager.ReadUsersWhere("users.parquet", Where("missing", OpEqual, "x"))
+			return err
+		},
+		"repeated column": func() error {
+			_, err := manager.ReadUsersColumns("users.parquet", "profile.interests")
+			return err
+		},
+		"value type": func() error {
+			_, err := manager.ReadUsersWhere("users.parquet", Where("id", OpEqual, "one"))
+			return err
+		},
+		"not a timestamp": func() error {
+			_, err := manager.ReadUsersWhere("users.parquet", Where("id", OpLess, time.Now()))
+			return err
+		},
+		"no columns": func() error {
+			_, err := manager.ReadUsersColumns("users.parquet")
+			return err
+		},
+	}
+	for name, run := range cases {
+		if err := run(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	t.Log("✓ Invalid filters and projections are rejected")
+}
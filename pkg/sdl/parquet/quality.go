@@ -0,0 +1,391 @@
+package parquet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RuleType names the check a quality rule performs
+type RuleType string
+
+const (
+	// RulePresent passes when the field is set: non-empty, non-zero or a non-zero time
+	RulePresent RuleType = "present"
+	// RuleRegex passes when the field matches Pattern
+	RuleRegex RuleType = "regex"
+	// RuleRange passes when the numeric value of the field lies within Min and Max.
+	// Times are compared as Unix seconds, lists by their length and strings are parsed
+	RuleRange RuleType = "range"
+	// RuleCompare passes when the field compares to the Other field with Op
+	RuleCompare RuleType = "compare"
+)
+
+// Comparison operators of compare rules
+var compareOps = map[string]func(c int) bool{
+	"eq": func(c int) bool { return c == 0 },
+	"ne": func(c int) bool { return c != 0 },
+	"lt": func(c int) bool { return c < 0 },
+	"le": func(c int) bool { return c <= 0 },
+	"gt": func(c int) bool { return c > 0 },
+	"ge": func(c int) bool { return c >= 0 },
+}
+
+// QualityRule is one weighted check of a user. Fields are column paths such
+// as email, profile.address.country or profile.metadata.source
+type QualityRule struct {
+	Name   string   `json:"name" yaml:"name"`
+	Type   RuleType `json:"type" yaml:"type"`
+	Field  string   `json:"field" yaml:"field"`
+	Weight float64  `json:"weight" yaml:"weight"`
+	// Pattern is the regular expression of a regex rule
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// Min and Max bound a range rule; either may be left out
+	Min *float64 `json:"min,omitempty" yaml:"min,omitempty"`
+	Max *float64 `json:"max,omitempty" yaml:"max,omitempty"`
+	// Op (eq, ne, lt, le, gt, ge) compares Field to Other in a compare rule
+	Op    string `json:"op,omitempty" yaml:"op,omitempty"`
+	Other string `json:"other,omitempty" yaml:"other,omitempty"`
+}
+
+// QualityRules is a rule set with the thresholds verification applies. A
+// record's score is the weight of the rules it passes over the total weight
+type QualityRules struct {
+	Rules []QualityRule `json:"rules" yaml:"rules"`
+	// LowQualityBelow is the score under which a record counts as low quality
+	LowQualityBelow float64 `json:"lowQualityBelow" yaml:"lowQualityBelow"`
+	// MinAverage is the lowest average score verification accepts
+	MinAverage float64 `json:"minAverage" yaml:"minAverage"`
+	// MaxLowQualityRatio is the largest share of low quality records verification accepts
+	MaxLowQualityRatio float64 `json:"maxLowQualityRatio" yaml:"maxLowQualityRatio"`
+}
+
+// DefaultQualityRules scores the completeness of a user out of 10: 2 each
+// for an ID and email, 1 each for a name, known status, first and last name,
+// phone and country
+func DefaultQualityRules() QualityRules {
+	return QualityRules{
+		Rules: []QualityRule{
+			{Name: "has_id", Type: RulePresent, Field: "id", Weight: 2},
+			{Name: "has_email", Type: RulePresent, Field: "email", Weight: 2},
+			{Name: "has_name", Type: RulePresent, Field: "name", Weight: 1},
+			{Name: "known_status", Type: RuleRegex, Field: "status", Pattern: "^(active|inactive|suspended)$", Weight: 1},
+			{Name: "has_first_name", Type: RulePresent, Field: "profile.first_name", Weight: 1},
+			{Name: "has_last_name", Type: RulePresent, Field: "profile.last_name", Weight: 1},
+			{Name: "has_phone", Type: RulePresent, Field: "profile.phone", Weight: 1},
+			{Name: "has_country", Type: RulePresent, Field: "profile.address.country", Weight: 1},
+		},
+		LowQualityBelow:    0.7,
+		MinAverage:         0.7,
+		MaxLowQualityRatio: 1,
+	}
+}
+
+// LoadQualityRules reads rules from a .json, .yaml or .yml file. Thresholds
+// the file leaves out keep their DefaultQualityRules values, as do the rules
+// when the file has none. Rules the file lists are taken as written
+func LoadQualityRules(path string) (QualityRules, error) {
+	defaults := DefaultQualityRules()
+	// Decode into an empty rule list so listed rules don't inherit the
+	// fields of the default rule at the same index
+	rules := defaults
+	rules.Rules = nil
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rules, fmt.Errorf("failed to read quality rules: %w", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.Unmarshal(data, &rules)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &rules)
+	default:
+		return rules, fmt.Errorf("unsupported quality rules format %q", ext)
+	}
+	if err != nil {
+		return rules, fmt.Errorf("failed to parse quality rules: %w", err)
+	}
+	if rules.Rules == nil {
+		rules.Rules = defaults.Rules
+	}
+	return rules, nil
+}
+
+// RecordQuality is the score of one record and the rules it failed
+type RecordQuality struct {
+	ID          int64    `json:"id"`
+	Score       float64  `json:"score"`
+	FailedRules []string `json:"failedRules,omitempty"`
+}
+
+// QualityEngine scores users against a validated rule set
+type QualityEngine struct {
+	rules    QualityRules
+	compiled []compiledRule
+	total    float64
+}
+
+type compiledRule struct {
+	QualityRule
+	field   fieldResolver
+	other   fieldResolver
+	pattern *regexp.Regexp
+	compare func(c int) bool
+}
+
+// NewQualityEngine validates rules and compiles their patterns
+func NewQualityEngine(rules QualityRules) (*QualityEngine, error) {
+	if len(rules.Rules) == 0 {
+		return nil, fmt.Errorf("quality rules cannot be empty")
+	}
+	for name, v := range map[string]float64{
+		"lowQualityBelow":    rules.LowQualityBelow,
+		"minAverage":         rules.MinAverage,
+		"maxLowQualityRatio": rules.MaxLowQualityRatio,
+	} {
+		if v < 0 || v > 1 {
+			return nil, fmt.Errorf("%s must be between 0 and 1, got %v", name, v)
+		}
+	}
+
+	engine := &QualityEngine{rules: rules}
+	names := make(map[string]bool)
+	for _, rule := range rules.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("quality rule on %s has no name", rule.Field)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("duplicate quality rule %s", rule.Name)
+		}
+		names[rule.Name] = true
+
+		compiled, err := compileRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quality rule %s: %w", rule.Name, err)
+		}
+		engine.compiled = append(engine.compiled, compiled)
+		engine.total += rule.Weight
+	}
+	return engine, nil
+}
+
+// defaultQualityEngine scores with DefaultQualityRules
+var defaultQualityEngine = func() *QualityEngine {
+	engine, err := NewQualityEngine(DefaultQualityRules())
+	if err != nil {
+		panic(err)
+	}
+	return engine
+}()
+
+func compileRule(rule QualityRule) (compiledRule, error) {
+	compiled := compiledRule{QualityRule: rule}
+	if rule.Weight <= 0 {
+		return compiled, fmt.Errorf("weight must be positive")
+	}
+	field, err := resolveField(rule.Field)
+	if err != nil {
+		return compiled, err
+	}
+	compiled.field = field
+
+	switch rule.Type {
+	case RulePresent:
+	case RuleRegex:
+		if compiled.pattern, err = regexp.Compile(rule.Pattern); err != nil {
+			return compiled, fmt.Errorf("invalid pattern: %w", err)
+		}
+	case RuleRange:
+		if rule.Min == nil && rule.Max == nil {
+			return compiled, fmt.Errorf("range needs a min or max")
+		}
+		if rule.Min != nil && rule.Max != nil && *rule.Min > *rule.Max {
+			return compiled, fmt.Errorf("min %v is greater than max %v", *rule.Min, *rule.Max)
+		}
+	case RuleCompare:
+		var ok bool
+		if compiled.compare, ok = compareOps[rule.Op]; !ok {
+			return compiled, fmt.Errorf("unsupported comparison %q", rule.Op)
+		}
+		if compiled.other, err = resolveField(rule.Other); err != nil {
+			return compiled, err
+		}
+	default:
+		return compiled, fmt.Errorf("unsupported rule type %q", rule.Type)
+	}
+	return compiled, nil
+}
+
+// Rules returns the rule set the engine was created with
+func (e *QualityEngine) Rules() QualityRules {
+	return e.rules
+}
+
+// Fields returns the fields the rules read, in rule order without repeats
+func (e *QualityEngine) Fields() []string {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, rule := range e.compiled {
+		for _, field := range []string{rule.Field, rule.Other} {
+			if field != "" && !seen[field] {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields
+}
+
+// Score evaluates every rule against user
+func (e *QualityEngine) Score(user User) RecordQuality {
+	result := RecordQuality{ID: user.ID}
+	passed := 0.0
+	for _, rule := range e.compiled {
+		if rule.passes(user) {
+			passed += rule.Weight
+		} else {
+			result.FailedRules = append(result.FailedRules, rule.Name)
+		}
+	}
+	result.Score = passed / e.total
+	return result
+}
+
+// LowQuality reports whether a score is under the low quality threshold
+func (e *QualityEngine) LowQuality(score float64) bool {
+	return score < e.rules.LowQualityBelow
+}
+
+func (r compiledRule) passes(user User) bool {
+	value := r.field(user)
+	switch r.Type {
+	case RulePresent:
+		return value.present
+	case RuleRegex:
+		return value.present && r.pattern.MatchString(value.text)
+	case RuleRange:
+		n, ok := value.number()
+		return ok && (r.Min == nil || n >= *r.Min) && (r.Max == nil || n <= *r.Max)
+	case RuleCompare:
+		other := r.other(user)
+		if !value.present || !other.present {
+			return false
+		}
+		return r.compare(value.compare(other))
+	}
+	return false
+}
+
+// fieldValue is a user field as the rules see it
+type fieldValue struct {
+	present bool
+	text    string
+	num     float64
+	numeric bool
+}
+
+func textValue(s string) fieldValue {
+	return fieldValue{present: s != "", text: s}
+}
+
+func numberValue(n float64, present bool) fieldValue {
+	return fieldValue{present: present, text: strconv.FormatFloat(n, 'f', -1, 64), num: n, numeric: true}
+}
+
+func timeValue(t time.Time) fieldValue {
+	v := numberValue(float64(t.Unix()), !t.IsZero())
+	v.text = t.Format(time.RFC3339)
+	return v
+}
+
+// number returns the numeric value of a numeric field or a parsable string
+func (v fieldValue) number() (float64, bool) {
+	if !v.present {
+		return 0, false
+	}
+	if v.numeric {
+		return v.num, true
+	}
+	n, err := strconv.ParseFloat(v.text, 64)
+	return n, err == nil
+}
+
+// compare orders two values numerically when both are numbers, otherwise as text
+func (v fieldValue) compare(other fieldValue) int {
+	a, aok := v.number()
+	b, bok := other.number()
+	if aok && bok {
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(v.text, other.text)
+}
+
+// fieldResolver reads one field of a user
+type fieldResolver func(u User) fieldValue
+
+// userFields resolve the column paths rules may refer to
+var userFields = map[string]fieldResolver{
+	"id":                 func(u User) fieldValue { return numberValue(float64(u.ID), u.ID != 0) },
+	"email":              func(u User) fieldValue { return textValue(u.Email) },
+	"name":               func(u User) fieldValue { return textValue(u.Name) },
+	"status":             func(u User) fieldValue { return textValue(u.Status) },
+	"created_at":         func(u User) fieldValue { return timeValue(u.CreatedAt) },
+	"updated_at":         func(u User) fieldValue { return timeValue(u.UpdatedAt) },
+	"profile.first_name": profileField(func(p *Profile) fieldValue { return textValue(p.FirstName) }),
+	"profile.last_name":  profileField(func(p *Profile) fieldValue { return textValue(p.LastName) }),
+	"profile.phone":      profileField(func(p *Profile) fieldValue { return textValue(p.Phone) }),
+	"profile.interests": profileField(func(p *Profile) fieldValue {
+		v := numberValue(float64(len(p.Interests)), len(p.Interests) > 0)
+		v.text = strings.Join(p.Interests, ",")
+		return v
+	}),
+	"profile.address.street":      addressField(func(a *Address) string { return a.Street }),
+	"profile.address.city":        addressField(func(a *Address) string { return a.City }),
+	"profile.address.state":       addressField(func(a *Address) string { return a.State }),
+	"profile.address.postal_code": addressField(func(a *Address) string { return a.PostalCode }),
+	"profile.address.country":     addressField(func(a *Address) string { return a.Country }),
+}
+
+func profileField(get func(p *Profile) fieldValue) fieldResolver {
+	return func(u User) fieldValue {
+		if u.Profile == nil {
+			return fieldValue{}
+		}
+		return get(u.Profile)
+	}
+}
+
+func addressField(get func(a *Address) string) fieldResolver {
+	return profileField(func(p *Profile) fieldValue {
+		if p.Address == nil {
+			return fieldValue{}
+		}
+		return textValue(get(p.Address))
+	})
+}
+
+// resolveField finds the resolver of a column path; profile.metadata.<key>
+// reads one metadata entry
+func resolveField(path string) (fieldResolver, error) {
+	if field, ok := userFields[path]; ok {
+		return field, nil
+	}
+	if key, ok := strings.CutPrefix(path, "profile.metadata."); ok && key != "" {
+		return profileField(func(p *Profile) fieldValue { return textValue(p.Metadata[key]) }), nil
+	}
+	return nil, fmt.Errorf("unknown field %q", path)
+}
@@ -0,0 +1,205 @@
+package parquet
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/testutil"
+)
+
+func TestQualityEngine_Rules(t *testing.T) {
+	minAge, maxAge := 18.0, 120.0
+	engine, err := NewQualityEngine(QualityRules{
+		Rules: []QualityRule{
+			{Name: "has_email", Type: RulePresent, Field: "email", Weight: 2},
+			{Name: "email_format", Type: RuleRegex, Field: "email", Pattern: `^[^@\s]+@[^@\s]+\.[a-z]+$`, Weight: 1},
+			{Name: "adult", Type: RuleRange, Field: "profile.metadata.age", Min: &minAge, Max: &maxAge, Weight: 1},
+			{Name: "updated_after_created", Type: RuleCompare, Field: "updated_at", Op: "ge", Other: "created_at", Weight: 1},
+		},
+		LowQualityBelow: 0.5,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	good := User{ID: 1, Email: "a@example.com", CreatedAt: created, UpdatedAt: created.Add(time.Hour),
+		Profile: &Profile{Metadata: map[string]string{"age": "30"}}}
+	if result := engine.Score(good); result.Score != 1 || len(result.FailedRules) != 0 {
+		t.Errorf("Expected a perfect score, got %+v", result)
+	}
+
+	bad := User{ID: 2, Email: "not-an-email", CreatedAt: created, UpdatedAt: created.Add(-time.Hour),
+		Profile: &Profile{Metadata: map[string]string{"age": "12"}}}
+	result := engine.Score(bad)
+	expected := []string{"email_format", "adult", "updated_after_created"}
+	if result.ID != 2 || result.Score != 0.4 || !reflect.DeepEqual(result.FailedRules, expected) {
+		t.Errorf("Expected score 0.4 failing %v, got %+v", expected, result)
+	}
+	if !engine.LowQuality(result.Score) || engine.LowQuality(0.5) {
+		t.Errorf("Expected scores below 0.5 to be low quality")
+	}
+
+	// Missing fields fail every rule that reads them
+	if result := engine.Score(User{ID: 3}); result.Score != 0 || len(result.FailedRules) != 4 {
+		t.Errorf("Expected every rule to fail, got %+v", result)
+	}
+
+	fields := []string{"email", "profile.metadata.age", "updated_at", "created_at"}
+	if !reflect.DeepEqual(engine.Fields(), fields) {
+		t.Errorf("Expected fields %v, got %v", fields, engine.Fields())
+	}
+
+	t.Log("✓ Presence, regex, range and cross-field rules are weighted")
+}
+
+func TestQualityEngine_Invalid(t *testing.T) {
+	cases := map[string]QualityRule{
+		"unknown field":    {Name: "r", Type: RulePresent, Field: "nickname", Weight: 1},
+		"bad pattern":      {Name: "r", Type: RuleRegex, Field: "email", Pattern: "(", Weight: 1},
+		"empty range":      {Name: "r", Type: RuleRange, Field: "id", Weight: 1},
+		"bad op":           {Name: "r", Type: RuleCompare, Field: "id", Op: "like", Other: "name", Weight: 1},
+		"zero weight":      {Name: "r", Type: RulePresent, Field: "id"},
+		"unknown type":     {Name: "r", Type: "spell_check", Field: "name", Weight: 1},
+		"missing name":     {Type: RulePresent, Field: "id", Weight: 1},
+		"unknown compared": {Name: "r", Type: RuleCompare, Field: "id", Op: "eq", Other: "age", Weight: 1},
+	}
+	for name, rule := range cases {
+		if _, err := NewQualityEngine(QualityRules{Rules: []QualityRule{rule}}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	duplicate := QualityRule{Name: "r", Type: RulePresent, Field: "id", Weight: 1}
+	if _, err := NewQualityEngine(QualityRules{Rules: []QualityRule{duplicate, duplicate}}); err == nil {
+		t.Error("Expected duplicate rule names to be rejected")
+	}
+	if _, err := NewQualityEngine(QualityRules{Rules: []QualityRule{duplicate}, MinAverage: 1.5}); err == nil {
+		t.Error("Expected an out of range threshold to be rejected")
+	}
+
+	t.Log("✓ Invalid rules are rejected")
+}
+
+func TestLoadQualityRules(t *testing.T) {
+	testDir := "tmp/test_quality_rules"
+	if err := os.MkdirAll(testDir, 0755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err)
+	}
+	defer os.RemoveAll(testDir)
+
+	files := map[string]string{
+		"rules.json": `{"rules": [{"name": "has_phone", "type": "present", "field": "profile.phone", "weight": 3}], "minAverage": 0.9}`,
+		"rules.yaml": "rules:\n  - name: has_phone\n    type: present\n    field: profile.phone\n    weight: 3\nminAverage: 0.9\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(testDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		rules, err := LoadQualityRules(path)
+		if err != nil {
+			t.Fatalf("Failed to load %s: %v", name, err)
+		}
+		if len(rules.Rules) != 1 || rules.Rules[0].Weight != 3 || rules.MinAverage != 0.9 {
+			t.Errorf("%s: unexpected rules %+v", name, rules)
+		}
+		// Thresholds left out keep their defaults
+		if rules.LowQualityBelow != 0.7 || rules.MaxLowQualityRatio != 1 {
+			t.Errorf("%s: expected default thresholds, got %+v", name, rules)
+		}
+	}
+
+	// A partially specified rule keeps its zero values instead of inheriting
+	// the default rule at the same index, and a file without rules keeps the defaults
+	partial := map[string]string{
+		"partial.json": `{"rules": [{"name": "has_email", "field": "email"}]}`,
+		"partial.yaml": "rules:\n  - name: has_email\n    field: email\n",
+	}
+	for name, content := range partial {
+		path := filepath.Join(testDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		rules, err := LoadQualityRules(path)
+		if err != nil {
+			t.Fatalf("Failed to load %s: %v", name, err)
+		}
+		want := []QualityRule{{Name: "has_email", Field: "email"}}
+		if !reflect.DeepEqual(rules.Rules, want) {
+			t.Errorf("%s: expected %+v, got %+v", name, want, rules.Rules)
+		}
+		if _, err := NewQualityEngine(rules); err == nil {
+			t.Errorf("%s: expected a rule without a type and weight to be rejected", name)
+		}
+	}
+	thresholds := filepath.Join(testDir, "thresholds.json")
+	if err := os.WriteFile(thresholds, []byte(`{"minAverage": 0.5}`), 0644); err != nil {
+		t.Fatalf("Failed to write thresholds.json: %v", err)
+	}
+	if rules, err := LoadQualityRules(thresholds); err != nil || !reflect.DeepEqual(rules.Rules, DefaultQualityRules().Rules) || rules.MinAverage != 0.5 {
+		t.Errorf("Expected default rules with a custom threshold, got %+v (%v)", rules, err)
+	}
+
+	if _, err := LoadQualityRules(filepath.Join(testDir, "missing.json")); err == nil {
+		t.Error("Expected a missing file to fail")
+	}
+	unsupported := filepath.Join(testDir, "rules.toml")
+	os.WriteFile(unsupported, []byte("rules = []"), 0644)
+	if _, err := LoadQualityRules(unsupported); err == nil || !strings.Contains(err.Error(), "unsupported") {
+		t.Errorf("Expected an unsupported format error, got %v", err)
+	}
+
+	t.Log("✓ Quality rules load from JSON and YAML")
+}
+
+func TestPipelineQualityRules(t *testing.T) {
+	testDir := "tmp/test_pipeline_quality"
+	pipeline := NewDataPipeline(testDir).WithClock(testutil.NewDefaultFakeClock())
+	defer pipeline.CleanupWorkflow()
+
+	// Require a postal code, which none of the sample users has, and accept
+	// any average but at most half the records scoring low
+	rules := DefaultQualityRules()
+	rules.Rules = append(rules.Rules, QualityRule{Name: "has_postal_code", Type: RulePresent, Field: "profile.address.postal_code", Weight: 5})
+	rules.MinAverage = 0
+	rules.MaxLowQualityRatio = 0.5
+	engine, err := NewQualityEngine(rules)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	pipeline.WithQualityEngine(engine)
+
+	err = pipeline.RunETLWorkflow()
+	if err == nil || !strings.Contains(err.Error(), "too many low quality records") {
+		t.Fatalf("Expected verification to fail the thresholds, got %v", err)
+	}
+
+	_, report, err := pipeline.committedOutput()
+	if err != nil {
+		t.Fatalf("Failed to resolve committed output: %v", err)
+	}
+	if report.FailedRules["has_postal_code"] != report.Records || len(report.Failures) != report.Records {
+		t.Fatalf("Expected every record to fail has_postal_code, got %v", report.FailedRules)
+	}
+	if failure := report.Failures[0]; failure.ID != 1 || !slices.Contains(failure.FailedRules, "has_postal_code") {
+		t.Errorf("Expected the record to list has_postal_code, got %+v", failure)
+	}
+
+	// The quality step's lineage follows the rules
+	graph, err := pipeline.CommittedLineage()
+	if err != nil {
+		t.Fatalf("Failed to read committed lineage: %v", err)
+	}
+	score, ok := graph.Column("profile.metadata.quality_score")
+	if !ok || !slices.Contains(score.Transforms[len(score.Transforms)-1].Inputs, "profile.address.postal_code") {
+		t.Errorf("Expected the postal code in the quality score lineage, got %+v", score)
+	}
+
+	t.Log("✓ Pipeline scores and verifies with configured rules")
+}
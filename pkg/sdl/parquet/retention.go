@@ -0,0 +1,259 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+
+	"go-transport-prac/internal/tracing"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/metrics"
+	"go-transport-prac/pkg/sdl/deadletter"
+)
+
+// Expirable is a record carrying optional retention metadata
+type Expirable interface {
+	// Expiry returns when the record expires, or false if it never does
+	Expiry() (time.Time, bool)
+}
+
+// Expiry returns the event's ExpiresAt
+func (a Analytics) Expiry() (time.Time, bool) {
+	if a.ExpiresAt == nil {
+		return time.Time{}, false
+	}
+	return *a.ExpiresAt, true
+}
+
+// Expiry returns the record headers' ExpiresAt
+func (r UserRecord) Expiry() (time.Time, bool) {
+	if r.Headers.ExpiresAt == nil {
+		return time.Time{}, false
+	}
+	return *r.Headers.ExpiresAt, true
+}
+
+// Expired reports whether r has expired at now
+func Expired(r Expirable, now time.Time) bool {
+	expiresAt, ok := r.Expiry()
+	return ok && !now.Before(expiresAt)
+}
+
+// RetainAnalytics sets events without an expiry to expire ttl after their timestamp
+func RetainAnalytics(events []Analytics, ttl time.Duration) {
+	for i := range events {
+		if events[i].ExpiresAt == nil {
+			expiresAt := events[i].Timestamp.Add(ttl)
+			events[i].ExpiresAt = &expiresAt
+		}
+	}
+}
+
+// WriteAnalytics writes analytics events to a Parquet file
+func (m *SimpleManager) WriteAnalytics(filename string, events []Analytics) error {
+	return writeRows(context.Background(), m, filename, events)
+}
+
+// WriteAnalyticsContext writes analytics events to a Parquet file, stopping
+// with ctx.Err() once ctx is done
+func (m *SimpleManager) WriteAnalyticsContext(ctx context.Context, filename string, events []Analytics) error {
+	return writeRows(ctx, m, filename, events)
+}
+
+// WriteAnalyticsWithOptions writes analytics events to a Parquet file with opts instead of the manager's options
+func (m *SimpleManager) WriteAnalyticsWithOptions(filename string, events []Analytics, opts WriterOptions) error {
+	return writeRowsWith(context.Background(), m, filename, events, opts)
+}
+
+// ReadAnalytics reads analytics events from a Parquet file
+func (m *SimpleManager) ReadAnalytics(filename string) ([]Analytics, error) {
+	return readRows[Analytics](context.Background(), m, filename)
+}
+
+// ReadAnalyticsContext reads analytics events from a Parquet file, stopping
+// with ctx.Err() once ctx is done
+func (m *SimpleManager) ReadAnalyticsContext(ctx context.Context, filename string) ([]Analytics, error) {
+	return readRows[Analytics](ctx, m, filename)
+}
+
+// PruneStats reports the outcome of pruning one file
+type PruneStats struct {
+	Filename    string
+	RowsBefore  int
+	RowsRemoved int
+	// Rewritten is false when nothing had expired and the file was left alone
+	Rewritten bool
+}
+
+// RowsKept returns the number of rows left in the file
+func (s PruneStats) RowsKept() int {
+	return s.RowsBefore - s.RowsRemoved
+}
+
+// PruneAnalytics rewrites filename without the events expired at now
+func (m *SimpleManager) PruneAnalytics(filename string, now time.Time) (PruneStats, error) {
+	return pruneFile[Analytics](m, filename, now)
+}
+
+// PruneUserRecords rewrites filename without the user records expired at now
+func (m *SimpleManager) PruneUserRecords(filename string, now time.Time) (PruneStats, error) {
+	return pruneFile[UserRecord](m, filename, now)
+}
+
+// pruneFile reads every row of filename, then rewrites the file with only the
+// rows that have not expired
+func pruneFile[T Expirable](m *SimpleManager, filename string, now time.Time) (PruneStats, error) {
+	rows, err := readRows[T](context.Background(), m, filename)
+	if err != nil {
+		return PruneStats{}, err
+	}
+
+	kept := rows[:0:0]
+	for _, row := range rows {
+		if !Expired(row, now) {
+			kept = append(kept, row)
+		}
+	}
+
+	stats := PruneStats{Filename: filename, RowsBefore: len(rows), RowsRemoved: len(rows) - len(kept)}
+	if stats.RowsRemoved == 0 {
+		return stats, nil
+	}
+
+	if err := writeRows(context.Background(), m, filename, kept); err != nil {
+		return stats, fmt.Errorf("failed to rewrite %s: %w", filename, err)
+	}
+	stats.Rewritten = true
+	return stats, nil
+}
+
+// readRows reads every row of a Parquet file, or in tolerant mode every row
+// group that decodes
+func readRows[T any](ctx context.Context, m *SimpleManager, filename string) (rows []T, err error) {
+	ctx, span := startSpan[T](ctx, "parquet.read", filename)
+	start := time.Now()
+	var size int64
+	defer func() {
+		observe[T](m, metrics.OperationDeserialize, start, size, err)
+		tracing.SetBytes(ctx, size)
+		tracing.End(span, len(rows), err)
+	}()
+
+	file, size, err := m.openFile(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if m.decodeMode == deadletter.Tolerant {
+		rows, err = readRowsTolerant[T](ctx, m, filename, file, size)
+		return rows, err
+	}
+
+	// Reading one row group at a time lets readRowGroup turn the panics of
+	// a malformed file into errors
+	pf, err := parquet.OpenFile(file, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+	rows = []T{}
+	for i, rg := range pf.RowGroups() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		groupRows, err := readRowGroup[T](rg)
+		// A read cut short by cancellation may not surface as an error
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read row group %d: %w", i, err)
+		}
+		rows = append(rows, groupRows...)
+	}
+	return rows, nil
+}
+
+// writeRows writes rows to a Parquet file with the manager's writer options
+func writeRows[T any](ctx context.Context, m *SimpleManager, filename string, rows []T) error {
+	return writeRowsWith(ctx, m, filename, rows, m.writerOptions)
+}
+
+// PruneReport summarizes one run of a PruneJob
+type PruneReport struct {
+	RanAt       time.Time
+	Files       []PruneStats
+	RowsRemoved int
+}
+
+// PruneJob drops expired rows from a fixed set of Parquet files
+type PruneJob struct {
+	manager *SimpleManager
+	clock   types.Clock
+	targets []pruneTarget
+}
+
+type pruneTarget struct {
+	filename string
+	prune    func(filename string, now time.Time) (PruneStats, error)
+}
+
+// NewPruneJob creates a job pruning files managed by m
+func NewPruneJob(m *SimpleManager) *PruneJob {
+	return &PruneJob{manager: m, clock: types.SystemClock{}}
+}
+
+// WithClock sets the clock used to decide which rows have expired
+func (j *PruneJob) WithClock(clock types.Clock) *PruneJob {
+	j.clock = types.ClockOrSystem(clock)
+	return j
+}
+
+// AddAnalyticsFile adds an analytics events file to the job
+func (j *PruneJob) AddAnalyticsFile(filename string) *PruneJob {
+	j.targets = append(j.targets, pruneTarget{filename, j.manager.PruneAnalytics})
+	return j
+}
+
+// AddUserRecordFile adds a user record envelope file to the job
+func (j *PruneJob) AddUserRecordFile(filename string) *PruneJob {
+	j.targets = append(j.targets, pruneTarget{filename, j.manager.PruneUserRecords})
+	return j
+}
+
+// RunOnce prunes every file, stopping at the first failure
+func (j *PruneJob) RunOnce() (PruneReport, error) {
+	report := PruneReport{RanAt: j.clock.Now()}
+
+	for _, target := range j.targets {
+		stats, err := target.prune(target.filename, report.RanAt)
+		if err != nil {
+			return report, fmt.Errorf("failed to prune %s: %w", target.filename, err)
+		}
+		report.Files = append(report.Files, stats)
+		report.RowsRemoved += stats.RowsRemoved
+	}
+
+	return report, nil
+}
+
+// Run prunes every interval until ctx is done, passing each report to onReport
+func (j *PruneJob) Run(ctx context.Context, interval time.Duration, onReport func(PruneReport, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := j.RunOnce()
+		if onReport != nil {
+			onReport(report, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
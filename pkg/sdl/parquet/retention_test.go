@@ -0,0 +1,119 @@
+package parquet
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/storage"
+)
+
+func TestPruneAnalytics(t *testing.T) {
+	testDir := "tmp/test_prune_analytics"
+	defer os.RemoveAll(testDir)
+
+	clock := testutil.NewDefaultFakeClock()
+	pipeline := NewDataPipeline(testDir).WithClock(clock)
+	manager := NewSimpleManager(testDir)
+
+	// 48 hourly batches of 10 events, retained for 24 hours after their timestamp
+	events := pipeline.generateAnalyticsData(48, 10)
+	RetainAnalytics(events, 24*time.Hour)
+
+	// Events already carrying an expiry keep it
+	forever := clock.Now().Add(100 * 365 * 24 * time.Hour)
+	events[0].ExpiresAt = &forever
+	events[1].ExpiresAt = nil
+
+	if err := manager.WriteAnalytics("events.parquet", events); err != nil {
+		t.Fatalf("Failed to write analytics: %v", err)
+	}
+
+	stats, err := manager.PruneAnalytics("events.parquet", clock.Now())
+	if err != nil {
+		t.Fatalf("Failed to prune: %v", err)
+	}
+
+	// The first 24 hours and the event exactly at the cutoff have expired,
+	// except the event retained indefinitely and the one without expiry metadata
+	if stats.RowsBefore != 480 || stats.RowsRemoved != 239 || stats.RowsKept() != 241 || !stats.Rewritten {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	kept, err := manager.ReadAnalytics("events.parquet")
+	if err != nil {
+		t.Fatalf("Failed to read pruned file: %v", err)
+	}
+	if len(kept) != stats.RowsKept() {
+		t.Fatalf("Expected %d rows in pruned file, got %d", stats.RowsKept(), len(kept))
+	}
+	for _, event := range kept {
+		if Expired(event, clock.Now()) {
+			t.Errorf("Expired event %d survived pruning", event.ID)
+		}
+	}
+	if kept[0].ID != 1 || kept[1].ID != 2 || kept[1].ExpiresAt != nil {
+		t.Errorf("Expected events 1 and 2 to be retained, got %d and %d", kept[0].ID, kept[1].ID)
+	}
+
+	// Pruning again finds nothing and leaves the file alone
+	stats, err = manager.PruneAnalytics("events.parquet", clock.Now())
+	if err != nil || stats.RowsRemoved != 0 || stats.Rewritten {
+		t.Errorf("Expected a no-op prune, got %+v (%v)", stats, err)
+	}
+
+	t.Logf("✓ Pruned %d expired events, kept %d", 239, len(kept))
+}
+
+func TestPruneJob(t *testing.T) {
+	clock := testutil.NewDefaultFakeClock()
+	manager := NewSimpleManager("").WithStorage(storage.NewMemoryStorage())
+
+	records := make([]UserRecord, 4)
+	for i := range records {
+		headers := RecordHeaders{IngestTime: clock.Now(), Source: "signup", Extra: map[string]string{}}
+		if i%2 == 0 {
+			headers = headers.WithTTL(time.Duration(i+1) * time.Hour)
+		}
+		records[i] = UserRecord{Headers: headers, User: User{ID: int64(i + 1), Email: "user@example.com", CreatedAt: clock.Now(), UpdatedAt: clock.Now()}}
+	}
+	if err := manager.WriteUserRecords("users.parquet", records); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+
+	events := NewDataPipeline("").WithClock(clock).generateAnalyticsData(2, 5)
+	RetainAnalytics(events, 150*time.Minute)
+	if err := manager.WriteAnalytics("events.parquet", events); err != nil {
+		t.Fatalf("Failed to write analytics: %v", err)
+	}
+
+	job := NewPruneJob(manager).WithClock(clock).
+		AddUserRecordFile("users.parquet").
+		AddAnalyticsFile("events.parquet")
+
+	// 80 minutes on, record 1 (1h TTL) and the first hour of events have expired
+	clock.Advance(80 * time.Minute)
+	report, err := job.RunOnce()
+	if err != nil {
+		t.Fatalf("Prune job failed: %v", err)
+	}
+	if len(report.Files) != 2 || report.Files[0].RowsRemoved != 1 || report.Files[1].RowsRemoved != 5 || report.RowsRemoved != 6 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+
+	remaining, err := manager.ReadUserRecords("users.parquet")
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if len(remaining) != 3 || remaining[0].User.ID != 2 {
+		t.Errorf("Expected records 2-4 to remain, got %d records", len(remaining))
+	}
+
+	// Missing files fail the run
+	if _, err := job.AddAnalyticsFile("missing.parquet").RunOnce(); err == nil {
+		t.Error("Expected pruning a missing file to fail")
+	}
+
+	t.Logf("✓ Prune job removed %d rows across %d files", report.RowsRemoved, len(report.Files))
+}
@@ -0,0 +1,47 @@
+package parquet
+
+import (
+	"io"
+
+	"github.com/segmentio/parquet-go"
+
+	"go-transport-prac/pkg/sdl/rotation"
+)
+
+// parquetSegment writes each batch as its own row group so file size tracks writes
+type parquetSegment[T any] struct {
+	writer *parquet.GenericWriter[T]
+}
+
+func (s *parquetSegment[T]) Write(records []T) error {
+	if _, err := s.writer.Write(records); err != nil {
+		return err
+	}
+	return s.writer.Flush()
+}
+
+func (s *parquetSegment[T]) Close() error {
+	return s.writer.Close()
+}
+
+// NewRotatingWriter creates a writer that rolls Parquet files of T by time window and/or size
+func NewRotatingWriter[T any](cfg rotation.Config) (*rotation.Writer[T], error) {
+	if cfg.Extension == "" {
+		cfg.Extension = ".parquet"
+	}
+	return rotation.NewWriter(cfg, func(w io.Writer) (rotation.SegmentWriter[T], error) {
+		return &parquetSegment[T]{writer: parquet.NewGenericWriter[T](w)}, nil
+	})
+}
+
+// NewUserRotatingWriter creates a rotating user writer in the manager's base directory,
+// producing files such as users-2024-06-01T10.parquet
+func (m *SimpleManager) NewUserRotatingWriter(cfg rotation.Config) (*rotation.Writer[User], error) {
+	if cfg.Dir == "" {
+		cfg.Dir = m.baseDir
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = "users"
+	}
+	return NewRotatingWriter[User](cfg)
+}
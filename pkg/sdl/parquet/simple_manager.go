@@ -1,16 +1,58 @@
 package parquet
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/format"
+
+	"go-transport-prac/internal/atomicfile"
+	"go-transport-prac/internal/ctxio"
+	"go-transport-prac/internal/filelock"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/metrics"
+	"go-transport-prac/pkg/sdl/catalog"
+	"go-transport-prac/pkg/sdl/checksum"
+	"go-transport-prac/pkg/sdl/deadletter"
+	"go-transport-prac/pkg/sdl/encryption"
 )
 
 // SimpleManager provides basic Parquet operations
+//
+// Once configured, a SimpleManager is safe for concurrent use; the With
+// methods are not. Writes and deletes of a file are serialized across every
+// manager in the process, and files are replaced atomically, so readers see
+// the old or the new contents without waiting. Read-modify-write operations
+// such as PruneAnalytics do not hold the lock between reading and writing,
+// and other processes writing the same directory are not covered
 type SimpleManager struct {
 	baseDir string
+	// storage, when set, replaces baseDir for file reads and writes
+	storage types.Storage
+	// writerOptions apply to every file written through the manager
+	writerOptions WriterOptions
+	// metrics, when set, records every whole-file write and read
+	metrics *metrics.Instrumenter
+	// decodeMode and deadLetters control how whole-file reads handle bad rows
+	decodeMode  deadletter.Mode
+	deadLetters *deadletter.File
+	// keys, when set, encrypts every file the manager writes
+	keys encryption.KeyProvider
+	// plaintextReads lets a manager with keys read files that are not encrypted
+	plaintextReads bool
+	// checksums records a SHA-256 sidecar for every file the manager writes
+	checksums bool
+	// catalog, when set, records every file the manager writes
+	catalog *catalog.Catalog
+	// sync flushes every written file to stable storage before returning
+	sync bool
 }
 
 // NewSimpleManager creates a new simple Parquet manager
@@ -23,127 +65,199 @@ func NewSimpleManager(baseDir string) *SimpleManager {
 	}
 }
 
+// WithStorage sends file reads and writes to storage instead of baseDir.
+// Filenames are used as object keys
+func (m *SimpleManager) WithStorage(storage types.Storage) *SimpleManager {
+	m.storage = storage
+	return m
+}
+
+// WithWriterOptions sets the options used for every file the manager writes.
+// Sorting columns must exist in every model written with these options
+func (m *SimpleManager) WithWriterOptions(opts WriterOptions) *SimpleManager {
+	m.writerOptions = opts
+	return m
+}
+
+// WithEncryption encrypts every file the manager writes with the current key
+// of keys and decrypts the files it reads. Files that are not encrypted then
+// fail to read, see WithPlaintextReads
+func (m *SimpleManager) WithEncryption(keys encryption.KeyProvider) *SimpleManager {
+	m.keys = keys
+	return m
+}
+
+// WithPlaintextReads lets a manager with encryption read files written before
+// it was turned on, while they are migrated. Leave it off otherwise, so a
+// plain file put in place of an encrypted one is not read as data
+func (m *SimpleManager) WithPlaintextReads(on bool) *SimpleManager {
+	m.plaintextReads = on
+	return m
+}
+
+// WithChecksums records the SHA-256 checksum of every file the manager writes
+// in a sidecar named after it, see VerifyFile
+func (m *SimpleManager) WithChecksums(on bool) *SimpleManager {
+	m.checksums = on
+	return m
+}
+
+// WithCatalog records every file the manager writes in c, with its entity,
+// row count, schema fingerprint, size and checksum. DeleteFile drops the
+// file's entry
+func (m *SimpleManager) WithCatalog(c *catalog.Catalog) *SimpleManager {
+	m.catalog = c
+	return m
+}
+
+// WithSync flushes every file the manager writes, and its rename into place,
+// to stable storage before the write returns. Files are always replaced
+// atomically; without sync a power loss can still lose a recent write
+func (m *SimpleManager) WithSync(on bool) *SimpleManager {
+	m.sync = on
+	return m
+}
+
+// Catalog returns the catalog set with WithCatalog, or nil
+func (m *SimpleManager) Catalog() *catalog.Catalog {
+	return m.catalog
+}
+
 // ensureDir creates directory if it doesn't exist
 func (m *SimpleManager) ensureDir() error {
 	return os.MkdirAll(m.baseDir, 0755)
 }
 
-// WriteUsers writes user data to Parquet file with default settings
+// WriteUsers writes user data to Parquet file with the manager's writer options
 func (m *SimpleManager) WriteUsers(filename string, users []User) error {
-	if err := m.ensureDir(); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
+	return m.WriteUsersWithOptions(filename, users, m.writerOptions)
+}
 
-	filePath := filepath.Join(m.baseDir, filename)
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer file.Close()
+// WriteUsersContext writes user data to Parquet file with the manager's
+// writer options, stopping with ctx.Err() once ctx is done
+func (m *SimpleManager) WriteUsersContext(ctx context.Context, filename string, users []User) error {
+	return m.writeUsers(ctx, filename, users, m.writerOptions)
+}
 
-	writer := parquet.NewGenericWriter[User](file)
-	defer writer.Close()
+// WriteUsersWithOptions writes user data to Parquet file with opts instead of the manager's options
+func (m *SimpleManager) WriteUsersWithOptions(filename string, users []User, opts WriterOptions) error {
+	return m.writeUsers(context.Background(), filename, users, opts)
+}
 
-	_, err = writer.Write(users)
-	if err != nil {
+func (m *SimpleManager) writeUsers(ctx context.Context, filename string, users []User, opts WriterOptions) error {
+	if err := writeRowsWith(ctx, m, filename, users, opts); err != nil {
 		return fmt.Errorf("failed to write users: %w", err)
 	}
-
 	return nil
 }
 
 // ReadUsers reads user data from Parquet file
 func (m *SimpleManager) ReadUsers(filename string) ([]User, error) {
-	filePath := filepath.Join(m.baseDir, filename)
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
-	}
-	defer file.Close()
-
-	reader := parquet.NewGenericReader[User](file)
-	defer reader.Close()
-
-	users := make([]User, reader.NumRows())
-	n, err := reader.Read(users)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read users: %w", err)
-	}
+	return readRows[User](context.Background(), m, filename)
+}
 
-	return users[:n], nil
+// ReadUsersContext reads user data from Parquet file, stopping with
+// ctx.Err() once ctx is done
+func (m *SimpleManager) ReadUsersContext(ctx context.Context, filename string) ([]User, error) {
+	return readRows[User](ctx, m, filename)
 }
 
-// WriteProducts writes product data to Parquet file
+// WriteProducts writes product data to Parquet file with the manager's writer options
 func (m *SimpleManager) WriteProducts(filename string, products []Product) error {
-	if err := m.ensureDir(); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
+	return m.WriteProductsWithOptions(filename, products, m.writerOptions)
+}
 
-	filePath := filepath.Join(m.baseDir, filename)
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer file.Close()
+// WriteProductsContext writes product data to Parquet file with the manager's
+// writer options, stopping with ctx.Err() once ctx is done
+func (m *SimpleManager) WriteProductsContext(ctx context.Context, filename string, products []Product) error {
+	return m.writeProducts(ctx, filename, products, m.writerOptions)
+}
 
-	writer := parquet.NewGenericWriter[Product](file)
-	defer writer.Close()
+// WriteProductsWithOptions writes product data to Parquet file with opts instead of the manager's options
+func (m *SimpleManager) WriteProductsWithOptions(filename string, products []Product, opts WriterOptions) error {
+	return m.writeProducts(context.Background(), filename, products, opts)
+}
 
-	_, err = writer.Write(products)
-	if err != nil {
+func (m *SimpleManager) writeProducts(ctx context.Context, filename string, products []Product, opts WriterOptions) error {
+	if err := writeRowsWith(ctx, m, filename, products, opts); err != nil {
 		return fmt.Errorf("failed to write products: %w", err)
 	}
-
 	return nil
 }
 
 // ReadProducts reads product data from Parquet file
 func (m *SimpleManager) ReadProducts(filename string) ([]Product, error) {
-	filePath := filepath.Join(m.baseDir, filename)
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
-	}
-	defer file.Close()
+	return readRows[Product](context.Background(), m, filename)
+}
 
-	reader := parquet.NewGenericReader[Product](file)
-	defer reader.Close()
+// ReadProductsContext reads product data from Parquet file, stopping with
+// ctx.Err() once ctx is done
+func (m *SimpleManager) ReadProductsContext(ctx context.Context, filename string) ([]Product, error) {
+	return readRows[Product](ctx, m, filename)
+}
 
-	products := make([]Product, reader.NumRows())
-	n, err := reader.Read(products)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read products: %w", err)
+// WriteOrders writes order data to Parquet file with the manager's writer options
+func (m *SimpleManager) WriteOrders(filename string, orders []Order) error {
+	return m.WriteOrdersWithOptions(filename, orders, m.writerOptions)
+}
+
+// WriteOrdersContext writes order data to Parquet file with the manager's
+// writer options, stopping with ctx.Err() once ctx is done
+func (m *SimpleManager) WriteOrdersContext(ctx context.Context, filename string, orders []Order) error {
+	return m.writeOrders(ctx, filename, orders, m.writerOptions)
+}
+
+// WriteOrdersWithOptions writes order data to Parquet file with opts instead of the manager's options
+func (m *SimpleManager) WriteOrdersWithOptions(filename string, orders []Order, opts WriterOptions) error {
+	return m.writeOrders(context.Background(), filename, orders, opts)
+}
+
+func (m *SimpleManager) writeOrders(ctx context.Context, filename string, orders []Order, opts WriterOptions) error {
+	if err := writeRowsWith(ctx, m, filename, orders, opts); err != nil {
+		return fmt.Errorf("failed to write orders: %w", err)
 	}
+	return nil
+}
 
-	return products[:n], nil
+// ReadOrders reads order data from Parquet file
+func (m *SimpleManager) ReadOrders(filename string) ([]Order, error) {
+	return readRows[Order](context.Background(), m, filename)
+}
+
+// ReadOrdersContext reads order data from Parquet file, stopping with
+// ctx.Err() once ctx is done
+func (m *SimpleManager) ReadOrdersContext(ctx context.Context, filename string) ([]Order, error) {
+	return readRows[Order](ctx, m, filename)
 }
 
 // GetBasicFileInfo returns basic information about a Parquet file
 func (m *SimpleManager) GetBasicFileInfo(filename string) (*BasicFileInfo, error) {
-	filePath := filepath.Join(m.baseDir, filename)
-	file, err := os.Open(filePath)
+	file, size, err := m.openFile(context.Background(), filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, err
 	}
 	defer file.Close()
 
-	stat, err := file.Stat()
+	pf, err := parquet.OpenFile(file, size)
 	if err != nil {
-		return nil, fmt.Errorf("failed to stat file: %w", err)
+		return nil, fmt.Errorf("failed to open parquet file: %w", err)
 	}
 
-	pf, err := parquet.OpenFile(file, stat.Size())
-	if err != nil {
-		return nil, fmt.Errorf("failed to open parquet file: %w", err)
+	filePath := filepath.Join(m.baseDir, filename)
+	if m.storage != nil {
+		filePath = filename
 	}
 
-	return &BasicFileInfo{
-		Filename: filename,
-		FilePath: filePath,
-		FileSize: stat.Size(),
-		NumRows:  pf.NumRows(),
-		Schema:   pf.Schema(),
-	}, nil
+	info := &BasicFileInfo{
+		Filename:     filename,
+		FilePath:     filePath,
+		FileSize:     size,
+		NumRows:      pf.NumRows(),
+		Schema:       pf.Schema(),
+		NumRowGroups: len(pf.Metadata().RowGroups),
+	}
+	describeWriterOptions(info, pf)
+	return info, nil
 }
 
 // BasicFileInfo contains basic information about a Parquet file
@@ -153,10 +267,89 @@ type BasicFileInfo struct {
 	FileSize int64
 	NumRows  int64
 	Schema   *parquet.Schema
+
+	// The fields below reflect the WriterOptions the file was written with
+
+	// Compression is the codec of every column chunk, or "mixed"
+	Compression  Codec
+	NumRowGroups int
+	// DataPageVersion is 1 or 2, or 0 when the file has no data pages
+	DataPageVersion int
+	// DataPages counts data pages across all column chunks
+	DataPages      int
+	SortingColumns []SortingColumn
+}
+
+// codecNames maps footer compression codecs back to codec names
+var codecNames = map[format.CompressionCodec]Codec{
+	format.Uncompressed: CodecNone,
+	format.Snappy:       CodecSnappy,
+	format.Gzip:         CodecGzip,
+	format.Zstd:         CodecZstd,
+}
+
+// describeWriterOptions fills the writer option fields of info from the file footer
+func describeWriterOptions(info *BasicFileInfo, pf *parquet.File) {
+	metadata := pf.Metadata()
+
+	for _, rg := range metadata.RowGroups {
+		for _, chunk := range rg.Columns {
+			codec, ok := codecNames[chunk.MetaData.Codec]
+			if !ok {
+				codec = Codec(strings.ToLower(chunk.MetaData.Codec.String()))
+			}
+			if info.Compression == "" {
+				info.Compression = codec
+			} else if info.Compression != codec {
+				info.Compression = "mixed"
+			}
+
+			for _, stats := range chunk.MetaData.EncodingStats {
+				switch stats.PageType {
+				case format.DataPage:
+					info.DataPageVersion = 1
+					info.DataPages += int(stats.Count)
+				case format.DataPageV2:
+					info.DataPageVersion = 2
+					info.DataPages += int(stats.Count)
+				}
+			}
+		}
+	}
+
+	if len(metadata.RowGroups) == 0 {
+		return
+	}
+	columns := pf.Schema().Columns()
+	for _, sc := range metadata.RowGroups[0].SortingColumns {
+		if int(sc.ColumnIdx) >= len(columns) {
+			continue
+		}
+		info.SortingColumns = append(info.SortingColumns, SortingColumn{
+			Path:       strings.Join(columns[sc.ColumnIdx], "."),
+			Descending: sc.Descending,
+			NullsFirst: sc.NullsFirst,
+		})
+	}
 }
 
-// ListFiles lists all Parquet files in the base directory
+// ListFiles lists all Parquet files in the base directory or storage backend
 func (m *SimpleManager) ListFiles() ([]string, error) {
+	if m.storage != nil {
+		keys, err := m.storage.List(context.Background(), "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files: %w", err)
+		}
+
+		var files []string
+		for _, key := range keys {
+			if filepath.Ext(key) == ".parquet" {
+				files = append(files, key)
+			}
+		}
+		return files, nil
+	}
+
 	if err := m.ensureDir(); err != nil {
 		return nil, fmt.Errorf("failed to create directory: %w", err)
 	}
@@ -176,8 +369,219 @@ func (m *SimpleManager) ListFiles() ([]string, error) {
 	return files, nil
 }
 
-// DeleteFile deletes a Parquet file
+// DeleteFile deletes a Parquet file, its checksum sidecar and its catalog
+// entry, if any
 func (m *SimpleManager) DeleteFile(filename string) error {
+	defer filelock.Files.Lock(filelock.Key(m.storage, m.baseDir, filename))()
+
+	ctx := context.Background()
+	if m.storage != nil {
+		if err := m.storage.Delete(ctx, filename); err != nil {
+			return err
+		}
+		if err := checksum.RemoveObjectSidecar(ctx, m.storage, filename); err != nil {
+			return err
+		}
+	} else {
+		filePath := filepath.Join(m.baseDir, filename)
+		if err := os.Remove(filePath); err != nil {
+			return err
+		}
+		if err := checksum.RemoveSidecar(filePath); err != nil {
+			return err
+		}
+	}
+	if m.catalog != nil {
+		return m.catalog.Remove(ctx, filename)
+	}
+	return nil
+}
+
+// StatFile returns the size and modification time of a Parquet file. Objects in
+// a storage backend report the creation time recorded in the catalog, or a
+// zero time when the manager has no entry for them
+func (m *SimpleManager) StatFile(filename string) (fs.FileInfo, error) {
+	if m.storage == nil {
+		return os.Stat(filepath.Join(m.baseDir, filename))
+	}
+	if m.catalog != nil {
+		if e, ok := m.catalog.Get(filename); ok {
+			return catalog.FileInfo(e), nil
+		}
+	}
+	obj, err := m.storage.Get(context.Background(), filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer obj.Close()
+	size, err := io.Copy(io.Discard, obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return catalog.FileInfo(catalog.Entry{File: filename, Bytes: size}), nil
+}
+
+// VerifyFile checks a file against the checksum recorded when it was
+// written with WithChecksums. It returns a *checksum.MismatchError for a
+// corrupted file and an error wrapping checksum.ErrNoChecksum when no
+// checksum was recorded
+func (m *SimpleManager) VerifyFile(filename string) error {
+	return m.VerifyFileContext(context.Background(), filename)
+}
+
+// VerifyFileContext is VerifyFile, stopping once ctx is done
+func (m *SimpleManager) VerifyFileContext(ctx context.Context, filename string) error {
+	// Hold off writers, so the file is checked against its own sidecar
+	defer filelock.Files.RLock(filelock.Key(m.storage, m.baseDir, filename))()
+
+	if m.storage != nil {
+		return checksum.VerifyObject(ctx, m.storage, filename)
+	}
+	return checksum.VerifyFile(ctx, filepath.Join(m.baseDir, filename))
+}
+// readerAtCloser is a Parquet input that must be closed after reading
+type readerAtCloser interface {
+	io.ReaderAt
+	io.Closer
+}
+
+// writeFile runs write against a local file, or a buffer uploaded to storage
+// once write returns. Writes fail once ctx is done, and a local file cut
+// short by cancellation is removed
+func (m *SimpleManager) writeFile(ctx context.Context, filename string, entry catalog.Entry, write func(io.Writer) error) error {
+	// The file, its sidecar and its catalog entry change together
+	defer filelock.Files.Lock(filelock.Key(m.storage, m.baseDir, filename))()
+
+	if m.keys != nil {
+		plain := write
+		write = func(w io.Writer) error { return encryption.Encrypt(ctx, w, m.keys, plain) }
+	}
+	// The checksum covers the stored bytes, so it also catches damage to
+	// encrypted files without needing their key
+	var hasher *checksum.Writer
+	if m.checksums || m.catalog != nil {
+		inner := write
+		write = func(w io.Writer) error {
+			hasher = checksum.NewWriter(w)
+			return inner(hasher)
+		}
+	}
+
+	if m.storage != nil {
+		var buf bytes.Buffer
+		if err := write(ctxio.NewWriter(ctx, &buf)); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		if err := m.storage.Put(ctx, filename, &buf); err != nil {
+			return fmt.Errorf("failed to store file: %w", err)
+		}
+		if m.checksums {
+			if err := checksum.PutSidecar(ctx, m.storage, filename, hasher.Sum()); err != nil {
+				return err
+			}
+		}
+		return m.catalogFile(ctx, filename, entry, hasher)
+	}
+
+	// filename may name a file in a subdirectory, such as a partition
 	filePath := filepath.Join(m.baseDir, filename)
-	return os.Remove(filePath)
-}
\ No newline at end of file
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// Write to a temporary file renamed over filename once complete, so a
+	// failed or interrupted write never leaves a truncated file behind
+	file, err := atomicfile.Create(filePath, m.sync)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Abort()
+
+	if err := write(ctxio.NewWriter(ctx, file)); err != nil {
+		// The encoder error is only a symptom of the cancellation
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	if err := file.Commit(); err != nil {
+		return err
+	}
+	if m.checksums {
+		if err := checksum.WriteSidecar(filePath, hasher.Sum()); err != nil {
+			return err
+		}
+	}
+	return m.catalogFile(ctx, filename, entry, hasher)
+}
+
+// catalogFile records a written file in the catalog, if the manager has one
+func (m *SimpleManager) catalogFile(ctx context.Context, filename string, entry catalog.Entry, hasher *checksum.Writer) error {
+	if m.catalog == nil {
+		return nil
+	}
+	entry.File = filename
+	entry.Bytes = hasher.Size()
+	entry.SHA256 = hasher.Sum()
+	if err := m.catalog.Record(ctx, entry); err != nil {
+		return fmt.Errorf("failed to catalog file: %w", err)
+	}
+	return nil
+}
+
+// openFile opens a local file, or downloads the object from storage, and
+// returns it with its size since Parquet footers are read from the end.
+// Reads from it fail once ctx is done
+func (m *SimpleManager) openFile(ctx context.Context, filename string) (readerAtCloser, int64, error) {
+	if m.storage == nil {
+		file, err := os.Open(filepath.Join(m.baseDir, filename))
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to open file: %w", err)
+		}
+		stat, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return nil, 0, fmt.Errorf("failed to stat file: %w", err)
+		}
+		plain, size, err := encryption.OpenAt(ctx, ctxio.NewReaderAt(ctx, file), stat.Size(), m.keys, m.plaintextReads)
+		if err != nil {
+			file.Close()
+			return nil, 0, fmt.Errorf("failed to open file: %w", err)
+		}
+		return sizedFile{plain, file, size}, size, nil
+	}
+
+	obj, err := m.storage.Get(ctx, filename)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer obj.Close()
+
+	data, err := io.ReadAll(ctxio.NewReader(ctx, obj))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to download file: %w", err)
+	}
+	plain, size, err := encryption.OpenAt(ctx, bytes.NewReader(data), int64(len(data)), m.keys, m.plaintextReads)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open file: %w", err)
+	}
+	return sizedFile{plain, nopCloser{}, size}, size, nil
+}
+
+// sizedFile reports its plaintext size to readers that look for a Size
+// method, such as parquet.NewGenericReader
+type sizedFile struct {
+	io.ReaderAt
+	io.Closer
+	size int64
+}
+
+func (f sizedFile) Size() int64 { return f.size }
+
+// nopCloser closes an input held in memory
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
@@ -1,9 +1,19 @@
 package parquet
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
+
+	"go-transport-prac/pkg/sdl/catalog"
+	"go-transport-prac/pkg/sdl/checksum"
+	"go-transport-prac/pkg/sdl/encryption"
+	"go-transport-prac/pkg/storage"
 )
 
 func TestSimpleParquetOperations(t *testing.T) {
@@ -231,4 +241,217 @@ func TestProductOperations(t *testing.T) {
 	}
 
 	t.Logf("✓ Product operations completed successfully")
-}
\ No newline at end of file
+}
+func TestSimpleManagerWithStorage(t *testing.T) {
+	testDir := "tmp/test_storage_parquet"
+	backend := storage.NewMemoryStorage()
+	manager := NewSimpleManager(testDir).WithStorage(backend)
+
+	users := []User{
+		{ID: 1, Email: "a@example.com", Name: "A", Status: "active", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		{ID: 2, Email: "b@example.com", Name: "B", Status: "inactive", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+	}
+	if err := manager.WriteUsers("users.parquet", users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	if err := manager.WriteProducts("products.parquet", []Product{{ID: 1, Name: "P", SKU: "P-1", Price: &Price{Currency: "USD", AmountCents: 100}}}); err != nil {
+		t.Fatalf("Failed to write products: %v", err)
+	}
+
+	if _, err := os.Stat(testDir); !os.IsNotExist(err) {
+		t.Errorf("Expected no local directory, stat returned %v", err)
+		os.RemoveAll(testDir)
+	}
+
+	readUsers, err := manager.ReadUsers("users.parquet")
+	if err != nil {
+		t.Fatalf("Failed to read users: %v", err)
+	}
+	if len(readUsers) != 2 || readUsers[1].Email != "b@example.com" {
+		t.Errorf("Unexpected users: %+v", readUsers)
+	}
+
+	info, err := manager.GetBasicFileInfo("users.parquet")
+	if err != nil {
+		t.Fatalf("Failed to get file info: %v", err)
+	}
+	if info.NumRows != 2 || info.FileSize == 0 {
+		t.Errorf("Unexpected file info: %+v", info)
+	}
+
+	files, err := manager.ListFiles()
+	if err != nil || len(files) != 2 {
+		t.Errorf("Expected 2 files, got %v (%v)", files, err)
+	}
+
+	if err := manager.DeleteFile("products.parquet"); err != nil {
+		t.Fatalf("Failed to delete file: %v", err)
+	}
+	if exists, _ := backend.Exists(t.Context(), "products.parquet"); exists {
+		t.Error("Expected products.parquet to be deleted")
+	}
+
+	t.Log("✓ Parquet files round-trip through the storage backend")
+}
+
+func TestSimpleManagerWithEncryption(t *testing.T) {
+	testDir := "tmp/test_encrypted_parquet"
+	defer os.RemoveAll(testDir)
+	keys, err := encryption.NewStaticKeys("k1", map[string][]byte{"k1": bytes.Repeat([]byte{2}, 32)})
+	if err != nil {
+		t.Fatalf("Failed to create keys: %v", err)
+	}
+	manager := NewSimpleManager(testDir).WithEncryption(keys)
+	plainManager := NewSimpleManager(testDir)
+
+	users := createSampleUsers(500)
+	if err := manager.WriteUsers("users.parquet", users); err != nil {
+		t.Fatalf("Failed to write encrypted users: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(testDir, "users.parquet"))
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if !encryption.IsEncrypted(data) || bytes.Contains(data, []byte("PAR1")) {
+		t.Fatal("Expected the file to be encrypted")
+	}
+
+	readUsers, err := manager.ReadUsers("users.parquet")
+	if err != nil {
+		t.Fatalf("Failed to read encrypted users: %v", err)
+	}
+	if len(readUsers) != len(users) || readUsers[499].Email != users[499].Email {
+		t.Errorf("User mismatch: wrote %d, read %d", len(users), len(readUsers))
+	}
+	// Streaming readers and file info read footers at random offsets
+	reader, err := manager.NewUserReader("users.parquet", 100)
+	if err != nil {
+		t.Fatalf("Failed to open streaming reader: %v", err)
+	}
+	count := 0
+	for reader.Next() {
+		count++
+	}
+	reader.Close()
+	if reader.Err() != nil || count != len(users) {
+		t.Errorf("Expected %d streamed users, got %d (%v)", len(users), count, reader.Err())
+	}
+	if info, err := manager.GetBasicFileInfo("users.parquet"); err != nil || info.NumRows != int64(len(users)) {
+		t.Errorf("Unexpected file info %+v (%v)", info, err)
+	}
+	if _, err := plainManager.ReadUsers("users.parquet"); err == nil {
+		t.Error("Expected an encrypted file to fail without keys")
+	}
+
+	// Plain files written before encryption was turned on only read while
+	// plaintext reads are allowed for migrating them
+	if err := plainManager.WriteUsers("plain.parquet", users[:10]); err != nil {
+		t.Fatalf("Failed to write plain users: %v", err)
+	}
+	if _, err := manager.ReadUsers("plain.parquet"); !errors.Is(err, encryption.ErrNotEncrypted) {
+		t.Errorf("Expected a plain file to be rejected with encryption on, got %v", err)
+	}
+	manager.WithPlaintextReads(true)
+	if readUsers, err := manager.ReadUsers("plain.parquet"); err != nil || len(readUsers) != 10 {
+		t.Errorf("Expected plain files to read with plaintext reads allowed, got %d users (%v)", len(readUsers), err)
+	}
+	manager.WithPlaintextReads(false)
+
+	// Storage backends get the same encrypted bytes
+	backend := storage.NewMemoryStorage()
+	manager.WithStorage(backend)
+	if err := manager.WriteUsers("users.parquet", users[:10]); err != nil {
+		t.Fatalf("Failed to write encrypted users to storage: %v", err)
+	}
+	if readUsers, err := manager.ReadUsers("users.parquet"); err != nil || len(readUsers) != 10 {
+		t.Errorf("Expected encrypted users to read from storage, got %d (%v)", len(readUsers), err)
+	}
+
+	t.Log("✓ Parquet files are encrypted at rest and decrypted transparently")
+}
+
+func TestSimpleManagerWithChecksums(t *testing.T) {
+	testDir := "tmp/test_checksum_parquet"
+	defer os.RemoveAll(testDir)
+	manager := NewSimpleManager(testDir).WithChecksums(true)
+
+	if err := manager.WriteUsers("users.parquet", createSampleUsers(100)); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	if err := manager.VerifyFile("users.parquet"); err != nil {
+		t.Fatalf("Expected a fresh file to verify: %v", err)
+	}
+	if files, err := manager.ListFiles(); err != nil || len(files) != 1 {
+		t.Errorf("Expected sidecars to be left out of listings, got %v (%v)", files, err)
+	}
+
+	// A flipped byte in a column chunk leaves the row count intact
+	path := filepath.Join(testDir, "users.parquet")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	data[len(data)/3] ^= 0xff
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to corrupt file: %v", err)
+	}
+	var mismatch *checksum.MismatchError
+	if err := manager.VerifyFile("users.parquet"); !errors.As(err, &mismatch) {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+
+	if err := manager.DeleteFile("users.parquet"); err != nil {
+		t.Fatalf("Failed to delete file: %v", err)
+	}
+	if _, err := os.Stat(checksum.SidecarName(path)); !os.IsNotExist(err) {
+		t.Error("Expected the sidecar to be deleted with its file")
+	}
+	if err := NewSimpleManager(testDir).VerifyFile("missing.parquet"); !errors.Is(err, checksum.ErrNoChecksum) {
+		t.Errorf("Expected ErrNoChecksum, got %v", err)
+	}
+
+	t.Log("✓ Checksums are recorded on write and catch corrupted files")
+}
+
+func TestSimpleManagerAtomicWrites(t *testing.T) {
+	testDir := "tmp/test_atomic_parquet"
+	defer os.RemoveAll(testDir)
+	manager := NewSimpleManager(testDir).WithSync(true)
+
+	users := createSampleUsers(50)
+	if err := manager.WriteUsers("users.parquet", users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	path := filepath.Join(testDir, "users.parquet")
+	original, _ := os.ReadFile(path)
+
+	// Fail halfway through replacing the file
+	failure := errors.New("disk full")
+	err := manager.writeFile(t.Context(), "users.parquet", catalog.Entry{}, func(w io.Writer) error {
+		w.Write(original[:len(original)/2])
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Expected the write error, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); !bytes.Equal(data, original) {
+		t.Error("Expected a failed write to leave the previous file intact")
+	}
+	if entries, _ := os.ReadDir(testDir); len(entries) != 1 {
+		t.Errorf("Expected the temporary file to be removed, found %d files", len(entries))
+	}
+
+	// A cancelled write is discarded the same way
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if err := manager.WriteUsersContext(ctx, "users.parquet", createSampleUsers(10)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled write to fail, got %v", err)
+	}
+
+	readBack, err := manager.ReadUsers("users.parquet")
+	if err != nil || len(readBack) != len(users) {
+		t.Errorf("Expected %d users after failed writes, got %d (%v)", len(users), len(readBack), err)
+	}
+
+	t.Log("✓ Failed writes never leave truncated files")
+}
@@ -0,0 +1,158 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/segmentio/parquet-go"
+)
+
+// DefaultBatchSize is the number of rows a streaming reader decodes at once
+// when no batch size is given
+const DefaultBatchSize = 1024
+
+// RowReader iterates the rows of a Parquet file, decoding batchSize rows at a
+// time so memory stays bounded however large the file is. Files read through
+// a storage backend are still downloaded whole before decoding.
+//
+//	reader, err := manager.NewUserReader("users.parquet", 0)
+//	if err != nil {
+//		return err
+//	}
+//	defer reader.Close()
+//	for reader.Next() {
+//		user := reader.Value()
+//	}
+//	return reader.Err()
+type RowReader[T any] struct {
+	ctx    context.Context
+	file   readerAtCloser
+	reader *parquet.GenericReader[T]
+	batch  []T
+	pos    int
+	n      int
+	value  T
+	eof    bool
+	err    error
+}
+
+// UserReader iterates the users of a Parquet file
+type UserReader = RowReader[User]
+
+// OpenRowReader opens filename for streaming reads of T. A batch size of zero
+// or less uses DefaultBatchSize
+func OpenRowReader[T any](m *SimpleManager, filename string, batchSize int) (*RowReader[T], error) {
+	return OpenRowReaderContext[T](context.Background(), m, filename, batchSize)
+}
+
+// OpenRowReaderContext is OpenRowReader for a reader that stops with
+// ctx.Err() once ctx is done
+func OpenRowReaderContext[T any](ctx context.Context, m *SimpleManager, filename string, batchSize int) (*RowReader[T], error) {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	file, _, err := m.openFile(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	return &RowReader[T]{
+		ctx:    ctx,
+		file:   file,
+		reader: parquet.NewGenericReader[T](file),
+		batch:  make([]T, batchSize),
+	}, nil
+}
+
+// NewUserReader opens filename for streaming user reads
+func (m *SimpleManager) NewUserReader(filename string, batchSize int) (*UserReader, error) {
+	return OpenRowReader[User](m, filename, batchSize)
+}
+
+// ReadUsersBatched calls fn with successive batches of at most batchSize users.
+// The slice is reused between calls, so fn must copy any users it keeps.
+// An error from fn stops the read and is returned as is
+func (m *SimpleManager) ReadUsersBatched(filename string, batchSize int, fn func([]User) error) error {
+	return m.ReadUsersBatchedContext(context.Background(), filename, batchSize, fn)
+}
+
+// ReadUsersBatchedContext is ReadUsersBatched, stopping with ctx.Err() between
+// batches once ctx is done
+func (m *SimpleManager) ReadUsersBatchedContext(ctx context.Context, filename string, batchSize int, fn func([]User) error) error {
+	reader, err := OpenRowReaderContext[User](ctx, m, filename, batchSize)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for reader.fill() {
+		if err := fn(reader.batch[:reader.n]); err != nil {
+			return err
+		}
+		reader.pos = reader.n
+	}
+	return reader.Err()
+}
+
+// NumRows returns the number of rows in the file
+func (r *RowReader[T]) NumRows() int64 {
+	return r.reader.NumRows()
+}
+
+// Next advances to the next row and reports whether there is one
+func (r *RowReader[T]) Next() bool {
+	if r.pos >= r.n && !r.fill() {
+		var zero T
+		r.value = zero
+		return false
+	}
+	r.value = r.batch[r.pos]
+	r.pos++
+	return true
+}
+
+// Value returns the row Next advanced to
+func (r *RowReader[T]) Value() T {
+	return r.value
+}
+
+// Err returns the error that ended the iteration, or nil at the end of the file
+func (r *RowReader[T]) Err() error {
+	return r.err
+}
+
+// Close releases the reader and the underlying file
+func (r *RowReader[T]) Close() error {
+	readErr := r.reader.Close()
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	if readErr != nil {
+		return fmt.Errorf("failed to close reader: %w", readErr)
+	}
+	return nil
+}
+
+// fill decodes the next batch and reports whether it holds any rows
+func (r *RowReader[T]) fill() bool {
+	for !r.eof && r.err == nil {
+		if err := r.ctx.Err(); err != nil {
+			r.err = err
+			return false
+		}
+		// Zero the batch so rows from the previous one do not share nested
+		// values with the rows decoded into the same slots
+		clear(r.batch)
+		n, err := r.reader.Read(r.batch)
+		r.pos, r.n = 0, n
+		if err == io.EOF {
+			r.eof = true
+		} else if err != nil {
+			r.err = fmt.Errorf("failed to read rows: %w", err)
+		}
+		if n > 0 {
+			return true
+		}
+	}
+	return false
+}
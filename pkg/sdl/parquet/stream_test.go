@@ -0,0 +1,111 @@
+package parquet
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestUserReader(t *testing.T) {
+	testDir := "tmp/test_stream_reader"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+
+	users := createVariedUsers(2500)
+	if err := manager.WriteUsersWithOptions("users.parquet", users, WriterOptions{RowGroupSize: 1000}); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+
+	reader, err := manager.NewUserReader("users.parquet", 300)
+	if err != nil {
+		t.Fatalf("Failed to open reader: %v", err)
+	}
+	if reader.NumRows() != int64(len(users)) {
+		t.Errorf("Expected %d rows, got %d", len(users), reader.NumRows())
+	}
+
+	var read []User
+	for reader.Next() {
+		read = append(read, reader.Value())
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatalf("Iteration failed: %v", err)
+	}
+	if err := reader.Close(); err != nil {
+		t.Errorf("Failed to close reader: %v", err)
+	}
+
+	if len(read) != len(users) {
+		t.Fatalf("Expected %d users, got %d", len(users), len(read))
+	}
+	// Values kept across batches must not be overwritten by later batches
+	for i, user := range read {
+		want := users[i]
+		if user.ID != want.ID || user.Status != want.Status {
+			t.Fatalf("User %d: expected id=%d status=%s, got id=%d status=%s", i, want.ID, want.Status, user.ID, user.Status)
+		}
+		if (want.Profile.Address == nil) != (user.Profile.Address == nil) {
+			t.Fatalf("User %d: address presence mismatch", i)
+		}
+		if want.Profile.Address != nil && user.Profile.Address.Country != want.Profile.Address.Country {
+			t.Fatalf("User %d: expected country %s, got %s", i, want.Profile.Address.Country, user.Profile.Address.Country)
+		}
+	}
+
+	if reader.Next() {
+		t.Error("Expected Next to stay false after the end of the file")
+	}
+
+	t.Logf("✓ Streamed %d users in batches of 300", len(read))
+}
+
+func TestReadUsersBatched(t *testing.T) {
+	testDir := "tmp/test_stream_batched"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+
+	users := createVariedUsers(2500)
+	if err := manager.WriteUsersWithOptions("users.parquet", users, WriterOptions{RowGroupSize: 1000}); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+
+	total, batches := 0, 0
+	var lastID int64
+	err := manager.ReadUsersBatched("users.parquet", 700, func(batch []User) error {
+		if len(batch) == 0 || len(batch) > 700 {
+			t.Errorf("Unexpected batch size %d", len(batch))
+		}
+		for _, user := range batch {
+			if user.ID != lastID+1 {
+				t.Fatalf("Expected id %d, got %d", lastID+1, user.ID)
+			}
+			lastID = user.ID
+		}
+		total += len(batch)
+		batches++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Batched read failed: %v", err)
+	}
+	if total != len(users) || batches < 4 {
+		t.Errorf("Expected %d users in at least 4 batches, got %d in %d", len(users), total, batches)
+	}
+
+	// An error from the callback stops the read
+	stop := errors.New("stop")
+	calls := 0
+	err = manager.ReadUsersBatched("users.parquet", 700, func([]User) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Expected the callback error after 1 call, got %v after %d", err, calls)
+	}
+
+	if err := manager.ReadUsersBatched("missing.parquet", 0, func([]User) error { return nil }); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+
+	t.Logf("✓ Read %d users in %d batches", total, batches)
+}
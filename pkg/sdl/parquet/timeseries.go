@@ -0,0 +1,260 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WriteTimeSeries writes time series points to a Parquet file
+func (m *SimpleManager) WriteTimeSeries(filename string, points []TimeSeriesData) error {
+	return writeRows(context.Background(), m, filename, points)
+}
+
+// WriteTimeSeriesContext writes time series points to a Parquet file,
+// stopping with ctx.Err() once ctx is done
+func (m *SimpleManager) WriteTimeSeriesContext(ctx context.Context, filename string, points []TimeSeriesData) error {
+	return writeRows(ctx, m, filename, points)
+}
+
+// ReadTimeSeries reads time series points from a Parquet file
+func (m *SimpleManager) ReadTimeSeries(filename string) ([]TimeSeriesData, error) {
+	return readRows[TimeSeriesData](context.Background(), m, filename)
+}
+
+// ReadTimeSeriesContext reads time series points from a Parquet file,
+// stopping with ctx.Err() once ctx is done
+func (m *SimpleManager) ReadTimeSeriesContext(ctx context.Context, filename string) ([]TimeSeriesData, error) {
+	return readRows[TimeSeriesData](ctx, m, filename)
+}
+
+// RollupFunc combines the values of one series within a bucket
+type RollupFunc int
+
+const (
+	RollupAvg RollupFunc = iota
+	RollupSum
+	RollupMax
+	RollupMin
+)
+
+func (f RollupFunc) String() string {
+	switch f {
+	case RollupAvg:
+		return "avg"
+	case RollupSum:
+		return "sum"
+	case RollupMax:
+		return "max"
+	case RollupMin:
+		return "min"
+	default:
+		return fmt.Sprintf("RollupFunc(%d)", int(f))
+	}
+}
+
+// RollupLevel downsamples points into buckets of Interval
+type RollupLevel struct {
+	Interval time.Duration
+	// Func combines metrics without an entry in Metrics
+	Func    RollupFunc
+	Metrics map[string]RollupFunc
+}
+
+// funcFor returns the function combining a metric
+func (l RollupLevel) funcFor(metric string) RollupFunc {
+	if f, ok := l.Metrics[metric]; ok {
+		return f
+	}
+	return l.Func
+}
+
+// Name labels the level by its interval, such as "1m", "5m" or "1h"
+func (l RollupLevel) Name() string {
+	switch d := l.Interval; {
+	case d%time.Hour == 0:
+		return strconv.FormatInt(int64(d/time.Hour), 10) + "h"
+	case d%time.Minute == 0:
+		return strconv.FormatInt(int64(d/time.Minute), 10) + "m"
+	case d%time.Second == 0:
+		return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+	default:
+		return d.String()
+	}
+}
+
+// StandardRollups returns the 1m, 5m and 1h levels combining every metric with fn
+func StandardRollups(fn RollupFunc) []RollupLevel {
+	return []RollupLevel{
+		{Interval: time.Minute, Func: fn},
+		{Interval: 5 * time.Minute, Func: fn},
+		{Interval: time.Hour, Func: fn},
+	}
+}
+
+// validate checks the interval and every function of the level
+func (l RollupLevel) validate() error {
+	if l.Interval <= 0 {
+		return fmt.Errorf("rollup interval must be positive, got %s", l.Interval)
+	}
+	if l.Func < RollupAvg || l.Func > RollupMin {
+		return fmt.Errorf("unsupported rollup function %s", l.Func)
+	}
+	for metric, f := range l.Metrics {
+		if f < RollupAvg || f > RollupMin {
+			return fmt.Errorf("unsupported rollup function %s for metric %s", f, metric)
+		}
+	}
+	return nil
+}
+
+// rollupBucket accumulates one series within one bucket
+type rollupBucket struct {
+	series string
+	point  TimeSeriesData
+	sum    float64
+	count  int
+}
+
+// Rollup downsamples points into one point per series and bucket, stamped
+// with the bucket start. A series is a metric name with its tags, user ID
+// and session ID. Points come back ordered by bucket, then series
+func Rollup(points []TimeSeriesData, level RollupLevel) ([]TimeSeriesData, error) {
+	if err := level.validate(); err != nil {
+		return nil, err
+	}
+
+	buckets := make(map[string]*rollupBucket)
+	var ordered []*rollupBucket
+	for _, p := range points {
+		start := p.Timestamp.UTC().Truncate(level.Interval)
+		series := seriesKey(p)
+		key := strconv.FormatInt(start.UnixNano(), 10) + "|" + series
+
+		b, ok := buckets[key]
+		if !ok {
+			b = &rollupBucket{series: series, point: TimeSeriesData{
+				Timestamp:  start,
+				MetricName: p.MetricName,
+				Value:      p.Value,
+				Tags:       maps.Clone(p.Tags),
+				UserID:     p.UserID,
+				SessionID:  p.SessionID,
+			}}
+			buckets[key] = b
+			ordered = append(ordered, b)
+		}
+		b.sum += p.Value
+		b.count++
+
+		switch level.funcFor(p.MetricName) {
+		case RollupMax:
+			b.point.Value = max(b.point.Value, p.Value)
+		case RollupMin:
+			b.point.Value = min(b.point.Value, p.Value)
+		}
+	}
+
+	slices.SortStableFunc(ordered, func(x, y *rollupBucket) int {
+		if c := x.point.Timestamp.Compare(y.point.Timestamp); c != 0 {
+			return c
+		}
+		return strings.Compare(x.series, y.series)
+	})
+	out := make([]TimeSeriesData, len(ordered))
+	for i, b := range ordered {
+		switch level.funcFor(b.point.MetricName) {
+		case RollupAvg:
+			b.point.Value = b.sum / float64(b.count)
+		case RollupSum:
+			b.point.Value = b.sum
+		}
+		out[i] = b.point
+	}
+	return out, nil
+}
+
+// seriesKey identifies the series of a point
+func seriesKey(p TimeSeriesData) string {
+	var b strings.Builder
+	b.WriteString(strconv.Quote(p.MetricName))
+	for _, k := range slices.Sorted(maps.Keys(p.Tags)) {
+		b.WriteString(strconv.Quote(k))
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(p.Tags[k]))
+	}
+	b.WriteString(strconv.FormatInt(p.UserID, 10))
+	b.WriteString(strconv.Quote(p.SessionID))
+	return b.String()
+}
+
+// WriteRollups downsamples points at each level and writes every level to
+// "<prefix>_<level>.parquet", returning the filenames in level order. Each
+// level is computed from points rather than the level before, so averages
+// stay exact; sums, maxima and minima match a level-by-level chain
+func (m *SimpleManager) WriteRollups(prefix string, points []TimeSeriesData, levels ...RollupLevel) ([]string, error) {
+	return m.WriteRollupsContext(context.Background(), prefix, points, levels...)
+}
+
+// WriteRollupsContext downsamples and writes points like WriteRollups,
+// stopping with ctx.Err() once ctx is done
+func (m *SimpleManager) WriteRollupsContext(ctx context.Context, prefix string, points []TimeSeriesData, levels ...RollupLevel) ([]string, error) {
+	files := make([]string, 0, len(levels))
+	for _, level := range levels {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		rolled, err := Rollup(points, level)
+		if err != nil {
+			return nil, err
+		}
+		filename := fmt.Sprintf("%s_%s.parquet", prefix, level.Name())
+		if err := m.WriteTimeSeriesContext(ctx, filename, rolled); err != nil {
+			return nil, fmt.Errorf("failed to write %s rollup: %w", level.Name(), err)
+		}
+		files = append(files, filename)
+	}
+	return files, nil
+}
+
+// RollupTimeSeries reads the points of src, downsamples them at level and
+// writes the result to dst
+func (m *SimpleManager) RollupTimeSeries(src, dst string, level RollupLevel) (int, error) {
+	points, err := m.ReadTimeSeries(src)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read time series: %w", err)
+	}
+	rolled, err := Rollup(points, level)
+	if err != nil {
+		return 0, err
+	}
+	if err := m.WriteTimeSeries(dst, rolled); err != nil {
+		return 0, fmt.Errorf("failed to write time series: %w", err)
+	}
+	return len(rolled), nil
+}
+
+// AnalyticsTimeSeries turns each metric of each event into a point tagged
+// with the event type and platform
+func AnalyticsTimeSeries(events []Analytics) []TimeSeriesData {
+	var points []TimeSeriesData
+	for _, e := range events {
+		tags := map[string]string{"event_type": e.EventType}
+		if e.DeviceInfo != nil {
+			tags["platform"] = e.DeviceInfo.Platform
+		}
+		for _, name := range slices.Sorted(maps.Keys(e.Metrics)) {
+			points = append(points, TimeSeriesData{
+				Timestamp:  e.Timestamp,
+				MetricName: name,
+				Value:      e.Metrics[name],
+				Tags:       maps.Clone(tags),
+			})
+		}
+	}
+	return points
+}
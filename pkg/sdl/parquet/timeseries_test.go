@@ -0,0 +1,165 @@
+package parquet
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// minutePoints returns two hours of one point per 10 seconds for a latency
+// and a requests series, the latter tagged by region
+func minutePoints() []TimeSeriesData {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	var points []TimeSeriesData
+	for i := 0; i < 720; i++ {
+		ts := start.Add(time.Duration(i) * 10 * time.Second)
+		points = append(points,
+			TimeSeriesData{Timestamp: ts, MetricName: "latency_ms", Value: float64(i % 60)},
+			TimeSeriesData{Timestamp: ts, MetricName: "requests", Value: 1, Tags: map[string]string{"region": []string{"eu", "us"}[i%2]}},
+		)
+	}
+	return points
+}
+
+func TestTimeSeriesRoundTrip(t *testing.T) {
+	testDir := "tmp/test_timeseries"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+
+	points := minutePoints()[:10]
+	points[1].UserID, points[1].SessionID = 42, "session_1"
+	if err := manager.WriteTimeSeries("series.parquet", points); err != nil {
+		t.Fatalf("Failed to write time series: %v", err)
+	}
+	back, err := manager.ReadTimeSeries("series.parquet")
+	if err != nil {
+		t.Fatalf("Failed to read time series: %v", err)
+	}
+	if len(back) != len(points) {
+		t.Fatalf("Expected %d points, got %d", len(points), len(back))
+	}
+	for i := range points {
+		if !back[i].Timestamp.Equal(points[i].Timestamp) || back[i].MetricName != points[i].MetricName ||
+			back[i].Value != points[i].Value || back[i].Tags["region"] != points[i].Tags["region"] ||
+			back[i].UserID != points[i].UserID || back[i].SessionID != points[i].SessionID {
+			t.Errorf("Point %d: expected %+v, got %+v", i, points[i], back[i])
+		}
+	}
+
+	t.Log("✓ Time series points round trip through Parquet")
+}
+
+func TestRollup(t *testing.T) {
+	points := minutePoints()
+
+	level := RollupLevel{Interval: time.Minute, Func: RollupAvg, Metrics: map[string]RollupFunc{"requests": RollupSum}}
+	minutes, err := Rollup(points, level)
+	if err != nil {
+		t.Fatalf("Rollup failed: %v", err)
+	}
+	// 120 minutes of latency plus requests in two regions
+	if len(minutes) != 120*3 {
+		t.Fatalf("Expected 360 one-minute points, got %d", len(minutes))
+	}
+	for i, p := range minutes {
+		if i > 0 && p.Timestamp.Before(minutes[i-1].Timestamp) {
+			t.Fatalf("Points out of order at %d", i)
+		}
+		if p.Timestamp.Second() != 0 {
+			t.Fatalf("Expected minute-aligned timestamps, got %s", p.Timestamp)
+		}
+		switch p.MetricName {
+		case "requests":
+			if p.Value != 3 {
+				t.Errorf("Expected 3 requests per region and minute, got %v at %s", p.Value, p.Timestamp)
+			}
+		case "latency_ms":
+			// Six samples a minute: i%60 for i = 6k..6k+5
+			k := p.Timestamp.Sub(points[0].Timestamp) / time.Minute
+			first := float64((6 * int(k)) % 60)
+			if want := first + 2.5; p.Value != want {
+				t.Errorf("Expected average latency %v at %s, got %v", want, p.Timestamp, p.Value)
+			}
+		}
+	}
+
+	hours, err := Rollup(points, RollupLevel{Interval: time.Hour, Func: RollupMax})
+	if err != nil {
+		t.Fatalf("Hourly rollup failed: %v", err)
+	}
+	if len(hours) != 2*3 {
+		t.Fatalf("Expected 6 hourly points, got %d", len(hours))
+	}
+	for _, p := range hours {
+		if p.MetricName == "latency_ms" && p.Value != 59 {
+			t.Errorf("Expected maximum latency 59, got %v", p.Value)
+		}
+	}
+
+	if _, err := Rollup(points, RollupLevel{}); err == nil {
+		t.Error("Expected a zero interval to be rejected")
+	}
+	if _, err := Rollup(points, RollupLevel{Interval: time.Minute, Func: RollupFunc(9)}); err == nil {
+		t.Error("Expected an unknown function to be rejected")
+	}
+
+	t.Log("✓ Rollup downsamples series with avg, sum and max")
+}
+
+func TestWriteRollups(t *testing.T) {
+	testDir := "tmp/test_rollups"
+	manager := NewSimpleManager(testDir)
+	defer os.RemoveAll(testDir)
+
+	points := minutePoints()
+	files, err := manager.WriteRollups("metrics", points, StandardRollups(RollupSum)...)
+	if err != nil {
+		t.Fatalf("Failed to write rollups: %v", err)
+	}
+	want := []string{"metrics_1m.parquet", "metrics_5m.parquet", "metrics_1h.parquet"}
+	if len(files) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, files)
+	}
+
+	// Every level keeps the total, and each has fewer points than the last
+	var total float64
+	for _, p := range points {
+		total += p.Value
+	}
+	previous := len(points)
+	for i, filename := range files {
+		if filename != want[i] {
+			t.Errorf("Expected %s, got %s", want[i], filename)
+		}
+		rolled, err := manager.ReadTimeSeries(filename)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", filename, err)
+		}
+		var sum float64
+		for _, p := range rolled {
+			sum += p.Value
+		}
+		if sum != total || len(rolled) >= previous {
+			t.Errorf("%s: expected total %v in fewer than %d points, got %v in %d", filename, total, previous, sum, len(rolled))
+		}
+		previous = len(rolled)
+	}
+
+	// A rollup of a rollup file matches the direct rollup for sums
+	n, err := manager.RollupTimeSeries("metrics_5m.parquet", "metrics_1h_chained.parquet", RollupLevel{Interval: time.Hour, Func: RollupSum})
+	if err != nil {
+		t.Fatalf("Failed to roll up the 5m file: %v", err)
+	}
+	direct, _ := manager.ReadTimeSeries("metrics_1h.parquet")
+	chained, _ := manager.ReadTimeSeries("metrics_1h_chained.parquet")
+	if n != len(direct) || len(chained) != len(direct) {
+		t.Fatalf("Expected %d chained points, got %d", len(direct), n)
+	}
+	for i := range direct {
+		if chained[i].Value != direct[i].Value || !chained[i].Timestamp.Equal(direct[i].Timestamp) {
+			t.Errorf("Point %d: chained %+v, direct %+v", i, chained[i], direct[i])
+		}
+	}
+
+	t.Log("✓ Rollup levels write 1m, 5m and 1h files")
+}
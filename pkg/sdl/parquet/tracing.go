@@ -0,0 +1,15 @@
+package parquet
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"go-transport-prac/internal/tracing"
+)
+
+// startSpan starts a span for a whole-file write or read of T rows, labelled
+// like the metrics with format "parquet" and the record name
+func startSpan[T any](ctx context.Context, name, filename string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, name, "parquet", recordName[T](), tracing.File(filename))
+}
@@ -0,0 +1,361 @@
+package parquet
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	"go-transport-prac/internal/types"
+)
+
+// Transform is one step of the ETL transform stage
+type Transform interface {
+	Name() string
+	Apply(users []User) ([]User, error)
+}
+
+// Derivation declares that a transform computes the output column from the
+// input columns, as dotted paths such as profile.first_name
+type Derivation struct {
+	Transform string
+	Output    string
+	Inputs    []string
+}
+
+// LineageDeclarer is implemented by transforms that declare the columns they
+// derive, so they appear in the column lineage committed with the ETL output
+type LineageDeclarer interface {
+	Lineage() []Derivation
+}
+
+// TransformFunc adapts a function to a Transform
+type TransformFunc struct {
+	name string
+	fn   func(users []User) ([]User, error)
+}
+
+// NewTransformFunc creates a transform named name running fn
+func NewTransformFunc(name string, fn func(users []User) ([]User, error)) *TransformFunc {
+	return &TransformFunc{name: name, fn: fn}
+}
+
+// Name returns the transform name
+func (t *TransformFunc) Name() string {
+	return t.name
+}
+
+// Apply runs the function
+func (t *TransformFunc) Apply(users []User) ([]User, error) {
+	return t.fn(users)
+}
+
+// Metric names recorded for every transform step, labelled with step
+const (
+	// TransformDuration is a latency histogram in seconds
+	TransformDuration = "etl_transform_duration_seconds"
+	// TransformRecords counts the records each step returned
+	TransformRecords = "etl_transform_records_total"
+	// TransformErrors counts failed steps
+	TransformErrors = "etl_transform_errors_total"
+)
+
+// Names of the built-in transforms
+const (
+	TransformNormalizeStatus = "normalize_status"
+	TransformNormalizePhone  = "normalize_phone"
+	TransformSplitName       = "split_name"
+	TransformStamp           = "stamp_transformed"
+	TransformQualityScore    = "quality_score"
+)
+
+// StepStats reports one step of a transform run
+type StepStats struct {
+	Name       string
+	RecordsIn  int
+	RecordsOut int
+	Duration   time.Duration
+}
+
+// TransformRegistry runs registered transforms in ascending order. Steps
+// registered with the same order run in registration order
+type TransformRegistry struct {
+	mu      sync.RWMutex
+	steps   []registeredTransform
+	metrics types.MetricsCollector
+}
+
+type registeredTransform struct {
+	transform Transform
+	order     int
+}
+
+// NewTransformRegistry creates an empty registry
+func NewTransformRegistry() *TransformRegistry {
+	return &TransformRegistry{}
+}
+
+// DefaultTransforms creates a registry of the built-in steps at orders 100
+// (status), 200 (phone), 300 (name), 400 (timestamp) and 500 (quality score),
+// leaving room for custom steps in between. A nil scorer scores with
+// DefaultQualityRules
+func DefaultTransforms(clock types.Clock, scorer QualityScorer) *TransformRegistry {
+	return NewTransformRegistry().
+		Register(NormalizeStatus(), 100).
+		Register(NormalizePhone(), 200).
+		Register(SplitName(), 300).
+		Register(StampTransformed(clock), 400).
+		Register(ScoreQuality(scorer), 500)
+}
+
+// WithMetrics records the duration, output records and errors of every step
+func (r *TransformRegistry) WithMetrics(collector types.MetricsCollector) *TransformRegistry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = collector
+	return r
+}
+
+// Register adds a transform to run at order. A transform registered under
+// an existing name replaces it and takes the new order
+func (r *TransformRegistry) Register(transform Transform, order int) *TransformRegistry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.remove(transform.Name())
+	r.steps = append(r.steps, registeredTransform{transform: transform, order: order})
+	sort.SliceStable(r.steps, func(i, j int) bool { return r.steps[i].order < r.steps[j].order })
+	return r
+}
+
+// Unregister removes the transform named name, reporting whether it was registered
+func (r *TransformRegistry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.remove(name)
+}
+
+func (r *TransformRegistry) remove(name string) bool {
+	for i, step := range r.steps {
+		if step.transform.Name() == name {
+			r.steps = append(r.steps[:i], r.steps[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the names of the registered transforms in the order they run
+func (r *TransformRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, len(r.steps))
+	for i, step := range r.steps {
+		names[i] = step.transform.Name()
+	}
+	return names
+}
+
+// Transforms returns the registered transforms in the order they run
+func (r *TransformRegistry) Transforms() []Transform {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	transforms := make([]Transform, len(r.steps))
+	for i, step := range r.steps {
+		transforms[i] = step.transform
+	}
+	return transforms
+}
+
+// Apply runs every transform on a copy of users, so steps may modify the
+// users and their profiles in place. It stops at the first failing step
+func (r *TransformRegistry) Apply(users []User) ([]User, []StepStats, error) {
+	r.mu.RLock()
+	steps := append([]registeredTransform(nil), r.steps...)
+	collector := r.metrics
+	r.mu.RUnlock()
+
+	users = cloneUsers(users)
+	stats := make([]StepStats, 0, len(steps))
+	for _, step := range steps {
+		name := step.transform.Name()
+		start := time.Now()
+		out, err := step.transform.Apply(users)
+		elapsed := time.Since(start)
+
+		tags := map[string]string{"step": name}
+		if collector != nil {
+			collector.Timer(TransformDuration, tags, elapsed)
+		}
+		if err != nil {
+			if collector != nil {
+				collector.Counter(TransformErrors, tags, 1)
+			}
+			return nil, stats, fmt.Errorf("transform %s failed: %w", name, err)
+		}
+		if collector != nil {
+			collector.Counter(TransformRecords, tags, float64(len(out)))
+		}
+
+		stats = append(stats, StepStats{Name: name, RecordsIn: len(users), RecordsOut: len(out), Duration: elapsed})
+		users = out
+	}
+	return users, stats, nil
+}
+
+// cloneUsers copies users with their own profiles and metadata
+func cloneUsers(users []User) []User {
+	cloned := make([]User, len(users))
+	for i, user := range users {
+		if user.Profile != nil {
+			profile := *user.Profile
+			profile.Metadata = maps.Clone(profile.Metadata)
+			user.Profile = &profile
+		}
+		cloned[i] = user
+	}
+	return cloned
+}
+
+// metadataOf returns the profile metadata of user, creating it if missing
+func metadataOf(user *User) map[string]string {
+	profile := profileOf(user)
+	if profile.Metadata == nil {
+		profile.Metadata = make(map[string]string)
+	}
+	return profile.Metadata
+}
+
+// builtinTransform is a built-in step modifying each user in place
+type builtinTransform struct {
+	name    string
+	lineage []Derivation
+	apply   func(user *User)
+}
+
+func (t *builtinTransform) Name() string {
+	return t.name
+}
+
+func (t *builtinTransform) Apply(users []User) ([]User, error) {
+	for i := range users {
+		t.apply(&users[i])
+	}
+	return users, nil
+}
+
+func (t *builtinTransform) Lineage() []Derivation {
+	return t.lineage
+}
+
+// NormalizeStatus lowercases active, inactive and suspended statuses and
+// marks any other status unknown
+func NormalizeStatus() Transform {
+	return &builtinTransform{
+		name: TransformNormalizeStatus,
+		lineage: []Derivation{
+			{Transform: "normalize_status", Output: "status", Inputs: []string{"status"}},
+			{Transform: "constant", Output: "profile.metadata.status_normalized"},
+		},
+		apply: func(user *User) {
+			switch user.Status {
+			case "ACTIVE", "Active", "active":
+				user.Status = "active"
+			case "INACTIVE", "Inactive", "inactive":
+				user.Status = "inactive"
+			case "SUSPENDED", "Suspended", "suspended":
+				user.Status = "suspended"
+			default:
+				user.Status = "unknown"
+			}
+			metadataOf(user)["status_normalized"] = "true"
+		},
+	}
+}
+
+// NormalizePhone adds the US country code to local phone numbers
+func NormalizePhone() Transform {
+	return &builtinTransform{
+		name:    TransformNormalizePhone,
+		lineage: []Derivation{{Transform: "normalize_phone", Output: "profile.phone", Inputs: []string{"profile.phone"}}},
+		apply: func(user *User) {
+			if user.Profile != nil && user.Profile.Phone != "" {
+				user.Profile.Phone = normalizePhoneNumber(user.Profile.Phone)
+			}
+		},
+	}
+}
+
+// SplitName fills in missing first and last names from the full name
+func SplitName() Transform {
+	return &builtinTransform{
+		name: TransformSplitName,
+		lineage: []Derivation{
+			{Transform: "split", Output: "profile.first_name", Inputs: []string{"name"}},
+			{Transform: "split", Output: "profile.last_name", Inputs: []string{"name"}},
+		},
+		apply: func(user *User) {
+			profile := profileOf(user)
+			if profile.FirstName != "" {
+				return
+			}
+			parts := splitFullName(user.Name)
+			if len(parts) > 0 {
+				profile.FirstName = parts[0]
+			}
+			if len(parts) > 1 {
+				profile.LastName = parts[1]
+			}
+		},
+	}
+}
+
+// StampTransformed records when each user was transformed, as read from clock
+func StampTransformed(clock types.Clock) Transform {
+	clock = types.ClockOrSystem(clock)
+	return &builtinTransform{
+		name:    TransformStamp,
+		lineage: []Derivation{{Transform: "now", Output: "profile.metadata.transformed"}},
+		apply: func(user *User) {
+			metadataOf(user)["transformed"] = clock.Now().Format(time.RFC3339)
+		},
+	}
+}
+
+// QualityScorer scores users for the quality score step
+type QualityScorer interface {
+	Score(user User) RecordQuality
+	// Fields returns the fields the score is computed from
+	Fields() []string
+}
+
+// ScoreQuality records the data quality score of each user, as computed by
+// scorer or DefaultQualityRules when nil; it should run after the steps that
+// fill in the scored fields
+func ScoreQuality(scorer QualityScorer) Transform {
+	if scorer == nil {
+		scorer = defaultQualityEngine
+	}
+	return &qualityTransform{scorer: scorer}
+}
+
+// qualityTransform declares its lineage from the fields the scorer reads,
+// which change with the rules
+type qualityTransform struct {
+	scorer QualityScorer
+}
+
+func (t *qualityTransform) Name() string {
+	return TransformQualityScore
+}
+
+func (t *qualityTransform) Apply(users []User) ([]User, error) {
+	for i := range users {
+		metadataOf(&users[i])["quality_score"] = fmt.Sprintf("%.2f", t.scorer.Score(users[i]).Score)
+	}
+	return users, nil
+}
+
+func (t *qualityTransform) Lineage() []Derivation {
+	return []Derivation{{Transform: "quality_score", Output: "profile.metadata.quality_score", Inputs: t.scorer.Fields()}}
+}
@@ -1,11 +1,21 @@
 package parquet
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
+
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/catalog"
+	"go-transport-prac/pkg/sdl/commit"
+	"go-transport-prac/pkg/sdl/deadletter"
+	"go-transport-prac/pkg/sdl/lineage"
 )
 
 // DataPipeline demonstrates a complete data processing workflow using Parquet
@@ -14,24 +24,136 @@ type DataPipeline struct {
 	inputDir    string
 	outputDir   string
 	processedDir string
+	clock        types.Clock
+	// lineage records the column lineage of the ETL run in progress
+	lineage *lineage.Recorder
+	// source replaces the built-in sample users as the ETL input when set
+	source      func() ([]User, error)
+	checkpoints CheckpointStore
+	transforms  *TransformRegistry
+	quality     *QualityEngine
+	// table also receives the loaded users when set
+	table *UserTable
+	// batchWorkers is the number of batches processed at once
+	batchWorkers int
 }
 
 // NewDataPipeline creates a new data processing pipeline
 func NewDataPipeline(baseDir string) *DataPipeline {
-	return &DataPipeline{
-		manager:      NewSimpleManager(filepath.Join(baseDir, "data")),
+	dp := &DataPipeline{
+		manager:      NewSimpleManager(filepath.Join(baseDir, "data")).WithChecksums(true),
 		inputDir:     filepath.Join(baseDir, "input"),
 		outputDir:    filepath.Join(baseDir, "output"),
 		processedDir: filepath.Join(baseDir, "processed"),
+		clock:        types.SystemClock{},
+		checkpoints:  NewFileCheckpointStore(filepath.Join(baseDir, "processed", "users_etl.checkpoint.json")),
+		quality:      defaultQualityEngine,
+		batchWorkers: 1,
+	}
+	dp.transforms = DefaultTransforms(pipelineClock{dp}, pipelineQuality{dp})
+
+	// The catalog of the data directory persists across runs, so cleanup
+	// also finds files written by earlier ones
+	catalogPath := filepath.Join(dp.manager.baseDir, catalogFile)
+	files, err := catalog.Open(context.Background(), catalog.NewJSONStore(catalogPath))
+	if err != nil {
+		log.Printf("Warning: failed to open %s, cataloguing in memory: %v", catalogPath, err)
+		files = catalog.New()
+	}
+	dp.manager.WithCatalog(files.WithClock(pipelineClock{dp}))
+	return dp
+}
+
+// catalogFile names the catalog kept in the pipeline's data directory
+const catalogFile = "catalog.json"
+
+// WithCatalog replaces the catalog of the pipeline's data directory; c must
+// not be nil
+func (dp *DataPipeline) WithCatalog(c *catalog.Catalog) *DataPipeline {
+	dp.manager.WithCatalog(c)
+	return dp
+}
+
+// Catalog returns the catalog of the files in the pipeline's data directory
+func (dp *DataPipeline) Catalog() *catalog.Catalog {
+	return dp.manager.Catalog()
+}
+
+// pipelineClock reads the pipeline's current clock, so built-in steps follow WithClock
+type pipelineClock struct {
+	dp *DataPipeline
+}
+
+func (c pipelineClock) Now() time.Time {
+	return c.dp.clock.Now()
+}
+
+// pipelineQuality scores with the pipeline's current quality engine, so the
+// built-in step follows WithQualityEngine
+type pipelineQuality struct {
+	dp *DataPipeline
+}
+
+func (q pipelineQuality) Score(user User) RecordQuality {
+	return q.dp.quality.Score(user)
+}
+
+func (q pipelineQuality) Fields() []string {
+	return q.dp.quality.Fields()
+}
+
+// WithClock sets the clock used for generated timestamps and output file names
+func (dp *DataPipeline) WithClock(clock types.Clock) *DataPipeline {
+	dp.clock = types.ClockOrSystem(clock)
+	return dp
+}
+
+// WithSource sets the function the ETL extracts users from
+func (dp *DataPipeline) WithSource(source func() ([]User, error)) *DataPipeline {
+	dp.source = source
+	return dp
+}
+
+// WithCheckpointStore sets where incremental ETL runs keep their checkpoint;
+// by default it is a file in the processed directory
+func (dp *DataPipeline) WithCheckpointStore(store CheckpointStore) *DataPipeline {
+	dp.checkpoints = store
+	return dp
+}
+
+// WithQualityEngine sets the rules records are scored and verified against;
+// nil restores DefaultQualityRules
+func (dp *DataPipeline) WithQualityEngine(engine *QualityEngine) *DataPipeline {
+	if engine == nil {
+		engine = defaultQualityEngine
 	}
+	dp.quality = engine
+	return dp
+}
+
+// WithDecodeMode sets how batch files are read back: in deadletter.Tolerant
+// mode rows that fail to decode are sent to letters and the batch goes on
+// with the rest
+func (dp *DataPipeline) WithDecodeMode(mode deadletter.Mode, letters *deadletter.File) *DataPipeline {
+	dp.manager.WithDecodeMode(mode, letters)
+	return dp
+}
+
+// Transforms returns the registry of transform steps, where custom steps are
+// registered, replaced or removed
+func (dp *DataPipeline) Transforms() *TransformRegistry {
+	return dp.transforms
 }
 
 // RunETLWorkflow demonstrates an ETL (Extract, Transform, Load) workflow
 func (dp *DataPipeline) RunETLWorkflow() error {
 	fmt.Println("=== ETL Workflow with Parquet ===")
+
+	runID := dp.clock.Now().Format("20060102_150405")
+	dp.lineage = lineage.NewRecorder("users_etl", runID, "raw_users", processedDataset).WithClock(dp.clock)
 	
 	// 1. Extract: Generate sample data (simulating data extraction)
-	rawUsers, err := dp.extractUserData()
+	rawUsers, err := dp.extract()
 	if err != nil {
 		return fmt.Errorf("extraction failed: %w", err)
 	}
@@ -45,7 +167,7 @@ func (dp *DataPipeline) RunETLWorkflow() error {
 	fmt.Printf("✓ Transformed %d user records\n", len(transformedUsers))
 	
 	// 3. Load: Save to Parquet format
-	if err := dp.loadUserData(transformedUsers); err != nil {
+	if err := dp.loadUserData(transformedUsers, nil); err != nil {
 		return fmt.Errorf("loading failed: %w", err)
 	}
 	fmt.Printf("✓ Loaded data to Parquet format\n")
@@ -59,6 +181,20 @@ func (dp *DataPipeline) RunETLWorkflow() error {
 	return nil
 }
 
+// extract reads users from the configured source, or the built-in sample
+func (dp *DataPipeline) extract() ([]User, error) {
+	if dp.source != nil {
+		users, err := dp.source()
+		if summary, ok := deadletter.Partial(err); ok {
+			// A tolerant extractor skipped malformed rows; go on with the rest
+			fmt.Printf("  ⚠ Skipped %d malformed rows: %v\n", summary.Failed, summary.First)
+			return users, nil
+		}
+		return users, err
+	}
+	return dp.extractUserData()
+}
+
 // extractUserData simulates extracting data from various sources
 func (dp *DataPipeline) extractUserData() ([]User, error) {
 	// Simulate data from different sources with varying quality
@@ -79,7 +215,19 @@ func (dp *DataPipeline) extractUserData() ([]User, error) {
 	}
 	
 	users := make([]User, len(rawData))
-	now := time.Now()
+	now := dp.clock.Now()
+
+	dp.recordLineage("extract", "copy", "id", "id")
+	dp.recordLineage("extract", "copy", "email", "email")
+	dp.recordLineage("extract", "default_name", "name", "name", "id")
+	dp.recordLineage("extract", "copy", "status", "status")
+	dp.recordLineage("extract", "copy", "profile.phone", "phone")
+	dp.recordLineage("extract", "copy", "profile.address.city", "city")
+	dp.recordLineage("extract", "copy", "profile.address.country", "country")
+	dp.recordLineage("extract", "constant", "profile.metadata.source")
+	dp.recordLineage("extract", "now", "profile.metadata.extracted")
+	dp.recordLineage("extract", "now", "created_at")
+	dp.recordLineage("extract", "now", "updated_at")
 	
 	for i, raw := range rawData {
 		// Convert raw data to User struct (minimal transformation here)
@@ -112,68 +260,43 @@ func (dp *DataPipeline) extractUserData() ([]User, error) {
 	return users, nil
 }
 
-// transformUserData cleans and enhances the extracted data
+// transformUserData cleans and enhances the extracted data with the registered transforms
 func (dp *DataPipeline) transformUserData(users []User) ([]User, error) {
 	fmt.Println("Applying data transformations...")
-	
-	transformed := make([]User, len(users))
-	
-	for i, user := range users {
-		// Copy the user
-		transformed[i] = user
-		
-		// 1. Normalize status values
-		switch user.Status {
-		case "ACTIVE", "Active", "active":
-			transformed[i].Status = "active"
-		case "INACTIVE", "Inactive", "inactive":
-			transformed[i].Status = "inactive"
-		case "SUSPENDED", "Suspended", "suspended":
-			transformed[i].Status = "suspended"
-		default:
-			transformed[i].Status = "unknown"
-		}
-		
-		// 2. Normalize phone numbers
-		if user.Profile != nil && user.Profile.Phone != "" {
-			transformed[i].Profile.Phone = dp.normalizePhoneNumber(user.Profile.Phone)
-		}
-		
-		// 3. Add computed fields
-		if transformed[i].Profile == nil {
-			transformed[i].Profile = &Profile{}
-		}
-		
-		if transformed[i].Profile.Metadata == nil {
-			transformed[i].Profile.Metadata = make(map[string]string)
-		}
-		
-		// Add transformation metadata
-		transformed[i].Profile.Metadata["transformed"] = time.Now().Format(time.RFC3339)
-		transformed[i].Profile.Metadata["status_normalized"] = "true"
-		
-		// 4. Extract name parts if available
-		if transformed[i].Profile.FirstName == "" && transformed[i].Name != "" {
-			parts := dp.splitFullName(transformed[i].Name)
-			transformed[i].Profile.FirstName = parts[0]
-			if len(parts) > 1 {
-				transformed[i].Profile.LastName = parts[1]
+
+	for _, transform := range dp.transforms.Transforms() {
+		if declarer, ok := transform.(LineageDeclarer); ok {
+			for _, d := range declarer.Lineage() {
+				dp.recordLineage("transform", d.Transform, d.Output, d.Inputs...)
 			}
 		}
-		
-		// 5. Add data quality scores
-		qualityScore := dp.calculateDataQuality(transformed[i])
-		transformed[i].Profile.Metadata["quality_score"] = fmt.Sprintf("%.2f", qualityScore)
 	}
-	
-	fmt.Printf("  - Normalized %d status values\n", len(transformed))
-	fmt.Printf("  - Enhanced %d user profiles\n", len(transformed))
-	
+
+	transformed, steps, err := dp.transforms.Apply(users)
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range steps {
+		fmt.Printf("  - %s: %d → %d records in %s\n", step.Name, step.RecordsIn, step.RecordsOut, step.Duration)
+	}
+
 	return transformed, nil
 }
 
+// recordLineage records a transform stage when an ETL run is tracking lineage
+func (dp *DataPipeline) recordLineage(stage, transform, output string, inputs ...string) {
+	if dp.lineage != nil {
+		dp.lineage.Record(stage, transform, output, inputs...)
+	}
+}
+
 // normalizePhoneNumber normalizes phone number format
 func (dp *DataPipeline) normalizePhoneNumber(phone string) string {
+	return normalizePhoneNumber(phone)
+}
+
+// normalizePhoneNumber adds the US country code to local numbers
+func normalizePhoneNumber(phone string) string {
 	// Simple normalization - in real world this would be more sophisticated
 	if len(phone) > 0 && phone[0] != '+' {
 		// Add country code for US numbers
@@ -186,6 +309,11 @@ func (dp *DataPipeline) normalizePhoneNumber(phone string) string {
 
 // splitFullName splits full name into parts
 func (dp *DataPipeline) splitFullName(fullName string) []string {
+	return splitFullName(fullName)
+}
+
+// splitFullName returns the first two words of a full name
+func splitFullName(fullName string) []string {
 	// Simple split - real implementation would handle edge cases
 	parts := []string{}
 	if fullName != "" {
@@ -218,89 +346,225 @@ func (dp *DataPipeline) splitFullName(fullName string) []string {
 
 // calculateDataQuality calculates a data quality score (0-1)
 func (dp *DataPipeline) calculateDataQuality(user User) float64 {
-	score := 0.0
-	maxScore := 10.0
-	
-	// Check required fields
-	if user.ID > 0 {
-		score += 2.0
+	return dp.quality.Score(user).Score
+}
+
+// processedDataset names the committed ETL output in the output directory
+const processedDataset = "users_processed"
+
+// QualityReport summarizes the data quality of a loaded batch
+type QualityReport struct {
+	Records           int       `json:"records"`
+	AverageQuality    float64   `json:"averageQuality"`
+	MinQuality        float64   `json:"minQuality"`
+	LowQualityRecords int       `json:"lowQualityRecords"`
+	DataFile          string    `json:"dataFile"`
+	GeneratedAt       time.Time `json:"generatedAt"`
+	// FailedRules counts the records failing each rule
+	FailedRules map[string]int `json:"failedRules,omitempty"`
+	// Failures lists the records that failed any rule
+	Failures []RecordQuality `json:"failures,omitempty"`
+}
+
+// qualityReport scores every user for the report committed next to the data
+func (dp *DataPipeline) qualityReport(users []User, dataFile string) QualityReport {
+	report := QualityReport{
+		Records:     len(users),
+		MinQuality:  1.0,
+		DataFile:    dataFile,
+		GeneratedAt: dp.clock.Now(),
 	}
-	if user.Email != "" {
-		score += 2.0
+
+	total := 0.0
+	for _, user := range users {
+		result := dp.quality.Score(user)
+		total += result.Score
+		if result.Score < report.MinQuality {
+			report.MinQuality = result.Score
+		}
+		if dp.quality.LowQuality(result.Score) {
+			report.LowQualityRecords++
+		}
+		if len(result.FailedRules) == 0 {
+			continue
+		}
+		if report.FailedRules == nil {
+			report.FailedRules = make(map[string]int)
+		}
+		for _, rule := range result.FailedRules {
+			report.FailedRules[rule]++
+		}
+		report.Failures = append(report.Failures, result)
 	}
-	if user.Name != "" {
-		score += 1.0
+	if len(users) > 0 {
+		report.AverageQuality = total / float64(len(users))
 	}
-	if user.Status != "unknown" {
-		score += 1.0
+
+	return report
+}
+
+// loadUserData saves transformed data and its quality report to Parquet.
+// Both files are published in one commit so readers never see one without the other;
+// an incremental run commits its checkpoint with them.
+func (dp *DataPipeline) loadUserData(users []User, checkpoint *Checkpoint) error {
+	committer := commit.NewCommitter(dp.outputDir, processedDataset).WithClock(dp.clock)
+
+	// Clear leftovers from a previous load that crashed before committing
+	if err := committer.Recover(); err != nil {
+		return fmt.Errorf("failed to recover output directory: %w", err)
 	}
-	
-	// Check profile completeness
-	if user.Profile != nil {
-		if user.Profile.FirstName != "" {
-			score += 1.0
+
+	txn, err := committer.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin commit: %w", err)
+	}
+	defer txn.Abort()
+
+	// Save to Parquet with timestamp
+	timestamp := dp.clock.Now().Format("20060102_150405")
+	filename := fmt.Sprintf("%s_%s.parquet", processedDataset, timestamp)
+
+	stagingManager := NewSimpleManager(txn.Dir()).WithChecksums(true)
+	if err := stagingManager.WriteUsers(filename, users); err != nil {
+		return err
+	}
+
+	report, err := json.MarshalIndent(dp.qualityReport(users, filename), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal quality report: %w", err)
+	}
+	if err := txn.WriteFile("quality_report.json", report); err != nil {
+		return err
+	}
+
+	if dp.lineage != nil {
+		graph, err := json.MarshalIndent(dp.lineage.Graph(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal lineage: %w", err)
 		}
-		if user.Profile.LastName != "" {
-			score += 1.0
+		if err := txn.WriteFile(lineageFile, graph); err != nil {
+			return err
 		}
-		if user.Profile.Phone != "" {
-			score += 1.0
+	}
+
+	if checkpoint != nil {
+		data, err := json.MarshalIndent(checkpoint, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal checkpoint: %w", err)
 		}
-		if user.Profile.Address != nil && user.Profile.Address.Country != "" {
-			score += 1.0
+		if err := txn.WriteFile(checkpointFile, data); err != nil {
+			return err
 		}
 	}
-	
-	return score / maxScore
+
+	// The database goes first: once the checkpoint is committed these users
+	// are never extracted again, while upserting them twice is harmless
+	if dp.table != nil {
+		if err := dp.table.UpsertUsers(context.Background(), users); err != nil {
+			return fmt.Errorf("failed to load users into the database: %w", err)
+		}
+	}
+
+	if _, err := txn.Commit(); err != nil {
+		return fmt.Errorf("failed to commit output: %w", err)
+	}
+	return nil
 }
 
-// loadUserData saves transformed data to Parquet
-func (dp *DataPipeline) loadUserData(users []User) error {
-	// Create output directory
-	if err := os.MkdirAll(dp.outputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+// lineageFile names the column lineage graph committed with the ETL output
+const lineageFile = "lineage.json"
+
+// CommittedLineage returns the column lineage of the last committed ETL run
+func (dp *DataPipeline) CommittedLineage() (*lineage.Graph, error) {
+	manifest, err := commit.ReadManifest(dp.outputDir, processedDataset)
+	if err != nil {
+		return nil, fmt.Errorf("no committed output found: %w", err)
 	}
-	
-	// Save to Parquet with timestamp
-	timestamp := time.Now().Format("20060102_150405")
-	filename := fmt.Sprintf("users_processed_%s.parquet", timestamp)
-	
-	outputManager := NewSimpleManager(dp.outputDir)
-	return outputManager.WriteUsers(filename, users)
+	path, ok := manifest.Path(lineageFile)
+	if !ok {
+		return nil, fmt.Errorf("lineage missing from commit")
+	}
+	return lineage.ReadFile(path)
 }
 
-// verifyLoadedData reads back and validates the loaded data
+// committedOutput returns the committed data file path and quality report
+func (dp *DataPipeline) committedOutput() (string, QualityReport, error) {
+	var report QualityReport
+
+	manifest, err := commit.ReadManifest(dp.outputDir, processedDataset)
+	if err != nil {
+		return "", report, fmt.Errorf("no committed output found: %w", err)
+	}
+	if err := manifest.Verify(); err != nil {
+		return "", report, fmt.Errorf("committed output is corrupt: %w", err)
+	}
+
+	reportPath, ok := manifest.Path("quality_report.json")
+	if !ok {
+		return "", report, fmt.Errorf("quality report missing from commit")
+	}
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		return "", report, fmt.Errorf("failed to read quality report: %w", err)
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return "", report, fmt.Errorf("failed to parse quality report: %w", err)
+	}
+
+	dataPath, ok := manifest.Path(report.DataFile)
+	if !ok {
+		return "", report, fmt.Errorf("data file %s missing from commit", report.DataFile)
+	}
+	return dataPath, report, nil
+}
+
+// verifyLoadedData reads back and validates the committed data
 func (dp *DataPipeline) verifyLoadedData() error {
-	outputManager := NewSimpleManager(dp.outputDir)
-	outputFiles, err := outputManager.ListFiles()
+	dataPath, report, err := dp.committedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to list output files: %w", err)
+		return err
 	}
-	
-	if len(outputFiles) == 0 {
-		return fmt.Errorf("no output files found")
+
+	outputManager := NewSimpleManager(filepath.Dir(dataPath))
+	if err := outputManager.VerifyFile(filepath.Base(dataPath)); err != nil {
+		return fmt.Errorf("failed to verify data file: %w", err)
 	}
-	
-	// Verify the most recent file
-	latestFile := outputFiles[len(outputFiles)-1]
-	users, err := outputManager.ReadUsers(latestFile)
+	users, err := outputManager.ReadUsers(filepath.Base(dataPath))
 	if err != nil {
 		return fmt.Errorf("failed to read back data: %w", err)
 	}
+	if len(users) != report.Records {
+		return fmt.Errorf("read %d records but quality report covers %d", len(users), report.Records)
+	}
+	if err := dp.verifyTable(users); err != nil {
+		return err
+	}
 	
-	// Validate data quality
+	// Validate data quality against the thresholds of the rules
 	totalQuality := 0.0
+	lowQuality := 0
 	for _, user := range users {
 		quality := dp.calculateDataQuality(user)
 		totalQuality += quality
+		if dp.quality.LowQuality(quality) {
+			lowQuality++
+		}
 	}
 	
 	avgQuality := totalQuality / float64(len(users))
+	lowRatio := float64(lowQuality) / float64(len(users))
 	fmt.Printf("  - Validated %d records\n", len(users))
 	fmt.Printf("  - Average data quality: %.2f\n", avgQuality)
+	for _, rule := range slices.Sorted(maps.Keys(report.FailedRules)) {
+		fmt.Printf("  - Rule %s failed for %d records\n", rule, report.FailedRules[rule])
+	}
 	
-	if avgQuality < 0.7 {
-		return fmt.Errorf("data quality too low: %.2f < 0.7", avgQuality)
+	thresholds := dp.quality.Rules()
+	if avgQuality < thresholds.MinAverage {
+		return fmt.Errorf("data quality too low: %.2f < %.2f", avgQuality, thresholds.MinAverage)
+	}
+	if lowRatio > thresholds.MaxLowQualityRatio {
+		return fmt.Errorf("too many low quality records: %.2f > %.2f", lowRatio, thresholds.MaxLowQualityRatio)
 	}
 	
 	return nil
@@ -308,35 +572,57 @@ func (dp *DataPipeline) verifyLoadedData() error {
 
 // RunBatchProcessing demonstrates batch processing workflow
 func (dp *DataPipeline) RunBatchProcessing() error {
+	_, err := dp.RunBatchProcessingContext(context.Background())
+	return err
+}
+
+// RunBatchProcessingContext runs the batch processing workflow with the
+// workers set by WithBatchWorkers, stopping once ctx is done
+func (dp *DataPipeline) RunBatchProcessingContext(ctx context.Context) (BatchStats, error) {
 	fmt.Println("=== Batch Processing Workflow ===")
 	
 	// Create multiple batches of data
 	batchSize := 1000
 	numBatches := 5
 	
-	fmt.Printf("Processing %d batches of %d records each...\n", numBatches, batchSize)
+	fmt.Printf("Processing %d batches of %d records each with %d workers...\n", numBatches, batchSize, dp.batchWorkers)
+	runStart := dp.clock.Now()
 	
-	for batch := 0; batch < numBatches; batch++ {
-		// Generate batch data
-		users := dp.generateBatchData(batch, batchSize)
-		
-		// Process batch
-		filename := fmt.Sprintf("batch_%03d.parquet", batch)
-		if err := dp.manager.WriteUsers(filename, users); err != nil {
-			return fmt.Errorf("failed to write batch %d: %w", batch, err)
-		}
-		
-		fmt.Printf("  ✓ Processed batch %d: %d records\n", batch, len(users))
+	batches, err := dp.processBatches(ctx, numBatches, batchSize)
+	if err != nil {
+		return batches, err
+	}
+	fmt.Printf("  ✓ Processed %d records in %v\n", batches.Records, batches.Duration)
+	
+	// Parquet files cannot be appended to, so the batches are compacted
+	// into as few files as the target size allows
+	compactCtx := catalog.WithTags(ctx, map[string]string{"stage": compactedStage})
+	stats, err := dp.manager.NewUserCompactor().WithDeleteInputs(true).CompactContext(compactCtx, batches.Files, "users_compacted")
+	if err != nil {
+		return batches, fmt.Errorf("failed to compact batches: %w", err)
+	}
+	fmt.Printf("  ✓ Compacted %d batch files into %d (%d duplicate rows dropped)\n",
+		len(stats.Inputs), len(stats.Outputs), stats.Duplicates)
+	if stats.DeadLettered > 0 {
+		fmt.Printf("  ⚠ Skipped %d rows that failed to decode\n", stats.DeadLettered)
 	}
 	
-	// Aggregate results
-	return dp.aggregateBatches()
+	// Aggregate the files this run compacted, as catalogued
+	compacted := dp.Catalog().List(catalog.Query{
+		Entity: recordName[User](),
+		From:   runStart,
+		Tags:   map[string]string{"stage": compactedStage},
+	})
+	return batches, dp.aggregateBatches(compacted)
 }
 
+// compactedStage tags the catalog entries of compacted batch files
+const compactedStage = "compacted"
+
 // generateBatchData creates sample data for batch processing
 func (dp *DataPipeline) generateBatchData(batchNum, size int) []User {
 	users := make([]User, size)
-	baseTime := time.Now().Add(-time.Duration(batchNum*24) * time.Hour)
+	baseTime := dp.clock.Now().Add(-time.Duration(batchNum*24) * time.Hour)
 	
 	for i := 0; i < size; i++ {
 		userID := int64(batchNum*size + i + 1)
@@ -364,55 +650,62 @@ func (dp *DataPipeline) generateBatchData(batchNum, size int) []User {
 				},
 			},
 			CreatedAt: baseTime.Add(time.Duration(i) * time.Minute),
-			UpdatedAt: time.Now(),
+			UpdatedAt: dp.clock.Now(),
 		}
 	}
 	
 	return users
 }
 
-// aggregateBatches combines all batch files into summary statistics
-func (dp *DataPipeline) aggregateBatches() error {
+// aggregateBatches combines the compacted batch files into summary statistics
+func (dp *DataPipeline) aggregateBatches(entries []catalog.Entry) error {
 	fmt.Println("Aggregating batch results...")
 	
-	files, err := dp.manager.ListFiles()
+	batchFiles := catalog.Files(entries)
+	if len(batchFiles) == 0 {
+		return fmt.Errorf("no compacted batches in the catalog")
+	}
+	for _, filename := range batchFiles {
+		if err := dp.manager.VerifyFile(filename); err != nil {
+			return fmt.Errorf("failed to verify batch: %w", err)
+		}
+	}
+	
+	// Footer metadata gives row counts and column statistics without decoding
+	_, summary, err := NewFileInspector(dp.manager).InspectAll(batchFiles...)
 	if err != nil {
-		return fmt.Errorf("failed to list files: %w", err)
+		return fmt.Errorf("failed to inspect batches: %w", err)
 	}
 	
-	totalUsers := 0
-	statusCounts := make(map[string]int)
-	countryCounts := make(map[string]int)
+	// Only the aggregated columns are decoded; no User structs are built
+	statuses, err := Agg().GroupBy("status").Count().Run(dp.manager, batchFiles...)
+	if err != nil {
+		return fmt.Errorf("failed to aggregate statuses: %w", err)
+	}
+	countries, err := Agg().GroupBy("profile.address.country").Count().Run(dp.manager, batchFiles...)
+	if err != nil {
+		return fmt.Errorf("failed to aggregate countries: %w", err)
+	}
 	
-	for _, filename := range files {
-		if len(filename) > 5 && filename[:5] == "batch" {
-			users, err := dp.manager.ReadUsers(filename)
-			if err != nil {
-				log.Printf("Warning: failed to read %s: %v", filename, err)
-				continue
-			}
-			
-			totalUsers += len(users)
-			
-			// Aggregate statistics
-			for _, user := range users {
-				statusCounts[user.Status]++
-				if user.Profile != nil && user.Profile.Address != nil {
-					countryCounts[user.Profile.Address.Country]++
-				}
-			}
-		}
+	totalUsers := statuses.Stats.RowsMatched
+	if int64(totalUsers) != summary.Rows {
+		return fmt.Errorf("aggregated %d users but batch footers report %d rows", totalUsers, summary.Rows)
+	}
+	if records, _ := catalog.Totals(entries); records != summary.Rows {
+		return fmt.Errorf("catalog records %d users but batch footers report %d rows", records, summary.Rows)
 	}
 	
 	fmt.Printf("✓ Aggregation complete:\n")
 	fmt.Printf("  - Total users processed: %d\n", totalUsers)
+	fmt.Printf("  - Files: %d, row groups: %d, %d bytes (compression ratio %.2f)\n",
+		summary.Files, summary.RowGroups, summary.FileBytes, summary.CompressionRatio())
 	fmt.Printf("  - Status distribution:\n")
-	for status, count := range statusCounts {
-		fmt.Printf("    %s: %d\n", status, count)
+	for _, row := range statuses.Rows {
+		fmt.Printf("    %s: %d\n", row.Group[0], row.Count)
 	}
 	fmt.Printf("  - Country distribution:\n")
-	for country, count := range countryCounts {
-		fmt.Printf("    %s: %d\n", country, count)
+	for _, row := range countries.Rows {
+		fmt.Printf("    %s: %d\n", row.Group[0], row.Count)
 	}
 	
 	return nil
@@ -422,6 +715,18 @@ func (dp *DataPipeline) aggregateBatches() error {
 func (dp *DataPipeline) CleanupWorkflow() error {
 	fmt.Println("=== Cleaning up workflow files ===")
 	
+	// Catalogued files go through the manager, which also drops their
+	// checksums and entries
+	entries := dp.Catalog().List(catalog.Query{})
+	if len(entries) > 0 {
+		removed, err := dp.deleteCatalogued(entries)
+		if err != nil {
+			log.Printf("Warning: %v", err)
+		}
+		records, bytes := catalog.Totals(removed)
+		fmt.Printf("✓ Deleted %d catalogued files (%d records, %d bytes)\n", len(removed), records, bytes)
+	}
+	
 	dirs := []string{
 		dp.manager.baseDir,
 		dp.inputDir,
@@ -440,8 +745,48 @@ func (dp *DataPipeline) CleanupWorkflow() error {
 	return nil
 }
 
+// CleanupBefore deletes the catalogued data files created before cutoff and
+// returns their entries
+func (dp *DataPipeline) CleanupBefore(cutoff time.Time) ([]catalog.Entry, error) {
+	return dp.deleteCatalogued(dp.Catalog().ListByDateRange(time.Time{}, cutoff))
+}
+
+// deleteCatalogued deletes the files of entries and returns those deleted
+func (dp *DataPipeline) deleteCatalogued(entries []catalog.Entry) ([]catalog.Entry, error) {
+	var removed []catalog.Entry
+	for _, e := range entries {
+		err := dp.manager.DeleteFile(e.File)
+		if os.IsNotExist(err) {
+			// Deleted behind the catalog's back; only the entry is left
+			if err := dp.Catalog().Remove(context.Background(), e.File); err != nil {
+				return removed, err
+			}
+			continue
+		}
+		if err != nil {
+			return removed, fmt.Errorf("failed to delete %s: %w", e.File, err)
+		}
+		removed = append(removed, e)
+	}
+	return removed, nil
+}
+
+// analyticsRollups averages durations and scores and sums values
+var analyticsRollups = []RollupLevel{
+	{Interval: time.Minute, Func: RollupAvg, Metrics: map[string]RollupFunc{"value": RollupSum}},
+	{Interval: 5 * time.Minute, Func: RollupAvg, Metrics: map[string]RollupFunc{"value": RollupSum}},
+	{Interval: time.Hour, Func: RollupAvg, Metrics: map[string]RollupFunc{"value": RollupSum, "score": RollupMax}},
+}
+
 // RunAnalyticsWorkflow demonstrates analytics data processing
 func (dp *DataPipeline) RunAnalyticsWorkflow() error {
+	_, err := dp.RunAnalyticsWorkflowContext(context.Background())
+	return err
+}
+
+// RunAnalyticsWorkflowContext writes generated analytics events and their
+// metric rollups, then reports on the events file, stopping once ctx is done
+func (dp *DataPipeline) RunAnalyticsWorkflowContext(ctx context.Context) (*AnalyticsReport, error) {
 	fmt.Println("=== Analytics Workflow ===")
 	
 	// Generate time-series analytics data
@@ -449,14 +794,27 @@ func (dp *DataPipeline) RunAnalyticsWorkflow() error {
 	
 	// Save analytics data
 	filename := "analytics_data.parquet"
-	if err := dp.writeAnalyticsData(filename, analyticsData); err != nil {
-		return fmt.Errorf("failed to save analytics data: %w", err)
+	if err := dp.manager.WriteAnalyticsContext(ctx, filename, analyticsData); err != nil {
+		return nil, fmt.Errorf("failed to save analytics data: %w", err)
 	}
 	
-	fmt.Printf("✓ Generated %d analytics events\n", len(analyticsData))
+	fmt.Printf("✓ Wrote %d analytics events to %s\n", len(analyticsData), filename)
+	
+	// Downsample the event metrics into 1m, 5m and 1h series
+	files, err := dp.manager.WriteRollupsContext(ctx, "analytics_metrics", AnalyticsTimeSeries(analyticsData), analyticsRollups...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write analytics rollups: %w", err)
+	}
+	for _, rollup := range files {
+		points, err := dp.manager.ReadTimeSeriesContext(ctx, rollup)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", rollup, err)
+		}
+		fmt.Printf("✓ Wrote %d points to %s\n", len(points), rollup)
+	}
 	
 	// Process analytics data
-	return dp.processAnalyticsData(filename)
+	return dp.processAnalyticsData(ctx, filename)
 }
 
 // generateAnalyticsData creates sample analytics events
@@ -464,7 +822,7 @@ func (dp *DataPipeline) generateAnalyticsData(hours, eventsPerHour int) []Analyt
 	totalEvents := hours * eventsPerHour
 	events := make([]Analytics, totalEvents)
 	
-	baseTime := time.Now().Add(-time.Duration(hours) * time.Hour)
+	baseTime := dp.clock.Now().Add(-time.Duration(hours) * time.Hour)
 	eventTypes := []string{"page_view", "click", "purchase", "signup", "logout"}
 	platforms := []string{"web", "mobile", "desktop"}
 	countries := []string{"US", "CA", "GB", "DE", "FR", "JP", "AU"}
@@ -504,22 +862,34 @@ func (dp *DataPipeline) generateAnalyticsData(hours, eventsPerHour int) []Analyt
 	return events
 }
 
-// writeAnalyticsData saves analytics data (simplified version without full manager)
-func (dp *DataPipeline) writeAnalyticsData(filename string, data []Analytics) error {
-	// This is a simplified implementation - in full version we'd use the complete manager
-	fmt.Printf("Writing %d analytics events to %s\n", len(data), filename)
-	return nil
-}
-
 // processAnalyticsData analyzes the analytics data
-func (dp *DataPipeline) processAnalyticsData(filename string) error {
+func (dp *DataPipeline) processAnalyticsData(ctx context.Context, filename string) (*AnalyticsReport, error) {
 	fmt.Println("Processing analytics data...")
 	
-	// Simulate analytics processing
-	fmt.Println("  ✓ Calculated conversion rates")
-	fmt.Println("  ✓ Generated user segments")
-	fmt.Println("  ✓ Computed engagement metrics")
-	fmt.Println("  ✓ Created daily/hourly aggregations")
+	report, err := dp.manager.AnalyzeAnalyticsContext(ctx, filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze analytics data: %w", err)
+	}
+	if report.Events == 0 {
+		return nil, fmt.Errorf("no analytics events in %s", filename)
+	}
 	
-	return nil
-}
\ No newline at end of file
+	fmt.Printf("  ✓ %d events from %d users in %d sessions\n", report.Events, report.Users, report.Sessions)
+	for _, eventType := range ConversionEvents {
+		fmt.Printf("  ✓ %s conversion rate: %.1f%%\n", eventType, report.ConversionRates[eventType]*100)
+	}
+	fmt.Printf("  ✓ Event types:\n")
+	for eventType, count := range report.EventTypes {
+		fmt.Printf("    %s: %d\n", eventType, count)
+	}
+	busiest := report.Hourly[0]
+	for _, h := range report.Hourly {
+		if h.Events > busiest.Events {
+			busiest = h
+		}
+	}
+	fmt.Printf("  ✓ %d hourly aggregations, busiest %s with %d events\n",
+		len(report.Hourly), busiest.Hour.Format(time.RFC3339), busiest.Events)
+	
+	return report, nil
+}
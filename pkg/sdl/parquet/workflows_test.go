@@ -1,8 +1,16 @@
 package parquet
 
 import (
+	"context"
+	"errors"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/commit"
 )
 
 func TestETLWorkflow(t *testing.T) {
@@ -18,6 +26,42 @@ func TestETLWorkflow(t *testing.T) {
 	t.Log("✓ ETL workflow completed successfully")
 }
 
+func TestETLLineage(t *testing.T) {
+	testDir := "tmp/test_etl_lineage"
+	pipeline := NewDataPipeline(testDir).WithClock(testutil.NewDefaultFakeClock())
+	defer pipeline.CleanupWorkflow()
+
+	if err := pipeline.RunETLWorkflow(); err != nil {
+		t.Fatalf("ETL workflow failed: %v", err)
+	}
+
+	graph, err := pipeline.CommittedLineage()
+	if err != nil {
+		t.Fatalf("Failed to read committed lineage: %v", err)
+	}
+	if graph.RunID != "20240101_120000" || graph.OutputDataset != processedDataset {
+		t.Errorf("Unexpected lineage metadata: %+v", graph)
+	}
+
+	firstName, ok := graph.Column("profile.first_name")
+	if !ok {
+		t.Fatal("Expected lineage for profile.first_name")
+	}
+	if !reflect.DeepEqual(firstName.Sources, []string{"id", "name"}) {
+		t.Errorf("Expected first name to derive from name and id, got %v", firstName.Sources)
+	}
+	last := firstName.Transforms[len(firstName.Transforms)-1]
+	if last.Stage != "transform" || last.Name != "split" {
+		t.Errorf("Expected the split transform last, got %+v", last)
+	}
+
+	if phone, _ := graph.Column("profile.phone"); !reflect.DeepEqual(phone.Sources, []string{"phone"}) || len(phone.Transforms) != 2 {
+		t.Errorf("Unexpected phone lineage: %+v", phone)
+	}
+
+	t.Logf("✓ Lineage for %d columns committed with the ETL output", len(graph.Columns))
+}
+
 func TestBatchProcessing(t *testing.T) {
 	testDir := "tmp/test_batch_processing"
 	pipeline := NewDataPipeline(testDir)
@@ -28,6 +72,22 @@ func TestBatchProcessing(t *testing.T) {
 		t.Fatalf("Batch processing failed: %v", err)
 	}
 
+	files, err := pipeline.manager.ListFiles()
+	if err != nil {
+		t.Fatalf("Failed to list batch files: %v", err)
+	}
+	_, summary, err := NewFileInspector(pipeline.manager).InspectAll(files...)
+	if err != nil {
+		t.Fatalf("Failed to inspect batch files: %v", err)
+	}
+	// The five batches are compacted into one file and removed
+	if summary.Files != 1 || summary.Rows != 5000 {
+		t.Errorf("Expected 5000 rows in 1 compacted file, got %d rows in %d", summary.Rows, summary.Files)
+	}
+	if len(files) != 1 || files[0] != "users_compacted_000.parquet" {
+		t.Errorf("Expected only users_compacted_000.parquet, got %v", files)
+	}
+
 	t.Log("✓ Batch processing completed successfully")
 }
 
@@ -36,10 +96,44 @@ func TestAnalyticsWorkflow(t *testing.T) {
 	pipeline := NewDataPipeline(testDir)  
 	defer pipeline.CleanupWorkflow()
 
-	err := pipeline.RunAnalyticsWorkflow()
+	report, err := pipeline.RunAnalyticsWorkflowContext(context.Background())
 	if err != nil {
 		t.Fatalf("Analytics workflow failed: %v", err)
 	}
+	if report.Events != 2400 || report.Sessions != 50 || len(report.Hourly) < 24 {
+		t.Errorf("Expected 2400 events in 50 sessions over 24 hours, got %d in %d over %d", report.Events, report.Sessions, len(report.Hourly))
+	}
+	// Sessions see a single event type each, one in five of them purchase
+	if report.ConversionRates["purchase"] != 0.2 {
+		t.Errorf("Expected a purchase conversion rate of 0.2, got %v", report.ConversionRates["purchase"])
+	}
+
+	hourly, err := pipeline.manager.ReadTimeSeries("analytics_metrics_1h.parquet")
+	if err != nil {
+		t.Fatalf("Failed to read hourly rollup: %v", err)
+	}
+	if len(hourly) == 0 {
+		t.Fatal("Expected hourly rollup points")
+	}
+	for _, p := range hourly {
+		if !p.Timestamp.Equal(p.Timestamp.Truncate(time.Hour)) {
+			t.Fatalf("Expected hourly timestamps, got %s", p.Timestamp)
+		}
+	}
+
+	// A cancelled workflow stops instead of writing and analyzing
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := pipeline.RunAnalyticsWorkflowContext(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled workflow to fail with context.Canceled, got %v", err)
+	}
+	points := AnalyticsTimeSeries(pipeline.generateAnalyticsData(1, 10))
+	if _, err := pipeline.manager.WriteRollupsContext(cancelled, "cancelled_metrics", points, analyticsRollups...); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected cancelled rollups to fail with context.Canceled, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(testDir, "data", "cancelled_metrics_1m.parquet")); !os.IsNotExist(err) {
+		t.Errorf("Expected no rollup written after cancellation, got %v", err)
+	}
 
 	t.Log("✓ Analytics workflow completed successfully")
 }
@@ -180,4 +274,96 @@ func TestNameSplitting(t *testing.T) {
 	}
 
 	t.Log("✓ Name splitting tests passed")
-}
\ No newline at end of file
+}
+
+func TestPipelineWithFakeClock(t *testing.T) {
+	testDir := "tmp/test_pipeline_clock"
+	clock := testutil.NewDefaultFakeClock()
+	pipeline := NewDataPipeline(testDir).WithClock(clock)
+	defer pipeline.CleanupWorkflow()
+
+	first := pipeline.generateBatchData(1, 5)
+	second := pipeline.generateBatchData(1, 5)
+	if !reflect.DeepEqual(first, second) {
+		t.Fatal("Expected identical batches from a frozen clock")
+	}
+
+	wantCreated := testutil.DefaultFakeTime.Add(-24 * time.Hour)
+	if !first[0].CreatedAt.Equal(wantCreated) {
+		t.Errorf("Expected CreatedAt %v, got %v", wantCreated, first[0].CreatedAt)
+	}
+	if !first[0].UpdatedAt.Equal(testutil.DefaultFakeTime) {
+		t.Errorf("Expected UpdatedAt %v, got %v", testutil.DefaultFakeTime, first[0].UpdatedAt)
+	}
+
+	if err := pipeline.RunETLWorkflow(); err != nil {
+		t.Fatalf("ETL workflow failed: %v", err)
+	}
+	dataPath, report, err := pipeline.committedOutput()
+	if err != nil {
+		t.Fatalf("Failed to resolve committed output: %v", err)
+	}
+	if filepath.Base(dataPath) != "users_processed_20240101_120000.parquet" {
+		t.Fatalf("Expected deterministic output file, got %s", dataPath)
+	}
+	if !report.GeneratedAt.Equal(testutil.DefaultFakeTime) {
+		t.Errorf("Expected report timestamp from fake clock, got %v", report.GeneratedAt)
+	}
+
+	t.Log("✓ Pipeline timestamps come from the injected clock")
+}
+
+func TestLoadUserDataCommitsAtomically(t *testing.T) {
+	testDir := "tmp/test_pipeline_commit"
+	clock := testutil.NewDefaultFakeClock()
+	pipeline := NewDataPipeline(testDir).WithClock(clock)
+	defer pipeline.CleanupWorkflow()
+
+	users := createSampleUsers(20)
+	if err := pipeline.loadUserData(users, nil); err != nil {
+		t.Fatalf("First load failed: %v", err)
+	}
+
+	// A second load that dies after staging its data file must not replace the first
+	txn, err := commit.NewCommitter(pipeline.outputDir, processedDataset).Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin commit: %v", err)
+	}
+	if err := NewSimpleManager(txn.Dir()).WriteUsers("users_processed_partial.parquet", users[:5]); err != nil {
+		t.Fatalf("Failed to stage data: %v", err)
+	}
+	// Its process died long ago, leaving the staging directory to nobody
+	crashed := filepath.Join(filepath.Dir(txn.Dir()), processedDataset+"-deadbeef-0123456789abcdef")
+	if err := os.Rename(txn.Dir(), crashed); err != nil {
+		t.Fatalf("Failed to hand over staging directory: %v", err)
+	}
+	txn.Abort()
+	stale := time.Now().Add(-2 * commit.StaleStagingAge)
+	os.Chtimes(crashed, stale, stale)
+
+	dataPath, report, err := pipeline.committedOutput()
+	if err != nil {
+		t.Fatalf("Failed to resolve committed output: %v", err)
+	}
+	if report.Records != 20 || filepath.Base(dataPath) != "users_processed_20240101_120000.parquet" {
+		t.Fatalf("Expected the first commit to stay visible, got %s with %d records", dataPath, report.Records)
+	}
+
+	clock.Advance(time.Hour)
+	if err := pipeline.loadUserData(users[:10], nil); err != nil {
+		t.Fatalf("Second load failed: %v", err)
+	}
+	if _, err := os.Stat(crashed); !os.IsNotExist(err) {
+		t.Errorf("Expected crashed staging directory to be recovered, got %v", err)
+	}
+	if err := pipeline.verifyLoadedData(); err != nil {
+		t.Fatalf("Verification failed: %v", err)
+	}
+
+	_, report, _ = pipeline.committedOutput()
+	if report.Records != 10 || report.DataFile != "users_processed_20240101_130000.parquet" {
+		t.Errorf("Expected second commit to be visible, got %+v", report)
+	}
+
+	t.Log("✓ Data and quality report published together")
+}
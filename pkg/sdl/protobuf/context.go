@@ -0,0 +1,95 @@
+package protobuf
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"go-transport-prac/internal/tracing"
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+// SerializeUserContext serializes a User message to bytes, failing with
+// ctx.Err() when ctx is already done
+func (m *Manager) SerializeUserContext(ctx context.Context, u *user.User) ([]byte, error) {
+	return withContext(ctx, "protobuf.serialize", "user", u, m.SerializeUser)
+}
+
+// DeserializeUserContext deserializes bytes to a User message, failing with
+// ctx.Err() when ctx is already done
+func (m *Manager) DeserializeUserContext(ctx context.Context, data []byte) (*user.User, error) {
+	return withContext(ctx, "protobuf.deserialize", "user", data, m.DeserializeUser)
+}
+
+// SerializeProductContext serializes a Product message to bytes, failing
+// with ctx.Err() when ctx is already done
+func (m *Manager) SerializeProductContext(ctx context.Context, p *product.Product) ([]byte, error) {
+	return withContext(ctx, "protobuf.serialize", "product", p, m.SerializeProduct)
+}
+
+// DeserializeProductContext deserializes bytes to a Product message, failing
+// with ctx.Err() when ctx is already done
+func (m *Manager) DeserializeProductContext(ctx context.Context, data []byte) (*product.Product, error) {
+	return withContext(ctx, "protobuf.deserialize", "product", data, m.DeserializeProduct)
+}
+
+// SerializeOrderContext serializes an Order message to bytes, failing with
+// ctx.Err() when ctx is already done
+func (m *Manager) SerializeOrderContext(ctx context.Context, o *order.Order) ([]byte, error) {
+	return withContext(ctx, "protobuf.serialize", "order", o, m.SerializeOrder)
+}
+
+// DeserializeOrderContext deserializes bytes to an Order message, failing
+// with ctx.Err() when ctx is already done
+func (m *Manager) DeserializeOrderContext(ctx context.Context, data []byte) (*order.Order, error) {
+	return withContext(ctx, "protobuf.deserialize", "order", data, m.DeserializeOrder)
+}
+
+// SerializeContext serializes any message, failing with ctx.Err() when ctx
+// is already done
+func (m *Manager) SerializeContext(ctx context.Context, msg proto.Message) ([]byte, error) {
+	return withContext(ctx, "protobuf.serialize", entityName(msg), msg, m.Serialize)
+}
+
+// DeserializeContext deserializes data into msg, failing with ctx.Err() when
+// ctx is already done
+func (m *Manager) DeserializeContext(ctx context.Context, data []byte, msg proto.Message) error {
+	_, err := withContext(ctx, "protobuf.deserialize", entityName(msg), data, func(data []byte) (proto.Message, error) {
+		return msg, m.Deserialize(data, msg)
+	})
+	return err
+}
+
+// withContext runs fn on in within a span unless ctx is already done. Single
+// messages encode in microseconds, so they are not interrupted once started
+func withContext[In, Out any](ctx context.Context, name, entity string, in In, fn func(In) (Out, error)) (out Out, err error) {
+	ctx, span := tracing.Start(ctx, name, "protobuf", entity)
+	defer func() { tracing.End(span, 1, err) }()
+
+	if err := ctx.Err(); err != nil {
+		var zero Out
+		return zero, err
+	}
+	out, err = fn(in)
+	tracing.SetBytes(ctx, encodedSize(in, out))
+	return out, err
+}
+
+// encodedSize returns the length of whichever of in and out is the encoded form
+func encodedSize(in, out any) int64 {
+	if data, ok := out.([]byte); ok {
+		return int64(len(data))
+	}
+	if data, ok := in.([]byte); ok {
+		return int64(len(data))
+	}
+	return 0
+}
+
+// entityName labels a message span by its message name, e.g. "user"
+func entityName(msg proto.Message) string {
+	return strings.ToLower(string(msg.ProtoReflect().Descriptor().Name()))
+}
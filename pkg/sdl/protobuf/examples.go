@@ -170,8 +170,9 @@ func (e *Examples) SerializationSizeComparison() error {
 
 // createSampleOrder creates a sample order for testing (separate from manager)
 func (e *Examples) createSampleOrder() *order.Order {
-	now := timestamppb.Now()
-	deliveryTime := timestamppb.New(time.Now().Add(5 * 24 * time.Hour))
+	current := e.manager.clock.Now()
+	now := timestamppb.New(current)
+	deliveryTime := timestamppb.New(current.Add(5 * 24 * time.Hour))
 
 	return &order.Order{
 		Id:          1,
@@ -0,0 +1,110 @@
+package protobuf
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// ApplyFieldMask copies the fields named by mask from src into dst, replacing
+// their current values. A masked field that is unset in src is cleared in dst,
+// which gives PATCH semantics: the mask says which fields the request owns.
+//
+// Paths use proto field names separated by dots (e.g. profile.address.city).
+// Only the last segment may name a repeated or map field. A nil or empty mask
+// leaves dst unchanged. All paths are validated before dst is modified.
+func ApplyFieldMask(dst, src proto.Message, mask *fieldmaskpb.FieldMask) error {
+	return applyMask(dst, src, mask, true)
+}
+
+// MergeWithMask merges the fields named by mask from src into dst using
+// proto.Merge semantics: unset fields in src are ignored, nested messages are
+// merged, repeated fields are appended and map entries are added.
+func MergeWithMask(dst, src proto.Message, mask *fieldmaskpb.FieldMask) error {
+	return applyMask(dst, src, mask, false)
+}
+
+func applyMask(dst, src proto.Message, mask *fieldmaskpb.FieldMask, replace bool) error {
+	if dst == nil || src == nil {
+		return fmt.Errorf("messages cannot be nil")
+	}
+
+	dstMsg, srcMsg := dst.ProtoReflect(), src.ProtoReflect()
+	if dstMsg.Descriptor().FullName() != srcMsg.Descriptor().FullName() {
+		return fmt.Errorf("message type mismatch: %s and %s",
+			dstMsg.Descriptor().FullName(), srcMsg.Descriptor().FullName())
+	}
+	if len(mask.GetPaths()) == 0 {
+		return nil
+	}
+
+	// Normalize sorts the paths and drops ones covered by a parent path
+	normalized := &fieldmaskpb.FieldMask{Paths: append([]string(nil), mask.GetPaths()...)}
+	normalized.Normalize()
+
+	paths := make([][]protoreflect.FieldDescriptor, 0, len(normalized.Paths))
+	for _, path := range normalized.Paths {
+		fields, err := resolvePath(dstMsg.Descriptor(), path)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, fields)
+	}
+
+	for _, fields := range paths {
+		applyPath(dstMsg, srcMsg, fields, replace)
+	}
+	return nil
+}
+
+// resolvePath maps a dotted path to the field descriptors along it
+func resolvePath(md protoreflect.MessageDescriptor, path string) ([]protoreflect.FieldDescriptor, error) {
+	segments := strings.Split(path, ".")
+	fields := make([]protoreflect.FieldDescriptor, 0, len(segments))
+
+	for i, name := range segments {
+		if md == nil {
+			return nil, fmt.Errorf("invalid field mask path %q: %s is not a message field", path, segments[i-1])
+		}
+		fd := md.Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			return nil, fmt.Errorf("invalid field mask path %q: %s has no field %q", path, md.FullName(), name)
+		}
+		if i < len(segments)-1 && (fd.IsList() || fd.IsMap()) {
+			return nil, fmt.Errorf("invalid field mask path %q: cannot traverse repeated field %s", path, name)
+		}
+		fields = append(fields, fd)
+		md = fd.Message()
+	}
+	return fields, nil
+}
+
+// applyPath walks down to the last field of the path and copies it from src
+func applyPath(dst, src protoreflect.Message, fields []protoreflect.FieldDescriptor, replace bool) {
+	fd := fields[0]
+
+	if len(fields) == 1 {
+		if replace {
+			dst.Clear(fd)
+		}
+		if !src.Has(fd) {
+			return
+		}
+		// Merging a message holding only this field deep-copies the value
+		only := dst.New()
+		only.Set(fd, src.Get(fd))
+		proto.Merge(dst.Interface(), only.Interface())
+		return
+	}
+
+	if !src.Has(fd) {
+		// Nothing to merge, and nothing to clear unless dst has the parent
+		if !replace || !dst.Has(fd) {
+			return
+		}
+	}
+	applyPath(dst.Mutable(fd).Message(), src.Get(fd).Message(), fields[1:], replace)
+}
@@ -0,0 +1,178 @@
+package protobuf
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+func maskOf(paths ...string) *fieldmaskpb.FieldMask {
+	return &fieldmaskpb.FieldMask{Paths: paths}
+}
+
+func TestApplyFieldMask_NestedPath(t *testing.T) {
+	stored := NewManager().CreateSampleUser()
+	original := proto.Clone(stored).(*user.User)
+
+	patch := &user.User{
+		Name: "Ignored Name",
+		Profile: &user.Profile{
+			Phone:   "+1-555-9999",
+			Address: &user.Address{City: "Boston", Country: "Ignored"},
+		},
+	}
+
+	if err := ApplyFieldMask(stored, patch, maskOf("profile.address.city")); err != nil {
+		t.Fatalf("Failed to apply field mask: %v", err)
+	}
+
+	if stored.Profile.Address.City != "Boston" {
+		t.Errorf("City not updated: got %s", stored.Profile.Address.City)
+	}
+
+	// Everything outside the mask is untouched
+	expected := proto.Clone(original).(*user.User)
+	expected.Profile.Address.City = "Boston"
+	if !proto.Equal(stored, expected) {
+		t.Errorf("Fields outside the mask changed:\ngot  %v\nwant %v", stored, expected)
+	}
+
+	// The patch must not share memory with the stored message
+	patch.Profile.Address.City = "Changed"
+	if stored.Profile.Address.City != "Boston" {
+		t.Error("Stored message aliases the patch")
+	}
+
+	t.Log("✓ Nested path updated without touching sibling fields")
+}
+
+func TestApplyFieldMask_ClearsUnsetFields(t *testing.T) {
+	stored := NewManager().CreateSampleUser()
+	stored.Profile.Interests = []string{"go", "music"}
+
+	patch := &user.User{
+		Email:   "new@example.com",
+		Profile: &user.Profile{Interests: []string{"climbing"}},
+	}
+
+	mask := maskOf("email", "profile.phone", "profile.interests", "profile.address.state")
+	if err := ApplyFieldMask(stored, patch, mask); err != nil {
+		t.Fatalf("Failed to apply field mask: %v", err)
+	}
+
+	if stored.Email != "new@example.com" {
+		t.Errorf("Email not updated: got %s", stored.Email)
+	}
+	if stored.Profile.Phone != "" {
+		t.Errorf("Expected phone to be cleared, got %s", stored.Profile.Phone)
+	}
+	if stored.Profile.Address.State != "" || stored.Profile.Address.City == "" {
+		t.Errorf("Expected only state cleared, got %v", stored.Profile.Address)
+	}
+	if len(stored.Profile.Interests) != 1 || stored.Profile.Interests[0] != "climbing" {
+		t.Errorf("Expected interests to be replaced, got %v", stored.Profile.Interests)
+	}
+
+	t.Log("✓ Masked fields unset in the patch are cleared")
+}
+
+func TestApplyFieldMask_ParentPathCoversChildren(t *testing.T) {
+	stored := NewManager().CreateSampleUser()
+	patch := &user.User{Profile: &user.Profile{FirstName: "Only"}}
+
+	if err := ApplyFieldMask(stored, patch, maskOf("profile.address.city", "profile")); err != nil {
+		t.Fatalf("Failed to apply field mask: %v", err)
+	}
+	if !proto.Equal(stored.Profile, patch.Profile) {
+		t.Errorf("Expected the whole profile to be replaced, got %v", stored.Profile)
+	}
+
+	// Clearing a nested field under an absent parent does not create the parent
+	empty := &user.User{Id: 7}
+	if err := ApplyFieldMask(empty, &user.User{}, maskOf("profile.address.city")); err != nil {
+		t.Fatalf("Failed to apply field mask: %v", err)
+	}
+	if empty.Profile != nil {
+		t.Errorf("Expected profile to stay unset, got %v", empty.Profile)
+	}
+
+	t.Log("✓ Parent paths replace the whole sub-message")
+}
+
+func TestMergeWithMask(t *testing.T) {
+	stored := NewManager().CreateSampleUser()
+	stored.Profile.Interests = []string{"go"}
+	stored.Profile.Metadata = map[string]string{"tier": "gold"}
+	phone := stored.Profile.Phone
+
+	patch := &user.User{
+		Profile: &user.Profile{
+			Interests: []string{"music"},
+			Metadata:  map[string]string{"locale": "en"},
+			Address:   &user.Address{City: "Boston"},
+		},
+	}
+
+	mask := maskOf("profile.phone", "profile.interests", "profile.metadata", "profile.address")
+	if err := MergeWithMask(stored, patch, mask); err != nil {
+		t.Fatalf("Failed to merge with mask: %v", err)
+	}
+
+	if stored.Profile.Phone != phone {
+		t.Errorf("Expected unset phone to be ignored, got %s", stored.Profile.Phone)
+	}
+	if len(stored.Profile.Interests) != 2 {
+		t.Errorf("Expected interests to be appended, got %v", stored.Profile.Interests)
+	}
+	if stored.Profile.Metadata["tier"] != "gold" || stored.Profile.Metadata["locale"] != "en" {
+		t.Errorf("Expected metadata entries to be merged, got %v", stored.Profile.Metadata)
+	}
+	if stored.Profile.Address.City != "Boston" || stored.Profile.Address.Street == "" {
+		t.Errorf("Expected address to be merged, got %v", stored.Profile.Address)
+	}
+
+	t.Log("✓ Merge keeps existing values for unset fields")
+}
+
+func TestFieldMask_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		dst  proto.Message
+		src  proto.Message
+		mask *fieldmaskpb.FieldMask
+	}{
+		{"unknown field", &user.User{}, &user.User{}, maskOf("profile.nickname")},
+		{"through scalar", &user.User{}, &user.User{}, maskOf("name.first")},
+		{"through repeated", &user.User{}, &user.User{}, maskOf("profile.interests.length")},
+		{"type mismatch", &user.User{}, &product.Product{}, maskOf("name")},
+		{"nil message", nil, &user.User{}, maskOf("name")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ApplyFieldMask(tt.dst, tt.src, tt.mask); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+
+	// A bad path is rejected before any valid path is applied
+	stored := &user.User{Name: "Before"}
+	if err := ApplyFieldMask(stored, &user.User{Name: "After"}, maskOf("name", "bogus")); err == nil {
+		t.Fatal("Expected an error for the bogus path")
+	}
+	if stored.Name != "Before" {
+		t.Errorf("Expected no changes after a validation error, got %s", stored.Name)
+	}
+
+	// An empty mask is a no-op
+	if err := ApplyFieldMask(stored, &user.User{}, nil); err != nil || stored.Name != "Before" {
+		t.Errorf("Expected empty mask to be a no-op, got %v / %s", err, stored.Name)
+	}
+
+	t.Log("✓ Invalid masks are rejected")
+}
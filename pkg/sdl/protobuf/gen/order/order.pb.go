@@ -1289,7 +1289,14 @@ const file_order_proto_rawDesc = "" +
 	"\x18ORDER_EVENT_TYPE_UPDATED\x10\x02\x12\x1e\n" +
 	"\x1aORDER_EVENT_TYPE_CANCELLED\x10\x03\x12\x1c\n" +
 	"\x18ORDER_EVENT_TYPE_SHIPPED\x10\x04\x12\x1e\n" +
-	"\x1aORDER_EVENT_TYPE_DELIVERED\x10\x05B.Z,go-transport-prac/pkg/sdl/protobuf/gen/orderb\x06proto3"
+	"\x1aORDER_EVENT_TYPE_DELIVERED\x10\x052\xa2\x03\n" +
+	"\fOrderService\x12>\n" +
+	"\vCreateOrder\x12\x19.order.CreateOrderRequest\x1a\x14.order.OrderResponse\x128\n" +
+	"\bGetOrder\x12\x16.order.GetOrderRequest\x1a\x14.order.OrderResponse\x12J\n" +
+	"\x11UpdateOrderStatus\x12\x1f.order.UpdateOrderStatusRequest\x1a\x14.order.OrderResponse\x12>\n" +
+	"\vCancelOrder\x12\x19.order.CancelOrderRequest\x1a\x14.order.OrderResponse\x12G\n" +
+	"\x0fGetOrdersByUser\x12\x1d.order.GetOrdersByUserRequest\x1a\x15.order.OrdersResponse\x12C\n" +
+	"\x12StreamOrdersByUser\x12\x1d.order.GetOrdersByUserRequest\x1a\f.order.Order0\x01B.Z,go-transport-prac/pkg/sdl/protobuf/gen/orderb\x06proto3"
 
 var (
 	file_order_proto_rawDescOnce sync.Once
@@ -1362,8 +1369,20 @@ var file_order_proto_depIdxs = []int32{
 	2,  // 30: order.OrderEvent.event_type:type_name -> order.OrderEventType
 	18, // 31: order.OrderEvent.timestamp:type_name -> google.protobuf.Timestamp
 	17, // 32: order.OrderEvent.metadata:type_name -> order.OrderEvent.MetadataEntry
-	33, // [33:33] is the sub-list for method output_type
-	33, // [33:33] is the sub-list for method input_type
+	8,  // 33: order.OrderService.CreateOrder:input_type -> order.CreateOrderRequest
+	10, // 34: order.OrderService.GetOrder:input_type -> order.GetOrderRequest
+	9,  // 35: order.OrderService.UpdateOrderStatus:input_type -> order.UpdateOrderStatusRequest
+	12, // 36: order.OrderService.CancelOrder:input_type -> order.CancelOrderRequest
+	11, // 37: order.OrderService.GetOrdersByUser:input_type -> order.GetOrdersByUserRequest
+	11, // 38: order.OrderService.StreamOrdersByUser:input_type -> order.GetOrdersByUserRequest
+	13, // 39: order.OrderService.CreateOrder:output_type -> order.OrderResponse
+	13, // 40: order.OrderService.GetOrder:output_type -> order.OrderResponse
+	13, // 41: order.OrderService.UpdateOrderStatus:output_type -> order.OrderResponse
+	13, // 42: order.OrderService.CancelOrder:output_type -> order.OrderResponse
+	14, // 43: order.OrderService.GetOrdersByUser:output_type -> order.OrdersResponse
+	3,  // 44: order.OrderService.StreamOrdersByUser:output_type -> order.Order
+	39, // [39:45] is the sub-list for method output_type
+	33, // [33:39] is the sub-list for method input_type
 	33, // [33:33] is the sub-list for extension type_name
 	33, // [33:33] is the sub-list for extension extendee
 	0,  // [0:33] is the sub-list for field type_name
@@ -1382,7 +1401,7 @@ func file_order_proto_init() {
 			NumEnums:      3,
 			NumMessages:   15,
 			NumExtensions: 0,
-			NumServices:   0,
+			NumServices:   1,
 		},
 		GoTypes:           file_order_proto_goTypes,
 		DependencyIndexes: file_order_proto_depIdxs,
@@ -1173,7 +1173,14 @@ const file_product_proto_rawDesc = "" +
 	"\x15PRODUCT_STATUS_ACTIVE\x10\x01\x12\x1b\n" +
 	"\x17PRODUCT_STATUS_INACTIVE\x10\x02\x12\x1f\n" +
 	"\x1bPRODUCT_STATUS_OUT_OF_STOCK\x10\x03\x12\x1f\n" +
-	"\x1bPRODUCT_STATUS_DISCONTINUED\x10\x04B0Z.go-transport-prac/pkg/sdl/protobuf/gen/productb\x06proto3"
+	"\x1bPRODUCT_STATUS_DISCONTINUED\x10\x042\xfb\x02\n" +
+	"\x0eProductService\x12H\n" +
+	"\rCreateProduct\x12\x1d.product.CreateProductRequest\x1a\x18.product.ProductResponse\x12B\n" +
+	"\n" +
+	"GetProduct\x12\x1a.product.GetProductRequest\x1a\x18.product.ProductResponse\x12H\n" +
+	"\rUpdateProduct\x12\x1d.product.UpdateProductRequest\x1a\x18.product.ProductResponse\x12K\n" +
+	"\x0eSearchProducts\x12\x1e.product.SearchProductsRequest\x1a\x19.product.ProductsResponse\x12D\n" +
+	"\x0eStreamProducts\x12\x1e.product.SearchProductsRequest\x1a\x10.product.Product0\x01B0Z.go-transport-prac/pkg/sdl/protobuf/gen/productb\x06proto3"
 
 var (
 	file_product_proto_rawDescOnce sync.Once
@@ -1228,8 +1235,18 @@ var file_product_proto_depIdxs = []int32{
 	0,  // 17: product.SearchProductsRequest.status:type_name -> product.ProductStatus
 	1,  // 18: product.ProductResponse.product:type_name -> product.Product
 	1,  // 19: product.ProductsResponse.products:type_name -> product.Product
-	20, // [20:20] is the sub-list for method output_type
-	20, // [20:20] is the sub-list for method input_type
+	7,  // 20: product.ProductService.CreateProduct:input_type -> product.CreateProductRequest
+	9,  // 21: product.ProductService.GetProduct:input_type -> product.GetProductRequest
+	8,  // 22: product.ProductService.UpdateProduct:input_type -> product.UpdateProductRequest
+	10, // 23: product.ProductService.SearchProducts:input_type -> product.SearchProductsRequest
+	10, // 24: product.ProductService.StreamProducts:input_type -> product.SearchProductsRequest
+	12, // 25: product.ProductService.CreateProduct:output_type -> product.ProductResponse
+	12, // 26: product.ProductService.GetProduct:output_type -> product.ProductResponse
+	12, // 27: product.ProductService.UpdateProduct:output_type -> product.ProductResponse
+	13, // 28: product.ProductService.SearchProducts:output_type -> product.ProductsResponse
+	1,  // 29: product.ProductService.StreamProducts:output_type -> product.Product
+	25, // [25:30] is the sub-list for method output_type
+	20, // [20:25] is the sub-list for method input_type
 	20, // [20:20] is the sub-list for extension type_name
 	20, // [20:20] is the sub-list for extension extendee
 	0,  // [0:20] is the sub-list for field type_name
@@ -1248,7 +1265,7 @@ func file_product_proto_init() {
 			NumEnums:      1,
 			NumMessages:   14,
 			NumExtensions: 0,
-			NumServices:   0,
+			NumServices:   1,
 		},
 		GoTypes:           file_product_proto_goTypes,
 		DependencyIndexes: file_product_proto_depIdxs,
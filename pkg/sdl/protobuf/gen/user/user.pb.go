@@ -671,6 +671,66 @@ func (x *UsersResponse) GetPageSize() int32 {
 	return 0
 }
 
+type ListUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        UserStatus             `protobuf:"varint,1,opt,name=status,proto3,enum=user.UserStatus" json:"status,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_user_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListUsersRequest) GetStatus() UserStatus {
+	if x != nil {
+		return x.Status
+	}
+	return UserStatus_USER_STATUS_UNSPECIFIED
+}
+
+func (x *ListUsersRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
 var File_user_proto protoreflect.FileDescriptor
 
 const file_user_proto_rawDesc = "" +
@@ -728,14 +788,29 @@ const file_user_proto_rawDesc = "" +
 	"\vtotal_count\x18\x02 \x01(\x05R\n" +
 	"totalCount\x12\x12\n" +
 	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
-	"\tpage_size\x18\x04 \x01(\x05R\bpageSize*\x8f\x01\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\"m\n" +
+	"\x10ListUsersRequest\x12(\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x10.user.UserStatusR\x06status\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize*\x8f\x01\n" +
 	"\n" +
 	"UserStatus\x12\x1b\n" +
 	"\x17USER_STATUS_UNSPECIFIED\x10\x00\x12\x16\n" +
 	"\x12USER_STATUS_ACTIVE\x10\x01\x12\x18\n" +
 	"\x14USER_STATUS_INACTIVE\x10\x02\x12\x19\n" +
 	"\x15USER_STATUS_SUSPENDED\x10\x03\x12\x17\n" +
-	"\x13USER_STATUS_DELETED\x10\x04B-Z+go-transport-prac/pkg/sdl/protobuf/gen/userb\x06proto3"
+	"\x13USER_STATUS_DELETED\x10\x042\xe2\x02\n" +
+	"\vUserService\x129\n" +
+	"\n" +
+	"CreateUser\x12\x17.user.CreateUserRequest\x1a\x12.user.UserResponse\x123\n" +
+	"\aGetUser\x12\x14.user.GetUserRequest\x1a\x12.user.UserResponse\x129\n" +
+	"\n" +
+	"UpdateUser\x12\x17.user.UpdateUserRequest\x1a\x12.user.UserResponse\x129\n" +
+	"\n" +
+	"DeleteUser\x12\x17.user.DeleteUserRequest\x1a\x12.user.UserResponse\x128\n" +
+	"\tListUsers\x12\x16.user.ListUsersRequest\x1a\x13.user.UsersResponse\x123\n" +
+	"\vStreamUsers\x12\x16.user.ListUsersRequest\x1a\n" +
+	".user.User0\x01B-Z+go-transport-prac/pkg/sdl/protobuf/gen/userb\x06proto3"
 
 var (
 	file_user_proto_rawDescOnce sync.Once
@@ -750,7 +825,7 @@ func file_user_proto_rawDescGZIP() []byte {
 }
 
 var file_user_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_user_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_user_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_user_proto_goTypes = []any{
 	(UserStatus)(0),               // 0: user.UserStatus
 	(*User)(nil),                  // 1: user.User
@@ -762,25 +837,39 @@ var file_user_proto_goTypes = []any{
 	(*DeleteUserRequest)(nil),     // 7: user.DeleteUserRequest
 	(*UserResponse)(nil),          // 8: user.UserResponse
 	(*UsersResponse)(nil),         // 9: user.UsersResponse
-	nil,                           // 10: user.Profile.MetadataEntry
-	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
+	(*ListUsersRequest)(nil),      // 10: user.ListUsersRequest
+	nil,                           // 11: user.Profile.MetadataEntry
+	(*timestamppb.Timestamp)(nil), // 12: google.protobuf.Timestamp
 }
 var file_user_proto_depIdxs = []int32{
 	0,  // 0: user.User.status:type_name -> user.UserStatus
 	2,  // 1: user.User.profile:type_name -> user.Profile
-	11, // 2: user.User.created_at:type_name -> google.protobuf.Timestamp
-	11, // 3: user.User.updated_at:type_name -> google.protobuf.Timestamp
+	12, // 2: user.User.created_at:type_name -> google.protobuf.Timestamp
+	12, // 3: user.User.updated_at:type_name -> google.protobuf.Timestamp
 	3,  // 4: user.Profile.address:type_name -> user.Address
-	10, // 5: user.Profile.metadata:type_name -> user.Profile.MetadataEntry
+	11, // 5: user.Profile.metadata:type_name -> user.Profile.MetadataEntry
 	2,  // 6: user.CreateUserRequest.profile:type_name -> user.Profile
 	2,  // 7: user.UpdateUserRequest.profile:type_name -> user.Profile
 	1,  // 8: user.UserResponse.user:type_name -> user.User
 	1,  // 9: user.UsersResponse.users:type_name -> user.User
-	10, // [10:10] is the sub-list for method output_type
-	10, // [10:10] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	0,  // 10: user.ListUsersRequest.status:type_name -> user.UserStatus
+	4,  // 11: user.UserService.CreateUser:input_type -> user.CreateUserRequest
+	6,  // 12: user.UserService.GetUser:input_type -> user.GetUserRequest
+	5,  // 13: user.UserService.UpdateUser:input_type -> user.UpdateUserRequest
+	7,  // 14: user.UserService.DeleteUser:input_type -> user.DeleteUserRequest
+	10, // 15: user.UserService.ListUsers:input_type -> user.ListUsersRequest
+	10, // 16: user.UserService.StreamUsers:input_type -> user.ListUsersRequest
+	8,  // 17: user.UserService.CreateUser:output_type -> user.UserResponse
+	8,  // 18: user.UserService.GetUser:output_type -> user.UserResponse
+	8,  // 19: user.UserService.UpdateUser:output_type -> user.UserResponse
+	8,  // 20: user.UserService.DeleteUser:output_type -> user.UserResponse
+	9,  // 21: user.UserService.ListUsers:output_type -> user.UsersResponse
+	1,  // 22: user.UserService.StreamUsers:output_type -> user.User
+	17, // [17:23] is the sub-list for method output_type
+	11, // [11:17] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_user_proto_init() }
@@ -794,9 +883,9 @@ func file_user_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_user_proto_rawDesc), len(file_user_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   10,
+			NumMessages:   11,
 			NumExtensions: 0,
-			NumServices:   0,
+			NumServices:   1,
 		},
 		GoTypes:           file_user_proto_goTypes,
 		DependencyIndexes: file_user_proto_depIdxs,
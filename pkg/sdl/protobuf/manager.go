@@ -7,17 +7,42 @@ import (
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"go-transport-prac/internal/types"
 	"go-transport-prac/pkg/sdl/protobuf/gen/order"
 	"go-transport-prac/pkg/sdl/protobuf/gen/product"
 	"go-transport-prac/pkg/sdl/protobuf/gen/user"
 )
 
 // Manager handles Protocol Buffers serialization and deserialization
-type Manager struct{}
+type Manager struct {
+	clock types.Clock
+	// validator, when set, rejects invalid messages before serialization
+	validator *Validator
+}
 
 // NewManager creates a new protobuf manager
 func NewManager() *Manager {
-	return &Manager{}
+	return &Manager{clock: types.SystemClock{}}
+}
+
+// WithClock sets the clock used for sample CreatedAt/UpdatedAt timestamps
+func (m *Manager) WithClock(clock types.Clock) *Manager {
+	m.clock = types.ClockOrSystem(clock)
+	return m
+}
+
+// WithValidator makes the Serialize methods reject messages that fail validator
+func (m *Manager) WithValidator(validator *Validator) *Manager {
+	m.validator = validator
+	return m
+}
+
+// validate runs the configured validator, if any
+func (m *Manager) validate(msg proto.Message) error {
+	if m.validator == nil {
+		return nil
+	}
+	return m.validator.Validate(msg)
 }
 
 // SerializeUser serializes a User message to bytes
@@ -25,6 +50,9 @@ func (m *Manager) SerializeUser(u *user.User) ([]byte, error) {
 	if u == nil {
 		return nil, fmt.Errorf("user cannot be nil")
 	}
+	if err := m.validate(u); err != nil {
+		return nil, err
+	}
 
 	return proto.Marshal(u)
 }
@@ -48,6 +76,9 @@ func (m *Manager) SerializeProduct(p *product.Product) ([]byte, error) {
 	if p == nil {
 		return nil, fmt.Errorf("product cannot be nil")
 	}
+	if err := m.validate(p); err != nil {
+		return nil, err
+	}
 
 	return proto.Marshal(p)
 }
@@ -71,6 +102,9 @@ func (m *Manager) SerializeOrder(o *order.Order) ([]byte, error) {
 	if o == nil {
 		return nil, fmt.Errorf("order cannot be nil")
 	}
+	if err := m.validate(o); err != nil {
+		return nil, err
+	}
 
 	return proto.Marshal(o)
 }
@@ -94,6 +128,9 @@ func (m *Manager) Serialize(msg proto.Message) ([]byte, error) {
 	if msg == nil {
 		return nil, fmt.Errorf("message cannot be nil")
 	}
+	if err := m.validate(msg); err != nil {
+		return nil, err
+	}
 
 	return proto.Marshal(msg)
 }
@@ -115,7 +152,7 @@ func (m *Manager) Deserialize(data []byte, msg proto.Message) error {
 
 // CreateSampleUser creates a sample user for testing
 func (m *Manager) CreateSampleUser() *user.User {
-	now := timestamppb.Now()
+	now := timestamppb.New(m.clock.Now())
 
 	return &user.User{
 		Id:     1,
@@ -146,7 +183,7 @@ func (m *Manager) CreateSampleUser() *user.User {
 
 // CreateSampleProduct creates a sample product for testing
 func (m *Manager) CreateSampleProduct() *product.Product {
-	now := timestamppb.Now()
+	now := timestamppb.New(m.clock.Now())
 
 	return &product.Product{
 		Id:          1,
@@ -194,8 +231,9 @@ func (m *Manager) CreateSampleProduct() *product.Product {
 
 // CreateSampleOrder creates a sample order for testing
 func (m *Manager) CreateSampleOrder() *order.Order {
-	now := timestamppb.Now()
-	deliveryTime := timestamppb.New(time.Now().Add(5 * 24 * time.Hour)) // 5 days from now
+	current := m.clock.Now()
+	now := timestamppb.New(current)
+	deliveryTime := timestamppb.New(current.Add(5 * 24 * time.Hour)) // 5 days from now
 
 	return &order.Order{
 		Id:          1,
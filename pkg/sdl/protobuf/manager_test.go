@@ -1,8 +1,14 @@
 package protobuf
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"google.golang.org/protobuf/proto"
+
+	"go-transport-prac/internal/testutil"
 	"go-transport-prac/pkg/sdl/protobuf/gen/user"
 )
 
@@ -266,4 +272,67 @@ func BenchmarkProductDeserialization(b *testing.B) {
 			b.Fatal(err)
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestManager_SampleTimestampsFromClock(t *testing.T) {
+	clock := testutil.NewDefaultFakeClock()
+	manager := NewManager().WithClock(clock)
+
+	u := manager.CreateSampleUser()
+	if !u.CreatedAt.AsTime().Equal(testutil.DefaultFakeTime) {
+		t.Errorf("User CreatedAt mismatch: got %v, want %v", u.CreatedAt.AsTime(), testutil.DefaultFakeTime)
+	}
+
+	clock.Advance(time.Hour)
+	o := manager.CreateSampleOrder()
+	want := testutil.DefaultFakeTime.Add(time.Hour)
+	if !o.CreatedAt.AsTime().Equal(want) {
+		t.Errorf("Order CreatedAt mismatch: got %v, want %v", o.CreatedAt.AsTime(), want)
+	}
+
+	// Fixed timestamps make the serialized message reproducible
+	first, err := manager.SerializeUser(manager.CreateSampleUser())
+	if err != nil {
+		t.Fatalf("Failed to serialize user: %v", err)
+	}
+	second, err := manager.SerializeUser(manager.CreateSampleUser())
+	if err != nil {
+		t.Fatalf("Failed to serialize user: %v", err)
+	}
+	if !proto.Equal(mustUser(t, manager, first), mustUser(t, manager, second)) {
+		t.Error("Expected identical users from a frozen clock")
+	}
+}
+
+func mustUser(t *testing.T, manager *Manager, data []byte) *user.User {
+	t.Helper()
+	u, err := manager.DeserializeUser(data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize user: %v", err)
+	}
+	return u
+}
+
+func TestManager_ContextVariants(t *testing.T) {
+	manager := NewManager()
+	ctx := context.Background()
+
+	data, err := manager.SerializeUserContext(ctx, manager.CreateSampleUser())
+	if err != nil {
+		t.Fatalf("Failed to serialize user: %v", err)
+	}
+	if _, err := manager.DeserializeUserContext(ctx, data); err != nil {
+		t.Fatalf("Failed to deserialize user: %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := manager.SerializeOrderContext(cancelled, manager.CreateSampleOrder()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if err := manager.DeserializeContext(cancelled, data, &user.User{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	t.Log("✓ Context variants stop once the context is cancelled")
+}
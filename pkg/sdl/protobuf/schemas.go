@@ -0,0 +1,51 @@
+package protobuf
+
+import (
+	"embed"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Embed the .proto sources the gen packages are compiled from
+//
+//go:embed proto/*.proto
+var protoFiles embed.FS
+
+// ProtoSource returns the source of one of the package's .proto files, such as user.proto
+func ProtoSource(name string) ([]byte, error) {
+	data, err := protoFiles.ReadFile("proto/" + name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read proto file %s: %w", name, err)
+	}
+	return data, nil
+}
+
+// FileDescriptorSet returns the files declaring msgs and every file they
+// import, dependencies first, so consumers without the .proto sources can
+// decode the messages (e.g. protoc --decode with --descriptor_set_in)
+func FileDescriptorSet(msgs ...proto.Message) *descriptorpb.FileDescriptorSet {
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := make(map[string]bool)
+
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+	}
+
+	for _, msg := range msgs {
+		add(msg.ProtoReflect().Descriptor().ParentFile())
+	}
+	return set
+}
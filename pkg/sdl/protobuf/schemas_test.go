@@ -0,0 +1,58 @@
+package protobuf
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protodesc"
+
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+func TestProtoSource(t *testing.T) {
+	source, err := ProtoSource("user.proto")
+	if err != nil {
+		t.Fatalf("Failed to read user.proto: %v", err)
+	}
+	if !strings.Contains(string(source), "message User {") {
+		t.Error("Expected user.proto to declare the User message")
+	}
+
+	if _, err := ProtoSource("missing.proto"); err == nil {
+		t.Error("Expected error for an unknown proto file")
+	}
+
+	t.Log("✓ Proto sources embedded")
+}
+
+func TestFileDescriptorSet(t *testing.T) {
+	set := FileDescriptorSet(&user.User{}, &order.Order{}, &user.Profile{})
+
+	// Dependencies come before the files importing them, each exactly once
+	seen := make(map[string]bool)
+	for _, file := range set.File {
+		if seen[file.GetName()] {
+			t.Errorf("File %s listed twice", file.GetName())
+		}
+		for _, dep := range file.GetDependency() {
+			if !seen[dep] {
+				t.Errorf("File %s listed before its dependency %s", file.GetName(), dep)
+			}
+		}
+		seen[file.GetName()] = true
+	}
+	if !seen["google/protobuf/timestamp.proto"] || !seen["user.proto"] || !seen["order.proto"] {
+		t.Errorf("Expected user, order and timestamp files, got %v", seen)
+	}
+
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		t.Fatalf("Descriptor set does not resolve: %v", err)
+	}
+	if _, err := files.FindDescriptorByName("user.User"); err != nil {
+		t.Errorf("Expected user.User in descriptor set: %v", err)
+	}
+
+	t.Log("✓ File descriptor set is self-contained")
+}
@@ -0,0 +1,269 @@
+package protobuf
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+// Rule checks a single field value. Check returns an empty string when the
+// value is valid and a human-readable reason otherwise. set reports whether
+// the field is populated
+type Rule struct {
+	Name  string
+	Check func(fd protoreflect.FieldDescriptor, value protoreflect.Value, set bool) string
+}
+
+// FieldViolation describes a field that failed a rule
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// String renders the violation as "field: message"
+func (v FieldViolation) String() string {
+	return v.Field + ": " + v.Message
+}
+
+// fieldRules are the rules attached to one field of a message type
+type fieldRules struct {
+	field protoreflect.FieldDescriptor
+	rules []Rule
+}
+
+// Validator applies field rules to protobuf messages by reflection. Rules are
+// registered per message type and apply wherever that type appears, including
+// nested, repeated and map-valued messages. Enum fields are always checked
+// against their declared values
+type Validator struct {
+	rules map[protoreflect.FullName][]fieldRules
+}
+
+// NewValidator creates a validator without field rules
+func NewValidator() *Validator {
+	return &Validator{rules: make(map[protoreflect.FullName][]fieldRules)}
+}
+
+// DefaultValidator returns a validator with rules for the user, product and order messages
+func DefaultValidator() *Validator {
+	return NewValidator().
+		Field(&user.User{}, "email", Required(), Email()).
+		Field(&user.User{}, "name", Required()).
+		Field(&user.User{}, "status", Required()).
+		Field(&product.Product{}, "name", Required()).
+		Field(&product.Product{}, "sku", Required()).
+		Field(&product.Product{}, "status", Required()).
+		Field(&product.Price{}, "currency", Pattern(`^[A-Z]{3}$`)).
+		Field(&product.Price{}, "amount_cents", NonNegative()).
+		Field(&product.Price{}, "discount_percentage", Range(0, 100)).
+		Field(&product.Inventory{}, "quantity", NonNegative()).
+		Field(&order.Order{}, "user_id", Required()).
+		Field(&order.Order{}, "status", Required()).
+		Field(&order.Order{}, "items", Required()).
+		Field(&order.OrderItem{}, "product_id", Required()).
+		Field(&order.OrderItem{}, "quantity", Min(1))
+}
+
+// Field attaches rules to a field of msg's type. It panics if the field does
+// not exist, since rules are registered at startup from code
+func (v *Validator) Field(msg proto.Message, name string, rules ...Rule) *Validator {
+	md := msg.ProtoReflect().Descriptor()
+	fd := md.Fields().ByName(protoreflect.Name(name))
+	if fd == nil {
+		panic(fmt.Sprintf("protobuf validation: %s has no field %q", md.FullName(), name))
+	}
+
+	for i, existing := range v.rules[md.FullName()] {
+		if existing.field == fd {
+			v.rules[md.FullName()][i].rules = append(existing.rules, rules...)
+			return v
+		}
+	}
+	v.rules[md.FullName()] = append(v.rules[md.FullName()], fieldRules{field: fd, rules: rules})
+	return v
+}
+
+// Violations returns every rule violation in msg, in field order
+func (v *Validator) Violations(msg proto.Message) []FieldViolation {
+	if msg == nil {
+		return []FieldViolation{{Field: "", Rule: "required", Message: "message cannot be nil"}}
+	}
+	var violations []FieldViolation
+	v.walk(msg.ProtoReflect(), "", &violations)
+	return violations
+}
+
+// Validate returns a validation error listing every violation in msg. Each
+// violated field is also added to the error's Fields keyed by its path
+func (v *Validator) Validate(msg proto.Message) error {
+	violations := v.Violations(msg)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(violations))
+	fields := make(map[string]interface{}, len(violations))
+	for i, violation := range violations {
+		messages[i] = violation.String()
+		fields[violation.Field] = violation.Message
+	}
+
+	err := errors.ValidationError(errors.CodeValidationFailed,
+		fmt.Sprintf("invalid %s", msg.ProtoReflect().Descriptor().FullName())).
+		WithFields(fields)
+	err.Details = strings.Join(messages, "; ")
+	return err
+}
+
+// walk checks the rules of m and recurses into populated message fields
+func (v *Validator) walk(m protoreflect.Message, prefix string, violations *[]FieldViolation) {
+	md := m.Descriptor()
+
+	for _, fr := range v.rules[md.FullName()] {
+		for _, rule := range fr.rules {
+			if reason := rule.Check(fr.field, m.Get(fr.field), m.Has(fr.field)); reason != "" {
+				*violations = append(*violations, FieldViolation{
+					Field:   prefix + string(fr.field.Name()),
+					Rule:    rule.Name,
+					Message: reason,
+				})
+			}
+		}
+	}
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !m.Has(fd) {
+			continue
+		}
+		path := prefix + string(fd.Name())
+
+		switch {
+		case fd.IsList():
+			list := m.Get(fd).List()
+			for j := 0; j < list.Len(); j++ {
+				v.walkValue(fd, list.Get(j), fmt.Sprintf("%s[%d]", path, j), violations)
+			}
+		case fd.IsMap():
+			m.Get(fd).Map().Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+				v.walkValue(fd.MapValue(), value, fmt.Sprintf("%s[%v]", path, key.Interface()), violations)
+				return true
+			})
+		default:
+			v.walkValue(fd, m.Get(fd), path, violations)
+		}
+	}
+}
+
+// walkValue checks enum values and recurses into messages
+func (v *Validator) walkValue(fd protoreflect.FieldDescriptor, value protoreflect.Value, path string, violations *[]FieldViolation) {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if fd.Enum().Values().ByNumber(value.Enum()) == nil {
+			*violations = append(*violations, FieldViolation{
+				Field:   path,
+				Rule:    "enum",
+				Message: fmt.Sprintf("%d is not a defined %s value", value.Enum(), fd.Enum().Name()),
+			})
+		}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		v.walk(value.Message(), path+".", violations)
+	}
+}
+
+// Required rejects unset fields: zero scalars, empty strings and lists, and
+// enums left at their zero (UNSPECIFIED) value
+func Required() Rule {
+	return Rule{Name: "required", Check: func(_ protoreflect.FieldDescriptor, _ protoreflect.Value, set bool) string {
+		if !set {
+			return "is required"
+		}
+		return ""
+	}}
+}
+
+// Email rejects strings that are not a bare email address. Empty values pass;
+// combine with Required to reject them
+func Email() Rule {
+	return Rule{Name: "email", Check: func(_ protoreflect.FieldDescriptor, value protoreflect.Value, set bool) string {
+		if !set {
+			return ""
+		}
+		addr, err := mail.ParseAddress(value.String())
+		if err != nil || addr.Address != value.String() {
+			return "must be a valid email address"
+		}
+		return ""
+	}}
+}
+
+// Pattern rejects strings that do not match expr. Empty values pass
+func Pattern(expr string) Rule {
+	re := regexp.MustCompile(expr)
+	return Rule{Name: "pattern", Check: func(_ protoreflect.FieldDescriptor, value protoreflect.Value, set bool) string {
+		if set && !re.MatchString(value.String()) {
+			return fmt.Sprintf("must match %s", expr)
+		}
+		return ""
+	}}
+}
+
+// NonNegative rejects numbers below zero
+func NonNegative() Rule {
+	return Rule{Name: "non_negative", Check: func(fd protoreflect.FieldDescriptor, value protoreflect.Value, _ bool) string {
+		if n, ok := number(fd, value); ok && n < 0 {
+			return "cannot be negative"
+		}
+		return ""
+	}}
+}
+
+// Min rejects numbers below min. Unset fields are checked as zero
+func Min(min float64) Rule {
+	return Rule{Name: "min", Check: func(fd protoreflect.FieldDescriptor, value protoreflect.Value, _ bool) string {
+		if n, ok := number(fd, value); ok && n < min {
+			return fmt.Sprintf("must be at least %v", min)
+		}
+		return ""
+	}}
+}
+
+// Range rejects numbers outside [min, max]. Unset fields are checked as zero
+func Range(min, max float64) Rule {
+	return Rule{Name: "range", Check: func(fd protoreflect.FieldDescriptor, value protoreflect.Value, _ bool) string {
+		n, ok := number(fd, value)
+		if ok && (n < min || n > max) {
+			return fmt.Sprintf("must be between %v and %v", min, max)
+		}
+		return ""
+	}}
+}
+
+// number converts numeric field values to float64
+func number(fd protoreflect.FieldDescriptor, value protoreflect.Value) (float64, bool) {
+	if fd.IsList() || fd.IsMap() {
+		return 0, false
+	}
+	switch fd.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return float64(value.Int()), true
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return float64(value.Uint()), true
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return value.Float(), true
+	}
+	return 0, false
+}
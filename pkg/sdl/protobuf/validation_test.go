@@ -0,0 +1,166 @@
+package protobuf
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+func TestDefaultValidator_SamplesAreValid(t *testing.T) {
+	manager := NewManager()
+	validator := DefaultValidator()
+
+	for _, msg := range []proto.Message{
+		manager.CreateSampleUser(),
+		manager.CreateSampleProduct(),
+		manager.CreateSampleOrder(),
+	} {
+		if violations := validator.Violations(msg); len(violations) > 0 {
+			t.Errorf("%s: unexpected violations %v", msg.ProtoReflect().Descriptor().Name(), violations)
+		}
+	}
+
+	t.Log("✓ Sample messages pass the default rules")
+}
+
+func TestValidator_FieldLevelDetails(t *testing.T) {
+	manager := NewManager()
+	validator := DefaultValidator()
+
+	invalidUser := manager.CreateSampleUser()
+	invalidUser.Email = ""
+	invalidUser.Status = user.UserStatus(42)
+
+	badEmail := manager.CreateSampleUser()
+	badEmail.Email = "not-an-email"
+
+	negativePrice := manager.CreateSampleProduct()
+	negativePrice.Price.AmountCents = -100
+	negativePrice.Price.OriginalPrice = &product.Price{Currency: "usd", AmountCents: 10}
+
+	badOrder := manager.CreateSampleOrder()
+	badOrder.Items[0].Quantity = 0
+	badOrder.Items[0].UnitPrice.AmountCents = -1
+
+	tests := []struct {
+		name     string
+		msg      proto.Message
+		expected map[string]string
+	}{
+		{
+			name: "empty email and unknown status",
+			msg:  invalidUser,
+			expected: map[string]string{
+				"email":  "required",
+				"status": "enum",
+			},
+		},
+		{
+			name:     "malformed email",
+			msg:      badEmail,
+			expected: map[string]string{"email": "email"},
+		},
+		{
+			name: "negative price",
+			msg:  negativePrice,
+			expected: map[string]string{
+				"price.amount_cents":            "non_negative",
+				"price.original_price.currency": "pattern",
+			},
+		},
+		{
+			name: "repeated nested items",
+			msg:  badOrder,
+			expected: map[string]string{
+				"items[0].quantity":                "min",
+				"items[0].unit_price.amount_cents": "non_negative",
+			},
+		},
+		{
+			name:     "empty order",
+			msg:      &order.Order{UserId: 1, Status: order.OrderStatus_ORDER_STATUS_PENDING},
+			expected: map[string]string{"items": "required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := validator.Violations(tt.msg)
+			got := make(map[string]string, len(violations))
+			for _, v := range violations {
+				got[v.Field] = v.Rule
+			}
+			if len(got) != len(tt.expected) {
+				t.Errorf("Expected %d violations, got %v", len(tt.expected), violations)
+			}
+			for field, rule := range tt.expected {
+				if got[field] != rule {
+					t.Errorf("Expected %s to fail %q, got %q", field, rule, got[field])
+				}
+			}
+		})
+	}
+
+	t.Log("✓ Violations carry field paths and rule names")
+}
+
+func TestManager_WithValidator(t *testing.T) {
+	invalid := NewManager().CreateSampleUser()
+	invalid.Email = ""
+
+	// Validation is opt-in
+	if _, err := NewManager().SerializeUser(invalid); err != nil {
+		t.Fatalf("Expected serialization without a validator to succeed: %v", err)
+	}
+
+	manager := NewManager().WithValidator(DefaultValidator())
+	_, err := manager.SerializeUser(invalid)
+	if err == nil {
+		t.Fatal("Expected invalid user to be rejected")
+	}
+	if !errors.IsType(err, errors.ErrorTypeValidation) || !errors.IsCode(err, errors.CodeValidationFailed) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+	appErr, _ := errors.AsAppError(err)
+	if appErr.Fields["email"] != "is required" {
+		t.Errorf("Expected field details for email, got %v", appErr.Fields)
+	}
+	if !strings.Contains(appErr.Error(), "email: is required") {
+		t.Errorf("Expected details in the error message, got %s", appErr.Error())
+	}
+
+	// The generic path validates too
+	if _, err := manager.Serialize(invalid); err == nil {
+		t.Error("Expected Serialize to reject the invalid user")
+	}
+	if _, err := manager.SerializeUser(manager.CreateSampleUser()); err != nil {
+		t.Errorf("Expected valid user to serialize: %v", err)
+	}
+
+	t.Log("✓ Manager rejects invalid messages with a ValidationError")
+}
+
+func TestValidator_CustomRules(t *testing.T) {
+	validator := NewValidator().
+		Field(&user.Profile{}, "phone", Required()).
+		Field(&user.Address{}, "country", Pattern(`^[A-Z]{2,3}$`))
+
+	u := &user.User{Profile: &user.Profile{Address: &user.Address{Country: "usa"}}}
+	violations := validator.Violations(u)
+	if len(violations) != 2 || violations[0].Field != "profile.phone" || violations[1].Field != "profile.address.country" {
+		t.Errorf("Unexpected violations: %v", violations)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for an unknown field")
+		}
+	}()
+	NewValidator().Field(&user.User{}, "nickname", Required())
+}
@@ -0,0 +1,285 @@
+// Package retention deletes old files from the data directories of the Avro
+// and Parquet managers by policy: a maximum age, a maximum total size and a
+// number of newest files kept per prefix. Policies run on demand or on a
+// schedule, and a dry run reports what would be deleted without deleting it.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"go-transport-prac/internal/types"
+)
+
+// Target is a data directory retention applies to. avro.Manager and
+// parquet.SimpleManager are targets; deleting through them also removes the
+// checksums and catalog entries of deleted files
+type Target interface {
+	// ListFiles lists the data files of the directory
+	ListFiles() ([]string, error)
+	// StatFile returns a file's size and modification time
+	StatFile(filename string) (fs.FileInfo, error)
+	// DeleteFile deletes a file
+	DeleteFile(filename string) error
+}
+
+// Policy decides which files of a target are deleted. A file is deleted when
+// any limit selects it; zero limits are off
+type Policy struct {
+	// MaxAge deletes files modified longer ago. Files with an unknown
+	// modification time are never too old
+	MaxAge time.Duration
+	// MaxTotalSize deletes the oldest files until the rest fit
+	MaxTotalSize int64
+	// KeepLast keeps the newest files of each prefix and deletes the rest
+	KeepLast int
+	// Prefix groups files for KeepLast. The default is DefaultPrefix
+	Prefix func(filename string) string
+}
+
+// Reasons a file is deleted
+const (
+	ReasonMaxAge   = "max-age"
+	ReasonMaxSize  = "max-total-size"
+	ReasonKeepLast = "keep-last"
+)
+
+// versionSuffix matches the timestamps, batch numbers and sequence numbers
+// ending generated file names
+var versionSuffix = regexp.MustCompile(`[_-]?[0-9][0-9_-]*$`)
+
+// DefaultPrefix groups generated files by the name before their trailing
+// numbers: users_20240101_120000.parquet and users_20240102_120000.parquet
+// share the prefix users
+func DefaultPrefix(filename string) string {
+	base := filename
+	if i := strings.LastIndexByte(base, '.'); i > 0 {
+		base = base[:i]
+	}
+	if prefix := versionSuffix.ReplaceAllString(base, ""); prefix != "" {
+		return prefix
+	}
+	return base
+}
+
+// Deletion is a file a policy selected
+type Deletion struct {
+	File    string
+	Size    int64
+	ModTime time.Time
+	Reason  string
+}
+
+// TargetReport is the outcome of a policy on one target
+type TargetReport struct {
+	Name           string
+	Files          int
+	Bytes          int64
+	Deleted        []Deletion
+	FreedBytes     int64
+	RemainingBytes int64
+}
+
+// Report is the outcome of one run over every target
+type Report struct {
+	RanAt   time.Time
+	DryRun  bool
+	Targets []TargetReport
+}
+
+// Deleted returns the number of files deleted, or that would be in a dry run
+func (r Report) Deleted() int {
+	n := 0
+	for _, t := range r.Targets {
+		n += len(t.Deleted)
+	}
+	return n
+}
+
+// FreedBytes returns the bytes freed, or that would be in a dry run
+func (r Report) FreedBytes() int64 {
+	var n int64
+	for _, t := range r.Targets {
+		n += t.FreedBytes
+	}
+	return n
+}
+
+// Print writes the report as a human-readable listing
+func (r Report) Print(w io.Writer) {
+	verb := "Deleted"
+	if r.DryRun {
+		verb = "Would delete"
+	}
+	for _, t := range r.Targets {
+		fmt.Fprintf(w, "%s: %d files, %d bytes\n", t.Name, t.Files, t.Bytes)
+		for _, d := range t.Deleted {
+			fmt.Fprintf(w, "  %s %s (%d bytes, %s, %s)\n", verb, d.File, d.Size, d.ModTime.Format(time.RFC3339), d.Reason)
+		}
+	}
+	fmt.Fprintf(w, "%s %d files, %d bytes\n", verb, r.Deleted(), r.FreedBytes())
+}
+
+type target struct {
+	name   string
+	target Target
+	policy Policy
+}
+
+// Manager applies retention policies to data directories
+type Manager struct {
+	clock   types.Clock
+	dryRun  bool
+	targets []target
+}
+
+// NewManager creates a retention manager with no targets
+func NewManager() *Manager {
+	return &Manager{clock: types.SystemClock{}}
+}
+
+// WithClock sets the clock file ages are measured against
+func (m *Manager) WithClock(clock types.Clock) *Manager {
+	m.clock = types.ClockOrSystem(clock)
+	return m
+}
+
+// WithDryRun reports what would be deleted without deleting anything
+func (m *Manager) WithDryRun(on bool) *Manager {
+	m.dryRun = on
+	return m
+}
+
+// Add applies policy to t, reported under name
+func (m *Manager) Add(name string, t Target, policy Policy) *Manager {
+	m.targets = append(m.targets, target{name, t, policy})
+	return m
+}
+
+// Apply runs every policy once. A failing target does not stop the others;
+// the error names every target that failed
+func (m *Manager) Apply(ctx context.Context) (Report, error) {
+	report := Report{RanAt: m.clock.Now(), DryRun: m.dryRun}
+	var failed []string
+	for _, t := range m.targets {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		tr, err := m.apply(t, report.RanAt)
+		report.Targets = append(report.Targets, tr)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", t.name, err))
+		}
+	}
+	if len(failed) > 0 {
+		return report, fmt.Errorf("failed to apply retention to %s", strings.Join(failed, "; "))
+	}
+	return report, nil
+}
+
+// Run applies the policies every interval until ctx is done, passing each
+// report to onReport
+func (m *Manager) Run(ctx context.Context, interval time.Duration, onReport func(Report, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := m.Apply(ctx)
+		if onReport != nil {
+			onReport(report, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// file is a target's file with its metadata
+type file struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+func (m *Manager) apply(t target, now time.Time) (TargetReport, error) {
+	report := TargetReport{Name: t.name}
+
+	names, err := t.target.ListFiles()
+	if err != nil {
+		return report, fmt.Errorf("failed to list files: %w", err)
+	}
+	files := make([]file, 0, len(names))
+	for _, name := range names {
+		info, err := t.target.StatFile(name)
+		if err != nil {
+			return report, fmt.Errorf("failed to stat %s: %w", name, err)
+		}
+		files = append(files, file{name, info.Size(), info.ModTime()})
+		report.Files++
+		report.Bytes += info.Size()
+	}
+
+	for _, d := range selectDeletions(files, t.policy, now) {
+		if !m.dryRun {
+			if err := t.target.DeleteFile(d.File); err != nil {
+				report.RemainingBytes = report.Bytes - report.FreedBytes
+				return report, fmt.Errorf("failed to delete %s: %w", d.File, err)
+			}
+		}
+		report.Deleted = append(report.Deleted, d)
+		report.FreedBytes += d.Size
+	}
+	report.RemainingBytes = report.Bytes - report.FreedBytes
+	return report, nil
+}
+
+// selectDeletions returns the files policy deletes at now, oldest first
+func selectDeletions(files []file, policy Policy, now time.Time) []Deletion {
+	// Newest first, so the files kept by count and size are met first
+	files = slices.Clone(files)
+	slices.SortStableFunc(files, func(a, b file) int {
+		if c := b.modTime.Compare(a.modTime); c != 0 {
+			return c
+		}
+		return strings.Compare(a.name, b.name)
+	})
+
+	prefix := policy.Prefix
+	if prefix == nil {
+		prefix = DefaultPrefix
+	}
+	kept := make(map[string]int)
+	var total int64
+	// Once a file does not fit, no older file is kept for size either
+	full := false
+	var deletions []Deletion
+	for _, f := range files {
+		reason := ""
+		switch {
+		case policy.MaxAge > 0 && !f.modTime.IsZero() && now.Sub(f.modTime) > policy.MaxAge:
+			reason = ReasonMaxAge
+		case policy.KeepLast > 0 && kept[prefix(f.name)] >= policy.KeepLast:
+			reason = ReasonKeepLast
+		case policy.MaxTotalSize > 0 && (full || total+f.size > policy.MaxTotalSize):
+			full = true
+			reason = ReasonMaxSize
+		}
+		if reason != "" {
+			deletions = append(deletions, Deletion{File: f.name, Size: f.size, ModTime: f.modTime, Reason: reason})
+			continue
+		}
+		kept[prefix(f.name)]++
+		total += f.size
+	}
+	slices.Reverse(deletions)
+	return deletions
+}
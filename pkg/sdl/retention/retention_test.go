@@ -0,0 +1,136 @@
+package retention
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/checksum"
+)
+
+var _ Target = (*avro.Manager)(nil)
+
+// writeAged writes users to filename and backdates it by age
+func writeAged(t *testing.T, m *avro.Manager, dir, filename string, users int, age time.Duration, now time.Time) {
+	t.Helper()
+	if err := m.WriteUsersToFile(filename, m.CreateSampleUsers(users)); err != nil {
+		t.Fatalf("Failed to write %s: %v", filename, err)
+	}
+	modTime := now.Add(-age)
+	if err := os.Chtimes(filepath.Join(dir, filename), modTime, modTime); err != nil {
+		t.Fatalf("Failed to backdate %s: %v", filename, err)
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	testDir := "tmp/test_retention"
+	defer os.RemoveAll(testDir)
+	clock := testutil.NewDefaultFakeClock()
+	now := clock.Now()
+
+	manager, err := avro.NewManager(testDir)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	manager.WithChecksums(true)
+	for day := 0; day < 5; day++ {
+		writeAged(t, manager, testDir, fmt.Sprintf("users_2024010%d.avro", 5-day), 10, time.Duration(day)*24*time.Hour, now)
+	}
+	writeAged(t, manager, testDir, "orders_1.avro", 10, 40*24*time.Hour, now)
+
+	retention := NewManager().WithClock(clock).WithDryRun(true).
+		Add("avro", manager, Policy{MaxAge: 30 * 24 * time.Hour, KeepLast: 3})
+
+	report, err := retention.Apply(context.Background())
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	reasons := make(map[string]string)
+	for _, d := range report.Targets[0].Deleted {
+		reasons[d.File] = d.Reason
+	}
+	expected := map[string]string{
+		"orders_1.avro":       ReasonMaxAge,
+		"users_20240101.avro": ReasonKeepLast,
+		"users_20240102.avro": ReasonKeepLast,
+	}
+	if fmt.Sprint(reasons) != fmt.Sprint(expected) {
+		t.Errorf("Expected deletions %v, got %v", expected, reasons)
+	}
+	if files, _ := manager.ListFiles(); len(files) != 6 {
+		t.Fatalf("Expected a dry run to delete nothing, %d files left", len(files))
+	}
+
+	var out bytes.Buffer
+	report.Print(&out)
+	if !strings.Contains(out.String(), "Would delete orders_1.avro") || !strings.Contains(out.String(), "Would delete 3 files") {
+		t.Errorf("Unexpected dry run listing:\n%s", out.String())
+	}
+
+	report, err = retention.WithDryRun(false).Apply(context.Background())
+	if err != nil {
+		t.Fatalf("Retention failed: %v", err)
+	}
+	files, _ := manager.ListFiles()
+	if len(files) != 3 || report.Deleted() != 3 || report.Targets[0].RemainingBytes <= 0 {
+		t.Errorf("Expected the 3 newest user files to be kept, got %v", files)
+	}
+	if _, err := os.Stat(checksum.SidecarName(filepath.Join(testDir, "orders_1.avro"))); !os.IsNotExist(err) {
+		t.Error("Expected deletions to go through the manager and remove checksums")
+	}
+
+	t.Log("✓ Age and keep-last policies delete only what they select")
+}
+
+func TestMaxTotalSize(t *testing.T) {
+	now := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	files := []file{
+		{"a_1", 20, now.Add(-4 * time.Hour)},
+		{"a_2", 40, now.Add(-3 * time.Hour)},
+		{"a_3", 10, now.Add(-2 * time.Hour)},
+		{"a_4", 50, now.Add(-1 * time.Hour)},
+	}
+
+	deletions := selectDeletions(files, Policy{MaxTotalSize: 90}, now)
+	// The newest two fit; a_2 would overflow, so it and everything older goes,
+	// even though the small a_1 alone would fit
+	if len(deletions) != 2 || deletions[0].File != "a_1" || deletions[1].File != "a_2" {
+		t.Errorf("Expected a_1 and a_2 to be deleted oldest first, got %+v", deletions)
+	}
+	for _, d := range deletions {
+		if d.Reason != ReasonMaxSize {
+			t.Errorf("Unexpected reason %s for %s", d.Reason, d.File)
+		}
+	}
+
+	// Unknown modification times are never too old
+	if d := selectDeletions([]file{{"b", 1, time.Time{}}}, Policy{MaxAge: time.Hour}, now); len(d) != 0 {
+		t.Errorf("Expected a file without a time to be kept, got %+v", d)
+	}
+
+	t.Log("✓ Size limits keep the newest files that fit")
+}
+
+func TestDefaultPrefix(t *testing.T) {
+	tests := map[string]string{
+		"processed_users_20240101_120000.parquet": "processed_users",
+		"users_compacted_000.parquet":             "users_compacted",
+		"batch-3.avro":                            "batch",
+		"users.avro":                              "users",
+		"2024.avro":                               "2024",
+	}
+	for name, expected := range tests {
+		if got := DefaultPrefix(name); got != expected {
+			t.Errorf("%s: expected prefix %q, got %q", name, expected, got)
+		}
+	}
+
+	t.Log("✓ Generated file names group by their stem")
+}
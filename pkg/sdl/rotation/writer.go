@@ -0,0 +1,239 @@
+// Package rotation provides a format-agnostic writer that rolls output files
+// by time window and/or size for long-running ingestion services.
+package rotation
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// inProgressSuffix marks files that are still being written
+const inProgressSuffix = ".inprogress"
+
+// SegmentWriter encodes records into a single output file
+type SegmentWriter[T any] interface {
+	Write(records []T) error
+	Close() error
+}
+
+// SegmentFactory creates a SegmentWriter on top of a freshly opened file
+type SegmentFactory[T any] func(w io.Writer) (SegmentWriter[T], error)
+
+// Config controls when files are rolled and how they are named
+type Config struct {
+	Dir       string        // Output directory
+	Prefix    string        // File name prefix, e.g. "users"
+	Extension string        // File extension including the dot, e.g. ".parquet"
+	Window    time.Duration // UTC-aligned time window per file, zero disables time-based rotation
+	MaxBytes  int64         // Maximum bytes per file, zero disables size-based rotation
+
+	// OnPublish is called with the final path of every completed file
+	OnPublish func(path string)
+	// Now returns the current time, defaults to time.Now
+	Now func() time.Time
+}
+
+// Writer writes records to the current segment and rotates files as windows or sizes are exceeded.
+// Completed files are renamed from their in-progress name and then published.
+type Writer[T any] struct {
+	mu      sync.Mutex
+	cfg     Config
+	factory SegmentFactory[T]
+
+	file        *os.File
+	counter     *countingWriter
+	segment     SegmentWriter[T]
+	path        string
+	windowStart time.Time
+	sequence    int
+	published   []string
+}
+
+// NewWriter creates a rotating writer
+func NewWriter[T any](cfg Config, factory SegmentFactory[T]) (*Writer[T], error) {
+	if cfg.Prefix == "" {
+		return nil, fmt.Errorf("prefix is required")
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	return &Writer[T]{cfg: cfg, factory: factory}, nil
+}
+
+// Write appends records to the current file, rotating first if its window has passed
+// and afterwards if it has grown beyond MaxBytes
+func (w *Writer[T]) Write(records ...T) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.cfg.Now().UTC()
+	if w.segment != nil && w.windowExpired(now) {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+
+	if w.segment == nil {
+		if err := w.open(now); err != nil {
+			return err
+		}
+	}
+
+	if err := w.segment.Write(records); err != nil {
+		return fmt.Errorf("failed to write records: %w", err)
+	}
+
+	if w.cfg.MaxBytes > 0 && w.counter.n >= w.cfg.MaxBytes {
+		return w.rotate()
+	}
+
+	return nil
+}
+
+// RotateIfDue closes the current file if its time window has passed.
+// Long-running services call this periodically so idle files are still published.
+func (w *Writer[T]) RotateIfDue() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.segment == nil || !w.windowExpired(w.cfg.Now().UTC()) {
+		return nil
+	}
+	return w.rotate()
+}
+
+// Rotate closes and publishes the current file, if any
+func (w *Writer[T]) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.rotate()
+}
+
+// Close publishes the current file and stops the writer
+func (w *Writer[T]) Close() error {
+	return w.Rotate()
+}
+
+// Published returns the paths of all completed files
+func (w *Writer[T]) Published() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]string(nil), w.published...)
+}
+
+// windowExpired reports whether now falls outside the current file's window
+func (w *Writer[T]) windowExpired(now time.Time) bool {
+	return w.cfg.Window > 0 && !now.Truncate(w.cfg.Window).Equal(w.windowStart)
+}
+
+// open starts a new file for the window containing now
+func (w *Writer[T]) open(now time.Time) error {
+	windowStart := now
+	if w.cfg.Window > 0 {
+		windowStart = now.Truncate(w.cfg.Window)
+	}
+	if !windowStart.Equal(w.windowStart) {
+		w.sequence = 0
+	}
+	w.windowStart = windowStart
+
+	path := w.nextPath()
+	file, err := os.Create(path + inProgressSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	counter := &countingWriter{w: file}
+	segment, err := w.factory(counter)
+	if err != nil {
+		file.Close()
+		os.Remove(path + inProgressSuffix)
+		return fmt.Errorf("failed to create segment writer: %w", err)
+	}
+
+	w.file = file
+	w.counter = counter
+	w.segment = segment
+	w.path = path
+	return nil
+}
+
+// nextPath returns a file name not yet used in the current window
+func (w *Writer[T]) nextPath() string {
+	for {
+		name := w.cfg.Prefix + "-" + w.windowStart.Format(w.timeLayout())
+		if w.sequence > 0 {
+			name += fmt.Sprintf("-%d", w.sequence)
+		}
+		w.sequence++
+
+		path := filepath.Join(w.cfg.Dir, name+w.cfg.Extension)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+	}
+}
+
+// timeLayout picks the coarsest timestamp layout that still distinguishes windows
+func (w *Writer[T]) timeLayout() string {
+	switch {
+	case w.cfg.Window >= 24*time.Hour && w.cfg.Window%(24*time.Hour) == 0:
+		return "2006-01-02"
+	case w.cfg.Window >= time.Hour && w.cfg.Window%time.Hour == 0:
+		return "2006-01-02T15"
+	case w.cfg.Window >= time.Minute && w.cfg.Window%time.Minute == 0:
+		return "2006-01-02T15-04"
+	default:
+		return "2006-01-02T15-04-05"
+	}
+}
+
+// rotate closes the current segment, renames it to its final name and publishes it
+func (w *Writer[T]) rotate() error {
+	if w.segment == nil {
+		return nil
+	}
+
+	segment, file, path := w.segment, w.file, w.path
+	w.segment, w.file, w.counter, w.path = nil, nil, nil, ""
+
+	if err := segment.Close(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to close segment: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	if err := os.Rename(path+inProgressSuffix, path); err != nil {
+		return fmt.Errorf("failed to publish file: %w", err)
+	}
+
+	w.published = append(w.published, path)
+	if w.cfg.OnPublish != nil {
+		w.cfg.OnPublish(path)
+	}
+
+	return nil
+}
+
+// countingWriter tracks bytes written to the current file
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
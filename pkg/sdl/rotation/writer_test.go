@@ -0,0 +1,130 @@
+package rotation
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// lineSegment writes one line per record
+type lineSegment struct {
+	w io.Writer
+}
+
+func (s *lineSegment) Write(records []string) error {
+	for _, r := range records {
+		if _, err := io.WriteString(s.w, r+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *lineSegment) Close() error { return nil }
+
+func newLineWriter(t *testing.T, cfg Config) *Writer[string] {
+	t.Helper()
+	w, err := NewWriter(cfg, func(w io.Writer) (SegmentWriter[string], error) {
+		return &lineSegment{w: w}, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+	return w
+}
+
+func TestRotationByTimeWindow(t *testing.T) {
+	dir := "tmp/test_rotation_time"
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2024, 6, 1, 10, 15, 0, 0, time.UTC)
+	var published []string
+
+	w := newLineWriter(t, Config{
+		Dir:       dir,
+		Prefix:    "users",
+		Extension: ".log",
+		Window:    time.Hour,
+		Now:       func() time.Time { return now },
+		OnPublish: func(path string) { published = append(published, path) },
+	})
+
+	if err := w.Write("a", "b"); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	// In-progress files are not visible under their final name
+	if _, err := os.Stat(filepath.Join(dir, "users-2024-06-01T10.log")); !os.IsNotExist(err) {
+		t.Error("Expected file to be in progress")
+	}
+
+	now = now.Add(time.Hour)
+	if err := w.Write("c"); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if len(published) != 1 || filepath.Base(published[0]) != "users-2024-06-01T10.log" {
+		t.Fatalf("Expected first window published, got %v", published)
+	}
+
+	// Idle rollover without further writes
+	now = now.Add(time.Hour)
+	if err := w.RotateIfDue(); err != nil {
+		t.Fatalf("RotateIfDue failed: %v", err)
+	}
+	if len(published) != 2 || filepath.Base(published[1]) != "users-2024-06-01T11.log" {
+		t.Fatalf("Expected second window published, got %v", published)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	content, err := os.ReadFile(published[0])
+	if err != nil {
+		t.Fatalf("Failed to read published file: %v", err)
+	}
+	if string(content) != "a\nb\n" {
+		t.Errorf("Unexpected content: %q", content)
+	}
+
+	t.Log("✓ Files rotated by time window")
+}
+
+func TestRotationBySize(t *testing.T) {
+	dir := "tmp/test_rotation_size"
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	w := newLineWriter(t, Config{
+		Dir:       dir,
+		Prefix:    "events",
+		Extension: ".log",
+		Window:    24 * time.Hour,
+		MaxBytes:  10,
+		Now:       func() time.Time { return now },
+	})
+
+	for i := 0; i < 5; i++ {
+		if err := w.Write(strings.Repeat("x", 5)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	var names []string
+	for _, path := range w.Published() {
+		names = append(names, filepath.Base(path))
+	}
+
+	expected := []string{"events-2024-06-01.log", "events-2024-06-01-1.log", "events-2024-06-01-2.log"}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+
+	t.Log("✓ Files rotated by size")
+}
@@ -0,0 +1,528 @@
+package xml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+// The documents mirror the Avro models with camelCase element names, the
+// same names as the models' JSON keys. Lists are wrapped (<interests><interest>)
+// and maps are sorted <entry key="..."> lists; empty ones are omitted and read
+// back as nil. Wrappers are pointers because encoding/xml writes an empty
+// a>b parent for an empty slice
+
+// dateLayout is the xs:date form of Product.ReleaseDate
+const dateLayout = "2006-01-02"
+
+type userDoc struct {
+	XMLName   xml.Name    `xml:"user"`
+	ID        int64       `xml:"id"`
+	Email     string      `xml:"email"`
+	Name      string      `xml:"name"`
+	Status    string      `xml:"status"`
+	Profile   *profileDoc `xml:"profile"`
+	CreatedAt time.Time   `xml:"createdAt"`
+	UpdatedAt time.Time   `xml:"updatedAt"`
+}
+
+type profileDoc struct {
+	FirstName string        `xml:"firstName"`
+	LastName  string        `xml:"lastName"`
+	Phone     *string       `xml:"phone"`
+	Address   *addressDoc   `xml:"address"`
+	Interests *interestsDoc `xml:"interests"`
+	Metadata  *entriesDoc   `xml:"metadata"`
+}
+
+type addressDoc struct {
+	Street     string `xml:"street"`
+	City       string `xml:"city"`
+	State      string `xml:"state"`
+	PostalCode string `xml:"postalCode"`
+	Country    string `xml:"country"`
+}
+
+type inventoryDoc struct {
+	Quantity       int32 `xml:"quantity"`
+	Reserved       int32 `xml:"reserved"`
+	Available      int32 `xml:"available"`
+	TrackInventory bool  `xml:"trackInventory"`
+	ReorderLevel   int32 `xml:"reorderLevel"`
+	MaxStock       int32 `xml:"maxStock"`
+}
+
+type shippingAddressDoc struct {
+	RecipientName string `xml:"recipientName"`
+	Street        string `xml:"street"`
+	City          string `xml:"city"`
+	State         string `xml:"state"`
+	PostalCode    string `xml:"postalCode"`
+	Country       string `xml:"country"`
+}
+
+type interestsDoc struct {
+	Values []string `xml:"interest"`
+}
+
+type categoriesDoc struct {
+	Values []string `xml:"category"`
+}
+
+type tagsDoc struct {
+	Values []string `xml:"tag"`
+}
+
+type itemsDoc struct {
+	Items []orderItemDoc `xml:"item"`
+}
+
+type entriesDoc struct {
+	Entries []entryDoc `xml:"entry"`
+}
+
+type entryDoc struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type productDoc struct {
+	XMLName        xml.Name       `xml:"product"`
+	ID             int64          `xml:"id"`
+	Name           string         `xml:"name"`
+	Description    string         `xml:"description"`
+	SKU            string         `xml:"sku"`
+	Price          priceDoc       `xml:"price"`
+	Inventory      inventoryDoc   `xml:"inventory"`
+	Categories     *categoriesDoc `xml:"categories"`
+	Tags           *tagsDoc       `xml:"tags"`
+	Status         string         `xml:"status"`
+	Specifications *entriesDoc    `xml:"specifications"`
+	ReleaseDate    *string        `xml:"releaseDate"`
+	CreatedAt      time.Time      `xml:"createdAt"`
+	UpdatedAt      time.Time      `xml:"updatedAt"`
+}
+
+type priceDoc struct {
+	Currency           string        `xml:"currency"`
+	AmountCents        int64         `xml:"amountCents"`
+	Amount             *avro.Decimal `xml:"amount"`
+	DiscountPercentage *float32      `xml:"discountPercentage"`
+}
+
+type orderDoc struct {
+	XMLName      xml.Name         `xml:"order"`
+	ID           int64            `xml:"id"`
+	UUID         *string          `xml:"uuid"`
+	UserID       int64            `xml:"userId"`
+	OrderNumber  string           `xml:"orderNumber"`
+	Status       string           `xml:"status"`
+	Items        *itemsDoc        `xml:"items"`
+	Summary      orderSummaryDoc  `xml:"summary"`
+	ShippingInfo *shippingInfoDoc `xml:"shippingInfo"`
+	PaymentInfo  *paymentInfoDoc  `xml:"paymentInfo"`
+	CreatedAt    time.Time        `xml:"createdAt"`
+	UpdatedAt    time.Time        `xml:"updatedAt"`
+	ShippedAt    *time.Time       `xml:"shippedAt"`
+	DeliveredAt  *time.Time       `xml:"deliveredAt"`
+}
+
+type orderItemDoc struct {
+	ProductID      int64       `xml:"productId"`
+	ProductName    string      `xml:"productName"`
+	ProductSKU     string      `xml:"productSku"`
+	Quantity       int32       `xml:"quantity"`
+	UnitPrice      priceDoc    `xml:"unitPrice"`
+	TotalPrice     priceDoc    `xml:"totalPrice"`
+	ProductVariant *entriesDoc `xml:"productVariant"`
+}
+
+type orderSummaryDoc struct {
+	Subtotal     priceDoc `xml:"subtotal"`
+	Tax          priceDoc `xml:"tax"`
+	ShippingCost priceDoc `xml:"shippingCost"`
+	Discount     priceDoc `xml:"discount"`
+	Total        priceDoc `xml:"total"`
+	TotalItems   int32    `xml:"totalItems"`
+}
+
+type shippingInfoDoc struct {
+	Address           shippingAddressDoc `xml:"address"`
+	Method            string             `xml:"method"`
+	TrackingNumber    *string            `xml:"trackingNumber"`
+	Carrier           *string            `xml:"carrier"`
+	Cost              priceDoc           `xml:"cost"`
+	EstimatedDelivery *time.Time         `xml:"estimatedDelivery"`
+	DeliveryTime      *string            `xml:"deliveryTime"`
+}
+
+type paymentInfoDoc struct {
+	Method        string     `xml:"method"`
+	Status        string     `xml:"status"`
+	TransactionID *string    `xml:"transactionId"`
+	Amount        priceDoc   `xml:"amount"`
+	ProcessedAt   *time.Time `xml:"processedAt"`
+	AuthorizedAt  *time.Time `xml:"authorizedAt"`
+}
+
+type usersDoc struct {
+	XMLName xml.Name  `xml:"users"`
+	Users   []userDoc `xml:"user"`
+}
+
+type productsDoc struct {
+	XMLName  xml.Name     `xml:"products"`
+	Products []productDoc `xml:"product"`
+}
+
+type ordersDoc struct {
+	XMLName xml.Name   `xml:"orders"`
+	Orders  []orderDoc `xml:"order"`
+}
+
+func toEntries(m map[string]string) *entriesDoc {
+	if len(m) == 0 {
+		return nil
+	}
+	doc := &entriesDoc{Entries: make([]entryDoc, 0, len(m))}
+	for k, v := range m {
+		doc.Entries = append(doc.Entries, entryDoc{Key: k, Value: v})
+	}
+	sort.Slice(doc.Entries, func(i, j int) bool { return doc.Entries[i].Key < doc.Entries[j].Key })
+	return doc
+}
+
+func fromEntries(doc *entriesDoc) map[string]string {
+	if doc == nil || len(doc.Entries) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(doc.Entries))
+	for _, e := range doc.Entries {
+		m[e.Key] = e.Value
+	}
+	return m
+}
+
+func nilIfEmpty(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+func utc(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.Time{}
+	}
+	return t.UTC()
+}
+
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := utc(*t)
+	return &u
+}
+
+// formatDuration writes d as an xs:duration in seconds, e.g. PT50400S
+func formatDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	secs, nanos := int64(d/time.Second), int64(d%time.Second)
+	if nanos == 0 {
+		return fmt.Sprintf("%sPT%dS", sign, secs)
+	}
+	frac := strings.TrimRight(fmt.Sprintf("%09d", nanos), "0")
+	return fmt.Sprintf("%sPT%d.%sS", sign, secs, frac)
+}
+
+var dayTimeDuration = regexp.MustCompile(`^(-)?P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)(?:\.(\d{1,9}))?S)?)?$`)
+
+// parseDuration reads an xs:duration made of days, hours, minutes and
+// seconds. Years and months have no fixed length and are rejected
+func parseDuration(s string) (time.Duration, error) {
+	m := dayTimeDuration.FindStringSubmatch(s)
+	if m == nil || strings.HasSuffix(s, "P") || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("invalid day-time duration %q", s)
+	}
+	var d time.Duration
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second}
+	for i, unit := range units {
+		if m[i+2] == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(m[i+2], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid day-time duration %q: %w", s, err)
+		}
+		d += time.Duration(n) * unit
+	}
+	if m[6] != "" {
+		nanos, _ := strconv.ParseInt((m[6] + "00000000")[:9], 10, 64)
+		d += time.Duration(nanos)
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+func toPriceDoc(p avro.Price) priceDoc {
+	return priceDoc{
+		Currency:           p.Currency,
+		AmountCents:        p.AmountCents,
+		Amount:             p.Amount,
+		DiscountPercentage: p.DiscountPercentage,
+	}
+}
+
+func (p priceDoc) model() avro.Price {
+	return avro.Price{
+		Currency:           p.Currency,
+		AmountCents:        p.AmountCents,
+		Amount:             p.Amount,
+		DiscountPercentage: p.DiscountPercentage,
+	}
+}
+
+func toUserDoc(u avro.User) userDoc {
+	doc := userDoc{
+		ID:        u.ID,
+		Email:     u.Email,
+		Name:      u.Name,
+		Status:    string(u.Status),
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+	if p := u.Profile; p != nil {
+		doc.Profile = &profileDoc{
+			FirstName: p.FirstName,
+			LastName:  p.LastName,
+			Phone:     p.Phone,
+			Metadata:  toEntries(p.Metadata),
+		}
+		if a := p.Address; a != nil {
+			address := addressDoc(*a)
+			doc.Profile.Address = &address
+		}
+		if len(p.Interests) > 0 {
+			doc.Profile.Interests = &interestsDoc{Values: p.Interests}
+		}
+	}
+	return doc
+}
+
+func (doc userDoc) model() avro.User {
+	user := avro.User{
+		ID:        doc.ID,
+		Email:     doc.Email,
+		Name:      doc.Name,
+		Status:    avro.UserStatus(doc.Status),
+		CreatedAt: utc(doc.CreatedAt),
+		UpdatedAt: utc(doc.UpdatedAt),
+	}
+	if p := doc.Profile; p != nil {
+		user.Profile = &avro.Profile{
+			FirstName: p.FirstName,
+			LastName:  p.LastName,
+			Phone:     p.Phone,
+			Metadata:  fromEntries(p.Metadata),
+		}
+		if a := p.Address; a != nil {
+			address := avro.Address(*a)
+			user.Profile.Address = &address
+		}
+		if p.Interests != nil {
+			user.Profile.Interests = nilIfEmpty(p.Interests.Values)
+		}
+	}
+	return user
+}
+
+func toProductDoc(p avro.Product) productDoc {
+	doc := productDoc{
+		ID:             p.ID,
+		Name:           p.Name,
+		Description:    p.Description,
+		SKU:            p.SKU,
+		Price:          toPriceDoc(p.Price),
+		Inventory:      inventoryDoc(p.Inventory),
+		Status:         string(p.Status),
+		Specifications: toEntries(p.Specifications),
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}
+	if len(p.Categories) > 0 {
+		doc.Categories = &categoriesDoc{Values: p.Categories}
+	}
+	if len(p.Tags) > 0 {
+		doc.Tags = &tagsDoc{Values: p.Tags}
+	}
+	if p.ReleaseDate != nil {
+		date := p.ReleaseDate.UTC().Format(dateLayout)
+		doc.ReleaseDate = &date
+	}
+	return doc
+}
+
+func (doc productDoc) model() (avro.Product, error) {
+	product := avro.Product{
+		ID:             doc.ID,
+		Name:           doc.Name,
+		Description:    doc.Description,
+		SKU:            doc.SKU,
+		Price:          doc.Price.model(),
+		Inventory:      avro.Inventory(doc.Inventory),
+		Status:         avro.ProductStatus(doc.Status),
+		Specifications: fromEntries(doc.Specifications),
+		CreatedAt:      utc(doc.CreatedAt),
+		UpdatedAt:      utc(doc.UpdatedAt),
+	}
+	if doc.Categories != nil {
+		product.Categories = nilIfEmpty(doc.Categories.Values)
+	}
+	if doc.Tags != nil {
+		product.Tags = nilIfEmpty(doc.Tags.Values)
+	}
+	if doc.ReleaseDate != nil {
+		date, err := time.Parse(dateLayout, strings.TrimSpace(*doc.ReleaseDate))
+		if err != nil {
+			return avro.Product{}, fmt.Errorf("invalid release date: %w", err)
+		}
+		product.ReleaseDate = &date
+	}
+	return product, nil
+}
+
+func toOrderDoc(o avro.Order) orderDoc {
+	doc := orderDoc{
+		ID:          o.ID,
+		UUID:        o.UUID,
+		UserID:      o.UserID,
+		OrderNumber: o.OrderNumber,
+		Status:      string(o.Status),
+		Summary: orderSummaryDoc{
+			Subtotal:     toPriceDoc(o.Summary.Subtotal),
+			Tax:          toPriceDoc(o.Summary.Tax),
+			ShippingCost: toPriceDoc(o.Summary.ShippingCost),
+			Discount:     toPriceDoc(o.Summary.Discount),
+			Total:        toPriceDoc(o.Summary.Total),
+			TotalItems:   o.Summary.TotalItems,
+		},
+		CreatedAt:   o.CreatedAt,
+		UpdatedAt:   o.UpdatedAt,
+		ShippedAt:   o.ShippedAt,
+		DeliveredAt: o.DeliveredAt,
+	}
+	if len(o.Items) > 0 {
+		doc.Items = &itemsDoc{}
+	}
+	for _, item := range o.Items {
+		doc.Items.Items = append(doc.Items.Items, orderItemDoc{
+			ProductID:      item.ProductID,
+			ProductName:    item.ProductName,
+			ProductSKU:     item.ProductSKU,
+			Quantity:       item.Quantity,
+			UnitPrice:      toPriceDoc(item.UnitPrice),
+			TotalPrice:     toPriceDoc(item.TotalPrice),
+			ProductVariant: toEntries(item.ProductVariant),
+		})
+	}
+	if s := o.ShippingInfo; s != nil {
+		doc.ShippingInfo = &shippingInfoDoc{
+			Address:           shippingAddressDoc(s.Address),
+			Method:            s.Method,
+			TrackingNumber:    s.TrackingNumber,
+			Carrier:           s.Carrier,
+			Cost:              toPriceDoc(s.Cost),
+			EstimatedDelivery: s.EstimatedDelivery,
+		}
+		if s.DeliveryTime != nil {
+			d := formatDuration(*s.DeliveryTime)
+			doc.ShippingInfo.DeliveryTime = &d
+		}
+	}
+	if p := o.PaymentInfo; p != nil {
+		doc.PaymentInfo = &paymentInfoDoc{
+			Method:        p.Method,
+			Status:        string(p.Status),
+			TransactionID: p.TransactionID,
+			Amount:        toPriceDoc(p.Amount),
+			ProcessedAt:   p.ProcessedAt,
+			AuthorizedAt:  p.AuthorizedAt,
+		}
+	}
+	return doc
+}
+
+func (doc orderDoc) model() (avro.Order, error) {
+	order := avro.Order{
+		ID:          doc.ID,
+		UUID:        doc.UUID,
+		UserID:      doc.UserID,
+		OrderNumber: doc.OrderNumber,
+		Status:      avro.OrderStatus(doc.Status),
+		Summary: avro.OrderSummary{
+			Subtotal:     doc.Summary.Subtotal.model(),
+			Tax:          doc.Summary.Tax.model(),
+			ShippingCost: doc.Summary.ShippingCost.model(),
+			Discount:     doc.Summary.Discount.model(),
+			Total:        doc.Summary.Total.model(),
+			TotalItems:   doc.Summary.TotalItems,
+		},
+		CreatedAt:   utc(doc.CreatedAt),
+		UpdatedAt:   utc(doc.UpdatedAt),
+		ShippedAt:   utcPtr(doc.ShippedAt),
+		DeliveredAt: utcPtr(doc.DeliveredAt),
+	}
+	if doc.Items != nil {
+		for _, item := range doc.Items.Items {
+			order.Items = append(order.Items, avro.OrderItem{
+				ProductID:      item.ProductID,
+				ProductName:    item.ProductName,
+				ProductSKU:     item.ProductSKU,
+				Quantity:       item.Quantity,
+				UnitPrice:      item.UnitPrice.model(),
+				TotalPrice:     item.TotalPrice.model(),
+				ProductVariant: fromEntries(item.ProductVariant),
+			})
+		}
+	}
+	if s := doc.ShippingInfo; s != nil {
+		order.ShippingInfo = &avro.ShippingInfo{
+			Address:           avro.ShippingAddress(s.Address),
+			Method:            s.Method,
+			TrackingNumber:    s.TrackingNumber,
+			Carrier:           s.Carrier,
+			Cost:              s.Cost.model(),
+			EstimatedDelivery: utcPtr(s.EstimatedDelivery),
+		}
+		if s.DeliveryTime != nil {
+			d, err := parseDuration(strings.TrimSpace(*s.DeliveryTime))
+			if err != nil {
+				return avro.Order{}, fmt.Errorf("invalid delivery time: %w", err)
+			}
+			order.ShippingInfo.DeliveryTime = &d
+		}
+	}
+	if p := doc.PaymentInfo; p != nil {
+		order.PaymentInfo = &avro.PaymentInfo{
+			Method:        p.Method,
+			Status:        avro.PaymentStatus(p.Status),
+			TransactionID: p.TransactionID,
+			Amount:        p.Amount.model(),
+			ProcessedAt:   utcPtr(p.ProcessedAt),
+			AuthorizedAt:  utcPtr(p.AuthorizedAt),
+		}
+	}
+	return order, nil
+}
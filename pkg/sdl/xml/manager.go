@@ -0,0 +1,327 @@
+// Package xml serializes the shared Avro models as XML documents and
+// validates documents against XML Schemas (XSD). The documents are described
+// by schemas/models.xsd; see ModelsSchema.
+package xml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+// ContentType is the media type of XML payloads
+const ContentType = "application/xml"
+
+// FileExtension is the extension of XML files
+const FileExtension = ".xml"
+
+// Manager handles XML serialization and deserialization
+type Manager struct {
+	baseDir string
+	indent  bool
+	schema  *Schema
+}
+
+var _ types.Serializer = (*Manager)(nil)
+
+// NewManager creates a new XML manager writing files under baseDir
+func NewManager(baseDir string) *Manager {
+	if baseDir == "" {
+		baseDir = "data/xml"
+	}
+	return &Manager{baseDir: baseDir}
+}
+
+// WithIndent writes documents indented by two spaces per level
+func (m *Manager) WithIndent(on bool) *Manager {
+	m.indent = on
+	return m
+}
+
+// WithSchema validates every document written and read against schema, e.g.
+// ModelsSchema(). A nil schema turns validation off
+func (m *Manager) WithSchema(schema *Schema) *Manager {
+	m.schema = schema
+	return m
+}
+
+// ContentType returns the media type of XML payloads
+func (m *Manager) ContentType() string {
+	return ContentType
+}
+
+// FileExtension returns the extension of XML files
+func (m *Manager) FileExtension() string {
+	return FileExtension
+}
+
+// Validate checks data against the manager's schema, if it has one
+func (m *Manager) Validate(data []byte) error {
+	if m.schema == nil {
+		return nil
+	}
+	if err := m.schema.Validate(data); err != nil {
+		return fmt.Errorf("document does not match schema: %w", err)
+	}
+	return nil
+}
+
+// document returns the XML document for the models and their slices, or
+// data itself for any other value
+func document(data any) any {
+	switch v := data.(type) {
+	case avro.User:
+		return toUserDoc(v)
+	case *avro.User:
+		return toUserDoc(*v)
+	case []avro.User:
+		doc := usersDoc{Users: make([]userDoc, len(v))}
+		for i, u := range v {
+			doc.Users[i] = toUserDoc(u)
+		}
+		return doc
+	case avro.Product:
+		return toProductDoc(v)
+	case *avro.Product:
+		return toProductDoc(*v)
+	case []avro.Product:
+		doc := productsDoc{Products: make([]productDoc, len(v))}
+		for i, p := range v {
+			doc.Products[i] = toProductDoc(p)
+		}
+		return doc
+	case avro.Order:
+		return toOrderDoc(v)
+	case *avro.Order:
+		return toOrderDoc(*v)
+	case []avro.Order:
+		doc := ordersDoc{Orders: make([]orderDoc, len(v))}
+		for i, o := range v {
+			doc.Orders[i] = toOrderDoc(o)
+		}
+		return doc
+	}
+	return data
+}
+
+// Serialize encodes a value as an XML document with an XML declaration.
+// Users, products and orders and slices of them use the model documents;
+// other values are marshaled with encoding/xml
+func (m *Manager) Serialize(data any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	if m.indent {
+		enc.Indent("", "  ")
+	}
+	if err := enc.Encode(document(data)); err != nil {
+		return nil, fmt.Errorf("failed to encode: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode: %w", err)
+	}
+	buf.WriteByte('\n')
+	if err := m.Validate(buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Deserialize decodes an XML document into target, a pointer. Pointers to
+// the models and to slices of them read the model documents
+func (m *Manager) Deserialize(data []byte, target any) error {
+	if len(data) == 0 {
+		return fmt.Errorf("data cannot be empty")
+	}
+	if err := m.Validate(data); err != nil {
+		return err
+	}
+
+	switch t := target.(type) {
+	case *avro.User:
+		var doc userDoc
+		if err := unmarshal(data, &doc); err != nil {
+			return err
+		}
+		*t = doc.model()
+	case *[]avro.User:
+		var doc usersDoc
+		if err := unmarshal(data, &doc); err != nil {
+			return err
+		}
+		users := make([]avro.User, len(doc.Users))
+		for i, u := range doc.Users {
+			users[i] = u.model()
+		}
+		*t = users
+	case *avro.Product:
+		var doc productDoc
+		if err := unmarshal(data, &doc); err != nil {
+			return err
+		}
+		product, err := doc.model()
+		if err != nil {
+			return err
+		}
+		*t = product
+	case *[]avro.Product:
+		var doc productsDoc
+		if err := unmarshal(data, &doc); err != nil {
+			return err
+		}
+		products := make([]avro.Product, len(doc.Products))
+		for i, p := range doc.Products {
+			product, err := p.model()
+			if err != nil {
+				return fmt.Errorf("product %d: %w", i, err)
+			}
+			products[i] = product
+		}
+		*t = products
+	case *avro.Order:
+		var doc orderDoc
+		if err := unmarshal(data, &doc); err != nil {
+			return err
+		}
+		order, err := doc.model()
+		if err != nil {
+			return err
+		}
+		*t = order
+	case *[]avro.Order:
+		var doc ordersDoc
+		if err := unmarshal(data, &doc); err != nil {
+			return err
+		}
+		orders := make([]avro.Order, len(doc.Orders))
+		for i, o := range doc.Orders {
+			order, err := o.model()
+			if err != nil {
+				return fmt.Errorf("order %d: %w", i, err)
+			}
+			orders[i] = order
+		}
+		*t = orders
+	default:
+		return unmarshal(data, target)
+	}
+	return nil
+}
+
+func unmarshal(data []byte, v any) error {
+	if err := xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode: %w", err)
+	}
+	return nil
+}
+
+// SerializeUser serializes a user to an XML document
+func (m *Manager) SerializeUser(user avro.User) ([]byte, error) {
+	return m.Serialize(user)
+}
+
+// DeserializeUser deserializes a user from an XML document
+func (m *Manager) DeserializeUser(data []byte) (avro.User, error) {
+	var user avro.User
+	if err := m.Deserialize(data, &user); err != nil {
+		return avro.User{}, fmt.Errorf("failed to deserialize user: %w", err)
+	}
+	return user, nil
+}
+
+// SerializeProduct serializes a product to an XML document
+func (m *Manager) SerializeProduct(product avro.Product) ([]byte, error) {
+	return m.Serialize(product)
+}
+
+// DeserializeProduct deserializes a product from an XML document
+func (m *Manager) DeserializeProduct(data []byte) (avro.Product, error) {
+	var product avro.Product
+	if err := m.Deserialize(data, &product); err != nil {
+		return avro.Product{}, fmt.Errorf("failed to deserialize product: %w", err)
+	}
+	return product, nil
+}
+
+// SerializeOrder serializes an order to an XML document
+func (m *Manager) SerializeOrder(order avro.Order) ([]byte, error) {
+	return m.Serialize(order)
+}
+
+// DeserializeOrder deserializes an order from an XML document
+func (m *Manager) DeserializeOrder(data []byte) (avro.Order, error) {
+	var order avro.Order
+	if err := m.Deserialize(data, &order); err != nil {
+		return avro.Order{}, fmt.Errorf("failed to deserialize order: %w", err)
+	}
+	return order, nil
+}
+
+// WriteUsersToFile writes users as one <users> document under the base directory
+func (m *Manager) WriteUsersToFile(filename string, users []avro.User) error {
+	return m.writeFile(filename, users)
+}
+
+// ReadUsersFromFile reads a <users> document under the base directory
+func (m *Manager) ReadUsersFromFile(filename string) ([]avro.User, error) {
+	var users []avro.User
+	if err := m.readFile(filename, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// WriteProductsToFile writes products as one <products> document under the base directory
+func (m *Manager) WriteProductsToFile(filename string, products []avro.Product) error {
+	return m.writeFile(filename, products)
+}
+
+// ReadProductsFromFile reads a <products> document under the base directory
+func (m *Manager) ReadProductsFromFile(filename string) ([]avro.Product, error) {
+	var products []avro.Product
+	if err := m.readFile(filename, &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// WriteOrdersToFile writes orders as one <orders> document under the base directory
+func (m *Manager) WriteOrdersToFile(filename string, orders []avro.Order) error {
+	return m.writeFile(filename, orders)
+}
+
+// ReadOrdersFromFile reads an <orders> document under the base directory
+func (m *Manager) ReadOrdersFromFile(filename string) ([]avro.Order, error) {
+	var orders []avro.Order
+	if err := m.readFile(filename, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (m *Manager) writeFile(filename string, values any) error {
+	data, err := m.Serialize(values)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.baseDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(m.baseDir, filename), data, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+func (m *Manager) readFile(filename string, target any) error {
+	data, err := os.ReadFile(filepath.Join(m.baseDir, filename))
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	return m.Deserialize(data, target)
+}
@@ -0,0 +1,251 @@
+package xml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+func samples(t *testing.T) *avro.Manager {
+	t.Helper()
+	manager, err := avro.NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create avro manager: %v", err)
+	}
+	return manager.WithClock(testutil.NewDefaultFakeClock())
+}
+
+func sampleOrder(id int64) avro.Order {
+	created := time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.UTC)
+	shipped, estimated := created.Add(2*time.Hour), created.Add(72*time.Hour)
+	deliveryTime := 14*time.Hour + 1500*time.Millisecond
+	uuid, tracking, carrier, transaction := "0b6f9c1e-3f4a-4c55-9a1e-6d2f0c1b2a3d", "TRK00000001", "", "txn_1"
+	usd := func(cents int64) avro.Price { return avro.Price{Currency: "USD", AmountCents: cents} }
+	return avro.Order{
+		ID:          id,
+		UUID:        &uuid,
+		UserID:      7,
+		OrderNumber: "ORD-000001",
+		Status:      avro.OrderStatusShipped,
+		Items: []avro.OrderItem{
+			{ProductID: 1, ProductName: "Sensor <v2> & co", ProductSKU: "SKU-000001", Quantity: 2, UnitPrice: usd(1500), TotalPrice: usd(3000),
+				ProductVariant: map[string]string{"color": "red", "size": "m"}},
+			{ProductID: 2, ProductName: "Gateway", ProductSKU: "SKU-000002", Quantity: 1, UnitPrice: usd(9900), TotalPrice: usd(9900)},
+		},
+		Summary: avro.OrderSummary{
+			Subtotal: usd(12900), Tax: usd(1290), ShippingCost: usd(500), Discount: usd(0), Total: usd(14690), TotalItems: 2,
+		},
+		ShippingInfo: &avro.ShippingInfo{
+			Address:           avro.ShippingAddress{RecipientName: "User 7", Street: "1 Main St", City: "Test City", State: "TS", PostalCode: "10000", Country: "USA"},
+			Method:            "standard",
+			TrackingNumber:    &tracking,
+			Carrier:           &carrier,
+			Cost:              usd(500),
+			EstimatedDelivery: &estimated,
+			DeliveryTime:      &deliveryTime,
+		},
+		PaymentInfo: &avro.PaymentInfo{
+			Method:        "credit_card",
+			Status:        avro.PaymentStatusCaptured,
+			TransactionID: &transaction,
+			Amount:        usd(14690),
+			ProcessedAt:   &shipped,
+		},
+		CreatedAt: created,
+		UpdatedAt: shipped,
+		ShippedAt: &shipped,
+	}
+}
+
+func TestXMLRoundTrip(t *testing.T) {
+	manager := NewManager("").WithSchema(ModelsSchema())
+
+	for _, user := range samples(t).CreateSampleUsers(3) {
+		data, err := manager.SerializeUser(user)
+		if err != nil {
+			t.Fatalf("Failed to serialize user: %v", err)
+		}
+		back, err := manager.DeserializeUser(data)
+		if err != nil {
+			t.Fatalf("Failed to deserialize user: %v", err)
+		}
+		if !reflect.DeepEqual(back, user) {
+			t.Errorf("User changed across XML:\n got %+v\nwant %+v", back, user)
+		}
+	}
+
+	product := samples(t).CreateSampleProducts(2)[1]
+	release := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	discount := float32(0.15)
+	product.ReleaseDate = &release
+	product.Price.DiscountPercentage = &discount
+	product.Price.Amount = avro.NewDecimal(123456, 2)
+	data, err := manager.SerializeProduct(product)
+	if err != nil {
+		t.Fatalf("Failed to serialize product: %v", err)
+	}
+	if !bytes.Contains(data, []byte("<amount>1234.56</amount>")) || !bytes.Contains(data, []byte("<releaseDate>2024-06-01</releaseDate>")) {
+		t.Errorf("Expected a decimal amount and an xs:date release date, got %s", data)
+	}
+	backProduct, err := manager.DeserializeProduct(data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize product: %v", err)
+	}
+	if backProduct.Price.Amount.Cmp(product.Price.Amount) != 0 {
+		t.Errorf("Expected amount %s, got %s", product.Price.Amount, backProduct.Price.Amount)
+	}
+	backProduct.Price.Amount, product.Price.Amount = nil, nil
+	if !reflect.DeepEqual(backProduct, product) {
+		t.Errorf("Product changed across XML:\n got %+v\nwant %+v", backProduct, product)
+	}
+
+	// Through the Serializer interface; markup in text is escaped and the
+	// empty carrier stays distinct from nil
+	order := sampleOrder(1)
+	data, err = manager.WithIndent(true).Serialize(&order)
+	if err != nil {
+		t.Fatalf("Failed to serialize order: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte(xml.Header)) || !bytes.Contains(data, []byte("<deliveryTime>PT50401.5S</deliveryTime>")) ||
+		!bytes.Contains(data, []byte("Sensor &lt;v2&gt; &amp; co")) || !bytes.Contains(data, []byte(`<entry key="color">red</entry>`)) {
+		t.Errorf("Unexpected order document:\n%s", data)
+	}
+	var backOrder avro.Order
+	if err := manager.Deserialize(data, &backOrder); err != nil {
+		t.Fatalf("Failed to deserialize order: %v", err)
+	}
+	if !reflect.DeepEqual(backOrder, order) {
+		t.Errorf("Order changed across XML:\n got %+v\nwant %+v", backOrder, order)
+	}
+
+	// Other values go through encoding/xml
+	type reading struct {
+		XMLName xml.Name `xml:"reading"`
+		Sensor  string   `xml:"sensor,attr"`
+		Value   float64  `xml:"value"`
+	}
+	plain := NewManager("")
+	data, err = plain.Serialize(reading{Sensor: "t1", Value: 21.5})
+	if err != nil {
+		t.Fatalf("Failed to serialize reading: %v", err)
+	}
+	var backReading reading
+	if err := plain.Deserialize(data, &backReading); err != nil || backReading.Sensor != "t1" || backReading.Value != 21.5 {
+		t.Errorf("Reading changed across XML: %+v (%v)", backReading, err)
+	}
+
+	// Empty lists and maps are left out and read back as nil
+	bare := samples(t).CreateSampleUsers(1)[0]
+	bare.Profile.Interests, bare.Profile.Metadata = []string{}, map[string]string{}
+	data, err = manager.SerializeUser(bare)
+	if err != nil {
+		t.Fatalf("Failed to serialize user: %v", err)
+	}
+	if bytes.Contains(data, []byte("<interests")) || bytes.Contains(data, []byte("<metadata")) {
+		t.Errorf("Expected empty lists to be omitted, got %s", data)
+	}
+	if back, err := manager.DeserializeUser(data); err != nil || back.Profile.Interests != nil || back.Profile.Metadata != nil {
+		t.Errorf("Expected nil interests and metadata, got %+v (%v)", back.Profile, err)
+	}
+
+	if _, err := manager.DeserializeProduct([]byte(xml.Header + "<user/>")); err == nil {
+		t.Error("Expected a user document to be rejected as a product")
+	}
+	if manager.ContentType() != "application/xml" || manager.FileExtension() != ".xml" {
+		t.Errorf("Unexpected content type %s or extension %s", manager.ContentType(), manager.FileExtension())
+	}
+
+	t.Log("✓ Users, products and orders round trip through XML")
+}
+
+func TestXMLSchemaValidation(t *testing.T) {
+	user := samples(t).CreateSampleUsers(1)[0]
+
+	// Invalid models are caught before they are written
+	invalid := user
+	invalid.Status = "BANNED"
+	invalid.Name = ""
+	_, err := NewManager("").WithSchema(ModelsSchema()).SerializeUser(invalid)
+	if err == nil {
+		t.Fatal("Expected an invalid user to fail validation")
+	}
+	for _, want := range []string{`/user/name: length 0`, `/user/status: "BANNED" is not one of`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in %v", want, err)
+		}
+	}
+
+	// Without a schema the same document is written, and checked when read
+	data, err := NewManager("").SerializeUser(invalid)
+	if err != nil {
+		t.Fatalf("Failed to serialize without a schema: %v", err)
+	}
+	if _, err := NewManager("").WithSchema(ModelsSchema()).DeserializeUser(data); err == nil {
+		t.Error("Expected validation to reject the document when read")
+	}
+
+	// Unknown and misordered elements, which encoding/xml would ignore
+	data, _ = NewManager("").SerializeUser(user)
+	extra := bytes.Replace(data, []byte("<name>"), []byte("<nickname>x</nickname><name>"), 1)
+	if err := ModelsSchema().Validate(extra); err == nil || !strings.Contains(err.Error(), "expected <name>, got <nickname>") {
+		t.Errorf("Expected an unknown element to be reported, got %v", err)
+	}
+	if _, err := NewManager("").DeserializeUser(extra); err != nil {
+		t.Errorf("Expected encoding/xml alone to accept the unknown element, got %v", err)
+	}
+
+	order := sampleOrder(1)
+	data, _ = NewManager("").SerializeOrder(order)
+	bad := bytes.Replace(data, []byte("<quantity>1</quantity>"), []byte("<quantity>-1</quantity>"), 1)
+	if err := ModelsSchema().Validate(bad); err == nil || !strings.Contains(err.Error(), "/order/items/item[2]/quantity: -1 is less than 0") {
+		t.Errorf("Expected the negative quantity of the second item to be reported, got %v", err)
+	}
+
+	t.Log("✓ Documents are validated against the models schema")
+}
+
+func TestXMLFiles(t *testing.T) {
+	testDir := "tmp/test_xml_files"
+	manager := NewManager(testDir).WithSchema(ModelsSchema())
+	defer os.RemoveAll(testDir)
+
+	users := samples(t).CreateSampleUsers(10)
+	if err := manager.WriteUsersToFile("users.xml", users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	if back, err := manager.ReadUsersFromFile("users.xml"); err != nil || !reflect.DeepEqual(back, users) {
+		t.Errorf("Users changed across the file (%v)", err)
+	}
+
+	products := samples(t).CreateSampleProducts(5)
+	if err := manager.WriteProductsToFile("products.xml", products); err != nil {
+		t.Fatalf("Failed to write products: %v", err)
+	}
+	if back, err := manager.ReadProductsFromFile("products.xml"); err != nil || !reflect.DeepEqual(back, products) {
+		t.Errorf("Products changed across the file (%v)", err)
+	}
+
+	orders := []avro.Order{sampleOrder(1), sampleOrder(2)}
+	if err := manager.WriteOrdersToFile("orders.xml", orders); err != nil {
+		t.Fatalf("Failed to write orders: %v", err)
+	}
+	if back, err := manager.ReadOrdersFromFile("orders.xml"); err != nil || !reflect.DeepEqual(back, orders) {
+		t.Errorf("Orders changed across the file (%v)", err)
+	}
+
+	if _, err := manager.ReadUsersFromFile("products.xml"); err == nil {
+		t.Error("Expected a products file to be rejected as users")
+	}
+	if _, err := manager.ReadUsersFromFile("missing.xml"); err == nil {
+		t.Error("Expected a missing file to fail")
+	}
+
+	t.Log("✓ XML files hold one collection document")
+}
@@ -0,0 +1,778 @@
+package xml
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Schema is a compiled XML Schema (XSD) for validating documents. It supports
+// the subset the model schemas use: global elements, named and anonymous
+// complex types with a sequence of elements (minOccurs/maxOccurs), attributes,
+// simple content, and simple types restricting a built-in type by
+// enumeration, pattern, length and inclusive bounds. Other constructs such as
+// choice, all, groups and imports are rejected when the schema is parsed.
+// Elements are matched by local name
+type Schema struct {
+	targetNamespace string
+	elements        map[string]*elementDecl
+}
+
+type elementDecl struct {
+	name     string
+	min, max int // max < 0 is unbounded
+	simple   *simpleType
+	complex  *complexType
+}
+
+type complexType struct {
+	children   []*elementDecl
+	attributes []attributeDecl
+	// text is the type of simple content; nil for element-only content
+	text *simpleType
+}
+
+type attributeDecl struct {
+	name     string
+	typ      *simpleType
+	required bool
+}
+
+type simpleType struct {
+	builtin              string
+	enumeration          []string
+	patterns             []*regexp.Regexp
+	minLength, maxLength int // < 0 is unset
+	minInclusive         *big.Rat
+	maxInclusive         *big.Rat
+}
+
+//go:embed schemas/models.xsd
+var modelsXSD []byte
+
+var modelsSchema = mustParseSchema(modelsXSD)
+
+// ModelsSchema returns the schema of the documents the Manager writes
+// (schemas/models.xsd): user, product and order and their users, products and
+// orders collections
+func ModelsSchema() *Schema {
+	return modelsSchema
+}
+
+func mustParseSchema(data []byte) *Schema {
+	schema, err := ParseSchema(data)
+	if err != nil {
+		panic(fmt.Sprintf("xml: invalid embedded schema: %v", err))
+	}
+	return schema
+}
+
+// LoadSchema parses the XSD file at path
+func LoadSchema(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	return ParseSchema(data)
+}
+
+// The XSD as written; unsupported collects any construct outside the subset
+
+type xsdSchema struct {
+	TargetNamespace string           `xml:"targetNamespace,attr"`
+	Elements        []xsdElement     `xml:"element"`
+	ComplexTypes    []xsdComplexType `xml:"complexType"`
+	SimpleTypes     []xsdSimpleType  `xml:"simpleType"`
+	Annotations     []struct{}       `xml:"annotation"`
+	Unsupported     []xsdAny         `xml:",any"`
+}
+
+type xsdElement struct {
+	Name        string          `xml:"name,attr"`
+	Type        string          `xml:"type,attr"`
+	MinOccurs   string          `xml:"minOccurs,attr"`
+	MaxOccurs   string          `xml:"maxOccurs,attr"`
+	ComplexType *xsdComplexType `xml:"complexType"`
+	SimpleType  *xsdSimpleType  `xml:"simpleType"`
+	Annotations []struct{}      `xml:"annotation"`
+	Unsupported []xsdAny        `xml:",any"`
+}
+
+type xsdComplexType struct {
+	Name          string            `xml:"name,attr"`
+	Sequence      *xsdSequence      `xml:"sequence"`
+	Attributes    []xsdAttribute    `xml:"attribute"`
+	SimpleContent *xsdSimpleContent `xml:"simpleContent"`
+	Annotations   []struct{}        `xml:"annotation"`
+	Unsupported   []xsdAny          `xml:",any"`
+}
+
+type xsdSequence struct {
+	Elements    []xsdElement `xml:"element"`
+	Annotations []struct{}   `xml:"annotation"`
+	Unsupported []xsdAny     `xml:",any"`
+}
+
+type xsdSimpleContent struct {
+	Extension struct {
+		Base        string         `xml:"base,attr"`
+		Attributes  []xsdAttribute `xml:"attribute"`
+		Unsupported []xsdAny       `xml:",any"`
+	} `xml:"extension"`
+	Unsupported []xsdAny `xml:",any"`
+}
+
+type xsdAttribute struct {
+	Name       string         `xml:"name,attr"`
+	Type       string         `xml:"type,attr"`
+	Use        string         `xml:"use,attr"`
+	SimpleType *xsdSimpleType `xml:"simpleType"`
+}
+
+type xsdSimpleType struct {
+	Name        string          `xml:"name,attr"`
+	Restriction *xsdRestriction `xml:"restriction"`
+	Annotations []struct{}      `xml:"annotation"`
+	Unsupported []xsdAny        `xml:",any"`
+}
+
+type xsdRestriction struct {
+	Base         string     `xml:"base,attr"`
+	Enumerations []xsdFacet `xml:"enumeration"`
+	Patterns     []xsdFacet `xml:"pattern"`
+	MinLength    *xsdFacet  `xml:"minLength"`
+	MaxLength    *xsdFacet  `xml:"maxLength"`
+	MinInclusive *xsdFacet  `xml:"minInclusive"`
+	MaxInclusive *xsdFacet  `xml:"maxInclusive"`
+	Unsupported  []xsdAny   `xml:",any"`
+}
+
+type xsdFacet struct {
+	Value string `xml:"value,attr"`
+}
+
+type xsdAny struct {
+	XMLName xml.Name
+}
+
+func unsupported(where string, extra []xsdAny) error {
+	if len(extra) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s: unsupported XSD construct <%s>", where, extra[0].XMLName.Local)
+}
+
+// schemaCompiler resolves named types, which may be referenced before they
+// are defined
+type schemaCompiler struct {
+	complexDefs map[string]*xsdComplexType
+	simpleDefs  map[string]*xsdSimpleType
+	complexes   map[string]*complexType
+	simples     map[string]*simpleType
+	resolving   map[string]bool
+}
+
+// ParseSchema compiles an XSD document
+func ParseSchema(data []byte) (*Schema, error) {
+	var raw xsdSchema
+	if err := xml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	if err := unsupported("schema", raw.Unsupported); err != nil {
+		return nil, err
+	}
+
+	c := &schemaCompiler{
+		complexDefs: make(map[string]*xsdComplexType),
+		simpleDefs:  make(map[string]*xsdSimpleType),
+		complexes:   make(map[string]*complexType),
+		simples:     make(map[string]*simpleType),
+		resolving:   make(map[string]bool),
+	}
+	for i := range raw.ComplexTypes {
+		c.complexDefs[raw.ComplexTypes[i].Name] = &raw.ComplexTypes[i]
+		// Allocated up front so recursive and forward references share it
+		c.complexes[raw.ComplexTypes[i].Name] = &complexType{}
+	}
+	for i := range raw.SimpleTypes {
+		c.simpleDefs[raw.SimpleTypes[i].Name] = &raw.SimpleTypes[i]
+	}
+	for name, def := range c.complexDefs {
+		if err := c.fillComplex(c.complexes[name], def, "complexType "+name); err != nil {
+			return nil, err
+		}
+	}
+
+	schema := &Schema{targetNamespace: raw.TargetNamespace, elements: make(map[string]*elementDecl)}
+	for _, el := range raw.Elements {
+		decl, err := c.element(el, true)
+		if err != nil {
+			return nil, err
+		}
+		schema.elements[decl.name] = decl
+	}
+	if len(schema.elements) == 0 {
+		return nil, fmt.Errorf("schema declares no global elements")
+	}
+	return schema, nil
+}
+
+func localName(qname string) string {
+	if i := strings.IndexByte(qname, ':'); i >= 0 {
+		return qname[i+1:]
+	}
+	return qname
+}
+
+func occurs(value string, def int) (int, error) {
+	switch value {
+	case "":
+		return def, nil
+	case "unbounded":
+		return -1, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid occurrence %q", value)
+	}
+	return n, nil
+}
+
+func (c *schemaCompiler) element(el xsdElement, global bool) (*elementDecl, error) {
+	where := "element " + el.Name
+	if err := unsupported(where, el.Unsupported); err != nil {
+		return nil, err
+	}
+	if el.Name == "" {
+		return nil, fmt.Errorf("element without a name (ref is not supported)")
+	}
+	decl := &elementDecl{name: el.Name, min: 1, max: 1}
+	if !global {
+		var err error
+		if decl.min, err = occurs(el.MinOccurs, 1); err != nil {
+			return nil, fmt.Errorf("%s: %w", where, err)
+		}
+		if decl.max, err = occurs(el.MaxOccurs, 1); err != nil {
+			return nil, fmt.Errorf("%s: %w", where, err)
+		}
+		if decl.max >= 0 && decl.max < decl.min {
+			return nil, fmt.Errorf("%s: maxOccurs is less than minOccurs", where)
+		}
+	}
+
+	switch {
+	case el.ComplexType != nil:
+		decl.complex = &complexType{}
+		if err := c.fillComplex(decl.complex, el.ComplexType, where); err != nil {
+			return nil, err
+		}
+	case el.SimpleType != nil:
+		st, err := c.simpleType(el.SimpleType, where)
+		if err != nil {
+			return nil, err
+		}
+		decl.simple = st
+	case el.Type != "":
+		name := localName(el.Type)
+		if ct, ok := c.complexes[name]; ok {
+			decl.complex = ct
+			break
+		}
+		st, err := c.namedSimple(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", where, err)
+		}
+		decl.simple = st
+	default:
+		// An element without a type holds any text
+		decl.simple = &simpleType{builtin: "string", minLength: -1, maxLength: -1}
+	}
+	return decl, nil
+}
+
+func (c *schemaCompiler) fillComplex(ct *complexType, def *xsdComplexType, where string) error {
+	if err := unsupported(where, def.Unsupported); err != nil {
+		return err
+	}
+	attributes := def.Attributes
+	if sc := def.SimpleContent; sc != nil {
+		if def.Sequence != nil {
+			return fmt.Errorf("%s: simple content cannot have child elements", where)
+		}
+		if err := unsupported(where, sc.Unsupported); err != nil {
+			return err
+		}
+		if err := unsupported(where, sc.Extension.Unsupported); err != nil {
+			return err
+		}
+		st, err := c.namedSimple(localName(sc.Extension.Base))
+		if err != nil {
+			return fmt.Errorf("%s: %w", where, err)
+		}
+		ct.text = st
+		attributes = append(attributes, sc.Extension.Attributes...)
+	}
+	if seq := def.Sequence; seq != nil {
+		if err := unsupported(where, seq.Unsupported); err != nil {
+			return err
+		}
+		for _, el := range seq.Elements {
+			child, err := c.element(el, false)
+			if err != nil {
+				return fmt.Errorf("%s: %w", where, err)
+			}
+			ct.children = append(ct.children, child)
+		}
+	}
+	for _, attr := range attributes {
+		decl := attributeDecl{name: attr.Name, required: attr.Use == "required"}
+		var err error
+		if attr.SimpleType != nil {
+			decl.typ, err = c.simpleType(attr.SimpleType, where)
+		} else {
+			decl.typ, err = c.namedSimple(localName(attr.Type))
+		}
+		if err != nil {
+			return fmt.Errorf("%s: attribute %s: %w", where, attr.Name, err)
+		}
+		ct.attributes = append(ct.attributes, decl)
+	}
+	return nil
+}
+
+// namedSimple resolves a named simple type or a built-in type
+func (c *schemaCompiler) namedSimple(name string) (*simpleType, error) {
+	if name == "" || name == "anySimpleType" {
+		name = "string"
+	}
+	if st, ok := c.simples[name]; ok {
+		return st, nil
+	}
+	def, ok := c.simpleDefs[name]
+	if !ok {
+		if _, ok := builtinTypes[name]; !ok {
+			return nil, fmt.Errorf("unknown type %q", name)
+		}
+		st := &simpleType{builtin: name, minLength: -1, maxLength: -1}
+		c.simples[name] = st
+		return st, nil
+	}
+	if c.resolving[name] {
+		return nil, fmt.Errorf("simple type %q derives from itself", name)
+	}
+	c.resolving[name] = true
+	defer delete(c.resolving, name)
+	st, err := c.simpleType(def, "simpleType "+name)
+	if err != nil {
+		return nil, err
+	}
+	c.simples[name] = st
+	return st, nil
+}
+
+func (c *schemaCompiler) simpleType(def *xsdSimpleType, where string) (*simpleType, error) {
+	if err := unsupported(where, def.Unsupported); err != nil {
+		return nil, err
+	}
+	r := def.Restriction
+	if r == nil {
+		return nil, fmt.Errorf("%s: only restrictions are supported", where)
+	}
+	if err := unsupported(where, r.Unsupported); err != nil {
+		return nil, err
+	}
+	base, err := c.namedSimple(localName(r.Base))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", where, err)
+	}
+
+	// Facets narrow the base type's facets
+	st := *base
+	st.patterns = append([]*regexp.Regexp(nil), base.patterns...)
+	if len(r.Enumerations) > 0 {
+		st.enumeration = nil
+		for _, e := range r.Enumerations {
+			st.enumeration = append(st.enumeration, e.Value)
+		}
+	}
+	for _, p := range r.Patterns {
+		re, err := regexp.Compile(`^(?:` + p.Value + `)$`)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern: %w", where, err)
+		}
+		st.patterns = append(st.patterns, re)
+	}
+	for _, f := range []struct {
+		facet *xsdFacet
+		dst   *int
+	}{{r.MinLength, &st.minLength}, {r.MaxLength, &st.maxLength}} {
+		if f.facet == nil {
+			continue
+		}
+		n, err := strconv.Atoi(f.facet.Value)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%s: invalid length %q", where, f.facet.Value)
+		}
+		*f.dst = n
+	}
+	for _, f := range []struct {
+		facet *xsdFacet
+		dst   **big.Rat
+	}{{r.MinInclusive, &st.minInclusive}, {r.MaxInclusive, &st.maxInclusive}} {
+		if f.facet == nil {
+			continue
+		}
+		bound, ok := new(big.Rat).SetString(f.facet.Value)
+		if !ok {
+			return nil, fmt.Errorf("%s: invalid bound %q", where, f.facet.Value)
+		}
+		*f.dst = bound
+	}
+	return &st, nil
+}
+
+var (
+	integerLexical  = regexp.MustCompile(`^[+-]?\d+$`)
+	decimalLexical  = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+	durationLexical = regexp.MustCompile(`^-?P(\d+Y)?(\d+M)?(\d+D)?(T(\d+H)?(\d+M)?(\d+(\.\d+)?S)?)?$`)
+)
+
+// integerRange bounds the built-in integer types; nil is unbounded
+type integerRange struct{ min, max *big.Int }
+
+func bounds(min, max int64) integerRange {
+	return integerRange{big.NewInt(min), big.NewInt(max)}
+}
+
+var integerTypes = map[string]integerRange{
+	"integer":            {},
+	"long":               bounds(-1<<63, 1<<63-1),
+	"int":                bounds(-1<<31, 1<<31-1),
+	"short":              bounds(-1<<15, 1<<15-1),
+	"byte":               bounds(-1<<7, 1<<7-1),
+	"nonNegativeInteger": {min: big.NewInt(0)},
+	"positiveInteger":    {min: big.NewInt(1)},
+	"unsignedInt":        bounds(0, 1<<32-1),
+}
+
+// builtinTypes checks the lexical space of each supported built-in type
+var builtinTypes = builtins()
+
+func builtins() map[string]func(string) error {
+	types := map[string]func(string) error{
+		"string":           func(string) error { return nil },
+		"normalizedString": func(string) error { return nil },
+		"token":            func(string) error { return nil },
+		"anyURI":           func(string) error { return nil },
+		"boolean": func(s string) error {
+			switch s {
+			case "true", "false", "1", "0":
+				return nil
+			}
+			return fmt.Errorf("%q is not a boolean", s)
+		},
+		"decimal": func(s string) error {
+			if !decimalLexical.MatchString(s) {
+				return fmt.Errorf("%q is not a decimal", s)
+			}
+			return nil
+		},
+		"float":  checkFloat,
+		"double": checkFloat,
+		"dateTime": func(s string) error {
+			layout := "2006-01-02T15:04:05.999999999"
+			if hasZone(s, 19) {
+				layout += "Z07:00"
+			}
+			if _, err := time.Parse(layout, s); err != nil {
+				return fmt.Errorf("%q is not a dateTime", s)
+			}
+			return nil
+		},
+		"date": func(s string) error {
+			layout := "2006-01-02"
+			if hasZone(s, 10) {
+				layout += "Z07:00"
+			}
+			if _, err := time.Parse(layout, s); err != nil {
+				return fmt.Errorf("%q is not a date", s)
+			}
+			return nil
+		},
+		"duration": func(s string) error {
+			if !durationLexical.MatchString(s) || strings.HasSuffix(s, "P") || strings.HasSuffix(s, "T") {
+				return fmt.Errorf("%q is not a duration", s)
+			}
+			return nil
+		},
+	}
+	for name := range integerTypes {
+		types[name] = func(s string) error {
+			_, err := parseInteger(name, s)
+			return err
+		}
+	}
+	return types
+}
+
+func parseInteger(typ, s string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(strings.TrimPrefix(s, "+"), 10)
+	if !integerLexical.MatchString(s) || !ok {
+		return nil, fmt.Errorf("%q is not an integer", s)
+	}
+	r := integerTypes[typ]
+	if (r.min != nil && n.Cmp(r.min) < 0) || (r.max != nil && n.Cmp(r.max) > 0) {
+		return nil, fmt.Errorf("%s is out of range for %s", s, typ)
+	}
+	return n, nil
+}
+
+func checkFloat(s string) error {
+	switch s {
+	case "INF", "-INF", "+INF", "NaN":
+		return nil
+	}
+	if _, err := strconv.ParseFloat(s, 64); err != nil && !errors.Is(err, strconv.ErrRange) {
+		return fmt.Errorf("%q is not a float", s)
+	}
+	return nil
+}
+
+// hasZone reports whether s continues past a date or dateTime of n characters
+// with a time zone, ignoring fractional seconds
+func hasZone(s string, n int) bool {
+	if len(s) <= n {
+		return false
+	}
+	rest := strings.TrimLeft(s[n:], ".0123456789")
+	return rest != ""
+}
+
+func (st *simpleType) isNumeric() bool {
+	_, integer := integerTypes[st.builtin]
+	return integer || st.builtin == "decimal" || st.builtin == "float" || st.builtin == "double"
+}
+
+func (st *simpleType) validate(value string) error {
+	switch st.builtin {
+	case "string", "anyURI":
+	case "normalizedString":
+		value = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(value)
+	default:
+		// Every other built-in type collapses whitespace
+		value = strings.Join(strings.Fields(value), " ")
+	}
+	if err := builtinTypes[st.builtin](value); err != nil {
+		return err
+	}
+
+	if len(st.enumeration) > 0 {
+		found := false
+		for _, e := range st.enumeration {
+			if e == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%q is not one of %s", value, strings.Join(st.enumeration, ", "))
+		}
+	}
+	for _, re := range st.patterns {
+		if !re.MatchString(value) {
+			return fmt.Errorf("%q does not match pattern %s", value, strings.TrimSuffix(strings.TrimPrefix(re.String(), "^(?:"), ")$"))
+		}
+	}
+	if length := utf8.RuneCountInString(value); (st.minLength >= 0 && length < st.minLength) || (st.maxLength >= 0 && length > st.maxLength) {
+		return fmt.Errorf("length %d of %q is outside [%d, %d]", length, value, st.minLength, st.maxLength)
+	}
+	if (st.minInclusive != nil || st.maxInclusive != nil) && st.isNumeric() {
+		v, ok := new(big.Rat).SetString(value)
+		if !ok {
+			return nil
+		}
+		if st.minInclusive != nil && v.Cmp(st.minInclusive) < 0 {
+			return fmt.Errorf("%s is less than %s", value, st.minInclusive.RatString())
+		}
+		if st.maxInclusive != nil && v.Cmp(st.maxInclusive) > 0 {
+			return fmt.Errorf("%s is greater than %s", value, st.maxInclusive.RatString())
+		}
+	}
+	return nil
+}
+
+// node is an element of the document being validated
+type node struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*node
+	text     strings.Builder
+}
+
+func parseDocument(data []byte) (*node, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var root *node
+	var stack []*node
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse document: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &node{name: t.Name, attrs: t.Attr}
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, fmt.Errorf("document has more than one root element")
+				}
+				root = n
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("document has no root element")
+	}
+	return root, nil
+}
+
+// Validate checks a document against the schema and returns every problem
+// found, each prefixed with the path of the element
+func (s *Schema) Validate(data []byte) error {
+	root, err := parseDocument(data)
+	if err != nil {
+		return err
+	}
+	decl, ok := s.elements[root.name.Local]
+	if !ok {
+		return fmt.Errorf("/%s: element is not declared by the schema", root.name.Local)
+	}
+	if s.targetNamespace != "" && root.name.Space != s.targetNamespace {
+		return fmt.Errorf("/%s: expected namespace %q, got %q", root.name.Local, s.targetNamespace, root.name.Space)
+	}
+	var problems []error
+	validateNode(root, decl, "/"+root.name.Local, &problems)
+	return errors.Join(problems...)
+}
+
+func validateNode(n *node, decl *elementDecl, path string, problems *[]error) {
+	report := func(format string, args ...any) {
+		*problems = append(*problems, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+	}
+
+	if decl.simple != nil {
+		if len(n.children) > 0 {
+			report("unexpected element <%s> in simple content", n.children[0].name.Local)
+		} else if hasOwnAttributes(n.attrs) {
+			report("unexpected attributes on a simple element")
+		} else if err := decl.simple.validate(n.text.String()); err != nil {
+			report("%v", err)
+		}
+		return
+	}
+
+	ct := decl.complex
+	validateAttributes(n, ct, report)
+	if ct.text != nil {
+		if len(n.children) > 0 {
+			report("unexpected element <%s> in simple content", n.children[0].name.Local)
+		} else if err := ct.text.validate(n.text.String()); err != nil {
+			report("%v", err)
+		}
+		return
+	}
+	if strings.TrimSpace(n.text.String()) != "" {
+		report("unexpected text in element-only content")
+	}
+
+	i := 0
+	for _, child := range ct.children {
+		count := 0
+		for i < len(n.children) && n.children[i].name.Local == child.name && (child.max < 0 || count < child.max) {
+			childPath := path + "/" + child.name
+			if child.max < 0 || child.max > 1 {
+				childPath = fmt.Sprintf("%s[%d]", childPath, count+1)
+			}
+			validateNode(n.children[i], child, childPath, problems)
+			i++
+			count++
+		}
+		if count < child.min {
+			if i < len(n.children) {
+				report("expected <%s>, got <%s>", child.name, n.children[i].name.Local)
+				return
+			}
+			report("missing element <%s>", child.name)
+		}
+	}
+	if i < len(n.children) {
+		report("unexpected element <%s>", n.children[i].name.Local)
+	}
+}
+
+// hasOwnAttributes reports whether attrs has more than namespace declarations
+// and xsi: attributes
+func hasOwnAttributes(attrs []xml.Attr) bool {
+	for _, a := range attrs {
+		if !isSchemaAttr(a) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSchemaAttr(a xml.Attr) bool {
+	return a.Name.Space == "xmlns" || a.Name.Local == "xmlns" ||
+		a.Name.Space == "http://www.w3.org/2001/XMLSchema-instance"
+}
+
+func validateAttributes(n *node, ct *complexType, report func(string, ...any)) {
+	seen := make(map[string]bool, len(n.attrs))
+	for _, a := range n.attrs {
+		if isSchemaAttr(a) {
+			continue
+		}
+		seen[a.Name.Local] = true
+		var decl *attributeDecl
+		for i := range ct.attributes {
+			if ct.attributes[i].name == a.Name.Local {
+				decl = &ct.attributes[i]
+				break
+			}
+		}
+		if decl == nil {
+			report("unexpected attribute %s", a.Name.Local)
+			continue
+		}
+		if err := decl.typ.validate(a.Value); err != nil {
+			report("attribute %s: %v", a.Name.Local, err)
+		}
+	}
+	for _, decl := range ct.attributes {
+		if decl.required && !seen[decl.name] {
+			report("missing attribute %s", decl.name)
+		}
+	}
+}
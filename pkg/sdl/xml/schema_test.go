@@ -0,0 +1,127 @@
+package xml
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-transport-prac/internal/testutil"
+)
+
+const mockUserXSD = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+  <xs:element name="user">
+    <xs:complexType>
+      <xs:sequence>
+        <xs:element name="id" type="xs:positiveInteger"/>
+        <xs:element name="name" type="xs:string"/>
+        <xs:element name="email" type="Email"/>
+        <xs:element name="active" type="xs:boolean"/>
+        <xs:element name="tag" type="xs:token" minOccurs="0" maxOccurs="2"/>
+      </xs:sequence>
+      <xs:attribute name="version" type="xs:int"/>
+    </xs:complexType>
+  </xs:element>
+  <xs:simpleType name="Email">
+    <xs:restriction base="xs:string">
+      <xs:pattern value="[^@\s]+@[^@\s]+"/>
+      <xs:maxLength value="64"/>
+    </xs:restriction>
+  </xs:simpleType>
+</xs:schema>`
+
+func TestSchemaValidate(t *testing.T) {
+	testDir := "tmp/test_xml_schema"
+	if err := os.MkdirAll(testDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	defer os.RemoveAll(testDir)
+	path := filepath.Join(testDir, "user.xsd")
+	if err := os.WriteFile(path, []byte(mockUserXSD), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	schema, err := LoadSchema(path)
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+
+	if err := schema.Validate([]byte(testutil.MockData.XMLData)); err != nil {
+		t.Errorf("Expected the mock user to be valid, got %v", err)
+	}
+
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"zero id", `<user><id>0</id><name>a</name><email>a@b</email><active>true</active></user>`, "/user/id: 0 is out of range"},
+		{"bad email", `<user><id>1</id><name>a</name><email>nobody</email><active>true</active></user>`, "/user/email: \"nobody\" does not match pattern"},
+		{"bad boolean", `<user><id>1</id><name>a</name><email>a@b</email><active>yes</active></user>`, "/user/active: \"yes\" is not a boolean"},
+		{"missing element", `<user><id>1</id><name>a</name><email>a@b</email></user>`, "/user: missing element <active>"},
+		{"too many", `<user><id>1</id><name>a</name><email>a@b</email><active>1</active><tag>a</tag><tag>b</tag><tag>c</tag></user>`, "/user: unexpected element <tag>"},
+		{"attribute", `<user version="x"><id>1</id><name>a</name><email>a@b</email><active>1</active></user>`, "/user: attribute version: \"x\" is not an integer"},
+		{"unknown attribute", `<user lang="en"><id>1</id><name>a</name><email>a@b</email><active>1</active></user>`, "/user: unexpected attribute lang"},
+		{"text", `<user>hi<id>1</id><name>a</name><email>a@b</email><active>1</active></user>`, "/user: unexpected text"},
+		{"undeclared root", `<account/>`, "/account: element is not declared"},
+		{"malformed", `<user><id>1</id>`, "failed to parse document"},
+	}
+	for _, tt := range tests {
+		err := schema.Validate([]byte(tt.doc))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	// Whitespace around non-string values is collapsed
+	ok := `<user version=" 2 "><id>
+		42
+	</id><name> a </name><email>a@b</email><active> false </active><tag>x</tag></user>`
+	if err := schema.Validate([]byte(ok)); err != nil {
+		t.Errorf("Expected surrounding whitespace to be accepted, got %v", err)
+	}
+
+	t.Log("✓ Documents are checked against an XSD")
+}
+
+func TestParseSchema(t *testing.T) {
+	if ModelsSchema() == nil || len(ModelsSchema().elements) != 6 {
+		t.Fatal("Expected the models schema to declare six global elements")
+	}
+
+	tests := []struct {
+		name string
+		xsd  string
+		want string
+	}{
+		{"choice", `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:element name="a"><xs:complexType><xs:choice/></xs:complexType></xs:element></xs:schema>`, "unsupported XSD construct <choice>"},
+		{"import", `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:import namespace="urn:x"/><xs:element name="a"/></xs:schema>`, "unsupported XSD construct <import>"},
+		{"unknown type", `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:element name="a" type="Missing"/></xs:schema>`, `unknown type "Missing"`},
+		{"bad pattern", `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:element name="a"><xs:simpleType><xs:restriction base="xs:string"><xs:pattern value="("/></xs:restriction></xs:simpleType></xs:element></xs:schema>`, "invalid pattern"},
+		{"occurs", `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"><xs:element name="a"><xs:complexType><xs:sequence><xs:element name="b" minOccurs="2" maxOccurs="1"/></xs:sequence></xs:complexType></xs:element></xs:schema>`, "maxOccurs is less than minOccurs"},
+		{"empty", `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema"/>`, "no global elements"},
+	}
+	for _, tt := range tests {
+		_, err := ParseSchema([]byte(tt.xsd))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	// Derived simple types keep their base's facets
+	schema, err := ParseSchema([]byte(`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+		<xs:element name="level" type="Level"/>
+		<xs:simpleType name="Level"><xs:restriction base="Percent"><xs:maxInclusive value="10"/></xs:restriction></xs:simpleType>
+		<xs:simpleType name="Percent"><xs:restriction base="xs:decimal"><xs:minInclusive value="0"/><xs:maxInclusive value="100"/></xs:restriction></xs:simpleType>
+	</xs:schema>`))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	for doc, valid := range map[string]bool{"<level>2.5</level>": true, "<level>-1</level>": false, "<level>11</level>": false, "<level>1e2</level>": false} {
+		if err := schema.Validate([]byte(doc)); (err == nil) != valid {
+			t.Errorf("%s: expected valid=%v, got %v", doc, valid, err)
+		}
+	}
+
+	t.Log("✓ XSD subset is compiled and unsupported constructs are rejected")
+}
@@ -0,0 +1,192 @@
+// Package yaml serializes the shared Avro models as YAML. Field names come
+// from the models' yaml tags, which match their JSON keys, so a YAML document
+// reads like the models' JSON encoding. Files and streams hold one document
+// per record, separated by "---".
+package yaml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"go-transport-prac/internal/recordio"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+// ContentType is the media type of YAML payloads
+const ContentType = "application/yaml"
+
+// FileExtension is the extension of YAML files
+const FileExtension = ".yaml"
+
+// Manager handles YAML serialization and deserialization
+type Manager struct {
+	baseDir string
+	indent  int
+	// strict rejects keys that do not map to a field
+	strict bool
+}
+
+var _ types.Serializer = (*Manager)(nil)
+
+// NewManager creates a new YAML manager writing files under baseDir
+func NewManager(baseDir string) *Manager {
+	if baseDir == "" {
+		baseDir = "data/yaml"
+	}
+	return &Manager{baseDir: baseDir, indent: 2}
+}
+
+// WithIndent sets the number of spaces per nesting level, 2 by default
+func (m *Manager) WithIndent(spaces int) *Manager {
+	if spaces > 0 {
+		m.indent = spaces
+	}
+	return m
+}
+
+// WithStrict rejects documents with keys that do not map to a field of the
+// target, which catches misspelled keys in hand-written files
+func (m *Manager) WithStrict(on bool) *Manager {
+	m.strict = on
+	return m
+}
+
+// ContentType returns the media type of YAML payloads
+func (m *Manager) ContentType() string {
+	return ContentType
+}
+
+// FileExtension returns the extension of YAML files
+func (m *Manager) FileExtension() string {
+	return FileExtension
+}
+
+func (m *Manager) newEncoder(w io.Writer) *yaml.Encoder {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(m.indent)
+	return enc
+}
+
+func (m *Manager) newDecoder(r io.Reader) *yaml.Decoder {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(m.strict)
+	return dec
+}
+
+// Serialize encodes any value as one YAML document
+func (m *Manager) Serialize(data any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := m.newEncoder(&buf)
+	if err := enc.Encode(data); err != nil {
+		return nil, fmt.Errorf("failed to encode: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Deserialize decodes one YAML document into target, a pointer. Times come
+// back in UTC when the document gives them in UTC
+func (m *Manager) Deserialize(data []byte, target any) error {
+	if len(data) == 0 {
+		return fmt.Errorf("data cannot be empty")
+	}
+	dec := m.newDecoder(bytes.NewReader(data))
+	if err := dec.Decode(target); err != nil {
+		return fmt.Errorf("failed to decode: %w", err)
+	}
+	var extra yaml.Node
+	if err := dec.Decode(&extra); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("data holds more than one document")
+	}
+	return nil
+}
+
+// SerializeUser serializes a user to YAML
+func (m *Manager) SerializeUser(user avro.User) ([]byte, error) {
+	return m.Serialize(user)
+}
+
+// DeserializeUser deserializes a user from YAML
+func (m *Manager) DeserializeUser(data []byte) (avro.User, error) {
+	var user avro.User
+	if err := m.Deserialize(data, &user); err != nil {
+		return avro.User{}, fmt.Errorf("failed to deserialize user: %w", err)
+	}
+	return user, nil
+}
+
+// SerializeProduct serializes a product to YAML
+func (m *Manager) SerializeProduct(product avro.Product) ([]byte, error) {
+	return m.Serialize(product)
+}
+
+// DeserializeProduct deserializes a product from YAML
+func (m *Manager) DeserializeProduct(data []byte) (avro.Product, error) {
+	var product avro.Product
+	if err := m.Deserialize(data, &product); err != nil {
+		return avro.Product{}, fmt.Errorf("failed to deserialize product: %w", err)
+	}
+	return product, nil
+}
+
+// SerializeOrder serializes an order to YAML
+func (m *Manager) SerializeOrder(order avro.Order) ([]byte, error) {
+	return m.Serialize(order)
+}
+
+// DeserializeOrder deserializes an order from YAML
+func (m *Manager) DeserializeOrder(data []byte) (avro.Order, error) {
+	var order avro.Order
+	if err := m.Deserialize(data, &order); err != nil {
+		return avro.Order{}, fmt.Errorf("failed to deserialize order: %w", err)
+	}
+	return order, nil
+}
+
+// EncodeUsers writes users to w as YAML documents separated by "---"
+func (m *Manager) EncodeUsers(w io.Writer, users []avro.User) error {
+	return recordio.EncodeAll(m.newEncoder(w), users)
+}
+
+// DecodeUsers reads YAML user documents from r until it ends
+func (m *Manager) DecodeUsers(r io.Reader) ([]avro.User, error) {
+	return recordio.DecodeAll[avro.User](m.newDecoder(r))
+}
+
+// WriteUsersToFile writes users to a YAML file under the base directory
+func (m *Manager) WriteUsersToFile(filename string, users []avro.User) error {
+	return recordio.WriteFile(filepath.Join(m.baseDir, filename), m.newEncoder, users)
+}
+
+// ReadUsersFromFile reads users from a YAML file under the base directory
+func (m *Manager) ReadUsersFromFile(filename string) ([]avro.User, error) {
+	return recordio.ReadFile[avro.User](filepath.Join(m.baseDir, filename), m.newDecoder)
+}
+
+// WriteProductsToFile writes products to a YAML file under the base directory
+func (m *Manager) WriteProductsToFile(filename string, products []avro.Product) error {
+	return recordio.WriteFile(filepath.Join(m.baseDir, filename), m.newEncoder, products)
+}
+
+// ReadProductsFromFile reads products from a YAML file under the base directory
+func (m *Manager) ReadProductsFromFile(filename string) ([]avro.Product, error) {
+	return recordio.ReadFile[avro.Product](filepath.Join(m.baseDir, filename), m.newDecoder)
+}
+
+// WriteOrdersToFile writes orders to a YAML file under the base directory
+func (m *Manager) WriteOrdersToFile(filename string, orders []avro.Order) error {
+	return recordio.WriteFile(filepath.Join(m.baseDir, filename), m.newEncoder, orders)
+}
+
+// ReadOrdersFromFile reads orders from a YAML file under the base directory
+func (m *Manager) ReadOrdersFromFile(filename string) ([]avro.Order, error) {
+	return recordio.ReadFile[avro.Order](filepath.Join(m.baseDir, filename), m.newDecoder)
+}
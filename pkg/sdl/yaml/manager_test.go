@@ -0,0 +1,215 @@
+package yaml
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+func samples(t *testing.T) *avro.Manager {
+	t.Helper()
+	manager, err := avro.NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create avro manager: %v", err)
+	}
+	return manager.WithClock(testutil.NewDefaultFakeClock())
+}
+
+func sampleOrder(id int64) avro.Order {
+	created := time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.UTC)
+	shipped := created.Add(2 * time.Hour)
+	deliveryTime := 14 * time.Hour
+	carrier := ""
+	usd := func(cents int64) avro.Price { return avro.Price{Currency: "USD", AmountCents: cents} }
+	return avro.Order{
+		ID:          id,
+		UserID:      7,
+		OrderNumber: "ORD-000001",
+		Status:      avro.OrderStatusShipped,
+		Items: []avro.OrderItem{
+			{ProductID: 1, ProductName: "Sensor: v2", ProductSKU: "SKU-000001", Quantity: 2, UnitPrice: usd(1500), TotalPrice: usd(3000),
+				ProductVariant: map[string]string{"color": "red", "size": "m"}},
+		},
+		Summary: avro.OrderSummary{Subtotal: usd(3000), Tax: usd(300), ShippingCost: usd(500), Discount: usd(0), Total: usd(3800), TotalItems: 2},
+		ShippingInfo: &avro.ShippingInfo{
+			Address:      avro.ShippingAddress{RecipientName: "User 7", Street: "1 Main St", City: "Test City", State: "TS", PostalCode: "10000", Country: "USA"},
+			Method:       "standard",
+			Carrier:      &carrier,
+			Cost:         usd(500),
+			DeliveryTime: &deliveryTime,
+		},
+		PaymentInfo: &avro.PaymentInfo{
+			Method:      "credit_card",
+			Status:      avro.PaymentStatusCaptured,
+			Amount:      avro.Price{Currency: "USD", AmountCents: 3800, Amount: avro.NewDecimal(3800, 2)},
+			ProcessedAt: &shipped,
+		},
+		CreatedAt: created,
+		UpdatedAt: shipped,
+		ShippedAt: &shipped,
+	}
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	manager := NewManager("")
+
+	user := samples(t).CreateSampleUsers(1)[0]
+	data, err := manager.SerializeUser(user)
+	if err != nil {
+		t.Fatalf("Failed to serialize user: %v", err)
+	}
+	if !bytes.Contains(data, []byte("createdAt: ")) || !bytes.Contains(data, []byte("\n  firstName: ")) {
+		t.Errorf("Expected JSON key names indented by two spaces, got:\n%s", data)
+	}
+	back, err := manager.DeserializeUser(data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize user: %v", err)
+	}
+	if !reflect.DeepEqual(back, user) {
+		t.Errorf("User changed across YAML:\n got %+v\nwant %+v", back, user)
+	}
+
+	product := samples(t).CreateSampleProducts(2)[1]
+	release := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	product.ReleaseDate = &release
+	product.Price.Amount = avro.NewDecimal(123456, 2)
+	data, err = manager.SerializeProduct(product)
+	if err != nil {
+		t.Fatalf("Failed to serialize product: %v", err)
+	}
+	backProduct, err := manager.DeserializeProduct(data)
+	if err != nil {
+		t.Fatalf("Failed to deserialize product: %v", err)
+	}
+	if backProduct.Price.Amount.Cmp(product.Price.Amount) != 0 {
+		t.Errorf("Expected amount %s, got %s", product.Price.Amount, backProduct.Price.Amount)
+	}
+	backProduct.Price.Amount, product.Price.Amount = nil, nil
+	if !reflect.DeepEqual(backProduct, product) {
+		t.Errorf("Product changed across YAML:\n got %+v\nwant %+v", backProduct, product)
+	}
+
+	// Through the Serializer interface; decimals and durations stay readable
+	order := sampleOrder(1)
+	data, err = manager.Serialize(&order)
+	if err != nil {
+		t.Fatalf("Failed to serialize order: %v", err)
+	}
+	if !bytes.Contains(data, []byte(`amount: "38"`)) || !bytes.Contains(data, []byte("deliveryTime: 14h0m0s")) {
+		t.Errorf("Unexpected order document:\n%s", data)
+	}
+	var backOrder avro.Order
+	if err := manager.Deserialize(data, &backOrder); err != nil {
+		t.Fatalf("Failed to deserialize order: %v", err)
+	}
+	if backOrder.PaymentInfo.Amount.Amount.Cmp(order.PaymentInfo.Amount.Amount) != 0 {
+		t.Errorf("Expected payment amount %s, got %s", order.PaymentInfo.Amount.Amount, backOrder.PaymentInfo.Amount.Amount)
+	}
+	backOrder.PaymentInfo.Amount.Amount, order.PaymentInfo.Amount.Amount = nil, nil
+	if !reflect.DeepEqual(backOrder, order) {
+		t.Errorf("Order changed across YAML:\n got %+v\nwant %+v", backOrder, order)
+	}
+
+	if err := manager.Deserialize([]byte("id: 1\n---\nid: 2\n"), &backOrder); err == nil {
+		t.Error("Expected two documents to be rejected")
+	}
+	if manager.ContentType() != "application/yaml" || manager.FileExtension() != ".yaml" {
+		t.Errorf("Unexpected content type %s or extension %s", manager.ContentType(), manager.FileExtension())
+	}
+
+	t.Log("✓ Users, products and orders round trip through YAML")
+}
+
+func TestYAMLHandWritten(t *testing.T) {
+	doc := `
+# A user as someone would type it
+id: 42
+email: ada@example.com
+name: Ada
+status: ACTIVE
+profile:
+  firstName: Ada
+  lastName: Lovelace
+  interests: [math, engines]
+  metadata:
+    source: import
+createdAt: 2024-01-02T03:04:05Z
+updatedAt: 2024-01-02T03:04:05.5+02:00
+`
+	user, err := NewManager("").DeserializeUser([]byte(doc))
+	if err != nil {
+		t.Fatalf("Failed to deserialize user: %v", err)
+	}
+	if user.ID != 42 || user.Profile.Interests[1] != "engines" || user.Profile.Metadata["source"] != "import" {
+		t.Errorf("Unexpected user %+v", user)
+	}
+	if !user.UpdatedAt.Equal(time.Date(2024, 1, 2, 1, 4, 5, 5e8, time.UTC)) {
+		t.Errorf("Expected the offset to be applied, got %v", user.UpdatedAt)
+	}
+
+	// Strict decoding catches misspelled keys that are otherwise ignored
+	typo := strings.Replace(doc, "firstName", "firstname", 1)
+	if _, err := NewManager("").DeserializeUser([]byte(typo)); err != nil {
+		t.Errorf("Expected the lenient manager to ignore the typo, got %v", err)
+	}
+	if _, err := NewManager("").WithStrict(true).DeserializeUser([]byte(typo)); err == nil || !strings.Contains(err.Error(), "firstname") {
+		t.Errorf("Expected the strict manager to reject the typo, got %v", err)
+	}
+
+	t.Log("✓ Hand-written YAML decodes into the models")
+}
+
+func TestYAMLStreams(t *testing.T) {
+	testDir := "tmp/test_yaml_streams"
+	manager := NewManager(testDir)
+	defer os.RemoveAll(testDir)
+
+	users := samples(t).CreateSampleUsers(5)
+	var buf bytes.Buffer
+	if err := manager.EncodeUsers(&buf, users); err != nil {
+		t.Fatalf("Failed to encode users: %v", err)
+	}
+	if n := strings.Count(buf.String(), "\n---\n"); n != len(users)-1 {
+		t.Errorf("Expected %d document separators, got %d", len(users)-1, n)
+	}
+	back, err := manager.DecodeUsers(&buf)
+	if err != nil || !reflect.DeepEqual(back, users) {
+		t.Fatalf("Users changed across the stream (%v)", err)
+	}
+
+	if err := manager.WriteUsersToFile("users.yaml", users); err != nil {
+		t.Fatalf("Failed to write users: %v", err)
+	}
+	if back, err := manager.ReadUsersFromFile("users.yaml"); err != nil || !reflect.DeepEqual(back, users) {
+		t.Errorf("Users changed across the file (%v)", err)
+	}
+	products := samples(t).CreateSampleProducts(3)
+	if err := manager.WriteProductsToFile("products.yaml", products); err != nil {
+		t.Fatalf("Failed to write products: %v", err)
+	}
+	if back, err := manager.ReadProductsFromFile("products.yaml"); err != nil || !reflect.DeepEqual(back, products) {
+		t.Errorf("Products changed across the file (%v)", err)
+	}
+	orders := []avro.Order{sampleOrder(1), sampleOrder(2)}
+	if err := manager.WriteOrdersToFile("orders.yaml", orders); err != nil {
+		t.Fatalf("Failed to write orders: %v", err)
+	}
+	if back, err := manager.ReadOrdersFromFile("orders.yaml"); err != nil || len(back) != 2 || back[1].ID != 2 {
+		t.Errorf("Orders changed across the file (%v)", err)
+	}
+
+	if _, err := manager.DecodeUsers(strings.NewReader("id: 1\n---\nid: [\n")); err == nil {
+		t.Error("Expected a malformed document to fail")
+	}
+	if _, err := manager.ReadUsersFromFile("missing.yaml"); err == nil {
+		t.Error("Expected a missing file to fail")
+	}
+
+	t.Log("✓ YAML streams hold one document per record")
+}
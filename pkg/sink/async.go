@@ -0,0 +1,249 @@
+package sink
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/logger"
+)
+
+// CodeWriterClosed marks records submitted after the writer was closed
+const CodeWriterClosed = "WRITER_CLOSED"
+
+// Batch reports the outcome of one sink write
+type Batch[T any] struct {
+	// Seq numbers batches from 1 in the order they were written
+	Seq      uint64
+	Records  []T
+	Err      error
+	Duration time.Duration
+}
+
+// Stats summarizes an AsyncWriter's progress
+type Stats struct {
+	Submitted int64
+	Written   int64
+	Failed    int64
+	Batches   int64
+	InFlight  int
+	// Throughput is acknowledged records per second of time spent in the sink
+	Throughput float64
+}
+
+// request is a queued record, or a flush barrier when barrier is set
+type request[T any] struct {
+	record  T
+	barrier chan error
+}
+
+// AsyncWriter batches submitted records onto a sink in the background, so
+// producers only block when MaxInFlight records are awaiting acknowledgement
+type AsyncWriter[T any] struct {
+	sink    Sink[T]
+	cfg     Config
+	logger  *logger.Logger
+	onBatch func(Batch[T])
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan request[T]
+	slots  chan struct{}
+	done   chan struct{}
+	// closeErr is the outcome of the final batch, read after done is closed
+	closeErr error
+
+	seq       uint64
+	submitted atomic.Int64
+	written   atomic.Int64
+	failed    atomic.Int64
+	batches   atomic.Int64
+	busy      atomic.Int64
+}
+
+// NewAsyncWriter starts a writer that batches records onto s; zero config
+// fields take their DefaultConfig values
+func NewAsyncWriter[T any](s Sink[T], cfg Config, log *logger.Logger) *AsyncWriter[T] {
+	defaults := DefaultConfig()
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaults.BatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaults.FlushInterval
+	}
+	if cfg.MaxInFlight <= 0 {
+		cfg.MaxInFlight = defaults.MaxInFlight
+	}
+	if log == nil {
+		log = logger.Global()
+	}
+
+	w := &AsyncWriter[T]{
+		sink:   s,
+		cfg:    cfg,
+		logger: log.WithComponent("async_writer"),
+		queue:  make(chan request[T], cfg.MaxInFlight),
+		slots:  make(chan struct{}, cfg.MaxInFlight),
+		done:   make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// WithCallback sets a function called after every batch with its outcome.
+// Set it before the first Submit; it runs on the writer's goroutine
+func (w *AsyncWriter[T]) WithCallback(fn func(Batch[T])) *AsyncWriter[T] {
+	w.onBatch = fn
+	return w
+}
+
+// Submit queues record for writing and returns without waiting for IO. It
+// blocks only while MaxInFlight records are unacknowledged, until ctx is done
+func (w *AsyncWriter[T]) Submit(ctx context.Context, record T) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return errors.InternalError(CodeWriterClosed, "async writer is closed")
+	}
+
+	select {
+	case w.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	w.submitted.Add(1)
+	w.queue <- request[T]{record: record}
+	return nil
+}
+
+// Flush waits until every record submitted before the call has been written
+// and returns the first batch error since the previous Flush
+func (w *AsyncWriter[T]) Flush(ctx context.Context) error {
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return errors.InternalError(CodeWriterClosed, "async writer is closed")
+	}
+	barrier := make(chan error, 1)
+	w.queue <- request[T]{barrier: barrier}
+	w.mu.RUnlock()
+
+	select {
+	case err := <-barrier:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting records, writes what is queued and waits for the
+// writer to finish or ctx to be done. It returns the first batch error since
+// the last Flush
+func (w *AsyncWriter[T]) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return w.closeErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats returns the writer's counters
+func (w *AsyncWriter[T]) Stats() Stats {
+	stats := Stats{
+		Submitted: w.submitted.Load(),
+		Written:   w.written.Load(),
+		Failed:    w.failed.Load(),
+		Batches:   w.batches.Load(),
+		InFlight:  len(w.slots),
+	}
+	if busy := time.Duration(w.busy.Load()); busy > 0 {
+		stats.Throughput = float64(stats.Written) / busy.Seconds()
+	}
+	return stats
+}
+
+// run collects queued records into batches, writing them when full, when the
+// flush interval passes, at a barrier, and once the queue is closed
+func (w *AsyncWriter[T]) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	var batch []T
+	var pending error
+	flush := func() {
+		if err := w.write(batch); err != nil && pending == nil {
+			pending = err
+		}
+		batch = nil
+	}
+
+	for {
+		select {
+		case req, ok := <-w.queue:
+			if !ok {
+				flush()
+				w.closeErr = pending
+				return
+			}
+			if req.barrier != nil {
+				flush()
+				req.barrier <- pending
+				pending = nil
+				continue
+			}
+			batch = append(batch, req.record)
+			if len(batch) >= w.cfg.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// write sends one batch to the sink, reports it and releases its in-flight slots
+func (w *AsyncWriter[T]) write(records []T) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	err := w.sink.WriteBatch(context.Background(), records)
+	duration := time.Since(start)
+
+	w.seq++
+	w.batches.Add(1)
+	w.busy.Add(int64(duration))
+	if err != nil {
+		w.failed.Add(int64(len(records)))
+		w.logger.Warn("Batch write failed", zap.Uint64("batch", w.seq), zap.Int("records", len(records)), zap.Error(err))
+	} else {
+		w.written.Add(int64(len(records)))
+		w.logger.Debug("Batch written", zap.Uint64("batch", w.seq), zap.Int("records", len(records)), zap.Duration("duration", duration))
+	}
+
+	if w.onBatch != nil {
+		w.onBatch(Batch[T]{Seq: w.seq, Records: records, Err: err, Duration: duration})
+	}
+
+	for range records {
+		<-w.slots
+	}
+	return err
+}
@@ -0,0 +1,23 @@
+package sink
+
+import "time"
+
+// Config holds AsyncWriter batching and backpressure settings
+type Config struct {
+	// BatchSize is the number of records written to the sink at once
+	BatchSize int
+	// FlushInterval bounds how long a partial batch waits before being written
+	FlushInterval time.Duration
+	// MaxInFlight caps records submitted but not yet acknowledged; Submit
+	// blocks once it is reached, bounding the writer's memory
+	MaxInFlight int
+}
+
+// DefaultConfig returns settings suited to small records and local sinks
+func DefaultConfig() Config {
+	return Config{
+		BatchSize:     100,
+		FlushInterval: 50 * time.Millisecond,
+		MaxInFlight:   1000,
+	}
+}
@@ -0,0 +1,78 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go-transport-prac/internal/types"
+)
+
+// Sink writes batches of records; a nil error acknowledges the whole batch
+type Sink[T any] interface {
+	WriteBatch(ctx context.Context, records []T) error
+}
+
+// SinkFunc adapts a function to a Sink
+type SinkFunc[T any] func(ctx context.Context, records []T) error
+
+// WriteBatch calls f
+func (f SinkFunc[T]) WriteBatch(ctx context.Context, records []T) error {
+	return f(ctx, records)
+}
+
+// BrokerSink publishes each record as a message on a topic
+type BrokerSink struct {
+	broker types.MessageBroker
+	topic  string
+}
+
+// NewBrokerSink creates a sink publishing to topic through broker
+func NewBrokerSink(broker types.MessageBroker, topic string) *BrokerSink {
+	return &BrokerSink{broker: broker, topic: topic}
+}
+
+// WriteBatch publishes records in order, stopping at the first failure
+func (s *BrokerSink) WriteBatch(ctx context.Context, records [][]byte) error {
+	for i, record := range records {
+		if err := s.broker.Publish(ctx, s.topic, record); err != nil {
+			return fmt.Errorf("failed to publish record %d of %d to %s: %w", i+1, len(records), s.topic, err)
+		}
+	}
+	return nil
+}
+
+// StreamSink encodes records onto a writer, such as a file wrapped by one of the
+// format stream writers. After each batch the writer is flushed and synced when
+// it supports it, so an acknowledged batch has reached the file
+type StreamSink[T any] struct {
+	w     io.Writer
+	write func(T) error
+}
+
+// NewStreamSink creates a sink that encodes each record with write, which is
+// expected to write to w
+func NewStreamSink[T any](w io.Writer, write func(T) error) *StreamSink[T] {
+	return &StreamSink[T]{w: w, write: write}
+}
+
+// WriteBatch encodes records then flushes and syncs the writer
+func (s *StreamSink[T]) WriteBatch(ctx context.Context, records []T) error {
+	for i, record := range records {
+		if err := s.write(record); err != nil {
+			return fmt.Errorf("failed to write record %d of %d: %w", i+1, len(records), err)
+		}
+	}
+
+	if f, ok := s.w.(interface{ Flush() error }); ok {
+		if err := f.Flush(); err != nil {
+			return fmt.Errorf("failed to flush batch: %w", err)
+		}
+	}
+	if f, ok := s.w.(interface{ Sync() error }); ok {
+		if err := f.Sync(); err != nil {
+			return fmt.Errorf("failed to sync batch: %w", err)
+		}
+	}
+	return nil
+}
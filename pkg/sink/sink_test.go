@@ -0,0 +1,240 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+// recordingSink keeps every batch it is given and fails batches listed in failOn
+type recordingSink struct {
+	mu      sync.Mutex
+	batches [][]int
+	failOn  map[int]bool
+	gate    chan struct{}
+}
+
+func (s *recordingSink) WriteBatch(ctx context.Context, records []int) error {
+	if s.gate != nil {
+		<-s.gate
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, append([]int(nil), records...))
+	if s.failOn[len(s.batches)] {
+		return fmt.Errorf("disk full")
+	}
+	return nil
+}
+
+func TestAsyncWriterBatchesAndAcknowledges(t *testing.T) {
+	s := &recordingSink{}
+	var acked []Batch[int]
+	w := NewAsyncWriter[int](s, Config{BatchSize: 10, FlushInterval: time.Hour, MaxInFlight: 100}, nil).
+		WithCallback(func(b Batch[int]) { acked = append(acked, b) })
+
+	for i := 0; i < 25; i++ {
+		if err := w.Submit(t.Context(), i); err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+	}
+	if err := w.Flush(t.Context()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	// Two full batches, then the remainder at the barrier
+	if len(acked) != 3 || len(acked[0].Records) != 10 || len(acked[2].Records) != 5 {
+		t.Fatalf("Unexpected batches: %+v", acked)
+	}
+	next := 0
+	for _, b := range acked {
+		if b.Err != nil {
+			t.Errorf("Batch %d failed: %v", b.Seq, b.Err)
+		}
+		for _, r := range b.Records {
+			if r != next {
+				t.Fatalf("Records out of order: expected %d, got %d", next, r)
+			}
+			next++
+		}
+	}
+
+	stats := w.Stats()
+	if stats.Submitted != 25 || stats.Written != 25 || stats.Batches != 3 || stats.InFlight != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if err := w.Close(t.Context()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	t.Log("✓ Records are batched in order and acknowledged per batch")
+}
+
+func TestAsyncWriterFlushInterval(t *testing.T) {
+	s := &recordingSink{}
+	written := make(chan Batch[int], 1)
+	w := NewAsyncWriter[int](s, Config{BatchSize: 100, FlushInterval: 10 * time.Millisecond}, nil).
+		WithCallback(func(b Batch[int]) { written <- b })
+	defer w.Close(t.Context())
+
+	w.Submit(t.Context(), 1)
+	select {
+	case b := <-written:
+		if len(b.Records) != 1 {
+			t.Errorf("Expected a partial batch of 1, got %d", len(b.Records))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Partial batch was not written after the flush interval")
+	}
+	t.Log("✓ Partial batches are written after the flush interval")
+}
+
+func TestAsyncWriterBoundsInFlight(t *testing.T) {
+	s := &recordingSink{gate: make(chan struct{})}
+	w := NewAsyncWriter[int](s, Config{BatchSize: 2, FlushInterval: time.Hour, MaxInFlight: 4}, nil)
+
+	for i := 0; i < 4; i++ {
+		if err := w.Submit(t.Context(), i); err != nil {
+			t.Fatalf("Submit %d should not block: %v", i, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	if err := w.Submit(ctx, 4); !stderrors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected Submit to block at the in-flight limit, got %v", err)
+	}
+	if inFlight := w.Stats().InFlight; inFlight != 4 {
+		t.Errorf("Expected 4 records in flight, got %d", inFlight)
+	}
+
+	// Releasing the sink frees slots for new records
+	close(s.gate)
+	if err := w.Submit(t.Context(), 4); err != nil {
+		t.Fatalf("Submit after release failed: %v", err)
+	}
+	if err := w.Close(t.Context()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if written := w.Stats().Written; written != 5 {
+		t.Errorf("Expected 5 records written, got %d", written)
+	}
+	t.Log("✓ Submit applies backpressure once MaxInFlight records are unacknowledged")
+}
+
+func TestAsyncWriterReportsFailures(t *testing.T) {
+	s := &recordingSink{failOn: map[int]bool{2: true}}
+	var failed []Batch[int]
+	w := NewAsyncWriter[int](s, Config{BatchSize: 2, FlushInterval: time.Hour}, nil).
+		WithCallback(func(b Batch[int]) {
+			if b.Err != nil {
+				failed = append(failed, b)
+			}
+		})
+
+	for i := 0; i < 6; i++ {
+		w.Submit(t.Context(), i)
+	}
+	if err := w.Flush(t.Context()); err == nil {
+		t.Fatal("Expected Flush to report the failed batch")
+	}
+	if len(failed) != 1 || failed[0].Seq != 2 || failed[0].Records[0] != 2 {
+		t.Fatalf("Expected batch 2 to fail, got %+v", failed)
+	}
+
+	// Errors are reported once; later flushes start clean
+	w.Submit(t.Context(), 6)
+	if err := w.Flush(t.Context()); err != nil {
+		t.Errorf("Expected a clean flush, got %v", err)
+	}
+	if stats := w.Stats(); stats.Failed != 2 || stats.Written != 5 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	if err := w.Close(t.Context()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := w.Submit(t.Context(), 7); !errors.IsCode(err, CodeWriterClosed) {
+		t.Errorf("Expected %s after Close, got %v", CodeWriterClosed, err)
+	}
+	t.Log("✓ Failed batches reach the callback and the next Flush")
+}
+
+// fakeBroker records published messages
+type fakeBroker struct {
+	types.MessageBroker
+	published map[string][][]byte
+}
+
+func (b *fakeBroker) Publish(ctx context.Context, topic string, message []byte) error {
+	b.published[topic] = append(b.published[topic], message)
+	return nil
+}
+
+func TestBrokerSink(t *testing.T) {
+	broker := &fakeBroker{published: make(map[string][][]byte)}
+	w := NewAsyncWriter[[]byte](NewBrokerSink(broker, "events"), Config{BatchSize: 3}, nil)
+
+	for i := 0; i < 7; i++ {
+		w.Submit(t.Context(), []byte(fmt.Sprintf("event-%d", i)))
+	}
+	if err := w.Close(t.Context()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	events := broker.published["events"]
+	if len(events) != 7 || string(events[6]) != "event-6" {
+		t.Errorf("Expected 7 events in order, got %d", len(events))
+	}
+	t.Log("✓ Broker sink publishes every acknowledged record")
+}
+
+func TestStreamSinkWritesAvroFile(t *testing.T) {
+	dir := "tmp/test_stream_sink"
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	manager, err := avro.NewManager(dir)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	file, err := os.Create(filepath.Join(dir, "users.avro"))
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	defer file.Close()
+
+	buffered := bufio.NewWriter(file)
+	encoder := manager.NewUserStreamWriter(buffered)
+	w := NewAsyncWriter[avro.User](NewStreamSink(buffered, encoder.Write), Config{BatchSize: 4}, nil)
+
+	users := manager.CreateSampleUsers(10)
+	for _, user := range users {
+		if err := w.Submit(t.Context(), user); err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+	}
+	if err := w.Close(t.Context()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	read, err := manager.ReadUsersFromFile("users.avro")
+	if err != nil {
+		t.Fatalf("Failed to read users: %v", err)
+	}
+	if len(read) != len(users) || read[9].ID != users[9].ID {
+		t.Errorf("Expected %d users back, got %d", len(users), len(read))
+	}
+	t.Log("✓ Stream sink flushes acknowledged batches to the file")
+}
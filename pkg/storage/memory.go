@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"go-transport-prac/internal/types"
+)
+
+var _ types.Storage = (*MemoryStorage)(nil)
+
+// MemoryStorage keeps objects in memory, for tests and local experiments
+type MemoryStorage struct {
+	mu      sync.RWMutex
+	objects map[string][]byte
+}
+
+// NewMemoryStorage creates an empty in-memory storage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{objects: make(map[string][]byte)}
+}
+
+// Put stores a copy of data under key
+func (s *MemoryStorage) Put(ctx context.Context, key string, data io.Reader) error {
+	content, err := io.ReadAll(data)
+	if err != nil {
+		return fmt.Errorf("failed to read object %s: %w", key, err)
+	}
+
+	s.mu.Lock()
+	s.objects[key] = content
+	s.mu.Unlock()
+	return nil
+}
+
+// Get returns a reader over the object stored under key
+func (s *MemoryStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.mu.RLock()
+	content, ok := s.objects[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, notFound(key)
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+// Delete removes the object stored under key
+func (s *MemoryStorage) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	delete(s.objects, key)
+	s.mu.Unlock()
+	return nil
+}
+
+// Exists reports whether an object is stored under key
+func (s *MemoryStorage) Exists(ctx context.Context, key string) (bool, error) {
+	s.mu.RLock()
+	_, ok := s.objects[key]
+	s.mu.RUnlock()
+	return ok, nil
+}
+
+// List returns the sorted keys starting with prefix
+func (s *MemoryStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var keys []string
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"go-transport-prac/internal/config"
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/types"
+)
+
+var _ types.Storage = (*MinIOStorage)(nil)
+
+// MinIOStorage stores objects in a single MinIO/S3 bucket
+type MinIOStorage struct {
+	client *minio.Client
+	bucket string
+	region string
+}
+
+// NewMinIOStorage creates a storage client for the configured endpoint and bucket
+func NewMinIOStorage(cfg config.MinIOConfig) (*MinIOStorage, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create minio client: %w", err)
+	}
+
+	return &MinIOStorage{client: client, bucket: cfg.BucketName, region: cfg.Region}, nil
+}
+
+// Bucket returns the bucket objects are stored in
+func (s *MinIOStorage) Bucket() string {
+	return s.bucket
+}
+
+// StorageID names the endpoint and bucket, so managers with separate clients
+// for one bucket share file locks
+func (s *MinIOStorage) StorageID() string {
+	return s.client.EndpointURL().String() + "/" + s.bucket
+}
+
+// EnsureBucket creates the bucket if it does not exist yet
+func (s *MinIOStorage) EnsureBucket(ctx context.Context) error {
+	exists, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		return fmt.Errorf("failed to check bucket %s: %w", s.bucket, err)
+	}
+	if exists {
+		return nil
+	}
+
+	if err := s.client.MakeBucket(ctx, s.bucket, minio.MakeBucketOptions{Region: s.region}); err != nil {
+		return fmt.Errorf("failed to create bucket %s: %w", s.bucket, err)
+	}
+	return nil
+}
+
+// Ping checks that the server is reachable and the bucket exists
+func (s *MinIOStorage) Ping(ctx context.Context) error {
+	exists, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		return fmt.Errorf("failed to ping minio: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("bucket %s does not exist", s.bucket)
+	}
+	return nil
+}
+
+// Put uploads data under key, replacing any existing object
+func (s *MinIOStorage) Put(ctx context.Context, key string, data io.Reader) error {
+	// A known size lets small objects go up in one request instead of a multipart upload
+	size := int64(-1)
+	if sized, ok := data.(interface{ Len() int }); ok {
+		size = int64(sized.Len())
+	}
+
+	if _, err := s.client.PutObject(ctx, s.bucket, key, data, size, minio.PutObjectOptions{
+		ContentType: "application/octet-stream",
+	}); err != nil {
+		return fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get opens the object stored under key
+func (s *MinIOStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object %s: %w", key, err)
+	}
+
+	// GetObject is lazy; Stat surfaces a missing key before the caller starts reading
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		if isNotFound(err) {
+			return nil, notFound(key)
+		}
+		return nil, fmt.Errorf("failed to get object %s: %w", key, err)
+	}
+	return obj, nil
+}
+
+// Delete removes the object stored under key; deleting a missing key is not an error
+func (s *MinIOStorage) Delete(ctx context.Context, key string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete object %s: %w", key, err)
+	}
+	return nil
+}
+
+// Exists reports whether an object is stored under key
+func (s *MinIOStorage) Exists(ctx context.Context, key string) (bool, error) {
+	if _, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{}); err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to stat object %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// List returns the keys of every object whose key starts with prefix
+func (s *MinIOStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	for obj := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", obj.Err)
+		}
+		keys = append(keys, obj.Key)
+	}
+	return keys, nil
+}
+
+// isNotFound reports whether err is an S3 missing key or bucket response
+func isNotFound(err error) bool {
+	switch minio.ToErrorResponse(err).Code {
+	case "NoSuchKey", "NoSuchBucket", "NotFound":
+		return true
+	}
+	return false
+}
+
+func notFound(key string) error {
+	return errors.NotFoundError(errors.CodeNotFound, fmt.Sprintf("object %s not found", key))
+}
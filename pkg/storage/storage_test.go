@@ -0,0 +1,233 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/config"
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/types"
+)
+
+// fakeS3 serves the subset of the S3 API MinIOStorage uses, with path-style addressing
+type fakeS3 struct {
+	mu      sync.Mutex
+	buckets map[string]map[string][]byte
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{buckets: make(map[string]map[string][]byte)}
+}
+
+func (f *fakeS3) ServeHTTP(w nethttp.ResponseWriter, r *nethttp.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	objects, ok := f.buckets[bucket]
+
+	switch {
+	case key == "" && r.Method == nethttp.MethodHead:
+		if !ok {
+			w.WriteHeader(nethttp.StatusNotFound)
+		}
+	case key == "" && r.Method == nethttp.MethodPut:
+		f.buckets[bucket] = make(map[string][]byte)
+	case !ok:
+		writeS3Error(w, nethttp.StatusNotFound, "NoSuchBucket")
+	case key == "" && r.Method == nethttp.MethodGet:
+		writeListing(w, bucket, objects, r.URL.Query().Get("prefix"))
+	case r.Method == nethttp.MethodPut:
+		data, err := readPayload(r)
+		if err != nil {
+			writeS3Error(w, nethttp.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		objects[key] = data
+		w.Header().Set("ETag", `"etag"`)
+	case r.Method == nethttp.MethodDelete:
+		delete(objects, key)
+		w.WriteHeader(nethttp.StatusNoContent)
+	case r.Method == nethttp.MethodGet || r.Method == nethttp.MethodHead:
+		data, ok := objects[key]
+		if !ok {
+			writeS3Error(w, nethttp.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(nethttp.TimeFormat))
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		if r.Method == nethttp.MethodGet {
+			w.Write(data)
+		}
+	default:
+		w.WriteHeader(nethttp.StatusMethodNotAllowed)
+	}
+}
+
+// readPayload returns the object body, undoing aws-chunked encoding used for
+// streaming signatures over plain HTTP
+func readPayload(r *nethttp.Request) ([]byte, error) {
+	if !strings.Contains(r.Header.Get("Content-Encoding"), "aws-chunked") &&
+		!strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+
+	var data bytes.Buffer
+	reader := bufio.NewReader(r.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return data.Bytes(), nil
+		}
+		if _, err := io.CopyN(&data, reader, size); err != nil {
+			return nil, err
+		}
+		if _, err := reader.Discard(2); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func writeS3Error(w nethttp.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+func writeListing(w nethttp.ResponseWriter, bucket string, objects map[string][]byte, prefix string) {
+	type content struct {
+		Key          string
+		Size         int
+		LastModified string
+		ETag         string
+	}
+	listing := struct {
+		XMLName     xml.Name `xml:"ListBucketResult"`
+		Name        string
+		Prefix      string
+		KeyCount    int
+		MaxKeys     int
+		IsTruncated bool
+		Contents    []content
+	}{Name: bucket, Prefix: prefix, MaxKeys: 1000}
+
+	for key, data := range objects {
+		if strings.HasPrefix(key, prefix) {
+			listing.Contents = append(listing.Contents, content{
+				Key:          key,
+				Size:         len(data),
+				LastModified: time.Now().UTC().Format(time.RFC3339),
+				ETag:         `"etag"`,
+			})
+		}
+	}
+	sort.Slice(listing.Contents, func(i, j int) bool { return listing.Contents[i].Key < listing.Contents[j].Key })
+	listing.KeyCount = len(listing.Contents)
+
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(listing)
+}
+
+// exerciseStorage checks the types.Storage contract shared by every backend
+func exerciseStorage(t *testing.T, s types.Storage) {
+	t.Helper()
+	ctx := t.Context()
+
+	if err := s.Put(ctx, "avro/users.avro", bytes.NewReader([]byte("users"))); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if err := s.Put(ctx, "avro/products.avro", strings.NewReader("products")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if err := s.Put(ctx, "parquet/users.parquet", bytes.NewBufferString("columns")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	rc, err := s.Get(ctx, "avro/users.avro")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "users" {
+		t.Errorf("Expected %q, got %q", "users", data)
+	}
+
+	keys, err := s.List(ctx, "avro/")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if !reflect.DeepEqual(keys, []string{"avro/products.avro", "avro/users.avro"}) {
+		t.Errorf("Unexpected keys: %v", keys)
+	}
+
+	if exists, err := s.Exists(ctx, "avro/users.avro"); err != nil || !exists {
+		t.Errorf("Expected object to exist, got %v (%v)", exists, err)
+	}
+	if err := s.Delete(ctx, "avro/users.avro"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if exists, err := s.Exists(ctx, "avro/users.avro"); err != nil || exists {
+		t.Errorf("Expected object to be gone, got %v (%v)", exists, err)
+	}
+
+	if _, err := s.Get(ctx, "avro/users.avro"); !errors.IsType(err, errors.ErrorTypeNotFound) {
+		t.Errorf("Expected NotFound error, got %v", err)
+	}
+}
+
+func TestMemoryStorage(t *testing.T) {
+	exerciseStorage(t, NewMemoryStorage())
+	t.Log("✓ Memory storage satisfies the storage contract")
+}
+
+func TestMinIOStorage(t *testing.T) {
+	server := httptest.NewServer(newFakeS3())
+	defer server.Close()
+
+	storage, err := NewMinIOStorage(config.MinIOConfig{
+		Endpoint:        strings.TrimPrefix(server.URL, "http://"),
+		AccessKeyID:     "minioadmin",
+		SecretAccessKey: "minioadmin",
+		BucketName:      "transport-data",
+		Region:          "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	// Operations on a missing bucket fail until it is created
+	if err := storage.Put(t.Context(), "key", strings.NewReader("x")); err == nil {
+		t.Error("Expected Put to fail before the bucket exists")
+	}
+	if err := storage.EnsureBucket(t.Context()); err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+	if err := storage.EnsureBucket(t.Context()); err != nil {
+		t.Fatalf("EnsureBucket should be idempotent: %v", err)
+	}
+
+	exerciseStorage(t, storage)
+	t.Log("✓ MinIO storage satisfies the storage contract")
+}
@@ -0,0 +1,154 @@
+package codec
+
+import (
+	"mime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-transport-prac/internal/errors"
+)
+
+// aliases maps other names of the built-in media types onto them
+var aliases = map[string]string{
+	"text/json":                       JSON,
+	"application/avro":                Avro,
+	"avro/binary":                     Avro,
+	"application/x-avro":              Avro,
+	"application/protobuf":            Protobuf,
+	"application/vnd.google.protobuf": Protobuf,
+	"application/x-msgpack":           MsgPack,
+	"application/vnd.msgpack":         MsgPack,
+}
+
+// Canonical returns the media type a built-in alias stands for, or
+// mediaType itself
+func Canonical(mediaType string) string {
+	mediaType = strings.ToLower(mediaType)
+	if canonical, ok := aliases[mediaType]; ok {
+		return canonical
+	}
+	return mediaType
+}
+
+// MediaRange is one media range of an Accept header
+type MediaRange struct {
+	Type    string
+	Quality float64
+}
+
+// matches reports whether the range admits mediaType
+func (r MediaRange) matches(mediaType string) bool {
+	switch {
+	case r.Type == "*/*":
+		return true
+	case strings.HasSuffix(r.Type, "/*"):
+		return strings.HasPrefix(mediaType, strings.TrimSuffix(r.Type, "*"))
+	default:
+		return r.Type == mediaType
+	}
+}
+
+// ParseAccept returns the ranges of an Accept header by descending quality,
+// in header order among equal qualities. Malformed ranges are skipped and
+// ranges with q=0 are kept, as they refuse a type
+func ParseAccept(accept string) []MediaRange {
+	var ranges []MediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil && parsed >= 0 && parsed <= 1 {
+				quality = parsed
+			}
+		}
+		ranges = append(ranges, MediaRange{Type: Canonical(mediaType), Quality: quality})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].Quality > ranges[j].Quality
+	})
+	return ranges
+}
+
+// Negotiate picks the media type of a response from offers, the media types
+// that can be produced. Ranges are tried by descending quality; a wildcard
+// range prefers fallback when it matches, then the first offer it matches.
+// An empty header selects fallback. Media types refused with q=0 are never
+// picked. Fails with a NotAcceptable error when nothing acceptable is offered
+func Negotiate(accept string, offers []string, fallback string) (string, error) {
+	return negotiate(accept, offers, fallback, Canonical)
+}
+
+// ContentType picks the media type of a request body from its Content-Type
+// header among offers, defaulting to fallback when the header is empty.
+// Fails with an UnsupportedMediaType error otherwise
+func ContentType(contentType string, offers []string, fallback string) (string, error) {
+	return contentTypeOf(contentType, offers, fallback, Canonical)
+}
+
+func negotiate(accept string, offers []string, fallback string, canonical func(string) string) (string, error) {
+	if strings.TrimSpace(accept) == "" {
+		return fallback, nil
+	}
+
+	ranges := ParseAccept(accept)
+	refused := make(map[string]bool)
+	for _, r := range ranges {
+		if r.Quality == 0 {
+			refused[canonical(r.Type)] = true
+		}
+	}
+	acceptable := func(mediaType string) bool {
+		return mediaType != "" && !refused[mediaType]
+	}
+
+	for _, r := range ranges {
+		if r.Quality == 0 {
+			continue
+		}
+		mediaType := canonical(r.Type)
+		if !strings.HasSuffix(mediaType, "/*") {
+			for _, offer := range offers {
+				if offer == mediaType && acceptable(offer) {
+					return offer, nil
+				}
+			}
+			continue
+		}
+
+		if r.matches(fallback) && acceptable(fallback) && slices.Contains(offers, fallback) {
+			return fallback, nil
+		}
+		for _, offer := range offers {
+			if r.matches(offer) && acceptable(offer) {
+				return offer, nil
+			}
+		}
+	}
+
+	return "", errors.NotAcceptableError(errors.CodeNotAcceptable, "none of the accepted media types can be produced: "+accept)
+}
+
+func contentTypeOf(contentType string, offers []string, fallback string, canonical func(string) string) (string, error) {
+	if strings.TrimSpace(contentType) == "" {
+		return fallback, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", errors.UnsupportedMediaTypeError(errors.CodeUnsupportedMediaType, "malformed Content-Type header")
+	}
+
+	for _, offer := range offers {
+		if offer == canonical(mediaType) {
+			return offer, nil
+		}
+	}
+	return "", errors.UnsupportedMediaTypeError(errors.CodeUnsupportedMediaType, "unsupported Content-Type "+mediaType)
+}
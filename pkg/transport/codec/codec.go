@@ -0,0 +1,150 @@
+// Package codec is a registry of serializers keyed by MIME type, so
+// transports pick the serializer of a request body from its Content-Type and
+// of a response from its Accept header, with quality values and a fallback
+// when the client does not say.
+package codec
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/msgpack"
+)
+
+// MIME types of the built-in codecs
+const (
+	JSON     = "application/json"
+	Avro     = "application/avro-binary"
+	Protobuf = "application/x-protobuf"
+	MsgPack  = msgpack.ContentType
+)
+
+// Registry holds serializers by MIME type. It is safe for concurrent use
+type Registry struct {
+	mu       sync.RWMutex
+	codecs   map[string]types.Serializer
+	aliases  map[string]string
+	order    []string
+	fallback string
+}
+
+// NewRegistry returns an empty registry falling back to JSON
+func NewRegistry() *Registry {
+	return &Registry{
+		codecs:   make(map[string]types.Serializer),
+		aliases:  make(map[string]string),
+		fallback: JSON,
+	}
+}
+
+// Default returns a registry with the JSON, Avro, Protobuf and MessagePack
+// codecs, falling back to JSON
+func Default() (*Registry, error) {
+	avroCodec, err := NewAvroSerializer()
+	if err != nil {
+		return nil, err
+	}
+	return NewRegistry().
+		Register(JSONSerializer{}).
+		Register(avroCodec).
+		Register(NewProtobufSerializer()).
+		Register(msgpack.NewManager("")), nil
+}
+
+// Register adds s under its ContentType and any aliases, replacing a codec
+// registered under the same type. Codecs registered first win wildcard
+// Accept ranges
+func (r *Registry) Register(s types.Serializer, aliases ...string) *Registry {
+	mediaType := strings.ToLower(s.ContentType())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.codecs[mediaType]; !ok {
+		r.order = append(r.order, mediaType)
+	}
+	r.codecs[mediaType] = s
+	for _, alias := range aliases {
+		r.aliases[strings.ToLower(alias)] = mediaType
+	}
+	return r
+}
+
+// WithFallback sets the MIME type used when a request names none
+func (r *Registry) WithFallback(mediaType string) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = r.canonical(mediaType)
+	return r
+}
+
+// Get returns the codec registered under mediaType or one of its aliases
+func (r *Registry) Get(mediaType string) (types.Serializer, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.codecs[r.canonical(mediaType)]
+	return s, ok
+}
+
+// MediaTypes returns the registered MIME types in registration order
+func (r *Registry) MediaTypes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.order)
+}
+
+// Fallback returns the codec used when a request names no MIME type
+func (r *Registry) Fallback() (types.Serializer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.get(r.fallback)
+}
+
+// ForContentType returns the codec of a request body from its Content-Type
+// header, the fallback when it is empty. Fails with an UnsupportedMediaType
+// error for types that are not registered
+func (r *Registry) ForContentType(contentType string) (types.Serializer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	mediaType, err := contentTypeOf(contentType, r.order, r.fallback, r.canonical)
+	if err != nil {
+		return nil, err
+	}
+	return r.get(mediaType)
+}
+
+// ForAccept returns the codec of a response from an Accept header, see
+// Negotiate. An empty fallback uses the registry's
+func (r *Registry) ForAccept(accept, fallback string) (types.Serializer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if fallback == "" {
+		fallback = r.fallback
+	}
+	mediaType, err := negotiate(accept, r.order, r.canonical(fallback), r.canonical)
+	if err != nil {
+		return nil, err
+	}
+	return r.get(mediaType)
+}
+
+// get returns the codec of a canonical MIME type. Must be called with r.mu held
+func (r *Registry) get(mediaType string) (types.Serializer, error) {
+	s, ok := r.codecs[mediaType]
+	if !ok {
+		return nil, fmt.Errorf("no codec registered for %s", mediaType)
+	}
+	return s, nil
+}
+
+// canonical resolves the registry's aliases and the built-in ones. Must be
+// called with r.mu held
+func (r *Registry) canonical(mediaType string) string {
+	mediaType = strings.ToLower(mediaType)
+	if canonical, ok := r.aliases[mediaType]; ok {
+		return canonical
+	}
+	return Canonical(mediaType)
+}
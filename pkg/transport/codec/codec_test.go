@@ -0,0 +1,154 @@
+package codec
+
+import (
+	"testing"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+func TestParseAccept(t *testing.T) {
+	ranges := ParseAccept("text/html;q=0.2, application/avro, bogus;;, application/*;q=0.5, */*;q=0")
+
+	want := []MediaRange{{Avro, 1}, {"application/*", 0.5}, {"text/html", 0.2}, {"*/*", 0}}
+	if len(ranges) != len(want) {
+		t.Fatalf("Expected %d ranges, got %v", len(want), ranges)
+	}
+	for i := range want {
+		if ranges[i] != want[i] {
+			t.Errorf("Range %d: expected %v, got %v", i, want[i], ranges[i])
+		}
+	}
+
+	t.Log("✓ Accept ranges are ordered by quality with aliases resolved")
+}
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{JSON, Avro, Protobuf}
+	tests := []struct {
+		accept   string
+		fallback string
+		want     string
+	}{
+		{"", Protobuf, Protobuf},
+		{"*/*", Avro, Avro},
+		{"application/json;q=0.5, avro/binary", JSON, Avro},
+		{"text/html, application/protobuf;q=0.1", JSON, Protobuf},
+		{"application/*, application/json;q=0", JSON, Avro},
+		{"*/*", MsgPack, JSON},
+		{"application/avro;q=0, text/html", JSON, ""},
+		{"application/msgpack", JSON, ""},
+	}
+
+	for _, tt := range tests {
+		got, err := Negotiate(tt.accept, offers, tt.fallback)
+		if got != tt.want || (err != nil) != (tt.want == "") {
+			t.Errorf("Negotiate(%q, %q) = %q, %v; want %q", tt.accept, tt.fallback, got, err, tt.want)
+		}
+		if appErr, ok := errors.AsAppError(err); err != nil && (!ok || appErr.Code != errors.CodeNotAcceptable) {
+			t.Errorf("Expected a NotAcceptable error, got %v", err)
+		}
+	}
+
+	t.Log("✓ Negotiation honours quality values, wildcards and the fallback")
+}
+
+func TestRegistry(t *testing.T) {
+	registry, err := Default()
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+	if got := registry.MediaTypes(); len(got) != 4 || got[0] != JSON {
+		t.Errorf("Expected the four built-in codecs, got %v", got)
+	}
+
+	for contentType, want := range map[string]string{
+		"":                                JSON,
+		"application/json; charset=utf-8": JSON,
+		"application/x-avro":              Avro,
+		"application/vnd.google.protobuf": Protobuf,
+		"application/msgpack":             MsgPack,
+	} {
+		s, err := registry.ForContentType(contentType)
+		if err != nil || s.ContentType() != want {
+			t.Errorf("ForContentType(%q) = %v, %v; want %s", contentType, s, err, want)
+		}
+	}
+	for _, contentType := range []string{"text/plain", "not a; type;"} {
+		_, err := registry.ForContentType(contentType)
+		if appErr, ok := errors.AsAppError(err); !ok || appErr.Code != errors.CodeUnsupportedMediaType {
+			t.Errorf("Expected %q to be unsupported, got %v", contentType, err)
+		}
+	}
+
+	if s, err := registry.ForAccept("*/*", ""); err != nil || s.ContentType() != JSON {
+		t.Errorf("Expected a wildcard to pick the JSON fallback, got %v, %v", s, err)
+	}
+	registry.WithFallback("application/x-msgpack")
+	if s, err := registry.ForAccept("", ""); err != nil || s.ContentType() != MsgPack {
+		t.Errorf("Expected the MessagePack fallback, got %v, %v", s, err)
+	}
+	if s, err := registry.ForAccept("text/*, application/x-protobuf;q=0.4", Avro); err != nil || s.ContentType() != Protobuf {
+		t.Errorf("Expected Protobuf, got %v, %v", s, err)
+	}
+
+	registry.Register(JSONSerializer{}, "application/vnd.api+json")
+	if s, ok := registry.Get("application/vnd.api+json"); !ok || s.ContentType() != JSON {
+		t.Errorf("Expected a registered alias to resolve, got %v", s)
+	}
+	if len(registry.MediaTypes()) != 4 {
+		t.Errorf("Expected re-registering to replace, got %v", registry.MediaTypes())
+	}
+
+	t.Log("✓ The registry picks codecs from Content-Type and Accept")
+}
+
+func TestCodecsRoundTrip(t *testing.T) {
+	registry, err := Default()
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+	manager, _ := avro.NewManager("")
+	users := manager.CreateSampleUsers(2)
+
+	for _, mediaType := range registry.MediaTypes() {
+		s, _ := registry.Get(mediaType)
+		data, err := s.Serialize(users[0])
+		if err != nil {
+			t.Fatalf("%s: failed to serialize: %v", mediaType, err)
+		}
+		var back avro.User
+		if err := s.Deserialize(data, &back); err != nil {
+			t.Fatalf("%s: failed to deserialize: %v", mediaType, err)
+		}
+		if back.ID != users[0].ID || back.Email != users[0].Email {
+			t.Errorf("%s: round trip mismatch, got %+v", mediaType, back)
+		}
+	}
+
+	avroCodec, _ := registry.Get(Avro)
+	data, err := avroCodec.Serialize(users)
+	if err != nil {
+		t.Fatalf("Failed to serialize a slice as Avro: %v", err)
+	}
+	var list []avro.User
+	if err := avroCodec.Deserialize(data, &list); err != nil || len(list) != 2 {
+		t.Errorf("Expected 2 users back, got %d: %v", len(list), err)
+	}
+
+	protoCodec, _ := registry.Get(Protobuf)
+	data, err = protoCodec.Serialize(&user.User{Id: 7, Email: "p@example.com"})
+	if err != nil {
+		t.Fatalf("Failed to serialize a message: %v", err)
+	}
+	var fromProto avro.User
+	if err := protoCodec.Deserialize(data, &fromProto); err != nil || fromProto.ID != 7 {
+		t.Errorf("Expected a message to decode into its model, got %+v: %v", fromProto, err)
+	}
+	if _, err := protoCodec.Serialize(struct{}{}); err == nil {
+		t.Error("Expected an unknown type to fail")
+	}
+
+	t.Log("✓ Every built-in codec round-trips the shared models")
+}
@@ -0,0 +1,214 @@
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	hamba "github.com/hamba/avro/v2"
+	"google.golang.org/protobuf/proto"
+
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/protobuf"
+	"go-transport-prac/pkg/sdl/protobuf/gen/analytics"
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+	"go-transport-prac/pkg/transport/internal/convert"
+)
+
+var (
+	_ types.Serializer = JSONSerializer{}
+	_ types.Serializer = (*AvroSerializer)(nil)
+	_ types.Serializer = (*ProtobufSerializer)(nil)
+)
+
+// JSONSerializer encodes values with encoding/json
+type JSONSerializer struct{}
+
+// Serialize encodes data as JSON
+func (JSONSerializer) Serialize(data any) ([]byte, error) {
+	return json.Marshal(data)
+}
+
+// Deserialize decodes JSON into target
+func (JSONSerializer) Deserialize(data []byte, target any) error {
+	return json.Unmarshal(data, target)
+}
+
+// ContentType returns the MIME type of JSON
+func (JSONSerializer) ContentType() string {
+	return JSON
+}
+
+// FileExtension returns the extension of JSON files
+func (JSONSerializer) FileExtension() string {
+	return ".json"
+}
+
+// AvroSerializer encodes the Avro User, Product, Order and Analytics models,
+// and slices of them, as Avro binary with their compiled-in schemas
+type AvroSerializer struct {
+	manager *avro.Manager
+	schemas map[reflect.Type]hamba.Schema
+}
+
+// NewAvroSerializer returns an Avro serializer knowing the shared models
+func NewAvroSerializer() (*AvroSerializer, error) {
+	manager, err := avro.NewManager("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avro manager: %w", err)
+	}
+	s := &AvroSerializer{manager: manager, schemas: make(map[reflect.Type]hamba.Schema)}
+	s.RegisterType(avro.User{}, manager.GetUserSchema())
+	s.RegisterType(avro.Product{}, manager.GetProductSchema())
+	s.RegisterType(avro.Order{}, manager.GetOrderSchema())
+	s.RegisterType(avro.Analytics{}, manager.GetAnalyticsSchema())
+	return s, nil
+}
+
+// RegisterType maps the type of v, and slices of it, to schema
+func (s *AvroSerializer) RegisterType(v any, schema hamba.Schema) *AvroSerializer {
+	t := indirectType(reflect.TypeOf(v))
+	s.schemas[t] = schema
+	s.schemas[reflect.SliceOf(t)] = hamba.NewArraySchema(schema)
+	return s
+}
+
+// Serialize encodes data with the schema registered for its type
+func (s *AvroSerializer) Serialize(data any) ([]byte, error) {
+	schema, err := s.schemaFor(data)
+	if err != nil {
+		return nil, err
+	}
+	return s.manager.SerializeStruct(schema, data)
+}
+
+// Deserialize decodes data into target, a pointer to a registered type
+func (s *AvroSerializer) Deserialize(data []byte, target any) error {
+	schema, err := s.schemaFor(target)
+	if err != nil {
+		return err
+	}
+	return s.manager.DeserializeStruct(schema, data, target)
+}
+
+// ContentType returns the MIME type of Avro binary
+func (s *AvroSerializer) ContentType() string {
+	return Avro
+}
+
+// FileExtension returns the extension of Avro binary files
+func (s *AvroSerializer) FileExtension() string {
+	return ".avro"
+}
+
+func (s *AvroSerializer) schemaFor(v any) (hamba.Schema, error) {
+	schema, ok := s.schemas[indirectType(reflect.TypeOf(v))]
+	if !ok {
+		return nil, fmt.Errorf("no avro schema registered for %T", v)
+	}
+	return schema, nil
+}
+
+// protoConversion converts one Go type to and from its Protobuf message
+type protoConversion struct {
+	toProto   func(any) (proto.Message, bool)
+	fromProto func(proto.Message, any) bool
+	newProto  func() proto.Message
+}
+
+// ProtobufSerializer encodes proto.Message values, and the Avro models
+// through their Protobuf counterparts
+type ProtobufSerializer struct {
+	manager     *protobuf.Manager
+	conversions map[reflect.Type]protoConversion
+}
+
+// NewProtobufSerializer returns a Protobuf serializer converting the User,
+// Product, Order and Analytics models
+func NewProtobufSerializer() *ProtobufSerializer {
+	s := &ProtobufSerializer{manager: protobuf.NewManager(), conversions: make(map[reflect.Type]protoConversion)}
+	RegisterProto(s, convert.UserToProto, convert.UserFromProto, func() *user.User { return &user.User{} })
+	RegisterProto(s, convert.ProductToProto, convert.ProductFromProto, func() *product.Product { return &product.Product{} })
+	RegisterProto(s, convert.OrderToProto, convert.OrderFromProto, func() *order.Order { return &order.Order{} })
+	RegisterProto(s, convert.AnalyticsToProto, convert.AnalyticsFromProto, func() *analytics.AnalyticsEvent { return &analytics.AnalyticsEvent{} })
+	return s
+}
+
+// RegisterProto lets s encode T as the message M
+func RegisterProto[T any, M proto.Message](s *ProtobufSerializer, to func(T) M, from func(M) T, newMsg func() M) {
+	s.conversions[reflect.TypeOf((*T)(nil)).Elem()] = protoConversion{
+		toProto: func(v any) (proto.Message, bool) {
+			switch v := v.(type) {
+			case T:
+				return to(v), true
+			case *T:
+				if v != nil {
+					return to(*v), true
+				}
+			}
+			return nil, false
+		},
+		fromProto: func(msg proto.Message, target any) bool {
+			p, ok := target.(*T)
+			if ok {
+				*p = from(msg.(M))
+			}
+			return ok
+		},
+		newProto: func() proto.Message { return newMsg() },
+	}
+}
+
+// Serialize encodes a proto.Message or a registered type
+func (s *ProtobufSerializer) Serialize(data any) ([]byte, error) {
+	if msg, ok := data.(proto.Message); ok {
+		return s.manager.Serialize(msg)
+	}
+	if c, ok := s.conversions[indirectType(reflect.TypeOf(data))]; ok {
+		if msg, ok := c.toProto(data); ok {
+			return s.manager.Serialize(msg)
+		}
+	}
+	return nil, fmt.Errorf("protobuf codec cannot encode %T", data)
+}
+
+// Deserialize decodes data into a proto.Message or a pointer to a
+// registered type
+func (s *ProtobufSerializer) Deserialize(data []byte, target any) error {
+	if msg, ok := target.(proto.Message); ok {
+		return s.manager.Deserialize(data, msg)
+	}
+	c, ok := s.conversions[indirectType(reflect.TypeOf(target))]
+	if !ok {
+		return fmt.Errorf("protobuf codec cannot decode into %T", target)
+	}
+	msg := c.newProto()
+	if err := s.manager.Deserialize(data, msg); err != nil {
+		return err
+	}
+	if !c.fromProto(msg, target) {
+		return fmt.Errorf("protobuf codec cannot decode into %T", target)
+	}
+	return nil
+}
+
+// ContentType returns the MIME type of Protobuf
+func (s *ProtobufSerializer) ContentType() string {
+	return Protobuf
+}
+
+// FileExtension returns the extension of Protobuf files
+func (s *ProtobufSerializer) FileExtension() string {
+	return ".pb"
+}
+
+// indirectType strips pointer indirection
+func indirectType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
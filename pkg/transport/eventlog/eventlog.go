@@ -0,0 +1,202 @@
+package eventlog
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/types"
+)
+
+// DefaultCapacity is the number of events a log retains when no capacity is given
+const DefaultCapacity = 1024
+
+// Event is one published event. Seq increases by one per event, starting at 1
+type Event struct {
+	Seq   uint64
+	Topic string
+	Time  time.Time
+	Value interface{}
+}
+
+// Cursor returns the cursor a client passes to resume after this event
+func (e Event) Cursor() string {
+	return FormatCursor(e.Seq)
+}
+
+// Page is the result of a read: the matching events and the cursor to read from next
+type Page struct {
+	Events []Event
+	Cursor string
+}
+
+// Log is a bounded, in-memory log of events shared by the streaming
+// transports, so a client can resume any of them from the same cursor.
+//
+// A cursor names the last event a client has seen; reads return the events
+// after it. The empty cursor means "from now": only events published after
+// the read started. Cursor "0" reads from the oldest retained event. Once
+// events a cursor still needs have been evicted, reads with it fail with a
+// gone error and the client must resynchronize from the empty cursor
+type Log struct {
+	clock    types.Clock
+	capacity int
+
+	mu     sync.Mutex
+	events []Event
+	next   uint64
+	notify chan struct{}
+}
+
+// NewLog creates a log retaining the last capacity events
+func NewLog(capacity int) *Log {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Log{
+		clock:    types.SystemClock{},
+		capacity: capacity,
+		next:     1,
+		notify:   make(chan struct{}),
+	}
+}
+
+// WithClock sets the clock used for event timestamps
+func (l *Log) WithClock(clock types.Clock) *Log {
+	l.clock = types.ClockOrSystem(clock)
+	return l
+}
+
+// FormatCursor renders a sequence number as a cursor
+func FormatCursor(seq uint64) string {
+	return strconv.FormatUint(seq, 10)
+}
+
+// Append publishes an event and wakes every waiting reader
+func (l *Log) Append(topic string, value interface{}) Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	event := Event{Seq: l.next, Topic: topic, Time: l.clock.Now(), Value: value}
+	l.next++
+
+	l.events = append(l.events, event)
+	if len(l.events) > l.capacity {
+		// Copy rather than reslice so evicted values can be collected
+		l.events = append(l.events[:0:0], l.events[len(l.events)-l.capacity:]...)
+	}
+
+	close(l.notify)
+	l.notify = make(chan struct{})
+	return event
+}
+
+// Head returns the cursor of the latest event, which reads nothing until the next one
+func (l *Log) Head() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return FormatCursor(l.next - 1)
+}
+
+// Len returns the number of retained events
+func (l *Log) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.events)
+}
+
+// Read returns up to limit events after cursor on the given topics, without
+// waiting. No topics means every topic; a limit of zero or less means no limit
+func (l *Log) Read(cursor string, topics []string, limit int) (Page, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	after, err := l.resolve(cursor)
+	if err != nil {
+		return Page{}, err
+	}
+	return l.read(after, topicSet(topics), limit), nil
+}
+
+// Wait is Read, but blocks until at least one matching event is published or
+// ctx is done. A wait that ends without events returns an empty page whose
+// cursor skips any events on other topics
+func (l *Log) Wait(ctx context.Context, cursor string, topics []string, limit int) (Page, error) {
+	filter := topicSet(topics)
+
+	l.mu.Lock()
+	after, err := l.resolve(cursor)
+	for err == nil {
+		page := l.read(after, filter, limit)
+		if len(page.Events) > 0 {
+			l.mu.Unlock()
+			return page, nil
+		}
+		// Nothing after the head matched, so the wait resumes from there and
+		// evicting the skipped events cannot expire it
+		after = l.next - 1
+		notify := l.notify
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return page, nil
+		case <-notify:
+		}
+
+		l.mu.Lock()
+		// More than the capacity may have been appended before relocking
+		_, err = l.resolve(FormatCursor(after))
+	}
+	l.mu.Unlock()
+	return Page{}, err
+}
+
+// resolve converts a cursor to the sequence number reads start after
+func (l *Log) resolve(cursor string) (uint64, error) {
+	head := l.next - 1
+	if cursor == "" {
+		return head, nil
+	}
+
+	seq, err := strconv.ParseUint(cursor, 10, 64)
+	if err != nil {
+		return 0, errors.BadRequestError(errors.CodeInvalidValue, fmt.Sprintf("invalid cursor %q", cursor))
+	}
+	if seq > head {
+		// Usually a cursor issued before the process restarted
+		return 0, errors.GoneError(errors.CodeExpired, fmt.Sprintf("cursor %s is ahead of the log at %d, resynchronize from the latest event", cursor, head))
+	}
+	if len(l.events) > 0 && seq+1 < l.events[0].Seq {
+		return 0, errors.GoneError(errors.CodeExpired, fmt.Sprintf("cursor %s has expired, the oldest retained event is %d", cursor, l.events[0].Seq))
+	}
+	return seq, nil
+}
+
+// read collects events after seq. The next cursor is the last returned event
+// when the limit cut the page short, otherwise the head of the log
+func (l *Log) read(after uint64, topics map[string]bool, limit int) Page {
+	var events []Event
+	for _, event := range l.events {
+		if event.Seq <= after || (len(topics) > 0 && !topics[event.Topic]) {
+			continue
+		}
+		events = append(events, event)
+		if limit > 0 && len(events) == limit {
+			return Page{Events: events, Cursor: event.Cursor()}
+		}
+	}
+	return Page{Events: events, Cursor: FormatCursor(l.next - 1)}
+}
+
+// topicSet indexes a topic filter
+func topicSet(topics []string) map[string]bool {
+	set := make(map[string]bool, len(topics))
+	for _, topic := range topics {
+		set[topic] = true
+	}
+	return set
+}
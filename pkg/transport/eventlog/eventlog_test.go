@@ -0,0 +1,174 @@
+package eventlog
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/testutil"
+)
+
+func TestReadCursors(t *testing.T) {
+	log := NewLog(0).WithClock(testutil.NewDefaultFakeClock())
+
+	if page, err := log.Read("", nil, 0); err != nil || len(page.Events) != 0 || page.Cursor != "0" {
+		t.Fatalf("Expected empty page at cursor 0, got %+v %v", page, err)
+	}
+
+	for i := 0; i < 5; i++ {
+		topic := "orders"
+		if i%2 == 1 {
+			topic = "analytics"
+		}
+		event := log.Append(topic, i)
+		if event.Seq != uint64(i+1) || !event.Time.Equal(testutil.DefaultFakeTime) {
+			t.Errorf("Unexpected event %+v", event)
+		}
+	}
+
+	page, err := log.Read("0", nil, 0)
+	if err != nil || len(page.Events) != 5 || page.Cursor != "5" {
+		t.Fatalf("Expected all 5 events ending at cursor 5, got %+v %v", page, err)
+	}
+
+	// A limit stops the cursor at the last returned event
+	page, err = log.Read("1", nil, 2)
+	if err != nil || len(page.Events) != 2 || page.Events[0].Seq != 2 || page.Cursor != "3" {
+		t.Fatalf("Expected events 2-3 with cursor 3, got %+v %v", page, err)
+	}
+
+	// Filtered pages skip past events on other topics
+	page, err = log.Read("3", []string{"orders"}, 0)
+	if err != nil || len(page.Events) != 1 || page.Events[0].Seq != 5 || page.Cursor != "5" {
+		t.Fatalf("Expected order event 5 with cursor 5, got %+v %v", page, err)
+	}
+	page, err = log.Read("4", []string{"analytics"}, 0)
+	if err != nil || len(page.Events) != 0 || page.Cursor != "5" {
+		t.Fatalf("Expected no analytics events with cursor 5, got %+v %v", page, err)
+	}
+
+	if page, err := log.Read("", nil, 0); err != nil || len(page.Events) != 0 || page.Cursor != log.Head() {
+		t.Errorf("Expected the empty cursor to start at the head, got %+v %v", page, err)
+	}
+
+	t.Log("✓ Cursors resume after the last seen event")
+}
+
+func TestCursorErrors(t *testing.T) {
+	log := NewLog(3)
+	for i := 0; i < 6; i++ {
+		log.Append("orders", i)
+	}
+	if log.Len() != 3 {
+		t.Fatalf("Expected 3 retained events, got %d", log.Len())
+	}
+
+	// Events 4-6 are retained, so cursor 3 is the oldest that still resumes
+	if page, err := log.Read("3", nil, 0); err != nil || len(page.Events) != 3 {
+		t.Errorf("Expected cursor 3 to read events 4-6, got %+v %v", page, err)
+	}
+
+	cases := map[string]errors.ErrorType{
+		"2":   errors.ErrorTypeGone,
+		"0":   errors.ErrorTypeGone,
+		"7":   errors.ErrorTypeGone,
+		"abc": errors.ErrorTypeBadRequest,
+		"-1":  errors.ErrorTypeBadRequest,
+	}
+	for cursor, want := range cases {
+		_, err := log.Read(cursor, nil, 0)
+		if !errors.IsType(err, want) {
+			t.Errorf("Cursor %q: expected %s error, got %v", cursor, want, err)
+		}
+	}
+
+	t.Log("✓ Expired and malformed cursors are rejected")
+}
+
+func TestWait(t *testing.T) {
+	log := NewLog(0)
+	head := log.Head()
+
+	var wg sync.WaitGroup
+	results := make(chan Page, 1)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		page, err := log.Wait(context.Background(), head, []string{"orders"}, 0)
+		if err != nil {
+			t.Errorf("Wait failed: %v", err)
+		}
+		results <- page
+	}()
+
+	// An event on another topic does not end the wait
+	time.Sleep(20 * time.Millisecond)
+	log.Append("analytics", "skipped")
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case page := <-results:
+		t.Fatalf("Wait returned before a matching event: %+v", page)
+	default:
+	}
+
+	log.Append("orders", "matched")
+	wg.Wait()
+	page := <-results
+	if len(page.Events) != 1 || page.Events[0].Value != "matched" || page.Cursor != "2" {
+		t.Fatalf("Expected the order event with cursor 2, got %+v", page)
+	}
+
+	// A wait that times out returns an empty page at the current head
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	page, err := log.Wait(ctx, page.Cursor, nil, 0)
+	if err != nil || len(page.Events) != 0 || page.Cursor != "2" {
+		t.Errorf("Expected empty page at cursor 2, got %+v %v", page, err)
+	}
+
+	// Events already in the log are returned without waiting
+	page, err = log.Wait(context.Background(), "0", nil, 0)
+	if err != nil || len(page.Events) != 2 {
+		t.Errorf("Expected 2 events immediately, got %+v %v", page, err)
+	}
+
+	t.Log("✓ Wait blocks until a matching event or the deadline")
+}
+
+func TestFilteredWaitSurvivesEviction(t *testing.T) {
+	log := NewLog(2)
+	log.Append("orders", 1)
+	cursor := log.Head()
+
+	results := make(chan Page, 1)
+	go func() {
+		page, err := log.Wait(context.Background(), cursor, []string{"orders"}, 0)
+		if err != nil {
+			t.Errorf("Wait failed: %v", err)
+		}
+		results <- page
+	}()
+
+	// Events on another topic fill the log, one at a time, past the waiter's cursor
+	for i := 0; i < 3; i++ {
+		time.Sleep(10 * time.Millisecond)
+		log.Append("analytics", i)
+	}
+	if _, err := log.Read(cursor, nil, 0); !errors.IsType(err, errors.ErrorTypeGone) {
+		t.Fatalf("Expected the original cursor to have expired, got %v", err)
+	}
+	log.Append("orders", 2)
+
+	select {
+	case page := <-results:
+		if len(page.Events) != 1 || page.Events[0].Value != 2 || page.Cursor != "5" {
+			t.Errorf("Expected order event 5, got %+v", page)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return the order event")
+	}
+
+	t.Log("✓ Filtered waiters skip past evicted events on other topics")
+}
@@ -0,0 +1,53 @@
+package framing
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+// Echo reads frames from rw and writes each one back until the stream ends
+func Echo(rw io.ReadWriter, prefix Prefix) error {
+	reader := NewReader(rw, prefix)
+	writer := NewWriter(rw, prefix)
+
+	for {
+		payload, err := reader.ReadFrame()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := writer.WriteFrame(payload); err != nil {
+			return err
+		}
+	}
+}
+
+// ServeEcho accepts connections on ln and echoes their frames until ln is
+// closed, then waits for open connections to finish
+func ServeEcho(ln net.Listener, prefix Prefix) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+			// A client dropping mid-frame only ends its own connection
+			_ = Echo(conn, prefix)
+		}()
+	}
+}
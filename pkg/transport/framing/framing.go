@@ -0,0 +1,157 @@
+package framing
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Binary Avro and Protobuf records are not self-delimiting, so a stream of
+// them needs each record wrapped in a frame: a length prefix followed by
+// that many payload bytes. Both ends of a connection must use the same Prefix.
+
+// Prefix selects how a frame's payload length is encoded
+type Prefix int
+
+const (
+	// PrefixVarint encodes the length as an unsigned varint, as protobuf's delimited format does
+	PrefixVarint Prefix = iota
+	// PrefixFixed32 encodes the length as a 4-byte big-endian integer
+	PrefixFixed32
+)
+
+// String returns the prefix name
+func (p Prefix) String() string {
+	switch p {
+	case PrefixVarint:
+		return "varint"
+	case PrefixFixed32:
+		return "fixed32"
+	default:
+		return fmt.Sprintf("Prefix(%d)", int(p))
+	}
+}
+
+// ParsePrefix returns the Prefix named s
+func ParsePrefix(s string) (Prefix, error) {
+	switch s {
+	case "varint":
+		return PrefixVarint, nil
+	case "fixed32":
+		return PrefixFixed32, nil
+	default:
+		return 0, fmt.Errorf("unknown frame prefix %q", s)
+	}
+}
+
+// DefaultMaxFrameSize is the largest payload a Reader accepts unless configured otherwise
+const DefaultMaxFrameSize = 4 << 20
+
+// ErrFrameTooLarge is returned for frames longer than the reader's maximum
+var ErrFrameTooLarge = errors.New("frame exceeds maximum size")
+
+// Writer writes length-prefixed frames. It is not safe for concurrent use
+type Writer struct {
+	w      io.Writer
+	prefix Prefix
+	buf    []byte
+}
+
+// NewWriter creates a frame writer over w
+func NewWriter(w io.Writer, prefix Prefix) *Writer {
+	return &Writer{w: w, prefix: prefix}
+}
+
+// WriteFrame writes payload as one frame. The prefix and payload go out in a
+// single Write so a frame is never split across writes on w
+func (fw *Writer) WriteFrame(payload []byte) error {
+	fw.buf = fw.buf[:0]
+	switch fw.prefix {
+	case PrefixVarint:
+		fw.buf = binary.AppendUvarint(fw.buf, uint64(len(payload)))
+	case PrefixFixed32:
+		if uint64(len(payload)) > uint64(^uint32(0)) {
+			return fmt.Errorf("failed to write frame: %w", ErrFrameTooLarge)
+		}
+		fw.buf = binary.BigEndian.AppendUint32(fw.buf, uint32(len(payload)))
+	default:
+		return fmt.Errorf("unsupported frame prefix %s", fw.prefix)
+	}
+	fw.buf = append(fw.buf, payload...)
+
+	if _, err := fw.w.Write(fw.buf); err != nil {
+		return fmt.Errorf("failed to write frame: %w", err)
+	}
+	return nil
+}
+
+// Reader reads length-prefixed frames. It is not safe for concurrent use
+type Reader struct {
+	r            *bufio.Reader
+	prefix       Prefix
+	maxFrameSize int
+}
+
+// NewReader creates a frame reader over r
+func NewReader(r io.Reader, prefix Prefix) *Reader {
+	return &Reader{r: bufio.NewReader(r), prefix: prefix, maxFrameSize: DefaultMaxFrameSize}
+}
+
+// WithMaxFrameSize sets the largest payload ReadFrame accepts
+func (fr *Reader) WithMaxFrameSize(size int) *Reader {
+	fr.maxFrameSize = size
+	return fr
+}
+
+// ReadFrame returns the next frame's payload. It returns io.EOF when the
+// stream ends between frames and io.ErrUnexpectedEOF when it ends inside one
+func (fr *Reader) ReadFrame() ([]byte, error) {
+	size, err := fr.readLength()
+	if err != nil {
+		return nil, err
+	}
+	if size > uint64(fr.maxFrameSize) {
+		return nil, fmt.Errorf("failed to read frame of %d bytes: %w", size, ErrFrameTooLarge)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(fr.r, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("failed to read frame payload: %w", err)
+	}
+	return payload, nil
+}
+
+// readLength reads a frame's length prefix
+func (fr *Reader) readLength() (uint64, error) {
+	switch fr.prefix {
+	case PrefixVarint:
+		// Peek first so a clean end of stream is reported as io.EOF
+		if _, err := fr.r.Peek(1); err != nil {
+			return 0, err
+		}
+		size, err := binary.ReadUvarint(fr.r)
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, fmt.Errorf("failed to read frame length: %w", err)
+		}
+		return size, nil
+	case PrefixFixed32:
+		var header [4]byte
+		if _, err := io.ReadFull(fr.r, header[:]); err != nil {
+			if err == io.EOF {
+				return 0, io.EOF
+			}
+			return 0, fmt.Errorf("failed to read frame length: %w", err)
+		}
+		return uint64(binary.BigEndian.Uint32(header[:])), nil
+	default:
+		return 0, fmt.Errorf("unsupported frame prefix %s", fr.prefix)
+	}
+}
@@ -0,0 +1,107 @@
+package framing
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestFrameRoundTrip(t *testing.T) {
+	payloads := [][]byte{[]byte("hello"), {}, bytes.Repeat([]byte{0xAB}, 300), []byte("last")}
+
+	for _, prefix := range []Prefix{PrefixVarint, PrefixFixed32} {
+		var buf bytes.Buffer
+		writer := NewWriter(&buf, prefix)
+		for _, payload := range payloads {
+			if err := writer.WriteFrame(payload); err != nil {
+				t.Fatalf("%s: failed to write frame: %v", prefix, err)
+			}
+		}
+
+		reader := NewReader(&buf, prefix)
+		for i, want := range payloads {
+			got, err := reader.ReadFrame()
+			if err != nil {
+				t.Fatalf("%s: failed to read frame %d: %v", prefix, i, err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s: frame %d mismatch: got %d bytes, want %d", prefix, i, len(got), len(want))
+			}
+		}
+		if _, err := reader.ReadFrame(); err != io.EOF {
+			t.Errorf("%s: expected io.EOF after last frame, got %v", prefix, err)
+		}
+	}
+
+	t.Log("✓ Frames round-trip with varint and fixed32 prefixes")
+}
+
+func TestFrameErrors(t *testing.T) {
+	for _, prefix := range []Prefix{PrefixVarint, PrefixFixed32} {
+		var buf bytes.Buffer
+		if err := NewWriter(&buf, prefix).WriteFrame([]byte("truncated")); err != nil {
+			t.Fatalf("%s: failed to write frame: %v", prefix, err)
+		}
+
+		// A stream cut inside a frame is not a clean end
+		cut := bytes.NewReader(buf.Bytes()[:buf.Len()-3])
+		if _, err := NewReader(cut, prefix).ReadFrame(); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("%s: expected io.ErrUnexpectedEOF, got %v", prefix, err)
+		}
+		header := bytes.NewReader(buf.Bytes()[:1])
+		if _, err := NewReader(header, prefix).ReadFrame(); err == nil || err == io.EOF {
+			t.Errorf("%s: expected error for a partial header, got %v", prefix, err)
+		}
+
+		if _, err := NewReader(bytes.NewReader(buf.Bytes()), prefix).WithMaxFrameSize(4).ReadFrame(); !errors.Is(err, ErrFrameTooLarge) {
+			t.Errorf("%s: expected ErrFrameTooLarge, got %v", prefix, err)
+		}
+	}
+
+	if _, err := ParsePrefix("fixed16"); err == nil {
+		t.Error("Expected error for unknown prefix")
+	}
+
+	t.Log("✓ Truncated and oversized frames are rejected")
+}
+
+func TestServeEcho(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	served := make(chan error, 1)
+	go func() {
+		served <- ServeEcho(ln, PrefixFixed32)
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	writer := NewWriter(conn, PrefixFixed32)
+	reader := NewReader(conn, PrefixFixed32)
+
+	for _, message := range []string{"one", "two", "three"} {
+		if err := writer.WriteFrame([]byte(message)); err != nil {
+			t.Fatalf("Failed to write frame: %v", err)
+		}
+		echo, err := reader.ReadFrame()
+		if err != nil {
+			t.Fatalf("Failed to read echo: %v", err)
+		}
+		if string(echo) != message {
+			t.Errorf("Expected echo %q, got %q", message, echo)
+		}
+	}
+
+	conn.Close()
+	ln.Close()
+	if err := <-served; err != nil {
+		t.Errorf("Expected clean shutdown, got %v", err)
+	}
+
+	t.Log("✓ Echo server returns every frame over TCP")
+}
@@ -0,0 +1,190 @@
+package graphql
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/protobuf"
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+	"go-transport-prac/pkg/transport/internal/convert"
+)
+
+// Encoding names accepted by the schema's Encoding enum
+const (
+	EncodingAvro     = "AVRO"
+	EncodingProtobuf = "PROTOBUF"
+)
+
+// codec turns entities into base64 Avro or Protobuf bytes and back using the SDL managers
+type codec struct {
+	avroManager  *avro.Manager
+	protoManager *protobuf.Manager
+}
+
+// newCodec creates a codec backed by new Avro and Protobuf managers
+func newCodec() (*codec, error) {
+	avroManager, err := avro.NewManager("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avro manager: %w", err)
+	}
+	return &codec{avroManager: avroManager, protoManager: protobuf.NewManager()}, nil
+}
+
+// encodeUser serializes a user and returns it base64 encoded
+func (c *codec) encodeUser(u *user.User, encoding string) (string, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch encoding {
+	case EncodingAvro:
+		data, err = c.avroManager.SerializeUserBinary(convert.UserFromProto(u))
+	case EncodingProtobuf:
+		data, err = c.protoManager.SerializeUser(u)
+	default:
+		return "", unsupportedEncoding(encoding)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to encode user: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// decodeUser reads a user from base64 encoded bytes
+func (c *codec) decodeUser(data, encoding string) (*user.User, error) {
+	raw, err := decodeBase64(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch encoding {
+	case EncodingAvro:
+		u, err := c.avroManager.DeserializeUserBinary(raw)
+		if err != nil {
+			return nil, invalidPayload("user", err)
+		}
+		return convert.UserToProto(u), nil
+	case EncodingProtobuf:
+		u, err := c.protoManager.DeserializeUser(raw)
+		if err != nil {
+			return nil, invalidPayload("user", err)
+		}
+		return u, nil
+	default:
+		return nil, unsupportedEncoding(encoding)
+	}
+}
+
+// encodeProduct serializes a product and returns it base64 encoded
+func (c *codec) encodeProduct(p *product.Product, encoding string) (string, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch encoding {
+	case EncodingAvro:
+		data, err = c.avroManager.SerializeProductBinary(convert.ProductFromProto(p))
+	case EncodingProtobuf:
+		data, err = c.protoManager.SerializeProduct(p)
+	default:
+		return "", unsupportedEncoding(encoding)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to encode product: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// decodeProduct reads a product from base64 encoded bytes
+func (c *codec) decodeProduct(data, encoding string) (*product.Product, error) {
+	raw, err := decodeBase64(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch encoding {
+	case EncodingAvro:
+		p, err := c.avroManager.DeserializeProductBinary(raw)
+		if err != nil {
+			return nil, invalidPayload("product", err)
+		}
+		return convert.ProductToProto(p), nil
+	case EncodingProtobuf:
+		p, err := c.protoManager.DeserializeProduct(raw)
+		if err != nil {
+			return nil, invalidPayload("product", err)
+		}
+		return p, nil
+	default:
+		return nil, unsupportedEncoding(encoding)
+	}
+}
+
+// encodeOrder serializes an order and returns it base64 encoded
+func (c *codec) encodeOrder(o *order.Order, encoding string) (string, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch encoding {
+	case EncodingAvro:
+		data, err = c.avroManager.SerializeStruct(c.avroManager.GetOrderSchema(), convert.OrderFromProto(o))
+	case EncodingProtobuf:
+		data, err = c.protoManager.SerializeOrder(o)
+	default:
+		return "", unsupportedEncoding(encoding)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to encode order: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// decodeOrder reads an order from base64 encoded bytes
+func (c *codec) decodeOrder(data, encoding string) (*order.Order, error) {
+	raw, err := decodeBase64(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch encoding {
+	case EncodingAvro:
+		var o avro.Order
+		if err := c.avroManager.DeserializeStruct(c.avroManager.GetOrderSchema(), raw, &o); err != nil {
+			return nil, invalidPayload("order", err)
+		}
+		return convert.OrderToProto(o), nil
+	case EncodingProtobuf:
+		o, err := c.protoManager.DeserializeOrder(raw)
+		if err != nil {
+			return nil, invalidPayload("order", err)
+		}
+		return o, nil
+	default:
+		return nil, unsupportedEncoding(encoding)
+	}
+}
+
+// decodeBase64 accepts standard base64 with or without padding
+func decodeBase64(data string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		raw, err = base64.RawStdEncoding.DecodeString(data)
+	}
+	if err != nil {
+		return nil, errors.BadRequestError(errors.CodeInvalidFormat, "data must be base64 encoded")
+	}
+	return raw, nil
+}
+
+func unsupportedEncoding(encoding string) error {
+	return errors.BadRequestError(errors.CodeInvalidValue, fmt.Sprintf("unsupported encoding %q", encoding))
+}
+
+func invalidPayload(entity string, err error) error {
+	return errors.Wrap(err, errors.ErrorTypeBadRequest, errors.CodeInvalidFormat, fmt.Sprintf("failed to decode %s", entity))
+}
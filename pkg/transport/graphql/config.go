@@ -0,0 +1,59 @@
+package graphql
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"go-transport-prac/internal/config"
+)
+
+// Config holds GraphQL server settings
+type Config struct {
+	// Addr is the host:port the server listens on
+	Addr string
+	// Path is the URL path queries are posted to
+	Path string
+
+	TLSEnabled bool
+	CertFile   string
+	KeyFile    string
+
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+
+	// MaxBodyBytes bounds the size of a request body
+	MaxBodyBytes int64
+	// MaxDepth bounds how deeply a query may nest selections
+	MaxDepth int
+	// ShutdownTimeout bounds how long Shutdown waits for in-flight requests
+	ShutdownTimeout time.Duration
+}
+
+// DefaultConfig returns a plaintext configuration on the default GraphQL port
+func DefaultConfig() Config {
+	return Config{
+		Addr:            "localhost:9090",
+		Path:            "/graphql",
+		ReadTimeout:     30 * time.Second,
+		WriteTimeout:    30 * time.Second,
+		IdleTimeout:     120 * time.Second,
+		MaxBodyBytes:    1024 * 1024,
+		MaxDepth:        10,
+		ShutdownTimeout: 10 * time.Second,
+	}
+}
+
+// NewConfig builds a GraphQL configuration from the application server configuration
+func NewConfig(cfg config.ServerConfig) Config {
+	gqlCfg := DefaultConfig()
+	gqlCfg.Addr = net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.GraphQLPort))
+	gqlCfg.TLSEnabled = cfg.TLSEnabled
+	gqlCfg.CertFile = cfg.CertFile
+	gqlCfg.KeyFile = cfg.KeyFile
+	gqlCfg.ReadTimeout = cfg.ReadTimeout
+	gqlCfg.WriteTimeout = cfg.WriteTimeout
+	gqlCfg.IdleTimeout = cfg.IdleTimeout
+	return gqlCfg
+}
@@ -0,0 +1,412 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+	"go-transport-prac/pkg/transport/grpc"
+)
+
+// Resolver resolves the Query and Mutation root fields against the store shared
+// with the gRPC services, so both transports apply the same validation
+type Resolver struct {
+	store *grpc.Store
+	codec *codec
+}
+
+// NewResolver creates a resolver backed by store
+func NewResolver(store *grpc.Store) (*Resolver, error) {
+	c, err := newCodec()
+	if err != nil {
+		return nil, err
+	}
+	return &Resolver{store: store, codec: c}, nil
+}
+
+// Queries
+
+func (r *Resolver) User(args struct{ ID graphqlgo.ID }) (*userResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	u, err := r.store.GetUser(id)
+	if err != nil {
+		return nil, resolverErr(err)
+	}
+	return &userResolver{root: r, u: u}, nil
+}
+
+func (r *Resolver) Users(args struct{ Status *string }) ([]*userResolver, error) {
+	status := user.UserStatus_USER_STATUS_UNSPECIFIED
+	if args.Status != nil {
+		status = user.UserStatus(user.UserStatus_value["USER_STATUS_"+*args.Status])
+	}
+
+	users := r.store.ListUsers(status)
+	resolvers := make([]*userResolver, len(users))
+	for i, u := range users {
+		resolvers[i] = &userResolver{root: r, u: u}
+	}
+	return resolvers, nil
+}
+
+func (r *Resolver) Product(args struct{ ID graphqlgo.ID }) (*productResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	p, err := r.store.GetProduct(id)
+	if err != nil {
+		return nil, resolverErr(err)
+	}
+	return &productResolver{root: r, p: p}, nil
+}
+
+func (r *Resolver) Products(args struct {
+	Query      *string
+	Categories *[]string
+}) ([]*productResolver, error) {
+	req := &product.SearchProductsRequest{}
+	if args.Query != nil {
+		req.Query = *args.Query
+	}
+	if args.Categories != nil {
+		req.Categories = *args.Categories
+	}
+
+	products := r.store.SearchProducts(req)
+	resolvers := make([]*productResolver, len(products))
+	for i, p := range products {
+		resolvers[i] = &productResolver{root: r, p: p}
+	}
+	return resolvers, nil
+}
+
+func (r *Resolver) Order(args struct{ ID graphqlgo.ID }) (*orderResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	o, err := r.store.GetOrder(id)
+	if err != nil {
+		return nil, resolverErr(err)
+	}
+	return &orderResolver{root: r, o: o}, nil
+}
+
+func (r *Resolver) OrdersByUser(args struct {
+	UserID graphqlgo.ID
+	Status *string
+}) ([]*orderResolver, error) {
+	userID, err := parseID(args.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return r.ordersByUser(userID, args.Status), nil
+}
+
+// ordersByUser resolves a user's orders, optionally filtered by a schema status name
+func (r *Resolver) ordersByUser(userID uint64, status *string) []*orderResolver {
+	filter := order.OrderStatus_ORDER_STATUS_UNSPECIFIED
+	if status != nil {
+		filter = orderStatus(*status)
+	}
+
+	orders := r.store.OrdersByUser(userID, filter)
+	resolvers := make([]*orderResolver, len(orders))
+	for i, o := range orders {
+		resolvers[i] = &orderResolver{root: r, o: o}
+	}
+	return resolvers
+}
+
+type decodeArgs struct {
+	Encoding string
+	Data     string
+}
+
+func (r *Resolver) DecodeUser(args decodeArgs) (*userResolver, error) {
+	u, err := r.codec.decodeUser(args.Data, args.Encoding)
+	if err != nil {
+		return nil, resolverErr(err)
+	}
+	return &userResolver{root: r, u: u}, nil
+}
+
+func (r *Resolver) DecodeProduct(args decodeArgs) (*productResolver, error) {
+	p, err := r.codec.decodeProduct(args.Data, args.Encoding)
+	if err != nil {
+		return nil, resolverErr(err)
+	}
+	return &productResolver{root: r, p: p}, nil
+}
+
+func (r *Resolver) DecodeOrder(args decodeArgs) (*orderResolver, error) {
+	o, err := r.codec.decodeOrder(args.Data, args.Encoding)
+	if err != nil {
+		return nil, resolverErr(err)
+	}
+	return &orderResolver{root: r, o: o}, nil
+}
+
+// Mutations
+
+type addressInput struct {
+	Street     string
+	City       string
+	State      string
+	PostalCode string
+	Country    string
+}
+
+type profileInput struct {
+	FirstName string
+	LastName  string
+	Phone     *string
+	Address   *addressInput
+	Interests *[]string
+}
+
+// toProto converts the input to a protobuf profile; a nil input stays nil
+func (in *profileInput) toProto() *user.Profile {
+	if in == nil {
+		return nil
+	}
+
+	profile := &user.Profile{FirstName: in.FirstName, LastName: in.LastName}
+	if in.Phone != nil {
+		profile.Phone = *in.Phone
+	}
+	if in.Interests != nil {
+		profile.Interests = *in.Interests
+	}
+	if a := in.Address; a != nil {
+		profile.Address = &user.Address{
+			Street:     a.Street,
+			City:       a.City,
+			State:      a.State,
+			PostalCode: a.PostalCode,
+			Country:    a.Country,
+		}
+	}
+	return profile
+}
+
+func (r *Resolver) CreateUser(args struct {
+	Input struct {
+		Email   string
+		Name    string
+		Profile *profileInput
+	}
+}) (*userResolver, error) {
+	u, err := r.store.CreateUser(&user.CreateUserRequest{
+		Email:   args.Input.Email,
+		Name:    args.Input.Name,
+		Profile: args.Input.Profile.toProto(),
+	})
+	if err != nil {
+		return nil, resolverErr(err)
+	}
+	return &userResolver{root: r, u: u}, nil
+}
+
+func (r *Resolver) UpdateUser(args struct {
+	Input struct {
+		ID      graphqlgo.ID
+		Name    *string
+		Profile *profileInput
+	}
+}) (*userResolver, error) {
+	id, err := parseID(args.Input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &user.UpdateUserRequest{Id: id, Profile: args.Input.Profile.toProto()}
+	if args.Input.Name != nil {
+		req.Name = *args.Input.Name
+	}
+
+	u, err := r.store.UpdateUser(req)
+	if err != nil {
+		return nil, resolverErr(err)
+	}
+	return &userResolver{root: r, u: u}, nil
+}
+
+func (r *Resolver) DeleteUser(args struct{ ID graphqlgo.ID }) (*userResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	u, err := r.store.DeleteUser(id)
+	if err != nil {
+		return nil, resolverErr(err)
+	}
+	return &userResolver{root: r, u: u}, nil
+}
+
+func (r *Resolver) CreateProduct(args struct {
+	Input struct {
+		Name        string
+		Description *string
+		Sku         string
+		Price       struct {
+			Currency    string
+			AmountCents int32
+		}
+		Quantity   *int32
+		Categories *[]string
+		Tags       *[]string
+	}
+}) (*productResolver, error) {
+	in := args.Input
+	req := &product.CreateProductRequest{
+		Name:  in.Name,
+		Sku:   in.Sku,
+		Price: &product.Price{Currency: in.Price.Currency, AmountCents: int64(in.Price.AmountCents)},
+	}
+	if in.Description != nil {
+		req.Description = *in.Description
+	}
+	if in.Quantity != nil {
+		req.Inventory = &product.Inventory{Quantity: *in.Quantity, Available: *in.Quantity, TrackInventory: true}
+	}
+	if in.Categories != nil {
+		req.Categories = *in.Categories
+	}
+	if in.Tags != nil {
+		req.Tags = *in.Tags
+	}
+
+	p, err := r.store.CreateProduct(req)
+	if err != nil {
+		return nil, resolverErr(err)
+	}
+	return &productResolver{root: r, p: p}, nil
+}
+
+func (r *Resolver) CreateOrder(args struct {
+	Input struct {
+		UserID graphqlgo.ID
+		Items  []struct {
+			ProductID graphqlgo.ID
+			Quantity  int32
+		}
+	}
+}) (*orderResolver, error) {
+	userID, err := parseID(args.Input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &order.CreateOrderRequest{UserId: userID}
+	for _, item := range args.Input.Items {
+		productID, err := parseID(item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		req.Items = append(req.Items, &order.OrderItem{ProductId: productID, Quantity: item.Quantity})
+	}
+
+	o, err := r.store.CreateOrder(req)
+	if err != nil {
+		return nil, resolverErr(err)
+	}
+	return &orderResolver{root: r, o: o}, nil
+}
+
+func (r *Resolver) UpdateOrderStatus(args struct {
+	ID             graphqlgo.ID
+	Status         string
+	TrackingNumber *string
+}) (*orderResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &order.UpdateOrderStatusRequest{Id: id, Status: orderStatus(args.Status)}
+	if args.TrackingNumber != nil {
+		req.TrackingNumber = *args.TrackingNumber
+	}
+
+	o, err := r.store.UpdateOrderStatus(req)
+	if err != nil {
+		return nil, resolverErr(err)
+	}
+	return &orderResolver{root: r, o: o}, nil
+}
+
+func (r *Resolver) CancelOrder(args struct {
+	ID     graphqlgo.ID
+	Reason *string
+}) (*orderResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &order.CancelOrderRequest{Id: id}
+	if args.Reason != nil {
+		req.Reason = *args.Reason
+	}
+
+	o, err := r.store.CancelOrder(req)
+	if err != nil {
+		return nil, resolverErr(err)
+	}
+	return &orderResolver{root: r, o: o}, nil
+}
+
+// parseID converts a GraphQL ID into a store ID
+func parseID(id graphqlgo.ID) (uint64, error) {
+	n, err := strconv.ParseUint(string(id), 10, 64)
+	if err != nil || n == 0 {
+		return 0, resolverErr(errors.BadRequestError(errors.CodeInvalidValue, fmt.Sprintf("invalid id %q", id)))
+	}
+	return n, nil
+}
+
+// formatID converts a store ID into a GraphQL ID
+func formatID(id uint64) graphqlgo.ID {
+	return graphqlgo.ID(strconv.FormatUint(id, 10))
+}
+
+// orderStatus maps a schema OrderStatus name onto the protobuf enum
+func orderStatus(name string) order.OrderStatus {
+	return order.OrderStatus(order.OrderStatus_value["ORDER_STATUS_"+name])
+}
+
+// appError exposes an AppError's code and type in the GraphQL error extensions
+type appError struct {
+	*errors.AppError
+}
+
+// Error returns the message alone, since the code is reported in the extensions
+func (e appError) Error() string {
+	return e.Message
+}
+
+// Extensions implements graphql-go's extension hook for resolver errors
+func (e appError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": e.Code,
+		"type": string(e.Type),
+	}
+}
+
+// resolverErr wraps AppErrors so their code reaches the client
+func resolverErr(err error) error {
+	if appErr, ok := errors.AsAppError(err); ok {
+		return appError{appErr}
+	}
+	return err
+}
@@ -0,0 +1,170 @@
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net"
+	nethttp "net/http"
+	"time"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/pkg/transport/grpc"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// Schema returns the GraphQL schema definition served by the transport
+func Schema() string {
+	return schemaSDL
+}
+
+// Server serves User, Product and Order queries and mutations over GraphQL
+type Server struct {
+	cfg    Config
+	logger *logger.Logger
+	store  *grpc.Store
+	schema *graphqlgo.Schema
+	mux    *nethttp.ServeMux
+	server *nethttp.Server
+}
+
+// request is the standard GraphQL-over-HTTP POST body
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// NewServer creates a GraphQL server; a nil store starts with an empty one
+func NewServer(cfg Config, store *grpc.Store, log *logger.Logger) (*Server, error) {
+	if log == nil {
+		log = logger.Global()
+	}
+	if store == nil {
+		store = grpc.NewStore(nil)
+	}
+
+	resolver, err := NewResolver(store)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []graphqlgo.SchemaOpt
+	if cfg.MaxDepth > 0 {
+		opts = append(opts, graphqlgo.MaxDepth(cfg.MaxDepth))
+	}
+	schema, err := graphqlgo.ParseSchema(schemaSDL, resolver, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse graphql schema: %w", err)
+	}
+
+	s := &Server{
+		cfg:    cfg,
+		logger: log.WithComponent("graphql"),
+		store:  store,
+		schema: schema,
+		mux:    nethttp.NewServeMux(),
+	}
+	s.mux.HandleFunc("POST "+cfg.Path, s.serveQuery)
+
+	s.server = &nethttp.Server{
+		Addr:         cfg.Addr,
+		Handler:      s.mux,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+	}
+
+	return s, nil
+}
+
+// Store returns the store backing the resolvers
+func (s *Server) Store() *grpc.Store {
+	return s.store
+}
+
+// Handler returns the server's root handler, e.g. for httptest
+func (s *Server) Handler() nethttp.Handler {
+	return s.mux
+}
+
+// ListenAndServe listens on the configured address and serves until stopped
+func (s *Server) ListenAndServe() error {
+	lis, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.cfg.Addr, err)
+	}
+	return s.Serve(lis)
+}
+
+// Serve serves GraphQL requests on lis until the server is shut down
+func (s *Server) Serve(lis net.Listener) error {
+	s.logger.Info("GraphQL server listening", zap.String("addr", lis.Addr().String()), zap.String("path", s.cfg.Path))
+
+	var err error
+	if s.cfg.TLSEnabled {
+		err = s.server.ServeTLS(lis, s.cfg.CertFile, s.cfg.KeyFile)
+	} else {
+		err = s.server.Serve(lis)
+	}
+	if err != nil && err != nethttp.ErrServerClosed {
+		return fmt.Errorf("GraphQL server failed: %w", err)
+	}
+	return nil
+}
+
+// Shutdown stops accepting new requests and waits for in-flight ones to finish
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.cfg.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.ShutdownTimeout)
+		defer cancel()
+	}
+
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.logger.Warn("GraphQL server forced to stop", zap.Error(err))
+		return err
+	}
+	s.logger.Info("GraphQL server stopped")
+	return nil
+}
+
+// serveQuery executes a single GraphQL operation. Resolver errors are reported
+// in the response body with status 200, as GraphQL clients expect
+func (s *Server) serveQuery(w nethttp.ResponseWriter, r *nethttp.Request) {
+	start := time.Now()
+
+	if s.cfg.MaxBodyBytes > 0 {
+		r.Body = nethttp.MaxBytesReader(w, r.Body, s.cfg.MaxBodyBytes)
+	}
+
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		nethttp.Error(w, "request body must be a JSON GraphQL request", nethttp.StatusBadRequest)
+		s.logger.LogHTTPRequest(r.Method, r.URL.Path, nethttp.StatusBadRequest, time.Since(start).String(), zap.Error(err))
+		return
+	}
+
+	resp := s.schema.Exec(r.Context(), req.Query, req.OperationName, req.Variables)
+	body, err := json.Marshal(resp)
+	if err != nil {
+		nethttp.Error(w, "failed to encode response", nethttp.StatusInternalServerError)
+		s.logger.LogHTTPRequest(r.Method, r.URL.Path, nethttp.StatusInternalServerError, time.Since(start).String(), zap.Error(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+
+	fields := []zap.Field{zap.String("operation", req.OperationName)}
+	if len(resp.Errors) > 0 {
+		fields = append(fields, zap.Int("errors", len(resp.Errors)))
+	}
+	s.logger.LogHTTPRequest(r.Method, r.URL.Path, nethttp.StatusOK, time.Since(start).String(), fields...)
+}
@@ -0,0 +1,249 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/transport/grpc"
+)
+
+// response mirrors a GraphQL response body
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// startTestServer serves the schema from an httptest server backed by a fake-clock store
+func startTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server, err := NewServer(DefaultConfig(), grpc.NewStore(testutil.NewDefaultFakeClock()), nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	ts := httptest.NewServer(server.Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// exec posts a query and decodes the data into out
+func exec(t *testing.T, ts *httptest.Server, query string, variables map[string]interface{}, out interface{}) response {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	resp, err := nethttp.Post(ts.URL+"/graphql", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+
+	var result response
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if out != nil && len(result.Errors) == 0 {
+		if err := json.Unmarshal(result.Data, out); err != nil {
+			t.Fatalf("Failed to decode data: %v", err)
+		}
+	}
+	return result
+}
+
+// seed creates a user, a product and an order for that user
+func seed(t *testing.T, ts *httptest.Server) (userID, productID, orderID string) {
+	t.Helper()
+
+	var created struct {
+		CreateUser struct{ ID string }
+	}
+	resp := exec(t, ts, `mutation {
+		createUser(input: {email: "ada@example.com", name: "Ada", profile: {firstName: "Ada", lastName: "Lovelace", interests: ["math"]}}) { id }
+	}`, nil, &created)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("createUser failed: %+v", resp.Errors)
+	}
+
+	var product struct {
+		CreateProduct struct{ ID string }
+	}
+	resp = exec(t, ts, `mutation {
+		createProduct(input: {name: "Widget", sku: "W-1", price: {currency: "USD", amountCents: 1999}, quantity: 10, categories: ["tools"]}) { id }
+	}`, nil, &product)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("createProduct failed: %+v", resp.Errors)
+	}
+
+	var ordered struct {
+		CreateOrder struct{ ID string }
+	}
+	resp = exec(t, ts, `mutation($user: ID!, $product: ID!) {
+		createOrder(input: {userId: $user, items: [{productId: $product, quantity: 2}]}) { id }
+	}`, map[string]interface{}{"user": created.CreateUser.ID, "product": product.CreateProduct.ID}, &ordered)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("createOrder failed: %+v", resp.Errors)
+	}
+
+	return created.CreateUser.ID, product.CreateProduct.ID, ordered.CreateOrder.ID
+}
+
+func TestQueriesAndMutations(t *testing.T) {
+	ts := startTestServer(t)
+	userID, _, orderID := seed(t, ts)
+
+	var data struct {
+		User struct {
+			Name    string
+			Status  string
+			Profile struct{ Interests []string }
+			Orders  []struct {
+				Status  string
+				Items   []struct{ ProductSku string }
+				Summary struct {
+					Total      struct{ AmountCents int }
+					TotalItems int
+				}
+			}
+		}
+	}
+	resp := exec(t, ts, `query($id: ID!) {
+		user(id: $id) {
+			name status profile { interests }
+			orders { status items { productSku } summary { total { amountCents } totalItems } }
+		}
+	}`, map[string]interface{}{"id": userID}, &data)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("user query failed: %+v", resp.Errors)
+	}
+	if data.User.Name != "Ada" || data.User.Status != "ACTIVE" || len(data.User.Profile.Interests) != 1 {
+		t.Errorf("Unexpected user: %+v", data.User)
+	}
+	if len(data.User.Orders) != 1 || data.User.Orders[0].Items[0].ProductSku != "W-1" || data.User.Orders[0].Summary.TotalItems != 2 {
+		t.Errorf("Unexpected orders: %+v", data.User.Orders)
+	}
+
+	var shipped struct {
+		UpdateOrderStatus struct {
+			Status         string
+			TrackingNumber *string
+			User           struct{ Email string }
+		}
+	}
+	resp = exec(t, ts, `mutation($id: ID!) {
+		updateOrderStatus(id: $id, status: SHIPPED, trackingNumber: "1Z999") { status trackingNumber user { email } }
+	}`, map[string]interface{}{"id": orderID}, &shipped)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("updateOrderStatus failed: %+v", resp.Errors)
+	}
+	if shipped.UpdateOrderStatus.Status != "SHIPPED" || shipped.UpdateOrderStatus.TrackingNumber == nil ||
+		*shipped.UpdateOrderStatus.TrackingNumber != "1Z999" || shipped.UpdateOrderStatus.User.Email != "ada@example.com" {
+		t.Errorf("Unexpected shipped order: %+v", shipped.UpdateOrderStatus)
+	}
+
+	var products struct {
+		Products []struct{ Name string }
+	}
+	exec(t, ts, `{ products(categories: ["tools"]) { name } }`, nil, &products)
+	if len(products.Products) != 1 || products.Products[0].Name != "Widget" {
+		t.Errorf("Unexpected products: %+v", products.Products)
+	}
+
+	t.Log("✓ Queries and mutations resolve against the shared store")
+}
+
+func TestEncodedRoundTrip(t *testing.T) {
+	ts := startTestServer(t)
+	userID, productID, orderID := seed(t, ts)
+
+	for _, encoding := range []string{EncodingAvro, EncodingProtobuf} {
+		var encoded struct {
+			User    struct{ Email, Encoded string }
+			Product struct{ Sku, Encoded string }
+			Order   struct{ OrderNumber, Encoded string }
+		}
+		resp := exec(t, ts, `query($user: ID!, $product: ID!, $order: ID!, $encoding: Encoding!) {
+			user(id: $user) { email encoded(encoding: $encoding) }
+			product(id: $product) { sku encoded(encoding: $encoding) }
+			order(id: $order) { orderNumber encoded(encoding: $encoding) }
+		}`, map[string]interface{}{"user": userID, "product": productID, "order": orderID, "encoding": encoding}, &encoded)
+		if len(resp.Errors) > 0 {
+			t.Fatalf("%s encode failed: %+v", encoding, resp.Errors)
+		}
+
+		var decoded struct {
+			DecodeUser    struct{ Email string }
+			DecodeProduct struct{ Sku string }
+			DecodeOrder   struct {
+				OrderNumber string
+				Items       []struct{ Quantity int }
+			}
+		}
+		resp = exec(t, ts, `query($encoding: Encoding!, $user: String!, $product: String!, $order: String!) {
+			decodeUser(encoding: $encoding, data: $user) { email }
+			decodeProduct(encoding: $encoding, data: $product) { sku }
+			decodeOrder(encoding: $encoding, data: $order) { orderNumber items { quantity } }
+		}`, map[string]interface{}{
+			"encoding": encoding,
+			"user":     encoded.User.Encoded,
+			"product":  encoded.Product.Encoded,
+			"order":    encoded.Order.Encoded,
+		}, &decoded)
+		if len(resp.Errors) > 0 {
+			t.Fatalf("%s decode failed: %+v", encoding, resp.Errors)
+		}
+
+		if decoded.DecodeUser.Email != encoded.User.Email ||
+			decoded.DecodeProduct.Sku != encoded.Product.Sku ||
+			decoded.DecodeOrder.OrderNumber != encoded.Order.OrderNumber ||
+			len(decoded.DecodeOrder.Items) != 1 || decoded.DecodeOrder.Items[0].Quantity != 2 {
+			t.Errorf("%s round trip mismatch: encoded %+v, decoded %+v", encoding, encoded, decoded)
+		}
+	}
+
+	t.Log("✓ Entities round-trip through base64 Avro and Protobuf")
+}
+
+func TestResolverErrors(t *testing.T) {
+	ts := startTestServer(t)
+
+	tests := []struct {
+		name  string
+		query string
+		code  string
+	}{
+		{"invalid id", `{ user(id: "abc") { id } }`, "INVALID_VALUE"},
+		{"missing user", `{ user(id: "42") { id } }`, "USER_NOT_FOUND"},
+		{"bad base64", `{ decodeUser(encoding: AVRO, data: "!!!") { id } }`, "INVALID_FORMAT"},
+		{"bad payload", `{ decodeOrder(encoding: PROTOBUF, data: "/////w==") { id } }`, "INVALID_FORMAT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := exec(t, ts, tt.query, nil, nil)
+			if len(resp.Errors) != 1 {
+				t.Fatalf("Expected one error, got %+v", resp.Errors)
+			}
+			if code := resp.Errors[0].Extensions["code"]; code != tt.code {
+				t.Errorf("Expected code %s, got %v (%s)", tt.code, code, resp.Errors[0].Message)
+			}
+		})
+	}
+
+	// Queries nesting deeper than MaxDepth are rejected before resolving
+	resp := exec(t, ts, `{ users { orders { user { orders { user { orders { user { orders { user { orders { user { id } } } } } } } } } } } }`, nil, nil)
+	if len(resp.Errors) == 0 {
+		t.Error("Expected depth limit error")
+	}
+
+	t.Log("✓ Resolver errors carry AppError codes in their extensions")
+}
@@ -0,0 +1,226 @@
+package graphql
+
+import (
+	"strings"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+// encodedArgs selects the wire format for the encoded field
+type encodedArgs struct {
+	Encoding string
+}
+
+type userResolver struct {
+	root *Resolver
+	u    *user.User
+}
+
+func (r *userResolver) ID() graphqlgo.ID           { return formatID(r.u.GetId()) }
+func (r *userResolver) Email() string              { return r.u.GetEmail() }
+func (r *userResolver) Name() string               { return r.u.GetName() }
+func (r *userResolver) CreatedAt() *graphqlgo.Time { return toTime(r.u.GetCreatedAt()) }
+func (r *userResolver) UpdatedAt() *graphqlgo.Time { return toTime(r.u.GetUpdatedAt()) }
+
+func (r *userResolver) Status() string {
+	return strings.TrimPrefix(r.u.GetStatus().String(), "USER_STATUS_")
+}
+
+func (r *userResolver) Profile() *profileResolver {
+	if r.u.GetProfile() == nil {
+		return nil
+	}
+	return &profileResolver{r.u.GetProfile()}
+}
+
+func (r *userResolver) Orders(args struct{ Status *string }) []*orderResolver {
+	return r.root.ordersByUser(r.u.GetId(), args.Status)
+}
+
+func (r *userResolver) Encoded(args encodedArgs) (string, error) {
+	data, err := r.root.codec.encodeUser(r.u, args.Encoding)
+	return data, resolverErr(err)
+}
+
+type profileResolver struct {
+	p *user.Profile
+}
+
+func (r *profileResolver) FirstName() string   { return r.p.GetFirstName() }
+func (r *profileResolver) LastName() string    { return r.p.GetLastName() }
+func (r *profileResolver) Interests() []string { return nonNil(r.p.GetInterests()) }
+
+func (r *profileResolver) Phone() *string {
+	if r.p.GetPhone() == "" {
+		return nil
+	}
+	phone := r.p.GetPhone()
+	return &phone
+}
+
+func (r *profileResolver) Address() *addressResolver {
+	if r.p.GetAddress() == nil {
+		return nil
+	}
+	return &addressResolver{r.p.GetAddress()}
+}
+
+type addressResolver struct {
+	a *user.Address
+}
+
+func (r *addressResolver) Street() string     { return r.a.GetStreet() }
+func (r *addressResolver) City() string       { return r.a.GetCity() }
+func (r *addressResolver) State() string      { return r.a.GetState() }
+func (r *addressResolver) PostalCode() string { return r.a.GetPostalCode() }
+func (r *addressResolver) Country() string    { return r.a.GetCountry() }
+
+type priceResolver struct {
+	p *product.Price
+}
+
+func (r *priceResolver) Currency() string   { return r.p.GetCurrency() }
+func (r *priceResolver) AmountCents() int32 { return int32(r.p.GetAmountCents()) }
+
+// newPriceResolver returns nil for a missing price so the field resolves to null
+func newPriceResolver(p *product.Price) *priceResolver {
+	if p == nil {
+		return nil
+	}
+	return &priceResolver{p}
+}
+
+type inventoryResolver struct {
+	i *product.Inventory
+}
+
+func (r *inventoryResolver) Quantity() int32  { return r.i.GetQuantity() }
+func (r *inventoryResolver) Reserved() int32  { return r.i.GetReserved() }
+func (r *inventoryResolver) Available() int32 { return r.i.GetAvailable() }
+
+type productResolver struct {
+	root *Resolver
+	p    *product.Product
+}
+
+func (r *productResolver) ID() graphqlgo.ID           { return formatID(r.p.GetId()) }
+func (r *productResolver) Name() string               { return r.p.GetName() }
+func (r *productResolver) Description() string        { return r.p.GetDescription() }
+func (r *productResolver) Sku() string                { return r.p.GetSku() }
+func (r *productResolver) Price() *priceResolver      { return newPriceResolver(r.p.GetPrice()) }
+func (r *productResolver) Categories() []string       { return nonNil(r.p.GetCategories()) }
+func (r *productResolver) Tags() []string             { return nonNil(r.p.GetTags()) }
+func (r *productResolver) CreatedAt() *graphqlgo.Time { return toTime(r.p.GetCreatedAt()) }
+func (r *productResolver) UpdatedAt() *graphqlgo.Time { return toTime(r.p.GetUpdatedAt()) }
+
+func (r *productResolver) Status() string {
+	return strings.TrimPrefix(r.p.GetStatus().String(), "PRODUCT_STATUS_")
+}
+
+func (r *productResolver) Inventory() *inventoryResolver {
+	if r.p.GetInventory() == nil {
+		return nil
+	}
+	return &inventoryResolver{r.p.GetInventory()}
+}
+
+func (r *productResolver) Encoded(args encodedArgs) (string, error) {
+	data, err := r.root.codec.encodeProduct(r.p, args.Encoding)
+	return data, resolverErr(err)
+}
+
+type orderItemResolver struct {
+	i *order.OrderItem
+}
+
+func (r *orderItemResolver) ProductID() graphqlgo.ID   { return formatID(r.i.GetProductId()) }
+func (r *orderItemResolver) ProductName() string       { return r.i.GetProductName() }
+func (r *orderItemResolver) ProductSku() string        { return r.i.GetProductSku() }
+func (r *orderItemResolver) Quantity() int32           { return r.i.GetQuantity() }
+func (r *orderItemResolver) UnitPrice() *priceResolver { return newPriceResolver(r.i.GetUnitPrice()) }
+func (r *orderItemResolver) TotalPrice() *priceResolver {
+	return newPriceResolver(r.i.GetTotalPrice())
+}
+
+type orderSummaryResolver struct {
+	s *order.OrderSummary
+}
+
+func (r *orderSummaryResolver) Subtotal() *priceResolver { return newPriceResolver(r.s.GetSubtotal()) }
+func (r *orderSummaryResolver) Total() *priceResolver    { return newPriceResolver(r.s.GetTotal()) }
+func (r *orderSummaryResolver) TotalItems() int32        { return r.s.GetTotalItems() }
+
+type orderResolver struct {
+	root *Resolver
+	o    *order.Order
+}
+
+func (r *orderResolver) ID() graphqlgo.ID             { return formatID(r.o.GetId()) }
+func (r *orderResolver) UserID() graphqlgo.ID         { return formatID(r.o.GetUserId()) }
+func (r *orderResolver) OrderNumber() string          { return r.o.GetOrderNumber() }
+func (r *orderResolver) CreatedAt() *graphqlgo.Time   { return toTime(r.o.GetCreatedAt()) }
+func (r *orderResolver) UpdatedAt() *graphqlgo.Time   { return toTime(r.o.GetUpdatedAt()) }
+func (r *orderResolver) ShippedAt() *graphqlgo.Time   { return toTime(r.o.GetShippedAt()) }
+func (r *orderResolver) DeliveredAt() *graphqlgo.Time { return toTime(r.o.GetDeliveredAt()) }
+
+func (r *orderResolver) Status() string {
+	return strings.TrimPrefix(r.o.GetStatus().String(), "ORDER_STATUS_")
+}
+
+// User resolves the order's owner, or null if the user no longer exists
+func (r *orderResolver) User() *userResolver {
+	u, err := r.root.store.GetUser(r.o.GetUserId())
+	if err != nil {
+		return nil
+	}
+	return &userResolver{root: r.root, u: u}
+}
+
+func (r *orderResolver) Items() []*orderItemResolver {
+	items := make([]*orderItemResolver, len(r.o.GetItems()))
+	for i, item := range r.o.GetItems() {
+		items[i] = &orderItemResolver{item}
+	}
+	return items
+}
+
+func (r *orderResolver) Summary() *orderSummaryResolver {
+	if r.o.GetSummary() == nil {
+		return nil
+	}
+	return &orderSummaryResolver{r.o.GetSummary()}
+}
+
+func (r *orderResolver) TrackingNumber() *string {
+	tracking := r.o.GetShipping().GetTrackingNumber()
+	if tracking == "" {
+		return nil
+	}
+	return &tracking
+}
+
+func (r *orderResolver) Encoded(args encodedArgs) (string, error) {
+	data, err := r.root.codec.encodeOrder(r.o, args.Encoding)
+	return data, resolverErr(err)
+}
+
+// toTime converts a protobuf timestamp, treating unset timestamps as null
+func toTime(ts *timestamppb.Timestamp) *graphqlgo.Time {
+	if ts == nil {
+		return nil
+	}
+	return &graphqlgo.Time{Time: ts.AsTime()}
+}
+
+// nonNil keeps non-null list fields from resolving to null
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
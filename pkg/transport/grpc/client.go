@@ -0,0 +1,225 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	grpcgo "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+// Client is a typed client for the User, Product and Order services
+type Client struct {
+	conn *grpcgo.ClientConn
+
+	Users    user.UserServiceClient
+	Products product.ProductServiceClient
+	Orders   order.OrderServiceClient
+	Health   healthpb.HealthClient
+}
+
+// NewClient creates a client for target. Connections are plaintext unless
+// opts supply transport credentials.
+func NewClient(target string, opts ...grpcgo.DialOption) (*Client, error) {
+	dialOpts := append([]grpcgo.DialOption{
+		grpcgo.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)
+
+	conn, err := grpcgo.NewClient(target, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client for %s: %w", target, err)
+	}
+
+	return &Client{
+		conn:     conn,
+		Users:    user.NewUserServiceClient(conn),
+		Products: product.NewProductServiceClient(conn),
+		Orders:   order.NewOrderServiceClient(conn),
+		Health:   healthpb.NewHealthClient(conn),
+	}, nil
+}
+
+// Close closes the underlying connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// CheckHealth returns whether service is serving; the empty name asks about
+// the server as a whole
+func (c *Client) CheckHealth(ctx context.Context, service string) (bool, error) {
+	resp, err := c.Health.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		return false, err
+	}
+	return resp.GetStatus() == healthpb.HealthCheckResponse_SERVING, nil
+}
+
+// CreateUser creates a user and returns it
+func (c *Client) CreateUser(ctx context.Context, req *user.CreateUserRequest) (*user.User, error) {
+	resp, err := c.Users.CreateUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetUser(), nil
+}
+
+// GetUser returns a user by ID
+func (c *Client) GetUser(ctx context.Context, id uint64) (*user.User, error) {
+	resp, err := c.Users.GetUser(ctx, &user.GetUserRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetUser(), nil
+}
+
+// UpdateUser updates a user and returns its new state
+func (c *Client) UpdateUser(ctx context.Context, req *user.UpdateUserRequest) (*user.User, error) {
+	resp, err := c.Users.UpdateUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetUser(), nil
+}
+
+// DeleteUser deletes a user by ID
+func (c *Client) DeleteUser(ctx context.Context, id uint64) error {
+	_, err := c.Users.DeleteUser(ctx, &user.DeleteUserRequest{Id: id})
+	return err
+}
+
+// ListUsers returns one page of users
+func (c *Client) ListUsers(ctx context.Context, req *user.ListUsersRequest) (*user.UsersResponse, error) {
+	return c.Users.ListUsers(ctx, req)
+}
+
+// StreamUsers calls fn for every streamed user, stopping at the first error fn returns
+func (c *Client) StreamUsers(ctx context.Context, req *user.ListUsersRequest, fn func(*user.User) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // Abandons the stream if fn stops early
+
+	stream, err := c.Users.StreamUsers(ctx, req)
+	if err != nil {
+		return err
+	}
+	return recvAll[user.User](stream, fn)
+}
+
+// CreateProduct creates a product and returns it
+func (c *Client) CreateProduct(ctx context.Context, req *product.CreateProductRequest) (*product.Product, error) {
+	resp, err := c.Products.CreateProduct(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetProduct(), nil
+}
+
+// GetProduct returns a product by ID
+func (c *Client) GetProduct(ctx context.Context, id uint64) (*product.Product, error) {
+	resp, err := c.Products.GetProduct(ctx, &product.GetProductRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetProduct(), nil
+}
+
+// UpdateProduct updates a product and returns its new state
+func (c *Client) UpdateProduct(ctx context.Context, req *product.UpdateProductRequest) (*product.Product, error) {
+	resp, err := c.Products.UpdateProduct(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetProduct(), nil
+}
+
+// SearchProducts returns one page of matching products
+func (c *Client) SearchProducts(ctx context.Context, req *product.SearchProductsRequest) (*product.ProductsResponse, error) {
+	return c.Products.SearchProducts(ctx, req)
+}
+
+// StreamProducts calls fn for every streamed product, stopping at the first error fn returns
+func (c *Client) StreamProducts(ctx context.Context, req *product.SearchProductsRequest, fn func(*product.Product) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.Products.StreamProducts(ctx, req)
+	if err != nil {
+		return err
+	}
+	return recvAll[product.Product](stream, fn)
+}
+
+// CreateOrder creates an order and returns it
+func (c *Client) CreateOrder(ctx context.Context, req *order.CreateOrderRequest) (*order.Order, error) {
+	resp, err := c.Orders.CreateOrder(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetOrder(), nil
+}
+
+// GetOrder returns an order by ID
+func (c *Client) GetOrder(ctx context.Context, id uint64) (*order.Order, error) {
+	resp, err := c.Orders.GetOrder(ctx, &order.GetOrderRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetOrder(), nil
+}
+
+// UpdateOrderStatus moves an order to a new status and returns its new state
+func (c *Client) UpdateOrderStatus(ctx context.Context, req *order.UpdateOrderStatusRequest) (*order.Order, error) {
+	resp, err := c.Orders.UpdateOrderStatus(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetOrder(), nil
+}
+
+// CancelOrder cancels an order and returns its new state
+func (c *Client) CancelOrder(ctx context.Context, id uint64, reason string) (*order.Order, error) {
+	resp, err := c.Orders.CancelOrder(ctx, &order.CancelOrderRequest{Id: id, Reason: reason})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetOrder(), nil
+}
+
+// GetOrdersByUser returns one page of a user's orders
+func (c *Client) GetOrdersByUser(ctx context.Context, req *order.GetOrdersByUserRequest) (*order.OrdersResponse, error) {
+	return c.Orders.GetOrdersByUser(ctx, req)
+}
+
+// StreamOrdersByUser calls fn for every streamed order, stopping at the first error fn returns
+func (c *Client) StreamOrdersByUser(ctx context.Context, req *order.GetOrdersByUserRequest, fn func(*order.Order) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.Orders.StreamOrdersByUser(ctx, req)
+	if err != nil {
+		return err
+	}
+	return recvAll[order.Order](stream, fn)
+}
+
+// recvAll drains a server stream into fn until io.EOF
+func recvAll[T any](stream grpcgo.ServerStreamingClient[T], fn func(*T) error) error {
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+}
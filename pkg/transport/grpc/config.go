@@ -0,0 +1,56 @@
+package grpc
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"go-transport-prac/internal/config"
+)
+
+// Config holds gRPC server settings
+type Config struct {
+	// Addr is the host:port the server listens on
+	Addr string
+
+	TLSEnabled bool
+	CertFile   string
+	KeyFile    string
+
+	// MaxRecvMsgSize bounds the size of a single inbound message in bytes
+	MaxRecvMsgSize int
+	// ShutdownTimeout bounds how long Shutdown waits for in-flight RPCs
+	ShutdownTimeout time.Duration
+
+	// HealthEnabled serves grpc.health.v1.Health for load balancers and probes
+	HealthEnabled bool
+	// HealthInterval is how often a registry passed to WithHealth is checked
+	HealthInterval time.Duration
+	// ReflectionEnabled serves server reflection, so tools like grpcurl can
+	// list and call the services without their proto files
+	ReflectionEnabled bool
+}
+
+// DefaultConfig returns a plaintext configuration on the default gRPC port,
+// with health checking on and reflection off
+func DefaultConfig() Config {
+	return Config{
+		Addr:            "localhost:8081",
+		MaxRecvMsgSize:  4 * 1024 * 1024,
+		ShutdownTimeout: 10 * time.Second,
+		HealthEnabled:   true,
+		HealthInterval:  5 * time.Second,
+	}
+}
+
+// NewConfig builds a gRPC configuration from the application server configuration
+func NewConfig(cfg config.ServerConfig) Config {
+	grpcCfg := DefaultConfig()
+	grpcCfg.Addr = net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.GRPCPort))
+	grpcCfg.TLSEnabled = cfg.TLSEnabled
+	grpcCfg.CertFile = cfg.CertFile
+	grpcCfg.KeyFile = cfg.KeyFile
+	grpcCfg.HealthEnabled = cfg.GRPCHealthEnabled
+	grpcCfg.ReflectionEnabled = cfg.GRPCReflectionEnabled
+	return grpcCfg
+}
@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"go-transport-prac/internal/health"
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+// ServiceNames are the fully qualified names of the services the server
+// registers, as reported to health checks and reflection
+var ServiceNames = []string{
+	user.UserService_ServiceDesc.ServiceName,
+	product.ProductService_ServiceDesc.ServiceName,
+	order.OrderService_ServiceDesc.ServiceName,
+}
+
+// registerIntrospection registers the health and reflection services enabled
+// in the config. Every service starts out serving
+func (s *Server) registerIntrospection() {
+	if s.cfg.HealthEnabled {
+		s.health = grpchealth.NewServer()
+		healthpb.RegisterHealthServer(s.server, s.health)
+		for _, name := range ServiceNames {
+			s.health.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+		}
+	}
+	if s.cfg.ReflectionEnabled {
+		reflection.Register(s.server)
+	}
+}
+
+// SetServingStatus reports service as serving or not to health checks; the
+// empty name is the server as a whole. It does nothing when health checking
+// is disabled
+func (s *Server) SetServingStatus(service string, serving bool) {
+	if s.health == nil {
+		return
+	}
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	s.health.SetServingStatus(service, status)
+}
+
+// setAllServing sets the status of the server and every service
+func (s *Server) setAllServing(serving bool) {
+	s.SetServingStatus("", serving)
+	for _, name := range ServiceNames {
+		s.SetServingStatus(name, serving)
+	}
+}
+
+// WithHealth reports the readiness of registry as the status of the server
+// and every service, checked every HealthInterval while serving, so a failing
+// dependency takes the server out of load balancing
+func (s *Server) WithHealth(registry *health.Registry) *Server {
+	s.registry = registry
+	return s
+}
+
+// watchHealth checks the registry until ctx is done, logging every change
+func (s *Server) watchHealth(ctx context.Context) {
+	interval := s.cfg.HealthInterval
+	if interval <= 0 {
+		interval = DefaultConfig().HealthInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	serving := true
+	for {
+		status := s.registry.Check(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if healthy := status.Status == health.StatusHealthy; healthy != serving {
+			serving = healthy
+			s.setAllServing(serving)
+			s.logger.Info("gRPC serving status changed", zap.Bool("serving", serving), zap.Any("checks", status.Checks))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	grpcgo "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"go-transport-prac/internal/health"
+	"go-transport-prac/internal/testutil"
+)
+
+// serveConfig serves all services with cfg over an in-memory listener
+func serveConfig(t *testing.T, cfg Config, registry *health.Registry) (*Server, *Client) {
+	t.Helper()
+
+	server, err := NewServer(cfg, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	if registry != nil {
+		server.WithHealth(registry)
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	go server.Serve(lis)
+	t.Cleanup(func() { server.Shutdown(context.Background()) })
+
+	client, err := NewClient("passthrough:///bufnet",
+		grpcgo.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return server, client
+}
+
+func TestHealth(t *testing.T) {
+	ctx := testutil.TimeoutContext(t, 5*time.Second)
+	var failing atomic.Bool
+	registry := health.NewRegistry("test").Register(health.Func("db", func(context.Context) error {
+		if failing.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	}))
+	cfg := DefaultConfig()
+	cfg.HealthInterval = 10 * time.Millisecond
+	server, client := serveConfig(t, cfg, registry)
+
+	for _, service := range append([]string{""}, ServiceNames...) {
+		if serving, err := client.CheckHealth(ctx, service); err != nil || !serving {
+			t.Errorf("Expected %q to be serving, got %v (%v)", service, serving, err)
+		}
+	}
+	if _, err := client.CheckHealth(ctx, "unknown.Service"); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for an unknown service, got %v", err)
+	}
+
+	// A failing dependency takes every service out of rotation until it recovers
+	failing.Store(true)
+	waitServing(t, ctx, client, "", false)
+	if serving, _ := client.CheckHealth(ctx, ServiceNames[2]); serving {
+		t.Error("Expected every service to stop serving with the server")
+	}
+	failing.Store(false)
+	waitServing(t, ctx, client, ServiceNames[0], true)
+
+	server.SetServingStatus(ServiceNames[1], false)
+	if serving, _ := client.CheckHealth(ctx, ServiceNames[1]); serving {
+		t.Error("Expected a service set to not serving to report it")
+	}
+
+	t.Log("✓ Health checks report the server, its services and its dependencies")
+}
+
+func waitServing(t *testing.T, ctx context.Context, client *Client, service string, want bool) {
+	t.Helper()
+	for {
+		serving, err := client.CheckHealth(ctx, service)
+		if err == nil && serving == want {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("Expected %s serving=%v, last got %v (%v)", service, want, serving, err)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func TestHealthShutdown(t *testing.T) {
+	ctx := testutil.TimeoutContext(t, 5*time.Second)
+	server, client := serveConfig(t, DefaultConfig(), nil)
+
+	server.health.Shutdown()
+	if serving, err := client.CheckHealth(ctx, ""); err != nil || serving {
+		t.Errorf("Expected a shutting down server to report not serving, got %v (%v)", serving, err)
+	}
+
+	t.Log("✓ Shutdown fails health checks before draining")
+}
+
+func TestReflection(t *testing.T) {
+	ctx := testutil.TimeoutContext(t, 5*time.Second)
+	cfg := DefaultConfig()
+	cfg.ReflectionEnabled = true
+	_, client := serveConfig(t, cfg, nil)
+
+	stream, err := reflectionpb.NewServerReflectionClient(client.conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		t.Fatalf("Failed to open reflection stream: %v", err)
+	}
+	if err := stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}); err != nil {
+		t.Fatalf("Failed to send reflection request: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Failed to list services: %v", err)
+	}
+	var names []string
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		names = append(names, service.GetName())
+	}
+	for _, expected := range append([]string{"grpc.health.v1.Health"}, ServiceNames...) {
+		if !slices.Contains(names, expected) {
+			t.Errorf("Expected %s to be listed, got %v", expected, names)
+		}
+	}
+
+	t.Log("✓ Reflection lists the services for grpcurl")
+}
+
+func TestIntrospectionDisabled(t *testing.T) {
+	ctx := testutil.TimeoutContext(t, 5*time.Second)
+	cfg := DefaultConfig()
+	cfg.HealthEnabled = false
+	server, client := serveConfig(t, cfg, nil)
+	server.SetServingStatus("", false)
+
+	if _, err := client.CheckHealth(ctx, ""); status.Code(err) != codes.Unimplemented {
+		t.Errorf("Expected health checks to be unimplemented, got %v", err)
+	}
+	stream, err := reflectionpb.NewServerReflectionClient(client.conn).ServerReflectionInfo(ctx)
+	if err == nil {
+		stream.Send(&reflectionpb.ServerReflectionRequest{MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{}})
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("Expected reflection to be off by default, got %v", err)
+	}
+
+	t.Log("✓ Health and reflection follow the config")
+}
@@ -0,0 +1,144 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	grpcgo "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"go-transport-prac/internal/errors"
+)
+
+// AuthorizationKey is the metadata key carrying "Bearer <token>"
+const AuthorizationKey = "authorization"
+
+// Application error codes of rejected calls
+const (
+	CodeMissingToken = "MISSING_TOKEN"
+	CodeInvalidToken = "INVALID_TOKEN"
+)
+
+// Authenticator checks the token of a call and returns the subject it
+// belongs to. A rejected token returns an error, mapped to its gRPC status
+// with Status; plain errors count as Unauthenticated
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (subject string, err error)
+}
+
+// AuthenticatorFunc adapts a function to Authenticator
+type AuthenticatorFunc func(ctx context.Context, token string) (string, error)
+
+// Authenticate implements Authenticator
+func (f AuthenticatorFunc) Authenticate(ctx context.Context, token string) (string, error) {
+	return f(ctx, token)
+}
+
+// StaticTokens accepts a fixed set of tokens, mapped to their subjects.
+// Tokens are compared in constant time
+type StaticTokens map[string]string
+
+// Authenticate implements Authenticator
+func (t StaticTokens) Authenticate(_ context.Context, token string) (string, error) {
+	for known, subject := range t {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(known)) == 1 {
+			return subject, nil
+		}
+	}
+	return "", errors.UnauthorizedError(CodeInvalidToken, "invalid token")
+}
+
+// PublicMethods are the calls health checks and reflection make, which
+// load balancers and tools send without credentials
+var PublicMethods = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.v1.ServerReflection/",
+	"/grpc.reflection.v1alpha.ServerReflection/",
+}
+
+// UnaryAuth rejects unary calls without a token auth accepts, except for
+// public methods: full method names, or prefixes ending in "/" for whole
+// services. The subject of an accepted token is available to handlers
+// through SubjectFrom
+func UnaryAuth(auth Authenticator, public ...string) grpcgo.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpcgo.UnaryServerInfo, handler grpcgo.UnaryHandler) (interface{}, error) {
+		if isPublic(info.FullMethod, public) {
+			return handler(ctx, req)
+		}
+		ctx, err := authenticate(ctx, auth)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuth is UnaryAuth for streaming calls
+func StreamAuth(auth Authenticator, public ...string) grpcgo.StreamServerInterceptor {
+	return func(srv interface{}, ss grpcgo.ServerStream, info *grpcgo.StreamServerInfo, handler grpcgo.StreamHandler) error {
+		if isPublic(info.FullMethod, public) {
+			return handler(srv, ss)
+		}
+		ctx, err := authenticate(ss.Context(), auth)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func isPublic(method string, public []string) bool {
+	for _, p := range public {
+		if method == p || (strings.HasSuffix(p, "/") && strings.HasPrefix(method, p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticate returns ctx carrying the subject of the call's token
+func authenticate(ctx context.Context, auth Authenticator) (context.Context, error) {
+	token := bearerToken(ctx)
+	if token == "" {
+		return nil, Status(errors.UnauthorizedError(CodeMissingToken, "missing bearer token"))
+	}
+	subject, err := auth.Authenticate(ctx, token)
+	if err != nil {
+		if !errors.IsAppError(err) {
+			err = errors.Wrap(err, errors.ErrorTypeUnauthorized, CodeInvalidToken, "invalid token")
+		}
+		return nil, Status(err)
+	}
+	return context.WithValue(ctx, subjectKey{}, subject), nil
+}
+
+// bearerToken returns the token of the call's authorization metadata
+func bearerToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get(AuthorizationKey) {
+		scheme, token, ok := strings.Cut(value, " ")
+		if ok && strings.EqualFold(scheme, "bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+type subjectKey struct{}
+
+// SubjectFrom returns the subject an auth interceptor accepted the call for
+func SubjectFrom(ctx context.Context) (string, bool) {
+	subject, ok := ctx.Value(subjectKey{}).(string)
+	return subject, ok
+}
+
+// contextStream replaces the context of a server stream
+type contextStream struct {
+	grpcgo.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,140 @@
+// Package interceptors provides gRPC server interceptors for logging, panic
+// recovery, Prometheus metrics and token authentication, each as a unary and
+// a stream variant, and a Chain to compose them into server options.
+package interceptors
+
+import (
+	"context"
+	stderrors "errors"
+
+	grpcgo "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/internal/types"
+)
+
+// Chain collects interceptors in the order they wrap a call: the first one
+// added sees the call first and its result last
+type Chain struct {
+	unary  []grpcgo.UnaryServerInterceptor
+	stream []grpcgo.StreamServerInterceptor
+}
+
+// NewChain returns an empty chain
+func NewChain() *Chain {
+	return &Chain{}
+}
+
+// Use adds a unary and a stream interceptor; either may be nil
+func (c *Chain) Use(unary grpcgo.UnaryServerInterceptor, stream grpcgo.StreamServerInterceptor) *Chain {
+	if unary != nil {
+		c.unary = append(c.unary, unary)
+	}
+	if stream != nil {
+		c.stream = append(c.stream, stream)
+	}
+	return c
+}
+
+// WithLogging logs every call, see UnaryLogging
+func (c *Chain) WithLogging(log *logger.Logger) *Chain {
+	return c.Use(UnaryLogging(log), StreamLogging(log))
+}
+
+// WithRecovery turns panics into Internal errors, see UnaryRecovery
+func (c *Chain) WithRecovery(log *logger.Logger) *Chain {
+	return c.Use(UnaryRecovery(log), StreamRecovery(log))
+}
+
+// WithMetrics counts and times every call, see UnaryMetrics
+func (c *Chain) WithMetrics(collector types.MetricsCollector) *Chain {
+	return c.Use(UnaryMetrics(collector), StreamMetrics(collector))
+}
+
+// WithAuth rejects calls without a valid token, see UnaryAuth
+func (c *Chain) WithAuth(auth Authenticator, public ...string) *Chain {
+	return c.Use(UnaryAuth(auth, public...), StreamAuth(auth, public...))
+}
+
+// Default returns the chain servers use: logging outermost so it records the
+// final status of every call, then recovery, so a panic is logged and counted
+// as Internal, then metrics. A nil collector leaves metrics out
+func Default(log *logger.Logger, collector types.MetricsCollector) *Chain {
+	c := NewChain().WithLogging(log).WithRecovery(log)
+	if collector != nil {
+		c.WithMetrics(collector)
+	}
+	return c
+}
+
+// Unary returns the unary interceptors in order
+func (c *Chain) Unary() []grpcgo.UnaryServerInterceptor {
+	return c.unary
+}
+
+// Stream returns the stream interceptors in order
+func (c *Chain) Stream() []grpcgo.StreamServerInterceptor {
+	return c.stream
+}
+
+// ServerOptions returns the options installing the chain on a server
+func (c *Chain) ServerOptions() []grpcgo.ServerOption {
+	return []grpcgo.ServerOption{
+		grpcgo.ChainUnaryInterceptor(c.unary...),
+		grpcgo.ChainStreamInterceptor(c.stream...),
+	}
+}
+
+// Status converts an application error into a gRPC status error. Status
+// errors pass through, context errors keep their meaning and anything else
+// is Internal
+func Status(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case stderrors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	appErr, ok := errors.AsAppError(err)
+	if !ok {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return status.Error(Code(appErr.Type), appErr.Error())
+}
+
+// Code maps an application error type to the closest gRPC status code
+func Code(errorType errors.ErrorType) codes.Code {
+	switch errorType {
+	case errors.ErrorTypeValidation, errors.ErrorTypeBadRequest,
+		errors.ErrorTypeUnsupportedMediaType, errors.ErrorTypeNotAcceptable:
+		return codes.InvalidArgument
+	case errors.ErrorTypeNotFound:
+		return codes.NotFound
+	case errors.ErrorTypeUnauthorized:
+		return codes.Unauthenticated
+	case errors.ErrorTypeForbidden:
+		return codes.PermissionDenied
+	case errors.ErrorTypeConflict:
+		return codes.FailedPrecondition
+	case errors.ErrorTypeTimeout:
+		return codes.DeadlineExceeded
+	case errors.ErrorTypeRateLimit:
+		return codes.ResourceExhausted
+	case errors.ErrorTypeExternal:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
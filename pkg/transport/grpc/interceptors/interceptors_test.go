@@ -0,0 +1,261 @@
+package interceptors
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	grpcgo "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/metrics"
+)
+
+func observedLogger() (*logger.Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	return &logger.Logger{Logger: zap.New(core)}, logs
+}
+
+// fakeStream is a server stream recording sent messages
+type fakeStream struct {
+	grpcgo.ServerStream
+	ctx  context.Context
+	sent int
+}
+
+func (s *fakeStream) Context() context.Context    { return s.ctx }
+func (s *fakeStream) SendMsg(interface{}) error   { s.sent++; return nil }
+func (s *fakeStream) RecvMsg(m interface{}) error { return nil }
+
+// serve serves the health service through chain and returns a client for it
+func serve(t *testing.T, chain *Chain) healthpb.HealthClient {
+	t.Helper()
+	server := grpcgo.NewServer(chain.ServerOptions()...)
+	healthpb.RegisterHealthServer(server, grpchealth.NewServer())
+	lis := bufconn.Listen(1024 * 1024)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpcgo.NewClient("passthrough:///bufnet",
+		grpcgo.WithTransportCredentials(insecure.NewCredentials()),
+		grpcgo.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestChainOrder(t *testing.T) {
+	ctx := testutil.TimeoutContext(t, 5*time.Second)
+	var calls []string
+	record := func(name string) grpcgo.UnaryServerInterceptor {
+		return func(ctx context.Context, req interface{}, info *grpcgo.UnaryServerInfo, handler grpcgo.UnaryHandler) (interface{}, error) {
+			calls = append(calls, name+" in")
+			defer func() { calls = append(calls, name+" out") }()
+			return handler(ctx, req)
+		}
+	}
+	client := serve(t, NewChain().Use(record("first"), nil).Use(record("second"), nil))
+
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Failed to call through the chain: %v", err)
+	}
+	if got := strings.Join(calls, ", "); got != "first in, second in, second out, first out" {
+		t.Errorf("Unexpected interceptor order: %s", got)
+	}
+
+	t.Log("✓ Interceptors wrap calls in the order they were added")
+}
+
+func TestRecovery(t *testing.T) {
+	log, logs := observedLogger()
+	info := &grpcgo.UnaryServerInfo{FullMethod: "/user.UserService/GetUser"}
+
+	_, err := UnaryRecovery(log)(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		panic("secret connection string")
+	})
+	if status.Code(err) != codes.Internal || strings.Contains(err.Error(), "secret") {
+		t.Errorf("Expected an Internal error hiding the panic, got %v", err)
+	}
+	if entries := logs.FilterMessage("gRPC handler panicked").All(); len(entries) != 1 || entries[0].ContextMap()["panic"] != "secret connection string" {
+		t.Errorf("Expected the panic to be logged, got %v", entries)
+	}
+
+	_, err = UnaryRecovery(log)(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		panic(errors.NotFoundError("USER_NOT_FOUND", "user not found"))
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected an application error panic to keep its code, got %v", err)
+	}
+
+	streamInfo := &grpcgo.StreamServerInfo{FullMethod: "/user.UserService/StreamUsers"}
+	err = StreamRecovery(log)(nil, &fakeStream{ctx: context.Background()}, streamInfo, func(interface{}, grpcgo.ServerStream) error {
+		var users map[string]int
+		users["alice"]++
+		return nil
+	})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("Expected a stream panic to become Internal, got %v", err)
+	}
+
+	t.Log("✓ Panics become status errors instead of crashing the server")
+}
+
+func TestMetrics(t *testing.T) {
+	cfg := metrics.DefaultConfig()
+	cfg.RuntimeMetrics = false
+	collector := metrics.NewCollector(cfg, nil)
+
+	info := &grpcgo.UnaryServerInfo{FullMethod: "/user.UserService/GetUser"}
+	UnaryMetrics(collector)(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	})
+	streamInfo := &grpcgo.StreamServerInfo{FullMethod: "/user.UserService/StreamUsers"}
+	StreamMetrics(collector)(nil, &fakeStream{ctx: context.Background()}, streamInfo, func(_ interface{}, ss grpcgo.ServerStream) error {
+		ss.SendMsg("a")
+		ss.SendMsg("b")
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	collector.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	for _, line := range []string{
+		`transport_grpc_requests_total{code="NotFound",method="/user.UserService/GetUser",type="unary"} 1`,
+		`transport_grpc_request_duration_seconds_count{code="OK",method="/user.UserService/StreamUsers",type="stream"} 1`,
+		`transport_grpc_stream_messages_total{direction="sent",method="/user.UserService/StreamUsers"} 2`,
+	} {
+		if !strings.Contains(rec.Body.String(), line) {
+			t.Errorf("Expected metrics to contain %q", line)
+		}
+	}
+
+	t.Log("✓ Calls are counted and timed by method and code")
+}
+
+func TestAuth(t *testing.T) {
+	auth := StaticTokens{"s3cret": "alice"}
+	info := &grpcgo.UnaryServerInfo{FullMethod: "/user.UserService/GetUser"}
+	var subject string
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		subject, _ = SubjectFrom(ctx)
+		return "ok", nil
+	}
+	call := func(interceptor grpcgo.UnaryServerInterceptor, info *grpcgo.UnaryServerInfo, header string) error {
+		ctx := context.Background()
+		if header != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(AuthorizationKey, header))
+		}
+		_, err := interceptor(ctx, nil, info, handler)
+		return err
+	}
+
+	interceptor := UnaryAuth(auth, PublicMethods...)
+	for header, code := range map[string]codes.Code{
+		"":              codes.Unauthenticated,
+		"Bearer wrong":  codes.Unauthenticated,
+		"Basic s3cret":  codes.Unauthenticated,
+		"Bearer s3cret": codes.OK,
+		"bearer s3cret": codes.OK,
+	} {
+		if err := call(interceptor, info, header); status.Code(err) != code {
+			t.Errorf("%q: expected %s, got %v", header, code, err)
+		}
+	}
+	if subject != "alice" {
+		t.Errorf("Expected handlers to see the token's subject, got %q", subject)
+	}
+
+	subject = ""
+	if err := call(interceptor, &grpcgo.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, ""); err != nil || subject != "" {
+		t.Errorf("Expected public methods to pass without a subject, got %v", err)
+	}
+
+	forbidden := UnaryAuth(AuthenticatorFunc(func(context.Context, string) (string, error) {
+		return "", errors.ForbiddenError("SUSPENDED", "account suspended")
+	}))
+	if err := call(forbidden, info, "Bearer any"); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected application errors to keep their code, got %v", err)
+	}
+	failing := UnaryAuth(AuthenticatorFunc(func(context.Context, string) (string, error) {
+		return "", stderrors.New("token service down")
+	}))
+	if err := call(failing, info, "Bearer any"); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected plain errors to be Unauthenticated, got %v", err)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationKey, "Bearer s3cret"))
+	streamInfo := &grpcgo.StreamServerInfo{FullMethod: "/user.UserService/StreamUsers"}
+	StreamAuth(auth)(nil, &fakeStream{ctx: ctx}, streamInfo, func(_ interface{}, ss grpcgo.ServerStream) error {
+		subject, _ = SubjectFrom(ss.Context())
+		return nil
+	})
+	if subject != "alice" {
+		t.Errorf("Expected streams to see the token's subject, got %q", subject)
+	}
+
+	t.Log("✓ Calls need a valid bearer token unless public")
+}
+
+func TestDefaultChain(t *testing.T) {
+	ctx := testutil.TimeoutContext(t, 5*time.Second)
+	log, logs := observedLogger()
+	cfg := metrics.DefaultConfig()
+	cfg.RuntimeMetrics = false
+	collector := metrics.NewCollector(cfg, nil)
+	client := serve(t, Default(log, collector).WithAuth(StaticTokens{"s3cret": "alice"}))
+
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected a call without a token to be rejected, got %v", err)
+	}
+	authed := metadata.AppendToOutgoingContext(ctx, AuthorizationKey, "Bearer s3cret")
+	if _, err := client.Check(authed, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Errorf("Expected a call with a token to pass: %v", err)
+	}
+
+	entries := logs.FilterMessage("gRPC request").All()
+	if len(entries) != 2 || entries[0].ContextMap()["status_code"] != int64(codes.Unauthenticated) {
+		t.Errorf("Expected both calls to be logged with their status, got %v", entries)
+	}
+	rec := httptest.NewRecorder()
+	collector.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), `code="Unauthenticated"`) || !strings.Contains(rec.Body.String(), `code="OK"`) {
+		t.Errorf("Expected rejected and accepted calls to be counted:\n%s", rec.Body.String())
+	}
+
+	t.Log("✓ The default chain logs, recovers and counts every call")
+}
+
+func TestStatus(t *testing.T) {
+	wrapped := stderrors.Join(stderrors.New("listing users"), context.DeadlineExceeded)
+	if code := status.Code(Status(wrapped)); code != codes.DeadlineExceeded {
+		t.Errorf("Expected a wrapped deadline to map to DeadlineExceeded, got %v", code)
+	}
+	if code := status.Code(Status(errors.Wrap(context.Canceled, errors.ErrorTypeInternal, "CANCELED", "stream ended"))); code != codes.Canceled {
+		t.Errorf("Expected a wrapped cancellation to map to Canceled, got %v", code)
+	}
+	if code := status.Code(Status(errors.NotFoundError("USER_NOT_FOUND", "no user"))); code != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", code)
+	}
+
+	t.Log("✓ Errors map to gRPC status codes through wrapping")
+}
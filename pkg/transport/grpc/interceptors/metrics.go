@@ -0,0 +1,72 @@
+package interceptors
+
+import (
+	"context"
+	"time"
+
+	grpcgo "google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"go-transport-prac/internal/types"
+)
+
+// Metric names, prefixed with the collector's namespace
+const (
+	MetricRequests       = "grpc_requests_total"
+	MetricDuration       = "grpc_request_duration_seconds"
+	MetricStreamMessages = "grpc_stream_messages_total"
+)
+
+// UnaryMetrics counts and times every unary call, labelled with its method,
+// type and status code
+func UnaryMetrics(collector types.MetricsCollector) grpcgo.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpcgo.UnaryServerInfo, handler grpcgo.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observe(collector, info.FullMethod, "unary", start, err)
+		return resp, err
+	}
+}
+
+// StreamMetrics counts and times every streaming call, and counts the
+// messages it sends and receives
+func StreamMetrics(collector types.MetricsCollector) grpcgo.StreamServerInterceptor {
+	return func(srv interface{}, ss grpcgo.ServerStream, info *grpcgo.StreamServerInfo, handler grpcgo.StreamHandler) error {
+		start := time.Now()
+		counted := &countingStream{ServerStream: ss}
+		err := handler(srv, counted)
+		observe(collector, info.FullMethod, "stream", start, err)
+		collector.Counter(MetricStreamMessages, map[string]string{"method": info.FullMethod, "direction": "sent"}, float64(counted.sent))
+		collector.Counter(MetricStreamMessages, map[string]string{"method": info.FullMethod, "direction": "received"}, float64(counted.received))
+		return err
+	}
+}
+
+func observe(collector types.MetricsCollector, method, kind string, start time.Time, err error) {
+	tags := map[string]string{"method": method, "type": kind, "code": status.Code(err).String()}
+	collector.Counter(MetricRequests, tags, 1)
+	collector.Timer(MetricDuration, tags, time.Since(start))
+}
+
+// countingStream counts the messages passing through a stream
+type countingStream struct {
+	grpcgo.ServerStream
+	sent     int
+	received int
+}
+
+func (s *countingStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent++
+	}
+	return err
+}
+
+func (s *countingStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received++
+	}
+	return err
+}
@@ -0,0 +1,56 @@
+package interceptors
+
+import (
+	"context"
+	"runtime/debug"
+
+	"go.uber.org/zap"
+	grpcgo "google.golang.org/grpc"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/logger"
+)
+
+// CodePanic is the application error code of a recovered panic
+const CodePanic = "PANIC"
+
+// UnaryRecovery turns a panicking handler into an error instead of a crashed
+// server. The panic is logged with its stack and answered with Internal, or
+// with the status of the *errors.AppError it panicked with
+func UnaryRecovery(log *logger.Logger) grpcgo.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpcgo.UnaryServerInfo, handler grpcgo.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(log, info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecovery is UnaryRecovery for streaming calls
+func StreamRecovery(log *logger.Logger) grpcgo.StreamServerInterceptor {
+	return func(srv interface{}, ss grpcgo.ServerStream, info *grpcgo.StreamServerInfo, handler grpcgo.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(log, info.FullMethod, r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// recovered logs a panic and returns the status error answering the call
+func recovered(log *logger.Logger, method string, r interface{}) error {
+	log.Error("gRPC handler panicked",
+		zap.String("grpc_method", method),
+		zap.Any("panic", r),
+		zap.ByteString("stack", debug.Stack()),
+	)
+
+	// The panic value stays in the log; callers only learn the call failed
+	if appErr, ok := r.(*errors.AppError); ok {
+		return Status(appErr)
+	}
+	return Status(errors.InternalError(CodePanic, "internal server error"))
+}
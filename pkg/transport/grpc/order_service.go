@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"context"
+
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+)
+
+// OrderService implements order.OrderServiceServer on top of a Store
+type OrderService struct {
+	order.UnimplementedOrderServiceServer
+	store *Store
+}
+
+// NewOrderService creates an order service backed by store
+func NewOrderService(store *Store) *OrderService {
+	return &OrderService{store: store}
+}
+
+// CreateOrder creates a pending order
+func (s *OrderService) CreateOrder(ctx context.Context, req *order.CreateOrderRequest) (*order.OrderResponse, error) {
+	o, err := s.store.CreateOrder(req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &order.OrderResponse{Order: o, Success: true, Message: "order created"}, nil
+}
+
+// GetOrder returns an order by ID
+func (s *OrderService) GetOrder(ctx context.Context, req *order.GetOrderRequest) (*order.OrderResponse, error) {
+	o, err := s.store.GetOrder(req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &order.OrderResponse{Order: o, Success: true}, nil
+}
+
+// UpdateOrderStatus moves an order to a new status
+func (s *OrderService) UpdateOrderStatus(ctx context.Context, req *order.UpdateOrderStatusRequest) (*order.OrderResponse, error) {
+	o, err := s.store.UpdateOrderStatus(req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &order.OrderResponse{Order: o, Success: true, Message: "order status updated"}, nil
+}
+
+// CancelOrder cancels an order that has not shipped
+func (s *OrderService) CancelOrder(ctx context.Context, req *order.CancelOrderRequest) (*order.OrderResponse, error) {
+	o, err := s.store.CancelOrder(req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &order.OrderResponse{Order: o, Success: true, Message: "order cancelled"}, nil
+}
+
+// GetOrdersByUser returns one page of a user's orders
+func (s *OrderService) GetOrdersByUser(ctx context.Context, req *order.GetOrdersByUserRequest) (*order.OrdersResponse, error) {
+	orders := s.store.OrdersByUser(req.GetUserId(), req.GetStatus())
+	start, end, page, pageSize := paginate(len(orders), req.GetPage(), req.GetPageSize())
+
+	return &order.OrdersResponse{
+		Orders:     orders[start:end],
+		TotalCount: int32(len(orders)),
+		Page:       page,
+		PageSize:   pageSize,
+	}, nil
+}
+
+// StreamOrdersByUser sends every order of a user, one message per order
+func (s *OrderService) StreamOrdersByUser(req *order.GetOrdersByUserRequest, stream order.OrderService_StreamOrdersByUserServer) error {
+	for _, o := range s.store.OrdersByUser(req.GetUserId(), req.GetStatus()) {
+		if err := stream.Context().Err(); err != nil {
+			return toStatus(err)
+		}
+		if err := stream.Send(o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"context"
+
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+)
+
+// ProductService implements product.ProductServiceServer on top of a Store
+type ProductService struct {
+	product.UnimplementedProductServiceServer
+	store *Store
+}
+
+// NewProductService creates a product service backed by store
+func NewProductService(store *Store) *ProductService {
+	return &ProductService{store: store}
+}
+
+// CreateProduct creates a product
+func (s *ProductService) CreateProduct(ctx context.Context, req *product.CreateProductRequest) (*product.ProductResponse, error) {
+	p, err := s.store.CreateProduct(req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &product.ProductResponse{Product: p, Success: true, Message: "product created"}, nil
+}
+
+// GetProduct returns a product by ID
+func (s *ProductService) GetProduct(ctx context.Context, req *product.GetProductRequest) (*product.ProductResponse, error) {
+	p, err := s.store.GetProduct(req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &product.ProductResponse{Product: p, Success: true}, nil
+}
+
+// UpdateProduct updates the fields set in the request
+func (s *ProductService) UpdateProduct(ctx context.Context, req *product.UpdateProductRequest) (*product.ProductResponse, error) {
+	p, err := s.store.UpdateProduct(req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &product.ProductResponse{Product: p, Success: true, Message: "product updated"}, nil
+}
+
+// SearchProducts returns one page of matching products
+func (s *ProductService) SearchProducts(ctx context.Context, req *product.SearchProductsRequest) (*product.ProductsResponse, error) {
+	products := s.store.SearchProducts(req)
+	start, end, page, pageSize := paginate(len(products), req.GetPage(), req.GetPageSize())
+
+	return &product.ProductsResponse{
+		Products:   products[start:end],
+		TotalCount: int32(len(products)),
+		Page:       page,
+		PageSize:   pageSize,
+	}, nil
+}
+
+// StreamProducts sends every matching product, one message per product
+func (s *ProductService) StreamProducts(req *product.SearchProductsRequest, stream product.ProductService_StreamProductsServer) error {
+	for _, p := range s.store.SearchProducts(req) {
+		if err := stream.Context().Err(); err != nil {
+			return toStatus(err)
+		}
+		if err := stream.Send(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"go.uber.org/zap"
+	grpcgo "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	grpchealth "google.golang.org/grpc/health"
+
+	"go-transport-prac/internal/health"
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+	"go-transport-prac/pkg/transport/grpc/interceptors"
+)
+
+// Server serves the User, Product and Order gRPC services
+type Server struct {
+	cfg    Config
+	logger *logger.Logger
+	server *grpcgo.Server
+	store  *Store
+
+	// health serves grpc.health.v1 when enabled; registry, when set, drives
+	// its statuses while serving
+	health   *grpchealth.Server
+	registry *health.Registry
+}
+
+// NewServer creates a server with all services registered against store.
+// Every call is logged and recovered from panics; extra options, such as an
+// interceptors.Chain adding metrics or auth, are appended after the ones
+// derived from cfg.
+func NewServer(cfg Config, store *Store, log *logger.Logger, opts ...grpcgo.ServerOption) (*Server, error) {
+	if store == nil {
+		store = NewStore(nil)
+	}
+	if log == nil {
+		log = logger.Global()
+	}
+
+	log = log.WithComponent("grpc")
+
+	serverOpts := interceptors.Default(log, nil).ServerOptions()
+	if cfg.MaxRecvMsgSize > 0 {
+		serverOpts = append(serverOpts, grpcgo.MaxRecvMsgSize(cfg.MaxRecvMsgSize))
+	}
+	if cfg.TLSEnabled {
+		creds, err := credentials.NewServerTLSFromFile(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS credentials: %w", err)
+		}
+		serverOpts = append(serverOpts, grpcgo.Creds(creds))
+	}
+	serverOpts = append(serverOpts, opts...)
+
+	server := grpcgo.NewServer(serverOpts...)
+	user.RegisterUserServiceServer(server, NewUserService(store))
+	product.RegisterProductServiceServer(server, NewProductService(store))
+	order.RegisterOrderServiceServer(server, NewOrderService(store))
+
+	s := &Server{
+		cfg:    cfg,
+		logger: log,
+		server: server,
+		store:  store,
+	}
+	s.registerIntrospection()
+	return s, nil
+}
+
+// GRPCServer returns the underlying server for registering additional services
+func (s *Server) GRPCServer() *grpcgo.Server {
+	return s.server
+}
+
+// Store returns the store backing the services
+func (s *Server) Store() *Store {
+	return s.store
+}
+
+// ListenAndServe listens on the configured address and serves until stopped
+func (s *Server) ListenAndServe() error {
+	lis, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.cfg.Addr, err)
+	}
+	return s.Serve(lis)
+}
+
+// Serve serves gRPC requests on lis until the server is stopped
+func (s *Server) Serve(lis net.Listener) error {
+	s.logger.Info("gRPC server listening", zap.String("addr", lis.Addr().String()))
+
+	if s.health != nil && s.registry != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go s.watchHealth(ctx)
+	}
+
+	if err := s.server.Serve(lis); err != nil {
+		return fmt.Errorf("gRPC server failed: %w", err)
+	}
+	return nil
+}
+
+// Shutdown stops accepting new RPCs and waits for in-flight ones to finish.
+// Remaining RPCs are cancelled once ctx is done or the shutdown timeout elapses.
+// Health checks report NOT_SERVING from the start of the shutdown.
+func (s *Server) Shutdown(ctx context.Context) error {
+	// Fail health checks first, so load balancers stop sending new RPCs
+	if s.health != nil {
+		s.health.Shutdown()
+	}
+	if s.cfg.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.ShutdownTimeout)
+		defer cancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.logger.Info("gRPC server stopped")
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		s.logger.Warn("gRPC server forced to stop", zap.Error(ctx.Err()))
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,216 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	grpcgo "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+// startTestServer serves all services over an in-memory listener and returns a connected client
+func startTestServer(t *testing.T) (*Client, *testutil.FakeClock) {
+	t.Helper()
+
+	clock := testutil.NewDefaultFakeClock()
+	server, err := NewServer(DefaultConfig(), NewStore(clock), nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	go server.Serve(lis)
+	t.Cleanup(func() { server.Shutdown(context.Background()) })
+
+	client, err := NewClient("passthrough:///bufnet",
+		grpcgo.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return client, clock
+}
+
+func TestUserService(t *testing.T) {
+	client, clock := startTestServer(t)
+	ctx := testutil.TimeoutContext(t, 5*time.Second)
+
+	created, err := client.CreateUser(ctx, &user.CreateUserRequest{Email: "alice@example.com", Name: "Alice"})
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if created.Status != user.UserStatus_USER_STATUS_ACTIVE {
+		t.Errorf("Expected active user, got %s", created.Status)
+	}
+	if !created.CreatedAt.AsTime().Equal(testutil.DefaultFakeTime) {
+		t.Errorf("Expected CreatedAt from fake clock, got %v", created.CreatedAt.AsTime())
+	}
+
+	if _, err := client.CreateUser(ctx, &user.CreateUserRequest{Email: "alice@example.com", Name: "Alice 2"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for duplicate email, got %v", err)
+	}
+	if _, err := client.CreateUser(ctx, &user.CreateUserRequest{Email: "invalid", Name: "Bad"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for invalid email, got %v", err)
+	}
+
+	clock.Advance(time.Minute)
+	updated, err := client.UpdateUser(ctx, &user.UpdateUserRequest{Id: created.Id, Name: "Alice Smith"})
+	if err != nil {
+		t.Fatalf("Failed to update user: %v", err)
+	}
+	if updated.Name != "Alice Smith" || !updated.UpdatedAt.AsTime().Equal(testutil.DefaultFakeTime.Add(time.Minute)) {
+		t.Errorf("Unexpected updated user: %v", updated)
+	}
+
+	if err := client.DeleteUser(ctx, created.Id); err != nil {
+		t.Fatalf("Failed to delete user: %v", err)
+	}
+	if _, err := client.GetUser(ctx, created.Id); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound after delete, got %v", err)
+	}
+
+	t.Log("✓ User unary RPCs work")
+}
+
+func TestListAndStreamUsers(t *testing.T) {
+	client, _ := startTestServer(t)
+	ctx := testutil.TimeoutContext(t, 5*time.Second)
+
+	for i := 0; i < 25; i++ {
+		email := string(rune('a'+i)) + "@example.com"
+		if _, err := client.CreateUser(ctx, &user.CreateUserRequest{Email: email, Name: "User"}); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+
+	page, err := client.ListUsers(ctx, &user.ListUsersRequest{Page: 3, PageSize: 10})
+	if err != nil {
+		t.Fatalf("Failed to list users: %v", err)
+	}
+	if page.TotalCount != 25 || len(page.Users) != 5 || page.Users[0].Id != 21 {
+		t.Errorf("Unexpected page: total=%d len=%d", page.TotalCount, len(page.Users))
+	}
+
+	var streamed []uint64
+	err = client.StreamUsers(ctx, &user.ListUsersRequest{}, func(u *user.User) error {
+		streamed = append(streamed, u.Id)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to stream users: %v", err)
+	}
+	if len(streamed) != 25 {
+		t.Fatalf("Expected 25 streamed users, got %d", len(streamed))
+	}
+	for i := 1; i < len(streamed); i++ {
+		if streamed[i] <= streamed[i-1] {
+			t.Fatalf("Expected users streamed in ID order, got %v", streamed)
+		}
+	}
+
+	t.Log("✓ User list and stream RPCs work")
+}
+
+func TestProductAndOrderServices(t *testing.T) {
+	client, clock := startTestServer(t)
+	ctx := testutil.TimeoutContext(t, 5*time.Second)
+
+	buyer, err := client.CreateUser(ctx, &user.CreateUserRequest{Email: "buyer@example.com", Name: "Buyer"})
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	laptop, err := client.CreateProduct(ctx, &product.CreateProductRequest{
+		Name:       "Laptop",
+		Sku:        "LAP-001",
+		Price:      &product.Price{Currency: "USD", AmountCents: 99900},
+		Categories: []string{"Electronics"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	if _, err := client.CreateProduct(ctx, &product.CreateProductRequest{
+		Name:       "Novel",
+		Sku:        "BK-001",
+		Price:      &product.Price{Currency: "USD", AmountCents: 1500},
+		Categories: []string{"Books"},
+	}); err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+
+	search, err := client.SearchProducts(ctx, &product.SearchProductsRequest{Categories: []string{"electronics"}})
+	if err != nil {
+		t.Fatalf("Failed to search products: %v", err)
+	}
+	if search.TotalCount != 1 || search.Products[0].Sku != "LAP-001" {
+		t.Errorf("Unexpected search result: %v", search.Products)
+	}
+
+	streamed := 0
+	err = client.StreamProducts(ctx, &product.SearchProductsRequest{PriceRange: &product.PriceRange{MaxAmountCents: 2000}}, func(p *product.Product) error {
+		streamed++
+		return nil
+	})
+	if err != nil || streamed != 1 {
+		t.Errorf("Expected 1 streamed product under $20, got %d (err=%v)", streamed, err)
+	}
+
+	created, err := client.CreateOrder(ctx, &order.CreateOrderRequest{
+		UserId: buyer.Id,
+		Items:  []*order.OrderItem{{ProductId: laptop.Id, Quantity: 2}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create order: %v", err)
+	}
+	if created.Summary.Total.AmountCents != 199800 || created.Summary.TotalItems != 2 {
+		t.Errorf("Unexpected order summary: %v", created.Summary)
+	}
+
+	clock.Advance(time.Hour)
+	shipped, err := client.UpdateOrderStatus(ctx, &order.UpdateOrderStatusRequest{
+		Id:             created.Id,
+		Status:         order.OrderStatus_ORDER_STATUS_SHIPPED,
+		TrackingNumber: "1Z999",
+	})
+	if err != nil {
+		t.Fatalf("Failed to ship order: %v", err)
+	}
+	if !shipped.ShippedAt.AsTime().Equal(testutil.DefaultFakeTime.Add(time.Hour)) || shipped.Shipping.TrackingNumber != "1Z999" {
+		t.Errorf("Unexpected shipped order: %v", shipped)
+	}
+
+	if _, err := client.CancelOrder(ctx, created.Id, "changed mind"); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition cancelling a shipped order, got %v", err)
+	}
+
+	if _, err := client.CreateOrder(ctx, &order.CreateOrderRequest{
+		UserId: 999,
+		Items:  []*order.OrderItem{{ProductId: laptop.Id, Quantity: 1}},
+	}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for unknown user, got %v", err)
+	}
+
+	orders := 0
+	err = client.StreamOrdersByUser(ctx, &order.GetOrdersByUserRequest{UserId: buyer.Id}, func(o *order.Order) error {
+		orders++
+		return nil
+	})
+	if err != nil || orders != 1 {
+		t.Errorf("Expected 1 streamed order, got %d (err=%v)", orders, err)
+	}
+
+	t.Log("✓ Product and order RPCs work")
+}
@@ -0,0 +1,10 @@
+package grpc
+
+import (
+	"go-transport-prac/pkg/transport/grpc/interceptors"
+)
+
+// toStatus converts an application error into a gRPC status error
+func toStatus(err error) error {
+	return interceptors.Status(err)
+}
@@ -0,0 +1,503 @@
+package grpc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// Store is an in-memory backing store for the User, Product and Order services.
+// Messages are cloned on the way in and out so callers never share state with the store.
+type Store struct {
+	mu       sync.RWMutex
+	clock    types.Clock
+	nextID   uint64
+	users    map[uint64]*user.User
+	products map[uint64]*product.Product
+	orders   map[uint64]*order.Order
+}
+
+// NewStore creates an empty store; a nil clock uses the system clock
+func NewStore(clock types.Clock) *Store {
+	return &Store{
+		clock:    types.ClockOrSystem(clock),
+		nextID:   1,
+		users:    make(map[uint64]*user.User),
+		products: make(map[uint64]*product.Product),
+		orders:   make(map[uint64]*order.Order),
+	}
+}
+
+// now returns the current time as a protobuf timestamp
+func (s *Store) now() *timestamppb.Timestamp {
+	return timestamppb.New(s.clock.Now())
+}
+
+// newID allocates the next ID; callers must hold the write lock
+func (s *Store) newID() uint64 {
+	id := s.nextID
+	s.nextID++
+	return id
+}
+
+// CreateUser stores a new active user
+func (s *Store) CreateUser(req *user.CreateUserRequest) (*user.User, error) {
+	if !strings.Contains(req.GetEmail(), "@") {
+		return nil, errors.ValidationError("INVALID_EMAIL", "a valid email is required")
+	}
+	if req.GetName() == "" {
+		return nil, errors.ValidationError("INVALID_NAME", "name is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.users {
+		if strings.EqualFold(existing.Email, req.GetEmail()) {
+			return nil, errors.ConflictError("USER_EXISTS", fmt.Sprintf("user with email %s already exists", req.GetEmail()))
+		}
+	}
+
+	now := s.now()
+	u := &user.User{
+		Id:        s.newID(),
+		Email:     req.GetEmail(),
+		Name:      req.GetName(),
+		Status:    user.UserStatus_USER_STATUS_ACTIVE,
+		Profile:   proto.Clone(req.GetProfile()).(*user.Profile),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.users[u.Id] = u
+
+	return proto.Clone(u).(*user.User), nil
+}
+
+// GetUser returns a user by ID
+func (s *Store) GetUser(id uint64) (*user.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		return nil, userNotFound(id)
+	}
+	return proto.Clone(u).(*user.User), nil
+}
+
+// UpdateUser replaces the name and profile of a user when they are set in the request
+func (s *Store) UpdateUser(req *user.UpdateUserRequest) (*user.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[req.GetId()]
+	if !ok {
+		return nil, userNotFound(req.GetId())
+	}
+
+	if req.GetName() != "" {
+		u.Name = req.GetName()
+	}
+	if req.GetProfile() != nil {
+		u.Profile = proto.Clone(req.GetProfile()).(*user.Profile)
+	}
+	u.UpdatedAt = s.now()
+
+	return proto.Clone(u).(*user.User), nil
+}
+
+// DeleteUser removes a user and returns its last state
+func (s *Store) DeleteUser(id uint64) (*user.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		return nil, userNotFound(id)
+	}
+	delete(s.users, id)
+
+	u.Status = user.UserStatus_USER_STATUS_DELETED
+	return u, nil
+}
+
+// ListUsers returns users ordered by ID, optionally filtered by status
+func (s *Store) ListUsers(status user.UserStatus) []*user.User {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := make([]*user.User, 0, len(s.users))
+	for _, u := range s.users {
+		if status == user.UserStatus_USER_STATUS_UNSPECIFIED || u.Status == status {
+			users = append(users, proto.Clone(u).(*user.User))
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Id < users[j].Id })
+	return users
+}
+
+// CreateProduct stores a new active product
+func (s *Store) CreateProduct(req *product.CreateProductRequest) (*product.Product, error) {
+	if req.GetName() == "" {
+		return nil, errors.ValidationError("INVALID_NAME", "name is required")
+	}
+	if req.GetSku() == "" {
+		return nil, errors.ValidationError("INVALID_SKU", "sku is required")
+	}
+	if req.GetPrice().GetAmountCents() < 0 {
+		return nil, errors.ValidationError("INVALID_PRICE", "price cannot be negative")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.products {
+		if existing.Sku == req.GetSku() {
+			return nil, errors.ConflictError("PRODUCT_EXISTS", fmt.Sprintf("product with sku %s already exists", req.GetSku()))
+		}
+	}
+
+	now := s.now()
+	p := &product.Product{
+		Id:             s.newID(),
+		Name:           req.GetName(),
+		Description:    req.GetDescription(),
+		Sku:            req.GetSku(),
+		Price:          proto.Clone(req.GetPrice()).(*product.Price),
+		Inventory:      proto.Clone(req.GetInventory()).(*product.Inventory),
+		Categories:     append([]string(nil), req.GetCategories()...),
+		Tags:           append([]string(nil), req.GetTags()...),
+		Status:         product.ProductStatus_PRODUCT_STATUS_ACTIVE,
+		Specifications: proto.Clone(req.GetSpecifications()).(*product.Specifications),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	s.products[p.Id] = p
+
+	return proto.Clone(p).(*product.Product), nil
+}
+
+// GetProduct returns a product by ID
+func (s *Store) GetProduct(id uint64) (*product.Product, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.products[id]
+	if !ok {
+		return nil, productNotFound(id)
+	}
+	return proto.Clone(p).(*product.Product), nil
+}
+
+// UpdateProduct replaces the fields that are set in the request
+func (s *Store) UpdateProduct(req *product.UpdateProductRequest) (*product.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.products[req.GetId()]
+	if !ok {
+		return nil, productNotFound(req.GetId())
+	}
+
+	if req.GetName() != "" {
+		p.Name = req.GetName()
+	}
+	if req.GetDescription() != "" {
+		p.Description = req.GetDescription()
+	}
+	if req.GetPrice() != nil {
+		p.Price = proto.Clone(req.GetPrice()).(*product.Price)
+	}
+	if req.GetInventory() != nil {
+		p.Inventory = proto.Clone(req.GetInventory()).(*product.Inventory)
+	}
+	if len(req.GetCategories()) > 0 {
+		p.Categories = append([]string(nil), req.GetCategories()...)
+	}
+	if len(req.GetTags()) > 0 {
+		p.Tags = append([]string(nil), req.GetTags()...)
+	}
+	if req.GetSpecifications() != nil {
+		p.Specifications = proto.Clone(req.GetSpecifications()).(*product.Specifications)
+	}
+	p.UpdatedAt = s.now()
+
+	return proto.Clone(p).(*product.Product), nil
+}
+
+// SearchProducts returns products ordered by ID that match every criterion set in the request
+func (s *Store) SearchProducts(req *product.SearchProductsRequest) []*product.Product {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	products := make([]*product.Product, 0)
+	for _, p := range s.products {
+		if matchesProductSearch(p, req) {
+			products = append(products, proto.Clone(p).(*product.Product))
+		}
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].Id < products[j].Id })
+	return products
+}
+
+// matchesProductSearch reports whether p satisfies the search criteria
+func matchesProductSearch(p *product.Product, req *product.SearchProductsRequest) bool {
+	if req.GetStatus() != product.ProductStatus_PRODUCT_STATUS_UNSPECIFIED && p.Status != req.GetStatus() {
+		return false
+	}
+
+	if query := strings.ToLower(req.GetQuery()); query != "" &&
+		!strings.Contains(strings.ToLower(p.Name), query) &&
+		!strings.Contains(strings.ToLower(p.Description), query) {
+		return false
+	}
+
+	if len(req.GetCategories()) > 0 {
+		found := false
+		for _, category := range req.GetCategories() {
+			for _, pc := range p.Categories {
+				if strings.EqualFold(pc, category) {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if r := req.GetPriceRange(); r != nil {
+		amount := p.GetPrice().GetAmountCents()
+		if r.GetCurrency() != "" && r.GetCurrency() != p.GetPrice().GetCurrency() {
+			return false
+		}
+		if r.GetMinAmountCents() > 0 && amount < r.GetMinAmountCents() {
+			return false
+		}
+		if r.GetMaxAmountCents() > 0 && amount > r.GetMaxAmountCents() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CreateOrder stores a new pending order, pricing items from the product catalog
+func (s *Store) CreateOrder(req *order.CreateOrderRequest) (*order.Order, error) {
+	if len(req.GetItems()) == 0 {
+		return nil, errors.ValidationError("EMPTY_ORDER", "an order needs at least one item")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[req.GetUserId()]; !ok {
+		return nil, userNotFound(req.GetUserId())
+	}
+
+	items := make([]*order.OrderItem, 0, len(req.GetItems()))
+	var subtotal int64
+	var totalItems int32
+	currency := "USD"
+
+	for _, requested := range req.GetItems() {
+		if requested.GetQuantity() <= 0 {
+			return nil, errors.ValidationError("INVALID_QUANTITY", fmt.Sprintf("invalid quantity for product %d", requested.GetProductId()))
+		}
+
+		p, ok := s.products[requested.GetProductId()]
+		if !ok {
+			return nil, productNotFound(requested.GetProductId())
+		}
+
+		unit := p.GetPrice().GetAmountCents()
+		currency = p.GetPrice().GetCurrency()
+		total := unit * int64(requested.GetQuantity())
+
+		items = append(items, &order.OrderItem{
+			ProductId:      p.Id,
+			ProductName:    p.Name,
+			ProductSku:     p.Sku,
+			Quantity:       requested.GetQuantity(),
+			UnitPrice:      &product.Price{Currency: currency, AmountCents: unit},
+			TotalPrice:     &product.Price{Currency: currency, AmountCents: total},
+			ProductVariant: requested.GetProductVariant(),
+		})
+		subtotal += total
+		totalItems += requested.GetQuantity()
+	}
+
+	now := s.now()
+	id := s.newID()
+	o := &order.Order{
+		Id:          id,
+		UserId:      req.GetUserId(),
+		OrderNumber: fmt.Sprintf("ORD-%06d", id),
+		Status:      order.OrderStatus_ORDER_STATUS_PENDING,
+		Items:       items,
+		Summary: &order.OrderSummary{
+			Subtotal:   &product.Price{Currency: currency, AmountCents: subtotal},
+			Total:      &product.Price{Currency: currency, AmountCents: subtotal},
+			TotalItems: totalItems,
+		},
+		Shipping:  proto.Clone(req.GetShipping()).(*order.ShippingInfo),
+		Payment:   proto.Clone(req.GetPayment()).(*order.PaymentInfo),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.orders[o.Id] = o
+
+	return proto.Clone(o).(*order.Order), nil
+}
+
+// GetOrder returns an order by ID
+func (s *Store) GetOrder(id uint64) (*order.Order, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	o, ok := s.orders[id]
+	if !ok {
+		return nil, orderNotFound(id)
+	}
+	return proto.Clone(o).(*order.Order), nil
+}
+
+// UpdateOrderStatus moves an order to a new status, stamping shipping and delivery times
+func (s *Store) UpdateOrderStatus(req *order.UpdateOrderStatusRequest) (*order.Order, error) {
+	if req.GetStatus() == order.OrderStatus_ORDER_STATUS_UNSPECIFIED {
+		return nil, errors.ValidationError("INVALID_STATUS", "status is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.orders[req.GetId()]
+	if !ok {
+		return nil, orderNotFound(req.GetId())
+	}
+	if isFinalOrderStatus(o.Status) {
+		return nil, errors.ConflictError("ORDER_CLOSED", fmt.Sprintf("order %d is %s", o.Id, o.Status))
+	}
+
+	now := s.now()
+	o.Status = req.GetStatus()
+	o.UpdatedAt = now
+
+	if req.GetTrackingNumber() != "" {
+		if o.Shipping == nil {
+			o.Shipping = &order.ShippingInfo{}
+		}
+		o.Shipping.TrackingNumber = req.GetTrackingNumber()
+	}
+
+	switch o.Status {
+	case order.OrderStatus_ORDER_STATUS_SHIPPED:
+		o.ShippedAt = now
+	case order.OrderStatus_ORDER_STATUS_DELIVERED:
+		o.DeliveredAt = now
+	}
+
+	return proto.Clone(o).(*order.Order), nil
+}
+
+// CancelOrder cancels an order that has not shipped yet
+func (s *Store) CancelOrder(req *order.CancelOrderRequest) (*order.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.orders[req.GetId()]
+	if !ok {
+		return nil, orderNotFound(req.GetId())
+	}
+
+	switch o.Status {
+	case order.OrderStatus_ORDER_STATUS_PENDING,
+		order.OrderStatus_ORDER_STATUS_CONFIRMED,
+		order.OrderStatus_ORDER_STATUS_PROCESSING:
+	default:
+		return nil, errors.ConflictError("ORDER_NOT_CANCELLABLE", fmt.Sprintf("order %d is %s", o.Id, o.Status))
+	}
+
+	o.Status = order.OrderStatus_ORDER_STATUS_CANCELLED
+	o.UpdatedAt = s.now()
+
+	return proto.Clone(o).(*order.Order), nil
+}
+
+// OrdersByUser returns a user's orders ordered by ID, optionally filtered by status
+func (s *Store) OrdersByUser(userID uint64, status order.OrderStatus) []*order.Order {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	orders := make([]*order.Order, 0)
+	for _, o := range s.orders {
+		if o.UserId != userID {
+			continue
+		}
+		if status == order.OrderStatus_ORDER_STATUS_UNSPECIFIED || o.Status == status {
+			orders = append(orders, proto.Clone(o).(*order.Order))
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].Id < orders[j].Id })
+	return orders
+}
+
+// isFinalOrderStatus reports whether an order can no longer change status
+func isFinalOrderStatus(status order.OrderStatus) bool {
+	switch status {
+	case order.OrderStatus_ORDER_STATUS_CANCELLED, order.OrderStatus_ORDER_STATUS_REFUNDED:
+		return true
+	default:
+		return false
+	}
+}
+
+// paginate returns the [start, end) bounds of a 1-based page along with the normalized page and size
+func paginate(total int, page, pageSize int32) (start, end int, normalizedPage, normalizedSize int32) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	start = int(page-1) * int(pageSize)
+	if start > total {
+		start = total
+	}
+	end = start + int(pageSize)
+	if end > total {
+		end = total
+	}
+	return start, end, page, pageSize
+}
+
+func userNotFound(id uint64) error {
+	return errors.NotFoundError("USER_NOT_FOUND", fmt.Sprintf("user %d not found", id))
+}
+
+func productNotFound(id uint64) error {
+	return errors.NotFoundError("PRODUCT_NOT_FOUND", fmt.Sprintf("product %d not found", id))
+}
+
+func orderNotFound(id uint64) error {
+	return errors.NotFoundError("ORDER_NOT_FOUND", fmt.Sprintf("order %d not found", id))
+}
@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"context"
+
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+// UserService implements user.UserServiceServer on top of a Store
+type UserService struct {
+	user.UnimplementedUserServiceServer
+	store *Store
+}
+
+// NewUserService creates a user service backed by store
+func NewUserService(store *Store) *UserService {
+	return &UserService{store: store}
+}
+
+// CreateUser creates a user
+func (s *UserService) CreateUser(ctx context.Context, req *user.CreateUserRequest) (*user.UserResponse, error) {
+	u, err := s.store.CreateUser(req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &user.UserResponse{User: u, Success: true, Message: "user created"}, nil
+}
+
+// GetUser returns a user by ID
+func (s *UserService) GetUser(ctx context.Context, req *user.GetUserRequest) (*user.UserResponse, error) {
+	u, err := s.store.GetUser(req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &user.UserResponse{User: u, Success: true}, nil
+}
+
+// UpdateUser updates a user's name and profile
+func (s *UserService) UpdateUser(ctx context.Context, req *user.UpdateUserRequest) (*user.UserResponse, error) {
+	u, err := s.store.UpdateUser(req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &user.UserResponse{User: u, Success: true, Message: "user updated"}, nil
+}
+
+// DeleteUser deletes a user
+func (s *UserService) DeleteUser(ctx context.Context, req *user.DeleteUserRequest) (*user.UserResponse, error) {
+	u, err := s.store.DeleteUser(req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &user.UserResponse{User: u, Success: true, Message: "user deleted"}, nil
+}
+
+// ListUsers returns one page of users
+func (s *UserService) ListUsers(ctx context.Context, req *user.ListUsersRequest) (*user.UsersResponse, error) {
+	users := s.store.ListUsers(req.GetStatus())
+	start, end, page, pageSize := paginate(len(users), req.GetPage(), req.GetPageSize())
+
+	return &user.UsersResponse{
+		Users:      users[start:end],
+		TotalCount: int32(len(users)),
+		Page:       page,
+		PageSize:   pageSize,
+	}, nil
+}
+
+// StreamUsers sends every matching user, one message per user
+func (s *UserService) StreamUsers(req *user.ListUsersRequest, stream user.UserService_StreamUsersServer) error {
+	for _, u := range s.store.ListUsers(req.GetStatus()) {
+		if err := stream.Context().Err(); err != nil {
+			return toStatus(err)
+		}
+		if err := stream.Send(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	nethttp "net/http"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/flags"
+	"go-transport-prac/internal/types"
+)
+
+// adminPrefix is the path every admin endpoint lives under
+const adminPrefix = "/admin/"
+
+// DefaultAdminActor is recorded for admin changes without an X-Admin-Actor header
+const DefaultAdminActor = "admin"
+
+// FlagsResponse is the body of GET /admin/flags
+type FlagsResponse struct {
+	Flags []flags.Flag `json:"flags"`
+}
+
+// AuditResponse is the body of GET /admin/audit
+type AuditResponse struct {
+	Changes []flags.Change `json:"changes"`
+}
+
+// LogLevelsResponse is the body of GET /admin/log-levels: the global level
+// and the per-component overrides
+type LogLevelsResponse struct {
+	Level      string            `json:"level"`
+	Components map[string]string `json:"components"`
+}
+
+// SetFlagRequest is the body of PUT /admin/flags/{name}
+type SetFlagRequest struct {
+	Value string `json:"value"`
+}
+
+// SetLogLevelRequest is the body of PUT /admin/log-levels and
+// PUT /admin/log-levels/{component}. An empty component level removes the override
+type SetLogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// WithAdmin serves an admin API for changing settings without a restart:
+//
+//   - GET /admin/flags, PUT /admin/flags/{name}: runtime flags of set
+//   - GET /admin/log-levels, PUT /admin/log-levels[/{component}]: log levels
+//   - GET /admin/audit: who changed what, oldest first
+//
+// Requests must carry "Authorization: Bearer <token>". With an empty token
+// the API is not served at all. Changes are attributed to the X-Admin-Actor
+// header and recorded in set's audit log
+func (s *Server) WithAdmin(set *flags.Set, token string) *Server {
+	if token == "" {
+		s.logger.Error("Admin API not served: it needs a token")
+		return s
+	}
+	a := &admin{server: s, flags: set, token: token}
+	for _, h := range []handler{
+		{method: nethttp.MethodGet, path: adminPrefix + "flags", handle: a.listFlags},
+		{method: nethttp.MethodPut, path: adminPrefix + "flags/{name}", handle: a.setFlag},
+		{method: nethttp.MethodGet, path: adminPrefix + "log-levels", handle: a.logLevels},
+		{method: nethttp.MethodPut, path: adminPrefix + "log-levels", handle: a.setLogLevel},
+		{method: nethttp.MethodPut, path: adminPrefix + "log-levels/{component}", handle: a.setLogLevel},
+		{method: nethttp.MethodGet, path: adminPrefix + "audit", handle: a.audit},
+	} {
+		h.handle = a.authorize(h.handle)
+		s.Handle(h)
+	}
+	return s
+}
+
+// isAdmin reports whether h is an admin endpoint. Runtime limits do not apply
+// to them, so a limit set too low can still be raised
+func isAdmin(h types.HTTPHandler) bool {
+	return strings.HasPrefix(h.Path(), adminPrefix)
+}
+
+// admin serves the admin endpoints of a server
+type admin struct {
+	server *Server
+	flags  *flags.Set
+	token  string
+}
+
+// authorize rejects requests without the admin token
+func (a *admin) authorize(next func(context.Context, types.HTTPRequest) (types.HTTPResponse, error)) func(context.Context, types.HTTPRequest) (types.HTTPResponse, error) {
+	return func(ctx context.Context, req types.HTTPRequest) (types.HTTPResponse, error) {
+		given, ok := strings.CutPrefix(req.Headers["Authorization"], "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(a.token)) != 1 {
+			return types.HTTPResponse{}, errors.UnauthorizedError(errors.CodeInvalidToken, "invalid admin token")
+		}
+		return next(ctx, req)
+	}
+}
+
+func (a *admin) listFlags(ctx context.Context, req types.HTTPRequest) (types.HTTPResponse, error) {
+	return adminJSON(FlagsResponse{Flags: a.flags.List()})
+}
+
+func (a *admin) setFlag(ctx context.Context, req types.HTTPRequest) (types.HTTPResponse, error) {
+	var body SetFlagRequest
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		return types.HTTPResponse{}, errors.BadRequestError(errors.CodeInvalidInput, "body must be {\"value\": \"...\"}")
+	}
+
+	change, err := a.flags.Set(req.PathParams["name"], body.Value, actor(req))
+	if err != nil {
+		return types.HTTPResponse{}, err
+	}
+	a.server.logger.Info("Flag changed", zap.String("flag", change.Name), zap.String("old", change.Old),
+		zap.String("new", change.New), zap.String("actor", change.Actor))
+	return adminJSON(change)
+}
+
+func (a *admin) logLevels(ctx context.Context, req types.HTTPRequest) (types.HTTPResponse, error) {
+	log := a.server.logger
+	return adminJSON(LogLevelsResponse{Level: log.GlobalLevel(), Components: log.ComponentLevels()})
+}
+
+// setLogLevel changes the global level, or a component's level when the path names one
+func (a *admin) setLogLevel(ctx context.Context, req types.HTTPRequest) (types.HTTPResponse, error) {
+	var body SetLogLevelRequest
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		return types.HTTPResponse{}, errors.BadRequestError(errors.CodeInvalidInput, "body must be {\"level\": \"...\"}")
+	}
+
+	log := a.server.logger
+	component := req.PathParams["component"]
+	name, old := "log.level", log.GlobalLevel()
+	var err error
+	if component == "" {
+		err = log.SetLevel(body.Level)
+	} else {
+		name, old = "log.level."+component, log.ComponentLevels()[component]
+		err = log.SetComponentLevel(component, body.Level)
+	}
+	if err != nil {
+		return types.HTTPResponse{}, errors.ValidationError(errors.CodeInvalidValue, err.Error())
+	}
+
+	change := a.flags.Record(name, old, strings.ToLower(body.Level), actor(req))
+	log.Info("Log level changed", zap.String("flag", change.Name), zap.String("old", change.Old),
+		zap.String("new", change.New), zap.String("actor", change.Actor))
+	return adminJSON(change)
+}
+
+func (a *admin) audit(ctx context.Context, req types.HTTPRequest) (types.HTTPResponse, error) {
+	return adminJSON(AuditResponse{Changes: a.flags.Audit()})
+}
+
+// actor names who made a change
+func actor(req types.HTTPRequest) string {
+	if name := strings.TrimSpace(req.Headers["X-Admin-Actor"]); name != "" {
+		return name
+	}
+	return DefaultAdminActor
+}
+
+// adminJSON answers with v as JSON
+func adminJSON(v interface{}) (types.HTTPResponse, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return types.HTTPResponse{}, errors.Wrap(err, errors.ErrorTypeInternal, errors.CodeSerializationError, "failed to encode response")
+	}
+	return respond(nethttp.StatusOK, FormatJSON, body), nil
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"go-transport-prac/internal/config"
+)
+
+// Config holds HTTP server settings
+type Config struct {
+	// Addr is the host:port the server listens on
+	Addr string
+
+	TLSEnabled bool
+	CertFile   string
+	KeyFile    string
+
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+
+	// MaxBodyBytes bounds the size of a request body
+	MaxBodyBytes int64
+	// RateLimit is the number of requests per second the server accepts, unlimited when 0
+	RateLimit int64
+	// RateBurst is how many requests can arrive at once under RateLimit, RateLimit when 0
+	RateBurst int64
+	// StrictDecode rejects request bodies with fields the model does not have
+	StrictDecode bool
+	// ShutdownTimeout bounds how long Shutdown waits for in-flight requests
+	ShutdownTimeout time.Duration
+
+	// LongPollWait is how long GET /events waits for an event when the client gives no wait
+	LongPollWait time.Duration
+	// LongPollMaxWait caps the wait a client can ask for; it should stay below WriteTimeout
+	LongPollMaxWait time.Duration
+}
+
+// DefaultConfig returns a plaintext configuration on the default HTTP port
+func DefaultConfig() Config {
+	return Config{
+		Addr:            "localhost:8080",
+		ReadTimeout:     30 * time.Second,
+		WriteTimeout:    30 * time.Second,
+		IdleTimeout:     120 * time.Second,
+		MaxBodyBytes:    4 * 1024 * 1024,
+		ShutdownTimeout: 10 * time.Second,
+		LongPollWait:    20 * time.Second,
+		LongPollMaxWait: 25 * time.Second,
+	}
+}
+
+// NewConfig builds an HTTP configuration from the application server configuration
+func NewConfig(cfg config.ServerConfig) Config {
+	httpCfg := DefaultConfig()
+	httpCfg.Addr = net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.HTTPPort))
+	httpCfg.TLSEnabled = cfg.TLSEnabled
+	httpCfg.CertFile = cfg.CertFile
+	httpCfg.KeyFile = cfg.KeyFile
+	httpCfg.ReadTimeout = cfg.ReadTimeout
+	httpCfg.WriteTimeout = cfg.WriteTimeout
+	httpCfg.IdleTimeout = cfg.IdleTimeout
+	return httpCfg
+}
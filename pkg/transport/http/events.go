@@ -0,0 +1,121 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/transport/eventlog"
+)
+
+const (
+	// DefaultEventsLimit is the page size of GET /events when no limit is given
+	DefaultEventsLimit = 100
+	// MaxEventsLimit caps the page size a client can ask for
+	MaxEventsLimit = 1000
+)
+
+// EventMessage is one event in a long-poll response
+type EventMessage struct {
+	Cursor string      `json:"cursor"`
+	Topic  string      `json:"topic"`
+	Time   time.Time   `json:"time"`
+	Data   interface{} `json:"data"`
+}
+
+// EventsResponse is the body of GET /events. Cursor is what the client
+// passes on its next poll; it advances even when no events matched
+type EventsResponse struct {
+	Events []EventMessage `json:"events"`
+	Cursor string         `json:"cursor"`
+}
+
+// WithEventLog serves GET /events from log, for clients that cannot hold a
+// WebSocket open. It takes the query parameters:
+//
+//   - cursor: resume after this event; empty waits for the next event
+//   - topics: comma-separated topics to return, all when empty
+//   - limit: maximum events per response, DefaultEventsLimit when empty
+//   - wait: how long to wait for an event, as a Go duration such as 10s;
+//     Config.LongPollWait when empty, capped at Config.LongPollMaxWait
+//
+// A poll that times out returns no events and the cursor to poll with next.
+// An expired cursor is answered with 410 Gone
+func (s *Server) WithEventLog(log *eventlog.Log) *Server {
+	s.Handle(handler{method: nethttp.MethodGet, path: "/events", handle: func(ctx context.Context, req types.HTTPRequest) (types.HTTPResponse, error) {
+		return s.pollEvents(ctx, log, req)
+	}})
+	return s
+}
+
+// pollEvents answers one long-poll request
+func (s *Server) pollEvents(ctx context.Context, log *eventlog.Log, req types.HTTPRequest) (types.HTTPResponse, error) {
+	if out, err := responseFormat(req.Headers["Accept"], FormatJSON); err != nil {
+		return types.HTTPResponse{}, err
+	} else if out != FormatJSON {
+		return types.HTTPResponse{}, errors.NotAcceptableError(errors.CodeNotAcceptable, "events are only available as JSON")
+	}
+
+	limit := DefaultEventsLimit
+	if param := req.Query["limit"]; param != "" {
+		n, err := strconv.Atoi(param)
+		if err != nil || n <= 0 {
+			return types.HTTPResponse{}, errors.ValidationError(errors.CodeInvalidValue, "limit must be a positive integer")
+		}
+		limit = min(n, MaxEventsLimit)
+	}
+
+	wait := s.cfg.LongPollWait
+	if param := req.Query["wait"]; param != "" {
+		d, err := time.ParseDuration(param)
+		if err != nil || d < 0 {
+			return types.HTTPResponse{}, errors.ValidationError(errors.CodeInvalidValue, "wait must be a non-negative duration such as 10s")
+		}
+		wait = d
+	}
+	if s.cfg.LongPollMaxWait > 0 {
+		wait = min(wait, s.cfg.LongPollMaxWait)
+	}
+
+	var topics []string
+	if param := req.Query["topics"]; param != "" {
+		topics = strings.Split(param, ",")
+	}
+
+	var (
+		page eventlog.Page
+		err  error
+	)
+	if wait == 0 {
+		page, err = log.Read(req.Query["cursor"], topics, limit)
+	} else {
+		ctx, cancel := context.WithTimeout(ctx, wait)
+		defer cancel()
+		// Shutdown ends pending polls instead of waiting them out
+		stop := context.AfterFunc(s.stopping, cancel)
+		defer stop()
+		page, err = log.Wait(ctx, req.Query["cursor"], topics, limit)
+	}
+	if err != nil {
+		return types.HTTPResponse{}, err
+	}
+
+	resp := EventsResponse{Events: make([]EventMessage, len(page.Events)), Cursor: page.Cursor}
+	for i, event := range page.Events {
+		resp.Events[i] = EventMessage{Cursor: event.Cursor(), Topic: event.Topic, Time: event.Time, Data: event.Value}
+	}
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return types.HTTPResponse{}, errors.Wrap(err, errors.ErrorTypeInternal, errors.CodeSerializationError, fmt.Sprintf("failed to encode %d events", len(resp.Events)))
+	}
+
+	r := respond(nethttp.StatusOK, FormatJSON, body)
+	r.Headers["Cache-Control"] = "no-store"
+	return r, nil
+}
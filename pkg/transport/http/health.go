@@ -0,0 +1,21 @@
+package http
+
+import (
+	"context"
+	"errors"
+
+	"go-transport-prac/internal/health"
+)
+
+// WithHealth serves registry at GET /healthz and GET /readyz. A "server"
+// check is added so readiness fails as soon as Shutdown starts draining
+func (s *Server) WithHealth(registry *health.Registry) *Server {
+	registry.Register(health.Func("server", func(context.Context) error {
+		if s.stopping.Err() != nil {
+			return errors.New("server is shutting down")
+		}
+		return nil
+	}))
+	registry.Mount(s.mux)
+	return s
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/flags"
+)
+
+// Runtime flags defined by WithFlags
+const (
+	FlagMaxBodyBytes = "http.max_body_bytes"
+	FlagRateLimit    = "http.rate_limit"
+	FlagRateBurst    = "http.rate_burst"
+	FlagStrictDecode = "http.strict_decode"
+)
+
+// WithFlags reads the request limits from set instead of the configuration,
+// defining them with the configured values, so they can change at runtime
+func (s *Server) WithFlags(set *flags.Set) *Server {
+	for _, err := range []error{
+		set.DefineInt(FlagMaxBodyBytes, s.cfg.MaxBodyBytes, "maximum request body size in bytes, unlimited when 0"),
+		set.DefineInt(FlagRateLimit, s.cfg.RateLimit, "requests per second the server accepts, unlimited when 0"),
+		set.DefineInt(FlagRateBurst, s.cfg.RateBurst, "requests accepted at once under the rate limit"),
+		set.DefineBool(FlagStrictDecode, s.cfg.StrictDecode, "reject request bodies with unknown fields"),
+	} {
+		if err != nil {
+			s.logger.Warn("Failed to define flag", zap.Error(err))
+		}
+	}
+	s.flags = set
+	return s
+}
+
+func (s *Server) maxBodyBytes() int64 {
+	if s.flags != nil {
+		return s.flags.Int(FlagMaxBodyBytes)
+	}
+	return s.cfg.MaxBodyBytes
+}
+
+func (s *Server) strictDecode() bool {
+	if s.flags != nil {
+		return s.flags.Bool(FlagStrictDecode)
+	}
+	return s.cfg.StrictDecode
+}
+
+// allow reports whether the rate limit admits another request
+func (s *Server) allow() bool {
+	rate, burst := s.cfg.RateLimit, s.cfg.RateBurst
+	if s.flags != nil {
+		rate, burst = s.flags.Int(FlagRateLimit), s.flags.Int(FlagRateBurst)
+	}
+	ok, _ := s.limiter.Take(s.clock.Now(), rate, burst)
+	return ok
+}
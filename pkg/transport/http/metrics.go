@@ -0,0 +1,27 @@
+package http
+
+import (
+	"strconv"
+	"time"
+
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/metrics"
+)
+
+// WithMetrics serves collector's metrics at GET /metrics and records the count
+// and latency of every request, labelled with method, route and status
+func (s *Server) WithMetrics(collector *metrics.Collector) *Server {
+	s.metrics = collector
+	s.mux.Handle("GET /metrics", collector.Handler())
+	return s
+}
+
+// observeRequest records one handled request when metrics are enabled
+func (s *Server) observeRequest(h types.HTTPHandler, status int, elapsed time.Duration) {
+	if s.metrics == nil {
+		return
+	}
+	tags := map[string]string{"method": h.Method(), "route": h.Path(), "status": strconv.Itoa(status)}
+	s.metrics.Counter("http_requests_total", tags, 1)
+	s.metrics.Timer("http_request_duration_seconds", tags, elapsed)
+}
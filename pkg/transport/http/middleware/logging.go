@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/logger"
+)
+
+// AccessLog logs every request through Logger.LogHTTPRequest with its status,
+// duration, response size and remote address, tagged with the request ID
+// when RequestID runs before it
+func AccessLog(log *logger.Logger) Middleware {
+	if log == nil {
+		log = logger.Global()
+	}
+	return func(next nethttp.Handler) nethttp.Handler {
+		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			start := time.Now()
+			rec := recorder(w)
+			// Logged even when a panic passes through, with the status
+			// written so far
+			defer func() {
+				status := rec.status
+				if status == 0 {
+					status = nethttp.StatusOK
+				}
+				LoggerFrom(r.Context(), log).LogHTTPRequest(r.Method, r.URL.Path, status, time.Since(start).String(),
+					zap.Int64("bytes", rec.bytes),
+					zap.String("remote_addr", r.RemoteAddr),
+				)
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Package middleware provides net/http middleware for request IDs, access
+// logs, panic recovery and rate limiting, and Chain to compose them. Errors
+// are answered with the same AppError JSON as the HTTP transport's handlers.
+package middleware
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/internal/types"
+)
+
+// Middleware wraps a handler with behaviour of its own
+type Middleware func(nethttp.Handler) nethttp.Handler
+
+// Chain returns a middleware applying mws in order: the first one sees a
+// request first and its response last. Nil middleware are skipped
+func Chain(mws ...Middleware) Middleware {
+	return func(h nethttp.Handler) nethttp.Handler {
+		for i := len(mws) - 1; i >= 0; i-- {
+			if mws[i] != nil {
+				h = mws[i](h)
+			}
+		}
+		return h
+	}
+}
+
+// Default returns request IDs, access logs and panic recovery, in that order,
+// so panics are logged with their request's ID and status
+func Default(log *logger.Logger) Middleware {
+	return Chain(RequestID(), AccessLog(log), Recover(log))
+}
+
+// WriteError renders err as a JSON APIResponse with its mapped status code
+func WriteError(w nethttp.ResponseWriter, err *errors.AppError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(err.HTTPStatusCode())
+
+	errorResponse := types.APIResponse[interface{}]{
+		Success: false,
+		Error: &types.APIError{
+			Code:    err.Code,
+			Message: err.Message,
+			Details: err.Details,
+			Fields:  err.Fields,
+		},
+	}
+
+	json.NewEncoder(w).Encode(errorResponse)
+}
+
+// responseRecorder records the status and size of a response passing through
+type responseRecorder struct {
+	nethttp.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = nethttp.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Flush passes flushes on, so streamed responses still stream
+func (r *responseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(nethttp.Flusher); ok {
+		if r.status == 0 {
+			r.status = nethttp.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (r *responseRecorder) Unwrap() nethttp.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// recorder returns w as a responseRecorder, wrapping it unless an outer
+// middleware already did
+func recorder(w nethttp.ResponseWriter) *responseRecorder {
+	if rec, ok := w.(*responseRecorder); ok {
+		return rec
+	}
+	return &responseRecorder{ResponseWriter: w}
+}
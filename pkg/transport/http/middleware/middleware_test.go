@@ -0,0 +1,239 @@
+package middleware
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/internal/types"
+)
+
+func observedLogger() (*logger.Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	return &logger.Logger{Logger: zap.New(core)}, logs
+}
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) *types.APIError {
+	t.Helper()
+	var resp types.APIResponse[interface{}]
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode error response %q: %v", rec.Body.String(), err)
+	}
+	if resp.Success || resp.Error == nil {
+		t.Fatalf("Expected an error response, got %s", rec.Body.String())
+	}
+	return resp.Error
+}
+
+func TestChainOrder(t *testing.T) {
+	var calls []string
+	mark := func(name string) Middleware {
+		return func(next nethttp.Handler) nethttp.Handler {
+			return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+				calls = append(calls, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	h := Chain(mark("first"), nil, mark("second"))(nethttp.HandlerFunc(func(nethttp.ResponseWriter, *nethttp.Request) {
+		calls = append(calls, "handler")
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if strings.Join(calls, ",") != "first,second,handler" {
+		t.Errorf("Expected first,second,handler, got %v", calls)
+	}
+
+	t.Log("✓ Middleware run in the order they are chained")
+}
+
+func TestRequestID(t *testing.T) {
+	log, logs := observedLogger()
+	var seen string
+	h := RequestID()(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		seen = RequestIDFrom(r.Context())
+		if r.Header.Get(RequestIDHeader) != seen {
+			t.Errorf("Expected the request header to carry %q, got %q", seen, r.Header.Get(RequestIDHeader))
+		}
+		LoggerFrom(r.Context(), log).Info("handled")
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if len(seen) != 32 || rec.Header().Get(RequestIDHeader) != seen {
+		t.Errorf("Expected a generated ID echoed in the response, got %q and %q", seen, rec.Header().Get(RequestIDHeader))
+	}
+	if id := logs.All()[0].ContextMap()["request_id"]; id != seen {
+		t.Errorf("Expected the log to carry request_id %q, got %v", seen, id)
+	}
+
+	for header, kept := range map[string]bool{
+		"client-abc-123":             true,
+		"forged\nline":               false,
+		strings.Repeat("x", 129):     false,
+		strings.Repeat("y", 128):     true,
+		"with space":                 false,
+		"f81d4fae-7dec-11d0-a765-00": true,
+	} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(RequestIDHeader, header)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		if (seen == header) != kept {
+			t.Errorf("Expected client ID %q kept=%v, got %q", header, kept, seen)
+		}
+	}
+
+	t.Log("✓ Requests carry a sane ID into context, headers and logs")
+}
+
+func TestAccessLog(t *testing.T) {
+	log, logs := observedLogger()
+	h := Chain(RequestID(), AccessLog(log))(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.WriteHeader(nethttp.StatusCreated)
+		w.Write([]byte("hello"))
+	}))
+
+	req := httptest.NewRequest("POST", "/users", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logs.FilterMessage("HTTP request").All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 access log, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["method"] != "POST" || fields["path"] != "/users" || fields["status_code"] != int64(201) ||
+		fields["bytes"] != int64(5) || fields["request_id"] != "req-1" {
+		t.Errorf("Unexpected access log fields %v", fields)
+	}
+
+	t.Log("✓ Access logs record status, size and request ID")
+}
+
+func TestRecover(t *testing.T) {
+	log, logs := observedLogger()
+	h := Default(log)(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		switch r.URL.Path {
+		case "/boom":
+			panic("secret detail")
+		case "/app":
+			panic(errors.NotFoundError(errors.CodeNotFound, "no such user"))
+		case "/late":
+			w.WriteHeader(nethttp.StatusAccepted)
+			panic("after the header")
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/boom", nil))
+	if rec.Code != nethttp.StatusInternalServerError {
+		t.Errorf("Expected 500, got %d", rec.Code)
+	}
+	if apiErr := decodeError(t, rec); apiErr.Code != CodePanic || strings.Contains(rec.Body.String(), "secret") {
+		t.Errorf("Expected a generic panic error, got %s", rec.Body.String())
+	}
+	panics := logs.FilterMessage("HTTP handler panicked").All()
+	if len(panics) != 1 || panics[0].ContextMap()["request_id"] == nil || panics[0].ContextMap()["stack"] == nil {
+		t.Fatalf("Expected the panic logged with its request ID and stack, got %v", panics)
+	}
+	if access := logs.FilterMessage("HTTP request").All(); len(access) != 1 || access[0].ContextMap()["status_code"] != int64(500) {
+		t.Errorf("Expected the access log to record 500, got %v", access)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/app", nil))
+	if rec.Code != nethttp.StatusNotFound || decodeError(t, rec).Code != errors.CodeNotFound {
+		t.Errorf("Expected an AppError panic to keep its status, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/late", nil))
+	if rec.Code != nethttp.StatusAccepted || rec.Body.Len() != 0 {
+		t.Errorf("Expected a started response to be left alone, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	func() {
+		defer func() {
+			if p := recover(); p != nethttp.ErrAbortHandler {
+				t.Errorf("Expected ErrAbortHandler to be passed on, got %v", p)
+			}
+		}()
+		Recover(log)(nethttp.HandlerFunc(func(nethttp.ResponseWriter, *nethttp.Request) {
+			panic(nethttp.ErrAbortHandler)
+		})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	}()
+
+	t.Log("✓ Panics are answered with AppError JSON and logged")
+}
+
+func TestTokenBucket(t *testing.T) {
+	var b TokenBucket
+	now := time.Unix(1700000000, 0)
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := b.Take(now, 2, 3); !ok {
+			t.Fatalf("Expected request %d within the burst to pass", i)
+		}
+	}
+	ok, wait := b.Take(now, 2, 3)
+	if ok || wait != 500*time.Millisecond {
+		t.Errorf("Expected an empty bucket to wait 500ms, got %v %v", ok, wait)
+	}
+	if ok, _ := b.Take(now.Add(500*time.Millisecond), 2, 3); !ok {
+		t.Error("Expected a token after waiting")
+	}
+	if ok, _ := b.Take(now, 0, 0); !ok {
+		t.Error("Expected rate 0 to admit everything")
+	}
+
+	t.Log("✓ Token buckets refill at their rate up to their burst")
+}
+
+func TestRateLimit(t *testing.T) {
+	clock := testutil.NewDefaultFakeClock()
+	limiter := NewLimiter(1, 2).WithClock(clock).PerKey(ClientIP)
+	h := limiter.Middleware()(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {}))
+
+	request := func(addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = addr
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := request("10.0.0.1:1000"); rec.Code != nethttp.StatusOK {
+			t.Fatalf("Expected request %d to pass, got %d", i, rec.Code)
+		}
+	}
+	rec := request("10.0.0.1:2000")
+	if rec.Code != nethttp.StatusTooManyRequests || rec.Header().Get("Retry-After") != "1" {
+		t.Errorf("Expected 429 with Retry-After 1, got %d %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if apiErr := decodeError(t, rec); apiErr.Code != errors.CodeRateLimit {
+		t.Errorf("Expected %s, got %s", errors.CodeRateLimit, apiErr.Code)
+	}
+	if rec := request("10.0.0.2:1000"); rec.Code != nethttp.StatusOK {
+		t.Errorf("Expected another client to have its own bucket, got %d", rec.Code)
+	}
+
+	// Refilled buckets are dropped on the next request
+	clock.Advance(5 * time.Second)
+	request("10.0.0.3:1000")
+	if limiter.Len() != 1 {
+		t.Errorf("Expected idle buckets to be pruned, got %d", limiter.Len())
+	}
+
+	t.Log("✓ Rate limits answer 429 per client and forget idle clients")
+}
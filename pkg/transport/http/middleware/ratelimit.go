@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	nethttp "net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/types"
+)
+
+// TokenBucket is a token bucket whose rate and burst are passed on every
+// take, so they can change at runtime. The zero value is a full bucket
+type TokenBucket struct {
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// Take takes a token at now from a bucket refilled with rate tokens per
+// second and holding at most burst, which defaults to rate. When the bucket
+// is empty it returns false and how long until a token is back. A rate of 0
+// or less admits everything
+func (b *TokenBucket) Take(now time.Time, rate, burst int64) (bool, time.Duration) {
+	if rate <= 0 {
+		return true, 0
+	}
+	if burst <= 0 {
+		burst = rate
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.last.IsZero() {
+		b.tokens = float64(burst)
+	} else if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * float64(rate)
+	}
+	b.tokens = math.Min(b.tokens, float64(burst))
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / float64(rate) * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// full reports whether the bucket would be full at now, when it is no
+// different from a new one
+func (b *TokenBucket) full(now time.Time, rate, burst int64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.last.IsZero() || b.tokens+now.Sub(b.last).Seconds()*float64(rate) >= float64(burst)
+}
+
+// Limiter rate limits requests, either all together or per key such as the
+// client address
+type Limiter struct {
+	rate  int64
+	burst int64
+	clock types.Clock
+	key   func(*nethttp.Request) string
+
+	mu        sync.Mutex
+	global    TokenBucket
+	buckets   map[string]*TokenBucket
+	lastPrune time.Time
+}
+
+// NewLimiter returns a limiter admitting rate requests per second, and burst
+// at once, across all requests
+func NewLimiter(rate, burst int64) *Limiter {
+	if burst <= 0 {
+		burst = rate
+	}
+	return &Limiter{rate: rate, burst: burst, clock: types.SystemClock{}}
+}
+
+// WithClock sets the clock buckets are refilled by
+func (l *Limiter) WithClock(clock types.Clock) *Limiter {
+	l.clock = types.ClockOrSystem(clock)
+	return l
+}
+
+// PerKey gives every key its own bucket, e.g. PerKey(ClientIP) limits each
+// client separately. Buckets of keys idle long enough to refill are dropped
+func (l *Limiter) PerKey(key func(*nethttp.Request) string) *Limiter {
+	l.key = key
+	l.buckets = make(map[string]*TokenBucket)
+	return l
+}
+
+// Allow takes a token for r, returning false and how long until the next
+// token when r is over the limit
+func (l *Limiter) Allow(r *nethttp.Request) (bool, time.Duration) {
+	now := l.clock.Now()
+	return l.bucket(r, now).Take(now, l.rate, l.burst)
+}
+
+// Len returns the number of per-key buckets held
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
+
+func (l *Limiter) bucket(r *nethttp.Request, now time.Time) *TokenBucket {
+	if l.key == nil {
+		return &l.global
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prune(now)
+	key := l.key(r)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &TokenBucket{}
+		l.buckets[key] = b
+	}
+	return b
+}
+
+// prune drops full buckets, at most once per refill period. Must be called
+// with l.mu held
+func (l *Limiter) prune(now time.Time) {
+	if l.rate <= 0 {
+		return
+	}
+	refill := time.Duration(float64(l.burst) / float64(l.rate) * float64(time.Second))
+	if now.Sub(l.lastPrune) < refill {
+		return
+	}
+	l.lastPrune = now
+	for key, b := range l.buckets {
+		if b.full(now, l.rate, l.burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Middleware answers requests over the limit with a 429 rate limit AppError
+// and a Retry-After header
+func (l *Limiter) Middleware() Middleware {
+	return func(next nethttp.Handler) nethttp.Handler {
+		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			if ok, wait := l.Allow(r); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				WriteError(w, errors.RateLimitError(errors.CodeRateLimit, "rate limit exceeded"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RateLimit admits rate requests per second, and burst at once, across all
+// requests. See Limiter for per-client limits
+func RateLimit(rate, burst int64) Middleware {
+	return NewLimiter(rate, burst).Middleware()
+}
+
+// ClientIP returns the host of the request's remote address
+func ClientIP(r *nethttp.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	nethttp "net/http"
+	"runtime/debug"
+
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/logger"
+)
+
+// CodePanic is the application error code of a recovered panic
+const CodePanic = "PANIC"
+
+// Recover turns a panicking handler into an error response instead of a
+// dropped connection. The panic is logged with its stack and answered with a
+// 500 AppError, or with the *errors.AppError it panicked with; the panic
+// value itself never reaches the client. http.ErrAbortHandler is passed on,
+// as net/http uses it to abort a response on purpose
+func Recover(log *logger.Logger) Middleware {
+	if log == nil {
+		log = logger.Global()
+	}
+	return func(next nethttp.Handler) nethttp.Handler {
+		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			rec := recorder(w)
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+				if p == nethttp.ErrAbortHandler {
+					panic(p)
+				}
+				LoggerFrom(r.Context(), log).Error("HTTP handler panicked",
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.Any("panic", p),
+					zap.ByteString("stack", debug.Stack()),
+				)
+
+				// A response already under way cannot be replaced
+				if rec.status != 0 {
+					return
+				}
+				appErr, ok := p.(*errors.AppError)
+				if !ok {
+					appErr = errors.InternalError(CodePanic, "internal server error")
+				}
+				WriteError(rec, appErr)
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
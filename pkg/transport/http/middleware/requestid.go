@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	nethttp "net/http"
+
+	"go-transport-prac/internal/logger"
+)
+
+// RequestIDHeader carries the request ID in requests and responses
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen bounds request IDs taken from clients
+const maxRequestIDLen = 128
+
+type requestIDKey struct{}
+
+// RequestID gives every request an ID: the client's X-Request-ID when it is
+// a sane one, a random one otherwise. The ID is echoed in the response, set
+// on the request header for handlers reading it there, and attached to the
+// context for RequestIDFrom and LoggerFrom
+func RequestID() Middleware {
+	return func(next nethttp.Handler) nethttp.Handler {
+		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = NewRequestID()
+				r.Header.Set(RequestIDHeader, id)
+			}
+			w.Header().Set(RequestIDHeader, id)
+			next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+		})
+	}
+}
+
+// NewRequestID returns a random 16-byte hex request ID
+func NewRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WithRequestID returns ctx carrying the request ID id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFrom returns the request ID attached to ctx, empty if none
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// LoggerFrom returns log with the request ID of ctx, or log itself when ctx
+// has none
+func LoggerFrom(ctx context.Context, log *logger.Logger) *logger.Logger {
+	if id := RequestIDFrom(ctx); id != "" {
+		return log.WithRequestID(id)
+	}
+	return log
+}
+
+// validRequestID accepts short IDs of printable ASCII, so client IDs cannot
+// forge log lines or bloat them
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
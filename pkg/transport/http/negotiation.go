@@ -0,0 +1,34 @@
+package http
+
+import (
+	"go-transport-prac/pkg/transport/codec"
+)
+
+// Format is a wire format an endpoint can read or write, named by the MIME
+// type of its codec
+type Format string
+
+const (
+	FormatJSON     Format = codec.JSON
+	FormatAvro     Format = codec.Avro
+	FormatProtobuf Format = codec.Protobuf
+	FormatMsgPack  Format = codec.MsgPack
+)
+
+// formatTypes lists the MIME types endpoints speak; aliases such as
+// application/avro resolve through the codec package
+var formatTypes = []string{string(FormatJSON), string(FormatAvro), string(FormatProtobuf), string(FormatMsgPack)}
+
+// requestFormat selects the format of a request body from its Content-Type, defaulting to JSON
+func requestFormat(contentType string) (Format, error) {
+	mediaType, err := codec.ContentType(contentType, formatTypes, string(FormatJSON))
+	return Format(mediaType), err
+}
+
+// responseFormat selects the response format from an Accept header.
+// Ranges are tried by descending quality, then by position; an empty
+// header or a wildcard falls back to the request format.
+func responseFormat(accept string, fallback Format) (Format, error) {
+	mediaType, err := codec.Negotiate(accept, formatTypes, string(fallback))
+	return Format(mediaType), err
+}
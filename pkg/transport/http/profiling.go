@@ -0,0 +1,18 @@
+package http
+
+import (
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/config"
+	"go-transport-prac/internal/profiling"
+)
+
+// WithProfiling serves the pprof endpoints under /debug/pprof/ when
+// cfg.EnableProfiling is set. They expose stacks and command lines, so keep
+// them off in production
+func (s *Server) WithProfiling(cfg config.DevelopmentConfig) *Server {
+	if profiling.Mount(s.mux, cfg) {
+		s.logger.Warn("Profiling endpoints enabled", zap.String("prefix", profiling.Prefix))
+	}
+	return s
+}
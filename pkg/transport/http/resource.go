@@ -0,0 +1,305 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	hamba "github.com/hamba/avro/v2"
+	"google.golang.org/protobuf/proto"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/protobuf"
+	"go-transport-prac/pkg/transport/codec"
+)
+
+// resource serves one entity type, storing it in memory and encoding it in
+// any supported format. T is the Avro model used as the canonical form.
+type resource[T any] struct {
+	name string
+
+	avroManager  *avro.Manager
+	protoManager *protobuf.Manager
+	schema       hamba.Schema
+	listSchema   hamba.Schema
+
+	// codecs encodes the formats without a case of their own
+	codecs *codec.Registry
+
+	toProto     func(T) proto.Message
+	fromProto   func(proto.Message) T
+	newProto    func() proto.Message
+	listToProto func([]T) proto.Message
+
+	id     func(*T) *int64
+	stamp  func(*T, time.Time)
+	now    func() time.Time
+	strict func() bool
+
+	mu     sync.RWMutex
+	items  map[int64]T
+	nextID int64
+}
+
+// decode reads a single entity from body in the given format. In strict
+// mode, JSON and Protobuf fields the model does not know are rejected
+func (r *resource[T]) decode(format Format, body []byte) (T, error) {
+	var v T
+
+	strict := r.strict != nil && r.strict()
+	var err error
+	switch format {
+	case FormatJSON:
+		if !strict {
+			err = json.Unmarshal(body, &v)
+			break
+		}
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.DisallowUnknownFields()
+		if err = decoder.Decode(&v); err == nil && decoder.More() {
+			err = fmt.Errorf("unexpected data after the %s", r.name)
+		}
+	case FormatAvro:
+		err = r.avroManager.DeserializeStruct(r.schema, body, &v)
+	case FormatProtobuf:
+		msg := r.newProto()
+		if err = r.protoManager.Deserialize(body, msg); err == nil {
+			if strict && len(msg.ProtoReflect().GetUnknown()) > 0 {
+				err = fmt.Errorf("unknown fields in %s", r.name)
+			} else {
+				v = r.fromProto(msg)
+			}
+		}
+	default:
+		err = r.codec(format, func(c types.Serializer) error { return c.Deserialize(body, &v) })
+	}
+
+	if err != nil {
+		appErr := errors.Wrapf(err, errors.ErrorTypeBadRequest, errors.CodeDeserializationError, "failed to decode %s", r.name)
+		appErr.Details = err.Error()
+		return v, appErr
+	}
+	return v, nil
+}
+
+// encode writes a single entity in the given format
+func (r *resource[T]) encode(format Format, v T) ([]byte, error) {
+	var data []byte
+
+	var err error
+	switch format {
+	case FormatJSON:
+		data, err = json.Marshal(v)
+	case FormatAvro:
+		data, err = r.avroManager.SerializeStruct(r.schema, v)
+	case FormatProtobuf:
+		data, err = r.protoManager.Serialize(r.toProto(v))
+	default:
+		err = r.codec(format, func(c types.Serializer) (err error) { data, err = c.Serialize(v); return err })
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.ErrorTypeInternal, errors.CodeSerializationError, "failed to encode %s", r.name)
+	}
+	return data, nil
+}
+
+// encodeList writes a list of entities: a JSON array, an Avro array or the
+// protobuf list response message
+func (r *resource[T]) encodeList(format Format, list []T) ([]byte, error) {
+	var data []byte
+
+	var err error
+	switch format {
+	case FormatJSON:
+		data, err = json.Marshal(list)
+	case FormatAvro:
+		data, err = r.avroManager.SerializeStruct(r.listSchema, list)
+	case FormatProtobuf:
+		data, err = r.protoManager.Serialize(r.listToProto(list))
+	default:
+		err = r.codec(format, func(c types.Serializer) (err error) { data, err = c.Serialize(list); return err })
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.ErrorTypeInternal, errors.CodeSerializationError, "failed to encode %s list", r.name)
+	}
+	return data, nil
+}
+
+// codec runs use with the registered codec of format
+func (r *resource[T]) codec(format Format, use func(types.Serializer) error) error {
+	c, ok := r.codecs.Get(string(format))
+	if !ok {
+		return fmt.Errorf("unknown format %s", format)
+	}
+	return use(c)
+}
+
+// formats negotiates the request body and response formats
+func formats(req types.HTTPRequest) (Format, Format, error) {
+	in, err := requestFormat(req.Headers["Content-Type"])
+	if err != nil {
+		return "", "", err
+	}
+	out, err := responseFormat(req.Headers["Accept"], in)
+	if err != nil {
+		return "", "", err
+	}
+	return in, out, nil
+}
+
+// respond builds a response carrying body in format
+func respond(status int, format Format, body []byte) types.HTTPResponse {
+	return types.HTTPResponse{
+		StatusCode: status,
+		Headers:    map[string]string{"Content-Type": string(format)},
+		Body:       body,
+	}
+}
+
+// create stores the decoded entity, assigning an ID and timestamps when missing
+func (r *resource[T]) create(ctx context.Context, req types.HTTPRequest) (types.HTTPResponse, error) {
+	in, out, err := formats(req)
+	if err != nil {
+		return types.HTTPResponse{}, err
+	}
+
+	v, err := r.decode(in, req.Body)
+	if err != nil {
+		return types.HTTPResponse{}, err
+	}
+
+	r.mu.Lock()
+	id := r.id(&v)
+	if *id == 0 {
+		r.nextID++
+		*id = r.nextID
+	} else if _, exists := r.items[*id]; exists {
+		r.mu.Unlock()
+		return types.HTTPResponse{}, errors.ConflictError(errors.CodeAlreadyExists, fmt.Sprintf("%s %d already exists", r.name, *id))
+	} else if *id > r.nextID {
+		r.nextID = *id
+	}
+	r.stamp(&v, r.now())
+	r.items[*id] = v
+	r.mu.Unlock()
+
+	body, err := r.encode(out, v)
+	if err != nil {
+		return types.HTTPResponse{}, err
+	}
+	return respond(nethttp.StatusCreated, out, body), nil
+}
+
+// get returns the entity named by the id path parameter
+func (r *resource[T]) get(ctx context.Context, req types.HTTPRequest) (types.HTTPResponse, error) {
+	out, err := responseFormat(req.Headers["Accept"], FormatJSON)
+	if err != nil {
+		return types.HTTPResponse{}, err
+	}
+
+	id, err := strconv.ParseInt(req.PathParams["id"], 10, 64)
+	if err != nil || id <= 0 {
+		return types.HTTPResponse{}, errors.ValidationError(errors.CodeInvalidValue, "id must be a positive integer")
+	}
+
+	r.mu.RLock()
+	v, ok := r.items[id]
+	r.mu.RUnlock()
+	if !ok {
+		return types.HTTPResponse{}, errors.NotFoundError(errors.CodeNotFound, fmt.Sprintf("%s %d not found", r.name, id))
+	}
+
+	body, err := r.encode(out, v)
+	if err != nil {
+		return types.HTTPResponse{}, err
+	}
+	return respond(nethttp.StatusOK, out, body), nil
+}
+
+// list returns every stored entity ordered by ID
+func (r *resource[T]) list(ctx context.Context, req types.HTTPRequest) (types.HTTPResponse, error) {
+	out, err := responseFormat(req.Headers["Accept"], FormatJSON)
+	if err != nil {
+		return types.HTTPResponse{}, err
+	}
+
+	r.mu.RLock()
+	ids := make([]int64, 0, len(r.items))
+	for id := range r.items {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	list := make([]T, len(ids))
+	for i, id := range ids {
+		list[i] = r.items[id]
+	}
+	r.mu.RUnlock()
+
+	body, err := r.encodeList(out, list)
+	if err != nil {
+		return types.HTTPResponse{}, err
+	}
+	return respond(nethttp.StatusOK, out, body), nil
+}
+
+// convert transcodes the body from its Content-Type into the accepted format without storing it
+func (r *resource[T]) convert(ctx context.Context, req types.HTTPRequest) (types.HTTPResponse, error) {
+	in, out, err := formats(req)
+	if err != nil {
+		return types.HTTPResponse{}, err
+	}
+
+	v, err := r.decode(in, req.Body)
+	if err != nil {
+		return types.HTTPResponse{}, err
+	}
+
+	body, err := r.encode(out, v)
+	if err != nil {
+		return types.HTTPResponse{}, err
+	}
+	return respond(nethttp.StatusOK, out, body), nil
+}
+
+// handlers returns the endpoints serving this resource under /v1/{name}s
+func (r *resource[T]) handlers() []types.HTTPHandler {
+	base := "/v1/" + r.name + "s"
+	return []types.HTTPHandler{
+		handler{method: nethttp.MethodPost, path: base, handle: r.create},
+		handler{method: nethttp.MethodGet, path: base, handle: r.list},
+		handler{method: nethttp.MethodGet, path: base + "/{id}", handle: r.get},
+		handler{method: nethttp.MethodPost, path: base + "/convert", handle: r.convert},
+	}
+}
+
+// handler adapts a function to types.HTTPHandler
+type handler struct {
+	method string
+	path   string
+	handle func(ctx context.Context, req types.HTTPRequest) (types.HTTPResponse, error)
+}
+
+// Handle processes an HTTP request
+func (h handler) Handle(ctx context.Context, req types.HTTPRequest) (types.HTTPResponse, error) {
+	return h.handle(ctx, req)
+}
+
+// Method returns the HTTP method this handler supports
+func (h handler) Method() string {
+	return h.method
+}
+
+// Path returns the URL path pattern this handler supports
+func (h handler) Path() string {
+	return h.path
+}
@@ -0,0 +1,330 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	nethttp "net/http"
+	"strings"
+	"time"
+
+	hamba "github.com/hamba/avro/v2"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/flags"
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/protobuf"
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+	"go-transport-prac/pkg/transport/codec"
+	"go-transport-prac/pkg/transport/http/middleware"
+	"go-transport-prac/pkg/transport/internal/convert"
+)
+
+// Server serves User, Product and Order endpoints in JSON, Avro or Protobuf
+type Server struct {
+	cfg      Config
+	logger   *logger.Logger
+	clock    types.Clock
+	handlers []types.HTTPHandler
+	mux      *nethttp.ServeMux
+	server   *nethttp.Server
+
+	// handler is mux wrapped in middleware, see Use
+	middleware []middleware.Middleware
+	handler    nethttp.Handler
+
+	// flags, when set, overrides the configured limits at runtime
+	flags   *flags.Set
+	limiter middleware.TokenBucket
+
+	// metrics, when set, records every request
+	metrics types.MetricsCollector
+
+	// stopping is cancelled by Shutdown to end pending long polls
+	stopping context.Context
+	stop     context.CancelFunc
+}
+
+// NewServer creates a server with the user, product and order endpoints registered
+func NewServer(cfg Config, log *logger.Logger) (*Server, error) {
+	if log == nil {
+		log = logger.Global()
+	}
+
+	avroManager, err := avro.NewManager("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avro manager: %w", err)
+	}
+	protoManager := protobuf.NewManager()
+	codecs, err := codec.Default()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create codecs: %w", err)
+	}
+
+	s := &Server{
+		cfg:    cfg,
+		logger: log.WithComponent("http"),
+		clock:  types.SystemClock{},
+		mux:    nethttp.NewServeMux(),
+	}
+	s.stopping, s.stop = context.WithCancel(context.Background())
+	now := func() time.Time { return s.clock.Now() }
+	strict := func() bool { return s.strictDecode() }
+
+	users := &resource[avro.User]{
+		name:         "user",
+		avroManager:  avroManager,
+		protoManager: protoManager,
+		codecs:       codecs,
+		schema:       avroManager.GetUserSchema(),
+		listSchema:   hamba.NewArraySchema(avroManager.GetUserSchema()),
+		toProto:      func(u avro.User) proto.Message { return convert.UserToProto(u) },
+		fromProto:    func(msg proto.Message) avro.User { return convert.UserFromProto(msg.(*user.User)) },
+		newProto:     func() proto.Message { return &user.User{} },
+		listToProto: func(list []avro.User) proto.Message {
+			resp := &user.UsersResponse{TotalCount: int32(len(list))}
+			for _, u := range list {
+				resp.Users = append(resp.Users, convert.UserToProto(u))
+			}
+			return resp
+		},
+		id:     func(u *avro.User) *int64 { return &u.ID },
+		stamp:  func(u *avro.User, t time.Time) { stampTimes(&u.CreatedAt, &u.UpdatedAt, t) },
+		now:    now,
+		strict: strict,
+		items:  make(map[int64]avro.User),
+	}
+
+	products := &resource[avro.Product]{
+		name:         "product",
+		avroManager:  avroManager,
+		protoManager: protoManager,
+		codecs:       codecs,
+		schema:       avroManager.GetProductSchema(),
+		listSchema:   hamba.NewArraySchema(avroManager.GetProductSchema()),
+		toProto:      func(p avro.Product) proto.Message { return convert.ProductToProto(p) },
+		fromProto:    func(msg proto.Message) avro.Product { return convert.ProductFromProto(msg.(*product.Product)) },
+		newProto:     func() proto.Message { return &product.Product{} },
+		listToProto: func(list []avro.Product) proto.Message {
+			resp := &product.ProductsResponse{TotalCount: int32(len(list))}
+			for _, p := range list {
+				resp.Products = append(resp.Products, convert.ProductToProto(p))
+			}
+			return resp
+		},
+		id:     func(p *avro.Product) *int64 { return &p.ID },
+		stamp:  func(p *avro.Product, t time.Time) { stampTimes(&p.CreatedAt, &p.UpdatedAt, t) },
+		now:    now,
+		strict: strict,
+		items:  make(map[int64]avro.Product),
+	}
+
+	orders := &resource[avro.Order]{
+		name:         "order",
+		avroManager:  avroManager,
+		protoManager: protoManager,
+		codecs:       codecs,
+		schema:       avroManager.GetOrderSchema(),
+		listSchema:   hamba.NewArraySchema(avroManager.GetOrderSchema()),
+		toProto:      func(o avro.Order) proto.Message { return convert.OrderToProto(o) },
+		fromProto:    func(msg proto.Message) avro.Order { return convert.OrderFromProto(msg.(*order.Order)) },
+		newProto:     func() proto.Message { return &order.Order{} },
+		listToProto: func(list []avro.Order) proto.Message {
+			resp := &order.OrdersResponse{TotalCount: int32(len(list))}
+			for _, o := range list {
+				resp.Orders = append(resp.Orders, convert.OrderToProto(o))
+			}
+			return resp
+		},
+		id:     func(o *avro.Order) *int64 { return &o.ID },
+		stamp:  func(o *avro.Order, t time.Time) { stampTimes(&o.CreatedAt, &o.UpdatedAt, t) },
+		now:    now,
+		strict: strict,
+		items:  make(map[int64]avro.Order),
+	}
+
+	for _, handlers := range [][]types.HTTPHandler{users.handlers(), products.handlers(), orders.handlers()} {
+		for _, h := range handlers {
+			s.Handle(h)
+		}
+	}
+
+	s.server = &nethttp.Server{
+		Addr:         cfg.Addr,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+	}
+	s.Use(middleware.RequestID(), middleware.Recover(s.logger))
+
+	return s, nil
+}
+
+// Use wraps every route of the server in mws, the first one outermost and
+// all of them inside any added before. Every server already starts with
+// request IDs and panic recovery, so added middleware see the request ID;
+// access logs and rate limits are applied per route by the server itself
+func (s *Server) Use(mws ...middleware.Middleware) *Server {
+	s.middleware = append(s.middleware, mws...)
+	s.handler = middleware.Chain(s.middleware...)(s.mux)
+	s.server.Handler = s.handler
+	return s
+}
+
+// WithClock sets the clock used for generated CreatedAt/UpdatedAt timestamps
+func (s *Server) WithClock(clock types.Clock) *Server {
+	s.clock = types.ClockOrSystem(clock)
+	return s
+}
+
+// stampTimes fills a missing CreatedAt and always refreshes UpdatedAt
+func stampTimes(createdAt, updatedAt *time.Time, now time.Time) {
+	if createdAt.IsZero() {
+		*createdAt = now
+	}
+	*updatedAt = now
+}
+
+// Handle registers an additional handler on the server
+func (s *Server) Handle(h types.HTTPHandler) {
+	s.handlers = append(s.handlers, h)
+	s.mux.Handle(h.Method()+" "+h.Path(), s.adapt(h))
+}
+
+// Handlers returns every registered handler
+func (s *Server) Handlers() []types.HTTPHandler {
+	return s.handlers
+}
+
+// Handler returns the server's root handler, e.g. for httptest
+func (s *Server) Handler() nethttp.Handler {
+	return s.handler
+}
+
+// ListenAndServe listens on the configured address and serves until stopped
+func (s *Server) ListenAndServe() error {
+	lis, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.cfg.Addr, err)
+	}
+	return s.Serve(lis)
+}
+
+// Serve serves HTTP requests on lis until the server is shut down
+func (s *Server) Serve(lis net.Listener) error {
+	s.logger.Info("HTTP server listening", zap.String("addr", lis.Addr().String()))
+
+	var err error
+	if s.cfg.TLSEnabled {
+		err = s.server.ServeTLS(lis, s.cfg.CertFile, s.cfg.KeyFile)
+	} else {
+		err = s.server.Serve(lis)
+	}
+	if err != nil && err != nethttp.ErrServerClosed {
+		return fmt.Errorf("HTTP server failed: %w", err)
+	}
+	return nil
+}
+
+// Shutdown stops accepting new requests and waits for in-flight ones to finish
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.cfg.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.ShutdownTimeout)
+		defer cancel()
+	}
+
+	s.stop()
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.logger.Warn("HTTP server forced to stop", zap.Error(err))
+		return err
+	}
+	s.logger.Info("HTTP server stopped")
+	return nil
+}
+
+// adapt turns a types.HTTPHandler into a net/http handler that logs the request
+// and renders AppErrors with their HTTP status
+func (s *Server) adapt(h types.HTTPHandler) nethttp.Handler {
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		start := time.Now()
+		log := middleware.LoggerFrom(r.Context(), s.logger)
+
+		var resp types.HTTPResponse
+		var err error
+		if !isAdmin(h) && !s.allow() {
+			err = errors.RateLimitError(errors.CodeRateLimit, "rate limit exceeded")
+		} else {
+			resp, err = s.serve(h, w, r)
+		}
+		if err != nil {
+			appErr, ok := errors.AsAppError(err)
+			if !ok {
+				appErr = errors.Wrap(err, errors.ErrorTypeInternal, errors.CodeInternalError, "internal server error")
+			}
+			writeErrorResponse(w, appErr)
+			s.observeRequest(h, appErr.HTTPStatusCode(), time.Since(start))
+			log.LogHTTPRequest(r.Method, r.URL.Path, appErr.HTTPStatusCode(), time.Since(start).String(), zap.Error(err))
+			return
+		}
+
+		for key, value := range resp.Headers {
+			w.Header().Set(key, value)
+		}
+		w.WriteHeader(resp.StatusCode)
+		w.Write(resp.Body)
+		s.observeRequest(h, resp.StatusCode, time.Since(start))
+		log.LogHTTPRequest(r.Method, r.URL.Path, resp.StatusCode, time.Since(start).String())
+	})
+}
+
+// serve converts r into a types.HTTPRequest and runs h
+func (s *Server) serve(h types.HTTPHandler, w nethttp.ResponseWriter, r *nethttp.Request) (types.HTTPResponse, error) {
+	limit := s.cfg.MaxBodyBytes
+	if !isAdmin(h) {
+		limit = s.maxBodyBytes()
+	}
+	if limit > 0 {
+		r.Body = nethttp.MaxBytesReader(w, r.Body, limit)
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return types.HTTPResponse{}, errors.BadRequestError(errors.CodeInvalidInput, "failed to read request body")
+	}
+
+	req := types.HTTPRequest{
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Headers:    make(map[string]string, len(r.Header)),
+		Body:       body,
+		Query:      make(map[string]string),
+		PathParams: make(map[string]string),
+		RequestID:  r.Header.Get("X-Request-ID"),
+	}
+	for key, values := range r.Header {
+		req.Headers[key] = strings.Join(values, ", ")
+	}
+	for key := range r.URL.Query() {
+		req.Query[key] = r.URL.Query().Get(key)
+	}
+	for _, segment := range strings.Split(h.Path(), "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			name := strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}")
+			req.PathParams[name] = r.PathValue(name)
+		}
+	}
+
+	return h.Handle(r.Context(), req)
+}
+
+// writeErrorResponse renders err as a JSON APIResponse with its mapped status code
+func writeErrorResponse(w nethttp.ResponseWriter, err *errors.AppError) {
+	middleware.WriteError(w, err)
+}
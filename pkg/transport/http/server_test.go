@@ -0,0 +1,691 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/proto"
+
+	"go-transport-prac/internal/flags"
+	"go-transport-prac/internal/health"
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/metrics"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/msgpack"
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+	"go-transport-prac/pkg/transport/eventlog"
+	"go-transport-prac/pkg/transport/http/middleware"
+)
+
+// startTestServer serves all endpoints from an httptest server driven by a fake clock
+func startTestServer(t *testing.T) (*httptest.Server, *avro.Manager) {
+	t.Helper()
+
+	server, err := NewServer(DefaultConfig(), nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	server.WithClock(testutil.NewDefaultFakeClock())
+
+	ts := httptest.NewServer(server.Handler())
+	t.Cleanup(ts.Close)
+
+	manager, err := avro.NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create avro manager: %v", err)
+	}
+	return ts, manager
+}
+
+// do sends a request and returns the status code, Content-Type and body
+func do(t *testing.T, method, url, contentType, accept string, body []byte) (int, string, []byte) {
+	t.Helper()
+
+	req, err := nethttp.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := nethttp.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	return resp.StatusCode, resp.Header.Get("Content-Type"), data
+}
+
+func TestUserEndpoints(t *testing.T) {
+	ts, manager := startTestServer(t)
+
+	sample := manager.CreateSampleUsers(1)[0]
+	sample.ID = 0
+	body, _ := json.Marshal(sample)
+
+	status, contentType, data := do(t, "POST", ts.URL+"/v1/users", "application/json", "", body)
+	if status != nethttp.StatusCreated || contentType != string(FormatJSON) {
+		t.Fatalf("Expected 201 JSON, got %d %s: %s", status, contentType, data)
+	}
+	var created avro.User
+	if err := json.Unmarshal(data, &created); err != nil {
+		t.Fatalf("Failed to decode created user: %v", err)
+	}
+	if created.ID != 1 || !created.UpdatedAt.Equal(testutil.DefaultFakeTime) {
+		t.Errorf("Expected ID 1 stamped by fake clock, got %d at %v", created.ID, created.UpdatedAt)
+	}
+
+	// The same user read back as Avro binary
+	status, contentType, data = do(t, "GET", ts.URL+"/v1/users/1", "", "application/avro", nil)
+	if status != nethttp.StatusOK || contentType != string(FormatAvro) {
+		t.Fatalf("Expected 200 Avro, got %d %s", status, contentType)
+	}
+	var fromAvro avro.User
+	if err := manager.DeserializeStruct(manager.GetUserSchema(), data, &fromAvro); err != nil {
+		t.Fatalf("Failed to decode Avro user: %v", err)
+	}
+	if fromAvro.Email != sample.Email || fromAvro.Profile.Address.City != sample.Profile.Address.City {
+		t.Errorf("Avro user mismatch: %+v", fromAvro)
+	}
+
+	// ... and as Protobuf
+	status, contentType, data = do(t, "GET", ts.URL+"/v1/users/1", "", "application/x-protobuf", nil)
+	if status != nethttp.StatusOK || contentType != string(FormatProtobuf) {
+		t.Fatalf("Expected 200 Protobuf, got %d %s", status, contentType)
+	}
+	var fromProto user.User
+	if err := proto.Unmarshal(data, &fromProto); err != nil {
+		t.Fatalf("Failed to decode Protobuf user: %v", err)
+	}
+	if fromProto.Email != sample.Email || fromProto.Status != user.UserStatus_USER_STATUS_ACTIVE {
+		t.Errorf("Protobuf user mismatch: %v", &fromProto)
+	}
+
+	// Creating from a Protobuf body assigns the next ID
+	fromProto.Id = 0
+	fromProto.Email = "proto@example.com"
+	payload, _ := proto.Marshal(&fromProto)
+	status, _, data = do(t, "POST", ts.URL+"/v1/users", "application/x-protobuf", "application/json", payload)
+	if status != nethttp.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", status, data)
+	}
+
+	status, _, data = do(t, "GET", ts.URL+"/v1/users", "", "application/x-protobuf", nil)
+	if status != nethttp.StatusOK {
+		t.Fatalf("Expected 200, got %d", status)
+	}
+	var list user.UsersResponse
+	if err := proto.Unmarshal(data, &list); err != nil {
+		t.Fatalf("Failed to decode user list: %v", err)
+	}
+	if list.TotalCount != 2 || list.Users[1].Email != "proto@example.com" {
+		t.Errorf("Unexpected user list: %v", &list)
+	}
+
+	// ... and as MessagePack, through the codec registry
+	status, contentType, data = do(t, "GET", ts.URL+"/v1/users", "", "application/x-msgpack", nil)
+	if status != nethttp.StatusOK || contentType != string(FormatMsgPack) {
+		t.Fatalf("Expected 200 MessagePack, got %d %s", status, contentType)
+	}
+	var fromMsgPack []avro.User
+	if err := msgpack.NewManager("").Deserialize(data, &fromMsgPack); err != nil {
+		t.Fatalf("Failed to decode MessagePack users: %v", err)
+	}
+	if len(fromMsgPack) != 2 || fromMsgPack[0].Email != sample.Email {
+		t.Errorf("Unexpected MessagePack users: %+v", fromMsgPack)
+	}
+
+	t.Log("✓ Users served as JSON, Avro, Protobuf and MessagePack")
+}
+
+func TestOrderConvertRoundTrip(t *testing.T) {
+	ts, manager := startTestServer(t)
+
+	tracking := "TRACK-1"
+	original := avro.Order{
+		ID:          7,
+		UserID:      1,
+		OrderNumber: "ORD-7",
+		Status:      avro.OrderStatusShipped,
+		Items: []avro.OrderItem{{
+			ProductID:      2,
+			ProductName:    "Widget",
+			ProductSKU:     "SKU-2",
+			Quantity:       3,
+			UnitPrice:      avro.Price{Currency: "USD", AmountCents: 500},
+			TotalPrice:     avro.Price{Currency: "USD", AmountCents: 1500},
+			ProductVariant: map[string]string{"color": "red"},
+		}},
+		Summary: avro.OrderSummary{
+			Subtotal:     avro.Price{Currency: "USD", AmountCents: 1500},
+			Tax:          avro.Price{Currency: "USD", AmountCents: 120},
+			ShippingCost: avro.Price{Currency: "USD", AmountCents: 0},
+			Discount:     avro.Price{Currency: "USD", AmountCents: 0},
+			Total:        avro.Price{Currency: "USD", AmountCents: 1620},
+			TotalItems:   3,
+		},
+		ShippingInfo: &avro.ShippingInfo{
+			Address:        avro.ShippingAddress{Street: "1 Main St", City: "Town", State: "TS", PostalCode: "12345", Country: "USA"},
+			Method:         "ground",
+			TrackingNumber: &tracking,
+			Cost:           avro.Price{Currency: "USD", AmountCents: 0},
+		},
+		CreatedAt: testutil.DefaultFakeTime,
+		UpdatedAt: testutil.DefaultFakeTime,
+	}
+
+	avroBody, err := manager.SerializeStruct(manager.GetOrderSchema(), original)
+	if err != nil {
+		t.Fatalf("Failed to encode order: %v", err)
+	}
+
+	// Avro -> Protobuf
+	status, _, protoBody := do(t, "POST", ts.URL+"/v1/orders/convert", "application/avro", "application/x-protobuf", avroBody)
+	if status != nethttp.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", status, protoBody)
+	}
+	var msg order.Order
+	if err := proto.Unmarshal(protoBody, &msg); err != nil {
+		t.Fatalf("Failed to decode Protobuf order: %v", err)
+	}
+	if msg.Status != order.OrderStatus_ORDER_STATUS_SHIPPED || msg.Shipping.TrackingNumber != tracking {
+		t.Errorf("Unexpected Protobuf order: %v", &msg)
+	}
+
+	// Protobuf -> Avro
+	status, _, back := do(t, "POST", ts.URL+"/v1/orders/convert", "application/x-protobuf", "application/avro", protoBody)
+	if status != nethttp.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", status, back)
+	}
+	var decoded avro.Order
+	if err := manager.DeserializeStruct(manager.GetOrderSchema(), back, &decoded); err != nil {
+		t.Fatalf("Failed to decode Avro order: %v", err)
+	}
+	decoded.CreatedAt, decoded.UpdatedAt = decoded.CreatedAt.UTC(), decoded.UpdatedAt.UTC()
+	if !reflect.DeepEqual(original, decoded) {
+		t.Errorf("Order changed in round trip:\nwant %+v\ngot  %+v", original, decoded)
+	}
+
+	status, _, _ = do(t, "GET", ts.URL+"/v1/orders", "", "", nil)
+	if status != nethttp.StatusOK {
+		t.Errorf("Expected 200 listing orders, got %d", status)
+	}
+
+	t.Log("✓ Orders transcoded between Avro and Protobuf without loss")
+}
+
+func TestErrorResponses(t *testing.T) {
+	ts, _ := startTestServer(t)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		accept      string
+		body        string
+		status      int
+		code        string
+	}{
+		{"unsupported content type", "POST", "/v1/users", "text/plain", "", "hi", nethttp.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE"},
+		{"not acceptable", "GET", "/v1/users", "", "text/html", "", nethttp.StatusNotAcceptable, "NOT_ACCEPTABLE"},
+		{"malformed body", "POST", "/v1/products", "application/json", "", "{", nethttp.StatusBadRequest, "DESERIALIZATION_ERROR"},
+		{"invalid id", "GET", "/v1/products/abc", "", "", "", nethttp.StatusBadRequest, "INVALID_VALUE"},
+		{"missing", "GET", "/v1/orders/42", "", "", "", nethttp.StatusNotFound, "NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, contentType, data := do(t, tt.method, ts.URL+tt.path, tt.contentType, tt.accept, []byte(tt.body))
+			if status != tt.status || contentType != string(FormatJSON) {
+				t.Fatalf("Expected %d JSON, got %d %s", tt.status, status, contentType)
+			}
+
+			var resp types.APIResponse[interface{}]
+			if err := json.Unmarshal(data, &resp); err != nil {
+				t.Fatalf("Failed to decode error response: %v", err)
+			}
+			if resp.Success || resp.Error == nil || resp.Error.Code != tt.code {
+				t.Errorf("Expected error code %s, got %s", tt.code, data)
+			}
+		})
+	}
+
+	t.Log("✓ Errors mapped to HTTP status codes")
+}
+
+func TestResponseFormatNegotiation(t *testing.T) {
+	tests := []struct {
+		accept   string
+		fallback Format
+		want     Format
+		wantErr  bool
+	}{
+		{"", FormatAvro, FormatAvro, false},
+		{"*/*", FormatProtobuf, FormatProtobuf, false},
+		{"application/json;q=0.5, application/avro", FormatJSON, FormatAvro, false},
+		{"text/html, application/protobuf;q=0.1", FormatJSON, FormatProtobuf, false},
+		{"application/avro;q=0, text/html", FormatJSON, "", true},
+		{"application/vnd.msgpack, application/json;q=0.9", FormatJSON, FormatMsgPack, false},
+		{"application/*, application/json;q=0", FormatJSON, FormatAvro, false},
+	}
+
+	for _, tt := range tests {
+		got, err := responseFormat(tt.accept, tt.fallback)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("responseFormat(%q) = %q, %v; want %q", tt.accept, got, err, tt.want)
+		}
+	}
+
+	t.Log("✓ Accept header negotiation honours quality values")
+}
+
+// poll long-polls /events and decodes the response
+func poll(t *testing.T, url string) (int, EventsResponse, []byte) {
+	t.Helper()
+
+	status, _, data := do(t, "GET", url, "", "", nil)
+	var resp EventsResponse
+	if status == nethttp.StatusOK {
+		if err := json.Unmarshal(data, &resp); err != nil {
+			t.Fatalf("Failed to decode events response: %v", err)
+		}
+	}
+	return status, resp, data
+}
+
+func TestLongPollEvents(t *testing.T) {
+	server, err := NewServer(DefaultConfig(), nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	log := eventlog.NewLog(4).WithClock(testutil.NewDefaultFakeClock())
+	server.WithEventLog(log)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	// A poll without a cursor or wait returns the head to resume from
+	status, resp, data := poll(t, ts.URL+"/events?wait=0")
+	if status != nethttp.StatusOK || len(resp.Events) != 0 || resp.Cursor != "0" {
+		t.Fatalf("Expected empty page at cursor 0, got %d %s", status, data)
+	}
+
+	// A waiting poll returns as soon as a matching event is published
+	done := make(chan EventsResponse, 1)
+	go func() {
+		_, resp, _ := poll(t, ts.URL+"/events?topics=orders&wait=5s&cursor="+resp.Cursor)
+		done <- resp
+	}()
+	time.Sleep(50 * time.Millisecond)
+	log.Append("analytics", map[string]string{"eventType": "page_view"})
+	log.Append("orders", map[string]interface{}{"id": 7, "status": "PENDING"})
+
+	select {
+	case resp = <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Long poll did not return after an event was published")
+	}
+	if len(resp.Events) != 1 || resp.Events[0].Topic != "orders" || resp.Events[0].Cursor != "2" || resp.Cursor != "2" {
+		t.Fatalf("Expected order event at cursor 2, got %+v", resp)
+	}
+	if data, ok := resp.Events[0].Data.(map[string]interface{}); !ok || data["status"] != "PENDING" {
+		t.Errorf("Expected event data to be the published value, got %#v", resp.Events[0].Data)
+	}
+
+	// A poll that times out keeps the client's place
+	status, resp, _ = poll(t, ts.URL+"/events?wait=20ms&cursor=2")
+	if status != nethttp.StatusOK || len(resp.Events) != 0 || resp.Cursor != "2" {
+		t.Errorf("Expected empty page at cursor 2, got %d %+v", status, resp)
+	}
+
+	// Limits page through the backlog
+	status, resp, _ = poll(t, ts.URL+"/events?cursor=0&limit=1&wait=0")
+	if status != nethttp.StatusOK || len(resp.Events) != 1 || resp.Cursor != "1" {
+		t.Errorf("Expected one event with cursor 1, got %d %+v", status, resp)
+	}
+
+	for i := 0; i < 4; i++ {
+		log.Append("orders", i)
+	}
+	tests := []struct {
+		name   string
+		query  string
+		accept string
+		status int
+		code   string
+	}{
+		{"expired cursor", "cursor=1", "", nethttp.StatusGone, "EXPIRED"},
+		{"malformed cursor", "cursor=next", "", nethttp.StatusBadRequest, "INVALID_VALUE"},
+		{"invalid wait", "wait=soon", "", nethttp.StatusBadRequest, "INVALID_VALUE"},
+		{"invalid limit", "limit=0", "", nethttp.StatusBadRequest, "INVALID_VALUE"},
+		{"not json", "wait=0", "application/avro", nethttp.StatusNotAcceptable, "NOT_ACCEPTABLE"},
+	}
+	for _, tt := range tests {
+		status, _, data := do(t, "GET", ts.URL+"/events?"+tt.query, "", tt.accept, nil)
+		var errResp types.APIResponse[interface{}]
+		json.Unmarshal(data, &errResp)
+		if status != tt.status || errResp.Error == nil || errResp.Error.Code != tt.code {
+			t.Errorf("%s: expected %d %s, got %d %s", tt.name, tt.status, tt.code, status, data)
+		}
+	}
+
+	t.Log("✓ Long polling resumes from cursors and reports expired ones")
+}
+
+func TestLongPollEndsOnShutdown(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.LongPollWait = time.Minute
+	cfg.LongPollMaxWait = time.Minute
+	server, err := NewServer(cfg, nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	server.WithEventLog(eventlog.NewLog(0))
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	done := make(chan EventsResponse, 1)
+	go func() {
+		_, resp, _ := poll(t, ts.URL+"/events")
+		done <- resp
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	select {
+	case resp := <-done:
+		if len(resp.Events) != 0 || resp.Cursor != "0" {
+			t.Errorf("Expected an empty page, got %+v", resp)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Pending long poll was not released by Shutdown")
+	}
+
+	t.Log("✓ Shutdown releases pending long polls")
+}
+
+// adminDo sends an admin request with token and actor headers
+func adminDo(t *testing.T, method, url, token string, body interface{}, out interface{}) int {
+	t.Helper()
+
+	var payload io.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		payload = bytes.NewReader(data)
+	}
+	req, err := nethttp.NewRequest(method, url, payload)
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-Admin-Actor", "alice")
+
+	resp, err := nethttp.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if out != nil {
+		json.NewDecoder(resp.Body).Decode(out)
+	}
+	return resp.StatusCode
+}
+
+func TestAdminAPI(t *testing.T) {
+	log, err := logger.New(logger.Config{Level: "warn", Format: "console", OutputPaths: "stderr"})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	server, err := NewServer(DefaultConfig(), log)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	clock := testutil.NewDefaultFakeClock()
+	settings := flags.NewSet().WithClock(clock)
+	server.WithClock(clock).WithFlags(settings).WithAdmin(settings, "secret")
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	if status := adminDo(t, "GET", ts.URL+"/admin/flags", "wrong", nil, nil); status != nethttp.StatusUnauthorized {
+		t.Fatalf("Expected 401 with a wrong token, got %d", status)
+	}
+	bare, _ := nethttp.NewRequest("GET", ts.URL+"/admin/flags", nil)
+	bare.Header.Set("Authorization", "secret")
+	resp, err := nethttp.DefaultClient.Do(bare)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusUnauthorized {
+		t.Fatalf("Expected 401 with the token outside a Bearer scheme, got %d", resp.StatusCode)
+	}
+
+	var list FlagsResponse
+	if status := adminDo(t, "GET", ts.URL+"/admin/flags", "secret", nil, &list); status != nethttp.StatusOK || len(list.Flags) != 4 {
+		t.Fatalf("Expected the 4 HTTP flags, got %d %+v", status, list)
+	}
+	if f, _ := settings.Lookup(FlagMaxBodyBytes); f.Value != "4194304" {
+		t.Errorf("Expected the configured body limit as the default, got %+v", f)
+	}
+
+	// Flags change the server's behaviour without a restart
+	var change flags.Change
+	if status := adminDo(t, "PUT", ts.URL+"/admin/flags/"+FlagMaxBodyBytes, "secret", SetFlagRequest{Value: "16"}, &change); status != nethttp.StatusOK {
+		t.Fatalf("Failed to set flag: %d", status)
+	}
+	if change.Old != "4194304" || change.New != "16" || change.Actor != "alice" {
+		t.Errorf("Unexpected change: %+v", change)
+	}
+	if status, _, _ := do(t, "POST", ts.URL+"/v1/users", "application/json", "", []byte(`{"name": "a body longer than sixteen bytes"}`)); status != nethttp.StatusBadRequest {
+		t.Errorf("Expected the lowered body limit to reject the request, got %d", status)
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		body   interface{}
+		status int
+	}{
+		{"unknown flag", "/admin/flags/missing", SetFlagRequest{Value: "1"}, nethttp.StatusNotFound},
+		{"wrong kind", "/admin/flags/" + FlagStrictDecode, SetFlagRequest{Value: "sometimes"}, nethttp.StatusBadRequest},
+		{"unknown level", "/admin/log-levels", SetLogLevelRequest{Level: "loud"}, nethttp.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if status := adminDo(t, "PUT", ts.URL+tt.path, "secret", tt.body, nil); status != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.status, status)
+		}
+	}
+
+	// Component levels override the global level
+	if status := adminDo(t, "PUT", ts.URL+"/admin/log-levels/http", "secret", SetLogLevelRequest{Level: "debug"}, nil); status != nethttp.StatusOK {
+		t.Fatalf("Failed to set component level: %d", status)
+	}
+	var levels LogLevelsResponse
+	adminDo(t, "GET", ts.URL+"/admin/log-levels", "secret", nil, &levels)
+	if levels.Level != "warn" || levels.Components["http"] != "debug" {
+		t.Errorf("Unexpected levels: %+v", levels)
+	}
+	if !server.logger.Core().Enabled(zapcore.DebugLevel) || log.Core().Enabled(zapcore.InfoLevel) {
+		t.Error("Expected only the http component to log debug entries")
+	}
+
+	var audit AuditResponse
+	adminDo(t, "GET", ts.URL+"/admin/audit", "secret", nil, &audit)
+	if len(audit.Changes) != 2 || audit.Changes[1].Name != "log.level.http" || audit.Changes[1].Old != "" || audit.Changes[1].New != "debug" {
+		t.Errorf("Expected the flag and level changes audited, got %+v", audit.Changes)
+	}
+
+	t.Log("✓ Admin API changes flags and log levels at runtime and audits them")
+}
+
+func TestAdminAPINeedsToken(t *testing.T) {
+	server, err := NewServer(DefaultConfig(), nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	settings := flags.NewSet()
+	server.WithFlags(settings).WithAdmin(settings, "")
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	if status := adminDo(t, "PUT", ts.URL+"/admin/flags/"+FlagRateLimit, "", SetFlagRequest{Value: "1"}, nil); status != nethttp.StatusNotFound {
+		t.Errorf("Expected no admin API without a token, got %d", status)
+	}
+	if f, _ := settings.Lookup(FlagRateLimit); f.Value != f.Default {
+		t.Errorf("Expected the flag to keep its default, got %+v", f)
+	}
+
+	t.Log("✓ Admin API is not served without a token")
+}
+
+func TestRuntimeLimits(t *testing.T) {
+	server, err := NewServer(DefaultConfig(), nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	clock := testutil.NewDefaultFakeClock()
+	settings := flags.NewSet()
+	server.WithClock(clock).WithFlags(settings).WithAdmin(settings, "secret")
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	body := []byte(`{"name": "Ada", "email": "ada@example.com", "nickname": "ada"}`)
+	if status, _, data := do(t, "POST", ts.URL+"/v1/users/convert", "application/json", "", body); status != nethttp.StatusOK {
+		t.Fatalf("Expected unknown fields to be ignored by default, got %d %s", status, data)
+	}
+	settings.Set(FlagStrictDecode, "true", "test")
+	if status, _, _ := do(t, "POST", ts.URL+"/v1/users/convert", "application/json", "", body); status != nethttp.StatusBadRequest {
+		t.Errorf("Expected strict decoding to reject unknown fields, got %d", status)
+	}
+
+	// Two requests per second with no burst beyond the rate
+	settings.Set(FlagRateLimit, "2", "test")
+	for i := 0; i < 2; i++ {
+		if status, _, _ := do(t, "GET", ts.URL+"/v1/users", "", "", nil); status != nethttp.StatusOK {
+			t.Fatalf("Request %d: expected 200, got %d", i, status)
+		}
+	}
+	if status, _, _ := do(t, "GET", ts.URL+"/v1/users", "", "", nil); status != nethttp.StatusTooManyRequests {
+		t.Errorf("Expected the third request to be rate limited, got %d", status)
+	}
+	if status := adminDo(t, "GET", ts.URL+"/admin/flags", "secret", nil, nil); status != nethttp.StatusOK {
+		t.Errorf("Expected the admin API to bypass the rate limit, got %d", status)
+	}
+	clock.Advance(time.Second)
+	if status, _, _ := do(t, "GET", ts.URL+"/v1/users", "", "", nil); status != nethttp.StatusOK {
+		t.Errorf("Expected the bucket to refill, got %d", status)
+	}
+
+	t.Log("✓ Strict decoding and rate limits follow their flags")
+}
+
+func TestMiddleware(t *testing.T) {
+	server, err := NewServer(DefaultConfig(), nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	server.Use(middleware.RateLimit(1, 1))
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	req, _ := nethttp.NewRequest("GET", ts.URL+"/v1/users", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-42")
+	resp, err := nethttp.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusOK || resp.Header.Get(middleware.RequestIDHeader) != "req-42" {
+		t.Errorf("Expected 200 echoing the request ID, got %d %q", resp.StatusCode, resp.Header.Get(middleware.RequestIDHeader))
+	}
+
+	resp, err = nethttp.Get(ts.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusTooManyRequests || resp.Header.Get(middleware.RequestIDHeader) == "" {
+		t.Errorf("Expected middleware added with Use to cover every route, got %d", resp.StatusCode)
+	}
+
+	t.Log("✓ Server middleware tags requests and wraps every route")
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	server, err := NewServer(DefaultConfig(), nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	cfg := metrics.DefaultConfig()
+	cfg.RuntimeMetrics = false
+	server.WithMetrics(metrics.NewCollector(cfg, nil))
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	do(t, "GET", ts.URL+"/v1/users", "", "", nil)
+	do(t, "GET", ts.URL+"/v1/users/42", "", "", nil)
+
+	status, _, body := do(t, "GET", ts.URL+"/metrics", "", "", nil)
+	if status != nethttp.StatusOK {
+		t.Fatalf("Expected metrics to be served, got %d", status)
+	}
+	for _, line := range []string{
+		`transport_http_requests_total{method="GET",route="/v1/users",status="200"} 1`,
+		`transport_http_requests_total{method="GET",route="/v1/users/{id}",status="404"} 1`,
+		`transport_http_request_duration_seconds_count{method="GET",route="/v1/users",status="200"} 1`,
+	} {
+		if !bytes.Contains(body, []byte(line)) {
+			t.Errorf("Expected metrics to contain %q", line)
+		}
+	}
+
+	t.Log("✓ Requests are counted and timed and served at /metrics")
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	server, err := NewServer(DefaultConfig(), nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	server.WithHealth(health.NewRegistry("test"))
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	if status, _, _ := do(t, "GET", ts.URL+"/healthz", "", "", nil); status != nethttp.StatusOK {
+		t.Errorf("Expected /healthz to answer 200, got %d", status)
+	}
+	if status, _, _ := do(t, "GET", ts.URL+"/readyz", "", "", nil); status != nethttp.StatusOK {
+		t.Errorf("Expected /readyz to answer 200, got %d", status)
+	}
+
+	// Readiness fails once shutdown starts draining
+	server.stop()
+	status, _, body := do(t, "GET", ts.URL+"/readyz", "", "", nil)
+	if status != nethttp.StatusServiceUnavailable || !bytes.Contains(body, []byte("shutting down")) {
+		t.Errorf("Expected /readyz to answer 503 while shutting down, got %d %s", status, body)
+	}
+
+	t.Log("✓ Health endpoints are served and readiness follows shutdown")
+}
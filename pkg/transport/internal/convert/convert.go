@@ -0,0 +1,301 @@
+// Package convert maps the Avro models, which the transports use on the wire,
+// to and from the generated protobuf messages. Users and products go through
+// the canonical pkg/model mappers.
+package convert
+
+import (
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go-transport-prac/pkg/model"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/protobuf/gen/analytics"
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+	"go-transport-prac/pkg/sdl/protobuf/gen/product"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+// enumToProto maps an Avro enum symbol like "ACTIVE" onto a protobuf enum value like USER_STATUS_ACTIVE
+func enumToProto(prefix, symbol string, values map[string]int32) int32 {
+	return values[prefix+symbol]
+}
+
+// enumFromProto maps a protobuf enum name like USER_STATUS_ACTIVE back onto its Avro symbol
+func enumFromProto(prefix, name string) string {
+	if strings.HasSuffix(name, "_UNSPECIFIED") {
+		return ""
+	}
+	return strings.TrimPrefix(name, prefix)
+}
+
+func timeToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func timePtrToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timeToProto(*t)
+}
+
+func timeFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func timePtrFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+func stringPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// UserToProto converts a user to its protobuf message
+func UserToProto(u avro.User) *user.User {
+	return model.UserFromAvro(u).ToProto()
+}
+
+// UserFromProto converts a protobuf user message to the Avro model
+func UserFromProto(msg *user.User) avro.User {
+	return model.UserFromProto(msg).ToAvro()
+}
+
+func priceToProto(p avro.Price) *product.Price {
+	return model.PriceFromAvro(p).ToProto()
+}
+
+func priceFromProto(msg *product.Price) avro.Price {
+	return model.PriceFromProto(msg).ToAvro()
+}
+
+// ProductToProto converts a product to its protobuf message
+func ProductToProto(p avro.Product) *product.Product {
+	return model.ProductFromAvro(p).ToProto()
+}
+
+// ProductFromProto converts a protobuf product message to the Avro model
+func ProductFromProto(msg *product.Product) avro.Product {
+	return model.ProductFromProto(msg).ToAvro()
+}
+
+// OrderToProto converts an order to its protobuf message. The shipping
+// recipient name has no protobuf field and is dropped.
+func OrderToProto(o avro.Order) *order.Order {
+	msg := &order.Order{
+		Id:          uint64(o.ID),
+		UserId:      uint64(o.UserID),
+		OrderNumber: o.OrderNumber,
+		Status:      order.OrderStatus(enumToProto("ORDER_STATUS_", string(o.Status), order.OrderStatus_value)),
+		Summary: &order.OrderSummary{
+			Subtotal:     priceToProto(o.Summary.Subtotal),
+			Tax:          priceToProto(o.Summary.Tax),
+			ShippingCost: priceToProto(o.Summary.ShippingCost),
+			Discount:     priceToProto(o.Summary.Discount),
+			Total:        priceToProto(o.Summary.Total),
+			TotalItems:   o.Summary.TotalItems,
+		},
+		CreatedAt:   timeToProto(o.CreatedAt),
+		UpdatedAt:   timeToProto(o.UpdatedAt),
+		ShippedAt:   timePtrToProto(o.ShippedAt),
+		DeliveredAt: timePtrToProto(o.DeliveredAt),
+	}
+
+	for _, item := range o.Items {
+		msg.Items = append(msg.Items, &order.OrderItem{
+			ProductId:      uint64(item.ProductID),
+			ProductName:    item.ProductName,
+			ProductSku:     item.ProductSKU,
+			Quantity:       item.Quantity,
+			UnitPrice:      priceToProto(item.UnitPrice),
+			TotalPrice:     priceToProto(item.TotalPrice),
+			ProductVariant: item.ProductVariant,
+		})
+	}
+
+	if s := o.ShippingInfo; s != nil {
+		msg.Shipping = &order.ShippingInfo{
+			Address: &user.Address{
+				Street:     s.Address.Street,
+				City:       s.Address.City,
+				State:      s.Address.State,
+				PostalCode: s.Address.PostalCode,
+				Country:    s.Address.Country,
+			},
+			Method:            s.Method,
+			TrackingNumber:    stringValue(s.TrackingNumber),
+			Carrier:           stringValue(s.Carrier),
+			Cost:              priceToProto(s.Cost),
+			EstimatedDelivery: timePtrToProto(s.EstimatedDelivery),
+		}
+	}
+
+	if p := o.PaymentInfo; p != nil {
+		msg.Payment = &order.PaymentInfo{
+			Method:        p.Method,
+			Status:        order.PaymentStatus(enumToProto("PAYMENT_STATUS_", string(p.Status), order.PaymentStatus_value)),
+			TransactionId: stringValue(p.TransactionID),
+			Amount:        priceToProto(p.Amount),
+			ProcessedAt:   timePtrToProto(p.ProcessedAt),
+		}
+	}
+
+	return msg
+}
+
+// OrderFromProto converts a protobuf order message to the Avro model
+func OrderFromProto(msg *order.Order) avro.Order {
+	summary := msg.GetSummary()
+	o := avro.Order{
+		ID:          int64(msg.GetId()),
+		UserID:      int64(msg.GetUserId()),
+		OrderNumber: msg.GetOrderNumber(),
+		Status:      avro.OrderStatus(enumFromProto("ORDER_STATUS_", msg.GetStatus().String())),
+		Summary: avro.OrderSummary{
+			Subtotal:     priceFromProto(summary.GetSubtotal()),
+			Tax:          priceFromProto(summary.GetTax()),
+			ShippingCost: priceFromProto(summary.GetShippingCost()),
+			Discount:     priceFromProto(summary.GetDiscount()),
+			Total:        priceFromProto(summary.GetTotal()),
+			TotalItems:   summary.GetTotalItems(),
+		},
+		CreatedAt:   timeFromProto(msg.GetCreatedAt()),
+		UpdatedAt:   timeFromProto(msg.GetUpdatedAt()),
+		ShippedAt:   timePtrFromProto(msg.GetShippedAt()),
+		DeliveredAt: timePtrFromProto(msg.GetDeliveredAt()),
+	}
+
+	for _, item := range msg.GetItems() {
+		o.Items = append(o.Items, avro.OrderItem{
+			ProductID:      int64(item.GetProductId()),
+			ProductName:    item.GetProductName(),
+			ProductSKU:     item.GetProductSku(),
+			Quantity:       item.GetQuantity(),
+			UnitPrice:      priceFromProto(item.GetUnitPrice()),
+			TotalPrice:     priceFromProto(item.GetTotalPrice()),
+			ProductVariant: item.GetProductVariant(),
+		})
+	}
+
+	if s := msg.GetShipping(); s != nil {
+		a := s.GetAddress()
+		o.ShippingInfo = &avro.ShippingInfo{
+			Address: avro.ShippingAddress{
+				Street:     a.GetStreet(),
+				City:       a.GetCity(),
+				State:      a.GetState(),
+				PostalCode: a.GetPostalCode(),
+				Country:    a.GetCountry(),
+			},
+			Method:            s.GetMethod(),
+			TrackingNumber:    stringPtr(s.GetTrackingNumber()),
+			Carrier:           stringPtr(s.GetCarrier()),
+			Cost:              priceFromProto(s.GetCost()),
+			EstimatedDelivery: timePtrFromProto(s.GetEstimatedDelivery()),
+		}
+	}
+
+	if p := msg.GetPayment(); p != nil {
+		o.PaymentInfo = &avro.PaymentInfo{
+			Method:        p.GetMethod(),
+			Status:        avro.PaymentStatus(enumFromProto("PAYMENT_STATUS_", p.GetStatus().String())),
+			TransactionID: stringPtr(p.GetTransactionId()),
+			Amount:        priceFromProto(p.GetAmount()),
+			ProcessedAt:   timePtrFromProto(p.GetProcessedAt()),
+		}
+	}
+
+	return o
+}
+
+// AnalyticsToProto converts an analytics event to its protobuf message
+func AnalyticsToProto(a avro.Analytics) *analytics.AnalyticsEvent {
+	msg := &analytics.AnalyticsEvent{
+		Id:         a.ID,
+		EventType:  a.EventType,
+		UserId:     a.UserID,
+		SessionId:  a.SessionID,
+		Timestamp:  timeToProto(a.Timestamp),
+		Properties: a.Properties,
+		Metrics:    a.Metrics,
+	}
+
+	if d := a.DeviceInfo; d != nil {
+		msg.DeviceInfo = &analytics.DeviceInfo{
+			UserAgent: d.UserAgent,
+			Platform:  d.Platform,
+			Browser:   d.Browser,
+			Version:   d.Version,
+			Mobile:    d.Mobile,
+		}
+	}
+
+	if l := a.Location; l != nil {
+		msg.Location = &analytics.Location{
+			Country:   l.Country,
+			Region:    l.Region,
+			City:      l.City,
+			Latitude:  l.Latitude,
+			Longitude: l.Longitude,
+		}
+	}
+
+	return msg
+}
+
+// AnalyticsFromProto converts a protobuf analytics event to the Avro model
+func AnalyticsFromProto(msg *analytics.AnalyticsEvent) avro.Analytics {
+	a := avro.Analytics{
+		ID:         msg.GetId(),
+		EventType:  msg.GetEventType(),
+		UserID:     msg.UserId,
+		SessionID:  msg.GetSessionId(),
+		Timestamp:  timeFromProto(msg.GetTimestamp()),
+		Properties: msg.GetProperties(),
+		Metrics:    msg.GetMetrics(),
+	}
+
+	if d := msg.GetDeviceInfo(); d != nil {
+		a.DeviceInfo = &avro.DeviceInfo{
+			UserAgent: d.GetUserAgent(),
+			Platform:  d.GetPlatform(),
+			Browser:   d.GetBrowser(),
+			Version:   d.GetVersion(),
+			Mobile:    d.GetMobile(),
+		}
+	}
+
+	if l := msg.GetLocation(); l != nil {
+		a.Location = &avro.Location{
+			Country:   l.GetCountry(),
+			Region:    l.Region,
+			City:      l.City,
+			Latitude:  l.Latitude,
+			Longitude: l.Longitude,
+		}
+	}
+
+	return a
+}
@@ -0,0 +1,463 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/internal/types"
+)
+
+// contentTypeHeader carries the codec MIME type on every message
+const contentTypeHeader = "content-type"
+
+// Headers added to dead-lettered messages
+const (
+	deadLetterTopicHeader     = "dlq-original-topic"
+	deadLetterPartitionHeader = "dlq-original-partition"
+	deadLetterOffsetHeader    = "dlq-original-offset"
+	deadLetterErrorHeader     = "dlq-error"
+)
+
+// messageWriter is the subset of kafka.Writer used by the broker
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafkago.Message) error
+	Close() error
+}
+
+// messageReader is the subset of kafka.Reader used by the broker
+type messageReader interface {
+	FetchMessage(ctx context.Context) (kafkago.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafkago.Message) error
+	Close() error
+}
+
+// subscription tracks a running consumer
+type subscription struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	reader messageReader
+	// manual subscriptions leave handled messages in pending for Commit
+	manual bool
+
+	mu      sync.Mutex
+	pending map[string]kafkago.Message
+}
+
+// Broker publishes and consumes Kafka messages, implementing types.MessageBroker.
+// Consumers commit an offset only after the handler succeeds, giving at-least-once delivery.
+type Broker struct {
+	cfg       Config
+	codec     Codec
+	logger    *logger.Logger
+	writer    messageWriter
+	newReader func(topic string) messageReader
+
+	mu            sync.Mutex
+	subscriptions map[string]*subscription
+	closed        bool
+}
+
+var _ types.MessageBroker = (*Broker)(nil)
+
+// NewBroker creates a Kafka broker using codec for typed messages. A nil
+// codec is created from cfg with NewCodecFromConfig, without schema registry
+// framing
+func NewBroker(cfg Config, codec Codec, log *logger.Logger) (*Broker, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, fmt.Errorf("at least one broker address is required")
+	}
+	if codec == nil {
+		var err error
+		if codec, err = NewCodecFromConfig(cfg, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	writer := &kafkago.Writer{
+		Addr:                   kafkago.TCP(cfg.Brokers...),
+		Balancer:               &kafkago.Hash{},
+		RequiredAcks:           kafkago.RequireAll,
+		BatchTimeout:           cfg.BatchTimeout,
+		AllowAutoTopicCreation: true,
+	}
+
+	newReader := func(topic string) messageReader {
+		return kafkago.NewReader(kafkago.ReaderConfig{
+			Brokers: cfg.Brokers,
+			GroupID: cfg.GroupID,
+			Topic:   topic,
+			Dialer:  &kafkago.Dialer{ClientID: cfg.ClientID, Timeout: 10 * time.Second},
+		})
+	}
+
+	return newBroker(cfg, codec, log, writer, newReader), nil
+}
+
+// newBroker wires a broker around explicit writer and reader implementations
+func newBroker(cfg Config, codec Codec, log *logger.Logger, writer messageWriter, newReader func(string) messageReader) *Broker {
+	if log == nil {
+		log = logger.Global()
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = DefaultConfig().RetryBackoff
+	}
+	if cfg.MaxRetryBackoff <= 0 {
+		cfg.MaxRetryBackoff = DefaultConfig().MaxRetryBackoff
+	}
+
+	return &Broker{
+		cfg:           cfg,
+		codec:         codec,
+		logger:        log.WithComponent("kafka"),
+		writer:        writer,
+		newReader:     newReader,
+		subscriptions: make(map[string]*subscription),
+	}
+}
+
+// Publish publishes a raw payload to a topic
+func (b *Broker) Publish(ctx context.Context, topic string, message []byte) error {
+	return b.write(ctx, kafkago.Message{Topic: topic, Value: message})
+}
+
+// PublishValue serializes v with the broker codec and publishes it keyed by key
+func (b *Broker) PublishValue(ctx context.Context, topic, key string, v interface{}) error {
+	payload, err := b.codec.Encode(topic, v)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	return b.write(ctx, kafkago.Message{
+		Topic: topic,
+		Key:   []byte(key),
+		Value: payload,
+		Headers: []kafkago.Header{
+			{Key: contentTypeHeader, Value: []byte(b.contentType(topic))},
+		},
+	})
+}
+
+// contentType returns the MIME type the codec encodes topic's values as
+func (b *Broker) contentType(topic string) string {
+	if c, ok := b.codec.(interface{ TopicContentType(string) string }); ok {
+		return c.TopicContentType(topic)
+	}
+	return b.codec.ContentType()
+}
+
+// Decode deserializes a consumed message into v with the broker codec
+func (b *Broker) Decode(message types.Message, v interface{}) error {
+	if err := b.codec.Decode(message.Topic, message.Data, v); err != nil {
+		return fmt.Errorf("failed to decode message %s: %w", message.ID, err)
+	}
+	return nil
+}
+
+// Subscribe starts consuming a topic in the background, calling handler for every message.
+// A failed handler is retried with exponential backoff and its offset is not committed
+// until it succeeds or the message is dead-lettered after MaxAttempts calls.
+func (b *Broker) Subscribe(ctx context.Context, topic string, handler types.MessageHandler) error {
+	return b.subscribe(ctx, topic, handler, false)
+}
+
+// SubscribeManual consumes a topic like Subscribe, but leaves the offsets of
+// handled messages uncommitted until they are passed to Commit. Handlers can
+// then acknowledge a message without blocking the partition, as a sink
+// buffering records does, and commit once the records are stored
+func (b *Broker) SubscribeManual(ctx context.Context, topic string, handler types.MessageHandler) error {
+	return b.subscribe(ctx, topic, handler, true)
+}
+
+// Commit commits the offsets of messages delivered by manual subscriptions.
+// Kafka offsets are cumulative, so committing a message also commits every
+// earlier message of its partition; callers commit in delivery order
+func (b *Broker) Commit(ctx context.Context, msgs ...types.Message) error {
+	byTopic := make(map[string][]types.Message)
+	for _, msg := range msgs {
+		byTopic[msg.Topic] = append(byTopic[msg.Topic], msg)
+	}
+
+	for topic, topicMsgs := range byTopic {
+		b.mu.Lock()
+		sub, exists := b.subscriptions[topic]
+		b.mu.Unlock()
+		if !exists || !sub.manual {
+			return fmt.Errorf("no manual subscription to topic %s", topic)
+		}
+		if err := sub.commit(ctx, topicMsgs); err != nil {
+			return fmt.Errorf("failed to commit offsets of topic %s: %w", topic, err)
+		}
+	}
+	return nil
+}
+
+// subscribe starts the consumer of a topic
+func (b *Broker) subscribe(ctx context.Context, topic string, handler types.MessageHandler, manual bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return fmt.Errorf("broker is closed")
+	}
+	if _, exists := b.subscriptions[topic]; exists {
+		return fmt.Errorf("already subscribed to topic %s", topic)
+	}
+
+	consumeCtx, cancel := context.WithCancel(ctx)
+	sub := &subscription{
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		reader:  b.newReader(topic),
+		manual:  manual,
+		pending: make(map[string]kafkago.Message),
+	}
+	b.subscriptions[topic] = sub
+
+	go func() {
+		defer close(sub.done)
+		defer sub.reader.Close()
+		b.consume(consumeCtx, topic, sub, handler)
+	}()
+
+	b.logger.Info("Subscribed to topic",
+		zap.String("topic", topic), zap.String("group_id", b.cfg.GroupID), zap.Bool("manual_commit", manual))
+	return nil
+}
+
+// Unsubscribe stops consuming a topic and waits for the consumer to exit
+func (b *Broker) Unsubscribe(ctx context.Context, topic string) error {
+	b.mu.Lock()
+	sub, exists := b.subscriptions[topic]
+	delete(b.subscriptions, topic)
+	b.mu.Unlock()
+
+	if !exists {
+		return fmt.Errorf("not subscribed to topic %s", topic)
+	}
+
+	sub.cancel()
+	select {
+	case <-sub.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops all consumers and closes the producer
+func (b *Broker) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	subs := b.subscriptions
+	b.subscriptions = make(map[string]*subscription)
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.cancel()
+		<-sub.done
+	}
+
+	return b.writer.Close()
+}
+
+// write sends messages through the producer
+func (b *Broker) write(ctx context.Context, msg kafkago.Message) error {
+	msg.Time = time.Now()
+	if err := b.writer.WriteMessages(ctx, msg); err != nil {
+		return fmt.Errorf("failed to publish to topic %s: %w", msg.Topic, err)
+	}
+	return nil
+}
+
+// consume runs the fetch/handle/commit loop until ctx is cancelled
+func (b *Broker) consume(ctx context.Context, topic string, sub *subscription, handler types.MessageHandler) {
+	for {
+		msg, err := sub.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			b.logger.Error("Failed to fetch message", zap.String("topic", topic), zap.Error(err))
+			if !sleepContext(ctx, b.cfg.RetryBackoff) {
+				return
+			}
+			continue
+		}
+
+		if !b.handleWithRetry(ctx, msg, handler) {
+			return // Not committed, so the message is redelivered to the next consumer
+		}
+		if sub.manual {
+			sub.track(msg)
+			continue
+		}
+
+		if err := sub.reader.CommitMessages(ctx, msg); err != nil && !errors.Is(err, context.Canceled) {
+			b.logger.Error("Failed to commit offset",
+				zap.String("topic", topic),
+				zap.Int64("offset", msg.Offset),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// handleWithRetry calls handler until it succeeds or MaxAttempts calls have
+// failed, in which case the message is dead-lettered. It returns false if ctx
+// ends first, leaving the message uncommitted
+func (b *Broker) handleWithRetry(ctx context.Context, msg kafkago.Message, handler types.MessageHandler) bool {
+	message := toMessage(msg)
+	backoff := b.cfg.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := handler(ctx, message)
+		if err == nil {
+			return true
+		}
+		if b.cfg.MaxAttempts > 0 && attempt >= b.cfg.MaxAttempts {
+			return b.deadLetter(ctx, msg, attempt, err)
+		}
+
+		b.logger.Warn("Message handler failed, retrying",
+			zap.String("topic", message.Topic),
+			zap.String("message_id", message.ID),
+			zap.Int("attempt", attempt),
+			zap.Error(err),
+		)
+
+		if !sleepContext(ctx, backoff) {
+			return false
+		}
+		backoff = b.nextBackoff(backoff)
+	}
+}
+
+// deadLetter moves a message whose handler kept failing to its dead-letter
+// topic, with headers recording where it came from and why it failed. The
+// write is retried until it succeeds, so a message is committed only once it
+// is parked; it returns false if ctx ends first
+func (b *Broker) deadLetter(ctx context.Context, msg kafkago.Message, attempts int, cause error) bool {
+	fields := []zap.Field{
+		zap.String("topic", msg.Topic),
+		zap.Int("partition", msg.Partition),
+		zap.Int64("offset", msg.Offset),
+		zap.Int("attempts", attempts),
+		zap.Error(cause),
+	}
+	if b.cfg.DeadLetterSuffix == "" {
+		b.logger.Error("Dropping message after exhausting handler attempts", fields...)
+		return true
+	}
+
+	letter := kafkago.Message{
+		Topic: msg.Topic + b.cfg.DeadLetterSuffix,
+		Key:   msg.Key,
+		Value: msg.Value,
+		Headers: append(append([]kafkago.Header(nil), msg.Headers...),
+			kafkago.Header{Key: deadLetterTopicHeader, Value: []byte(msg.Topic)},
+			kafkago.Header{Key: deadLetterPartitionHeader, Value: []byte(strconv.Itoa(msg.Partition))},
+			kafkago.Header{Key: deadLetterOffsetHeader, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+			kafkago.Header{Key: deadLetterErrorHeader, Value: []byte(cause.Error())},
+		),
+	}
+	backoff := b.cfg.RetryBackoff
+	for {
+		err := b.write(ctx, letter)
+		if err == nil {
+			b.logger.Error("Dead-lettered message after exhausting handler attempts",
+				append(fields, zap.String("dead_letter_topic", letter.Topic))...)
+			return true
+		}
+		b.logger.Error("Failed to dead-letter message", append(fields, zap.NamedError("write_error", err))...)
+		if !sleepContext(ctx, backoff) {
+			return false
+		}
+		backoff = b.nextBackoff(backoff)
+	}
+}
+
+// nextBackoff doubles a retry delay up to MaxRetryBackoff
+func (b *Broker) nextBackoff(backoff time.Duration) time.Duration {
+	return min(backoff*2, b.cfg.MaxRetryBackoff)
+}
+
+// track holds a handled message of a manual subscription until it is committed
+func (s *subscription) track(msg kafkago.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[toMessage(msg).ID] = msg
+}
+
+// commit commits the tracked messages among msgs and forgets every tracked
+// message their offsets cover. Messages already covered are ignored
+func (s *subscription) commit(ctx context.Context, msgs []types.Message) error {
+	s.mu.Lock()
+	commits := make([]kafkago.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		if km, ok := s.pending[msg.ID]; ok {
+			commits = append(commits, km)
+		}
+	}
+	s.mu.Unlock()
+	if len(commits) == 0 {
+		return nil
+	}
+
+	if err := s.reader.CommitMessages(ctx, commits...); err != nil {
+		return err
+	}
+
+	committed := make(map[int]int64)
+	for _, km := range commits {
+		if offset, ok := committed[km.Partition]; !ok || km.Offset > offset {
+			committed[km.Partition] = km.Offset
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, km := range s.pending {
+		if offset, ok := committed[km.Partition]; ok && km.Offset <= offset {
+			delete(s.pending, id)
+		}
+	}
+	return nil
+}
+
+// toMessage converts a Kafka message to the transport-neutral message type
+func toMessage(msg kafkago.Message) types.Message {
+	headers := make(map[string]string, len(msg.Headers)+1)
+	for _, h := range msg.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	if len(msg.Key) > 0 {
+		headers["key"] = string(msg.Key)
+	}
+
+	return types.Message{
+		ID:        fmt.Sprintf("%s-%d-%d", msg.Topic, msg.Partition, msg.Offset),
+		Topic:     msg.Topic,
+		Data:      msg.Value,
+		Headers:   headers,
+		Timestamp: msg.Time,
+	}
+}
+
+// sleepContext waits for d, returning false if ctx is cancelled first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
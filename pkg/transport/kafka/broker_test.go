@@ -0,0 +1,403 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/protobuf"
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+// fakeCluster is an in-memory stand-in for a single-partition Kafka cluster
+type fakeCluster struct {
+	mu        sync.Mutex
+	cond      *sync.Cond
+	topics    map[string][]kafkago.Message
+	committed map[string]int64
+}
+
+func newFakeCluster() *fakeCluster {
+	c := &fakeCluster{topics: make(map[string][]kafkago.Message), committed: make(map[string]int64)}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+func (c *fakeCluster) WriteMessages(ctx context.Context, msgs ...kafkago.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, m := range msgs {
+		m.Offset = int64(len(c.topics[m.Topic]))
+		c.topics[m.Topic] = append(c.topics[m.Topic], m)
+	}
+	c.cond.Broadcast()
+	return nil
+}
+
+func (c *fakeCluster) Close() error { return nil }
+
+func (c *fakeCluster) committedOffset(topic string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.committed[topic]
+}
+
+// reader starts from the committed offset, like a consumer group member
+func (c *fakeCluster) reader(topic string) messageReader {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &fakeReader{cluster: c, topic: topic, pos: c.committed[topic]}
+}
+
+type fakeReader struct {
+	cluster *fakeCluster
+	topic   string
+	pos     int64
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafkago.Message, error) {
+	stop := context.AfterFunc(ctx, func() {
+		r.cluster.mu.Lock()
+		r.cluster.cond.Broadcast()
+		r.cluster.mu.Unlock()
+	})
+	defer stop()
+
+	r.cluster.mu.Lock()
+	defer r.cluster.mu.Unlock()
+	for int64(len(r.cluster.topics[r.topic])) <= r.pos {
+		if ctx.Err() != nil {
+			return kafkago.Message{}, ctx.Err()
+		}
+		r.cluster.cond.Wait()
+	}
+	msg := r.cluster.topics[r.topic][r.pos]
+	r.pos++
+	return msg, nil
+}
+
+func (r *fakeReader) CommitMessages(ctx context.Context, msgs ...kafkago.Message) error {
+	r.cluster.mu.Lock()
+	defer r.cluster.mu.Unlock()
+	for _, m := range msgs {
+		r.cluster.committed[r.topic] = m.Offset + 1
+	}
+	return nil
+}
+
+func (r *fakeReader) Close() error { return nil }
+
+func newTestBroker(t *testing.T, cluster *fakeCluster, codec Codec) *Broker {
+	t.Helper()
+	log, err := logger.NewDevelopment()
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	cfg := DefaultConfig()
+	cfg.RetryBackoff = time.Millisecond
+	cfg.MaxRetryBackoff = 5 * time.Millisecond
+	cfg.MaxAttempts = 0 // retry until unsubscribed
+	return newBroker(cfg, codec, log, cluster, cluster.reader)
+}
+
+// messages returns a copy of the messages written to topic
+func (c *fakeCluster) messages(topic string) []kafkago.Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]kafkago.Message(nil), c.topics[topic]...)
+}
+
+func TestBrokerAvroPublishSubscribe(t *testing.T) {
+	cluster := newFakeCluster()
+	manager, err := avro.NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create avro manager: %v", err)
+	}
+	registry := avro.NewSchemaRegistry()
+	broker := newTestBroker(t, cluster, NewAvroCodec(manager, registry))
+	defer broker.Close()
+
+	users := manager.CreateSampleUsers(3)
+	for _, u := range users {
+		if err := broker.PublishValue(context.Background(), "users", u.Email, u); err != nil {
+			t.Fatalf("Failed to publish user: %v", err)
+		}
+	}
+
+	// Payloads carry the registry header for the topic subject
+	schemaID, _, err := DecodeWireFormat(cluster.topics["users"][0].Value)
+	if err != nil {
+		t.Fatalf("Expected framed payload: %v", err)
+	}
+	if latest, err := registry.GetLatestSchema("users-value"); err != nil || latest.ID != schemaID {
+		t.Fatalf("Expected schema ID %d registered for users-value, got %v (%v)", schemaID, latest.ID, err)
+	}
+
+	received := make(chan avro.User, len(users))
+	err = broker.Subscribe(context.Background(), "users", func(ctx context.Context, msg types.Message) error {
+		var u avro.User
+		if err := broker.Decode(msg, &u); err != nil {
+			return err
+		}
+		if msg.Headers[contentTypeHeader] != "application/avro" || msg.Headers["key"] != u.Email {
+			t.Errorf("Unexpected headers: %v", msg.Headers)
+		}
+		received <- u
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	for i := range users {
+		select {
+		case u := <-received:
+			if u.ID != users[i].ID {
+				t.Errorf("Message %d: expected user %d, got %d", i, users[i].ID, u.ID)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("Timed out waiting for messages")
+		}
+	}
+
+	if err := broker.Unsubscribe(context.Background(), "users"); err != nil {
+		t.Fatalf("Failed to unsubscribe: %v", err)
+	}
+	if offset := cluster.committedOffset("users"); offset != int64(len(users)) {
+		t.Errorf("Expected committed offset %d, got %d", len(users), offset)
+	}
+
+	t.Log("✓ Avro messages published and consumed with registry framing")
+}
+
+func TestBrokerAtLeastOnce(t *testing.T) {
+	cluster := newFakeCluster()
+	broker := newTestBroker(t, cluster, JSONCodec{})
+	defer broker.Close()
+
+	if err := broker.Publish(context.Background(), "events", []byte(`{"n":1}`)); err != nil {
+		t.Fatalf("Failed to publish: %v", err)
+	}
+
+	// Handler fails twice before succeeding; the offset must not advance until then
+	var mu sync.Mutex
+	attempts := 0
+	done := make(chan struct{})
+	err := broker.Subscribe(context.Background(), "events", func(ctx context.Context, msg types.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts < 3 {
+			if cluster.committedOffset("events") != 0 {
+				t.Error("Offset committed before handler succeeded")
+			}
+			return errors.New("transient failure")
+		}
+		close(done)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Handler never succeeded")
+	}
+	if err := broker.Unsubscribe(context.Background(), "events"); err != nil {
+		t.Fatalf("Failed to unsubscribe: %v", err)
+	}
+	if cluster.committedOffset("events") != 1 {
+		t.Errorf("Expected offset committed after success")
+	}
+
+	// A consumer that stops mid-failure leaves the message for redelivery
+	if err := broker.Publish(context.Background(), "events", []byte(`{"n":2}`)); err != nil {
+		t.Fatalf("Failed to publish: %v", err)
+	}
+	failing := make(chan struct{}, 1)
+	err = broker.Subscribe(context.Background(), "events", func(ctx context.Context, msg types.Message) error {
+		select {
+		case failing <- struct{}{}:
+		default:
+		}
+		return errors.New("down")
+	})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	<-failing
+	if err := broker.Unsubscribe(context.Background(), "events"); err != nil {
+		t.Fatalf("Failed to unsubscribe: %v", err)
+	}
+
+	redelivered := make(chan string, 1)
+	err = broker.Subscribe(context.Background(), "events", func(ctx context.Context, msg types.Message) error {
+		redelivered <- string(msg.Data)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	select {
+	case data := <-redelivered:
+		if data != `{"n":2}` {
+			t.Errorf("Expected redelivery of second message, got %s", data)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Message was not redelivered")
+	}
+
+	t.Log("✓ Offsets committed only after successful handling")
+}
+
+func TestBrokerDeadLetters(t *testing.T) {
+	cluster := newFakeCluster()
+	broker := newTestBroker(t, cluster, JSONCodec{})
+	broker.cfg.MaxAttempts = 3
+	defer broker.Close()
+
+	if err := broker.PublishValue(context.Background(), "events", "key-1", map[string]int{"n": 1}); err != nil {
+		t.Fatalf("Failed to publish: %v", err)
+	}
+	if err := broker.Publish(context.Background(), "events", []byte(`{"n":2}`)); err != nil {
+		t.Fatalf("Failed to publish: %v", err)
+	}
+
+	var mu sync.Mutex
+	attempts := make(map[string]int)
+	handled := make(chan struct{})
+	err := broker.Subscribe(context.Background(), "events", func(ctx context.Context, msg types.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[string(msg.Data)]++
+		if string(msg.Data) == `{"n":2}` {
+			close(handled)
+			return nil
+		}
+		return errors.New("poison message")
+	})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	// The poison message must not stall the messages behind it
+	select {
+	case <-handled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Poison message blocked the topic")
+	}
+	if err := broker.Unsubscribe(context.Background(), "events"); err != nil {
+		t.Fatalf("Failed to unsubscribe: %v", err)
+	}
+	if attempts[`{"n":1}`] != 3 {
+		t.Errorf("Expected 3 attempts before dead-lettering, got %d", attempts[`{"n":1}`])
+	}
+	if cluster.committedOffset("events") != 2 {
+		t.Errorf("Expected both offsets committed, got %d", cluster.committedOffset("events"))
+	}
+
+	letters := cluster.messages("events.dlq")
+	if len(letters) != 1 {
+		t.Fatalf("Expected one dead-lettered message, got %d", len(letters))
+	}
+	letter := toMessage(letters[0])
+	if string(letter.Data) != `{"n":1}` || letter.Headers["key"] != "key-1" {
+		t.Errorf("Dead letter does not carry the original message: %+v", letter)
+	}
+	if letter.Headers[deadLetterTopicHeader] != "events" || letter.Headers[deadLetterOffsetHeader] != "0" ||
+		letter.Headers[deadLetterErrorHeader] != "poison message" || letter.Headers[contentTypeHeader] != "application/json" {
+		t.Errorf("Unexpected dead letter headers: %v", letter.Headers)
+	}
+
+	t.Log("✓ Messages that keep failing are dead-lettered and committed")
+}
+
+func TestBrokerManualCommit(t *testing.T) {
+	cluster := newFakeCluster()
+	broker := newTestBroker(t, cluster, JSONCodec{})
+	defer broker.Close()
+
+	for i := 0; i < 3; i++ {
+		if err := broker.Publish(context.Background(), "events", []byte(`{}`)); err != nil {
+			t.Fatalf("Failed to publish: %v", err)
+		}
+	}
+
+	handled := make(chan types.Message, 3)
+	err := broker.SubscribeManual(context.Background(), "events", func(ctx context.Context, msg types.Message) error {
+		handled <- msg
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	var msgs []types.Message
+	for len(msgs) < 3 {
+		select {
+		case msg := <-handled:
+			msgs = append(msgs, msg)
+		case <-time.After(2 * time.Second):
+			t.Fatal("Messages were not delivered")
+		}
+	}
+	if cluster.committedOffset("events") != 0 {
+		t.Fatal("Expected handled messages to stay uncommitted")
+	}
+
+	if err := broker.Commit(context.Background(), msgs[1]); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	if cluster.committedOffset("events") != 2 {
+		t.Errorf("Expected offsets up to the second message committed, got %d", cluster.committedOffset("events"))
+	}
+	// The first message was covered by the second's commit
+	if err := broker.Commit(context.Background(), msgs[0]); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	if cluster.committedOffset("events") != 2 {
+		t.Errorf("Expected a covered commit to leave the offset, got %d", cluster.committedOffset("events"))
+	}
+	if err := broker.Commit(context.Background(), types.Message{Topic: "other"}); err == nil {
+		t.Error("Expected a commit without a manual subscription to fail")
+	}
+
+	t.Log("✓ Manual subscriptions commit only what the subscriber commits")
+}
+
+func TestProtobufCodecFraming(t *testing.T) {
+	codec := NewProtobufCodec().WithSchemaID("user.User", 42)
+	sample := protobuf.NewManager().CreateSampleUser()
+
+	data, err := codec.Encode("users", sample)
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+
+	schemaID, _, err := DecodeWireFormat(data)
+	if err != nil || schemaID != 42 {
+		t.Fatalf("Expected schema ID 42, got %d (%v)", schemaID, err)
+	}
+
+	var decoded user.User
+	if err := codec.Decode("users", data, &decoded); err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if decoded.GetEmail() != sample.GetEmail() {
+		t.Errorf("Expected email %s, got %s", sample.GetEmail(), decoded.GetEmail())
+	}
+
+	if _, _, err := DecodeWireFormat([]byte{1, 0}); !errors.Is(err, ErrInvalidFrame) {
+		t.Errorf("Expected ErrInvalidFrame, got %v", err)
+	}
+
+	t.Log("✓ Protobuf payloads framed with schema ID")
+}
@@ -0,0 +1,50 @@
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"go-transport-prac/pkg/sdl/encryption"
+)
+
+// KeyCipher is a Cipher sealing values in the encryption package's AES-GCM
+// format. Each value records the ID of the key it was sealed with, so values
+// written before a key rotation keep decrypting
+type KeyCipher struct {
+	keys encryption.KeyProvider
+}
+
+var _ Cipher = (*KeyCipher)(nil)
+
+// NewKeyCipher creates a cipher encrypting with the current key of keys
+func NewKeyCipher(keys encryption.KeyProvider) *KeyCipher {
+	return &KeyCipher{keys: keys}
+}
+
+// Encrypt seals plaintext with the current key
+func (c *KeyCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	err := encryption.Encrypt(context.Background(), &buf, c.keys, func(w io.Writer) error {
+		_, err := w.Write(plaintext)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt value: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Decrypt opens a value sealed by Encrypt, rejecting values that are not encrypted
+func (c *KeyCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	r, err := encryption.Open(context.Background(), bytes.NewReader(ciphertext), c.keys, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,291 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	hamba "github.com/hamba/avro/v2"
+	"google.golang.org/protobuf/proto"
+
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/protobuf"
+)
+
+// Codec serializes message values for a topic
+type Codec interface {
+	Encode(topic string, v interface{}) ([]byte, error)
+	Decode(topic string, data []byte, v interface{}) error
+	ContentType() string
+}
+
+// NewCodec creates the codec for a serialization format.
+// A non-nil registry enables schema registry framing for Avro payloads.
+func NewCodec(format Format, registry *avro.SchemaRegistry) (Codec, error) {
+	switch format {
+	case FormatAvro:
+		manager, err := avro.NewManager("")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create avro manager: %w", err)
+		}
+		return NewAvroCodec(manager, registry), nil
+	case FormatProtobuf:
+		return NewProtobufCodec(), nil
+	case FormatJSON:
+		return JSONCodec{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// AvroCodec encodes the Avro User/Product/Order models, optionally framed with schema registry IDs
+type AvroCodec struct {
+	manager  *avro.Manager
+	registry *avro.SchemaRegistry
+	schemas  map[reflect.Type]hamba.Schema
+
+	mu        sync.Mutex
+	schemaIDs map[string]int                 // subject -> registered schema ID
+	pins      map[string]avro.SchemaMetadata // subject -> pinned schema version
+	warned    map[string]int                 // subject -> newest writer version already warned about
+	logger    *logger.Logger
+}
+
+// NewAvroCodec creates an Avro codec; registry may be nil to send unframed payloads
+func NewAvroCodec(manager *avro.Manager, registry *avro.SchemaRegistry) *AvroCodec {
+	return &AvroCodec{
+		manager:  manager,
+		registry: registry,
+		schemas: map[reflect.Type]hamba.Schema{
+			reflect.TypeOf(avro.User{}):    manager.GetUserSchema(),
+			reflect.TypeOf(avro.Product{}): manager.GetProductSchema(),
+			reflect.TypeOf(avro.Order{}):   manager.GetOrderSchema(),
+		},
+		schemaIDs: make(map[string]int),
+		pins:      make(map[string]avro.SchemaMetadata),
+		warned:    make(map[string]int),
+		logger:    logger.Global().WithComponent("kafka"),
+	}
+}
+
+// RegisterType maps an additional Go type to its Avro schema
+func (c *AvroCodec) RegisterType(v interface{}, schema hamba.Schema) {
+	c.schemas[indirectType(reflect.TypeOf(v))] = schema
+}
+
+// Encode serializes v with the schema registered for its type, or with the
+// pinned schema version when the topic subject is pinned
+func (c *AvroCodec) Encode(topic string, v interface{}) ([]byte, error) {
+	schema, err := c.schemaFor(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.registry != nil {
+		pinned, ok, err := c.pinnedSchema(subjectName(topic), schema)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			payload, err := c.manager.SerializeStruct(pinned.Schema, v)
+			if err != nil {
+				return nil, err
+			}
+			return EncodeWireFormat(pinned.ID, payload), nil
+		}
+	}
+
+	payload, err := c.manager.SerializeStruct(schema, v)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.registry == nil {
+		return payload, nil
+	}
+
+	schemaID, err := c.schemaID(subjectName(topic), schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return EncodeWireFormat(schemaID, payload), nil
+}
+
+// Decode deserializes data into v, resolving the writer schema from the registry when framed
+func (c *AvroCodec) Decode(topic string, data []byte, v interface{}) error {
+	readerSchema, err := c.schemaFor(v)
+	if err != nil {
+		return err
+	}
+
+	if c.registry == nil {
+		return c.manager.DeserializeStruct(readerSchema, data, v)
+	}
+
+	schemaID, payload, err := DecodeWireFormat(data)
+	if err != nil {
+		return err
+	}
+
+	writer, err := c.registry.GetSchema(schemaID)
+	if err != nil {
+		return fmt.Errorf("failed to look up writer schema: %w", err)
+	}
+
+	// Pinned readers decode into the pinned version rather than the compiled-in schema
+	subject := subjectName(topic)
+	pinned, ok, err := c.pinnedSchema(subject, readerSchema)
+	if err != nil {
+		return err
+	}
+	if ok {
+		c.warnIfNewer(subject, writer, pinned)
+		readerSchema = pinned.Schema
+	}
+
+	if writer.Schema.Fingerprint() == readerSchema.Fingerprint() {
+		return c.manager.DeserializeStruct(readerSchema, payload, v)
+	}
+
+	record, err := c.manager.DeserializeWithSchemas(writer.Schema, readerSchema, payload)
+	if err != nil {
+		return err
+	}
+
+	return avro.AvroToStruct(readerSchema, record, v)
+}
+
+// ContentType returns the MIME type of encoded payloads
+func (c *AvroCodec) ContentType() string {
+	return "application/avro"
+}
+
+// schemaFor returns the schema registered for the type of v
+func (c *AvroCodec) schemaFor(v interface{}) (hamba.Schema, error) {
+	schema, ok := c.schemas[indirectType(reflect.TypeOf(v))]
+	if !ok {
+		return nil, fmt.Errorf("no avro schema registered for %T", v)
+	}
+	return schema, nil
+}
+
+// schemaID registers the schema under subject once and caches its ID
+func (c *AvroCodec) schemaID(subject string, schema hamba.Schema) (int, error) {
+	key := subject + "/" + schema.(hamba.NamedSchema).FullName()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if id, ok := c.schemaIDs[key]; ok {
+		return id, nil
+	}
+
+	id, err := c.registry.RegisterSchema(subject, schema.String())
+	if err != nil {
+		return 0, fmt.Errorf("failed to register schema for subject %s: %w", subject, err)
+	}
+
+	c.schemaIDs[key] = id
+	return id, nil
+}
+
+// ProtobufCodec encodes protobuf messages, optionally framed with schema registry IDs
+type ProtobufCodec struct {
+	manager   *protobuf.Manager
+	schemaIDs map[string]int // message full name -> schema ID
+}
+
+// NewProtobufCodec creates a protobuf codec that sends unframed payloads
+func NewProtobufCodec() *ProtobufCodec {
+	return &ProtobufCodec{
+		manager:   protobuf.NewManager(),
+		schemaIDs: make(map[string]int),
+	}
+}
+
+// WithSchemaID frames messages of the given full name (e.g. "user.User") with a registry schema ID
+func (c *ProtobufCodec) WithSchemaID(messageName string, schemaID int) *ProtobufCodec {
+	c.schemaIDs[messageName] = schemaID
+	return c
+}
+
+// Encode serializes a proto.Message
+func (c *ProtobufCodec) Encode(topic string, v interface{}) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("protobuf codec requires proto.Message, got %T", v)
+	}
+
+	payload, err := c.manager.Serialize(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaID, framed := c.schemaIDs[string(msg.ProtoReflect().Descriptor().FullName())]
+	if !framed {
+		return payload, nil
+	}
+
+	// A single zero byte is the message-index shortcut for the first message in the schema
+	return EncodeWireFormat(schemaID, append([]byte{0}, payload...)), nil
+}
+
+// Decode deserializes data into a proto.Message
+func (c *ProtobufCodec) Decode(topic string, data []byte, v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("protobuf codec requires proto.Message, got %T", v)
+	}
+
+	if _, framed := c.schemaIDs[string(msg.ProtoReflect().Descriptor().FullName())]; framed {
+		_, payload, err := DecodeWireFormat(data)
+		if err != nil {
+			return err
+		}
+		if len(payload) == 0 || payload[0] != 0 {
+			return fmt.Errorf("%w: unsupported message index", ErrInvalidFrame)
+		}
+		data = payload[1:]
+	}
+
+	return c.manager.Deserialize(data, msg)
+}
+
+// ContentType returns the MIME type of encoded payloads
+func (c *ProtobufCodec) ContentType() string {
+	return "application/x-protobuf"
+}
+
+// JSONCodec encodes values with encoding/json
+type JSONCodec struct{}
+
+// Encode serializes v as JSON
+func (JSONCodec) Encode(topic string, v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Decode deserializes JSON into v
+func (JSONCodec) Decode(topic string, data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// ContentType returns the MIME type of encoded payloads
+func (JSONCodec) ContentType() string {
+	return "application/json"
+}
+
+// subjectName returns the schema registry subject for a topic's values
+func subjectName(topic string) string {
+	return topic + "-value"
+}
+
+// indirectType strips pointer indirection
+func indirectType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
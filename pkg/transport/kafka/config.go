@@ -0,0 +1,79 @@
+package kafka
+
+import (
+	"time"
+
+	"go-transport-prac/internal/config"
+)
+
+// Format identifies how message payloads are serialized
+type Format string
+
+const (
+	FormatAvro     Format = "avro"
+	FormatProtobuf Format = "protobuf"
+	FormatJSON     Format = "json"
+)
+
+// Config holds Kafka producer and consumer settings
+type Config struct {
+	Brokers  []string
+	GroupID  string
+	ClientID string
+	Format   Format
+
+	// BatchTimeout bounds how long the producer waits to fill a batch
+	BatchTimeout time.Duration
+	// RetryBackoff is the initial delay before redelivering a message whose handler failed
+	RetryBackoff time.Duration
+	// MaxRetryBackoff caps the exponential handler retry delay
+	MaxRetryBackoff time.Duration
+	// MaxAttempts is the number of handler calls before a message is
+	// dead-lettered; 0 retries until the consumer stops
+	MaxAttempts int
+	// DeadLetterSuffix names the topic exhausted messages are moved to,
+	// appended to their topic; empty drops them after logging
+	DeadLetterSuffix string
+
+	// SchemaPins maps registry subjects to the schema version producers write
+	// with and consumers read into; applied by NewCodecFromConfig
+	SchemaPins map[string]int
+
+	// Serializers configures format, version policy, compression and
+	// encryption per subject; see NewCodecFromConfig
+	Serializers SerializerConfig
+	// SerializersFile is a JSON SerializerConfig, loaded when Serializers is empty
+	SerializersFile string
+	// Ciphers maps the EncryptionKey names of Serializers to their ciphers,
+	// such as a KeyCipher
+	Ciphers map[string]Cipher
+}
+
+// DefaultConfig returns a configuration for a local single-broker cluster
+func DefaultConfig() Config {
+	return Config{
+		Brokers:          []string{"localhost:9092"},
+		GroupID:          "go-transport-prac",
+		ClientID:         "go-transport-prac",
+		Format:           FormatAvro,
+		BatchTimeout:     10 * time.Millisecond,
+		RetryBackoff:     100 * time.Millisecond,
+		MaxRetryBackoff:  10 * time.Second,
+		MaxAttempts:      10,
+		DeadLetterSuffix: ".dlq",
+	}
+}
+
+// NewConfig builds a Kafka configuration from the application configuration
+func NewConfig(cfg config.KafkaConfig) Config {
+	kafkaCfg := DefaultConfig()
+	kafkaCfg.Brokers = cfg.Brokers
+	kafkaCfg.GroupID = cfg.GroupID
+	kafkaCfg.ClientID = cfg.ClientID
+	kafkaCfg.Format = Format(cfg.Format)
+	kafkaCfg.SchemaPins = cfg.SchemaPins
+	kafkaCfg.SerializersFile = cfg.SerializersFile
+	kafkaCfg.MaxAttempts = cfg.MaxAttempts
+	kafkaCfg.DeadLetterSuffix = cfg.DeadLetterSuffix
+	return kafkaCfg
+}
@@ -0,0 +1,33 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// magicByte prefixes every payload in the schema registry wire format
+const magicByte byte = 0
+
+// ErrInvalidFrame is returned when a payload does not start with the schema registry header
+var ErrInvalidFrame = errors.New("invalid schema registry frame")
+
+// EncodeWireFormat prefixes payload with the schema registry header: magic byte plus big-endian schema ID
+func EncodeWireFormat(schemaID int, payload []byte) []byte {
+	framed := make([]byte, 5+len(payload))
+	framed[0] = magicByte
+	binary.BigEndian.PutUint32(framed[1:5], uint32(schemaID))
+	copy(framed[5:], payload)
+	return framed
+}
+
+// DecodeWireFormat splits a schema registry framed payload into its schema ID and body
+func DecodeWireFormat(data []byte) (int, []byte, error) {
+	if len(data) < 5 {
+		return 0, nil, fmt.Errorf("%w: %d bytes is shorter than header", ErrInvalidFrame, len(data))
+	}
+	if data[0] != magicByte {
+		return 0, nil, fmt.Errorf("%w: unknown magic byte %d", ErrInvalidFrame, data[0])
+	}
+	return int(binary.BigEndian.Uint32(data[1:5])), data[5:], nil
+}
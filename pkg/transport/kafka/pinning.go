@@ -0,0 +1,178 @@
+package kafka
+
+import (
+	"fmt"
+	"sort"
+
+	hamba "github.com/hamba/avro/v2"
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+// Schema pinning lets operators roll out schema changes deliberately: a pinned
+// subject is always written with the pinned registry version instead of the
+// codec's compiled-in schema, and readers decode into that version, warning
+// when they see data written with a newer one.
+
+// WithLogger sets the logger used for schema pinning warnings
+func (c *AvroCodec) WithLogger(log *logger.Logger) *AvroCodec {
+	if log == nil {
+		log = logger.Global()
+	}
+	c.logger = log.WithComponent("kafka")
+	return c
+}
+
+// PinSchemaVersion pins subject to a registered schema version
+func (c *AvroCodec) PinSchemaVersion(subject string, version int) error {
+	if c.registry == nil {
+		return fmt.Errorf("schema pinning requires a schema registry")
+	}
+
+	metadata, err := c.registry.GetSchemaVersion(subject, version)
+	if err != nil {
+		return fmt.Errorf("failed to pin %s: %w", subject, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pins[subject] = metadata
+	c.logger.Info("Pinned schema version",
+		zap.String("subject", subject),
+		zap.Int("version", metadata.Version),
+		zap.Int("schema_id", metadata.ID),
+	)
+	return nil
+}
+
+// PinSchemaVersions applies a subject -> version map, typically Config.SchemaPins
+func (c *AvroCodec) PinSchemaVersions(pins map[string]int) error {
+	subjects := make([]string, 0, len(pins))
+	for subject := range pins {
+		subjects = append(subjects, subject)
+	}
+	sort.Strings(subjects)
+
+	for _, subject := range subjects {
+		if err := c.PinSchemaVersion(subject, pins[subject]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PinnedVersion returns the version subject is pinned to, if any
+func (c *AvroCodec) PinnedVersion(subject string) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	metadata, ok := c.pins[subject]
+	return metadata.Version, ok
+}
+
+// Unpin returns subject to writing with the codec's own schema
+func (c *AvroCodec) Unpin(subject string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.pins, subject)
+}
+
+// UpgradePin moves a pinned subject forward to version, or to the latest
+// registered version when version is 0. Pins never move backwards and the
+// upgrade is refused if readers still on the current pin could not decode
+// data written with the new version.
+func (c *AvroCodec) UpgradePin(subject string, version int) (int, error) {
+	if c.registry == nil {
+		return 0, fmt.Errorf("schema pinning requires a schema registry")
+	}
+
+	c.mu.Lock()
+	current, pinned := c.pins[subject]
+	c.mu.Unlock()
+	if !pinned {
+		return 0, fmt.Errorf("subject %s is not pinned", subject)
+	}
+
+	var target avro.SchemaMetadata
+	var err error
+	if version == 0 {
+		target, err = c.registry.GetLatestSchema(subject)
+	} else {
+		target, err = c.registry.GetSchemaVersion(subject, version)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to upgrade %s: %w", subject, err)
+	}
+
+	if target.Version < current.Version {
+		return 0, fmt.Errorf("cannot upgrade %s from version %d to older version %d", subject, current.Version, target.Version)
+	}
+	if target.Version == current.Version {
+		return current.Version, nil
+	}
+
+	if incompatibilities := avro.CheckReaderWriterCompatibility(current.Schema, target.Schema); len(incompatibilities) > 0 {
+		return 0, fmt.Errorf("cannot upgrade %s to version %d: readers on version %d would fail: %s",
+			subject, target.Version, current.Version, incompatibilities[0])
+	}
+
+	c.mu.Lock()
+	c.pins[subject] = target
+	c.mu.Unlock()
+
+	c.logger.Info("Upgraded schema pin",
+		zap.String("subject", subject),
+		zap.Int("from_version", current.Version),
+		zap.Int("to_version", target.Version),
+	)
+	return target.Version, nil
+}
+
+// pinnedSchema returns the pinned schema for subject if it describes the same record as local
+func (c *AvroCodec) pinnedSchema(subject string, local hamba.Schema) (avro.SchemaMetadata, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	metadata, ok := c.pins[subject]
+	if !ok {
+		return avro.SchemaMetadata{}, false, nil
+	}
+
+	pinned, ok := metadata.Schema.(hamba.NamedSchema)
+	if !ok {
+		return avro.SchemaMetadata{}, false, fmt.Errorf("subject %s is pinned to a %s schema, not a named record", subject, metadata.Schema.Type())
+	}
+	named, ok := local.(hamba.NamedSchema)
+	if !ok {
+		return avro.SchemaMetadata{}, false, fmt.Errorf("subject %s is pinned but value uses a %s schema, not a named record", subject, local.Type())
+	}
+	if pinnedName, localName := pinned.FullName(), named.FullName(); pinnedName != localName {
+		return avro.SchemaMetadata{}, false, fmt.Errorf("subject %s is pinned to %s but value uses %s", subject, pinnedName, localName)
+	}
+	return metadata, true, nil
+}
+
+// warnIfNewer logs once per writer version when data is newer than the pinned reader schema
+func (c *AvroCodec) warnIfNewer(subject string, writer, reader avro.SchemaMetadata) {
+	if writer.Subject != subject || writer.Version <= reader.Version {
+		return
+	}
+
+	c.mu.Lock()
+	if c.warned[subject] >= writer.Version {
+		c.mu.Unlock()
+		return
+	}
+	c.warned[subject] = writer.Version
+	c.mu.Unlock()
+
+	c.logger.Warn("Reading data written with a newer schema than the pinned reader schema",
+		zap.String("subject", subject),
+		zap.Int("writer_version", writer.Version),
+		zap.Int("reader_version", reader.Version),
+	)
+}
@@ -0,0 +1,182 @@
+package kafka
+
+import (
+	"os"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+// newPinningRegistry registers user v1 and v2 under users-value
+func newPinningRegistry(t *testing.T) *avro.SchemaRegistry {
+	t.Helper()
+
+	registry := avro.NewSchemaRegistry()
+	for _, file := range []string{"user.avsc", "user_v2.avsc"} {
+		schema, err := os.ReadFile("../../sdl/avro/schemas/" + file)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		if _, err := registry.RegisterSchema("users-value", string(schema)); err != nil {
+			t.Fatalf("Failed to register %s: %v", file, err)
+		}
+	}
+	return registry
+}
+
+func newPinningCodec(t *testing.T, registry *avro.SchemaRegistry) (*AvroCodec, *observer.ObservedLogs) {
+	t.Helper()
+
+	manager, err := avro.NewManager("")
+	if err != nil {
+		t.Fatalf("Failed to create avro manager: %v", err)
+	}
+	core, logs := observer.New(zapcore.InfoLevel)
+	return NewAvroCodec(manager, registry).WithLogger(&logger.Logger{Logger: zap.New(core)}), logs
+}
+
+func TestPinnedProducerWritesPinnedVersion(t *testing.T) {
+	registry := newPinningRegistry(t)
+	codec, _ := newPinningCodec(t, registry)
+	manager, _ := avro.NewManager("")
+	user := manager.CreateSampleUsers(1)[0]
+
+	if err := codec.PinSchemaVersions(map[string]int{"users-value": 2}); err != nil {
+		t.Fatalf("Failed to pin: %v", err)
+	}
+
+	data, err := codec.Encode("users", user)
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	schemaID, _, err := DecodeWireFormat(data)
+	if err != nil {
+		t.Fatalf("Expected framed payload: %v", err)
+	}
+	v2, _ := registry.GetSchemaVersion("users-value", 2)
+	if schemaID != v2.ID {
+		t.Fatalf("Expected pinned schema ID %d, got %d", v2.ID, schemaID)
+	}
+
+	var decoded avro.User
+	if err := codec.Decode("users", data, &decoded); err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if decoded.Email != user.Email {
+		t.Errorf("Expected %s, got %s", user.Email, decoded.Email)
+	}
+
+	if err := codec.PinSchemaVersion("users-value", 3); err == nil {
+		t.Error("Expected error pinning an unregistered version")
+	}
+
+	t.Log("✓ Pinned subjects are written with the pinned version")
+}
+
+func TestUpgradePin(t *testing.T) {
+	registry := newPinningRegistry(t)
+	codec, _ := newPinningCodec(t, registry)
+
+	if _, err := codec.UpgradePin("users-value", 0); err == nil {
+		t.Error("Expected error upgrading an unpinned subject")
+	}
+
+	if err := codec.PinSchemaVersion("users-value", 1); err != nil {
+		t.Fatalf("Failed to pin: %v", err)
+	}
+	version, err := codec.UpgradePin("users-value", 0)
+	if err != nil {
+		t.Fatalf("Failed to upgrade: %v", err)
+	}
+	if pinned, _ := codec.PinnedVersion("users-value"); version != 2 || pinned != 2 {
+		t.Errorf("Expected pin at version 2, got %d/%d", version, pinned)
+	}
+
+	if _, err := codec.UpgradePin("users-value", 1); err == nil {
+		t.Error("Expected error moving a pin backwards")
+	}
+
+	t.Log("✓ Pins move forward only through explicit upgrades")
+}
+
+func TestUpgradePinRejectsUnreadableVersion(t *testing.T) {
+	registry := avro.NewSchemaRegistry()
+	registry.SetCompatibilityLevel("events-value", avro.CompatibilityNone)
+	registry.RegisterSchema("events-value", `{"type":"record","name":"Event","fields":[{"name":"id","type":"long"},{"name":"kind","type":"string"}]}`)
+	registry.RegisterSchema("events-value", `{"type":"record","name":"Event","fields":[{"name":"id","type":"long"}]}`)
+
+	codec, _ := newPinningCodec(t, registry)
+	if err := codec.PinSchemaVersion("events-value", 1); err != nil {
+		t.Fatalf("Failed to pin: %v", err)
+	}
+
+	// Readers pinned to v1 require "kind", which v2 data no longer carries
+	if _, err := codec.UpgradePin("events-value", 2); err == nil {
+		t.Fatal("Expected upgrade to an unreadable version to fail")
+	}
+	if pinned, _ := codec.PinnedVersion("events-value"); pinned != 1 {
+		t.Errorf("Expected pin to stay at 1, got %d", pinned)
+	}
+
+	t.Log("✓ Upgrades that would break pinned readers are refused")
+}
+
+func TestPinnedConsumerWarnsOnNewerData(t *testing.T) {
+	registry := newPinningRegistry(t)
+	manager, _ := avro.NewManager("")
+	users := manager.CreateSampleUsers(2)
+
+	producer, _ := newPinningCodec(t, registry)
+	producer.PinSchemaVersion("users-value", 2)
+
+	consumer, logs := newPinningCodec(t, registry)
+	consumer.PinSchemaVersion("users-value", 1)
+
+	for _, user := range users {
+		data, err := producer.Encode("users", user)
+		if err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+		var decoded avro.User
+		if err := consumer.Decode("users", data, &decoded); err != nil {
+			t.Fatalf("Failed to decode newer data: %v", err)
+		}
+		if decoded.ID != user.ID {
+			t.Errorf("Expected user %d, got %d", user.ID, decoded.ID)
+		}
+	}
+
+	warnings := logs.FilterLevelExact(zapcore.WarnLevel).All()
+	if len(warnings) != 1 {
+		t.Fatalf("Expected exactly one warning, got %d", len(warnings))
+	}
+	fields := warnings[0].ContextMap()
+	if fields["writer_version"] != int64(2) || fields["reader_version"] != int64(1) {
+		t.Errorf("Unexpected warning fields: %v", fields)
+	}
+
+	t.Log("✓ Pinned consumers warn once about newer writer schemas")
+}
+
+func TestPinnedNonRecordSchemaFails(t *testing.T) {
+	registry := avro.NewSchemaRegistry()
+	if _, err := registry.RegisterSchema("users-value", `"string"`); err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+	codec, _ := newPinningCodec(t, registry)
+	if err := codec.PinSchemaVersion("users-value", 1); err != nil {
+		t.Fatalf("Failed to pin: %v", err)
+	}
+
+	manager, _ := avro.NewManager("")
+	if _, err := codec.Encode("users", manager.CreateSampleUsers(1)[0]); err == nil {
+		t.Fatal("Expected a pin to a non-record schema to fail")
+	}
+
+	t.Log("✓ Pins to schemas that are not named records fail instead of panicking")
+}
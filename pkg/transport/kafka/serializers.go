@@ -0,0 +1,373 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"go-transport-prac/internal/compress"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+// Per-subject serializer configuration makes transport behavior declarative:
+// producers and consumers share one SerializerConfig, usually loaded from a
+// file, instead of each choosing a format, pins and codec options per call.
+
+// Compression names how a subject's encoded values are compressed
+type Compression string
+
+const (
+	CompressionNone   Compression = "none"
+	CompressionGzip   Compression = "gzip"
+	CompressionSnappy Compression = "snappy"
+	CompressionZstd   Compression = "zstd"
+)
+
+// VersionPolicy selects the schema version Avro values are written with
+type VersionPolicy string
+
+const (
+	// VersionLatest writes with the codec's compiled-in schema, registering it as needed
+	VersionLatest VersionPolicy = "latest"
+	// VersionPinned writes with, and reads into, the registered SubjectConfig.Version
+	VersionPinned VersionPolicy = "pinned"
+)
+
+// Cipher encrypts encoded values of the subjects configured with its key.
+// KeyCipher implements it with the encryption package
+type Cipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// SubjectConfig is how the values of one subject are serialized.
+// Empty fields take the value from SerializerConfig.Defaults
+type SubjectConfig struct {
+	Format        Format        `json:"format,omitempty"`
+	VersionPolicy VersionPolicy `json:"versionPolicy,omitempty"`
+	// Version is the registry version pinned subjects use
+	Version     int         `json:"version,omitempty"`
+	Compression Compression `json:"compression,omitempty"`
+	// EncryptionKey names the cipher, added with WithCipher, that encrypts
+	// values after compression; empty leaves them unencrypted
+	EncryptionKey string `json:"encryptionKey,omitempty"`
+}
+
+// SerializerConfig maps subjects to their serializer settings. Keys are
+// registry subjects such as "orders-value" or, as a shorthand, topic names
+type SerializerConfig struct {
+	Defaults SubjectConfig            `json:"defaults"`
+	Subjects map[string]SubjectConfig `json:"subjects,omitempty"`
+}
+
+// LoadSerializerConfig reads a SerializerConfig from a JSON file
+func LoadSerializerConfig(path string) (SerializerConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return SerializerConfig{}, fmt.Errorf("failed to read serializer config: %w", err)
+	}
+
+	var cfg SerializerConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return SerializerConfig{}, fmt.Errorf("failed to parse serializer config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// IsZero reports whether no settings are configured
+func (c SerializerConfig) IsZero() bool {
+	return c.Defaults == (SubjectConfig{}) && len(c.Subjects) == 0
+}
+
+// Resolve returns the settings for a topic's values: its subject entry, else
+// its topic entry, with empty fields filled from the defaults
+func (c SerializerConfig) Resolve(topic string) SubjectConfig {
+	cfg, ok := c.Subjects[subjectName(topic)]
+	if !ok {
+		cfg = c.Subjects[topic]
+	}
+	return cfg.withDefaults(c.Defaults)
+}
+
+// Validate checks every subject's resolved settings
+func (c SerializerConfig) Validate() error {
+	if err := c.Defaults.withDefaults(SubjectConfig{}).validate(); err != nil {
+		return fmt.Errorf("invalid defaults: %w", err)
+	}
+	if c.Defaults.VersionPolicy == VersionPinned {
+		return fmt.Errorf("invalid defaults: versions can only be pinned per subject")
+	}
+	for _, key := range c.keys() {
+		if err := c.Subjects[key].withDefaults(c.Defaults).validate(); err != nil {
+			return fmt.Errorf("invalid settings for %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// keys returns the configured subject keys in order
+func (c SerializerConfig) keys() []string {
+	keys := make([]string, 0, len(c.Subjects))
+	for key := range c.Subjects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// withDefaults fills empty fields from defaults, then from the built-in defaults
+func (s SubjectConfig) withDefaults(defaults SubjectConfig) SubjectConfig {
+	if s.Format == "" {
+		s.Format = defaults.Format
+	}
+	if s.VersionPolicy == "" {
+		s.VersionPolicy = defaults.VersionPolicy
+		if s.Version == 0 {
+			s.Version = defaults.Version
+		}
+	}
+	if s.Compression == "" {
+		s.Compression = defaults.Compression
+	}
+	if s.EncryptionKey == "" {
+		s.EncryptionKey = defaults.EncryptionKey
+	}
+
+	if s.Format == "" {
+		s.Format = FormatAvro
+	}
+	if s.VersionPolicy == "" {
+		s.VersionPolicy = VersionLatest
+	}
+	if s.Compression == "" {
+		s.Compression = CompressionNone
+	}
+	return s
+}
+
+func (s SubjectConfig) validate() error {
+	switch s.Format {
+	case FormatAvro, FormatProtobuf, FormatJSON:
+	default:
+		return fmt.Errorf("unsupported format: %s", s.Format)
+	}
+
+	switch s.VersionPolicy {
+	case VersionLatest:
+	case VersionPinned:
+		if s.Format != FormatAvro {
+			return fmt.Errorf("version pinning is only supported for avro, not %s", s.Format)
+		}
+		if s.Version < 1 {
+			return fmt.Errorf("pinned version must be at least 1, got %d", s.Version)
+		}
+	default:
+		return fmt.Errorf("unsupported version policy: %s", s.VersionPolicy)
+	}
+
+	if _, ok := compressors[s.Compression]; !ok {
+		return fmt.Errorf("unsupported compression: %s", s.Compression)
+	}
+	return nil
+}
+
+// SerializerRegistry is a Codec that serializes each topic's values as its
+// subject is configured: encode with the subject's format, then compress,
+// then encrypt. Decoding reverses the steps
+type SerializerRegistry struct {
+	cfg    SerializerConfig
+	codecs map[Format]Codec
+
+	mu      sync.RWMutex
+	ciphers map[string]Cipher
+}
+
+// NewSerializerRegistry creates the codecs cfg uses and pins the subjects
+// configured with VersionPinned. A registry is required for pinning and
+// enables schema registry framing for Avro, as with NewCodec
+func NewSerializerRegistry(cfg SerializerConfig, registry *avro.SchemaRegistry) (*SerializerRegistry, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	r := &SerializerRegistry{cfg: cfg, codecs: make(map[Format]Codec), ciphers: make(map[string]Cipher)}
+	settings := []SubjectConfig{cfg.Resolve("")}
+	for _, key := range cfg.keys() {
+		settings = append(settings, cfg.Subjects[key].withDefaults(cfg.Defaults))
+	}
+	for _, s := range settings {
+		if _, ok := r.codecs[s.Format]; ok {
+			continue
+		}
+		codec, err := NewCodec(s.Format, registry)
+		if err != nil {
+			return nil, err
+		}
+		r.codecs[s.Format] = codec
+	}
+
+	for _, key := range cfg.keys() {
+		s := cfg.Subjects[key].withDefaults(cfg.Defaults)
+		if s.VersionPolicy != VersionPinned {
+			continue
+		}
+		subject := key
+		if !strings.HasSuffix(subject, "-value") {
+			subject = subjectName(key)
+		}
+		if err := r.codecs[FormatAvro].(*AvroCodec).PinSchemaVersion(subject, s.Version); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// NewCodecFromConfig creates the codec a broker configuration describes: a
+// SerializerRegistry with cfg.Ciphers when Serializers or SerializersFile is
+// set, otherwise the Format codec with SchemaPins applied. NewBroker uses it
+// when it is given no codec
+func NewCodecFromConfig(cfg Config, registry *avro.SchemaRegistry) (Codec, error) {
+	serializers := cfg.Serializers
+	if serializers.IsZero() && cfg.SerializersFile != "" {
+		var err error
+		if serializers, err = LoadSerializerConfig(cfg.SerializersFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if serializers.IsZero() {
+		codec, err := NewCodec(cfg.Format, registry)
+		if err != nil {
+			return nil, err
+		}
+		if avroCodec, ok := codec.(*AvroCodec); ok && len(cfg.SchemaPins) > 0 {
+			if err := avroCodec.PinSchemaVersions(cfg.SchemaPins); err != nil {
+				return nil, err
+			}
+		}
+		return codec, nil
+	}
+
+	if serializers.Defaults.Format == "" {
+		serializers.Defaults.Format = cfg.Format
+	}
+	if len(cfg.SchemaPins) > 0 {
+		subjects := make(map[string]SubjectConfig, len(serializers.Subjects)+len(cfg.SchemaPins))
+		for key, s := range serializers.Subjects {
+			subjects[key] = s
+		}
+		for subject, version := range cfg.SchemaPins {
+			s := subjects[subject]
+			s.VersionPolicy, s.Version = VersionPinned, version
+			subjects[subject] = s
+		}
+		serializers.Subjects = subjects
+	}
+	r, err := NewSerializerRegistry(serializers, registry)
+	if err != nil {
+		return nil, err
+	}
+	for keyID, cipher := range cfg.Ciphers {
+		r.WithCipher(keyID, cipher)
+	}
+	return r, nil
+}
+
+// WithCipher makes cipher available to subjects configured with keyID
+func (r *SerializerRegistry) WithCipher(keyID string, cipher Cipher) *SerializerRegistry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ciphers[keyID] = cipher
+	return r
+}
+
+// Config returns the resolved settings for a topic's values
+func (r *SerializerRegistry) Config(topic string) SubjectConfig {
+	return r.cfg.Resolve(topic)
+}
+
+// Codec returns the codec used for format, e.g. to register more Avro types
+// on the *AvroCodec, or nil when no subject uses it
+func (r *SerializerRegistry) Codec(format Format) Codec {
+	return r.codecs[format]
+}
+
+// Encode serializes v as the topic's subject is configured
+func (r *SerializerRegistry) Encode(topic string, v interface{}) ([]byte, error) {
+	s := r.cfg.Resolve(topic)
+
+	data, err := r.codecs[s.Format].Encode(topic, v)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = compressors[s.Compression].Compress(data); err != nil {
+		return nil, fmt.Errorf("failed to compress %s value: %w", topic, err)
+	}
+	if s.EncryptionKey == "" {
+		return data, nil
+	}
+
+	cipher, err := r.cipher(s.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = cipher.Encrypt(data); err != nil {
+		return nil, fmt.Errorf("failed to encrypt %s value: %w", topic, err)
+	}
+	return data, nil
+}
+
+// Decode deserializes data into v as the topic's subject is configured
+func (r *SerializerRegistry) Decode(topic string, data []byte, v interface{}) error {
+	s := r.cfg.Resolve(topic)
+
+	if s.EncryptionKey != "" {
+		cipher, err := r.cipher(s.EncryptionKey)
+		if err != nil {
+			return err
+		}
+		if data, err = cipher.Decrypt(data); err != nil {
+			return fmt.Errorf("failed to decrypt %s value: %w", topic, err)
+		}
+	}
+
+	data, err := compressors[s.Compression].Decompress(data)
+	if err != nil {
+		return fmt.Errorf("failed to decompress %s value: %w", topic, err)
+	}
+	return r.codecs[s.Format].Decode(topic, data, v)
+}
+
+// ContentType returns the MIME type of the default format
+func (r *SerializerRegistry) ContentType() string {
+	return r.TopicContentType("")
+}
+
+// TopicContentType returns the MIME type of a topic's format
+func (r *SerializerRegistry) TopicContentType(topic string) string {
+	return r.codecs[r.cfg.Resolve(topic).Format].ContentType()
+}
+
+// cipher returns the cipher registered for keyID
+func (r *SerializerRegistry) cipher(keyID string) (Cipher, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cipher, ok := r.ciphers[keyID]
+	if !ok {
+		return nil, fmt.Errorf("no cipher registered for encryption key %q", keyID)
+	}
+	return cipher, nil
+}
+
+// compressors compress whole values. Decompression is capped, so a small
+// crafted message cannot exhaust memory
+var compressors = map[Compression]compress.Codec{
+	CompressionNone:   compress.None,
+	CompressionGzip:   compress.Gzip,
+	CompressionSnappy: compress.Snappy,
+	CompressionZstd:   compress.Zstd,
+}
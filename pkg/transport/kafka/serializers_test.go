@@ -0,0 +1,195 @@
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-transport-prac/internal/compress"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/encryption"
+)
+
+func TestSerializerRegistry(t *testing.T) {
+	testDir := "tmp/test_serializers"
+	defer os.RemoveAll(testDir)
+	if err := os.MkdirAll(testDir, 0755); err != nil {
+		t.Fatalf("Failed to create test dir: %v", err)
+	}
+	path := filepath.Join(testDir, "serializers.json")
+	config := `{
+		"defaults": {"format": "avro", "compression": "snappy"},
+		"subjects": {
+			"users-value": {"versionPolicy": "pinned", "version": 1, "compression": "gzip"},
+			"events": {"format": "json", "compression": "zstd", "encryptionKey": "events-key"}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	keys, err := encryption.NewStaticKeys("k1", map[string][]byte{"k1": bytes.Repeat([]byte{7}, 32)})
+	if err != nil {
+		t.Fatalf("Failed to create keys: %v", err)
+	}
+	cfg := DefaultConfig()
+	cfg.SerializersFile = path
+	cfg.Ciphers = map[string]Cipher{"events-key": NewKeyCipher(keys)}
+	registry := newPinningRegistry(t)
+	codec, err := NewCodecFromConfig(cfg, registry)
+	if err != nil {
+		t.Fatalf("Failed to create codec: %v", err)
+	}
+	serializers := codec.(*SerializerRegistry)
+
+	if s := serializers.Config("users"); s.Format != FormatAvro || s.VersionPolicy != VersionPinned || s.Compression != CompressionGzip {
+		t.Errorf("Unexpected users settings: %+v", s)
+	}
+	if s := serializers.Config("orders"); s.Format != FormatAvro || s.VersionPolicy != VersionLatest || s.Compression != CompressionSnappy {
+		t.Errorf("Expected orders to use the defaults, got %+v", s)
+	}
+
+	cluster := newFakeCluster()
+	broker := newTestBroker(t, cluster, serializers)
+	defer broker.Close()
+
+	manager, _ := avro.NewManager("")
+	user := manager.CreateSampleUsers(1)[0]
+	event := map[string]interface{}{"type": "page_view", "userId": float64(7)}
+	ctx := context.Background()
+	if err := broker.PublishValue(ctx, "users", user.Email, user); err != nil {
+		t.Fatalf("Failed to publish user: %v", err)
+	}
+	if err := broker.PublishValue(ctx, "events", "7", event); err != nil {
+		t.Fatalf("Failed to publish event: %v", err)
+	}
+
+	// Pinned users are written with version 1, then gzipped
+	raw, err := compressors[CompressionGzip].Decompress(cluster.topics["users"][0].Value)
+	if err != nil {
+		t.Fatalf("Expected a gzipped users payload: %v", err)
+	}
+	schemaID, _, err := DecodeWireFormat(raw)
+	v1, _ := registry.GetSchemaVersion("users-value", 1)
+	if err != nil || schemaID != v1.ID {
+		t.Errorf("Expected schema ID %d, got %d (%v)", v1.ID, schemaID, err)
+	}
+
+	// Events are JSON, compressed, then encrypted
+	events := cluster.topics["events"][0]
+	if bytes.Contains(events.Value, []byte("page_view")) {
+		t.Error("Expected the event payload to be encrypted")
+	}
+	if contentType := string(events.Headers[0].Value); contentType != "application/json" {
+		t.Errorf("Expected the events content type to be JSON, got %s", contentType)
+	}
+
+	var decodedUser avro.User
+	if err := serializers.Decode("users", cluster.topics["users"][0].Value, &decodedUser); err != nil || decodedUser.Email != user.Email {
+		t.Errorf("Failed to decode user: %+v (%v)", decodedUser, err)
+	}
+	var decodedEvent map[string]interface{}
+	if err := serializers.Decode("events", events.Value, &decodedEvent); err != nil || decodedEvent["type"] != "page_view" {
+		t.Errorf("Failed to decode event: %v (%v)", decodedEvent, err)
+	}
+
+	// Consumers without the key cannot read encrypted subjects
+	keyless, _ := NewSerializerRegistry(serializers.cfg, registry)
+	if err := keyless.Decode("events", events.Value, &decodedEvent); err == nil {
+		t.Error("Expected decoding without a cipher to fail")
+	}
+
+	// Values sealed before a key rotation keep decrypting
+	if err := keys.Add("k2", bytes.Repeat([]byte{9}, 32)); err != nil {
+		t.Fatalf("Failed to add key: %v", err)
+	}
+	if err := keys.Rotate("k2"); err != nil {
+		t.Fatalf("Failed to rotate key: %v", err)
+	}
+	decodedEvent = nil
+	if err := serializers.Decode("events", events.Value, &decodedEvent); err != nil || decodedEvent["type"] != "page_view" {
+		t.Errorf("Failed to decode event after rotation: %v (%v)", decodedEvent, err)
+	}
+
+	// A broker given no codec builds it from its configuration, without a registry to pin with
+	if _, err := NewBroker(cfg, nil, nil); err == nil {
+		t.Error("Expected pinned subjects without a registry to fail")
+	}
+	unpinned := cfg
+	unpinned.SerializersFile = ""
+	unpinned.Serializers = SerializerConfig{Subjects: map[string]SubjectConfig{
+		"events": {Format: FormatJSON, EncryptionKey: "events-key"},
+	}}
+	configured, err := NewBroker(unpinned, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create broker from config: %v", err)
+	}
+	defer configured.Close()
+	if s, ok := configured.codec.(*SerializerRegistry); !ok || s.Config("events").EncryptionKey != "events-key" {
+		t.Errorf("Expected a serializer registry codec, got %T", configured.codec)
+	}
+
+	t.Log("✓ Producers and consumers serialize each subject as configured")
+}
+
+func TestSerializerConfigValidation(t *testing.T) {
+	cases := map[string]SerializerConfig{
+		"format":      {Defaults: SubjectConfig{Format: "xml"}},
+		"compression": {Subjects: map[string]SubjectConfig{"users": {Compression: "brotli"}}},
+		"policy":      {Subjects: map[string]SubjectConfig{"users": {VersionPolicy: "oldest"}}},
+		"version":     {Subjects: map[string]SubjectConfig{"users": {VersionPolicy: VersionPinned}}},
+		"pin format":  {Subjects: map[string]SubjectConfig{"users": {Format: FormatJSON, VersionPolicy: VersionPinned, Version: 1}}},
+		"pin default": {Defaults: SubjectConfig{VersionPolicy: VersionPinned, Version: 1}},
+	}
+	for name, cfg := range cases {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+
+	// Pinning a version the registry does not have fails at construction
+	cfg := SerializerConfig{Subjects: map[string]SubjectConfig{"users": {VersionPolicy: VersionPinned, Version: 3}}}
+	if _, err := NewSerializerRegistry(cfg, newPinningRegistry(t)); err == nil {
+		t.Error("Expected an error pinning an unregistered version")
+	}
+
+	// SchemaPins become pinned subjects of the serializer config
+	kafkaCfg := DefaultConfig()
+	kafkaCfg.Serializers = SerializerConfig{Defaults: SubjectConfig{Compression: CompressionZstd}}
+	kafkaCfg.SchemaPins = map[string]int{"users-value": 2}
+	codec, err := NewCodecFromConfig(kafkaCfg, newPinningRegistry(t))
+	if err != nil {
+		t.Fatalf("Failed to create codec: %v", err)
+	}
+	avroCodec := codec.(*SerializerRegistry).Codec(FormatAvro).(*AvroCodec)
+	if version, ok := avroCodec.PinnedVersion("users-value"); !ok || version != 2 {
+		t.Errorf("Expected users-value pinned to 2, got %d (%v)", version, ok)
+	}
+
+	t.Log("✓ Invalid serializer settings are rejected")
+}
+
+func TestSerializerRegistryDecompressLimit(t *testing.T) {
+	oversized := make([]byte, compress.MaxDecompressedSize+1)
+	for _, compression := range []Compression{CompressionGzip, CompressionSnappy, CompressionZstd} {
+		cfg := SerializerConfig{Defaults: SubjectConfig{Format: FormatJSON, Compression: compression}}
+		serializers, err := NewSerializerRegistry(cfg, newPinningRegistry(t))
+		if err != nil {
+			t.Fatalf("Failed to create serializers: %v", err)
+		}
+		value, err := compressors[compression].Compress(oversized)
+		if err != nil {
+			t.Fatalf("%s: failed to compress: %v", compression, err)
+		}
+
+		var v interface{}
+		if err := serializers.Decode("events", value, &v); !errors.Is(err, compress.ErrTooLarge) {
+			t.Errorf("%s: expected a %d byte value to be rejected, got %v", compression, len(oversized), err)
+		}
+	}
+
+	t.Log("✓ Consumed values inflating past the size limit are rejected")
+}
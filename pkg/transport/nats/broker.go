@@ -0,0 +1,371 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	natsgo "github.com/nats-io/nats.go"
+	"github.com/nats-io/nuid"
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/transport/kafka"
+)
+
+const (
+	// contentTypeHeader carries the codec MIME type on typed messages
+	contentTypeHeader = "content-type"
+	// timestampHeader carries the publish time, which core NATS does not record
+	timestampHeader = "timestamp"
+)
+
+// Codec serializes message values for a subject. The Kafka transport's
+// Avro, Protobuf and JSON codecs satisfy it
+type Codec = kafka.Codec
+
+// NewCodec creates the codec for a serialization format
+func NewCodec(format kafka.Format) (Codec, error) {
+	return kafka.NewCodec(format, nil)
+}
+
+// conn is the subset of a NATS connection used by the broker
+type conn interface {
+	PublishMsg(msg *natsgo.Msg) error
+	Subscribe(subject, queue string, handler natsgo.MsgHandler) (unsubscriber, error)
+	FlushWithContext(ctx context.Context) error
+	// Close drains subscriptions and pending publishes, then closes the connection
+	Close() error
+}
+
+// unsubscriber is the subset of a NATS subscription used by the broker
+type unsubscriber interface {
+	Unsubscribe() error
+}
+
+// subscription tracks a running subscriber
+type subscription struct {
+	sub  unsubscriber
+	stop func() bool
+}
+
+// Broker publishes and subscribes to NATS subjects, implementing types.MessageBroker.
+// Core NATS delivers at most once: a message published while no subscriber is
+// connected, or whose handler fails, is not redelivered.
+type Broker struct {
+	cfg    Config
+	codec  Codec
+	logger *logger.Logger
+	conn   conn
+
+	connected  atomic.Bool
+	reconnects atomic.Int64
+
+	mu            sync.Mutex
+	subscriptions map[string]*subscription
+	closed        bool
+}
+
+var _ types.MessageBroker = (*Broker)(nil)
+
+// NewBroker connects to NATS and uses codec for typed messages. A nil codec
+// is created from cfg.Format
+func NewBroker(cfg Config, codec Codec, log *logger.Logger) (*Broker, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("NATS URL is required")
+	}
+	if codec == nil {
+		var err error
+		if codec, err = NewCodec(cfg.Format); err != nil {
+			return nil, err
+		}
+	}
+
+	b := newBroker(cfg, codec, log, nil)
+	closed := make(chan struct{})
+	nc, err := natsgo.Connect(cfg.URL,
+		natsgo.Name(cfg.Name),
+		natsgo.MaxReconnects(cfg.MaxReconnects),
+		natsgo.ReconnectWait(cfg.ReconnectWait),
+		natsgo.ReconnectBufSize(cfg.ReconnectBufSize),
+		natsgo.RetryOnFailedConnect(cfg.RetryOnFailedConnect),
+		natsgo.DrainTimeout(cfg.DrainTimeout),
+		natsgo.ConnectHandler(func(nc *natsgo.Conn) { b.onConnect(nc.ConnectedUrlRedacted()) }),
+		natsgo.DisconnectErrHandler(func(_ *natsgo.Conn, err error) { b.onDisconnect(err) }),
+		natsgo.ReconnectHandler(func(nc *natsgo.Conn) { b.onReconnect(nc.ConnectedUrlRedacted()) }),
+		natsgo.ClosedHandler(func(*natsgo.Conn) {
+			b.connected.Store(false)
+			close(closed)
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS at %s: %w", cfg.URL, err)
+	}
+	if nc.IsConnected() {
+		b.onConnect(nc.ConnectedUrlRedacted())
+	}
+
+	b.conn = &natsConn{conn: nc, closed: closed}
+	return b, nil
+}
+
+// newBroker wires a broker around an explicit connection
+func newBroker(cfg Config, codec Codec, log *logger.Logger, c conn) *Broker {
+	if log == nil {
+		log = logger.Global()
+	}
+
+	b := &Broker{
+		cfg:           cfg,
+		codec:         codec,
+		logger:        log.WithComponent("nats"),
+		conn:          c,
+		subscriptions: make(map[string]*subscription),
+	}
+	if c != nil {
+		b.connected.Store(true)
+	}
+	return b
+}
+
+// Publish publishes a raw payload to a subject. It returns once the message is
+// buffered; while reconnecting, messages are buffered up to ReconnectBufSize
+func (b *Broker) Publish(ctx context.Context, topic string, message []byte) error {
+	return b.publish(ctx, &natsgo.Msg{Subject: topic, Data: message, Header: natsgo.Header{}})
+}
+
+// PublishValue serializes v with the broker codec and publishes it
+func (b *Broker) PublishValue(ctx context.Context, topic string, v interface{}) error {
+	payload, err := b.codec.Encode(topic, v)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	header := natsgo.Header{}
+	header.Set(contentTypeHeader, b.contentType(topic))
+	return b.publish(ctx, &natsgo.Msg{Subject: topic, Data: payload, Header: header})
+}
+
+// contentType returns the MIME type the codec encodes topic's values as
+func (b *Broker) contentType(topic string) string {
+	if c, ok := b.codec.(interface{ TopicContentType(string) string }); ok {
+		return c.TopicContentType(topic)
+	}
+	return b.codec.ContentType()
+}
+
+// Decode deserializes a received message into v with the broker codec
+func (b *Broker) Decode(message types.Message, v interface{}) error {
+	if err := b.codec.Decode(message.Topic, message.Data, v); err != nil {
+		return fmt.Errorf("failed to decode message %s: %w", message.ID, err)
+	}
+	return nil
+}
+
+// Flush waits until the server has processed everything published so far
+func (b *Broker) Flush(ctx context.Context) error {
+	if err := b.conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("failed to flush NATS connection: %w", err)
+	}
+	return nil
+}
+
+// Subscribe calls handler for every message on a subject, joining the
+// configured queue group when there is one. The subscription ends when ctx is
+// done or Unsubscribe is called; subjects may use the * and > wildcards
+func (b *Broker) Subscribe(ctx context.Context, topic string, handler types.MessageHandler) error {
+	return b.QueueSubscribe(ctx, topic, b.cfg.QueueGroup, handler)
+}
+
+// QueueSubscribe subscribes as a member of queue, so each message is handled
+// by one member of the group; an empty queue receives every message
+func (b *Broker) QueueSubscribe(ctx context.Context, topic, queue string, handler types.MessageHandler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return fmt.Errorf("broker is closed")
+	}
+	if _, exists := b.subscriptions[topic]; exists {
+		return fmt.Errorf("already subscribed to topic %s", topic)
+	}
+
+	sub, err := b.conn.Subscribe(topic, queue, func(msg *natsgo.Msg) {
+		b.handle(ctx, msg, handler)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to topic %s: %w", topic, err)
+	}
+
+	s := &subscription{sub: sub}
+	s.stop = context.AfterFunc(ctx, func() {
+		// Only the subscription ctx belongs to, not a later one on the same topic
+		b.mu.Lock()
+		current := b.subscriptions[topic] == s
+		if current {
+			delete(b.subscriptions, topic)
+		}
+		b.mu.Unlock()
+		if current {
+			s.sub.Unsubscribe()
+		}
+	})
+	b.subscriptions[topic] = s
+
+	b.logger.Info("Subscribed to topic", zap.String("topic", topic), zap.String("queue_group", queue))
+	return nil
+}
+
+// Unsubscribe stops receiving messages on a subject
+func (b *Broker) Unsubscribe(ctx context.Context, topic string) error {
+	b.mu.Lock()
+	sub, exists := b.subscriptions[topic]
+	delete(b.subscriptions, topic)
+	b.mu.Unlock()
+
+	if !exists {
+		return fmt.Errorf("not subscribed to topic %s", topic)
+	}
+
+	sub.stop()
+	if err := sub.sub.Unsubscribe(); err != nil {
+		return fmt.Errorf("failed to unsubscribe from topic %s: %w", topic, err)
+	}
+	return nil
+}
+
+// Close drains subscriptions and pending publishes, then closes the connection
+func (b *Broker) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	subs := b.subscriptions
+	b.subscriptions = make(map[string]*subscription)
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.stop()
+	}
+
+	if err := b.conn.Close(); err != nil {
+		return fmt.Errorf("failed to close NATS connection: %w", err)
+	}
+	return nil
+}
+
+// Connected reports whether the connection is currently up
+func (b *Broker) Connected() bool {
+	return b.connected.Load()
+}
+
+// Reconnects returns how many times the connection has been re-established
+func (b *Broker) Reconnects() int64 {
+	return b.reconnects.Load()
+}
+
+// publish stamps a message id and hands msg to the connection
+func (b *Broker) publish(ctx context.Context, msg *natsgo.Msg) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	msg.Header.Set(natsgo.MsgIdHdr, nuid.Next())
+	msg.Header.Set(timestampHeader, time.Now().UTC().Format(time.RFC3339Nano))
+
+	if err := b.conn.PublishMsg(msg); err != nil {
+		return fmt.Errorf("failed to publish to topic %s: %w", msg.Subject, err)
+	}
+	return nil
+}
+
+// handle runs handler for one delivered message, logging failures
+func (b *Broker) handle(ctx context.Context, msg *natsgo.Msg, handler types.MessageHandler) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	message := toMessage(msg)
+	if err := handler(ctx, message); err != nil {
+		b.logger.Warn("Message handler failed",
+			zap.String("topic", message.Topic),
+			zap.String("message_id", message.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+// onConnect records the initial connection
+func (b *Broker) onConnect(url string) {
+	b.connected.Store(true)
+	b.logger.Info("Connected to NATS", zap.String("url", url))
+}
+
+// onDisconnect records a lost connection; publishes are buffered until it returns
+func (b *Broker) onDisconnect(err error) {
+	b.connected.Store(false)
+	b.logger.Warn("Disconnected from NATS", zap.Error(err))
+}
+
+// onReconnect records a re-established connection; subscriptions are restored by the client
+func (b *Broker) onReconnect(url string) {
+	b.connected.Store(true)
+	b.reconnects.Add(1)
+	b.logger.Info("Reconnected to NATS", zap.String("url", url), zap.Int64("reconnects", b.reconnects.Load()))
+}
+
+// toMessage converts a NATS message to the transport-neutral message type
+func toMessage(msg *natsgo.Msg) types.Message {
+	headers := make(map[string]string, len(msg.Header))
+	for key := range msg.Header {
+		headers[key] = msg.Header.Get(key)
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, headers[timestampHeader])
+	if err != nil {
+		timestamp = time.Now()
+	}
+
+	return types.Message{
+		ID:        headers[natsgo.MsgIdHdr],
+		Topic:     msg.Subject,
+		Data:      msg.Data,
+		Headers:   headers,
+		Timestamp: timestamp,
+	}
+}
+
+// natsConn adapts *natsgo.Conn to conn
+type natsConn struct {
+	conn   *natsgo.Conn
+	closed chan struct{}
+}
+
+func (c *natsConn) PublishMsg(msg *natsgo.Msg) error {
+	return c.conn.PublishMsg(msg)
+}
+
+func (c *natsConn) Subscribe(subject, queue string, handler natsgo.MsgHandler) (unsubscriber, error) {
+	if queue != "" {
+		return c.conn.QueueSubscribe(subject, queue, handler)
+	}
+	return c.conn.Subscribe(subject, handler)
+}
+
+func (c *natsConn) FlushWithContext(ctx context.Context) error {
+	return c.conn.FlushWithContext(ctx)
+}
+
+func (c *natsConn) Close() error {
+	if err := c.conn.Drain(); err != nil {
+		c.conn.Close()
+		return err
+	}
+	// Drain closes the connection once it finishes or DrainTimeout elapses
+	<-c.closed
+	return nil
+}
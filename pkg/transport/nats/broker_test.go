@@ -0,0 +1,301 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	natsgo "github.com/nats-io/nats.go"
+
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/transport/kafka"
+)
+
+// fakeServer is an in-memory stand-in for a NATS server: plain subscribers get
+// every message on their subject, queue groups share them round-robin
+type fakeServer struct {
+	mu     sync.Mutex
+	subs   map[string][]*fakeSub
+	next   map[string]int // subject/queue -> next member
+	closed bool
+}
+
+type fakeSub struct {
+	server  *fakeServer
+	subject string
+	queue   string
+	handler natsgo.MsgHandler
+}
+
+func newFakeServer() *fakeServer {
+	return &fakeServer{subs: make(map[string][]*fakeSub), next: make(map[string]int)}
+}
+
+// conn returns a client connection to the server
+func (s *fakeServer) conn() conn {
+	return &fakeConn{server: s}
+}
+
+func (s *fakeServer) deliver(msg *natsgo.Msg) {
+	s.mu.Lock()
+	var targets []*fakeSub
+	groups := make(map[string][]*fakeSub)
+	for _, sub := range s.subs[msg.Subject] {
+		if sub.queue == "" {
+			targets = append(targets, sub)
+		} else {
+			groups[sub.queue] = append(groups[sub.queue], sub)
+		}
+	}
+	for queue, members := range groups {
+		key := msg.Subject + "/" + queue
+		targets = append(targets, members[s.next[key]%len(members)])
+		s.next[key]++
+	}
+	s.mu.Unlock()
+
+	for _, sub := range targets {
+		sub.handler(&natsgo.Msg{Subject: msg.Subject, Data: msg.Data, Header: msg.Header})
+	}
+}
+
+func (sub *fakeSub) Unsubscribe() error {
+	s := sub.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subs := s.subs[sub.subject]
+	for i, other := range subs {
+		if other == sub {
+			s.subs[sub.subject] = append(subs[:i:i], subs[i+1:]...)
+			return nil
+		}
+	}
+	return natsgo.ErrBadSubscription
+}
+
+type fakeConn struct {
+	server *fakeServer
+	subs   []*fakeSub
+}
+
+func (c *fakeConn) PublishMsg(msg *natsgo.Msg) error {
+	c.server.deliver(msg)
+	return nil
+}
+
+func (c *fakeConn) Subscribe(subject, queue string, handler natsgo.MsgHandler) (unsubscriber, error) {
+	sub := &fakeSub{server: c.server, subject: subject, queue: queue, handler: handler}
+	c.server.mu.Lock()
+	c.server.subs[subject] = append(c.server.subs[subject], sub)
+	c.server.mu.Unlock()
+	c.subs = append(c.subs, sub)
+	return sub, nil
+}
+
+func (c *fakeConn) FlushWithContext(ctx context.Context) error {
+	return ctx.Err()
+}
+
+func (c *fakeConn) Close() error {
+	for _, sub := range c.subs {
+		sub.Unsubscribe()
+	}
+	return nil
+}
+
+func newTestBroker(t *testing.T, server *fakeServer, cfg Config, codec Codec) *Broker {
+	t.Helper()
+	log, err := logger.NewDevelopment()
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	return newBroker(cfg, codec, log, server.conn())
+}
+
+func TestBrokerPublishSubscribe(t *testing.T) {
+	server := newFakeServer()
+	codec, err := NewCodec(kafka.FormatAvro)
+	if err != nil {
+		t.Fatalf("Failed to create codec: %v", err)
+	}
+	broker := newTestBroker(t, server, DefaultConfig(), codec)
+	defer broker.Close()
+
+	received := make(chan types.Message, 4)
+	err = broker.Subscribe(context.Background(), "users", func(ctx context.Context, msg types.Message) error {
+		received <- msg
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	if err := broker.Publish(context.Background(), "users", []byte("raw")); err != nil {
+		t.Fatalf("Failed to publish: %v", err)
+	}
+	msg := <-received
+	if string(msg.Data) != "raw" || msg.ID == "" || msg.Topic != "users" || time.Since(msg.Timestamp) > time.Minute {
+		t.Errorf("Unexpected raw message: %+v", msg)
+	}
+
+	manager, _ := avro.NewManager("")
+	user := manager.CreateSampleUsers(1)[0]
+	if err := broker.PublishValue(context.Background(), "users", user); err != nil {
+		t.Fatalf("Failed to publish user: %v", err)
+	}
+	msg = <-received
+	if msg.Headers[contentTypeHeader] != codec.ContentType() {
+		t.Errorf("Expected content type %s, got %q", codec.ContentType(), msg.Headers[contentTypeHeader])
+	}
+	var decoded avro.User
+	if err := broker.Decode(msg, &decoded); err != nil {
+		t.Fatalf("Failed to decode user: %v", err)
+	}
+	if decoded.ID != user.ID || decoded.Email != user.Email {
+		t.Errorf("Expected user %d, got %+v", user.ID, decoded)
+	}
+
+	if err := broker.Subscribe(context.Background(), "users", nil); err == nil {
+		t.Error("Expected error subscribing twice to a topic")
+	}
+
+	t.Log("✓ Raw and codec-encoded messages reach subscribers")
+}
+
+func TestBrokerQueueGroups(t *testing.T) {
+	server := newFakeServer()
+	cfg := DefaultConfig()
+	cfg.QueueGroup = "workers"
+	codec := kafka.JSONCodec{}
+
+	var mu sync.Mutex
+	counts := make(map[string]int)
+	count := func(name string) types.MessageHandler {
+		return func(ctx context.Context, msg types.Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			counts[name]++
+			return nil
+		}
+	}
+
+	for _, name := range []string{"worker-1", "worker-2"} {
+		worker := newTestBroker(t, server, cfg, codec)
+		defer worker.Close()
+		if err := worker.Subscribe(context.Background(), "orders", count(name)); err != nil {
+			t.Fatalf("Failed to subscribe %s: %v", name, err)
+		}
+	}
+	// A subscriber outside the group sees every message
+	audit := newTestBroker(t, server, DefaultConfig(), codec)
+	defer audit.Close()
+	if err := audit.Subscribe(context.Background(), "orders", count("audit")); err != nil {
+		t.Fatalf("Failed to subscribe audit: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := audit.PublishValue(context.Background(), "orders", map[string]int{"id": i}); err != nil {
+			t.Fatalf("Failed to publish: %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if counts["worker-1"]+counts["worker-2"] != 10 || counts["worker-1"] == 0 || counts["worker-2"] == 0 {
+		t.Errorf("Expected the queue group to share 10 messages, got %v", counts)
+	}
+	if counts["audit"] != 10 {
+		t.Errorf("Expected the fan-out subscriber to get 10 messages, got %d", counts["audit"])
+	}
+
+	t.Log("✓ Queue groups share messages while plain subscribers get them all")
+}
+
+func TestBrokerUnsubscribe(t *testing.T) {
+	server := newFakeServer()
+	broker := newTestBroker(t, server, DefaultConfig(), kafka.JSONCodec{})
+
+	var mu sync.Mutex
+	var got []string
+	handler := func(ctx context.Context, msg types.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, string(msg.Data))
+		return errors.New("handler errors are logged, not redelivered")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := broker.Subscribe(ctx, "events", handler); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	broker.Publish(context.Background(), "events", []byte("one"))
+
+	// Cancelling the subscription context unsubscribes
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for {
+		broker.mu.Lock()
+		_, subscribed := broker.subscriptions["events"]
+		broker.mu.Unlock()
+		if !subscribed || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	broker.Publish(context.Background(), "events", []byte("two"))
+
+	if err := broker.Subscribe(context.Background(), "events", handler); err != nil {
+		t.Fatalf("Failed to resubscribe: %v", err)
+	}
+	broker.Publish(context.Background(), "events", []byte("three"))
+	if err := broker.Unsubscribe(context.Background(), "events"); err != nil {
+		t.Fatalf("Failed to unsubscribe: %v", err)
+	}
+	broker.Publish(context.Background(), "events", []byte("four"))
+
+	mu.Lock()
+	if len(got) != 2 || got[0] != "one" || got[1] != "three" {
+		t.Errorf("Expected [one three], got %v", got)
+	}
+	mu.Unlock()
+
+	if err := broker.Unsubscribe(context.Background(), "events"); err == nil {
+		t.Error("Expected error unsubscribing twice")
+	}
+	broker.Close()
+	if err := broker.Subscribe(context.Background(), "events", handler); err == nil {
+		t.Error("Expected error subscribing on a closed broker")
+	}
+
+	t.Log("✓ Subscriptions end on Unsubscribe, context cancellation and Close")
+}
+
+func TestBrokerReconnectTracking(t *testing.T) {
+	broker := newTestBroker(t, newFakeServer(), DefaultConfig(), kafka.JSONCodec{})
+	defer broker.Close()
+
+	if !broker.Connected() || broker.Reconnects() != 0 {
+		t.Fatalf("Expected a connected broker with no reconnects")
+	}
+
+	broker.onDisconnect(errors.New("connection reset"))
+	if broker.Connected() {
+		t.Error("Expected broker to report disconnected")
+	}
+	broker.onReconnect("nats://localhost:4222")
+	broker.onDisconnect(nil)
+	broker.onReconnect("nats://localhost:4223")
+	if !broker.Connected() || broker.Reconnects() != 2 {
+		t.Errorf("Expected connected after 2 reconnects, got %v / %d", broker.Connected(), broker.Reconnects())
+	}
+
+	if _, err := NewBroker(Config{}, nil, nil); err == nil {
+		t.Error("Expected error without a URL")
+	}
+
+	t.Log("✓ Disconnects and reconnects are tracked")
+}
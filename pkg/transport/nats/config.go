@@ -0,0 +1,58 @@
+package nats
+
+import (
+	"time"
+
+	"go-transport-prac/internal/config"
+	"go-transport-prac/pkg/transport/kafka"
+)
+
+// Config holds NATS connection and subscription settings
+type Config struct {
+	// URL is a comma-separated list of servers to connect to
+	URL  string
+	Name string
+	// Format selects the codec for typed messages, shared with the Kafka transport
+	Format kafka.Format
+	// QueueGroup makes Subscribe join a queue group, so each message goes to
+	// one member instead of every subscriber; empty fans out
+	QueueGroup string
+
+	// MaxReconnects is how many reconnect attempts are made before the
+	// connection is closed for good; negative retries forever
+	MaxReconnects int
+	// ReconnectWait is the delay between reconnect attempts to the same server
+	ReconnectWait time.Duration
+	// ReconnectBufSize bounds the bytes published while disconnected that are
+	// buffered and flushed after reconnecting
+	ReconnectBufSize int
+	// RetryOnFailedConnect keeps connecting in the background when no server is reachable at startup
+	RetryOnFailedConnect bool
+	// DrainTimeout bounds how long Close waits for subscriptions to finish pending messages
+	DrainTimeout time.Duration
+}
+
+// DefaultConfig returns a configuration for a local NATS server
+func DefaultConfig() Config {
+	return Config{
+		URL:              "nats://localhost:4222",
+		Name:             "go-transport-prac",
+		Format:           kafka.FormatAvro,
+		MaxReconnects:    -1,
+		ReconnectWait:    2 * time.Second,
+		ReconnectBufSize: 8 * 1024 * 1024,
+		DrainTimeout:     10 * time.Second,
+	}
+}
+
+// NewConfig builds a NATS configuration from the application configuration
+func NewConfig(cfg config.NATSConfig) Config {
+	natsCfg := DefaultConfig()
+	natsCfg.URL = cfg.URL
+	natsCfg.Name = cfg.Name
+	natsCfg.Format = kafka.Format(cfg.Format)
+	natsCfg.QueueGroup = cfg.QueueGroup
+	natsCfg.MaxReconnects = cfg.MaxReconnects
+	natsCfg.ReconnectWait = cfg.ReconnectWait
+	return natsCfg
+}
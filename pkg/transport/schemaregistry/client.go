@@ -0,0 +1,185 @@
+package schemaregistry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-transport-prac/internal/resilience"
+)
+
+// Client talks to a Confluent-compatible schema registry, this package's
+// Server included. Requests failing with a network error, a 5xx or a 429
+// are retried with backoff behind a circuit breaker; other failures return
+// an *APIError at once
+type Client struct {
+	baseURL string
+	http    *nethttp.Client
+	retry   resilience.RetryPolicy
+	breaker *resilience.Breaker
+	policy  resilience.Policy
+}
+
+// NewClient returns a client for the registry at baseURL, e.g.
+// http://localhost:8085
+func NewClient(baseURL string) *Client {
+	c := &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    &nethttp.Client{Timeout: 10 * time.Second},
+		retry:   resilience.DefaultRetryPolicy(),
+		breaker: resilience.NewBreaker(resilience.DefaultBreakerConfig()),
+	}
+	c.compose()
+	return c
+}
+
+// WithHTTPClient sets the HTTP client requests are sent with
+func (c *Client) WithHTTPClient(client *nethttp.Client) *Client {
+	c.http = client
+	return c
+}
+
+// WithRetry sets the retry policy; a MaxAttempts of 1 disables retries
+func (c *Client) WithRetry(policy resilience.RetryPolicy) *Client {
+	c.retry = policy
+	c.compose()
+	return c
+}
+
+// WithBreaker sets the circuit breaker; nil disables it
+func (c *Client) WithBreaker(breaker *resilience.Breaker) *Client {
+	c.breaker = breaker
+	c.compose()
+	return c
+}
+
+// compose retries around the breaker, so an open breaker ends the retries
+func (c *Client) compose() {
+	if c.breaker == nil {
+		c.policy = c.retry
+		return
+	}
+	c.policy = resilience.Compose(c.retry, c.breaker)
+}
+
+// RegisterSchema registers schema under subject, returning its global ID.
+// Registering a schema the subject already has returns the existing ID
+func (c *Client) RegisterSchema(ctx context.Context, subject, schema string) (int, error) {
+	var resp struct {
+		ID int `json:"id"`
+	}
+	err := c.do(ctx, nethttp.MethodPost, "/subjects/"+url.PathEscape(subject)+"/versions", schemaRequest{Schema: schema}, &resp)
+	if err != nil {
+		return 0, fmt.Errorf("failed to register schema for subject %s: %w", subject, err)
+	}
+	return resp.ID, nil
+}
+
+// GetSchema returns the schema with the given global ID
+func (c *Client) GetSchema(ctx context.Context, id int) (string, error) {
+	var resp struct {
+		Schema string `json:"schema"`
+	}
+	if err := c.do(ctx, nethttp.MethodGet, "/schemas/ids/"+strconv.Itoa(id), nil, &resp); err != nil {
+		return "", fmt.Errorf("failed to get schema %d: %w", id, err)
+	}
+	return resp.Schema, nil
+}
+
+// LatestVersion returns the latest version of subject
+func (c *Client) LatestVersion(ctx context.Context, subject string) (SchemaVersion, error) {
+	return c.version(ctx, subject, "latest")
+}
+
+// Version returns the given version of subject
+func (c *Client) Version(ctx context.Context, subject string, version int) (SchemaVersion, error) {
+	return c.version(ctx, subject, strconv.Itoa(version))
+}
+
+func (c *Client) version(ctx context.Context, subject, version string) (SchemaVersion, error) {
+	var resp SchemaVersion
+	if err := c.do(ctx, nethttp.MethodGet, "/subjects/"+url.PathEscape(subject)+"/versions/"+version, nil, &resp); err != nil {
+		return SchemaVersion{}, fmt.Errorf("failed to get version %s of subject %s: %w", version, subject, err)
+	}
+	return resp, nil
+}
+
+// Subjects returns the registered subjects
+func (c *Client) Subjects(ctx context.Context) ([]string, error) {
+	var subjects []string
+	if err := c.do(ctx, nethttp.MethodGet, "/subjects", nil, &subjects); err != nil {
+		return nil, fmt.Errorf("failed to list subjects: %w", err)
+	}
+	return subjects, nil
+}
+
+// Versions returns the versions registered under subject
+func (c *Client) Versions(ctx context.Context, subject string) ([]int, error) {
+	var versions []int
+	if err := c.do(ctx, nethttp.MethodGet, "/subjects/"+url.PathEscape(subject)+"/versions", nil, &versions); err != nil {
+		return nil, fmt.Errorf("failed to list versions of subject %s: %w", subject, err)
+	}
+	return versions, nil
+}
+
+// do sends a request under the client's policy and decodes the response
+// into out
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	return c.policy.Execute(ctx, func(ctx context.Context) error {
+		req, err := nethttp.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(payload))
+		if err != nil {
+			return resilience.Permanent(fmt.Errorf("failed to create request: %w", err))
+		}
+		req.Header.Set("Accept", ContentType)
+		if body != nil {
+			req.Header.Set("Content-Type", ContentType)
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != nethttp.StatusOK {
+			return responseError(resp)
+		}
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resilience.Permanent(fmt.Errorf("failed to decode response: %w", err))
+		}
+		return nil
+	})
+}
+
+// responseError turns an error response into an *APIError, marked permanent
+// unless the registry may recover from it
+func responseError(resp *nethttp.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	apiErr := &APIError{Code: resp.StatusCode, status: resp.StatusCode}
+	if json.Unmarshal(data, apiErr) != nil || apiErr.Message == "" {
+		apiErr.Message = strings.TrimSpace(string(data))
+		if apiErr.Message == "" {
+			apiErr.Message = nethttp.StatusText(resp.StatusCode)
+		}
+	}
+
+	if resp.StatusCode >= 500 || resp.StatusCode == nethttp.StatusTooManyRequests {
+		return apiErr
+	}
+	return resilience.Permanent(apiErr)
+}
@@ -0,0 +1,105 @@
+package schemaregistry
+
+import (
+	"context"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-transport-prac/internal/resilience"
+)
+
+// fastRetry keeps client tests quick
+var fastRetry = resilience.RetryPolicy{MaxAttempts: 3, Backoff: resilience.Backoff{Initial: time.Millisecond}}
+
+// countingServer serves the registry, failing the first failures requests
+// with status
+func countingServer(t *testing.T, status int, failures int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	handler := NewServer(DefaultConfig(), nil, nil).Handler()
+	ts := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if requests.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &requests
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	ts := startTestServer(t)
+	client := NewClient(ts.URL + "/").WithRetry(fastRetry)
+
+	id, err := client.RegisterSchema(ctx, "items-value", itemV1)
+	if err != nil || id != 1 {
+		t.Fatalf("Expected ID 1, got %d: %v", id, err)
+	}
+	if id2, _ := client.RegisterSchema(ctx, "items-value", itemV2); id2 != 2 {
+		t.Errorf("Expected ID 2, got %d", id2)
+	}
+
+	if schema, err := client.GetSchema(ctx, 1); err != nil || schema == "" {
+		t.Errorf("Expected schema 1, got %q: %v", schema, err)
+	}
+	latest, err := client.LatestVersion(ctx, "items-value")
+	if err != nil || latest.Version != 2 || latest.ID != 2 || latest.Subject != "items-value" {
+		t.Errorf("Expected version 2, got %+v: %v", latest, err)
+	}
+	if subjects, err := client.Subjects(ctx); err != nil || len(subjects) != 1 {
+		t.Errorf("Expected 1 subject, got %v: %v", subjects, err)
+	}
+	if versions, err := client.Versions(ctx, "items-value"); err != nil || len(versions) != 2 {
+		t.Errorf("Expected 2 versions, got %v: %v", versions, err)
+	}
+	if first, err := client.Version(ctx, "items-value", 1); err != nil || first.Version != 1 || first.Schema == "" {
+		t.Errorf("Expected version 1, got %+v: %v", first, err)
+	}
+	_, err = client.Version(ctx, "orders-value", 1)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != codeSubjectNotFound {
+		t.Errorf("Expected a subject not found error, got %v", err)
+	}
+
+	_, err = client.RegisterSchema(ctx, "items-value", incompatible)
+	if !errors.As(err, &apiErr) || apiErr.Code != codeIncompatibleSchema || apiErr.StatusCode() != nethttp.StatusConflict {
+		t.Errorf("Expected an incompatible schema error, got %v", err)
+	}
+
+	t.Log("✓ Client registers and looks up schemas")
+}
+
+func TestClientRetries(t *testing.T) {
+	ctx := context.Background()
+
+	ts, requests := countingServer(t, nethttp.StatusServiceUnavailable, 2)
+	client := NewClient(ts.URL).WithRetry(fastRetry)
+	if _, err := client.Subjects(ctx); err != nil || requests.Load() != 3 {
+		t.Errorf("Expected success on the third request, got %v after %d", err, requests.Load())
+	}
+
+	_, err := client.GetSchema(ctx, 42)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != codeSchemaNotFound || requests.Load() != 4 {
+		t.Errorf("Expected a 404 not to be retried, got %v after %d requests", err, requests.Load()-3)
+	}
+
+	ts, requests = countingServer(t, nethttp.StatusInternalServerError, 100)
+	client = NewClient(ts.URL).
+		WithRetry(fastRetry).
+		WithBreaker(resilience.NewBreaker(resilience.BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute}))
+	if _, err := client.Subjects(ctx); !errors.Is(err, resilience.ErrOpen) || requests.Load() != 2 {
+		t.Errorf("Expected the breaker to open after 2 failures, got %v after %d requests", err, requests.Load())
+	}
+	if _, err := client.Subjects(ctx); !errors.Is(err, resilience.ErrOpen) || requests.Load() != 2 {
+		t.Errorf("Expected an open breaker to skip the registry, got %v after %d requests", err, requests.Load())
+	}
+
+	t.Log("✓ Client retries transient failures behind a circuit breaker")
+}
@@ -0,0 +1,54 @@
+package schemaregistry
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"go-transport-prac/internal/config"
+)
+
+// Config holds schema registry server settings
+type Config struct {
+	// Addr is the host:port the server listens on
+	Addr string
+
+	TLSEnabled bool
+	CertFile   string
+	KeyFile    string
+
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+
+	// MaxBodyBytes bounds the size of a request body
+	MaxBodyBytes int64
+	// ShutdownTimeout bounds how long Shutdown waits for in-flight requests
+	ShutdownTimeout time.Duration
+}
+
+// DefaultConfig returns a plaintext configuration on the default registry port.
+// Confluent's own default, 8081, is taken by the gRPC server
+func DefaultConfig() Config {
+	return Config{
+		Addr:            "localhost:8085",
+		ReadTimeout:     30 * time.Second,
+		WriteTimeout:    30 * time.Second,
+		IdleTimeout:     120 * time.Second,
+		MaxBodyBytes:    1024 * 1024,
+		ShutdownTimeout: 10 * time.Second,
+	}
+}
+
+// NewConfig builds a registry configuration from the application server configuration
+func NewConfig(cfg config.ServerConfig) Config {
+	regCfg := DefaultConfig()
+	regCfg.Addr = net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.RegistryPort))
+	regCfg.TLSEnabled = cfg.TLSEnabled
+	regCfg.CertFile = cfg.CertFile
+	regCfg.KeyFile = cfg.KeyFile
+	regCfg.ReadTimeout = cfg.ReadTimeout
+	regCfg.WriteTimeout = cfg.WriteTimeout
+	regCfg.IdleTimeout = cfg.IdleTimeout
+	return regCfg
+}
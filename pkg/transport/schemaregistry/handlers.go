@@ -0,0 +1,305 @@
+package schemaregistry
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	nethttp "net/http"
+	"sort"
+	"strconv"
+
+	hamba "github.com/hamba/avro/v2"
+
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+// schemaTypeAvro is the only schema type served
+const schemaTypeAvro = "AVRO"
+
+// routes registers the Confluent REST endpoints
+func (s *Server) routes() {
+	s.handle("GET /subjects", s.listSubjects)
+	s.handle("POST /subjects/{subject}", s.lookupSchema)
+	s.handle("DELETE /subjects/{subject}", s.deleteSubject)
+	s.handle("GET /subjects/{subject}/versions", s.listVersions)
+	s.handle("POST /subjects/{subject}/versions", s.registerSchema)
+	s.handle("GET /subjects/{subject}/versions/{version}", s.getVersion)
+	s.handle("GET /subjects/{subject}/versions/{version}/schema", s.getVersionSchema)
+	s.handle("DELETE /subjects/{subject}/versions/{version}", s.deleteVersion)
+
+	s.handle("GET /schemas/types", s.listTypes)
+	s.handle("GET /schemas/ids/{id}", s.getSchema)
+	s.handle("GET /schemas/ids/{id}/schema", s.getRawSchema)
+
+	s.handle("POST /compatibility/subjects/{subject}/versions", s.checkCompatibility)
+	s.handle("POST /compatibility/subjects/{subject}/versions/{version}", s.checkCompatibility)
+
+	s.handle("GET /config", s.getConfig)
+	s.handle("GET /config/{subject}", s.getConfig)
+	s.handle("PUT /config/{subject}", s.putConfig)
+	s.handle("GET /mode/{subject}", s.getMode)
+	s.handle("PUT /mode/{subject}", s.putMode)
+}
+
+// schemaRequest is the body of register, lookup and compatibility requests
+type schemaRequest struct {
+	Schema     string                 `json:"schema"`
+	SchemaType string                 `json:"schemaType,omitempty"`
+	References []avro.SchemaReference `json:"references,omitempty"`
+}
+
+// SchemaVersion describes one version of a subject
+type SchemaVersion struct {
+	Subject string `json:"subject"`
+	ID      int    `json:"id"`
+	Version int    `json:"version"`
+	Schema  string `json:"schema"`
+}
+
+func newSchemaVersion(metadata avro.SchemaMetadata) SchemaVersion {
+	return SchemaVersion{
+		Subject: metadata.Subject,
+		ID:      metadata.ID,
+		Version: metadata.Version,
+		Schema:  metadata.SchemaJSON,
+	}
+}
+
+// readSchema decodes a schema request, rejecting schemas the registry cannot hold
+func readSchema(r *nethttp.Request) (schemaRequest, error) {
+	var req schemaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return req, newAPIError(nethttp.StatusUnprocessableEntity, codeInvalidSchema, fmt.Sprintf("invalid request body: %v", err))
+	}
+	if req.SchemaType != "" && req.SchemaType != schemaTypeAvro {
+		return req, newAPIError(nethttp.StatusUnprocessableEntity, codeInvalidSchema,
+			fmt.Sprintf("unsupported schema type %s; only %s is served", req.SchemaType, schemaTypeAvro))
+	}
+	if len(req.References) > 0 {
+		return req, newAPIError(nethttp.StatusUnprocessableEntity, codeInvalidSchema, "schema references are not supported")
+	}
+	if _, err := hamba.Parse(req.Schema); err != nil {
+		return req, newAPIError(nethttp.StatusUnprocessableEntity, codeInvalidSchema, fmt.Sprintf("invalid schema: %v", err))
+	}
+	return req, nil
+}
+
+// versionOf resolves the {version} path value, "latest" or a positive number
+func (s *Server) versionOf(r *nethttp.Request) (int, error) {
+	value := r.PathValue("version")
+	if value == "latest" || value == "-1" {
+		latest, err := s.registry.GetLatestSchema(r.PathValue("subject"))
+		if err != nil {
+			return 0, err
+		}
+		return latest.Version, nil
+	}
+
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 || version > math.MaxInt32 {
+		return 0, newAPIError(nethttp.StatusUnprocessableEntity, codeInvalidVersion,
+			fmt.Sprintf("the specified version %q is not a valid version id; use \"latest\" or a positive integer", value))
+	}
+	return version, nil
+}
+
+// idOf parses the {id} path value
+func idOf(r *nethttp.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return 0, newAPIError(nethttp.StatusNotFound, codeSchemaNotFound, fmt.Sprintf("schema %s not found", r.PathValue("id")))
+	}
+	return id, nil
+}
+
+func (s *Server) listSubjects(*nethttp.Request) (interface{}, error) {
+	subjects := s.registry.ListSubjects()
+	sort.Strings(subjects)
+	return subjects, nil
+}
+
+func (s *Server) listVersions(r *nethttp.Request) (interface{}, error) {
+	return s.registry.ListSchemaVersions(r.PathValue("subject"))
+}
+
+func (s *Server) registerSchema(r *nethttp.Request) (interface{}, error) {
+	req, err := readSchema(r)
+	if err != nil {
+		return nil, err
+	}
+	id, err := s.registry.RegisterSchema(r.PathValue("subject"), req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]int{"id": id}, nil
+}
+
+func (s *Server) lookupSchema(r *nethttp.Request) (interface{}, error) {
+	req, err := readSchema(r)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := s.registry.LookupSchema(r.PathValue("subject"), req.Schema)
+	if err != nil {
+		return nil, err
+	}
+	return newSchemaVersion(metadata), nil
+}
+
+func (s *Server) getVersion(r *nethttp.Request) (interface{}, error) {
+	version, err := s.versionOf(r)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := s.registry.GetSchemaVersion(r.PathValue("subject"), version)
+	if err != nil {
+		return nil, err
+	}
+	return newSchemaVersion(metadata), nil
+}
+
+func (s *Server) getVersionSchema(r *nethttp.Request) (interface{}, error) {
+	version, err := s.versionOf(r)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := s.registry.GetSchemaVersion(r.PathValue("subject"), version)
+	if err != nil {
+		return nil, err
+	}
+	return rawSchema(metadata.SchemaJSON), nil
+}
+
+func (s *Server) deleteSubject(r *nethttp.Request) (interface{}, error) {
+	return s.registry.DeleteSubject(r.PathValue("subject"), r.URL.Query().Get("permanent") == "true")
+}
+
+func (s *Server) deleteVersion(r *nethttp.Request) (interface{}, error) {
+	version, err := s.versionOf(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.registry.DeleteSchemaVersion(r.PathValue("subject"), version, r.URL.Query().Get("permanent") == "true")
+}
+
+func (s *Server) listTypes(*nethttp.Request) (interface{}, error) {
+	return []string{schemaTypeAvro}, nil
+}
+
+func (s *Server) getSchema(r *nethttp.Request) (interface{}, error) {
+	id, err := idOf(r)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := s.registry.GetSchema(id)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"schema": metadata.SchemaJSON}, nil
+}
+
+func (s *Server) getRawSchema(r *nethttp.Request) (interface{}, error) {
+	id, err := idOf(r)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := s.registry.GetSchema(id)
+	if err != nil {
+		return nil, err
+	}
+	return rawSchema(metadata.SchemaJSON), nil
+}
+
+// compatibilityResponse reports whether a schema may be registered;
+// Messages is only filled in with ?verbose=true
+type compatibilityResponse struct {
+	IsCompatible bool     `json:"is_compatible"`
+	Messages     []string `json:"messages,omitempty"`
+}
+
+// checkCompatibility checks a schema against one version, or against the
+// versions the subject's level requires when no version is given
+func (s *Server) checkCompatibility(r *nethttp.Request) (interface{}, error) {
+	req, err := readSchema(r)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := r.PathValue("subject")
+	var report *avro.CompatibilityReport
+	if r.PathValue("version") == "" {
+		report, err = s.registry.CheckCompatibilityReport(subject, req.Schema)
+	} else {
+		var version int
+		if version, err = s.versionOf(r); err != nil {
+			return nil, err
+		}
+		report, err = s.registry.CheckCompatibilityWithVersion(subject, version, req.Schema)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp := compatibilityResponse{IsCompatible: report.Compatible}
+	if r.URL.Query().Get("verbose") == "true" {
+		for _, inc := range report.Incompatibilities {
+			resp.Messages = append(resp.Messages, inc.String())
+		}
+	}
+	return resp, nil
+}
+
+// configRequest is the body of PUT /config/{subject}
+type configRequest struct {
+	Compatibility avro.CompatibilityLevel `json:"compatibility"`
+}
+
+// getConfig reports the compatibility level of a subject, or the default
+// level for GET /config
+func (s *Server) getConfig(r *nethttp.Request) (interface{}, error) {
+	return map[string]avro.CompatibilityLevel{
+		"compatibilityLevel": s.registry.GetCompatibilityLevel(r.PathValue("subject")),
+	}, nil
+}
+
+func (s *Server) putConfig(r *nethttp.Request) (interface{}, error) {
+	var req configRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !validLevel(req.Compatibility) {
+		return nil, newAPIError(nethttp.StatusUnprocessableEntity, codeInvalidCompatibility,
+			fmt.Sprintf("invalid compatibility level %q", req.Compatibility))
+	}
+	if err := s.registry.SetCompatibilityLevel(r.PathValue("subject"), req.Compatibility); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// validLevel reports whether level is one of the Confluent compatibility levels
+func validLevel(level avro.CompatibilityLevel) bool {
+	switch level {
+	case avro.CompatibilityNone, avro.CompatibilityBackward, avro.CompatibilityForward, avro.CompatibilityFull,
+		avro.CompatibilityBackwardTransitive, avro.CompatibilityForwardTransitive, avro.CompatibilityFullTransitive:
+		return true
+	}
+	return false
+}
+
+// modeBody is the body of GET and PUT /mode/{subject}
+type modeBody struct {
+	Mode avro.Mode `json:"mode"`
+}
+
+func (s *Server) getMode(r *nethttp.Request) (interface{}, error) {
+	return modeBody{Mode: s.registry.GetMode(r.PathValue("subject"))}, nil
+}
+
+func (s *Server) putMode(r *nethttp.Request) (interface{}, error) {
+	var req modeBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, newAPIError(nethttp.StatusUnprocessableEntity, codeInvalidMode, fmt.Sprintf("invalid request body: %v", err))
+	}
+	if err := s.registry.SetMode(r.PathValue("subject"), req.Mode); err != nil {
+		return nil, newAPIError(nethttp.StatusUnprocessableEntity, codeInvalidMode, fmt.Sprintf("invalid mode %q", req.Mode))
+	}
+	return req, nil
+}
@@ -0,0 +1,216 @@
+// Package schemaregistry serves an avro.SchemaRegistry over the Confluent
+// Schema Registry REST API, so Confluent clients in any language can use it
+// for local development
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net"
+	nethttp "net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/pkg/sdl/avro"
+)
+
+// ContentType is the media type of every response, as Confluent sends it
+const ContentType = "application/vnd.schemaregistry.v1+json"
+
+// Server serves a schema registry over HTTP
+type Server struct {
+	cfg      Config
+	logger   *logger.Logger
+	registry *avro.SchemaRegistry
+	mux      *nethttp.ServeMux
+	server   *nethttp.Server
+}
+
+// NewServer creates a server for registry; a nil registry starts with an empty one
+func NewServer(cfg Config, registry *avro.SchemaRegistry, log *logger.Logger) *Server {
+	if log == nil {
+		log = logger.Global()
+	}
+	if registry == nil {
+		registry = avro.NewSchemaRegistry()
+	}
+
+	s := &Server{
+		cfg:      cfg,
+		logger:   log.WithComponent("schemaregistry"),
+		registry: registry,
+		mux:      nethttp.NewServeMux(),
+	}
+	s.routes()
+
+	s.server = &nethttp.Server{
+		Addr:         cfg.Addr,
+		Handler:      s.mux,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+	}
+	return s
+}
+
+// Registry returns the registry being served
+func (s *Server) Registry() *avro.SchemaRegistry {
+	return s.registry
+}
+
+// Handler returns the server's root handler, e.g. for httptest
+func (s *Server) Handler() nethttp.Handler {
+	return s.mux
+}
+
+// ListenAndServe listens on the configured address and serves until stopped
+func (s *Server) ListenAndServe() error {
+	lis, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.cfg.Addr, err)
+	}
+	return s.Serve(lis)
+}
+
+// Serve serves registry requests on lis until the server is shut down
+func (s *Server) Serve(lis net.Listener) error {
+	s.logger.Info("Schema registry listening", zap.String("addr", lis.Addr().String()))
+
+	var err error
+	if s.cfg.TLSEnabled {
+		err = s.server.ServeTLS(lis, s.cfg.CertFile, s.cfg.KeyFile)
+	} else {
+		err = s.server.Serve(lis)
+	}
+	if err != nil && err != nethttp.ErrServerClosed {
+		return fmt.Errorf("schema registry failed: %w", err)
+	}
+	return nil
+}
+
+// Shutdown stops accepting new requests and waits for in-flight ones to finish
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.cfg.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.ShutdownTimeout)
+		defer cancel()
+	}
+
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.logger.Warn("Schema registry forced to stop", zap.Error(err))
+		return err
+	}
+	s.logger.Info("Schema registry stopped")
+	return nil
+}
+
+// handlerFunc returns the JSON response body of a request; a rawSchema is
+// written as-is
+type handlerFunc func(r *nethttp.Request) (interface{}, error)
+
+// rawSchema is a schema returned by the .../schema endpoints without a wrapper
+type rawSchema string
+
+// handle serves pattern with fn, writing its result or Confluent error body
+func (s *Server) handle(pattern string, fn handlerFunc) {
+	s.mux.HandleFunc(pattern, func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		start := time.Now()
+		if s.cfg.MaxBodyBytes > 0 {
+			r.Body = nethttp.MaxBytesReader(w, r.Body, s.cfg.MaxBodyBytes)
+		}
+
+		w.Header().Set("Content-Type", ContentType)
+		result, err := fn(r)
+		if err != nil {
+			apiErr := toAPIError(err)
+			w.WriteHeader(apiErr.status)
+			json.NewEncoder(w).Encode(apiErr)
+			s.logger.LogHTTPRequest(r.Method, r.URL.Path, apiErr.status, time.Since(start).String(),
+				zap.Int("error_code", apiErr.Code), zap.Error(err))
+			return
+		}
+
+		if schema, ok := result.(rawSchema); ok {
+			w.Write([]byte(schema))
+		} else {
+			json.NewEncoder(w).Encode(result)
+		}
+		s.logger.LogHTTPRequest(r.Method, r.URL.Path, nethttp.StatusOK, time.Since(start).String())
+	})
+}
+
+// Confluent error codes: the HTTP status followed by a detail number
+const (
+	codeSubjectNotFound       = 40401
+	codeVersionNotFound       = 40402
+	codeSchemaNotFound        = 40403
+	codeSubjectSoftDeleted    = 40404
+	codeSubjectNotSoftDeleted = 40405
+	codeVersionSoftDeleted    = 40406
+	codeVersionNotSoftDeleted = 40407
+	codeIncompatibleSchema    = 409
+	codeInvalidSchema         = 42201
+	codeInvalidVersion        = 42202
+	codeInvalidCompatibility  = 42203
+	codeInvalidMode           = 42204
+	codeOperationNotPermitted = 42205
+	codeInternal              = 50001
+)
+
+// APIError is the Confluent error body, returned by the server and by Client
+type APIError struct {
+	Code    int    `json:"error_code"`
+	Message string `json:"message"`
+	status  int
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// StatusCode returns the HTTP status the error was answered with
+func (e *APIError) StatusCode() int {
+	return e.status
+}
+
+func newAPIError(status, code int, message string) *APIError {
+	return &APIError{Code: code, Message: message, status: status}
+}
+
+// registryCodes maps the registry's error codes to Confluent's
+var registryCodes = map[string]int{
+	avro.CodeSubjectNotFound:       codeSubjectNotFound,
+	avro.CodeVersionNotFound:       codeVersionNotFound,
+	avro.CodeSchemaNotFound:        codeSchemaNotFound,
+	avro.CodeSubjectSoftDeleted:    codeSubjectSoftDeleted,
+	avro.CodeSubjectNotSoftDeleted: codeSubjectNotSoftDeleted,
+	avro.CodeVersionSoftDeleted:    codeVersionSoftDeleted,
+	avro.CodeVersionNotSoftDeleted: codeVersionNotSoftDeleted,
+	errors.CodeResourceLocked:      codeOperationNotPermitted,
+}
+
+// toAPIError translates a registry error into the error Confluent clients expect
+func toAPIError(err error) *APIError {
+	var apiErr *APIError
+	if stderrors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	var compatErr *avro.CompatibilityError
+	if stderrors.As(err, &compatErr) {
+		return newAPIError(nethttp.StatusConflict, codeIncompatibleSchema, err.Error())
+	}
+
+	if appErr, ok := errors.AsAppError(err); ok {
+		if code, ok := registryCodes[appErr.Code]; ok {
+			// The first three digits of a Confluent error code are its HTTP status
+			return newAPIError(code/100, code, appErr.Message)
+		}
+	}
+	return newAPIError(nethttp.StatusInternalServerError, codeInternal, err.Error())
+}
@@ -0,0 +1,197 @@
+package schemaregistry
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const itemV1 = `{"type":"record","name":"Item","namespace":"com.example.test","fields":[{"name":"id","type":"int"}]}`
+
+const itemV2 = `{"type":"record","name":"Item","namespace":"com.example.test","fields":[{"name":"id","type":"int"},{"name":"color","type":"string","default":"red"}]}`
+
+// incompatible adds a field without a default, which breaks backward compatibility
+const incompatible = `{"type":"record","name":"Item","namespace":"com.example.test","fields":[{"name":"id","type":"int"},{"name":"size","type":"int"}]}`
+
+func startTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(NewServer(DefaultConfig(), nil, nil).Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// call sends a request and decodes the JSON response into out, returning the status
+func call(t *testing.T, ts *httptest.Server, method, path string, body interface{}, out interface{}) int {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		reader = bytes.NewReader(data)
+	}
+	req, _ := nethttp.NewRequest(method, ts.URL+path, reader)
+	req.Header.Set("Content-Type", ContentType)
+	resp, err := nethttp.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != ContentType {
+		t.Errorf("Expected content type %s, got %s", ContentType, ct)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("Failed to decode %s %s: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestRegisterAndLookup(t *testing.T) {
+	ts := startTestServer(t)
+
+	var registered struct{ ID int }
+	if code := call(t, ts, "POST", "/subjects/items-value/versions", schemaRequest{Schema: itemV1}, &registered); code != 200 || registered.ID != 1 {
+		t.Fatalf("Expected ID 1, got %d (%d)", registered.ID, code)
+	}
+	if code := call(t, ts, "POST", "/subjects/items-value/versions", schemaRequest{Schema: itemV2}, &registered); code != 200 || registered.ID != 2 {
+		t.Fatalf("Expected ID 2, got %d (%d)", registered.ID, code)
+	}
+
+	var subjects []string
+	call(t, ts, "GET", "/subjects", nil, &subjects)
+	if len(subjects) != 1 || subjects[0] != "items-value" {
+		t.Errorf("Expected [items-value], got %v", subjects)
+	}
+	var versions []int
+	call(t, ts, "GET", "/subjects/items-value/versions", nil, &versions)
+	if len(versions) != 2 {
+		t.Errorf("Expected 2 versions, got %v", versions)
+	}
+
+	var latest SchemaVersion
+	call(t, ts, "GET", "/subjects/items-value/versions/latest", nil, &latest)
+	if latest.Version != 2 || latest.ID != 2 || latest.Subject != "items-value" || latest.Schema != itemV2 {
+		t.Errorf("Unexpected latest version: %+v", latest)
+	}
+	var found SchemaVersion
+	call(t, ts, "POST", "/subjects/items-value", schemaRequest{Schema: itemV1}, &found)
+	if found.Version != 1 || found.ID != 1 {
+		t.Errorf("Expected lookup to find version 1, got %+v", found)
+	}
+	var byID struct{ Schema string }
+	call(t, ts, "GET", "/schemas/ids/1", nil, &byID)
+	if byID.Schema != itemV1 {
+		t.Errorf("Expected schema 1, got %s", byID.Schema)
+	}
+	var raw map[string]interface{}
+	call(t, ts, "GET", "/subjects/items-value/versions/1/schema", nil, &raw)
+	if raw["name"] != "Item" {
+		t.Errorf("Expected the raw schema, got %v", raw)
+	}
+
+	t.Log("✓ Schemas are registered and looked up through the Confluent endpoints")
+}
+
+func TestCompatibilityAndConfig(t *testing.T) {
+	ts := startTestServer(t)
+	call(t, ts, "POST", "/subjects/items-value/versions", schemaRequest{Schema: itemV1}, nil)
+
+	var result compatibilityResponse
+	call(t, ts, "POST", "/compatibility/subjects/items-value/versions/latest?verbose=true", schemaRequest{Schema: incompatible}, &result)
+	if result.IsCompatible || len(result.Messages) == 0 {
+		t.Errorf("Expected an incompatible result with messages, got %+v", result)
+	}
+	call(t, ts, "POST", "/compatibility/subjects/items-value/versions", schemaRequest{Schema: itemV2}, &result)
+	if !result.IsCompatible {
+		t.Errorf("Expected v2 to be compatible, got %+v", result)
+	}
+
+	var apiErr APIError
+	if code := call(t, ts, "POST", "/subjects/items-value/versions", schemaRequest{Schema: incompatible}, &apiErr); code != 409 || apiErr.Code != codeIncompatibleSchema {
+		t.Errorf("Expected 409 registering an incompatible schema, got %d %+v", code, apiErr)
+	}
+
+	var config configRequest
+	call(t, ts, "PUT", "/config/items-value", configRequest{Compatibility: "NONE"}, &config)
+	var current map[string]string
+	call(t, ts, "GET", "/config/items-value", nil, &current)
+	if current["compatibilityLevel"] != "NONE" {
+		t.Errorf("Expected NONE, got %v", current)
+	}
+	if code := call(t, ts, "POST", "/subjects/items-value/versions", schemaRequest{Schema: incompatible}, nil); code != 200 {
+		t.Errorf("Expected registration to pass with NONE, got %d", code)
+	}
+	if code := call(t, ts, "PUT", "/config/items-value", configRequest{Compatibility: "SOMETIMES"}, &apiErr); code != 422 || apiErr.Code != codeInvalidCompatibility {
+		t.Errorf("Expected 422 for an unknown level, got %d %+v", code, apiErr)
+	}
+
+	t.Log("✓ Compatibility checks and per-subject config follow the Confluent API")
+}
+
+func TestErrorsAndDeletion(t *testing.T) {
+	ts := startTestServer(t)
+
+	cases := []struct {
+		method, path string
+		body         interface{}
+		status, code int
+	}{
+		{"GET", "/subjects/missing/versions", nil, 404, codeSubjectNotFound},
+		{"GET", "/subjects/missing/versions/latest", nil, 404, codeSubjectNotFound},
+		{"GET", "/schemas/ids/99", nil, 404, codeSchemaNotFound},
+		{"GET", "/subjects/missing/versions/zero", nil, 422, codeInvalidVersion},
+		{"POST", "/subjects/items-value/versions", schemaRequest{Schema: "{not json"}, 422, codeInvalidSchema},
+		{"POST", "/subjects/items-value/versions", schemaRequest{Schema: itemV1, SchemaType: "PROTOBUF"}, 422, codeInvalidSchema},
+	}
+	for _, c := range cases {
+		var apiErr APIError
+		if code := call(t, ts, c.method, c.path, c.body, &apiErr); code != c.status || apiErr.Code != c.code {
+			t.Errorf("%s %s: expected %d/%d, got %d/%d (%s)", c.method, c.path, c.status, c.code, code, apiErr.Code, apiErr.Message)
+		}
+	}
+
+	call(t, ts, "POST", "/subjects/items-value/versions", schemaRequest{Schema: itemV1}, nil)
+	call(t, ts, "POST", "/subjects/items-value/versions", schemaRequest{Schema: itemV2}, nil)
+
+	var apiErr APIError
+	if code := call(t, ts, "GET", "/subjects/items-value/versions/3", nil, &apiErr); code != 404 || apiErr.Code != codeVersionNotFound {
+		t.Errorf("Expected 40402 for a missing version, got %d %+v", code, apiErr)
+	}
+	if code := call(t, ts, "DELETE", "/subjects/items-value?permanent=true", nil, &apiErr); code != 404 || apiErr.Code != codeSubjectNotSoftDeleted {
+		t.Errorf("Expected 40405 deleting a live subject permanently, got %d %+v", code, apiErr)
+	}
+
+	var deletedVersion int
+	call(t, ts, "DELETE", "/subjects/items-value/versions/latest", nil, &deletedVersion)
+	if deletedVersion != 2 {
+		t.Errorf("Expected version 2 to be deleted, got %d", deletedVersion)
+	}
+	var deleted []int
+	call(t, ts, "DELETE", "/subjects/items-value", nil, &deleted)
+	if len(deleted) != 1 || deleted[0] != 1 {
+		t.Errorf("Expected the remaining version 1 to be deleted, got %v", deleted)
+	}
+	if code := call(t, ts, "DELETE", "/subjects/items-value", nil, &apiErr); code != 404 || apiErr.Code != codeSubjectSoftDeleted {
+		t.Errorf("Expected 40404 soft deleting twice, got %d %+v", code, apiErr)
+	}
+	call(t, ts, "DELETE", "/subjects/items-value?permanent=true", nil, &deleted)
+	if len(deleted) != 2 {
+		t.Errorf("Expected both versions to be deleted permanently, got %v", deleted)
+	}
+
+	var mode modeBody
+	call(t, ts, "PUT", "/mode/orders-value", modeBody{Mode: "READONLY"}, &mode)
+	if code := call(t, ts, "POST", "/subjects/orders-value/versions", schemaRequest{Schema: itemV1}, &apiErr); code != 422 || apiErr.Code != codeOperationNotPermitted {
+		t.Errorf("Expected 42205 registering under a read-only subject, got %d %+v", code, apiErr)
+	}
+	if code := call(t, ts, "PUT", "/mode/orders-value", modeBody{Mode: "IMPORT"}, &apiErr); code != 422 || apiErr.Code != codeInvalidMode {
+		t.Errorf("Expected 42204 for an unknown mode, got %d %+v", code, apiErr)
+	}
+
+	t.Log("✓ Errors carry Confluent error codes and deletions follow soft/permanent semantics")
+}
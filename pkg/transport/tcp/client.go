@@ -0,0 +1,199 @@
+package tcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"go-transport-prac/pkg/sdl/protobuf/gen/envelope"
+	"go-transport-prac/pkg/transport/framing"
+)
+
+// ErrClientClosed is returned by calls on a closed client
+var ErrClientClosed = errors.New("tcp client is closed")
+
+// RemoteError is a handler error returned by the server
+type RemoteError struct {
+	Method  string
+	Message string
+}
+
+// Error implements the error interface
+func (e *RemoteError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Method, e.Message)
+}
+
+// clientConn is a pooled connection with its framing state
+type clientConn struct {
+	conn   net.Conn
+	reader *framing.Reader
+	writer *framing.Writer
+}
+
+// Client calls handlers on a TCP server over a pool of connections.
+// It is safe for concurrent use; each call holds one connection
+type Client struct {
+	cfg    Config
+	dialer net.Dialer
+	nextID atomic.Uint64
+
+	mu     sync.Mutex
+	idle   []*clientConn
+	closed bool
+}
+
+// NewClient creates a client for the server at cfg.Addr. Connections are dialed on first use
+func NewClient(cfg Config) *Client {
+	return &Client{
+		cfg:    cfg,
+		dialer: net.Dialer{Timeout: cfg.DialTimeout},
+	}
+}
+
+// Call sends payload to method and returns the response payload. The call
+// fails when ctx is done or the configured read or write timeout elapses
+func (c *Client) Call(ctx context.Context, method string, payload []byte) ([]byte, error) {
+	cc, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &envelope.Envelope{Id: c.nextID.Add(1), Method: method, Payload: payload}
+	resp, err := c.roundTrip(ctx, cc, req)
+	if err != nil {
+		// The connection may hold a partial frame, so it is never reused
+		cc.conn.Close()
+		return nil, fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	c.put(cc)
+
+	if resp.GetError() != "" {
+		return nil, &RemoteError{Method: method, Message: resp.GetError()}
+	}
+	return resp.GetPayload(), nil
+}
+
+// CallProto marshals req, calls method and unmarshals the response into resp
+func (c *Client) CallProto(ctx context.Context, method string, req, resp proto.Message) error {
+	payload, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %w", method, err)
+	}
+	data, err := c.Call(ctx, method, payload)
+	if err != nil {
+		return err
+	}
+	if err := proto.Unmarshal(data, resp); err != nil {
+		return fmt.Errorf("failed to unmarshal %s response: %w", method, err)
+	}
+	return nil
+}
+
+// roundTrip writes req on cc and reads its response
+func (c *Client) roundTrip(ctx context.Context, cc *clientConn, req *envelope.Envelope) (*envelope.Envelope, error) {
+	// Unblock the connection if ctx is cancelled mid-call
+	stop := context.AfterFunc(ctx, func() {
+		cc.conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	cc.conn.SetWriteDeadline(c.callDeadline(ctx, c.cfg.WriteTimeout))
+	if err := writeEnvelope(cc.writer, req); err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	cc.conn.SetReadDeadline(c.callDeadline(ctx, c.cfg.ReadTimeout))
+	frame, err := cc.reader.ReadFrame()
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	var resp envelope.Envelope
+	if err := proto.Unmarshal(frame, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal envelope: %w", err)
+	}
+	if resp.GetId() != req.GetId() {
+		return nil, fmt.Errorf("response id %d does not match request id %d", resp.GetId(), req.GetId())
+	}
+	return &resp, nil
+}
+
+// callDeadline returns the earlier of ctx's deadline and now plus timeout
+func (c *Client) callDeadline(ctx context.Context, timeout time.Duration) time.Time {
+	d := deadline(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && (d.IsZero() || ctxDeadline.Before(d)) {
+		return ctxDeadline
+	}
+	return d
+}
+
+// contextError prefers ctx's error when ctx ended the call
+func contextError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// get returns an idle connection or dials a new one
+func (c *Client) get(ctx context.Context) (*clientConn, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrClientClosed
+	}
+	if n := len(c.idle); n > 0 {
+		cc := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return cc, nil
+	}
+	c.mu.Unlock()
+
+	conn, err := c.dialer.DialContext(ctx, "tcp", c.cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", c.cfg.Addr, err)
+	}
+	return &clientConn{
+		conn:   conn,
+		reader: framing.NewReader(conn, framing.PrefixVarint).WithMaxFrameSize(c.cfg.MaxFrameSize),
+		writer: framing.NewWriter(conn, framing.PrefixVarint),
+	}, nil
+}
+
+// put returns cc to the pool, closing it when the pool is full or the client closed
+func (c *Client) put(cc *clientConn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed || len(c.idle) >= c.cfg.PoolSize {
+		cc.conn.Close()
+		return
+	}
+	c.idle = append(c.idle, cc)
+}
+
+// Idle returns the number of pooled connections
+func (c *Client) Idle() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.idle)
+}
+
+// Close closes the pooled connections. Calls in progress finish on their own
+// connections, which are closed instead of being pooled
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	for _, cc := range c.idle {
+		cc.conn.Close()
+	}
+	c.idle = nil
+	return nil
+}
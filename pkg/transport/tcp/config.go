@@ -0,0 +1,64 @@
+package tcp
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"go-transport-prac/internal/config"
+	"go-transport-prac/pkg/transport/framing"
+)
+
+// Config holds raw TCP server and client settings
+type Config struct {
+	// Addr is the host:port the server listens on and the client dials
+	Addr string
+
+	// ReadTimeout bounds reading one request on the server and one response on the client
+	ReadTimeout time.Duration
+	// WriteTimeout bounds writing one frame
+	WriteTimeout time.Duration
+	// IdleTimeout is how long a server connection may wait for its next request
+	IdleTimeout time.Duration
+	// DialTimeout bounds establishing a client connection
+	DialTimeout time.Duration
+
+	// MaxFrameSize bounds the size of a single envelope in bytes
+	MaxFrameSize int
+	// PoolSize is the number of idle connections a client keeps open
+	PoolSize int
+	// ShutdownTimeout bounds how long Shutdown waits for in-flight requests
+	ShutdownTimeout time.Duration
+}
+
+// DefaultConfig returns a configuration on the default TCP port
+func DefaultConfig() Config {
+	return Config{
+		Addr:            "localhost:8083",
+		ReadTimeout:     30 * time.Second,
+		WriteTimeout:    30 * time.Second,
+		IdleTimeout:     120 * time.Second,
+		DialTimeout:     5 * time.Second,
+		MaxFrameSize:    framing.DefaultMaxFrameSize,
+		PoolSize:        4,
+		ShutdownTimeout: 10 * time.Second,
+	}
+}
+
+// NewConfig builds a TCP configuration from the application server configuration
+func NewConfig(cfg config.ServerConfig) Config {
+	tcpCfg := DefaultConfig()
+	tcpCfg.Addr = net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.TCPPort))
+	tcpCfg.ReadTimeout = cfg.ReadTimeout
+	tcpCfg.WriteTimeout = cfg.WriteTimeout
+	tcpCfg.IdleTimeout = cfg.IdleTimeout
+	return tcpCfg
+}
+
+// deadline returns now plus timeout, or the zero time for no deadline
+func deadline(timeout time.Duration) time.Time {
+	if timeout <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(timeout)
+}
@@ -0,0 +1,254 @@
+package tcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/pkg/sdl/protobuf/gen/envelope"
+	"go-transport-prac/pkg/transport/framing"
+)
+
+// Requests and responses are envelope.Envelope messages in varint
+// length-prefixed frames, the protobuf delimited format. A connection carries
+// one request at a time: the client writes a request and waits for the
+// response with the same id before sending the next one.
+
+// HandlerFunc handles the payload of one request and returns the response payload
+type HandlerFunc func(ctx context.Context, payload []byte) ([]byte, error)
+
+// ProtoHandler adapts a typed protobuf handler. newReq returns the empty request message to decode into
+func ProtoHandler[Req, Resp proto.Message](newReq func() Req, fn func(ctx context.Context, req Req) (Resp, error)) HandlerFunc {
+	return func(ctx context.Context, payload []byte) ([]byte, error) {
+		req := newReq()
+		if err := proto.Unmarshal(payload, req); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal request: %w", err)
+		}
+		resp, err := fn(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return proto.Marshal(resp)
+	}
+}
+
+// Server dispatches envelopes read from raw TCP connections to registered handlers
+type Server struct {
+	cfg    Config
+	logger *logger.Logger
+
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+
+	// ctx is cancelled when the server stops, cancelling in-flight handlers
+	ctx     context.Context
+	cancel  context.CancelFunc
+	closing atomic.Bool
+	// active counts connections still serving; it is only added to under mu
+	active sync.WaitGroup
+}
+
+// NewServer creates a server with no handlers registered
+func NewServer(cfg Config, log *logger.Logger) *Server {
+	if log == nil {
+		log = logger.Global()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Server{
+		cfg:      cfg,
+		logger:   log.WithComponent("tcp"),
+		handlers: make(map[string]HandlerFunc),
+		conns:    make(map[net.Conn]struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Handle registers handler for method, replacing any previous handler
+func (s *Server) Handle(method string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = handler
+}
+
+// ListenAndServe listens on the configured address and serves until stopped
+func (s *Server) ListenAndServe() error {
+	lis, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.cfg.Addr, err)
+	}
+	return s.Serve(lis)
+}
+
+// Serve accepts connections on lis until the server is shut down
+func (s *Server) Serve(lis net.Listener) error {
+	s.mu.Lock()
+	s.listener = lis
+	s.mu.Unlock()
+
+	s.logger.Info("TCP server listening", zap.String("addr", lis.Addr().String()))
+
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			if s.closing.Load() || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("TCP server failed: %w", err)
+		}
+
+		s.mu.Lock()
+		if s.closing.Load() {
+			s.mu.Unlock()
+			conn.Close()
+			return nil
+		}
+		s.conns[conn] = struct{}{}
+		s.active.Add(1)
+		s.mu.Unlock()
+
+		go s.serveConn(conn)
+	}
+}
+
+// serveConn handles requests on conn until the client disconnects or the server shuts down
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+		s.active.Done()
+	}()
+
+	reader := framing.NewReader(conn, framing.PrefixVarint).WithMaxFrameSize(s.cfg.MaxFrameSize)
+	writer := framing.NewWriter(conn, framing.PrefixVarint)
+
+	for {
+		// Checked after setting the deadline so Shutdown's deadline is never overwritten
+		conn.SetReadDeadline(deadline(s.cfg.IdleTimeout))
+		if s.closing.Load() {
+			return
+		}
+
+		frame, err := reader.ReadFrame()
+		if err != nil {
+			if err != io.EOF && !s.closing.Load() {
+				s.logger.Debug("TCP connection closed", zap.String("remote", conn.RemoteAddr().String()), zap.Error(err))
+			}
+			return
+		}
+
+		resp := s.dispatch(frame)
+		conn.SetWriteDeadline(deadline(s.cfg.WriteTimeout))
+		if err := writeEnvelope(writer, resp); err != nil {
+			s.logger.Warn("Failed to write TCP response", zap.String("method", resp.GetMethod()), zap.Error(err))
+			return
+		}
+	}
+}
+
+// dispatch runs the handler for one request frame and builds its response
+func (s *Server) dispatch(frame []byte) *envelope.Envelope {
+	start := time.Now()
+
+	var req envelope.Envelope
+	if err := proto.Unmarshal(frame, &req); err != nil {
+		return &envelope.Envelope{Error: fmt.Sprintf("invalid envelope: %v", err)}
+	}
+	resp := &envelope.Envelope{Id: req.GetId(), Method: req.GetMethod()}
+
+	s.mu.RLock()
+	handler, ok := s.handlers[req.GetMethod()]
+	s.mu.RUnlock()
+	if !ok {
+		resp.Error = fmt.Sprintf("unknown method %q", req.GetMethod())
+		return resp
+	}
+
+	ctx := s.ctx
+	if s.cfg.ReadTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.ReadTimeout)
+		defer cancel()
+	}
+
+	payload, err := handler(ctx, req.GetPayload())
+	if err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Payload = payload
+	}
+
+	s.logger.Debug("TCP request",
+		zap.String("method", req.GetMethod()),
+		zap.Bool("ok", err == nil),
+		zap.Duration("duration", time.Since(start)))
+	return resp
+}
+
+// Shutdown stops accepting connections, closes idle ones and waits for
+// in-flight requests to finish. Remaining connections are closed once ctx is
+// done or the shutdown timeout elapses.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.cfg.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.ShutdownTimeout)
+		defer cancel()
+	}
+
+	s.mu.Lock()
+	s.closing.Store(true)
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	// Wake connections blocked waiting for their next request; busy ones
+	// finish their current request and then see closing
+	for conn := range s.conns {
+		conn.SetReadDeadline(time.Now())
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.active.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel()
+		s.logger.Info("TCP server stopped")
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		s.mu.Lock()
+		for conn := range s.conns {
+			conn.Close()
+		}
+		s.mu.Unlock()
+		s.logger.Warn("TCP server forced to stop", zap.Error(ctx.Err()))
+		return ctx.Err()
+	}
+}
+
+// writeEnvelope marshals env and writes it as one frame
+func writeEnvelope(w *framing.Writer, env *envelope.Envelope) error {
+	data, err := proto.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("failed to marshal envelope: %w", err)
+	}
+	return w.WriteFrame(data)
+}
@@ -0,0 +1,206 @@
+package tcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"go-transport-prac/pkg/sdl/protobuf/gen/user"
+)
+
+// startTestServer serves handlers on a loopback listener and returns a client for it
+func startTestServer(t *testing.T, cfg Config, handlers map[string]HandlerFunc) (*Server, *Client) {
+	t.Helper()
+
+	server := NewServer(cfg, nil)
+	for method, handler := range handlers {
+		server.Handle(method, handler)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go server.Serve(lis)
+	t.Cleanup(func() { server.Shutdown(context.Background()) })
+
+	cfg.Addr = lis.Addr().String()
+	client := NewClient(cfg)
+	t.Cleanup(func() { client.Close() })
+
+	return server, client
+}
+
+// getUser answers user.Get requests with a user named after the requested id
+func getUser(_ context.Context, req *user.GetUserRequest) (*user.UserResponse, error) {
+	if req.GetId() == 0 {
+		return nil, errors.New("user id is required")
+	}
+	return &user.UserResponse{
+		User:    &user.User{Id: req.GetId(), Name: fmt.Sprintf("User %d", req.GetId())},
+		Success: true,
+	}, nil
+}
+
+func TestCallDispatch(t *testing.T) {
+	_, client := startTestServer(t, DefaultConfig(), map[string]HandlerFunc{
+		"user.Get": ProtoHandler(func() *user.GetUserRequest { return &user.GetUserRequest{} }, getUser),
+		"echo": func(_ context.Context, payload []byte) ([]byte, error) {
+			return payload, nil
+		},
+	})
+	ctx := context.Background()
+
+	var resp user.UserResponse
+	if err := client.CallProto(ctx, "user.Get", &user.GetUserRequest{Id: 42}, &resp); err != nil {
+		t.Fatalf("Failed to call user.Get: %v", err)
+	}
+	if resp.GetUser().GetId() != 42 || resp.GetUser().GetName() != "User 42" {
+		t.Errorf("Unexpected user: %v", resp.GetUser())
+	}
+
+	echo, err := client.Call(ctx, "echo", []byte("ping"))
+	if err != nil || string(echo) != "ping" {
+		t.Errorf("Expected echo ping, got %q (%v)", echo, err)
+	}
+
+	// Handler errors and unknown methods come back as remote errors on a reusable connection
+	var remote *RemoteError
+	err = client.CallProto(ctx, "user.Get", &user.GetUserRequest{}, &resp)
+	if !errors.As(err, &remote) || remote.Message != "user id is required" {
+		t.Errorf("Expected remote handler error, got %v", err)
+	}
+	if _, err := client.Call(ctx, "user.Delete", nil); !errors.As(err, &remote) {
+		t.Errorf("Expected remote error for unknown method, got %v", err)
+	}
+	if client.Idle() != 1 {
+		t.Errorf("Expected one pooled connection, got %d", client.Idle())
+	}
+
+	t.Log("✓ Requests are dispatched to registered handlers")
+}
+
+func TestClientPool(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.PoolSize = 2
+	_, client := startTestServer(t, cfg, map[string]HandlerFunc{
+		"slow": func(ctx context.Context, payload []byte) ([]byte, error) {
+			time.Sleep(10 * time.Millisecond)
+			return payload, nil
+		},
+	})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			payload := []byte(fmt.Sprintf("call %d", i))
+			resp, err := client.Call(context.Background(), "slow", payload)
+			if err == nil && string(resp) != string(payload) {
+				err = fmt.Errorf("expected %q, got %q", payload, resp)
+			}
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Concurrent call failed: %v", err)
+		}
+	}
+
+	if idle := client.Idle(); idle != 2 {
+		t.Errorf("Expected the pool to keep 2 idle connections, got %d", idle)
+	}
+
+	client.Close()
+	if _, err := client.Call(context.Background(), "slow", nil); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Expected ErrClientClosed, got %v", err)
+	}
+
+	t.Log("✓ Concurrent calls share a bounded connection pool")
+}
+
+func TestCallTimeouts(t *testing.T) {
+	release := make(chan struct{})
+	cfg := DefaultConfig()
+	cfg.ReadTimeout = 50 * time.Millisecond
+	_, client := startTestServer(t, cfg, map[string]HandlerFunc{
+		"block": func(ctx context.Context, payload []byte) ([]byte, error) {
+			<-release
+			return payload, nil
+		},
+	})
+	defer close(release)
+
+	// The client's read timeout bounds waiting for the response
+	start := time.Now()
+	if _, err := client.Call(context.Background(), "block", nil); err == nil {
+		t.Fatal("Expected read timeout")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Read timeout took %v", elapsed)
+	}
+
+	// A cancelled context ends the call with its error
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.Call(ctx, "block", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if client.Idle() != 0 {
+		t.Errorf("Expected failed connections to be discarded, got %d idle", client.Idle())
+	}
+
+	t.Log("✓ Calls honour read timeouts and context deadlines")
+}
+
+func TestShutdownWaitsForInFlight(t *testing.T) {
+	started := make(chan struct{})
+	server, client := startTestServer(t, DefaultConfig(), map[string]HandlerFunc{
+		"work": func(ctx context.Context, payload []byte) ([]byte, error) {
+			close(started)
+			time.Sleep(50 * time.Millisecond)
+			return []byte("done"), nil
+		},
+		"ping": func(ctx context.Context, payload []byte) ([]byte, error) {
+			return nil, nil
+		},
+	})
+
+	// An idle pooled connection must not hold up shutdown
+	idle := NewClient(client.cfg)
+	defer idle.Close()
+	if _, err := idle.Call(context.Background(), "ping", nil); err != nil {
+		t.Fatalf("Failed to call ping: %v", err)
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		resp, err := client.Call(context.Background(), "work", nil)
+		if err == nil && string(resp) != "done" {
+			err = fmt.Errorf("unexpected response %q", resp)
+		}
+		result <- err
+	}()
+	<-started
+
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if err := <-result; err != nil {
+		t.Errorf("In-flight call failed during shutdown: %v", err)
+	}
+	if _, err := NewClient(client.cfg).Call(context.Background(), "ping", nil); err == nil {
+		t.Error("Expected calls to fail after shutdown")
+	}
+
+	t.Log("✓ Shutdown completes in-flight requests and closes idle connections")
+}
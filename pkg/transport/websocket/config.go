@@ -0,0 +1,59 @@
+package websocket
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"go-transport-prac/internal/config"
+)
+
+// Config holds WebSocket server settings
+type Config struct {
+	// Addr is the host:port the server listens on
+	Addr string
+	// Path is the URL path clients connect to
+	Path string
+
+	TLSEnabled bool
+	CertFile   string
+	KeyFile    string
+
+	// PingInterval is how often the server pings each client; it must be shorter than PongWait
+	PingInterval time.Duration
+	// PongWait is how long a connection may stay silent before it is dropped
+	PongWait time.Duration
+	// WriteWait bounds a single frame write
+	WriteWait time.Duration
+
+	// SendBuffer is the number of frames queued per connection before it is treated as a slow consumer
+	SendBuffer int
+	// MaxMessageSize bounds the size of a client control message
+	MaxMessageSize int64
+	// ShutdownTimeout bounds how long Shutdown waits for clients to acknowledge the close frame
+	ShutdownTimeout time.Duration
+}
+
+// DefaultConfig returns a plaintext configuration on the default WebSocket port
+func DefaultConfig() Config {
+	return Config{
+		Addr:            "localhost:8082",
+		Path:            "/ws",
+		PingInterval:    25 * time.Second,
+		PongWait:        60 * time.Second,
+		WriteWait:       10 * time.Second,
+		SendBuffer:      256,
+		MaxMessageSize:  4 * 1024,
+		ShutdownTimeout: 10 * time.Second,
+	}
+}
+
+// NewConfig builds a WebSocket configuration from the application server configuration
+func NewConfig(cfg config.ServerConfig) Config {
+	wsCfg := DefaultConfig()
+	wsCfg.Addr = net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.WSPort))
+	wsCfg.TLSEnabled = cfg.TLSEnabled
+	wsCfg.CertFile = cfg.CertFile
+	wsCfg.KeyFile = cfg.KeyFile
+	return wsCfg
+}
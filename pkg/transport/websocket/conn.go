@@ -0,0 +1,199 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	websocketgo "github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/logger"
+)
+
+// errConnClosed is returned when sending to a connection that is shutting down
+var errConnClosed = fmt.Errorf("connection closed")
+
+// conn is one client connection. Only writePump writes to the socket; readPump
+// handles control messages and keeps the read deadline alive on pongs.
+type conn struct {
+	id     string
+	userID string
+	format Format
+
+	ws     *websocketgo.Conn
+	cfg    Config
+	logger *logger.Logger
+
+	send      chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu     sync.RWMutex
+	topics map[string]bool
+}
+
+// newConn wraps an upgraded socket subscribed to topics
+func newConn(id, userID string, format Format, ws *websocketgo.Conn, cfg Config, log *logger.Logger, topics []string) *conn {
+	c := &conn{
+		id:     id,
+		userID: userID,
+		format: format,
+		ws:     ws,
+		cfg:    cfg,
+		logger: log,
+		send:   make(chan []byte, cfg.SendBuffer),
+		done:   make(chan struct{}),
+		topics: make(map[string]bool),
+	}
+	c.subscribe(topics)
+	return c
+}
+
+// ID returns the connection ID
+func (c *conn) ID() string {
+	return c.id
+}
+
+// UserID returns the user ID the client supplied when connecting
+func (c *conn) UserID() string {
+	return c.userID
+}
+
+// Format returns the encoding the connection receives events in
+func (c *conn) Format() Format {
+	return c.format
+}
+
+// Send queues a binary frame, waiting for buffer space until ctx is done
+func (c *conn) Send(ctx context.Context, message []byte) error {
+	select {
+	case <-c.done:
+		return errConnClosed
+	default:
+	}
+
+	select {
+	case c.send <- message:
+		return nil
+	case <-c.done:
+		return errConnClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// offer queues a frame without blocking and reports whether there was room
+func (c *conn) offer(message []byte) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+
+	select {
+	case c.send <- message:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close sends a close frame and stops the connection once the client acknowledges it
+func (c *conn) Close() error {
+	c.closeOnce.Do(func() { close(c.done) })
+	return nil
+}
+
+// abort drops the connection without waiting for the close handshake
+func (c *conn) abort() {
+	c.Close()
+	c.ws.Close()
+}
+
+// subscribe adds topics to the connection's subscription
+func (c *conn) subscribe(topics []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, topic := range topics {
+		c.topics[topic] = true
+	}
+}
+
+// unsubscribe removes topics from the connection's subscription
+func (c *conn) unsubscribe(topics []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, topic := range topics {
+		delete(c.topics, topic)
+	}
+}
+
+// subscribed reports whether the connection receives events for topic
+func (c *conn) subscribed(topic string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.topics[topic]
+}
+
+// subscriptions returns the topics the connection receives
+func (c *conn) subscriptions() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	topics := make([]string, 0, len(c.topics))
+	for topic := range c.topics {
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+// readPump reads control messages until the client goes away or the connection
+// closes, passing each one to handle
+func (c *conn) readPump(handle func(message []byte)) {
+	c.ws.SetReadLimit(c.cfg.MaxMessageSize)
+	c.ws.SetReadDeadline(time.Now().Add(c.cfg.PongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(c.cfg.PongWait))
+	})
+
+	for {
+		_, message, err := c.ws.ReadMessage()
+		if err != nil {
+			if websocketgo.IsUnexpectedCloseError(err, websocketgo.CloseNormalClosure, websocketgo.CloseGoingAway) {
+				c.logger.Warn("WebSocket connection lost", zap.String("conn_id", c.id), zap.Error(err))
+			}
+			return
+		}
+		handle(message)
+	}
+}
+
+// writePump writes queued frames and keepalive pings, and the close frame once
+// the connection is closed
+func (c *conn) writePump() {
+	ticker := time.NewTicker(c.cfg.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case message := <-c.send:
+			c.ws.SetWriteDeadline(time.Now().Add(c.cfg.WriteWait))
+			if err := c.ws.WriteMessage(websocketgo.BinaryMessage, message); err != nil {
+				c.abort()
+				return
+			}
+		case <-ticker.C:
+			if err := c.ws.WriteControl(websocketgo.PingMessage, nil, time.Now().Add(c.cfg.WriteWait)); err != nil {
+				c.abort()
+				return
+			}
+		case <-c.done:
+			closeMessage := websocketgo.FormatCloseMessage(websocketgo.CloseGoingAway, "server closing connection")
+			if err := c.ws.WriteControl(websocketgo.CloseMessage, closeMessage, time.Now().Add(c.cfg.WriteWait)); err != nil {
+				c.ws.Close()
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package websocket
+
+import (
+	"fmt"
+	nethttp "net/http"
+	"strings"
+
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/protobuf"
+	"go-transport-prac/pkg/transport/codec"
+	"go-transport-prac/pkg/transport/eventlog"
+	"go-transport-prac/pkg/transport/internal/convert"
+)
+
+// Format is the binary encoding a connection receives events in
+type Format string
+
+const (
+	FormatProtobuf Format = "protobuf"
+	FormatAvro     Format = "avro"
+)
+
+// subprotocols lists the formats clients can request through Sec-WebSocket-Protocol
+var subprotocols = []string{string(FormatProtobuf), string(FormatAvro)}
+
+// Topics clients can subscribe to
+const (
+	TopicOrders    = "orders"
+	TopicAnalytics = "analytics"
+)
+
+// allTopics is the subscription of a client that did not ask for specific topics
+var allTopics = []string{TopicOrders, TopicAnalytics}
+
+// formatTypes maps the MIME types of the formats onto them, for clients
+// negotiating with an Accept header
+var formatTypes = map[string]Format{
+	codec.Protobuf: FormatProtobuf,
+	codec.Avro:     FormatAvro,
+}
+
+// negotiateFormat picks the format for a new connection. A Sec-WebSocket-Protocol
+// the server supports wins, then the "format" query parameter, then the Accept
+// header, then Protobuf.
+func negotiateFormat(r *nethttp.Request, subprotocol string) (Format, error) {
+	if subprotocol != "" {
+		return Format(subprotocol), nil
+	}
+
+	switch format := strings.ToLower(r.URL.Query().Get("format")); format {
+	case "":
+		mediaType, err := codec.Negotiate(r.Header.Get("Accept"), []string{codec.Protobuf, codec.Avro}, codec.Protobuf)
+		if err != nil {
+			return "", fmt.Errorf("unsupported Accept header %q", r.Header.Get("Accept"))
+		}
+		return formatTypes[mediaType], nil
+	case string(FormatProtobuf), string(FormatAvro):
+		return Format(format), nil
+	default:
+		return "", fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// EncodeFrame prefixes payload with its topic: one length byte followed by the topic name
+func EncodeFrame(topic string, payload []byte) ([]byte, error) {
+	if len(topic) == 0 || len(topic) > 255 {
+		return nil, fmt.Errorf("invalid topic length %d", len(topic))
+	}
+
+	frame := make([]byte, 0, 1+len(topic)+len(payload))
+	frame = append(frame, byte(len(topic)))
+	frame = append(frame, topic...)
+	return append(frame, payload...), nil
+}
+
+// DecodeFrame splits a binary frame into its topic and serialized event
+func DecodeFrame(frame []byte) (string, []byte, error) {
+	if len(frame) == 0 {
+		return "", nil, fmt.Errorf("empty frame")
+	}
+
+	n := int(frame[0])
+	if n == 0 || len(frame) < 1+n {
+		return "", nil, fmt.Errorf("truncated frame topic")
+	}
+	return string(frame[1 : 1+n]), frame[1+n:], nil
+}
+
+// encoder serializes events into the per-format frames of a broadcast
+type encoder struct {
+	avroManager  *avro.Manager
+	protoManager *protobuf.Manager
+}
+
+// newEncoder creates an encoder backed by the Avro and Protobuf managers
+func newEncoder() (*encoder, error) {
+	avroManager, err := avro.NewManager("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avro manager: %w", err)
+	}
+
+	return &encoder{
+		avroManager:  avroManager,
+		protoManager: protobuf.NewManager(),
+	}, nil
+}
+
+// orderFrame serializes an order as a frame in the given format
+func (e *encoder) orderFrame(o avro.Order, format Format) ([]byte, error) {
+	var (
+		payload []byte
+		err     error
+	)
+	switch format {
+	case FormatAvro:
+		payload, err = e.avroManager.SerializeStruct(e.avroManager.GetOrderSchema(), o)
+	default:
+		payload, err = e.protoManager.Serialize(convert.OrderToProto(o))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize order as %s: %w", format, err)
+	}
+	return EncodeFrame(TopicOrders, payload)
+}
+
+// analyticsFrame serializes an analytics event as a frame in the given format
+func (e *encoder) analyticsFrame(a avro.Analytics, format Format) ([]byte, error) {
+	var (
+		payload []byte
+		err     error
+	)
+	switch format {
+	case FormatAvro:
+		payload, err = e.avroManager.SerializeAnalytics(a)
+	default:
+		payload, err = e.protoManager.Serialize(convert.AnalyticsToProto(a))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize analytics event as %s: %w", format, err)
+	}
+	return EncodeFrame(TopicAnalytics, payload)
+}
+
+// eventFrame serializes an event read back from the event log
+func (e *encoder) eventFrame(event eventlog.Event, format Format) ([]byte, error) {
+	switch v := event.Value.(type) {
+	case avro.Order:
+		return e.orderFrame(v, format)
+	case avro.Analytics:
+		return e.analyticsFrame(v, format)
+	default:
+		return nil, fmt.Errorf("cannot replay %s event of type %T", event.Topic, event.Value)
+	}
+}
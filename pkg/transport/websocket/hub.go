@@ -0,0 +1,243 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/logger"
+	"go-transport-prac/internal/types"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/transport/eventlog"
+)
+
+// controlMessage is a text message a client sends to change its subscription
+type controlMessage struct {
+	Action string   `json:"action"`
+	Topics []string `json:"topics"`
+}
+
+// Hub tracks connected clients and broadcasts events to their subscriptions.
+// Each event is serialized at most once per format, however many clients receive it.
+type Hub struct {
+	encoder *encoder
+	logger  *logger.Logger
+	events  *eventlog.Log
+
+	mu    sync.RWMutex
+	conns map[string]*conn
+}
+
+// NewHub creates a hub with no connections
+func NewHub(log *logger.Logger) (*Hub, error) {
+	if log == nil {
+		log = logger.Global()
+	}
+
+	enc, err := newEncoder()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Hub{
+		encoder: enc,
+		logger:  log.WithComponent("websocket"),
+		conns:   make(map[string]*conn),
+	}, nil
+}
+
+// WithEventLog appends every broadcast to log, so clients can resume from a
+// cursor here or through any other transport reading the same log
+func (h *Hub) WithEventLog(log *eventlog.Log) *Hub {
+	h.events = log
+	return h
+}
+
+// EventLog returns the log broadcasts are appended to, or nil
+func (h *Hub) EventLog() *eventlog.Log {
+	return h.events
+}
+
+// OnConnect registers a connection for broadcasts
+func (h *Hub) OnConnect(ctx context.Context, wc types.WebSocketConnection) error {
+	return h.resume(wc, "")
+}
+
+// resume registers a connection and first queues the events after cursor on
+// its topics. Registration and replay hold the hub lock, which broadcasts
+// also take while appending to the log, so no event is missed or sent twice.
+// An empty cursor replays nothing
+func (h *Hub) resume(wc types.WebSocketConnection, cursor string) error {
+	c, ok := wc.(*conn)
+	if !ok {
+		return fmt.Errorf("unsupported connection type %T", wc)
+	}
+
+	h.mu.Lock()
+	replayed := 0
+	if cursor != "" {
+		if h.events == nil {
+			h.mu.Unlock()
+			return errors.BadRequestError(errors.CodeInvalidInput, "cursor requires an event log")
+		}
+		page, err := h.events.Read(cursor, c.subscriptions(), 0)
+		if err != nil {
+			h.mu.Unlock()
+			return err
+		}
+		for _, event := range page.Events {
+			frame, err := h.encoder.eventFrame(event, c.Format())
+			if err != nil {
+				h.mu.Unlock()
+				return err
+			}
+			if !c.offer(frame) {
+				h.mu.Unlock()
+				return errors.GoneError(errors.CodeExpired,
+					fmt.Sprintf("%d events after cursor %s do not fit the send buffer, resynchronize from the latest event", len(page.Events), cursor))
+			}
+		}
+		replayed = len(page.Events)
+	}
+	h.conns[c.ID()] = c
+	h.mu.Unlock()
+
+	h.logger.Info("WebSocket client connected",
+		zap.String("conn_id", c.ID()),
+		zap.String("user_id", c.UserID()),
+		zap.String("format", string(c.Format())),
+		zap.Int("replayed", replayed),
+	)
+	return nil
+}
+
+// OnMessage applies a subscribe or unsubscribe control message
+func (h *Hub) OnMessage(ctx context.Context, wc types.WebSocketConnection, message []byte) error {
+	h.mu.RLock()
+	c, ok := h.conns[wc.ID()]
+	h.mu.RUnlock()
+	if !ok {
+		return errors.NotFoundError(errors.CodeNotFound, "connection is not registered")
+	}
+
+	var msg controlMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return errors.BadRequestError(errors.CodeInvalidInput, "control message must be JSON")
+	}
+	if err := validateTopics(msg.Topics); err != nil {
+		return err
+	}
+
+	switch msg.Action {
+	case "subscribe":
+		c.subscribe(msg.Topics)
+	case "unsubscribe":
+		c.unsubscribe(msg.Topics)
+	default:
+		return errors.BadRequestError(errors.CodeInvalidInput, fmt.Sprintf("unknown action %q", msg.Action))
+	}
+	return nil
+}
+
+// OnDisconnect removes a connection from the hub
+func (h *Hub) OnDisconnect(ctx context.Context, wc types.WebSocketConnection) error {
+	h.mu.Lock()
+	delete(h.conns, wc.ID())
+	h.mu.Unlock()
+
+	h.logger.Info("WebSocket client disconnected", zap.String("conn_id", wc.ID()))
+	return nil
+}
+
+// Connections returns the number of registered connections
+func (h *Hub) Connections() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.conns)
+}
+
+// BroadcastOrder sends an order to every client subscribed to the orders topic
+// and returns how many clients it was queued for
+func (h *Hub) BroadcastOrder(o avro.Order) (int, error) {
+	return h.broadcast(TopicOrders, o, func(format Format) ([]byte, error) {
+		return h.encoder.orderFrame(o, format)
+	})
+}
+
+// BroadcastAnalytics sends an analytics event to every client subscribed to the
+// analytics topic and returns how many clients it was queued for
+func (h *Hub) BroadcastAnalytics(a avro.Analytics) (int, error) {
+	return h.broadcast(TopicAnalytics, a, func(format Format) ([]byte, error) {
+		return h.encoder.analyticsFrame(a, format)
+	})
+}
+
+// broadcast appends value to the event log and queues the frame for topic on
+// every subscribed connection. Clients whose send buffer is full are
+// disconnected rather than slowing everyone down.
+func (h *Hub) broadcast(topic string, value interface{}, encode func(Format) ([]byte, error)) (int, error) {
+	h.mu.RLock()
+	if h.events != nil {
+		h.events.Append(topic, value)
+	}
+	targets := make([]*conn, 0, len(h.conns))
+	for _, c := range h.conns {
+		if c.subscribed(topic) {
+			targets = append(targets, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	frames := make(map[Format][]byte, 2)
+	delivered := 0
+	for _, c := range targets {
+		frame, ok := frames[c.Format()]
+		if !ok {
+			var err error
+			if frame, err = encode(c.Format()); err != nil {
+				return delivered, err
+			}
+			frames[c.Format()] = frame
+		}
+
+		if c.offer(frame) {
+			delivered++
+			continue
+		}
+		h.logger.Warn("Dropping slow WebSocket client", zap.String("conn_id", c.ID()), zap.String("topic", topic))
+		c.abort()
+	}
+	return delivered, nil
+}
+
+// closeAll asks every connection to close
+func (h *Hub) closeAll() {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, c := range h.conns {
+		c.Close()
+	}
+}
+
+// abortAll drops every connection that is still open
+func (h *Hub) abortAll() {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, c := range h.conns {
+		c.abort()
+	}
+}
+
+// validateTopics rejects topics the hub does not publish
+func validateTopics(topics []string) error {
+	for _, topic := range topics {
+		if topic != TopicOrders && topic != TopicAnalytics {
+			return errors.BadRequestError(errors.CodeInvalidInput, fmt.Sprintf("unknown topic %q", topic))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+package websocket
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	nethttp "net/http"
+	"strings"
+	"sync"
+	"time"
+
+	websocketgo "github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"go-transport-prac/internal/errors"
+	"go-transport-prac/internal/flags"
+	"go-transport-prac/internal/logger"
+)
+
+// Server upgrades HTTP requests to WebSocket connections served by a Hub
+type Server struct {
+	cfg      Config
+	logger   *logger.Logger
+	hub      *Hub
+	upgrader websocketgo.Upgrader
+	server   *nethttp.Server
+
+	// flags, when set, overrides the configured message size limit at runtime
+	flags *flags.Set
+
+	mu      sync.Mutex
+	closing bool
+	pumps   sync.WaitGroup
+}
+
+// NewServer creates a server with an empty hub
+func NewServer(cfg Config, log *logger.Logger) (*Server, error) {
+	if log == nil {
+		log = logger.Global()
+	}
+
+	hub, err := NewHub(log)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		cfg:    cfg,
+		logger: log.WithComponent("websocket"),
+		hub:    hub,
+		upgrader: websocketgo.Upgrader{
+			HandshakeTimeout: cfg.WriteWait,
+		},
+	}
+
+	mux := nethttp.NewServeMux()
+	mux.Handle(cfg.Path, s)
+	s.server = &nethttp.Server{Addr: cfg.Addr, Handler: mux}
+
+	return s, nil
+}
+
+// FlagMaxMessageSize is the runtime flag defined by WithFlags
+const FlagMaxMessageSize = "websocket.max_message_size"
+
+// WithFlags reads the client message size limit from set instead of the
+// configuration, so it can change at runtime. It applies to new connections
+func (s *Server) WithFlags(set *flags.Set) *Server {
+	if err := set.DefineInt(FlagMaxMessageSize, s.cfg.MaxMessageSize, "maximum size of a client control message in bytes"); err != nil {
+		s.logger.Warn("Failed to define flag", zap.Error(err))
+	}
+	s.flags = set
+	return s
+}
+
+// connConfig returns the configuration of a new connection
+func (s *Server) connConfig() Config {
+	cfg := s.cfg
+	if s.flags != nil {
+		cfg.MaxMessageSize = s.flags.Int(FlagMaxMessageSize)
+	}
+	return cfg
+}
+
+// Hub returns the hub events are broadcast through
+func (s *Server) Hub() *Hub {
+	return s.hub
+}
+
+// Handler returns the server's root handler, e.g. for httptest
+func (s *Server) Handler() nethttp.Handler {
+	return s.server.Handler
+}
+
+// ServeHTTP negotiates the connection format and topics, upgrades the request
+// and runs the connection until it closes
+func (s *Server) ServeHTTP(w nethttp.ResponseWriter, r *nethttp.Request) {
+	subprotocol := selectSubprotocol(r)
+	format, err := negotiateFormat(r, subprotocol)
+	if err != nil {
+		writeError(w, errors.NotAcceptableError(errors.CodeNotAcceptable, err.Error()))
+		return
+	}
+
+	topics := allTopics
+	if param := r.URL.Query().Get("topics"); param != "" {
+		topics = strings.Split(param, ",")
+		if err := validateTopics(topics); err != nil {
+			appErr, _ := errors.AsAppError(err)
+			writeError(w, appErr)
+			return
+		}
+	}
+
+	// Reject a cursor the hub cannot resume from while an HTTP error can still be sent
+	cursor := r.URL.Query().Get("cursor")
+	if cursor != "" {
+		if s.hub.EventLog() == nil {
+			writeError(w, errors.BadRequestError(errors.CodeInvalidInput, "cursor requires an event log"))
+			return
+		}
+		if _, err := s.hub.EventLog().Read(cursor, topics, 1); err != nil {
+			appErr, _ := errors.AsAppError(err)
+			writeError(w, appErr)
+			return
+		}
+	}
+
+	var header nethttp.Header
+	if subprotocol != "" {
+		header = nethttp.Header{"Sec-Websocket-Protocol": {subprotocol}}
+	}
+	ws, err := s.upgrader.Upgrade(w, r, header)
+	if err != nil {
+		// Upgrade has already replied with an HTTP error
+		s.logger.Debug("WebSocket upgrade failed", zap.Error(err))
+		return
+	}
+
+	id, err := newConnID()
+	if err != nil {
+		ws.Close()
+		s.logger.Error("Failed to create connection id", zap.Error(err))
+		return
+	}
+	c := newConn(id, r.URL.Query().Get("user_id"), format, ws, s.connConfig(), s.logger, topics)
+
+	// The request context ends when ServeHTTP returns, so the connection gets its own
+	ctx := context.Background()
+	if err := s.hub.resume(c, cursor); err != nil {
+		s.logger.Warn("Failed to register connection", zap.Error(err))
+		// The replay can outgrow the send buffer, or the cursor expire after the check above
+		ws.WriteControl(websocketgo.CloseMessage,
+			websocketgo.FormatCloseMessage(websocketgo.ClosePolicyViolation, closeReason(err)),
+			time.Now().Add(s.cfg.WriteWait))
+		ws.Close()
+		return
+	}
+
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		s.hub.OnDisconnect(ctx, c)
+		ws.Close()
+		return
+	}
+	s.pumps.Add(2)
+	s.mu.Unlock()
+
+	go func() {
+		defer s.pumps.Done()
+		c.writePump()
+	}()
+	go func() {
+		defer s.pumps.Done()
+		c.readPump(func(message []byte) {
+			if err := s.hub.OnMessage(ctx, c, message); err != nil {
+				s.logger.Warn("Rejected WebSocket control message", zap.String("conn_id", c.ID()), zap.Error(err))
+			}
+		})
+		c.abort()
+		s.hub.OnDisconnect(ctx, c)
+	}()
+}
+
+// ListenAndServe listens on the configured address and serves until stopped
+func (s *Server) ListenAndServe() error {
+	lis, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.cfg.Addr, err)
+	}
+	return s.Serve(lis)
+}
+
+// Serve accepts WebSocket connections on lis until the server is shut down
+func (s *Server) Serve(lis net.Listener) error {
+	s.logger.Info("WebSocket server listening", zap.String("addr", lis.Addr().String()), zap.String("path", s.cfg.Path))
+
+	var err error
+	if s.cfg.TLSEnabled {
+		err = s.server.ServeTLS(lis, s.cfg.CertFile, s.cfg.KeyFile)
+	} else {
+		err = s.server.Serve(lis)
+	}
+	if err != nil && err != nethttp.ErrServerClosed {
+		return fmt.Errorf("WebSocket server failed: %w", err)
+	}
+	return nil
+}
+
+// Shutdown stops accepting connections, sends every client a close frame and
+// waits for them to acknowledge it. Clients still open when ctx or the shutdown
+// timeout expires are dropped.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.cfg.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.ShutdownTimeout)
+		defer cancel()
+	}
+
+	s.mu.Lock()
+	s.closing = true
+	s.mu.Unlock()
+
+	// Hijacked connections are not tracked by net/http, so this only stops the listener
+	err := s.server.Shutdown(ctx)
+
+	s.hub.closeAll()
+	done := make(chan struct{})
+	go func() {
+		s.pumps.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.logger.Warn("WebSocket clients did not close in time, dropping them")
+		s.hub.abortAll()
+		<-done
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
+
+	s.logger.Info("WebSocket server stopped")
+	return err
+}
+
+// selectSubprotocol returns the first format the client offered in Sec-WebSocket-Protocol
+func selectSubprotocol(r *nethttp.Request) string {
+	for _, offered := range websocketgo.Subprotocols(r) {
+		for _, supported := range subprotocols {
+			if strings.EqualFold(offered, supported) {
+				return supported
+			}
+		}
+	}
+	return ""
+}
+
+// newConnID returns a random connection ID
+func newConnID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// closeReason fits an error into the 123 bytes a close frame allows for its reason
+func closeReason(err error) string {
+	reason := err.Error()
+	if len(reason) > 123 {
+		reason = strings.ToValidUTF8(reason[:123], "")
+	}
+	return reason
+}
+
+// writeError rejects a handshake with the error's mapped status code
+func writeError(w nethttp.ResponseWriter, err *errors.AppError) {
+	nethttp.Error(w, err.Message, err.HTTPStatusCode())
+}
@@ -0,0 +1,372 @@
+package websocket
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	websocketgo "github.com/gorilla/websocket"
+
+	"go-transport-prac/internal/testutil"
+	"go-transport-prac/pkg/sdl/avro"
+	"go-transport-prac/pkg/sdl/protobuf/gen/analytics"
+	"go-transport-prac/pkg/sdl/protobuf/gen/order"
+	"go-transport-prac/pkg/transport/eventlog"
+)
+
+// startTestServer serves the hub from an httptest server and returns its ws:// URL
+func startTestServer(t *testing.T, cfg Config) (*Server, string) {
+	t.Helper()
+
+	server, err := NewServer(cfg, nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	ts := httptest.NewServer(server.Handler())
+	t.Cleanup(ts.Close)
+	t.Cleanup(func() { server.Shutdown(context.Background()) })
+
+	return server, "ws" + strings.TrimPrefix(ts.URL, "http") + cfg.Path
+}
+
+// dial connects a client, optionally requesting subprotocols
+func dial(t *testing.T, url string, subprotocols ...string) *websocketgo.Conn {
+	t.Helper()
+
+	dialer := websocketgo.Dialer{Subprotocols: subprotocols, HandshakeTimeout: 5 * time.Second}
+	ws, _, err := dialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Failed to dial %s: %v", url, err)
+	}
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+// readFrame reads the next binary frame and splits it into topic and payload
+func readFrame(t *testing.T, ws *websocketgo.Conn) (string, []byte) {
+	t.Helper()
+
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	kind, data, err := ws.ReadMessage()
+	if err != nil {
+		t.Fatalf("Failed to read frame: %v", err)
+	}
+	if kind != websocketgo.BinaryMessage {
+		t.Fatalf("Expected binary frame, got message type %d", kind)
+	}
+
+	topic, payload, err := DecodeFrame(data)
+	if err != nil {
+		t.Fatalf("Failed to decode frame: %v", err)
+	}
+	return topic, payload
+}
+
+func sampleOrder() avro.Order {
+	return avro.Order{
+		ID:          7,
+		UserID:      42,
+		OrderNumber: "ORD-0007",
+		Status:      "CONFIRMED",
+		Items: []avro.OrderItem{{
+			ProductID:   1,
+			ProductName: "Laptop",
+			ProductSKU:  "LAP-001",
+			Quantity:    1,
+			UnitPrice:   avro.Price{Currency: "USD", AmountCents: 99900},
+			TotalPrice:  avro.Price{Currency: "USD", AmountCents: 99900},
+		}},
+		Summary: avro.OrderSummary{
+			Subtotal:     avro.Price{Currency: "USD", AmountCents: 99900},
+			Tax:          avro.Price{Currency: "USD", AmountCents: 0},
+			ShippingCost: avro.Price{Currency: "USD", AmountCents: 0},
+			Discount:     avro.Price{Currency: "USD", AmountCents: 0},
+			Total:        avro.Price{Currency: "USD", AmountCents: 99900},
+			TotalItems:   1,
+		},
+		CreatedAt: testutil.DefaultFakeTime,
+		UpdatedAt: testutil.DefaultFakeTime,
+	}
+}
+
+func sampleAnalytics() avro.Analytics {
+	userID := int64(42)
+	city := "Taipei"
+	return avro.Analytics{
+		ID:         1,
+		EventType:  "page_view",
+		UserID:     &userID,
+		SessionID:  "sess-1",
+		Timestamp:  testutil.DefaultFakeTime,
+		Properties: map[string]string{"page": "/checkout"},
+		Metrics:    map[string]float64{"load_ms": 123.5},
+		DeviceInfo: &avro.DeviceInfo{Platform: "web", Browser: "firefox", Mobile: false},
+		Location:   &avro.Location{Country: "TW", City: &city},
+	}
+}
+
+func TestFrameRoundTrip(t *testing.T) {
+	frame, err := EncodeFrame(TopicOrders, []byte{1, 2, 3})
+	if err != nil {
+		t.Fatalf("Failed to encode frame: %v", err)
+	}
+
+	topic, payload, err := DecodeFrame(frame)
+	if err != nil {
+		t.Fatalf("Failed to decode frame: %v", err)
+	}
+	if topic != TopicOrders || string(payload) != "\x01\x02\x03" {
+		t.Errorf("Unexpected frame contents: topic=%q payload=%v", topic, payload)
+	}
+
+	if _, _, err := DecodeFrame([]byte{10, 'o'}); err == nil {
+		t.Error("Expected error for truncated topic")
+	}
+	if _, err := EncodeFrame("", nil); err == nil {
+		t.Error("Expected error for empty topic")
+	}
+
+	t.Log("✓ Frames round-trip topic and payload")
+}
+
+func TestBroadcastPerConnectionFormat(t *testing.T) {
+	server, url := startTestServer(t, DefaultConfig())
+
+	protoClient := dial(t, url, "protobuf")
+	if protoClient.Subprotocol() != "protobuf" {
+		t.Errorf("Expected protobuf subprotocol, got %q", protoClient.Subprotocol())
+	}
+	avroClient := dial(t, url, "avro")
+	queryClient := dial(t, url+"?format=avro")
+	testutil.WaitForCondition(t, func() bool { return server.Hub().Connections() == 3 }, 5*time.Second, "clients to register")
+
+	sent := sampleOrder()
+	if n, err := server.Hub().BroadcastOrder(sent); err != nil || n != 3 {
+		t.Fatalf("Expected order queued for 3 clients, got %d (err=%v)", n, err)
+	}
+	event := sampleAnalytics()
+	if n, err := server.Hub().BroadcastAnalytics(event); err != nil || n != 3 {
+		t.Fatalf("Expected analytics queued for 3 clients, got %d (err=%v)", n, err)
+	}
+
+	topic, payload := readFrame(t, protoClient)
+	var protoOrder order.Order
+	if err := server.Hub().encoder.protoManager.Deserialize(payload, &protoOrder); err != nil || topic != TopicOrders {
+		t.Fatalf("Failed to decode protobuf order frame (topic=%q): %v", topic, err)
+	}
+	if protoOrder.OrderNumber != sent.OrderNumber || protoOrder.Status != order.OrderStatus_ORDER_STATUS_CONFIRMED {
+		t.Errorf("Unexpected protobuf order: %v", &protoOrder)
+	}
+
+	topic, payload = readFrame(t, protoClient)
+	var protoEvent analytics.AnalyticsEvent
+	if err := server.Hub().encoder.protoManager.Deserialize(payload, &protoEvent); err != nil || topic != TopicAnalytics {
+		t.Fatalf("Failed to decode protobuf analytics frame (topic=%q): %v", topic, err)
+	}
+	if protoEvent.GetUserId() != 42 || protoEvent.GetLocation().GetCity() != "Taipei" {
+		t.Errorf("Unexpected protobuf analytics event: %v", &protoEvent)
+	}
+
+	manager := server.Hub().encoder.avroManager
+	for _, client := range []*websocketgo.Conn{avroClient, queryClient} {
+		topic, payload := readFrame(t, client)
+		var avroOrder avro.Order
+		if err := manager.DeserializeStruct(manager.GetOrderSchema(), payload, &avroOrder); err != nil || topic != TopicOrders {
+			t.Fatalf("Failed to decode avro order frame (topic=%q): %v", topic, err)
+		}
+		if avroOrder.OrderNumber != sent.OrderNumber || !avroOrder.CreatedAt.Equal(sent.CreatedAt) {
+			t.Errorf("Unexpected avro order: %+v", avroOrder)
+		}
+
+		topic, payload = readFrame(t, client)
+		avroEvent, err := manager.DeserializeAnalytics(payload)
+		if err != nil || topic != TopicAnalytics {
+			t.Fatalf("Failed to decode avro analytics frame (topic=%q): %v", topic, err)
+		}
+		if avroEvent.Metrics["load_ms"] != 123.5 || avroEvent.Location == nil || *avroEvent.Location.City != "Taipei" {
+			t.Errorf("Unexpected avro analytics event: %+v", avroEvent)
+		}
+	}
+
+	t.Log("✓ Events are delivered in each connection's negotiated format")
+}
+
+func TestSubscriptions(t *testing.T) {
+	server, url := startTestServer(t, DefaultConfig())
+
+	client := dial(t, url+"?topics=analytics")
+	testutil.WaitForCondition(t, func() bool { return server.Hub().Connections() == 1 }, 5*time.Second, "client to register")
+
+	if n, _ := server.Hub().BroadcastOrder(sampleOrder()); n != 0 {
+		t.Errorf("Expected no order delivery to an analytics-only client, got %d", n)
+	}
+
+	if err := client.WriteMessage(websocketgo.TextMessage, []byte(`{"action":"subscribe","topics":["orders"]}`)); err != nil {
+		t.Fatalf("Failed to send subscribe: %v", err)
+	}
+	if err := client.WriteMessage(websocketgo.TextMessage, []byte(`{"action":"unsubscribe","topics":["analytics"]}`)); err != nil {
+		t.Fatalf("Failed to send unsubscribe: %v", err)
+	}
+	testutil.WaitForCondition(t, func() bool {
+		server.Hub().mu.RLock()
+		defer server.Hub().mu.RUnlock()
+		for _, c := range server.Hub().conns {
+			return c.subscribed(TopicOrders) && !c.subscribed(TopicAnalytics)
+		}
+		return false
+	}, 5*time.Second, "subscription to change")
+
+	if n, _ := server.Hub().BroadcastAnalytics(sampleAnalytics()); n != 0 {
+		t.Errorf("Expected no analytics delivery after unsubscribe, got %d", n)
+	}
+	if n, _ := server.Hub().BroadcastOrder(sampleOrder()); n != 1 {
+		t.Errorf("Expected order delivery after subscribe, got %d", n)
+	}
+	if topic, _ := readFrame(t, client); topic != TopicOrders {
+		t.Errorf("Expected orders frame, got %q", topic)
+	}
+
+	t.Log("✓ Clients receive only the topics they subscribe to")
+}
+
+func TestHandshakeRejections(t *testing.T) {
+	_, url := startTestServer(t, DefaultConfig())
+
+	for _, query := range []string{"?format=xml", "?topics=payments"} {
+		_, resp, err := websocketgo.DefaultDialer.Dial(url+query, nil)
+		if err == nil {
+			t.Fatalf("Expected handshake %s to fail", query)
+		}
+		if resp == nil || resp.StatusCode < 400 {
+			t.Errorf("Expected HTTP error for %s, got %v", query, resp)
+		}
+	}
+
+	t.Log("✓ Unsupported formats and topics are rejected before upgrading")
+}
+
+func TestNegotiateFormatFromAccept(t *testing.T) {
+	tests := []struct {
+		query  string
+		accept string
+		want   Format
+	}{
+		{"", "", FormatProtobuf},
+		{"", "application/avro-binary", FormatAvro},
+		{"", "application/x-protobuf;q=0.5, avro/binary", FormatAvro},
+		{"", "*/*", FormatProtobuf},
+		{"?format=protobuf", "application/avro-binary", FormatProtobuf},
+		{"", "application/json", ""},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/ws"+tt.query, nil)
+		r.Header.Set("Accept", tt.accept)
+		got, err := negotiateFormat(r, "")
+		if got != tt.want || (err != nil) != (tt.want == "") {
+			t.Errorf("negotiateFormat(%q, Accept %q) = %q, %v; want %q", tt.query, tt.accept, got, err, tt.want)
+		}
+	}
+
+	t.Log("✓ Connections without a subprotocol or query negotiate through Accept")
+}
+
+func TestResumeFromCursor(t *testing.T) {
+	server, url := startTestServer(t, DefaultConfig())
+	log := eventlog.NewLog(4)
+	server.Hub().WithEventLog(log)
+
+	// Broadcasts with no clients connected are still recorded for later replay
+	first := sampleOrder()
+	server.Hub().BroadcastOrder(first)
+	server.Hub().BroadcastAnalytics(sampleAnalytics())
+	second := sampleOrder()
+	second.OrderNumber = "ORD-2"
+	server.Hub().BroadcastOrder(second)
+	if log.Len() != 3 {
+		t.Fatalf("Expected 3 logged events, got %d", log.Len())
+	}
+
+	client := dial(t, url+"?topics=orders&cursor=0", "avro")
+	testutil.WaitForCondition(t, func() bool { return server.Hub().Connections() == 1 }, 5*time.Second, "client to register")
+	live := sampleOrder()
+	live.OrderNumber = "ORD-3"
+	if n, _ := server.Hub().BroadcastOrder(live); n != 1 {
+		t.Fatalf("Expected live order queued for 1 client, got %d", n)
+	}
+
+	manager := server.Hub().encoder.avroManager
+	for _, want := range []string{first.OrderNumber, second.OrderNumber, live.OrderNumber} {
+		topic, payload := readFrame(t, client)
+		var got avro.Order
+		if err := manager.DeserializeStruct(manager.GetOrderSchema(), payload, &got); err != nil || topic != TopicOrders {
+			t.Fatalf("Failed to decode order frame (topic=%q): %v", topic, err)
+		}
+		if got.OrderNumber != want {
+			t.Errorf("Expected order %s, got %s", want, got.OrderNumber)
+		}
+	}
+
+	// Fill the log past its capacity so cursor 0 has expired
+	for i := 0; i < 4; i++ {
+		server.Hub().BroadcastAnalytics(sampleAnalytics())
+	}
+	for query, status := range map[string]int{"?cursor=0": 410, "?cursor=abc": 400, "?cursor=99": 410} {
+		_, resp, err := websocketgo.DefaultDialer.Dial(url+query, nil)
+		if err == nil {
+			t.Fatalf("Expected handshake %s to fail", query)
+		}
+		if resp == nil || resp.StatusCode != status {
+			t.Errorf("Expected HTTP %d for %s, got %v", status, query, resp)
+		}
+	}
+
+	t.Log("✓ Clients resume missed events from a cursor before live events")
+}
+
+func TestKeepaliveAndGracefulShutdown(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.PingInterval = 20 * time.Millisecond
+	cfg.PongWait = 150 * time.Millisecond
+	server, url := startTestServer(t, cfg)
+
+	// A client that keeps reading answers pings; one that never reads does not
+	reader := dial(t, url)
+	closed := make(chan error, 1)
+	go func() {
+		for {
+			if _, _, err := reader.ReadMessage(); err != nil {
+				closed <- err
+				return
+			}
+		}
+	}()
+	dial(t, url)
+	testutil.WaitForCondition(t, func() bool { return server.Hub().Connections() == 2 }, 5*time.Second, "clients to register")
+
+	testutil.WaitForCondition(t, func() bool { return server.Hub().Connections() == 1 }, 5*time.Second, "silent client to be dropped")
+	time.Sleep(3 * cfg.PongWait)
+	if server.Hub().Connections() != 1 {
+		t.Fatalf("Expected the responsive client to stay connected")
+	}
+
+	if err := server.Shutdown(testutil.TimeoutContext(t, 5*time.Second)); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	select {
+	case err := <-closed:
+		if !websocketgo.IsCloseError(err, websocketgo.CloseGoingAway) {
+			t.Errorf("Expected going-away close frame, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Client did not receive a close frame")
+	}
+	if server.Hub().Connections() != 0 {
+		t.Errorf("Expected no connections after shutdown, got %d", server.Hub().Connections())
+	}
+
+	t.Log("✓ Pings keep live clients connected and shutdown closes them cleanly")
+}